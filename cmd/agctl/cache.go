@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/spf13/cobra"
+)
+
+// newCacheCmd cria os comandos de cache
+func newCacheCmd(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Gerencia os caches do gateway",
+	}
+
+	clear := &cobra.Command{
+		Use:   "clear",
+		Short: "Limpa o cache de rotas",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCacheCommand(cmd, opts, http.MethodGet, "/admin/clear-cache")
+		},
+	}
+
+	purge := &cobra.Command{
+		Use:   "purge <path>",
+		Short: "Descarta as respostas armazenadas em cache para a rota",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			query := url.Values{"path": {args[0]}}
+			return runCacheCommand(cmd, opts, http.MethodDelete, "/admin/cache/responses?"+query.Encode())
+		},
+	}
+
+	cmd.AddCommand(clear, purge)
+	return cmd
+}
+
+// runCacheCommand executa a operação de cache e exibe a mensagem da API
+func runCacheCommand(cmd *cobra.Command, opts *options, method, path string) error {
+	c, err := newClient(opts)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
+	defer cancel()
+
+	var result map[string]interface{}
+	if err := c.do(ctx, method, path, nil, &result); err != nil {
+		return err
+	}
+	if opts.output == "json" {
+		return printJSON(cmd.OutOrStdout(), result)
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), result["message"])
+	return nil
+}
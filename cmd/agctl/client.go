@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// client chama a API administrativa do gateway
+type client struct {
+	server string
+	token  string
+	http   *http.Client
+}
+
+// apiError é o corpo de erro da API administrativa
+type apiError struct {
+	Error   string      `json:"error"`
+	Details interface{} `json:"details"`
+}
+
+// newClient cria o cliente com as opções globais
+func newClient(opts *options) (*client, error) {
+	if opts.token == "" {
+		return nil, errors.New("token não informado: use --token ou AGCTL_TOKEN")
+	}
+	return &client{
+		server: strings.TrimSuffix(opts.server, "/"),
+		token:  opts.token,
+		http:   &http.Client{},
+	}, nil
+}
+
+// request monta a requisição autenticada para o caminho informado
+func (c *client) request(ctx context.Context, method, path string, body interface{}) (*http.Request, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.server+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req, nil
+}
+
+// do executa a requisição e decodifica a resposta em out, quando informado
+func (c *client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	req, err := c.request(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("falha ao acessar o gateway: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if err := checkStatus(resp); err != nil {
+		return err
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("resposta inválida do gateway: %w", err)
+	}
+	return nil
+}
+
+// checkStatus converte respostas de erro da API em erros legíveis
+func checkStatus(resp *http.Response) error {
+	if resp.StatusCode < 400 {
+		return nil
+	}
+
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	var body apiError
+	if err := json.Unmarshal(data, &body); err != nil || body.Error == "" {
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+	if body.Details != nil {
+		return fmt.Errorf("%s: %s (%v)", resp.Status, body.Error, body.Details)
+	}
+	return fmt.Errorf("%s: %s", resp.Status, body.Error)
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/diillson/api-gateway-go/internal/infra/middleware"
+	"github.com/spf13/cobra"
+)
+
+// newLogsCmd cria os comandos de log
+func newLogsCmd(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logs",
+		Short: "Acompanha os logs do gateway",
+	}
+
+	var route string
+	var minStatus int
+	tail := &cobra.Command{
+		Use:   "tail",
+		Short: "Acompanha o log de acesso da instância em tempo real",
+		Long: "Acompanha o log de acesso da instância em tempo real, sem amostragem. " +
+			"Com várias instâncias, cada conexão recebe apenas as requisições da instância atendida.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := newClient(opts)
+			if err != nil {
+				return err
+			}
+
+			query := url.Values{}
+			if route != "" {
+				query.Set("route", route)
+			}
+			if minStatus > 0 {
+				query.Set("minStatus", strconv.Itoa(minStatus))
+			}
+			req, err := c.request(cmd.Context(), http.MethodGet, "/admin/api/v1/logs/access?"+query.Encode(), nil)
+			if err != nil {
+				return err
+			}
+
+			// Sem prazo: a conexão permanece aberta até a interrupção do comando
+			resp, err := c.http.Do(req)
+			if err != nil {
+				return fmt.Errorf("falha ao acessar o gateway: %w", err)
+			}
+			defer resp.Body.Close()
+			if err := checkStatus(resp); err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			scanner := bufio.NewScanner(resp.Body)
+			for scanner.Scan() {
+				if opts.output == "json" {
+					fmt.Fprintln(out, scanner.Text())
+					continue
+				}
+				var entry middleware.AccessLogEntry
+				if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+					continue
+				}
+				fmt.Fprintf(out, "%s %3d %-7s %s %.1fms %s %s\n",
+					entry.Time.Format("15:04:05.000"),
+					entry.Status,
+					entry.Method,
+					entry.Path,
+					entry.LatencyMs,
+					orDash(entry.Route),
+					orDash(entry.RequestID))
+			}
+			if err := scanner.Err(); err != nil && cmd.Context().Err() == nil {
+				return fmt.Errorf("conexão interrompida: %w", err)
+			}
+			return nil
+		},
+	}
+	tail.Flags().StringVar(&route, "route", "", "Filtra pelo caminho registrado da rota (ex.: /api/users/:id)")
+	tail.Flags().IntVar(&minStatus, "min-status", 0, "Exibe apenas respostas com status a partir deste (ex.: 500)")
+
+	cmd.AddCommand(tail)
+	return cmd
+}
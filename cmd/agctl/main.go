@@ -0,0 +1,19 @@
+// Comando agctl administra o API Gateway pela API administrativa.
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+func main() {
+	// Ctrl+C encerra as requisições em andamento, como o acompanhamento de logs
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := newRootCmd().ExecuteContext(ctx); err != nil {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// routeMetrics são as métricas de uma rota retornadas por /admin/metrics
+type routeMetrics struct {
+	CallCount       int64    `json:"callCount"`
+	AvgResponseTime string   `json:"avgResponseTime"`
+	Methods         []string `json:"methods"`
+	IsActive        bool     `json:"isActive"`
+}
+
+// newMetricsCmd cria o comando de métricas das rotas
+func newMetricsCmd(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "metrics",
+		Short: "Exibe as chamadas e o tempo médio de resposta das rotas ativas",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := newClient(opts)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
+			defer cancel()
+
+			var result map[string]routeMetrics
+			if err := c.do(ctx, http.MethodGet, "/admin/metrics", nil, &result); err != nil {
+				return err
+			}
+			if opts.output == "json" {
+				return printJSON(cmd.OutOrStdout(), result)
+			}
+
+			paths := make([]string, 0, len(result))
+			for path := range result {
+				paths = append(paths, path)
+			}
+			sort.Strings(paths)
+
+			rows := make([][]string, 0, len(paths))
+			for _, path := range paths {
+				m := result[path]
+				rows = append(rows, []string{
+					path,
+					strings.Join(m.Methods, ","),
+					strconv.FormatInt(m.CallCount, 10),
+					m.AvgResponseTime,
+				})
+			}
+			return printTable([]string{"PATH", "METHODS", "CALLS", "AVG RESPONSE"}, rows)
+		},
+	}
+}
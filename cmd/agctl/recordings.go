@@ -0,0 +1,342 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/spf13/cobra"
+)
+
+// recordingsBasePath é o recurso de gravações da API administrativa
+const recordingsBasePath = "/admin/api/v1/recordings"
+
+// replayedFromHeader identifica no destino a gravação reproduzida
+const replayedFromHeader = "X-Replayed-From"
+
+// recordingPage é uma página da listagem de gravações
+type recordingPage struct {
+	Items    []*model.Recording `json:"items"`
+	Page     int                `json:"page"`
+	PageSize int                `json:"pageSize"`
+	Total    int                `json:"total"`
+}
+
+// replaySkippedHeaders são os cabeçalhos recalculados no envio da reprodução
+var replaySkippedHeaders = map[string]bool{
+	"Host":                true,
+	"Content-Length":      true,
+	"Connection":          true,
+	"Keep-Alive":          true,
+	"Proxy-Connection":    true,
+	"Te":                  true,
+	"Trailer":             true,
+	"Transfer-Encoding":   true,
+	"Upgrade":             true,
+	"Proxy-Authorization": true,
+}
+
+// newRecordingsCmd cria os comandos das gravações de tráfego
+func newRecordingsCmd(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "recordings",
+		Aliases: []string{"recording"},
+		Short:   "Consulta e reproduz as requisições gravadas pelo gateway",
+	}
+	cmd.AddCommand(
+		newRecordingsListCmd(opts),
+		newRecordingsGetCmd(opts),
+		newRecordingsReplayCmd(opts),
+	)
+	return cmd
+}
+
+func newRecordingsListCmd(opts *options) *cobra.Command {
+	var filter recordingQuery
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "Lista as gravações, da mais recente à mais antiga",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := newClient(opts)
+			if err != nil {
+				return err
+			}
+			recordings, err := filter.fetch(cmd.Context(), c, opts)
+			if err != nil {
+				return err
+			}
+
+			if opts.output == "json" {
+				return printJSON(cmd.OutOrStdout(), recordings)
+			}
+			rows := make([][]string, 0, len(recordings))
+			for _, recording := range recordings {
+				rows = append(rows, []string{
+					recording.ID,
+					recording.Time.Local().Format("2006-01-02 15:04:05"),
+					recording.Method,
+					recording.Path,
+					strconv.Itoa(recording.Status),
+					fmt.Sprintf("%.1fms", recording.DurationMs),
+					recording.Route,
+				})
+			}
+			return printTable([]string{"ID", "TIME", "METHOD", "PATH", "STATUS", "DURATION", "ROUTE"}, rows)
+		},
+	}
+	filter.register(cmd)
+	return cmd
+}
+
+func newRecordingsGetCmd(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get ID",
+		Short: "Exibe a gravação com os cabeçalhos e os corpos",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := newClient(opts)
+			if err != nil {
+				return err
+			}
+			recording, err := getRecording(cmd.Context(), c, opts, args[0])
+			if err != nil {
+				return err
+			}
+
+			if opts.output == "json" {
+				return printJSON(cmd.OutOrStdout(), recording)
+			}
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "%s %s %s\n", recording.ID, recording.Time.Local().Format(time.RFC3339), orDash(recording.RequestID))
+			fmt.Fprintf(out, "\n> %s %s\n", recording.Method, requestURI(recording))
+			printHeaders(out, "> ", recording.RequestHeaders)
+			printBody(out, recording.RequestBody, recording.RequestBodyTruncated)
+			fmt.Fprintf(out, "\n< %d (%.1fms)\n", recording.Status, recording.DurationMs)
+			printHeaders(out, "< ", recording.ResponseHeaders)
+			printBody(out, recording.ResponseBody, recording.ResponseBodyTruncated)
+			return nil
+		},
+	}
+}
+
+func newRecordingsReplayCmd(opts *options) *cobra.Command {
+	var filter recordingQuery
+	var ids []string
+	var target string
+	var delay time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "replay",
+		Short: "Reenvia as requisições gravadas a outro ambiente",
+		Long: "Reenvia as requisições gravadas ao destino informado, com o método, o caminho, a query, " +
+			"os cabeçalhos e o corpo gravados, e compara o status e o corpo das respostas. " +
+			"Cabeçalhos removidos da gravação não são enviados e campos removidos seguem como " + model.RedactedValue + "; " +
+			"requisições com o corpo truncado são ignoradas.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			base, err := url.Parse(strings.TrimSuffix(target, "/"))
+			if err != nil || base.Scheme == "" || base.Host == "" {
+				return fmt.Errorf("destino inválido: %q", target)
+			}
+			c, err := newClient(opts)
+			if err != nil {
+				return err
+			}
+
+			var recordings []*model.Recording
+			if len(ids) > 0 {
+				for _, id := range ids {
+					recording, err := getRecording(cmd.Context(), c, opts, id)
+					if err != nil {
+						return fmt.Errorf("gravação %s: %w", id, err)
+					}
+					recordings = append(recordings, recording)
+				}
+			} else if recordings, err = filter.fetch(cmd.Context(), c, opts); err != nil {
+				return err
+			}
+
+			// Reproduzir na ordem em que as requisições foram recebidas
+			for i, j := 0, len(recordings)-1; i < j; i, j = i+1, j-1 {
+				recordings[i], recordings[j] = recordings[j], recordings[i]
+			}
+
+			// O destino é chamado sem o token do gateway e sem seguir redirecionamentos
+			replayClient := &http.Client{
+				CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+			}
+			results := make([][]string, 0, len(recordings))
+			failed := 0
+			for i, recording := range recordings {
+				if i > 0 && delay > 0 {
+					time.Sleep(delay)
+				}
+				status, match, err := replay(cmd.Context(), replayClient, opts, base, recording)
+				if err != nil {
+					failed++
+					status = err.Error()
+				}
+				results = append(results, []string{
+					recording.ID,
+					recording.Method,
+					requestURI(recording),
+					strconv.Itoa(recording.Status),
+					status,
+					match,
+				})
+			}
+
+			if err := printTable([]string{"ID", "METHOD", "PATH", "RECORDED", "REPLAYED", "BODY"}, results); err != nil {
+				return err
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d de %d requisições não foram reproduzidas", failed, len(recordings))
+			}
+			return nil
+		},
+	}
+	filter.register(cmd)
+	cmd.Flags().StringVar(&target, "target", "", "URL base do destino da reprodução (ex.: http://localhost:9000)")
+	cmd.Flags().StringSliceVar(&ids, "id", nil, "Reproduz apenas as gravações informadas (repetível)")
+	cmd.Flags().DurationVar(&delay, "delay", 0, "Intervalo entre as requisições reproduzidas")
+	cmd.MarkFlagRequired("target")
+	return cmd
+}
+
+// recordingQuery são os filtros da listagem de gravações
+type recordingQuery struct {
+	route     string
+	method    string
+	minStatus int
+	from      string
+	limit     int
+}
+
+func (q *recordingQuery) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&q.route, "route", "", "Filtra pelo caminho registrado da rota (ex.: /api/users/:id)")
+	cmd.Flags().StringVar(&q.method, "method", "", "Filtra pelo método HTTP")
+	cmd.Flags().IntVar(&q.minStatus, "min-status", 0, "Apenas respostas com status a partir deste (ex.: 500)")
+	cmd.Flags().StringVar(&q.from, "from", "", "Apenas gravações a partir deste momento (RFC 3339)")
+	cmd.Flags().IntVar(&q.limit, "limit", 20, "Número máximo de gravações")
+}
+
+// fetch consulta as gravações mais recentes do filtro
+func (q *recordingQuery) fetch(ctx context.Context, c *client, opts *options) ([]*model.Recording, error) {
+	if q.limit < 1 || q.limit > 500 {
+		return nil, fmt.Errorf("--limit deve estar entre 1 e 500")
+	}
+	query := url.Values{"pageSize": {strconv.Itoa(q.limit)}}
+	for name, value := range map[string]string{"route": q.route, "method": strings.ToUpper(q.method), "from": q.from} {
+		if value != "" {
+			query.Set(name, value)
+		}
+	}
+	if q.minStatus > 0 {
+		query.Set("minStatus", strconv.Itoa(q.minStatus))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, opts.timeout)
+	defer cancel()
+	var result recordingPage
+	if err := c.do(ctx, http.MethodGet, recordingsBasePath+"?"+query.Encode(), nil, &result); err != nil {
+		return nil, err
+	}
+	return result.Items, nil
+}
+
+func getRecording(ctx context.Context, c *client, opts *options, id string) (*model.Recording, error) {
+	ctx, cancel := context.WithTimeout(ctx, opts.timeout)
+	defer cancel()
+	var recording model.Recording
+	if err := c.do(ctx, http.MethodGet, recordingsBasePath+"/"+url.PathEscape(id), nil, &recording); err != nil {
+		return nil, err
+	}
+	return &recording, nil
+}
+
+// replay reenvia a requisição gravada ao destino e retorna o status recebido
+// e se o corpo da resposta coincide com o gravado
+func replay(ctx context.Context, httpClient *http.Client, opts *options, base *url.URL, recording *model.Recording) (string, string, error) {
+	if recording.RequestBodyTruncated {
+		return "", "", fmt.Errorf("ignorada: corpo truncado na gravação")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, opts.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, recording.Method, base.String()+requestURI(recording), bytes.NewReader(recording.RequestBody))
+	if err != nil {
+		return "", "", err
+	}
+	for name, values := range recording.RequestHeaders {
+		if replaySkippedHeaders[http.CanonicalHeaderKey(name)] {
+			continue
+		}
+		for _, value := range values {
+			if value != model.RedactedValue {
+				req.Header.Add(name, value)
+			}
+		}
+	}
+	req.Header.Set(replayedFromHeader, recording.ID)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", "", fmt.Errorf("falha: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", "", fmt.Errorf("falha ao ler resposta: %w", err)
+	}
+
+	// Corpos truncados ou descartados na gravação não podem ser comparados
+	match := "-"
+	if !recording.ResponseBodyTruncated && (len(recording.ResponseBody) > 0 || len(body) == 0) {
+		match = "different"
+		if bytes.Equal(body, recording.ResponseBody) {
+			match = "equal"
+		}
+	}
+	return strconv.Itoa(resp.StatusCode), match, nil
+}
+
+// requestURI monta o caminho com a query da requisição gravada
+func requestURI(recording *model.Recording) string {
+	if recording.Query == "" {
+		return recording.Path
+	}
+	return recording.Path + "?" + recording.Query
+}
+
+func printHeaders(w io.Writer, prefix string, header http.Header) {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range header[name] {
+			fmt.Fprintf(w, "%s%s: %s\n", prefix, name, value)
+		}
+	}
+}
+
+func printBody(w io.Writer, body []byte, truncated bool) {
+	if len(body) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\n%s\n", body)
+	if truncated {
+		fmt.Fprintln(w, "... (truncado)")
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// options são as opções globais dos comandos
+type options struct {
+	server  string
+	token   string
+	output  string
+	timeout time.Duration
+}
+
+// newRootCmd cria o comando raiz com os subcomandos
+func newRootCmd() *cobra.Command {
+	opts := &options{}
+
+	root := &cobra.Command{
+		Use:          "agctl",
+		Short:        "Administra o API Gateway pela API administrativa",
+		SilenceUsage: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if opts.output != "table" && opts.output != "json" {
+				return fmt.Errorf("formato de saída inválido: %s (use table ou json)", opts.output)
+			}
+			return nil
+		},
+	}
+
+	server := os.Getenv("AGCTL_SERVER")
+	if server == "" {
+		server = "http://localhost:8080"
+	}
+	root.PersistentFlags().StringVar(&opts.server, "server", server, "URL do gateway (ou AGCTL_SERVER)")
+	root.PersistentFlags().StringVar(&opts.token, "token", os.Getenv("AGCTL_TOKEN"), "Token JWT de administrador (ou AGCTL_TOKEN)")
+	root.PersistentFlags().StringVarP(&opts.output, "output", "o", "table", "Formato de saída: table ou json")
+	root.PersistentFlags().DurationVar(&opts.timeout, "timeout", 30*time.Second, "Tempo máximo de cada requisição")
+
+	root.AddCommand(
+		newRoutesCmd(opts),
+		newCacheCmd(opts),
+		newMetricsCmd(opts),
+		newValidateCmd(opts),
+		newLogsCmd(opts),
+		newRecordingsCmd(opts),
+	)
+	return root
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/internal/infra/routefile"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// routesBasePath é o recurso de rotas da API administrativa
+const routesBasePath = "/admin/api/v1/routes"
+
+// routePage é uma página da listagem de rotas
+type routePage struct {
+	Items    []*model.Route `json:"items"`
+	Page     int            `json:"page"`
+	PageSize int            `json:"pageSize"`
+	Total    int            `json:"total"`
+}
+
+// newRoutesCmd cria os comandos de gerenciamento de rotas
+func newRoutesCmd(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "routes",
+		Aliases: []string{"route"},
+		Short:   "Gerencia as rotas do gateway",
+	}
+	cmd.AddCommand(
+		newRoutesListCmd(opts),
+		newRoutesGetCmd(opts),
+		newRoutesApplyCmd(opts, "add", "Cadastra as rotas do arquivo", http.MethodPost),
+		newRoutesApplyCmd(opts, "update", "Atualiza as rotas do arquivo", http.MethodPut),
+		newRoutesDeleteCmd(opts),
+	)
+	return cmd
+}
+
+func newRoutesListCmd(opts *options) *cobra.Command {
+	var page, pageSize int
+	var all bool
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "Lista as rotas cadastradas, inclusive as inativas",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := newClient(opts)
+			if err != nil {
+				return err
+			}
+
+			var routes []*model.Route
+			for current := page; ; current++ {
+				ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
+				var result routePage
+				query := url.Values{"page": {strconv.Itoa(current)}, "pageSize": {strconv.Itoa(pageSize)}}
+				err := c.do(ctx, http.MethodGet, routesBasePath+"?"+query.Encode(), nil, &result)
+				cancel()
+				if err != nil {
+					return err
+				}
+				routes = append(routes, result.Items...)
+				if !all || len(result.Items) == 0 || current*pageSize >= result.Total {
+					break
+				}
+			}
+
+			if opts.output == "json" {
+				return printJSON(cmd.OutOrStdout(), routes)
+			}
+			rows := make([][]string, 0, len(routes))
+			for _, r := range routes {
+				rows = append(rows, []string{
+					r.Path,
+					strings.Join(r.Methods, ","),
+					upstreamSummary(r),
+					strconv.FormatBool(r.IsActive),
+					strconv.FormatInt(r.CallCount, 10),
+				})
+			}
+			return printTable([]string{"PATH", "METHODS", "UPSTREAM", "ACTIVE", "CALLS"}, rows)
+		},
+	}
+	cmd.Flags().IntVar(&page, "page", 1, "Página inicial")
+	cmd.Flags().IntVar(&pageSize, "page-size", 50, "Rotas por página (máximo 500)")
+	cmd.Flags().BoolVar(&all, "all", false, "Percorre todas as páginas")
+	return cmd
+}
+
+func newRoutesGetCmd(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get <path>",
+		Short: "Exibe a configuração de uma rota",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := newClient(opts)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
+			defer cancel()
+
+			var r model.Route
+			if err := c.do(ctx, http.MethodGet, routesBasePath+args[0], nil, &r); err != nil {
+				return err
+			}
+			if opts.output == "json" {
+				return printJSON(cmd.OutOrStdout(), r)
+			}
+			return printTable([]string{"FIELD", "VALUE"}, [][]string{
+				{"Path", r.Path},
+				{"Methods", strings.Join(r.Methods, ",")},
+				{"Upstream", upstreamSummary(&r)},
+				{"Active", strconv.FormatBool(r.IsActive)},
+				{"Description", orDash(r.Description)},
+				{"Timeout", orDash(r.Timeout)},
+				{"Calls", strconv.FormatInt(r.CallCount, 10)},
+				{"AvgResponse", r.AverageResponseTime().String()},
+			})
+		},
+	}
+}
+
+// newRoutesApplyCmd cria os comandos que enviam as rotas de um arquivo
+func newRoutesApplyCmd(opts *options, use, short, method string) *cobra.Command {
+	var file string
+
+	cmd := &cobra.Command{
+		Use:   use + " -f <arquivo>",
+		Short: short,
+		Long: short + ". O arquivo YAML ou JSON contém uma rota, uma lista de rotas " +
+			"ou um objeto com a chave routes, no formato do arquivo de rotas.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			routes, err := readRoutes(file)
+			if err != nil {
+				return err
+			}
+			c, err := newClient(opts)
+			if err != nil {
+				return err
+			}
+
+			var applied []*model.Route
+			for _, r := range routes {
+				target := routesBasePath
+				if method == http.MethodPut {
+					target += r.Path
+				}
+
+				ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
+				var result model.Route
+				err := c.do(ctx, method, target, r, &result)
+				cancel()
+				if err != nil {
+					return fmt.Errorf("%s: %w", r.Path, err)
+				}
+				applied = append(applied, &result)
+				if opts.output == "table" {
+					fmt.Fprintf(cmd.OutOrStdout(), "%s: %s\n", r.Path, appliedVerb(method))
+				}
+			}
+			if opts.output == "json" {
+				return printJSON(cmd.OutOrStdout(), applied)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&file, "file", "f", "", "Arquivo YAML ou JSON com as rotas")
+	_ = cmd.MarkFlagRequired("file")
+	return cmd
+}
+
+func newRoutesDeleteCmd(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <path>...",
+		Short: "Exclui as rotas informadas",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := newClient(opts)
+			if err != nil {
+				return err
+			}
+			for _, path := range args {
+				ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
+				err := c.do(ctx, http.MethodDelete, routesBasePath+path, nil, nil)
+				cancel()
+				if err != nil {
+					return fmt.Errorf("%s: %w", path, err)
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "%s: excluída\n", path)
+			}
+			return nil
+		},
+	}
+}
+
+// readRoutes lê e valida as rotas do arquivo. Além do formato do arquivo de
+// rotas, aceita um documento com uma única rota.
+func readRoutes(file string) ([]*model.Route, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao ler o arquivo: %w", err)
+	}
+
+	var document interface{}
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("arquivo inválido: %w", err)
+	}
+	if object, ok := document.(map[string]interface{}); ok && !hasRoutesKey(object) {
+		document = []interface{}{object}
+	}
+	converted, err := json.Marshal(document)
+	if err != nil {
+		return nil, fmt.Errorf("arquivo inválido: %w", err)
+	}
+
+	routes, err := routefile.Parse(converted, ".json")
+	if err != nil {
+		return nil, err
+	}
+	if len(routes) == 0 {
+		return nil, fmt.Errorf("nenhuma rota em %s", filepath.Base(file))
+	}
+	return routes, nil
+}
+
+// hasRoutesKey indica um documento no formato {routes: [...]}
+func hasRoutesKey(object map[string]interface{}) bool {
+	for key := range object {
+		if strings.EqualFold(key, "routes") {
+			return true
+		}
+	}
+	return false
+}
+
+// upstreamSummary resume o backend da rota para exibição em tabela
+func upstreamSummary(r *model.Route) string {
+	if len(r.Targets) > 1 {
+		return fmt.Sprintf("%s (+%d)", r.Targets[0].URL, len(r.Targets)-1)
+	}
+	return orDash(r.Upstreams()[0].URL)
+}
+
+// appliedVerb descreve a operação concluída
+func appliedVerb(method string) string {
+	if method == http.MethodPut {
+		return "atualizada"
+	}
+	return "cadastrada"
+}
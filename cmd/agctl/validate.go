@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/diillson/api-gateway-go/internal/infra/routefile"
+	"github.com/spf13/cobra"
+)
+
+// validation é o resultado da validação de um arquivo
+type validation struct {
+	File   string `json:"file"`
+	Valid  bool   `json:"valid"`
+	Routes int    `json:"routes"`
+	Error  string `json:"error,omitempty"`
+}
+
+// newValidateCmd cria o comando que valida arquivos de rotas localmente
+func newValidateCmd(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate <arquivo>...",
+		Short: "Valida arquivos de rotas (routes.file) sem acessar o gateway",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			results := make([]validation, 0, len(args))
+			failed := 0
+			for _, file := range args {
+				result := validateFile(file)
+				if !result.Valid {
+					failed++
+				}
+				results = append(results, result)
+			}
+
+			if opts.output == "json" {
+				if err := printJSON(cmd.OutOrStdout(), results); err != nil {
+					return err
+				}
+			} else {
+				for _, result := range results {
+					if result.Valid {
+						fmt.Fprintf(cmd.OutOrStdout(), "%s: válido (%d rotas)\n", result.File, result.Routes)
+					} else {
+						fmt.Fprintf(cmd.ErrOrStderr(), "%s: %s\n", result.File, result.Error)
+					}
+				}
+			}
+
+			if failed > 0 {
+				return fmt.Errorf("%d arquivo(s) inválido(s)", failed)
+			}
+			return nil
+		},
+	}
+}
+
+// validateFile valida o arquivo com as mesmas regras aplicadas pelo gateway
+func validateFile(file string) validation {
+	result := validation{File: file}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	routes, err := routefile.Parse(data, filepath.Ext(file))
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Valid = true
+	result.Routes = len(routes)
+	return result
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/diillson/api-gateway-go/pkg/config"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// errHTTP3Unsupported indica um binário compilado sem a tag http3
+var errHTTP3Unsupported = errors.New("binário compilado sem suporte a HTTP/3; recompile com -tags http3")
+
+// altSvc guarda o anúncio do listener HTTP/3, vazio enquanto ele não está ativo
+var altSvc atomic.Value
+
+// advertiseHTTP3 anuncia o listener HTTP/3 nas respostas HTTPS pelo
+// cabeçalho Alt-Svc, para que os clientes passem a usá-lo
+func advertiseHTTP3() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if value, _ := altSvc.Load().(string); value != "" && c.Request.TLS != nil {
+			c.Header("Alt-Svc", value)
+		}
+		c.Next()
+	}
+}
+
+// startHTTP3 inicia o listener HTTP/3 (QUIC) na porta UDP configurada, com os
+// mesmos certificados e handler do servidor HTTPS. Retorna a função de
+// encerramento do listener.
+func startHTTP3(server *http.Server, cfg *config.Config, logger *zap.Logger) (func(context.Context) error, error) {
+	tlsConfig := server.TLSConfig.Clone()
+	if tlsConfig.GetCertificate == nil {
+		cert, err := tls.LoadX509KeyPair(cfg.Server.CertFile, cfg.Server.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao carregar certificado do HTTP/3: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	port := cfg.Server.HTTP3.Port
+	if port == 0 {
+		_, value, err := net.SplitHostPort(server.Addr)
+		if err != nil {
+			return nil, fmt.Errorf("endereço do servidor HTTPS inválido: %w", err)
+		}
+		if port, err = strconv.Atoi(value); err != nil {
+			return nil, fmt.Errorf("porta do servidor HTTPS inválida: %w", err)
+		}
+	}
+	addr := fmt.Sprintf(":%d", port)
+
+	serve, shutdown, err := newHTTP3Server(addr, server.Handler, tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		logger.Info("Iniciando listener HTTP/3", zap.String("addr", addr))
+		if err := serve(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			altSvc.Store("")
+			logger.Error("Erro no listener HTTP/3", zap.Error(err))
+		}
+	}()
+
+	altSvc.Store(fmt.Sprintf(`h3=":%d"; ma=%d`, port, int(cfg.Server.HTTP3.AltSvcMaxAge.Seconds())))
+	return shutdown, nil
+}
@@ -0,0 +1,24 @@
+//go:build http3
+
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+
+	"github.com/quic-go/quic-go/http3"
+)
+
+// newHTTP3Server cria o servidor HTTP/3 com o quic-go
+func newHTTP3Server(addr string, handler http.Handler, tlsConfig *tls.Config) (func() error, func(context.Context) error, error) {
+	server := &http3.Server{
+		Addr:      addr,
+		Handler:   handler,
+		TLSConfig: http3.ConfigureTLSConfig(tlsConfig),
+	}
+	shutdown := func(context.Context) error {
+		return server.Close()
+	}
+	return server.ListenAndServe, shutdown, nil
+}
@@ -0,0 +1,15 @@
+//go:build !http3
+
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+)
+
+// newHTTP3Server falha nos binários compilados sem a tag http3, que não
+// incluem o quic-go
+func newHTTP3Server(string, http.Handler, *tls.Config) (func() error, func(context.Context) error, error) {
+	return nil, nil, errHTTP3Unsupported
+}
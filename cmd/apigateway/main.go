@@ -10,20 +10,25 @@ import (
 	"go.opentelemetry.io/otel/codes"
 	"go.uber.org/zap"
 	"golang.org/x/crypto/acme/autocert"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"strings"
 	"syscall"
+	"text/tabwriter"
 	"time"
+	_ "time/tzdata" // Base de fusos embutida para as janelas de acesso das rotas
 
 	"github.com/diillson/api-gateway-go/internal/app"
+	"github.com/diillson/api-gateway-go/internal/app/audit"
+	"github.com/diillson/api-gateway-go/internal/infra/acme"
 	"github.com/diillson/api-gateway-go/pkg/logging"
 	"github.com/gin-gonic/gin"
 )
 
 // Função para configurar servidor HTTPS
-func setupServer(router *gin.Engine, cfg *config.Config, logger *zap.Logger) *http.Server {
+func setupServer(router *gin.Engine, cfg *config.Config, certStore autocert.Cache, logger *zap.Logger) *http.Server {
 	// Verificar ambiente
 	env := os.Getenv("ENV")
 
@@ -35,8 +40,9 @@ func setupServer(router *gin.Engine, cfg *config.Config, logger *zap.Logger) *ht
 			zap.Int("port", cfg.Server.Port))
 
 		return &http.Server{
-			Addr:    fmt.Sprintf(":%d", cfg.Server.Port),
-			Handler: router,
+			Addr:           fmt.Sprintf(":%d", cfg.Server.Port),
+			Handler:        router.Handler(),
+			MaxHeaderBytes: cfg.Server.MaxHeaderBytes,
 		}
 	}
 
@@ -57,38 +63,7 @@ func setupServer(router *gin.Engine, cfg *config.Config, logger *zap.Logger) *ht
 		}
 	}
 
-	// Se o usuário forneceu certificados próprios, use-os
-	if hasCertificates {
-		logger.Info("Usando certificados TLS fornecidos pelo usuário",
-			zap.String("certFile", cfg.Server.CertFile),
-			zap.String("keyFile", cfg.Server.KeyFile))
-
-		// Servidor HTTPS com certificados fornecidos
-		server := &http.Server{
-			Addr:    ":443",
-			Handler: router,
-			TLSConfig: &tls.Config{
-				MinVersion:               tls.VersionTLS13,
-				PreferServerCipherSuites: true,
-				CipherSuites: []uint16{
-					tls.TLS_AES_128_GCM_SHA256,
-					tls.TLS_AES_256_GCM_SHA384,
-					tls.TLS_CHACHA20_POLY1305_SHA256,
-				},
-			},
-		}
-
-		// Iniciar o redirecionador HTTP -> HTTPS
-		go startHTTPRedirector(logger)
-
-		// Este servidor deverá ser iniciado com:
-		// server.ListenAndServeTLS(cfg.Server.CertFile, cfg.Server.KeyFile)
-		return server
-	}
-
-	// CASO NÃO TENHA CERTIFICADOS PRÓPRIOS - TENTAR LET'S ENCRYPT
-
-	// Modo de produção com Let's Encrypt (HTTPS automático)
+	// Domínios com certificados emitidos via ACME (Let's Encrypt)
 	var domains []string
 
 	// Priorizar variável de ambiente se estiver definida
@@ -106,51 +81,78 @@ func setupServer(router *gin.Engine, cfg *config.Config, logger *zap.Logger) *ht
 	// Verificar se temos domínios válidos (e remover 'localhost')
 	validDomains := make([]string, 0)
 	for _, domain := range domains {
+		domain = strings.TrimSpace(domain)
 		if domain != "" && domain != "localhost" && domain != "127.0.0.1" {
 			validDomains = append(validDomains, domain)
 		}
 	}
 
+	// Se o usuário forneceu certificados próprios e nenhum domínio usa ACME, use-os
+	if hasCertificates && len(validDomains) == 0 {
+		logger.Info("Usando certificados TLS fornecidos pelo usuário",
+			zap.String("certFile", cfg.Server.CertFile),
+			zap.String("keyFile", cfg.Server.KeyFile))
+
+		// Servidor HTTPS com certificados fornecidos
+		server := &http.Server{
+			Addr:           ":443",
+			Handler:        router,
+			MaxHeaderBytes: cfg.Server.MaxHeaderBytes,
+			TLSConfig:      serverTLSConfig(),
+		}
+
+		// Iniciar o redirecionador HTTP -> HTTPS
+		go startHTTPRedirector(logger)
+
+		// Este servidor deverá ser iniciado com:
+		// server.ListenAndServeTLS(cfg.Server.CertFile, cfg.Server.KeyFile)
+		return server
+	}
+
 	if len(validDomains) == 0 {
 		logger.Warn("Nenhum domínio válido configurado para Let's Encrypt. Usando HTTP.",
 			zap.Strings("domains", domains))
 		return &http.Server{
-			Addr:    fmt.Sprintf(":%d", cfg.Server.Port),
-			Handler: router,
+			Addr:           fmt.Sprintf(":%d", cfg.Server.Port),
+			Handler:        router.Handler(),
+			MaxHeaderBytes: cfg.Server.MaxHeaderBytes,
 		}
 	}
 
 	// Obter email para Let's Encrypt
-	email := os.Getenv("LETSENCRYPT_EMAIL")
-	if email == "" {
+	acmeConfig := cfg.Server.ACME
+	if acmeConfig.Email == "" {
+		acmeConfig.Email = os.Getenv("LETSENCRYPT_EMAIL")
+	}
+	if acmeConfig.Email == "" {
 		logger.Warn("Email para Let's Encrypt não configurado. Usando valor anônimo.")
 	}
 
+	// Certificado próprio usado para os nomes fora dos domínios do ACME
+	var fallback *tls.Certificate
+	if hasCertificates {
+		cert, err := tls.LoadX509KeyPair(cfg.Server.CertFile, cfg.Server.KeyFile)
+		if err != nil {
+			logger.Error("Falha ao carregar certificado próprio; apenas os domínios do ACME serão atendidos",
+				zap.Error(err))
+		} else {
+			fallback = &cert
+		}
+	}
+
 	// Configurar gerenciador de certificados Let's Encrypt
 	logger.Info("Inicializando Let's Encrypt para domínios",
-		zap.Strings("domains", validDomains))
+		zap.Strings("domains", validDomains),
+		zap.String("storage", acmeConfig.Storage))
 
-	certManager := autocert.Manager{
-		Prompt:     autocert.AcceptTOS,
-		HostPolicy: autocert.HostWhitelist(validDomains...),
-		Cache:      autocert.DirCache("./certs"),
-		Email:      email,
-	}
+	certManager := acme.NewManager(acmeConfig, validDomains, certStore)
 
-	// Servidor HTTPS com Let's Encrypt
+	// Servidor HTTPS com Let's Encrypt; o certificado é escolhido pelo SNI
 	server := &http.Server{
-		Addr: ":443",
-		TLSConfig: &tls.Config{
-			GetCertificate:           certManager.GetCertificate,
-			MinVersion:               tls.VersionTLS13,
-			PreferServerCipherSuites: true,
-			CipherSuites: []uint16{
-				tls.TLS_AES_128_GCM_SHA256,
-				tls.TLS_AES_256_GCM_SHA384,
-				tls.TLS_CHACHA20_POLY1305_SHA256,
-			},
-		},
-		Handler: router,
+		Addr:           ":443",
+		MaxHeaderBytes: cfg.Server.MaxHeaderBytes,
+		TLSConfig:      acme.ServerTLSConfig(serverTLSConfig(), certManager, validDomains, fallback),
+		Handler:        router,
 	}
 
 	// Iniciar servidor HTTP para desafios Let's Encrypt e redirecionamento HTTPS
@@ -170,11 +172,25 @@ func setupServer(router *gin.Engine, cfg *config.Config, logger *zap.Logger) *ht
 
 	logger.Info("Servidor HTTPS com Let's Encrypt configurado com sucesso",
 		zap.Strings("domains", validDomains),
-		zap.String("email", email))
+		zap.String("email", acmeConfig.Email),
+		zap.Bool("fallbackCertificate", fallback != nil))
 
 	return server
 }
 
+// serverTLSConfig retorna a configuração TLS comum aos servidores HTTPS
+func serverTLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion:               tls.VersionTLS13,
+		PreferServerCipherSuites: true,
+		CipherSuites: []uint16{
+			tls.TLS_AES_128_GCM_SHA256,
+			tls.TLS_AES_256_GCM_SHA384,
+			tls.TLS_CHACHA20_POLY1305_SHA256,
+		},
+	}
+}
+
 // startHTTPRedirector inicia um servidor HTTP simples para redirecionar para HTTPS
 func startHTTPRedirector(logger *zap.Logger) {
 	httpServer := &http.Server{
@@ -199,6 +215,51 @@ func redirectHTTPS(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, target, http.StatusMovedPermanently)
 }
 
+// runMigrate executa o subcomando migrate: "apigateway migrate" aplica as
+// migrações pendentes, "apigateway migrate status" lista sua situação e
+// "apigateway migrate rollback" reverte a última migração aplicada
+func runMigrate(ctx context.Context, logger *zap.Logger, cfg *config.Config, args []string) {
+	action := "up"
+	if len(args) > 0 {
+		action = args[0]
+	}
+
+	switch action {
+	case "up":
+		if err := app.RunMigrations(ctx, logger, cfg); err != nil {
+			logger.Fatal("Falha ao aplicar migrações", zap.Error(err))
+		}
+		logger.Info("Migrações aplicadas com sucesso")
+
+	case "status":
+		shards, err := app.MigrationStatus(ctx, logger, cfg)
+		if err != nil {
+			logger.Fatal("Falha ao consultar migrações", zap.Error(err))
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "SHARD\tVERSÃO\tNOME\tAPLICADA EM")
+		for _, shard := range shards {
+			for _, migration := range shard.Migrations {
+				appliedAt := "pendente"
+				if migration.AppliedAt != nil {
+					appliedAt = migration.AppliedAt.Format(time.RFC3339)
+				}
+				fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", shard.Shard, migration.Version, migration.Name, appliedAt)
+			}
+		}
+		w.Flush()
+
+	case "rollback":
+		if err := app.RollbackMigrations(ctx, logger, cfg); err != nil {
+			logger.Fatal("Falha ao reverter migração", zap.Error(err))
+		}
+
+	default:
+		logger.Fatal("Ação de migração desconhecida; use \"up\", \"status\" ou \"rollback\"", zap.String("action", action))
+	}
+}
+
 func main() {
 	// Inicializar logger
 	logger, err := logging.NewLogger()
@@ -217,15 +278,25 @@ func main() {
 		span.SetStatus(codes.Error, err.Error())
 		logger.Fatal("Falha ao carregar configuração", zap.Error(err))
 	}
+	if err := logging.SetLevel(cfg.Logging.Level); err != nil {
+		logger.Fatal("logging.level inválido", zap.Error(err))
+	}
+
+	// Subcomando migrate: aplica as migrações do banco e encerra, sem iniciar o servidor
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(ctx, logger, cfg, os.Args[2:])
+		return
+	}
+
+	// Formatos de propagação do contexto de tracing. Valem mesmo sem tracing
+	// habilitado, repassando aos backends o contexto recebido dos clientes.
+	if err := telemetry.SetPropagators(cfg.Tracing.Propagators); err != nil {
+		logger.Fatal("Formato de propagação de tracing inválido", zap.Error(err))
+	}
 
 	// Inicializar o tracer se estiver habilitado
 	if cfg.Tracing.Enabled {
-		tp, err := telemetry.NewTracerProvider(
-			context.Background(),
-			cfg.Tracing.ServiceName,
-			cfg.Tracing.Endpoint,
-			logger,
-		)
+		tp, err := telemetry.NewTracerProvider(context.Background(), cfg.Tracing, logger)
 		if err != nil {
 			logger.Error("Falha ao inicializar tracer", zap.Error(err))
 		} else {
@@ -236,8 +307,23 @@ func main() {
 		}
 	}
 
+	// Exportar as métricas OpenTelemetry via OTLP, se habilitado
+	if cfg.Metrics.OTLP.Enabled {
+		endpoint := cfg.Metrics.OTLP.Endpoint
+		if endpoint == "" {
+			endpoint = cfg.Tracing.Endpoint
+		}
+		mp, err := telemetry.NewMeterProvider(context.Background(), cfg.Tracing.ServiceName, endpoint,
+			cfg.Metrics.OTLP.Interval, logger)
+		if err != nil {
+			logger.Error("Falha ao inicializar exportação de métricas OpenTelemetry", zap.Error(err))
+		} else {
+			defer mp.Shutdown(context.Background())
+		}
+	}
+
 	// Inicializar aplicação
-	application, err := app.NewApp(logger, cfg)
+	application, err := app.NewApp(logger, config.NewSnapshot("./config", cfg))
 	if err != nil {
 		span.SetStatus(codes.Error, err.Error())
 		logger.Fatal("Falha ao inicializar aplicação", zap.Error(err))
@@ -245,10 +331,32 @@ func main() {
 
 	// Configurar o router
 	router := gin.Default()
+	// HTTP/2 sem TLS para clientes gRPC quando o servidor roda em HTTP
+	router.UseH2C = cfg.Server.H2C
+	// O Gin confia apenas nos proxies configurados ao ler o X-Forwarded-For
+	if len(cfg.Server.TrustedProxies) > 0 {
+		if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+			logger.Fatal("server.trustedProxies inválido", zap.Error(err))
+		}
+	}
+	if cfg.Server.HTTP3.Enabled {
+		// Anuncia o listener HTTP/3 assim que ele estiver ativo
+		router.Use(advertiseHTTP3())
+	}
 	application.RegisterRoutes(router)
 
 	// Configurar servidor HTTP
-	server := setupServer(router, cfg, logger)
+	server := setupServer(router, cfg, application.CertificateStore, logger)
+
+	// Iniciar o listener HTTP/3 (QUIC) ao lado do HTTPS, se habilitado
+	var shutdownHTTP3 func(context.Context) error
+	if cfg.Server.HTTP3.Enabled {
+		if server.TLSConfig == nil {
+			logger.Warn("HTTP/3 requer o servidor HTTPS; listener HTTP/3 não iniciado")
+		} else if shutdownHTTP3, err = startHTTP3(server, cfg, logger); err != nil {
+			logger.Error("Falha ao iniciar listener HTTP/3; seguindo apenas com HTTP/1.1 e HTTP/2", zap.Error(err))
+		}
+	}
 
 	// Iniciar o servidor em uma goroutine
 	go func() {
@@ -259,7 +367,7 @@ func main() {
 			logger.Info("Iniciando servidor HTTPS", zap.String("addr", server.Addr))
 
 			// Verificar se devemos usar certificados próprios
-			if cfg.Server.CertFile != "" && cfg.Server.KeyFile != "" {
+			if server.TLSConfig.GetCertificate == nil {
 				logger.Info("Usando certificados próprios",
 					zap.String("certFile", cfg.Server.CertFile),
 					zap.String("keyFile", cfg.Server.KeyFile))
@@ -282,6 +390,32 @@ func main() {
 		}
 	}()
 
+	// Iniciar a API administrativa gRPC, se habilitada
+	if application.GRPCAdmin != nil {
+		listener, err := net.Listen("tcp", cfg.Admin.GRPC.Address)
+		if err != nil {
+			logger.Fatal("Falha ao abrir porta da API administrativa gRPC",
+				zap.String("addr", cfg.Admin.GRPC.Address), zap.Error(err))
+		}
+
+		go func() {
+			logger.Info("Iniciando API administrativa gRPC", zap.String("addr", cfg.Admin.GRPC.Address))
+			if err := application.GRPCAdmin.Serve(listener); err != nil {
+				logger.Error("Erro na API administrativa gRPC", zap.Error(err))
+			}
+		}()
+	}
+
+	// SIGHUP recarrega a configuração sem reiniciar o servidor
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			logger.Info("SIGHUP recebido; recarregando configuração")
+			application.ReloadConfig(audit.WithActor(context.Background(), audit.Actor{Username: "system:sighup"}))
+		}
+	}()
+
 	// Esperar por sinal de interrupção para shutdown gracioso
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -296,5 +430,19 @@ func main() {
 		logger.Fatal("Erro ao encerrar servidor", zap.Error(err))
 	}
 
+	if shutdownHTTP3 != nil {
+		if err := shutdownHTTP3(ctx); err != nil {
+			logger.Warn("Erro ao encerrar listener HTTP/3", zap.Error(err))
+		}
+	}
+
+	if application.GRPCAdmin != nil {
+		application.GRPCAdmin.GracefulStop(ctx)
+	}
+
+	if err := application.Close(); err != nil {
+		logger.Error("Erro ao liberar recursos da aplicação", zap.Error(err))
+	}
+
 	logger.Info("Servidor encerrado com sucesso")
 }
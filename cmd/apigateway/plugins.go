@@ -0,0 +1,9 @@
+package main
+
+// Plugins registrados no binário. Plugins próprios são incluídos importando o
+// pacote que os registra no init(), ex.:
+//
+//	_ "github.com/empresa/gateway-plugins/assinatura"
+import (
+	_ "github.com/diillson/api-gateway-go/pkg/plugin/wasm"
+)
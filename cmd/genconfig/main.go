@@ -37,10 +37,70 @@ func main() {
 			IdleTimeout:    30 * time.Second,
 			MaxHeaderBytes: 1 << 20, // 1 MB
 			TLS:            false,
+			H2C:            false,
 			CertFile:       "/path/to/cert.pem",
 			KeyFile:        "/path/to/key.pem",
 			BaseURL:        "https://api.example.com",
 			Domains:        []string{"api.example.com"},
+			ACME: config.ACMEConfig{
+				Email:       "",
+				Storage:     "dir", // Opções: dir, cache (Redis) ou database
+				CacheDir:    "./certs",
+				RenewBefore: 720 * time.Hour,
+			},
+			Limits: config.RequestLimitsConfig{
+				Enabled:             true,
+				MaxHeaderCount:      100,
+				MaxHeaderValueBytes: 8192,
+				MaxURLLength:        8192,
+				RejectSmuggling:     true,
+			},
+			SecurityHeaders: config.SecurityHeadersConfig{
+				Enabled: true,
+				HSTS: config.HSTSConfig{
+					Enabled:           true, // Enviado apenas em requisições HTTPS
+					MaxAge:            8760 * time.Hour,
+					IncludeSubDomains: true,
+					Preload:           false,
+				},
+				FrameOptions:       "DENY", // Opções: DENY, SAMEORIGIN ou vazio
+				ContentTypeOptions: true,
+				ReferrerPolicy:     "strict-origin-when-cross-origin",
+				PermissionsPolicy:  "",
+				// Ex.: "default-src 'self'; script-src 'self' 'nonce-{nonce}'"
+				ContentSecurityPolicy: "",
+				NonceHeader:           "X-CSP-Nonce",
+				StripResponseHeaders:  []string{"Server", "X-Powered-By", "X-AspNet-Version", "X-AspNetMvc-Version"},
+			},
+			Compression: config.CompressionConfig{
+				Enabled:   false,
+				Encodings: []string{"br", "zstd", "gzip"}, // Ordem de preferência do gateway
+				MinSize:   1024,
+				ContentTypes: []string{
+					"text/*", "application/json", "application/javascript", "application/xml",
+					"application/problem+json", "image/svg+xml",
+				},
+			},
+			HTTP3: config.HTTP3Config{
+				Enabled:      false, // Requer tls e binário compilado com -tags http3
+				Port:         0,     // Porta UDP; 0 usa a mesma do HTTPS
+				AltSvcMaxAge: 24 * time.Hour,
+			},
+			Overload: config.OverloadConfig{
+				Enabled:       false,
+				InitialLimit:  100,
+				MinLimit:      10,
+				MaxLimit:      2000,
+				TargetLatency: 0, // 0 usa tolerance sobre a latência mínima observada
+				Tolerance:     2.0,
+				BackoffRatio:  0.9,
+				MaxQueue:      100,
+				QueueTimeout:  100 * time.Millisecond,
+				RetryAfter:    time.Second,
+			},
+			TrustedProxies:    []string{}, // Ex.: ["10.0.0.0/8"] para o balanceador interno
+			ForwardedForDepth: 0,          // Número fixo de proxies à frente do gateway
+			RequestIDHeader:   "X-Request-ID",
 		},
 		Database: config.DatabaseConfig{
 			Driver:          "postgres",
@@ -48,17 +108,22 @@ func main() {
 			MaxIdleConns:    10,
 			MaxOpenConns:    50,
 			ConnMaxLifetime: 1 * time.Hour,
+			ConnMaxIdleTime: 10 * time.Minute,
+			PingTimeout:     2 * time.Second,
+			QueryTimeout:    5 * time.Second,
 			LogLevel:        "warn",
 			SlowThreshold:   200 * time.Millisecond,
-			MigrationDir:    "./migrations",
+			MigrationDir:    "",    // Vazio usa as migrações embutidas no binário
 			SkipMigrations:  false, // Opção: false aplica migrações (padrão), true pula
 		},
 		Cache: config.CacheConfig{
 			Enabled:     true,
-			Type:        "memory", // Opções: "memory" ou "redis"
+			Type:        "memory", // Opções: "memory", "local", "redis" ou "memcached"
 			TTL:         5 * time.Minute,
-			MaxItems:    10000, // Apenas para cache em memória
-			MaxMemoryMB: 100,   // Apenas para cache em memória
+			MaxItems:    10000, // Apenas para cache local
+			MaxMemoryMB: 100,   // Apenas para cache local
+			// Lista de rotas expirada servida enquanto é recarregada (0 desativa)
+			StaleWhileRevalidate: time.Minute,
 			Redis: config.RedisOptions{
 				Address:      "localhost:6379",
 				Password:     "",
@@ -73,6 +138,16 @@ func main() {
 				IdleTimeout:  5 * time.Minute,
 				MaxConnAge:   30 * time.Minute,
 			},
+			Memcached: config.MemcachedOptions{
+				Servers:      []string{"localhost:11211"},
+				Timeout:      500 * time.Millisecond,
+				MaxIdleConns: 10,
+			},
+			Response: config.ResponseCacheConfig{
+				Enabled:      false, // Apenas rotas com responseCache definido são armazenadas
+				DefaultTTL:   1 * time.Minute,
+				MaxBodyBytes: 1 << 20, // 1 MB
+			},
 		},
 		Auth: config.AuthConfig{
 			Enabled:          true,
@@ -84,11 +159,48 @@ func main() {
 			AdminUsers:       []string{"admin"},
 			PasswordMinLen:   8,
 			RequireTwoFactor: false,
+			JWKS: config.JWKSConfig{
+				RefreshInterval: 15 * time.Minute,
+			},
+			OIDC: config.OIDCConfig{
+				SessionTTL: 8 * time.Hour,
+				Session: config.OIDCSessionConfig{
+					Enabled:    false,
+					CSRFHeader: "X-CSRF-Token",
+					CSRFCookie: "ag_csrf",
+					SameSite:   "lax",
+				},
+			},
+			APIKeys: config.APIKeysConfig{
+				Enabled:  false,
+				Header:   "X-Api-Key",
+				CacheTTL: time.Minute,
+			},
+			Credentials: config.CredentialsConfig{
+				Enabled:    false, // Rotas com auth basic ou hmac para parceiros sem JWT/OAuth
+				Realm:      "api-gateway",
+				CacheTTL:   time.Minute,
+				MaxSkew:    5 * time.Minute,
+				MaxBodyKiB: 10240,
+			},
+			Revocation: config.RevocationConfig{
+				MaxTokenLifetime: 24 * time.Hour,
+			},
 		},
 		Metrics: config.MetricsConfig{
 			Enabled:        true,
 			PrometheusPath: "/metrics",
 			ReportInterval: 15 * time.Second,
+			OTLP: config.OTLPMetricsConfig{
+				Enabled:  false,
+				Endpoint: "", // Vazio usa tracing.endpoint
+				Interval: 30 * time.Second,
+			},
+			RouteStats: config.RouteStatsConfig{
+				Enabled:       true,
+				FlushInterval: 30 * time.Second,
+				Retention:     24 * time.Hour,
+			},
 		},
 		Logging: config.LoggingConfig{
 			Level:      "info",
@@ -96,6 +208,11 @@ func main() {
 			OutputPath: "stdout",
 			ErrorPath:  "stderr",
 			Production: true,
+			Access: config.AccessLogConfig{
+				Enabled:    true,
+				Format:     "json",
+				SampleRate: 1,
+			},
 		},
 		Tracing: config.TracingConfig{
 			Enabled:       false,
@@ -103,6 +220,23 @@ func main() {
 			Endpoint:      "otel-collector:4317",
 			ServiceName:   "api-gateway",
 			SamplingRatio: 0.1,
+			Propagators:   []string{"tracecontext", "baggage"},
+			Sampler:       "parentbased_ratio",
+			ResourceAttributes: map[string]interface{}{
+				"deployment.environment": "production",
+			},
+			Routes: []config.TracingRouteConfig{
+				{Path: "/health", SamplingRatio: 0},
+			},
+			Redact: config.TracingRedactConfig{
+				Attributes: []string{
+					"http.request.header.authorization",
+					"http.request.header.proxy-authorization",
+					"http.request.header.cookie",
+					"http.request.header.x-api-key",
+					"http.response.header.set-cookie",
+				},
+			},
 		},
 		Features: config.FeaturesConfig{
 			RateLimiter:       true,
@@ -114,6 +248,168 @@ func main() {
 			Monitoring:        true,
 			AutoRouteRegister: false,
 		},
+		Upstream: config.UpstreamConfig{
+			EgressProxy: config.EgressProxyConfig{
+				URL:     "", // Ex.: "http://proxy.corp:3128" ou "socks5://proxy.corp:1080"
+				NoProxy: []string{"localhost", ".internal", "10.0.0.0/8"},
+			},
+			Transport: config.TransportConfig{
+				MaxIdleConns:        512,
+				MaxIdleConnsPerHost: 64, // O padrão do Go (2) limita rotas com muito tráfego
+				MaxConnsPerHost:     0,  // Sem limite
+				IdleConnTimeout:     90 * time.Second,
+				TLSHandshakeTimeout: 10 * time.Second,
+				DialTimeout:         30 * time.Second,
+				KeepAlive:           30 * time.Second,
+				DisableKeepAlives:   false,
+				ForceAttemptHTTP2:   true,
+			},
+			RetryBudget: config.RetryBudgetConfig{
+				Enabled:             true,
+				Ratio:               0.2, // Até 20% das requisições podem ser repetidas
+				MinRetriesPerSecond: 3,
+				Window:              10 * time.Second,
+			},
+			Kubernetes: config.KubernetesDiscoveryConfig{
+				Enabled:     false, // Habilita backends service://namespace/nome:porta
+				APIServer:   "",
+				SyncTimeout: 5 * time.Second,
+			},
+			Consul: config.ConsulDiscoveryConfig{
+				Enabled:         false, // Habilita backends consul://servico?tag=...
+				Address:         "http://localhost:8500",
+				Token:           "", // Prefira a variável de ambiente
+				Datacenter:      "",
+				RefreshInterval: 10 * time.Second,
+				OnlyPassing:     true, // Ignora instâncias com verificações em alerta
+			},
+			DNS: config.DNSDiscoveryConfig{
+				Enabled:         false, // Habilita backends srv://_http._tcp.nome
+				RefreshInterval: 30 * time.Second,
+			},
+			Resolver: config.UpstreamResolverConfig{
+				Enabled:         true,
+				Servers:         []string{}, // Ex.: ["10.0.0.2:53"]; vazio usa o resolvedor do sistema
+				TTL:             30 * time.Second,
+				MinTTL:          time.Second,
+				MaxTTL:          5 * time.Minute,
+				Timeout:         2 * time.Second,
+				RefreshInterval: time.Second,
+			},
+		},
+		Admin: config.AdminConfig{
+			GRPC: config.GRPCAdminConfig{
+				Enabled: false,
+				Address: ":9090",
+			},
+			IPFilter: config.IPFilterConfig{
+				Allow: []string{}, // Ex.: ["10.0.0.0/8", "192.168.0.0/16"]
+				Deny:  []string{},
+			},
+			UI: config.AdminUIConfig{
+				Enabled: true, // Painel em /admin/ui
+			},
+		},
+		Cluster: config.ClusterConfig{
+			Enabled:           false,
+			Name:              "api-gateway",
+			NodeID:            "", // Vazio usa o hostname
+			AdvertiseAddress:  "",
+			HeartbeatInterval: 2 * time.Second,
+			MemberTTL:         10 * time.Second,
+			LeaseTTL:          15 * time.Second,
+			Election:          "redis", // Ou "kubernetes", com um recurso Lease
+			Kubernetes: config.ClusterKubernetesConfig{
+				APIServer: "", // Vazio usa o serviço da API visto de dentro do pod
+				Namespace: "", // Vazio usa o namespace do pod
+				LeaseName: "", // Vazio usa "<name>-leader"
+			},
+		},
+		Quotas: config.QuotasConfig{
+			Enabled:       false,
+			Daily:         0, // 0 sem limite; faixas de chaves de API podem definir a própria cota
+			Monthly:       0,
+			FlushInterval: time.Minute,
+		},
+		RateLimit: config.RateLimitConfig{
+			Headers: "both", // standard (RateLimit-*), legacy (X-RateLimit-*), both ou none
+		},
+		Idempotency: config.IdempotencyConfig{
+			Enabled:      false,
+			Header:       "Idempotency-Key",
+			TTL:          24 * time.Hour,
+			LockTimeout:  time.Minute,
+			MaxBodyBytes: 1 << 20,
+		},
+		Recording: config.RecordingConfig{
+			Enabled:   false, // Rotas com Recording têm requisições e respostas gravadas no banco
+			QueueSize: 1000,
+			Retention: 72 * time.Hour,
+		},
+		Geo: config.GeoConfig{
+			Enabled:       false,
+			Database:      "", // Ex.: "./config/GeoLite2-Country.mmdb"
+			CountryHeader: "", // Ex.: "CF-IPCountry" atrás da Cloudflare
+		},
+		Flags: config.FeatureFlagsConfig{
+			Provider: "", // "static" (flags abaixo) ou "ofrep" (ex.: flagd, GO Feature Flag)
+			URL:      "", // Ex.: "http://flagd:8016" com o provedor ofrep
+			Headers:  map[string]string{},
+			Timeout:  2 * time.Second,
+			CacheTTL: 30 * time.Second,
+			Flags:    map[string]config.FeatureFlagConfig{}, // Ex.: {"checkout-v2": {Enabled: true, Percentage: 10, Consumers: ["beta-tester"]}}
+		},
+		Health: config.HealthConfig{
+			Timeout:           5 * time.Second,
+			CriticalUpstreams: []string{}, // Ex.: ["/api/pagamentos"]
+		},
+		Routes: config.RoutesConfig{
+			File:     "", // Ex.: "./config/routes.yaml"
+			Watch:    true,
+			Prune:    false,
+			Debounce: 500 * time.Millisecond,
+			// Rotas carregadas antes de a instância ficar pronta
+			Warmup:              true,
+			WarmupRetryInterval: 5 * time.Second,
+			SnapshotFile:        "", // Ex.: "./data/routes.snapshot"
+			Webhooks: config.RouteWebhooksConfig{
+				Endpoints:  []config.RouteWebhookEndpointConfig{}, // Ex.: [{URL: "https://ci.example.com/hooks/gateway", Secret: "...", Events: ["created", "deleted"]}]
+				Timeout:    10 * time.Second,
+				MaxRetries: 3,
+			},
+			Hooks: config.RouteHooksConfig{
+				QueueSize:  1024, // Notificações pós-resposta aguardando entrega
+				Workers:    8,
+				Timeout:    5 * time.Second,
+				MaxRetries: 2,
+			},
+			Kubernetes: config.RoutesKubernetesConfig{
+				Enabled:        false, // Sincroniza recursos GatewayRoute do cluster
+				APIServer:      "",
+				Namespace:      "", // Vazio acompanha todos os namespaces
+				HTTPRoute:      false,
+				GatewayName:    "",
+				ResyncInterval: 5 * time.Minute,
+			},
+		},
+		OpenAPI: config.OpenAPIConfig{
+			Title:     "API Gateway",
+			PublicURL: "", // Ex.: "https://api.example.com"
+			CacheTTL:  5 * time.Minute,
+			Timeout:   10 * time.Second,
+		},
+		Security: config.SecurityConfig{
+			Secrets: config.SecretsConfig{
+				Provider:        "env", // Opções: env, file, vault, aws, kubernetes
+				RefreshInterval: 5 * time.Minute,
+				JWTSecret:       "", // Ex.: "api-gateway#jwt" no Vault ou no Secrets Manager
+				APIKeyPepper:    "", // Ex.: "api-gateway#apikey-pepper"
+				Vault: config.VaultSecretsConfig{
+					Mount:     "secret",
+					KVVersion: 2,
+				},
+			},
+		},
 	}
 
 	// Converter para YAML
@@ -146,6 +442,12 @@ func main() {
 	re = regexp.MustCompile(`(\s+type:\s+memory)`)
 	yamlStr = re.ReplaceAllString(yamlStr, `$1  # Opções: "memory" ou "redis"`)
 
+	re = regexp.MustCompile(`(\s+pingtimeout:\s+2s)`)
+	yamlStr = re.ReplaceAllString(yamlStr, `$1  # Timeout do ping ao banco usado no readiness`)
+
+	re = regexp.MustCompile(`(\s+migrationdir:\s+"")`)
+	yamlStr = re.ReplaceAllString(yamlStr, `$1  # Vazio usa as migrações embutidas no binário`)
+
 	re = regexp.MustCompile(`(\s+skipmigrations:\s+false)`)
 	yamlStr = re.ReplaceAllString(yamlStr, `$1  # Opção: false aplica migrações (padrão), true pula`)
 
@@ -162,7 +464,7 @@ func main() {
 	re = regexp.MustCompile(`(\s+minidleconns:\s+5)`)
 	yamlStr = re.ReplaceAllString(yamlStr, `$1  # Número mínimo de conexões ociosas mantidas abertas`)
 
-	re = regexp.MustCompile(`(\s+maxretries:\s+3)`)
+	re = regexp.MustCompile(`(\s+minidleconns:\s+5.*\s+maxretries:\s+3)`)
 	yamlStr = re.ReplaceAllString(yamlStr, `$1  # Número máximo de tentativas de reconexão`)
 
 	re = regexp.MustCompile(`(\s+readtimeout:\s+3s)`)
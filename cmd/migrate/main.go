@@ -22,11 +22,11 @@ func main() {
 		migrationDir string
 	)
 
-	flag.StringVar(&action, "action", "migrate", "Ação (migrate, create)")
+	flag.StringVar(&action, "action", "migrate", "Ação (migrate, status, rollback, create)")
 	flag.StringVar(&name, "name", "", "Nome da migração (apenas para action=create)")
 	flag.StringVar(&driver, "driver", "sqlite", "Driver de banco de dados (sqlite, mysql, postgres)")
 	flag.StringVar(&dsn, "dsn", "./routes.db", "DSN do banco de dados")
-	flag.StringVar(&migrationDir, "dir", "./migrations", "Diretório de migrações")
+	flag.StringVar(&migrationDir, "dir", "", "Diretório de migrações (vazio usa as embutidas no binário)")
 	flag.Parse()
 
 	// Inicializar logger
@@ -62,19 +62,53 @@ func main() {
 
 		logger.Info("Migrações aplicadas com sucesso")
 
-	case "create":
-		if name == "" {
-			logger.Fatal("Nome da migração é obrigatório para action=create")
+	case "status":
+		// Consultar a situação sem aplicar migrações
+		dbConfig.SkipMigrations = true
+		db, err := database.NewDatabase(ctx, dbConfig, logger)
+		if err != nil {
+			logger.Fatal("Falha ao inicializar banco de dados", zap.Error(err))
 		}
+		defer db.Close()
 
-		// Criar nova migração
+		migrations, err := db.MigrationStatus(ctx)
+		if err != nil {
+			logger.Fatal("Falha ao consultar migrações", zap.Error(err))
+		}
+
+		for _, migration := range migrations {
+			appliedAt := "pendente"
+			if migration.AppliedAt != nil {
+				appliedAt = migration.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%d\t%s\t%s\n", migration.Version, migration.Name, appliedAt)
+		}
+
+	case "rollback":
+		// Reverter a última migração aplicada
+		dbConfig.SkipMigrations = true
 		db, err := database.NewDatabase(ctx, dbConfig, logger)
 		if err != nil {
 			logger.Fatal("Falha ao inicializar banco de dados", zap.Error(err))
 		}
 		defer db.Close()
 
-		filepath, err := db.CreateMigration(name)
+		migration, err := db.RollbackMigration(ctx)
+		if err != nil {
+			logger.Fatal("Falha ao reverter migração", zap.Error(err))
+		}
+
+		logger.Info("Migração revertida",
+			zap.Int64("version", migration.Version),
+			zap.String("name", migration.Name))
+
+	case "create":
+		if name == "" {
+			logger.Fatal("Nome da migração é obrigatório para action=create")
+		}
+
+		// Criar nova migração; não é necessário conectar ao banco
+		filepath, err := database.NewMigrationManager(nil, logger, migrationDir).CreateMigration(name)
 		if err != nil {
 			logger.Fatal("Falha ao criar migração", zap.Error(err))
 		}
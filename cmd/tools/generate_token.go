@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 func main() {
@@ -34,7 +35,8 @@ func main() {
 
 	// Criar os claims do JWT exatamente no formato que o sistema espera
 	claims := jwt.MapClaims{
-		"user_id": userID, // ID do usuário admin que foi criado
+		"jti":     uuid.New().String(), // Identificador usado na revogação do token
+		"user_id": userID,              // ID do usuário admin que foi criado
 		"role":    "admin",
 		"exp":     time.Now().Add(24 * time.Hour).Unix(),
 		"iat":     time.Now().Unix(),
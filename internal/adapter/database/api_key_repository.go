@@ -0,0 +1,141 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/internal/domain/repository"
+	"gorm.io/gorm"
+)
+
+// APIKeyRepository implementa o armazenamento de chaves de API com GORM
+type APIKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyRepository cria um novo repositório de chaves de API
+func NewAPIKeyRepository(db *gorm.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+// CreateAPIKey armazena uma nova chave com o hash do seu valor
+func (r *APIKeyRepository) CreateAPIKey(ctx context.Context, key *model.APIKey, keyHash string) error {
+	routesJSON, err := json.Marshal(key.Routes)
+	if err != nil {
+		return fmt.Errorf("falha ao serializar rotas da chave: %w", err)
+	}
+
+	entity := model.APIKeyEntity{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		KeyHash:    keyHash,
+		RoutesJSON: string(routesJSON),
+		Tier:       key.Tier,
+		ExpiresAt:  key.ExpiresAt,
+	}
+	if err := r.db.WithContext(ctx).Create(&entity).Error; err != nil {
+		return fmt.Errorf("falha ao criar chave de API: %w", err)
+	}
+	key.CreatedAt = entity.CreatedAt
+	return nil
+}
+
+// GetAPIKeyByHash obtém a chave pelo hash do seu valor
+func (r *APIKeyRepository) GetAPIKeyByHash(ctx context.Context, keyHash string) (*model.APIKey, error) {
+	var entity model.APIKeyEntity
+	if err := r.db.WithContext(ctx).Where("key_hash = ?", keyHash).First(&entity).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repository.ErrAPIKeyNotFound
+		}
+		return nil, fmt.Errorf("falha ao buscar chave de API: %w", err)
+	}
+	return apiKeyFromEntity(&entity)
+}
+
+// ListAPIKeys retorna todas as chaves, inclusive as revogadas
+func (r *APIKeyRepository) ListAPIKeys(ctx context.Context) ([]*model.APIKey, error) {
+	var entities []model.APIKeyEntity
+	if err := r.db.WithContext(ctx).Order("created_at").Find(&entities).Error; err != nil {
+		return nil, fmt.Errorf("falha ao listar chaves de API: %w", err)
+	}
+
+	keys := make([]*model.APIKey, 0, len(entities))
+	for i := range entities {
+		key, err := apiKeyFromEntity(&entities[i])
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// RevokeAPIKey revoga a chave pelo ID e retorna o hash do seu valor
+func (r *APIKeyRepository) RevokeAPIKey(ctx context.Context, id string) (string, error) {
+	var entity model.APIKeyEntity
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&entity).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", repository.ErrAPIKeyNotFound
+		}
+		return "", fmt.Errorf("falha ao buscar chave de API: %w", err)
+	}
+
+	now := time.Now()
+	err := r.db.WithContext(ctx).Model(&model.APIKeyEntity{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"revoked": true, "revoked_at": now}).Error
+	if err != nil {
+		return "", fmt.Errorf("falha ao revogar chave de API: %w", err)
+	}
+	return entity.KeyHash, nil
+}
+
+// TouchAPIKey registra o último uso da chave
+func (r *APIKeyRepository) TouchAPIKey(ctx context.Context, id string, usedAt time.Time) error {
+	err := r.db.WithContext(ctx).Model(&model.APIKeyEntity{}).
+		Where("id = ?", id).
+		Update("last_used_at", usedAt).Error
+	if err != nil {
+		return fmt.Errorf("falha ao registrar uso da chave de API: %w", err)
+	}
+	return nil
+}
+
+// UpdateAPIKeyHash substitui o hash armazenado da chave
+func (r *APIKeyRepository) UpdateAPIKeyHash(ctx context.Context, id, keyHash string) error {
+	err := r.db.WithContext(ctx).Model(&model.APIKeyEntity{}).
+		Where("id = ?", id).
+		Update("key_hash", keyHash).Error
+	if err != nil {
+		return fmt.Errorf("falha ao atualizar hash da chave de API: %w", err)
+	}
+	return nil
+}
+
+// apiKeyFromEntity converte a entidade de banco no modelo de domínio
+func apiKeyFromEntity(entity *model.APIKeyEntity) (*model.APIKey, error) {
+	var routes []string
+	if entity.RoutesJSON != "" {
+		// Uma lista vazia libera todas as rotas, então rotas ilegíveis não podem ser ignoradas
+		if err := json.Unmarshal([]byte(entity.RoutesJSON), &routes); err != nil {
+			return nil, fmt.Errorf("rotas da chave de API %s inválidas: %w", entity.ID, err)
+		}
+	}
+	return &model.APIKey{
+		ID:         entity.ID,
+		Name:       entity.Name,
+		Prefix:     entity.Prefix,
+		Routes:     routes,
+		Tier:       entity.Tier,
+		Revoked:    entity.Revoked,
+		ExpiresAt:  entity.ExpiresAt,
+		LastUsedAt: entity.LastUsedAt,
+		RevokedAt:  entity.RevokedAt,
+		CreatedAt:  entity.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,120 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/internal/domain/repository"
+	"gorm.io/gorm"
+)
+
+// AuditRepository implementa o armazenamento do log de auditoria com GORM
+type AuditRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditRepository cria um novo repositório de auditoria
+func NewAuditRepository(db *gorm.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// CreateAuditRecord grava um novo registro
+func (r *AuditRepository) CreateAuditRecord(ctx context.Context, record *model.AuditRecord) error {
+	var changesJSON string
+	if len(record.Changes) > 0 {
+		data, err := json.Marshal(record.Changes)
+		if err != nil {
+			return fmt.Errorf("falha ao serializar alterações do registro de auditoria: %w", err)
+		}
+		changesJSON = string(data)
+	}
+
+	entity := model.AuditEntity{
+		ID:          record.ID,
+		Time:        record.Time,
+		Action:      record.Action,
+		Resource:    record.Resource,
+		ActorID:     record.ActorID,
+		Actor:       record.Actor,
+		SourceIP:    record.SourceIP,
+		BeforeJSON:  string(record.Before),
+		AfterJSON:   string(record.After),
+		ChangesJSON: changesJSON,
+	}
+	if err := r.db.WithContext(ctx).Create(&entity).Error; err != nil {
+		return fmt.Errorf("falha ao gravar registro de auditoria: %w", err)
+	}
+	return nil
+}
+
+// ListAuditRecords retorna os registros do filtro, do mais recente ao mais
+// antigo, e o total sem paginação
+func (r *AuditRepository) ListAuditRecords(ctx context.Context, filter repository.AuditFilter) ([]*model.AuditRecord, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.AuditEntity{})
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.Resource != "" {
+		query = query.Where("resource = ?", filter.Resource)
+	}
+	if filter.Actor != "" {
+		query = query.Where("actor = ?", filter.Actor)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("occurred_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("occurred_at < ?", filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("falha ao contar registros de auditoria: %w", err)
+	}
+
+	var entities []model.AuditEntity
+	query = query.Order("occurred_at DESC").Offset(filter.Offset)
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if err := query.Find(&entities).Error; err != nil {
+		return nil, 0, fmt.Errorf("falha ao listar registros de auditoria: %w", err)
+	}
+
+	records := make([]*model.AuditRecord, 0, len(entities))
+	for i := range entities {
+		record, err := auditRecordFromEntity(&entities[i])
+		if err != nil {
+			return nil, 0, err
+		}
+		records = append(records, record)
+	}
+	return records, total, nil
+}
+
+// auditRecordFromEntity converte a entidade de banco no modelo de domínio
+func auditRecordFromEntity(entity *model.AuditEntity) (*model.AuditRecord, error) {
+	record := &model.AuditRecord{
+		ID:       entity.ID,
+		Time:     entity.Time,
+		Action:   entity.Action,
+		Resource: entity.Resource,
+		ActorID:  entity.ActorID,
+		Actor:    entity.Actor,
+		SourceIP: entity.SourceIP,
+	}
+	if entity.BeforeJSON != "" {
+		record.Before = json.RawMessage(entity.BeforeJSON)
+	}
+	if entity.AfterJSON != "" {
+		record.After = json.RawMessage(entity.AfterJSON)
+	}
+	if entity.ChangesJSON != "" {
+		if err := json.Unmarshal([]byte(entity.ChangesJSON), &record.Changes); err != nil {
+			return nil, fmt.Errorf("alterações do registro de auditoria %s inválidas: %w", entity.ID, err)
+		}
+	}
+	return record, nil
+}
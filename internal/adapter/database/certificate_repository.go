@@ -0,0 +1,50 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/internal/domain/repository"
+	"gorm.io/gorm"
+)
+
+// CertificateRepository implementa o armazenamento dos dados do ACME com GORM
+type CertificateRepository struct {
+	db *gorm.DB
+}
+
+// NewCertificateRepository cria um novo repositório de certificados
+func NewCertificateRepository(db *gorm.DB) *CertificateRepository {
+	return &CertificateRepository{db: db}
+}
+
+// GetCertificate obtém os dados gravados na chave
+func (r *CertificateRepository) GetCertificate(ctx context.Context, key string) ([]byte, error) {
+	var entity model.CertificateEntity
+	if err := r.db.WithContext(ctx).Where("name = ?", key).First(&entity).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repository.ErrCertificateNotFound
+		}
+		return nil, fmt.Errorf("falha ao buscar certificado: %w", err)
+	}
+	return []byte(entity.Data), nil
+}
+
+// PutCertificate grava os dados na chave, substituindo os anteriores
+func (r *CertificateRepository) PutCertificate(ctx context.Context, key string, data []byte) error {
+	entity := model.CertificateEntity{Name: key, Data: string(data)}
+	if err := r.db.WithContext(ctx).Save(&entity).Error; err != nil {
+		return fmt.Errorf("falha ao gravar certificado: %w", err)
+	}
+	return nil
+}
+
+// DeleteCertificate remove os dados da chave
+func (r *CertificateRepository) DeleteCertificate(ctx context.Context, key string) error {
+	if err := r.db.WithContext(ctx).Where("name = ?", key).Delete(&model.CertificateEntity{}).Error; err != nil {
+		return fmt.Errorf("falha ao remover certificado: %w", err)
+	}
+	return nil
+}
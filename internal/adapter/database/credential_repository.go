@@ -0,0 +1,167 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/internal/domain/repository"
+	"github.com/diillson/api-gateway-go/pkg/encryption"
+	"gorm.io/gorm"
+)
+
+// CredentialRepository implementa o armazenamento das credenciais de parceiros
+// com GORM. Os segredos são cifrados quando a criptografia em repouso está ativa.
+type CredentialRepository struct {
+	db        *gorm.DB
+	encryptor *encryption.Envelope
+}
+
+// NewCredentialRepository cria um novo repositório de credenciais. Com
+// encryptor nil os segredos ficam em texto puro.
+func NewCredentialRepository(db *gorm.DB, encryptor *encryption.Envelope) *CredentialRepository {
+	return &CredentialRepository{db: db, encryptor: encryptor}
+}
+
+// CreateCredential armazena uma nova credencial com o seu segredo
+func (r *CredentialRepository) CreateCredential(ctx context.Context, credential *model.Credential, secret string) error {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.CredentialEntity{}).
+		Where("identifier = ?", credential.Identifier).
+		Count(&count).Error
+	if err != nil {
+		return fmt.Errorf("falha ao verificar credencial existente: %w", err)
+	}
+	if count > 0 {
+		return repository.ErrCredentialExists
+	}
+
+	routesJSON, err := json.Marshal(credential.Routes)
+	if err != nil {
+		return fmt.Errorf("falha ao serializar rotas da credencial: %w", err)
+	}
+	if r.encryptor != nil {
+		if secret, err = r.encryptor.EncryptString(secret); err != nil {
+			return fmt.Errorf("falha ao cifrar segredo da credencial: %w", err)
+		}
+	}
+
+	entity := model.CredentialEntity{
+		ID:         credential.ID,
+		Name:       credential.Name,
+		Type:       credential.Type,
+		Identifier: credential.Identifier,
+		Secret:     secret,
+		RoutesJSON: string(routesJSON),
+		ExpiresAt:  credential.ExpiresAt,
+	}
+	if err := r.db.WithContext(ctx).Create(&entity).Error; err != nil {
+		return fmt.Errorf("falha ao criar credencial: %w", err)
+	}
+	credential.CreatedAt = entity.CreatedAt
+	return nil
+}
+
+// GetCredentialByIdentifier obtém a credencial e o seu segredo, já decifrado
+func (r *CredentialRepository) GetCredentialByIdentifier(ctx context.Context, identifier string) (*model.Credential, string, error) {
+	var entity model.CredentialEntity
+	if err := r.db.WithContext(ctx).Where("identifier = ?", identifier).First(&entity).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", repository.ErrCredentialNotFound
+		}
+		return nil, "", fmt.Errorf("falha ao buscar credencial: %w", err)
+	}
+
+	secret := entity.Secret
+	if encryption.IsEncrypted(secret) {
+		if r.encryptor == nil {
+			return nil, "", fmt.Errorf("credencial %s está cifrada, mas a criptografia não está configurada", entity.ID)
+		}
+		plaintext, err := r.encryptor.DecryptString(secret)
+		if err != nil {
+			return nil, "", fmt.Errorf("falha ao decifrar segredo da credencial %s: %w", entity.ID, err)
+		}
+		secret = plaintext
+	}
+
+	credential, err := credentialFromEntity(&entity)
+	if err != nil {
+		return nil, "", err
+	}
+	return credential, secret, nil
+}
+
+// ListCredentials retorna todas as credenciais, inclusive as revogadas
+func (r *CredentialRepository) ListCredentials(ctx context.Context) ([]*model.Credential, error) {
+	var entities []model.CredentialEntity
+	if err := r.db.WithContext(ctx).Order("created_at").Find(&entities).Error; err != nil {
+		return nil, fmt.Errorf("falha ao listar credenciais: %w", err)
+	}
+
+	credentials := make([]*model.Credential, 0, len(entities))
+	for i := range entities {
+		credential, err := credentialFromEntity(&entities[i])
+		if err != nil {
+			return nil, err
+		}
+		credentials = append(credentials, credential)
+	}
+	return credentials, nil
+}
+
+// RevokeCredential revoga a credencial pelo ID e retorna o seu identificador
+func (r *CredentialRepository) RevokeCredential(ctx context.Context, id string) (string, error) {
+	var entity model.CredentialEntity
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&entity).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", repository.ErrCredentialNotFound
+		}
+		return "", fmt.Errorf("falha ao buscar credencial: %w", err)
+	}
+
+	now := time.Now()
+	err := r.db.WithContext(ctx).Model(&model.CredentialEntity{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"revoked": true, "revoked_at": now}).Error
+	if err != nil {
+		return "", fmt.Errorf("falha ao revogar credencial: %w", err)
+	}
+	return entity.Identifier, nil
+}
+
+// TouchCredential registra o último uso da credencial
+func (r *CredentialRepository) TouchCredential(ctx context.Context, id string, usedAt time.Time) error {
+	err := r.db.WithContext(ctx).Model(&model.CredentialEntity{}).
+		Where("id = ?", id).
+		Update("last_used_at", usedAt).Error
+	if err != nil {
+		return fmt.Errorf("falha ao registrar uso da credencial: %w", err)
+	}
+	return nil
+}
+
+// credentialFromEntity converte a entidade de banco no modelo de domínio
+func credentialFromEntity(entity *model.CredentialEntity) (*model.Credential, error) {
+	var routes []string
+	if entity.RoutesJSON != "" {
+		// Uma lista vazia libera todas as rotas, então rotas ilegíveis não podem ser ignoradas
+		if err := json.Unmarshal([]byte(entity.RoutesJSON), &routes); err != nil {
+			return nil, fmt.Errorf("rotas da credencial %s inválidas: %w", entity.ID, err)
+		}
+	}
+	return &model.Credential{
+		ID:         entity.ID,
+		Name:       entity.Name,
+		Type:       entity.Type,
+		Identifier: entity.Identifier,
+		Routes:     routes,
+		Revoked:    entity.Revoked,
+		ExpiresAt:  entity.ExpiresAt,
+		LastUsedAt: entity.LastUsedAt,
+		RevokedAt:  entity.RevokedAt,
+		CreatedAt:  entity.CreatedAt,
+	}, nil
+}
@@ -2,11 +2,13 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"time"
 
-	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/internal/infra/metrics"
 	"github.com/glebarez/sqlite"
+	mysqldriver "github.com/go-sql-driver/mysql"
 	"go.uber.org/zap"
 	"gorm.io/driver/mysql"
 	"gorm.io/driver/postgres"
@@ -21,6 +23,9 @@ type Config struct {
 	MaxIdleConns    int
 	MaxOpenConns    int
 	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+	PingTimeout     time.Duration
+	QueryTimeout    time.Duration // prazo de cada operação sem prazo no contexto; 0 desabilita
 	LogLevel        logger.LogLevel
 	SlowThreshold   time.Duration
 	MigrationDir    string
@@ -29,9 +34,10 @@ type Config struct {
 
 // Database gerencia a conexão com o banco de dados
 type Database struct {
-	db        *gorm.DB
-	logger    *zap.Logger
-	migration *MigrationManager
+	db          *gorm.DB
+	logger      *zap.Logger
+	migration   *MigrationManager
+	pingTimeout time.Duration
 }
 
 // NewDatabase cria uma nova instância do banco de dados
@@ -53,6 +59,8 @@ func NewDatabase(ctx context.Context, config Config, zapLogger *zap.Logger) (*Da
 		DisableForeignKeyConstraintWhenMigrating: true,
 		SkipDefaultTransaction:                   true,
 		PrepareStmt:                              true,
+		// Converte os erros de cada driver nos do GORM (ex.: gorm.ErrDuplicatedKey)
+		TranslateError: true,
 	}
 
 	// Conectar ao banco de dados
@@ -63,7 +71,11 @@ func NewDatabase(ctx context.Context, config Config, zapLogger *zap.Logger) (*Da
 	case "sqlite":
 		db, err = gorm.Open(sqlite.Open(config.DSN), gormConfig)
 	case "mysql":
-		db, err = gorm.Open(mysql.Open(config.DSN), gormConfig)
+		dsn, dsnErr := mysqlDSN(config.DSN)
+		if dsnErr != nil {
+			return nil, dsnErr
+		}
+		db, err = gorm.Open(mysql.Open(dsn), gormConfig)
 	case "postgres":
 		db, err = gorm.Open(postgres.Open(config.DSN), gormConfig)
 	default:
@@ -74,6 +86,14 @@ func NewDatabase(ctx context.Context, config Config, zapLogger *zap.Logger) (*Da
 		return nil, fmt.Errorf("falha ao conectar ao banco de dados: %w", err)
 	}
 
+	// Medir a latência das operações nas métricas OpenTelemetry
+	if err := registerQueryMetrics(db); err != nil {
+		return nil, fmt.Errorf("falha ao registrar métricas do banco de dados: %w", err)
+	}
+	if err := registerQueryTimeout(db, config.QueryTimeout); err != nil {
+		return nil, fmt.Errorf("falha ao registrar prazo das operações do banco de dados: %w", err)
+	}
+
 	// Configurar pool de conexões
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -83,6 +103,7 @@ func NewDatabase(ctx context.Context, config Config, zapLogger *zap.Logger) (*Da
 	sqlDB.SetMaxIdleConns(config.MaxIdleConns)
 	sqlDB.SetMaxOpenConns(config.MaxOpenConns)
 	sqlDB.SetConnMaxLifetime(config.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(config.ConnMaxIdleTime)
 
 	// Testar conexão
 	if err := sqlDB.PingContext(ctx); err != nil {
@@ -93,15 +114,16 @@ func NewDatabase(ctx context.Context, config Config, zapLogger *zap.Logger) (*Da
 	migration := NewMigrationManager(db, zapLogger, config.MigrationDir)
 
 	database := &Database{
-		db:        db,
-		logger:    zapLogger,
-		migration: migration,
+		db:          db,
+		logger:      zapLogger,
+		migration:   migration,
+		pingTimeout: config.PingTimeout,
 	}
 
 	// Aplicar migrações apenas se não forem puladas
 	if !config.SkipMigrations {
-		if err := database.migrate(ctx); err != nil {
-			return nil, fmt.Errorf("falha ao aplicar migrações: %w", err)
+		if err := database.Migrate(ctx); err != nil {
+			return nil, err
 		}
 	} else {
 		zapLogger.Info("Migrações foram puladas devido à configuração")
@@ -110,18 +132,74 @@ func NewDatabase(ctx context.Context, config Config, zapLogger *zap.Logger) (*Da
 	return database, nil
 }
 
+// mysqlDSN habilita a conversão das colunas de data em time.Time, exigida
+// pelas entidades, mesmo quando o DSN configurado omite parseTime
+func mysqlDSN(dsn string) (string, error) {
+	cfg, err := mysqldriver.ParseDSN(dsn)
+	if err != nil {
+		return "", fmt.Errorf("DSN MySQL inválido: %w", err)
+	}
+	cfg.ParseTime = true
+	return cfg.FormatDSN(), nil
+}
+
 // DB retorna a instância do GORM DB
 func (d *Database) DB() *gorm.DB {
 	return d.db
 }
 
-// Ping verifica a conexão com o banco de dados
+// Ping verifica a conexão com o banco de dados, respeitando o timeout configurado
 func (d *Database) Ping(ctx context.Context) error {
 	sqlDB, err := d.db.DB()
 	if err != nil {
 		return err
 	}
-	return sqlDB.PingContext(ctx)
+
+	if d.pingTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.pingTimeout)
+		defer cancel()
+	}
+
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return fmt.Errorf("falha no ping ao banco de dados: %w", err)
+	}
+	return nil
+}
+
+// Stats retorna as estatísticas atuais do pool de conexões
+func (d *Database) Stats() sql.DBStats {
+	sqlDB, err := d.db.DB()
+	if err != nil {
+		return sql.DBStats{}
+	}
+	return sqlDB.Stats()
+}
+
+// StartPoolMetrics publica periodicamente as estatísticas do pool nas métricas
+// até que o contexto seja cancelado
+func (d *Database) StartPoolMetrics(ctx context.Context, name string, interval time.Duration, apiMetrics *metrics.APIMetrics) {
+	if apiMetrics == nil {
+		return
+	}
+	if interval <= 0 {
+		interval = 15 * time.Second
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		apiMetrics.UpdateDBPoolStats(name, d.Stats())
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				apiMetrics.UpdateDBPoolStats(name, d.Stats())
+			}
+		}
+	}()
 }
 
 // Close fecha a conexão com o banco de dados
@@ -133,8 +211,9 @@ func (d *Database) Close() error {
 	return sqlDB.Close()
 }
 
-// migrate aplica migrações de banco de dados
-func (d *Database) migrate(ctx context.Context) error {
+// Migrate aplica as migrações pendentes. O esquema de todas as tabelas vem
+// das migrações versionadas, então uma falha impede o uso do banco.
+func (d *Database) Migrate(ctx context.Context) error {
 	// Verificar o driver para ajustes específicos
 	driver := d.db.Dialector.Name()
 
@@ -143,27 +222,25 @@ func (d *Database) migrate(ctx context.Context) error {
 		d.db.Exec("PRAGMA foreign_keys = ON")
 	}
 
-	// Auto migração para garantir que a tabela de rotas existe
-	if err := d.db.AutoMigrate(&model.RouteEntity{}); err != nil {
-		return fmt.Errorf("falha ao aplicar auto migração: %w", err)
-	}
-
-	// Aplicar migrações SQL
-	if d.migration != nil {
-		if err := d.migration.ApplyMigrations(ctx); err != nil {
-			// Log do erro, mas não falhe em caso de problemas com migrações
-			d.logger.Error("falha ao aplicar migrações SQL", zap.Error(err))
-		}
+	if err := d.migration.ApplyMigrations(ctx); err != nil {
+		return fmt.Errorf("falha ao aplicar migrações: %w", err)
 	}
 
 	return nil
 }
 
+// MigrationStatus lista as migrações aplicadas e pendentes
+func (d *Database) MigrationStatus(ctx context.Context) ([]MigrationStatus, error) {
+	return d.migration.Status(ctx)
+}
+
+// RollbackMigration reverte a última migração aplicada
+func (d *Database) RollbackMigration(ctx context.Context) (*MigrationStatus, error) {
+	return d.migration.Rollback(ctx)
+}
+
 // CreateMigration cria um novo arquivo de migração
 func (d *Database) CreateMigration(name string) (string, error) {
-	if d.migration == nil {
-		return "", fmt.Errorf("gerenciador de migrações não configurado")
-	}
 	return d.migration.CreateMigration(name)
 }
 
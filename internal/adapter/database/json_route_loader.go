@@ -9,19 +9,21 @@ import (
 	"time"
 
 	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/internal/domain/repository"
+	"github.com/diillson/api-gateway-go/pkg/tenant"
 	"go.uber.org/zap"
 )
 
 // JSONRouteLoader carrega rotas de um arquivo JSON
 type JSONRouteLoader struct {
-	db     *Database
+	repo   repository.RouteRepository
 	logger *zap.Logger
 }
 
-// NewJSONRouteLoader cria um novo carregador de rotas JSON
-func NewJSONRouteLoader(db *Database, logger *zap.Logger) *JSONRouteLoader {
+// NewJSONRouteLoader cria um novo carregador de rotas JSON que grava no repositório informado
+func NewJSONRouteLoader(repo repository.RouteRepository, logger *zap.Logger) *JSONRouteLoader {
 	return &JSONRouteLoader{
-		db:     db,
+		repo:   repo,
 		logger: logger,
 	}
 }
@@ -54,8 +56,7 @@ func (l *JSONRouteLoader) LoadRoutesFromJSON(filePath string) error {
 		return nil
 	}
 
-	// Criar o repositório de rotas
-	repo := NewRouteRepository(l.db.DB(), l.logger)
+	repo := l.repo
 
 	// Criar um contexto válido com timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -63,6 +64,9 @@ func (l *JSONRouteLoader) LoadRoutesFromJSON(filePath string) error {
 
 	// Inserir ou atualizar cada rota
 	for _, route := range routes {
+		// Cada rota é gravada no tenant informado no arquivo
+		ctx := tenant.WithTenant(ctx, route.Tenant)
+
 		// Verificar se a rota já existe
 		_, err := repo.GetRouteByPath(ctx, route.Path)
 		if err == nil {
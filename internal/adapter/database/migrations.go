@@ -12,10 +12,81 @@ import (
 	"strings"
 	"time"
 
+	"github.com/diillson/api-gateway-go/migrations"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+const (
+	// defaultMigrationDir é o diretório, no repositório, das migrações embutidas
+	// no binário; CreateMigration o usa quando nenhum diretório é configurado
+	defaultMigrationDir = "migrations/migrations"
+
+	// migrationLockName e migrationLockID identificam o bloqueio que impede
+	// instâncias iniciadas juntas de aplicar as mesmas migrações
+	migrationLockName = "api_gateway_migrations"
+	migrationLockID   = 72401160
+
+	// migrationLockTimeout é a espera pelo bloqueio no MySQL, em segundos
+	migrationLockTimeout = 300
+
+	// migrationDownMarker separa, no arquivo, os comandos que aplicam a migração
+	// dos que a revertem (usados pelo rollback)
+	migrationDownMarker = "-- migrate:down"
+
+	// baseRoutesVersion é a migração com o esquema base da tabela de rotas
+	baseRoutesVersion = 20230101000000
+
+	// rateLimitVersion é a migração com as colunas de limite de taxa das rotas
+	rateLimitVersion = 20230515123000
+)
+
+// migrationDrivers são os sufixos aceitos nas migrações específicas de um driver
+// (ex.: 20230101000000_create_table_routes.postgres.sql)
+var migrationDrivers = map[string]bool{"sqlite": true, "mysql": true, "postgres": true}
+
+// baseRouteColumns são as colunas do esquema base da tabela de rotas
+// (20230101000000_create_table_routes) que não existiam na primeira versão da
+// tabela. Antes das migrações versionadas a tabela era criada e ampliada pela
+// migração automática do GORM, então um banco dessa época pode não ter
+// algumas delas; adoptRoutesTable as adiciona antes de registrar a migração base.
+var baseRouteColumns = []struct {
+	name       string
+	definition string
+}{
+	{"upstream_auth", "TEXT"},
+	{"upstream_tls", "TEXT"},
+	{"egress_proxy", "TEXT"},
+	{"response_cache", "TEXT"},
+	{"access_schedule", "TEXT"},
+	{"labels", "TEXT"},
+	{"cost_attribution", "TEXT"},
+	{"health_check", "TEXT"},
+	{"telemetry", "TEXT"},
+	{"targets", "TEXT"},
+	{"load_balancing", "TEXT"},
+	{"circuit_breaker", "TEXT"},
+	{"canary", "TEXT"},
+	{"match_conditions", "TEXT"},
+	{"variants", "TEXT"},
+	{"websocket", "TEXT"},
+	{"protocol", "TEXT"},
+	{"grpc_web", "BOOLEAN DEFAULT FALSE"},
+	{"auth_policy", "TEXT"},
+	{"authorization", "TEXT"},
+	{"external_authz", "TEXT"},
+	{"ip_filter", "TEXT"},
+	{"header_transforms", "TEXT"},
+	{"path_rewrite", "TEXT"},
+	{"body_transforms", "TEXT"},
+	{"upstream_timeout", "TEXT"},
+	{"retry_policy", "TEXT"},
+	{"hedging_policy", "TEXT"},
+	{"bulkhead_policy", "TEXT"},
+	{"openapi_spec", "TEXT"},
+}
+
 // Migration representa uma migração de banco de dados
 type Migration struct {
 	ID        uint  `gorm:"primaryKey"`
@@ -24,7 +95,15 @@ type Migration struct {
 	AppliedAt time.Time
 }
 
-// MigrationManager gerencia migrações de banco de dados
+// MigrationStatus descreve a situação de uma migração no banco
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	AppliedAt *time.Time // nil quando pendente
+}
+
+// MigrationManager gerencia migrações de banco de dados. As migrações são lidas
+// do diretório configurado ou, sem diretório, das embutidas no binário.
 type MigrationManager struct {
 	db        *gorm.DB
 	logger    *zap.Logger
@@ -40,104 +119,391 @@ func NewMigrationManager(db *gorm.DB, logger *zap.Logger, directory string) *Mig
 	}
 }
 
+// source retorna o sistema de arquivos com as migrações
+func (m *MigrationManager) source() fs.FS {
+	if m.directory == "" {
+		return migrations.FS()
+	}
+	return os.DirFS(m.directory)
+}
+
 // Initialize inicializa a tabela de migrações
 func (m *MigrationManager) Initialize(ctx context.Context) error {
+	return m.initialize(m.db.WithContext(ctx))
+}
+
+// initialize cria a tabela de migrações na conexão informada
+func (m *MigrationManager) initialize(db *gorm.DB) error {
 	// Cria a tabela de migrações se não existir
-	if err := m.db.WithContext(ctx).AutoMigrate(&Migration{}); err != nil {
+	if err := db.AutoMigrate(&Migration{}); err != nil {
 		return fmt.Errorf("falha ao criar tabela de migrações: %w", err)
 	}
 
 	return nil
 }
 
-// ApplyMigrations aplica todas as migrações pendentes
+// ApplyMigrations aplica todas as migrações pendentes, em ordem de versão
 func (m *MigrationManager) ApplyMigrations(ctx context.Context) error {
-	// Inicializar a tabela de migrações
-	if err := m.Initialize(ctx); err != nil {
-		return err
-	}
+	// Migrações podem demorar mais que o prazo das operações comuns
+	ctx = withoutQueryTimeout(ctx)
 
-	// Buscar migrações já aplicadas
-	var appliedMigrations []Migration
-	if err := m.db.WithContext(ctx).Order("version").Find(&appliedMigrations).Error; err != nil {
-		return fmt.Errorf("falha ao buscar migrações aplicadas: %w", err)
-	}
+	return m.withLock(ctx, func(conn *gorm.DB) error {
+		// Inicializar a tabela de migrações
+		if err := m.initialize(conn); err != nil {
+			return err
+		}
 
-	// Mapear migrações aplicadas pelo número de versão
-	appliedVersions := make(map[int64]bool)
-	for _, migration := range appliedMigrations {
-		appliedVersions[migration.Version] = true
-	}
+		// Buscar migrações já aplicadas
+		appliedMigrations, err := m.applied(conn)
+		if err != nil {
+			return err
+		}
 
-	// Listar arquivos de migração
-	migrationFiles, err := m.findMigrationFiles()
-	if err != nil {
-		return fmt.Errorf("falha ao listar arquivos de migração: %w", err)
-	}
+		// Mapear migrações aplicadas pelo número de versão
+		appliedVersions := make(map[int64]bool)
+		for _, migration := range appliedMigrations {
+			appliedVersions[migration.Version] = true
+		}
+
+		// Completar a tabela de rotas de bancos anteriores às migrações versionadas
+		if !appliedVersions[baseRoutesVersion] {
+			if err := m.adoptRoutesTable(conn, appliedVersions); err != nil {
+				return err
+			}
+		}
+
+		// Listar arquivos de migração, já ordenados por versão
+		migrationFiles, err := m.findMigrationFiles(conn.Dialector.Name())
+		if err != nil {
+			return fmt.Errorf("falha ao listar arquivos de migração: %w", err)
+		}
+
+		// Aplicar migrações pendentes em transação
+		pending := 0
+		for _, file := range migrationFiles {
+			if appliedVersions[file.Version] {
+				continue
+			}
+			pending++
+
+			m.logger.Info("Aplicando migração", zap.Int64("version", file.Version), zap.String("name", file.Name))
+
+			if err := m.apply(conn, file); err != nil {
+				return fmt.Errorf("migração %d_%s: %w", file.Version, file.Name, err)
+			}
+
+			m.logger.Info("Migração aplicada com sucesso", zap.Int64("version", file.Version), zap.String("name", file.Name))
+		}
 
-	// Ordenar os arquivos por número de versão
-	sort.Slice(migrationFiles, func(i, j int) bool {
-		return migrationFiles[i].Version < migrationFiles[j].Version
+		m.logger.Info("Esquema do banco de dados atualizado",
+			zap.Int("applied", pending),
+			zap.Int("total", len(migrationFiles)))
+
+		return nil
 	})
+}
 
-	// Aplicar migrações pendentes em transação
-	for _, file := range migrationFiles {
-		if appliedVersions[file.Version] {
-			m.logger.Info("Migração já aplicada", zap.Int64("version", file.Version), zap.String("name", file.Name))
+// adoptRoutesTable ajusta a tabela de rotas existente ao esquema base, de que
+// as migrações seguintes dependem. Sem a tabela, a migração base a cria completa.
+func (m *MigrationManager) adoptRoutesTable(conn *gorm.DB, appliedVersions map[int64]bool) error {
+	migrator := conn.Migrator()
+	if !migrator.HasTable("routes") {
+		return nil
+	}
+
+	for _, column := range baseRouteColumns {
+		if migrator.HasColumn("routes", column.name) {
 			continue
 		}
 
-		m.logger.Info("Aplicando migração", zap.Int64("version", file.Version), zap.String("name", file.Name))
+		m.logger.Info("Adicionando coluna ausente à tabela de rotas", zap.String("column", column.name))
+		if err := conn.Exec("ALTER TABLE routes ADD COLUMN ? "+column.definition, clause.Column{Name: column.name}).Error; err != nil {
+			return fmt.Errorf("falha ao adicionar coluna %s à tabela de rotas: %w", column.name, err)
+		}
+	}
 
-		// Ler o conteúdo do arquivo
-		content, err := os.ReadFile(file.Path)
-		if err != nil {
-			return fmt.Errorf("falha ao ler arquivo de migração: %w", err)
+	// A migração automática nomeia o índice único do caminho idx_routes_path;
+	// no MySQL o esquema base o chama path, nome removido pela migração de tenant
+	if conn.Dialector.Name() == "mysql" && migrator.HasIndex("routes", "idx_routes_path") {
+		if err := migrator.RenameIndex("routes", "idx_routes_path", "path"); err != nil {
+			return fmt.Errorf("falha ao renomear índice do caminho das rotas: %w", err)
 		}
+	}
 
-		// Iniciar uma transação
-		tx := m.db.WithContext(ctx).Begin()
-		if tx.Error != nil {
-			return fmt.Errorf("falha ao iniciar transação: %w", tx.Error)
+	// No MySQL, versões anteriores aplicavam a migração genérica de limite de
+	// taxa, que adicionava as colunas e falhava ao criar os índices, sem ser
+	// registrada (comandos DDL não são desfeitos). Nesse caso os índices que
+	// faltam são criados e a migração é registrada, em vez de repetir as colunas.
+	if appliedVersions[rateLimitVersion] || !migrator.HasColumn("routes", "rate_limit_per_minute") {
+		return nil
+	}
+
+	for _, column := range []struct{ name, definition string }{
+		{"rate_limit_per_hour", "INT DEFAULT 0"},
+		{"rate_limit_burst_factor", "DOUBLE PRECISION DEFAULT 1.5"},
+	} {
+		if migrator.HasColumn("routes", column.name) {
+			continue
 		}
+		if err := conn.Exec("ALTER TABLE routes ADD COLUMN ? "+column.definition, clause.Column{Name: column.name}).Error; err != nil {
+			return fmt.Errorf("falha ao adicionar coluna %s à tabela de rotas: %w", column.name, err)
+		}
+	}
+	for _, index := range []struct{ name, column string }{
+		{"idx_routes_call_count", "call_count"},
+		{"idx_routes_is_active", "is_active"},
+	} {
+		if migrator.HasIndex("routes", index.name) {
+			continue
+		}
+		if err := conn.Exec("CREATE INDEX ? ON routes (?)", clause.Column{Name: index.name}, clause.Column{Name: index.column}).Error; err != nil {
+			return fmt.Errorf("falha ao criar índice %s na tabela de rotas: %w", index.name, err)
+		}
+	}
 
-		// Dividir o conteúdo em comandos SQL individuais
-		sqlCommands := splitSQLCommands(string(content))
+	m.logger.Info("Registrando migração de limite de taxa aplicada parcialmente por versão anterior")
+	if err := conn.Create(&Migration{
+		Version:   rateLimitVersion,
+		Name:      "add_rate_limit_to_routes",
+		AppliedAt: time.Now(),
+	}).Error; err != nil {
+		return fmt.Errorf("falha ao registrar migração: %w", err)
+	}
+	appliedVersions[rateLimitVersion] = true
 
-		// Executar cada comando SQL individualmente
-		for _, sqlCmd := range sqlCommands {
-			sqlCmd = strings.TrimSpace(sqlCmd)
-			if sqlCmd == "" {
-				continue
-			}
+	return nil
+}
 
-			if err := tx.Exec(sqlCmd).Error; err != nil {
-				tx.Rollback()
-				return fmt.Errorf("falha ao executar migração: %w", err)
-			}
-		}
+// apply executa uma migração e a registra na mesma transação. No MySQL,
+// comandos DDL confirmam a transação implicitamente.
+func (m *MigrationManager) apply(conn *gorm.DB, file MigrationFile) error {
+	up, _, err := m.read(file)
+	if err != nil {
+		return err
+	}
 
+	return m.run(conn, up, func(tx *gorm.DB) error {
 		// Registrar a migração como aplicada
 		if err := tx.Create(&Migration{
 			Version:   file.Version,
 			Name:      file.Name,
 			AppliedAt: time.Now(),
 		}).Error; err != nil {
-			tx.Rollback()
 			return fmt.Errorf("falha ao registrar migração: %w", err)
 		}
+		return nil
+	})
+}
+
+// Rollback reverte a última migração aplicada, executando a seção do arquivo
+// após a linha "-- migrate:down". Migrações sem essa seção não podem ser
+// revertidas; nesse caso a correção é feita por uma nova migração.
+func (m *MigrationManager) Rollback(ctx context.Context) (*MigrationStatus, error) {
+	ctx = withoutQueryTimeout(ctx)
+
+	var reverted *MigrationStatus
+	err := m.withLock(ctx, func(conn *gorm.DB) error {
+		if err := m.initialize(conn); err != nil {
+			return err
+		}
+
+		appliedMigrations, err := m.applied(conn)
+		if err != nil {
+			return err
+		}
+		if len(appliedMigrations) == 0 {
+			return errors.New("nenhuma migração aplicada para reverter")
+		}
+		last := appliedMigrations[len(appliedMigrations)-1]
+
+		migrationFiles, err := m.findMigrationFiles(conn.Dialector.Name())
+		if err != nil {
+			return fmt.Errorf("falha ao listar arquivos de migração: %w", err)
+		}
+
+		var file *MigrationFile
+		for i := range migrationFiles {
+			if migrationFiles[i].Version == last.Version {
+				file = &migrationFiles[i]
+				break
+			}
+		}
+		if file == nil {
+			return fmt.Errorf("arquivo da migração %d_%s não encontrado", last.Version, last.Name)
+		}
+
+		_, down, err := m.read(*file)
+		if err != nil {
+			return err
+		}
+		if strings.TrimSpace(down) == "" {
+			return fmt.Errorf("migração %d_%s não pode ser revertida: o arquivo não tem a seção %q",
+				file.Version, file.Name, migrationDownMarker)
+		}
+
+		m.logger.Info("Revertendo migração", zap.Int64("version", file.Version), zap.String("name", file.Name))
+
+		err = m.run(conn, down, func(tx *gorm.DB) error {
+			// Remover o registro da migração
+			if err := tx.Where("version = ?", file.Version).Delete(&Migration{}).Error; err != nil {
+				return fmt.Errorf("falha ao remover registro da migração: %w", err)
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("migração %d_%s: %w", file.Version, file.Name, err)
+		}
+
+		m.logger.Info("Migração revertida com sucesso", zap.Int64("version", file.Version), zap.String("name", file.Name))
+		reverted = &MigrationStatus{Version: file.Version, Name: file.Name}
+		return nil
+	})
+
+	return reverted, err
+}
+
+// read lê o arquivo da migração e o divide nas seções de aplicação e reversão
+func (m *MigrationManager) read(file MigrationFile) (up, down string, err error) {
+	content, err := fs.ReadFile(m.source(), file.Path)
+	if err != nil {
+		return "", "", fmt.Errorf("falha ao ler arquivo de migração: %w", err)
+	}
+
+	up, down = splitMigration(string(content))
+	return up, down, nil
+}
+
+// run executa os comandos SQL e a atualização da tabela de migrações (record)
+// em uma transação
+func (m *MigrationManager) run(conn *gorm.DB, sql string, record func(tx *gorm.DB) error) error {
+	// Iniciar uma transação
+	tx := conn.Begin()
+	if tx.Error != nil {
+		return fmt.Errorf("falha ao iniciar transação: %w", tx.Error)
+	}
+
+	// Executar cada comando SQL individualmente
+	for _, sqlCmd := range splitSQLCommands(sql) {
+		sqlCmd = strings.TrimSpace(sqlCmd)
+		if sqlCmd == "" {
+			continue
+		}
 
-		// Commit da transação
-		if err := tx.Commit().Error; err != nil {
-			return fmt.Errorf("falha ao confirmar transação: %w", err)
+		if err := tx.Exec(sqlCmd).Error; err != nil {
+			tx.Rollback()
+			return fmt.Errorf("falha ao executar migração: %w", err)
 		}
+	}
 
-		m.logger.Info("Migração aplicada com sucesso", zap.Int64("version", file.Version), zap.String("name", file.Name))
+	if err := record(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Commit da transação
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("falha ao confirmar transação: %w", err)
 	}
 
 	return nil
 }
 
+// splitMigration separa o conteúdo da migração na seção que a aplica e na que
+// a reverte, iniciada pela linha "-- migrate:down"
+func splitMigration(content string) (up, down string) {
+	lines := strings.SplitAfter(content, "\n")
+	for i, line := range lines {
+		if strings.EqualFold(strings.TrimSpace(line), migrationDownMarker) {
+			return strings.Join(lines[:i], ""), strings.Join(lines[i+1:], "")
+		}
+	}
+	return content, ""
+}
+
+// Status lista as migrações conhecidas, aplicadas ou pendentes, em ordem de versão
+func (m *MigrationManager) Status(ctx context.Context) ([]MigrationStatus, error) {
+	if err := m.Initialize(ctx); err != nil {
+		return nil, err
+	}
+
+	appliedMigrations, err := m.applied(m.db.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	migrationFiles, err := m.findMigrationFiles(m.db.Dialector.Name())
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar arquivos de migração: %w", err)
+	}
+
+	statuses := make(map[int64]*MigrationStatus, len(migrationFiles))
+	for _, file := range migrationFiles {
+		statuses[file.Version] = &MigrationStatus{Version: file.Version, Name: file.Name}
+	}
+	// Migrações aplicadas por versões mais novas do binário também aparecem
+	for _, migration := range appliedMigrations {
+		appliedAt := migration.AppliedAt
+		status, ok := statuses[migration.Version]
+		if !ok {
+			status = &MigrationStatus{Version: migration.Version, Name: migration.Name}
+			statuses[migration.Version] = status
+		}
+		status.AppliedAt = &appliedAt
+	}
+
+	result := make([]MigrationStatus, 0, len(statuses))
+	for _, status := range statuses {
+		result = append(result, *status)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Version < result[j].Version
+	})
+
+	return result, nil
+}
+
+// applied busca as migrações já aplicadas
+func (m *MigrationManager) applied(db *gorm.DB) ([]Migration, error) {
+	var appliedMigrations []Migration
+	if err := db.Order("version").Find(&appliedMigrations).Error; err != nil {
+		return nil, fmt.Errorf("falha ao buscar migrações aplicadas: %w", err)
+	}
+	return appliedMigrations, nil
+}
+
+// withLock executa fn em uma única conexão, com o bloqueio de migrações do
+// banco. O SQLite dispensa o bloqueio, por ser acessado por uma só instância.
+func (m *MigrationManager) withLock(ctx context.Context, fn func(conn *gorm.DB) error) error {
+	return m.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		switch conn.Dialector.Name() {
+		case "postgres":
+			if err := conn.Exec("SELECT pg_advisory_lock(?)", migrationLockID).Error; err != nil {
+				return fmt.Errorf("falha ao obter bloqueio de migrações: %w", err)
+			}
+			defer func() {
+				if err := conn.Exec("SELECT pg_advisory_unlock(?)", migrationLockID).Error; err != nil {
+					m.logger.Warn("Falha ao liberar bloqueio de migrações", zap.Error(err))
+				}
+			}()
+		case "mysql":
+			var acquired int
+			if err := conn.Raw("SELECT GET_LOCK(?, ?)", migrationLockName, migrationLockTimeout).Scan(&acquired).Error; err != nil {
+				return fmt.Errorf("falha ao obter bloqueio de migrações: %w", err)
+			}
+			if acquired != 1 {
+				return errors.New("tempo esgotado aguardando bloqueio de migrações")
+			}
+			defer func() {
+				if err := conn.Exec("SELECT RELEASE_LOCK(?)", migrationLockName).Error; err != nil {
+					m.logger.Warn("Falha ao liberar bloqueio de migrações", zap.Error(err))
+				}
+			}()
+		}
+
+		return fn(conn)
+	})
+}
+
 // Função auxiliar para dividir o SQL em comandos individuais
 func splitSQLCommands(sql string) []string {
 	// Dividir por ponto e vírgula, mas ignorar ponto e vírgula dentro de strings ou comentários
@@ -212,43 +578,66 @@ type MigrationFile struct {
 	Path    string
 }
 
-// findMigrationFiles encontra todos os arquivos de migração .sql
-func (m *MigrationManager) findMigrationFiles() ([]MigrationFile, error) {
-	var files []MigrationFile
+// findMigrationFiles encontra os arquivos de migração .sql do driver, ordenados
+// por versão. Um arquivo específico do driver (<versão>_<nome>.<driver>.sql)
+// substitui o genérico de mesma versão; os de outros drivers são ignorados.
+func (m *MigrationManager) findMigrationFiles(driver string) ([]MigrationFile, error) {
+	files := make(map[int64]MigrationFile)
+	specific := make(map[int64]bool)
 
-	err := filepath.Walk(m.directory, func(path string, info fs.FileInfo, err error) error {
+	err := fs.WalkDir(m.source(), ".", func(filePath string, entry fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
 
-		if info.IsDir() {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
 			return nil
 		}
 
-		if !strings.HasSuffix(info.Name(), ".sql") {
-			return nil
+		// Extrair versão e nome do arquivo (formato: YYYYMMDDHHMMSS_name[.driver].sql)
+		base := strings.TrimSuffix(entry.Name(), ".sql")
+		fileDriver := ""
+		if i := strings.LastIndex(base, "."); i >= 0 {
+			fileDriver = base[i+1:]
+			base = base[:i]
+			if !migrationDrivers[fileDriver] {
+				m.logger.Warn("Driver de migração desconhecido", zap.String("file", entry.Name()))
+				return nil
+			}
+			if fileDriver != driver {
+				return nil
+			}
 		}
 
-		// Extrair versão e nome do arquivo (formato: YYYYMMDDHHMMSS_name.sql)
-		parts := strings.SplitN(info.Name(), "_", 2)
+		parts := strings.SplitN(base, "_", 2)
 		if len(parts) != 2 {
-			m.logger.Warn("Formato de arquivo de migração inválido", zap.String("file", info.Name()))
+			m.logger.Warn("Formato de arquivo de migração inválido", zap.String("file", entry.Name()))
 			return nil
 		}
 
 		version, err := strconv.ParseInt(parts[0], 10, 64)
 		if err != nil {
-			m.logger.Warn("Versão de migração inválida", zap.String("file", info.Name()))
+			m.logger.Warn("Versão de migração inválida", zap.String("file", entry.Name()))
 			return nil
 		}
 
-		name := strings.TrimSuffix(parts[1], ".sql")
-
-		files = append(files, MigrationFile{
+		file := MigrationFile{
 			Version: version,
-			Name:    name,
-			Path:    path,
-		})
+			Name:    parts[1],
+			Path:    filePath,
+		}
+
+		if existing, ok := files[version]; ok {
+			if existing.Name != file.Name {
+				return fmt.Errorf("versão de migração duplicada %d: %s e %s", version, existing.Path, filePath)
+			}
+			// O arquivo do driver prevalece sobre o genérico
+			if specific[version] || fileDriver == "" {
+				return nil
+			}
+		}
+		files[version] = file
+		specific[version] = fileDriver != ""
 
 		return nil
 	})
@@ -261,7 +650,15 @@ func (m *MigrationManager) findMigrationFiles() ([]MigrationFile, error) {
 		return nil, errors.New("nenhum arquivo de migração encontrado")
 	}
 
-	return files, nil
+	result := make([]MigrationFile, 0, len(files))
+	for _, file := range files {
+		result = append(result, file)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Version < result[j].Version
+	})
+
+	return result, nil
 }
 
 // CreateMigration cria um novo arquivo de migração
@@ -272,14 +669,19 @@ func (m *MigrationManager) CreateMigration(name string) (string, error) {
 	// Gerar timestamp para versão
 	timestamp := time.Now().Format("20060102150405")
 
+	directory := m.directory
+	if directory == "" {
+		directory = defaultMigrationDir
+	}
+
 	// Criar o diretório se não existir
-	if err := os.MkdirAll(m.directory, 0755); err != nil {
+	if err := os.MkdirAll(directory, 0755); err != nil {
 		return "", fmt.Errorf("falha ao criar diretório: %w", err)
 	}
 
 	// Nome do arquivo
 	filename := fmt.Sprintf("%s_%s.sql", timestamp, name)
-	filepath := filepath.Join(m.directory, filename)
+	filepath := filepath.Join(directory, filename)
 
 	// Criar arquivo vazio
 	file, err := os.Create(filepath)
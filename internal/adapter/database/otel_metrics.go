@@ -0,0 +1,63 @@
+package database
+
+import (
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"gorm.io/gorm"
+)
+
+// queryStartKey guarda o início da operação na instância do GORM
+const queryStartKey = "otel:query_start"
+
+// queryDuration mede a latência das operações no banco de dados. Sem a
+// exportação OpenTelemetry habilitada, o registro não tem efeito.
+var queryDuration, _ = otel.Meter("api-gateway.database").Float64Histogram(
+	"api_gateway.db.query.duration",
+	metric.WithDescription("Duração das operações no banco de dados"),
+	metric.WithUnit("s"),
+)
+
+// registerQueryMetrics registra callbacks do GORM que medem a duração de cada
+// operação, identificada pelo tipo e pela tabela
+func registerQueryMetrics(db *gorm.DB) error {
+	before := func(tx *gorm.DB) {
+		tx.InstanceSet(queryStartKey, time.Now())
+	}
+	after := func(operation string) func(*gorm.DB) {
+		return func(tx *gorm.DB) {
+			value, ok := tx.InstanceGet(queryStartKey)
+			if !ok {
+				return
+			}
+			start, ok := value.(time.Time)
+			if !ok {
+				return
+			}
+			queryDuration.Record(tx.Statement.Context, time.Since(start).Seconds(), metric.WithAttributes(
+				attribute.String("db.operation", operation),
+				attribute.String("db.table", tx.Statement.Table),
+				attribute.Bool("error", tx.Error != nil && tx.Error != gorm.ErrRecordNotFound),
+			))
+		}
+	}
+
+	callbacks := db.Callback()
+	return errors.Join(
+		callbacks.Create().Before("gorm:create").Register("otel:before_create", before),
+		callbacks.Create().After("gorm:create").Register("otel:after_create", after("create")),
+		callbacks.Query().Before("gorm:query").Register("otel:before_query", before),
+		callbacks.Query().After("gorm:query").Register("otel:after_query", after("query")),
+		callbacks.Update().Before("gorm:update").Register("otel:before_update", before),
+		callbacks.Update().After("gorm:update").Register("otel:after_update", after("update")),
+		callbacks.Delete().Before("gorm:delete").Register("otel:before_delete", before),
+		callbacks.Delete().After("gorm:delete").Register("otel:after_delete", after("delete")),
+		callbacks.Row().Before("gorm:row").Register("otel:before_row", before),
+		callbacks.Row().After("gorm:row").Register("otel:after_row", after("row")),
+		callbacks.Raw().Before("gorm:raw").Register("otel:before_raw", before),
+		callbacks.Raw().After("gorm:raw").Register("otel:after_raw", after("raw")),
+	)
+}
@@ -0,0 +1,65 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// queryCancelKey guarda o cancelamento do prazo da operação na instância do GORM
+const queryCancelKey = "timeout:cancel"
+
+type noQueryTimeoutKey struct{}
+
+// withoutQueryTimeout dispensa as operações do contexto do prazo por operação,
+// para operações longas como as migrações
+func withoutQueryTimeout(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noQueryTimeoutKey{}, true)
+}
+
+// registerQueryTimeout registra callbacks do GORM que limitam a duração de
+// cada operação, do primeiro ao último callback (incluindo preloads e hooks).
+// Contextos que já têm prazo ou que dispensam o prazo são mantidos. Consultas que retornam linhas ao
+// chamador (Row e Rows) ficam de fora, pois a leitura continua depois dos
+// callbacks.
+func registerQueryTimeout(db *gorm.DB, timeout time.Duration) error {
+	if timeout <= 0 {
+		return nil
+	}
+
+	before := func(tx *gorm.DB) {
+		ctx := tx.Statement.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		if _, ok := ctx.Deadline(); ok || ctx.Value(noQueryTimeoutKey{}) != nil {
+			return
+		}
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		tx.Statement.Context = ctx
+		tx.InstanceSet(queryCancelKey, cancel)
+	}
+	after := func(tx *gorm.DB) {
+		if value, ok := tx.InstanceGet(queryCancelKey); ok {
+			if cancel, ok := value.(context.CancelFunc); ok {
+				cancel()
+			}
+		}
+	}
+
+	callbacks := db.Callback()
+	return errors.Join(
+		callbacks.Create().Before("*").Register("timeout:before_create", before),
+		callbacks.Create().After("*").Register("timeout:after_create", after),
+		callbacks.Query().Before("*").Register("timeout:before_query", before),
+		callbacks.Query().After("*").Register("timeout:after_query", after),
+		callbacks.Update().Before("*").Register("timeout:before_update", before),
+		callbacks.Update().After("*").Register("timeout:after_update", after),
+		callbacks.Delete().Before("*").Register("timeout:before_delete", before),
+		callbacks.Delete().After("*").Register("timeout:after_delete", after),
+		callbacks.Raw().Before("*").Register("timeout:before_raw", before),
+		callbacks.Raw().After("*").Register("timeout:after_raw", after),
+	)
+}
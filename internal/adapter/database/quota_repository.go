@@ -0,0 +1,86 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// QuotaRepository implementa o armazenamento do uso dos consumidores com GORM
+type QuotaRepository struct {
+	db *gorm.DB
+}
+
+// NewQuotaRepository cria um novo repositório de uso dos consumidores
+func NewQuotaRepository(db *gorm.DB) *QuotaRepository {
+	return &QuotaRepository{db: db}
+}
+
+// SaveUsage grava os totais informados, substituindo os já gravados
+func (r *QuotaRepository) SaveUsage(ctx context.Context, usage []model.ConsumerUsage) error {
+	if len(usage) == 0 {
+		return nil
+	}
+
+	entities := make([]model.ConsumerUsageEntity, 0, len(usage))
+	for _, u := range usage {
+		entities = append(entities, model.ConsumerUsageEntity{
+			Consumer:  u.Consumer,
+			Period:    u.Period,
+			Requests:  u.Requests,
+			UpdatedAt: u.UpdatedAt,
+		})
+	}
+
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "consumer"}, {Name: "period"}},
+		DoUpdates: clause.AssignmentColumns([]string{"requests", "updated_at"}),
+	}).Create(&entities).Error
+	if err != nil {
+		return fmt.Errorf("falha ao gravar uso dos consumidores: %w", err)
+	}
+	return nil
+}
+
+// GetUsage retorna o total gravado do consumidor no período; 0 se não houver
+func (r *QuotaRepository) GetUsage(ctx context.Context, consumer, period string) (int64, error) {
+	var entities []model.ConsumerUsageEntity
+	err := r.db.WithContext(ctx).
+		Where("consumer = ? AND period = ?", consumer, period).
+		Limit(1).
+		Find(&entities).Error
+	if err != nil {
+		return 0, fmt.Errorf("falha ao buscar uso do consumidor: %w", err)
+	}
+	if len(entities) == 0 {
+		return 0, nil
+	}
+	return entities[0].Requests, nil
+}
+
+// ListUsage retorna os períodos gravados do consumidor, do mais recente ao
+// mais antigo
+func (r *QuotaRepository) ListUsage(ctx context.Context, consumer string, limit int) ([]model.ConsumerUsage, error) {
+	var entities []model.ConsumerUsageEntity
+	query := r.db.WithContext(ctx).Where("consumer = ?", consumer).Order("period DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if err := query.Find(&entities).Error; err != nil {
+		return nil, fmt.Errorf("falha ao listar uso do consumidor: %w", err)
+	}
+
+	usage := make([]model.ConsumerUsage, 0, len(entities))
+	for _, entity := range entities {
+		usage = append(usage, model.ConsumerUsage{
+			Consumer:  entity.Consumer,
+			Period:    entity.Period,
+			Requests:  entity.Requests,
+			UpdatedAt: entity.UpdatedAt,
+		})
+	}
+	return usage, nil
+}
@@ -0,0 +1,170 @@
+package database
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/internal/domain/repository"
+	"gorm.io/gorm"
+)
+
+// RecordingRepository implementa o armazenamento das gravações de tráfego com GORM
+type RecordingRepository struct {
+	db *gorm.DB
+}
+
+// NewRecordingRepository cria um novo repositório de gravações
+func NewRecordingRepository(db *gorm.DB) *RecordingRepository {
+	return &RecordingRepository{db: db}
+}
+
+// SaveRecordings grava as gravações informadas
+func (r *RecordingRepository) SaveRecordings(ctx context.Context, recordings []*model.Recording) error {
+	if len(recordings) == 0 {
+		return nil
+	}
+
+	entities := make([]model.RecordingEntity, 0, len(recordings))
+	for _, recording := range recordings {
+		requestHeaders, err := marshalJSONColumn(recording.RequestHeaders)
+		if err != nil {
+			return fmt.Errorf("falha ao serializar cabeçalhos da requisição gravada: %w", err)
+		}
+		responseHeaders, err := marshalJSONColumn(recording.ResponseHeaders)
+		if err != nil {
+			return fmt.Errorf("falha ao serializar cabeçalhos da resposta gravada: %w", err)
+		}
+		entities = append(entities, model.RecordingEntity{
+			ID:                    recording.ID,
+			Time:                  recording.Time.UTC(),
+			Tenant:                recording.Tenant,
+			Route:                 recording.Route,
+			RequestID:             recording.RequestID,
+			Method:                recording.Method,
+			Path:                  recording.Path,
+			Query:                 recording.Query,
+			Host:                  recording.Host,
+			RequestHeadersJSON:    requestHeaders,
+			RequestBody:           base64.StdEncoding.EncodeToString(recording.RequestBody),
+			RequestBodyTruncated:  recording.RequestBodyTruncated,
+			Status:                recording.Status,
+			ResponseHeadersJSON:   responseHeaders,
+			ResponseBody:          base64.StdEncoding.EncodeToString(recording.ResponseBody),
+			ResponseBodyTruncated: recording.ResponseBodyTruncated,
+			DurationMs:            recording.DurationMs,
+		})
+	}
+
+	if err := r.db.WithContext(ctx).Create(&entities).Error; err != nil {
+		return fmt.Errorf("falha ao gravar requisições gravadas: %w", err)
+	}
+	return nil
+}
+
+// ListRecordings retorna as gravações do filtro, da mais recente à mais
+// antiga, e o total sem paginação
+func (r *RecordingRepository) ListRecordings(ctx context.Context, filter repository.RecordingFilter) ([]*model.Recording, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.RecordingEntity{})
+	if filter.Tenant != "" {
+		query = query.Where("tenant = ?", filter.Tenant)
+	}
+	if filter.Route != "" {
+		query = query.Where("route = ?", filter.Route)
+	}
+	if filter.Method != "" {
+		query = query.Where("method = ?", filter.Method)
+	}
+	if filter.MinStatus > 0 {
+		query = query.Where("status >= ?", filter.MinStatus)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("recorded_at >= ?", filter.From.UTC())
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("recorded_at < ?", filter.To.UTC())
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("falha ao contar gravações: %w", err)
+	}
+
+	var entities []model.RecordingEntity
+	query = query.Order("recorded_at DESC").Offset(filter.Offset)
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if err := query.Find(&entities).Error; err != nil {
+		return nil, 0, fmt.Errorf("falha ao listar gravações: %w", err)
+	}
+
+	recordings := make([]*model.Recording, 0, len(entities))
+	for i := range entities {
+		recording, err := recordingFromEntity(&entities[i])
+		if err != nil {
+			return nil, 0, err
+		}
+		recordings = append(recordings, recording)
+	}
+	return recordings, total, nil
+}
+
+// GetRecording retorna a gravação pelo ID
+func (r *RecordingRepository) GetRecording(ctx context.Context, id string) (*model.Recording, error) {
+	var entity model.RecordingEntity
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&entity).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repository.ErrRecordingNotFound
+		}
+		return nil, fmt.Errorf("falha ao buscar gravação: %w", err)
+	}
+	return recordingFromEntity(&entity)
+}
+
+// DeleteBefore remove as gravações anteriores ao momento informado
+func (r *RecordingRepository) DeleteBefore(ctx context.Context, before time.Time) error {
+	err := r.db.WithContext(ctx).
+		Where("recorded_at < ?", before.UTC()).
+		Delete(&model.RecordingEntity{}).Error
+	if err != nil {
+		return fmt.Errorf("falha ao remover gravações antigas: %w", err)
+	}
+	return nil
+}
+
+// recordingFromEntity converte a entidade de banco no modelo de domínio
+func recordingFromEntity(entity *model.RecordingEntity) (*model.Recording, error) {
+	recording := &model.Recording{
+		ID:                    entity.ID,
+		Time:                  entity.Time.UTC(),
+		Tenant:                entity.Tenant,
+		Route:                 entity.Route,
+		RequestID:             entity.RequestID,
+		Method:                entity.Method,
+		Path:                  entity.Path,
+		Query:                 entity.Query,
+		Host:                  entity.Host,
+		RequestBodyTruncated:  entity.RequestBodyTruncated,
+		Status:                entity.Status,
+		ResponseBodyTruncated: entity.ResponseBodyTruncated,
+		DurationMs:            entity.DurationMs,
+	}
+	if err := unmarshalJSONColumn(entity.RequestHeadersJSON, &recording.RequestHeaders); err != nil {
+		return nil, fmt.Errorf("cabeçalhos da requisição da gravação %s inválidos: %w", entity.ID, err)
+	}
+	if err := unmarshalJSONColumn(entity.ResponseHeadersJSON, &recording.ResponseHeaders); err != nil {
+		return nil, fmt.Errorf("cabeçalhos da resposta da gravação %s inválidos: %w", entity.ID, err)
+	}
+	var err error
+	if recording.RequestBody, err = base64.StdEncoding.DecodeString(entity.RequestBody); err != nil {
+		return nil, fmt.Errorf("corpo da requisição da gravação %s inválido: %w", entity.ID, err)
+	}
+	if recording.ResponseBody, err = base64.StdEncoding.DecodeString(entity.ResponseBody); err != nil {
+		return nil, fmt.Errorf("corpo da resposta da gravação %s inválido: %w", entity.ID, err)
+	}
+	return recording, nil
+}
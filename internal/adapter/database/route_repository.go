@@ -14,30 +14,63 @@ import (
 
 	"github.com/diillson/api-gateway-go/internal/domain/model"
 	"github.com/diillson/api-gateway-go/internal/domain/repository"
+	"github.com/diillson/api-gateway-go/pkg/encryption"
+	"github.com/diillson/api-gateway-go/pkg/tenant"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
+// routeConfigColumns são as colunas de configuração gravadas em UpdateRoute.
+// Métricas e datas de criação ficam de fora para não serem sobrescritas.
+var routeConfigColumns = []string{
+	"path", "service_url", "methods", "headers", "description", "is_active",
+	"required_headers", "upstream_auth", "upstream_tls", "egress_proxy", "response_cache",
+	"access_schedule", "labels", "cost_attribution", "health_check", "telemetry", "targets",
+	"load_balancing", "circuit_breaker", "canary", "match_conditions", "variants",
+	"websocket", "protocol", "grpc_web", "auth_policy", "authorization", "external_authz",
+	"ip_filter", "header_transforms", "path_rewrite", "body_transforms", "upstream_timeout",
+	"retry_policy", "hedging_policy", "bulkhead_policy", "compression", "request_body",
+	"request_validation", "cors", "maintenance", "stub", "mirror", "idempotency",
+	"session_affinity", "connection_pool", "plugins", "request_filter", "hooks",
+	"graphql", "composite", "outlier_detection", "recording", "geo_filter",
+	"error_policy", "deprecation", "active_from", "active_until", "feature_flag",
+	"openapi_spec", "ratelimit_headers", "updated_at",
+}
+
 // RouteRepository implementa repository.RouteRepository
 type RouteRepository struct {
-	db     *gorm.DB
-	logger *zap.Logger
-	tracer trace.Tracer
+	db        *gorm.DB
+	logger    *zap.Logger
+	tracer    trace.Tracer
+	encryptor *encryption.Envelope
 }
 
 // NewRouteRepository cria um novo repositório de rotas
 func NewRouteRepository(db *gorm.DB, logger *zap.Logger) repository.RouteRepository {
+	return NewEncryptedRouteRepository(db, logger, nil)
+}
+
+// NewEncryptedRouteRepository cria um repositório de rotas que cifra os campos
+// sensíveis antes de gravá-los. Com encryptor nil os valores ficam em texto puro.
+func NewEncryptedRouteRepository(db *gorm.DB, logger *zap.Logger, encryptor *encryption.Envelope) repository.RouteRepository {
 	// Obter tracer para o repositório
 	tracer := otel.GetTracerProvider().Tracer("api-gateway.repository.route")
 
 	return &RouteRepository{
-		db:     db,
-		logger: logger,
-		tracer: tracer,
+		db:        db,
+		logger:    logger,
+		tracer:    tracer,
+		encryptor: encryptor,
 	}
 }
 
-// GetRoutes retorna todas as rotas ativas
+// scoped restringe a consulta às rotas do tenant do contexto. Sem tenant, a
+// consulta alcança apenas as rotas do escopo padrão.
+func (r *RouteRepository) scoped(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Where("tenant = ?", tenant.FromContext(ctx))
+}
+
+// GetRoutes retorna todas as rotas ativas do tenant
 func (r *RouteRepository) GetRoutes(ctx context.Context) ([]*model.Route, error) {
 	// Criar span para a operação
 	ctx, span := r.tracer.Start(
@@ -52,7 +85,7 @@ func (r *RouteRepository) GetRoutes(ctx context.Context) ([]*model.Route, error)
 
 	var entities []model.RouteEntity
 
-	if err := r.db.WithContext(ctx).Where("is_active = ?", true).Find(&entities).Error; err != nil {
+	if err := r.scoped(ctx).Where("is_active = ?", true).Find(&entities).Error; err != nil {
 		r.logger.Error("falha ao buscar rotas", zap.Error(err))
 		// Registrar erro no span
 		span.SetStatus(codes.Error, "database error")
@@ -66,7 +99,7 @@ func (r *RouteRepository) GetRoutes(ctx context.Context) ([]*model.Route, error)
 
 	routes := make([]*model.Route, 0, len(entities))
 	for _, entity := range entities {
-		route, err := entityToModel(&entity, r.db)
+		route, err := r.toModel(&entity)
 		if err != nil {
 			r.logger.Error("falha ao converter entidade para modelo", zap.Error(err))
 			// Registrar erro de conversão no span, mas continuar
@@ -108,7 +141,7 @@ func (r *RouteRepository) GetRouteByPath(ctx context.Context, path string) (*mod
 
 	var entity model.RouteEntity
 
-	if err := r.db.WithContext(ctx).Where("path = ?", path).First(&entity).Error; err != nil {
+	if err := r.scoped(ctx).Where("path = ?", path).First(&entity).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			// Registrar not found no span
 			span.SetStatus(codes.Error, "route not found")
@@ -133,7 +166,7 @@ func (r *RouteRepository) GetRouteByPath(ctx context.Context, path string) (*mod
 	// Registrar que a rota foi encontrada
 	span.SetAttributes(attribute.Bool("route.found", true))
 
-	route, err := entityToModel(&entity, r.db)
+	route, err := r.toModel(&entity)
 	if err != nil {
 		// Registrar erro no span
 		span.SetStatus(codes.Error, "conversion error")
@@ -156,7 +189,7 @@ func (r *RouteRepository) GetRouteByPath(ctx context.Context, path string) (*mod
 	return route, nil
 }
 
-// AddRoute adiciona uma nova rota
+// AddRoute adiciona uma nova rota no tenant do contexto
 func (r *RouteRepository) AddRoute(ctx context.Context, route *model.Route) error {
 	// Criar span para a operação
 	ctx, span := r.tracer.Start(
@@ -171,7 +204,7 @@ func (r *RouteRepository) AddRoute(ctx context.Context, route *model.Route) erro
 	)
 	defer span.End()
 
-	entity, err := modelToEntity(route, r.db)
+	entity, err := r.toEntity(route)
 	if err != nil {
 		span.SetStatus(codes.Error, "conversion error")
 		span.SetAttributes(
@@ -180,8 +213,15 @@ func (r *RouteRepository) AddRoute(ctx context.Context, route *model.Route) erro
 		)
 		return fmt.Errorf("falha ao converter modelo para entidade: %w", err)
 	}
+	entity.Tenant = tenant.FromContext(ctx)
 
 	if err := r.db.WithContext(ctx).Create(entity).Error; err != nil {
+		// Outra instância pode ter cadastrado o mesmo caminho concorrentemente
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			span.SetStatus(codes.Error, "route exists")
+			return repository.ErrRouteExists
+		}
+
 		r.logger.Error("falha ao adicionar rota",
 			zap.String("path", route.Path),
 			zap.Error(err))
@@ -216,7 +256,7 @@ func (r *RouteRepository) UpdateRoute(ctx context.Context, route *model.Route) e
 	)
 	defer span.End()
 
-	entity, err := modelToEntity(route, r.db)
+	entity, err := r.toEntity(route)
 	if err != nil {
 		span.SetStatus(codes.Error, "conversion error")
 		span.SetAttributes(
@@ -226,7 +266,10 @@ func (r *RouteRepository) UpdateRoute(ctx context.Context, route *model.Route) e
 		return fmt.Errorf("falha ao converter modelo para entidade: %w", err)
 	}
 
-	result := r.db.WithContext(ctx).Where("path = ?", route.Path).Updates(entity)
+	result := r.scoped(ctx).Model(&model.RouteEntity{}).
+		Where("path = ?", route.Path).
+		Select(routeConfigColumns).
+		Updates(entity)
 	if result.Error != nil {
 		r.logger.Error("falha ao atualizar rota",
 			zap.String("path", route.Path),
@@ -270,7 +313,7 @@ func (r *RouteRepository) DeleteRoute(ctx context.Context, path string) error {
 	)
 	defer span.End()
 
-	result := r.db.WithContext(ctx).Where("path = ?", path).Delete(&model.RouteEntity{})
+	result := r.scoped(ctx).Where("path = ?", path).Delete(&model.RouteEntity{})
 
 	if result.Error != nil {
 		r.logger.Error("falha ao excluir rota",
@@ -316,7 +359,7 @@ func (r *RouteRepository) UpdateMetrics(ctx context.Context, path string, callCo
 	)
 	defer span.End()
 
-	result := r.db.WithContext(ctx).Model(&model.RouteEntity{}).
+	result := r.scoped(ctx).Model(&model.RouteEntity{}).
 		Where("path = ?", path).
 		Updates(map[string]interface{}{
 			"call_count":      callCount,
@@ -374,7 +417,7 @@ func (r *RouteRepository) GetRoutesWithFilters(ctx context.Context, filters map[
 	var entities []model.RouteEntity
 
 	// Construir a consulta com os filtros
-	query := r.db.WithContext(ctx)
+	query := r.scoped(ctx)
 
 	// Aplicar filtros
 	for key, value := range filters {
@@ -400,7 +443,7 @@ func (r *RouteRepository) GetRoutesWithFilters(ctx context.Context, filters map[
 	conversionErrors := 0
 
 	for _, entity := range entities {
-		route, err := entityToModel(&entity, r.db)
+		route, err := r.toModel(&entity)
 		if err != nil {
 			r.logger.Error("falha ao converter entidade para modelo", zap.Error(err))
 			// Registrar evento de erro no span, mas continuar processando
@@ -432,6 +475,149 @@ func (r *RouteRepository) GetRoutesWithFilters(ctx context.Context, filters map[
 	return routes, nil
 }
 
+// ReEncryptSecrets cifra novamente os campos sensíveis que estão em texto puro ou
+// protegidos por uma chave antiga, retornando o número de rotas atualizadas
+func (r *RouteRepository) ReEncryptSecrets(ctx context.Context) (int, error) {
+	ctx, span := r.tracer.Start(
+		ctx,
+		"RouteRepository.ReEncryptSecrets",
+		trace.WithAttributes(
+			attribute.String("db.operation", "update"),
+			attribute.String("db.table", "routes"),
+		),
+	)
+	defer span.End()
+
+	if r.encryptor == nil {
+		return 0, fmt.Errorf("criptografia em repouso não está configurada")
+	}
+
+	var entities []model.RouteEntity
+	if err := r.db.WithContext(ctx).Where("upstream_auth <> '' OR hooks <> ''").Find(&entities).Error; err != nil {
+		span.SetStatus(codes.Error, "database error")
+		span.SetAttributes(
+			attribute.Bool("error", true),
+			attribute.String("error.message", err.Error()),
+		)
+		return 0, fmt.Errorf("falha ao buscar rotas para recriptografia: %w", err)
+	}
+
+	updated := 0
+	for i := range entities {
+		entity := &entities[i]
+		changes := make(map[string]interface{})
+		for column, field := range sensitiveColumns(entity) {
+			if *field == "" || !r.encryptor.NeedsReEncryption(*field) {
+				continue
+			}
+			sealed, err := r.encryptor.ReEncryptString(*field)
+			if err != nil {
+				span.SetStatus(codes.Error, "encryption error")
+				return updated, fmt.Errorf("falha ao recriptografar rota %s: %w", entity.Path, err)
+			}
+			changes[column] = sealed
+		}
+		if len(changes) == 0 {
+			continue
+		}
+
+		if err := r.db.WithContext(ctx).Model(&model.RouteEntity{}).
+			Where("id = ?", entity.ID).
+			Updates(changes).Error; err != nil {
+			span.SetStatus(codes.Error, "database error")
+			return updated, fmt.Errorf("falha ao gravar rota %s recriptografada: %w", entity.Path, err)
+		}
+		updated++
+	}
+
+	span.SetAttributes(attribute.Int("routes.reencrypted", updated))
+	span.SetStatus(codes.Ok, "")
+	return updated, nil
+}
+
+// sensitiveColumns retorna os campos da entidade cifrados em repouso, por coluna
+func sensitiveColumns(entity *model.RouteEntity) map[string]*string {
+	return map[string]*string{
+		"upstream_auth": &entity.UpstreamAuthJSON,
+		"hooks":         &entity.HooksJSON,
+	}
+}
+
+// toModel decifra os campos sensíveis da entidade e a converte em modelo
+func (r *RouteRepository) toModel(entity *model.RouteEntity) (*model.Route, error) {
+	decrypted := *entity
+	for _, field := range sensitiveColumns(&decrypted) {
+		if !encryption.IsEncrypted(*field) {
+			continue
+		}
+		if r.encryptor == nil {
+			return nil, fmt.Errorf("rota %s possui campos cifrados, mas a criptografia não está configurada", entity.Path)
+		}
+
+		plaintext, err := r.encryptor.DecryptString(*field)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao decifrar credenciais da rota %s: %w", entity.Path, err)
+		}
+		*field = plaintext
+	}
+
+	return entityToModel(&decrypted, r.db)
+}
+
+// toEntity converte o modelo em entidade e cifra os campos sensíveis
+func (r *RouteRepository) toEntity(route *model.Route) (*model.RouteEntity, error) {
+	entity, err := modelToEntity(route, r.db)
+	if err != nil {
+		return nil, err
+	}
+	if r.encryptor == nil {
+		return entity, nil
+	}
+
+	for _, field := range sensitiveColumns(entity) {
+		if *field == "" {
+			continue
+		}
+		sealed, err := r.encryptor.EncryptString(*field)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao cifrar credenciais da rota: %w", err)
+		}
+		*field = sealed
+	}
+
+	return entity, nil
+}
+
+// marshalJSONColumn serializa um valor opcional para uma coluna de texto JSON.
+// Valores nulos resultam em string vazia.
+func marshalJSONColumn(value interface{}) (string, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	if string(data) == "null" {
+		return "", nil
+	}
+	return string(data), nil
+}
+
+// unmarshalJSONColumn deserializa uma coluna de texto JSON, tolerando valores
+// vazios e o formato entre aspas usado pelo SQLite
+func unmarshalJSONColumn(raw string, dest interface{}) error {
+	if raw == "" || raw == "null" {
+		return nil
+	}
+
+	if err := json.Unmarshal([]byte(raw), dest); err != nil {
+		var quoted string
+		if json.Unmarshal([]byte(raw), &quoted) != nil {
+			return err
+		}
+		return json.Unmarshal([]byte(quoted), dest)
+	}
+	return nil
+}
+
 // entityToModel converte uma entidade em um modelo
 func entityToModel(entity *model.RouteEntity, db *gorm.DB) (*model.Route, error) {
 	var methods []string
@@ -513,18 +699,298 @@ func entityToModel(entity *model.RouteEntity, db *gorm.DB) (*model.Route, error)
 		}
 	}
 
+	var upstreamAuth *model.UpstreamAuth
+	if err := unmarshalJSONColumn(entity.UpstreamAuthJSON, &upstreamAuth); err != nil {
+		return nil, fmt.Errorf("falha ao deserializar credenciais do backend: %w", err)
+	}
+
+	var upstreamTLS *model.UpstreamTLS
+	if err := unmarshalJSONColumn(entity.UpstreamTLSJSON, &upstreamTLS); err != nil {
+		return nil, fmt.Errorf("falha ao deserializar TLS do backend: %w", err)
+	}
+
+	var responseCache *model.ResponseCachePolicy
+	if err := unmarshalJSONColumn(entity.ResponseCacheJSON, &responseCache); err != nil {
+		return nil, fmt.Errorf("falha ao deserializar política de cache de respostas: %w", err)
+	}
+
+	var accessSchedule *model.AccessSchedule
+	if err := unmarshalJSONColumn(entity.AccessScheduleJSON, &accessSchedule); err != nil {
+		return nil, fmt.Errorf("falha ao deserializar janelas de acesso: %w", err)
+	}
+
+	var labels map[string]string
+	if err := unmarshalJSONColumn(entity.LabelsJSON, &labels); err != nil {
+		return nil, fmt.Errorf("falha ao deserializar rótulos: %w", err)
+	}
+
+	var costAttribution *model.CostAttribution
+	if err := unmarshalJSONColumn(entity.CostAttributionJSON, &costAttribution); err != nil {
+		return nil, fmt.Errorf("falha ao deserializar atribuição de custo: %w", err)
+	}
+
+	var healthCheck *model.HealthCheck
+	if err := unmarshalJSONColumn(entity.HealthCheckJSON, &healthCheck); err != nil {
+		return nil, fmt.Errorf("falha ao deserializar verificação de saúde: %w", err)
+	}
+
+	var telemetry *model.RouteTelemetry
+	if err := unmarshalJSONColumn(entity.TelemetryJSON, &telemetry); err != nil {
+		return nil, fmt.Errorf("falha ao deserializar telemetria: %w", err)
+	}
+
+	var targets []model.Target
+	if err := unmarshalJSONColumn(entity.TargetsJSON, &targets); err != nil {
+		return nil, fmt.Errorf("falha ao deserializar alvos: %w", err)
+	}
+
+	var circuitBreaker *model.CircuitBreakerPolicy
+	if err := unmarshalJSONColumn(entity.CircuitBreakerJSON, &circuitBreaker); err != nil {
+		return nil, fmt.Errorf("falha ao deserializar política de circuit breaker: %w", err)
+	}
+
+	var canary *model.CanaryRouting
+	if err := unmarshalJSONColumn(entity.CanaryJSON, &canary); err != nil {
+		return nil, fmt.Errorf("falha ao deserializar roteamento canary: %w", err)
+	}
+
+	var match *model.MatchConditions
+	if err := unmarshalJSONColumn(entity.MatchJSON, &match); err != nil {
+		return nil, fmt.Errorf("falha ao deserializar condições de correspondência: %w", err)
+	}
+
+	var variants []model.RouteVariant
+	if err := unmarshalJSONColumn(entity.VariantsJSON, &variants); err != nil {
+		return nil, fmt.Errorf("falha ao deserializar variantes da rota: %w", err)
+	}
+
+	var webSocket *model.WebSocketPolicy
+	if err := unmarshalJSONColumn(entity.WebSocketJSON, &webSocket); err != nil {
+		return nil, fmt.Errorf("falha ao deserializar política de WebSocket: %w", err)
+	}
+
+	var auth *model.RouteAuth
+	if err := unmarshalJSONColumn(entity.AuthJSON, &auth); err != nil {
+		return nil, fmt.Errorf("falha ao deserializar política de autenticação: %w", err)
+	}
+
+	var authorization *model.AuthorizationPolicy
+	if err := unmarshalJSONColumn(entity.AuthorizationJSON, &authorization); err != nil {
+		return nil, fmt.Errorf("falha ao deserializar regras de autorização: %w", err)
+	}
+
+	var externalAuthz *model.ExternalAuthz
+	if err := unmarshalJSONColumn(entity.ExternalAuthzJSON, &externalAuthz); err != nil {
+		return nil, fmt.Errorf("falha ao deserializar autorização externa: %w", err)
+	}
+
+	var ipFilter *model.IPFilter
+	if err := unmarshalJSONColumn(entity.IPFilterJSON, &ipFilter); err != nil {
+		return nil, fmt.Errorf("falha ao deserializar ipFilter: %w", err)
+	}
+
+	var headerTransforms *model.HeaderTransforms
+	if err := unmarshalJSONColumn(entity.HeaderTransformsJSON, &headerTransforms); err != nil {
+		return nil, fmt.Errorf("falha ao deserializar headerTransforms: %w", err)
+	}
+
+	var pathRewrite *model.PathRewrite
+	if err := unmarshalJSONColumn(entity.PathRewriteJSON, &pathRewrite); err != nil {
+		return nil, fmt.Errorf("falha ao deserializar pathRewrite: %w", err)
+	}
+
+	var bodyTransforms *model.BodyTransforms
+	if err := unmarshalJSONColumn(entity.BodyTransformsJSON, &bodyTransforms); err != nil {
+		return nil, fmt.Errorf("falha ao deserializar bodyTransforms: %w", err)
+	}
+
+	var retry *model.RetryPolicy
+	if err := unmarshalJSONColumn(entity.RetryJSON, &retry); err != nil {
+		return nil, fmt.Errorf("falha ao deserializar retry: %w", err)
+	}
+
+	var hedging *model.HedgingPolicy
+	if err := unmarshalJSONColumn(entity.HedgingJSON, &hedging); err != nil {
+		return nil, fmt.Errorf("falha ao deserializar hedging: %w", err)
+	}
+
+	var bulkhead *model.BulkheadPolicy
+	if err := unmarshalJSONColumn(entity.BulkheadJSON, &bulkhead); err != nil {
+		return nil, fmt.Errorf("falha ao deserializar limite de concorrência: %w", err)
+	}
+
+	var compressionPolicy *model.CompressionPolicy
+	if err := unmarshalJSONColumn(entity.CompressionJSON, &compressionPolicy); err != nil {
+		return nil, fmt.Errorf("falha ao deserializar compressão: %w", err)
+	}
+
+	var requestBody *model.RequestBodyPolicy
+	if err := unmarshalJSONColumn(entity.RequestBodyJSON, &requestBody); err != nil {
+		return nil, fmt.Errorf("falha ao deserializar limites do corpo: %w", err)
+	}
+
+	var validation *model.RequestValidation
+	if err := unmarshalJSONColumn(entity.ValidationJSON, &validation); err != nil {
+		return nil, fmt.Errorf("falha ao deserializar validação das requisições: %w", err)
+	}
+
+	var cors *model.CORSPolicy
+	if err := unmarshalJSONColumn(entity.CORSJSON, &cors); err != nil {
+		return nil, fmt.Errorf("falha ao deserializar política CORS: %w", err)
+	}
+
+	var maintenance *model.MaintenanceMode
+	if err := unmarshalJSONColumn(entity.MaintenanceJSON, &maintenance); err != nil {
+		return nil, fmt.Errorf("falha ao deserializar modo de manutenção: %w", err)
+	}
+
+	var stub *model.StubResponse
+	if err := unmarshalJSONColumn(entity.StubJSON, &stub); err != nil {
+		return nil, fmt.Errorf("falha ao deserializar resposta simulada: %w", err)
+	}
+
+	var mirror *model.TrafficMirror
+	if err := unmarshalJSONColumn(entity.MirrorJSON, &mirror); err != nil {
+		return nil, fmt.Errorf("falha ao deserializar espelhamento de tráfego: %w", err)
+	}
+
+	var idempotency *model.IdempotencyPolicy
+	if err := unmarshalJSONColumn(entity.IdempotencyJSON, &idempotency); err != nil {
+		return nil, fmt.Errorf("falha ao deserializar política de idempotência: %w", err)
+	}
+
+	var affinity *model.SessionAffinity
+	if err := unmarshalJSONColumn(entity.AffinityJSON, &affinity); err != nil {
+		return nil, fmt.Errorf("falha ao deserializar afinidade de sessão: %w", err)
+	}
+
+	var pool *model.ConnectionPool
+	if err := unmarshalJSONColumn(entity.PoolJSON, &pool); err != nil {
+		return nil, fmt.Errorf("falha ao deserializar pool de conexões: %w", err)
+	}
+
+	var plugins []model.PluginRef
+	if err := unmarshalJSONColumn(entity.PluginsJSON, &plugins); err != nil {
+		return nil, fmt.Errorf("falha ao deserializar plugins: %w", err)
+	}
+
+	var requestFilter *model.RequestFilter
+	if err := unmarshalJSONColumn(entity.FilterJSON, &requestFilter); err != nil {
+		return nil, fmt.Errorf("falha ao deserializar filtro de requisições: %w", err)
+	}
+
+	var hooks *model.RouteHooks
+	if err := unmarshalJSONColumn(entity.HooksJSON, &hooks); err != nil {
+		return nil, fmt.Errorf("falha ao deserializar webhooks: %w", err)
+	}
+
+	var graphQL *model.GraphQLPolicy
+	if err := unmarshalJSONColumn(entity.GraphQLJSON, &graphQL); err != nil {
+		return nil, fmt.Errorf("falha ao deserializar política GraphQL: %w", err)
+	}
+
+	var composite *model.CompositeRoute
+	if err := unmarshalJSONColumn(entity.CompositeJSON, &composite); err != nil {
+		return nil, fmt.Errorf("falha ao deserializar composição: %w", err)
+	}
+
+	var outlierDetection *model.OutlierDetection
+	if err := unmarshalJSONColumn(entity.OutlierJSON, &outlierDetection); err != nil {
+		return nil, fmt.Errorf("falha ao deserializar detecção de alvos anômalos: %w", err)
+	}
+
+	var recording *model.RecordingPolicy
+	if err := unmarshalJSONColumn(entity.RecordingJSON, &recording); err != nil {
+		return nil, fmt.Errorf("falha ao deserializar política de gravação: %w", err)
+	}
+
+	var geoFilter *model.GeoFilter
+	if err := unmarshalJSONColumn(entity.GeoFilterJSON, &geoFilter); err != nil {
+		return nil, fmt.Errorf("falha ao deserializar geoFilter: %w", err)
+	}
+
+	var errorPolicy *model.ErrorPolicy
+	if err := unmarshalJSONColumn(entity.ErrorsJSON, &errorPolicy); err != nil {
+		return nil, fmt.Errorf("falha ao deserializar política de erros: %w", err)
+	}
+
+	var deprecation *model.RouteDeprecation
+	if err := unmarshalJSONColumn(entity.DeprecationJSON, &deprecation); err != nil {
+		return nil, fmt.Errorf("falha ao deserializar descontinuação: %w", err)
+	}
+
+	var featureFlag *model.RouteFeatureFlag
+	if err := unmarshalJSONColumn(entity.FeatureFlagJSON, &featureFlag); err != nil {
+		return nil, fmt.Errorf("falha ao deserializar featureFlag: %w", err)
+	}
+
 	return &model.Route{
-		Path:            entity.Path,
-		ServiceURL:      entity.ServiceURL,
-		Methods:         methods,
-		Headers:         headers,
-		Description:     entity.Description,
-		IsActive:        entity.IsActive,
-		CallCount:       entity.CallCount,
-		TotalResponse:   time.Duration(entity.TotalResponse),
-		RequiredHeaders: requiredHeaders,
-		CreatedAt:       entity.CreatedAt,
-		UpdatedAt:       entity.UpdatedAt,
+		Tenant:           entity.Tenant,
+		Path:             entity.Path,
+		ServiceURL:       entity.ServiceURL,
+		Methods:          methods,
+		Headers:          headers,
+		Description:      entity.Description,
+		IsActive:         entity.IsActive,
+		CallCount:        entity.CallCount,
+		TotalResponse:    time.Duration(entity.TotalResponse),
+		RequiredHeaders:  requiredHeaders,
+		UpstreamAuth:     upstreamAuth,
+		TLS:              upstreamTLS,
+		EgressProxy:      entity.EgressProxy,
+		ResponseCache:    responseCache,
+		AccessSchedule:   accessSchedule,
+		Labels:           labels,
+		CostAttribution:  costAttribution,
+		HealthCheck:      healthCheck,
+		Telemetry:        telemetry,
+		Targets:          targets,
+		LoadBalancing:    entity.LoadBalancing,
+		SessionAffinity:  affinity,
+		ConnectionPool:   pool,
+		CircuitBreaker:   circuitBreaker,
+		Canary:           canary,
+		Match:            match,
+		Variants:         variants,
+		WebSocket:        webSocket,
+		Protocol:         entity.Protocol,
+		GRPCWeb:          entity.GRPCWeb,
+		Auth:             auth,
+		Authorization:    authorization,
+		ExternalAuthz:    externalAuthz,
+		IPFilter:         ipFilter,
+		GeoFilter:        geoFilter,
+		HeaderTransforms: headerTransforms,
+		PathRewrite:      pathRewrite,
+		BodyTransforms:   bodyTransforms,
+		Timeout:          entity.Timeout,
+		Retry:            retry,
+		Hedging:          hedging,
+		Bulkhead:         bulkhead,
+		Compression:      compressionPolicy,
+		RequestBody:      requestBody,
+		Validation:       validation,
+		CORS:             cors,
+		Maintenance:      maintenance,
+		Errors:           errorPolicy,
+		Stub:             stub,
+		Mirror:           mirror,
+		Idempotency:      idempotency,
+		RateLimitHeaders: entity.RateLimitHeaders,
+		Plugins:          plugins,
+		RequestFilter:    requestFilter,
+		Hooks:            hooks,
+		GraphQL:          graphQL,
+		Composite:        composite,
+		OutlierDetection: outlierDetection,
+		Recording:        recording,
+		Deprecation:      deprecation,
+		ActiveFrom:       entity.ActiveFrom,
+		ActiveUntil:      entity.ActiveUntil,
+		FeatureFlag:      featureFlag,
+		OpenAPISpec:      entity.OpenAPISpec,
+		CreatedAt:        entity.CreatedAt,
+		UpdatedAt:        entity.UpdatedAt,
 	}, nil
 }
 
@@ -593,16 +1059,296 @@ func modelToEntity(route *model.Route, db *gorm.DB) (*model.RouteEntity, error)
 		}
 	}
 
+	upstreamAuthJSON, err := marshalJSONColumn(route.UpstreamAuth)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar credenciais do backend: %w", err)
+	}
+
+	upstreamTLSJSON, err := marshalJSONColumn(route.TLS)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar TLS do backend: %w", err)
+	}
+
+	responseCacheJSON, err := marshalJSONColumn(route.ResponseCache)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar política de cache de respostas: %w", err)
+	}
+
+	accessScheduleJSON, err := marshalJSONColumn(route.AccessSchedule)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar janelas de acesso: %w", err)
+	}
+
+	labelsJSON, err := marshalJSONColumn(route.Labels)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar rótulos: %w", err)
+	}
+
+	costAttributionJSON, err := marshalJSONColumn(route.CostAttribution)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar atribuição de custo: %w", err)
+	}
+
+	healthCheckJSON, err := marshalJSONColumn(route.HealthCheck)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar verificação de saúde: %w", err)
+	}
+
+	telemetryJSON, err := marshalJSONColumn(route.Telemetry)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar telemetria: %w", err)
+	}
+
+	targetsJSON, err := marshalJSONColumn(route.Targets)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar alvos: %w", err)
+	}
+
+	circuitBreakerJSON, err := marshalJSONColumn(route.CircuitBreaker)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar política de circuit breaker: %w", err)
+	}
+
+	canaryJSON, err := marshalJSONColumn(route.Canary)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar roteamento canary: %w", err)
+	}
+
+	matchJSON, err := marshalJSONColumn(route.Match)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar condições de correspondência: %w", err)
+	}
+
+	variantsJSON, err := marshalJSONColumn(route.Variants)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar variantes da rota: %w", err)
+	}
+
+	webSocketJSON, err := marshalJSONColumn(route.WebSocket)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar política de WebSocket: %w", err)
+	}
+
+	authJSON, err := marshalJSONColumn(route.Auth)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar política de autenticação: %w", err)
+	}
+
+	authorizationJSON, err := marshalJSONColumn(route.Authorization)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar regras de autorização: %w", err)
+	}
+
+	externalAuthzJSON, err := marshalJSONColumn(route.ExternalAuthz)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar autorização externa: %w", err)
+	}
+
+	ipFilterJSON, err := marshalJSONColumn(route.IPFilter)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar ipFilter: %w", err)
+	}
+
+	headerTransformsJSON, err := marshalJSONColumn(route.HeaderTransforms)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar headerTransforms: %w", err)
+	}
+
+	pathRewriteJSON, err := marshalJSONColumn(route.PathRewrite)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar pathRewrite: %w", err)
+	}
+
+	bodyTransformsJSON, err := marshalJSONColumn(route.BodyTransforms)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar bodyTransforms: %w", err)
+	}
+
+	retryJSON, err := marshalJSONColumn(route.Retry)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar retry: %w", err)
+	}
+
+	hedgingJSON, err := marshalJSONColumn(route.Hedging)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar hedging: %w", err)
+	}
+
+	bulkheadJSON, err := marshalJSONColumn(route.Bulkhead)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar limite de concorrência: %w", err)
+	}
+
+	compressionJSON, err := marshalJSONColumn(route.Compression)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar compressão: %w", err)
+	}
+
+	requestBodyJSON, err := marshalJSONColumn(route.RequestBody)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar limites do corpo: %w", err)
+	}
+
+	validationJSON, err := marshalJSONColumn(route.Validation)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar validação das requisições: %w", err)
+	}
+
+	corsJSON, err := marshalJSONColumn(route.CORS)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar política CORS: %w", err)
+	}
+
+	maintenanceJSON, err := marshalJSONColumn(route.Maintenance)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar modo de manutenção: %w", err)
+	}
+
+	stubJSON, err := marshalJSONColumn(route.Stub)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar resposta simulada: %w", err)
+	}
+
+	mirrorJSON, err := marshalJSONColumn(route.Mirror)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar espelhamento de tráfego: %w", err)
+	}
+
+	idempotencyJSON, err := marshalJSONColumn(route.Idempotency)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar política de idempotência: %w", err)
+	}
+
+	affinityJSON, err := marshalJSONColumn(route.SessionAffinity)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar afinidade de sessão: %w", err)
+	}
+
+	poolJSON, err := marshalJSONColumn(route.ConnectionPool)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar pool de conexões: %w", err)
+	}
+
+	pluginsJSON, err := marshalJSONColumn(route.Plugins)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar plugins: %w", err)
+	}
+
+	filterJSON, err := marshalJSONColumn(route.RequestFilter)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar filtro de requisições: %w", err)
+	}
+
+	hooksJSON, err := marshalJSONColumn(route.Hooks)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar webhooks: %w", err)
+	}
+
+	graphQLJSON, err := marshalJSONColumn(route.GraphQL)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar política GraphQL: %w", err)
+	}
+
+	compositeJSON, err := marshalJSONColumn(route.Composite)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar composição: %w", err)
+	}
+
+	outlierJSON, err := marshalJSONColumn(route.OutlierDetection)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar detecção de alvos anômalos: %w", err)
+	}
+
+	recordingJSON, err := marshalJSONColumn(route.Recording)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar política de gravação: %w", err)
+	}
+
+	geoFilterJSON, err := marshalJSONColumn(route.GeoFilter)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar geoFilter: %w", err)
+	}
+
+	errorsJSON, err := marshalJSONColumn(route.Errors)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar política de erros: %w", err)
+	}
+
+	deprecationJSON, err := marshalJSONColumn(route.Deprecation)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar descontinuação: %w", err)
+	}
+
+	featureFlagJSON, err := marshalJSONColumn(route.FeatureFlag)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar featureFlag: %w", err)
+	}
+
 	entity := &model.RouteEntity{
-		Path:                route.Path,
-		ServiceURL:          route.ServiceURL,
-		MethodsJSON:         methodsJSONStr,
-		HeadersJSON:         headersJSONStr,
-		Description:         route.Description,
-		IsActive:            route.IsActive,
-		CallCount:           route.CallCount,
-		TotalResponse:       int64(route.TotalResponse),
-		RequiredHeadersJSON: requiredHeadersJSONStr,
+		Tenant:               route.Tenant,
+		Path:                 route.Path,
+		ServiceURL:           route.ServiceURL,
+		MethodsJSON:          methodsJSONStr,
+		HeadersJSON:          headersJSONStr,
+		Description:          route.Description,
+		IsActive:             route.IsActive,
+		CallCount:            route.CallCount,
+		TotalResponse:        int64(route.TotalResponse),
+		RequiredHeadersJSON:  requiredHeadersJSONStr,
+		UpstreamAuthJSON:     upstreamAuthJSON,
+		UpstreamTLSJSON:      upstreamTLSJSON,
+		EgressProxy:          route.EgressProxy,
+		ResponseCacheJSON:    responseCacheJSON,
+		AccessScheduleJSON:   accessScheduleJSON,
+		LabelsJSON:           labelsJSON,
+		CostAttributionJSON:  costAttributionJSON,
+		HealthCheckJSON:      healthCheckJSON,
+		TelemetryJSON:        telemetryJSON,
+		TargetsJSON:          targetsJSON,
+		CircuitBreakerJSON:   circuitBreakerJSON,
+		CanaryJSON:           canaryJSON,
+		MatchJSON:            matchJSON,
+		VariantsJSON:         variantsJSON,
+		WebSocketJSON:        webSocketJSON,
+		AuthJSON:             authJSON,
+		AuthorizationJSON:    authorizationJSON,
+		ExternalAuthzJSON:    externalAuthzJSON,
+		IPFilterJSON:         ipFilterJSON,
+		HeaderTransformsJSON: headerTransformsJSON,
+		PathRewriteJSON:      pathRewriteJSON,
+		BodyTransformsJSON:   bodyTransformsJSON,
+		HedgingJSON:          hedgingJSON,
+		BulkheadJSON:         bulkheadJSON,
+		CompressionJSON:      compressionJSON,
+		RequestBodyJSON:      requestBodyJSON,
+		ValidationJSON:       validationJSON,
+		CORSJSON:             corsJSON,
+		MaintenanceJSON:      maintenanceJSON,
+		StubJSON:             stubJSON,
+		MirrorJSON:           mirrorJSON,
+		IdempotencyJSON:      idempotencyJSON,
+		AffinityJSON:         affinityJSON,
+		PoolJSON:             poolJSON,
+		PluginsJSON:          pluginsJSON,
+		FilterJSON:           filterJSON,
+		HooksJSON:            hooksJSON,
+		GraphQLJSON:          graphQLJSON,
+		CompositeJSON:        compositeJSON,
+		OutlierJSON:          outlierJSON,
+		RecordingJSON:        recordingJSON,
+		GeoFilterJSON:        geoFilterJSON,
+		ErrorsJSON:           errorsJSON,
+		DeprecationJSON:      deprecationJSON,
+		ActiveFrom:           route.ActiveFrom,
+		ActiveUntil:          route.ActiveUntil,
+		FeatureFlagJSON:      featureFlagJSON,
+		OpenAPISpec:          route.OpenAPISpec,
+		RateLimitHeaders:     route.RateLimitHeaders,
+		Timeout:              route.Timeout,
+		RetryJSON:            retryJSON,
+		LoadBalancing:        route.LoadBalancing,
+		Protocol:             route.Protocol,
+		GRPCWeb:              route.GRPCWeb,
 	}
 
 	// Preservar as datas se estiverem definidas
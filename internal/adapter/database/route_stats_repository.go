@@ -0,0 +1,109 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RouteStatsRepository implementa o armazenamento das estatísticas por minuto
+// das rotas com GORM
+type RouteStatsRepository struct {
+	db *gorm.DB
+}
+
+// NewRouteStatsRepository cria um novo repositório de estatísticas das rotas
+func NewRouteStatsRepository(db *gorm.DB) *RouteStatsRepository {
+	return &RouteStatsRepository{db: db}
+}
+
+// SaveBuckets grava as estatísticas informadas, substituindo as já gravadas
+// para a mesma instância, rota e minuto
+func (r *RouteStatsRepository) SaveBuckets(ctx context.Context, buckets []model.RouteStatsBucket) error {
+	if len(buckets) == 0 {
+		return nil
+	}
+
+	entities := make([]model.RouteStatsEntity, 0, len(buckets))
+	for _, bucket := range buckets {
+		statusJSON, err := marshalJSONColumn(bucket.Status)
+		if err != nil {
+			return fmt.Errorf("falha ao serializar status das estatísticas: %w", err)
+		}
+		latencyJSON, err := marshalJSONColumn(bucket.Latency)
+		if err != nil {
+			return fmt.Errorf("falha ao serializar latências das estatísticas: %w", err)
+		}
+		entities = append(entities, model.RouteStatsEntity{
+			Node:        bucket.Node,
+			Tenant:      bucket.Tenant,
+			Route:       bucket.Route,
+			Minute:      bucket.Minute.UTC(),
+			Requests:    bucket.Requests,
+			Errors:      bucket.Errors,
+			DurationNs:  int64(bucket.Duration),
+			StatusJSON:  statusJSON,
+			LatencyJSON: latencyJSON,
+		})
+	}
+
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "node"}, {Name: "tenant"}, {Name: "route"}, {Name: "minute"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"requests", "errors", "duration_ns", "status", "latency",
+		}),
+	}).Create(&entities).Error
+	if err != nil {
+		return fmt.Errorf("falha ao gravar estatísticas das rotas: %w", err)
+	}
+	return nil
+}
+
+// ListBuckets retorna as estatísticas da rota, de todas as instâncias, a
+// partir do minuto informado
+func (r *RouteStatsRepository) ListBuckets(ctx context.Context, tenant, route string, since time.Time) ([]model.RouteStatsBucket, error) {
+	var entities []model.RouteStatsEntity
+	err := r.db.WithContext(ctx).
+		Where("tenant = ? AND route = ? AND minute >= ?", tenant, route, since.UTC()).
+		Order("minute").
+		Find(&entities).Error
+	if err != nil {
+		return nil, fmt.Errorf("falha ao buscar estatísticas da rota: %w", err)
+	}
+
+	buckets := make([]model.RouteStatsBucket, 0, len(entities))
+	for _, entity := range entities {
+		bucket := model.RouteStatsBucket{
+			Node:     entity.Node,
+			Tenant:   entity.Tenant,
+			Route:    entity.Route,
+			Minute:   entity.Minute.UTC(),
+			Requests: entity.Requests,
+			Errors:   entity.Errors,
+			Duration: time.Duration(entity.DurationNs),
+		}
+		if err := unmarshalJSONColumn(entity.StatusJSON, &bucket.Status); err != nil {
+			return nil, fmt.Errorf("falha ao deserializar status das estatísticas: %w", err)
+		}
+		if err := unmarshalJSONColumn(entity.LatencyJSON, &bucket.Latency); err != nil {
+			return nil, fmt.Errorf("falha ao deserializar latências das estatísticas: %w", err)
+		}
+		buckets = append(buckets, bucket)
+	}
+	return buckets, nil
+}
+
+// DeleteBefore remove as estatísticas anteriores ao minuto informado
+func (r *RouteStatsRepository) DeleteBefore(ctx context.Context, before time.Time) error {
+	err := r.db.WithContext(ctx).
+		Where("minute < ?", before.UTC()).
+		Delete(&model.RouteStatsEntity{}).Error
+	if err != nil {
+		return fmt.Errorf("falha ao remover estatísticas antigas das rotas: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package database
+
+import (
+	"context"
+	"hash/fnv"
+	"sort"
+
+	"github.com/diillson/api-gateway-go/pkg/tenant"
+)
+
+// PrimaryShard é o nome do shard correspondente ao banco de dados principal
+const PrimaryShard = "primary"
+
+// ShardResolver decide em qual shard ficam os dados de um tenant
+type ShardResolver struct {
+	assignments   map[string]string
+	shards        []string
+	hashUnmapped  bool
+	fallbackShard string
+}
+
+// NewShardResolver cria um resolvedor a partir das atribuições explícitas de tenants.
+// Com hashUnmapped, tenants sem atribuição são distribuídos entre todos os shards
+// por hash; caso contrário ficam no shard principal.
+func NewShardResolver(assignments map[string]string, shards []string, hashUnmapped bool) *ShardResolver {
+	names := append([]string(nil), shards...)
+	sort.Strings(names)
+
+	return &ShardResolver{
+		assignments:   assignments,
+		shards:        names,
+		hashUnmapped:  hashUnmapped,
+		fallbackShard: PrimaryShard,
+	}
+}
+
+// Resolve retorna o shard do tenant
+func (r *ShardResolver) Resolve(tenantID string) string {
+	if tenantID == "" {
+		return r.fallbackShard
+	}
+
+	if shard, ok := r.assignments[tenantID]; ok {
+		return shard
+	}
+
+	if r.hashUnmapped && len(r.shards) > 0 {
+		h := fnv.New32a()
+		h.Write([]byte(tenantID))
+		return r.shards[h.Sum32()%uint32(len(r.shards))]
+	}
+
+	return r.fallbackShard
+}
+
+// ResolveContext retorna o shard do tenant associado ao contexto
+func (r *ShardResolver) ResolveContext(ctx context.Context) string {
+	return r.Resolve(tenant.FromContext(ctx))
+}
+
+// Shards retorna os nomes de todos os shards conhecidos
+func (r *ShardResolver) Shards() []string {
+	return append([]string(nil), r.shards...)
+}
@@ -0,0 +1,117 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/internal/domain/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// ShardedRouteRepository distribui o armazenamento de rotas entre vários bancos,
+// escolhendo o shard a partir do tenant presente no contexto
+type ShardedRouteRepository struct {
+	shards   map[string]repository.RouteRepository
+	resolver *ShardResolver
+	logger   *zap.Logger
+	tracer   trace.Tracer
+}
+
+// NewShardedRouteRepository cria um repositório de rotas particionado por tenant.
+// O mapa deve conter o shard principal (PrimaryShard).
+func NewShardedRouteRepository(shards map[string]repository.RouteRepository, resolver *ShardResolver, logger *zap.Logger) (*ShardedRouteRepository, error) {
+	if _, ok := shards[PrimaryShard]; !ok {
+		return nil, fmt.Errorf("shard %s é obrigatório", PrimaryShard)
+	}
+
+	for _, name := range resolver.Shards() {
+		if _, ok := shards[name]; !ok {
+			return nil, fmt.Errorf("shard %s não possui repositório configurado", name)
+		}
+	}
+
+	return &ShardedRouteRepository{
+		shards:   shards,
+		resolver: resolver,
+		logger:   logger,
+		tracer:   otel.GetTracerProvider().Tracer("api-gateway.repository.sharded_route"),
+	}, nil
+}
+
+// shardFor retorna o repositório responsável pelo tenant do contexto
+func (r *ShardedRouteRepository) shardFor(ctx context.Context) (repository.RouteRepository, string) {
+	name := r.resolver.ResolveContext(ctx)
+	repo, ok := r.shards[name]
+	if !ok {
+		r.logger.Warn("Shard resolvido não está configurado, usando o principal",
+			zap.String("shard", name))
+		return r.shards[PrimaryShard], PrimaryShard
+	}
+
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("db.shard", name))
+	return repo, name
+}
+
+// GetRoutes retorna as rotas ativas do shard do tenant
+func (r *ShardedRouteRepository) GetRoutes(ctx context.Context) ([]*model.Route, error) {
+	repo, _ := r.shardFor(ctx)
+	return repo.GetRoutes(ctx)
+}
+
+// GetRouteByPath obtém uma rota do shard do tenant
+func (r *ShardedRouteRepository) GetRouteByPath(ctx context.Context, path string) (*model.Route, error) {
+	repo, _ := r.shardFor(ctx)
+	return repo.GetRouteByPath(ctx, path)
+}
+
+// AddRoute adiciona uma rota no shard do tenant
+func (r *ShardedRouteRepository) AddRoute(ctx context.Context, route *model.Route) error {
+	repo, _ := r.shardFor(ctx)
+	return repo.AddRoute(ctx, route)
+}
+
+// UpdateRoute atualiza uma rota no shard do tenant
+func (r *ShardedRouteRepository) UpdateRoute(ctx context.Context, route *model.Route) error {
+	repo, _ := r.shardFor(ctx)
+	return repo.UpdateRoute(ctx, route)
+}
+
+// DeleteRoute remove uma rota do shard do tenant
+func (r *ShardedRouteRepository) DeleteRoute(ctx context.Context, path string) error {
+	repo, _ := r.shardFor(ctx)
+	return repo.DeleteRoute(ctx, path)
+}
+
+// UpdateMetrics atualiza as métricas de uma rota no shard do tenant
+func (r *ShardedRouteRepository) UpdateMetrics(ctx context.Context, path string, callCount int64, totalResponseTime int64) error {
+	repo, _ := r.shardFor(ctx)
+	return repo.UpdateMetrics(ctx, path, callCount, totalResponseTime)
+}
+
+// GetRoutesWithFilters obtém rotas filtradas do shard do tenant
+func (r *ShardedRouteRepository) GetRoutesWithFilters(ctx context.Context, filters map[string]interface{}) ([]*model.Route, error) {
+	repo, _ := r.shardFor(ctx)
+	return repo.GetRoutesWithFilters(ctx, filters)
+}
+
+// ReEncryptSecrets recriptografa os campos sensíveis em todos os shards
+func (r *ShardedRouteRepository) ReEncryptSecrets(ctx context.Context) (int, error) {
+	total := 0
+	for name, repo := range r.shards {
+		reEncrypter, ok := repo.(repository.SecretReEncrypter)
+		if !ok {
+			continue
+		}
+
+		count, err := reEncrypter.ReEncryptSecrets(ctx)
+		total += count
+		if err != nil {
+			return total, fmt.Errorf("shard %s: %w", name, err)
+		}
+	}
+	return total, nil
+}
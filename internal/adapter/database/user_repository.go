@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/diillson/api-gateway-go/internal/domain/model"
@@ -47,6 +48,7 @@ func (r *UserRepository) GetUserByCredentials(username, password string) (*model
 		Username: user.Username,
 		Role:     user.Role,
 		Email:    user.Email,
+		Tenant:   user.Tenant,
 	}, nil
 }
 
@@ -64,9 +66,30 @@ func (r *UserRepository) GetUserByID(id string) (*model.User, error) {
 		Username: user.Username,
 		Role:     user.Role,
 		Email:    user.Email,
+		Tenant:   user.Tenant,
 	}, nil
 }
 
+// ListUsers retorna todos os usuários cadastrados, sem as senhas
+func (r *UserRepository) ListUsers(ctx context.Context) ([]*model.User, error) {
+	var entities []model.UserEntity
+	if err := r.db.WithContext(ctx).Order("username").Find(&entities).Error; err != nil {
+		return nil, fmt.Errorf("falha ao listar usuários: %w", err)
+	}
+
+	users := make([]*model.User, 0, len(entities))
+	for _, user := range entities {
+		users = append(users, &model.User{
+			ID:       user.ID,
+			Username: user.Username,
+			Role:     user.Role,
+			Email:    user.Email,
+			Tenant:   user.Tenant,
+		})
+	}
+	return users, nil
+}
+
 // DiagnoseUserStorage ajuda a diagnosticar problemas com armazenamento de usuários
 func (r *UserRepository) DiagnoseUserStorage(username string) (string, error) {
 	var user model.UserEntity
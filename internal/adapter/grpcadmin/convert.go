@@ -0,0 +1,168 @@
+package grpcadmin
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// coreRouteFields são os campos da rota representados diretamente na mensagem
+// Route; os demais seguem em options com os mesmos nomes da API REST
+var coreRouteFields = []string{
+	"Path", "ServiceURL", "Methods", "Headers", "Description", "IsActive",
+	"RequiredHeaders", "CallCount", "TotalResponse", "CreatedAt", "UpdatedAt",
+}
+
+// setField atribui um valor escalar a um campo da mensagem
+func setField(msg *dynamicpb.Message, name string, value protoreflect.Value) {
+	msg.Set(msg.Descriptor().Fields().ByName(protoreflect.Name(name)), value)
+}
+
+// setStrings atribui uma lista de strings a um campo repeated
+func setStrings(msg *dynamicpb.Message, name string, values []string) {
+	fd := msg.Descriptor().Fields().ByName(protoreflect.Name(name))
+	list := msg.Mutable(fd).List()
+	for _, value := range values {
+		list.Append(protoreflect.ValueOfString(value))
+	}
+}
+
+// setTimestamp atribui um instante a um campo google.protobuf.Timestamp
+func setTimestamp(msg *dynamicpb.Message, name string, t time.Time) {
+	if t.IsZero() {
+		return
+	}
+	setField(msg, name, protoreflect.ValueOfMessage(timestamppb.New(t).ProtoReflect()))
+}
+
+// getString lê um campo string da mensagem
+func getString(msg protoreflect.Message, name string) string {
+	return msg.Get(msg.Descriptor().Fields().ByName(protoreflect.Name(name))).String()
+}
+
+// getBool lê um campo bool da mensagem
+func getBool(msg protoreflect.Message, name string) bool {
+	return msg.Get(msg.Descriptor().Fields().ByName(protoreflect.Name(name))).Bool()
+}
+
+// getEnum lê o número de um campo enum da mensagem
+func getEnum(msg protoreflect.Message, name string) protoreflect.EnumNumber {
+	return msg.Get(msg.Descriptor().Fields().ByName(protoreflect.Name(name))).Enum()
+}
+
+// getStrings lê um campo repeated string da mensagem
+func getStrings(msg protoreflect.Message, name string) []string {
+	list := msg.Get(msg.Descriptor().Fields().ByName(protoreflect.Name(name))).List()
+	if list.Len() == 0 {
+		return nil
+	}
+	values := make([]string, 0, list.Len())
+	for i := 0; i < list.Len(); i++ {
+		values = append(values, list.Get(i).String())
+	}
+	return values
+}
+
+// routeToMessage converte a rota para a mensagem Route, com segredos mascarados
+func (s *schema) routeToMessage(route *model.Route) (*dynamicpb.Message, error) {
+	msg := s.newMessage("Route")
+	setField(msg, "path", protoreflect.ValueOfString(route.Path))
+	setField(msg, "service_url", protoreflect.ValueOfString(route.ServiceURL))
+	setStrings(msg, "methods", route.Methods)
+	setStrings(msg, "headers", route.Headers)
+	setField(msg, "description", protoreflect.ValueOfString(route.Description))
+	setField(msg, "is_active", protoreflect.ValueOfBool(route.IsActive))
+	setStrings(msg, "required_headers", route.RequiredHeaders)
+	setField(msg, "call_count", protoreflect.ValueOfInt64(route.CallCount))
+	setField(msg, "average_response_ms",
+		protoreflect.ValueOfFloat64(float64(route.AverageResponseTime())/float64(time.Millisecond)))
+	setTimestamp(msg, "created_at", route.CreatedAt)
+	setTimestamp(msg, "updated_at", route.UpdatedAt)
+
+	data, err := json.Marshal(route.Redacted())
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar rota: %w", err)
+	}
+	var options map[string]interface{}
+	if err := json.Unmarshal(data, &options); err != nil {
+		return nil, fmt.Errorf("falha ao serializar rota: %w", err)
+	}
+	for _, name := range coreRouteFields {
+		delete(options, name)
+	}
+	for name, value := range options {
+		if value == nil || value == "" {
+			delete(options, name)
+		}
+	}
+	if len(options) > 0 {
+		st, err := structpb.NewStruct(options)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao converter opções da rota: %w", err)
+		}
+		setField(msg, "options", protoreflect.ValueOfMessage(st.ProtoReflect()))
+	}
+
+	return msg, nil
+}
+
+// messageToRoute converte a mensagem Route recebida em uma rota de domínio.
+// Métricas e datas enviadas pelo cliente são ignoradas.
+func messageToRoute(msg protoreflect.Message) (*model.Route, error) {
+	fields := map[string]interface{}{}
+
+	optionsField := msg.Descriptor().Fields().ByName("options")
+	if msg.Has(optionsField) {
+		// A mensagem aninhada chega como dinâmica; converter para Struct pelo formato de fio
+		raw, err := proto.Marshal(msg.Get(optionsField).Message().Interface())
+		if err != nil {
+			return nil, fmt.Errorf("opções da rota inválidas: %w", err)
+		}
+		var st structpb.Struct
+		if err := proto.Unmarshal(raw, &st); err != nil {
+			return nil, fmt.Errorf("opções da rota inválidas: %w", err)
+		}
+		for name, value := range st.AsMap() {
+			fields[name] = value
+		}
+	}
+
+	fields["Path"] = getString(msg, "path")
+	fields["ServiceURL"] = getString(msg, "service_url")
+	fields["Methods"] = getStrings(msg, "methods")
+	fields["Headers"] = getStrings(msg, "headers")
+	fields["Description"] = getString(msg, "description")
+	fields["IsActive"] = getBool(msg, "is_active")
+	fields["RequiredHeaders"] = getStrings(msg, "required_headers")
+	delete(fields, "CallCount")
+	delete(fields, "TotalResponse")
+	delete(fields, "CreatedAt")
+	delete(fields, "UpdatedAt")
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao converter rota: %w", err)
+	}
+	var route model.Route
+	if err := json.Unmarshal(data, &route); err != nil {
+		return nil, fmt.Errorf("opções da rota inválidas: %w", err)
+	}
+	return &route, nil
+}
+
+// userToMessage converte um usuário para a mensagem Consumer
+func (s *schema) userToMessage(user *model.User) *dynamicpb.Message {
+	msg := s.newMessage("Consumer")
+	setField(msg, "id", protoreflect.ValueOfString(user.ID))
+	setField(msg, "username", protoreflect.ValueOfString(user.Username))
+	setField(msg, "email", protoreflect.ValueOfString(user.Email))
+	setField(msg, "role", protoreflect.ValueOfString(user.Role))
+	return msg
+}
@@ -0,0 +1,252 @@
+package grpcadmin
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	// Registram os tipos bem conhecidos usados pelo descritor
+	_ "google.golang.org/protobuf/types/known/emptypb"
+	_ "google.golang.org/protobuf/types/known/structpb"
+	_ "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Nomes do serviço publicado em api/proto/admin/v1/admin.proto
+const (
+	protoPackage = "apigateway.admin.v1"
+	serviceName  = protoPackage + ".AdminService"
+)
+
+// O descritor abaixo espelha api/proto/admin/v1/admin.proto. Ele é montado em
+// código para que o servidor fale o mesmo formato de fio dos clientes gerados a
+// partir do .proto, sem depender de código gerado pelo protoc no repositório.
+// Qualquer alteração no .proto deve ser refletida aqui.
+
+var (
+	typeString  = descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum()
+	typeBool    = descriptorpb.FieldDescriptorProto_TYPE_BOOL.Enum()
+	typeInt32   = descriptorpb.FieldDescriptorProto_TYPE_INT32.Enum()
+	typeInt64   = descriptorpb.FieldDescriptorProto_TYPE_INT64.Enum()
+	typeDouble  = descriptorpb.FieldDescriptorProto_TYPE_DOUBLE.Enum()
+	typeMessage = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+	typeEnum    = descriptorpb.FieldDescriptorProto_TYPE_ENUM.Enum()
+
+	labelOptional = descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()
+	labelRepeated = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+)
+
+// field cria a definição de um campo escalar ou de mensagem
+func field(name string, number int32, fieldType *descriptorpb.FieldDescriptorProto_Type, typeName string) *descriptorpb.FieldDescriptorProto {
+	f := &descriptorpb.FieldDescriptorProto{
+		Name:     proto.String(name),
+		JsonName: proto.String(jsonName(name)),
+		Number:   proto.Int32(number),
+		Label:    labelOptional,
+		Type:     fieldType,
+	}
+	if typeName != "" {
+		f.TypeName = proto.String(typeName)
+	}
+	return f
+}
+
+// repeated marca o campo como lista
+func repeated(f *descriptorpb.FieldDescriptorProto) *descriptorpb.FieldDescriptorProto {
+	f.Label = labelRepeated
+	return f
+}
+
+// message cria a definição de uma mensagem
+func message(name string, fields ...*descriptorpb.FieldDescriptorProto) *descriptorpb.DescriptorProto {
+	return &descriptorpb.DescriptorProto{Name: proto.String(name), Field: fields}
+}
+
+// enum cria a definição de um enum com valores na ordem informada
+func enum(name string, values ...string) *descriptorpb.EnumDescriptorProto {
+	e := &descriptorpb.EnumDescriptorProto{Name: proto.String(name)}
+	for i, value := range values {
+		e.Value = append(e.Value, &descriptorpb.EnumValueDescriptorProto{
+			Name:   proto.String(value),
+			Number: proto.Int32(int32(i)),
+		})
+	}
+	return e
+}
+
+// method cria a definição de um método do serviço
+func method(name, input, output string, serverStreaming bool) *descriptorpb.MethodDescriptorProto {
+	m := &descriptorpb.MethodDescriptorProto{
+		Name:       proto.String(name),
+		InputType:  proto.String(input),
+		OutputType: proto.String(output),
+	}
+	if serverStreaming {
+		m.ServerStreaming = proto.Bool(true)
+	}
+	return m
+}
+
+// jsonName converte snake_case em lowerCamelCase, como faz o protoc
+func jsonName(name string) string {
+	out := make([]byte, 0, len(name))
+	upper := false
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if c == '_' {
+			upper = true
+			continue
+		}
+		if upper && c >= 'a' && c <= 'z' {
+			c -= 'a' - 'A'
+		}
+		upper = false
+		out = append(out, c)
+	}
+	return string(out)
+}
+
+// local qualifica um nome de tipo do pacote
+func local(name string) string {
+	return "." + protoPackage + "." + name
+}
+
+const (
+	wktEmpty     = ".google.protobuf.Empty"
+	wktStruct    = ".google.protobuf.Struct"
+	wktTimestamp = ".google.protobuf.Timestamp"
+)
+
+// fileDescriptorProto monta o descritor de admin.proto
+func fileDescriptorProto() *descriptorpb.FileDescriptorProto {
+	routeEvent := message("RouteEvent",
+		field("type", 1, typeEnum, local("RouteEvent.Type")),
+		field("path", 2, typeString, ""),
+		field("route", 3, typeMessage, local("Route")),
+		field("time", 4, typeMessage, wktTimestamp),
+	)
+	routeEvent.EnumType = []*descriptorpb.EnumDescriptorProto{
+		enum("Type", "TYPE_UNSPECIFIED", "CREATED", "UPDATED", "DELETED", "SNAPSHOT"),
+	}
+
+	purgeCacheRequest := message("PurgeCacheRequest",
+		field("scope", 1, typeEnum, local("PurgeCacheRequest.Scope")),
+	)
+	purgeCacheRequest.EnumType = []*descriptorpb.EnumDescriptorProto{
+		enum("Scope", "ROUTES", "ALL"),
+	}
+
+	return &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("api/proto/admin/v1/admin.proto"),
+		Package: proto.String(protoPackage),
+		Syntax:  proto.String("proto3"),
+		Dependency: []string{
+			"google/protobuf/empty.proto",
+			"google/protobuf/struct.proto",
+			"google/protobuf/timestamp.proto",
+		},
+		Options: &descriptorpb.FileOptions{
+			GoPackage: proto.String("github.com/diillson/api-gateway-go/api/proto/admin/v1;adminv1"),
+		},
+		MessageType: []*descriptorpb.DescriptorProto{
+			message("Route",
+				field("path", 1, typeString, ""),
+				field("service_url", 2, typeString, ""),
+				repeated(field("methods", 3, typeString, "")),
+				repeated(field("headers", 4, typeString, "")),
+				field("description", 5, typeString, ""),
+				field("is_active", 6, typeBool, ""),
+				repeated(field("required_headers", 7, typeString, "")),
+				field("call_count", 8, typeInt64, ""),
+				field("average_response_ms", 9, typeDouble, ""),
+				field("options", 10, typeMessage, wktStruct),
+				field("created_at", 11, typeMessage, wktTimestamp),
+				field("updated_at", 12, typeMessage, wktTimestamp),
+			),
+			message("ListRoutesRequest"),
+			message("ListRoutesResponse",
+				repeated(field("routes", 1, typeMessage, local("Route"))),
+			),
+			message("GetRouteRequest", field("path", 1, typeString, "")),
+			message("DeleteRouteRequest", field("path", 1, typeString, "")),
+			message("WatchRoutesRequest", field("send_initial", 1, typeBool, "")),
+			routeEvent,
+			message("Consumer",
+				field("id", 1, typeString, ""),
+				field("username", 2, typeString, ""),
+				field("email", 3, typeString, ""),
+				field("role", 4, typeString, ""),
+			),
+			message("ListConsumersResponse",
+				repeated(field("consumers", 1, typeMessage, local("Consumer"))),
+			),
+			purgeCacheRequest,
+			message("PurgeCacheResponse", field("purged", 1, typeBool, "")),
+			message("Status",
+				field("version", 1, typeString, ""),
+				field("started_at", 2, typeMessage, wktTimestamp),
+				field("route_count", 3, typeInt32, ""),
+				field("database", 4, typeString, ""),
+				field("cache", 5, typeString, ""),
+			),
+		},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("AdminService"),
+			Method: []*descriptorpb.MethodDescriptorProto{
+				method("ListRoutes", local("ListRoutesRequest"), local("ListRoutesResponse"), false),
+				method("GetRoute", local("GetRouteRequest"), local("Route"), false),
+				method("CreateRoute", local("Route"), local("Route"), false),
+				method("UpdateRoute", local("Route"), local("Route"), false),
+				method("DeleteRoute", local("DeleteRouteRequest"), wktEmpty, false),
+				method("WatchRoutes", local("WatchRoutesRequest"), local("RouteEvent"), true),
+				method("ListConsumers", wktEmpty, local("ListConsumersResponse"), false),
+				method("PurgeCache", local("PurgeCacheRequest"), local("PurgeCacheResponse"), false),
+				method("GetStatus", wktEmpty, local("Status"), false),
+			},
+		}},
+	}
+}
+
+// schema reúne os descritores resolvidos usados pelo servidor
+type schema struct {
+	file    protoreflect.FileDescriptor
+	service protoreflect.ServiceDescriptor
+}
+
+// loadSchema resolve o descritor e o registra para uso por reflexão gRPC e clientes dinâmicos
+func loadSchema() (*schema, error) {
+	if existing, err := protoregistry.GlobalFiles.FindFileByPath("api/proto/admin/v1/admin.proto"); err == nil {
+		return &schema{file: existing, service: existing.Services().ByName("AdminService")}, nil
+	}
+
+	file, err := protodesc.NewFile(fileDescriptorProto(), protoregistry.GlobalFiles)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao montar descritor da API administrativa gRPC: %w", err)
+	}
+	if err := protoregistry.GlobalFiles.RegisterFile(file); err != nil {
+		return nil, fmt.Errorf("falha ao registrar descritor da API administrativa gRPC: %w", err)
+	}
+	return &schema{file: file, service: file.Services().ByName("AdminService")}, nil
+}
+
+// newMessage cria uma mensagem vazia do tipo informado (nome simples ou qualificado)
+func (s *schema) newMessage(name string) *dynamicpb.Message {
+	desc := s.messageDescriptor(name)
+	return dynamicpb.NewMessage(desc)
+}
+
+// messageDescriptor localiza o descritor de uma mensagem do pacote ou bem conhecida
+func (s *schema) messageDescriptor(name string) protoreflect.MessageDescriptor {
+	if desc := s.file.Messages().ByName(protoreflect.Name(name)); desc != nil {
+		return desc
+	}
+	desc, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(name))
+	if err != nil {
+		panic(fmt.Sprintf("grpcadmin: mensagem desconhecida %s", name))
+	}
+	return desc.(protoreflect.MessageDescriptor)
+}
@@ -0,0 +1,529 @@
+package grpcadmin
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/app/audit"
+	"github.com/diillson/api-gateway-go/internal/app/route"
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/internal/domain/repository"
+	"github.com/diillson/api-gateway-go/pkg/cache"
+	"github.com/diillson/api-gateway-go/pkg/tenant"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// watchBuffer é o número de eventos mantidos por assinante de WatchRoutes
+const watchBuffer = 64
+
+// Valores dos enums de admin.proto
+const (
+	eventTypeCreated  protoreflect.EnumNumber = 1
+	eventTypeUpdated  protoreflect.EnumNumber = 2
+	eventTypeDeleted  protoreflect.EnumNumber = 3
+	eventTypeSnapshot protoreflect.EnumNumber = 4
+
+	purgeScopeAll protoreflect.EnumNumber = 1
+)
+
+// Authenticator valida os tokens enviados no metadata "authorization"
+type Authenticator interface {
+	ValidateToken(tokenString string) (*model.User, error)
+	IsAdmin(user *model.User) bool
+	IsGlobalAdmin(user *model.User) bool
+}
+
+// ConsumerSource lista os consumidores cadastrados
+type ConsumerSource interface {
+	ListUsers(ctx context.Context) ([]*model.User, error)
+}
+
+// Pinger verifica a disponibilidade de uma dependência
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Server expõe a API administrativa via gRPC, conforme api/proto/admin/v1/admin.proto
+type Server struct {
+	schema       *schema
+	routes       *route.Service
+	consumers    ConsumerSource
+	auth         Authenticator
+	db           Pinger
+	cache        cache.Cache
+	tenantHeader string
+	allowSource  func(addr netip.Addr) bool
+	logger       *zap.Logger
+	startedAt    time.Time
+	grpc         *grpc.Server
+}
+
+type userContextKey struct{}
+
+// NewServer cria o servidor gRPC administrativo
+func NewServer(routes *route.Service, consumers ConsumerSource, auth Authenticator, db Pinger,
+	cacheInstance cache.Cache, logger *zap.Logger) (*Server, error) {
+
+	schema, err := loadSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		schema:    schema,
+		routes:    routes,
+		consumers: consumers,
+		auth:      auth,
+		db:        db,
+		cache:     cacheInstance,
+		logger:    logger,
+		startedAt: time.Now(),
+	}
+	s.grpc = grpc.NewServer(
+		grpc.UnaryInterceptor(s.unaryAuth),
+		grpc.StreamInterceptor(s.streamAuth),
+	)
+	s.grpc.RegisterService(s.serviceDesc(), s)
+	return s, nil
+}
+
+// SetTenantHeader define a chave de metadata que identifica o tenant, a mesma
+// usada como cabeçalho na API REST
+func (s *Server) SetTenantHeader(header string) {
+	s.tenantHeader = strings.ToLower(header)
+}
+
+// SetSourceFilter restringe as chamadas às origens aceitas pela função,
+// avaliada sobre o endereço da conexão
+func (s *Server) SetSourceFilter(allow func(addr netip.Addr) bool) {
+	s.allowSource = allow
+}
+
+// Serve atende conexões no listener até Stop ou GracefulStop
+func (s *Server) Serve(listener net.Listener) error {
+	return s.grpc.Serve(listener)
+}
+
+// GracefulStop encerra o servidor aguardando as chamadas em andamento.
+// Streams de WatchRoutes são finalizados pelo cancelamento das conexões.
+func (s *Server) GracefulStop(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		s.grpc.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		s.grpc.Stop()
+	}
+}
+
+// authenticate valida o token de administrador e propaga usuário e tenant pelo contexto
+func (s *Server) authenticate(ctx context.Context) (context.Context, error) {
+	var addr netip.Addr
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		if addrPort, err := netip.ParseAddrPort(p.Addr.String()); err == nil {
+			addr = addrPort.Addr().Unmap()
+		}
+	}
+	if s.allowSource != nil {
+		if !s.allowSource(addr) {
+			s.logger.Warn("Chamada administrativa gRPC recusada pela origem", zap.String("ip", addr.String()))
+			return nil, status.Error(codes.PermissionDenied, "Acesso negado para o endereço de origem")
+		}
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	var token string
+	if values := md.Get("authorization"); len(values) > 0 {
+		token = strings.TrimSpace(strings.TrimPrefix(values[0], "Bearer "))
+	}
+	if token == "" {
+		return nil, status.Error(codes.Unauthenticated, "Token de autenticação não fornecido")
+	}
+
+	user, err := s.auth.ValidateToken(token)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "Token inválido")
+	}
+	if !s.auth.IsAdmin(user) {
+		return nil, status.Error(codes.PermissionDenied, "Acesso restrito a administradores")
+	}
+
+	ctx = context.WithValue(ctx, userContextKey{}, user)
+	actor := audit.Actor{ID: user.ID, Username: user.Username}
+	if addr.IsValid() {
+		actor.IP = addr.String()
+	}
+	ctx = audit.WithActor(ctx, actor)
+	if s.tenantHeader != "" {
+		if values := md.Get(s.tenantHeader); len(values) > 0 {
+			ctx = tenant.WithTenant(ctx, strings.TrimSpace(values[0]))
+		}
+	}
+
+	// Administradores de um tenant só operam sobre ele
+	if user.Tenant != "" {
+		if requested := tenant.FromContext(ctx); requested != "" && requested != user.Tenant {
+			return nil, status.Error(codes.PermissionDenied, "Tenant fora do escopo do administrador")
+		}
+		ctx = tenant.WithTenant(ctx, user.Tenant)
+	}
+	return ctx, nil
+}
+
+// requireGlobalAdmin recusa operações que afetam todos os tenants quando o
+// chamador administra apenas um tenant
+func (s *Server) requireGlobalAdmin(ctx context.Context) error {
+	user, _ := ctx.Value(userContextKey{}).(*model.User)
+	if !s.auth.IsGlobalAdmin(user) {
+		return status.Error(codes.PermissionDenied, "Operação restrita a administradores de todos os tenants")
+	}
+	return nil
+}
+
+func (s *Server) unaryAuth(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+
+	ctx, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (s *Server) streamAuth(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+
+	ctx, err := s.authenticate(stream.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authenticatedStream{ServerStream: stream, ctx: ctx})
+}
+
+// authenticatedStream substitui o contexto do stream pelo contexto autenticado
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+// unaryMethod adapta uma função sobre mensagens dinâmicas a um método gRPC unário
+func (s *Server) unaryMethod(name, input string,
+	fn func(ctx context.Context, req *dynamicpb.Message) (proto.Message, error)) grpc.MethodDesc {
+
+	fullMethod := "/" + serviceName + "/" + name
+	return grpc.MethodDesc{
+		MethodName: name,
+		Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error,
+			interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+
+			req := s.schema.newMessage(input)
+			if err := dec(req); err != nil {
+				return nil, err
+			}
+			call := func(ctx context.Context, req interface{}) (interface{}, error) {
+				return fn(ctx, req.(*dynamicpb.Message))
+			}
+			if interceptor == nil {
+				return call(ctx, req)
+			}
+			return interceptor(ctx, req, &grpc.UnaryServerInfo{Server: srv, FullMethod: fullMethod}, call)
+		},
+	}
+}
+
+// serviceDesc descreve o AdminService para o servidor gRPC
+func (s *Server) serviceDesc() *grpc.ServiceDesc {
+	return &grpc.ServiceDesc{
+		ServiceName: serviceName,
+		HandlerType: (*interface{})(nil),
+		Methods: []grpc.MethodDesc{
+			s.unaryMethod("ListRoutes", "ListRoutesRequest", s.listRoutes),
+			s.unaryMethod("GetRoute", "GetRouteRequest", s.getRoute),
+			s.unaryMethod("CreateRoute", "Route", s.createRoute),
+			s.unaryMethod("UpdateRoute", "Route", s.updateRoute),
+			s.unaryMethod("DeleteRoute", "DeleteRouteRequest", s.deleteRoute),
+			s.unaryMethod("ListConsumers", "google.protobuf.Empty", s.listConsumers),
+			s.unaryMethod("PurgeCache", "PurgeCacheRequest", s.purgeCache),
+			s.unaryMethod("GetStatus", "google.protobuf.Empty", s.getStatus),
+		},
+		Streams: []grpc.StreamDesc{{
+			StreamName:    "WatchRoutes",
+			Handler:       s.watchRoutes,
+			ServerStreams: true,
+		}},
+		Metadata: "api/proto/admin/v1/admin.proto",
+	}
+}
+
+// routeError converte erros do serviço de rotas em status gRPC
+func (s *Server) routeError(err error, operation string) error {
+	switch {
+	case errors.Is(err, repository.ErrRouteNotFound):
+		return status.Error(codes.NotFound, "Rota não encontrada")
+	case errors.Is(err, repository.ErrRouteExists):
+		return status.Error(codes.AlreadyExists, "Rota já cadastrada")
+	case errors.Is(err, route.ErrPathConflict):
+		return status.Error(codes.AlreadyExists, err.Error())
+	}
+	s.logger.Error("Falha na API administrativa gRPC", zap.String("operation", operation), zap.Error(err))
+	return status.Error(codes.Internal, "Falha ao processar a requisição")
+}
+
+func (s *Server) listRoutes(ctx context.Context, _ *dynamicpb.Message) (proto.Message, error) {
+	routes, err := s.routes.GetRoutes(ctx)
+	if err != nil {
+		return nil, s.routeError(err, "ListRoutes")
+	}
+
+	resp := s.schema.newMessage("ListRoutesResponse")
+	list := resp.Mutable(resp.Descriptor().Fields().ByName("routes")).List()
+	for _, r := range routes {
+		msg, err := s.schema.routeToMessage(r)
+		if err != nil {
+			return nil, s.routeError(err, "ListRoutes")
+		}
+		list.Append(protoreflect.ValueOfMessage(msg))
+	}
+	return resp, nil
+}
+
+func (s *Server) getRoute(ctx context.Context, req *dynamicpb.Message) (proto.Message, error) {
+	path := getString(req, "path")
+	if path == "" {
+		return nil, status.Error(codes.InvalidArgument, "path é obrigatório")
+	}
+
+	r, err := s.routes.GetRouteByPath(ctx, path)
+	if err != nil {
+		return nil, s.routeError(err, "GetRoute")
+	}
+	msg, err := s.schema.routeToMessage(r)
+	if err != nil {
+		return nil, s.routeError(err, "GetRoute")
+	}
+	return msg, nil
+}
+
+// decodeRoute converte e valida a rota recebida
+func decodeRoute(req *dynamicpb.Message) (*model.Route, error) {
+	r, err := messageToRoute(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := r.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return r, nil
+}
+
+func (s *Server) createRoute(ctx context.Context, req *dynamicpb.Message) (proto.Message, error) {
+	r, err := decodeRoute(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.routes.AddRoute(ctx, r); err != nil {
+		return nil, s.routeError(err, "CreateRoute")
+	}
+
+	s.logger.Info("Rota registrada via API administrativa gRPC", zap.String("path", r.Path))
+	return s.getRoute(ctx, pathRequest(s.schema, r.Path))
+}
+
+func (s *Server) updateRoute(ctx context.Context, req *dynamicpb.Message) (proto.Message, error) {
+	r, err := decodeRoute(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.routes.UpdateRoute(ctx, r); err != nil {
+		return nil, s.routeError(err, "UpdateRoute")
+	}
+
+	s.logger.Info("Rota atualizada via API administrativa gRPC", zap.String("path", r.Path))
+	return s.getRoute(ctx, pathRequest(s.schema, r.Path))
+}
+
+// pathRequest monta uma GetRouteRequest para reler a rota gravada
+func pathRequest(s *schema, path string) *dynamicpb.Message {
+	req := s.newMessage("GetRouteRequest")
+	setField(req, "path", protoreflect.ValueOfString(path))
+	return req
+}
+
+func (s *Server) deleteRoute(ctx context.Context, req *dynamicpb.Message) (proto.Message, error) {
+	path := getString(req, "path")
+	if path == "" {
+		return nil, status.Error(codes.InvalidArgument, "path é obrigatório")
+	}
+	if err := s.routes.DeleteRoute(ctx, path); err != nil {
+		return nil, s.routeError(err, "DeleteRoute")
+	}
+
+	s.logger.Info("Rota excluída via API administrativa gRPC", zap.String("path", path))
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) listConsumers(ctx context.Context, _ *dynamicpb.Message) (proto.Message, error) {
+	if err := s.requireGlobalAdmin(ctx); err != nil {
+		return nil, err
+	}
+	users, err := s.consumers.ListUsers(ctx)
+	if err != nil {
+		s.logger.Error("Falha ao listar consumidores", zap.Error(err))
+		return nil, status.Error(codes.Internal, "Falha ao listar consumidores")
+	}
+
+	resp := s.schema.newMessage("ListConsumersResponse")
+	list := resp.Mutable(resp.Descriptor().Fields().ByName("consumers")).List()
+	for _, user := range users {
+		list.Append(protoreflect.ValueOfMessage(s.schema.userToMessage(user)))
+	}
+	return resp, nil
+}
+
+func (s *Server) purgeCache(ctx context.Context, req *dynamicpb.Message) (proto.Message, error) {
+	var err error
+	if getEnum(req, "scope") == purgeScopeAll {
+		if err := s.requireGlobalAdmin(ctx); err != nil {
+			return nil, err
+		}
+		err = s.cache.Clear(ctx)
+	} else {
+		err = s.routes.ClearCache(ctx)
+	}
+	if errors.Is(err, cache.ErrClearUnsupported) {
+		return nil, status.Error(codes.FailedPrecondition, "O cache configurado não permite limpar todas as chaves; use o escopo ROUTES")
+	}
+	if err != nil {
+		s.logger.Error("Falha ao limpar cache", zap.Error(err))
+		return nil, status.Error(codes.Internal, "Falha ao limpar cache")
+	}
+
+	resp := s.schema.newMessage("PurgeCacheResponse")
+	setField(resp, "purged", protoreflect.ValueOfBool(true))
+	return resp, nil
+}
+
+func (s *Server) getStatus(ctx context.Context, _ *dynamicpb.Message) (proto.Message, error) {
+	resp := s.schema.newMessage("Status")
+	setField(resp, "version", protoreflect.ValueOfString(os.Getenv("APP_VERSION")))
+	setField(resp, "started_at", protoreflect.ValueOfMessage(timestamppb.New(s.startedAt).ProtoReflect()))
+
+	if routes, err := s.routes.GetRoutes(ctx); err == nil {
+		setField(resp, "route_count", protoreflect.ValueOfInt32(int32(len(routes))))
+	}
+	setField(resp, "database", protoreflect.ValueOfString(dependencyStatus(ctx, s.db)))
+	setField(resp, "cache", protoreflect.ValueOfString(dependencyStatus(ctx, s.cache)))
+	return resp, nil
+}
+
+// dependencyStatus resume o resultado do ping de uma dependência
+func dependencyStatus(ctx context.Context, dep Pinger) string {
+	if dep == nil {
+		return "disabled"
+	}
+	if err := dep.Ping(ctx); err != nil {
+		return "unavailable"
+	}
+	return "ok"
+}
+
+// watchRoutes envia as alterações de rotas do tenant do chamador até o cliente desconectar.
+// Assinantes lentos podem perder eventos e devem se ressincronizar com ListRoutes.
+func (s *Server) watchRoutes(_ interface{}, stream grpc.ServerStream) error {
+	req := s.schema.newMessage("WatchRoutesRequest")
+	if err := stream.RecvMsg(req); err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	tenantID := tenant.FromContext(ctx)
+
+	// Assinar antes do snapshot para não perder alterações concorrentes
+	events, unsubscribe := s.routes.Subscribe(watchBuffer)
+	defer unsubscribe()
+
+	if getBool(req, "send_initial") {
+		routes, err := s.routes.GetRoutes(ctx)
+		if err != nil {
+			return s.routeError(err, "WatchRoutes")
+		}
+		now := time.Now()
+		for _, r := range routes {
+			if err := s.sendEvent(stream, eventTypeSnapshot, r.Path, r, now); err != nil {
+				return err
+			}
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if event.Tenant != tenantID {
+				continue
+			}
+			if err := s.sendEvent(stream, eventTypeFor(event.Type), event.Path, event.Route, event.Time); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// sendEvent envia uma mensagem RouteEvent ao cliente
+func (s *Server) sendEvent(stream grpc.ServerStream, eventType protoreflect.EnumNumber, path string,
+	r *model.Route, at time.Time) error {
+
+	msg := s.schema.newMessage("RouteEvent")
+	setField(msg, "type", protoreflect.ValueOfEnum(eventType))
+	setField(msg, "path", protoreflect.ValueOfString(path))
+	setTimestamp(msg, "time", at)
+	if r != nil {
+		routeMsg, err := s.schema.routeToMessage(r)
+		if err != nil {
+			return s.routeError(err, "WatchRoutes")
+		}
+		setField(msg, "route", protoreflect.ValueOfMessage(routeMsg))
+	}
+	return stream.SendMsg(msg)
+}
+
+// eventTypeFor converte o tipo do evento do serviço de rotas para o enum do proto
+func eventTypeFor(eventType route.EventType) protoreflect.EnumNumber {
+	switch eventType {
+	case route.EventCreated:
+		return eventTypeCreated
+	case route.EventUpdated:
+		return eventTypeUpdated
+	case route.EventDeleted:
+		return eventTypeDeleted
+	}
+	return 0
+}
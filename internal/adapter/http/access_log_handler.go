@@ -0,0 +1,86 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/diillson/api-gateway-go/internal/infra/middleware"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// accessLogStreamBuffer é a capacidade do canal de cada acompanhamento;
+// entradas excedentes de clientes lentos são descartadas
+const accessLogStreamBuffer = 256
+
+// AccessLogSubscriber fornece as requisições concluídas em tempo real
+type AccessLogSubscriber interface {
+	SubscribeAccessLog(buffer int) (<-chan middleware.AccessLogEntry, func())
+}
+
+// AccessLogHandler transmite o log de acesso desta instância
+type AccessLogHandler struct {
+	subscriber AccessLogSubscriber
+	logger     *zap.Logger
+}
+
+// NewAccessLogHandler cria o handler do log de acesso em tempo real
+func NewAccessLogHandler(subscriber AccessLogSubscriber, logger *zap.Logger) *AccessLogHandler {
+	return &AccessLogHandler{
+		subscriber: subscriber,
+		logger:     logger,
+	}
+}
+
+// RegisterRoutes registra o endpoint no grupo informado
+func (h *AccessLogHandler) RegisterRoutes(group *gin.RouterGroup) {
+	group.GET("/logs/access", h.Stream)
+}
+
+// Stream envia as requisições concluídas como JSON delimitado por linhas até
+// o cliente desconectar. Filtros: route (caminho registrado) e minStatus.
+func (h *AccessLogHandler) Stream(c *gin.Context) {
+	route := c.Query("route")
+	minStatus := 0
+	if value := c.Query("minStatus"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Parâmetro 'minStatus' inválido"})
+			return
+		}
+		minStatus = parsed
+	}
+
+	entries, cancel := h.subscriber.SubscribeAccessLog(accessLogStreamBuffer)
+	defer cancel()
+
+	h.logger.Info("Acompanhamento do log de acesso iniciado",
+		zap.String("route", route),
+		zap.String("ip", c.ClientIP()))
+
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Header("Cache-Control", "no-cache")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	encoder := json.NewEncoder(c.Writer)
+	ctx := c.Request.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case entry, ok := <-entries:
+			if !ok {
+				return
+			}
+			if (route != "" && entry.Route != route) || entry.Status < minStatus {
+				continue
+			}
+			if err := encoder.Encode(entry); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/internal/infra/featureflag"
+	"github.com/diillson/api-gateway-go/pkg/geoip"
+	"github.com/diillson/api-gateway-go/pkg/tenant"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// checkActivation recusa as requisições fora da ativação agendada da rota
+// (activeFrom/activeUntil), respondendo como se a rota não estivesse
+// registrada. Retorna false quando a requisição foi respondida.
+func (h *Handler) checkActivation(c *gin.Context, route *model.Route, span trace.Span) bool {
+	if route.ActiveAt(time.Now()) {
+		return true
+	}
+
+	path := c.Request.URL.Path
+	h.logger.Info("Rota fora da ativação agendada",
+		zap.String("path", path),
+		zap.String("activeFrom", route.ActiveFrom),
+		zap.String("activeUntil", route.ActiveUntil))
+	span.SetAttributes(attribute.Bool("route.scheduled_inactive", true))
+	if h.metrics != nil {
+		h.metrics.RequestError(path, c.Request.Method, "route_not_active")
+	}
+	writeRouteNotFound(c, path)
+	return false
+}
+
+// checkFeatureFlag avalia a flag da rota para o consumidor. Sem a flag, a rota
+// responde como não registrada; sem provedor ou quando ele falha, vale o
+// fallback da rota. Retorna false quando a requisição foi respondida.
+func (h *Handler) checkFeatureFlag(c *gin.Context, route *model.Route, span trace.Span) bool {
+	flag := route.FeatureFlag
+	if flag == nil {
+		return true
+	}
+
+	enabled := flag.Fallback
+	if h.featureFlags != nil {
+		subject := featureflag.Subject{
+			Route:    route.Path,
+			Consumer: consumerName(c),
+			Tenant:   tenant.FromContext(c.Request.Context()),
+			ClientIP: clientIP(c),
+			Country:  geoip.FromContext(c.Request.Context()).Country,
+		}
+		result, err := h.featureFlags.Evaluate(c.Request.Context(), flag.Name, subject)
+		if err != nil {
+			level := h.logger.Warn
+			if errors.Is(err, featureflag.ErrFlagNotFound) {
+				level = h.logger.Error
+			}
+			level("Falha ao avaliar a feature flag da rota; usando o fallback",
+				zap.String("route", route.Path),
+				zap.String("flag", flag.Name),
+				zap.Bool("fallback", flag.Fallback),
+				zap.Error(err))
+		} else {
+			enabled = result
+		}
+	}
+
+	span.SetAttributes(
+		attribute.String("feature_flag.key", flag.Name),
+		attribute.Bool("feature_flag.enabled", enabled),
+	)
+	if enabled {
+		return true
+	}
+
+	path := c.Request.URL.Path
+	if h.metrics != nil {
+		h.metrics.RequestError(path, c.Request.Method, "feature_flag_disabled")
+	}
+	writeRouteNotFound(c, path)
+	return false
+}
+
+// writeRouteNotFound responde como uma rota não registrada no gateway
+func writeRouteNotFound(c *gin.Context, path string) {
+	c.JSON(http.StatusNotFound, gin.H{
+		"error":   "API not found",
+		"details": "A rota requisitada não está registrada no API Gateway",
+		"path":    path,
+	})
+}
@@ -0,0 +1,266 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/diillson/api-gateway-go/internal/app/route"
+	"github.com/diillson/api-gateway-go/internal/app/routestats"
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/internal/domain/repository"
+	"github.com/diillson/api-gateway-go/internal/infra/metrics"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Paginação da listagem de rotas
+const (
+	defaultRoutesPageSize = 50
+	maxRoutesPageSize     = 500
+)
+
+// AdminRouteHandler expõe o CRUD de rotas da API administrativa versionada
+// (/admin/api/v1/routes). O caminho da rota é o sufixo da URL, por exemplo
+// GET /admin/api/v1/routes/api/users/:id.
+type AdminRouteHandler struct {
+	routeService *route.Service
+	logger       *zap.Logger
+	metrics      *metrics.APIMetrics
+	stats        *routestats.Service
+}
+
+// routeStatsSuffix é o sufixo da URL que consulta as estatísticas da rota,
+// ex.: GET /admin/api/v1/routes/api/users/:id/metrics
+const routeStatsSuffix = "/metrics"
+
+// NewAdminRouteHandler cria o handler da API administrativa de rotas
+func NewAdminRouteHandler(routeService *route.Service, logger *zap.Logger) *AdminRouteHandler {
+	return &AdminRouteHandler{
+		routeService: routeService,
+		logger:       logger,
+	}
+}
+
+// SetMetrics configura o objeto de métricas
+func (h *AdminRouteHandler) SetMetrics(metrics *metrics.APIMetrics) {
+	h.metrics = metrics
+}
+
+// SetRouteStats configura as estatísticas por rota retornadas em
+// GET /routes/<caminho>/metrics
+func (h *AdminRouteHandler) SetRouteStats(stats *routestats.Service) {
+	h.stats = stats
+}
+
+// RegisterRoutes registra os endpoints no grupo informado
+func (h *AdminRouteHandler) RegisterRoutes(group *gin.RouterGroup) {
+	group.GET("/routes", h.List)
+	group.POST("/routes", h.Create)
+	group.GET("/routes/*path", h.Get)
+	group.PUT("/routes/*path", h.Update)
+	group.DELETE("/routes/*path", h.Delete)
+}
+
+// List retorna uma página das rotas cadastradas, inclusive as inativas,
+// ordenadas pelo caminho. Parâmetros: page (a partir de 1) e pageSize.
+func (h *AdminRouteHandler) List(c *gin.Context) {
+	page, err := queryInt(c, "page", 1)
+	if err != nil || page < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parâmetro 'page' inválido"})
+		return
+	}
+	pageSize, err := queryInt(c, "pageSize", defaultRoutesPageSize)
+	if err != nil || pageSize < 1 || pageSize > maxRoutesPageSize {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Parâmetro 'pageSize' inválido",
+			"details": "pageSize deve estar entre 1 e " + strconv.Itoa(maxRoutesPageSize),
+		})
+		return
+	}
+
+	routes, err := h.routeService.ListAllRoutes(c.Request.Context())
+	if err != nil {
+		h.fail(c, err, "list_routes_error", "Falha ao listar rotas")
+		return
+	}
+
+	start := (page - 1) * pageSize
+	if start > len(routes) {
+		start = len(routes)
+	}
+	end := start + pageSize
+	if end > len(routes) {
+		end = len(routes)
+	}
+
+	items := make([]*model.Route, 0, end-start)
+	for _, r := range routes[start:end] {
+		items = append(items, r.Redacted())
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items":    items,
+		"page":     page,
+		"pageSize": pageSize,
+		"total":    len(routes),
+	})
+}
+
+// Get retorna a rota com exatamente o caminho informado. Caminhos terminados
+// em /metrics retornam as estatísticas da rota sem o sufixo, quando ela existe.
+func (h *AdminRouteHandler) Get(c *gin.Context) {
+	path := c.Param("path")
+	if routePath, ok := strings.CutSuffix(path, routeStatsSuffix); ok && routePath != "" {
+		if r, err := h.routeService.GetRoute(c.Request.Context(), routePath); err == nil {
+			h.Metrics(c, r)
+			return
+		}
+	}
+
+	r, err := h.routeService.GetRoute(c.Request.Context(), path)
+	if err != nil {
+		h.fail(c, err, "get_route_error", "Falha ao obter rota")
+		return
+	}
+	c.JSON(http.StatusOK, r.Redacted())
+}
+
+// Metrics retorna as estatísticas da rota nas janelas de 1m, 5m e 1h
+func (h *AdminRouteHandler) Metrics(c *gin.Context, r *model.Route) {
+	if h.stats == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Estatísticas por rota desabilitadas"})
+		return
+	}
+
+	report, err := h.stats.Report(c.Request.Context(), r.Tenant, r.Path)
+	if err != nil {
+		h.fail(c, err, "route_stats_error", "Falha ao obter estatísticas da rota")
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// Create cadastra uma nova rota. Com dryRun=true, apenas valida a rota.
+func (h *AdminRouteHandler) Create(c *gin.Context) {
+	r, ok := h.bindRoute(c, "")
+	if !ok || h.dryRun(c, r) {
+		return
+	}
+
+	if err := h.routeService.AddRoute(c.Request.Context(), r); err != nil {
+		h.fail(c, err, "add_route_error", "Falha ao registrar rota")
+		return
+	}
+
+	h.logger.Info("Rota registrada via API administrativa", zap.String("path", r.Path))
+	c.Header("Location", c.FullPath()+r.Path)
+	c.JSON(http.StatusCreated, r.Redacted())
+}
+
+// Update substitui a configuração da rota do caminho informado. O caminho do
+// corpo, se presente, deve ser o mesmo da URL. Com dryRun=true, apenas valida
+// a rota.
+func (h *AdminRouteHandler) Update(c *gin.Context) {
+	path := c.Param("path")
+	r, ok := h.bindRoute(c, path)
+	if !ok || h.dryRun(c, r) {
+		return
+	}
+
+	if err := h.routeService.UpdateRoute(c.Request.Context(), r); err != nil {
+		h.fail(c, err, "update_route_error", "Falha ao atualizar rota")
+		return
+	}
+
+	h.logger.Info("Rota atualizada via API administrativa", zap.String("path", r.Path))
+	c.JSON(http.StatusOK, r.Redacted())
+}
+
+// Delete remove a rota do caminho informado
+func (h *AdminRouteHandler) Delete(c *gin.Context) {
+	path := c.Param("path")
+	if err := h.routeService.DeleteRoute(c.Request.Context(), path); err != nil {
+		h.fail(c, err, "delete_route_error", "Falha ao excluir rota")
+		return
+	}
+
+	h.logger.Info("Rota excluída via API administrativa", zap.String("path", path))
+	c.Status(http.StatusNoContent)
+}
+
+// bindRoute decodifica e valida a rota do corpo. Com path informado, o
+// caminho do corpo é preenchido com ele ou precisa coincidir.
+func (h *AdminRouteHandler) bindRoute(c *gin.Context, path string) (*model.Route, bool) {
+	var r model.Route
+	if err := c.ShouldBindJSON(&r); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return nil, false
+	}
+
+	if path != "" {
+		if r.Path == "" {
+			r.Path = path
+		}
+		if r.Path != path {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Rota inválida",
+				"details": "o path do corpo difere do caminho da URL; para renomear, exclua e recadastre a rota",
+			})
+			return nil, false
+		}
+	}
+
+	if err := r.Validate(); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Rota inválida", "details": err.Error()})
+		return nil, false
+	}
+	return &r, true
+}
+
+// dryRun responde com a rota validada, sem gravá-la, quando a requisição pede
+// apenas a validação (usada pelo editor do painel administrativo)
+func (h *AdminRouteHandler) dryRun(c *gin.Context, r *model.Route) bool {
+	if c.Query("dryRun") != "true" {
+		return false
+	}
+	c.JSON(http.StatusOK, r.Redacted())
+	return true
+}
+
+// fail converte os erros do serviço de rotas na resposta HTTP
+func (h *AdminRouteHandler) fail(c *gin.Context, err error, errorType, message string) {
+	switch {
+	case errors.Is(err, repository.ErrRouteNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Rota não encontrada", "path": c.Param("path")})
+		return
+	case errors.Is(err, repository.ErrRouteExists):
+		c.JSON(http.StatusConflict, gin.H{"error": "Rota já cadastrada"})
+		return
+	case isRouteConflict(err):
+		c.JSON(http.StatusConflict, gin.H{"error": "Caminho conflita com rota existente", "details": err.Error()})
+		return
+	}
+
+	h.logger.Error(message, zap.Error(err))
+	if h.metrics != nil {
+		h.metrics.RequestError(c.FullPath(), c.Request.Method, errorType)
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+}
+
+// isRouteConflict indica que a rota já está cadastrada ou que seu caminho é
+// equivalente ao de outra rota
+func isRouteConflict(err error) bool {
+	return errors.Is(err, repository.ErrRouteExists) || errors.Is(err, route.ErrPathConflict)
+}
+
+// queryInt lê um parâmetro inteiro da query, com valor padrão quando ausente
+func queryInt(c *gin.Context, name string, fallback int) (int, error) {
+	value := c.Query(name)
+	if value == "" {
+		return fallback, nil
+	}
+	return strconv.Atoi(value)
+}
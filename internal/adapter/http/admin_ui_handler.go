@@ -0,0 +1,43 @@
+package http
+
+import (
+	"io/fs"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// AdminUIHandler serve o painel administrativo, uma página estática que
+// consome a API administrativa com o token do usuário
+type AdminUIHandler struct {
+	files  http.FileSystem
+	logger *zap.Logger
+}
+
+// NewAdminUIHandler cria o handler do painel com os arquivos informados
+func NewAdminUIHandler(files fs.FS, logger *zap.Logger) *AdminUIHandler {
+	return &AdminUIHandler{
+		files:  http.FS(files),
+		logger: logger,
+	}
+}
+
+// RegisterRoutes registra os arquivos do painel no grupo informado. Os
+// arquivos não exigem autenticação; os dados vêm da API administrativa, que
+// continua exigindo o token.
+func (h *AdminUIHandler) RegisterRoutes(group *gin.RouterGroup) {
+	group.GET("/*filepath", h.Serve)
+	group.HEAD("/*filepath", h.Serve)
+}
+
+// Serve entrega o arquivo solicitado; a raiz entrega a página do painel
+func (h *AdminUIHandler) Serve(c *gin.Context) {
+	// A página carrega apenas os próprios arquivos e não pode ser embutida
+	// em outros sites
+	c.Header("Content-Security-Policy", "default-src 'self'; frame-ancestors 'none'")
+	c.Header("X-Frame-Options", "DENY")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Cache-Control", "no-cache")
+	c.FileFromFS(c.Param("filepath"), h.files)
+}
@@ -0,0 +1,90 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/app/auth"
+	"github.com/diillson/api-gateway-go/internal/domain/repository"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// APIKeyHandler expõe a administração das chaves de API
+type APIKeyHandler struct {
+	service *auth.APIKeyService
+	logger  *zap.Logger
+}
+
+// NewAPIKeyHandler cria um novo handler de chaves de API
+func NewAPIKeyHandler(service *auth.APIKeyService, logger *zap.Logger) *APIKeyHandler {
+	return &APIKeyHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// CreateAPIKeyRequest contém os dados para emitir uma chave de API
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" binding:"required"`
+	Routes    []string   `json:"routes"`
+	Tier      string     `json:"tier"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+// Create emite uma nova chave. O valor da chave é exibido apenas nesta resposta.
+func (h *APIKeyHandler) Create(c *gin.Context) {
+	var req CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	key, value, err := h.service.Create(c.Request.Context(), auth.APIKeyRequest{
+		Name:      req.Name,
+		Routes:    req.Routes,
+		Tier:      req.Tier,
+		ExpiresAt: req.ExpiresAt,
+	})
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidAPIKeyRequest) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("Falha ao criar chave de API", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Falha ao criar chave de API"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"key":    value,
+		"apiKey": key,
+	})
+}
+
+// List retorna as chaves emitidas, sem os valores
+func (h *APIKeyHandler) List(c *gin.Context) {
+	keys, err := h.service.List(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Falha ao listar chaves de API", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Falha ao listar chaves de API"})
+		return
+	}
+	c.JSON(http.StatusOK, keys)
+}
+
+// Revoke revoga uma chave pelo ID
+func (h *APIKeyHandler) Revoke(c *gin.Context) {
+	id := c.Param("id")
+	if err := h.service.Revoke(c.Request.Context(), id); err != nil {
+		if errors.Is(err, repository.ErrAPIKeyNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Chave de API não encontrada"})
+			return
+		}
+		h.logger.Error("Falha ao revogar chave de API", zap.String("id", id), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Falha ao revogar chave de API"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Chave de API revogada com sucesso"})
+}
@@ -0,0 +1,92 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/app/audit"
+	"github.com/diillson/api-gateway-go/internal/domain/repository"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultAuditPageSize = 100
+	maxAuditPageSize     = 1000
+)
+
+// AuditHandler expõe a consulta ao log de auditoria
+type AuditHandler struct {
+	recorder *audit.Recorder
+	logger   *zap.Logger
+}
+
+// NewAuditHandler cria um novo handler do log de auditoria
+func NewAuditHandler(recorder *audit.Recorder, logger *zap.Logger) *AuditHandler {
+	return &AuditHandler{
+		recorder: recorder,
+		logger:   logger,
+	}
+}
+
+// RegisterRoutes registra os endpoints no grupo informado
+func (h *AuditHandler) RegisterRoutes(group *gin.RouterGroup) {
+	group.GET("/audit", h.List)
+}
+
+// List retorna uma página dos registros de auditoria, do mais recente ao mais
+// antigo. Filtros: action, resource, actor, from e to (RFC 3339, to exclusivo).
+// Paginação: page (a partir de 1) e pageSize.
+func (h *AuditHandler) List(c *gin.Context) {
+	page, err := queryInt(c, "page", 1)
+	if err != nil || page < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parâmetro 'page' inválido"})
+		return
+	}
+	pageSize, err := queryInt(c, "pageSize", defaultAuditPageSize)
+	if err != nil || pageSize < 1 || pageSize > maxAuditPageSize {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Parâmetro 'pageSize' inválido",
+			"details": "pageSize deve estar entre 1 e " + strconv.Itoa(maxAuditPageSize),
+		})
+		return
+	}
+
+	filter := repository.AuditFilter{
+		Action:   c.Query("action"),
+		Resource: c.Query("resource"),
+		Actor:    c.Query("actor"),
+		Offset:   (page - 1) * pageSize,
+		Limit:    pageSize,
+	}
+	for name, target := range map[string]*time.Time{"from": &filter.From, "to": &filter.To} {
+		value := c.Query(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Parâmetro '" + name + "' inválido",
+				"details": "use o formato RFC 3339 (ex.: 2026-10-16T14:00:00Z)",
+			})
+			return
+		}
+		*target = parsed
+	}
+
+	records, total, err := h.recorder.List(c.Request.Context(), filter)
+	if err != nil {
+		h.logger.Error("Falha ao consultar log de auditoria", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Falha ao consultar log de auditoria"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items":    records,
+		"page":     page,
+		"pageSize": pageSize,
+		"total":    total,
+	})
+}
@@ -0,0 +1,89 @@
+package http
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/pkg/reqtemplate"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// transformRequestBody aplica a transformação de corpo da rota à requisição e
+// disponibiliza os dados da requisição à transformação da resposta, feita no
+// proxy. Retorna false quando a requisição foi recusada.
+func (h *Handler) transformRequestBody(c *gin.Context, route *model.Route, data *reqtemplate.Data, span trace.Span) bool {
+	transforms := route.BodyTransforms
+	if transforms == nil {
+		return true
+	}
+	if transforms.Response != nil {
+		c.Request = c.Request.WithContext(reqtemplate.WithData(c.Request.Context(), data))
+	}
+
+	transform := transforms.Request
+	if transform == nil || c.Request.Body == nil || c.Request.Body == http.NoBody ||
+		c.Request.Header.Get("Content-Encoding") != "" || !reqtemplate.IsJSON(c.ContentType()) {
+		return true
+	}
+	path := c.Request.URL.Path
+
+	limit := transform.Limit()
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, limit+1))
+	if err != nil {
+		// O corpo pode ter excedido o limite da rota (requestBody.maxBytes)
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Corpo da requisição excede o limite da rota"})
+			return false
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Falha ao ler o corpo da requisição"})
+		return false
+	}
+	if int64(len(body)) > limit {
+		if h.metrics != nil {
+			h.metrics.RequestError(path, c.Request.Method, "body_too_large")
+		}
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Corpo da requisição excede o limite da transformação"})
+		return false
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		return true
+	}
+
+	template, err := reqtemplate.CompileBody(transform.Template)
+	if err == nil {
+		body, err = template.Transform(body, data)
+	}
+	if err != nil {
+		span.SetAttributes(attribute.String("body_transform.error", err.Error()))
+		if h.metrics != nil {
+			h.metrics.RequestError(path, c.Request.Method, "body_transform_error")
+		}
+		if errors.Is(err, reqtemplate.ErrInvalidBody) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Corpo da requisição não é JSON válido"})
+			return false
+		}
+		h.logger.Error("Falha ao transformar o corpo da requisição",
+			zap.String("path", path),
+			zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Falha ao transformar o corpo da requisição"})
+		return false
+	}
+
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	c.Request.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	c.Request.ContentLength = int64(len(body))
+	c.Request.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	span.SetAttributes(attribute.Bool("body_transform.request", true))
+	return true
+}
@@ -0,0 +1,55 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/diillson/api-gateway-go/pkg/cluster"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ClusterHandler expõe o estado do cluster de instâncias
+type ClusterHandler struct {
+	node   *cluster.Node
+	logger *zap.Logger
+}
+
+// NewClusterHandler cria um novo handler de cluster. node é nil quando o
+// gateway opera como instância única.
+func NewClusterHandler(node *cluster.Node, logger *zap.Logger) *ClusterHandler {
+	return &ClusterHandler{
+		node:   node,
+		logger: logger,
+	}
+}
+
+// Members lista as instâncias ativas e o líder atual
+func (h *ClusterHandler) Members(c *gin.Context) {
+	if h.node == nil {
+		c.JSON(http.StatusOK, gin.H{"enabled": false, "members": []cluster.Member{}})
+		return
+	}
+
+	members, err := h.node.Members(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Falha ao listar membros do cluster", zap.Error(err))
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Falha ao consultar o cluster"})
+		return
+	}
+
+	leader := ""
+	for _, member := range members {
+		if member.Leader {
+			leader = member.ID
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"enabled":  true,
+		"node":     h.node.ID(),
+		"leader":   leader,
+		"isLeader": h.node.IsLeader(),
+		"members":  members,
+		"jobs":     h.node.Jobs(), // Tarefas exclusivas do líder e se estão em execução nesta instância
+	})
+}
@@ -0,0 +1,272 @@
+package http
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/pkg/compression"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// compressResponse passa a comprimir a resposta da rota na codificação
+// negociada com o cliente. A função retornada finaliza a compressão e deve ser
+// chamada após o proxy, antes de ler o tamanho da resposta.
+func (h *Handler) compressResponse(c *gin.Context, route *model.Route) func() {
+	cfg := h.compression.Load()
+	if cfg == nil {
+		return func() {}
+	}
+
+	enabled := cfg.Enabled
+	minSize := cfg.MinSize
+	contentTypes := cfg.ContentTypes
+	if policy := route.Compression; policy != nil {
+		if policy.Enabled != nil {
+			enabled = *policy.Enabled
+		}
+		if policy.MinSize > 0 {
+			minSize = policy.MinSize
+		}
+		if len(policy.ContentTypes) > 0 {
+			contentTypes = policy.ContentTypes
+		}
+	}
+	// Conexões WebSocket e respostas a HEAD não têm corpo a comprimir
+	if !enabled || c.Request.Method == http.MethodHead || c.GetHeader("Upgrade") != "" {
+		return func() {}
+	}
+
+	encoding := compression.Negotiate(c.GetHeader("Accept-Encoding"), cfg.Encodings)
+	if encoding == compression.Identity {
+		return func() {}
+	}
+
+	original := c.Writer
+	writer := &compressWriter{
+		ResponseWriter: original,
+		encoding:       encoding,
+		minSize:        minSize,
+		contentTypes:   contentTypes,
+		logger:         h.logger,
+	}
+	c.Writer = writer
+	return func() {
+		writer.close()
+		c.Writer = original
+	}
+}
+
+// compressWriter comprime o corpo da resposta quando o tipo de conteúdo é
+// aceito e o tamanho atinge o mínimo. Sem Content-Length, guarda o início do
+// corpo até saber se o mínimo foi atingido. Respostas já codificadas pelo
+// backend são repassadas sem alteração.
+type compressWriter struct {
+	gin.ResponseWriter
+	encoding     string
+	minSize      int
+	contentTypes []string
+	logger       *zap.Logger
+
+	status  int
+	decided bool
+	encoder compression.Writer
+	pending []byte
+}
+
+// WriteHeader adia o envio do status até a decisão sobre a compressão, que
+// altera os cabeçalhos
+func (w *compressWriter) WriteHeader(code int) {
+	if w.decided || w.status != 0 {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	w.status = code
+	if !w.compressible(true) {
+		w.passthrough()
+	}
+}
+
+// WriteHeaderNow envia o status e os cabeçalhos sem aguardar o corpo
+func (w *compressWriter) WriteHeaderNow() {
+	if !w.decided {
+		w.start(w.compressible(false))
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if !w.decided {
+		if !w.compressible(true) {
+			w.passthrough()
+		} else {
+			w.pending = append(w.pending, data...)
+			if len(w.pending) < w.minSize {
+				return len(data), nil
+			}
+			w.start(true)
+			return len(data), w.flushPending()
+		}
+	}
+	if w.encoder != nil {
+		return w.encoder.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush envia o que já foi comprimido, para respostas entregues aos poucos.
+// Antes do mínimo, a resposta passa a ser comprimida, pois o tamanho final é
+// desconhecido.
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		w.start(w.status != 0 && w.compressible(false))
+		if err := w.flushPending(); err != nil {
+			w.logger.Warn("Falha ao comprimir resposta", zap.Error(err))
+		}
+	}
+	if w.encoder != nil {
+		if err := w.encoder.Flush(); err != nil {
+			w.logger.Warn("Falha ao comprimir resposta", zap.Error(err))
+		}
+	}
+	w.ResponseWriter.Flush()
+}
+
+// Hijack repassa a conexão sem compressão (ex.: upgrade de protocolo)
+func (w *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if !w.decided {
+		w.passthrough()
+	}
+	return w.ResponseWriter.Hijack()
+}
+
+// close envia o corpo pendente e finaliza a compressão
+func (w *compressWriter) close() {
+	if !w.decided {
+		if w.status == 0 {
+			return
+		}
+		// Corpo menor que o mínimo: enviado sem compressão
+		w.passthrough()
+	}
+	if err := w.flushPending(); err != nil {
+		w.logger.Warn("Falha ao enviar resposta", zap.Error(err))
+	}
+	if w.encoder != nil {
+		if err := w.encoder.Close(); err != nil {
+			w.logger.Warn("Falha ao finalizar compressão da resposta", zap.Error(err))
+		}
+	}
+}
+
+// compressible indica se a resposta pode ser comprimida pelos cabeçalhos já
+// definidos. Com checkSize, respostas com Content-Length abaixo do mínimo são
+// recusadas.
+func (w *compressWriter) compressible(checkSize bool) bool {
+	if w.status < http.StatusOK || w.status == http.StatusNoContent || w.status == http.StatusNotModified {
+		return false
+	}
+	header := w.Header()
+	if encoding := header.Get("Content-Encoding"); encoding != "" && encoding != compression.Identity {
+		return false
+	}
+	if strings.Contains(header.Get("Cache-Control"), "no-transform") {
+		return false
+	}
+	if checkSize {
+		if length, err := strconv.Atoi(header.Get("Content-Length")); err == nil && length < w.minSize {
+			return false
+		}
+	}
+	return matchesContentType(header.Get("Content-Type"), w.contentTypes)
+}
+
+// passthrough envia a resposta sem compressão
+func (w *compressWriter) passthrough() {
+	w.start(false)
+}
+
+// start decide a compressão, ajusta os cabeçalhos e envia o status
+func (w *compressWriter) start(compress bool) {
+	w.decided = true
+	header := w.Header()
+	if compress {
+		encoder, err := compression.NewWriter(w.encoding, w.ResponseWriter)
+		if err != nil {
+			w.logger.Warn("Falha ao iniciar compressão da resposta", zap.String("encoding", w.encoding), zap.Error(err))
+		} else {
+			w.encoder = encoder
+			header.Set("Content-Encoding", w.encoding)
+			header.Del("Content-Length")
+			// O ETag forte identifica a representação sem compressão
+			if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+				header.Set("ETag", "W/"+etag)
+			}
+			if !varyIncludesEncoding(header) {
+				header.Add("Vary", "Accept-Encoding")
+			}
+		}
+	}
+	if w.status != 0 {
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+}
+
+// flushPending envia o início do corpo guardado antes da decisão
+func (w *compressWriter) flushPending() error {
+	if len(w.pending) == 0 {
+		return nil
+	}
+	pending := w.pending
+	w.pending = nil
+	var err error
+	if w.encoder != nil {
+		_, err = w.encoder.Write(pending)
+	} else {
+		_, err = w.ResponseWriter.Write(pending)
+	}
+	return err
+}
+
+// matchesContentType indica se o tipo de conteúdo está na lista. Itens como
+// "text/*" aceitam todos os subtipos.
+func matchesContentType(contentType string, accepted []string) bool {
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	if mediaType == "" {
+		return false
+	}
+	for _, item := range accepted {
+		item = strings.ToLower(strings.TrimSpace(item))
+		if item == mediaType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(item, "/*"); ok && strings.HasPrefix(mediaType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// varyIncludesEncoding indica se o Vary da resposta já cita o Accept-Encoding
+func varyIncludesEncoding(header http.Header) bool {
+	for _, value := range header.Values("Vary") {
+		for _, item := range strings.Split(value, ",") {
+			item = strings.TrimSpace(item)
+			if item == "*" || strings.EqualFold(item, "Accept-Encoding") {
+				return true
+			}
+		}
+	}
+	return false
+}
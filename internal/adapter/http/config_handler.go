@@ -0,0 +1,45 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/diillson/api-gateway-go/pkg/config"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ConfigReloader recarrega a configuração da instância
+type ConfigReloader interface {
+	ReloadConfig(ctx context.Context) (config.ReloadResult, error)
+}
+
+// ConfigHandler expõe a recarga da configuração sem reinício
+type ConfigHandler struct {
+	reloader ConfigReloader
+	logger   *zap.Logger
+}
+
+// NewConfigHandler cria o handler de recarga da configuração
+func NewConfigHandler(reloader ConfigReloader, logger *zap.Logger) *ConfigHandler {
+	return &ConfigHandler{
+		reloader: reloader,
+		logger:   logger,
+	}
+}
+
+// RegisterRoutes registra o endpoint no grupo informado
+func (h *ConfigHandler) RegisterRoutes(group *gin.RouterGroup) {
+	group.POST("/config/reload", h.Reload)
+}
+
+// Reload recarrega a configuração desta instância e informa as chaves
+// aplicadas e as que só valem após reiniciar
+func (h *ConfigHandler) Reload(c *gin.Context) {
+	result, err := h.reloader.ReloadConfig(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Configuração inválida: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,142 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// isPreflight indica se a requisição é um preflight CORS do navegador
+func isPreflight(r *http.Request) bool {
+	return r.Method == http.MethodOptions && r.Header.Get("Origin") != "" &&
+		r.Header.Get("Access-Control-Request-Method") != ""
+}
+
+// applyCORS aplica a política CORS da rota. Preflights são respondidos pelo
+// gateway, sem chegar ao backend; nas demais requisições de origens
+// permitidas, os cabeçalhos CORS substituem os do backend no envio da
+// resposta, inclusive nas respostas de erro do gateway. Retorna false quando a
+// requisição já foi respondida.
+func (h *Handler) applyCORS(c *gin.Context, route *model.Route, span trace.Span) bool {
+	policy := route.CORS
+	origin := c.GetHeader("Origin")
+	if policy == nil || origin == "" {
+		return true
+	}
+	path := c.Request.URL.Path
+
+	if !policy.AllowsOrigin(origin) {
+		span.SetAttributes(attribute.String("cors.rejected_origin", origin))
+		if !isPreflight(c.Request) {
+			// Sem os cabeçalhos CORS, o navegador bloqueia a leitura da resposta
+			return true
+		}
+		h.logger.Debug("Preflight de origem não permitida",
+			zap.String("path", path),
+			zap.String("origin", origin))
+		if h.metrics != nil {
+			h.metrics.RequestError(path, c.Request.Method, "cors_rejected")
+		}
+		c.JSON(http.StatusForbidden, gin.H{"error": "Origem não permitida pela rota"})
+		return false
+	}
+
+	if isPreflight(c.Request) {
+		return h.preflight(c, route, policy, origin)
+	}
+
+	c.Writer = &headerRewriter{
+		ResponseWriter: c.Writer,
+		rewrite: func() {
+			header := c.Writer.Header()
+			clearCORSHeaders(header)
+			setAllowOrigin(header, policy, origin)
+			if len(policy.ExposeHeaders) > 0 {
+				header.Set("Access-Control-Expose-Headers", strings.Join(policy.ExposeHeaders, ", "))
+			}
+		},
+	}
+	return true
+}
+
+// preflight responde ao preflight com os métodos e cabeçalhos permitidos
+func (h *Handler) preflight(c *gin.Context, route *model.Route, policy *model.CORSPolicy, origin string) bool {
+	methods := policy.Methods(route.Methods)
+	requestedMethod := strings.ToUpper(c.GetHeader("Access-Control-Request-Method"))
+	var requestedHeaders []string
+	for _, value := range c.Request.Header.Values("Access-Control-Request-Headers") {
+		for _, header := range strings.Split(value, ",") {
+			if header = strings.TrimSpace(header); header != "" {
+				requestedHeaders = append(requestedHeaders, header)
+			}
+		}
+	}
+
+	allowed := false
+	for _, method := range methods {
+		if method == requestedMethod {
+			allowed = true
+			break
+		}
+	}
+	if !allowed || !policy.AllowsHeaders(requestedHeaders) {
+		h.logger.Debug("Preflight recusado pela política CORS da rota",
+			zap.String("path", c.Request.URL.Path),
+			zap.String("method", requestedMethod),
+			zap.Strings("headers", requestedHeaders))
+		if h.metrics != nil {
+			h.metrics.RequestError(c.Request.URL.Path, c.Request.Method, "cors_rejected")
+		}
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":           "Método ou cabeçalhos não permitidos pela rota",
+			"allowed_methods": methods,
+		})
+		return false
+	}
+
+	header := c.Writer.Header()
+	setAllowOrigin(header, policy, origin)
+	header.Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+	if len(requestedHeaders) > 0 {
+		// Os cabeçalhos solicitados já foram verificados pela política
+		header.Set("Access-Control-Allow-Headers", strings.Join(requestedHeaders, ", "))
+	}
+	if policy.MaxAge > 0 {
+		header.Set("Access-Control-Max-Age", strconv.Itoa(policy.MaxAge))
+	}
+	header.Add("Vary", "Access-Control-Request-Method")
+	header.Add("Vary", "Access-Control-Request-Headers")
+	c.Status(http.StatusNoContent)
+	c.Writer.WriteHeaderNow()
+	return false
+}
+
+// setAllowOrigin informa a origem permitida. Com credenciais ou lista de
+// origens, a origem da requisição é repetida e a resposta varia por Origin.
+func setAllowOrigin(header http.Header, policy *model.CORSPolicy, origin string) {
+	if policy.AnyOrigin() {
+		header.Set("Access-Control-Allow-Origin", "*")
+	} else {
+		header.Set("Access-Control-Allow-Origin", origin)
+		header.Add("Vary", "Origin")
+	}
+	if policy.AllowCredentials {
+		header.Set("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+// clearCORSHeaders remove os cabeçalhos CORS enviados pelo backend, que
+// poderiam contradizer a política da rota
+func clearCORSHeaders(header http.Header) {
+	for name := range header {
+		if strings.HasPrefix(name, "Access-Control-") {
+			header.Del(name)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/app/auth"
+	"github.com/diillson/api-gateway-go/internal/domain/repository"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// CredentialHandler expõe a administração das credenciais basic e hmac
+type CredentialHandler struct {
+	service *auth.CredentialService
+	logger  *zap.Logger
+}
+
+// NewCredentialHandler cria um novo handler de credenciais
+func NewCredentialHandler(service *auth.CredentialService, logger *zap.Logger) *CredentialHandler {
+	return &CredentialHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// CreateCredentialRequest contém os dados para emitir uma credencial
+type CreateCredentialRequest struct {
+	Name      string     `json:"name" binding:"required"`
+	Type      string     `json:"type" binding:"required"`
+	Username  string     `json:"username"`
+	Password  string     `json:"password"`
+	Routes    []string   `json:"routes"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+// Create emite uma nova credencial. A senha ou o segredo de assinatura é
+// exibido apenas nesta resposta.
+func (h *CredentialHandler) Create(c *gin.Context) {
+	var req CreateCredentialRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	credential, secret, err := h.service.Create(c.Request.Context(), auth.CredentialRequest{
+		Name:      req.Name,
+		Type:      req.Type,
+		Username:  req.Username,
+		Password:  req.Password,
+		Routes:    req.Routes,
+		ExpiresAt: req.ExpiresAt,
+	})
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidCredentialRequest) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("Falha ao criar credencial", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Falha ao criar credencial"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"secret":     secret,
+		"credential": credential,
+	})
+}
+
+// List retorna as credenciais emitidas, sem os segredos
+func (h *CredentialHandler) List(c *gin.Context) {
+	credentials, err := h.service.List(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Falha ao listar credenciais", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Falha ao listar credenciais"})
+		return
+	}
+	c.JSON(http.StatusOK, credentials)
+}
+
+// Revoke revoga uma credencial pelo ID
+func (h *CredentialHandler) Revoke(c *gin.Context) {
+	id := c.Param("id")
+	if err := h.service.Revoke(c.Request.Context(), id); err != nil {
+		if errors.Is(err, repository.ErrCredentialNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Credencial não encontrada"})
+			return
+		}
+		h.logger.Error("Falha ao revogar credencial", zap.String("id", id), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Falha ao revogar credencial"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Credencial revogada com sucesso"})
+}
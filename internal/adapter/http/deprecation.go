@@ -0,0 +1,96 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// applyDeprecation informa a descontinuação da rota nos cabeçalhos Deprecation
+// (RFC 9745), Sunset (RFC 8594) e Link, e recusa as requisições com 410 após a
+// data de desativação quando a rota exige. Retorna false quando a requisição
+// foi respondida.
+func (h *Handler) applyDeprecation(c *gin.Context, route *model.Route, span trace.Span) bool {
+	deprecation := route.Deprecation
+	now := time.Now()
+	if deprecation == nil || !deprecation.Deprecated(now) {
+		return true
+	}
+
+	header := c.Writer.Header()
+	if since := deprecation.SinceTime(); !since.IsZero() {
+		header.Set("Deprecation", "@"+strconv.FormatInt(since.Unix(), 10))
+	} else {
+		header.Set("Deprecation", "true")
+	}
+	sunset := deprecation.SunsetTime()
+	if !sunset.IsZero() {
+		header.Set("Sunset", sunset.Format(http.TimeFormat))
+	}
+	if deprecation.Link != "" {
+		header.Add("Link", "<"+deprecation.Link+`>; rel="deprecation"; type="text/html"`)
+	}
+	if deprecation.Successor != "" {
+		header.Add("Link", "<"+deprecation.Successor+`>; rel="successor-version"`)
+	}
+	span.SetAttributes(attribute.Bool("route.deprecated", true))
+
+	if !deprecation.Sunsetted(now) {
+		return true
+	}
+
+	path := c.Request.URL.Path
+	h.logger.Warn("Requisição recusada a rota desativada",
+		zap.String("path", path),
+		zap.String("route", route.Path),
+		zap.String("consumer", deprecatedConsumer(c)),
+		zap.Time("sunset", sunset))
+	span.SetAttributes(attribute.Bool("route.sunset", true))
+	if h.metrics != nil {
+		h.metrics.RequestError(path, c.Request.Method, "route_sunset")
+		h.metrics.DeprecatedRouteUsed(route.Path, deprecatedConsumer(c), true)
+	}
+
+	body := gin.H{
+		"error":   "API desativada",
+		"details": "Esta rota foi desativada em " + sunset.Format(time.RFC3339),
+	}
+	if deprecation.Successor != "" {
+		body["successor"] = deprecation.Successor
+	}
+	c.JSON(http.StatusGone, body)
+	return false
+}
+
+// recordDeprecatedUsage registra o uso da rota descontinuada pelo consumidor
+// autenticado, para acompanhar a migração antes da desativação
+func (h *Handler) recordDeprecatedUsage(c *gin.Context, route *model.Route) {
+	deprecation := route.Deprecation
+	if deprecation == nil || !deprecation.Deprecated(time.Now()) {
+		return
+	}
+
+	consumer := deprecatedConsumer(c)
+	h.logger.Info("Uso de rota descontinuada",
+		zap.String("route", route.Path),
+		zap.String("method", c.Request.Method),
+		zap.String("consumer", consumer),
+		zap.String("sunset", deprecation.Sunset))
+	if h.metrics != nil {
+		h.metrics.DeprecatedRouteUsed(route.Path, consumer, false)
+	}
+}
+
+// deprecatedConsumer identifica o consumidor nas métricas de descontinuação
+func deprecatedConsumer(c *gin.Context) string {
+	if consumer := consumerName(c); consumer != "" {
+		return consumer
+	}
+	return "anonymous"
+}
@@ -0,0 +1,112 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/internal/infra/extauthz"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// SetExternalAuthorizer configura o cliente dos serviços de autorização externos
+func (h *Handler) SetExternalAuthorizer(authorizer *extauthz.Authorizer) {
+	h.extAuthz = authorizer
+}
+
+// authorizeExternal consulta o serviço de autorização externo da rota e aplica
+// a decisão: recusas são devolvidas ao cliente e permissões podem alterar os
+// cabeçalhos enviados ao backend. Retorna false quando a requisição foi recusada.
+func (h *Handler) authorizeExternal(c *gin.Context, route *model.Route, span trace.Span) bool {
+	policy := route.ExternalAuthz
+	if policy == nil {
+		return true
+	}
+	path := c.Request.URL.Path
+
+	if h.extAuthz == nil {
+		h.logger.Error("Rota exige autorização externa, mas o cliente não está configurado",
+			zap.String("path", path))
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Autorização da rota indisponível"})
+		return false
+	}
+
+	decision, cached, err := h.extAuthz.Check(c.Request.Context(), policy, externalAuthzRequest(c, route))
+	if err != nil {
+		span.SetAttributes(attribute.String("ext_authz.error", err.Error()))
+		if policy.FailOpen {
+			h.logger.Warn("Serviço de autorização externo indisponível; permitindo pela configuração failOpen",
+				zap.String("path", path),
+				zap.Error(err))
+			span.SetAttributes(attribute.String("ext_authz.decision", "fail_open"))
+			return true
+		}
+
+		h.logger.Error("Serviço de autorização externo indisponível",
+			zap.String("path", path),
+			zap.Error(err))
+		span.SetAttributes(attribute.String("ext_authz.decision", "error"))
+		if h.metrics != nil {
+			h.metrics.RequestError(path, c.Request.Method, "ext_authz_error")
+		}
+		c.JSON(http.StatusForbidden, gin.H{"error": "Acesso negado: serviço de autorização indisponível"})
+		return false
+	}
+
+	span.SetAttributes(attribute.Bool("ext_authz.cached", cached))
+	if !decision.Allowed {
+		span.SetAttributes(
+			attribute.String("ext_authz.decision", "deny"),
+			attribute.Int("ext_authz.status", decision.Status),
+		)
+		if h.metrics != nil {
+			h.metrics.RequestError(path, c.Request.Method, "ext_authz_denied")
+		}
+		for name, values := range decision.Headers {
+			for _, value := range values {
+				c.Writer.Header().Add(name, value)
+			}
+		}
+		c.Status(decision.Status)
+		c.Writer.WriteString(decision.Body)
+		c.Abort()
+		return false
+	}
+
+	span.SetAttributes(attribute.String("ext_authz.decision", "allow"))
+	for _, name := range decision.RemoveHeaders {
+		c.Request.Header.Del(name)
+	}
+	for name, value := range decision.UpstreamHeaders {
+		c.Request.Header.Set(name, value)
+	}
+	return true
+}
+
+// externalAuthzRequest extrai os metadados enviados ao serviço de autorização
+func externalAuthzRequest(c *gin.Context, route *model.Route) *extauthz.Request {
+	headers := make(map[string]string, len(c.Request.Header))
+	for name, values := range c.Request.Header {
+		headers[strings.ToLower(name)] = strings.Join(values, ",")
+	}
+
+	scheme := "http"
+	if isSecureRequest(c.Request) {
+		scheme = "https"
+	}
+
+	return &extauthz.Request{
+		Route:    route.Path,
+		Method:   c.Request.Method,
+		Scheme:   scheme,
+		Host:     c.Request.Host,
+		Path:     c.Request.URL.RequestURI(),
+		Query:    c.Request.URL.RawQuery,
+		Protocol: c.Request.Proto,
+		ClientIP: clientIP(c),
+		Headers:  headers,
+	}
+}
@@ -0,0 +1,298 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/pkg/graphql"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxGraphQLBodyBytes limita o corpo das requisições GraphQL lido pelo gateway
+const maxGraphQLBodyBytes = 1 << 20
+
+// maxOperationLabel limita o nome da operação usado nas métricas
+const maxOperationLabel = 64
+
+// graphQLRequest é o corpo de uma requisição GraphQL sobre HTTP
+type graphQLRequest struct {
+	Query         string                 `json:"query,omitempty"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+	Extensions    map[string]interface{} `json:"extensions,omitempty"`
+}
+
+// graphQLOperation identifica uma operação aceita, para as métricas de duração
+type graphQLOperation struct {
+	name string
+	kind string
+}
+
+// graphQLRejection é a recusa de uma operação, com o código do erro GraphQL
+type graphQLRejection struct {
+	status  int
+	code    string
+	message string
+}
+
+func (r *graphQLRejection) Error() string {
+	return r.message
+}
+
+// inspectGraphQL analisa as operações das rotas GraphQL, aplicando os limites
+// de profundidade, custo e apelidos e a lista de operações persistidas. As
+// operações enviadas apenas pelo hash persistido têm o documento incluído na
+// requisição encaminhada. Retorna as operações aceitas e false quando a
+// requisição foi recusada.
+func (h *Handler) inspectGraphQL(c *gin.Context, route *model.Route, span trace.Span) ([]graphQLOperation, bool) {
+	policy := route.GraphQL
+	if policy == nil {
+		return nil, true
+	}
+
+	requests, batch, err := h.readGraphQLRequests(c)
+	if err != nil {
+		h.rejectGraphQL(c, route, graphQLOperation{name: "unknown"}, err)
+		return nil, false
+	}
+
+	rewrite := false
+	operations := make([]graphQLOperation, 0, len(requests))
+	for i := range requests {
+		request := &requests[i]
+		persisted, err := resolvePersisted(policy, request)
+		if err != nil {
+			h.rejectGraphQL(c, route, graphQLOperation{name: operationLabel(request.OperationName)}, err)
+			return nil, false
+		}
+		rewrite = rewrite || persisted
+
+		operation, analysis, err := analyzeGraphQL(policy, request)
+		if err != nil {
+			h.rejectGraphQL(c, route, operation, err)
+			return nil, false
+		}
+		if h.metrics != nil {
+			h.metrics.GraphQLOperation(route.Path, operation.name, operation.kind, "accepted", analysis.Complexity)
+		}
+		if !batch {
+			span.SetAttributes(
+				attribute.String("graphql.operation", operation.name),
+				attribute.String("graphql.type", operation.kind),
+				attribute.Int("graphql.depth", analysis.Depth),
+				attribute.Int("graphql.complexity", analysis.Complexity),
+			)
+		}
+		operations = append(operations, operation)
+	}
+	if batch {
+		span.SetAttributes(attribute.Int("graphql.batch_size", len(requests)))
+	}
+
+	if rewrite && c.Request.Method == http.MethodGet {
+		query := c.Request.URL.Query()
+		query.Set("query", requests[0].Query)
+		c.Request.URL.RawQuery = query.Encode()
+	} else if rewrite {
+		var body []byte
+		if batch {
+			body, err = json.Marshal(requests)
+		} else {
+			body, err = json.Marshal(requests[0])
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Falha ao montar a requisição GraphQL"})
+			return nil, false
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		c.Request.ContentLength = int64(len(body))
+		c.Request.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	}
+	return operations, true
+}
+
+// readGraphQLRequests extrai as operações da query string (GET) ou do corpo
+// (JSON, lote JSON ou application/graphql), restaurando o corpo para o
+// backend. Indica se a requisição é um lote.
+func (h *Handler) readGraphQLRequests(c *gin.Context) ([]graphQLRequest, bool, error) {
+	if c.Request.Method == http.MethodGet {
+		query := c.Request.URL.Query()
+		request := graphQLRequest{Query: query.Get("query"), OperationName: query.Get("operationName")}
+		for name, target := range map[string]*map[string]interface{}{"variables": &request.Variables, "extensions": &request.Extensions} {
+			if raw := query.Get(name); raw != "" {
+				if err := json.Unmarshal([]byte(raw), target); err != nil {
+					return nil, false, badGraphQLRequest("Parâmetro " + name + " não é JSON válido")
+				}
+			}
+		}
+		return []graphQLRequest{request}, false, nil
+	}
+
+	if c.Request.Body == nil || c.Request.Body == http.NoBody {
+		return nil, false, badGraphQLRequest("Corpo da requisição GraphQL vazio")
+	}
+	if c.Request.Header.Get("Content-Encoding") != "" {
+		return nil, false, &graphQLRejection{status: http.StatusUnsupportedMediaType, code: "UNSUPPORTED_MEDIA_TYPE",
+			message: "Corpo da requisição GraphQL deve ser enviado sem compressão"}
+	}
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxGraphQLBodyBytes+1))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return nil, false, &graphQLRejection{status: http.StatusRequestEntityTooLarge, code: "BODY_TOO_LARGE",
+				message: "Corpo da requisição excede o limite da rota"}
+		}
+		return nil, false, badGraphQLRequest("Falha ao ler o corpo da requisição")
+	}
+	if len(body) > maxGraphQLBodyBytes {
+		return nil, false, &graphQLRejection{status: http.StatusRequestEntityTooLarge, code: "BODY_TOO_LARGE",
+			message: "Corpo da requisição GraphQL excede o limite do gateway"}
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	if mediaType, _, _ := mime.ParseMediaType(c.ContentType()); mediaType == "application/graphql" {
+		return []graphQLRequest{{Query: string(body), OperationName: c.Query("operationName")}}, false, nil
+	}
+
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var requests []graphQLRequest
+		if err := json.Unmarshal(trimmed, &requests); err != nil || len(requests) == 0 {
+			return nil, false, badGraphQLRequest("Lote de operações GraphQL inválido")
+		}
+		return requests, true, nil
+	}
+	var request graphQLRequest
+	if err := json.Unmarshal(trimmed, &request); err != nil {
+		return nil, false, badGraphQLRequest("Corpo da requisição GraphQL não é JSON válido")
+	}
+	return []graphQLRequest{request}, false, nil
+}
+
+// resolvePersisted aplica a lista de operações persistidas. Requisições com
+// apenas extensions.persistedQuery.sha256Hash recebem o documento da lista;
+// retorna true nesse caso, quando o corpo precisa ser remontado.
+func resolvePersisted(policy *model.GraphQLPolicy, request *graphQLRequest) (bool, error) {
+	hash := persistedQueryHash(request)
+	if request.Query == "" && hash != "" {
+		document, ok := policy.PersistedOperations[hash]
+		if !ok {
+			return false, &graphQLRejection{status: http.StatusBadRequest, code: "PERSISTED_QUERY_NOT_FOUND",
+				message: "PersistedQueryNotFound"}
+		}
+		request.Query = document
+		return true, nil
+	}
+	if request.Query == "" {
+		return false, badGraphQLRequest("Requisição GraphQL sem query")
+	}
+	if hash != "" && hash != model.PersistedOperationHash(request.Query) {
+		return false, badGraphQLRequest("O hash da operação persistida não corresponde à query")
+	}
+	if len(policy.PersistedOperations) > 0 {
+		if _, ok := policy.PersistedOperations[model.PersistedOperationHash(request.Query)]; !ok {
+			return false, &graphQLRejection{status: http.StatusForbidden, code: "OPERATION_NOT_ALLOWED",
+				message: "Operação GraphQL não permitida nesta rota"}
+		}
+	}
+	return false, nil
+}
+
+// persistedQueryHash retorna o hash enviado no formato das persisted queries do Apollo
+func persistedQueryHash(request *graphQLRequest) string {
+	persisted, _ := request.Extensions["persistedQuery"].(map[string]interface{})
+	hash, _ := persisted["sha256Hash"].(string)
+	return hash
+}
+
+// analyzeGraphQL analisa a operação selecionada e aplica os limites da rota
+func analyzeGraphQL(policy *model.GraphQLPolicy, request *graphQLRequest) (graphQLOperation, *graphql.Analysis, error) {
+	operation := graphQLOperation{name: operationLabel(request.OperationName), kind: "unknown"}
+	doc, err := graphql.Parse(request.Query)
+	if err != nil {
+		return operation, nil, badGraphQLRequest("Query GraphQL inválida: " + err.Error())
+	}
+	op, err := doc.Operation(request.OperationName)
+	if err != nil {
+		return operation, nil, badGraphQLRequest(err.Error())
+	}
+	operation = graphQLOperation{name: operationLabel(op.Name), kind: op.Type}
+
+	analysis, err := doc.Analyze(op, request.Variables)
+	if err != nil {
+		return operation, nil, badGraphQLRequest(err.Error())
+	}
+	switch {
+	case policy.DisableIntrospection && analysis.Introspection:
+		return operation, analysis, &graphQLRejection{status: http.StatusForbidden, code: "INTROSPECTION_DISABLED",
+			message: "Introspecção do schema desabilitada nesta rota"}
+	case policy.MaxDepth > 0 && analysis.Depth > policy.MaxDepth:
+		return operation, analysis, &graphQLRejection{status: http.StatusBadRequest, code: "MAX_DEPTH_EXCEEDED",
+			message: "Operação excede a profundidade máxima de " + strconv.Itoa(policy.MaxDepth)}
+	case policy.MaxComplexity > 0 && analysis.Complexity > policy.MaxComplexity:
+		return operation, analysis, &graphQLRejection{status: http.StatusBadRequest, code: "MAX_COMPLEXITY_EXCEEDED",
+			message: "Operação excede o custo máximo de " + strconv.Itoa(policy.MaxComplexity)}
+	case policy.MaxAliases > 0 && analysis.Aliases > policy.MaxAliases:
+		return operation, analysis, &graphQLRejection{status: http.StatusBadRequest, code: "MAX_ALIASES_EXCEEDED",
+			message: "Operação excede o máximo de " + strconv.Itoa(policy.MaxAliases) + " apelidos"}
+	}
+	return operation, analysis, nil
+}
+
+// rejectGraphQL responde a recusa no formato de erros do GraphQL, mantendo o
+// campo error das demais respostas do gateway
+func (h *Handler) rejectGraphQL(c *gin.Context, route *model.Route, operation graphQLOperation, err error) {
+	var rejection *graphQLRejection
+	if !errors.As(err, &rejection) {
+		rejection = &graphQLRejection{status: http.StatusBadRequest, code: "BAD_REQUEST", message: err.Error()}
+	}
+	if h.metrics != nil {
+		kind := operation.kind
+		if kind == "" {
+			kind = "unknown"
+		}
+		h.metrics.GraphQLOperation(route.Path, operation.name, kind, rejection.code, 0)
+		h.metrics.RequestError(c.Request.URL.Path, c.Request.Method, "graphql_rejected")
+	}
+	c.JSON(rejection.status, gin.H{
+		"error": rejection.message,
+		"errors": []gin.H{{
+			"message":    rejection.message,
+			"extensions": gin.H{"code": rejection.code},
+		}},
+	})
+}
+
+// recordGraphQL registra a duração das operações encaminhadas
+func (h *Handler) recordGraphQL(route *model.Route, operations []graphQLOperation, duration time.Duration) {
+	if h.metrics == nil {
+		return
+	}
+	for _, operation := range operations {
+		h.metrics.GraphQLCompleted(route.Path, operation.name, duration)
+	}
+}
+
+// operationLabel retorna o nome da operação usado nas métricas
+func operationLabel(name string) string {
+	switch {
+	case name == "":
+		return "anonymous"
+	case len(name) > maxOperationLabel:
+		return name[:maxOperationLabel]
+	}
+	return name
+}
+
+func badGraphQLRequest(message string) error {
+	return &graphQLRejection{status: http.StatusBadRequest, code: "BAD_REQUEST", message: message}
+}
@@ -0,0 +1,79 @@
+package http
+
+import (
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/pkg/reqtemplate"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// applyHeaderTransforms aplica as regras de cabeçalhos da rota: as da
+// requisição imediatamente e as da resposta no momento em que o cabeçalho da
+// resposta é enviado, seja ela do backend, do cache ou de erro do proxy
+func (h *Handler) applyHeaderTransforms(c *gin.Context, route *model.Route, data *reqtemplate.Data) {
+	transforms := route.HeaderTransforms
+	if transforms == nil {
+		return
+	}
+
+	if transforms.Request != nil {
+		h.logHeaderErrors(route, "request", transforms.Request.Apply(c.Request.Header, data))
+	}
+
+	if transforms.Response != nil {
+		c.Writer = &headerRewriter{
+			ResponseWriter: c.Writer,
+			rewrite: func() {
+				h.logHeaderErrors(route, "response", transforms.Response.Apply(c.Writer.Header(), data))
+			},
+		}
+	}
+}
+
+func (h *Handler) logHeaderErrors(route *model.Route, direction string, errs []error) {
+	for _, err := range errs {
+		h.logger.Debug("Falha ao avaliar regra de cabeçalho da rota",
+			zap.String("path", route.Path),
+			zap.String("direction", direction),
+			zap.Error(err))
+	}
+}
+
+// headerRewriter altera os cabeçalhos da resposta uma única vez, antes do envio
+type headerRewriter struct {
+	gin.ResponseWriter
+	rewrite func()
+	done    bool
+}
+
+func (w *headerRewriter) apply() {
+	if !w.done && !w.ResponseWriter.Written() {
+		w.done = true
+		w.rewrite()
+	}
+}
+
+func (w *headerRewriter) WriteHeader(code int) {
+	w.apply()
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *headerRewriter) WriteHeaderNow() {
+	w.apply()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *headerRewriter) Write(data []byte) (int, error) {
+	w.apply()
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *headerRewriter) WriteString(s string) (int, error) {
+	w.apply()
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *headerRewriter) Flush() {
+	w.apply()
+	w.ResponseWriter.Flush()
+}
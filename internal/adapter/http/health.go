@@ -2,11 +2,16 @@ package http
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	"github.com/diillson/api-gateway-go/internal/app/route"
+	"github.com/diillson/api-gateway-go/internal/domain/model"
 	"net/http"
 	"os"
 	"runtime"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -20,13 +25,24 @@ type HealthChecker struct {
 	cache        CacheChecker
 	logger       *zap.Logger
 	dependencies []Dependency
+	shardPools   map[string]PoolStatsProvider
+	timeout      atomic.Int64 // time.Duration; alterado nas recargas da configuração
+	requireWarm  bool         // o roteador só é considerado pronto após a carga inicial das rotas
 }
 
+// defaultReadinessTimeout limita a duração das verificações do readiness
+const defaultReadinessTimeout = 5 * time.Second
+
 // DatabaseChecker define a interface para verificar o banco de dados
 type DatabaseChecker interface {
 	Ping(ctx context.Context) error
 }
 
+// PoolStatsProvider é implementado por bancos que expõem estatísticas do pool de conexões
+type PoolStatsProvider interface {
+	Stats() sql.DBStats
+}
+
 // CacheChecker define a interface para verificar o cache
 type CacheChecker interface {
 	Ping(ctx context.Context) error
@@ -65,90 +81,104 @@ func NewHealthChecker(router *route.Service, db DatabaseChecker, cache CacheChec
 	return hc
 }
 
-// LivenessCheck verifica se o aplicativo está vivo (execução básica)
-func (h *HealthChecker) LivenessCheck(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
-		"status": "UP",
-		"time":   time.Now(),
-	})
+// AddDependency adiciona uma dependência verificada no readiness e no health detalhado
+func (h *HealthChecker) AddDependency(dep Dependency) {
+	h.dependencies = append(h.dependencies, dep)
 }
 
-// ReadinessCheck verifica se o aplicativo está pronto para receber tráfego
-func (h *HealthChecker) ReadinessCheck(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
-	defer cancel()
+// AddDatabaseShard registra um shard de banco de dados para reportar sua saúde
+// e as estatísticas do pool de conexões
+func (h *HealthChecker) AddDatabaseShard(name string, db DatabaseChecker) {
+	h.AddDependency(Dependency{
+		Name:     "database:" + name,
+		Check:    db.Ping,
+		Critical: false, // a falha de um shard afeta apenas os tenants alocados nele
+	})
 
-	status := http.StatusOK
-	result := gin.H{
-		"status": "UP",
-		"time":   time.Now(),
-		"checks": make(map[string]interface{}),
+	if provider, ok := db.(PoolStatsProvider); ok {
+		if h.shardPools == nil {
+			h.shardPools = make(map[string]PoolStatsProvider)
+		}
+		h.shardPools[name] = provider
 	}
+}
 
-	checks := result["checks"].(map[string]interface{})
-	var wg sync.WaitGroup
-
-	// Verificar cada dependência em paralelo
-	for _, dep := range h.dependencies {
-		wg.Add(1)
-		go func(d Dependency) {
-			defer wg.Done()
+// SetTimeout define o prazo das verificações do readiness
+func (h *HealthChecker) SetTimeout(timeout time.Duration) {
+	h.timeout.Store(int64(timeout))
+}
 
-			start := time.Now()
-			err := d.Check(ctx)
-			duration := time.Since(start)
+func (h *HealthChecker) checkTimeout() time.Duration {
+	timeout := time.Duration(h.timeout.Load())
+	if timeout <= 0 {
+		return defaultReadinessTimeout
+	}
+	return timeout
+}
 
-			depStatus := "UP"
+// AddCriticalUpstream inclui no readiness o estado das verificações ativas
+// dos backends da rota: a instância deixa de estar pronta quando nenhum alvo
+// da rota está disponível
+func (h *HealthChecker) AddCriticalUpstream(path string) {
+	h.AddDependency(Dependency{
+		Name: "upstream:" + path,
+		Check: func(ctx context.Context) error {
+			route, err := h.router.GetRouteByPath(ctx, path)
 			if err != nil {
-				depStatus = "DOWN"
-				h.logger.Error("health check falhou",
-					zap.String("dependency", d.Name),
-					zap.Error(err))
-
-				if d.Critical {
-					status = http.StatusServiceUnavailable
-				}
+				return fmt.Errorf("rota não encontrada: %w", err)
 			}
-
-			checks[d.Name] = gin.H{
-				"status":   depStatus,
-				"time":     duration.String(),
-				"critical": d.Critical,
+			if len(h.router.AvailableTargets(route)) == 0 {
+				return errors.New("nenhum backend disponível")
 			}
-		}(dep)
-	}
+			return nil
+		},
+		Critical: true,
+	})
+}
 
-	// Adicionar check do roteador
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
+// RequireWarmRoutes condiciona a verificação do roteador à carga inicial das
+// rotas: a instância só fica pronta depois que elas estão no cache e no índice
+func (h *HealthChecker) RequireWarmRoutes() {
+	h.requireWarm = true
+}
 
-		start := time.Now()
-		routes, err := h.router.GetRoutes(ctx)
-		duration := time.Since(start)
-
-		routeStatus := "UP"
-		routeDetails := gin.H{
-			"status":   routeStatus,
-			"time":     duration.String(),
-			"critical": true,
+// SetDatabaseCritical define se a falha do banco de dados torna a instância não
+// pronta. Com o snapshot das rotas, o tráfego pode ser atendido sem o banco.
+func (h *HealthChecker) SetDatabaseCritical(critical bool) {
+	for i := range h.dependencies {
+		if h.dependencies[i].Name == "database" {
+			h.dependencies[i].Critical = critical
 		}
+	}
+}
 
-		if err != nil {
-			routeStatus = "DOWN"
-			routeDetails["status"] = routeStatus
-			status = http.StatusServiceUnavailable
-			h.logger.Error("health check do roteador falhou", zap.Error(err))
-		} else {
-			routeDetails["count"] = len(routes)
-		}
+// LivenessCheck verifica se o aplicativo está vivo (execução básica)
+func (h *HealthChecker) LivenessCheck(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status": "UP",
+		"time":   time.Now(),
+	})
+}
 
-		checks["router"] = routeDetails
-	}()
+// ReadinessCheck verifica se o aplicativo está pronto para receber tráfego
+func (h *HealthChecker) ReadinessCheck(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), h.checkTimeout())
+	defer cancel()
 
-	wg.Wait()
+	status := http.StatusOK
+	checks := make(map[string]interface{}, len(h.dependencies)+1)
+	for _, result := range h.runChecks(ctx, true) {
+		if result.err != nil && result.critical {
+			status = http.StatusServiceUnavailable
+		}
+		checks[result.name] = result.toMap(false)
+	}
 
-	// Atualizar status geral
+	result := gin.H{
+		"status": "UP",
+		"time":   time.Now(),
+		"checks": checks,
+	}
 	if status != http.StatusOK {
 		result["status"] = "DOWN"
 	}
@@ -167,59 +197,146 @@ func (h *HealthChecker) DetailedHealth(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
+	status := http.StatusOK
+	checks := make(map[string]interface{}, len(h.dependencies)+1)
+	for _, result := range h.runChecks(ctx, false) {
+		if result.err != nil && result.critical {
+			status = http.StatusServiceUnavailable
+		}
+		checks[result.name] = result.toMap(true)
+	}
+
 	details := gin.H{
 		"status":      "UP",
 		"time":        time.Now(),
 		"version":     getVersion(),
 		"environment": getEnvironment(),
-		"checks":      make(map[string]interface{}),
+		"checks":      checks,
 		"system":      getSystemInfo(),
 	}
 
-	checks := details["checks"].(map[string]interface{})
+	if provider, ok := h.db.(PoolStatsProvider); ok {
+		details["database_pool"] = poolStatsToMap(provider.Stats())
+	}
 
-	// Verificações de saúde básicas (reutilizar código do ReadinessCheck)
-	status := http.StatusOK
+	if len(h.shardPools) > 0 {
+		shards := make(map[string]interface{}, len(h.shardPools))
+		for name, provider := range h.shardPools {
+			shards[name] = gin.H{
+				"health": checks["database:"+name],
+				"pool":   poolStatsToMap(provider.Stats()),
+			}
+		}
+		details["database_shards"] = shards
+	}
+
+	if status != http.StatusOK {
+		details["status"] = "DOWN"
+	}
+
+	c.JSON(status, details)
+}
+
+// checkResult é o resultado da verificação de uma dependência
+type checkResult struct {
+	name     string
+	critical bool
+	err      error
+	latency  time.Duration
+	count    int // rotas carregadas, apenas na verificação do roteador
+}
+
+// toMap converte o resultado para a resposta JSON; a mensagem de erro só é
+// exposta no health detalhado
+func (r checkResult) toMap(withError bool) gin.H {
+	status := "UP"
+	if r.err != nil {
+		status = "DOWN"
+	}
+	result := gin.H{
+		"status":     status,
+		"time":       r.latency.String(),
+		"latency_ms": float64(r.latency.Microseconds()) / 1000,
+		"critical":   r.critical,
+	}
+	if r.name == "router" && r.err == nil {
+		result["count"] = r.count
+	}
+	if withError {
+		var message interface{}
+		if r.err != nil {
+			message = r.err.Error()
+		}
+		result["error"] = message
+	}
+	return result
+}
+
+// runChecks verifica as dependências e o carregamento das rotas em paralelo
+func (h *HealthChecker) runChecks(ctx context.Context, logErrors bool) []checkResult {
+	results := make([]checkResult, len(h.dependencies)+1)
 	var wg sync.WaitGroup
 
-	for _, dep := range h.dependencies {
+	for i, dep := range h.dependencies {
 		wg.Add(1)
-		go func(d Dependency) {
+		go func(i int, d Dependency) {
 			defer wg.Done()
 
 			start := time.Now()
 			err := d.Check(ctx)
-			duration := time.Since(start)
+			results[i] = checkResult{name: d.Name, critical: d.Critical, err: err, latency: time.Since(start)}
 
-			depStatus := "UP"
-			if err != nil {
-				depStatus = "DOWN"
-				if d.Critical {
-					status = http.StatusServiceUnavailable
-				}
-			}
-
-			checks[d.Name] = gin.H{
-				"status":   depStatus,
-				"time":     duration.String(),
-				"critical": d.Critical,
-				"error": func() interface{} {
-					if err != nil {
-						return err.Error()
-					}
-					return nil
-				}(),
+			if err != nil && logErrors {
+				h.logger.Error("health check falhou",
+					zap.String("dependency", d.Name),
+					zap.Error(err))
 			}
-		}(dep)
+		}(i, dep)
 	}
 
+	// Adicionar check do roteador
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		start := time.Now()
+		var routes []*model.Route
+		var err error
+		if h.requireWarm && !h.router.Warmed() {
+			err = errors.New("carga inicial das rotas em andamento")
+		} else {
+			routes, err = h.router.GetRoutes(ctx)
+		}
+		results[len(results)-1] = checkResult{
+			name:     "router",
+			critical: true,
+			err:      err,
+			latency:  time.Since(start),
+			count:    len(routes),
+		}
+
+		if err != nil && logErrors {
+			h.logger.Error("health check do roteador falhou", zap.Error(err))
+		}
+	}()
+
 	wg.Wait()
+	return results
+}
 
-	if status != http.StatusOK {
-		details["status"] = "DOWN"
+// poolStatsToMap converte as estatísticas do pool de conexões para a resposta JSON
+func poolStatsToMap(stats sql.DBStats) gin.H {
+	return gin.H{
+		"max_open_connections": stats.MaxOpenConnections,
+		"open_connections":     stats.OpenConnections,
+		"in_use":               stats.InUse,
+		"idle":                 stats.Idle,
+		"wait_count":           stats.WaitCount,
+		"wait_duration":        stats.WaitDuration.String(),
+		"max_idle_closed":      stats.MaxIdleClosed,
+		"max_idle_time_closed": stats.MaxIdleTimeClosed,
+		"max_lifetime_closed":  stats.MaxLifetimeClosed,
 	}
-
-	c.JSON(status, details)
 }
 
 // getVersion retorna a versão do aplicativo
@@ -0,0 +1,129 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/internal/infra/webhook"
+	"github.com/diillson/api-gateway-go/pkg/requestid"
+	"github.com/diillson/api-gateway-go/pkg/tenant"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// SetHooks configura o cliente dos webhooks pré-requisição e pós-resposta das rotas
+func (h *Handler) SetHooks(client *webhook.HookClient) {
+	h.hooks = client
+}
+
+// runPreHooks consulta em ordem os webhooks pré-requisição da rota. Recusas
+// são devolvidas ao cliente; permissões podem alterar os cabeçalhos enviados
+// ao backend. Retorna false quando a requisição foi respondida.
+func (h *Handler) runPreHooks(c *gin.Context, route *model.Route, span trace.Span) bool {
+	if route.Hooks == nil || len(route.Hooks.Pre) == 0 {
+		return true
+	}
+	path := c.Request.URL.Path
+
+	if h.hooks == nil {
+		h.logger.Error("Rota possui webhooks, mas o cliente não está configurado",
+			zap.String("path", path))
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Webhooks da rota indisponíveis"})
+		return false
+	}
+
+	for i := range route.Hooks.Pre {
+		hook := &route.Hooks.Pre[i]
+		result, err := h.hooks.CallPre(c.Request.Context(), hook, hookRequest(c, route, hook.Headers))
+		if err != nil {
+			span.SetAttributes(attribute.String("hooks.pre.error", err.Error()))
+			if hook.FailOpen {
+				h.logger.Warn("Webhook pré-requisição indisponível, seguindo com failOpen",
+					zap.String("path", path),
+					zap.String("hook", hook.Name),
+					zap.Error(err))
+				continue
+			}
+			h.logger.Error("Falha no webhook pré-requisição",
+				zap.String("path", path),
+				zap.String("hook", hook.Name),
+				zap.Error(err))
+			if h.metrics != nil {
+				h.metrics.RequestError(path, c.Request.Method, "pre_hook_error")
+			}
+			c.JSON(http.StatusBadGateway, gin.H{"error": "Falha no webhook pré-requisição da rota"})
+			return false
+		}
+
+		if !result.Allowed {
+			span.SetAttributes(attribute.String("hooks.pre.rejected_by", hook.Name))
+			if h.metrics != nil {
+				h.metrics.RequestError(path, c.Request.Method, "pre_hook_rejected")
+			}
+			for name, values := range result.Header {
+				c.Writer.Header()[name] = values
+			}
+			c.Status(result.Status)
+			if _, err := c.Writer.Write(result.Body); err != nil {
+				h.logger.Debug("Falha ao enviar a recusa do webhook", zap.Error(err))
+			}
+			return false
+		}
+
+		for _, name := range result.RemoveHeaders {
+			c.Request.Header.Del(name)
+		}
+		for name, value := range result.SetHeaders {
+			c.Request.Header.Set(name, value)
+		}
+	}
+	return true
+}
+
+// notifyPostHooks enfileira a notificação dos webhooks pós-resposta da rota
+func (h *Handler) notifyPostHooks(c *gin.Context, route *model.Route, start time.Time) {
+	if route.Hooks == nil || len(route.Hooks.Post) == 0 || h.hooks == nil {
+		return
+	}
+	// Os cabeçalhos incluídos são a união dos pedidos pelos webhooks
+	var names []string
+	for _, hook := range route.Hooks.Post {
+		names = append(names, hook.Headers...)
+	}
+	h.hooks.NotifyPost(route.Hooks.Post, &webhook.HookResponse{
+		HookRequest:   *hookRequest(c, route, names),
+		Status:        c.Writer.Status(),
+		DurationMs:    float64(time.Since(start).Microseconds()) / 1000,
+		ResponseBytes: max(c.Writer.Size(), 0),
+		Timestamp:     time.Now().UTC(),
+	})
+}
+
+// hookRequest extrai os metadados da requisição enviados aos webhooks, com
+// apenas os cabeçalhos informados
+func hookRequest(c *gin.Context, route *model.Route, headerNames []string) *webhook.HookRequest {
+	var headers map[string]string
+	for _, name := range headerNames {
+		if value := c.Request.Header.Get(name); value != "" {
+			if headers == nil {
+				headers = make(map[string]string, len(headerNames))
+			}
+			headers[http.CanonicalHeaderKey(name)] = value
+		}
+	}
+
+	return &webhook.HookRequest{
+		Route:     route.Path,
+		Method:    c.Request.Method,
+		Path:      c.Request.URL.Path,
+		Query:     c.Request.URL.RawQuery,
+		Headers:   headers,
+		ClientIP:  clientIP(c),
+		Consumer:  consumerName(c),
+		Tenant:    tenant.FromContext(c.Request.Context()),
+		RequestID: requestid.FromContext(c.Request.Context()),
+	}
+}
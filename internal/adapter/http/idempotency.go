@@ -0,0 +1,239 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/diillson/api-gateway-go/internal/app/idempotency"
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/pkg/tenant"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// maxIdempotencyKeyLength limita o tamanho da chave enviada pelo cliente
+const maxIdempotencyKeyLength = 255
+
+// IdempotentReplayedHeader marca as respostas repetidas a partir do armazenamento
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+// beginIdempotency trata o cabeçalho Idempotency-Key da requisição. Para uma
+// chave já concluída, repete a resposta armazenada; para uma chave nova, passa
+// a registrar a resposta, que é armazenada pela função retornada após o envio.
+// Retorna false quando a requisição já foi respondida. Falhas no Redis não
+// bloqueiam a requisição.
+func (h *Handler) beginIdempotency(c *gin.Context, route *model.Route, span trace.Span) (bool, func()) {
+	noop := func() {}
+	policy := route.Idempotency
+	store := h.idempotency
+	if policy == nil || store == nil || !policy.AppliesTo(c.Request.Method) {
+		return true, noop
+	}
+	path := c.Request.URL.Path
+
+	key := c.GetHeader(store.Header())
+	if key == "" {
+		if !policy.Required {
+			return true, noop
+		}
+		if h.metrics != nil {
+			h.metrics.RequestError(path, c.Request.Method, "idempotency_key_missing")
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Cabeçalho " + store.Header() + " obrigatório"})
+		return false, noop
+	}
+	if len(key) > maxIdempotencyKeyLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Cabeçalho " + store.Header() + " excede o tamanho máximo"})
+		return false, noop
+	}
+
+	body, ok := h.readIdempotentBody(c, store.MaxBodyBytes())
+	if !ok {
+		return false, noop
+	}
+	fingerprint := requestFingerprint(c.Request, body)
+	scope := tenant.FromContext(c.Request.Context()) + "\x00" + route.Path
+	if consumer, ok := quotaConsumer(c); ok {
+		scope += "\x00" + consumer.ID
+	}
+
+	ctx := c.Request.Context()
+	stored, err := store.Begin(ctx, scope, key, fingerprint)
+	switch {
+	case errors.Is(err, idempotency.ErrInProgress):
+		span.SetAttributes(attribute.String("idempotency.result", "in_progress"))
+		if h.metrics != nil {
+			h.metrics.RequestError(path, c.Request.Method, "idempotency_in_progress")
+		}
+		c.Header("Retry-After", "1")
+		c.JSON(http.StatusConflict, gin.H{"error": "Requisição com a mesma chave de idempotência em andamento"})
+		return false, noop
+	case errors.Is(err, idempotency.ErrMismatch):
+		span.SetAttributes(attribute.String("idempotency.result", "mismatch"))
+		if h.metrics != nil {
+			h.metrics.RequestError(path, c.Request.Method, "idempotency_mismatch")
+		}
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Chave de idempotência reutilizada com outra requisição"})
+		return false, noop
+	case err != nil:
+		h.logger.Warn("Falha ao consultar chave de idempotência, seguindo sem repetição",
+			zap.String("path", path),
+			zap.Error(err))
+		return true, noop
+	case stored != nil:
+		span.SetAttributes(attribute.String("idempotency.result", "replayed"))
+		h.replayResponse(c, stored)
+		return false, noop
+	}
+
+	span.SetAttributes(attribute.String("idempotency.result", "stored"))
+	recorder := &responseRecorder{ResponseWriter: c.Writer, limit: store.MaxBodyBytes()}
+	c.Writer = recorder
+	return true, func() {
+		c.Writer = recorder.ResponseWriter
+		// A resposta é armazenada mesmo que o cliente tenha desconectado
+		ctx := context.WithoutCancel(ctx)
+		if !recorder.wroteHeader || recorder.overflow || recorder.status >= http.StatusInternalServerError {
+			// Sem resposta completa, o cliente pode tentar de novo com a mesma chave
+			if err := store.Release(ctx, scope, key); err != nil {
+				h.logger.Warn("Falha ao liberar chave de idempotência", zap.String("path", path), zap.Error(err))
+			}
+			return
+		}
+		response := &idempotency.Response{
+			Status: recorder.status,
+			Header: recorder.header,
+			Body:   recorder.body.Bytes(),
+		}
+		if err := store.Complete(ctx, scope, key, fingerprint, response, policy.TTLDuration()); err != nil {
+			h.logger.Warn("Falha ao armazenar resposta idempotente", zap.String("path", path), zap.Error(err))
+		}
+	}
+}
+
+// readIdempotentBody lê o corpo da requisição para compor a identificação da
+// requisição e o devolve para o envio ao backend
+func (h *Handler) readIdempotentBody(c *gin.Context, limit int64) ([]byte, bool) {
+	if c.Request.Body == nil || c.Request.Body == http.NoBody {
+		return nil, true
+	}
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, limit+1))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Corpo da requisição excede o limite da rota"})
+			return nil, false
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Falha ao ler o corpo da requisição"})
+		return nil, false
+	}
+	if int64(len(body)) > limit {
+		c.Header("Connection", "close")
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"error":     "Corpo da requisição excede o limite das requisições idempotentes",
+			"max_bytes": limit,
+		})
+		return nil, false
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	return body, true
+}
+
+// requestFingerprint resume o método, o caminho, a query e o corpo da
+// requisição, detectando chaves reutilizadas com outra requisição
+func requestFingerprint(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	io.WriteString(hash, r.Method+" "+r.URL.Path+"?"+r.URL.RawQuery+"\n")
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// replayResponse envia a resposta armazenada para a chave
+func (h *Handler) replayResponse(c *gin.Context, stored *idempotency.Response) {
+	header := c.Writer.Header()
+	for name, values := range stored.Header {
+		header[name] = append([]string(nil), values...)
+	}
+	header.Set(IdempotentReplayedHeader, "true")
+	c.Status(stored.Status)
+	if c.Request.Method == http.MethodHead || len(stored.Body) == 0 {
+		c.Writer.WriteHeaderNow()
+		return
+	}
+	if _, err := c.Writer.Write(stored.Body); err != nil {
+		h.logger.Debug("Falha ao enviar resposta idempotente", zap.Error(err))
+	}
+}
+
+// responseRecorder registra o status, os cabeçalhos e o corpo da resposta
+// enquanto ela é enviada ao cliente. Corpos acima do limite não são guardados,
+// ou são guardados até o limite com truncate.
+type responseRecorder struct {
+	gin.ResponseWriter
+	limit       int64
+	truncate    bool
+	status      int
+	header      http.Header
+	body        bytes.Buffer
+	wroteHeader bool
+	overflow    bool
+}
+
+func (w *responseRecorder) record(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	w.status = code
+	w.header = w.ResponseWriter.Header().Clone()
+	for _, name := range []string{"Date", "Connection", "Transfer-Encoding", "Keep-Alive"} {
+		w.header.Del(name)
+	}
+}
+
+func (w *responseRecorder) capture(n int, data []byte) {
+	if w.overflow {
+		return
+	}
+	if int64(w.body.Len()+n) > w.limit {
+		w.overflow = true
+		if w.truncate {
+			w.body.Write(data[:w.limit-int64(w.body.Len())])
+			return
+		}
+		w.body.Reset()
+		return
+	}
+	w.body.Write(data[:n])
+}
+
+func (w *responseRecorder) WriteHeader(code int) {
+	w.record(code)
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *responseRecorder) WriteHeaderNow() {
+	w.record(w.ResponseWriter.Status())
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.record(w.ResponseWriter.Status())
+	n, err := w.ResponseWriter.Write(data)
+	w.capture(n, data)
+	return n, err
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.record(w.ResponseWriter.Status())
+	n, err := w.ResponseWriter.WriteString(s)
+	w.capture(n, []byte(s))
+	return n, err
+}
@@ -0,0 +1,274 @@
+package http
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/app/idempotency"
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/pkg/config"
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// fakeRedis atende o subconjunto do protocolo RESP usado pelo armazenamento de
+// idempotência: SET (com NX, EX e PX), GET e DEL
+type fakeRedis struct {
+	ln    net.Listener
+	mu    sync.Mutex
+	items map[string]string
+}
+
+func newFakeRedis(tb testing.TB) *fakeRedis {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		tb.Fatalf("falha ao abrir o servidor redis de teste: %v", err)
+	}
+	f := &fakeRedis{ln: ln, items: make(map[string]string)}
+	go f.serve()
+	tb.Cleanup(func() { ln.Close() })
+	return f
+}
+
+func (f *fakeRedis) serve() {
+	for {
+		conn, err := f.ln.Accept()
+		if err != nil {
+			return
+		}
+		go f.handle(conn)
+	}
+}
+
+func (f *fakeRedis) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		fmt.Fprint(conn, f.exec(args))
+	}
+}
+
+// readCommand lê um comando RESP: um array de bulk strings
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, count)
+	for i := range args {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "$")))
+		if err != nil {
+			return nil, err
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		args[i] = string(data[:size])
+	}
+	return args, nil
+}
+
+func (f *fakeRedis) exec(args []string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch strings.ToLower(args[0]) {
+	case "set":
+		// A expiração é ignorada: os testes não dependem dela
+		for _, option := range args[3:] {
+			if strings.EqualFold(option, "nx") {
+				if _, exists := f.items[args[1]]; exists {
+					return "$-1\r\n"
+				}
+			}
+		}
+		f.items[args[1]] = args[2]
+		return "+OK\r\n"
+	case "get":
+		value, ok := f.items[args[1]]
+		if !ok {
+			return "$-1\r\n"
+		}
+		return fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+	case "del":
+		deleted := 0
+		for _, key := range args[1:] {
+			if _, ok := f.items[key]; ok {
+				delete(f.items, key)
+				deleted++
+			}
+		}
+		return fmt.Sprintf(":%d\r\n", deleted)
+	default:
+		return "-ERR comando não suportado\r\n"
+	}
+}
+
+// newIdempotencyHandler cria o handler com o armazenamento de idempotência no
+// redis de teste, aceitando corpos de até 1 KiB
+func newIdempotencyHandler(t *testing.T) *Handler {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	fake := newFakeRedis(t)
+	client := redis.NewClient(&redis.Options{Addr: fake.ln.Addr().String()})
+	t.Cleanup(func() { client.Close() })
+	store := idempotency.NewStore(client, config.IdempotencyConfig{
+		Enabled:      true,
+		Header:       "Idempotency-Key",
+		TTL:          time.Hour,
+		LockTimeout:  time.Minute,
+		MaxBodyBytes: 1024,
+	}, zap.NewNop())
+	return &Handler{logger: zap.NewNop(), idempotency: store}
+}
+
+// serveIdempotent trata a requisição como o handler das rotas: o backend só é
+// chamado se beginIdempotency deixar a requisição seguir, e a resposta é
+// armazenada ao final. Com finish false, a requisição fica em andamento.
+func serveIdempotent(h *Handler, route *model.Route, req *http.Request, backend func(*gin.Context), finish bool) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = req
+	proceed, finishIdempotency := h.beginIdempotency(c, route, trace.SpanFromContext(req.Context()))
+	if proceed {
+		backend(c)
+	}
+	if finish {
+		finishIdempotency()
+	}
+	return rec
+}
+
+func newIdempotentRequest(key, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/api/pagamentos", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+	return req
+}
+
+func TestIdempotencyReplay(t *testing.T) {
+	h := newIdempotencyHandler(t)
+	route := &model.Route{Path: "/api/pagamentos", Idempotency: &model.IdempotencyPolicy{}}
+
+	calls := 0
+	backend := func(c *gin.Context) {
+		calls++
+		c.Header("Location", "/api/pagamentos/"+strconv.Itoa(calls))
+		c.String(http.StatusCreated, "pagamento %d", calls)
+	}
+
+	first := serveIdempotent(h, route, newIdempotentRequest("chave-1", `{"valor": 10}`), backend, true)
+	if first.Code != http.StatusCreated || first.Header().Get(IdempotentReplayedHeader) != "" {
+		t.Fatalf("primeira requisição: status = %d, repetida = %q", first.Code, first.Header().Get(IdempotentReplayedHeader))
+	}
+
+	replay := serveIdempotent(h, route, newIdempotentRequest("chave-1", `{"valor": 10}`), backend, true)
+	if calls != 1 {
+		t.Fatalf("backend chamado %d vezes, esperada uma", calls)
+	}
+	if replay.Code != http.StatusCreated || replay.Body.String() != "pagamento 1" {
+		t.Fatalf("repetição: status = %d, corpo = %q; esperada a primeira resposta", replay.Code, replay.Body.String())
+	}
+	if replay.Header().Get(IdempotentReplayedHeader) != "true" || replay.Header().Get("Location") != "/api/pagamentos/1" {
+		t.Fatalf("repetição: cabeçalhos = %v", replay.Header())
+	}
+
+	// Outra chave chega ao backend
+	if rec := serveIdempotent(h, route, newIdempotentRequest("chave-2", `{"valor": 10}`), backend, true); rec.Body.String() != "pagamento 2" {
+		t.Fatalf("nova chave: corpo = %q, esperada uma nova resposta", rec.Body.String())
+	}
+}
+
+func TestIdempotencyMismatch(t *testing.T) {
+	h := newIdempotencyHandler(t)
+	route := &model.Route{Path: "/api/pagamentos", Idempotency: &model.IdempotencyPolicy{}}
+	backend := func(c *gin.Context) { c.String(http.StatusCreated, "ok") }
+
+	serveIdempotent(h, route, newIdempotentRequest("chave-1", `{"valor": 10}`), backend, true)
+
+	// A mesma chave com outro corpo não repete a resposta
+	rec := serveIdempotent(h, route, newIdempotentRequest("chave-1", `{"valor": 99}`), backend, true)
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("corpo diferente: status = %d, esperado 422", rec.Code)
+	}
+	// Nem com outra query
+	req := newIdempotentRequest("chave-1", `{"valor": 10}`)
+	req.URL.RawQuery = "moeda=usd"
+	if rec := serveIdempotent(h, route, req, backend, true); rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("query diferente: status = %d, esperado 422", rec.Code)
+	}
+}
+
+func TestIdempotencyInProgress(t *testing.T) {
+	h := newIdempotencyHandler(t)
+	route := &model.Route{Path: "/api/pagamentos", Idempotency: &model.IdempotencyPolicy{}}
+	backend := func(c *gin.Context) { c.String(http.StatusCreated, "ok") }
+
+	// A primeira requisição reserva a chave e ainda não terminou
+	serveIdempotent(h, route, newIdempotentRequest("chave-1", `{"valor": 10}`), backend, false)
+
+	rec := serveIdempotent(h, route, newIdempotentRequest("chave-1", `{"valor": 10}`), backend, true)
+	if rec.Code != http.StatusConflict || rec.Header().Get("Retry-After") == "" {
+		t.Fatalf("chave em andamento: status = %d, Retry-After = %q; esperado 409", rec.Code, rec.Header().Get("Retry-After"))
+	}
+}
+
+func TestIdempotencyReleasesOnServerError(t *testing.T) {
+	h := newIdempotencyHandler(t)
+	route := &model.Route{Path: "/api/pagamentos", Idempotency: &model.IdempotencyPolicy{}}
+
+	failing := func(c *gin.Context) { c.String(http.StatusBadGateway, "falha") }
+	if rec := serveIdempotent(h, route, newIdempotentRequest("chave-1", `{"valor": 10}`), failing, true); rec.Code != http.StatusBadGateway {
+		t.Fatalf("status = %d, esperado 502", rec.Code)
+	}
+
+	// A falha do backend não é repetida: o cliente pode tentar de novo
+	succeeding := func(c *gin.Context) { c.String(http.StatusCreated, "ok") }
+	rec := serveIdempotent(h, route, newIdempotentRequest("chave-1", `{"valor": 10}`), succeeding, true)
+	if rec.Code != http.StatusCreated || rec.Header().Get(IdempotentReplayedHeader) != "" {
+		t.Fatalf("nova tentativa: status = %d, repetida = %q; esperada a resposta do backend", rec.Code, rec.Header().Get(IdempotentReplayedHeader))
+	}
+}
+
+func TestIdempotencyRequiredKey(t *testing.T) {
+	h := newIdempotencyHandler(t)
+	backend := func(c *gin.Context) { c.String(http.StatusCreated, "ok") }
+
+	required := &model.Route{Path: "/api/pagamentos", Idempotency: &model.IdempotencyPolicy{Required: true}}
+	if rec := serveIdempotent(h, required, newIdempotentRequest("", `{}`), backend, true); rec.Code != http.StatusBadRequest {
+		t.Fatalf("sem chave obrigatória: status = %d, esperado 400", rec.Code)
+	}
+
+	optional := &model.Route{Path: "/api/pagamentos", Idempotency: &model.IdempotencyPolicy{}}
+	if rec := serveIdempotent(h, optional, newIdempotentRequest("", `{}`), backend, true); rec.Code != http.StatusCreated {
+		t.Fatalf("sem chave opcional: status = %d, esperado 201", rec.Code)
+	}
+
+	if rec := serveIdempotent(h, optional, newIdempotentRequest("chave-1", strings.Repeat("a", 1025)), backend, true); rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("corpo acima do limite: status = %d, esperado 413", rec.Code)
+	}
+}
@@ -0,0 +1,80 @@
+package http
+
+import (
+	"net/http"
+	"net/netip"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/pkg/clientip"
+	"github.com/diillson/api-gateway-go/pkg/geoip"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// filterSource aplica as listas de origens permitidas e bloqueadas da rota ao
+// endereço do cliente. Retorna false quando a requisição foi recusada.
+func (h *Handler) filterSource(c *gin.Context, route *model.Route, span trace.Span) bool {
+	if route.IPFilter == nil {
+		return true
+	}
+
+	addr, ok := clientip.FromContext(c.Request.Context())
+	if !ok {
+		addr, _ = netip.ParseAddr(c.ClientIP())
+	}
+	if route.IPFilter.Allows(addr) {
+		span.SetAttributes(attribute.String("ip_filter.decision", "allow"))
+		return true
+	}
+
+	path := c.Request.URL.Path
+	h.logger.Warn("Origem recusada pelo filtro de IP da rota",
+		zap.String("path", path),
+		zap.String("ip", addr.String()))
+	span.SetAttributes(
+		attribute.String("ip_filter.decision", "deny"),
+		attribute.String("ip_filter.client_ip", addr.String()),
+	)
+	if h.metrics != nil {
+		h.metrics.RequestError(path, c.Request.Method, "ip_denied")
+	}
+	c.JSON(http.StatusForbidden, gin.H{"error": "Acesso negado para o endereço de origem"})
+	return false
+}
+
+// filterCountry aplica as listas de países permitidos e bloqueados da rota ao
+// país do cliente. Retorna false quando a requisição foi recusada.
+func (h *Handler) filterCountry(c *gin.Context, route *model.Route, span trace.Span) bool {
+	if route.GeoFilter == nil {
+		return true
+	}
+
+	country := geoip.FromContext(c.Request.Context()).Country
+	if route.GeoFilter.Allows(country) {
+		span.SetAttributes(attribute.String("geo_filter.decision", "allow"))
+		return true
+	}
+
+	path := c.Request.URL.Path
+	h.logger.Warn("País de origem recusado pelo filtro geográfico da rota",
+		zap.String("path", path),
+		zap.String("ip", clientIP(c)),
+		zap.String("country", country))
+	span.SetAttributes(attribute.String("geo_filter.decision", "deny"))
+	if h.metrics != nil {
+		h.metrics.RequestError(path, c.Request.Method, "geo_denied")
+	}
+	c.JSON(http.StatusForbidden, gin.H{"error": "Acesso negado para o país de origem"})
+	return false
+}
+
+// clientIP retorna o endereço do cliente resolvido considerando os proxies
+// confiáveis, ou o do Gin quando a resolução não foi executada
+func clientIP(c *gin.Context) string {
+	if ip := c.GetString("client_ip"); ip != "" {
+		return ip
+	}
+	return c.ClientIP()
+}
@@ -0,0 +1,151 @@
+package http
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/diillson/api-gateway-go/internal/app/auth"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// OIDCHandler conclui o login dos navegadores nas rotas protegidas por OIDC
+type OIDCHandler struct {
+	service *auth.OIDCService
+	logger  *zap.Logger
+}
+
+// NewOIDCHandler cria um novo handler de login OIDC
+func NewOIDCHandler(service *auth.OIDCService, logger *zap.Logger) *OIDCHandler {
+	return &OIDCHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Callback recebe o retorno do provedor, cria a sessão e devolve o navegador
+// ao endereço que originou o login
+func (h *OIDCHandler) Callback(c *gin.Context) {
+	provider := c.Param("provider")
+
+	if errorCode := c.Query("error"); errorCode != "" {
+		h.logger.Warn("Provedor OIDC recusou o login",
+			zap.String("provider", provider),
+			zap.String("error", errorCode),
+			zap.String("description", c.Query("error_description")))
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Login recusado pelo provedor de identidade"})
+		return
+	}
+
+	state := c.Query("state")
+	code := c.Query("code")
+	if state == "" || code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parâmetros state e code são obrigatórios"})
+		return
+	}
+
+	// O estado precisa ser o do navegador que iniciou o login
+	stateCookie, err := c.Cookie(auth.OIDCStateCookie)
+	if err != nil || subtle.ConstantTimeCompare([]byte(stateCookie), []byte(state)) != 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Estado de login inválido"})
+		return
+	}
+	secure := isSecureRequest(c.Request)
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     auth.OIDCStateCookie,
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	login, err := h.service.CompleteLogin(c.Request.Context(), provider, state, code)
+	if err != nil {
+		h.logger.Warn("Falha ao concluir login OIDC",
+			zap.String("provider", provider),
+			zap.Error(err))
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Falha ao concluir login"})
+		return
+	}
+
+	h.setSessionCookies(c, provider, login.Cookie, login.CSRFToken, int(login.TTL.Seconds()))
+
+	// Apenas caminhos locais, evitando redirecionamentos abertos
+	returnTo := login.ReturnTo
+	if !strings.HasPrefix(returnTo, "/") || strings.HasPrefix(returnTo, "//") || strings.HasPrefix(returnTo, "/\\") {
+		returnTo = "/"
+	}
+	c.Redirect(http.StatusFound, returnTo)
+}
+
+// Logout encerra a sessão do navegador e remove os cookies de sessão e CSRF
+func (h *OIDCHandler) Logout(c *gin.Context) {
+	provider := c.Param("provider")
+
+	if err := h.service.Logout(c.Request.Context(), provider, c.Request); err != nil {
+		switch {
+		case errors.Is(err, auth.ErrUnknownProvider):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Provedor OIDC não configurado"})
+		case errors.Is(err, auth.ErrInvalidCSRF):
+			c.JSON(http.StatusForbidden, gin.H{"error": "Token CSRF ausente ou inválido"})
+		case errors.Is(err, auth.ErrNoCredentials):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Sessão não encontrada"})
+		default:
+			h.logger.Error("Falha ao encerrar sessão OIDC",
+				zap.String("provider", provider),
+				zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Falha ao encerrar sessão"})
+		}
+		return
+	}
+
+	h.setSessionCookies(c, provider, "", "", -1)
+	c.Status(http.StatusNoContent)
+}
+
+// setSessionCookies grava (ou remove, com maxAge negativo) o cookie de sessão
+// HttpOnly e, com a camada de sessão, o cookie CSRF legível pelo SPA
+func (h *OIDCHandler) setSessionCookies(c *gin.Context, provider, session, csrfToken string, maxAge int) {
+	cfg := h.service.SessionConfig()
+	sameSite := http.SameSiteLaxMode
+	if cfg.Enabled {
+		sameSite = sameSiteMode(cfg.SameSite)
+	}
+	// Navegadores recusam SameSite=None sem Secure
+	secure := isSecureRequest(c.Request) || sameSite == http.SameSiteNoneMode
+
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     auth.SessionCookie(provider),
+		Value:    session,
+		Path:     "/",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   secure,
+		SameSite: sameSite,
+	})
+	if cfg.Enabled && (csrfToken != "" || maxAge < 0) {
+		http.SetCookie(c.Writer, &http.Cookie{
+			Name:     cfg.CSRFCookie,
+			Value:    csrfToken,
+			Path:     "/",
+			MaxAge:   maxAge,
+			Secure:   secure,
+			SameSite: sameSite,
+		})
+	}
+}
+
+// sameSiteMode converte o SameSite configurado; valores desconhecidos usam Lax
+func sameSiteMode(value string) http.SameSite {
+	switch strings.ToLower(value) {
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteLaxMode
+	}
+}
@@ -0,0 +1,56 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/diillson/api-gateway-go/internal/infra/openapi"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// OpenAPIHandler expõe o catálogo OpenAPI agregado dos backends
+type OpenAPIHandler struct {
+	aggregator *openapi.Aggregator
+	publicURL  string
+	logger     *zap.Logger
+}
+
+// NewOpenAPIHandler cria o handler do catálogo OpenAPI
+func NewOpenAPIHandler(aggregator *openapi.Aggregator, logger *zap.Logger) *OpenAPIHandler {
+	return &OpenAPIHandler{
+		aggregator: aggregator,
+		logger:     logger,
+	}
+}
+
+// SetPublicURL define a URL pública do gateway usada como servidor do documento
+func (h *OpenAPIHandler) SetPublicURL(publicURL string) {
+	h.publicURL = strings.TrimSuffix(publicURL, "/")
+}
+
+// RegisterRoutes registra o endpoint no grupo informado
+func (h *OpenAPIHandler) RegisterRoutes(group *gin.RouterGroup) {
+	group.GET("/openapi", h.Get)
+}
+
+// Get retorna o documento agregado. Parâmetro: refresh=true para obter
+// novamente os documentos dos backends, ignorando o cache.
+func (h *OpenAPIHandler) Get(c *gin.Context) {
+	serverURL := h.publicURL
+	if serverURL == "" {
+		scheme := "http"
+		if isSecureRequest(c.Request) {
+			scheme = "https"
+		}
+		serverURL = scheme + "://" + c.Request.Host
+	}
+
+	document, err := h.aggregator.Build(c.Request.Context(), serverURL, c.Query("refresh") == "true")
+	if err != nil {
+		h.logger.Error("Falha ao montar o catálogo OpenAPI", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Falha ao montar o catálogo OpenAPI"})
+		return
+	}
+	c.JSON(http.StatusOK, document)
+}
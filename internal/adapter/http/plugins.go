@@ -0,0 +1,96 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/pkg/plugin"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// pluginChains guarda as cadeias de plugins já instanciadas, pela configuração
+var pluginChains sync.Map
+
+// runPlugins executa os plugins da rota na ordem configurada. Plugins que
+// alteram a resposta têm os cabeçalhos ajustados no envio, como nas
+// transformações de cabeçalhos. Retorna false quando a requisição foi
+// respondida por um plugin ou quando a cadeia não pôde ser criada.
+func (h *Handler) runPlugins(c *gin.Context, route *model.Route, span trace.Span) bool {
+	if len(route.Plugins) == 0 {
+		return true
+	}
+	path := c.Request.URL.Path
+
+	refs := route.PluginChain()
+	filters, err := pluginChain(refs)
+	if err != nil {
+		// Plugins costumam aplicar políticas de segurança: sem eles, a rota não é atendida
+		h.logger.Error("Falha ao instanciar plugins da rota",
+			zap.String("path", route.Path),
+			zap.Error(err))
+		if h.metrics != nil {
+			h.metrics.RequestError(path, c.Request.Method, "plugin_error")
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Falha ao carregar plugins da rota"})
+		return false
+	}
+
+	var responseFilters []plugin.ResponseFilter
+	for i, filter := range filters {
+		if !filter.OnRequest(c) {
+			name := refs[i].Name
+			span.SetAttributes(attribute.String("plugin.stopped_by", name))
+			if !c.Writer.Written() {
+				// O plugin recusou a requisição sem responder
+				c.JSON(http.StatusForbidden, gin.H{"error": "Requisição recusada pelo plugin " + name})
+			}
+			if h.metrics != nil {
+				h.metrics.RequestError(path, c.Request.Method, "plugin_rejected")
+			}
+			return false
+		}
+		if responseFilter, ok := filter.(plugin.ResponseFilter); ok {
+			responseFilters = append(responseFilters, responseFilter)
+		}
+	}
+
+	if len(responseFilters) > 0 {
+		c.Writer = &headerRewriter{
+			ResponseWriter: c.Writer,
+			rewrite: func() {
+				header := c.Writer.Header()
+				for _, filter := range responseFilters {
+					filter.OnResponse(c, header)
+				}
+			},
+		}
+	}
+	return true
+}
+
+// pluginChain retorna os filtros da cadeia, instanciados uma vez por configuração
+func pluginChain(refs []model.PluginRef) ([]plugin.Filter, error) {
+	key, err := json.Marshal(refs)
+	if err != nil {
+		return nil, err
+	}
+	if cached, ok := pluginChains.Load(string(key)); ok {
+		return cached.([]plugin.Filter), nil
+	}
+
+	filters := make([]plugin.Filter, 0, len(refs))
+	for _, ref := range refs {
+		filter, err := plugin.New(ref.Name, ref.Config)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+	actual, _ := pluginChains.LoadOrStore(string(key), filters)
+	return actual.([]plugin.Filter), nil
+}
@@ -0,0 +1,87 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/diillson/api-gateway-go/internal/app/auth"
+	"github.com/diillson/api-gateway-go/internal/app/quota"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// QuotaHandler expõe a consulta ao uso e às cotas dos consumidores
+type QuotaHandler struct {
+	quotas  *quota.Service
+	apiKeys *auth.APIKeyService
+	logger  *zap.Logger
+}
+
+// NewQuotaHandler cria um novo handler de cotas
+func NewQuotaHandler(quotas *quota.Service, logger *zap.Logger) *QuotaHandler {
+	return &QuotaHandler{
+		quotas: quotas,
+		logger: logger,
+	}
+}
+
+// SetAPIKeys configura o serviço de chaves de API, usado para aplicar as cotas
+// da faixa de cada chave
+func (h *QuotaHandler) SetAPIKeys(apiKeys *auth.APIKeyService) {
+	h.apiKeys = apiKeys
+}
+
+// RegisterRoutes registra os endpoints no grupo informado
+func (h *QuotaHandler) RegisterRoutes(group *gin.RouterGroup) {
+	group.GET("/quotas/:consumer", h.Get)
+}
+
+// Get retorna o consumo corrente do consumidor (apikey:<id> ou sub:<id>) nos
+// períodos diário e mensal, com as cotas aplicáveis, e o histórico gravado
+func (h *QuotaHandler) Get(c *gin.Context) {
+	consumerID := c.Param("consumer")
+	if !strings.HasPrefix(consumerID, "apikey:") && !strings.HasPrefix(consumerID, "sub:") {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Consumidor inválido",
+			"details": "Use apikey:<id> ou sub:<id>",
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+	consumer := quota.Consumer{ID: consumerID}
+	if id, ok := strings.CutPrefix(consumerID, "apikey:"); ok && h.apiKeys != nil {
+		keys, err := h.apiKeys.List(ctx)
+		if err != nil {
+			h.logger.Error("Falha ao listar chaves de API", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Falha ao consultar a chave de API"})
+			return
+		}
+		for _, key := range keys {
+			if key.ID == id {
+				consumer.Tier = key.Tier
+				break
+			}
+		}
+	}
+
+	usage, err := h.quotas.Current(ctx, consumer)
+	if err != nil {
+		h.logger.Error("Falha ao consultar cota do consumidor", zap.String("consumer", consumerID), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Falha ao consultar a cota do consumidor"})
+		return
+	}
+	history, err := h.quotas.History(ctx, consumerID)
+	if err != nil {
+		h.logger.Error("Falha ao consultar histórico de uso", zap.String("consumer", consumerID), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Falha ao consultar o histórico de uso"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"consumer": consumerID,
+		"tier":     consumer.Tier,
+		"periods":  usage.Periods,
+		"history":  history,
+	})
+}
@@ -0,0 +1,86 @@
+package http
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/adapter/proxy"
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/pkg/requestid"
+	"github.com/diillson/api-gateway-go/pkg/tenant"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// recordedBody devolve os bytes do corpo já lidos para a gravação antes do
+// restante, que segue sem ser lido até o envio ao backend
+type recordedBody struct {
+	io.Reader
+	io.Closer
+}
+
+// beginRecording passa a gravar a requisição e a resposta enviada ao cliente,
+// conforme a amostragem da política de gravação da rota. A função retornada
+// encerra a gravação e a envia ao armazenamento. A requisição é gravada como
+// recebida pelo gateway, antes das transformações da rota.
+func (h *Handler) beginRecording(c *gin.Context, route *model.Route) func() {
+	noop := func() {}
+	policy := route.Recording
+	// Streams gRPC e conexões WebSocket não têm um par requisição/resposta
+	if policy == nil || h.recorder == nil || route.IsGRPC() || proxy.IsWebSocketRequest(c.Request) {
+		return noop
+	}
+	if !h.recorder.Sample(policy, c.Request.Method) {
+		return noop
+	}
+
+	limit := policy.BodyLimit()
+	started := time.Now()
+	recording := &model.Recording{
+		ID:             uuid.New().String(),
+		Time:           started.UTC(),
+		Tenant:         tenant.FromContext(c.Request.Context()),
+		Route:          route.Path,
+		RequestID:      requestid.FromContext(c.Request.Context()),
+		Method:         c.Request.Method,
+		Path:           c.Request.URL.Path,
+		Query:          c.Request.URL.RawQuery,
+		Host:           c.Request.Host,
+		RequestHeaders: c.Request.Header.Clone(),
+	}
+
+	if c.Request.Body != nil && c.Request.Body != http.NoBody {
+		// Ler apenas o início do corpo; o corpo completo segue para o backend
+		prefix, _ := io.ReadAll(io.LimitReader(c.Request.Body, limit+1))
+		if int64(len(prefix)) > limit {
+			recording.RequestBody = prefix[:limit]
+			recording.RequestBodyTruncated = true
+		} else {
+			recording.RequestBody = prefix
+		}
+		c.Request.Body = &recordedBody{
+			Reader: io.MultiReader(bytes.NewReader(prefix), c.Request.Body),
+			Closer: c.Request.Body,
+		}
+	}
+
+	recorder := &responseRecorder{ResponseWriter: c.Writer, limit: limit, truncate: true}
+	c.Writer = recorder
+	return func() {
+		c.Writer = recorder.ResponseWriter
+
+		// Os cabeçalhos e o status finais, pois o Gin pode alterá-los até o envio
+		recording.Status = recorder.ResponseWriter.Status()
+		recording.ResponseHeaders = recorder.ResponseWriter.Header().Clone()
+		for _, name := range []string{"Date", "Connection", "Transfer-Encoding", "Keep-Alive"} {
+			recording.ResponseHeaders.Del(name)
+		}
+		recording.ResponseBody = bytes.Clone(recorder.body.Bytes())
+		recording.ResponseBodyTruncated = recorder.overflow
+		recording.DurationMs = float64(time.Since(started).Microseconds()) / 1000
+
+		h.recorder.Capture(policy, recording)
+	}
+}
@@ -0,0 +1,115 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/app/recording"
+	"github.com/diillson/api-gateway-go/internal/domain/repository"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultRecordingPageSize = 50
+	maxRecordingPageSize     = 500
+)
+
+// RecordingHandler expõe a consulta às requisições gravadas
+type RecordingHandler struct {
+	recorder *recording.Recorder
+	logger   *zap.Logger
+}
+
+// NewRecordingHandler cria um novo handler das gravações de tráfego
+func NewRecordingHandler(recorder *recording.Recorder, logger *zap.Logger) *RecordingHandler {
+	return &RecordingHandler{
+		recorder: recorder,
+		logger:   logger,
+	}
+}
+
+// RegisterRoutes registra os endpoints no grupo informado
+func (h *RecordingHandler) RegisterRoutes(group *gin.RouterGroup) {
+	group.GET("/recordings", h.List)
+	group.GET("/recordings/:id", h.Get)
+}
+
+// List retorna uma página das gravações, da mais recente à mais antiga.
+// Filtros: route, tenant, method, minStatus, from e to (RFC 3339, to
+// exclusivo). Paginação: page (a partir de 1) e pageSize.
+func (h *RecordingHandler) List(c *gin.Context) {
+	page, err := queryInt(c, "page", 1)
+	if err != nil || page < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parâmetro 'page' inválido"})
+		return
+	}
+	pageSize, err := queryInt(c, "pageSize", defaultRecordingPageSize)
+	if err != nil || pageSize < 1 || pageSize > maxRecordingPageSize {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Parâmetro 'pageSize' inválido",
+			"details": "pageSize deve estar entre 1 e " + strconv.Itoa(maxRecordingPageSize),
+		})
+		return
+	}
+	minStatus, err := queryInt(c, "minStatus", 0)
+	if err != nil || minStatus < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parâmetro 'minStatus' inválido"})
+		return
+	}
+
+	filter := repository.RecordingFilter{
+		Tenant:    c.Query("tenant"),
+		Route:     c.Query("route"),
+		Method:    c.Query("method"),
+		MinStatus: minStatus,
+		Offset:    (page - 1) * pageSize,
+		Limit:     pageSize,
+	}
+	for name, target := range map[string]*time.Time{"from": &filter.From, "to": &filter.To} {
+		value := c.Query(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Parâmetro '" + name + "' inválido",
+				"details": "use o formato RFC 3339 (ex.: 2026-10-16T14:00:00Z)",
+			})
+			return
+		}
+		*target = parsed
+	}
+
+	recordings, total, err := h.recorder.List(c.Request.Context(), filter)
+	if err != nil {
+		h.logger.Error("Falha ao consultar gravações", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Falha ao consultar gravações"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items":    recordings,
+		"page":     page,
+		"pageSize": pageSize,
+		"total":    total,
+	})
+}
+
+// Get retorna a gravação pelo ID
+func (h *RecordingHandler) Get(c *gin.Context) {
+	recording, err := h.recorder.Get(c.Request.Context(), c.Param("id"))
+	if errors.Is(err, repository.ErrRecordingNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Gravação não encontrada"})
+		return
+	}
+	if err != nil {
+		h.logger.Error("Falha ao consultar gravação", zap.String("id", c.Param("id")), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Falha ao consultar gravação"})
+		return
+	}
+	c.JSON(http.StatusOK, recording)
+}
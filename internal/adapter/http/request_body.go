@@ -0,0 +1,64 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// limitRequestBody aplica os limites do corpo da requisição da rota. Corpos
+// com Content-Length acima do máximo são recusados sem leitura; os demais são
+// lidos com o limite, e o proxy responde 413 ao ultrapassá-lo. Retorna false
+// quando a requisição foi recusada.
+func (h *Handler) limitRequestBody(c *gin.Context, route *model.Route, span trace.Span) bool {
+	policy := route.RequestBody
+	if policy == nil || c.Request.Body == nil || c.Request.Body == http.NoBody || c.Request.ContentLength == 0 {
+		return true
+	}
+	path := c.Request.URL.Path
+
+	contentType := c.GetHeader("Content-Type")
+	if !policy.AllowsContentType(contentType) {
+		h.logger.Warn("Tipo de conteúdo não aceito pela rota",
+			zap.String("path", path),
+			zap.String("content_type", contentType))
+		span.SetAttributes(attribute.String("request_body.rejected", "content_type"))
+		if h.metrics != nil {
+			h.metrics.RequestError(path, c.Request.Method, "unsupported_media_type")
+		}
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{
+			"error":         "Tipo de conteúdo não aceito pela rota",
+			"content_types": policy.ContentTypes,
+		})
+		return false
+	}
+
+	if policy.MaxBytes <= 0 {
+		return true
+	}
+	if c.Request.ContentLength > policy.MaxBytes {
+		h.logger.Warn("Corpo da requisição excede o limite da rota",
+			zap.String("path", path),
+			zap.Int64("content_length", c.Request.ContentLength),
+			zap.Int64("max_bytes", policy.MaxBytes))
+		span.SetAttributes(attribute.String("request_body.rejected", "too_large"))
+		if h.metrics != nil {
+			h.metrics.RequestError(path, c.Request.Method, "body_too_large")
+		}
+		// O corpo não é lido, então a conexão não pode ser reaproveitada
+		c.Header("Connection", "close")
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"error":     "Corpo da requisição excede o limite da rota",
+			"max_bytes": policy.MaxBytes,
+		})
+		return false
+	}
+
+	// Sem Content-Length (chunked), o limite é aplicado durante o envio ao backend
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, policy.MaxBytes)
+	return true
+}
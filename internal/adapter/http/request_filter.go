@@ -0,0 +1,182 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// filterRequest avalia as regras do filtro de requisições da rota. Retorna a
+// rota que atende a requisição, com o backend da regra route quando aplicada,
+// e false quando a requisição foi negada. Regras cuja expressão falha na
+// avaliação (ex.: tipos incompatíveis) não se aplicam. O corpo só é lido
+// quando alguma regra usa request.body.
+func (h *Handler) filterRequest(c *gin.Context, route *model.Route, span trace.Span) (*model.Route, bool) {
+	filter := route.RequestFilter
+	if filter == nil {
+		return route, true
+	}
+	path := c.Request.URL.Path
+
+	var body interface{}
+	if filter.InspectsBody() {
+		var ok bool
+		if body, ok = h.readFilterBody(c, filter.BodyLimit(), span); !ok {
+			return route, false
+		}
+	}
+	vars := filterVariables(c, route, body)
+
+	for i := range filter.Rules {
+		rule := &filter.Rules[i]
+		matched, err := rule.Matches(vars)
+		if err != nil {
+			h.logger.Debug("Falha ao avaliar regra do filtro de requisições",
+				zap.String("path", route.Path),
+				zap.String("rule", rule.Name),
+				zap.String("when", rule.When),
+				zap.Error(err))
+			continue
+		}
+		if !matched {
+			continue
+		}
+
+		switch rule.Action {
+		case model.FilterAnnotate:
+			for name, value := range rule.Headers {
+				c.Request.Header.Set(name, value)
+			}
+			span.AddEvent("request_filter.annotate", trace.WithAttributes(attribute.String("filter.rule", rule.Name)))
+			continue
+		case model.FilterDeny:
+			span.SetAttributes(
+				attribute.String("filter.rule", rule.Name),
+				attribute.String("filter.decision", model.FilterDeny))
+			h.denyByFilter(c, path, rule.DenyStatus(), rule.DenyMessage())
+			return route, false
+		case model.FilterRoute:
+			span.SetAttributes(
+				attribute.String("filter.rule", rule.Name),
+				attribute.String("filter.decision", model.FilterRoute))
+			h.logger.Debug("Requisição desviada pelo filtro da rota",
+				zap.String("path", route.Path),
+				zap.String("rule", rule.Name),
+				zap.String("serviceURL", rule.ServiceURL))
+			return route.WithFilterBackend(rule), true
+		default:
+			span.SetAttributes(
+				attribute.String("filter.rule", rule.Name),
+				attribute.String("filter.decision", model.FilterAllow))
+			return route, true
+		}
+	}
+
+	if filter.DeniesByDefault() {
+		span.SetAttributes(attribute.String("filter.decision", "default_deny"))
+		h.denyByFilter(c, path, http.StatusForbidden, "Acesso negado pelo filtro da rota")
+		return route, false
+	}
+	return route, true
+}
+
+func (h *Handler) denyByFilter(c *gin.Context, path string, status int, message string) {
+	if h.metrics != nil {
+		h.metrics.RequestError(path, c.Request.Method, "filter_denied")
+	}
+	c.JSON(status, gin.H{"error": message})
+}
+
+// readFilterBody lê o corpo JSON avaliado pelas regras e o devolve à
+// requisição para o backend. Corpos acima do limite são recusados com 413;
+// corpos vazios ou que não são JSON resultam em request.body nulo.
+func (h *Handler) readFilterBody(c *gin.Context, limit int64, span trace.Span) (interface{}, bool) {
+	if c.Request.Body == nil || c.Request.Body == http.NoBody {
+		return nil, true
+	}
+	path := c.Request.URL.Path
+
+	raw, err := io.ReadAll(io.LimitReader(c.Request.Body, limit+1))
+	if err != nil {
+		h.logger.Warn("Falha ao ler corpo da requisição para o filtro", zap.String("path", path), zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Falha ao ler corpo da requisição"})
+		return nil, false
+	}
+	if int64(len(raw)) > limit {
+		span.SetAttributes(attribute.String("filter.decision", "body_too_large"))
+		if h.metrics != nil {
+			h.metrics.RequestError(path, c.Request.Method, "body_too_large")
+		}
+		c.Header("Connection", "close")
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"error":     "Corpo da requisição excede o limite do filtro da rota",
+			"max_bytes": limit,
+		})
+		return nil, false
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(raw))
+	c.Request.ContentLength = int64(len(raw))
+
+	var body interface{}
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &body); err != nil {
+			h.logger.Debug("Corpo da requisição não é JSON; request.body fica nulo",
+				zap.String("path", path),
+				zap.Error(err))
+			body = nil
+		}
+	}
+	return body, true
+}
+
+// filterVariables monta as variáveis das expressões do filtro. Os nomes dos
+// cabeçalhos ficam em minúsculas; cabeçalhos e parâmetros de query repetidos
+// usam o primeiro valor. body é o corpo JSON, nulo quando não foi lido.
+func filterVariables(c *gin.Context, route *model.Route, body interface{}) map[string]interface{} {
+	data := requestTemplateData(c, route)
+
+	headers := make(map[string]interface{}, len(c.Request.Header))
+	for name, values := range c.Request.Header {
+		if len(values) > 0 {
+			headers[strings.ToLower(name)] = values[0]
+		}
+	}
+	query := make(map[string]interface{})
+	for name, values := range c.Request.URL.Query() {
+		if len(values) > 0 {
+			query[name] = values[0]
+		}
+	}
+	claims := data.Claims
+	if claims == nil {
+		claims = map[string]interface{}{}
+	}
+
+	return map[string]interface{}{
+		"request": map[string]interface{}{
+			"method":  data.Method,
+			"path":    data.Path,
+			"host":    c.Request.Host,
+			"ip":      data.ClientIP,
+			"headers": headers,
+			"query":   query,
+			"params":  data.Params,
+			"body":    body,
+		},
+		"jwt":      map[string]interface{}{"claims": claims},
+		"consumer": data.Consumer,
+		"tenant":   data.Tenant,
+		"route": map[string]interface{}{
+			"path":   data.Route,
+			"labels": data.Labels,
+		},
+	}
+}
@@ -0,0 +1,197 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/internal/infra/openapi"
+	"github.com/diillson/api-gateway-go/pkg/jsonschema"
+	"github.com/diillson/api-gateway-go/pkg/reqtemplate"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultValidationMaxBytes é o tamanho máximo do corpo lido para validação
+	defaultValidationMaxBytes = 1 << 20
+	// maxValidationDetails limita as violações listadas na resposta
+	maxValidationDetails = 20
+)
+
+// compiledSchemas guarda os schemas das rotas já compilados, pelo texto do schema
+var compiledSchemas sync.Map
+
+// validationDetail é uma violação do schema informada ao cliente
+type validationDetail struct {
+	Location string `json:"location"` // body ou query
+	Path     string `json:"path"`     // JSON Pointer do valor inválido; na query, /<parâmetro>
+	Message  string `json:"message"`
+}
+
+// validateRequest valida o corpo JSON e a query string com os schemas da rota.
+// Requisições inválidas são recusadas com 400 e a lista das violações. Se o
+// documento OpenAPI da operação estiver indisponível, a requisição segue sem
+// validação. Retorna false quando a requisição foi recusada.
+func (h *Handler) validateRequest(c *gin.Context, route *model.Route, span trace.Span) bool {
+	validation := route.Validation
+	if validation == nil {
+		return true
+	}
+	path := c.Request.URL.Path
+
+	schemas, err := h.requestSchemas(c, route)
+	if err != nil {
+		h.logger.Warn("Requisição encaminhada sem validação: schemas indisponíveis",
+			zap.String("path", path),
+			zap.String("operation", validation.Operation),
+			zap.Error(err))
+		span.SetAttributes(attribute.String("validation.skipped", err.Error()))
+		return true
+	}
+
+	var details []validationDetail
+	if schemas.Query != nil {
+		for _, violation := range schemas.Query.ValidateQuery(c.Request.URL.Query()) {
+			details = append(details, validationDetail{Location: "query", Path: violation.Path, Message: violation.Message})
+		}
+	}
+
+	if schemas.Body != nil {
+		bodyDetails, ok := h.validateBody(c, route, schemas)
+		if !ok {
+			return false
+		}
+		details = append(details, bodyDetails...)
+	}
+
+	if len(details) == 0 {
+		return true
+	}
+
+	h.logger.Debug("Requisição recusada pela validação",
+		zap.String("path", path),
+		zap.Int("violations", len(details)))
+	span.SetAttributes(attribute.Int("validation.errors", len(details)))
+	if h.metrics != nil {
+		h.metrics.RequestError(path, c.Request.Method, "validation_error")
+	}
+	if len(details) > maxValidationDetails {
+		details = details[:maxValidationDetails]
+	}
+	c.JSON(http.StatusBadRequest, gin.H{
+		"error":   "Requisição inválida",
+		"details": details,
+	})
+	return false
+}
+
+// validateBody lê e valida o corpo JSON, que é restaurado para o backend.
+// Retorna false quando a requisição já foi respondida.
+func (h *Handler) validateBody(c *gin.Context, route *model.Route, schemas *openapi.RequestSchemas) ([]validationDetail, bool) {
+	path := c.Request.URL.Path
+	if c.Request.Body == nil || c.Request.Body == http.NoBody || c.Request.ContentLength == 0 {
+		if schemas.BodyRequired {
+			return []validationDetail{{Location: "body", Message: "corpo da requisição obrigatório"}}, true
+		}
+		return nil, true
+	}
+	if c.Request.Header.Get("Content-Encoding") != "" || !reqtemplate.IsJSON(c.ContentType()) {
+		if h.metrics != nil {
+			h.metrics.RequestError(path, c.Request.Method, "unsupported_media_type")
+		}
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "Corpo da requisição deve ser JSON sem compressão para ser validado"})
+		return nil, false
+	}
+
+	limit := route.Validation.MaxBytes
+	if limit <= 0 {
+		limit = defaultValidationMaxBytes
+	}
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, limit+1))
+	if err != nil {
+		// O corpo pode ter excedido o limite da rota (requestBody.maxBytes)
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Corpo da requisição excede o limite da rota"})
+			return nil, false
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Falha ao ler o corpo da requisição"})
+		return nil, false
+	}
+	if int64(len(body)) > limit {
+		if h.metrics != nil {
+			h.metrics.RequestError(path, c.Request.Method, "body_too_large")
+		}
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Corpo da requisição excede o limite da validação"})
+		return nil, false
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		if schemas.BodyRequired {
+			return []validationDetail{{Location: "body", Message: "corpo da requisição obrigatório"}}, true
+		}
+		return nil, true
+	}
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return []validationDetail{{Location: "body", Message: "corpo da requisição não é JSON válido"}}, true
+	}
+
+	var details []validationDetail
+	for _, violation := range schemas.Body.Validate(value) {
+		details = append(details, validationDetail{Location: "body", Path: violation.Path, Message: violation.Message})
+	}
+	return details, true
+}
+
+// requestSchemas retorna os schemas da rota: os informados na própria rota ou
+// os da operação no documento OpenAPI do backend
+func (h *Handler) requestSchemas(c *gin.Context, route *model.Route) (*openapi.RequestSchemas, error) {
+	validation := route.Validation
+	if validation.Operation != "" {
+		if h.openAPI == nil {
+			return nil, errors.New("catálogo OpenAPI não configurado")
+		}
+		return h.openAPI.RequestSchemas(c.Request.Context(), route, validation.Operation)
+	}
+
+	schemas := &openapi.RequestSchemas{}
+	var err error
+	if len(validation.Body) > 0 {
+		if schemas.Body, err = compileSchema(validation.Body); err != nil {
+			return nil, err
+		}
+		// Com schema de corpo na rota, o corpo é exigido nos métodos que o enviam
+		switch c.Request.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch:
+			schemas.BodyRequired = true
+		}
+	}
+	if len(validation.Query) > 0 {
+		if schemas.Query, err = compileSchema(validation.Query); err != nil {
+			return nil, err
+		}
+	}
+	return schemas, nil
+}
+
+// compileSchema compila o schema, reaproveitando compilações anteriores
+func compileSchema(raw json.RawMessage) (*jsonschema.Schema, error) {
+	if cached, ok := compiledSchemas.Load(string(raw)); ok {
+		return cached.(*jsonschema.Schema), nil
+	}
+	schema, err := jsonschema.Compile(raw)
+	if err != nil {
+		return nil, err
+	}
+	compiledSchemas.Store(string(raw), schema)
+	return schema, nil
+}
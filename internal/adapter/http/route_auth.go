@@ -0,0 +1,291 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/diillson/api-gateway-go/internal/app/auth"
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// SetAuthService configura o serviço que valida os tokens JWT das rotas com a
+// política "jwt"
+func (h *Handler) SetAuthService(service *auth.AuthService) {
+	h.authService = service
+}
+
+// SetOIDC configura o serviço que autentica as rotas protegidas por OIDC
+func (h *Handler) SetOIDC(service *auth.OIDCService) {
+	h.oidc = service
+}
+
+// authenticateRoute aplica a política de autenticação da rota. Retorna false
+// quando a requisição já foi respondida (401, 403, 503 ou redirecionamento ao login).
+func (h *Handler) authenticateRoute(c *gin.Context, route *model.Route, span trace.Span) bool {
+	path := c.Request.URL.Path
+
+	// Chaves de API acessam apenas as rotas liberadas para elas
+	key := apiKeyFromContext(c)
+	if key != nil && !key.AllowsRoute(route.Path) {
+		h.logger.Warn("Chave de API sem acesso à rota",
+			zap.String("path", path),
+			zap.String("apiKey", key.ID))
+		if h.metrics != nil {
+			h.metrics.RequestError(path, c.Request.Method, "forbidden")
+		}
+		span.SetAttributes(attribute.Bool("auth.denied", true))
+		c.JSON(http.StatusForbidden, gin.H{"error": "Chave de API sem acesso a esta rota"})
+		return false
+	}
+	if key != nil {
+		span.SetAttributes(attribute.String("auth.api_key", key.ID))
+	}
+
+	if route.Auth == nil {
+		return true
+	}
+	switch route.Auth.Type {
+	case model.RouteAuthAPIKey:
+		if key == nil {
+			if h.metrics != nil {
+				h.metrics.RequestError(path, c.Request.Method, "unauthenticated")
+			}
+			span.SetAttributes(attribute.Bool("auth.denied", true))
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Chave de API necessária"})
+			return false
+		}
+		return true
+	case model.RouteAuthJWT:
+		return h.authenticateJWT(c, span)
+	case model.RouteAuthBasic:
+		return h.authenticateBasic(c, route, span)
+	case model.RouteAuthHMAC:
+		return h.authenticateHMAC(c, route, span)
+	default:
+		return h.authenticateOIDC(c, route, span)
+	}
+}
+
+// authenticateJWT valida o token Bearer emitido pelo gateway ou pelo provedor
+// de identidade configurado no JWKS
+func (h *Handler) authenticateJWT(c *gin.Context, span trace.Span) bool {
+	path := c.Request.URL.Path
+
+	var token string
+	if header := c.GetHeader("Authorization"); strings.HasPrefix(header, "Bearer ") {
+		token = strings.TrimSpace(strings.TrimPrefix(header, "Bearer "))
+	}
+	if h.authService == nil || token == "" {
+		if h.metrics != nil {
+			h.metrics.RequestError(path, c.Request.Method, "unauthenticated")
+		}
+		span.SetAttributes(attribute.Bool("auth.denied", true))
+		c.Header("WWW-Authenticate", `Bearer realm="api-gateway"`)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token JWT necessário"})
+		return false
+	}
+
+	user, claims, err := h.authService.ValidateTokenClaims(token)
+	if err != nil {
+		h.logger.Warn("Token inválido para rota protegida",
+			zap.String("path", path),
+			zap.Error(err))
+		if h.metrics != nil {
+			h.metrics.RequestError(path, c.Request.Method, "unauthenticated")
+		}
+		span.SetAttributes(attribute.Bool("auth.denied", true))
+		c.Header("WWW-Authenticate", `Bearer realm="api-gateway", error="invalid_token"`)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido ou expirado"})
+		return false
+	}
+
+	c.Set("user", user)
+	c.Set("claims", claims)
+	span.SetAttributes(attribute.String("auth.subject", user.ID))
+	return true
+}
+
+// authorizeRoute avalia as regras de autorização da rota para o consumidor
+// autenticado e registra a decisão no span. Retorna false quando a requisição
+// foi recusada.
+func (h *Handler) authorizeRoute(c *gin.Context, route *model.Route, span trace.Span) bool {
+	if route.Authorization == nil {
+		return true
+	}
+	path := c.Request.URL.Path
+
+	value, exists := c.Get("user")
+	user, _ := value.(*model.User)
+	if !exists || user == nil {
+		span.SetAttributes(
+			attribute.String("authz.decision", "deny"),
+			attribute.String("authz.reason", "consumidor não autenticado"),
+		)
+		if h.metrics != nil {
+			h.metrics.RequestError(path, c.Request.Method, "unauthenticated")
+		}
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Autenticação necessária"})
+		return false
+	}
+
+	var claims map[string]interface{}
+	if value, exists := c.Get("claims"); exists {
+		claims, _ = value.(map[string]interface{})
+	}
+
+	allowed, reason := route.Authorization.Evaluate(user.Role, claims)
+	if !allowed {
+		h.logger.Warn("Acesso negado pelas regras de autorização da rota",
+			zap.String("path", path),
+			zap.String("user", user.ID),
+			zap.String("reason", reason))
+		span.SetAttributes(
+			attribute.String("authz.decision", "deny"),
+			attribute.String("authz.reason", reason),
+		)
+		if h.metrics != nil {
+			h.metrics.RequestError(path, c.Request.Method, "forbidden")
+		}
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   "Acesso negado",
+			"details": reason,
+		})
+		return false
+	}
+
+	span.SetAttributes(attribute.String("authz.decision", "allow"))
+	return true
+}
+
+// authenticateOIDC autentica o consumidor no provedor OIDC da rota
+func (h *Handler) authenticateOIDC(c *gin.Context, route *model.Route, span trace.Span) bool {
+	path := c.Request.URL.Path
+
+	if h.oidc == nil {
+		h.logger.Error("Rota exige OIDC, mas nenhum provedor está configurado",
+			zap.String("path", path),
+			zap.String("provider", route.Auth.Provider))
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Autenticação da rota indisponível"})
+		return false
+	}
+
+	identity, err := h.oidc.Authenticate(c.Request.Context(), route.Auth.Provider, c.Request)
+	if err != nil {
+		span.SetAttributes(
+			attribute.String("auth.provider", route.Auth.Provider),
+			attribute.Bool("auth.denied", true),
+		)
+		if h.metrics != nil {
+			h.metrics.RequestError(path, c.Request.Method, "unauthenticated")
+		}
+
+		if errors.Is(err, auth.ErrUnknownProvider) {
+			h.logger.Error("Provedor OIDC da rota não configurado",
+				zap.String("path", path),
+				zap.String("provider", route.Auth.Provider))
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Autenticação da rota indisponível"})
+			return false
+		}
+
+		if errors.Is(err, auth.ErrInvalidCSRF) {
+			h.logger.Warn("Token CSRF inválido na sessão OIDC",
+				zap.String("path", path),
+				zap.String("provider", route.Auth.Provider))
+			c.JSON(http.StatusForbidden, gin.H{"error": "Token CSRF ausente ou inválido"})
+			return false
+		}
+
+		if errors.Is(err, auth.ErrNoCredentials) && route.Auth.Login && isBrowserNavigation(c.Request) &&
+			h.oidc.CanLogin(route.Auth.Provider) {
+			h.redirectToLogin(c, route)
+			return false
+		}
+
+		h.logger.Warn("Requisição não autenticada para rota protegida",
+			zap.String("path", path),
+			zap.String("provider", route.Auth.Provider),
+			zap.Error(err))
+		c.Header("WWW-Authenticate", `Bearer realm="api-gateway"`)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Autenticação necessária"})
+		return false
+	}
+
+	c.Set("user", identity.User)
+	c.Set("claims", identity.Claims)
+
+	// O backend recebe o access token da sessão, e não os cookies do gateway
+	if identity.AccessToken != "" {
+		c.Request.Header.Set("Authorization", "Bearer "+identity.AccessToken)
+		removeCookie(c.Request, auth.SessionCookie(route.Auth.Provider))
+		if session := h.oidc.SessionConfig(); session.Enabled {
+			removeCookie(c.Request, session.CSRFCookie)
+			c.Request.Header.Del(session.CSRFHeader)
+		}
+	}
+
+	span.SetAttributes(
+		attribute.String("auth.provider", route.Auth.Provider),
+		attribute.String("auth.subject", identity.User.ID),
+	)
+	return true
+}
+
+// redirectToLogin inicia o login no provedor e guarda o estado em cookie
+func (h *Handler) redirectToLogin(c *gin.Context, route *model.Route) {
+	loginURL, state, err := h.oidc.StartLogin(c.Request.Context(), route.Auth.Provider, c.Request.URL.RequestURI())
+	if err != nil {
+		h.logger.Error("Falha ao iniciar login OIDC",
+			zap.String("provider", route.Auth.Provider),
+			zap.Error(err))
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Provedor de identidade indisponível"})
+		return
+	}
+
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     auth.OIDCStateCookie,
+		Value:    state,
+		Path:     "/",
+		MaxAge:   600,
+		HttpOnly: true,
+		Secure:   isSecureRequest(c.Request),
+		SameSite: http.SameSiteLaxMode,
+	})
+	c.Redirect(http.StatusFound, loginURL)
+}
+
+// apiKeyFromContext retorna a chave de API autenticada na requisição, se houver
+func apiKeyFromContext(c *gin.Context) *model.APIKey {
+	value, exists := c.Get(auth.APIKeyContextKey)
+	if !exists {
+		return nil
+	}
+	key, _ := value.(*model.APIKey)
+	return key
+}
+
+// isBrowserNavigation identifica a navegação de um navegador, a única que pode
+// ser redirecionada ao login
+func isBrowserNavigation(r *http.Request) bool {
+	return r.Method == http.MethodGet && strings.Contains(r.Header.Get("Accept"), "text/html")
+}
+
+// isSecureRequest indica se o cliente acessou o gateway por HTTPS
+func isSecureRequest(r *http.Request) bool {
+	return r.TLS != nil || strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https")
+}
+
+// removeCookie retira um cookie do cabeçalho Cookie da requisição
+func removeCookie(r *http.Request, name string) {
+	cookies := r.Cookies()
+	r.Header.Del("Cookie")
+	for _, cookie := range cookies {
+		if cookie.Name != name {
+			r.AddCookie(cookie)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package http
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/diillson/api-gateway-go/internal/app/auth"
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// Cabeçalhos das requisições assinadas com HMAC
+const (
+	HeaderSignatureKey       = "X-Gateway-Key"
+	HeaderSignatureTimestamp = "X-Gateway-Timestamp"
+	HeaderSignatureNonce     = "X-Gateway-Nonce"
+	HeaderSignature          = "X-Gateway-Signature"
+)
+
+// SetCredentials configura o serviço que autentica as rotas com as políticas
+// "basic" e "hmac"
+func (h *Handler) SetCredentials(service *auth.CredentialService) {
+	h.credentials = service
+}
+
+// authenticateBasic valida o usuário e a senha do HTTP Basic. O cabeçalho
+// Authorization não é repassado ao backend.
+func (h *Handler) authenticateBasic(c *gin.Context, route *model.Route, span trace.Span) bool {
+	path := c.Request.URL.Path
+
+	username, password, ok := c.Request.BasicAuth()
+	if h.credentials == nil || !ok {
+		h.denyCredential(c, span, "Basic", "Credenciais necessárias")
+		return false
+	}
+
+	credential, err := h.credentials.AuthenticateBasic(c.Request.Context(), username, password)
+	if err != nil {
+		if !errors.Is(err, auth.ErrInvalidCredential) {
+			h.logger.Error("Falha ao validar credencial basic", zap.String("path", path), zap.Error(err))
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Autenticação da rota indisponível"})
+			return false
+		}
+		h.logger.Warn("Credencial basic inválida para rota protegida",
+			zap.String("path", path),
+			zap.String("username", username))
+		h.denyCredential(c, span, "Basic", "Credenciais inválidas")
+		return false
+	}
+
+	if !h.acceptCredential(c, route, credential, span) {
+		return false
+	}
+	c.Request.Header.Del("Authorization")
+	return true
+}
+
+// authenticateHMAC valida a assinatura HMAC da requisição. O corpo é lido até
+// o limite configurado para compor a assinatura e devolvido à requisição; os
+// cabeçalhos de assinatura não são repassados ao backend.
+func (h *Handler) authenticateHMAC(c *gin.Context, route *model.Route, span trace.Span) bool {
+	path := c.Request.URL.Path
+
+	if h.credentials == nil || c.GetHeader(HeaderSignature) == "" {
+		h.denyCredential(c, span, "HMAC-SHA256", "Assinatura da requisição necessária")
+		return false
+	}
+
+	body, ok := h.readSignedBody(c, span)
+	if !ok {
+		return false
+	}
+	bodyHash := sha256.Sum256(body)
+
+	uri := c.Request.RequestURI
+	if uri == "" {
+		uri = c.Request.URL.RequestURI()
+	}
+	signed := &auth.SignedRequest{
+		KeyID:     c.GetHeader(HeaderSignatureKey),
+		Timestamp: c.GetHeader(HeaderSignatureTimestamp),
+		Nonce:     c.GetHeader(HeaderSignatureNonce),
+		Signature: c.GetHeader(HeaderSignature),
+		Method:    c.Request.Method,
+		URI:       uri,
+		BodyHash:  hex.EncodeToString(bodyHash[:]),
+	}
+
+	credential, err := h.credentials.AuthenticateSignature(c.Request.Context(), signed)
+	if err != nil {
+		if !errors.Is(err, auth.ErrInvalidCredential) && !errors.Is(err, auth.ErrInvalidSignature) {
+			h.logger.Error("Falha ao validar assinatura HMAC", zap.String("path", path), zap.Error(err))
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Autenticação da rota indisponível"})
+			return false
+		}
+		h.logger.Warn("Assinatura HMAC inválida para rota protegida",
+			zap.String("path", path),
+			zap.String("keyId", signed.KeyID),
+			zap.Error(err))
+		h.denyCredential(c, span, "HMAC-SHA256", "Assinatura inválida")
+		return false
+	}
+
+	if !h.acceptCredential(c, route, credential, span) {
+		return false
+	}
+	for _, header := range []string{HeaderSignatureKey, HeaderSignatureTimestamp, HeaderSignatureNonce, HeaderSignature} {
+		c.Request.Header.Del(header)
+	}
+	return true
+}
+
+// readSignedBody lê o corpo da requisição assinada, recusando com 413 os
+// corpos acima do limite, e o devolve à requisição para o backend
+func (h *Handler) readSignedBody(c *gin.Context, span trace.Span) ([]byte, bool) {
+	if c.Request.Body == nil || c.Request.Body == http.NoBody {
+		return nil, true
+	}
+
+	limit := h.credentials.MaxBodyBytes()
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, limit+1))
+	if err != nil {
+		h.logger.Warn("Falha ao ler corpo da requisição assinada", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Falha ao ler corpo da requisição"})
+		return nil, false
+	}
+	if int64(len(body)) > limit {
+		span.SetAttributes(attribute.Bool("auth.denied", true))
+		if h.metrics != nil {
+			h.metrics.RequestError(c.Request.URL.Path, c.Request.Method, "request_too_large")
+		}
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"error":     "Corpo da requisição assinada excede o limite",
+			"max_bytes": limit,
+		})
+		return nil, false
+	}
+
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	c.Request.ContentLength = int64(len(body))
+	return body, true
+}
+
+// acceptCredential verifica se a credencial autenticada pode acessar a rota e
+// a registra como consumidor da requisição
+func (h *Handler) acceptCredential(c *gin.Context, route *model.Route, credential *model.Credential, span trace.Span) bool {
+	if !credential.AllowsRoute(route.Path) {
+		h.logger.Warn("Credencial sem acesso à rota",
+			zap.String("path", c.Request.URL.Path),
+			zap.String("credential", credential.ID))
+		span.SetAttributes(attribute.Bool("auth.denied", true))
+		if h.metrics != nil {
+			h.metrics.RequestError(c.Request.URL.Path, c.Request.Method, "forbidden")
+		}
+		c.JSON(http.StatusForbidden, gin.H{"error": "Credencial sem acesso a esta rota"})
+		return false
+	}
+
+	c.Set("user", &model.User{ID: credential.ID, Username: credential.Name, Role: credential.Type})
+	c.Set("claims", map[string]interface{}{
+		"sub":        credential.ID,
+		"name":       credential.Name,
+		"credential": credential.Type,
+	})
+	span.SetAttributes(
+		attribute.String("auth.subject", credential.ID),
+		attribute.String("auth.credential_type", credential.Type),
+	)
+	return true
+}
+
+// denyCredential responde com 401 às requisições sem credencial válida,
+// indicando o esquema esperado no WWW-Authenticate
+func (h *Handler) denyCredential(c *gin.Context, span trace.Span, scheme, message string) {
+	span.SetAttributes(attribute.Bool("auth.denied", true))
+	if h.metrics != nil {
+		h.metrics.RequestError(c.Request.URL.Path, c.Request.Method, "unauthenticated")
+	}
+	realm := "api-gateway"
+	if h.credentials != nil && h.credentials.Realm() != "" {
+		realm = h.credentials.Realm()
+	}
+	c.Header("WWW-Authenticate", fmt.Sprintf("%s realm=%q", scheme, realm))
+	c.JSON(http.StatusUnauthorized, gin.H{"error": message})
+}
@@ -0,0 +1,202 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/app/auth"
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/internal/domain/repository"
+	"github.com/diillson/api-gateway-go/pkg/cache"
+	"github.com/diillson/api-gateway-go/pkg/config"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// stubCredentials é um repositório de credenciais em memória
+type stubCredentials struct {
+	credentials map[string]*model.Credential
+	secrets     map[string]string
+}
+
+func (r *stubCredentials) CreateCredential(_ context.Context, credential *model.Credential, secret string) error {
+	r.credentials[credential.Identifier] = credential
+	r.secrets[credential.Identifier] = secret
+	return nil
+}
+
+func (r *stubCredentials) GetCredentialByIdentifier(_ context.Context, identifier string) (*model.Credential, string, error) {
+	credential, ok := r.credentials[identifier]
+	if !ok {
+		return nil, "", repository.ErrCredentialNotFound
+	}
+	return credential, r.secrets[identifier], nil
+}
+
+func (r *stubCredentials) ListCredentials(context.Context) ([]*model.Credential, error) {
+	return nil, nil
+}
+
+func (r *stubCredentials) RevokeCredential(context.Context, string) (string, error) {
+	return "", repository.ErrCredentialNotFound
+}
+
+func (r *stubCredentials) TouchCredential(context.Context, string, time.Time) error {
+	return nil
+}
+
+// newCredentialHandler cria o handler com o serviço de credenciais, aceitando
+// corpos assinados de até 1 KiB
+func newCredentialHandler(t *testing.T) (*Handler, *auth.CredentialService) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	repo := &stubCredentials{credentials: map[string]*model.Credential{}, secrets: map[string]string{}}
+	service := auth.NewCredentialService(repo,
+		cache.NewMemoryCache(time.Minute, time.Minute, nil, zap.NewNop()),
+		config.CredentialsConfig{Realm: "parceiros", CacheTTL: time.Minute, MaxSkew: 5 * time.Minute, MaxBodyKiB: 1},
+		zap.NewNop())
+	return &Handler{logger: zap.NewNop(), credentials: service}, service
+}
+
+// serveRouteAuth executa a autenticação da rota e indica se ela foi aceita
+func serveRouteAuth(authenticate func(*gin.Context, *model.Route, trace.Span) bool, route *model.Route, req *http.Request) (*httptest.ResponseRecorder, *http.Request, bool) {
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = req
+	ok := authenticate(c, route, trace.SpanFromContext(req.Context()))
+	return rec, c.Request, ok
+}
+
+// newHMACRequest monta uma requisição assinada com o segredo da chave
+func newHMACRequest(keyID, secret, nonce, target string, body []byte) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, target, bytes.NewReader(body))
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	bodyHash := sha256.Sum256(body)
+	signed := &auth.SignedRequest{
+		Method:    http.MethodPost,
+		URI:       target,
+		Timestamp: timestamp,
+		Nonce:     nonce,
+		BodyHash:  hex.EncodeToString(bodyHash[:]),
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(signed.StringToSign()))
+
+	req.Header.Set(HeaderSignatureKey, keyID)
+	req.Header.Set(HeaderSignatureTimestamp, timestamp)
+	req.Header.Set(HeaderSignatureNonce, nonce)
+	req.Header.Set(HeaderSignature, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	return req
+}
+
+func TestAuthenticateHMACRoute(t *testing.T) {
+	h, service := newCredentialHandler(t)
+	credential, secret, err := service.Create(context.Background(), auth.CredentialRequest{
+		Name: "parceiro", Type: model.CredentialHMAC, Routes: []string{"/api/pedidos"},
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	allowed := &model.Route{Path: "/api/pedidos"}
+
+	body := []byte(`{"pedido": 42}`)
+	rec, req, ok := serveRouteAuth(h.authenticateHMAC, allowed,
+		newHMACRequest(credential.Identifier, secret, "nonce-1", "/api/pedidos?origem=parceiro", body))
+	if !ok {
+		t.Fatalf("requisição assinada recusada: %d %s", rec.Code, rec.Body.String())
+	}
+	if forwarded, _ := io.ReadAll(req.Body); !bytes.Equal(forwarded, body) {
+		t.Fatalf("corpo repassado = %q, esperado o corpo original", forwarded)
+	}
+	if req.Header.Get(HeaderSignature) != "" || req.Header.Get(HeaderSignatureKey) != "" {
+		t.Fatal("cabeçalhos de assinatura repassados ao backend")
+	}
+
+	// A mesma assinatura não é aceita de novo
+	rec, _, ok = serveRouteAuth(h.authenticateHMAC, allowed,
+		newHMACRequest(credential.Identifier, secret, "nonce-1", "/api/pedidos?origem=parceiro", body))
+	if ok || rec.Code != http.StatusUnauthorized {
+		t.Fatalf("repetição: status = %d, aceita = %v; esperado 401", rec.Code, ok)
+	}
+	if got := rec.Header().Get("WWW-Authenticate"); got != `HMAC-SHA256 realm="parceiros"` {
+		t.Fatalf("WWW-Authenticate = %q", got)
+	}
+
+	// A credencial só acessa as rotas listadas
+	rec, _, ok = serveRouteAuth(h.authenticateHMAC, &model.Route{Path: "/api/faturas"},
+		newHMACRequest(credential.Identifier, secret, "nonce-2", "/api/faturas", body))
+	if ok || rec.Code != http.StatusForbidden {
+		t.Fatalf("rota fora do escopo: status = %d, aceita = %v; esperado 403", rec.Code, ok)
+	}
+}
+
+func TestAuthenticateHMACBodyLimit(t *testing.T) {
+	h, service := newCredentialHandler(t)
+	credential, secret, err := service.Create(context.Background(), auth.CredentialRequest{Name: "parceiro", Type: model.CredentialHMAC})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	route := &model.Route{Path: "/api/pedidos"}
+
+	body := bytes.Repeat([]byte("a"), 1025)
+	rec, _, ok := serveRouteAuth(h.authenticateHMAC, route,
+		newHMACRequest(credential.Identifier, secret, "nonce-1", "/api/pedidos", body))
+	if ok || rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("status = %d, aceita = %v; esperado 413", rec.Code, ok)
+	}
+	if !strings.Contains(rec.Body.String(), `"max_bytes":1024`) {
+		t.Fatalf("corpo = %s, esperado o limite em bytes", rec.Body.String())
+	}
+
+	// No limite, o corpo é aceito
+	rec, _, ok = serveRouteAuth(h.authenticateHMAC, route,
+		newHMACRequest(credential.Identifier, secret, "nonce-2", "/api/pedidos", body[:1024]))
+	if !ok {
+		t.Fatalf("corpo no limite recusado: %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestAuthenticateBasicRoute(t *testing.T) {
+	h, service := newCredentialHandler(t)
+	if _, _, err := service.Create(context.Background(), auth.CredentialRequest{
+		Name: "parceiro", Type: model.CredentialBasic, Username: "parceiro.a", Password: "senha-forte",
+		Routes: []string{"/api/pedidos"},
+	}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	newRequest := func(target, password string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.SetBasicAuth("parceiro.a", password)
+		return req
+	}
+
+	rec, req, ok := serveRouteAuth(h.authenticateBasic, &model.Route{Path: "/api/pedidos"}, newRequest("/api/pedidos", "senha-forte"))
+	if !ok {
+		t.Fatalf("credencial válida recusada: %d %s", rec.Code, rec.Body.String())
+	}
+	if req.Header.Get("Authorization") != "" {
+		t.Fatal("cabeçalho Authorization repassado ao backend")
+	}
+
+	rec, _, ok = serveRouteAuth(h.authenticateBasic, &model.Route{Path: "/api/pedidos"}, newRequest("/api/pedidos", "senha-errada"))
+	if ok || rec.Code != http.StatusUnauthorized || rec.Header().Get("WWW-Authenticate") != `Basic realm="parceiros"` {
+		t.Fatalf("senha errada: status = %d, WWW-Authenticate = %q", rec.Code, rec.Header().Get("WWW-Authenticate"))
+	}
+
+	rec, _, ok = serveRouteAuth(h.authenticateBasic, &model.Route{Path: "/api/faturas"}, newRequest("/api/faturas", "senha-forte"))
+	if ok || rec.Code != http.StatusForbidden {
+		t.Fatalf("rota fora do escopo: status = %d, aceita = %v; esperado 403", rec.Code, ok)
+	}
+}
@@ -0,0 +1,87 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/app/route"
+	"github.com/diillson/api-gateway-go/pkg/tenant"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	// routeEventStreamBuffer é a capacidade do canal de cada acompanhamento;
+	// eventos excedentes de clientes lentos são descartados
+	routeEventStreamBuffer = 64
+
+	// routeEventKeepAlive mantém a conexão aberta em proxies com timeout de inatividade
+	routeEventKeepAlive = 30 * time.Second
+)
+
+// RouteEventSource fornece as alterações de rotas em tempo real
+type RouteEventSource interface {
+	Subscribe(buffer int) (<-chan route.Event, func())
+}
+
+// RouteEventsHandler transmite as alterações de rotas por Server-Sent Events
+type RouteEventsHandler struct {
+	source RouteEventSource
+	logger *zap.Logger
+}
+
+// NewRouteEventsHandler cria o handler do fluxo de alterações de rotas
+func NewRouteEventsHandler(source RouteEventSource, logger *zap.Logger) *RouteEventsHandler {
+	return &RouteEventsHandler{
+		source: source,
+		logger: logger,
+	}
+}
+
+// RegisterRoutes registra o endpoint no grupo informado
+func (h *RouteEventsHandler) RegisterRoutes(group *gin.RouterGroup) {
+	group.GET("/events/routes", h.Stream)
+}
+
+// Stream envia as alterações de rotas do tenant da requisição como eventos
+// SSE (route.created, route.updated, route.deleted) até o cliente desconectar.
+// Clientes que perderem eventos devem se ressincronizar com a listagem de rotas.
+func (h *RouteEventsHandler) Stream(c *gin.Context) {
+	ctx := c.Request.Context()
+	tenantID := tenant.FromContext(ctx)
+
+	events, cancel := h.source.Subscribe(routeEventStreamBuffer)
+	defer cancel()
+
+	h.logger.Info("Acompanhamento de alterações de rotas iniciado", zap.String("ip", c.ClientIP()))
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	keepAlive := time.NewTicker(routeEventKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-keepAlive.C:
+			if _, err := c.Writer.WriteString(": keep-alive\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if event.Tenant != tenantID {
+				continue
+			}
+			c.SSEvent("route."+string(event.Type), event.Redacted())
+			c.Writer.Flush()
+		}
+	}
+}
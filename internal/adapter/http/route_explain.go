@@ -0,0 +1,182 @@
+package http
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ExplainRouteRequest descreve a requisição cujo roteamento será explicado
+type ExplainRouteRequest struct {
+	Method    string            `json:"method" binding:"required"`
+	Host      string            `json:"host"`
+	Path      string            `json:"path" binding:"required"` // Caminho, opcionalmente com a query string
+	Headers   map[string]string `json:"headers"`
+	Country   string            `json:"country"`   // País do cliente, no lugar da geolocalização
+	Continent string            `json:"continent"` // Continente do cliente
+}
+
+// routePolicy é uma etapa do atendimento que se aplica à rota escolhida
+type routePolicy struct {
+	Stage  string      `json:"stage"`
+	Detail interface{} `json:"detail,omitempty"`
+}
+
+// ExplainRoute responde por que uma requisição corresponde (ou não) a uma
+// rota: as candidatas avaliadas, a escolhida e as variantes, as políticas
+// aplicadas na ordem do atendimento e os backends resolvidos. Nenhuma
+// requisição é enviada aos backends.
+func (h *Handler) ExplainRoute(c *gin.Context) {
+	var req ExplainRouteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+	target, err := url.ParseRequestURI(req.Path)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Caminho inválido: " + err.Error()})
+		return
+	}
+
+	header := make(http.Header, len(req.Headers))
+	for name, value := range req.Headers {
+		header.Set(name, value)
+	}
+	info := model.RequestInfo{
+		Host:      req.Host,
+		Method:    req.Method,
+		Header:    header,
+		Country:   req.Country,
+		Continent: req.Continent,
+	}
+
+	explanation, err := h.routeService.ExplainMatch(c.Request.Context(), target.Path, info)
+	if err != nil {
+		h.logger.Error("Falha ao explicar roteamento", zap.String("path", target.Path), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Falha ao carregar as rotas"})
+		return
+	}
+
+	response := gin.H{
+		"request": gin.H{
+			"method":    req.Method,
+			"host":      req.Host,
+			"path":      target.Path,
+			"country":   req.Country,
+			"continent": req.Continent,
+		},
+		"matched":    explanation.Route != nil,
+		"candidates": explanation.Candidates,
+	}
+	if explanation.Route == nil {
+		response["outcome"] = "404: nenhuma rota corresponde ao caminho e às condições"
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	route := explanation.Route
+	now := time.Now()
+	response["route"] = route.Redacted()
+	response["variant"] = explanation.Variant
+	if len(explanation.Variants) > 0 {
+		response["variants"] = explanation.Variants
+	}
+	response["outcome"] = explainOutcome(route, req.Method, now)
+	response["policies"] = routePolicies(route)
+
+	targets := make([]gin.H, 0, len(route.Upstreams()))
+	for _, upstream := range route.Upstreams() {
+		targets = append(targets, gin.H{
+			"url":       upstream.URL,
+			"weight":    upstream.EffectiveWeight(),
+			"available": h.routeService.IsTargetAvailable(route.Path, upstream.URL),
+		})
+	}
+	upstream := gin.H{
+		"protocol":      route.Protocol,
+		"loadBalancing": route.Balancing(),
+		"targets":       targets,
+	}
+	if route.PathRewrite != nil {
+		upstream["path"] = route.PathRewrite.Rewrite(route.Path, target.Path)
+	}
+	if route.Canary != nil {
+		upstream["canary"] = route.Canary
+	}
+	response["upstream"] = upstream
+
+	c.JSON(http.StatusOK, response)
+}
+
+// explainOutcome antecipa a resposta das verificações que independem do
+// consumidor, na ordem de ServeAPI
+func explainOutcome(route *model.Route, method string, now time.Time) string {
+	switch {
+	case !route.IsActive:
+		return "503: rota inativa"
+	case !route.ActiveAt(now):
+		return "404: fora da ativação agendada"
+	case route.Deprecation != nil && route.Deprecation.Enforce && route.Deprecation.Sunsetted(now):
+		return "410: rota desativada após o sunset"
+	case route.Maintenance != nil:
+		return "resposta de manutenção, sem consultar o backend"
+	case !route.IsMethodAllowed(method):
+		return "405: método não permitido"
+	case route.Stub != nil:
+		return "resposta simulada, sem consultar o backend"
+	default:
+		return "encaminhada ao backend, se aprovada pelas políticas da rota"
+	}
+}
+
+// routePolicies lista as políticas configuradas na rota, na ordem em que são
+// aplicadas por ServeAPI
+func routePolicies(route *model.Route) []routePolicy {
+	var policies []routePolicy
+	add := func(enabled bool, stage string, detail interface{}) {
+		if enabled {
+			policies = append(policies, routePolicy{Stage: stage, Detail: detail})
+		}
+	}
+
+	add(route.ActiveFrom != "" || route.ActiveUntil != "", "activation",
+		gin.H{"activeFrom": route.ActiveFrom, "activeUntil": route.ActiveUntil})
+	add(route.IPFilter != nil, "ipFilter", nil)
+	add(route.GeoFilter != nil, "geoFilter", nil)
+	add(route.CORS != nil, "cors", nil)
+	add(route.Deprecation != nil, "deprecation", route.Deprecation)
+	add(route.Maintenance != nil, "maintenance", nil)
+	add(len(route.Methods) > 0, "methods", route.Methods)
+	add(route.RequestBody != nil, "requestBody", route.RequestBody)
+	add(route.Auth != nil, "auth", route.Auth)
+	add(route.Authorization != nil, "authorization", route.Authorization)
+	add(route.ExternalAuthz != nil, "externalAuthz", nil)
+	add(route.FeatureFlag != nil, "featureFlag", route.FeatureFlag)
+	add(route.RequestFilter != nil, "requestFilter", nil)
+	add(route.AccessSchedule != nil, "accessSchedule", nil)
+	add(len(route.RequiredHeaders) > 0, "requiredHeaders", route.RequiredHeaders)
+	add(route.Validation != nil, "validation", nil)
+	add(route.GraphQL != nil, "graphql", nil)
+	add(route.HeaderTransforms != nil, "headerTransforms", nil)
+	add(route.BodyTransforms != nil, "bodyTransforms", nil)
+	add(route.Hooks != nil, "hooks", nil)
+	for _, plugin := range route.Plugins {
+		add(true, "plugin", plugin.Name)
+	}
+	add(route.Compression != nil, "compression", nil)
+	add(route.Idempotency != nil, "idempotency", nil)
+	add(route.ResponseCache != nil, "responseCache", nil)
+	add(route.Stub != nil, "stub", nil)
+	add(route.Composite != nil, "composite", nil)
+	add(route.Bulkhead != nil, "bulkhead", nil)
+	add(route.CircuitBreaker != nil, "circuitBreaker", nil)
+	add(route.Retry != nil, "retry", nil)
+	add(route.Hedging != nil, "hedging", nil)
+	add(route.Mirror != nil, "mirror", nil)
+	add(route.Errors != nil, "errors", nil)
+	return policies
+}
@@ -1,12 +1,27 @@
 package http
 
 import (
-	"context"
 	"fmt"
 	"github.com/diillson/api-gateway-go/internal/adapter/proxy"
+	"github.com/diillson/api-gateway-go/internal/app/auth"
+	"github.com/diillson/api-gateway-go/internal/app/idempotency"
+	"github.com/diillson/api-gateway-go/internal/app/quota"
+	"github.com/diillson/api-gateway-go/internal/app/recording"
 	"github.com/diillson/api-gateway-go/internal/app/route"
+	"github.com/diillson/api-gateway-go/internal/app/routestats"
 	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/internal/infra/extauthz"
+	"github.com/diillson/api-gateway-go/internal/infra/featureflag"
 	"github.com/diillson/api-gateway-go/internal/infra/metrics"
+	"github.com/diillson/api-gateway-go/internal/infra/middleware"
+	"github.com/diillson/api-gateway-go/internal/infra/openapi"
+	"github.com/diillson/api-gateway-go/internal/infra/usage"
+	"github.com/diillson/api-gateway-go/internal/infra/webhook"
+	"github.com/diillson/api-gateway-go/pkg/config"
+	"github.com/diillson/api-gateway-go/pkg/geoip"
+	"github.com/diillson/api-gateway-go/pkg/ratelimit"
+	"github.com/diillson/api-gateway-go/pkg/reqtemplate"
+	"github.com/diillson/api-gateway-go/pkg/tenant"
 	"github.com/gin-gonic/gin"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -15,6 +30,8 @@ import (
 	"go.uber.org/zap"
 	"net/http"
 	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 )
 
@@ -52,6 +69,10 @@ func (h *RouteHandler) RegisterAPI(c *gin.Context) {
 	}
 
 	if err := h.routeService.AddRoute(c.Request.Context(), &route); err != nil {
+		if isRouteConflict(err) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		h.logger.Error("Falha ao registrar API", zap.Error(err))
 		if h.metrics != nil {
 			h.metrics.RequestError(c.FullPath(), c.Request.Method, "add_route_error")
@@ -75,7 +96,12 @@ func (h *RouteHandler) ListAPIs(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, routes)
+	redacted := make([]*model.Route, 0, len(routes))
+	for _, route := range routes {
+		redacted = append(redacted, route.Redacted())
+	}
+
+	c.JSON(http.StatusOK, redacted)
 }
 
 // UpdateAPI atualiza uma rota existente
@@ -92,6 +118,10 @@ func (h *RouteHandler) UpdateAPI(c *gin.Context) {
 	}
 
 	if err := h.routeService.UpdateRoute(c.Request.Context(), &route); err != nil {
+		if isRouteConflict(err) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		h.logger.Error("Falha ao atualizar API", zap.Error(err))
 		if h.metrics != nil {
 			h.metrics.RequestError(c.FullPath(), c.Request.Method, "update_route_error")
@@ -156,19 +186,85 @@ type Handler struct {
 	logger        *zap.Logger
 	routeService  *route.Service
 	metrics       *metrics.APIMetrics
+	usage         *usage.Aggregator
+	routeStats    *routestats.Service
+	oidc          *auth.OIDCService
+	authService   *auth.AuthService
+	credentials   *auth.CredentialService
+	extAuthz      *extauthz.Authorizer
+	quotas        *quota.Service
+	idempotency   *idempotency.Store
+	recorder      *recording.Recorder
+	featureFlags  featureflag.Provider
+	hooks         *webhook.HookClient
+	openAPI       *openapi.Aggregator
+	compression   atomic.Pointer[config.CompressionConfig] // trocada nas recargas da configuração
+	// rateLimitHeaders é o estilo global dos cabeçalhos de limite (ratelimit.headers)
+	rateLimitHeaders atomic.Pointer[string]
 }
 
 func NewHandler(routeService *route.Service, proxy *proxy.ReverseProxy, db DatabaseChecker, cache CacheChecker, logger *zap.Logger) *Handler {
 	routeHandler := NewRouteHandler(routeService, logger)
 	healthChecker := NewHealthChecker(routeService, db, cache, logger)
 
-	return &Handler{
+	h := &Handler{
 		routeHandler:  routeHandler,
 		healthChecker: healthChecker,
 		proxy:         proxy,
 		logger:        logger,
 		routeService:  routeService,
 	}
+	h.SetRateLimitHeaders(ratelimit.HeadersBoth)
+	return h
+}
+
+// SetUsageAggregator configura o agregador de uso para atribuição de custos
+func (h *Handler) SetUsageAggregator(aggregator *usage.Aggregator) {
+	h.usage = aggregator
+}
+
+// SetRouteStats configura as estatísticas por rota em janelas de tempo
+func (h *Handler) SetRouteStats(stats *routestats.Service) {
+	h.routeStats = stats
+}
+
+// SetQuotas configura a contabilização das cotas de uso dos consumidores
+func (h *Handler) SetQuotas(quotas *quota.Service) {
+	h.quotas = quotas
+}
+
+// SetIdempotency configura o armazenamento das respostas das requisições com
+// Idempotency-Key
+func (h *Handler) SetIdempotency(store *idempotency.Store) {
+	h.idempotency = store
+}
+
+// SetRecorder configura a gravação das requisições das rotas com política de
+// gravação
+func (h *Handler) SetRecorder(recorder *recording.Recorder) {
+	h.recorder = recorder
+}
+
+// SetFeatureFlags configura o provedor das feature flags das rotas
+func (h *Handler) SetFeatureFlags(provider featureflag.Provider) {
+	h.featureFlags = provider
+}
+
+// SetOpenAPI configura o catálogo OpenAPI usado na validação das requisições
+// pelas operações dos backends
+func (h *Handler) SetOpenAPI(aggregator *openapi.Aggregator) {
+	h.openAPI = aggregator
+}
+
+// SetCompression configura a compressão das respostas das rotas
+func (h *Handler) SetCompression(cfg config.CompressionConfig) {
+	h.compression.Store(&cfg)
+}
+
+// SetRateLimitHeaders configura o estilo global dos cabeçalhos de limite nas
+// respostas das rotas
+func (h *Handler) SetRateLimitHeaders(style string) {
+	h.rateLimitHeaders.Store(&style)
 }
 
 // SetMetrics configura as métricas para o handler e seus componentes
@@ -177,6 +273,31 @@ func (h *Handler) SetMetrics(metrics *metrics.APIMetrics) {
 	h.routeHandler.SetMetrics(metrics)
 }
 
+// AddDatabaseShard registra um shard de banco de dados nos health checks
+func (h *Handler) AddDatabaseShard(name string, db DatabaseChecker) {
+	h.healthChecker.AddDatabaseShard(name, db)
+}
+
+// SetHealthTimeout define o prazo das verificações do readiness
+func (h *Handler) SetHealthTimeout(timeout time.Duration) {
+	h.healthChecker.SetTimeout(timeout)
+}
+
+// AddCriticalUpstream inclui os backends da rota nas verificações do readiness
+func (h *Handler) AddCriticalUpstream(path string) {
+	h.healthChecker.AddCriticalUpstream(path)
+}
+
+// RequireWarmRoutes condiciona o readiness à carga inicial das rotas
+func (h *Handler) RequireWarmRoutes() {
+	h.healthChecker.RequireWarmRoutes()
+}
+
+// SetDatabaseCritical define se a falha do banco de dados afeta o readiness
+func (h *Handler) SetDatabaseCritical(critical bool) {
+	h.healthChecker.SetDatabaseCritical(critical)
+}
+
 func (h *Handler) HealthCheck(c *gin.Context) {
 	h.healthChecker.LivenessCheck(c)
 }
@@ -209,10 +330,43 @@ func (h *Handler) DiagnoseRoute(c *gin.Context) {
 	h.routeHandler.DiagnoseRoute(c)
 }
 
+func (h *Handler) UpstreamHealth(c *gin.Context) {
+	h.routeHandler.UpstreamHealth(c)
+}
+
 func (h *Handler) DeleteAPI(c *gin.Context) {
 	h.routeHandler.DeleteAPI(c)
 }
 
+// PurgeResponseCache descarta as respostas armazenadas em cache para a rota
+// informada em 'path'
+func (h *Handler) PurgeResponseCache(c *gin.Context) {
+	path := c.Query("path")
+	if path == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parâmetro 'path' é obrigatório"})
+		return
+	}
+
+	route, err := h.routeService.GetRouteByPath(c.Request.Context(), path)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Rota não encontrada", "path": path})
+		return
+	}
+
+	if err := h.proxy.InvalidateResponses(c.Request.Context(), route.Path); err != nil {
+		h.logger.Error("Falha ao invalidar cache de respostas",
+			zap.String("path", route.Path),
+			zap.Error(err))
+		if h.metrics != nil {
+			h.metrics.RequestError(c.FullPath(), c.Request.Method, "purge_response_cache_error")
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Falha ao invalidar cache de respostas"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Cache de respostas invalidado com sucesso", "route": route.Path})
+}
+
 func (h *Handler) GetMetrics(c *gin.Context) {
 	h.routeHandler.GetMetrics(c)
 }
@@ -251,6 +405,22 @@ func (h *Handler) ServeAPI(c *gin.Context) {
 		zap.String("raw_path", c.Request.URL.RawPath),
 		zap.String("raw_query", c.Request.URL.RawQuery))
 
+	// Condições de host, método e cabeçalhos das rotas são avaliadas na busca.
+	// Em preflights CORS, vale o método que será usado na requisição.
+	method := c.Request.Method
+	if isPreflight(c.Request) {
+		method = strings.ToUpper(c.GetHeader("Access-Control-Request-Method"))
+	}
+	location := geoip.FromContext(ctx)
+	ctx = route.WithRequestInfo(ctx, model.RequestInfo{
+		Host:      c.Request.Host,
+		Method:    method,
+		Header:    c.Request.Header,
+		Country:   location.Country,
+		Continent: location.Continent,
+	})
+	c.Request = c.Request.WithContext(ctx)
+
 	route, err := h.routeService.GetRouteByPath(ctx, path)
 	if err != nil {
 		h.logger.Error("Rota não encontrada",
@@ -262,14 +432,36 @@ func (h *Handler) ServeAPI(c *gin.Context) {
 		}
 
 		// Verifique se é um erro específico de rota não encontrada
-		c.JSON(http.StatusNotFound, gin.H{
-			"error":   "API not found",
-			"details": "A rota requisitada não está registrada no API Gateway",
-			"path":    path,
-		})
+		writeRouteNotFound(c, path)
 		return
 	}
 
+	// Caminho registrado da rota, usado pelo log de acesso
+	c.Set("route", route.Path)
+
+	// Cabeçalhos dos limites já aplicados (ex.: faixa da chave de API) no
+	// estilo próprio da rota
+	if route.RateLimitHeaders != "" {
+		middleware.ApplyRateLimitHeaders(c, route.RateLimitHeaders)
+	}
+
+	// Registrar a requisição nas métricas da rota ao final do atendimento,
+	// inclusive quando rejeitada pelo gateway
+	received := time.Now()
+	defer func() {
+		duration := time.Since(received)
+		if h.metrics != nil {
+			h.metrics.RouteRequestCompleted(route.Tenant, route.Path, c.Request.Method, c.Writer.Status(), duration)
+		}
+		if h.routeStats != nil {
+			h.routeStats.Record(route.Tenant, route.Path, c.Writer.Status(), duration)
+		}
+	}()
+
+	// Gravar a requisição e a resposta para reprodução, conforme a amostragem
+	finishRecording := h.beginRecording(c, route)
+	defer finishRecording()
+
 	// Logar a rota encontrada
 	h.logger.Info("Rota encontrada",
 		zap.String("path", route.Path),
@@ -288,6 +480,34 @@ func (h *Handler) ServeAPI(c *gin.Context) {
 		return
 	}
 
+	// Rotas agendadas só respondem dentro da sua ativação
+	if !h.checkActivation(c, route, span) {
+		return
+	}
+
+	// Restringir as origens antes de qualquer outra verificação da rota
+	if !h.filterSource(c, route, span) {
+		return
+	}
+	if !h.filterCountry(c, route, span) {
+		return
+	}
+
+	// Responder aos preflights e definir os cabeçalhos CORS da rota
+	if !h.applyCORS(c, route, span) {
+		return
+	}
+
+	// Informar a descontinuação da rota e recusar as desativadas
+	if !h.applyDeprecation(c, route, span) {
+		return
+	}
+
+	// Rotas em manutenção respondem sem consultar o backend
+	if !h.serveMaintenance(c, route, span) {
+		return
+	}
+
 	// Verificar se o método é permitido
 	if !route.IsMethodAllowed(c.Request.Method) {
 		h.logger.Warn("Método não permitido",
@@ -306,6 +526,66 @@ func (h *Handler) ServeAPI(c *gin.Context) {
 		return
 	}
 
+	// Recusar corpos grandes demais ou de tipos não aceitos pela rota
+	if !h.limitRequestBody(c, route, span) {
+		return
+	}
+
+	// Autenticar o consumidor conforme a política da rota
+	if !h.authenticateRoute(c, route, span) {
+		return
+	}
+
+	// Aplicar as regras de autorização da rota ao consumidor autenticado
+	if !h.authorizeRoute(c, route, span) {
+		return
+	}
+
+	// Delegar a decisão ao serviço de autorização externo da rota
+	if !h.authorizeExternal(c, route, span) {
+		return
+	}
+
+	// Habilitar a rota conforme a feature flag avaliada para o consumidor
+	if !h.checkFeatureFlag(c, route, span) {
+		return
+	}
+
+	// Registrar o uso das rotas descontinuadas por consumidor
+	h.recordDeprecatedUsage(c, route)
+
+	// Aplicar as regras do filtro de requisições, que podem desviar a rota
+	route, allowed := h.filterRequest(c, route, span)
+	if !allowed {
+		return
+	}
+
+	// Verificar janelas de horário de acesso da rota
+	if route.AccessSchedule != nil {
+		schedule := route.AccessSchedule.For(consumerIdentifiers(c)...)
+		if allowed, reason := schedule.Check(time.Now()); !allowed {
+			h.logger.Warn("Acesso fora da janela permitida",
+				zap.String("path", path),
+				zap.String("reason", reason))
+
+			if h.metrics != nil {
+				h.metrics.RequestError(path, c.Request.Method, "outside_access_window")
+			}
+
+			span.SetAttributes(attribute.Bool("access_schedule.denied", true))
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":   "Acesso fora da janela permitida",
+				"details": reason,
+			})
+			return
+		}
+	}
+
+	// Contabilizar a requisição na cota diária e mensal do consumidor
+	if !h.enforceQuota(c, route, span) {
+		return
+	}
+
 	// Verificar cabeçalhos obrigatórios
 	if len(route.RequiredHeaders) > 0 {
 		headers := make(map[string]string)
@@ -332,6 +612,49 @@ func (h *Handler) ServeAPI(c *gin.Context) {
 		}
 	}
 
+	// Validar o corpo e a query string com os schemas da rota
+	if !h.validateRequest(c, route, span) {
+		return
+	}
+
+	// Analisar as operações das rotas GraphQL e aplicar os limites de custo
+	graphQLOperations, ok := h.inspectGraphQL(c, route, span)
+	if !ok {
+		return
+	}
+
+	// Contexto de negócio da rota nos traces e no baggage propagado aos backends
+	templateData := requestTemplateData(c, route)
+	ctx = h.applyRouteTelemetry(ctx, route, templateData, span)
+	c.Request = c.Request.WithContext(ctx)
+
+	// Alterar os cabeçalhos da requisição e da resposta conforme a rota
+	h.applyHeaderTransforms(c, route, templateData)
+
+	// Adaptar o corpo JSON da requisição conforme a rota
+	if !h.transformRequestBody(c, route, templateData, span) {
+		return
+	}
+
+	// Consultar os webhooks pré-requisição da rota
+	if !h.runPreHooks(c, route, span) {
+		return
+	}
+
+	// Executar os plugins configurados na rota
+	if !h.runPlugins(c, route, span) {
+		return
+	}
+
+	// Atribuir o uso da requisição para showback de custos
+	attribution := usage.Resolve(route, templateData)
+	c.Set(usage.ContextKey, attribution)
+	span.SetAttributes(
+		attribute.String("cost.team", attribution.Team),
+		attribute.String("cost.product", attribution.Product),
+		attribute.String("cost.consumer", attribution.Consumer),
+	)
+
 	// Registrar a chamada da rota nas métricas
 	if h.metrics != nil {
 		h.metrics.RequestStarted(path, c.Request.Method)
@@ -342,7 +665,27 @@ func (h *Handler) ServeAPI(c *gin.Context) {
 	h.logger.Info("Encaminhando requisição para o proxy",
 		zap.String("target", route.ServiceURL))
 
-	if err := h.proxy.ProxyRequest(route, c.Writer, c.Request); err != nil {
+	// Comprimir a resposta conforme o Accept-Encoding do cliente
+	finishCompression := h.compressResponse(c, route)
+	// Repetir a resposta armazenada para a mesma Idempotency-Key
+	proceed, finishIdempotency := h.beginIdempotency(c, route, span)
+	switch {
+	case !proceed:
+		// Já respondida pelo gateway (resposta repetida ou chave em uso)
+	case route.Stub != nil:
+		// Resposta simulada no lugar do backend
+		h.serveStub(c, route, templateData, span)
+	case route.Composite != nil:
+		// Respostas de vários backends combinadas pelo gateway
+		err = h.proxy.Compose(route, c.Writer, c.Request, templateData)
+	default:
+		err = h.proxy.ProxyRequest(route, c.Writer, c.Request)
+	}
+	finishIdempotency()
+	finishCompression()
+	h.notifyPostHooks(c, route, start)
+	h.recordGraphQL(route, graphQLOperations, time.Since(start))
+	if err != nil {
 		h.logger.Error("Erro ao encaminhar requisição",
 			zap.String("path", path),
 			zap.Error(err))
@@ -369,11 +712,17 @@ func (h *Handler) ServeAPI(c *gin.Context) {
 		h.metrics.RequestCompleted(path, c.Request.Method,
 			strconv.Itoa(c.Writer.Status()), duration,
 			int(c.Request.ContentLength), c.Writer.Size())
+		h.metrics.RecordCost(attribution.Team, attribution.Product, attribution.Consumer,
+			duration, int(c.Request.ContentLength), c.Writer.Size())
+	}
+	if h.usage != nil {
+		h.usage.Record(route.Path, tenant.FromContext(ctx), attribution, c.Writer.Status(),
+			duration, int(c.Request.ContentLength), c.Writer.Size())
 	}
 
 	// Atualizar métricas da rota de forma assíncrona
 	go func() {
-		if err := h.routeService.UpdateMetrics(context.Background(),
+		if err := h.routeService.UpdateMetrics(tenant.Detach(ctx),
 			path, 1, int64(duration)); err != nil {
 			h.logger.Error("Erro ao atualizar métricas", zap.Error(err))
 		}
@@ -390,6 +739,22 @@ func (h *RouteHandler) ClearCache(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Cache limpo com sucesso"})
 }
 
+// UpstreamHealth lista o estado de saúde dos backends verificados ativamente,
+// opcionalmente filtrado pela rota informada em ?path=
+func (h *RouteHandler) UpstreamHealth(c *gin.Context) {
+	targets := h.routeService.UpstreamHealth(c.Query("path"))
+
+	summary := map[string]int{}
+	for _, target := range targets {
+		summary[string(target.Status)]++
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"targets": targets,
+		"summary": summary,
+	})
+}
+
 // DiagnoseRoute diagnostica problemas em uma rota específica
 func (h *RouteHandler) DiagnoseRoute(c *gin.Context) {
 	path := c.Query("path")
@@ -423,42 +788,123 @@ func (h *RouteHandler) DiagnoseRoute(c *gin.Context) {
 	if !route.IsActive {
 		c.JSON(http.StatusOK, gin.H{
 			"status":     "inactive",
-			"route":      route,
+			"route":      route.Redacted(),
 			"message":    "A rota existe, mas está inativa",
 			"suggestion": "Ative a rota para que possa ser acessada",
 		})
 		return
 	}
 
-	// Testar conectividade com o serviço de destino
+	// Testar conectividade com cada alvo da rota
 	client := http.Client{Timeout: 5 * time.Second}
-	req, err := http.NewRequest("GET", route.ServiceURL, nil)
-	if err != nil {
-		c.JSON(http.StatusOK, gin.H{
-			"status":     "url_invalid",
-			"route":      route,
-			"error":      err.Error(),
-			"suggestion": "A URL do serviço parece ser inválida, corrija-a",
-		})
-		return
+	upstreams := route.AllUpstreams()
+	targets := make([]gin.H, 0, len(upstreams))
+	status := "ok"
+	for _, target := range upstreams {
+		result := gin.H{"url": target.URL}
+		targets = append(targets, result)
+
+		req, err := http.NewRequest("GET", target.URL, nil)
+		if err != nil {
+			result["status"] = "url_invalid"
+			result["error"] = err.Error()
+			status = "url_invalid"
+			continue
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			result["status"] = "service_unreachable"
+			result["error"] = err.Error()
+			if status == "ok" {
+				status = "service_unreachable"
+			}
+			continue
+		}
+		resp.Body.Close()
+
+		result["status"] = "ok"
+		result["service_status"] = resp.StatusCode
 	}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		c.JSON(http.StatusOK, gin.H{
-			"status":     "service_unreachable",
-			"route":      route,
-			"error":      err.Error(),
-			"suggestion": "O serviço de destino não está acessível. Verifique se está online e se a URL está correta.",
-		})
-		return
+	response := gin.H{
+		"status":  status,
+		"route":   route.Redacted(),
+		"targets": targets,
+	}
+	switch status {
+	case "url_invalid":
+		response["suggestion"] = "A URL do serviço parece ser inválida, corrija-a"
+	case "service_unreachable":
+		response["suggestion"] = "O serviço de destino não está acessível. Verifique se está online e se a URL está correta."
+	default:
+		response["message"] = "A rota está configurada corretamente e o serviço de destino está acessível"
+	}
+	if len(targets) == 1 {
+		for key, value := range targets[0] {
+			if key != "url" && key != "status" {
+				response[key] = value
+			}
+		}
 	}
-	defer resp.Body.Close()
 
-	c.JSON(http.StatusOK, gin.H{
-		"status":         "ok",
-		"route":          route,
-		"service_status": resp.StatusCode,
-		"message":        "A rota está configurada corretamente e o serviço de destino está acessível",
-	})
+	c.JSON(http.StatusOK, response)
+}
+
+// consumerName retorna o nome do consumidor autenticado, se houver
+func consumerName(c *gin.Context) string {
+	ids := consumerIdentifiers(c)
+	if len(ids) < 2 {
+		return ""
+	}
+	if ids[1] != "" {
+		return ids[1]
+	}
+	return ids[0]
+}
+
+// requestTemplateData reúne os dados da requisição usados nas expressões da rota
+func requestTemplateData(c *gin.Context, route *model.Route) *reqtemplate.Data {
+	headers := make(map[string]string, len(c.Request.Header))
+	for name, values := range c.Request.Header {
+		if len(values) > 0 {
+			headers[name] = values[0]
+		}
+	}
+
+	var claims map[string]interface{}
+	if value, exists := c.Get("claims"); exists {
+		claims, _ = value.(map[string]interface{})
+	}
+
+	labels := route.Labels
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	return &reqtemplate.Data{
+		Route:    route.Path,
+		Method:   c.Request.Method,
+		Path:     c.Request.URL.Path,
+		ClientIP: clientIP(c),
+		Tenant:   tenant.FromContext(c.Request.Context()),
+		Consumer: consumerName(c),
+		Labels:   labels,
+		Headers:  headers,
+		Params:   model.PathParams(route.Path, c.Request.URL.Path),
+		Claims:   claims,
+	}
+}
+
+// consumerIdentifiers retorna os identificadores do consumidor autenticado, se houver
+func consumerIdentifiers(c *gin.Context) []string {
+	value, exists := c.Get("user")
+	if !exists {
+		return nil
+	}
+	user, ok := value.(*model.User)
+	if !ok || user == nil {
+		return nil
+	}
+	return []string{user.ID, user.Username}
 }
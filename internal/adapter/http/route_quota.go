@@ -0,0 +1,117 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/app/auth"
+	"github.com/diillson/api-gateway-go/internal/app/quota"
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/internal/infra/middleware"
+	"github.com/diillson/api-gateway-go/pkg/ratelimit"
+	"github.com/diillson/api-gateway-go/pkg/tenant"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// enforceQuota contabiliza a requisição na cota do consumidor autenticado e a
+// recusa quando a cota diária ou mensal foi excedida. Requisições anônimas não
+// têm cota. Retorna false quando a requisição foi recusada.
+func (h *Handler) enforceQuota(c *gin.Context, route *model.Route, span trace.Span) bool {
+	if h.quotas == nil {
+		return true
+	}
+	consumer, ok := quotaConsumer(c)
+	if !ok {
+		return true
+	}
+
+	usage, err := h.quotas.Consume(c.Request.Context(), consumer)
+	if err != nil {
+		h.logger.Error("erro ao contabilizar cota do consumidor",
+			zap.String("consumer", consumer.ID), zap.Error(err))
+		return true // Em caso de erro, permite a requisição
+	}
+
+	exceeded, isExceeded := usage.Exceeded()
+	reported, ok := usage.Tightest()
+	if isExceeded {
+		reported, ok = exceeded, true
+	}
+	if ok {
+		middleware.AddRateLimitState(c, h.rateLimitStyle(route), ratelimit.State{
+			Legacy:    "X-Quota",
+			Limit:     reported.Limit,
+			Remaining: reported.Remaining(),
+			ResetAt:   reported.ResetAt,
+			Window:    quotaWindow(reported),
+		})
+	}
+	if !isExceeded {
+		return true
+	}
+
+	path := c.Request.URL.Path
+	h.logger.Warn("Cota do consumidor excedida",
+		zap.String("path", path),
+		zap.String("consumer", consumer.ID),
+		zap.String("period", exceeded.Period))
+	span.SetAttributes(
+		attribute.Bool("quota.exceeded", true),
+		attribute.String("quota.period", exceeded.Period),
+	)
+	if h.metrics != nil {
+		h.metrics.RateLimitExceeded(tenant.FromContext(c.Request.Context()), route.Path, c.Request.Method, "quota_"+exceeded.Period)
+	}
+
+	retryAfter := ratelimit.RetryAfter(exceeded.ResetAt, time.Now())
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	c.JSON(http.StatusTooManyRequests, gin.H{
+		"error":       "Cota de uso do consumidor excedida",
+		"period":      exceeded.Period,
+		"limit":       exceeded.Limit,
+		"reset_at":    exceeded.ResetAt.Format(time.RFC3339),
+		"retry_after": retryAfter,
+	})
+	return false
+}
+
+// quotaWindow retorna a duração do período da cota: um dia ou o mês que
+// termina no reinício
+func quotaWindow(p quota.PeriodUsage) time.Duration {
+	if p.Period == "monthly" {
+		return p.ResetAt.Sub(p.ResetAt.AddDate(0, -1, 0))
+	}
+	return 24 * time.Hour
+}
+
+// rateLimitStyle retorna o estilo dos cabeçalhos de limite da rota, ou o
+// global quando a rota não define um
+func (h *Handler) rateLimitStyle(route *model.Route) string {
+	if route.RateLimitHeaders != "" {
+		return route.RateLimitHeaders
+	}
+	return *h.rateLimitHeaders.Load()
+}
+
+// quotaConsumer identifica o consumidor da cota: a chave de API ou o subject
+// do token
+func quotaConsumer(c *gin.Context) (quota.Consumer, bool) {
+	if value, exists := c.Get(auth.APIKeyContextKey); exists {
+		if key, ok := value.(*model.APIKey); ok && key != nil {
+			return quota.Consumer{ID: "apikey:" + key.ID, Tier: key.Tier}, true
+		}
+	}
+	value, exists := c.Get("user")
+	if !exists {
+		return quota.Consumer{}, false
+	}
+	user, ok := value.(*model.User)
+	if !ok || user == nil || user.ID == "" {
+		return quota.Consumer{}, false
+	}
+	return quota.Consumer{ID: "sub:" + user.ID}, true
+}
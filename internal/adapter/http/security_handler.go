@@ -0,0 +1,60 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/diillson/api-gateway-go/internal/domain/repository"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// SecurityHandler expõe operações administrativas de segurança dos dados armazenados
+type SecurityHandler struct {
+	reEncrypters map[string]repository.SecretReEncrypter
+	logger       *zap.Logger
+}
+
+// NewSecurityHandler cria um novo handler de segurança
+func NewSecurityHandler(logger *zap.Logger) *SecurityHandler {
+	return &SecurityHandler{
+		reEncrypters: make(map[string]repository.SecretReEncrypter),
+		logger:       logger,
+	}
+}
+
+// RegisterReEncrypter registra um repositório com campos cifrados
+func (h *SecurityHandler) RegisterReEncrypter(name string, reEncrypter repository.SecretReEncrypter) {
+	h.reEncrypters[name] = reEncrypter
+}
+
+// ReEncrypt cifra novamente todos os campos sensíveis com a chave ativa,
+// usado após a rotação da chave mestra
+func (h *SecurityHandler) ReEncrypt(c *gin.Context) {
+	if len(h.reEncrypters) == 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Criptografia em repouso não está habilitada"})
+		return
+	}
+
+	results := make(map[string]interface{}, len(h.reEncrypters))
+	status := http.StatusOK
+
+	for name, reEncrypter := range h.reEncrypters {
+		count, err := reEncrypter.ReEncryptSecrets(c.Request.Context())
+		if err != nil {
+			h.logger.Error("Falha ao recriptografar dados",
+				zap.String("store", name),
+				zap.Int("updated", count),
+				zap.Error(err))
+			results[name] = gin.H{"updated": count, "error": err.Error()}
+			status = http.StatusInternalServerError
+			continue
+		}
+
+		h.logger.Info("Dados recriptografados com a chave ativa",
+			zap.String("store", name),
+			zap.Int("updated", count))
+		results[name] = gin.H{"updated": count}
+	}
+
+	c.JSON(status, gin.H{"results": results})
+}
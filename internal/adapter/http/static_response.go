@@ -0,0 +1,107 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/pkg/reqtemplate"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// maintenanceBody é o corpo padrão das rotas em manutenção
+const maintenanceBody = `{"error":"Serviço em manutenção"}`
+
+// serveMaintenance responde com a resposta de manutenção quando a rota está em
+// manutenção. Retorna false quando a requisição foi respondida.
+func (h *Handler) serveMaintenance(c *gin.Context, route *model.Route, span trace.Span) bool {
+	maintenance := route.Maintenance
+	if maintenance == nil || !maintenance.Enabled {
+		return true
+	}
+	path := c.Request.URL.Path
+
+	span.SetAttributes(attribute.Bool("route.maintenance", true))
+	if h.metrics != nil {
+		h.metrics.RequestError(path, c.Request.Method, "maintenance")
+	}
+	if seconds := maintenance.RetryAfterSeconds(); seconds > 0 {
+		c.Header("Retry-After", strconv.Itoa(seconds))
+	}
+	h.writeStaticResponse(c, route, maintenance.StaticResponse, http.StatusServiceUnavailable, maintenanceBody, requestTemplateData(c, route))
+	return false
+}
+
+// serveStub responde com a resposta simulada da rota no lugar do backend
+func (h *Handler) serveStub(c *gin.Context, route *model.Route, data *reqtemplate.Data, span trace.Span) {
+	stub := route.Stub
+	span.SetAttributes(attribute.Bool("route.stub", true))
+
+	if delay := stub.DelayDuration(); delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-c.Request.Context().Done():
+			return
+		}
+	}
+	h.writeStaticResponse(c, route, stub.StaticResponse, http.StatusOK, "", data)
+}
+
+// writeStaticResponse envia a resposta configurada na rota, avaliando as
+// expressões dos cabeçalhos e do corpo. Sem Content-Type, corpos JSON são
+// enviados como application/json e os demais como texto.
+func (h *Handler) writeStaticResponse(c *gin.Context, route *model.Route, response model.StaticResponse, defaultStatus int, defaultBody string, data *reqtemplate.Data) {
+	status := response.Status
+	if status == 0 {
+		status = defaultStatus
+	}
+
+	header := c.Writer.Header()
+	for name, value := range response.Headers {
+		rendered, err := reqtemplate.Render(value, data)
+		if err != nil {
+			h.logger.Debug("Falha ao avaliar cabeçalho da resposta fixa da rota",
+				zap.String("path", route.Path),
+				zap.String("header", name),
+				zap.Error(err))
+			continue
+		}
+		header.Set(name, rendered)
+	}
+
+	body := defaultBody
+	if response.Body != "" {
+		rendered, err := reqtemplate.Render(response.Body, data)
+		if err != nil {
+			h.logger.Warn("Falha ao avaliar corpo da resposta fixa da rota",
+				zap.String("path", route.Path),
+				zap.Error(err))
+		}
+		body = rendered
+	}
+
+	if header.Get("Content-Type") == "" && body != "" {
+		if json.Valid([]byte(body)) {
+			header.Set("Content-Type", "application/json; charset=utf-8")
+		} else {
+			header.Set("Content-Type", "text/plain; charset=utf-8")
+		}
+	}
+	header.Set("Content-Length", strconv.Itoa(len(body)))
+
+	c.Status(status)
+	if c.Request.Method == http.MethodHead || body == "" {
+		c.Writer.WriteHeaderNow()
+		return
+	}
+	if _, err := c.Writer.WriteString(body); err != nil {
+		h.logger.Debug("Falha ao enviar resposta fixa da rota", zap.Error(err))
+	}
+}
@@ -0,0 +1,64 @@
+package http
+
+import (
+	"context"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/pkg/reqtemplate"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// applyRouteTelemetry adiciona ao span os atributos configurados na rota e
+// retorna o contexto com as entradas de baggage, que o proxy propaga aos backends.
+// Expressões que falham ou resultam em valor vazio são ignoradas.
+func (h *Handler) applyRouteTelemetry(ctx context.Context, route *model.Route, data *reqtemplate.Data, span trace.Span) context.Context {
+	if route.Telemetry == nil {
+		return ctx
+	}
+
+	attrs := make([]attribute.KeyValue, 0, len(route.Telemetry.Attributes))
+	for name, expr := range route.Telemetry.Attributes {
+		value, err := reqtemplate.Render(expr, data)
+		if err != nil {
+			h.logger.Debug("Falha ao avaliar atributo de telemetria da rota",
+				zap.String("path", route.Path),
+				zap.String("attribute", name),
+				zap.Error(err))
+			continue
+		}
+		if value != "" {
+			attrs = append(attrs, attribute.String(name, value))
+		}
+	}
+	span.SetAttributes(attrs...)
+
+	if len(route.Telemetry.Baggage) == 0 {
+		return ctx
+	}
+
+	bag := baggage.FromContext(ctx)
+	for key, expr := range route.Telemetry.Baggage {
+		value, err := reqtemplate.Render(expr, data)
+		if err != nil || value == "" {
+			continue
+		}
+		member, err := baggage.NewMemberRaw(key, value)
+		if err != nil {
+			h.logger.Debug("Entrada de baggage inválida",
+				zap.String("path", route.Path),
+				zap.String("key", key),
+				zap.Error(err))
+			continue
+		}
+		if bag, err = bag.SetMember(member); err != nil {
+			h.logger.Debug("Falha ao adicionar entrada de baggage",
+				zap.String("path", route.Path),
+				zap.String("key", key),
+				zap.Error(err))
+		}
+	}
+	return baggage.ContextWithBaggage(ctx, bag)
+}
@@ -0,0 +1,109 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/app/audit"
+	"github.com/diillson/api-gateway-go/internal/app/auth"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// TokenHandler expõe a revogação de tokens JWT
+type TokenHandler struct {
+	authService *auth.AuthService
+	denylist    *auth.TokenDenylist
+	logger      *zap.Logger
+}
+
+// NewTokenHandler cria um novo handler de revogação de tokens
+func NewTokenHandler(authService *auth.AuthService, denylist *auth.TokenDenylist, logger *zap.Logger) *TokenHandler {
+	return &TokenHandler{
+		authService: authService,
+		denylist:    denylist,
+		logger:      logger,
+	}
+}
+
+// RevokeTokenRequest identifica os tokens a revogar. Informe apenas um entre
+// token, jti e subject.
+type RevokeTokenRequest struct {
+	Token     string     `json:"token"`
+	JTI       string     `json:"jti"`
+	ExpiresAt *time.Time `json:"expiresAt"` // Expiração do token do jti, quando conhecida
+	Subject   string     `json:"subject"`
+}
+
+// Revoke revoga um token, pelo valor ou pelo jti, ou todos os tokens de um subject
+func (h *TokenHandler) Revoke(c *gin.Context) {
+	var req RevokeTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	informed := 0
+	for _, value := range []string{req.Token, req.JTI, req.Subject} {
+		if value != "" {
+			informed++
+		}
+	}
+	if informed != 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Informe apenas um entre token, jti e subject"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	switch {
+	case req.Token != "":
+		if err := h.authService.RevokeToken(ctx, req.Token); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Não foi possível revogar o token: " + err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Token revogado"})
+
+	case req.JTI != "":
+		var expiresAt time.Time
+		if req.ExpiresAt != nil {
+			expiresAt = *req.ExpiresAt
+		}
+		if err := h.denylist.RevokeToken(ctx, req.JTI, expiresAt); err != nil {
+			h.logger.Error("Falha ao revogar token", zap.String("jti", req.JTI), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Falha ao revogar token"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Token revogado"})
+
+	default:
+		revokedAt, err := h.denylist.RevokeSubject(ctx, req.Subject)
+		if err != nil {
+			h.logger.Error("Falha ao revogar tokens do subject", zap.String("subject", req.Subject), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Falha ao revogar tokens"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"message":   "Tokens do subject revogados",
+			"revokedAt": revokedAt,
+		})
+	}
+}
+
+// Logout revoga o token Bearer usado na própria requisição
+func (h *TokenHandler) Logout(c *gin.Context) {
+	header := c.GetHeader("Authorization")
+	token := strings.TrimSpace(strings.TrimPrefix(header, "Bearer "))
+	if !strings.HasPrefix(header, "Bearer ") || token == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token JWT necessário"})
+		return
+	}
+
+	// O dono do token é identificado pelas claims ao revogá-lo
+	ctx := audit.WithActor(c.Request.Context(), audit.Actor{IP: c.ClientIP()})
+	if err := h.authService.RevokeToken(ctx, token); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido ou expirado"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
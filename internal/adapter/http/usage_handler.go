@@ -0,0 +1,81 @@
+package http
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/infra/usage"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// UsageHandler expõe a exportação do uso agregado para showback de custos
+type UsageHandler struct {
+	aggregator *usage.Aggregator
+	logger     *zap.Logger
+}
+
+// NewUsageHandler cria um novo handler de uso
+func NewUsageHandler(aggregator *usage.Aggregator, logger *zap.Logger) *UsageHandler {
+	return &UsageHandler{
+		aggregator: aggregator,
+		logger:     logger,
+	}
+}
+
+// Export retorna o uso acumulado desde a última exportação com reset.
+// Parâmetros: format=json|csv e reset=true para iniciar um novo período.
+func (h *UsageHandler) Export(c *gin.Context) {
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Formato inválido, use json ou csv"})
+		return
+	}
+	reset := c.Query("reset") == "true"
+
+	from, to, records := h.aggregator.Snapshot(reset)
+
+	h.logger.Info("Uso exportado",
+		zap.String("format", format),
+		zap.Bool("reset", reset),
+		zap.Int("records", len(records)))
+
+	if format == "json" {
+		c.JSON(http.StatusOK, gin.H{
+			"from":    from.Format(time.RFC3339),
+			"to":      to.Format(time.RFC3339),
+			"records": records,
+		})
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", "attachment; filename=usage-"+to.Format("20060102T150405")+".csv")
+	c.Status(http.StatusOK)
+
+	writer := csv.NewWriter(c.Writer)
+	_ = writer.Write([]string{"from", "to", "route", "tenant", "team", "product", "consumer",
+		"requests", "errors", "duration_seconds", "bytes_in", "bytes_out"})
+	for _, record := range records {
+		_ = writer.Write([]string{
+			from.Format(time.RFC3339),
+			to.Format(time.RFC3339),
+			record.Route,
+			record.Tenant,
+			record.Team,
+			record.Product,
+			record.Consumer,
+			strconv.FormatInt(record.Requests, 10),
+			strconv.FormatInt(record.Errors, 10),
+			strconv.FormatFloat(record.DurationSeconds, 'f', 3, 64),
+			strconv.FormatInt(record.BytesIn, 10),
+			strconv.FormatInt(record.BytesOut, 10),
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		h.logger.Error("Falha ao escrever exportação de uso", zap.Error(err))
+	}
+}
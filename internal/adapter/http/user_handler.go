@@ -36,6 +36,7 @@ type RegisterRequest struct {
 	Password string `json:"password" binding:"required"`
 	Email    string `json:"email" binding:"required,email"`
 	Role     string `json:"role"`
+	Tenant   string `json:"tenant"` // restringe o usuário a um tenant; vazio para todos
 }
 
 func (h *UserHandler) RegisterUser(c *gin.Context) {
@@ -71,6 +72,7 @@ func (h *UserHandler) RegisterUser(c *gin.Context) {
 		Password:  string(hashedPassword),
 		Email:     req.Email,
 		Role:      req.Role,
+		Tenant:    req.Tenant,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
@@ -140,6 +142,7 @@ func (h *UserHandler) RegisterUser(c *gin.Context) {
 		"id":       user.ID,
 		"username": user.Username,
 		"role":     user.Role,
+		"tenant":   user.Tenant,
 	})
 }
 
@@ -167,6 +170,7 @@ func (h *UserHandler) GetUsers(c *gin.Context) {
 			"username":  user.Username,
 			"email":     user.Email,
 			"role":      user.Role,
+			"tenant":    user.Tenant,
 			"createdAt": user.CreatedAt,
 		})
 	}
@@ -189,7 +193,7 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 		return
 	}
 
-	if currentUser.ID != id && currentUser.Role != "admin" {
+	if currentUser.ID != id && !isAdmin(c) {
 		c.JSON(http.StatusForbidden, gin.H{"error": "Acesso negado"})
 		return
 	}
@@ -211,6 +215,7 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 		"username":  user.Username,
 		"email":     user.Email,
 		"role":      user.Role,
+		"tenant":    user.Tenant,
 		"createdAt": user.CreatedAt,
 		"updatedAt": user.UpdatedAt,
 	})
@@ -222,6 +227,7 @@ type UpdateUserRequest struct {
 	Password *string `json:"password"`
 	Email    *string `json:"email"`
 	Role     *string `json:"role"`
+	Tenant   *string `json:"tenant"`
 }
 
 func (h *UserHandler) UpdateUser(c *gin.Context) {
@@ -239,7 +245,7 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 	}
 
 	// Apenas admin pode atualizar outros usuários ou alterar roles
-	isCurrentUserAdmin := currentUser.Role == "admin"
+	isCurrentUserAdmin := isAdmin(c)
 	isSelfUpdate := currentUser.ID == id
 
 	if !isCurrentUserAdmin && !isSelfUpdate {
@@ -324,6 +330,15 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		updates["role"] = *req.Role
 	}
 
+	if req.Tenant != nil {
+		// Apenas admin pode alterar o tenant, que limita o escopo do usuário
+		if !isCurrentUserAdmin {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Apenas administradores podem alterar o tenant"})
+			return
+		}
+		updates["tenant"] = *req.Tenant
+	}
+
 	// Se não há nada para atualizar
 	if len(updates) <= 1 { // apenas updated_at
 		c.JSON(http.StatusOK, gin.H{
@@ -452,9 +467,10 @@ func (h *UserHandler) Login(c *gin.Context) {
 
 	// Gerar token JWT
 	claims := jwt.MapClaims{
+		"jti":     uuid.New().String(),
 		"user_id": user.ID,
 		"role":    user.Role,
-		"exp":     time.Now().Add(24 * time.Hour).Unix(),
+		"exp":     time.Now().Add(security.TokenLifetime).Unix(),
 		"iat":     time.Now().Unix(),
 		"nbf":     time.Now().Unix(),
 	}
@@ -532,8 +548,9 @@ func isAdmin(c *gin.Context) bool {
 		return false
 	}
 
+	// Administradores de um tenant não gerenciam os usuários do gateway
 	user, ok := userValue.(*model.User)
-	return ok && user.Role == "admin"
+	return ok && user.Role == "admin" && user.Tenant == ""
 }
 
 // getCurrentUser obtém o usuário atual do contexto
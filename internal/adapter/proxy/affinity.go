@@ -0,0 +1,51 @@
+package proxy
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net"
+	"net/http"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/pkg/clientip"
+)
+
+// affinityKey retorna a chave que fixa o cliente em um alvo da rota. Com
+// origem cookie e o cookie ausente, o gateway cria um identificador para o
+// cliente. Retorna vazio quando a requisição não traz a chave.
+func affinityKey(affinity *model.SessionAffinity, w http.ResponseWriter, r *http.Request) string {
+	switch affinity.Source {
+	case model.AffinityHeader:
+		return r.Header.Get(affinity.Name)
+	case model.AffinityClientIP:
+		if addr, ok := clientip.FromContext(r.Context()); ok {
+			return addr.String()
+		}
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			return r.RemoteAddr
+		}
+		return host
+	case model.AffinityCookie:
+		if cookie, err := r.Cookie(affinity.Name); err == nil && cookie.Value != "" {
+			return cookie.Value
+		}
+		buf := make([]byte, 16)
+		if _, err := rand.Read(buf); err != nil {
+			return ""
+		}
+		value := hex.EncodeToString(buf)
+		// Mantido na requisição para que uma nova escolha do alvo use a mesma chave
+		r.AddCookie(&http.Cookie{Name: affinity.Name, Value: value})
+		http.SetCookie(w, &http.Cookie{
+			Name:     affinity.Name,
+			Value:    value,
+			Path:     "/",
+			MaxAge:   int(affinity.CookieTTL().Seconds()),
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		})
+		return value
+	}
+	return ""
+}
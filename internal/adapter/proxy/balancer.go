@@ -0,0 +1,212 @@
+package proxy
+
+import (
+	"hash/fnv"
+	"math"
+	"sync"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+)
+
+// Balancer escolhe o alvo de cada requisição entre os alvos disponíveis da rota
+type Balancer struct {
+	mu       sync.Mutex
+	next     map[string]int            // rota -> posição do round-robin
+	current  map[string]map[string]int // rota -> alvo -> peso corrente (weighted)
+	inFlight map[string]int            // alvo -> requisições em andamento
+}
+
+// NewBalancer cria um balanceador sem estado
+func NewBalancer() *Balancer {
+	return &Balancer{
+		next:     make(map[string]int),
+		current:  make(map[string]map[string]int),
+		inFlight: make(map[string]int),
+	}
+}
+
+// Pick escolhe um alvo da rota entre os aceitos por available, segundo a
+// estratégia configurada. A função retornada deve ser chamada ao fim da
+// requisição. Retorna false quando nenhum alvo está disponível.
+func (b *Balancer) Pick(route *model.Route, available func(target string) bool) (model.Target, func(), bool) {
+	candidates := make([]model.Target, 0, len(route.Upstreams()))
+	for _, target := range route.Upstreams() {
+		if available == nil || available(target.URL) {
+			candidates = append(candidates, target)
+		}
+	}
+	if len(candidates) == 0 {
+		return model.Target{}, func() {}, false
+	}
+
+	b.mu.Lock()
+	var chosen model.Target
+	switch {
+	case len(candidates) == 1:
+		chosen = candidates[0]
+	case route.Balancing() == model.LoadBalanceLeastConnections:
+		chosen = b.leastConnections(route.Path, candidates)
+	case route.Balancing() == model.LoadBalanceWeighted:
+		chosen = b.weighted(route.Path, candidates)
+	default:
+		chosen = b.roundRobin(route.Path, candidates)
+	}
+	b.mu.Unlock()
+
+	return chosen, b.acquire(chosen), true
+}
+
+// PickCanary escolhe a versão do backend para uma rota canary. Com stickyKey
+// informada, o mesmo valor é sempre atribuído à mesma versão enquanto ela
+// estiver disponível; sem ela, o tráfego segue os pesos dos alvos.
+func (b *Balancer) PickCanary(route *model.Route, stickyKey string, available func(target string) bool) (model.Target, func(), bool) {
+	if stickyKey != "" {
+		// A faixa de pesos considera todos os alvos, para que a atribuição não
+		// mude quando outro alvo fica indisponível
+		if target := weightedByHash(route.Upstreams(), stickyKey); available == nil || available(target.URL) {
+			return target, b.acquire(target), true
+		}
+	}
+
+	candidates := make([]model.Target, 0, len(route.Upstreams()))
+	for _, target := range route.Upstreams() {
+		if available == nil || available(target.URL) {
+			candidates = append(candidates, target)
+		}
+	}
+	if len(candidates) == 0 {
+		return model.Target{}, func() {}, false
+	}
+
+	b.mu.Lock()
+	chosen := b.weighted(route.Path, candidates)
+	b.mu.Unlock()
+
+	return chosen, b.acquire(chosen), true
+}
+
+// PickSticky escolhe o alvo da chave por hash consistente (rendezvous
+// ponderado): a mesma chave vai sempre ao mesmo alvo enquanto ele estiver
+// disponível e, quando um alvo sai, apenas as chaves dele mudam de alvo
+func (b *Balancer) PickSticky(route *model.Route, key string, available func(target string) bool) (model.Target, func(), bool) {
+	var chosen model.Target
+	best := -1.0
+	for _, target := range route.Upstreams() {
+		if available != nil && !available(target.URL) {
+			continue
+		}
+		if score := rendezvousScore(key, target); score > best {
+			chosen, best = target, score
+		}
+	}
+	if best < 0 {
+		return model.Target{}, func() {}, false
+	}
+	return chosen, b.acquire(chosen), true
+}
+
+// rendezvousScore pontua o alvo para a chave; a maior pontuação vence. O
+// logaritmo faz cada alvo vencer na proporção do seu peso.
+func rendezvousScore(key string, target model.Target) float64 {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	h.Write([]byte{0})
+	h.Write([]byte(target.URL))
+	// Mistura os bits (finalizador do splitmix64), pois o FNV distribui mal
+	// chaves parecidas
+	x := h.Sum64()
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	u := (float64(x>>11) + 0.5) / (1 << 53)
+	return float64(target.EffectiveWeight()) / -math.Log(u)
+}
+
+// weightedByHash mapeia a chave para um alvo de forma estável, respeitando a
+// proporção dos pesos
+func weightedByHash(targets []model.Target, key string) model.Target {
+	total := 0
+	for _, target := range targets {
+		total += target.EffectiveWeight()
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	point := int(h.Sum32() % uint32(total))
+	for _, target := range targets {
+		if point < target.EffectiveWeight() {
+			return target
+		}
+		point -= target.EffectiveWeight()
+	}
+	return targets[len(targets)-1]
+}
+
+// acquire registra uma requisição em andamento no alvo. A função retornada a
+// libera e pode ser chamada mais de uma vez.
+func (b *Balancer) acquire(chosen model.Target) func() {
+	b.mu.Lock()
+	b.inFlight[chosen.URL]++
+	b.mu.Unlock()
+
+	var once sync.Once
+	release := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			if b.inFlight[chosen.URL]--; b.inFlight[chosen.URL] <= 0 {
+				delete(b.inFlight, chosen.URL)
+			}
+		})
+	}
+	return release
+}
+
+// roundRobin alterna entre os candidatos em ordem
+func (b *Balancer) roundRobin(routePath string, candidates []model.Target) model.Target {
+	position := b.next[routePath] % len(candidates)
+	b.next[routePath] = position + 1
+	return candidates[position]
+}
+
+// leastConnections escolhe o candidato com menos requisições em andamento,
+// desempatando em round-robin para não concentrar tráfego no primeiro alvo
+func (b *Balancer) leastConnections(routePath string, candidates []model.Target) model.Target {
+	start := b.next[routePath] % len(candidates)
+	b.next[routePath] = start + 1
+
+	best := candidates[start]
+	for i := 1; i < len(candidates); i++ {
+		candidate := candidates[(start+i)%len(candidates)]
+		if b.inFlight[candidate.URL] < b.inFlight[best.URL] {
+			best = candidate
+		}
+	}
+	return best
+}
+
+// weighted implementa o round-robin ponderado suave: a cada escolha todos os
+// candidatos ganham seu peso e o escolhido perde a soma dos pesos, distribuindo
+// o tráfego proporcionalmente sem rajadas para o alvo de maior peso
+func (b *Balancer) weighted(routePath string, candidates []model.Target) model.Target {
+	weights, ok := b.current[routePath]
+	if !ok {
+		weights = make(map[string]int)
+		b.current[routePath] = weights
+	}
+
+	total := 0
+	best := -1
+	for i, candidate := range candidates {
+		weight := candidate.EffectiveWeight()
+		weights[candidate.URL] += weight
+		total += weight
+		if best < 0 || weights[candidate.URL] > weights[candidates[best].URL] {
+			best = i
+		}
+	}
+	weights[candidates[best].URL] -= total
+	return candidates[best]
+}
@@ -0,0 +1,95 @@
+package proxy
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/pkg/compression"
+	"github.com/diillson/api-gateway-go/pkg/reqtemplate"
+	"go.uber.org/zap"
+)
+
+// transformResponseBody aplica a transformação de corpo da rota à resposta do
+// backend. Respostas sem corpo, que não são JSON ou maiores que o limite seguem
+// sem alterações; falhas do template viram 502 para o cliente.
+func (p *ReverseProxy) transformResponseBody(res *http.Response, route *model.Route) {
+	if route.BodyTransforms == nil || route.BodyTransforms.Response == nil {
+		return
+	}
+	transform := route.BodyTransforms.Response
+	if res.Request.Method == http.MethodHead || res.StatusCode == http.StatusNoContent ||
+		res.StatusCode == http.StatusNotModified || !reqtemplate.IsJSON(res.Header.Get("Content-Type")) {
+		return
+	}
+
+	contentEncoding := res.Header.Get("Content-Encoding")
+	if !compression.IsSupported(contentEncoding) {
+		return
+	}
+
+	limit := transform.Limit()
+	raw, err := io.ReadAll(io.LimitReader(res.Body, limit+1))
+	if err != nil {
+		p.failBodyTransform(res, route, err)
+		return
+	}
+	if int64(len(raw)) > limit {
+		p.logger.Warn("Resposta maior que o limite da transformação de corpo; enviada sem alterações",
+			zap.String("path", route.Path),
+			zap.Int64("limit", limit))
+		res.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(raw), res.Body), Closer: res.Body}
+		return
+	}
+	res.Body.Close()
+
+	body, err := compression.Decode(contentEncoding, raw, limit)
+	if err != nil {
+		res.Body = io.NopCloser(bytes.NewReader(raw))
+		return
+	}
+
+	template, err := reqtemplate.CompileBody(transform.Template)
+	if err != nil {
+		p.failBodyTransform(res, route, err)
+		return
+	}
+	transformed, err := template.Transform(body, reqtemplate.DataFromContext(res.Request.Context()))
+	if errors.Is(err, reqtemplate.ErrInvalidBody) {
+		// O backend declarou JSON, mas enviou outro conteúdo
+		p.logger.Debug("Resposta do backend não é JSON válido; enviada sem alterações",
+			zap.String("path", route.Path), zap.Error(err))
+		replaceResponseBody(res, body)
+		return
+	}
+	if err != nil {
+		p.failBodyTransform(res, route, err)
+		return
+	}
+	replaceResponseBody(res, transformed)
+}
+
+// failBodyTransform substitui a resposta por um erro de gateway
+func (p *ReverseProxy) failBodyTransform(res *http.Response, route *model.Route, err error) {
+	p.logger.Error("Falha ao transformar o corpo da resposta",
+		zap.String("path", route.Path), zap.Error(err))
+	if p.metrics != nil {
+		p.metrics.RequestError(route.Path, res.Request.Method, "body_transform_error")
+	}
+	res.StatusCode = http.StatusBadGateway
+	res.Status = http.StatusText(http.StatusBadGateway)
+	res.Header = http.Header{"Content-Type": {"application/json; charset=utf-8"}}
+	replaceResponseBody(res, []byte(`{"error":"Falha ao transformar a resposta do backend"}`))
+}
+
+// replaceResponseBody troca o corpo da resposta por um conteúdo sem compressão
+func replaceResponseBody(res *http.Response, body []byte) {
+	res.Body = io.NopCloser(bytes.NewReader(body))
+	res.ContentLength = int64(len(body))
+	res.Header.Del("Content-Encoding")
+	res.Header.Del("ETag")
+	res.Header.Set("Content-Length", strconv.Itoa(len(body)))
+}
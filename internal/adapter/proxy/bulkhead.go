@@ -0,0 +1,77 @@
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/pkg/resilience"
+)
+
+// bulkheadEntry guarda o limitador com os limites usados na sua criação
+type bulkheadEntry struct {
+	bulkhead      *resilience.Bulkhead
+	maxConcurrent int
+	maxQueue      int
+}
+
+// acquireBulkhead reserva as vagas da rota e do alvo conforme a política de
+// concorrência. A espera na fila é compartilhada pelas duas reservas. A função
+// retornada libera as vagas.
+func (p *ReverseProxy) acquireBulkhead(ctx context.Context, route *model.Route, targetURL string) (func(), error) {
+	policy := route.Bulkhead
+	if policy == nil {
+		return func() {}, nil
+	}
+
+	deadline := time.Now().Add(policy.QueueTimeoutDuration())
+	var releases []func()
+	release := func() {
+		for i := len(releases) - 1; i >= 0; i-- {
+			releases[i]()
+		}
+	}
+
+	if policy.MaxConcurrent > 0 {
+		routeRelease, err := p.bulkhead(route.Path, policy.MaxConcurrent, policy.MaxQueue).Acquire(ctx, time.Until(deadline))
+		if err != nil {
+			return nil, err
+		}
+		releases = append(releases, routeRelease)
+	}
+	if policy.MaxPerTarget > 0 {
+		targetRelease, err := p.bulkhead(circuitKey(route.Path, targetURL), policy.MaxPerTarget, policy.MaxQueue).Acquire(ctx, time.Until(deadline))
+		if err != nil {
+			release()
+			return nil, err
+		}
+		releases = append(releases, targetRelease)
+	}
+	return release, nil
+}
+
+// bulkhead obtém ou cria o limitador da chave. Quando os limites mudam, um
+// novo limitador substitui o anterior; as requisições em andamento liberam as
+// vagas do limitador antigo.
+func (p *ReverseProxy) bulkhead(key string, maxConcurrent, maxQueue int) *resilience.Bulkhead {
+	p.bulkheadLock.Lock()
+	defer p.bulkheadLock.Unlock()
+
+	entry, exists := p.bulkheads[key]
+	if !exists || entry.maxConcurrent != maxConcurrent || entry.maxQueue != maxQueue {
+		entry = &bulkheadEntry{
+			bulkhead:      resilience.NewBulkhead(maxConcurrent, maxQueue),
+			maxConcurrent: maxConcurrent,
+			maxQueue:      maxQueue,
+		}
+		p.bulkheads[key] = entry
+	}
+	return entry.bulkhead
+}
+
+// writeBulkheadFull responde 503 quando não há vaga para a requisição
+func writeBulkheadFull(w http.ResponseWriter, r *http.Request, route *model.Route) {
+	w.Header().Set("Retry-After", "1")
+	writeError(w, r, route, http.StatusServiceUnavailable, model.ErrorCodeConcurrencyLimit)
+}
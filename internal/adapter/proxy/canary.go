@@ -0,0 +1,51 @@
+package proxy
+
+import (
+	"net/http"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+)
+
+// pickTarget escolhe o alvo da requisição: rotas canary distribuem o tráfego
+// entre as versões do backend, rotas com afinidade de sessão mantêm o cliente
+// no mesmo alvo e as demais seguem a estratégia de balanceamento
+func (p *ReverseProxy) pickTarget(route *model.Route, w http.ResponseWriter, r *http.Request, available func(string) bool) (model.Target, func(), bool) {
+	if route.Canary == nil {
+		if route.SessionAffinity != nil {
+			if key := affinityKey(route.SessionAffinity, w, r); key != "" {
+				return p.balancer.PickSticky(route, key, available)
+			}
+		}
+		return p.balancer.Pick(route, available)
+	}
+	canary := route.Canary
+
+	// Cliente já atribuído a uma versão disponível
+	if canary.StickyCookie != "" {
+		if cookie, err := r.Cookie(canary.StickyCookie); err == nil {
+			for _, target := range route.Upstreams() {
+				if target.Version == cookie.Value && available(target.URL) {
+					return target, p.balancer.acquire(target), true
+				}
+			}
+		}
+	}
+
+	stickyKey := ""
+	if canary.StickyHeader != "" {
+		stickyKey = r.Header.Get(canary.StickyHeader)
+	}
+
+	target, release, ok := p.balancer.PickCanary(route, stickyKey, available)
+	if ok && canary.StickyCookie != "" {
+		http.SetCookie(w, &http.Cookie{
+			Name:     canary.StickyCookie,
+			Value:    target.Version,
+			Path:     "/",
+			MaxAge:   int(canary.StickyTTLDuration().Seconds()),
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		})
+	}
+	return target, release, ok
+}
@@ -0,0 +1,103 @@
+package proxy
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/pkg/resilience"
+)
+
+// upstreamStatusError indica que o backend respondeu com erro de servidor. A
+// resposta já foi repassada ao cliente; o erro serve apenas ao circuit breaker.
+type upstreamStatusError struct {
+	status int
+}
+
+func (e *upstreamStatusError) Error() string {
+	return fmt.Sprintf("backend respondeu com status %d", e.status)
+}
+
+// circuitKey identifica o circuito de um alvo de uma rota
+func circuitKey(routePath, targetURL string) string {
+	return routePath + "|" + targetURL
+}
+
+// circuitConfig monta a configuração do circuit breaker a partir da política
+// da rota; sem política, os valores padrão do gateway são usados
+func circuitConfig(key string, policy *model.CircuitBreakerPolicy) resilience.CircuitBreakerConfig {
+	if policy == nil {
+		return resilience.CircuitBreakerConfig{
+			Name:        "cb-" + key,
+			MaxRequests: 5,
+			Interval:    time.Minute,
+			Timeout:     30 * time.Second,
+		}
+	}
+	return resilience.CircuitBreakerConfig{
+		Name:               "cb-" + key,
+		MaxRequestsFail:    policy.ConsecutiveFailures,
+		Interval:           policy.WindowDuration(),
+		Timeout:            policy.CoolDownDuration(),
+		MaxRequests:        policy.HalfOpenRequests,
+		ErrorRateThreshold: policy.ErrorRate,
+		MinimumRequests:    policy.MinimumRequests,
+	}
+}
+
+// getCircuitBreaker obtém ou cria o circuit breaker de um alvo da rota. Um
+// circuito existente é reconfigurado quando a política da rota muda.
+func (p *ReverseProxy) getCircuitBreaker(key string, policy *model.CircuitBreakerPolicy) *resilience.CircuitBreaker {
+	config := circuitConfig(key, policy)
+
+	p.cbLock.RLock()
+	cb, exists := p.circuitBreakers[key]
+	current := p.circuitConfigs[key]
+	p.cbLock.RUnlock()
+
+	if exists && current == config {
+		return cb
+	}
+
+	p.cbLock.Lock()
+	defer p.cbLock.Unlock()
+
+	// Verificar novamente em caso de race condition
+	if cb, exists = p.circuitBreakers[key]; exists {
+		if p.circuitConfigs[key] != config {
+			cb.Configure(config)
+			p.circuitConfigs[key] = config
+		}
+		return cb
+	}
+
+	cb = resilience.NewCircuitBreaker(config, p.logger, p.metrics)
+	if p.cluster != nil {
+		cb.OnStateChange(p.publishCircuitState(key))
+	}
+	p.circuitBreakers[key] = cb
+	p.circuitConfigs[key] = config
+
+	return cb
+}
+
+// circuitOpen indica se o circuito do alvo está aberto e ainda em cool-down
+func (p *ReverseProxy) circuitOpen(route *model.Route, targetURL string) bool {
+	p.cbLock.RLock()
+	cb, exists := p.circuitBreakers[circuitKey(route.Path, targetURL)]
+	p.cbLock.RUnlock()
+	return exists && cb.RetryAfter() > 0
+}
+
+// writeCircuitOpen responde imediatamente com 503 enquanto o circuito está aberto
+func writeCircuitOpen(w http.ResponseWriter, r *http.Request, route *model.Route, retryAfter time.Duration) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	writeError(w, r, route, http.StatusServiceUnavailable, model.ErrorCodeCircuitOpen)
+}
@@ -0,0 +1,70 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/diillson/api-gateway-go/pkg/cluster"
+	"github.com/diillson/api-gateway-go/pkg/resilience"
+	"go.uber.org/zap"
+)
+
+// circuitTopic é o tópico do cluster usado para compartilhar o estado dos circuit breakers
+const circuitTopic = "circuit"
+
+// circuitMessage descreve uma mudança de estado de circuit breaker
+type circuitMessage struct {
+	Service     string                  `json:"service"` // Chave do circuito: rota|alvo
+	State       resilience.CircuitState `json:"state"`
+	ChangedAt   time.Time               `json:"changed_at"`
+	NextAttempt time.Time               `json:"next_attempt,omitempty"`
+}
+
+// SetCluster compartilha o estado dos circuit breakers com as demais instâncias:
+// um circuito aberto por uma instância passa a rejeitar requisições em todas.
+// Deve ser chamado antes de node.Start.
+func (p *ReverseProxy) SetCluster(node *cluster.Node) {
+	p.cbLock.Lock()
+	p.cluster = node
+	for key, cb := range p.circuitBreakers {
+		cb.OnStateChange(p.publishCircuitState(key))
+	}
+	p.cbLock.Unlock()
+
+	node.Subscribe(circuitTopic, func(payload []byte) {
+		var msg circuitMessage
+		if err := json.Unmarshal(payload, &msg); err != nil {
+			p.logger.Warn("Estado de circuit breaker do cluster inválido", zap.Error(err))
+			return
+		}
+		p.cbLock.RLock()
+		cb, exists := p.circuitBreakers[msg.Service]
+		p.cbLock.RUnlock()
+		if !exists {
+			// O circuito é criado com a configuração padrão e ajustado à
+			// política da rota na próxima requisição
+			cb = p.getCircuitBreaker(msg.Service, nil)
+		}
+		cb.ApplyState(msg.State, msg.ChangedAt, msg.NextAttempt)
+	})
+}
+
+// publishCircuitState cria a função que publica as mudanças de um circuit breaker no cluster
+func (p *ReverseProxy) publishCircuitState(key string) resilience.StateChangeFunc {
+	return func(_ string, state resilience.CircuitState, changedAt, nextAttempt time.Time) {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		err := p.cluster.Publish(ctx, circuitTopic, circuitMessage{
+			Service:     key,
+			State:       state,
+			ChangedAt:   changedAt,
+			NextAttempt: nextAttempt,
+		})
+		if err != nil {
+			p.logger.Warn("Falha ao compartilhar estado do circuit breaker",
+				zap.String("circuit", key), zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/internal/infra/metrics"
+	"github.com/diillson/api-gateway-go/pkg/reqtemplate"
+	"github.com/diillson/api-gateway-go/pkg/requestid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// compositeResult é a resposta decodificada de uma chamada da composição
+type compositeResult struct {
+	body interface{}
+	err  error
+}
+
+// Compose atende as rotas de composição: chama os backends em paralelo e
+// responde com as respostas JSON combinadas pelo template da rota. A falha de
+// uma chamada obrigatória cancela as demais e responde 502, ou 504 quando o
+// tempo da composição se esgota.
+func (p *ReverseProxy) Compose(route *model.Route, w http.ResponseWriter, r *http.Request, data *reqtemplate.Data) error {
+	composite := route.Composite
+
+	ctx, span := p.tracer.Start(r.Context(),
+		fmt.Sprintf("Compose %s", r.URL.Path),
+		trace.WithSpanKind(trace.SpanKindInternal),
+	)
+	defer span.End()
+	span.SetAttributes(attribute.Int("composite.calls", len(composite.Calls)))
+
+	transport, err := p.UpstreamTransport(route)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		writeCompositeError(w, r, route, "", http.StatusBadGateway)
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, composite.TimeoutDuration())
+	defer cancel()
+
+	results := make([]compositeResult, len(composite.Calls))
+	failed := -1 // Primeira chamada obrigatória que falhou
+	var failOnce sync.Once
+	var wg sync.WaitGroup
+	for i := range composite.Calls {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			call := &composite.Calls[i]
+			body, err := p.compositeCall(ctx, transport, route, call, r, data)
+			results[i] = compositeResult{body: body, err: err}
+			if err != nil && !call.Optional {
+				// As demais respostas não serão usadas
+				failOnce.Do(func() {
+					failed = i
+					cancel()
+				})
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if failed >= 0 {
+		call := &composite.Calls[failed]
+		err := fmt.Errorf("chamada %q da composição falhou: %w", call.Name, results[failed].err)
+		span.SetStatus(codes.Error, err.Error())
+		span.SetAttributes(attribute.String("composite.failed_call", call.Name))
+		status := http.StatusBadGateway
+		if errors.Is(err, context.DeadlineExceeded) {
+			status = http.StatusGatewayTimeout
+		}
+		writeCompositeError(w, r, route, call.Name, status)
+		return err
+	}
+
+	responses := make(map[string]interface{}, len(composite.Calls))
+	for i, result := range results {
+		call := &composite.Calls[i]
+		if result.err != nil {
+			p.logger.Debug("Chamada opcional da composição falhou",
+				zap.String("path", route.Path),
+				zap.String("call", call.Name),
+				zap.Error(result.err))
+		}
+		responses[call.Name] = result.body
+	}
+
+	var body []byte
+	if composite.Template != "" {
+		tmpl, err := reqtemplate.CompileBody(composite.Template)
+		if err == nil {
+			body, err = tmpl.Apply(responses, data)
+		}
+		if err != nil {
+			err = fmt.Errorf("falha ao combinar as respostas da composição: %w", err)
+			span.SetStatus(codes.Error, err.Error())
+			writeCompositeError(w, r, route, "", http.StatusBadGateway)
+			return err
+		}
+	} else if body, err = json.Marshal(responses); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		writeCompositeError(w, r, route, "", http.StatusBadGateway)
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(http.StatusOK)
+	if r.Method != http.MethodHead {
+		if _, err := w.Write(body); err != nil {
+			p.logger.Debug("Falha ao enviar a resposta da composição", zap.Error(err))
+		}
+	}
+	span.SetStatus(codes.Ok, "")
+	return nil
+}
+
+// compositeCall faz uma chamada da composição e decodifica a resposta JSON.
+// Respostas com status de erro, corpos acima do limite e corpos que não são
+// JSON são falhas.
+func (p *ReverseProxy) compositeCall(ctx context.Context, transport http.RoundTripper, route *model.Route, call *model.CompositeCall, r *http.Request, data *reqtemplate.Data) (interface{}, error) {
+	ctx, span := p.tracer.Start(ctx,
+		fmt.Sprintf("Compose call %s", call.Name),
+		trace.WithSpanKind(trace.SpanKindClient),
+	)
+	defer span.End()
+
+	start := time.Now()
+	body, result, err := p.doCompositeCall(ctx, transport, route, call, r, data)
+	if p.metrics != nil {
+		p.metrics.CompositeCall(route.Path, call.Name, result, time.Since(start))
+	}
+	span.SetAttributes(attribute.String("composite.result", result))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	span.SetStatus(codes.Ok, "")
+	return body, nil
+}
+
+func (p *ReverseProxy) doCompositeCall(ctx context.Context, transport http.RoundTripper, route *model.Route, call *model.CompositeCall, r *http.Request, data *reqtemplate.Data) (interface{}, string, error) {
+	target, err := reqtemplate.Render(call.URL, data)
+	if err != nil {
+		return nil, "error", err
+	}
+	req, err := http.NewRequestWithContext(ctx, call.HTTPMethod(), target, nil)
+	if err != nil {
+		return nil, "error", err
+	}
+
+	// Repassar os cabeçalhos da rota e os da chamada
+	for _, header := range route.Headers {
+		if value := r.Header.Get(header); value != "" {
+			req.Header.Set(header, value)
+		}
+	}
+	if value := r.Header.Get(requestid.DefaultHeader); value != "" {
+		req.Header.Set(requestid.DefaultHeader, value)
+	}
+	req.Header.Set("X-Forwarded-For", r.RemoteAddr)
+	req.Header.Set("X-Forwarded-Host", r.Host)
+	req.Header.Set("Accept", "application/json")
+	for name, value := range call.Headers {
+		rendered, err := reqtemplate.Render(value, data)
+		if err != nil {
+			return nil, "error", fmt.Errorf("cabeçalho %s: %w", name, err)
+		}
+		req.Header.Set(name, rendered)
+	}
+	if route.UpstreamAuth != nil {
+		req.SetBasicAuth(route.UpstreamAuth.Username, route.UpstreamAuth.Password)
+	}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	res, err := transport.RoundTrip(req)
+	if err != nil {
+		switch {
+		case errors.Is(ctx.Err(), context.DeadlineExceeded):
+			return nil, "timeout", err
+		case errors.Is(ctx.Err(), context.Canceled):
+			return nil, "canceled", err
+		}
+		return nil, "error", err
+	}
+	defer res.Body.Close()
+
+	result := metrics.StatusClass(res.StatusCode)
+	if res.StatusCode >= http.StatusBadRequest {
+		io.Copy(io.Discard, io.LimitReader(res.Body, maxDrainBytes))
+		return nil, result, fmt.Errorf("backend respondeu com status %d", res.StatusCode)
+	}
+
+	limit := route.Composite.ResponseLimit()
+	raw, err := io.ReadAll(io.LimitReader(res.Body, limit+1))
+	if err != nil {
+		return nil, "error", err
+	}
+	if int64(len(raw)) > limit {
+		return nil, "body_too_large", fmt.Errorf("resposta acima do limite de %d bytes", limit)
+	}
+
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return nil, result, nil
+	}
+	var body interface{}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&body); err != nil {
+		return nil, "invalid_body", fmt.Errorf("%w: %v", reqtemplate.ErrInvalidBody, err)
+	}
+	return body, result, nil
+}
+
+// writeCompositeError responde a falha da composição, informando a chamada
+// obrigatória que falhou
+func writeCompositeError(w http.ResponseWriter, r *http.Request, route *model.Route, call string, status int) {
+	code := model.ErrorCodeCompositionFailed
+	if status == http.StatusGatewayTimeout {
+		code = model.ErrorCodeUpstreamTimeout
+	}
+	body := newErrorBody(r, route, status, code)
+	body.Call = call
+	writeErrorBody(w, r, route, body)
+}
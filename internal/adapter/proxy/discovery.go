@@ -0,0 +1,99 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"go.uber.org/zap"
+)
+
+// serviceWeightScale multiplica os pesos dos alvos quando o peso de um alvo
+// descoberto é dividido entre suas instâncias, preservando a proporção entre os alvos
+const serviceWeightScale = 100
+
+// ServiceResolver resolve um alvo de descoberta de serviços (service://,
+// consul:// ou srv://) nas instâncias saudáveis, com pesos relativos entre si
+type ServiceResolver interface {
+	Resolve(ctx context.Context, raw string) ([]model.Target, error)
+}
+
+// ErrNoEndpoints indica que nenhum alvo descoberto da rota tem instâncias saudáveis
+var ErrNoEndpoints = errors.New("nenhuma instância saudável nos serviços do backend")
+
+// SetServiceResolver habilita os backends resolvidos por descoberta de serviços
+func (p *ReverseProxy) SetServiceResolver(resolver ServiceResolver) {
+	p.services = resolver
+}
+
+// resolveServices retorna uma cópia da rota com os alvos descobertos trocados
+// pelas suas instâncias. Cada instância é um alvo próprio, com circuit breaker
+// e bulkhead próprios. O peso de um alvo é dividido entre suas instâncias na
+// proporção dos pesos delas. Rotas sem descoberta são retornadas sem cópia.
+func (p *ReverseProxy) resolveServices(ctx context.Context, route *model.Route) (*model.Route, error) {
+	upstreams := route.Upstreams()
+	hasService := false
+	for _, target := range upstreams {
+		if model.IsDiscoveryTarget(target.URL) {
+			hasService = true
+			break
+		}
+	}
+	if !hasService {
+		return route, nil
+	}
+	if p.services == nil {
+		return nil, errors.New("descoberta de serviços desabilitada na configuração (upstream)")
+	}
+
+	targets := make([]model.Target, 0, len(upstreams))
+	var lastErr error
+	for _, target := range upstreams {
+		if !model.IsDiscoveryTarget(target.URL) {
+			targets = append(targets, model.Target{
+				URL:     target.URL,
+				Weight:  target.EffectiveWeight() * serviceWeightScale,
+				Version: target.Version,
+			})
+			continue
+		}
+
+		instances, err := p.services.Resolve(ctx, target.URL)
+		if err == nil && len(instances) > 0 {
+			total := 0
+			for _, instance := range instances {
+				total += instance.EffectiveWeight()
+			}
+			for _, instance := range instances {
+				weight := target.EffectiveWeight() * serviceWeightScale * instance.EffectiveWeight() / total
+				if weight < 1 {
+					weight = 1
+				}
+				targets = append(targets, model.Target{URL: instance.URL, Weight: weight, Version: target.Version})
+			}
+			continue
+		}
+
+		// Os demais alvos continuam atendendo
+		if err != nil {
+			lastErr = err
+			p.logger.Warn("Falha ao resolver serviço do backend",
+				zap.String("path", route.Path),
+				zap.String("service", target.URL),
+				zap.Error(err))
+		}
+	}
+
+	if len(targets) == 0 {
+		if lastErr != nil {
+			return nil, fmt.Errorf("%w: %v", ErrNoEndpoints, lastErr)
+		}
+		return nil, ErrNoEndpoints
+	}
+
+	resolved := *route
+	resolved.ServiceURL = ""
+	resolved.Targets = targets
+	return &resolved, nil
+}
@@ -0,0 +1,157 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"html/template"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/pkg/requestid"
+)
+
+// errorBody é o corpo padronizado das falhas ao atender pelo backend. Os
+// detalhes da falha ficam apenas nos logs e nos traces.
+type errorBody struct {
+	Error     string `json:"error"`
+	Code      string `json:"code"`
+	Status    int    `json:"status"`
+	RequestID string `json:"requestId,omitempty"`
+	Call      string `json:"call,omitempty"` // chamada obrigatória da composição que falhou
+}
+
+// errorPages guarda os templates das páginas de erro já compilados
+var errorPages sync.Map
+
+// newErrorBody monta o corpo do erro com a mensagem da rota
+func newErrorBody(r *http.Request, route *model.Route, status int, code string) errorBody {
+	return errorBody{
+		Error:     errorPolicy(route).Message(code),
+		Code:      code,
+		Status:    status,
+		RequestID: requestid.FromContext(r.Context()),
+	}
+}
+
+// writeError responde a falha com o corpo padronizado, ou com a página HTML
+// da rota para clientes que aceitam text/html
+func writeError(w http.ResponseWriter, r *http.Request, route *model.Route, status int, code string) {
+	writeErrorBody(w, r, route, newErrorBody(r, route, status, code))
+}
+
+func writeErrorBody(w http.ResponseWriter, r *http.Request, route *model.Route, body errorBody) {
+	content, contentType := renderError(r, errorPolicy(route), body)
+	header := w.Header()
+	header.Del("Content-Length")
+	header.Del("Content-Encoding")
+	header.Set("Content-Type", contentType)
+	header.Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(body.Status)
+	if r.Method != http.MethodHead {
+		w.Write(content)
+	}
+}
+
+// renderError monta a página HTML da rota para o erro, quando o cliente a
+// aceita, ou o corpo JSON
+func renderError(r *http.Request, policy *model.ErrorPolicy, body errorBody) ([]byte, string) {
+	if acceptsHTML(r) {
+		if key, page := policy.Page(body.Status, body.Code); page != "" {
+			if tmpl, err := compileErrorPage(key, page); err == nil {
+				var buf bytes.Buffer
+				err := tmpl.Execute(&buf, model.ErrorPageData{
+					Status:     body.Status,
+					StatusText: http.StatusText(body.Status),
+					Code:       body.Code,
+					Message:    body.Error,
+					RequestID:  body.RequestID,
+					Path:       r.URL.Path,
+				})
+				if err == nil {
+					return buf.Bytes(), "text/html; charset=utf-8"
+				}
+			}
+		}
+	}
+	content, _ := json.Marshal(body)
+	return content, "application/json; charset=utf-8"
+}
+
+// normalizeUpstreamError substitui o corpo das respostas 5xx do backend pelo
+// erro padronizado, quando a rota pede, evitando expor detalhes internos
+func normalizeUpstreamError(res *http.Response, route *model.Route) {
+	policy := route.Errors
+	if policy == nil || !policy.NormalizeUpstream || res.StatusCode < http.StatusInternalServerError {
+		return
+	}
+
+	body := newErrorBody(res.Request, route, res.StatusCode, model.ErrorCodeUpstreamServerError)
+	content, contentType := renderError(res.Request, policy, body)
+	if res.Body != nil {
+		res.Body.Close()
+	}
+	res.Body = io.NopCloser(bytes.NewReader(content))
+	res.ContentLength = int64(len(content))
+	res.Header.Set("Content-Length", strconv.Itoa(len(content)))
+	res.Header.Set("Content-Type", contentType)
+	res.Header.Del("Content-Encoding")
+	res.Header.Del("ETag")
+}
+
+// classifyUpstreamError identifica a falha de conexão com o backend: o tipo
+// registrado nas métricas, o código de erro e o status da resposta
+func classifyUpstreamError(err error) (string, string, int) {
+	var netErr net.Error
+	var dnsErr *net.DNSError
+	var certErr *tls.CertificateVerificationError
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	switch {
+	case errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) ||
+		strings.Contains(err.Error(), "context deadline exceeded"):
+		return "timeout_error", model.ErrorCodeUpstreamTimeout, http.StatusGatewayTimeout
+	case strings.Contains(err.Error(), "connection refused"):
+		return "connection_refused", model.ErrorCodeUpstreamConnectionRefused, http.StatusServiceUnavailable
+	case (errors.As(err, &dnsErr) && dnsErr.IsNotFound) || strings.Contains(err.Error(), "no such host"):
+		return "host_not_found", model.ErrorCodeUpstreamHostNotFound, http.StatusBadGateway
+	case errors.As(err, &certErr) || errors.As(err, &unknownAuthority) || errors.As(err, &hostnameErr) ||
+		strings.Contains(err.Error(), "tls: "):
+		return "tls_error", model.ErrorCodeUpstreamTLS, http.StatusBadGateway
+	default:
+		return "proxy_error", model.ErrorCodeUpstreamError, http.StatusBadGateway
+	}
+}
+
+func errorPolicy(route *model.Route) *model.ErrorPolicy {
+	if route == nil {
+		return nil
+	}
+	return route.Errors
+}
+
+// acceptsHTML indica se o cliente aceita respostas text/html, como navegadores
+func acceptsHTML(r *http.Request) bool {
+	return strings.Contains(strings.ToLower(r.Header.Get("Accept")), "text/html")
+}
+
+// compileErrorPage compila o template da página uma única vez por processo
+func compileErrorPage(name, page string) (*template.Template, error) {
+	if tmpl, ok := errorPages.Load(page); ok {
+		return tmpl.(*template.Template), nil
+	}
+	tmpl, err := template.New(name).Parse(page)
+	if err != nil {
+		return nil, err
+	}
+	errorPages.Store(page, tmpl)
+	return tmpl, nil
+}
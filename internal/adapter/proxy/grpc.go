@@ -0,0 +1,215 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"golang.org/x/net/http2"
+)
+
+// grpcWebTrailerFlag marca o frame gRPC-Web que carrega os trailers da resposta
+const grpcWebTrailerFlag = 0x80
+
+// grpcTransport encaminha chamadas gRPC por HTTP/2, escolhendo o transporte
+// pelo esquema do alvo
+type grpcTransport struct {
+	secure    *http.Transport
+	cleartext *http2.Transport
+}
+
+// RoundTrip implementa http.RoundTripper
+func (t *grpcTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme == "http" {
+		return t.cleartext.RoundTrip(req)
+	}
+	return t.secure.RoundTrip(req)
+}
+
+// CloseIdleConnections fecha as conexões ociosas dos dois transportes
+func (t *grpcTransport) CloseIdleConnections() {
+	t.secure.CloseIdleConnections()
+	t.cleartext.CloseIdleConnections()
+}
+
+// grpcContext define o prazo das chamadas gRPC pelo cabeçalho grpc-timeout do
+// cliente. Sem ele, a chamada não tem prazo, permitindo streams longos.
+func grpcContext(ctx context.Context, header http.Header) (context.Context, context.CancelFunc) {
+	if timeout, ok := parseGRPCTimeout(header.Get("Grpc-Timeout")); ok {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// parseGRPCTimeout interpreta o formato do grpc-timeout (ex.: "100m", "5S")
+func parseGRPCTimeout(value string) (time.Duration, bool) {
+	if len(value) < 2 {
+		return 0, false
+	}
+	amount, err := strconv.ParseInt(value[:len(value)-1], 10, 64)
+	if err != nil || amount < 0 {
+		return 0, false
+	}
+
+	units := map[byte]time.Duration{
+		'H': time.Hour, 'M': time.Minute, 'S': time.Second,
+		'm': time.Millisecond, 'u': time.Microsecond, 'n': time.Nanosecond,
+	}
+	unit, ok := units[value[len(value)-1]]
+	if !ok {
+		return 0, false
+	}
+	return time.Duration(amount) * unit, true
+}
+
+// isGRPCWebRequest indica se a requisição usa o protocolo gRPC-Web
+func isGRPCWebRequest(r *http.Request) bool {
+	return strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc-web")
+}
+
+// isGRPCWebText indica se o corpo gRPC-Web está codificado em base64
+func isGRPCWebText(contentType string) bool {
+	return strings.HasPrefix(contentType, "application/grpc-web-text")
+}
+
+// translateGRPCWebRequest converte a requisição gRPC-Web de saída em uma
+// chamada gRPC. O formato dos frames é o mesmo; apenas o modo texto precisa
+// ser decodificado.
+func translateGRPCWebRequest(req *http.Request) {
+	contentType := req.Header.Get("Content-Type")
+	if isGRPCWebText(contentType) {
+		req.Body = io.NopCloser(base64.NewDecoder(base64.StdEncoding, req.Body))
+		req.ContentLength = -1
+		req.Header.Del("Content-Length")
+		contentType = strings.Replace(contentType, "application/grpc-web-text", "application/grpc", 1)
+	} else {
+		contentType = strings.Replace(contentType, "application/grpc-web", "application/grpc", 1)
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Del("X-Grpc-Web")
+}
+
+// translateGRPCWebResponse converte a resposta gRPC do backend para gRPC-Web:
+// os trailers HTTP/2 passam a ser um frame final do corpo, que os navegadores
+// conseguem ler
+func translateGRPCWebResponse(res *http.Response, text bool) {
+	contentType := res.Header.Get("Content-Type")
+	if !strings.HasPrefix(contentType, "application/grpc") {
+		return
+	}
+	webType := "application/grpc-web"
+	if text {
+		webType = "application/grpc-web-text"
+	}
+	res.Header.Set("Content-Type", strings.Replace(contentType, "application/grpc", webType, 1))
+	res.Header.Del("Content-Length")
+	res.Header.Del("Trailer")
+	res.ContentLength = -1
+
+	var body io.ReadCloser = &grpcWebBody{res: res, body: res.Body}
+	if text {
+		body = &base64Body{ReadCloser: body, chunk: make([]byte, 3*1024)}
+	}
+	res.Body = body
+	// Os trailers seguem no corpo; sem trailers declarados, o proxy não os
+	// repassa como trailers HTTP
+	res.Trailer = nil
+}
+
+// grpcWebBody repassa o corpo da resposta gRPC e, ao fim, acrescenta o frame
+// com os trailers recebidos do backend
+type grpcWebBody struct {
+	res     *http.Response
+	body    io.ReadCloser
+	done    bool
+	trailer []byte
+}
+
+// Read implementa io.Reader
+func (b *grpcWebBody) Read(p []byte) (int, error) {
+	if !b.done {
+		n, err := b.body.Read(p)
+		if err != io.EOF {
+			return n, err
+		}
+		// O transporte preenche os trailers da resposta ao fim do corpo; eles
+		// são consumidos aqui para não serem repassados também como trailers HTTP
+		b.done = true
+		b.trailer = grpcWebTrailerFrame(b.res.Trailer)
+		b.res.Trailer = nil
+		if n > 0 {
+			return n, nil
+		}
+	}
+	if len(b.trailer) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, b.trailer)
+	b.trailer = b.trailer[n:]
+	return n, nil
+}
+
+// Close implementa io.Closer
+func (b *grpcWebBody) Close() error {
+	return b.body.Close()
+}
+
+// grpcWebTrailerFrame monta o frame gRPC-Web com os trailers
+func grpcWebTrailerFrame(trailer http.Header) []byte {
+	names := make([]string, 0, len(trailer))
+	for name := range trailer {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var payload bytes.Buffer
+	for _, name := range names {
+		for _, value := range trailer[name] {
+			payload.WriteString(strings.ToLower(name) + ": " + value + "\r\n")
+		}
+	}
+
+	frame := make([]byte, 5, 5+payload.Len())
+	frame[0] = grpcWebTrailerFlag
+	binary.BigEndian.PutUint32(frame[1:], uint32(payload.Len()))
+	return append(frame, payload.Bytes()...)
+}
+
+// base64Body codifica o corpo em base64 para o modo texto do gRPC-Web. Cada
+// leitura gera um bloco completo, como permitido pelo protocolo.
+type base64Body struct {
+	io.ReadCloser
+	chunk   []byte
+	pending []byte
+}
+
+// Read implementa io.Reader
+func (b *base64Body) Read(p []byte) (int, error) {
+	if len(b.pending) == 0 {
+		n, err := b.ReadCloser.Read(b.chunk)
+		if n == 0 {
+			return 0, err
+		}
+		b.pending = []byte(base64.StdEncoding.EncodeToString(b.chunk[:n]))
+	}
+	n := copy(p, b.pending)
+	b.pending = b.pending[n:]
+	return n, nil
+}
+
+// grpcFlushInterval retorna o intervalo de flush do proxy para a rota; rotas
+// gRPC repassam os dados imediatamente
+func grpcFlushInterval(route *model.Route) time.Duration {
+	if route.IsGRPC() {
+		return -1
+	}
+	return 0
+}
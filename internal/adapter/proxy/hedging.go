@@ -0,0 +1,212 @@
+package proxy
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// minHedgeSamples é o número de latências observadas antes de usar o percentil;
+// até lá vale o atraso máximo da política
+const minHedgeSamples = 20
+
+// hedgeTransport dispara requisições adicionais a outros alvos da rota quando
+// a primeira demora mais que o atraso calculado, usando a primeira resposta
+// recebida e cancelando as demais
+type hedgeTransport struct {
+	next       http.RoundTripper
+	fallback   http.RoundTripper
+	route      *model.Route
+	serviceURL string
+	proxy      *ReverseProxy
+}
+
+// hedgeResult é o resultado de uma das requisições paralelas
+type hedgeResult struct {
+	index   int
+	res     *http.Response
+	err     error
+	latency time.Duration
+}
+
+// withHedging envolve o transporte com o hedging da rota. Requisições que não
+// podem ser duplicadas seguem pela política de novas tentativas.
+func (p *ReverseProxy) withHedging(transport http.RoundTripper, route *model.Route, serviceURL string) http.RoundTripper {
+	retry := p.withRetry(transport, route)
+	if route.Hedging == nil || route.IsGRPC() {
+		return retry
+	}
+	return &hedgeTransport{next: transport, fallback: retry, route: route, serviceURL: serviceURL, proxy: p}
+}
+
+func (t *hedgeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if (req.Method != http.MethodGet && req.Method != http.MethodHead) || (req.Body != nil && req.Body != http.NoBody) {
+		return t.fallback.RoundTrip(req)
+	}
+
+	ctx := req.Context()
+	policy := t.route.Hedging
+	targets := t.hedgeTargets()
+	results := make(chan hedgeResult, policy.Hedges()+1)
+	var cancels []context.CancelFunc
+
+	launch := func(target string) {
+		attemptCtx, cancel := context.WithCancel(ctx)
+		cancels = append(cancels, cancel)
+		attemptReq := req.Clone(attemptCtx)
+		if target != t.serviceURL {
+			retarget(attemptReq, req, target)
+		}
+		go func(index int) {
+			start := time.Now()
+			res, err := t.next.RoundTrip(attemptReq)
+			results <- hedgeResult{index: index, res: res, err: err, latency: time.Since(start)}
+		}(len(cancels) - 1)
+	}
+
+	launch(t.serviceURL)
+	pending := 1
+	hedges := 0
+
+	delay := t.delay()
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			if !t.proxy.retryBudget.TryRetry() {
+				t.proxy.logger.Debug("Orçamento de novas tentativas esgotado; hedge não enviado",
+					zap.String("path", t.route.Path))
+				t.recordMetric(req.Method, "retry_budget_exhausted")
+				continue
+			}
+			launch(targets[hedges%len(targets)])
+			hedges++
+			pending++
+			t.recordMetric(req.Method, "upstream_hedge")
+			if hedges < policy.Hedges() {
+				timer.Reset(delay)
+			}
+
+		case result := <-results:
+			pending--
+			if result.err != nil && pending > 0 {
+				// Outra requisição ainda pode responder
+				cancels[result.index]()
+				continue
+			}
+
+			for i, cancel := range cancels {
+				if i != result.index {
+					cancel()
+				}
+			}
+			if pending > 0 {
+				go discardHedges(results, pending)
+			}
+			if hedges > 0 {
+				trace.SpanFromContext(ctx).SetAttributes(
+					attribute.Int("proxy.hedges", hedges),
+					attribute.Bool("proxy.hedge_won", result.index > 0),
+				)
+			}
+			if result.err != nil {
+				cancels[result.index]()
+				return nil, result.err
+			}
+
+			t.proxy.latencies.Observe(t.route.Path, result.latency)
+			result.res.Body = cancelOnClose{ReadCloser: result.res.Body, cancel: cancels[result.index]}
+			return result.res, nil
+		}
+	}
+}
+
+// delay calcula o atraso antes de cada hedge: o valor fixo da política ou o
+// percentil das latências recentes da rota, limitado ao mínimo e ao máximo
+func (t *hedgeTransport) delay() time.Duration {
+	policy := t.route.Hedging
+	if fixed := policy.FixedDelay(); fixed > 0 {
+		return fixed
+	}
+	delay, ok := t.proxy.latencies.Percentile(t.route.Path, policy.PercentileValue(), minHedgeSamples)
+	if !ok {
+		return policy.MaxDelayDuration()
+	}
+	if minDelay := policy.MinDelayDuration(); delay < minDelay {
+		return minDelay
+	}
+	if maxDelay := policy.MaxDelayDuration(); delay > maxDelay {
+		return maxDelay
+	}
+	return delay
+}
+
+// hedgeTargets retorna os demais alvos disponíveis da rota, da mesma versão
+// do alvo principal; sem nenhum, o hedge vai ao próprio alvo principal
+func (t *hedgeTransport) hedgeTargets() []string {
+	upstreams := t.route.Upstreams()
+	version := ""
+	for _, target := range upstreams {
+		if target.URL == t.serviceURL {
+			version = target.Version
+			break
+		}
+	}
+
+	var targets []string
+	for _, target := range upstreams {
+		if target.URL == t.serviceURL || target.Version != version {
+			continue
+		}
+		if t.proxy.health != nil && !t.proxy.health.IsAvailable(t.route.Path, target.URL) {
+			continue
+		}
+		if t.proxy.circuitOpen(t.route, target.URL) {
+			continue
+		}
+		targets = append(targets, target.URL)
+	}
+	if len(targets) == 0 {
+		targets = []string{t.serviceURL}
+	}
+	return targets
+}
+
+func (t *hedgeTransport) recordMetric(method, errorType string) {
+	if t.proxy.metrics != nil {
+		t.proxy.metrics.RequestError(t.route.Path, method, errorType)
+	}
+}
+
+// retarget aponta a cópia da requisição para outro alvo da rota
+func retarget(attemptReq, req *http.Request, target string) {
+	targetURL, err := url.Parse(target)
+	if err != nil {
+		return
+	}
+	attemptReq.URL.Scheme = targetURL.Scheme
+	attemptReq.URL.Host = targetURL.Host
+	if req.Host == req.URL.Host {
+		attemptReq.Host = targetURL.Host
+	}
+}
+
+// discardHedges descarta as respostas das requisições perdedoras, já canceladas
+func discardHedges(results <-chan hedgeResult, pending int) {
+	for i := 0; i < pending; i++ {
+		result := <-results
+		if result.err == nil {
+			io.Copy(io.Discard, io.LimitReader(result.res.Body, maxDrainBytes))
+			result.res.Body.Close()
+		}
+	}
+}
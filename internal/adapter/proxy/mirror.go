@@ -0,0 +1,120 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/internal/infra/metrics"
+	"go.uber.org/zap"
+)
+
+// maxConcurrentMirrors limita as requisições espelhadas em andamento; acima
+// dele, novas cópias são descartadas para não afetar o tráfego real
+const maxConcurrentMirrors = 256
+
+// MirrorHeader identifica no backend sombra as requisições espelhadas
+const MirrorHeader = "X-Gateway-Mirror"
+
+// mirror envia em segundo plano uma cópia da requisição ao backend sombra da
+// rota, conforme a porcentagem configurada. O corpo é guardado para ser
+// enviado aos dois backends; corpos acima do limite não são espelhados.
+func (p *ReverseProxy) mirror(route *model.Route, r *http.Request) {
+	policy := route.Mirror
+	if policy == nil || rand.Float64()*100 >= policy.Percentage {
+		return
+	}
+
+	select {
+	case p.mirrors <- struct{}{}:
+	default:
+		p.recordMirror(route, "dropped")
+		return
+	}
+	release := func() { <-p.mirrors }
+
+	var body []byte
+	if r.Body != nil && r.Body != http.NoBody {
+		limit := policy.BodyLimit()
+		if r.ContentLength > limit {
+			release()
+			p.recordMirror(route, "body_too_large")
+			return
+		}
+		data, err := io.ReadAll(io.LimitReader(r.Body, limit+1))
+		if err != nil || int64(len(data)) > limit {
+			// O corpo segue inteiro para o backend, inclusive o erro de leitura
+			rest := r.Body
+			if err != nil {
+				rest = readCloser{Reader: errorReader{err: err}, Closer: r.Body}
+			}
+			r.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(data), rest), Closer: r.Body}
+			release()
+			p.recordMirror(route, "body_too_large")
+			return
+		}
+		r.Body = readCloser{Reader: bytes.NewReader(data), Closer: r.Body}
+		body = data
+	}
+
+	mirrorURL, err := url.Parse(policy.URL)
+	if err != nil {
+		release()
+		return
+	}
+	transport, err := p.UpstreamTransport(route)
+	if err != nil {
+		release()
+		p.recordMirror(route, "error")
+		return
+	}
+
+	// A cópia não é cancelada com a requisição original
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), policy.TimeoutDuration())
+	req := r.Clone(ctx)
+	req.RequestURI = ""
+	req.Body = http.NoBody
+	req.ContentLength = int64(len(body))
+	if body != nil {
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	rewriteRequest(ctx, req, r, route, mirrorURL)
+	req.Header.Set(MirrorHeader, "true")
+
+	go func() {
+		defer release()
+		defer cancel()
+
+		res, err := transport.RoundTrip(req)
+		if err != nil {
+			p.logger.Debug("Falha na requisição espelhada",
+				zap.String("path", route.Path),
+				zap.String("mirror", policy.URL),
+				zap.Error(err))
+			p.recordMirror(route, "error")
+			return
+		}
+		io.Copy(io.Discard, io.LimitReader(res.Body, maxDrainBytes))
+		res.Body.Close()
+		p.recordMirror(route, metrics.StatusClass(res.StatusCode))
+	}()
+}
+
+func (p *ReverseProxy) recordMirror(route *model.Route, result string) {
+	if p.metrics != nil {
+		p.metrics.MirrorRequest(route.Path, result)
+	}
+}
+
+// errorReader devolve o erro de leitura do corpo original após os dados já lidos
+type errorReader struct {
+	err error
+}
+
+func (r errorReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
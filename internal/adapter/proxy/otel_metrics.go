@@ -0,0 +1,33 @@
+package proxy
+
+import (
+	"context"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/infra/metrics"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelmetric "go.opentelemetry.io/otel/metric"
+)
+
+// upstreamDuration mede a duração das requisições aos backends. Sem a
+// exportação OpenTelemetry habilitada, o registro não tem efeito.
+var upstreamDuration, _ = otel.Meter("api-gateway.proxy").Float64Histogram(
+	"api_gateway.upstream.duration",
+	otelmetric.WithDescription("Duração das requisições encaminhadas aos backends"),
+	otelmetric.WithUnit("s"),
+)
+
+// recordUpstreamDuration registra a duração da requisição ao backend da rota;
+// status zero indica falha de conexão
+func recordUpstreamDuration(ctx context.Context, routePath, method string, status int, duration time.Duration) {
+	class := "error"
+	if status > 0 {
+		class = metrics.StatusClass(status)
+	}
+	upstreamDuration.Record(ctx, duration.Seconds(), otelmetric.WithAttributes(
+		attribute.String("route", routePath),
+		attribute.String("http.request.method", method),
+		attribute.String("http.response.status_class", class),
+	))
+}
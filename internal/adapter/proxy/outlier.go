@@ -0,0 +1,256 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/internal/infra/metrics"
+	"github.com/diillson/api-gateway-go/pkg/resilience"
+	"go.uber.org/zap"
+)
+
+// minRecoveryShare é a fração mínima do tráfego enviada ao alvo no início da
+// recuperação, para que ele volte a ser medido
+const minRecoveryShare = 0.1
+
+// OutlierDetector acompanha as respostas de cada alvo das rotas com
+// OutlierDetection e retira temporariamente do balanceamento os que destoam
+// dos demais. Ao fim da retirada, o alvo recebe uma parte crescente do tráfego
+// durante o período de recuperação.
+type OutlierDetector struct {
+	mu      sync.Mutex
+	pools   map[string]*outlierPool // rota -> estatísticas dos alvos
+	metrics *metrics.APIMetrics
+	logger  *zap.Logger
+}
+
+// outlierPool são as estatísticas dos alvos de uma rota no intervalo atual
+type outlierPool struct {
+	targets       map[string]*outlierTarget
+	intervalStart time.Time
+}
+
+type outlierTarget struct {
+	requests     int
+	errors       int
+	latency      time.Duration
+	consecutive  int       // erros seguidos
+	ejections    int       // retiradas seguidas, que multiplicam o tempo da próxima
+	ejected      bool      // retirado e ainda não devolvido ao balanceamento
+	ejectedUntil time.Time // fim da retirada e início da recuperação
+	lastSeen     time.Time
+}
+
+// NewOutlierDetector cria o detector de alvos anômalos
+func NewOutlierDetector(metrics *metrics.APIMetrics, logger *zap.Logger) *OutlierDetector {
+	return &OutlierDetector{
+		pools:   make(map[string]*outlierPool),
+		metrics: metrics,
+		logger:  logger,
+	}
+}
+
+// Available indica se o alvo pode receber a requisição. Durante a
+// recuperação, o alvo é aceito na proporção do tempo já decorrido.
+func (d *OutlierDetector) Available(route *model.Route, targetURL string) bool {
+	if route.OutlierDetection == nil {
+		return true
+	}
+
+	now := time.Now()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	pool, ok := d.pools[route.Path]
+	if !ok {
+		return true
+	}
+	target, ok := pool.targets[targetURL]
+	if !ok {
+		return true
+	}
+	if now.Before(target.ejectedUntil) {
+		return false
+	}
+	if target.ejected {
+		target.ejected = false
+		d.logger.Info("Alvo devolvido ao balanceamento após retirada por anomalia",
+			zap.String("path", route.Path),
+			zap.String("target", targetURL))
+		if d.metrics != nil {
+			d.metrics.UpstreamHealthChanged(route.Path, targetURL, "outlier", true)
+		}
+	}
+
+	recovery := route.OutlierDetection.RecoveryDuration()
+	if elapsed := now.Sub(target.ejectedUntil); recovery > 0 && elapsed < recovery {
+		share := max(minRecoveryShare, float64(elapsed)/float64(recovery))
+		return rand.Float64() < share
+	}
+	return true
+}
+
+// Record contabiliza a resposta do alvo. Falhas são respostas 5xx e erros de
+// conexão ou de tempo esgotado.
+func (d *OutlierDetector) Record(route *model.Route, targetURL string, failed bool, latency time.Duration) {
+	policy := route.OutlierDetection
+	if policy == nil {
+		return
+	}
+
+	now := time.Now()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	pool, ok := d.pools[route.Path]
+	if !ok {
+		pool = &outlierPool{targets: make(map[string]*outlierTarget), intervalStart: now}
+		d.pools[route.Path] = pool
+	}
+	target, ok := pool.targets[targetURL]
+	if !ok {
+		target = &outlierTarget{}
+		pool.targets[targetURL] = target
+	}
+
+	target.lastSeen = now
+	target.requests++
+	target.latency += latency
+	if failed {
+		target.errors++
+		target.consecutive++
+	} else {
+		target.consecutive = 0
+	}
+
+	if threshold := policy.ErrorThreshold(); threshold > 0 && target.consecutive >= threshold && !target.ejected {
+		d.eject(route, pool, targetURL, target, "consecutive_errors", now)
+	}
+
+	if now.Sub(pool.intervalStart) >= policy.IntervalDuration() {
+		d.analyze(route, pool, now)
+	}
+}
+
+// analyze compara os alvos com volume suficiente no intervalo, retirando os
+// que têm taxa de erros ou latência muito acima das dos demais, e inicia um
+// novo intervalo
+func (d *OutlierDetector) analyze(route *model.Route, pool *outlierPool, now time.Time) {
+	policy := route.OutlierDetection
+
+	var eligible []string
+	for url, target := range pool.targets {
+		if !target.ejected && target.requests >= policy.RequestVolume() {
+			eligible = append(eligible, url)
+		}
+	}
+	sort.Strings(eligible)
+
+	// Cada alvo é comparado aos demais, e não à média que ele mesmo distorce
+	if len(eligible) >= 2 {
+		for _, url := range eligible {
+			target := pool.targets[url]
+			var otherRequests, otherErrors int
+			var otherLatencies []time.Duration
+			for _, other := range eligible {
+				if other == url {
+					continue
+				}
+				stats := pool.targets[other]
+				otherRequests += stats.requests
+				otherErrors += stats.errors
+				otherLatencies = append(otherLatencies, stats.latency/time.Duration(stats.requests))
+			}
+
+			errorRate := float64(target.errors) / float64(target.requests)
+			otherRate := float64(otherErrors) / float64(otherRequests)
+			if margin := policy.ErrorMargin(); margin > 0 && errorRate-otherRate > margin {
+				d.eject(route, pool, url, target, "error_rate", now)
+				continue
+			}
+
+			average := target.latency / time.Duration(target.requests)
+			if factor := policy.LatencyFactor; factor > 0 && float64(average) > factor*float64(medianDuration(otherLatencies)) {
+				d.eject(route, pool, url, target, "latency", now)
+			}
+		}
+	}
+
+	// Alvos que atenderam o intervalo sem serem retirados voltam aos poucos ao
+	// tempo base de retirada; alvos sem tráfego há muito tempo são descartados
+	staleAfter := max(10*policy.IntervalDuration(), policy.MaxEjectionDuration())
+	for url, target := range pool.targets {
+		if !target.ejected && now.Sub(target.lastSeen) > staleAfter {
+			delete(pool.targets, url)
+			continue
+		}
+		if !target.ejected && target.ejections > 0 && target.requests > 0 {
+			target.ejections--
+		}
+		target.requests, target.errors, target.latency = 0, 0, 0
+	}
+	pool.intervalStart = now
+}
+
+// eject retira o alvo do balanceamento, respeitando o percentual máximo de
+// alvos retirados. O tempo de retirada cresce a cada retirada seguida.
+func (d *OutlierDetector) eject(route *model.Route, pool *outlierPool, targetURL string, target *outlierTarget, reason string, now time.Time) {
+	policy := route.OutlierDetection
+
+	ejected := 0
+	for _, other := range pool.targets {
+		if other.ejected {
+			ejected++
+		}
+	}
+	if ejected >= max(1, len(pool.targets)*policy.MaxEjectedPercent()/100) {
+		return
+	}
+
+	target.ejections++
+	duration := min(policy.BaseEjectionDuration()*time.Duration(target.ejections), policy.MaxEjectionDuration())
+	target.ejected = true
+	target.ejectedUntil = now.Add(duration)
+	target.consecutive = 0
+
+	d.logger.Warn("Alvo retirado do balanceamento por anomalia",
+		zap.String("path", route.Path),
+		zap.String("target", targetURL),
+		zap.String("reason", reason),
+		zap.Duration("duration", duration),
+		zap.Int("ejections", target.ejections))
+	if d.metrics != nil {
+		d.metrics.UpstreamEjected(route.Path, targetURL, reason)
+		d.metrics.UpstreamHealthChanged(route.Path, targetURL, "outlier", false)
+	}
+}
+
+// recordOutlier contabiliza o resultado da requisição ao alvo na detecção de
+// alvos anômalos. Requisições recusadas pelo circuit breaker e canceladas pelo
+// cliente não dizem nada sobre o alvo.
+func (p *ReverseProxy) recordOutlier(route *model.Route, targetURL string, r *http.Request, err error, latency time.Duration) {
+	if route.OutlierDetection == nil || errors.Is(err, resilience.ErrCircuitOpen) {
+		return
+	}
+	if errors.Is(r.Context().Err(), context.Canceled) {
+		return
+	}
+	p.outliers.Record(route, targetURL, err != nil, latency)
+}
+
+// medianDuration retorna a mediana das durações
+func medianDuration(values []time.Duration) time.Duration {
+	sorted := append([]time.Duration(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
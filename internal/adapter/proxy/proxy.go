@@ -2,10 +2,15 @@ package proxy
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/internal/infra/healthcheck"
 	"github.com/diillson/api-gateway-go/internal/infra/metrics"
 	"github.com/diillson/api-gateway-go/pkg/cache"
+	"github.com/diillson/api-gateway-go/pkg/cluster"
+	"github.com/diillson/api-gateway-go/pkg/config"
+	"github.com/diillson/api-gateway-go/pkg/dnscache"
 	"github.com/diillson/api-gateway-go/pkg/resilience"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -16,8 +21,8 @@ import (
 	"net/http"
 	"net/http/httputil"
 	"net/url"
-	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -25,10 +30,26 @@ import (
 type ReverseProxy struct {
 	cache           cache.Cache
 	circuitBreakers map[string]*resilience.CircuitBreaker
+	circuitConfigs  map[string]resilience.CircuitBreakerConfig
 	cbLock          sync.RWMutex
 	logger          *zap.Logger
 	metrics         *metrics.APIMetrics
 	tracer          trace.Tracer
+	transports      *TransportPool
+	responseCache   *ResponseCache
+	health          *healthcheck.Registry
+	balancer        *Balancer
+	outliers        *OutlierDetector
+	cluster         *cluster.Node
+	wsLock          sync.Mutex
+	wsConnections   map[string]int // rota -> conexões WebSocket abertas
+	bulkheadLock    sync.Mutex
+	bulkheads       map[string]*bulkheadEntry // rota ou rota|alvo -> limitador de concorrência
+	retryBudget     *resilience.RetryBudget
+	latencies       *resilience.LatencyTracker
+	services        ServiceResolver
+	mirrors         chan struct{}            // vagas das requisições espelhadas em andamento
+	stripHeaders    atomic.Pointer[[]string] // cabeçalhos retirados das respostas dos backends
 }
 
 // NewReverseProxy cria um novo ReverseProxy
@@ -41,25 +62,116 @@ func NewReverseProxy(cache cache.Cache, logger *zap.Logger) *ReverseProxy {
 	return &ReverseProxy{
 		cache:           cache,
 		circuitBreakers: make(map[string]*resilience.CircuitBreaker),
+		circuitConfigs:  make(map[string]resilience.CircuitBreakerConfig),
 		logger:          logger,
 		tracer:          tracer,
+		transports:      NewTransportPool(logger),
+		balancer:        NewBalancer(),
+		outliers:        NewOutlierDetector(nil, logger),
+		wsConnections:   make(map[string]int),
+		bulkheads:       make(map[string]*bulkheadEntry),
+		latencies:       resilience.NewLatencyTracker(),
+		mirrors:         make(chan struct{}, maxConcurrentMirrors),
 	}
 }
 
+// SetRetryBudget limita as novas tentativas e os hedges de todas as rotas a
+// uma fração das requisições recentes
+func (p *ReverseProxy) SetRetryBudget(budget *resilience.RetryBudget) {
+	p.retryBudget = budget
+}
+
 // SetMetrics configura as métricas para o proxy
 func (p *ReverseProxy) SetMetrics(metrics *metrics.APIMetrics) {
 	p.metrics = metrics
+	p.outliers.metrics = metrics
+}
+
+// SetEgressProxy configura o proxy de saída global usado para alcançar os backends
+func (p *ReverseProxy) SetEgressProxy(cfg config.EgressProxyConfig) {
+	p.transports.SetEgressProxy(cfg)
+}
+
+// SetTransport configura os ajustes globais do pool de conexões com os backends
+func (p *ReverseProxy) SetTransport(cfg config.TransportConfig) {
+	p.transports.SetTransport(cfg)
+}
+
+// SetResolver resolve os backends pelo cache de DNS, renovando as conexões
+// quando os endereços mudam
+func (p *ReverseProxy) SetResolver(resolver *dnscache.Resolver) {
+	p.transports.SetResolver(resolver)
+}
+
+// SetResponseCache habilita o cache de respostas dos backends
+func (p *ReverseProxy) SetResponseCache(responseCache *ResponseCache) {
+	p.responseCache = responseCache
+}
+
+// SetHealthRegistry configura o registro de saúde consultado antes de encaminhar
+func (p *ReverseProxy) SetHealthRegistry(registry *healthcheck.Registry) {
+	p.health = registry
+}
+
+// InvalidateResponses descarta as respostas da rota armazenadas no cache de
+// respostas. Sem cache de respostas habilitado, não faz nada.
+func (p *ReverseProxy) InvalidateResponses(ctx context.Context, routePath string) error {
+	if p.responseCache == nil {
+		return nil
+	}
+	return p.responseCache.Invalidate(ctx, routePath)
 }
 
+// UpstreamTransport retorna o transporte usado para alcançar o backend da rota,
+// permitindo que as verificações de saúde sigam o mesmo caminho do tráfego.
+// Rotas gRPC e com HTTP/2 sem TLS (h2c) usam o transporte HTTP/2.
+func (p *ReverseProxy) UpstreamTransport(route *model.Route) (http.RoundTripper, error) {
+	if route.IsGRPC() || route.ConnectionPool.H2C() {
+		return p.transports.GetGRPC(route)
+	}
+	return p.transports.Get(route)
+}
+
+// ErrUpstreamUnhealthy indica que o backend foi marcado como indisponível pela verificação ativa
+var ErrUpstreamUnhealthy = errors.New("backend marcado como indisponível pela verificação de saúde")
+
 // ProxyRequest encaminha uma requisição para o backend
 func (p *ReverseProxy) ProxyRequest(route *model.Route, w http.ResponseWriter, r *http.Request) error {
 	// Obter o contexto atual com o span
 	ctx := r.Context()
 
+	// Trocar os Services do Kubernetes pelos pods prontos
+	route, err := p.resolveServices(ctx, route)
+	if err != nil {
+		p.logger.Warn("Backend da rota sem endpoints disponíveis",
+			zap.String("path", r.URL.Path),
+			zap.Error(err))
+		if p.metrics != nil {
+			p.metrics.RequestError(r.URL.Path, r.Method, "no_endpoints")
+		}
+		writeError(w, r, route, http.StatusServiceUnavailable, model.ErrorCodeUpstreamUnavailable)
+		return err
+	}
+
+	// Escolher o alvo entre os backends da rota não marcados como indisponíveis,
+	// evitando os que estão com o circuito aberto ou retirados por anomalia
+	healthy := func(targetURL string) bool {
+		return p.health == nil || p.health.IsAvailable(route.Path, targetURL)
+	}
+	target, release, available := p.pickTarget(route, w, r, func(targetURL string) bool {
+		return healthy(targetURL) && !p.circuitOpen(route, targetURL) && p.outliers.Available(route, targetURL)
+	})
+	if !available {
+		// Com todos os circuitos abertos, o circuit breaker do alvo escolhido
+		// responde rapidamente em vez de tratar a rota como indisponível
+		target, release, available = p.pickTarget(route, w, r, healthy)
+	}
+	defer release()
+
 	// Criar um novo span para esta operação de proxy
 	ctx, span := p.tracer.Start(
 		ctx,
-		fmt.Sprintf("Proxy %s -> %s", r.URL.Path, route.ServiceURL),
+		fmt.Sprintf("Proxy %s -> %s", r.URL.Path, target.URL),
 		trace.WithSpanKind(trace.SpanKindClient),
 	)
 	defer span.End()
@@ -69,25 +181,115 @@ func (p *ReverseProxy) ProxyRequest(route *model.Route, w http.ResponseWriter, r
 
 	// Adicionar atributos relevantes ao span
 	span.SetAttributes(
-		attribute.String("proxy.target_url", route.ServiceURL),
+		attribute.String("proxy.target_url", target.URL),
 		attribute.String("proxy.source_path", r.URL.Path),
 		attribute.StringSlice("proxy.allowed_methods", route.Methods),
 		attribute.Bool("proxy.is_active", route.IsActive),
+		attribute.String("proxy.load_balancing", route.Balancing()),
+		attribute.Int("proxy.targets", len(route.Upstreams())),
 	)
-	// Verifica se o circuito está aberto para este serviço
-	cb := p.getCircuitBreaker(route.ServiceURL)
+	if route.Canary != nil {
+		span.SetAttributes(attribute.String("proxy.canary.version", target.Version))
+	}
+	if route.SessionAffinity != nil {
+		span.SetAttributes(attribute.String("proxy.session_affinity", route.SessionAffinity.Source))
+	}
+
+	// Não encaminhar quando todos os backends estão marcados como indisponíveis
+	if !available {
+		span.SetStatus(codes.Error, ErrUpstreamUnhealthy.Error())
+		span.SetAttributes(attribute.Bool("error", true))
+		span.SetAttributes(attribute.String("error.message", ErrUpstreamUnhealthy.Error()))
+
+		if p.metrics != nil {
+			p.metrics.RequestError(r.URL.Path, r.Method, "upstream_unhealthy")
+		}
+		writeError(w, r, route, http.StatusServiceUnavailable, model.ErrorCodeUpstreamUnavailable)
+		return ErrUpstreamUnhealthy
+	}
+
+	// Conexões WebSocket seguem sem cache de respostas, timeout de requisição
+	// nem circuit breaker, pois permanecem abertas enquanto houver tráfego
+	if IsWebSocketRequest(r) {
+		span.SetAttributes(attribute.Bool("proxy.websocket", true))
+		if err := p.proxyWebSocket(route, target.URL, w, r); err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.SetAttributes(attribute.Bool("error", true))
+			span.SetAttributes(attribute.String("error.message", err.Error()))
+			return err
+		}
+		span.SetStatus(codes.Ok, "")
+		return nil
+	}
+
+	// Atender pelo cache de respostas, quando habilitado para a rota
+	cacheKey := ""
+	if p.responseCache != nil && p.responseCache.Cacheable(route, r) {
+		cacheKey = p.responseCache.Key(ctx, route, r)
+		entry, ok := p.responseCache.Lookup(ctx, route, cacheKey, r)
+		if p.metrics != nil {
+			p.metrics.RouteCacheLookup(route.Path, ok)
+		}
+		if ok {
+			span.SetAttributes(attribute.Bool("proxy.cache.hit", true))
+			if err := p.responseCache.Serve(w, r, entry); err != nil {
+				p.logger.Warn("Falha ao servir resposta do cache",
+					zap.String("path", r.URL.Path),
+					zap.Error(err))
+			}
+			span.SetStatus(codes.Ok, "")
+			return nil
+		}
+		span.SetAttributes(attribute.Bool("proxy.cache.hit", false))
+	}
+
+	// Enviar uma cópia da requisição ao backend sombra. Chamadas gRPC são
+	// streams e não são espelhadas.
+	if !route.IsGRPC() {
+		p.mirror(route, r)
+	}
+
+	// Reservar vagas na rota e no alvo, aguardando na fila quando estão ocupadas
+	releaseBulkhead, err := p.acquireBulkhead(ctx, route, target.URL)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		span.SetAttributes(attribute.Bool("error", true))
+		span.SetAttributes(attribute.String("error.message", err.Error()))
+
+		if p.metrics != nil {
+			p.metrics.RequestError(r.URL.Path, r.Method, "bulkhead_rejected")
+		}
+		writeBulkheadFull(w, r, route)
+		return err
+	}
+	defer releaseBulkhead()
+
+	// Contabilizar a requisição no orçamento de novas tentativas
+	p.retryBudget.RecordRequest()
+
+	// Verifica se o circuito está aberto para este alvo da rota
+	cb := p.getCircuitBreaker(circuitKey(route.Path, target.URL), route.CircuitBreaker)
 
 	// Propagar o contexto de tracing para o serviço downstream
 	propagator := otel.GetTextMapPropagator()
 	carrier := propagation.HeaderCarrier(r.Header)
 	propagator.Inject(ctx, carrier)
 
-	// Cria contexto com timeout para a requisição
-	ctxWithTimeout, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	// Cria contexto com o timeout da rota, que inclui as novas tentativas.
+	// Chamadas gRPC seguem o prazo informado pelo cliente, pois podem ser
+	// streams de longa duração.
+	var ctxWithTimeout context.Context
+	var cancel context.CancelFunc
+	if route.IsGRPC() {
+		ctxWithTimeout, cancel = grpcContext(r.Context(), r.Header)
+	} else {
+		ctxWithTimeout, cancel = context.WithTimeout(r.Context(), route.UpstreamTimeout())
+	}
 	defer cancel()
 
 	// Executa a requisição através do circuit breaker
-	_, err := cb.Execute(ctxWithTimeout, func(execCtx context.Context) (interface{}, error) {
+	upstreamStart := time.Now()
+	_, err = cb.Execute(ctxWithTimeout, func(execCtx context.Context) (interface{}, error) {
 		// Atualizar o request com o contexto de execução
 		execRequest := r.WithContext(execCtx)
 
@@ -99,7 +301,10 @@ func (p *ReverseProxy) ProxyRequest(route *model.Route, w http.ResponseWriter, r
 		)
 		defer execSpan.End()
 
-		result, err := p.doProxy(route, w, execRequest)
+		result, err := p.doProxy(route, target.URL, w, execRequest, cacheKey)
+		if status, ok := result.(int); ok && err == nil && status >= http.StatusInternalServerError {
+			err = &upstreamStatusError{status: status}
+		}
 
 		if err != nil {
 			execSpan.SetStatus(codes.Error, err.Error())
@@ -112,6 +317,37 @@ func (p *ReverseProxy) ProxyRequest(route *model.Route, w http.ResponseWriter, r
 		return result, err
 	})
 
+	p.recordOutlier(route, target.URL, r, err, time.Since(upstreamStart))
+
+	span.SetAttributes(
+		attribute.String("circuit_breaker.name", cb.Name()),
+		attribute.String("circuit_breaker.state", cb.GetState().String()),
+	)
+
+	// Circuito aberto: responder imediatamente sem acionar o backend
+	if errors.Is(err, resilience.ErrCircuitOpen) {
+		span.SetStatus(codes.Error, err.Error())
+		span.SetAttributes(attribute.Bool("error", true))
+		span.SetAttributes(attribute.String("error.message", err.Error()))
+		span.SetAttributes(attribute.Bool("circuit_breaker.request_rejected", true))
+
+		if p.metrics != nil {
+			p.metrics.RequestError(r.URL.Path, r.Method, "circuit_open")
+		}
+		writeCircuitOpen(w, r, route, cb.RetryAfter())
+		return err
+	}
+
+	// Erro de servidor do backend: a resposta já foi repassada ao cliente e
+	// conta apenas como falha para o circuit breaker
+	var statusErr *upstreamStatusError
+	if errors.As(err, &statusErr) {
+		span.SetStatus(codes.Error, err.Error())
+		span.SetAttributes(attribute.Bool("error", true))
+		span.SetAttributes(attribute.String("error.message", err.Error()))
+		return nil
+	}
+
 	if err != nil {
 		span.SetStatus(codes.Error, err.Error())
 		span.SetAttributes(attribute.Bool("error", true))
@@ -123,18 +359,19 @@ func (p *ReverseProxy) ProxyRequest(route *model.Route, w http.ResponseWriter, r
 	return nil
 }
 
-// doProxy executa o proxy reverso real
-func (p *ReverseProxy) doProxy(route *model.Route, w http.ResponseWriter, r *http.Request) (interface{}, error) {
+// doProxy executa o proxy reverso real contra o alvo escolhido. Com cacheKey
+// preenchida, a resposta do backend é armazenada no cache de respostas.
+func (p *ReverseProxy) doProxy(route *model.Route, serviceURL string, w http.ResponseWriter, r *http.Request, cacheKey string) (interface{}, error) {
 	// Obter o contexto com span
 	ctx := r.Context()
 
 	// Criar span para esta operação
 	ctx, span := p.tracer.Start(
 		ctx,
-		fmt.Sprintf("ProxyRequest:%s->%s", r.URL.Path, route.ServiceURL),
+		fmt.Sprintf("ProxyRequest:%s->%s", r.URL.Path, serviceURL),
 		trace.WithSpanKind(trace.SpanKindClient),
 		trace.WithAttributes(
-			attribute.String("proxy.target_url", route.ServiceURL),
+			attribute.String("proxy.target_url", serviceURL),
 			attribute.String("proxy.source_path", r.URL.Path),
 			attribute.StringSlice("proxy.allowed_methods", route.Methods),
 			attribute.Bool("proxy.is_active", route.IsActive),
@@ -149,60 +386,93 @@ func (p *ReverseProxy) doProxy(route *model.Route, w http.ResponseWriter, r *htt
 	p.logger.Info("Executando proxy com trace",
 		zap.String("trace_id", span.SpanContext().TraceID().String()),
 		zap.String("span_id", span.SpanContext().SpanID().String()),
-		zap.String("target", route.ServiceURL),
+		zap.String("target", serviceURL),
 		zap.String("path", r.URL.Path))
 
-	targetURL, err := url.Parse(route.ServiceURL)
+	targetURL, err := url.Parse(serviceURL)
 	if err != nil {
 		span.SetStatus(codes.Error, "failed to parse service URL")
 		span.SetAttributes(attribute.Bool("error", true))
 		span.SetAttributes(attribute.String("error.message", err.Error()))
 
 		p.logger.Error("falha ao analisar URL do serviço",
-			zap.String("serviceURL", route.ServiceURL),
+			zap.String("serviceURL", serviceURL),
 			zap.Error(err))
-		http.Error(w, "Erro interno do servidor", http.StatusInternalServerError)
+		writeError(w, r, route, http.StatusInternalServerError, model.ErrorCodeUpstreamMisconfigured)
 		return nil, err
 	}
 
+	// Obter o transporte com a configuração de conexão da rota (TLS, mTLS)
+	transport, err := p.UpstreamTransport(route)
+	if err != nil {
+		span.SetStatus(codes.Error, "failed to build upstream transport")
+		span.SetAttributes(attribute.Bool("error", true))
+		span.SetAttributes(attribute.String("error.message", err.Error()))
+
+		p.logger.Error("falha ao preparar conexão com o backend",
+			zap.String("path", route.Path),
+			zap.Error(err))
+		writeError(w, r, route, http.StatusBadGateway, model.ErrorCodeUpstreamMisconfigured)
+		return nil, err
+	}
+
+	if route.TLS != nil {
+		span.SetAttributes(
+			attribute.Bool("proxy.tls.custom", true),
+			attribute.Bool("proxy.tls.client_cert", route.TLS.CertFile != ""),
+			attribute.Bool("proxy.tls.insecure_skip_verify", route.TLS.InsecureSkipVerify),
+		)
+	}
+	if route.EgressProxy != "" {
+		span.SetAttributes(attribute.String("proxy.egress", route.Redacted().EgressProxy))
+	}
+
 	// Registrar métricas para esta requisição de proxy, se disponível
 	if p.metrics != nil {
 		p.metrics.RequestStarted(route.Path, r.Method)
 	}
 
+	// Codificações aceitas pelo cliente, usadas para recodificar respostas armazenadas no cache
+	clientAcceptEncoding := r.Header.Get("Accept-Encoding")
+
+	// Requisições gRPC-Web são traduzidas para gRPC quando a rota permite
+	grpcWeb := route.GRPCWeb && isGRPCWebRequest(r)
+	grpcWebText := grpcWeb && isGRPCWebText(r.Header.Get("Content-Type"))
+	if grpcWeb {
+		// O backend gRPC exige o anúncio de suporte a trailers
+		r.Header.Set("Te", "trailers")
+		span.SetAttributes(attribute.Bool("proxy.grpc_web", true))
+	}
+
+	// Status da resposta e erro de conexão do backend, usados pelo circuit breaker
+	var status int
+	var upstreamErr error
+
 	// Cria o proxy reverso com tracing
 	proxy := &httputil.ReverseProxy{
+		Transport: p.withHedging(transport, route, serviceURL),
+
+		// Respostas gRPC são streams: repassar cada mensagem assim que chega
+		FlushInterval: grpcFlushInterval(route),
+
 		Director: func(req *http.Request) {
-			// Preservar o caminho e a query string
-			req.URL.Scheme = targetURL.Scheme
-			req.URL.Host = targetURL.Host
-			req.URL.Path = r.URL.Path
-			req.URL.RawQuery = r.URL.RawQuery
-
-			// Preservar o IP original
-			req.Header.Set("X-Forwarded-For", r.RemoteAddr)
-			req.Header.Set("X-Forwarded-Host", r.Host)
-			req.Host = targetURL.Host
-
-			// Adicionar cabeçalhos personalizados, se necessário
-			for _, header := range route.Headers {
-				if val := r.Header.Get(header); val != "" {
-					req.Header.Set(header, val)
-				}
-			}
+			rewriteRequest(ctx, req, r, route, targetURL)
 
-			// ADICIONADO: Propagar explicitamente o contexto de tracing para o serviço downstream
-			otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+			// Para respostas armazenadas no cache ou transformadas, deixar o
+			// transporte negociar a compressão com o backend e entregar o corpo
+			// já descomprimido
+			if cacheKey != "" || (route.BodyTransforms != nil && route.BodyTransforms.Response != nil) {
+				req.Header.Del("Accept-Encoding")
+			}
 
-			// Adicionar span ID como cabeçalho para correlação
-			spanContext := trace.SpanContextFromContext(ctx)
-			if spanContext.IsValid() {
-				req.Header.Set("X-Trace-ID", spanContext.TraceID().String())
-				req.Header.Set("X-Span-ID", spanContext.SpanID().String())
+			if grpcWeb {
+				translateGRPCWebRequest(req)
 			}
 		},
 
 		ModifyResponse: func(res *http.Response) error {
+			status = res.StatusCode
+
 			// Adicionar informações da resposta ao span
 			span.SetAttributes(
 				attribute.Int("http.response.status_code", res.StatusCode),
@@ -218,14 +488,45 @@ func (p *ReverseProxy) doProxy(route *model.Route, w http.ResponseWriter, r *htt
 				span.SetStatus(codes.Ok, "")
 			}
 
+			if grpcWeb {
+				translateGRPCWebResponse(res, grpcWebText)
+			}
+
+			p.sanitizeResponseHeaders(res, w.Header())
+
+			// Trocar o corpo das falhas do backend pelo erro padronizado da rota
+			if !route.IsGRPC() {
+				normalizeUpstreamError(res, route)
+			}
+
+			// Adaptar o corpo JSON antes de armazená-lo e entregá-lo ao cliente
+			p.transformResponseBody(res, route)
+
+			if cacheKey != "" {
+				return p.responseCache.Capture(res, route, cacheKey, clientAcceptEncoding)
+			}
+
 			return nil
 		},
 
 		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			// Corpo da requisição acima do limite da rota: falha do cliente, e não
+			// do backend, então não conta para o circuit breaker
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				span.SetAttributes(attribute.Bool("request_body.too_large", true))
+				if p.metrics != nil {
+					p.metrics.RequestError(r.URL.Path, r.Method, "body_too_large")
+				}
+				writeError(w, r, route, http.StatusRequestEntityTooLarge, model.ErrorCodeRequestBodyTooLarge)
+				return
+			}
+
 			p.logger.Error("erro no proxy",
 				zap.String("path", r.URL.Path),
-				zap.String("serviceURL", route.ServiceURL),
+				zap.String("serviceURL", serviceURL),
 				zap.Error(err))
+			upstreamErr = err
 
 			// Adicionar detalhes do erro ao span
 			span.SetStatus(codes.Error, err.Error())
@@ -233,36 +534,28 @@ func (p *ReverseProxy) doProxy(route *model.Route, w http.ResponseWriter, r *htt
 			span.SetAttributes(attribute.String("error.message", err.Error()))
 			span.RecordError(err) // ADICIONADO: Registrar o erro explicitamente
 
-			// Determinar o tipo de erro e status HTTP apropriado
-			var errorType string
-			var statusCode int
-
-			// Analisar o erro para determinar o tipo apropriado
-			if strings.Contains(err.Error(), "context deadline exceeded") {
-				errorType = "timeout_error"
-				statusCode = http.StatusGatewayTimeout
-			} else if strings.Contains(err.Error(), "connection refused") {
-				errorType = "connection_refused"
-				statusCode = http.StatusServiceUnavailable
-			} else if strings.Contains(err.Error(), "no such host") {
-				errorType = "host_not_found"
-				statusCode = http.StatusBadGateway
-			} else {
-				errorType = "proxy_error"
-				statusCode = http.StatusBadGateway
-			}
+			// Determinar o tipo de erro, o código e o status HTTP apropriados
+			errorType, errorCode, statusCode := classifyUpstreamError(err)
+			span.SetAttributes(attribute.String("error.code", errorCode))
 
 			// Registrar erro nas métricas com tipo específico
 			if p.metrics != nil {
 				p.metrics.RequestError(r.URL.Path, r.Method, errorType)
+				p.metrics.UpstreamError(route.Path, r.Method, errorType)
 			}
 
-			http.Error(w, "Erro ao encaminhar requisição: "+err.Error(), statusCode)
+			// O detalhe da falha fica nos logs e no trace, sem ser exposto ao cliente
+			writeError(w, r, route, statusCode, errorCode)
 		},
 	}
 
 	// Executa o proxy
+	upstreamStart := time.Now()
 	proxy.ServeHTTP(w, r)
+	recordUpstreamDuration(ctx, route.Path, r.Method, status, time.Since(upstreamStart))
+	if upstreamErr != nil {
+		return nil, upstreamErr
+	}
 
 	// A resposta foi enviada com sucesso se chegou aqui
 	// Garantir que o status OK seja definido (pode ter sido substituído por valores do ModifyResponse)
@@ -270,39 +563,46 @@ func (p *ReverseProxy) doProxy(route *model.Route, w http.ResponseWriter, r *htt
 		span.SetStatus(codes.Ok, "")
 	}
 
-	return nil, nil
+	return status, nil
 }
 
-// getCircuitBreaker obtém ou cria um circuit breaker para um serviço
-func (p *ReverseProxy) getCircuitBreaker(serviceURL string) *resilience.CircuitBreaker {
-	p.cbLock.RLock()
-	cb, exists := p.circuitBreakers[serviceURL]
-	p.cbLock.RUnlock()
-
-	if exists {
-		return cb
+// rewriteRequest aponta a requisição de saída para o alvo, preservando o
+// caminho, a query e o contexto de tracing da requisição original
+func rewriteRequest(ctx context.Context, req, r *http.Request, route *model.Route, targetURL *url.URL) {
+	// Preservar o caminho, ou reescrevê-lo conforme a rota, e a query string
+	req.URL.Scheme = targetURL.Scheme
+	req.URL.Host = targetURL.Host
+	req.URL.Path = r.URL.Path
+	if route.PathRewrite != nil {
+		req.URL.Path = route.PathRewrite.Rewrite(route.Path, r.URL.Path)
+		req.URL.RawPath = ""
 	}
+	req.URL.RawQuery = r.URL.RawQuery
 
-	// Se não existe, cria um novo circuit breaker
-	p.cbLock.Lock()
-	defer p.cbLock.Unlock()
+	// Preservar o IP original
+	req.Header.Set("X-Forwarded-For", r.RemoteAddr)
+	req.Header.Set("X-Forwarded-Host", r.Host)
+	req.Host = targetURL.Host
 
-	// Verificar novamente em caso de race condition
-	cb, exists = p.circuitBreakers[serviceURL]
-	if exists {
-		return cb
+	// Adicionar cabeçalhos personalizados, se necessário
+	for _, header := range route.Headers {
+		if val := r.Header.Get(header); val != "" {
+			req.Header.Set(header, val)
+		}
 	}
 
-	// Criar nova configuração de circuit breaker
-	config := resilience.CircuitBreakerConfig{
-		Name:        "cb-" + serviceURL,
-		MaxRequests: 5,
-		Interval:    time.Minute,
-		Timeout:     30 * time.Second,
+	// Autenticar no backend com as credenciais configuradas na rota
+	if route.UpstreamAuth != nil {
+		req.SetBasicAuth(route.UpstreamAuth.Username, route.UpstreamAuth.Password)
 	}
 
-	cb = resilience.NewCircuitBreaker(config, p.logger, p.metrics)
-	p.circuitBreakers[serviceURL] = cb
+	// ADICIONADO: Propagar explicitamente o contexto de tracing para o serviço downstream
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
 
-	return cb
+	// Adicionar span ID como cabeçalho para correlação
+	spanContext := trace.SpanContextFromContext(ctx)
+	if spanContext.IsValid() {
+		req.Header.Set("X-Trace-ID", spanContext.TraceID().String())
+		req.Header.Set("X-Span-ID", spanContext.SpanID().String())
+	}
 }
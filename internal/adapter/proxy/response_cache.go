@@ -0,0 +1,455 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/pkg/cache"
+	"github.com/diillson/api-gateway-go/pkg/compression"
+	"github.com/diillson/api-gateway-go/pkg/config"
+	"github.com/diillson/api-gateway-go/pkg/tenant"
+	"go.uber.org/zap"
+)
+
+// CachedResponse é uma resposta de backend armazenada no cache. O corpo é sempre
+// guardado sem compressão e recodificado conforme o Accept-Encoding de cada cliente.
+type CachedResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	StoredAt   time.Time
+	// Cabeçalhos do Vary do backend. Quando preenchido em uma entrada sem
+	// status, a entrada apenas indica que a resposta varia por esses cabeçalhos
+	// e está armazenada na chave da variante.
+	VaryHeaders []string
+}
+
+// responseGenerationTTL mantém a geração de invalidação de uma rota por mais
+// tempo que qualquer resposta armazenada
+const responseGenerationTTL = 30 * 24 * time.Hour
+
+// hopByHopHeaders não devem ser armazenados junto com a resposta
+var hopByHopHeaders = []string{
+	"Connection", "Keep-Alive", "Proxy-Authenticate", "Proxy-Authorization",
+	"Te", "Trailer", "Transfer-Encoding", "Upgrade",
+}
+
+// ResponseCache armazena respostas de backend das rotas com política de cache
+type ResponseCache struct {
+	cache  cache.Cache
+	config config.ResponseCacheConfig
+	logger *zap.Logger
+}
+
+// NewResponseCache cria o cache de respostas sobre a abstração de cache existente
+func NewResponseCache(c cache.Cache, cfg config.ResponseCacheConfig, logger *zap.Logger) *ResponseCache {
+	return &ResponseCache{
+		cache:  c,
+		config: cfg,
+		logger: logger,
+	}
+}
+
+// Cacheable indica se a requisição pode ser atendida pelo cache
+func (rc *ResponseCache) Cacheable(route *model.Route, r *http.Request) bool {
+	if !rc.config.Enabled || route.ResponseCache == nil {
+		return false
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+	// Respostas de requisições autenticadas podem ser específicas do usuário
+	if r.Header.Get("Authorization") != "" {
+		return false
+	}
+	// O cliente pediu que a resposta não seja armazenada
+	if !route.ResponseCache.IgnoreCacheControl {
+		if _, noStore := parseCacheControl(r.Header.Get("Cache-Control"))["no-store"]; noStore {
+			return false
+		}
+	}
+	return true
+}
+
+// Key compõe a chave de cache da requisição a partir do caminho, da query e dos
+// cabeçalhos configurados na rota. A codificação aceita pelo cliente não faz
+// parte da chave, pois o corpo é armazenado em forma canônica.
+func (rc *ResponseCache) Key(ctx context.Context, route *model.Route, r *http.Request) string {
+	policy := route.ResponseCache
+
+	key := "response:"
+	if tenantID := tenant.FromContext(ctx); tenantID != "" {
+		key += "tenant:" + tenantID + ":"
+	}
+	if generation := rc.generation(ctx, route.Path); generation != 0 {
+		key += "g" + strconv.FormatInt(generation, 36) + ":"
+	}
+	key += r.URL.Path
+
+	query := r.URL.Query()
+	if len(policy.QueryParams) > 0 {
+		selected := url.Values{}
+		for _, name := range policy.QueryParams {
+			if values, ok := query[name]; ok {
+				selected[name] = values
+			}
+		}
+		query = selected
+	}
+	if len(query) > 0 {
+		key += "?" + query.Encode()
+	}
+
+	for _, name := range policy.KeyHeaders {
+		key += "|" + http.CanonicalHeaderKey(name) + "=" + strings.Join(r.Header.Values(name), ",")
+	}
+	return key
+}
+
+// Lookup busca a resposta armazenada para a requisição, seguindo a variante
+// indicada pelo Vary do backend. Requisições com Cache-Control no-cache não
+// são atendidas pelo cache, mas a resposta obtida do backend é armazenada.
+func (rc *ResponseCache) Lookup(ctx context.Context, route *model.Route, key string, r *http.Request) (*CachedResponse, bool) {
+	if !route.ResponseCache.IgnoreCacheControl && requiresRevalidation(r.Header) {
+		return nil, false
+	}
+
+	entry, found := rc.get(ctx, key)
+	if found && entry.StatusCode == 0 && len(entry.VaryHeaders) > 0 {
+		entry, found = rc.get(ctx, variantKey(key, entry.VaryHeaders, r.Header))
+	}
+	if !found || entry.StatusCode == 0 {
+		return nil, false
+	}
+	return entry, true
+}
+
+// get lê uma entrada do cache
+func (rc *ResponseCache) get(ctx context.Context, key string) (*CachedResponse, bool) {
+	var entry CachedResponse
+	found, err := rc.cache.Get(ctx, key, &entry)
+	if err != nil {
+		rc.logger.Warn("Falha ao consultar cache de respostas", zap.String("key", key), zap.Error(err))
+		return nil, false
+	}
+	if !found {
+		return nil, false
+	}
+	return &entry, true
+}
+
+// Invalidate descarta as respostas armazenadas da rota. As entradas existentes
+// deixam de ser encontradas e expiram pelo próprio TTL.
+func (rc *ResponseCache) Invalidate(ctx context.Context, routePath string) error {
+	generation := time.Now().UnixNano()
+	if err := rc.cache.Set(ctx, generationKey(ctx, routePath), generation, responseGenerationTTL); err != nil {
+		return fmt.Errorf("falha ao invalidar cache de respostas: %w", err)
+	}
+	return nil
+}
+
+// generation retorna a geração de invalidação atual da rota
+func (rc *ResponseCache) generation(ctx context.Context, routePath string) int64 {
+	var generation int64
+	if _, err := rc.cache.Get(ctx, generationKey(ctx, routePath), &generation); err != nil {
+		rc.logger.Warn("Falha ao consultar geração do cache de respostas",
+			zap.String("route", routePath), zap.Error(err))
+	}
+	return generation
+}
+
+// generationKey monta a chave da geração de invalidação da rota
+func generationKey(ctx context.Context, routePath string) string {
+	key := "response-generation:"
+	if tenantID := tenant.FromContext(ctx); tenantID != "" {
+		key += "tenant:" + tenantID + ":"
+	}
+	return key + routePath
+}
+
+// variantKey compõe a chave da variante da resposta pelos cabeçalhos do Vary
+func variantKey(key string, varyHeaders []string, header http.Header) string {
+	key += "|vary"
+	for _, name := range varyHeaders {
+		key += "|" + name + "=" + strings.Join(header.Values(name), ",")
+	}
+	return key
+}
+
+// Serve escreve a resposta armazenada, codificada conforme o Accept-Encoding do cliente
+func (rc *ResponseCache) Serve(w http.ResponseWriter, r *http.Request, entry *CachedResponse) error {
+	header := w.Header()
+	for name, values := range entry.Header {
+		header[name] = append([]string(nil), values...)
+	}
+	header.Set("X-Cache", "HIT")
+	header.Set("Age", strconv.Itoa(int(time.Since(entry.StoredAt).Seconds())))
+
+	// Requisição condicional com a mesma versão: responder sem corpo
+	if notModified(r, entry.Header) {
+		header.Del("Content-Length")
+		w.WriteHeader(http.StatusNotModified)
+		return nil
+	}
+
+	body, encoding, err := encodeFor(r.Header.Get("Accept-Encoding"), entry.Body)
+	if err != nil {
+		return err
+	}
+	setEncodingHeaders(header, encoding, len(body))
+
+	w.WriteHeader(entry.StatusCode)
+	if r.Method == http.MethodHead {
+		return nil
+	}
+	_, err = w.Write(body)
+	return err
+}
+
+// Capture armazena a resposta do backend e a recodifica para o cliente que a originou.
+// Deve ser chamado em ModifyResponse; acceptEncoding é o cabeçalho original do cliente.
+func (rc *ResponseCache) Capture(res *http.Response, route *model.Route, key, acceptEncoding string) error {
+	res.Header.Set("X-Cache", "MISS")
+
+	if res.Request.Method != http.MethodGet || res.StatusCode != http.StatusOK || res.Header.Get("Set-Cookie") != "" {
+		return nil
+	}
+
+	ttl, storable := rc.freshness(route, res.Header)
+	if !storable {
+		return nil
+	}
+
+	// Vary: * indica que a resposta não pode ser reutilizada
+	varyHeaders, varyAll := varyFields(res.Header)
+	if varyAll {
+		return nil
+	}
+
+	contentEncoding := res.Header.Get("Content-Encoding")
+	if !compression.IsSupported(contentEncoding) {
+		return nil
+	}
+
+	// Ler o corpo até o limite; respostas maiores seguem sem cache
+	limit := rc.config.MaxBodyBytes
+	raw, err := io.ReadAll(io.LimitReader(res.Body, limit+1))
+	if err != nil {
+		return err
+	}
+	if int64(len(raw)) > limit {
+		res.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(raw), res.Body), Closer: res.Body}
+		return nil
+	}
+	res.Body.Close()
+
+	canonical, err := compression.Decode(contentEncoding, raw, limit)
+	if err != nil {
+		// Não foi possível obter a forma canônica: entregar a resposta original sem cache
+		rc.logger.Debug("Resposta não armazenada no cache", zap.String("key", key), zap.Error(err))
+		res.Body = io.NopCloser(bytes.NewReader(raw))
+		return nil
+	}
+
+	// Sem ETag do backend, gerar um validador fraco a partir do corpo canônico
+	// para que os clientes possam revalidar com If-None-Match
+	if res.Header.Get("ETag") == "" {
+		sum := sha256.Sum256(canonical)
+		res.Header.Set("ETag", `W/"`+hex.EncodeToString(sum[:8])+`"`)
+	}
+
+	entry := &CachedResponse{
+		StatusCode: res.StatusCode,
+		Header:     storableHeader(res.Header),
+		Body:       canonical,
+		StoredAt:   time.Now(),
+	}
+	ctx := res.Request.Context()
+	storeKey := key
+	if len(varyHeaders) > 0 {
+		// A chave base indica os cabeçalhos do Vary; a resposta fica na chave da variante
+		manifest := &CachedResponse{VaryHeaders: varyHeaders, StoredAt: entry.StoredAt}
+		if err := rc.cache.Set(ctx, key, manifest, ttl); err != nil {
+			rc.logger.Warn("Falha ao armazenar resposta no cache", zap.String("key", key), zap.Error(err))
+		}
+		storeKey = variantKey(key, varyHeaders, res.Request.Header)
+	}
+	if err := rc.cache.Set(ctx, storeKey, entry, ttl); err != nil {
+		rc.logger.Warn("Falha ao armazenar resposta no cache", zap.String("key", storeKey), zap.Error(err))
+	}
+
+	body, encoding, err := encodeFor(acceptEncoding, canonical)
+	if err != nil {
+		return err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+	res.ContentLength = int64(len(body))
+	setEncodingHeaders(res.Header, encoding, len(body))
+	return nil
+}
+
+// freshness calcula por quanto tempo a resposta pode ser armazenada. O
+// Cache-Control do backend prevalece sobre o TTL da rota, exceto quando a rota
+// o ignora. Retorna false quando a resposta não pode ser armazenada.
+func (rc *ResponseCache) freshness(route *model.Route, header http.Header) (time.Duration, bool) {
+	ttl := route.ResponseCache.Duration(rc.config.DefaultTTL)
+	if route.ResponseCache.IgnoreCacheControl {
+		return ttl, true
+	}
+
+	directives := parseCacheControl(header.Get("Cache-Control"))
+	for _, directive := range []string{"no-store", "no-cache", "private"} {
+		if _, ok := directives[directive]; ok {
+			return 0, false
+		}
+	}
+	for _, directive := range []string{"s-maxage", "max-age"} {
+		if value, ok := directives[directive]; ok {
+			seconds, err := strconv.Atoi(value)
+			if err != nil || seconds <= 0 {
+				return 0, false
+			}
+			return time.Duration(seconds) * time.Second, true
+		}
+	}
+	return ttl, true
+}
+
+// parseCacheControl separa as diretivas de um cabeçalho Cache-Control
+func parseCacheControl(value string) map[string]string {
+	directives := make(map[string]string)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, arg, _ := strings.Cut(part, "=")
+		directives[strings.ToLower(strings.TrimSpace(name))] = strings.Trim(strings.TrimSpace(arg), `"`)
+	}
+	return directives
+}
+
+// requiresRevalidation indica se o cliente pediu uma resposta obtida do backend
+func requiresRevalidation(header http.Header) bool {
+	directives := parseCacheControl(header.Get("Cache-Control"))
+	if _, ok := directives["no-cache"]; ok {
+		return true
+	}
+	if maxAge, ok := directives["max-age"]; ok && maxAge == "0" {
+		return true
+	}
+	return len(directives) == 0 && strings.EqualFold(header.Get("Pragma"), "no-cache")
+}
+
+// varyFields retorna os cabeçalhos do Vary que diferenciam as respostas,
+// exceto Accept-Encoding, tratado pela recodificação do corpo. O segundo
+// retorno indica Vary: *.
+func varyFields(header http.Header) ([]string, bool) {
+	var fields []string
+	for _, value := range header.Values("Vary") {
+		for _, item := range strings.Split(value, ",") {
+			item = strings.TrimSpace(item)
+			switch {
+			case item == "*":
+				return nil, true
+			case item == "" || strings.EqualFold(item, "Accept-Encoding"):
+			default:
+				fields = append(fields, http.CanonicalHeaderKey(item))
+			}
+		}
+	}
+	sort.Strings(fields)
+	return fields, false
+}
+
+// notModified avalia os cabeçalhos condicionais da requisição contra a
+// resposta armazenada. If-None-Match tem precedência sobre If-Modified-Since.
+func notModified(r *http.Request, stored http.Header) bool {
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		etag := strings.TrimPrefix(stored.Get("ETag"), "W/")
+		if etag == "" {
+			return false
+		}
+		for _, candidate := range strings.Split(match, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	modified, err := http.ParseTime(stored.Get("Last-Modified"))
+	if err != nil {
+		return false
+	}
+	return !modified.After(since)
+}
+
+// encodeFor codifica o corpo canônico na melhor codificação aceita pelo cliente
+func encodeFor(acceptEncoding string, canonical []byte) ([]byte, string, error) {
+	encoding := compression.Negotiate(acceptEncoding, compression.Supported)
+	body, err := compression.Encode(encoding, canonical)
+	if err != nil {
+		return nil, "", err
+	}
+	return body, encoding, nil
+}
+
+// setEncodingHeaders ajusta os cabeçalhos de conteúdo para a codificação escolhida
+func setEncodingHeaders(header http.Header, encoding string, length int) {
+	if encoding == compression.Identity {
+		header.Del("Content-Encoding")
+	} else {
+		header.Set("Content-Encoding", encoding)
+	}
+	if !varyIncludes(header, "Accept-Encoding") {
+		header.Add("Vary", "Accept-Encoding")
+	}
+	header.Set("Content-Length", strconv.Itoa(length))
+}
+
+// storableHeader copia os cabeçalhos que fazem sentido em respostas servidas do cache
+func storableHeader(header http.Header) http.Header {
+	stored := header.Clone()
+	for _, name := range hopByHopHeaders {
+		stored.Del(name)
+	}
+	stored.Del("Content-Encoding")
+	stored.Del("Content-Length")
+	stored.Del("X-Cache")
+	return stored
+}
+
+// varyIncludes verifica se o cabeçalho Vary já contém o campo informado
+func varyIncludes(header http.Header, field string) bool {
+	for _, value := range header.Values("Vary") {
+		for _, item := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(item), field) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// readCloser combina um leitor com o Close do corpo original
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
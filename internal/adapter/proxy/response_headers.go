@@ -0,0 +1,44 @@
+package proxy
+
+import (
+	"net/http"
+)
+
+// gatewaySecurityHeaders são os cabeçalhos que o gateway pode definir em
+// server.securityHeaders. Quando o gateway os define, a cópia enviada pelo
+// backend é descartada para que o cliente não receba políticas conflitantes.
+var gatewaySecurityHeaders = []string{
+	"Strict-Transport-Security",
+	"X-Frame-Options",
+	"X-Content-Type-Options",
+	"Referrer-Policy",
+	"Permissions-Policy",
+	"Content-Security-Policy",
+}
+
+// SetStripResponseHeaders configura os cabeçalhos internos retirados das
+// respostas dos backends, como os que revelam o servidor ou a tecnologia
+// usada. Pode ser chamado com o proxy em uso, na recarga da configuração.
+func (p *ReverseProxy) SetStripResponseHeaders(names []string) {
+	stripHeaders := make([]string, 0, len(names))
+	for _, name := range names {
+		stripHeaders = append(stripHeaders, http.CanonicalHeaderKey(name))
+	}
+	p.stripHeaders.Store(&stripHeaders)
+}
+
+// sanitizeResponseHeaders retira da resposta do backend os cabeçalhos internos
+// e os cabeçalhos de segurança já definidos pelo gateway. Os cabeçalhos
+// hop-by-hop já foram removidos pelo httputil.ReverseProxy.
+func (p *ReverseProxy) sanitizeResponseHeaders(res *http.Response, gateway http.Header) {
+	if stripHeaders := p.stripHeaders.Load(); stripHeaders != nil {
+		for _, name := range *stripHeaders {
+			res.Header.Del(name)
+		}
+	}
+	for _, name := range gatewaySecurityHeaders {
+		if gateway.Get(name) != "" {
+			res.Header.Del(name)
+		}
+	}
+}
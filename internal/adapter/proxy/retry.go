@@ -0,0 +1,153 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/internal/infra/metrics"
+	"github.com/diillson/api-gateway-go/pkg/resilience"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// maxRetryBodyBytes limita o corpo guardado para reenvio; requisições maiores
+// são enviadas uma única vez
+const maxRetryBodyBytes = 1 << 20
+
+// maxDrainBytes limita a leitura do corpo descartado antes de uma nova
+// tentativa, permitindo reaproveitar a conexão
+const maxDrainBytes = 64 << 10
+
+// retryTransport aplica a política de novas tentativas da rota sobre o
+// transporte do backend
+type retryTransport struct {
+	next    http.RoundTripper
+	route   *model.Route
+	budget  *resilience.RetryBudget
+	metrics *metrics.APIMetrics
+	logger  *zap.Logger
+}
+
+// withRetry envolve o transporte quando a rota tem política de novas tentativas
+func (p *ReverseProxy) withRetry(transport http.RoundTripper, route *model.Route) http.RoundTripper {
+	if route.Retry == nil || route.IsGRPC() {
+		return transport
+	}
+	return &retryTransport{next: transport, route: route, budget: p.retryBudget, metrics: p.metrics, logger: p.logger}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	policy := t.route.Retry
+	if !policy.RetriesMethod(req.Method) {
+		return t.attempt(req)
+	}
+
+	// Guardar o corpo para reenviá-lo em cada tentativa
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		data, err := io.ReadAll(io.LimitReader(req.Body, maxRetryBodyBytes+1))
+		if err != nil {
+			req.Body.Close()
+			return nil, err
+		}
+		if len(data) > maxRetryBodyBytes {
+			req.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(data), req.Body), Closer: req.Body}
+			return t.attempt(req)
+		}
+		req.Body.Close()
+		body = data
+	}
+
+	ctx := req.Context()
+	for attempt := 0; ; attempt++ {
+		attemptReq := req
+		if body != nil {
+			attemptReq = req.Clone(ctx)
+			attemptReq.Body = io.NopCloser(bytes.NewReader(body))
+			attemptReq.GetBody = func() (io.ReadCloser, error) {
+				return io.NopCloser(bytes.NewReader(body)), nil
+			}
+		}
+
+		res, err := t.attempt(attemptReq)
+		retryable := (err != nil && ctx.Err() == nil) || (err == nil && policy.RetriesStatus(res.StatusCode))
+		if !retryable || attempt >= policy.Attempts || !t.reserve(req.Method) {
+			if attempt > 0 {
+				trace.SpanFromContext(ctx).SetAttributes(attribute.Int("proxy.retry.attempts", attempt))
+			}
+			return res, err
+		}
+
+		reason := "error"
+		if err == nil {
+			reason = http.StatusText(res.StatusCode)
+			io.Copy(io.Discard, io.LimitReader(res.Body, maxDrainBytes))
+			res.Body.Close()
+		}
+		delay := policy.Delay(attempt + 1)
+		t.logger.Debug("Repetindo requisição ao backend",
+			zap.String("path", t.route.Path),
+			zap.Int("attempt", attempt+1),
+			zap.String("reason", reason),
+			zap.Duration("delay", delay),
+			zap.Error(err))
+		if t.metrics != nil {
+			t.metrics.RequestError(t.route.Path, req.Method, "upstream_retry")
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// reserve consome o orçamento global para uma nova tentativa
+func (t *retryTransport) reserve(method string) bool {
+	if t.budget.TryRetry() {
+		return true
+	}
+	t.logger.Debug("Orçamento de novas tentativas esgotado", zap.String("path", t.route.Path))
+	if t.metrics != nil {
+		t.metrics.RequestError(t.route.Path, method, "retry_budget_exhausted")
+	}
+	return false
+}
+
+// attempt executa uma tentativa, limitada pelo timeout por tentativa
+func (t *retryTransport) attempt(req *http.Request) (*http.Response, error) {
+	timeout := t.route.Retry.PerTryTimeoutDuration()
+	if timeout <= 0 {
+		return t.next.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	res, err := t.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	// O prazo continua valendo durante a leitura do corpo
+	res.Body = cancelOnClose{ReadCloser: res.Body, cancel: cancel}
+	return res, nil
+}
+
+// cancelOnClose libera o contexto da tentativa quando o corpo é fechado
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
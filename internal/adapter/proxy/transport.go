@@ -0,0 +1,468 @@
+package proxy
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/pkg/config"
+	"github.com/diillson/api-gateway-go/pkg/dnscache"
+	"go.uber.org/zap"
+	"golang.org/x/net/http/httpproxy"
+	"golang.org/x/net/http2"
+)
+
+// TransportPool mantém os transportes HTTP usados para falar com os backends.
+// Rotas com a mesma configuração de conexão compartilham o mesmo transporte,
+// preservando o reaproveitamento de conexões.
+type TransportPool struct {
+	mu         sync.RWMutex
+	transports map[string]*http.Transport
+	grpc       map[string]*grpcTransport
+	egress     config.EgressProxyConfig
+	settings   config.TransportConfig
+	resolver   *dnscache.Resolver
+	logger     *zap.Logger
+}
+
+// renewGrace é o tempo após uma mudança no DNS em que as conexões ociosas dos
+// transportes substituídos são fechadas de novo, alcançando as que estavam em
+// uso na troca
+const renewGrace = 30 * time.Second
+
+// defaultTransportConfig são os ajustes do pool quando SetTransport não é chamado
+var defaultTransportConfig = config.TransportConfig{
+	MaxIdleConns:        512,
+	MaxIdleConnsPerHost: 64,
+	IdleConnTimeout:     90 * time.Second,
+	TLSHandshakeTimeout: 10 * time.Second,
+	DialTimeout:         30 * time.Second,
+	KeepAlive:           30 * time.Second,
+	ForceAttemptHTTP2:   true,
+}
+
+// NewTransportPool cria um novo pool de transportes
+func NewTransportPool(logger *zap.Logger) *TransportPool {
+	return &TransportPool{
+		transports: make(map[string]*http.Transport),
+		grpc:       make(map[string]*grpcTransport),
+		settings:   defaultTransportConfig,
+		logger:     logger,
+	}
+}
+
+// SetEgressProxy define o proxy de saída global. Transportes já criados são
+// descartados para que as próximas requisições usem a nova configuração.
+func (p *TransportPool) SetEgressProxy(cfg config.EgressProxyConfig) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.egress = cfg
+	p.resetLocked()
+}
+
+// SetTransport define os ajustes globais do pool de conexões. Transportes já
+// criados são descartados, como em SetEgressProxy.
+func (p *TransportPool) SetTransport(cfg config.TransportConfig) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.settings = cfg
+	p.resetLocked()
+}
+
+// SetResolver passa a resolver os backends pelo cache de DNS informado.
+// Quando os endereços de um backend mudam, os transportes são substituídos
+// para que as próximas requisições conectem aos novos endereços.
+func (p *TransportPool) SetResolver(resolver *dnscache.Resolver) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.resolver = resolver
+	resolver.OnChange(p.renew)
+	p.resetLocked()
+}
+
+// renew substitui os transportes após uma mudança de endereços no DNS. As
+// requisições em andamento terminam nos transportes antigos, cujas conexões
+// ociosas são fechadas agora e novamente após renewGrace.
+func (p *TransportPool) renew(host string) {
+	p.mu.Lock()
+	retired := make([]interface{ CloseIdleConnections() }, 0, len(p.transports)+len(p.grpc))
+	for key, transport := range p.transports {
+		retired = append(retired, transport)
+		delete(p.transports, key)
+	}
+	for key, transport := range p.grpc {
+		retired = append(retired, transport)
+		delete(p.grpc, key)
+	}
+	p.mu.Unlock()
+
+	p.logger.Info("Conexões com os backends renovadas após mudança no DNS",
+		zap.String("host", host),
+		zap.Int("transports", len(retired)))
+
+	for _, transport := range retired {
+		transport.CloseIdleConnections()
+		time.AfterFunc(renewGrace, transport.CloseIdleConnections)
+	}
+}
+
+// resetLocked descarta os transportes criados; requer p.mu
+func (p *TransportPool) resetLocked() {
+	for key, transport := range p.transports {
+		transport.CloseIdleConnections()
+		delete(p.transports, key)
+	}
+	for key, transport := range p.grpc {
+		transport.CloseIdleConnections()
+		delete(p.grpc, key)
+	}
+}
+
+// Get retorna o transporte adequado à configuração de conexão da rota
+func (p *TransportPool) Get(route *model.Route) (*http.Transport, error) {
+	key, err := transportKey(route)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.RLock()
+	transport, ok := p.transports[key]
+	p.mu.RUnlock()
+	if ok {
+		return transport, nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if transport, ok := p.transports[key]; ok {
+		return transport, nil
+	}
+
+	transport, err = p.build(route)
+	if err != nil {
+		return nil, err
+	}
+	p.transports[key] = transport
+	return transport, nil
+}
+
+// GetGRPC retorna o transporte HTTP/2 das rotas gRPC. Backends https negociam
+// HTTP/2 pelo ALPN com a configuração TLS da rota; backends http usam HTTP/2
+// sem TLS (h2c), conectando diretamente sem o proxy de saída.
+func (p *TransportPool) GetGRPC(route *model.Route) (http.RoundTripper, error) {
+	key, err := transportKey(route)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.RLock()
+	transport, ok := p.grpc[key]
+	p.mu.RUnlock()
+	if ok {
+		return transport, nil
+	}
+
+	secure, err := p.Get(route)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if transport, ok := p.grpc[key]; ok {
+		return transport, nil
+	}
+
+	pool := route.ConnectionPool
+	dial := dialFunc(p.settings, pool, p.resolver)
+	transport = &grpcTransport{
+		secure: secure,
+		cleartext: &http2.Transport{
+			AllowHTTP: true,
+			DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+				return dial(ctx, network, addr)
+			},
+			IdleConnTimeout: pool.IdleConnTimeoutOr(p.settings.IdleConnTimeout),
+		},
+	}
+	p.grpc[key] = transport
+	return transport, nil
+}
+
+// CloseIdleConnections fecha as conexões ociosas de todos os transportes
+func (p *TransportPool) CloseIdleConnections() {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	for _, transport := range p.transports {
+		transport.CloseIdleConnections()
+	}
+	for _, transport := range p.grpc {
+		transport.CloseIdleConnections()
+	}
+}
+
+// build cria um novo transporte para a configuração da rota
+func (p *TransportPool) build(route *model.Route) (*http.Transport, error) {
+	transport := newBaseTransport(p.settings, route.ConnectionPool, p.resolver)
+	transport.Proxy = p.proxyFunc(route)
+
+	if route.TLS != nil {
+		tlsConfig, err := buildTLSConfig(route.TLS)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao configurar TLS do backend da rota %s: %w", route.Path, err)
+		}
+		transport.TLSClientConfig = tlsConfig
+
+		if route.TLS.InsecureSkipVerify {
+			// Registro de auditoria: a verificação do certificado foi desabilitada explicitamente
+			p.logger.Warn("AUDITORIA: verificação TLS do backend desabilitada para a rota",
+				zap.String("path", route.Path),
+				zap.String("serviceURL", route.ServiceURL),
+				zap.String("reason", route.TLS.InsecureReason))
+		}
+	}
+
+	return transport, nil
+}
+
+// proxyFunc decide qual proxy de saída a rota usa. Rotas sem configuração herdam
+// o proxy global; sem proxy global, valem as variáveis HTTP_PROXY/HTTPS_PROXY/NO_PROXY.
+func (p *TransportPool) proxyFunc(route *model.Route) func(*http.Request) (*url.URL, error) {
+	proxyURL := p.egress.URL
+	switch route.EgressProxy {
+	case model.EgressProxyDirect:
+		return nil
+	case "":
+	default:
+		proxyURL = route.EgressProxy
+	}
+
+	if proxyURL == "" {
+		return http.ProxyFromEnvironment
+	}
+
+	// As regras NO_PROXY globais também valem para proxies definidos na rota
+	resolve := (&httpproxy.Config{
+		HTTPProxy:  proxyURL,
+		HTTPSProxy: proxyURL,
+		NoProxy:    strings.Join(p.egress.NoProxy, ","),
+	}).ProxyFunc()
+
+	return func(req *http.Request) (*url.URL, error) {
+		return resolve(req.URL)
+	}
+}
+
+// dialFunc cria a função de conexão com os backends. Com o cache de DNS, os
+// nomes são resolvidos por ele, nos servidores DNS da rota quando definidos.
+func dialFunc(settings config.TransportConfig, pool *model.ConnectionPool, resolver *dnscache.Resolver) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	dialer := &net.Dialer{
+		Timeout:   pool.DialTimeoutOr(settings.DialTimeout),
+		KeepAlive: pool.KeepAliveOr(settings.KeepAlive),
+	}
+	if resolver == nil {
+		return dialer.DialContext
+	}
+	return resolver.DialContext(dialer, pool.ResolverServers())
+}
+
+// newBaseTransport cria um transporte com os ajustes globais do pool de
+// conexões, substituídos pelos definidos na rota
+func newBaseTransport(settings config.TransportConfig, pool *model.ConnectionPool, resolver *dnscache.Resolver) *http.Transport {
+	transport := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialFunc(settings, pool, resolver),
+		ForceAttemptHTTP2:     settings.ForceAttemptHTTP2,
+		MaxIdleConns:          settings.MaxIdleConns,
+		MaxIdleConnsPerHost:   settings.MaxIdleConnsPerHost,
+		MaxConnsPerHost:       settings.MaxConnsPerHost,
+		IdleConnTimeout:       pool.IdleConnTimeoutOr(settings.IdleConnTimeout),
+		TLSHandshakeTimeout:   pool.TLSHandshakeTimeoutOr(settings.TLSHandshakeTimeout),
+		DisableKeepAlives:     settings.DisableKeepAlives,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+	if pool == nil {
+		return transport
+	}
+
+	if pool.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = pool.MaxIdleConnsPerHost
+		if transport.MaxIdleConns > 0 && transport.MaxIdleConns < pool.MaxIdleConnsPerHost {
+			transport.MaxIdleConns = pool.MaxIdleConnsPerHost
+		}
+	}
+	if pool.MaxConnsPerHost > 0 {
+		transport.MaxConnsPerHost = pool.MaxConnsPerHost
+	}
+	if pool.DisableKeepAlives {
+		transport.DisableKeepAlives = true
+	}
+	switch pool.HTTP2 {
+	case model.HTTP2Enabled:
+		transport.ForceAttemptHTTP2 = true
+	case model.HTTP2Disabled:
+		// Um mapa vazio impede a negociação do HTTP/2 pelo ALPN
+		transport.ForceAttemptHTTP2 = false
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+	return transport
+}
+
+// UpstreamTLSConfig retorna a configuração TLS usada para falar com o backend da rota
+func UpstreamTLSConfig(route *model.Route) (*tls.Config, error) {
+	if route.TLS == nil {
+		return &tls.Config{MinVersion: tls.VersionTLS12}, nil
+	}
+	return buildTLSConfig(route.TLS)
+}
+
+// buildTLSConfig converte a configuração TLS da rota em tls.Config
+func buildTLSConfig(cfg *model.UpstreamTLS) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		ServerName:         cfg.ServerName,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+		MinVersion:         parseTLSVersion(cfg.MinVersion),
+	}
+
+	if cfg.CAFile != "" {
+		caPEM, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao ler bundle de CAs: %w", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("nenhum certificado válido em %s", cfg.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.CertFile != "" {
+		clientCert := &clientCertificate{certFile: cfg.CertFile, keyFile: cfg.KeyFile}
+		if err := clientCert.load(); err != nil {
+			return nil, fmt.Errorf("falha ao carregar certificado de cliente: %w", err)
+		}
+		tlsConfig.GetClientCertificate = clientCert.get
+	}
+
+	return tlsConfig, nil
+}
+
+// clientCertificate mantém o certificado de cliente do mTLS e o recarrega
+// quando os arquivos mudam, acompanhando a rotação de certificados de curta
+// duração (cert-manager, SPIFFE) sem recriar o transporte
+type clientCertificate struct {
+	certFile string
+	keyFile  string
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	modTime time.Time
+}
+
+// get é usado como tls.Config.GetClientCertificate. Se a recarga falhar (ex.:
+// arquivos em meio à escrita), o certificado anterior continua em uso.
+func (c *clientCertificate) get(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if modTime, err := c.latestModTime(); err == nil && modTime.After(c.modTime) {
+		if err := c.loadLocked(); err != nil && c.cert == nil {
+			return nil, err
+		}
+	}
+	return c.cert, nil
+}
+
+// load carrega o certificado e a chave
+func (c *clientCertificate) load() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.loadLocked()
+}
+
+func (c *clientCertificate) loadLocked() error {
+	modTime, err := c.latestModTime()
+	if err != nil {
+		return err
+	}
+	cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		return err
+	}
+	c.cert = &cert
+	c.modTime = modTime
+	return nil
+}
+
+// latestModTime retorna a modificação mais recente entre certificado e chave
+func (c *clientCertificate) latestModTime() (time.Time, error) {
+	var latest time.Time
+	for _, file := range []string{c.certFile, c.keyFile} {
+		info, err := os.Stat(file)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
+
+// parseTLSVersion converte a versão textual para a constante do crypto/tls
+func parseTLSVersion(version string) uint16 {
+	switch version {
+	case "1.0":
+		return tls.VersionTLS10
+	case "1.1":
+		return tls.VersionTLS11
+	case "1.3":
+		return tls.VersionTLS13
+	default:
+		return tls.VersionTLS12
+	}
+}
+
+// transportKey identifica a configuração de conexão da rota
+func transportKey(route *model.Route) (string, error) {
+	key := "default"
+	if route.TLS != nil {
+		data, err := json.Marshal(route.TLS)
+		if err != nil {
+			return "", fmt.Errorf("falha ao serializar configuração TLS: %w", err)
+		}
+		key = "tls:" + string(data)
+	}
+
+	if route.EgressProxy != "" {
+		key += "|proxy:" + route.EgressProxy
+	}
+	if route.ConnectionPool != nil {
+		data, err := json.Marshal(route.ConnectionPool)
+		if err != nil {
+			return "", fmt.Errorf("falha ao serializar pool de conexões: %w", err)
+		}
+		key += "|pool:" + string(data)
+	}
+	return key, nil
+}
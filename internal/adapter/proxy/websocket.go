@@ -0,0 +1,277 @@
+package proxy
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrWebSocketLimit indica que a rota atingiu o limite de conexões WebSocket
+	ErrWebSocketLimit = errors.New("limite de conexões WebSocket da rota atingido")
+	// ErrWebSocketSubprotocol indica que nenhum subprotocolo oferecido é aceito pela rota
+	ErrWebSocketSubprotocol = errors.New("nenhum subprotocolo WebSocket oferecido é aceito pela rota")
+)
+
+// IsWebSocketRequest indica se a requisição pede o upgrade da conexão para WebSocket
+func IsWebSocketRequest(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket") &&
+		containsToken(headerTokens(r.Header, "Connection"), "upgrade")
+}
+
+// proxyWebSocket encaminha o handshake ao backend e, após o upgrade, copia os
+// dados nos dois sentidos até uma das pontas encerrar a conexão ou o tempo sem
+// tráfego da rota se esgotar
+func (p *ReverseProxy) proxyWebSocket(route *model.Route, serviceURL string, w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	policy := route.WebSocket
+
+	// Oferecer ao backend apenas os subprotocolos aceitos pela rota
+	offered := headerTokens(r.Header, "Sec-WebSocket-Protocol")
+	accepted := make([]string, 0, len(offered))
+	for _, name := range offered {
+		if policy.AllowsSubprotocol(name) {
+			accepted = append(accepted, name)
+		}
+	}
+	if len(offered) > 0 && len(accepted) == 0 {
+		if p.metrics != nil {
+			p.metrics.RequestError(r.URL.Path, r.Method, "websocket_subprotocol")
+		}
+		http.Error(w, "Subprotocolo WebSocket não suportado", http.StatusBadRequest)
+		return ErrWebSocketSubprotocol
+	}
+
+	release, ok := p.acquireWebSocket(route)
+	if !ok {
+		if p.metrics != nil {
+			p.metrics.RequestError(r.URL.Path, r.Method, "websocket_limit")
+		}
+		writeError(w, r, route, http.StatusServiceUnavailable, model.ErrorCodeConcurrencyLimit)
+		return ErrWebSocketLimit
+	}
+	defer release()
+
+	targetURL, err := url.Parse(serviceURL)
+	if err != nil {
+		writeError(w, r, route, http.StatusInternalServerError, model.ErrorCodeUpstreamMisconfigured)
+		return err
+	}
+	transport, err := p.transports.Get(route)
+	if err != nil {
+		writeError(w, r, route, http.StatusBadGateway, model.ErrorCodeUpstreamMisconfigured)
+		return err
+	}
+
+	var upstreamErr error
+	upgraded := false
+	proxy := &httputil.ReverseProxy{
+		Transport: transport,
+
+		Director: func(req *http.Request) {
+			rewriteRequest(ctx, req, r, route, targetURL)
+			if len(accepted) > 0 {
+				req.Header.Set("Sec-WebSocket-Protocol", strings.Join(accepted, ", "))
+			}
+		},
+
+		ModifyResponse: func(res *http.Response) error {
+			p.sanitizeResponseHeaders(res, w.Header())
+			if res.StatusCode != http.StatusSwitchingProtocols {
+				return nil
+			}
+			// O backend só pode escolher um dos subprotocolos oferecidos
+			if selected := res.Header.Get("Sec-WebSocket-Protocol"); selected != "" && !containsToken(accepted, selected) {
+				return fmt.Errorf("backend escolheu subprotocolo WebSocket não oferecido: %q", selected)
+			}
+			upgraded = true
+			return nil
+		},
+
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			p.logger.Error("erro no proxy WebSocket",
+				zap.String("path", r.URL.Path),
+				zap.String("serviceURL", serviceURL),
+				zap.Error(err))
+			upstreamErr = err
+
+			if p.metrics != nil {
+				p.metrics.RequestError(r.URL.Path, r.Method, "websocket_error")
+			}
+			_, errorCode, statusCode := classifyUpstreamError(err)
+			writeError(w, r, route, statusCode, errorCode)
+		},
+	}
+
+	idleTimeout := policy.IdleTimeoutDuration()
+	writer := &websocketWriter{
+		ResponseWriter: w,
+		idleTimeout:    idleTimeout,
+		onIdle: func() {
+			p.logger.Info("Conexão WebSocket encerrada por inatividade",
+				zap.String("path", route.Path),
+				zap.Duration("idleTimeout", idleTimeout))
+		},
+		onHijack: func() {
+			if p.metrics != nil {
+				p.metrics.WebSocketConnectionOpened(route.Path)
+			}
+		},
+	}
+
+	start := time.Now()
+	proxy.ServeHTTP(writer, r)
+	if writer.hijacked && p.metrics != nil {
+		p.metrics.WebSocketConnectionClosed(route.Path)
+	}
+	if upstreamErr != nil {
+		return upstreamErr
+	}
+	if upgraded {
+		p.logger.Info("Conexão WebSocket encerrada",
+			zap.String("path", route.Path),
+			zap.String("serviceURL", serviceURL),
+			zap.Duration("duration", time.Since(start)))
+	}
+	return nil
+}
+
+// acquireWebSocket reserva uma conexão WebSocket da rota, respeitando o limite
+// configurado. A função retornada libera a reserva.
+func (p *ReverseProxy) acquireWebSocket(route *model.Route) (func(), bool) {
+	limit := 0
+	if route.WebSocket != nil {
+		limit = route.WebSocket.MaxConnections
+	}
+
+	p.wsLock.Lock()
+	defer p.wsLock.Unlock()
+	if limit > 0 && p.wsConnections[route.Path] >= limit {
+		return nil, false
+	}
+	p.wsConnections[route.Path]++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			p.wsLock.Lock()
+			defer p.wsLock.Unlock()
+			if p.wsConnections[route.Path]--; p.wsConnections[route.Path] <= 0 {
+				delete(p.wsConnections, route.Path)
+			}
+		})
+	}, true
+}
+
+// websocketWriter entrega ao proxy a conexão do cliente sem os prazos de
+// leitura e escrita do servidor HTTP, encerrando-a após o tempo sem tráfego
+type websocketWriter struct {
+	http.ResponseWriter
+	idleTimeout time.Duration
+	onIdle      func()
+	onHijack    func()
+	hijacked    bool
+}
+
+// Hijack implementa http.Hijacker
+func (w *websocketWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("conexão do cliente não permite upgrade")
+	}
+	conn, brw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Os prazos do servidor valem para requisições, não para conexões longas
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+
+	w.hijacked = true
+	if w.onHijack != nil {
+		w.onHijack()
+	}
+	return newIdleConn(conn, w.idleTimeout, w.onIdle), brw, nil
+}
+
+// idleConn fecha a conexão quando nenhum dado trafega em nenhum dos sentidos
+// durante o tempo configurado
+type idleConn struct {
+	net.Conn
+	timeout time.Duration
+	timer   *time.Timer
+}
+
+// newIdleConn envolve a conexão com o controle de inatividade
+func newIdleConn(conn net.Conn, timeout time.Duration, onIdle func()) *idleConn {
+	return &idleConn{
+		Conn:    conn,
+		timeout: timeout,
+		timer: time.AfterFunc(timeout, func() {
+			if onIdle != nil {
+				onIdle()
+			}
+			conn.Close()
+		}),
+	}
+}
+
+// Read implementa net.Conn, renovando o prazo de inatividade
+func (c *idleConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if n > 0 {
+		c.timer.Reset(c.timeout)
+	}
+	return n, err
+}
+
+// Write implementa net.Conn, renovando o prazo de inatividade
+func (c *idleConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	if n > 0 {
+		c.timer.Reset(c.timeout)
+	}
+	return n, err
+}
+
+// Close implementa net.Conn
+func (c *idleConn) Close() error {
+	c.timer.Stop()
+	return c.Conn.Close()
+}
+
+// headerTokens retorna os valores separados por vírgula do cabeçalho
+func headerTokens(header http.Header, name string) []string {
+	var tokens []string
+	for _, value := range header.Values(name) {
+		for _, token := range strings.Split(value, ",") {
+			if token = strings.TrimSpace(token); token != "" {
+				tokens = append(tokens, token)
+			}
+		}
+	}
+	return tokens
+}
+
+// containsToken indica se o token está na lista, sem diferenciar maiúsculas
+func containsToken(tokens []string, token string) bool {
+	for _, candidate := range tokens {
+		if strings.EqualFold(candidate, token) {
+			return true
+		}
+	}
+	return false
+}
@@ -2,21 +2,49 @@ package app
 
 import (
 	"context"
+	"fmt"
 	"github.com/diillson/api-gateway-go/internal/adapter/database"
+	"github.com/diillson/api-gateway-go/internal/adapter/grpcadmin"
 	"github.com/diillson/api-gateway-go/internal/adapter/http"
 	"github.com/diillson/api-gateway-go/internal/adapter/proxy"
+	"github.com/diillson/api-gateway-go/internal/app/audit"
 	"github.com/diillson/api-gateway-go/internal/app/auth"
+	"github.com/diillson/api-gateway-go/internal/app/idempotency"
+	"github.com/diillson/api-gateway-go/internal/app/quota"
+	"github.com/diillson/api-gateway-go/internal/app/recording"
 	"github.com/diillson/api-gateway-go/internal/app/route"
+	"github.com/diillson/api-gateway-go/internal/app/routestats"
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/internal/domain/repository"
 	"github.com/diillson/api-gateway-go/internal/domain/service"
+	"github.com/diillson/api-gateway-go/internal/infra/acme"
+	"github.com/diillson/api-gateway-go/internal/infra/discovery"
+	"github.com/diillson/api-gateway-go/internal/infra/extauthz"
+	"github.com/diillson/api-gateway-go/internal/infra/featureflag"
+	"github.com/diillson/api-gateway-go/internal/infra/healthcheck"
 	"github.com/diillson/api-gateway-go/internal/infra/metrics"
 	"github.com/diillson/api-gateway-go/internal/infra/middleware"
+	"github.com/diillson/api-gateway-go/internal/infra/openapi"
+	"github.com/diillson/api-gateway-go/internal/infra/routecrd"
+	"github.com/diillson/api-gateway-go/internal/infra/routefile"
+	"github.com/diillson/api-gateway-go/internal/infra/usage"
+	"github.com/diillson/api-gateway-go/internal/infra/webhook"
 	"github.com/diillson/api-gateway-go/pkg/cache"
+	"github.com/diillson/api-gateway-go/pkg/cluster"
 	"github.com/diillson/api-gateway-go/pkg/config"
+	"github.com/diillson/api-gateway-go/pkg/dnscache"
+	"github.com/diillson/api-gateway-go/pkg/encryption"
+	"github.com/diillson/api-gateway-go/pkg/kubeapi"
+	"github.com/diillson/api-gateway-go/pkg/logging"
+	"github.com/diillson/api-gateway-go/pkg/plugin"
+	"github.com/diillson/api-gateway-go/pkg/resilience"
+	"github.com/diillson/api-gateway-go/pkg/secrets"
 	"github.com/diillson/api-gateway-go/pkg/security"
+	"github.com/diillson/api-gateway-go/web"
 	"github.com/gin-gonic/gin"
 	"github.com/go-redis/redis/v8"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
+	"golang.org/x/crypto/acme/autocert"
 	net2 "net/http"
 	"os"
 	"time"
@@ -31,21 +59,79 @@ type App struct {
 	Cache          cache.Cache
 	MetricsHandler *middleware.MetricsHandler
 	APIMetrics     *metrics.APIMetrics
+
+	SecurityHandler *http.SecurityHandler
+	UsageHandler    *http.UsageHandler
+	ClusterHandler  *http.ClusterHandler
+	TokenHandler    *http.TokenHandler // nil sem cache compartilhado para as revogações
+	RoutesHandler   *http.AdminRouteHandler
+	OpenAPIHandler  *http.OpenAPIHandler
+	AuditHandler    *http.AuditHandler
+
+	// RouteEventsHandler transmite as alterações de rotas por SSE
+	RouteEventsHandler *http.RouteEventsHandler
+
+	// QuotaHandler consulta o uso e as cotas dos consumidores; nil quando desabilitadas
+	QuotaHandler *http.QuotaHandler
+
+	// RecordingHandler consulta as requisições gravadas; nil quando desabilitada
+	RecordingHandler *http.RecordingHandler
+
+	// APIKeyHandler administra as chaves de API; nil quando desabilitadas
+	APIKeyHandler *http.APIKeyHandler
+
+	// CredentialHandler administra as credenciais basic e hmac; nil quando desabilitadas
+	CredentialHandler *http.CredentialHandler
+
+	// OIDCHandler conclui o login de navegadores; nil sem provedores OIDC
+	OIDCHandler *http.OIDCHandler
+
+	// AdminUIHandler serve o painel administrativo; nil quando desabilitado
+	AdminUIHandler *http.AdminUIHandler
+
+	// CertificateStore guarda os certificados emitidos via ACME
+	CertificateStore autocert.Cache
+
+	// GRPCAdmin é a API administrativa gRPC; nil quando desabilitada
+	GRPCAdmin *grpcadmin.Server
+
+	// Shards contém os bancos adicionais do armazenamento particionado por tenant
+	Shards map[string]*database.Database
+
+	// cluster representa a instância no cluster; nil em instância única
+	cluster       *cluster.Node
+	clusterClient *redis.Client
+
+	// quotas contabiliza o uso dos consumidores; nil quando desabilitadas
+	quotas      *quota.Service
+	quotaClient *redis.Client
+
+	// routeStats conta as estatísticas por rota; nil quando desabilitadas
+	routeStats *routestats.Service
+
+	// idempotencyClient guarda as respostas das requisições com Idempotency-Key; nil quando desabilitado
+	idempotencyClient *redis.Client
+
+	// externalAuthorizer mantém as conexões com os serviços de autorização externos
+	externalAuthorizer *extauthz.Authorizer
+
+	// cancelBackground encerra as rotinas em segundo plano iniciadas pela aplicação
+	cancelBackground context.CancelFunc
+
+	// config é a configuração em vigor, recarregada por ReloadConfig
+	config  *config.Snapshot
+	auditor *audit.Recorder
 }
 
 // NewApp cria uma nova instância da aplicação com todas as dependências injetadas
-func NewApp(logger *zap.Logger, cfg *config.Config) (*App, error) {
+func NewApp(logger *zap.Logger, snapshot *config.Snapshot) (*App, error) {
+	cfg := snapshot.Load()
+
+	// Logger disponível aos plugins das rotas
+	plugin.SetLogger(logger)
+
 	// Configurações do banco de dados baseadas no arquivo config.yaml
-	dbConfig := database.Config{
-		Driver:          cfg.Database.Driver,
-		DSN:             cfg.Database.DSN,
-		MaxIdleConns:    cfg.Database.MaxIdleConns,
-		MaxOpenConns:    cfg.Database.MaxOpenConns,
-		ConnMaxLifetime: cfg.Database.ConnMaxLifetime,
-		LogLevel:        4,
-		SlowThreshold:   cfg.Database.SlowThreshold,
-		MigrationDir:    cfg.Database.MigrationDir,
-	}
+	dbConfig := newDatabaseConfig(cfg, cfg.Database.Driver, cfg.Database.DSN)
 
 	// Inicializar banco de dados
 	ctx := context.Background()
@@ -60,9 +146,19 @@ func NewApp(logger *zap.Logger, cfg *config.Config) (*App, error) {
 
 	// Inicializar métricas
 	apiMetrics := metrics.NewAPIMetrics()
-	metricsHandler := &middleware.MetricsHandler{
-		Metrics: apiMetrics,
-		Logger:  logger,
+	// O endpoint do Prometheus é exposto apenas com metrics.enabled
+	var metricsHandler *middleware.MetricsHandler
+	if cfg.Metrics.Enabled {
+		metricsHandler = middleware.NewMetricsHandler(apiMetrics, logger)
+		metricsHandler.Path = cfg.Metrics.PrometheusPath
+	}
+
+	// Contexto das rotinas em segundo plano, cancelado em Close
+	backgroundCtx, cancelBackground := context.WithCancel(context.Background())
+
+	// Publicar estatísticas do pool de conexões
+	if cfg.Metrics.Enabled {
+		db.StartPoolMetrics(backgroundCtx, "primary", cfg.Metrics.ReportInterval, apiMetrics)
 	}
 
 	// Inicializar o cache apropriado com base na configuração
@@ -129,6 +225,32 @@ func NewApp(logger *zap.Logger, cfg *config.Config) (*App, error) {
 				logger.Info("Cache Redis inicializado com sucesso")
 			}
 		}
+	} else if cfg.Cache.Type == "memcached" {
+		logger.Info("Inicializando cache Memcached",
+			zap.Strings("servers", cfg.Cache.Memcached.Servers),
+			zap.Duration("ttl", cfg.Cache.TTL))
+
+		cacheInstance, err = cache.NewMemcachedCache(
+			cfg.Cache.Memcached.Servers,
+			cfg.Cache.Memcached.Timeout,
+			cfg.Cache.Memcached.MaxIdleConns,
+			logger,
+		)
+		if err != nil {
+			logger.Error("Falha ao conectar ao Memcached, usando cache local",
+				zap.Error(err),
+				zap.Strings("memcached.servers", cfg.Cache.Memcached.Servers))
+			// Fallback para cache local, com os limites de itens e de memória
+			cacheInstance = cache.NewLocalCache(cfg.Cache.TTL, cfg.Cache.MaxItems, cfg.Cache.MaxMemoryMB, apiMetrics, logger)
+		} else {
+			logger.Info("Cache Memcached inicializado com sucesso")
+		}
+	} else if cfg.Cache.Type == "local" {
+		logger.Info("Inicializando cache local",
+			zap.Duration("ttl", cfg.Cache.TTL),
+			zap.Int("maxItems", cfg.Cache.MaxItems),
+			zap.Int("maxMemoryMB", cfg.Cache.MaxMemoryMB))
+		cacheInstance = cache.NewLocalCache(cfg.Cache.TTL, cfg.Cache.MaxItems, cfg.Cache.MaxMemoryMB, apiMetrics, logger)
 	} else {
 		// Cache em memória (padrão)
 		logger.Info("Inicializando cache em memória",
@@ -137,48 +259,516 @@ func NewApp(logger *zap.Logger, cfg *config.Config) (*App, error) {
 		cacheInstance = cache.NewMemoryCache(cfg.Cache.TTL, 10*time.Minute, apiMetrics, logger)
 	}
 
+	// Provedor de segredos, criado quando algum segredo é lido dele
+	var secretsProvider secrets.Provider
+	if cfg.Security.Secrets.JWTSecret != "" || cfg.Security.Secrets.APIKeyPepper != "" ||
+		cfg.Security.Encryption.KeysSecret != "" {
+		secretsProvider, err = secrets.NewProvider(cfg.Security.Secrets)
+		if err != nil {
+			cancelBackground()
+			return nil, fmt.Errorf("falha ao configurar provedor de segredos: %w", err)
+		}
+	}
+
+	// Inicializar criptografia em repouso dos campos sensíveis
+	envelope, err := newEnvelope(backgroundCtx, cfg, secretsProvider, logger)
+	if err != nil {
+		cancelBackground()
+		return nil, err
+	}
+
 	// Inicializar repositórios
-	routeRepo := database.NewRouteRepository(db.DB(), logger)
+	var routeRepo repository.RouteRepository = database.NewEncryptedRouteRepository(db.DB(), logger, envelope)
 	userRepo := database.NewUserRepository(db.DB())
 
+	// Particionar o armazenamento de rotas por tenant, se houver shards configurados
+	shards := make(map[string]*database.Database)
+	if len(cfg.Database.Shards) > 0 {
+		routeRepo, err = newShardedRouteRepository(ctx, cfg, routeRepo, envelope, shards, logger)
+		if err != nil {
+			cancelBackground()
+			for _, shardDB := range shards {
+				shardDB.Close()
+			}
+			return nil, err
+		}
+
+		if cfg.Metrics.Enabled {
+			for name, shardDB := range shards {
+				shardDB.StartPoolMetrics(backgroundCtx, name, cfg.Metrics.ReportInterval, apiMetrics)
+			}
+		}
+	}
+
+	securityHandler := http.NewSecurityHandler(logger)
+	if envelope != nil {
+		logger.Info("Criptografia em repouso habilitada",
+			zap.String("activeKey", cfg.Security.Encryption.ActiveKey))
+		if reEncrypter, ok := routeRepo.(repository.SecretReEncrypter); ok {
+			securityHandler.RegisterReEncrypter("routes", reEncrypter)
+		}
+	}
+
+	// Segredo JWT obtido do provedor de segredos, atualizado periodicamente
+	if ref := cfg.Security.Secrets.JWTSecret; ref != "" {
+		loadCtx, cancelLoad := context.WithTimeout(backgroundCtx, 15*time.Second)
+		jwtSecret, err := secrets.NewSecret(loadCtx, secretsProvider, ref, logger)
+		cancelLoad()
+		if err != nil {
+			cancelBackground()
+			return nil, err
+		}
+		go jwtSecret.Start(backgroundCtx, cfg.Security.Secrets.RefreshInterval)
+		security.SetJWTSecretSource(jwtSecret)
+		logger.Info("Segredo JWT obtido do provedor de segredos",
+			zap.String("provider", secretsProvider.Name()),
+			zap.Duration("refreshInterval", cfg.Security.Secrets.RefreshInterval))
+	}
+
 	// Inicializar gerenciador de chaves JWT
 	keyManager, err := security.NewKeyManager(logger)
 	if err != nil {
+		cancelBackground()
 		return nil, err
 	}
 
+	// Tokens de provedores de identidade externos, verificados pelo JWKS
+	if cfg.Auth.JWKS.URL != "" {
+		jwks := security.NewJWKS(cfg.Auth.JWKS.URL, cfg.Auth.JWKS.RefreshInterval, logger)
+		refreshCtx, cancelRefresh := context.WithTimeout(backgroundCtx, 10*time.Second)
+		if err := jwks.Refresh(refreshCtx); err != nil {
+			// As chaves serão obtidas novamente no primeiro token recebido
+			logger.Warn("Falha ao obter chaves JWKS na inicialização",
+				zap.String("url", cfg.Auth.JWKS.URL), zap.Error(err))
+		}
+		cancelRefresh()
+		go jwks.Start(backgroundCtx)
+		keyManager.SetJWKS(jwks, cfg.Auth.JWKS.Issuer, cfg.Auth.JWKS.Audience)
+		logger.Info("Validação de tokens via JWKS habilitada",
+			zap.String("url", cfg.Auth.JWKS.URL),
+			zap.String("issuer", cfg.Auth.JWKS.Issuer))
+	}
+
 	// Inicializar serviços
 	authService := auth.NewAuthService(keyManager, userRepo, logger)
+
+	// Log de auditoria imutável das operações administrativas
+	auditRecorder := audit.NewRecorder(database.NewAuditRepository(db.DB()), logger)
+
+	// Lista de tokens revogados, consultada a cada validação de token. A
+	// revogação só vale em todas as instâncias com um cache compartilhado; nos
+	// demais casos, os endpoints de revogação e de logout não são expostos.
+	var tokenHandler *http.TokenHandler
+	switch cacheInstance.(type) {
+	case *cache.RedisCache, *cache.MemcachedCache:
+		// As revogações precisam durar mais que qualquer token aceito
+		keyManager.SetMaxExternalLifetime(cfg.Auth.Revocation.MaxTokenLifetime)
+		tokenDenylist := auth.NewTokenDenylist(cacheInstance, cfg.Auth.Revocation.MaxTokenLifetime, logger)
+		tokenDenylist.SetAuditor(auditRecorder)
+		authService.SetDenylist(tokenDenylist)
+		tokenHandler = http.NewTokenHandler(authService, tokenDenylist, logger)
+	default:
+		logger.Warn("Cache compartilhado (redis ou memcached) indisponível: revogação de tokens desabilitada; /admin/tokens/revoke e /auth/logout não serão expostos")
+	}
 	routeService := route.NewService(routeRepo, cacheInstance, logger)
+	routeService.SetStaleWhileRevalidate(cfg.Cache.StaleWhileRevalidate)
+	routeService.SetSnapshot(cfg.Routes.SnapshotFile, envelope)
+	routeService.SetCacheEnvelope(envelope)
+	routeService.SetAuditor(auditRecorder)
+
+	// Notificação das alterações de rotas a sistemas externos
+	if webhooks := cfg.Routes.Webhooks; len(webhooks.Endpoints) > 0 {
+		webhook.NewDispatcher(webhooks, logger).Start(backgroundCtx, routeService)
+		logger.Info("Webhooks de alteração de rotas habilitados",
+			zap.Int("endpoints", len(webhooks.Endpoints)))
+	}
 
 	// Inicializar serviços de domínio
-	services, err := service.NewServices(routeRepo, userRepo, cacheInstance, logger)
+	services, err := service.NewServices(routeService, userRepo, logger)
 	if err != nil {
+		cancelBackground()
 		return nil, err
 	}
 
 	// Inicializar proxy reverso com métricas
 	reverseProxy := proxy.NewReverseProxy(cacheInstance, logger)
 	reverseProxy.SetMetrics(apiMetrics)
+	reverseProxy.SetEgressProxy(cfg.Upstream.EgressProxy)
+	reverseProxy.SetTransport(cfg.Upstream.Transport)
+	if cfg.Upstream.Resolver.Enabled {
+		resolver, err := dnscache.New(cfg.Upstream.Resolver, logger)
+		if err != nil {
+			cancelBackground()
+			return nil, fmt.Errorf("falha ao inicializar cache de DNS dos backends: %w", err)
+		}
+		reverseProxy.SetResolver(resolver)
+		go resolver.Run(backgroundCtx)
+	}
+	if cfg.Server.SecurityHeaders.Enabled {
+		reverseProxy.SetStripResponseHeaders(cfg.Server.SecurityHeaders.StripResponseHeaders)
+	}
+	if budget := cfg.Upstream.RetryBudget; budget.Enabled {
+		reverseProxy.SetRetryBudget(resilience.NewRetryBudget(budget.Ratio, budget.MinRetriesPerSecond, budget.Window))
+	}
+	serviceResolver := discovery.NewResolver()
+	if cfg.Upstream.Kubernetes.Enabled {
+		resolver, err := discovery.NewKubernetesResolver(backgroundCtx, cfg.Upstream.Kubernetes, logger)
+		if err != nil {
+			cancelBackground()
+			return nil, fmt.Errorf("falha ao inicializar descoberta de serviços do Kubernetes: %w", err)
+		}
+		serviceResolver.Register(model.ServiceScheme, resolver)
+	}
+	if cfg.Upstream.Consul.Enabled {
+		serviceResolver.Register(model.ConsulScheme, discovery.NewConsulSource(backgroundCtx, cfg.Upstream.Consul, logger))
+	}
+	if cfg.Upstream.DNS.Enabled {
+		serviceResolver.Register(model.SRVScheme, discovery.NewSRVSource(backgroundCtx, cfg.Upstream.DNS, logger))
+	}
+	reverseProxy.SetServiceResolver(serviceResolver)
+
+	// Operação em cluster: descoberta de instâncias, liderança e estado compartilhado
+	var clusterNode *cluster.Node
+	var clusterClient *redis.Client
+	if cfg.Cluster.Enabled {
+		clusterNode, clusterClient, err = newClusterNode(cfg, logger)
+		if err != nil {
+			cancelBackground()
+			return nil, err
+		}
+		clusterNode.OnLeadershipChange(func(leader bool) {
+			apiMetrics.ClusterLeadershipChanged(clusterNode.ID(), leader)
+		})
+		reverseProxy.SetCluster(clusterNode)
+	}
+
+	// Verificação ativa de saúde dos backends
+	healthRegistry := healthcheck.NewRegistry()
+	healthRegistry.OnTransition(func(health healthcheck.TargetHealth, previous healthcheck.Status) {
+		logger.Warn("Estado de saúde do backend alterado",
+			zap.String("route", health.Route),
+			zap.String("target", health.Target),
+			zap.String("from", string(previous)),
+			zap.String("to", string(health.Status)),
+			zap.String("lastError", health.LastError))
+		apiMetrics.UpstreamHealthChanged(health.Route, health.Target, health.Checker,
+			health.Status == healthcheck.StatusHealthy)
+	})
+	reverseProxy.SetHealthRegistry(healthRegistry)
+	routeService.SetHealthRegistry(healthRegistry)
+	if cfg.Features.HealthCheck {
+		checker := healthcheck.NewChecker(routeService, healthRegistry, time.Second, logger)
+		checker.RegisterProber(model.HealthCheckGRPC, healthcheck.NewGRPCProber(proxy.UpstreamTLSConfig))
+		checker.RegisterProber(model.HealthCheckHTTP, healthcheck.NewHTTPProber(reverseProxy.UpstreamTransport))
+		if clusterNode != nil {
+			// Em cluster, apenas o líder verifica os backends e replica o resultado
+			healthcheck.ShareWithCluster(healthRegistry, clusterNode, logger)
+			clusterNode.RunAsLeader(backgroundCtx, "healthcheck", checker.Start)
+		} else {
+			go checker.Start(backgroundCtx)
+		}
+	}
+	if clusterNode != nil {
+		go clusterNode.Start(backgroundCtx)
+	}
+	if cfg.Cache.Response.Enabled {
+		reverseProxy.SetResponseCache(proxy.NewResponseCache(cacheInstance, cfg.Cache.Response, logger))
+	}
 
 	// Inicializar middleware com as métricas já criadas
 	metricsMiddleware := middleware.NewMetricsMiddleware(apiMetrics, logger)
-	middlewares := middleware.NewMiddleware(logger, authService, apiMetrics)
+	middlewares, err := middleware.NewMiddleware(logger, authService, apiMetrics)
+	if err != nil {
+		cancelBackground()
+		return nil, fmt.Errorf("falha ao inicializar middlewares: %w", err)
+	}
 
 	// Adicionar middleware de métricas ao conjunto de middlewares
 	middlewares.SetMetricsMiddleware(metricsMiddleware)
 
 	// Inicializar handlers HTTP com métricas
 	handler := http.NewHandler(routeService, reverseProxy, db, cacheInstance, logger)
+	for name, shardDB := range shards {
+		handler.AddDatabaseShard(name, shardDB)
+	}
+	handler.SetHealthTimeout(cfg.Health.Timeout)
+	for _, path := range cfg.Health.CriticalUpstreams {
+		handler.AddCriticalUpstream(path)
+	}
+
+	if cfg.Routes.File != "" {
+		// Rotas declarativas: o arquivo é aplicado agora e, opcionalmente, a cada alteração
+		routeFile := routefile.NewSource(cfg.Routes.File, routeService, cfg.Routes.Prune, logger)
+		routeFileCtx := audit.WithActor(backgroundCtx, audit.Actor{Username: "system:routefile"})
+		routeFile.Apply(routeFileCtx)
+		if cfg.Routes.Watch {
+			go routeFile.Watch(routeFileCtx, cfg.Routes.Debounce)
+		}
+	} else {
+		// Carregar rotas do arquivo JSON se existir
+		jsonLoader := database.NewJSONRouteLoader(routeRepo, logger)
+		if err := jsonLoader.LoadRoutesFromJSON("./config/routes.json"); err != nil {
+			logger.Error("Erro ao carregar rotas do arquivo JSON", zap.Error(err))
+		}
+	}
 
-	// Carregar rotas do arquivo JSON se existir
-	jsonLoader := database.NewJSONRouteLoader(db, logger)
-	if err := jsonLoader.LoadRoutesFromJSON("./config/routes.json"); err != nil {
-		logger.Error("Erro ao carregar rotas do arquivo JSON", zap.Error(err))
+	if cfg.Routes.Kubernetes.Enabled {
+		// Rotas declaradas como recursos do cluster, reconciliadas continuamente
+		controller, err := routecrd.NewController(cfg.Routes.Kubernetes, routeService, logger)
+		if err != nil {
+			cancelBackground()
+			return nil, fmt.Errorf("falha ao inicializar sincronização de rotas do Kubernetes: %w", err)
+		}
+		controllerCtx := audit.WithActor(backgroundCtx, audit.Actor{Username: "system:kubernetes"})
+		if clusterNode != nil {
+			// Em cluster, apenas o líder aplica as rotas e atualiza o status dos recursos
+			clusterNode.RunAsLeader(controllerCtx, "routes-kubernetes", controller.Run)
+		} else {
+			go controller.Run(controllerCtx)
+		}
+	}
+
+	// Carregar as rotas antes de a instância ficar pronta. Se a carga falhar,
+	// o readiness indica indisponibilidade até uma nova tentativa ter sucesso.
+	if cfg.Routes.SnapshotFile != "" {
+		// Com o snapshot, as rotas continuam sendo atendidas sem o banco
+		handler.SetDatabaseCritical(false)
+	}
+	if cfg.Routes.Warmup {
+		handler.RequireWarmRoutes()
+		warmCtx, cancelWarm := context.WithTimeout(backgroundCtx, 15*time.Second)
+		count, err := routeService.Warm(warmCtx)
+		cancelWarm()
+		if err != nil {
+			logger.Warn("Falha na carga inicial das rotas; a instância não ficará pronta até a próxima tentativa",
+				zap.Duration("retryInterval", cfg.Routes.WarmupRetryInterval), zap.Error(err))
+			go routeService.WarmUntilReady(backgroundCtx, cfg.Routes.WarmupRetryInterval)
+		} else {
+			logger.Info("Rotas carregadas no cache e no índice", zap.Int("routes", count))
+		}
 	}
 
 	// Configurar métricas no handler
 	handler.SetMetrics(apiMetrics)
+	handler.SetCompression(cfg.Server.Compression)
+	handler.SetRateLimitHeaders(cfg.RateLimit.Headers)
+
+	// API administrativa de rotas versionada
+	routesHandler := http.NewAdminRouteHandler(routeService, logger)
+	routesHandler.SetMetrics(apiMetrics)
+
+	// Catálogo OpenAPI agregado dos backends
+	openAPIAggregator := openapi.NewAggregator(routeService, logger)
+	openAPIAggregator.SetTitle(cfg.OpenAPI.Title)
+	openAPIAggregator.SetCacheTTL(cfg.OpenAPI.CacheTTL)
+	openAPIAggregator.SetTimeout(cfg.OpenAPI.Timeout)
+	openAPIHandler := http.NewOpenAPIHandler(openAPIAggregator, logger)
+	handler.SetOpenAPI(openAPIAggregator)
+	openAPIHandler.SetPublicURL(cfg.OpenAPI.PublicURL)
+
+	// Estatísticas por rota em janelas de tempo, gravadas no banco por instância
+	var routeStats *routestats.Service
+	if cfg.Metrics.RouteStats.Enabled {
+		statsNode, err := instanceID(cfg, clusterNode)
+		if err != nil {
+			cancelBackground()
+			return nil, err
+		}
+		routeStats = routestats.NewService(database.NewRouteStatsRepository(db.DB()), statsNode, cfg.Metrics.RouteStats, logger)
+		handler.SetRouteStats(routeStats)
+		routesHandler.SetRouteStats(routeStats)
+		go routeStats.Run(backgroundCtx)
+	}
+
+	// Agregador de uso para atribuição de custos
+	usageAggregator := usage.NewAggregator()
+	handler.SetUsageAggregator(usageAggregator)
+	handler.SetAuthService(authService)
+
+	// Autorização delegada a serviços externos por rota (HTTP ou ext_authz gRPC)
+	externalAuthorizer := extauthz.NewAuthorizer(cacheInstance, logger)
+	handler.SetExternalAuthorizer(externalAuthorizer)
+
+	// Webhooks pré-requisição e pós-resposta definidos nas rotas
+	hookClient := webhook.NewHookClient(cfg.Routes.Hooks, logger)
+	hookClient.Start(backgroundCtx)
+	handler.SetHooks(hookClient)
+
+	// Chaves de API para consumidores máquina-a-máquina
+	var apiKeyHandler *http.APIKeyHandler
+	var apiKeyService *auth.APIKeyService
+	if cfg.Auth.APIKeys.Enabled {
+		apiKeyService = auth.NewAPIKeyService(database.NewAPIKeyRepository(db.DB()), cacheInstance, cfg.Auth.APIKeys, logger)
+		apiKeyService.SetAuditor(auditRecorder)
+		if ref := cfg.Security.Secrets.APIKeyPepper; ref != "" {
+			loadCtx, cancelLoad := context.WithTimeout(backgroundCtx, 15*time.Second)
+			pepper, err := secrets.NewSecret(loadCtx, secretsProvider, ref, logger)
+			cancelLoad()
+			if err != nil {
+				cancelBackground()
+				return nil, err
+			}
+			go pepper.Start(backgroundCtx, cfg.Security.Secrets.RefreshInterval)
+			apiKeyService.SetPepper(pepper)
+		}
+		middlewares.SetAPIKeys(apiKeyService, cfg.Auth.APIKeys.Header)
+		apiKeyHandler = http.NewAPIKeyHandler(apiKeyService, logger)
+		logger.Info("Autenticação por chaves de API habilitada",
+			zap.String("header", cfg.Auth.APIKeys.Header),
+			zap.Int("tiers", len(cfg.Auth.APIKeys.Tiers)))
+	}
+
+	// Credenciais HTTP Basic e assinaturas HMAC para parceiros sem JWT ou OAuth
+	var credentialHandler *http.CredentialHandler
+	var credentialService *auth.CredentialService
+	if cfg.Auth.Credentials.Enabled {
+		credentialService = auth.NewCredentialService(database.NewCredentialRepository(db.DB(), envelope), cacheInstance, cfg.Auth.Credentials, logger)
+		credentialService.SetAuditor(auditRecorder)
+		handler.SetCredentials(credentialService)
+		credentialHandler = http.NewCredentialHandler(credentialService, logger)
+		logger.Info("Autenticação por credenciais basic e hmac habilitada",
+			zap.String("realm", cfg.Auth.Credentials.Realm),
+			zap.Duration("maxSkew", cfg.Auth.Credentials.MaxSkew))
+		switch cacheInstance.(type) {
+		case *cache.MemoryCache, *cache.LocalCache:
+			logger.Warn("Cache local: nonces das assinaturas hmac não são compartilhados entre instâncias; use redis ou memcached com mais de uma réplica")
+		}
+	}
+
+	// Cotas de uso diárias e mensais por consumidor, contadas no Redis
+	var quotaService *quota.Service
+	var quotaHandler *http.QuotaHandler
+	var quotaClient *redis.Client
+	if cfg.Quotas.Enabled {
+		quotaClient, err = cache.NewRedisClientWithConfig(&redis.Options{
+			Addr:     cfg.Cache.Redis.Address,
+			Password: cfg.Cache.Redis.Password,
+			DB:       cfg.Cache.Redis.DB,
+		}, logger)
+		if err != nil {
+			cancelBackground()
+			return nil, fmt.Errorf("falha ao conectar ao Redis das cotas: %w", err)
+		}
+		quotaService = quota.NewService(quotaClient, database.NewQuotaRepository(db.DB()), cfg.Quotas, logger)
+		quotaHandler = http.NewQuotaHandler(quotaService, logger)
+		if apiKeyService != nil {
+			quotaService.SetTiers(apiKeyService)
+			quotaHandler.SetAPIKeys(apiKeyService)
+		}
+		handler.SetQuotas(quotaService)
+
+		if clusterNode != nil {
+			// Em cluster, apenas o líder grava os contadores compartilhados no banco
+			clusterNode.RunAsLeader(backgroundCtx, "quotas", quotaService.Run)
+		} else {
+			go quotaService.Run(backgroundCtx)
+		}
+		logger.Info("Cotas de uso habilitadas",
+			zap.Int64("daily", cfg.Quotas.Daily),
+			zap.Int64("monthly", cfg.Quotas.Monthly))
+	}
+
+	// Respostas das requisições com Idempotency-Key, compartilhadas no Redis
+	var idempotencyClient *redis.Client
+	if cfg.Idempotency.Enabled {
+		idempotencyClient, err = cache.NewRedisClientWithConfig(&redis.Options{
+			Addr:     cfg.Cache.Redis.Address,
+			Password: cfg.Cache.Redis.Password,
+			DB:       cfg.Cache.Redis.DB,
+		}, logger)
+		if err != nil {
+			cancelBackground()
+			return nil, fmt.Errorf("falha ao conectar ao Redis da idempotência: %w", err)
+		}
+		handler.SetIdempotency(idempotency.NewStore(idempotencyClient, cfg.Idempotency, logger))
+		logger.Info("Idempotência habilitada",
+			zap.String("header", cfg.Idempotency.Header),
+			zap.Duration("ttl", cfg.Idempotency.TTL))
+	}
+
+	// Gravação de uma amostra das requisições das rotas para reprodução
+	var recordingHandler *http.RecordingHandler
+	if cfg.Recording.Enabled {
+		recorder := recording.NewRecorder(database.NewRecordingRepository(db.DB()), cfg.Recording, apiMetrics, logger)
+		handler.SetRecorder(recorder)
+		recordingHandler = http.NewRecordingHandler(recorder, logger)
+		go recorder.Run(backgroundCtx)
+		if clusterNode != nil {
+			clusterNode.RunAsLeader(backgroundCtx, "recording-retention", recorder.RunRetention)
+		} else {
+			go recorder.RunRetention(backgroundCtx)
+		}
+		logger.Info("Gravação de tráfego habilitada",
+			zap.Int("queueSize", cfg.Recording.QueueSize),
+			zap.Duration("retention", cfg.Recording.Retention))
+	}
+
+	// Provedor das feature flags que habilitam as rotas por coorte
+	featureFlags := featureflag.New(cfg.Flags, logger)
+	if featureFlags != nil {
+		handler.SetFeatureFlags(featureFlags)
+		logger.Info("Feature flags das rotas habilitadas", zap.String("provider", cfg.Flags.Provider))
+	}
+
+	// Autenticação das rotas protegidas por provedores OpenID Connect
+	var oidcHandler *http.OIDCHandler
+	if len(cfg.Auth.OIDC.Providers) > 0 {
+		oidcService := auth.NewOIDCService(cfg.Auth.OIDC, cacheInstance, logger)
+		oidcService.SetCookieEncryptor(envelope)
+		handler.SetOIDC(oidcService)
+		oidcHandler = http.NewOIDCHandler(oidcService, logger)
+		logger.Info("Autenticação OIDC de rotas habilitada",
+			zap.Int("providers", len(cfg.Auth.OIDC.Providers)))
+	}
+
+	// API administrativa gRPC, compartilhando o serviço de rotas da API REST para
+	// que as alterações feitas por ambas sejam publicadas em WatchRoutes
+	var grpcAdmin *grpcadmin.Server
+	if cfg.Admin.GRPC.Enabled {
+		grpcAdmin, err = grpcadmin.NewServer(routeService, userRepo, authService, db, cacheInstance, logger)
+		if err != nil {
+			cancelBackground()
+			return nil, err
+		}
+		grpcAdmin.SetTenantHeader(cfg.Database.TenantHeader)
+		grpcAdmin.SetSourceFilter(middlewares.AllowsAdminAddr)
+	}
+
+	// Configurações aplicadas sem reinício quando a configuração é recarregada
+	snapshot.OnReload(func(cfg *config.Config) {
+		if err := logging.SetLevel(cfg.Logging.Level); err != nil {
+			logger.Warn("Nível de log inválido", zap.String("level", cfg.Logging.Level), zap.Error(err))
+		}
+		middlewares.Reload(cfg)
+		handler.SetHealthTimeout(cfg.Health.Timeout)
+		handler.SetCompression(cfg.Server.Compression)
+		handler.SetRateLimitHeaders(cfg.RateLimit.Headers)
+		if cfg.Server.SecurityHeaders.Enabled {
+			reverseProxy.SetStripResponseHeaders(cfg.Server.SecurityHeaders.StripResponseHeaders)
+		} else {
+			reverseProxy.SetStripResponseHeaders(nil)
+		}
+		if err := keyManager.Reload(cfg.Auth); err != nil {
+			logger.Warn("Configuração de autenticação recarregada não aplicada", zap.Error(err))
+		}
+		if static, ok := featureFlags.(*featureflag.StaticProvider); ok {
+			static.SetFlags(cfg.Flags.Flags)
+		}
+		if apiKeyService != nil {
+			apiKeyService.SetTiers(cfg.Auth.APIKeys.Tiers)
+		}
+		if credentialService != nil {
+			credentialService.SetConfig(cfg.Auth.Credentials)
+		}
+		if quotaService != nil {
+			quotaService.SetLimits(cfg.Quotas.Daily, cfg.Quotas.Monthly)
+		}
+	})
+
+	var adminUIHandler *http.AdminUIHandler
+	if cfg.Admin.UI.Enabled {
+		adminUIHandler = http.NewAdminUIHandler(web.AdminUI(), logger)
+	}
 
 	return &App{
 		Logger:         logger,
@@ -189,17 +779,315 @@ func NewApp(logger *zap.Logger, cfg *config.Config) (*App, error) {
 		Cache:          cacheInstance,
 		MetricsHandler: metricsHandler,
 		APIMetrics:     apiMetrics,
+
+		SecurityHandler: securityHandler,
+		UsageHandler:    http.NewUsageHandler(usageAggregator, logger),
+		APIKeyHandler:   apiKeyHandler,
+		OIDCHandler:     oidcHandler,
+		GRPCAdmin:       grpcAdmin,
+		ClusterHandler:  http.NewClusterHandler(clusterNode, logger),
+		TokenHandler:    tokenHandler,
+		RoutesHandler:   routesHandler,
+		OpenAPIHandler:  openAPIHandler,
+		AuditHandler:    http.NewAuditHandler(auditRecorder, logger),
+		QuotaHandler:    quotaHandler,
+		Shards:          shards,
+
+		RouteEventsHandler: http.NewRouteEventsHandler(routeService, logger),
+		RecordingHandler:   recordingHandler,
+		CredentialHandler:  credentialHandler,
+		AdminUIHandler:     adminUIHandler,
+
+		CertificateStore: newCertificateStore(cfg, db, cacheInstance, logger),
+
+		cluster:       clusterNode,
+		clusterClient: clusterClient,
+
+		quotas:      quotaService,
+		routeStats:  routeStats,
+		quotaClient: quotaClient,
+
+		idempotencyClient: idempotencyClient,
+
+		cancelBackground:   cancelBackground,
+		externalAuthorizer: externalAuthorizer,
+
+		config:  snapshot,
+		auditor: auditRecorder,
 	}, nil
 }
 
+// ReloadConfig recarrega a configuração desta instância, aplicando as chaves
+// recarregáveis. Uma configuração inválida é rejeitada e a atual é mantida.
+func (a *App) ReloadConfig(ctx context.Context) (config.ReloadResult, error) {
+	result, err := a.config.Reload()
+	if err != nil {
+		a.Logger.Error("Configuração inválida; a atual foi mantida", zap.Error(err))
+		return result, err
+	}
+
+	a.Logger.Info("Configuração recarregada", zap.Strings("applied", result.Applied))
+	if len(result.RestartRequired) > 0 {
+		a.Logger.Warn("Alterações na configuração que exigem reinício foram ignoradas",
+			zap.Strings("keys", result.RestartRequired))
+	}
+	a.auditor.Record(ctx, model.AuditConfigReload, "config", nil, result)
+	return result, nil
+}
+
+// newClusterNode conecta ao Redis de coordenação e cria a instância do cluster
+func newClusterNode(cfg *config.Config, logger *zap.Logger) (*cluster.Node, *redis.Client, error) {
+	client, err := cache.NewRedisClientWithConfig(&redis.Options{
+		Addr:     cfg.Cache.Redis.Address,
+		Password: cfg.Cache.Redis.Password,
+		DB:       cfg.Cache.Redis.DB,
+	}, logger)
+	if err != nil {
+		return nil, nil, fmt.Errorf("falha ao conectar ao Redis do cluster: %w", err)
+	}
+
+	nodeID := cfg.Cluster.NodeID
+	if nodeID == "" {
+		if nodeID, err = os.Hostname(); err != nil {
+			client.Close()
+			return nil, nil, fmt.Errorf("falha ao obter hostname para identificar a instância: %w", err)
+		}
+	}
+
+	node, err := cluster.NewNode(client, cluster.Config{
+		Name:              cfg.Cluster.Name,
+		NodeID:            nodeID,
+		Address:           cfg.Cluster.AdvertiseAddress,
+		HeartbeatInterval: cfg.Cluster.HeartbeatInterval,
+		MemberTTL:         cfg.Cluster.MemberTTL,
+		LeaseTTL:          cfg.Cluster.LeaseTTL,
+	}, logger)
+	if err != nil {
+		client.Close()
+		return nil, nil, err
+	}
+
+	// Eleição do líder por um Lease do Kubernetes; membros e mensagens
+	// continuam no Redis
+	if cfg.Cluster.Election == "kubernetes" {
+		kube, err := kubeapi.NewInClusterClient(cfg.Cluster.Kubernetes.APIServer)
+		if err != nil {
+			client.Close()
+			return nil, nil, fmt.Errorf("falha ao configurar eleição pelo Kubernetes: %w", err)
+		}
+		leaseName := cfg.Cluster.Kubernetes.LeaseName
+		if leaseName == "" {
+			leaseName = cfg.Cluster.Name + "-leader"
+		}
+		lease, err := cluster.NewKubernetesLease(kube, cfg.Cluster.Kubernetes.Namespace, leaseName)
+		if err != nil {
+			client.Close()
+			return nil, nil, err
+		}
+		node.SetLease(lease)
+	}
+
+	logger.Info("Operação em cluster habilitada",
+		zap.String("cluster", cfg.Cluster.Name),
+		zap.String("node", nodeID),
+		zap.String("election", cfg.Cluster.Election))
+	return node, client, nil
+}
+
+// instanceID identifica a instância: o identificador no cluster ou o hostname
+func instanceID(cfg *config.Config, node *cluster.Node) (string, error) {
+	if node != nil {
+		return node.ID(), nil
+	}
+	if cfg.Cluster.NodeID != "" {
+		return cfg.Cluster.NodeID, nil
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		return "", fmt.Errorf("falha ao obter hostname para identificar a instância: %w", err)
+	}
+	return hostname, nil
+}
+
+// newDatabaseConfig monta a configuração de conexão com as opções de pool comuns
+func newDatabaseConfig(cfg *config.Config, driver, dsn string) database.Config {
+	return database.Config{
+		Driver:          driver,
+		DSN:             dsn,
+		MaxIdleConns:    cfg.Database.MaxIdleConns,
+		MaxOpenConns:    cfg.Database.MaxOpenConns,
+		ConnMaxLifetime: cfg.Database.ConnMaxLifetime,
+		ConnMaxIdleTime: cfg.Database.ConnMaxIdleTime,
+		PingTimeout:     cfg.Database.PingTimeout,
+		QueryTimeout:    cfg.Database.QueryTimeout,
+		LogLevel:        4,
+		SlowThreshold:   cfg.Database.SlowThreshold,
+		MigrationDir:    cfg.Database.MigrationDir,
+		SkipMigrations:  cfg.Database.SkipMigrations,
+	}
+}
+
+// newShardedRouteRepository conecta aos shards configurados e cria o repositório
+// de rotas particionado. As conexões abertas são registradas em shards.
+func newShardedRouteRepository(ctx context.Context, cfg *config.Config, primary repository.RouteRepository,
+	envelope *encryption.Envelope, shards map[string]*database.Database, logger *zap.Logger) (repository.RouteRepository, error) {
+
+	repos := map[string]repository.RouteRepository{database.PrimaryShard: primary}
+	names := []string{database.PrimaryShard}
+	assignments := make(map[string]string)
+
+	for _, shardCfg := range cfg.Database.Shards {
+		shardDB, err := database.NewDatabase(ctx, newDatabaseConfig(cfg, shardCfg.Driver, shardCfg.DSN), logger)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao conectar ao shard %s: %w", shardCfg.Name, err)
+		}
+
+		shards[shardCfg.Name] = shardDB
+		repos[shardCfg.Name] = database.NewEncryptedRouteRepository(shardDB.DB(), logger, envelope)
+		names = append(names, shardCfg.Name)
+
+		for _, tenantID := range shardCfg.Tenants {
+			assignments[tenantID] = shardCfg.Name
+		}
+
+		logger.Info("Shard de banco de dados conectado",
+			zap.String("shard", shardCfg.Name),
+			zap.String("driver", shardCfg.Driver),
+			zap.Int("tenants", len(shardCfg.Tenants)))
+	}
+
+	resolver := database.NewShardResolver(assignments, names, cfg.Database.HashUnassignedTenants)
+	sharded, err := database.NewShardedRouteRepository(repos, resolver, logger)
+	if err != nil {
+		return nil, err
+	}
+	return sharded, nil
+}
+
+// newEnvelope cria o envelope de criptografia em repouso a partir da configuração.
+// As chaves de security.encryption.keysSecret são lidas do provedor de segredos
+// na inicialização. Retorna nil quando a criptografia está desabilitada.
+func newEnvelope(ctx context.Context, cfg *config.Config, provider secrets.Provider, logger *zap.Logger) (*encryption.Envelope, error) {
+	encCfg := cfg.Security.Encryption
+	if !encCfg.Enabled {
+		return nil, nil
+	}
+
+	keys := make(map[string]string, len(encCfg.Keys))
+	for id, key := range encCfg.Keys {
+		keys[id] = key
+	}
+
+	specs := []string{encCfg.KeySpec}
+	if encCfg.KeysSecret != "" {
+		loadCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+		secret, err := secrets.NewSecret(loadCtx, provider, encCfg.KeysSecret, logger)
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("falha ao obter chaves de criptografia: %w", err)
+		}
+		specs = append(specs, secret.Value())
+	} else {
+		logger.Warn("Chaves de criptografia lidas da configuração; prefira security.encryption.keysSecret")
+	}
+
+	for _, spec := range specs {
+		if spec == "" {
+			continue
+		}
+		specKeys, err := encryption.ParseKeySpec(spec)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao interpretar chaves de criptografia: %w", err)
+		}
+		for id, key := range specKeys {
+			keys[id] = key
+		}
+	}
+
+	keyring, err := encryption.NewKeyring(encCfg.ActiveKey, keys)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao carregar chaves de criptografia: %w", err)
+	}
+
+	return encryption.NewEnvelope(keyring)
+}
+
+// Close encerra as rotinas em segundo plano e libera os recursos da aplicação
+func (a *App) Close() error {
+	if a.cancelBackground != nil {
+		a.cancelBackground()
+	}
+	if a.cluster != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		if err := a.cluster.Leave(ctx); err != nil {
+			a.Logger.Warn("Falha ao deixar o cluster", zap.Error(err))
+		}
+		cancel()
+		a.clusterClient.Close()
+	}
+	if a.quotas != nil {
+		// Grava os contadores pendentes antes de fechar o banco
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		a.quotas.Flush(ctx)
+		cancel()
+		a.quotaClient.Close()
+	}
+	if a.routeStats != nil {
+		// Grava as estatísticas pendentes antes de fechar o banco
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		a.routeStats.Flush(ctx)
+		cancel()
+	}
+	if a.idempotencyClient != nil {
+		a.idempotencyClient.Close()
+	}
+	if a.externalAuthorizer != nil {
+		a.externalAuthorizer.Close()
+	}
+	for name, shardDB := range a.Shards {
+		if err := shardDB.Close(); err != nil {
+			a.Logger.Error("Falha ao fechar shard de banco de dados",
+				zap.String("shard", name), zap.Error(err))
+		}
+	}
+	if a.DB != nil {
+		return a.DB.Close()
+	}
+	return nil
+}
+
+// newCertificateStore escolhe onde os certificados do ACME são guardados. O
+// cache (Redis) e o banco de dados compartilham os certificados e os desafios
+// entre as instâncias.
+func newCertificateStore(cfg *config.Config, db *database.Database, c cache.Cache, logger *zap.Logger) autocert.Cache {
+	switch cfg.Server.ACME.Storage {
+	case "cache":
+		if !cfg.Cache.Enabled || cfg.Cache.Type != "redis" {
+			logger.Warn("Certificados ACME no cache em memória não são compartilhados entre instâncias")
+		}
+		return acme.NewCacheStore(c)
+	case "database":
+		return acme.NewRepositoryStore(database.NewCertificateRepository(db.DB()))
+	default:
+		return autocert.DirCache(cfg.Server.ACME.CacheDir)
+	}
+}
+
 // RegisterRoutes registra todas as rotas no router
 func (a *App) RegisterRoutes(router *gin.Engine) {
 	// Configurar middleware global
+	router.Use(a.Middleware.RequestID())
+	router.Use(a.Middleware.ClientIP())
+	router.Use(a.Middleware.Geo())
+	router.Use(a.Middleware.RequestLimits())
+	router.Use(a.Middleware.SecurityHeaders())
 	router.Use(a.Middleware.IgnoreFavicon())
 	router.Use(a.Middleware.Recovery())
+	router.Use(a.Middleware.Tenant())
 	router.Use(a.Middleware.Logger())
 	router.Use(a.Middleware.Tracing())
 	router.Use(a.Middleware.Metrics())
+	router.Use(a.Middleware.APIKeyAuth())
 
 	userHandler := http.NewUserHandler(a.DB.DB(), a.Logger)
 
@@ -207,11 +1095,18 @@ func (a *App) RegisterRoutes(router *gin.Engine) {
 	auth := router.Group("/auth")
 	{
 		auth.POST("/login", userHandler.Login)
+		if a.TokenHandler != nil {
+			auth.POST("/logout", a.TokenHandler.Logout)
+		}
+		if a.OIDCHandler != nil {
+			auth.GET("/oidc/:provider/callback", a.OIDCHandler.Callback)
+			auth.POST("/oidc/:provider/logout", a.OIDCHandler.Logout)
+		}
 	}
 
 	// Rotas para gerenciamento de usuários
 	users := router.Group("/admin/users")
-	users.Use(a.Middleware.Authenticate)
+	users.Use(a.Middleware.AdminIPFilter(), a.Middleware.TenantHeader(), a.Middleware.Authenticate)
 	{
 		users.POST("", userHandler.RegisterUser)     // Criar novo usuário
 		users.GET("", userHandler.GetUsers)          // Listar todos os usuários
@@ -221,17 +1116,23 @@ func (a *App) RegisterRoutes(router *gin.Engine) {
 	}
 
 	// Expor endpoint de métricas para Prometheus
-	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
-	a.Logger.Info("Endpoint de métricas Prometheus registrado em /metrics")
+	if a.MetricsHandler != nil {
+		a.MetricsHandler.RegisterEndpoint(router)
+	}
 
 	// Rotas públicas
 	router.GET("/health", a.Handler.HealthCheck)
 	router.GET("/health/readiness", a.Handler.ReadinessCheck)
 	router.GET("/health/liveness", a.Handler.HealthCheck)
 
+	// Probes do Kubernetes
+	router.GET("/healthz", a.Handler.HealthCheck)
+	router.GET("/livez", a.Handler.HealthCheck)
+	router.GET("/readyz", a.Handler.ReadinessCheck)
+
 	// Rotas administrativas
 	admin := router.Group("/admin")
-	admin.Use(a.Middleware.Authenticate)
+	admin.Use(a.Middleware.AdminIPFilter(), a.Middleware.TenantHeader(), a.Middleware.Authenticate)
 	{
 		admin.POST("/register", a.Handler.RegisterAPI)
 		admin.GET("/apis", a.Handler.ListAPIs)
@@ -240,8 +1141,30 @@ func (a *App) RegisterRoutes(router *gin.Engine) {
 		admin.GET("/metrics", a.Handler.GetMetrics)
 		//admin.POST("/users", userHandler.RegisterUser)
 		admin.GET("/clear-cache", a.Handler.ClearCache)
+		admin.DELETE("/cache/responses", a.Handler.PurgeResponseCache)
 		admin.GET("/diagnose-route", a.Handler.DiagnoseRoute)
-		admin.GET("/health/detailed", a.Handler.DetailedHealth)
+		admin.POST("/explain-route", a.Handler.ExplainRoute)
+
+		// Operações que afetam todos os tenants, negadas aos administradores de um tenant
+		global := admin.Group("", a.Middleware.RequireGlobalAdmin)
+		global.GET("/health/detailed", a.Handler.DetailedHealth)
+		global.GET("/health/upstreams", a.Handler.UpstreamHealth)
+		global.POST("/security/reencrypt", a.SecurityHandler.ReEncrypt)
+		global.GET("/usage/export", a.UsageHandler.Export)
+		global.GET("/cluster/members", a.ClusterHandler.Members)
+		if a.TokenHandler != nil {
+			global.POST("/tokens/revoke", a.TokenHandler.Revoke)
+		}
+		if a.APIKeyHandler != nil {
+			global.POST("/apikeys", a.APIKeyHandler.Create)
+			global.GET("/apikeys", a.APIKeyHandler.List)
+			global.DELETE("/apikeys/:id", a.APIKeyHandler.Revoke)
+		}
+		if a.CredentialHandler != nil {
+			global.POST("/credentials", a.CredentialHandler.Create)
+			global.GET("/credentials", a.CredentialHandler.List)
+			global.DELETE("/credentials/:id", a.CredentialHandler.Revoke)
+		}
 
 		// Rota de diagnóstico (apenas para desenvolvimento)
 		if os.Getenv("ENV") == "development" {
@@ -249,10 +1172,36 @@ func (a *App) RegisterRoutes(router *gin.Engine) {
 		}
 	}
 
-	router.Any("/api/*path", a.Handler.ServeAPI)
-	router.Any("/ws/*path", a.Handler.ServeAPI)
+	// API administrativa versionada, restrita a administradores
+	adminV1 := router.Group("/admin/api/v1")
+	adminV1.Use(a.Middleware.AdminIPFilter(), a.Middleware.TenantHeader(), a.Middleware.AuthenticateAdmin)
+	a.RoutesHandler.RegisterRoutes(adminV1)
+	a.OpenAPIHandler.RegisterRoutes(adminV1)
+	a.RouteEventsHandler.RegisterRoutes(adminV1)
+
+	adminV1Global := adminV1.Group("", a.Middleware.RequireGlobalAdmin)
+	a.AuditHandler.RegisterRoutes(adminV1Global)
+	http.NewAccessLogHandler(a.Middleware, a.Logger).RegisterRoutes(adminV1Global)
+	http.NewConfigHandler(a, a.Logger).RegisterRoutes(adminV1Global)
+	if a.QuotaHandler != nil {
+		a.QuotaHandler.RegisterRoutes(adminV1Global)
+	}
+	if a.RecordingHandler != nil {
+		a.RecordingHandler.RegisterRoutes(adminV1Global)
+	}
+
+	// Painel administrativo; a autenticação é feita pela API que ele consome
+	if a.AdminUIHandler != nil {
+		a.AdminUIHandler.RegisterRoutes(router.Group("/admin/ui", a.Middleware.AdminIPFilter()))
+	}
+
+	// A proteção contra sobrecarga vale apenas para o tráfego das rotas; as
+	// rotas administrativas e de saúde continuam respondendo sob carga
+	overload := a.Middleware.Overload()
+	router.Any("/api/*path", overload, a.Handler.ServeAPI)
+	router.Any("/ws/*path", overload, a.Handler.ServeAPI)
 
-	router.NoRoute(func(c *gin.Context) {
+	router.NoRoute(overload, func(c *gin.Context) {
 		// Verificar se estamos em uma rota que já possui um handler
 		path := c.Request.URL.Path
 
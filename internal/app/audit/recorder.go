@@ -0,0 +1,131 @@
+// Package audit registra as operações administrativas em um log imutável.
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/internal/domain/repository"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Actor identifica quem executou a operação
+type Actor struct {
+	ID       string
+	Username string
+	IP       string
+}
+
+// systemActor identifica operações sem usuário autenticado no contexto
+const systemActor = "system"
+
+type actorContextKey struct{}
+
+// WithActor associa ao contexto quem executa as operações
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, actor)
+}
+
+// ActorFromContext retorna quem executa as operações, se informado
+func ActorFromContext(ctx context.Context) (Actor, bool) {
+	actor, ok := ctx.Value(actorContextKey{}).(Actor)
+	return actor, ok
+}
+
+// Recorder grava os registros de auditoria das operações administrativas
+type Recorder struct {
+	repo   repository.AuditRepository
+	logger *zap.Logger
+}
+
+// NewRecorder cria o gravador de auditoria
+func NewRecorder(repo repository.AuditRepository, logger *zap.Logger) *Recorder {
+	return &Recorder{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// Record grava a operação com o estado anterior e o posterior do recurso,
+// ambos opcionais, e os campos alterados entre eles. A operação já foi
+// aplicada, então uma falha na gravação é registrada em log sem desfazê-la.
+func (r *Recorder) Record(ctx context.Context, action, resource string, before, after interface{}) {
+	if r == nil {
+		return
+	}
+
+	record := &model.AuditRecord{
+		ID:       uuid.New().String(),
+		Time:     time.Now().UTC(),
+		Action:   action,
+		Resource: resource,
+		Actor:    systemActor,
+		Before:   marshalState(before),
+		After:    marshalState(after),
+	}
+	if actor, ok := ActorFromContext(ctx); ok {
+		record.ActorID = actor.ID
+		record.SourceIP = actor.IP
+		if actor.Username != "" {
+			record.Actor = actor.Username
+		}
+	}
+	record.Changes = diff(record.Before, record.After)
+
+	// A gravação não depende do cancelamento da requisição que a originou
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	if err := r.repo.CreateAuditRecord(ctx, record); err != nil {
+		r.logger.Error("Falha ao gravar registro de auditoria",
+			zap.String("action", action),
+			zap.String("resource", resource),
+			zap.String("actor", record.Actor),
+			zap.Error(err))
+	}
+}
+
+// List consulta os registros de auditoria
+func (r *Recorder) List(ctx context.Context, filter repository.AuditFilter) ([]*model.AuditRecord, int64, error) {
+	return r.repo.ListAuditRecords(ctx, filter)
+}
+
+// marshalState serializa o estado do recurso; nil indica estado ausente
+func marshalState(state interface{}) json.RawMessage {
+	if state == nil {
+		return nil
+	}
+	data, err := json.Marshal(state)
+	if err != nil || bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	return data
+}
+
+// diff compara os campos de primeiro nível dos estados em JSON
+func diff(before, after json.RawMessage) map[string]model.AuditChange {
+	if before == nil || after == nil {
+		return nil
+	}
+	var beforeFields, afterFields map[string]json.RawMessage
+	if json.Unmarshal(before, &beforeFields) != nil || json.Unmarshal(after, &afterFields) != nil {
+		return nil
+	}
+
+	changes := make(map[string]model.AuditChange)
+	for field, value := range beforeFields {
+		if !bytes.Equal(value, afterFields[field]) {
+			changes[field] = model.AuditChange{Before: value, After: afterFields[field]}
+		}
+	}
+	for field, value := range afterFields {
+		if _, ok := beforeFields[field]; !ok {
+			changes[field] = model.AuditChange{After: value}
+		}
+	}
+	return changes
+}
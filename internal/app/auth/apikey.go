@@ -0,0 +1,259 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/app/audit"
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/internal/domain/repository"
+	"github.com/diillson/api-gateway-go/pkg/cache"
+	"github.com/diillson/api-gateway-go/pkg/config"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// APIKeyContextKey é a chave do contexto do Gin com a chave de API autenticada
+const APIKeyContextKey = "api_key"
+
+// apiKeyPrefix identifica as chaves emitidas pelo gateway
+const apiKeyPrefix = "agk_"
+
+var (
+	// ErrInvalidAPIKey indica uma chave inexistente, revogada ou expirada
+	ErrInvalidAPIKey = errors.New("chave de API inválida")
+	// ErrInvalidAPIKeyRequest indica dados inválidos para emitir uma chave
+	ErrInvalidAPIKeyRequest = errors.New("dados da chave de API inválidos")
+)
+
+// APIKeyRequest contém os dados para emitir uma chave de API
+type APIKeyRequest struct {
+	Name      string
+	Routes    []string
+	Tier      string
+	ExpiresAt *time.Time
+}
+
+// PepperSource fornece o pepper do hash das chaves de API e o valor anterior à
+// última rotação, como secrets.Secret
+type PepperSource interface {
+	Value() string
+	Previous() string
+}
+
+// APIKeyService emite, valida e revoga as chaves de API dos consumidores
+type APIKeyService struct {
+	repo     repository.APIKeyRepository
+	cache    cache.Cache
+	cacheTTL time.Duration
+	tiers    atomic.Pointer[map[string]config.APIKeyTierConfig] // trocadas nas recargas da configuração
+	pepper   PepperSource                                       // nil mantém o SHA-256 sem pepper
+	logger   *zap.Logger
+	auditor  *audit.Recorder
+}
+
+// NewAPIKeyService cria o serviço de chaves de API
+func NewAPIKeyService(repo repository.APIKeyRepository, c cache.Cache, cfg config.APIKeysConfig, logger *zap.Logger) *APIKeyService {
+	s := &APIKeyService{
+		repo:     repo,
+		cache:    c,
+		cacheTTL: cfg.CacheTTL,
+		logger:   logger,
+	}
+	s.SetTiers(cfg.Tiers)
+	return s
+}
+
+// SetTiers substitui as faixas de rate limit, inclusive das chaves já emitidas
+func (s *APIKeyService) SetTiers(configured []config.APIKeyTierConfig) {
+	tiers := make(map[string]config.APIKeyTierConfig, len(configured))
+	for _, tier := range configured {
+		tiers[tier.Name] = tier
+	}
+	s.tiers.Store(&tiers)
+}
+
+// SetAuditor configura o registro das revogações no log de auditoria
+func (s *APIKeyService) SetAuditor(auditor *audit.Recorder) {
+	s.auditor = auditor
+}
+
+// SetPepper configura o pepper do hash das chaves. Chaves gravadas sem pepper
+// ou com o pepper anterior continuam aceitas e são regravadas com o atual no
+// primeiro uso.
+func (s *APIKeyService) SetPepper(pepper PepperSource) {
+	s.pepper = pepper
+}
+
+// Tier retorna a faixa de rate limit pelo nome
+func (s *APIKeyService) Tier(name string) (config.APIKeyTierConfig, bool) {
+	tier, ok := (*s.tiers.Load())[name]
+	return tier, ok
+}
+
+// Create emite uma nova chave. O valor retornado não é armazenado e não pode
+// ser recuperado depois.
+func (s *APIKeyService) Create(ctx context.Context, req APIKeyRequest) (*model.APIKey, string, error) {
+	if strings.TrimSpace(req.Name) == "" {
+		return nil, "", fmt.Errorf("%w: nome é obrigatório", ErrInvalidAPIKeyRequest)
+	}
+	if req.Tier != "" {
+		if _, ok := s.Tier(req.Tier); !ok {
+			return nil, "", fmt.Errorf("%w: faixa de rate limit não configurada: %s", ErrInvalidAPIKeyRequest, req.Tier)
+		}
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, "", fmt.Errorf("%w: expiração deve estar no futuro", ErrInvalidAPIKeyRequest)
+	}
+
+	secret, err := randomToken()
+	if err != nil {
+		return nil, "", err
+	}
+	value := apiKeyPrefix + secret
+
+	key := &model.APIKey{
+		ID:        uuid.New().String(),
+		Name:      req.Name,
+		Prefix:    value[:len(apiKeyPrefix)+8],
+		Routes:    req.Routes,
+		Tier:      req.Tier,
+		ExpiresAt: req.ExpiresAt,
+	}
+	if err := s.repo.CreateAPIKey(ctx, key, s.hash(value)); err != nil {
+		return nil, "", err
+	}
+
+	s.logger.Info("Chave de API criada",
+		zap.String("id", key.ID),
+		zap.String("name", key.Name),
+		zap.String("tier", key.Tier))
+	return key, value, nil
+}
+
+// Authenticate valida a chave apresentada pelo consumidor. Chaves válidas ficam
+// em cache para evitar uma consulta ao banco a cada requisição.
+func (s *APIKeyService) Authenticate(ctx context.Context, value string) (*model.APIKey, error) {
+	if !strings.HasPrefix(value, apiKeyPrefix) {
+		return nil, ErrInvalidAPIKey
+	}
+	keyHash := s.hash(value)
+
+	var key model.APIKey
+	found, err := s.cache.Get(ctx, apiKeyCacheKey(keyHash), &key)
+	if err != nil {
+		s.logger.Warn("Falha ao consultar chave de API no cache", zap.Error(err))
+	}
+	if !found {
+		stored, current, err := s.lookup(ctx, value, keyHash)
+		if err != nil {
+			if errors.Is(err, repository.ErrAPIKeyNotFound) {
+				return nil, ErrInvalidAPIKey
+			}
+			return nil, err
+		}
+		key = *stored
+
+		// O último uso é registrado uma vez por período de cache
+		if err := s.repo.TouchAPIKey(ctx, key.ID, time.Now()); err != nil {
+			s.logger.Warn("Falha ao registrar uso da chave de API", zap.String("id", key.ID), zap.Error(err))
+		}
+		// A revogação remove do cache a entrada do hash armazenado, então a
+		// chave só entra no cache com o hash atual
+		if s.cacheTTL > 0 && current {
+			if err := s.cache.Set(ctx, apiKeyCacheKey(keyHash), key, s.cacheTTL); err != nil {
+				s.logger.Warn("Falha ao armazenar chave de API no cache", zap.Error(err))
+			}
+		}
+	}
+
+	if !key.IsValid(time.Now()) {
+		return nil, ErrInvalidAPIKey
+	}
+	return &key, nil
+}
+
+// List retorna todas as chaves emitidas
+func (s *APIKeyService) List(ctx context.Context) ([]*model.APIKey, error) {
+	return s.repo.ListAPIKeys(ctx)
+}
+
+// Revoke revoga a chave e a remove do cache, encerrando o acesso imediatamente
+func (s *APIKeyService) Revoke(ctx context.Context, id string) error {
+	keyHash, err := s.repo.RevokeAPIKey(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.cache.Delete(ctx, apiKeyCacheKey(keyHash)); err != nil {
+		s.logger.Warn("Falha ao remover chave de API do cache", zap.String("id", id), zap.Error(err))
+	}
+
+	s.auditor.Record(ctx, model.AuditAPIKeyRevoke, id, nil, nil)
+	s.logger.Info("Chave de API revogada", zap.String("id", id))
+	return nil
+}
+
+// lookup busca a chave pelo hash atual e, sem resultado, pelos hashes legados
+// (sem pepper ou com o pepper anterior), regravando-a com o hash atual. Indica
+// se o hash armazenado é o atual.
+func (s *APIKeyService) lookup(ctx context.Context, value, keyHash string) (*model.APIKey, bool, error) {
+	key, err := s.repo.GetAPIKeyByHash(ctx, keyHash)
+	if !errors.Is(err, repository.ErrAPIKeyNotFound) || s.pepper == nil {
+		return key, err == nil, err
+	}
+
+	legacy := []string{hashAPIKey("", value)}
+	if previous := s.pepper.Previous(); previous != "" {
+		legacy = append(legacy, hashAPIKey(previous, value))
+	}
+	for _, legacyHash := range legacy {
+		key, err := s.repo.GetAPIKeyByHash(ctx, legacyHash)
+		if errors.Is(err, repository.ErrAPIKeyNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		if err := s.repo.UpdateAPIKeyHash(ctx, key.ID, keyHash); err != nil {
+			s.logger.Warn("Falha ao regravar hash da chave de API", zap.String("id", key.ID), zap.Error(err))
+			return key, false, nil
+		}
+		s.logger.Info("Hash da chave de API regravado com o pepper atual", zap.String("id", key.ID))
+		return key, true, nil
+	}
+	return nil, false, repository.ErrAPIKeyNotFound
+}
+
+// hash calcula o hash da chave com o pepper atual
+func (s *APIKeyService) hash(value string) string {
+	if s.pepper == nil {
+		return hashAPIKey("", value)
+	}
+	return hashAPIKey(s.pepper.Value(), value)
+}
+
+// hashAPIKey calcula o hash armazenado da chave: HMAC-SHA256 com o pepper ou,
+// sem pepper, SHA-256. As chaves têm 256 bits aleatórios, então um hash rápido
+// é suficiente; o pepper, guardado fora do banco, impede que um vazamento da
+// tabela permita testar chaves.
+func hashAPIKey(pepper, value string) string {
+	if pepper == "" {
+		sum := sha256.Sum256([]byte(value))
+		return hex.EncodeToString(sum[:])
+	}
+	mac := hmac.New(sha256.New, []byte(pepper))
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// apiKeyCacheKey monta a chave de cache da chave de API
+func apiKeyCacheKey(keyHash string) string {
+	return "apikey:" + keyHash
+}
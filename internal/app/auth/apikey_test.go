@@ -0,0 +1,145 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/internal/domain/repository"
+	"github.com/diillson/api-gateway-go/pkg/cache"
+	"github.com/diillson/api-gateway-go/pkg/config"
+	"go.uber.org/zap"
+)
+
+// stubAPIKeys é um repositório de chaves de API em memória, indexado pelo hash
+type stubAPIKeys map[string]*model.APIKey
+
+func (r stubAPIKeys) CreateAPIKey(_ context.Context, key *model.APIKey, keyHash string) error {
+	r[keyHash] = key
+	return nil
+}
+
+func (r stubAPIKeys) GetAPIKeyByHash(_ context.Context, keyHash string) (*model.APIKey, error) {
+	if key, ok := r[keyHash]; ok {
+		copied := *key
+		return &copied, nil
+	}
+	return nil, repository.ErrAPIKeyNotFound
+}
+
+func (r stubAPIKeys) ListAPIKeys(context.Context) ([]*model.APIKey, error) {
+	return nil, nil
+}
+
+func (r stubAPIKeys) RevokeAPIKey(_ context.Context, id string) (string, error) {
+	for keyHash, key := range r {
+		if key.ID == id {
+			key.Revoked = true
+			return keyHash, nil
+		}
+	}
+	return "", repository.ErrAPIKeyNotFound
+}
+
+func (r stubAPIKeys) TouchAPIKey(context.Context, string, time.Time) error {
+	return nil
+}
+
+func (r stubAPIKeys) UpdateAPIKeyHash(_ context.Context, id, keyHash string) error {
+	for oldHash, key := range r {
+		if key.ID == id {
+			delete(r, oldHash)
+			r[keyHash] = key
+			return nil
+		}
+	}
+	return repository.ErrAPIKeyNotFound
+}
+
+// stubPepper é um pepper fixo, com o valor anterior à rotação
+type stubPepper struct{ current, previous string }
+
+func (p *stubPepper) Value() string    { return p.current }
+func (p *stubPepper) Previous() string { return p.previous }
+
+func newTestAPIKeys(repo stubAPIKeys) *APIKeyService {
+	c := cache.NewMemoryCache(time.Minute, time.Minute, nil, zap.NewNop())
+	return NewAPIKeyService(repo, c, config.APIKeysConfig{CacheTTL: time.Minute}, zap.NewNop())
+}
+
+func TestAPIKeyPepper(t *testing.T) {
+	ctx := context.Background()
+	repo := stubAPIKeys{}
+	s := newTestAPIKeys(repo)
+	s.SetPepper(&stubPepper{current: "pepper-1"})
+
+	key, value, err := s.Create(ctx, APIKeyRequest{Name: "billing-job"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, ok := repo[hashAPIKey("pepper-1", value)]; !ok {
+		t.Fatal("chave não foi gravada com o hash HMAC do pepper")
+	}
+	if _, ok := repo[hashAPIKey("", value)]; ok {
+		t.Fatal("chave gravada com o SHA-256 sem pepper")
+	}
+
+	got, err := s.Authenticate(ctx, value)
+	if err != nil || got.ID != key.ID {
+		t.Fatalf("Authenticate = %v, %v; esperada a chave %s", got, err, key.ID)
+	}
+	if _, err := s.Authenticate(ctx, value+"x"); err != ErrInvalidAPIKey {
+		t.Fatalf("Authenticate com chave alterada = %v, esperado ErrInvalidAPIKey", err)
+	}
+}
+
+func TestAPIKeyRehash(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous string
+		stored   string // pepper do hash gravado
+	}{
+		{"sem pepper", "", ""},
+		{"pepper anterior", "pepper-1", "pepper-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			value := apiKeyPrefix + "segredo-legado"
+			repo := stubAPIKeys{hashAPIKey(tt.stored, value): {ID: "k1", Name: "legado"}}
+			s := newTestAPIKeys(repo)
+			s.SetPepper(&stubPepper{current: "pepper-2", previous: tt.previous})
+
+			got, err := s.Authenticate(ctx, value)
+			if err != nil || got.ID != "k1" {
+				t.Fatalf("Authenticate = %v, %v; esperada a chave legada", got, err)
+			}
+			if _, ok := repo[hashAPIKey("pepper-2", value)]; !ok || len(repo) != 1 {
+				t.Fatal("chave legada não foi regravada com o pepper atual")
+			}
+
+			// A revogação remove a chave do cache pelo hash regravado
+			if err := s.Revoke(ctx, "k1"); err != nil {
+				t.Fatalf("Revoke: %v", err)
+			}
+			if _, err := s.Authenticate(ctx, value); err != ErrInvalidAPIKey {
+				t.Fatalf("Authenticate após revogação = %v, esperado ErrInvalidAPIKey", err)
+			}
+		})
+	}
+}
+
+func TestAPIKeyLegacyHashWithoutPepper(t *testing.T) {
+	ctx := context.Background()
+	value := apiKeyPrefix + "segredo"
+	repo := stubAPIKeys{hashAPIKey("", value): {ID: "k1", Name: "sem-pepper"}}
+	s := newTestAPIKeys(repo)
+
+	if got, err := s.Authenticate(ctx, value); err != nil || got.ID != "k1" {
+		t.Fatalf("Authenticate = %v, %v; esperada a chave sem pepper", got, err)
+	}
+	if _, ok := repo[hashAPIKey("", value)]; !ok {
+		t.Fatal("sem pepper, o hash armazenado não deve mudar")
+	}
+}
@@ -0,0 +1,343 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/app/audit"
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/internal/domain/repository"
+	"github.com/diillson/api-gateway-go/pkg/cache"
+	"github.com/diillson/api-gateway-go/pkg/config"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// hmacKeyPrefix identifica as chaves de assinatura emitidas pelo gateway
+const hmacKeyPrefix = "hk_"
+
+// maxCachedCredentials limita as credenciais mantidas em cache em cada instância
+const maxCachedCredentials = 10000
+
+var (
+	// ErrInvalidCredential indica credenciais inexistentes, incorretas, revogadas ou expiradas
+	ErrInvalidCredential = errors.New("credencial inválida")
+	// ErrInvalidSignature indica uma assinatura HMAC incorreta, vencida ou repetida
+	ErrInvalidSignature = errors.New("assinatura inválida")
+	// ErrInvalidCredentialRequest indica dados inválidos para emitir uma credencial
+	ErrInvalidCredentialRequest = errors.New("dados da credencial inválidos")
+)
+
+// credentialUsername restringe os usuários das credenciais basic (o ":" separa a senha)
+var credentialUsername = regexp.MustCompile(`^[A-Za-z0-9._@-]{3,100}$`)
+
+// CredentialRequest contém os dados para emitir uma credencial
+type CredentialRequest struct {
+	Name      string
+	Type      string
+	Username  string // Usuário das credenciais basic
+	Password  string // Senha das credenciais basic; vazia gera uma senha aleatória
+	Routes    []string
+	ExpiresAt *time.Time
+}
+
+// SignedRequest contém os dados de uma requisição assinada com HMAC
+type SignedRequest struct {
+	KeyID     string
+	Timestamp string // Segundos desde a época Unix
+	Nonce     string
+	Signature string // HMAC-SHA256 em hexadecimal
+	Method    string
+	URI       string // Caminho com a query string, como enviado pelo cliente
+	BodyHash  string // SHA-256 do corpo em hexadecimal
+}
+
+// StringToSign monta o texto assinado pelo parceiro:
+// MÉTODO\nURI\nTIMESTAMP\nNONCE\nSHA256(corpo)
+func (r *SignedRequest) StringToSign() string {
+	return strings.Join([]string{strings.ToUpper(r.Method), r.URI, r.Timestamp, r.Nonce, r.BodyHash}, "\n")
+}
+
+// cachedCredential é a credencial validada mantida em cache na instância
+type cachedCredential struct {
+	credential *model.Credential
+	secret     string   // Hash bcrypt (basic) ou segredo de assinatura (hmac)
+	verified   [32]byte // SHA-256 da última senha validada com o bcrypt
+	expires    time.Time
+}
+
+// CredentialService emite, valida e revoga as credenciais dos parceiros que
+// não usam JWT nem OAuth: usuário e senha (HTTP Basic) e chaves de assinatura
+// HMAC. As credenciais validadas ficam em cache em cada instância; os nonces
+// das assinaturas são registrados no cache compartilhado contra repetições.
+type CredentialService struct {
+	repo     repository.CredentialRepository
+	nonces   cache.Cache
+	settings atomic.Pointer[config.CredentialsConfig] // trocada nas recargas da configuração
+	logger   *zap.Logger
+	auditor  *audit.Recorder
+
+	mu     sync.Mutex
+	cached map[string]*cachedCredential
+}
+
+// NewCredentialService cria o serviço de credenciais
+func NewCredentialService(repo repository.CredentialRepository, nonces cache.Cache, cfg config.CredentialsConfig, logger *zap.Logger) *CredentialService {
+	s := &CredentialService{
+		repo:   repo,
+		nonces: nonces,
+		logger: logger,
+		cached: make(map[string]*cachedCredential),
+	}
+	s.SetConfig(cfg)
+	return s
+}
+
+// SetConfig substitui o realm, os prazos e o tamanho máximo do corpo. Ligar ou
+// desligar as credenciais (enabled) exige reinício.
+func (s *CredentialService) SetConfig(cfg config.CredentialsConfig) {
+	s.settings.Store(&cfg)
+}
+
+// SetAuditor configura o registro das revogações no log de auditoria
+func (s *CredentialService) SetAuditor(auditor *audit.Recorder) {
+	s.auditor = auditor
+}
+
+// Realm retorna o realm informado no WWW-Authenticate das rotas basic
+func (s *CredentialService) Realm() string {
+	return s.settings.Load().Realm
+}
+
+// MaxBodyBytes retorna o tamanho máximo do corpo das requisições assinadas
+func (s *CredentialService) MaxBodyBytes() int64 {
+	return s.settings.Load().MaxBodyKiB * 1024
+}
+
+// Create emite uma nova credencial e retorna o segredo (senha ou segredo de
+// assinatura), que não pode ser recuperado depois
+func (s *CredentialService) Create(ctx context.Context, req CredentialRequest) (*model.Credential, string, error) {
+	if strings.TrimSpace(req.Name) == "" {
+		return nil, "", fmt.Errorf("%w: nome é obrigatório", ErrInvalidCredentialRequest)
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, "", fmt.Errorf("%w: expiração deve estar no futuro", ErrInvalidCredentialRequest)
+	}
+
+	credential := &model.Credential{
+		ID:        uuid.New().String(),
+		Name:      req.Name,
+		Type:      req.Type,
+		Routes:    req.Routes,
+		ExpiresAt: req.ExpiresAt,
+	}
+
+	var secret, stored string
+	switch req.Type {
+	case model.CredentialBasic:
+		if !credentialUsername.MatchString(req.Username) || strings.HasPrefix(req.Username, hmacKeyPrefix) {
+			return nil, "", fmt.Errorf("%w: usuário inválido", ErrInvalidCredentialRequest)
+		}
+		credential.Identifier = req.Username
+		secret = req.Password
+		if secret == "" {
+			generated, err := randomToken()
+			if err != nil {
+				return nil, "", err
+			}
+			secret = generated
+		}
+		hash, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, "", fmt.Errorf("falha ao gerar hash da senha: %w", err)
+		}
+		stored = string(hash)
+	case model.CredentialHMAC:
+		if req.Username != "" || req.Password != "" {
+			return nil, "", fmt.Errorf("%w: credenciais hmac não aceitam usuário nem senha", ErrInvalidCredentialRequest)
+		}
+		keyID, err := randomToken()
+		if err != nil {
+			return nil, "", err
+		}
+		credential.Identifier = hmacKeyPrefix + keyID[:16]
+		if secret, err = randomToken(); err != nil {
+			return nil, "", err
+		}
+		stored = secret
+	default:
+		return nil, "", fmt.Errorf("%w: tipo inválido %q (use basic ou hmac)", ErrInvalidCredentialRequest, req.Type)
+	}
+
+	if err := s.repo.CreateCredential(ctx, credential, stored); err != nil {
+		if errors.Is(err, repository.ErrCredentialExists) {
+			return nil, "", fmt.Errorf("%w: usuário já cadastrado", ErrInvalidCredentialRequest)
+		}
+		return nil, "", err
+	}
+
+	s.logger.Info("Credencial criada",
+		zap.String("id", credential.ID),
+		zap.String("name", credential.Name),
+		zap.String("type", credential.Type),
+		zap.String("identifier", credential.Identifier))
+	return credential, secret, nil
+}
+
+// AuthenticateBasic valida o usuário e a senha do HTTP Basic. A senha é
+// comparada com o bcrypt apenas quando difere da última validada na instância.
+func (s *CredentialService) AuthenticateBasic(ctx context.Context, username, password string) (*model.Credential, error) {
+	entry, err := s.lookup(ctx, username, model.CredentialBasic)
+	if err != nil {
+		return nil, err
+	}
+
+	digest := sha256.Sum256([]byte(password))
+	s.mu.Lock()
+	verified := entry.verified
+	s.mu.Unlock()
+	if subtle.ConstantTimeCompare(digest[:], verified[:]) != 1 {
+		if bcrypt.CompareHashAndPassword([]byte(entry.secret), []byte(password)) != nil {
+			return nil, ErrInvalidCredential
+		}
+		s.mu.Lock()
+		entry.verified = digest
+		s.mu.Unlock()
+	}
+	return entry.credential, nil
+}
+
+// AuthenticateSignature valida a assinatura HMAC da requisição: o horário
+// dentro da tolerância, a assinatura com o segredo da chave e o nonce ainda
+// não usado. Os nonces são mantidos pelo dobro da tolerância.
+func (s *CredentialService) AuthenticateSignature(ctx context.Context, req *SignedRequest) (*model.Credential, error) {
+	if req.KeyID == "" || req.Timestamp == "" || req.Nonce == "" || req.Signature == "" {
+		return nil, fmt.Errorf("%w: cabeçalhos de assinatura ausentes", ErrInvalidSignature)
+	}
+	var seconds int64
+	if _, err := fmt.Sscan(req.Timestamp, &seconds); err != nil {
+		return nil, fmt.Errorf("%w: timestamp inválido", ErrInvalidSignature)
+	}
+	maxSkew := s.settings.Load().MaxSkew
+	if skew := time.Since(time.Unix(seconds, 0)); skew > maxSkew || skew < -maxSkew {
+		return nil, fmt.Errorf("%w: timestamp fora da tolerância", ErrInvalidSignature)
+	}
+	if len(req.Nonce) > 128 {
+		return nil, fmt.Errorf("%w: nonce muito longo", ErrInvalidSignature)
+	}
+
+	entry, err := s.lookup(ctx, req.KeyID, model.CredentialHMAC)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := hex.DecodeString(strings.TrimPrefix(strings.ToLower(req.Signature), "sha256="))
+	if err != nil {
+		return nil, fmt.Errorf("%w: assinatura não está em hexadecimal", ErrInvalidSignature)
+	}
+	mac := hmac.New(sha256.New, []byte(entry.secret))
+	mac.Write([]byte(req.StringToSign()))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return nil, fmt.Errorf("%w: assinatura não confere", ErrInvalidSignature)
+	}
+
+	// O nonce só é registrado após a assinatura conferir, para que requisições
+	// forjadas não esgotem os nonces do parceiro
+	fresh, err := s.useNonce(ctx, req.KeyID, req.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	if !fresh {
+		return nil, fmt.Errorf("%w: nonce já utilizado", ErrInvalidSignature)
+	}
+	return entry.credential, nil
+}
+
+// List retorna todas as credenciais emitidas, sem os segredos
+func (s *CredentialService) List(ctx context.Context) ([]*model.Credential, error) {
+	return s.repo.ListCredentials(ctx)
+}
+
+// Revoke revoga a credencial. A instância que atende a revogação a remove do
+// cache imediatamente; as demais, ao fim do tempo de cache.
+func (s *CredentialService) Revoke(ctx context.Context, id string) error {
+	identifier, err := s.repo.RevokeCredential(ctx, id)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	delete(s.cached, identifier)
+	s.mu.Unlock()
+
+	s.auditor.Record(ctx, model.AuditCredentialRevoke, id, nil, nil)
+	s.logger.Info("Credencial revogada", zap.String("id", id))
+	return nil
+}
+
+// lookup obtém a credencial válida do tipo informado, do cache da instância ou
+// do repositório
+func (s *CredentialService) lookup(ctx context.Context, identifier, credentialType string) (*cachedCredential, error) {
+	now := time.Now()
+	s.mu.Lock()
+	entry, ok := s.cached[identifier]
+	s.mu.Unlock()
+
+	if !ok || !now.Before(entry.expires) {
+		credential, secret, err := s.repo.GetCredentialByIdentifier(ctx, identifier)
+		if err != nil {
+			if errors.Is(err, repository.ErrCredentialNotFound) {
+				return nil, ErrInvalidCredential
+			}
+			return nil, err
+		}
+
+		// O último uso é registrado uma vez por período de cache
+		if err := s.repo.TouchCredential(ctx, credential.ID, now); err != nil {
+			s.logger.Warn("Falha ao registrar uso da credencial", zap.String("id", credential.ID), zap.Error(err))
+		}
+
+		cacheTTL := s.settings.Load().CacheTTL
+		entry = &cachedCredential{credential: credential, secret: secret, expires: now.Add(cacheTTL)}
+		if cacheTTL > 0 {
+			s.mu.Lock()
+			if len(s.cached) >= maxCachedCredentials {
+				s.cached = make(map[string]*cachedCredential)
+			}
+			s.cached[identifier] = entry
+			s.mu.Unlock()
+		}
+	}
+
+	if entry.credential.Type != credentialType || !entry.credential.IsValid(now) {
+		return nil, ErrInvalidCredential
+	}
+	return entry, nil
+}
+
+// useNonce registra o nonce da chave e indica se ainda não havia sido usado.
+// O registro exige um cache com inserção atômica, para que duas requisições
+// com o mesmo nonce não sejam aceitas ao mesmo tempo.
+func (s *CredentialService) useNonce(ctx context.Context, keyID, nonce string) (bool, error) {
+	adder, ok := s.nonces.(cache.Adder)
+	if !ok {
+		return false, errors.New("cache sem inserção atômica: nonces das assinaturas hmac não podem ser registrados")
+	}
+
+	key := "hmac-nonce:" + keyID + ":" + nonce
+	added, err := adder.Add(ctx, key, true, 2*s.settings.Load().MaxSkew)
+	if err != nil {
+		return false, fmt.Errorf("falha ao registrar nonce da assinatura: %w", err)
+	}
+	return added, nil
+}
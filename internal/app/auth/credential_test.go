@@ -0,0 +1,224 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/internal/domain/repository"
+	"github.com/diillson/api-gateway-go/pkg/cache"
+	"github.com/diillson/api-gateway-go/pkg/config"
+	"go.uber.org/zap"
+)
+
+// storedCredential é a credencial gravada com o seu segredo
+type storedCredential struct {
+	credential *model.Credential
+	secret     string
+}
+
+// stubCredentials é um repositório de credenciais em memória, indexado pelo
+// identificador
+type stubCredentials map[string]storedCredential
+
+func (r stubCredentials) CreateCredential(_ context.Context, credential *model.Credential, secret string) error {
+	if _, ok := r[credential.Identifier]; ok {
+		return repository.ErrCredentialExists
+	}
+	r[credential.Identifier] = storedCredential{credential, secret}
+	return nil
+}
+
+func (r stubCredentials) GetCredentialByIdentifier(_ context.Context, identifier string) (*model.Credential, string, error) {
+	entry, ok := r[identifier]
+	if !ok {
+		return nil, "", repository.ErrCredentialNotFound
+	}
+	copied := *entry.credential
+	return &copied, entry.secret, nil
+}
+
+func (r stubCredentials) ListCredentials(context.Context) ([]*model.Credential, error) {
+	return nil, nil
+}
+
+func (r stubCredentials) RevokeCredential(_ context.Context, id string) (string, error) {
+	for identifier, entry := range r {
+		if entry.credential.ID == id {
+			entry.credential.Revoked = true
+			return identifier, nil
+		}
+	}
+	return "", repository.ErrCredentialNotFound
+}
+
+func (r stubCredentials) TouchCredential(context.Context, string, time.Time) error {
+	return nil
+}
+
+// noAdd esconde a inserção atômica do cache
+type noAdd struct{ cache.Cache }
+
+func newTestCredentials(nonces cache.Cache) *CredentialService {
+	if nonces == nil {
+		nonces = cache.NewMemoryCache(time.Minute, time.Minute, nil, zap.NewNop())
+	}
+	return NewCredentialService(stubCredentials{}, nonces, config.CredentialsConfig{
+		CacheTTL:   time.Minute,
+		MaxSkew:    5 * time.Minute,
+		MaxBodyKiB: 1,
+	}, zap.NewNop())
+}
+
+// signRequest assina a requisição com o segredo, como um parceiro faria
+func signRequest(secret string, req *SignedRequest) *SignedRequest {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(req.StringToSign()))
+	req.Signature = hex.EncodeToString(mac.Sum(nil))
+	return req
+}
+
+func newSignedRequest(keyID string, at time.Time, nonce string) *SignedRequest {
+	return &SignedRequest{
+		KeyID:     keyID,
+		Timestamp: strconv.FormatInt(at.Unix(), 10),
+		Nonce:     nonce,
+		Method:    "POST",
+		URI:       "/api/pedidos?origem=parceiro",
+		BodyHash:  "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+	}
+}
+
+func TestAuthenticateSignatureSkew(t *testing.T) {
+	ctx := context.Background()
+	s := newTestCredentials(nil)
+	credential, secret, err := s.Create(ctx, CredentialRequest{Name: "parceiro", Type: model.CredentialHMAC})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	now := time.Now()
+	tests := []struct {
+		name string
+		at   time.Time
+		ok   bool
+	}{
+		{"agora", now, true},
+		{"dentro da tolerância no passado", now.Add(-4 * time.Minute), true},
+		{"dentro da tolerância no futuro", now.Add(4 * time.Minute), true},
+		{"antes da tolerância", now.Add(-6 * time.Minute), false},
+		{"depois da tolerância", now.Add(6 * time.Minute), false},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := signRequest(secret, newSignedRequest(credential.Identifier, tt.at, "nonce-"+strconv.Itoa(i)))
+			_, err := s.AuthenticateSignature(ctx, req)
+			if (err == nil) != tt.ok {
+				t.Fatalf("AuthenticateSignature = %v, esperado aceito: %v", err, tt.ok)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidSignature) {
+				t.Fatalf("erro = %v, esperado ErrInvalidSignature", err)
+			}
+		})
+	}
+
+	req := signRequest(secret, newSignedRequest(credential.Identifier, now, "nonce-texto"))
+	req.Timestamp = "ontem"
+	if _, err := s.AuthenticateSignature(ctx, req); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("timestamp inválido = %v, esperado ErrInvalidSignature", err)
+	}
+}
+
+func TestAuthenticateSignatureNonceReplay(t *testing.T) {
+	ctx := context.Background()
+	s := newTestCredentials(nil)
+	credential, secret, err := s.Create(ctx, CredentialRequest{Name: "parceiro", Type: model.CredentialHMAC})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	now := time.Now()
+
+	// Uma assinatura forjada não consome o nonce do parceiro
+	forged := signRequest("segredo-errado", newSignedRequest(credential.Identifier, now, "nonce-1"))
+	if _, err := s.AuthenticateSignature(ctx, forged); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("assinatura forjada = %v, esperado ErrInvalidSignature", err)
+	}
+
+	req := signRequest(secret, newSignedRequest(credential.Identifier, now, "nonce-1"))
+	got, err := s.AuthenticateSignature(ctx, req)
+	if err != nil || got.ID != credential.ID {
+		t.Fatalf("AuthenticateSignature = %v, %v; esperada a credencial", got, err)
+	}
+	if _, err := s.AuthenticateSignature(ctx, req); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("repetição = %v, esperado ErrInvalidSignature", err)
+	}
+
+	next := signRequest(secret, newSignedRequest(credential.Identifier, now, "nonce-2"))
+	if _, err := s.AuthenticateSignature(ctx, next); err != nil {
+		t.Fatalf("novo nonce = %v, esperado aceito", err)
+	}
+
+	// A assinatura cobre o corpo: alterá-lo invalida a requisição
+	tampered := signRequest(secret, newSignedRequest(credential.Identifier, now, "nonce-3"))
+	tampered.BodyHash = hex.EncodeToString(make([]byte, sha256.Size))
+	if _, err := s.AuthenticateSignature(ctx, tampered); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("corpo alterado = %v, esperado ErrInvalidSignature", err)
+	}
+}
+
+func TestAuthenticateSignatureRequiresAtomicCache(t *testing.T) {
+	ctx := context.Background()
+	s := newTestCredentials(noAdd{cache.NewMemoryCache(time.Minute, time.Minute, nil, zap.NewNop())})
+	credential, secret, err := s.Create(ctx, CredentialRequest{Name: "parceiro", Type: model.CredentialHMAC})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	req := signRequest(secret, newSignedRequest(credential.Identifier, time.Now(), "nonce-1"))
+	_, err = s.AuthenticateSignature(ctx, req)
+	if err == nil || errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("AuthenticateSignature = %v, esperada falha do cache sem inserção atômica", err)
+	}
+}
+
+func TestAuthenticateBasic(t *testing.T) {
+	ctx := context.Background()
+	s := newTestCredentials(nil)
+	credential, _, err := s.Create(ctx, CredentialRequest{
+		Name: "parceiro", Type: model.CredentialBasic, Username: "parceiro.a", Password: "senha-forte",
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	hmacCredential, _, err := s.Create(ctx, CredentialRequest{Name: "assinante", Type: model.CredentialHMAC})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	if got, err := s.AuthenticateBasic(ctx, "parceiro.a", "senha-forte"); err != nil || got.ID != credential.ID {
+		t.Fatalf("AuthenticateBasic = %v, %v; esperada a credencial", got, err)
+	}
+	// A senha validada fica em cache; uma senha diferente ainda é recusada
+	if _, err := s.AuthenticateBasic(ctx, "parceiro.a", "senha-errada"); !errors.Is(err, ErrInvalidCredential) {
+		t.Fatalf("senha errada = %v, esperado ErrInvalidCredential", err)
+	}
+	if _, err := s.AuthenticateBasic(ctx, "desconhecido", "senha-forte"); !errors.Is(err, ErrInvalidCredential) {
+		t.Fatalf("usuário desconhecido = %v, esperado ErrInvalidCredential", err)
+	}
+	if _, err := s.AuthenticateBasic(ctx, hmacCredential.Identifier, ""); !errors.Is(err, ErrInvalidCredential) {
+		t.Fatalf("chave hmac usada como basic = %v, esperado ErrInvalidCredential", err)
+	}
+
+	if err := s.Revoke(ctx, credential.ID); err != nil {
+		t.Fatalf("Revoke: %v", err)
+	}
+	if _, err := s.AuthenticateBasic(ctx, "parceiro.a", "senha-forte"); !errors.Is(err, ErrInvalidCredential) {
+		t.Fatalf("credencial revogada = %v, esperado ErrInvalidCredential", err)
+	}
+}
@@ -0,0 +1,348 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/pkg/cache"
+	"github.com/diillson/api-gateway-go/pkg/config"
+	"github.com/diillson/api-gateway-go/pkg/encryption"
+	"github.com/diillson/api-gateway-go/pkg/security"
+	"go.uber.org/zap"
+)
+
+// oidcLoginTTL é o tempo para o usuário concluir o login no provedor
+const oidcLoginTTL = 10 * time.Minute
+
+// OIDCStateCookie vincula o retorno do provedor ao navegador que iniciou o login
+const OIDCStateCookie = "ag_oidc_state"
+
+var (
+	// ErrNoCredentials indica que a requisição não trouxe token nem sessão
+	ErrNoCredentials = errors.New("credenciais ausentes")
+	// ErrUnknownProvider indica que a rota referencia um provedor OIDC não configurado
+	ErrUnknownProvider = errors.New("provedor OIDC não configurado")
+	// ErrInvalidLogin indica um retorno de login sem estado válido
+	ErrInvalidLogin = errors.New("estado de login inválido ou expirado")
+	// ErrInvalidCSRF indica um método inseguro da sessão sem o token CSRF correto
+	ErrInvalidCSRF = errors.New("token CSRF ausente ou inválido")
+)
+
+// Identity é o consumidor autenticado pela política da rota
+type Identity struct {
+	User   *model.User
+	Claims map[string]interface{}
+	// AccessToken obtido no login do navegador, repassado ao backend
+	AccessToken string
+}
+
+// oidcSession é a sessão criada pelo login do navegador, guardada no cache
+type oidcSession struct {
+	Provider    string
+	Claims      map[string]interface{}
+	AccessToken string
+	CSRFToken   string // Vazio sem a camada de sessão
+	ExpiresAt   time.Time
+}
+
+// LoginResult é a sessão criada ao concluir o login do navegador
+type LoginResult struct {
+	Cookie    string // Valor do cookie de sessão, cifrado com a camada de sessão
+	CSRFToken string // Token exigido nos métodos inseguros; vazio sem a camada de sessão
+	TTL       time.Duration
+	ReturnTo  string
+}
+
+// oidcLogin guarda os dados de um login em andamento
+type oidcLogin struct {
+	Provider string
+	Nonce    string
+	ReturnTo string
+}
+
+// OIDCService autentica os consumidores das rotas protegidas por provedores
+// OpenID Connect, por access token ou pela sessão do login no navegador
+type OIDCService struct {
+	providers  map[string]*security.OIDCProvider
+	cache      cache.Cache
+	sessionTTL time.Duration
+	session    config.OIDCSessionConfig
+	cookies    *encryption.Envelope
+	logger     *zap.Logger
+}
+
+// NewOIDCService cria o serviço com os provedores configurados
+func NewOIDCService(cfg config.OIDCConfig, c cache.Cache, logger *zap.Logger) *OIDCService {
+	providers := make(map[string]*security.OIDCProvider, len(cfg.Providers))
+	for _, providerCfg := range cfg.Providers {
+		providers[providerCfg.Name] = security.NewOIDCProvider(providerCfg, logger)
+	}
+	return &OIDCService{
+		providers:  providers,
+		cache:      c,
+		sessionTTL: cfg.SessionTTL,
+		session:    cfg.Session,
+		logger:     logger,
+	}
+}
+
+// SetCookieEncryptor configura o envelope que cifra o cookie de sessão quando
+// a camada de sessão está habilitada
+func (s *OIDCService) SetCookieEncryptor(envelope *encryption.Envelope) {
+	s.cookies = envelope
+}
+
+// SessionConfig retorna a configuração da camada de sessão dos navegadores
+func (s *OIDCService) SessionConfig() config.OIDCSessionConfig {
+	return s.session
+}
+
+// SessionCookie retorna o nome do cookie de sessão do provedor
+func SessionCookie(provider string) string {
+	return "ag_oidc_" + provider
+}
+
+// Authenticate identifica o consumidor pelo access token Bearer ou, na falta
+// dele, pela sessão criada no login do navegador
+func (s *OIDCService) Authenticate(ctx context.Context, providerName string, r *http.Request) (*Identity, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+
+	if token := bearerToken(r); token != "" {
+		claims, err := provider.VerifyAccessToken(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+		return identityFromClaims(claims, ""), nil
+	}
+
+	_, session, err := s.requestSession(ctx, providerName, r)
+	if err != nil {
+		return nil, err
+	}
+	return identityFromClaims(session.Claims, session.AccessToken), nil
+}
+
+// Logout encerra a sessão do navegador. Com a camada de sessão, o logout
+// também exige o token CSRF.
+func (s *OIDCService) Logout(ctx context.Context, providerName string, r *http.Request) error {
+	if _, ok := s.providers[providerName]; !ok {
+		return ErrUnknownProvider
+	}
+	sessionID, _, err := s.requestSession(ctx, providerName, r)
+	if err != nil {
+		return err
+	}
+	if err := s.cache.Delete(ctx, sessionKey(sessionID)); err != nil {
+		return fmt.Errorf("falha ao remover sessão OIDC: %w", err)
+	}
+	return nil
+}
+
+// requestSession obtém a sessão do cookie da requisição e, nos métodos
+// inseguros das sessões com token CSRF, confere o cabeçalho do token
+func (s *OIDCService) requestSession(ctx context.Context, providerName string, r *http.Request) (string, *oidcSession, error) {
+	cookie, err := r.Cookie(SessionCookie(providerName))
+	if err != nil || cookie.Value == "" {
+		return "", nil, ErrNoCredentials
+	}
+	sessionID := cookie.Value
+	if s.session.Enabled {
+		// Cookies em texto puro, anteriores à camada de sessão, não são aceitos
+		if s.cookies == nil || !encryption.IsEncrypted(sessionID) {
+			return "", nil, ErrNoCredentials
+		}
+		if sessionID, err = s.cookies.DecryptString(sessionID); err != nil {
+			return "", nil, ErrNoCredentials
+		}
+	}
+
+	var session oidcSession
+	found, err := s.cache.Get(ctx, sessionKey(sessionID), &session)
+	if err != nil {
+		return "", nil, fmt.Errorf("falha ao consultar sessão OIDC: %w", err)
+	}
+	if !found || session.Provider != providerName || time.Now().After(session.ExpiresAt) {
+		return "", nil, ErrNoCredentials
+	}
+
+	if session.CSRFToken != "" && !isSafeMethod(r.Method) {
+		token := r.Header.Get(s.session.CSRFHeader)
+		if token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(session.CSRFToken)) != 1 {
+			return "", nil, ErrInvalidCSRF
+		}
+	}
+	return sessionID, &session, nil
+}
+
+// CanLogin indica se o provedor permite o login de navegadores
+func (s *OIDCService) CanLogin(providerName string) bool {
+	provider, ok := s.providers[providerName]
+	return ok && provider.SupportsLogin()
+}
+
+// StartLogin inicia o login por authorization code e retorna a URL do provedor
+// e o estado que deve ser guardado no cookie OIDCStateCookie
+func (s *OIDCService) StartLogin(ctx context.Context, providerName, returnTo string) (string, string, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return "", "", ErrUnknownProvider
+	}
+
+	state, err := randomToken()
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := randomToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	loginURL, err := provider.AuthCodeURL(ctx, state, nonce)
+	if err != nil {
+		return "", "", err
+	}
+	login := oidcLogin{Provider: providerName, Nonce: nonce, ReturnTo: returnTo}
+	if err := s.cache.Set(ctx, loginKey(state), login, oidcLoginTTL); err != nil {
+		return "", "", fmt.Errorf("falha ao registrar login OIDC: %w", err)
+	}
+	return loginURL, state, nil
+}
+
+// CompleteLogin troca o código pelos tokens, valida o ID token e cria a sessão.
+// Com a camada de sessão, o cookie é cifrado e a sessão recebe um token CSRF.
+func (s *OIDCService) CompleteLogin(ctx context.Context, providerName, state, code string) (*LoginResult, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+
+	var login oidcLogin
+	found, err := s.cache.Get(ctx, loginKey(state), &login)
+	if err != nil || !found || login.Provider != providerName {
+		return nil, ErrInvalidLogin
+	}
+	// O estado vale para um único retorno
+	if err := s.cache.Delete(ctx, loginKey(state)); err != nil {
+		s.logger.Warn("Falha ao remover estado de login OIDC", zap.Error(err))
+	}
+
+	tokens, err := provider.Exchange(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := provider.VerifyIDToken(ctx, tokens.IDToken, login.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := s.sessionTTL
+	if expiresIn := time.Duration(tokens.ExpiresIn) * time.Second; expiresIn > 0 && expiresIn < ttl {
+		ttl = expiresIn
+	}
+
+	sessionID, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	session := oidcSession{
+		Provider:    providerName,
+		Claims:      claims,
+		AccessToken: tokens.AccessToken,
+		ExpiresAt:   time.Now().Add(ttl),
+	}
+	result := &LoginResult{Cookie: sessionID, TTL: ttl, ReturnTo: login.ReturnTo}
+	if s.session.Enabled {
+		if s.cookies == nil {
+			return nil, errors.New("camada de sessão habilitada sem chave de criptografia")
+		}
+		if result.Cookie, err = s.cookies.EncryptString(sessionID); err != nil {
+			return nil, fmt.Errorf("falha ao cifrar cookie de sessão: %w", err)
+		}
+		if session.CSRFToken, err = randomToken(); err != nil {
+			return nil, err
+		}
+		result.CSRFToken = session.CSRFToken
+	}
+	if err := s.cache.Set(ctx, sessionKey(sessionID), session, ttl); err != nil {
+		return nil, fmt.Errorf("falha ao criar sessão OIDC: %w", err)
+	}
+
+	s.logger.Info("Login OIDC concluído",
+		zap.String("provider", providerName),
+		zap.Any("subject", claims["sub"]))
+	return result, nil
+}
+
+// identityFromClaims monta o consumidor a partir das claims do provedor
+func identityFromClaims(claims map[string]interface{}, accessToken string) *Identity {
+	subject, _ := claims["sub"].(string)
+	username, _ := claims["preferred_username"].(string)
+	if username == "" {
+		username = subject
+	}
+	email, _ := claims["email"].(string)
+	role, _ := claims["role"].(string)
+	if role == "" {
+		role = "user"
+	}
+
+	return &Identity{
+		User: &model.User{
+			ID:       subject,
+			Username: username,
+			Role:     role,
+			Email:    email,
+		},
+		Claims:      claims,
+		AccessToken: accessToken,
+	}
+}
+
+// bearerToken extrai o token do cabeçalho Authorization
+func bearerToken(r *http.Request) string {
+	header := r.Header.Get("Authorization")
+	if len(header) > 7 && strings.EqualFold(header[:7], "Bearer ") {
+		return strings.TrimSpace(header[7:])
+	}
+	return ""
+}
+
+// isSafeMethod indica os métodos que não alteram estado e dispensam o token CSRF
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+// randomToken gera um identificador aleatório para estados e sessões
+func randomToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, buf); err != nil {
+		return "", fmt.Errorf("falha ao gerar identificador aleatório: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// sessionKey monta a chave de cache da sessão
+func sessionKey(id string) string {
+	return "oidc-session:" + id
+}
+
+// loginKey monta a chave de cache do login em andamento
+func loginKey(state string) string {
+	return "oidc-login:" + state
+}
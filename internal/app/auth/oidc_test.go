@@ -0,0 +1,221 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diillson/api-gateway-go/pkg/cache"
+	"github.com/diillson/api-gateway-go/pkg/config"
+	"github.com/diillson/api-gateway-go/pkg/encryption"
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+)
+
+// fakeIdP é um provedor OIDC mínimo: descoberta, JWKS e endpoint de token que
+// devolve um ID token com o nonce do último login iniciado
+type fakeIdP struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	nonce  string
+}
+
+func newFakeIdP(t *testing.T) *fakeIdP {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	idp := &fakeIdP{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 idp.server.URL,
+			"authorization_endpoint": idp.server.URL + "/authorize",
+			"token_endpoint":         idp.server.URL + "/token",
+			"jwks_uri":               idp.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "k1",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"iss":   idp.server.URL,
+			"aud":   "gateway",
+			"sub":   "usuario-1",
+			"nonce": idp.nonce,
+			"exp":   time.Now().Add(time.Hour).Unix(),
+		})
+		token.Header["kid"] = "k1"
+		signed, err := token.SignedString(key)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"id_token": signed, "access_token": "token-de-acesso"})
+	})
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+	return idp
+}
+
+// newTestOIDC cria o serviço com o provedor "corp" e, se habilitada, a camada
+// de sessão com cookies cifrados
+func newTestOIDC(t *testing.T, idp *fakeIdP, session bool) *OIDCService {
+	t.Helper()
+	s := NewOIDCService(config.OIDCConfig{
+		Providers: []config.OIDCProviderConfig{{
+			Name:        "corp",
+			Issuer:      idp.server.URL,
+			ClientID:    "gateway",
+			RedirectURL: "https://gateway.exemplo.com/auth/oidc/corp/callback",
+		}},
+		SessionTTL: time.Hour,
+		Session:    config.OIDCSessionConfig{Enabled: session, CSRFHeader: "X-CSRF-Token", CSRFCookie: "ag_csrf"},
+	}, cache.NewMemoryCache(time.Minute, time.Minute, nil, zap.NewNop()), zap.NewNop())
+
+	key := base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", 32)))
+	keyring, err := encryption.NewKeyring("k1", map[string]string{"k1": key})
+	if err != nil {
+		t.Fatalf("NewKeyring: %v", err)
+	}
+	envelope, err := encryption.NewEnvelope(keyring)
+	if err != nil {
+		t.Fatalf("NewEnvelope: %v", err)
+	}
+	s.SetCookieEncryptor(envelope)
+	return s
+}
+
+// login conclui um login no provedor falso
+func login(t *testing.T, s *OIDCService, idp *fakeIdP) *LoginResult {
+	t.Helper()
+	ctx := context.Background()
+	loginURL, state, err := s.StartLogin(ctx, "corp", "/app")
+	if err != nil {
+		t.Fatalf("StartLogin: %v", err)
+	}
+	parsed, err := url.Parse(loginURL)
+	if err != nil {
+		t.Fatalf("URL de login inválida: %v", err)
+	}
+	idp.nonce = parsed.Query().Get("nonce")
+
+	result, err := s.CompleteLogin(ctx, "corp", state, "codigo")
+	if err != nil {
+		t.Fatalf("CompleteLogin: %v", err)
+	}
+	return result
+}
+
+func sessionRequest(method, cookie, csrfToken string) *http.Request {
+	req := httptest.NewRequest(method, "/app/dados", nil)
+	if cookie != "" {
+		req.AddCookie(&http.Cookie{Name: SessionCookie("corp"), Value: cookie})
+	}
+	if csrfToken != "" {
+		req.Header.Set("X-CSRF-Token", csrfToken)
+	}
+	return req
+}
+
+func TestOIDCSessionCSRF(t *testing.T) {
+	ctx := context.Background()
+	idp := newFakeIdP(t)
+	s := newTestOIDC(t, idp, true)
+	result := login(t, s, idp)
+
+	if !encryption.IsEncrypted(result.Cookie) || result.CSRFToken == "" {
+		t.Fatal("com a camada de sessão, o cookie deve ser cifrado e a sessão ter token CSRF")
+	}
+
+	tests := []struct {
+		name    string
+		req     *http.Request
+		wantErr error
+	}{
+		{"GET sem token CSRF", sessionRequest(http.MethodGet, result.Cookie, ""), nil},
+		{"POST com token CSRF", sessionRequest(http.MethodPost, result.Cookie, result.CSRFToken), nil},
+		{"POST sem token CSRF", sessionRequest(http.MethodPost, result.Cookie, ""), ErrInvalidCSRF},
+		{"DELETE com token errado", sessionRequest(http.MethodDelete, result.Cookie, "token-errado"), ErrInvalidCSRF},
+		{"sem cookie", sessionRequest(http.MethodGet, "", ""), ErrNoCredentials},
+		{"cookie adulterado", sessionRequest(http.MethodGet, result.Cookie[:len(result.Cookie)-4]+"AAAA", ""), ErrNoCredentials},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			identity, err := s.Authenticate(ctx, "corp", tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Authenticate = %v, esperado %v", err, tt.wantErr)
+			}
+			if err == nil && (identity.User.ID != "usuario-1" || identity.AccessToken != "token-de-acesso") {
+				t.Fatalf("identidade = %+v, esperada a do login", identity)
+			}
+		})
+	}
+}
+
+func TestOIDCSessionRejectsPlainCookie(t *testing.T) {
+	idp := newFakeIdP(t)
+	s := newTestOIDC(t, idp, true)
+	result := login(t, s, idp)
+
+	sessionID, err := s.cookies.DecryptString(result.Cookie)
+	if err != nil {
+		t.Fatalf("DecryptString: %v", err)
+	}
+	// O identificador da sessão em texto puro não autentica
+	if _, err := s.Authenticate(context.Background(), "corp", sessionRequest(http.MethodGet, sessionID, "")); !errors.Is(err, ErrNoCredentials) {
+		t.Fatalf("Authenticate com cookie em texto puro = %v, esperado ErrNoCredentials", err)
+	}
+}
+
+func TestOIDCLogoutRequiresCSRF(t *testing.T) {
+	ctx := context.Background()
+	idp := newFakeIdP(t)
+	s := newTestOIDC(t, idp, true)
+	result := login(t, s, idp)
+
+	if err := s.Logout(ctx, "corp", sessionRequest(http.MethodPost, result.Cookie, "")); !errors.Is(err, ErrInvalidCSRF) {
+		t.Fatalf("Logout sem token CSRF = %v, esperado ErrInvalidCSRF", err)
+	}
+	if err := s.Logout(ctx, "corp", sessionRequest(http.MethodPost, result.Cookie, result.CSRFToken)); err != nil {
+		t.Fatalf("Logout: %v", err)
+	}
+	if _, err := s.Authenticate(ctx, "corp", sessionRequest(http.MethodGet, result.Cookie, "")); !errors.Is(err, ErrNoCredentials) {
+		t.Fatalf("Authenticate após logout = %v, esperado ErrNoCredentials", err)
+	}
+	if err := s.Logout(ctx, "outro", sessionRequest(http.MethodPost, result.Cookie, result.CSRFToken)); !errors.Is(err, ErrUnknownProvider) {
+		t.Fatalf("Logout de provedor desconhecido = %v, esperado ErrUnknownProvider", err)
+	}
+}
+
+func TestOIDCSessionWithoutSessionLayer(t *testing.T) {
+	idp := newFakeIdP(t)
+	s := newTestOIDC(t, idp, false)
+	result := login(t, s, idp)
+
+	if encryption.IsEncrypted(result.Cookie) || result.CSRFToken != "" {
+		t.Fatal("sem a camada de sessão, o cookie não é cifrado nem há token CSRF")
+	}
+	if _, err := s.Authenticate(context.Background(), "corp", sessionRequest(http.MethodPost, result.Cookie, "")); err != nil {
+		t.Fatalf("Authenticate sem a camada de sessão = %v", err)
+	}
+}
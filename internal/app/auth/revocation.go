@@ -0,0 +1,120 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/app/audit"
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/pkg/cache"
+	"github.com/diillson/api-gateway-go/pkg/security"
+	"go.uber.org/zap"
+)
+
+// ErrTokenRevoked indica que o token foi revogado antes de expirar
+var ErrTokenRevoked = errors.New("token revogado")
+
+const (
+	revokedTokenPrefix   = "jwt:revoked:jti:"
+	revokedSubjectPrefix = "jwt:revoked:sub:"
+)
+
+// TokenDenylist mantém no cache os tokens revogados, por jti ou por subject.
+// Cada entrada expira junto com os tokens que ela invalida.
+type TokenDenylist struct {
+	cache       cache.Cache
+	maxLifetime time.Duration
+	logger      *zap.Logger
+	auditor     *audit.Recorder
+}
+
+// NewTokenDenylist cria a lista de revogação. maxLifetime é a maior validade
+// aceita nos tokens externos; as revogações sem expiração conhecida duram o
+// maior valor entre ele e a validade dos tokens emitidos pelo gateway.
+func NewTokenDenylist(c cache.Cache, maxLifetime time.Duration, logger *zap.Logger) *TokenDenylist {
+	if maxLifetime < security.TokenLifetime {
+		maxLifetime = security.TokenLifetime
+	}
+	return &TokenDenylist{
+		cache:       c,
+		maxLifetime: maxLifetime,
+		logger:      logger,
+	}
+}
+
+// SetAuditor configura o registro das revogações no log de auditoria
+func (d *TokenDenylist) SetAuditor(auditor *audit.Recorder) {
+	d.auditor = auditor
+}
+
+// RevokeToken revoga um token pelo jti até a sua expiração. Sem expiração
+// conhecida, a revogação dura a validade máxima dos tokens.
+func (d *TokenDenylist) RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	if jti == "" {
+		return errors.New("jti vazio")
+	}
+
+	ttl := d.maxLifetime
+	if !expiresAt.IsZero() {
+		ttl = time.Until(expiresAt)
+	}
+	if ttl <= 0 {
+		// O token já expirou e não é mais aceito
+		return nil
+	}
+
+	if err := d.cache.Set(ctx, revokedTokenPrefix+jti, true, ttl); err != nil {
+		return fmt.Errorf("falha ao revogar token: %w", err)
+	}
+	d.auditor.Record(ctx, model.AuditTokenRevoke, "jti:"+jti, nil, map[string]interface{}{"expiresAt": expiresAt})
+	d.logger.Info("Token revogado", zap.String("jti", jti), zap.Duration("ttl", ttl))
+	return nil
+}
+
+// RevokeSubject revoga todos os tokens do subject emitidos até agora. Tokens
+// emitidos depois da revogação continuam válidos.
+func (d *TokenDenylist) RevokeSubject(ctx context.Context, subject string) (time.Time, error) {
+	if subject == "" {
+		return time.Time{}, errors.New("subject vazio")
+	}
+
+	revokedAt := time.Now()
+	if err := d.cache.Set(ctx, revokedSubjectPrefix+subject, revokedAt.Unix(), d.maxLifetime); err != nil {
+		return time.Time{}, fmt.Errorf("falha ao revogar tokens do subject: %w", err)
+	}
+	d.auditor.Record(ctx, model.AuditTokenRevoke, "sub:"+subject, nil, map[string]interface{}{"revokedAt": revokedAt})
+	d.logger.Info("Tokens do subject revogados", zap.String("subject", subject))
+	return revokedAt, nil
+}
+
+// IsRevoked verifica se o token foi revogado pelo jti ou pelo subject. Tokens
+// sem iat são considerados revogados quando o subject foi revogado.
+func (d *TokenDenylist) IsRevoked(ctx context.Context, subject, jti string, issuedAt time.Time) (bool, error) {
+	if jti != "" {
+		var revoked bool
+		found, err := d.cache.Get(ctx, revokedTokenPrefix+jti, &revoked)
+		if err != nil {
+			return false, fmt.Errorf("falha ao consultar revogação do token: %w", err)
+		}
+		if found {
+			return true, nil
+		}
+	}
+
+	if subject != "" {
+		var revokedAt int64
+		found, err := d.cache.Get(ctx, revokedSubjectPrefix+subject, &revokedAt)
+		if err != nil {
+			return false, fmt.Errorf("falha ao consultar revogação do subject: %w", err)
+		}
+		// O iat tem precisão de segundos; tokens do mesmo segundo da revogação
+		// também são recusados
+		if found && issuedAt.Unix() <= revokedAt {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/diillson/api-gateway-go/pkg/cache"
+	"github.com/diillson/api-gateway-go/pkg/security"
+	"go.uber.org/zap"
+)
+
+// ttlCache registra a expiração de cada chave gravada
+type ttlCache struct {
+	cache.Cache
+	ttls map[string]time.Duration
+}
+
+func newTTLCache() *ttlCache {
+	return &ttlCache{
+		Cache: cache.NewMemoryCache(time.Minute, time.Minute, nil, zap.NewNop()),
+		ttls:  make(map[string]time.Duration),
+	}
+}
+
+func (c *ttlCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	c.ttls[key] = expiration
+	return c.Cache.Set(ctx, key, value, expiration)
+}
+
+func TestTokenDenylistRevokeSubject(t *testing.T) {
+	ctx := context.Background()
+	c := newTTLCache()
+	denylist := NewTokenDenylist(c, 48*time.Hour, zap.NewNop())
+
+	issuedBefore := time.Now().Add(-time.Minute)
+	revokedAt, err := denylist.RevokeSubject(ctx, "usuario-1")
+	if err != nil {
+		t.Fatalf("RevokeSubject: %v", err)
+	}
+	if ttl := c.ttls[revokedSubjectPrefix+"usuario-1"]; ttl != 48*time.Hour {
+		t.Fatalf("revogação por subject expira em %s, esperada a validade máxima de 48h", ttl)
+	}
+
+	if revoked, _ := denylist.IsRevoked(ctx, "usuario-1", "", issuedBefore); !revoked {
+		t.Fatal("token emitido antes da revogação continua aceito")
+	}
+	if revoked, _ := denylist.IsRevoked(ctx, "usuario-1", "", revokedAt.Add(2*time.Second)); revoked {
+		t.Fatal("token emitido depois da revogação foi recusado")
+	}
+	if revoked, _ := denylist.IsRevoked(ctx, "usuario-2", "", issuedBefore); revoked {
+		t.Fatal("a revogação atingiu outro subject")
+	}
+}
+
+func TestTokenDenylistCoversGatewayTokens(t *testing.T) {
+	ctx := context.Background()
+	c := newTTLCache()
+	// Tokens externos de até 1h, mas os do gateway valem security.TokenLifetime
+	denylist := NewTokenDenylist(c, time.Hour, zap.NewNop())
+
+	if _, err := denylist.RevokeSubject(ctx, "usuario-1"); err != nil {
+		t.Fatalf("RevokeSubject: %v", err)
+	}
+	if ttl := c.ttls[revokedSubjectPrefix+"usuario-1"]; ttl != security.TokenLifetime {
+		t.Fatalf("revogação por subject expira em %s, antes dos tokens do gateway (%s)", ttl, security.TokenLifetime)
+	}
+}
+
+func TestTokenDenylistRevokeToken(t *testing.T) {
+	ctx := context.Background()
+	c := newTTLCache()
+	denylist := NewTokenDenylist(c, 48*time.Hour, zap.NewNop())
+
+	if err := denylist.RevokeToken(ctx, "jti-1", time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("RevokeToken: %v", err)
+	}
+	if ttl := c.ttls[revokedTokenPrefix+"jti-1"]; ttl <= 59*time.Minute || ttl > time.Hour {
+		t.Fatalf("revogação por jti expira em %s, esperada a expiração do token", ttl)
+	}
+
+	// Sem expiração conhecida, vale a validade máxima
+	if err := denylist.RevokeToken(ctx, "jti-2", time.Time{}); err != nil {
+		t.Fatalf("RevokeToken: %v", err)
+	}
+	if ttl := c.ttls[revokedTokenPrefix+"jti-2"]; ttl != 48*time.Hour {
+		t.Fatalf("revogação por jti sem expiração dura %s, esperado 48h", ttl)
+	}
+
+	// Tokens já expirados não precisam entrar na lista
+	if err := denylist.RevokeToken(ctx, "jti-3", time.Now().Add(-time.Hour)); err != nil {
+		t.Fatalf("RevokeToken: %v", err)
+	}
+	if _, ok := c.ttls[revokedTokenPrefix+"jti-3"]; ok {
+		t.Fatal("token expirado foi gravado na lista de revogação")
+	}
+
+	for jti, want := range map[string]bool{"jti-1": true, "jti-2": true, "jti-3": false} {
+		if revoked, err := denylist.IsRevoked(ctx, "", jti, time.Now()); err != nil || revoked != want {
+			t.Errorf("IsRevoked(%s) = %v, %v; esperado %v", jti, revoked, err, want)
+		}
+	}
+}
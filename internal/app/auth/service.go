@@ -9,6 +9,7 @@ import (
 	"go.opentelemetry.io/otel/trace"
 	"time"
 
+	"github.com/diillson/api-gateway-go/internal/app/audit"
 	"github.com/diillson/api-gateway-go/internal/domain/model"
 	"github.com/diillson/api-gateway-go/pkg/security"
 	"go.uber.org/zap"
@@ -24,6 +25,7 @@ type UserRepository interface {
 type AuthService struct {
 	keyManager *security.KeyManager
 	userRepo   UserRepository
+	denylist   *TokenDenylist
 	logger     *zap.Logger
 }
 
@@ -36,6 +38,11 @@ func NewAuthService(keyManager *security.KeyManager, userRepo UserRepository, lo
 	}
 }
 
+// SetDenylist habilita a recusa de tokens revogados
+func (s *AuthService) SetDenylist(denylist *TokenDenylist) {
+	s.denylist = denylist
+}
+
 // Login autentica um usuário e gera um token JWT
 func (s *AuthService) Login(ctx context.Context, username, password string) (string, error) {
 	ctx, span := otel.Tracer("api-gateway.auth").Start(
@@ -56,8 +63,7 @@ func (s *AuthService) Login(ctx context.Context, username, password string) (str
 		return "", errors.New("credenciais inválidas")
 	}
 
-	// Gerar token com duração de 24 horas
-	token, err := s.keyManager.GenerateToken(user.ID, user.Role, 24*time.Hour)
+	token, err := s.keyManager.GenerateToken(user.ID, user.Role, security.TokenLifetime)
 	if err != nil {
 		span.SetStatus(codes.Error, "generated token failed")
 		span.SetAttributes(attribute.Bool("error", true))
@@ -72,21 +78,125 @@ func (s *AuthService) Login(ctx context.Context, username, password string) (str
 
 // ValidateToken valida um token JWT e retorna o usuário correspondente
 func (s *AuthService) ValidateToken(tokenString string) (*model.User, error) {
+	user, _, err := s.ValidateTokenClaims(tokenString)
+	return user, err
+}
+
+// ValidateTokenClaims valida um token JWT e retorna o usuário e todas as claims
+// do token, usadas nas regras de autorização das rotas
+func (s *AuthService) ValidateTokenClaims(tokenString string) (*model.User, map[string]interface{}, error) {
 	claims, err := s.keyManager.VerifyToken(tokenString)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	if s.denylist != nil {
+		var issuedAt time.Time
+		if claims.IssuedAt != nil {
+			issuedAt = claims.IssuedAt.Time
+		}
+		revoked, err := s.denylist.IsRevoked(context.Background(), tokenSubject(claims), claims.ID, issuedAt)
+		if err != nil {
+			// Sem a lista de revogação não é possível garantir que o token é válido
+			s.logger.Error("Falha ao verificar revogação do token", zap.Error(err))
+			return nil, nil, err
+		}
+		if revoked {
+			return nil, nil, ErrTokenRevoked
+		}
+	}
+
+	// Usuários do provedor de identidade externo não existem no repositório
+	var user *model.User
+	if claims.External {
+		user = externalUser(claims)
+	} else {
+		user, err = s.userRepo.GetUserByID(claims.UserID)
+		if err != nil {
+			s.logger.Error("Usuário do token não encontrado", zap.String("user_id", claims.UserID), zap.Error(err))
+			return nil, nil, errors.New("usuário inválido")
+		}
 	}
 
-	user, err := s.userRepo.GetUserByID(claims.UserID)
+	tokenClaims := make(map[string]interface{}, len(claims.Raw)+2)
+	for name, value := range claims.Raw {
+		tokenClaims[name] = value
+	}
+	tokenClaims["user_id"] = user.ID
+	tokenClaims["role"] = user.Role
+	if user.Tenant != "" {
+		tokenClaims["tenant"] = user.Tenant
+	}
+	return user, tokenClaims, nil
+}
+
+// RevokeToken revoga o token informado até a sua expiração
+func (s *AuthService) RevokeToken(ctx context.Context, tokenString string) error {
+	if s.denylist == nil {
+		return errors.New("revogação de tokens não configurada")
+	}
+
+	claims, err := s.keyManager.VerifyToken(tokenString)
 	if err != nil {
-		s.logger.Error("Usuário do token não encontrado", zap.String("user_id", claims.UserID), zap.Error(err))
-		return nil, errors.New("usuário inválido")
+		return err
+	}
+	if claims.ID == "" {
+		return errors.New("token sem claim jti; revogue os tokens pelo subject")
+	}
+
+	// Sem usuário autenticado, como no logout, o autor é o dono do token
+	if actor, _ := audit.ActorFromContext(ctx); actor.Username == "" {
+		actor.ID = tokenSubject(claims)
+		actor.Username = actor.ID
+		if claims.PreferredUsername != "" {
+			actor.Username = claims.PreferredUsername
+		}
+		ctx = audit.WithActor(ctx, actor)
 	}
 
-	return user, nil
+	var expiresAt time.Time
+	if claims.ExpiresAt != nil {
+		expiresAt = claims.ExpiresAt.Time
+	}
+	return s.denylist.RevokeToken(ctx, claims.ID, expiresAt)
 }
 
 // IsAdmin verifica se um usuário tem permissão administrativa
 func (s *AuthService) IsAdmin(user *model.User) bool {
 	return user != nil && user.Role == "admin"
 }
+
+// IsGlobalAdmin verifica se o usuário administra todos os tenants. Usuários com
+// tenant definido, pelo cadastro ou pela claim tenant do token, administram
+// apenas o próprio tenant.
+func (s *AuthService) IsGlobalAdmin(user *model.User) bool {
+	return s.IsAdmin(user) && user.Tenant == ""
+}
+
+// tokenSubject identifica o dono do token: o sub de tokens externos ou o
+// user_id dos tokens do gateway
+func tokenSubject(claims *security.Claims) string {
+	if claims.External || claims.UserID == "" {
+		return claims.Subject
+	}
+	return claims.UserID
+}
+
+// externalUser monta o usuário a partir das claims de um token externo
+func externalUser(claims *security.Claims) *model.User {
+	username := claims.PreferredUsername
+	if username == "" {
+		username = claims.Subject
+	}
+	role := claims.Role
+	if role == "" {
+		role = "user"
+	}
+	return &model.User{
+		ID:       claims.Subject,
+		Username: username,
+		Role:     role,
+		Email:    claims.Email,
+		Tenant:   claims.Tenant,
+	}
+}
@@ -0,0 +1,140 @@
+// Package idempotency armazena as respostas das requisições com
+// Idempotency-Key para repeti-las nas novas tentativas dos clientes.
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/diillson/api-gateway-go/pkg/config"
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+)
+
+const keyPrefix = "idempotency:"
+
+// Estados de uma chave de idempotência
+const (
+	stateInProgress = "in_progress"
+	stateCompleted  = "completed"
+)
+
+var (
+	// ErrInProgress indica outra requisição com a mesma chave ainda em andamento
+	ErrInProgress = errors.New("requisição com a mesma chave de idempotência em andamento")
+	// ErrMismatch indica a chave reutilizada com uma requisição diferente
+	ErrMismatch = errors.New("chave de idempotência reutilizada com outra requisição")
+)
+
+// Response é a resposta armazenada para uma chave
+type Response struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// record é o valor guardado no Redis para uma chave
+type record struct {
+	State       string    `json:"state"`
+	Fingerprint string    `json:"fingerprint"` // Hash do método, caminho, query e corpo
+	Response    *Response `json:"response,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// Store guarda as chaves no Redis, compartilhado entre as instâncias. A
+// primeira requisição reserva a chave até concluir; as seguintes recebem a
+// resposta armazenada ou ErrInProgress.
+type Store struct {
+	client *redis.Client
+	cfg    config.IdempotencyConfig
+	logger *zap.Logger
+}
+
+// NewStore cria o armazenamento das chaves de idempotência
+func NewStore(client *redis.Client, cfg config.IdempotencyConfig, logger *zap.Logger) *Store {
+	return &Store{client: client, cfg: cfg, logger: logger}
+}
+
+// Header retorna o cabeçalho com a chave enviada pelo cliente
+func (s *Store) Header() string {
+	return s.cfg.Header
+}
+
+// MaxBodyBytes retorna o tamanho máximo dos corpos de requisição e resposta
+func (s *Store) MaxBodyBytes() int64 {
+	return s.cfg.MaxBodyBytes
+}
+
+// Begin reserva a chave para a requisição identificada por fingerprint. Se a
+// chave já foi concluída com a mesma requisição, retorna a resposta
+// armazenada; com outra requisição, ErrMismatch. Com a chave reservada por
+// outra requisição ainda em andamento, retorna ErrInProgress.
+func (s *Store) Begin(ctx context.Context, scope, key, fingerprint string) (*Response, error) {
+	redisKey := s.redisKey(scope, key)
+	pending, err := json.Marshal(record{State: stateInProgress, Fingerprint: fingerprint, CreatedAt: time.Now()})
+	if err != nil {
+		return nil, err
+	}
+	reserved, err := s.client.SetNX(ctx, redisKey, pending, s.cfg.LockTimeout).Result()
+	if err != nil {
+		return nil, err
+	}
+	if reserved {
+		return nil, nil
+	}
+
+	data, err := s.client.Get(ctx, redisKey).Bytes()
+	if err == redis.Nil {
+		// A chave expirou entre as duas operações: tentar reservar de novo
+		return s.Begin(ctx, scope, key, fingerprint)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var stored record
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, err
+	}
+	if stored.Fingerprint != fingerprint {
+		return nil, ErrMismatch
+	}
+	if stored.State != stateCompleted || stored.Response == nil {
+		return nil, ErrInProgress
+	}
+	return stored.Response, nil
+}
+
+// Complete armazena a resposta da chave reservada por Begin
+func (s *Store) Complete(ctx context.Context, scope, key, fingerprint string, response *Response, ttl time.Duration) error {
+	if ttl <= 0 {
+		ttl = s.cfg.TTL
+	}
+	data, err := json.Marshal(record{
+		State:       stateCompleted,
+		Fingerprint: fingerprint,
+		Response:    response,
+		CreatedAt:   time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	return s.client.Set(ctx, s.redisKey(scope, key), data, ttl).Err()
+}
+
+// Release libera a chave reservada sem armazenar a resposta, permitindo que o
+// cliente tente de novo (ex.: erro do backend)
+func (s *Store) Release(ctx context.Context, scope, key string) error {
+	return s.client.Del(ctx, s.redisKey(scope, key)).Err()
+}
+
+// redisKey monta a chave no Redis. O escopo (rota e consumidor) e a chave do
+// cliente são resumidos para limitar o tamanho.
+func (s *Store) redisKey(scope, key string) string {
+	sum := sha256.Sum256([]byte(scope + "\x00" + key))
+	return keyPrefix + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,89 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/diillson/api-gateway-go/internal/adapter/database"
+	"github.com/diillson/api-gateway-go/pkg/config"
+	"go.uber.org/zap"
+)
+
+// ShardMigrations é a situação das migrações de um banco
+type ShardMigrations struct {
+	Shard      string
+	Migrations []database.MigrationStatus
+}
+
+// RunMigrations aplica as migrações pendentes no banco principal e nos shards,
+// sem iniciar o gateway. Usado pelo subcomando migrate e por implantações que
+// pulam as migrações na inicialização (database.skipMigrations).
+func RunMigrations(ctx context.Context, logger *zap.Logger, cfg *config.Config) error {
+	return forEachDatabase(ctx, logger, cfg, func(name string, db *database.Database) error {
+		logger.Info("Aplicando migrações", zap.String("shard", name))
+		return db.Migrate(ctx)
+	})
+}
+
+// RollbackMigrations reverte a última migração aplicada no banco principal e
+// em cada shard, mantendo os esquemas na mesma versão
+func RollbackMigrations(ctx context.Context, logger *zap.Logger, cfg *config.Config) error {
+	return forEachDatabase(ctx, logger, cfg, func(name string, db *database.Database) error {
+		migration, err := db.RollbackMigration(ctx)
+		if err != nil {
+			return err
+		}
+		logger.Info("Migração revertida",
+			zap.String("shard", name),
+			zap.Int64("version", migration.Version),
+			zap.String("name", migration.Name))
+		return nil
+	})
+}
+
+// MigrationStatus lista as migrações aplicadas e pendentes do banco principal e dos shards
+func MigrationStatus(ctx context.Context, logger *zap.Logger, cfg *config.Config) ([]ShardMigrations, error) {
+	var result []ShardMigrations
+	err := forEachDatabase(ctx, logger, cfg, func(name string, db *database.Database) error {
+		migrations, err := db.MigrationStatus(ctx)
+		if err != nil {
+			return err
+		}
+		result = append(result, ShardMigrations{Shard: name, Migrations: migrations})
+		return nil
+	})
+	return result, err
+}
+
+// forEachDatabase conecta, um de cada vez, ao banco principal e aos shards
+// configurados, sem aplicar migrações na conexão
+func forEachDatabase(ctx context.Context, logger *zap.Logger, cfg *config.Config,
+	fn func(name string, db *database.Database) error) error {
+
+	targets := []config.DatabaseShardConfig{{
+		Name:   database.PrimaryShard,
+		Driver: cfg.Database.Driver,
+		DSN:    cfg.Database.DSN,
+	}}
+	targets = append(targets, cfg.Database.Shards...)
+
+	for _, target := range targets {
+		dbConfig := newDatabaseConfig(cfg, target.Driver, target.DSN)
+		dbConfig.SkipMigrations = true
+
+		db, err := database.NewDatabase(ctx, dbConfig, logger)
+		if err != nil {
+			return fmt.Errorf("falha ao conectar ao banco %s: %w", target.Name, err)
+		}
+
+		err = fn(target.Name, db)
+		if closeErr := db.Close(); closeErr != nil {
+			logger.Warn("Falha ao fechar conexão com o banco", zap.String("shard", target.Name), zap.Error(closeErr))
+		}
+		if err != nil {
+			return fmt.Errorf("banco %s: %w", target.Name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,343 @@
+// Package quota contabiliza o uso diário e mensal de cada consumidor e aplica
+// as cotas configuradas, base para a cobrança pelo uso das APIs.
+package quota
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/internal/domain/repository"
+	"github.com/diillson/api-gateway-go/pkg/config"
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+)
+
+const (
+	// PeriodDaily identifica a cota diária
+	PeriodDaily = "daily"
+	// PeriodMonthly identifica a cota mensal
+	PeriodMonthly = "monthly"
+)
+
+const (
+	keyPrefix = "quota:"
+	// dirtyKey é o conjunto de contadores alterados desde a última gravação no banco
+	dirtyKey = keyPrefix + "dirty"
+	// counterRetention mantém o contador após o fim do período, para que a
+	// última gravação no banco ainda o encontre
+	counterRetention = 48 * time.Hour
+	// flushBatch é a quantidade de contadores gravados por vez no banco
+	flushBatch = 500
+	// historyLimit é a quantidade de períodos retornados no histórico
+	historyLimit = 90
+)
+
+// Consumer identifica quem consome as APIs
+type Consumer struct {
+	ID   string // apikey:<id> ou sub:<id>
+	Tier string // Faixa da chave de API, se houver
+}
+
+// PeriodUsage é o consumo do consumidor em um período de cota
+type PeriodUsage struct {
+	Period  string    `json:"period"` // daily ou monthly
+	Key     string    `json:"key"`    // Dia (2026-10-16) ou mês (2026-10)
+	Used    int64     `json:"used"`
+	Limit   int64     `json:"limit"` // 0 sem limite
+	ResetAt time.Time `json:"resetAt"`
+}
+
+// Remaining retorna as requisições restantes no período; -1 sem limite
+func (p PeriodUsage) Remaining() int64 {
+	if p.Limit <= 0 {
+		return -1
+	}
+	if p.Used >= p.Limit {
+		return 0
+	}
+	return p.Limit - p.Used
+}
+
+// Usage é o consumo corrente do consumidor
+type Usage struct {
+	Consumer string        `json:"consumer"`
+	Periods  []PeriodUsage `json:"periods"`
+}
+
+// Exceeded retorna o período cuja cota foi excedida, se houver
+func (u Usage) Exceeded() (PeriodUsage, bool) {
+	for _, p := range u.Periods {
+		if p.Limit > 0 && p.Used > p.Limit {
+			return p, true
+		}
+	}
+	return PeriodUsage{}, false
+}
+
+// Tightest retorna o período limitado com menos requisições restantes, usado
+// nos cabeçalhos da resposta
+func (u Usage) Tightest() (PeriodUsage, bool) {
+	var tightest PeriodUsage
+	found := false
+	for _, p := range u.Periods {
+		if p.Limit <= 0 {
+			continue
+		}
+		if !found || p.Remaining() < tightest.Remaining() {
+			tightest = p
+			found = true
+		}
+	}
+	return tightest, found
+}
+
+// TierSource fornece as faixas das chaves de API, que podem definir cotas próprias
+type TierSource interface {
+	Tier(name string) (config.APIKeyTierConfig, bool)
+}
+
+// limits são as cotas padrão dos consumidores
+type limits struct {
+	daily   int64
+	monthly int64
+}
+
+// Service conta as requisições de cada consumidor no Redis, compartilhado
+// entre as instâncias, e grava os totais no banco periodicamente. Os
+// períodos seguem o calendário UTC.
+type Service struct {
+	client        *redis.Client
+	repo          repository.QuotaRepository
+	limits        atomic.Pointer[limits]
+	tiers         TierSource
+	flushInterval time.Duration
+	logger        *zap.Logger
+}
+
+// NewService cria o serviço de cotas
+func NewService(client *redis.Client, repo repository.QuotaRepository, cfg config.QuotasConfig, logger *zap.Logger) *Service {
+	s := &Service{
+		client:        client,
+		repo:          repo,
+		flushInterval: cfg.FlushInterval,
+		logger:        logger,
+	}
+	s.SetLimits(cfg.Daily, cfg.Monthly)
+	return s
+}
+
+// SetLimits substitui as cotas padrão dos consumidores
+func (s *Service) SetLimits(daily, monthly int64) {
+	s.limits.Store(&limits{daily: daily, monthly: monthly})
+}
+
+// SetTiers configura a origem das faixas das chaves de API
+func (s *Service) SetTiers(tiers TierSource) {
+	s.tiers = tiers
+}
+
+// Consume contabiliza uma requisição do consumidor e retorna o consumo
+// resultante. Requisições que excedem a cota não são contabilizadas.
+func (s *Service) Consume(ctx context.Context, consumer Consumer) (Usage, error) {
+	usage := s.usage(consumer, time.Now())
+
+	pipe := s.client.TxPipeline()
+	counts := make([]*redis.IntCmd, len(usage.Periods))
+	for i, p := range usage.Periods {
+		key := counterKey(consumer.ID, p.Key)
+		counts[i] = pipe.Incr(ctx, key)
+		pipe.ExpireAt(ctx, key, p.ResetAt.Add(counterRetention))
+		pipe.SAdd(ctx, dirtyKey, dirtyMember(consumer.ID, p.Key))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return usage, err
+	}
+
+	for i := range usage.Periods {
+		p := &usage.Periods[i]
+		p.Used = counts[i].Val()
+		if p.Used == 1 {
+			// Contador novo no Redis: parte do total gravado no banco, caso o
+			// período já tenha começado antes (ex.: Redis reiniciado)
+			used, err := s.seed(ctx, consumer.ID, p.Key)
+			if err != nil {
+				return usage, err
+			}
+			p.Used = used
+		}
+	}
+
+	if _, exceeded := usage.Exceeded(); exceeded {
+		pipe := s.client.Pipeline()
+		for i := range usage.Periods {
+			pipe.Decr(ctx, counterKey(consumer.ID, usage.Periods[i].Key))
+			usage.Periods[i].Used--
+		}
+		if _, err := pipe.Exec(ctx); err != nil {
+			s.logger.Warn("Falha ao descontar requisição recusada da cota",
+				zap.String("consumer", consumer.ID), zap.Error(err))
+		}
+	}
+	return usage, nil
+}
+
+// Current retorna o consumo corrente do consumidor sem contabilizar requisições
+func (s *Service) Current(ctx context.Context, consumer Consumer) (Usage, error) {
+	usage := s.usage(consumer, time.Now())
+	for i := range usage.Periods {
+		p := &usage.Periods[i]
+		used, err := s.client.Get(ctx, counterKey(consumer.ID, p.Key)).Int64()
+		if err == redis.Nil {
+			used, err = s.repo.GetUsage(ctx, consumer.ID, p.Key)
+		}
+		if err != nil {
+			return usage, err
+		}
+		p.Used = used
+	}
+	return usage, nil
+}
+
+// History retorna os totais gravados do consumidor, do período mais recente ao
+// mais antigo
+func (s *Service) History(ctx context.Context, consumerID string) ([]model.ConsumerUsage, error) {
+	return s.repo.ListUsage(ctx, consumerID, historyLimit)
+}
+
+// Run grava periodicamente os contadores alterados no banco até o contexto ser
+// cancelado. Em cluster, deve ser executado apenas pelo líder.
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Flush(ctx)
+		}
+	}
+}
+
+// Flush grava no banco os contadores alterados desde a última gravação
+func (s *Service) Flush(ctx context.Context) {
+	for {
+		members, err := s.client.SPopN(ctx, dirtyKey, flushBatch).Result()
+		if err != nil {
+			s.logger.Error("Falha ao obter contadores de cota alterados", zap.Error(err))
+			return
+		}
+		if len(members) == 0 {
+			return
+		}
+
+		usage := make([]model.ConsumerUsage, 0, len(members))
+		now := time.Now()
+		for _, member := range members {
+			consumer, period, ok := parseDirtyMember(member)
+			if !ok {
+				continue
+			}
+			requests, err := s.client.Get(ctx, counterKey(consumer, period)).Int64()
+			if err != nil {
+				if err != redis.Nil {
+					s.logger.Warn("Falha ao ler contador de cota",
+						zap.String("consumer", consumer), zap.String("period", period), zap.Error(err))
+				}
+				continue
+			}
+			usage = append(usage, model.ConsumerUsage{
+				Consumer:  consumer,
+				Period:    period,
+				Requests:  requests,
+				UpdatedAt: now,
+			})
+		}
+
+		if err := s.repo.SaveUsage(ctx, usage); err != nil {
+			s.logger.Error("Falha ao gravar uso dos consumidores", zap.Error(err))
+			// Os contadores voltam ao conjunto para a próxima gravação
+			restore := make([]interface{}, len(members))
+			for i, member := range members {
+				restore[i] = member
+			}
+			if err := s.client.SAdd(ctx, dirtyKey, restore...).Err(); err != nil {
+				s.logger.Error("Falha ao restaurar contadores de cota alterados", zap.Error(err))
+			}
+			return
+		}
+		s.logger.Debug("Uso dos consumidores gravado", zap.Int("counters", len(usage)))
+	}
+}
+
+// seed soma ao contador recém-criado o total já gravado no banco
+func (s *Service) seed(ctx context.Context, consumerID, period string) (int64, error) {
+	stored, err := s.repo.GetUsage(ctx, consumerID, period)
+	if err != nil {
+		s.logger.Warn("Falha ao obter uso gravado do consumidor",
+			zap.String("consumer", consumerID), zap.String("period", period), zap.Error(err))
+		return 1, nil
+	}
+	if stored == 0 {
+		return 1, nil
+	}
+	return s.client.IncrBy(ctx, counterKey(consumerID, period), stored).Result()
+}
+
+// usage monta os períodos correntes do consumidor com as cotas aplicáveis
+func (s *Service) usage(consumer Consumer, now time.Time) Usage {
+	daily, monthly := s.limitsFor(consumer)
+	now = now.UTC()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	return Usage{
+		Consumer: consumer.ID,
+		Periods: []PeriodUsage{
+			{Period: PeriodDaily, Key: day.Format("2006-01-02"), Limit: daily, ResetAt: day.AddDate(0, 0, 1)},
+			{Period: PeriodMonthly, Key: month.Format("2006-01"), Limit: monthly, ResetAt: month.AddDate(0, 1, 0)},
+		},
+	}
+}
+
+// limitsFor retorna as cotas do consumidor. A faixa da chave de API prevalece
+// sobre as cotas padrão.
+func (s *Service) limitsFor(consumer Consumer) (int64, int64) {
+	defaults := s.limits.Load()
+	daily, monthly := defaults.daily, defaults.monthly
+	if consumer.Tier != "" && s.tiers != nil {
+		if tier, ok := s.tiers.Tier(consumer.Tier); ok {
+			if tier.DailyQuota > 0 {
+				daily = tier.DailyQuota
+			}
+			if tier.MonthlyQuota > 0 {
+				monthly = tier.MonthlyQuota
+			}
+		}
+	}
+	return daily, monthly
+}
+
+// counterKey monta a chave do contador do consumidor no período
+func counterKey(consumerID, period string) string {
+	return keyPrefix + consumerID + ":" + period
+}
+
+// dirtyMember identifica o contador no conjunto de contadores alterados
+func dirtyMember(consumerID, period string) string {
+	return consumerID + "|" + period
+}
+
+// parseDirtyMember separa o consumidor e o período. O período não contém
+// "|", então a última ocorrência separa os dois.
+func parseDirtyMember(member string) (string, string, bool) {
+	i := strings.LastIndex(member, "|")
+	if i <= 0 || i == len(member)-1 {
+		return "", "", false
+	}
+	return member[:i], member[i+1:], true
+}
@@ -0,0 +1,241 @@
+// Package recording grava uma amostra das requisições das rotas e das
+// respostas enviadas aos clientes, para reproduzir em outro ambiente problemas
+// que só ocorrem com o tráfego real.
+package recording
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/rand"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/internal/domain/repository"
+	"github.com/diillson/api-gateway-go/internal/infra/metrics"
+	"github.com/diillson/api-gateway-go/pkg/config"
+	"go.uber.org/zap"
+)
+
+const (
+	// batchSize é o máximo de gravações de cada escrita no banco
+	batchSize = 100
+	// flushInterval é a espera máxima de uma gravação na fila
+	flushInterval = time.Second
+	// pruneInterval é o intervalo entre as remoções das gravações antigas
+	pruneInterval = time.Hour
+)
+
+// Recorder grava em segundo plano as requisições capturadas pelas rotas com
+// política de gravação
+type Recorder struct {
+	repo      repository.RecordingRepository
+	queue     chan *model.Recording
+	retention time.Duration
+	metrics   *metrics.APIMetrics
+	logger    *zap.Logger
+}
+
+// NewRecorder cria o gravador de tráfego
+func NewRecorder(repo repository.RecordingRepository, cfg config.RecordingConfig, metrics *metrics.APIMetrics, logger *zap.Logger) *Recorder {
+	return &Recorder{
+		repo:      repo,
+		queue:     make(chan *model.Recording, cfg.QueueSize),
+		retention: cfg.Retention,
+		metrics:   metrics,
+		logger:    logger,
+	}
+}
+
+// Sample sorteia se a requisição será gravada, conforme a política da rota
+func (r *Recorder) Sample(policy *model.RecordingPolicy, method string) bool {
+	return policy.Records(method) && rand.Float64() < policy.SampleRate
+}
+
+// Capture remove os dados sensíveis da gravação e a coloca na fila do banco.
+// Com a fila cheia, a gravação é descartada para não atrasar as requisições.
+func (r *Recorder) Capture(policy *model.RecordingPolicy, recording *model.Recording) {
+	Redact(policy, recording)
+
+	select {
+	case r.queue <- recording:
+		r.recordMetric(recording.Route, "queued")
+	default:
+		r.recordMetric(recording.Route, "dropped")
+		r.logger.Warn("Fila de gravações cheia, gravação descartada",
+			zap.String("path", recording.Route))
+	}
+}
+
+// Run grava as requisições da fila até o contexto ser cancelado
+func (r *Recorder) Run(ctx context.Context) {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	var batch []*model.Recording
+	for {
+		select {
+		case <-ctx.Done():
+			// Gravar o que já foi capturado antes de encerrar
+			shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+			r.save(shutdownCtx, append(batch, r.drain()...))
+			cancel()
+			return
+		case recording := <-r.queue:
+			if batch = append(batch, recording); len(batch) >= batchSize {
+				r.save(ctx, batch)
+				batch = nil
+			}
+		case <-ticker.C:
+			r.save(ctx, batch)
+			batch = nil
+		}
+	}
+}
+
+// RunRetention remove periodicamente as gravações antigas até o contexto ser
+// cancelado. Em cluster, basta uma instância executá-la.
+func (r *Recorder) RunRetention(ctx context.Context) {
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+	for {
+		if err := r.repo.DeleteBefore(ctx, time.Now().Add(-r.retention)); err != nil && ctx.Err() == nil {
+			r.logger.Warn("Falha ao remover gravações antigas", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// List consulta as gravações
+func (r *Recorder) List(ctx context.Context, filter repository.RecordingFilter) ([]*model.Recording, int64, error) {
+	return r.repo.ListRecordings(ctx, filter)
+}
+
+// Get retorna a gravação pelo ID
+func (r *Recorder) Get(ctx context.Context, id string) (*model.Recording, error) {
+	return r.repo.GetRecording(ctx, id)
+}
+
+func (r *Recorder) save(ctx context.Context, batch []*model.Recording) {
+	if len(batch) == 0 {
+		return
+	}
+	if err := r.repo.SaveRecordings(ctx, batch); err != nil {
+		r.logger.Error("Falha ao gravar requisições gravadas",
+			zap.Int("count", len(batch)),
+			zap.Error(err))
+	}
+}
+
+// drain retira da fila as gravações pendentes
+func (r *Recorder) drain() []*model.Recording {
+	var pending []*model.Recording
+	for {
+		select {
+		case recording := <-r.queue:
+			pending = append(pending, recording)
+		default:
+			return pending
+		}
+	}
+}
+
+func (r *Recorder) recordMetric(route, result string) {
+	if r.metrics != nil {
+		r.metrics.RecordingCaptured(route, result)
+	}
+}
+
+// Redact substitui os valores dos cabeçalhos, dos parâmetros da query e dos
+// campos JSON indicados pela política. Corpos comprimidos não podem ter campos
+// removidos e são descartados quando a política remove campos.
+func Redact(policy *model.RecordingPolicy, recording *model.Recording) {
+	redactHeaders(policy, recording.RequestHeaders)
+	redactHeaders(policy, recording.ResponseHeaders)
+
+	if len(policy.RedactFields) == 0 {
+		return
+	}
+	fields := make(map[string]bool, len(policy.RedactFields))
+	for _, field := range policy.RedactFields {
+		fields[strings.ToLower(field)] = true
+	}
+
+	if recording.Query != "" {
+		if values, err := url.ParseQuery(recording.Query); err == nil {
+			changed := false
+			for name, list := range values {
+				if fields[strings.ToLower(name)] {
+					for i := range list {
+						list[i] = model.RedactedValue
+					}
+					changed = true
+				}
+			}
+			if changed {
+				recording.Query = values.Encode()
+			}
+		}
+	}
+
+	recording.RequestBody, recording.RequestBodyTruncated = redactBody(recording.RequestHeaders.Get("Content-Encoding"), recording.RequestBody, recording.RequestBodyTruncated, fields)
+	recording.ResponseBody, recording.ResponseBodyTruncated = redactBody(recording.ResponseHeaders.Get("Content-Encoding"), recording.ResponseBody, recording.ResponseBodyTruncated, fields)
+}
+
+func redactHeaders(policy *model.RecordingPolicy, header map[string][]string) {
+	for name, values := range header {
+		if policy.RedactsHeader(name) {
+			for i := range values {
+				values[i] = model.RedactedValue
+			}
+		}
+	}
+}
+
+// redactBody remove os campos do corpo JSON. Corpos que não são JSON seguem
+// como estão; corpos comprimidos ou truncados, que não podem ser analisados,
+// são descartados.
+func redactBody(encoding string, body []byte, truncated bool, fields map[string]bool) ([]byte, bool) {
+	if len(body) == 0 {
+		return body, truncated
+	}
+	if (encoding != "" && !strings.EqualFold(encoding, "identity")) || truncated {
+		return nil, true
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return body, truncated
+	}
+	redactValue(document, fields)
+	redacted, err := json.Marshal(document)
+	if err != nil {
+		return nil, true
+	}
+	return redacted, truncated
+}
+
+func redactValue(value interface{}, fields map[string]bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if fields[strings.ToLower(key)] {
+				v[key] = model.RedactedValue
+				continue
+			}
+			redactValue(field, fields)
+		}
+	case []interface{}:
+		for _, item := range v {
+			redactValue(item, fields)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package route
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/internal/domain/repository"
+)
+
+// ErrPathConflict indica que o caminho da rota é equivalente ao de outra rota
+// cadastrada, que a sombrearia na busca
+var ErrPathConflict = errors.New("caminho conflita com rota existente")
+
+// ListAllRoutes retorna todas as rotas do repositório, inclusive as inativas,
+// ordenadas pelo caminho. Diferente de GetRoutes, não usa o cache.
+func (s *Service) ListAllRoutes(ctx context.Context) ([]*model.Route, error) {
+	routes, err := s.repo.GetRoutesWithFilters(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(routes, func(i, j int) bool { return routes[i].Path < routes[j].Path })
+	return routes, nil
+}
+
+// GetRoute obtém a rota cadastrada com exatamente o caminho informado,
+// inclusive inativa, sem correspondência de padrões
+func (s *Service) GetRoute(ctx context.Context, path string) (*model.Route, error) {
+	return s.repo.GetRouteByPath(ctx, path)
+}
+
+// checkPathConflict verifica se outra rota do tenant já usa o caminho ou um
+// caminho equivalente (ex.: /users/:id e /users/:userId). Tenants diferentes
+// podem usar os mesmos caminhos. Rotas com condições de correspondência
+// (match), como hosts distintos, podem compartilhar o padrão quando ambas as
+// definem.
+func (s *Service) checkPathConflict(ctx context.Context, route *model.Route, replacing bool) error {
+	routes, err := s.repo.GetRoutesWithFilters(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	pattern := pathPattern(route.Path)
+	for _, existing := range routes {
+		if existing.Path == route.Path {
+			if replacing {
+				continue
+			}
+			return repository.ErrRouteExists
+		}
+		if pathPattern(existing.Path) != pattern {
+			continue
+		}
+		if route.Match != nil && existing.Match != nil {
+			continue
+		}
+		return fmt.Errorf("%w: %s", ErrPathConflict, existing.Path)
+	}
+	return nil
+}
+
+// pathPattern normaliza os nomes dos parâmetros do caminho, que não
+// distinguem rotas na busca
+func pathPattern(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = ":"
+		}
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,45 @@
+package route
+
+import (
+	"context"
+
+	"github.com/diillson/api-gateway-go/internal/app/audit"
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"go.uber.org/zap"
+)
+
+// SetAuditor configura o registro das alterações de rotas no log de auditoria
+func (s *Service) SetAuditor(auditor *audit.Recorder) {
+	s.auditor = auditor
+}
+
+// auditBefore obtém o estado da rota antes da alteração, apenas quando a
+// auditoria está habilitada
+func (s *Service) auditBefore(ctx context.Context, path string) *model.Route {
+	if s.auditor == nil {
+		return nil
+	}
+	route, err := s.repo.GetRouteByPath(ctx, path)
+	if err != nil {
+		s.logger.Warn("Falha ao obter estado anterior da rota para auditoria",
+			zap.String("path", path),
+			zap.Error(err))
+		return nil
+	}
+	return route
+}
+
+// audit registra a alteração com os estados da rota sem valores sensíveis
+func (s *Service) audit(ctx context.Context, action, path string, before, after *model.Route) {
+	if s.auditor == nil {
+		return
+	}
+	var beforeState, afterState interface{}
+	if before != nil {
+		beforeState = before.Redacted()
+	}
+	if after != nil {
+		afterState = after.Redacted()
+	}
+	s.auditor.Record(ctx, action, path, beforeState, afterState)
+}
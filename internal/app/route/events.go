@@ -0,0 +1,84 @@
+package route
+
+import (
+	"sync"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+)
+
+// EventType identifica o tipo de alteração de uma rota
+type EventType string
+
+const (
+	EventCreated EventType = "created"
+	EventUpdated EventType = "updated"
+	EventDeleted EventType = "deleted"
+)
+
+// Event descreve uma alteração de rota feita por este serviço
+type Event struct {
+	Type   EventType    `json:"type"`
+	Path   string       `json:"path"`
+	Route  *model.Route `json:"route,omitempty"` // nil em EventDeleted
+	Tenant string       `json:"tenant,omitempty"`
+	Time   time.Time    `json:"time"`
+}
+
+// Redacted retorna uma cópia do evento com a rota sem valores sensíveis,
+// adequada para entrega a sistemas externos
+func (e Event) Redacted() Event {
+	if e.Route != nil {
+		e.Route = e.Route.Redacted()
+	}
+	return e
+}
+
+// eventBus distribui eventos de alteração de rotas aos assinantes
+type eventBus struct {
+	mu          sync.RWMutex
+	subscribers map[int]chan Event
+	nextID      int
+}
+
+func newEventBus() *eventBus {
+	return &eventBus{subscribers: make(map[int]chan Event)}
+}
+
+// subscribe registra um assinante com o buffer informado
+func (b *eventBus) subscribe(buffer int) (<-chan Event, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextID
+	b.nextID++
+	ch := make(chan Event, buffer)
+	b.subscribers[id] = ch
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			delete(b.subscribers, id)
+			close(ch)
+		})
+	}
+}
+
+// publish entrega o evento sem bloquear; assinantes lentos perdem eventos
+// e devem se ressincronizar com a listagem de rotas
+func (b *eventBus) publish(event Event) int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	dropped := 0
+	for _, ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+			dropped++
+		}
+	}
+	return dropped
+}
@@ -0,0 +1,125 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+)
+
+// MatchCandidate é uma rota cujo caminho corresponde à requisição, avaliada
+// na busca em ordem de precedência
+type MatchCandidate struct {
+	Path     string `json:"path"`
+	Kind     string `json:"kind"` // exact, params ou prefix
+	Matched  bool   `json:"matched"`
+	Selected bool   `json:"selected"`
+	Reason   string `json:"reason"`
+}
+
+// VariantDecision é a avaliação de uma variante da rota escolhida
+type VariantDecision struct {
+	Index   int    `json:"index"`
+	Matched bool   `json:"matched"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// MatchExplanation descreve a decisão de roteamento de uma requisição
+type MatchExplanation struct {
+	Candidates []MatchCandidate  `json:"candidates"`
+	Variants   []VariantDecision `json:"variants,omitempty"`
+	Variant    int               `json:"variant"` // -1 mantém o backend padrão
+	Route      *model.Route      `json:"-"`       // Rota escolhida, com a variante aplicada
+}
+
+// ExplainMatch avalia a requisição contra todas as rotas, como GetRouteByPath,
+// mas registra cada candidata: se atende às condições, qual foi escolhida e
+// por que as demais foram descartadas. O índice é construído a partir das
+// rotas atuais, sem usar nem alterar o índice em memória das buscas.
+func (s *Service) ExplainMatch(ctx context.Context, path string, info model.RequestInfo) (*MatchExplanation, error) {
+	routes, _, err := s.loadRoutes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	explanation := &MatchExplanation{Variant: -1}
+	var selected *model.Route
+	seen := make(map[*model.Route]bool)
+
+	// accept recusa todas as rotas para que a busca percorra todas as candidatas
+	newRouteIndex(routes).match(path, func(r *model.Route) bool {
+		if seen[r] {
+			return false
+		}
+		seen[r] = true
+
+		candidate := MatchCandidate{Path: r.Path, Kind: pathKind(r.Path)}
+		mismatch := ""
+		if r.Match != nil {
+			mismatch = r.Match.Mismatch(info)
+		}
+		switch {
+		case mismatch != "":
+			candidate.Reason = "condição não atendida: " + mismatch
+		case selected != nil:
+			candidate.Matched = true
+			candidate.Reason = fmt.Sprintf("preterida por %s, de maior precedência", selected.Path)
+		default:
+			candidate.Matched = true
+			candidate.Selected = true
+			candidate.Reason = selectionReason(r, path)
+			selected = r
+		}
+		explanation.Candidates = append(explanation.Candidates, candidate)
+		return false
+	})
+
+	if selected == nil {
+		return explanation, nil
+	}
+
+	for i := range selected.Variants {
+		decision := VariantDecision{Index: i}
+		if mismatch := selected.Variants[i].Match.Mismatch(info); mismatch != "" {
+			decision.Reason = mismatch
+		} else {
+			decision.Matched = explanation.Variant < 0
+			if decision.Matched {
+				explanation.Variant = i
+			} else {
+				decision.Reason = fmt.Sprintf("preterida pela variante %d", explanation.Variant)
+			}
+		}
+		explanation.Variants = append(explanation.Variants, decision)
+	}
+	explanation.Route, _ = selected.ForRequest(info)
+	return explanation, nil
+}
+
+// pathKind classifica o caminho da rota pela precedência na busca
+func pathKind(path string) string {
+	switch {
+	case strings.HasSuffix(path, "/*"):
+		return "prefix"
+	case strings.Contains(path, ":"):
+		return "params"
+	default:
+		return "exact"
+	}
+}
+
+// selectionReason descreve por que a rota escolhida venceu a busca
+func selectionReason(r *model.Route, path string) string {
+	reason := "caminho exato"
+	switch {
+	case r.Path != path && pathKind(r.Path) == "prefix":
+		reason = "curinga de prefixo mais longo"
+	case r.Path != path:
+		reason = "caminho com parâmetros"
+	}
+	if r.Match != nil && !r.Match.IsEmpty() {
+		reason += " e condições atendidas"
+	}
+	return "primeira candidata aceita: " + reason
+}
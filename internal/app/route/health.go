@@ -0,0 +1,49 @@
+package route
+
+import (
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/internal/infra/healthcheck"
+)
+
+// SetHealthRegistry configura o registro com o resultado das verificações
+// ativas de saúde dos backends
+func (s *Service) SetHealthRegistry(registry *healthcheck.Registry) {
+	s.health = registry
+}
+
+// IsTargetAvailable indica se o alvo da rota pode receber tráfego. Sem
+// verificação ativa, todos os alvos são considerados disponíveis.
+func (s *Service) IsTargetAvailable(path, target string) bool {
+	return s.health == nil || s.health.IsAvailable(path, target)
+}
+
+// AvailableTargets retorna os alvos da rota que podem receber tráfego
+func (s *Service) AvailableTargets(route *model.Route) []model.Target {
+	targets := make([]model.Target, 0, len(route.Upstreams()))
+	for _, target := range route.Upstreams() {
+		if s.IsTargetAvailable(route.Path, target.URL) {
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
+
+// UpstreamHealth retorna o estado de saúde dos alvos verificados. Com path
+// informado, apenas os alvos dessa rota são retornados.
+func (s *Service) UpstreamHealth(path string) []healthcheck.TargetHealth {
+	if s.health == nil {
+		return []healthcheck.TargetHealth{}
+	}
+
+	snapshot := s.health.Snapshot()
+	if path == "" {
+		return snapshot
+	}
+	filtered := make([]healthcheck.TargetHealth, 0, len(snapshot))
+	for _, health := range snapshot {
+		if health.Route == path {
+			filtered = append(filtered, health)
+		}
+	}
+	return filtered
+}
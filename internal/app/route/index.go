@@ -0,0 +1,203 @@
+package route
+
+import (
+	"strings"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+)
+
+// indexTTL limita a idade do índice em memória, para que alterações feitas
+// por outras instâncias do gateway passem a valer sem reinício
+const indexTTL = 30 * time.Second
+
+// maxIndexes limita os índices em memória, um por tenant. Ao atingir o limite,
+// os índices expirados e depois o mais antigo são descartados.
+const maxIndexes = 256
+
+// routeIndex indexa as rotas pelo caminho para que a busca não percorra todas
+// as rotas: caminhos exatos ficam em um mapa, rotas com parâmetros (:id) em
+// uma árvore de segmentos e rotas curinga (/*) em uma árvore radix de prefixos.
+type routeIndex struct {
+	exact    map[string][]*model.Route
+	params   *segmentNode
+	prefixes *radixNode
+	size     int
+	builtAt  time.Time
+}
+
+// newRouteIndex constrói o índice com as mesmas regras de MatchRoutePath
+func newRouteIndex(routes []*model.Route) *routeIndex {
+	idx := &routeIndex{
+		exact:    make(map[string][]*model.Route, len(routes)),
+		params:   &segmentNode{},
+		prefixes: &radixNode{},
+		size:     len(routes),
+		builtAt:  time.Now(),
+	}
+
+	for _, route := range routes {
+		idx.exact[route.Path] = append(idx.exact[route.Path], route)
+		switch {
+		case strings.HasSuffix(route.Path, "/*"):
+			idx.prefixes.insert(strings.TrimSuffix(route.Path, "/*"), route)
+		case strings.Contains(route.Path, ":"):
+			idx.params.insert(route.Path, route)
+		}
+	}
+	return idx
+}
+
+// expired indica se o índice deve ser reconstruído
+func (idx *routeIndex) expired() bool {
+	return time.Since(idx.builtAt) > indexTTL
+}
+
+// match retorna a primeira rota correspondente ao caminho aceita por accept,
+// em ordem de precedência: caminho exato, caminho com parâmetros e curinga de
+// prefixo mais longo. Rotas de mesma precedência seguem a ordem do repositório.
+func (idx *routeIndex) match(path string, accept func(*model.Route) bool) *model.Route {
+	for _, route := range idx.exact[path] {
+		if accept(route) {
+			return route
+		}
+	}
+	if route := idx.params.match(path, false, accept); route != nil {
+		return route
+	}
+	return idx.prefixes.match(path, accept)
+}
+
+// segmentNode é um nó da árvore de segmentos das rotas com parâmetros
+type segmentNode struct {
+	static map[string]*segmentNode
+	param  *segmentNode
+	routes []*model.Route
+}
+
+// insert adiciona a rota no nó correspondente aos seus segmentos
+func (n *segmentNode) insert(path string, route *model.Route) {
+	node := n
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, ":") {
+			if node.param == nil {
+				node.param = &segmentNode{}
+			}
+			node = node.param
+			continue
+		}
+		if node.static == nil {
+			node.static = make(map[string]*segmentNode)
+		}
+		child, ok := node.static[segment]
+		if !ok {
+			child = &segmentNode{}
+			node.static[segment] = child
+		}
+		node = child
+	}
+	node.routes = append(node.routes, route)
+}
+
+// match consome um segmento de rest por nível, preferindo segmentos fixos a
+// parâmetros. done indica que todos os segmentos já foram consumidos.
+func (n *segmentNode) match(rest string, done bool, accept func(*model.Route) bool) *model.Route {
+	if done {
+		for _, route := range n.routes {
+			if accept(route) {
+				return route
+			}
+		}
+		return nil
+	}
+
+	segment, next, last := rest, "", true
+	if i := strings.IndexByte(rest, '/'); i >= 0 {
+		segment, next, last = rest[:i], rest[i+1:], false
+	}
+
+	if child, ok := n.static[segment]; ok {
+		if route := child.match(next, last, accept); route != nil {
+			return route
+		}
+	}
+	if n.param != nil {
+		return n.param.match(next, last, accept)
+	}
+	return nil
+}
+
+// radixNode é um nó da árvore radix de prefixos das rotas curinga. Como em
+// MatchRoutePath, o prefixo é comparado caractere a caractere.
+type radixNode struct {
+	prefix   string
+	children []*radixNode
+	routes   []*model.Route
+}
+
+// insert adiciona a rota no nó do prefixo, dividindo arestas quando necessário
+func (n *radixNode) insert(key string, route *model.Route) {
+	node := n
+	for key != "" {
+		child := node.child(key[0])
+		if child == nil {
+			node.children = append(node.children, &radixNode{prefix: key, routes: []*model.Route{route}})
+			return
+		}
+
+		common := 0
+		for common < len(key) && common < len(child.prefix) && key[common] == child.prefix[common] {
+			common++
+		}
+		if common < len(child.prefix) {
+			split := &radixNode{prefix: child.prefix[common:], children: child.children, routes: child.routes}
+			child.prefix = child.prefix[:common]
+			child.children = []*radixNode{split}
+			child.routes = nil
+		}
+		key = key[common:]
+		node = child
+	}
+	node.routes = append(node.routes, route)
+}
+
+// child retorna o filho cuja aresta começa com o byte informado
+func (n *radixNode) child(b byte) *radixNode {
+	for _, child := range n.children {
+		if child.prefix[0] == b {
+			return child
+		}
+	}
+	return nil
+}
+
+// match percorre os prefixos do caminho e avalia do mais longo ao mais curto
+func (n *radixNode) match(path string, accept func(*model.Route) bool) *model.Route {
+	var buf [16]*radixNode
+	matched := buf[:0]
+	if len(n.routes) > 0 {
+		matched = append(matched, n)
+	}
+
+	node, rest := n, path
+	for rest != "" {
+		child := node.child(rest[0])
+		if child == nil || !strings.HasPrefix(rest, child.prefix) {
+			break
+		}
+		rest = rest[len(child.prefix):]
+		node = child
+		if len(node.routes) > 0 {
+			matched = append(matched, node)
+		}
+	}
+
+	for i := len(matched) - 1; i >= 0; i-- {
+		for _, route := range matched[i].routes {
+			if accept(route) {
+				return route
+			}
+		}
+	}
+	return nil
+}
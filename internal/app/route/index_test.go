@@ -0,0 +1,182 @@
+package route
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+)
+
+func acceptAll(*model.Route) bool { return true }
+
+func newRoutes(paths ...string) []*model.Route {
+	routes := make([]*model.Route, len(paths))
+	for i, path := range paths {
+		routes[i] = &model.Route{Path: path}
+	}
+	return routes
+}
+
+func TestRouteIndexMatch(t *testing.T) {
+	idx := newRouteIndex(newRoutes(
+		"/health",
+		"/api/users",
+		"/api/users/:id",
+		"/api/users/:id/orders",
+		"/api/users/me",
+		"/api/*",
+		"/api/users/*",
+		"/static/*",
+		"/weather/:cep",
+	))
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/health", "/health"},
+		{"/api/users", "/api/users"},
+		{"/api/users/42", "/api/users/:id"},
+		{"/api/users/me", "/api/users/me"},
+		{"/api/users/42/orders", "/api/users/:id/orders"},
+		{"/api/users/42/orders/7", "/api/users/*"},
+		{"/api/products", "/api/*"},
+		{"/apiv2", "/api/*"}, // o prefixo é comparado caractere a caractere
+		{"/static/css/app.css", "/static/*"},
+		{"/weather/01001000", "/weather/:cep"},
+		{"/weather/01001000/extra", ""},
+		{"/unknown", ""},
+		{"/", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got := idx.match(tt.path, acceptAll)
+			if tt.want == "" {
+				if got != nil {
+					t.Fatalf("match(%q) = %q, esperado nenhuma rota", tt.path, got.Path)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatalf("match(%q) = nil, esperado %q", tt.path, tt.want)
+			}
+			if got.Path != tt.want {
+				t.Fatalf("match(%q) = %q, esperado %q", tt.path, got.Path, tt.want)
+			}
+			if !model.MatchRoutePath(got.Path, tt.path) {
+				t.Fatalf("MatchRoutePath(%q, %q) rejeita a rota escolhida pelo índice", got.Path, tt.path)
+			}
+		})
+	}
+}
+
+func TestRouteIndexMatchAccept(t *testing.T) {
+	routes := newRoutes("/api/orders", "/api/orders", "/api/*")
+	routes[0].Methods = []string{"GET"}
+	routes[1].Methods = []string{"POST"}
+	idx := newRouteIndex(routes)
+
+	post := func(r *model.Route) bool { return len(r.Methods) == 0 || r.Methods[0] == "POST" }
+	if got := idx.match("/api/orders", post); got != routes[1] {
+		t.Fatalf("match com filtro retornou %+v, esperada a segunda rota de mesmo caminho", got)
+	}
+
+	none := func(r *model.Route) bool { return strings.HasSuffix(r.Path, "/*") }
+	if got := idx.match("/api/orders", none); got != routes[2] {
+		t.Fatalf("match deveria recorrer ao curinga quando as rotas exatas são recusadas, retornou %+v", got)
+	}
+}
+
+// TestRouteIndexAgreesWithMatchRoutePath compara o índice com a busca linear
+// por MatchRoutePath em rotas e caminhos gerados aleatoriamente
+func TestRouteIndexAgreesWithMatchRoutePath(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	segments := []string{"api", "v1", "users", "orders", "items", "a", "ab"}
+	randomPath := func(param, wildcard bool) string {
+		n := 1 + rng.Intn(4)
+		parts := make([]string, n)
+		for i := range parts {
+			parts[i] = segments[rng.Intn(len(segments))]
+			if param && rng.Intn(3) == 0 {
+				parts[i] = ":p" + fmt.Sprint(i)
+			}
+		}
+		path := "/" + strings.Join(parts, "/")
+		if wildcard {
+			path += "/*"
+		}
+		return path
+	}
+
+	for round := 0; round < 50; round++ {
+		var paths []string
+		for i := 0; i < 40; i++ {
+			switch rng.Intn(3) {
+			case 0:
+				paths = append(paths, randomPath(false, false))
+			case 1:
+				paths = append(paths, randomPath(true, false))
+			default:
+				paths = append(paths, randomPath(false, true))
+			}
+		}
+		routes := newRoutes(paths...)
+		idx := newRouteIndex(routes)
+
+		for i := 0; i < 200; i++ {
+			request := randomPath(false, false)
+			got := idx.match(request, acceptAll)
+
+			matches := false
+			for _, route := range routes {
+				if model.MatchRoutePath(route.Path, request) {
+					matches = true
+					break
+				}
+			}
+
+			if got == nil {
+				if matches {
+					t.Fatalf("índice não encontrou rota para %q, mas MatchRoutePath encontra (rotas: %v)", request, paths)
+				}
+				continue
+			}
+			if !model.MatchRoutePath(got.Path, request) {
+				t.Fatalf("índice retornou %q para %q, recusada por MatchRoutePath", got.Path, request)
+			}
+		}
+	}
+}
+
+func BenchmarkRouteIndexMatch(b *testing.B) {
+	const services = 1000
+
+	var paths []string
+	for i := 0; i < services; i++ {
+		paths = append(paths,
+			fmt.Sprintf("/api/service%d/items", i),
+			fmt.Sprintf("/api/service%d/items/:id", i),
+			fmt.Sprintf("/api/service%d/items/:id/details", i),
+			fmt.Sprintf("/static/service%d/*", i),
+		)
+	}
+	idx := newRouteIndex(newRoutes(paths...))
+
+	requests := []string{
+		"/api/service500/items",
+		"/api/service999/items/123",
+		"/api/service1/items/abc/details",
+		"/static/service750/js/app.js",
+		"/not/found",
+	}
+
+	b.Logf("%d rotas indexadas", len(paths))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		idx.match(requests[i%len(requests)], acceptAll)
+	}
+}
@@ -0,0 +1,141 @@
+package route
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/pkg/encryption"
+	"go.uber.org/zap"
+)
+
+// routesCacheTTL é o tempo em que a lista de rotas em cache é considerada atual
+const routesCacheTTL = 5 * time.Minute
+
+// SetStaleWhileRevalidate permite servir a lista de rotas expirada por até d
+// enquanto ela é recarregada em segundo plano. Zero desativa.
+func (s *Service) SetStaleWhileRevalidate(d time.Duration) {
+	s.staleTTL = d
+}
+
+// SetCacheEnvelope define o envelope que cifra a lista de rotas gravada no
+// cache. Sem envelope, listas com segredos não são gravadas no cache.
+func (s *Service) SetCacheEnvelope(envelope *encryption.Envelope) {
+	s.envelope = envelope
+}
+
+// loadRoutes obtém a lista de rotas do cache ou, na ausência dela, do
+// repositório. Requisições simultâneas compartilham uma única carga do
+// repositório, evitando sobrecarga quando a chave expira sob tráfego. Indica
+// também se a lista veio do cache.
+func (s *Service) loadRoutes(ctx context.Context) ([]*model.Route, bool, error) {
+	cacheKey := scopedCacheKey(ctx, "routes")
+
+	routes, found, err := s.cachedRoutes(ctx, cacheKey)
+	if err != nil {
+		s.logger.Error("Erro ao buscar rotas do cache", zap.Error(err))
+		// Continuamos para buscar do repositório em caso de erro
+	} else if found {
+		if s.staleTTL > 0 && s.isStale(ctx, cacheKey) {
+			// Servir a lista expirada e recarregá-la em segundo plano
+			s.loads.DoChan(cacheKey, func() (interface{}, error) {
+				return s.refreshRoutes(context.WithoutCancel(ctx), cacheKey)
+			})
+		}
+		return routes, true, nil
+	}
+
+	// A carga não é cancelada se a requisição que a iniciou terminar, pois
+	// outras requisições podem estar aguardando o mesmo resultado
+	result, err, _ := s.loads.Do(cacheKey, func() (interface{}, error) {
+		return s.refreshRoutes(context.WithoutCancel(ctx), cacheKey)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return result.([]*model.Route), false, nil
+}
+
+// refreshRoutes busca as rotas no repositório e atualiza o cache
+func (s *Service) refreshRoutes(ctx context.Context, cacheKey string) ([]*model.Route, error) {
+	s.logger.Info("Lista de rotas não encontrada no cache, buscando do repositório")
+	routes, err := s.repo.GetRoutes(ctx)
+	if err != nil {
+		s.logger.Error("Erro ao buscar rotas do repositório", zap.Error(err))
+		if fallback, ok := s.snapshotFallback(ctx, cacheKey, err); ok {
+			return fallback, nil
+		}
+		return nil, err
+	}
+	s.saveSnapshot(ctx, routes)
+
+	// Com stale-while-revalidate, a lista permanece no cache além do prazo e
+	// um marcador separado indica até quando ela é atual
+	if err := s.cacheRoutes(ctx, cacheKey, routes, routesCacheTTL+s.staleTTL); err != nil {
+		s.logger.Warn("Erro ao armazenar rotas no cache", zap.Error(err))
+	}
+	if s.staleTTL > 0 {
+		if err := s.cache.Set(ctx, cacheKey+":fresh", true, routesCacheTTL); err != nil {
+			s.logger.Warn("Erro ao armazenar validade das rotas no cache", zap.Error(err))
+		}
+	}
+	return routes, nil
+}
+
+// cacheRoutes grava a lista de rotas no cache, que pode ser compartilhado entre
+// as instâncias. Com o envelope, a lista é gravada cifrada; sem ele, listas
+// com segredos (credenciais do upstream, chaves dos hooks) não são gravadas e
+// cada instância as carrega do repositório.
+func (s *Service) cacheRoutes(ctx context.Context, cacheKey string, routes []*model.Route, ttl time.Duration) error {
+	if s.envelope != nil {
+		data, err := json.Marshal(routes)
+		if err != nil {
+			return err
+		}
+		encrypted, err := s.envelope.Encrypt(data)
+		if err != nil {
+			return fmt.Errorf("falha ao cifrar rotas: %w", err)
+		}
+		return s.cache.Set(ctx, cacheKey, encrypted, ttl)
+	}
+
+	for _, r := range routes {
+		if r.HasSecrets() {
+			// Uma lista antiga não pode continuar sendo servida no lugar desta
+			return s.cache.Delete(ctx, cacheKey)
+		}
+	}
+	return s.cache.Set(ctx, cacheKey, routes, ttl)
+}
+
+// cachedRoutes lê a lista de rotas gravada por cacheRoutes
+func (s *Service) cachedRoutes(ctx context.Context, cacheKey string) ([]*model.Route, bool, error) {
+	var routes []*model.Route
+	if s.envelope == nil {
+		found, err := s.cache.Get(ctx, cacheKey, &routes)
+		return routes, found, err
+	}
+
+	var encrypted string
+	found, err := s.cache.Get(ctx, cacheKey, &encrypted)
+	if err != nil || !found {
+		return nil, found, err
+	}
+	data, err := s.envelope.Decrypt(encrypted)
+	if err != nil {
+		return nil, false, fmt.Errorf("falha ao decifrar rotas do cache: %w", err)
+	}
+	if err := json.Unmarshal(data, &routes); err != nil {
+		return nil, false, fmt.Errorf("rotas do cache inválidas: %w", err)
+	}
+	return routes, true, nil
+}
+
+// isStale indica se a lista de rotas em cache passou do prazo de validade
+func (s *Service) isStale(ctx context.Context, cacheKey string) bool {
+	var fresh bool
+	found, err := s.cache.Get(ctx, cacheKey+":fresh", &fresh)
+	return err == nil && !found
+}
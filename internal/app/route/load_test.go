@@ -0,0 +1,88 @@
+package route
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/pkg/cache"
+	"github.com/diillson/api-gateway-go/pkg/encryption"
+	"go.uber.org/zap"
+)
+
+func newTestEnvelope(t *testing.T) *encryption.Envelope {
+	t.Helper()
+	key := base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", 32)))
+	keyring, err := encryption.NewKeyring("k1", map[string]string{"k1": key})
+	if err != nil {
+		t.Fatalf("NewKeyring: %v", err)
+	}
+	envelope, err := encryption.NewEnvelope(keyring)
+	if err != nil {
+		t.Fatalf("NewEnvelope: %v", err)
+	}
+	return envelope
+}
+
+func secretRoutes() []*model.Route {
+	return []*model.Route{
+		{Path: "/api/publica", ServiceURL: "http://publica:8080"},
+		{
+			Path:         "/api/pagamentos",
+			ServiceURL:   "http://pagamentos:8080",
+			UpstreamAuth: &model.UpstreamAuth{Username: "gateway", Password: "senha-do-upstream"},
+		},
+	}
+}
+
+func TestCacheRoutesEncrypted(t *testing.T) {
+	ctx := context.Background()
+	c := cache.NewMemoryCache(time.Minute, time.Minute, nil, zap.NewNop())
+	s := NewService(nil, c, zap.NewNop())
+	s.SetCacheEnvelope(newTestEnvelope(t))
+
+	if err := s.cacheRoutes(ctx, "routes", secretRoutes(), time.Minute); err != nil {
+		t.Fatalf("cacheRoutes: %v", err)
+	}
+
+	var stored string
+	if found, err := c.Get(ctx, "routes", &stored); err != nil || !found {
+		t.Fatalf("lista não gravada no cache: %v", err)
+	}
+	if !encryption.IsEncrypted(stored) || strings.Contains(stored, "senha-do-upstream") {
+		t.Fatal("lista de rotas gravada no cache sem cifragem")
+	}
+
+	routes, found, err := s.cachedRoutes(ctx, "routes")
+	if err != nil || !found {
+		t.Fatalf("cachedRoutes = %v, %v", found, err)
+	}
+	if len(routes) != 2 || routes[1].UpstreamAuth.Password != "senha-do-upstream" {
+		t.Fatal("lista decifrada do cache difere da gravada")
+	}
+}
+
+func TestCacheRoutesWithoutEnvelope(t *testing.T) {
+	ctx := context.Background()
+	c := cache.NewMemoryCache(time.Minute, time.Minute, nil, zap.NewNop())
+	s := NewService(nil, c, zap.NewNop())
+
+	public := secretRoutes()[:1]
+	if err := s.cacheRoutes(ctx, "routes", public, time.Minute); err != nil {
+		t.Fatalf("cacheRoutes: %v", err)
+	}
+	if _, found, _ := s.cachedRoutes(ctx, "routes"); !found {
+		t.Fatal("lista sem segredos não foi gravada no cache")
+	}
+
+	// Uma lista com segredos não é gravada e remove a anterior
+	if err := s.cacheRoutes(ctx, "routes", secretRoutes(), time.Minute); err != nil {
+		t.Fatalf("cacheRoutes: %v", err)
+	}
+	if _, found, _ := s.cachedRoutes(ctx, "routes"); found {
+		t.Fatal("lista com segredos gravada no cache sem cifragem")
+	}
+}
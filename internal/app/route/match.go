@@ -0,0 +1,43 @@
+package route
+
+import (
+	"context"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// requestInfoKey é a chave de contexto dos dados da requisição em roteamento
+type requestInfoKey struct{}
+
+// WithRequestInfo associa ao contexto os dados da requisição. Com eles,
+// GetRouteByPath avalia as condições de host, método e cabeçalhos das rotas e
+// escolhe a variante correspondente; sem eles, apenas o caminho é considerado.
+func WithRequestInfo(ctx context.Context, info model.RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// requestInfoFromContext retorna os dados da requisição associados ao contexto
+func requestInfoFromContext(ctx context.Context) (model.RequestInfo, bool) {
+	info, ok := ctx.Value(requestInfoKey{}).(model.RequestInfo)
+	return info, ok
+}
+
+// matchesRequest indica se a rota atende às condições da requisição do contexto
+func matchesRequest(ctx context.Context, route *model.Route) bool {
+	info, ok := requestInfoFromContext(ctx)
+	return !ok || route.MatchesRequest(info)
+}
+
+// resolveForRequest aplica a variante da rota correspondente à requisição do contexto
+func resolveForRequest(ctx context.Context, route *model.Route, span trace.Span) *model.Route {
+	info, ok := requestInfoFromContext(ctx)
+	if !ok || len(route.Variants) == 0 {
+		return route
+	}
+
+	resolved, variant := route.ForRequest(info)
+	span.SetAttributes(attribute.Int("route.variant", variant))
+	return resolved
+}
@@ -6,64 +6,92 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/diillson/api-gateway-go/internal/app/audit"
 	"github.com/diillson/api-gateway-go/internal/domain/model"
 	"github.com/diillson/api-gateway-go/internal/domain/repository"
+	"github.com/diillson/api-gateway-go/internal/infra/healthcheck"
 	"github.com/diillson/api-gateway-go/pkg/cache"
+	"github.com/diillson/api-gateway-go/pkg/encryption"
+	"github.com/diillson/api-gateway-go/pkg/tenant"
 	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
 )
 
 type Service struct {
-	repo   repository.RouteRepository
-	cache  cache.Cache
-	logger *zap.Logger
+	repo    repository.RouteRepository
+	cache   cache.Cache
+	logger  *zap.Logger
+	tracer  trace.Tracer // delega ao provedor global, inclusive o configurado depois
+	events  *eventBus
+	health  *healthcheck.Registry
+	auditor *audit.Recorder
+
+	indexMu  sync.RWMutex
+	indexes  map[string]*routeIndex // escopo (tenant) -> índice de rotas
+	indexGen uint64                 // incrementado a cada invalidação
+
+	loads    singleflight.Group   // cargas de rotas em andamento, por escopo
+	staleTTL time.Duration        // tempo em que a lista expirada ainda é servida
+	snapshot *routeSnapshot       // cópia local das rotas; nil quando desabilitada
+	warmed   atomic.Bool          // rotas já carregadas por Warm
+	envelope *encryption.Envelope // cifra a lista de rotas no cache; nil não grava listas com segredos
 }
 
-func NewService(repo repository.RouteRepository, cache cache.Cache, logger *zap.Logger) *Service {
-	return &Service{
-		repo:   repo,
-		cache:  cache,
-		logger: logger,
+// scopedCacheKey isola as chaves de cache por tenant, evitando que rotas de um
+// tenant sejam servidas a outro quando o armazenamento é particionado
+func scopedCacheKey(ctx context.Context, key string) string {
+	if tenantID := tenant.FromContext(ctx); tenantID != "" {
+		return "tenant:" + tenantID + ":" + key
 	}
+	return key
 }
 
-// GetRoutes retorna todas as rotas ativas
-func (s *Service) GetRoutes(ctx context.Context) ([]*model.Route, error) {
-	var routes []*model.Route
-
-	// Tentar cache primeiro
-	cacheKey := "routes"
-	found, err := s.cache.Get(ctx, cacheKey, &routes)
-	if err != nil {
-		s.logger.Error("Erro ao buscar rotas do cache", zap.Error(err))
-		return nil, err
-	}
-
-	if found {
-		return routes, nil
+func NewService(repo repository.RouteRepository, cache cache.Cache, logger *zap.Logger) *Service {
+	return &Service{
+		repo:    repo,
+		cache:   cache,
+		logger:  logger,
+		tracer:  otel.Tracer("api-gateway.route.service"),
+		events:  newEventBus(),
+		indexes: make(map[string]*routeIndex),
 	}
+}
 
-	// Se não estiver no cache, buscar do repositório
-	routes, err = s.repo.GetRoutes(ctx)
-	if err != nil {
-		return nil, err
-	}
+// Subscribe assina os eventos de alteração de rotas feitas por este serviço.
+// A função retornada cancela a assinatura e fecha o canal.
+func (s *Service) Subscribe(buffer int) (<-chan Event, func()) {
+	return s.events.subscribe(buffer)
+}
 
-	// Armazenar no cache para futuras requisições
-	if err := s.cache.Set(ctx, cacheKey, routes, 5*time.Minute); err != nil {
-		s.logger.Warn("Erro ao armazenar rotas no cache", zap.Error(err))
+// publish notifica os assinantes sobre uma alteração de rota
+func (s *Service) publish(ctx context.Context, eventType EventType, path string, route *model.Route) {
+	dropped := s.events.publish(Event{
+		Type:   eventType,
+		Path:   path,
+		Route:  route,
+		Tenant: tenant.FromContext(ctx),
+		Time:   time.Now(),
+	})
+	if dropped > 0 {
+		s.logger.Warn("Assinantes lentos perderam evento de alteração de rota",
+			zap.String("path", path),
+			zap.Int("dropped", dropped))
 	}
+}
 
-	return routes, nil
+// GetRoutes retorna todas as rotas ativas
+func (s *Service) GetRoutes(ctx context.Context) ([]*model.Route, error) {
+	routes, _, err := s.loadRoutes(ctx)
+	return routes, err
 }
 
 func (s *Service) GetRouteByPath(ctx context.Context, path string) (*model.Route, error) {
-	// Obter o tracer atual do contexto
-	tracer := otel.GetTracerProvider().Tracer("api-gateway.route.service")
-
 	// Criar um span para esta operação
-	ctx, span := tracer.Start(
+	ctx, span := s.tracer.Start(
 		ctx,
 		"RouteService.GetRouteByPath",
 		trace.WithAttributes(
@@ -76,94 +104,57 @@ func (s *Service) GetRouteByPath(ctx context.Context, path string) (*model.Route
 	// Adicionar log para debug
 	s.logger.Info("Buscando rota", zap.String("path", path))
 
-	// Primeiro tentar cache individual da rota
-	var route *model.Route
-	routeCacheKey := "route:" + path
-
-	found, err := s.cache.Get(ctx, routeCacheKey, &route)
-	if err != nil {
-		s.logger.Error("Erro ao verificar cache individual de rota",
-			zap.String("path", path),
-			zap.Error(err))
-		// Continuamos a execução mesmo com erro no cache
-	} else if found {
-		// rota encontrada no cahe, adiciona log e trace
-		s.logger.Info("Rota encontrada no cache individual",
-			zap.String("path", path),
-			zap.String("serviceURL", route.ServiceURL),
-			zap.String("cache_key", routeCacheKey))
-
-		// Add attributes to the span
-		span.SetAttributes(
-			attribute.String("route.service_url", route.ServiceURL),
-			attribute.Bool("route.is_active", route.IsActive),
-			attribute.Bool("route.from_cache", true),
-			attribute.Bool("cache.hit", true),
-		)
-
-		span.SetStatus(codes.Ok, "rota encontrada no individual!")
-		return route, nil
-	}
-
-	// Se não estiver no cache individual, buscar da lista de rotas (que pode estar em cache)
-	var routes []*model.Route
+	// Usar o índice de rotas em memória, reconstruindo-o a partir da lista de
+	// rotas (que pode estar em cache) quando ausente ou expirado
+	scope := scopedCacheKey(ctx, "routes")
+	index, generation := s.currentIndex(scope)
+	if index == nil {
+		// Apenas uma requisição por escopo reconstrói o índice; as demais
+		// aguardam e reutilizam o resultado
+		result, err, shared := s.loads.Do("index:"+scope, func() (interface{}, error) {
+			routes, fromCache, err := s.loadRoutes(ctx)
+			if err != nil {
+				return nil, err
+			}
+			span.SetAttributes(attribute.Bool("routes_list.from_cache", fromCache))
 
-	// Tentar cache para a lista de rotas
-	cacheKey := "routes"
-	found, err = s.cache.Get(ctx, cacheKey, &routes)
-	if err != nil {
-		s.logger.Error("Erro ao buscar rotas do cache", zap.Error(err))
-		// Continuamos para buscar do repositório em caso de erro
-	} else if found {
-		s.logger.Debug("Lista de rotas encontrada no cache",
-			zap.Int("routes_count", len(routes)))
-		span.SetAttributes(attribute.Bool("routes_list.from_cache", true))
-	} else {
-		// Se não estiver no cache, buscar do repositório
-		s.logger.Info("Lista de rotas não encontrada no cache, buscando do repositório")
-		routes, err = s.repo.GetRoutes(ctx)
+			built := newRouteIndex(routes)
+			s.storeIndex(scope, built, generation)
+			return built, nil
+		})
 		if err != nil {
 			s.logger.Error("Erro ao buscar rotas do repositório", zap.Error(err))
 			span.SetStatus(codes.Error, "repository error")
 			span.SetAttributes(attribute.Bool("error", true))
 			return nil, err
 		}
-
-		// Armazenar no cache para futuras requisições
-		if err := s.cache.Set(ctx, cacheKey, routes, 5*time.Minute); err != nil {
-			s.logger.Warn("Erro ao armazenar rotas no cache", zap.Error(err))
-		}
-		span.SetAttributes(attribute.Bool("routes_list.from_cache", false))
+		index = result.(*routeIndex)
+		span.SetAttributes(
+			attribute.Bool("route_index.rebuilt", true),
+			attribute.Bool("route_index.shared", shared),
+		)
 	}
 
-	// Registrar a quantidade de rotas encontradas
-	span.SetAttributes(attribute.Int("routes.count", len(routes)))
-
-	// Percorrer todas as rotas e verificar correspondência
-	for _, r := range routes {
-		if model.MatchRoutePath(r.Path, path) {
-			s.logger.Info("Rota encontrada com correspondência de padrão",
-				zap.String("registeredPath", r.Path),
-				zap.String("requestPath", path),
-				zap.String("serviceURL", r.ServiceURL))
-
-			// Cache individual da rota para acesso mais rápido em requisições futuras
-			routeCacheKey := "route:" + path
-			if err := s.cache.Set(ctx, routeCacheKey, r, 5*time.Minute); err != nil {
-				s.logger.Warn("Erro ao armazenar rota no cache", zap.Error(err))
-			}
+	// Registrar a quantidade de rotas indexadas
+	span.SetAttributes(attribute.Int("routes.count", index.size))
 
-			// Adicionar informações de correspondência de padrões ao span
-			span.SetAttributes(
-				attribute.String("route.service_url", r.ServiceURL),
-				attribute.Bool("route.is_active", r.IsActive),
-				attribute.Bool("route.pattern_match", true),
-				attribute.String("route.registered_path", r.Path),
-			)
-			span.SetStatus(codes.Ok, "rota encontrada por correspondência de padrões")
+	r := index.match(path, func(r *model.Route) bool { return matchesRequest(ctx, r) })
+	if r != nil {
+		s.logger.Info("Rota encontrada com correspondência de padrão",
+			zap.String("registeredPath", r.Path),
+			zap.String("requestPath", path),
+			zap.String("serviceURL", r.ServiceURL))
 
-			return r, nil
-		}
+		// Adicionar informações de correspondência de padrões ao span
+		span.SetAttributes(
+			attribute.String("route.service_url", r.ServiceURL),
+			attribute.Bool("route.is_active", r.IsActive),
+			attribute.Bool("route.pattern_match", r.Path != path),
+			attribute.String("route.registered_path", r.Path),
+		)
+		span.SetStatus(codes.Ok, "rota encontrada por correspondência de padrões")
+
+		return resolveForRequest(ctx, r, span), nil
 	}
 
 	// Se não encontrou correspondência
@@ -173,10 +164,65 @@ func (s *Service) GetRouteByPath(ctx context.Context, path string) (*model.Route
 	return nil, repository.ErrRouteNotFound
 }
 
+// currentIndex retorna o índice de rotas do escopo, se ainda válido, e a
+// geração atual dos índices, usada por storeIndex
+func (s *Service) currentIndex(scope string) (*routeIndex, uint64) {
+	s.indexMu.RLock()
+	defer s.indexMu.RUnlock()
+	index, ok := s.indexes[scope]
+	if !ok || index.expired() {
+		return nil, s.indexGen
+	}
+	return index, s.indexGen
+}
+
+// storeIndex guarda o índice de rotas do escopo. Um índice construído antes de
+// uma invalidação é descartado, pois pode conter rotas desatualizadas.
+func (s *Service) storeIndex(scope string, index *routeIndex, generation uint64) {
+	s.indexMu.Lock()
+	defer s.indexMu.Unlock()
+	if generation != s.indexGen {
+		return
+	}
+	if _, ok := s.indexes[scope]; !ok && len(s.indexes) >= maxIndexes {
+		s.evictIndexes()
+	}
+	s.indexes[scope] = index
+}
+
+// evictIndexes abre espaço para um novo índice, descartando os expirados ou,
+// se nenhum expirou, o mais antigo. Deve ser chamado com indexMu bloqueado.
+func (s *Service) evictIndexes() {
+	oldest := ""
+	for scope, index := range s.indexes {
+		if index.expired() {
+			delete(s.indexes, scope)
+			continue
+		}
+		if oldest == "" || index.builtAt.Before(s.indexes[oldest].builtAt) {
+			oldest = scope
+		}
+	}
+	if len(s.indexes) >= maxIndexes {
+		delete(s.indexes, oldest)
+	}
+}
+
+// invalidateIndex descarta o índice de rotas do escopo, que é reconstruído na
+// próxima busca
+func (s *Service) invalidateIndex(ctx context.Context) {
+	s.indexMu.Lock()
+	defer s.indexMu.Unlock()
+	delete(s.indexes, scopedCacheKey(ctx, "routes"))
+	s.indexGen++
+}
+
 // ClearCache limpa o cache de rotas
 func (s *Service) ClearCache(ctx context.Context) error {
+	s.invalidateIndex(ctx)
+
 	// Limpar cache de rotas
-	if err := s.cache.Delete(ctx, "routes"); err != nil {
+	if err := s.cache.Delete(ctx, scopedCacheKey(ctx, "routes")); err != nil {
 		s.logger.Error("Erro ao limpar cache de rotas", zap.Error(err))
 		return err
 	}
@@ -189,7 +235,7 @@ func (s *Service) ClearCache(ctx context.Context) error {
 	}
 
 	for _, route := range routes {
-		cacheKey := "route:" + route.Path
+		cacheKey := scopedCacheKey(ctx, "route:"+route.Path)
 		if err := s.cache.Delete(ctx, cacheKey); err != nil {
 			s.logger.Warn("Erro ao limpar cache de rota",
 				zap.String("path", route.Path),
@@ -197,59 +243,81 @@ func (s *Service) ClearCache(ctx context.Context) error {
 		}
 	}
 
+	s.audit(ctx, model.AuditCacheClear, "routes", nil, nil)
 	s.logger.Info("Cache de rotas limpo com sucesso")
 	return nil
 }
 
-// AddRoute adiciona uma nova rota
+// AddRoute adiciona uma nova rota no tenant do contexto. Retorna
+// repository.ErrRouteExists quando o caminho já está cadastrado no tenant e
+// ErrPathConflict quando é equivalente a outro.
 func (s *Service) AddRoute(ctx context.Context, route *model.Route) error {
+	route.Tenant = tenant.FromContext(ctx)
+	if err := s.checkPathConflict(ctx, route, false); err != nil {
+		return err
+	}
 	if err := s.repo.AddRoute(ctx, route); err != nil {
 		return err
 	}
 
 	// Invalidar cache de rotas
-	if err := s.cache.Delete(ctx, "routes"); err != nil {
+	if err := s.cache.Delete(ctx, scopedCacheKey(ctx, "routes")); err != nil {
 		s.logger.Warn("Erro ao invalidar cache de rotas", zap.Error(err))
 	}
 
+	s.invalidateIndex(ctx)
+	s.publish(ctx, EventCreated, route.Path, route)
+	s.audit(ctx, model.AuditRouteCreate, route.Path, nil, route)
 	return nil
 }
 
 // UpdateRoute atualiza uma rota existente
 func (s *Service) UpdateRoute(ctx context.Context, route *model.Route) error {
+	route.Tenant = tenant.FromContext(ctx)
+	if err := s.checkPathConflict(ctx, route, true); err != nil {
+		return err
+	}
+	before := s.auditBefore(ctx, route.Path)
 	if err := s.repo.UpdateRoute(ctx, route); err != nil {
 		return err
 	}
 
 	// Invalidar caches
-	cacheKey := "route:" + route.Path
+	cacheKey := scopedCacheKey(ctx, "route:"+route.Path)
 	if err := s.cache.Delete(ctx, cacheKey); err != nil {
 		s.logger.Warn("Erro ao invalidar cache de rota", zap.Error(err))
 	}
 
-	if err := s.cache.Delete(ctx, "routes"); err != nil {
+	if err := s.cache.Delete(ctx, scopedCacheKey(ctx, "routes")); err != nil {
 		s.logger.Warn("Erro ao invalidar cache de rotas", zap.Error(err))
 	}
 
+	s.invalidateIndex(ctx)
+	s.publish(ctx, EventUpdated, route.Path, route)
+	s.audit(ctx, model.AuditRouteUpdate, route.Path, before, route)
 	return nil
 }
 
 // DeleteRoute remove uma rota
 func (s *Service) DeleteRoute(ctx context.Context, path string) error {
+	before := s.auditBefore(ctx, path)
 	if err := s.repo.DeleteRoute(ctx, path); err != nil {
 		return err
 	}
 
 	// Invalidar caches
-	cacheKey := "route:" + path
+	cacheKey := scopedCacheKey(ctx, "route:"+path)
 	if err := s.cache.Delete(ctx, cacheKey); err != nil {
 		s.logger.Warn("Erro ao invalidar cache de rota", zap.Error(err))
 	}
 
-	if err := s.cache.Delete(ctx, "routes"); err != nil {
+	if err := s.cache.Delete(ctx, scopedCacheKey(ctx, "routes")); err != nil {
 		s.logger.Warn("Erro ao invalidar cache de rotas", zap.Error(err))
 	}
 
+	s.invalidateIndex(ctx)
+	s.publish(ctx, EventDeleted, path, nil)
+	s.audit(ctx, model.AuditRouteDelete, path, before, nil)
 	return nil
 }
 
@@ -0,0 +1,56 @@
+package route
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestStoreIndexBounded(t *testing.T) {
+	s := NewService(nil, nil, zap.NewNop())
+
+	for i := 0; i < maxIndexes*2; i++ {
+		s.storeIndex(fmt.Sprintf("tenant:t%d:routes", i), newRouteIndex(nil), 0)
+	}
+	if len(s.indexes) != maxIndexes {
+		t.Fatalf("%d índices em memória, esperado o limite de %d", len(s.indexes), maxIndexes)
+	}
+	if _, ok := s.indexes[fmt.Sprintf("tenant:t%d:routes", maxIndexes*2-1)]; !ok {
+		t.Fatal("o índice mais recente foi descartado")
+	}
+}
+
+func TestStoreIndexEvictsExpiredFirst(t *testing.T) {
+	s := NewService(nil, nil, zap.NewNop())
+
+	for i := 0; i < maxIndexes; i++ {
+		index := newRouteIndex(nil)
+		if i%2 == 0 {
+			index.builtAt = time.Now().Add(-2 * indexTTL)
+		}
+		s.storeIndex(fmt.Sprintf("tenant:t%d:routes", i), index, 0)
+	}
+	s.storeIndex("routes", newRouteIndex(nil), 0)
+
+	if len(s.indexes) != maxIndexes/2+1 {
+		t.Fatalf("%d índices em memória, esperados apenas os válidos", len(s.indexes))
+	}
+	for scope, index := range s.indexes {
+		if index.expired() {
+			t.Fatalf("índice expirado %s mantido", scope)
+		}
+	}
+}
+
+func TestStoreIndexDiscardsStaleGeneration(t *testing.T) {
+	s := NewService(nil, nil, zap.NewNop())
+
+	_, generation := s.currentIndex("routes")
+	s.indexGen++
+	s.storeIndex("routes", newRouteIndex(nil), generation)
+	if index, _ := s.currentIndex("routes"); index != nil {
+		t.Fatal("índice construído antes da invalidação foi guardado")
+	}
+}
@@ -0,0 +1,218 @@
+package route
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/pkg/encryption"
+	"github.com/diillson/api-gateway-go/pkg/tenant"
+	"go.uber.org/zap"
+)
+
+// snapshotRetryTTL é o tempo em que a lista do snapshot fica em cache antes de
+// uma nova tentativa de carregar as rotas do repositório
+const snapshotRetryTTL = 30 * time.Second
+
+// routeSnapshot é a cópia local da última lista de rotas carregada do
+// repositório, usada quando o repositório está indisponível
+type routeSnapshot struct {
+	path     string
+	envelope *encryption.Envelope // cifra o arquivo; nil grava em texto claro
+
+	mu       sync.Mutex
+	digest   [sha256.Size]byte // conteúdo da última gravação, evitando regravar a mesma lista
+	routes   []*model.Route    // lista lida do arquivo, reutilizada nas falhas seguintes
+	loadedAt time.Time         // data de gravação do arquivo lido
+}
+
+// SetSnapshot habilita a cópia local das rotas no arquivo informado. Com o
+// envelope, o arquivo é cifrado, pois as rotas podem conter segredos.
+func (s *Service) SetSnapshot(path string, envelope *encryption.Envelope) {
+	if path == "" {
+		s.snapshot = nil
+		return
+	}
+	s.snapshot = &routeSnapshot{path: path, envelope: envelope}
+}
+
+// Warm carrega as rotas do repositório (ou do snapshot) no cache e constrói o
+// índice em memória, para que as primeiras requisições não aguardem a carga.
+// Retorna a quantidade de rotas carregadas.
+func (s *Service) Warm(ctx context.Context) (int, error) {
+	cacheKey := scopedCacheKey(ctx, "routes")
+	_, generation := s.currentIndex(cacheKey)
+
+	result, err, _ := s.loads.Do(cacheKey, func() (interface{}, error) {
+		return s.refreshRoutes(context.WithoutCancel(ctx), cacheKey)
+	})
+	if err != nil {
+		return 0, err
+	}
+	routes := result.([]*model.Route)
+	s.storeIndex(cacheKey, newRouteIndex(routes), generation)
+	s.warmed.Store(true)
+	return len(routes), nil
+}
+
+// WarmUntilReady repete Warm a cada intervalo até que as rotas sejam
+// carregadas ou o contexto seja cancelado
+func (s *Service) WarmUntilReady(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		count, err := s.Warm(ctx)
+		if err != nil {
+			s.logger.Warn("Rotas ainda não carregadas; nova tentativa agendada",
+				zap.Duration("interval", interval), zap.Error(err))
+			continue
+		}
+		s.logger.Info("Rotas carregadas; instância pronta", zap.Int("routes", count))
+		return
+	}
+}
+
+// Warmed indica se as rotas já foram carregadas por Warm
+func (s *Service) Warmed() bool {
+	return s.warmed.Load()
+}
+
+// saveSnapshot grava a lista de rotas no snapshot. Apenas o escopo global é
+// gravado; as rotas dos tenants ficam nos respectivos armazenamentos.
+func (s *Service) saveSnapshot(ctx context.Context, routes []*model.Route) {
+	if s.snapshot == nil || tenant.FromContext(ctx) != "" {
+		return
+	}
+	if err := s.snapshot.save(routes); err != nil {
+		s.logger.Warn("Falha ao gravar snapshot das rotas",
+			zap.String("path", s.snapshot.path), zap.Error(err))
+	}
+}
+
+// snapshotFallback retorna as rotas do snapshot quando o repositório falha
+func (s *Service) snapshotFallback(ctx context.Context, cacheKey string, repoErr error) ([]*model.Route, bool) {
+	if s.snapshot == nil || tenant.FromContext(ctx) != "" {
+		return nil, false
+	}
+	routes, savedAt, err := s.snapshot.load()
+	if err != nil {
+		if !os.IsNotExist(err) {
+			s.logger.Error("Falha ao ler snapshot das rotas",
+				zap.String("path", s.snapshot.path), zap.Error(err))
+		}
+		return nil, false
+	}
+
+	s.logger.Warn("Repositório de rotas indisponível; servindo as rotas do snapshot local",
+		zap.String("path", s.snapshot.path),
+		zap.Time("savedAt", savedAt),
+		zap.Int("routes", len(routes)),
+		zap.Error(repoErr))
+
+	// Sem o marcador de validade, o repositório é consultado de novo assim que
+	// a lista expira
+	if err := s.cacheRoutes(ctx, cacheKey, routes, snapshotRetryTTL); err != nil {
+		s.logger.Warn("Erro ao armazenar rotas do snapshot no cache", zap.Error(err))
+	}
+	return routes, true
+}
+
+// save grava a lista de forma atômica, por um arquivo temporário renomeado
+func (r *routeSnapshot) save(routes []*model.Route) error {
+	data, err := json.Marshal(routes)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	digest := sha256.Sum256(data)
+	if digest == r.digest {
+		return nil
+	}
+
+	content := data
+	if r.envelope != nil {
+		encrypted, err := r.envelope.Encrypt(data)
+		if err != nil {
+			return fmt.Errorf("falha ao cifrar snapshot: %w", err)
+		}
+		content = []byte(encrypted)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(r.path), filepath.Base(r.path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), r.path); err != nil {
+		return err
+	}
+
+	r.digest = digest
+	r.routes = nil // a próxima falha relê a lista gravada
+	return nil
+}
+
+// load lê a lista do snapshot, uma única vez enquanto o arquivo não é regravado
+func (r *routeSnapshot) load() ([]*model.Route, time.Time, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.routes != nil {
+		return r.routes, r.loadedAt, nil
+	}
+
+	info, err := os.Stat(r.path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	content, err := os.ReadFile(r.path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	if r.envelope != nil {
+		if content, err = r.envelope.Decrypt(string(content)); err != nil {
+			return nil, time.Time{}, fmt.Errorf("falha ao decifrar snapshot: %w", err)
+		}
+	}
+
+	var routes []*model.Route
+	if err := json.Unmarshal(content, &routes); err != nil {
+		return nil, time.Time{}, fmt.Errorf("snapshot inválido: %w", err)
+	}
+	if routes == nil {
+		routes = []*model.Route{}
+	}
+
+	r.routes = routes
+	r.loadedAt = info.ModTime()
+	return routes, r.loadedAt, nil
+}
@@ -0,0 +1,340 @@
+// Package routestats mantém as estatísticas de cada rota em janelas de tempo
+// (1m, 5m e 1h): volume, erros, classes de status e percentis de latência.
+// Cada instância conta em memória por minuto e grava periodicamente no banco;
+// as consultas combinam as estatísticas de todas as instâncias.
+package routestats
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/internal/domain/repository"
+	"github.com/diillson/api-gateway-go/internal/infra/metrics"
+	"github.com/diillson/api-gateway-go/pkg/config"
+	"go.uber.org/zap"
+)
+
+// memoryRetention é o tempo mantido em memória, suficiente para a maior janela
+const memoryRetention = time.Hour + time.Minute
+
+// pruneInterval é o intervalo mínimo entre as remoções das estatísticas
+// antigas do banco
+const pruneInterval = 10 * time.Minute
+
+// Window é uma janela de tempo das estatísticas
+type Window struct {
+	Name     string
+	Duration time.Duration
+}
+
+// Windows são as janelas retornadas nas consultas
+var Windows = []Window{
+	{Name: "1m", Duration: time.Minute},
+	{Name: "5m", Duration: 5 * time.Minute},
+	{Name: "1h", Duration: time.Hour},
+}
+
+// latencyBounds são os limites superiores das faixas do histograma de
+// latência: de 1ms a 2min, crescendo 25% por faixa. A última faixa acumula as
+// requisições acima do maior limite.
+var latencyBounds = func() []time.Duration {
+	var bounds []time.Duration
+	for bound := float64(time.Millisecond); bound < float64(2*time.Minute); bound *= 1.25 {
+		bounds = append(bounds, time.Duration(bound))
+	}
+	return append(bounds, 2*time.Minute)
+}()
+
+// Report são as estatísticas de uma rota em cada janela
+type Report struct {
+	Route       string        `json:"route"`
+	Tenant      string        `json:"tenant,omitempty"`
+	GeneratedAt time.Time     `json:"generatedAt"`
+	Windows     []WindowStats `json:"windows"`
+}
+
+// WindowStats são as estatísticas de uma rota em uma janela. A janela começa
+// no início do minuto, incluindo o minuto em andamento.
+type WindowStats struct {
+	Window            string           `json:"window"`
+	From              time.Time        `json:"from"`
+	Requests          int64            `json:"requests"`
+	RequestsPerSecond float64          `json:"requestsPerSecond"`
+	Errors            int64            `json:"errors"`
+	ErrorRate         float64          `json:"errorRate"` // Fração das requisições com status 5xx
+	Status            map[string]int64 `json:"status"`
+	Latency           LatencyStats     `json:"latencyMs"`
+}
+
+// LatencyStats são a média e os percentis de latência, em milissegundos
+type LatencyStats struct {
+	Avg float64 `json:"avg"`
+	P50 float64 `json:"p50"`
+	P95 float64 `json:"p95"`
+	P99 float64 `json:"p99"`
+}
+
+type bucketKey struct {
+	tenant, route string
+	minute        time.Time
+}
+
+// Service conta as requisições das rotas e responde às consultas por janela
+type Service struct {
+	repo          repository.RouteStatsRepository
+	node          string
+	flushInterval time.Duration
+	retention     time.Duration
+	logger        *zap.Logger
+
+	mu        sync.Mutex
+	buckets   map[bucketKey]*model.RouteStatsBucket
+	dirty     map[bucketKey]bool
+	lastPrune time.Time
+}
+
+// NewService cria o serviço de estatísticas da instância informada. Sem
+// repositório, as estatísticas ficam apenas em memória.
+func NewService(repo repository.RouteStatsRepository, node string, cfg config.RouteStatsConfig, logger *zap.Logger) *Service {
+	return &Service{
+		repo:          repo,
+		node:          node,
+		flushInterval: cfg.FlushInterval,
+		retention:     cfg.Retention,
+		logger:        logger,
+		buckets:       make(map[bucketKey]*model.RouteStatsBucket),
+		dirty:         make(map[bucketKey]bool),
+	}
+}
+
+// Record contabiliza uma requisição atendida pela rota
+func (s *Service) Record(tenant, route string, status int, duration time.Duration) {
+	key := bucketKey{tenant: tenant, route: route, minute: time.Now().UTC().Truncate(time.Minute)}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &model.RouteStatsBucket{
+			Node:    s.node,
+			Tenant:  tenant,
+			Route:   route,
+			Minute:  key.minute,
+			Status:  make(map[string]int64),
+			Latency: make([]int64, len(latencyBounds)+1),
+		}
+		s.buckets[key] = bucket
+	}
+
+	bucket.Requests++
+	if status >= 500 {
+		bucket.Errors++
+	}
+	bucket.Duration += duration
+	bucket.Status[metrics.StatusClass(status)]++
+	bucket.Latency[latencyBucket(duration)]++
+	s.dirty[key] = true
+}
+
+// Run grava periodicamente as estatísticas no banco até o contexto ser cancelado
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Flush(ctx)
+		}
+	}
+}
+
+// Flush grava no banco as estatísticas alteradas desde a última gravação e
+// descarta as que saíram das janelas
+func (s *Service) Flush(ctx context.Context) {
+	now := time.Now().UTC()
+
+	s.mu.Lock()
+	changed := make([]model.RouteStatsBucket, 0, len(s.dirty))
+	for key := range s.dirty {
+		changed = append(changed, copyBucket(s.buckets[key]))
+	}
+	s.dirty = make(map[bucketKey]bool)
+	s.mu.Unlock()
+
+	if s.repo != nil && len(changed) > 0 {
+		if err := s.repo.SaveBuckets(ctx, changed); err != nil {
+			s.logger.Error("Falha ao gravar estatísticas das rotas", zap.Error(err))
+			// As estatísticas serão gravadas na próxima vez
+			s.mu.Lock()
+			for _, bucket := range changed {
+				s.dirty[bucketKey{tenant: bucket.Tenant, route: bucket.Route, minute: bucket.Minute}] = true
+			}
+			s.mu.Unlock()
+		}
+	}
+
+	s.mu.Lock()
+	for key := range s.buckets {
+		if now.Sub(key.minute) > memoryRetention && !s.dirty[key] {
+			delete(s.buckets, key)
+		}
+	}
+	prune := s.repo != nil && now.Sub(s.lastPrune) >= pruneInterval
+	if prune {
+		s.lastPrune = now
+	}
+	s.mu.Unlock()
+
+	if prune {
+		if err := s.repo.DeleteBefore(ctx, now.Add(-s.retention)); err != nil {
+			s.logger.Warn("Falha ao remover estatísticas antigas das rotas", zap.Error(err))
+		}
+	}
+}
+
+// Report retorna as estatísticas da rota em cada janela, somando as de todas
+// as instâncias gravadas no banco às desta instância
+func (s *Service) Report(ctx context.Context, tenant, route string) (*Report, error) {
+	now := time.Now().UTC()
+	since := now.Add(-Windows[len(Windows)-1].Duration).Truncate(time.Minute)
+
+	var stored []model.RouteStatsBucket
+	if s.repo != nil {
+		var err error
+		if stored, err = s.repo.ListBuckets(ctx, tenant, route, since); err != nil {
+			return nil, err
+		}
+	}
+
+	var buckets []model.RouteStatsBucket
+	local := make(map[time.Time]bool)
+	s.mu.Lock()
+	for key, bucket := range s.buckets {
+		if key.tenant == tenant && key.route == route && !key.minute.Before(since) {
+			buckets = append(buckets, copyBucket(bucket))
+			local[key.minute] = true
+		}
+	}
+	s.mu.Unlock()
+
+	// Os minutos em memória desta instância são mais recentes que os gravados;
+	// os demais vêm do banco, inclusive os gravados antes de um reinício
+	for _, bucket := range stored {
+		if bucket.Node != s.node || !local[bucket.Minute] {
+			buckets = append(buckets, bucket)
+		}
+	}
+
+	report := &Report{Route: route, Tenant: tenant, GeneratedAt: now}
+	for _, window := range Windows {
+		report.Windows = append(report.Windows, summarize(window, now, buckets))
+	}
+	return report, nil
+}
+
+// summarize soma as estatísticas dos minutos da janela
+func summarize(window Window, now time.Time, buckets []model.RouteStatsBucket) WindowStats {
+	from := now.Add(-window.Duration).Truncate(time.Minute)
+	stats := WindowStats{Window: window.Name, From: from, Status: make(map[string]int64)}
+
+	var duration time.Duration
+	latency := make([]int64, len(latencyBounds)+1)
+	for _, bucket := range buckets {
+		if bucket.Minute.Before(from) {
+			continue
+		}
+		stats.Requests += bucket.Requests
+		stats.Errors += bucket.Errors
+		duration += bucket.Duration
+		for class, count := range bucket.Status {
+			stats.Status[class] += count
+		}
+		// Faixas gravadas com outro histograma são ignoradas
+		if len(bucket.Latency) == len(latency) {
+			for i, count := range bucket.Latency {
+				latency[i] += count
+			}
+		}
+	}
+
+	if elapsed := now.Sub(from).Seconds(); elapsed > 0 {
+		stats.RequestsPerSecond = round(float64(stats.Requests) / elapsed)
+	}
+	if stats.Requests > 0 {
+		stats.ErrorRate = round(float64(stats.Errors) / float64(stats.Requests))
+		stats.Latency = LatencyStats{
+			Avg: milliseconds(duration / time.Duration(stats.Requests)),
+			P50: milliseconds(percentile(latency, 0.50)),
+			P95: milliseconds(percentile(latency, 0.95)),
+			P99: milliseconds(percentile(latency, 0.99)),
+		}
+	}
+	return stats
+}
+
+// latencyBucket retorna a faixa do histograma da duração
+func latencyBucket(duration time.Duration) int {
+	for i, bound := range latencyBounds {
+		if duration <= bound {
+			return i
+		}
+	}
+	return len(latencyBounds)
+}
+
+// percentile estima o percentil do histograma, interpolando dentro da faixa
+func percentile(counts []int64, q float64) time.Duration {
+	var total int64
+	for _, count := range counts {
+		total += count
+	}
+	if total == 0 {
+		return 0
+	}
+
+	rank := q * float64(total)
+	var cumulative int64
+	for i, count := range counts {
+		if count == 0 || float64(cumulative+count) < rank {
+			cumulative += count
+			continue
+		}
+		if i == len(latencyBounds) {
+			// Acima do maior limite não há como interpolar
+			return latencyBounds[len(latencyBounds)-1]
+		}
+		var lower time.Duration
+		if i > 0 {
+			lower = latencyBounds[i-1]
+		}
+		fraction := (rank - float64(cumulative)) / float64(count)
+		return lower + time.Duration(fraction*float64(latencyBounds[i]-lower))
+	}
+	return latencyBounds[len(latencyBounds)-1]
+}
+
+func copyBucket(bucket *model.RouteStatsBucket) model.RouteStatsBucket {
+	copied := *bucket
+	copied.Status = make(map[string]int64, len(bucket.Status))
+	for class, count := range bucket.Status {
+		copied.Status[class] = count
+	}
+	copied.Latency = append([]int64(nil), bucket.Latency...)
+	return copied
+}
+
+func milliseconds(d time.Duration) float64 {
+	return round(float64(d) / float64(time.Millisecond))
+}
+
+// round limita os valores a três casas decimais
+func round(value float64) float64 {
+	return math.Round(value*1000) / 1000
+}
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// featureFlagName aceita os nomes de flag usuais dos provedores (ex.: checkout-v2)
+var featureFlagName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.:-]{0,127}$`)
+
+// RouteFeatureFlag condiciona a rota a uma flag do provedor de feature flags
+// do gateway (flags), avaliada para o consumidor de cada requisição. Sem a
+// flag, a rota responde como não registrada.
+type RouteFeatureFlag struct {
+	Name     string // Nome da flag no provedor
+	Fallback bool   // Resultado usado quando o provedor não está configurado ou não responde
+}
+
+// Validate verifica o nome da flag
+func (f *RouteFeatureFlag) Validate() error {
+	if !featureFlagName.MatchString(f.Name) {
+		return fmt.Errorf("featureFlag.name inválido: %q", f.Name)
+	}
+	return nil
+}
+
+// validateActivation verifica as datas da ativação agendada da rota
+func (r *Route) validateActivation() error {
+	from, err := parseRouteDate(r.ActiveFrom)
+	if err != nil {
+		return fmt.Errorf("activeFrom inválido: %q (use RFC3339 ou 2006-01-02)", r.ActiveFrom)
+	}
+	until, err := parseRouteDate(r.ActiveUntil)
+	if err != nil {
+		return fmt.Errorf("activeUntil inválido: %q (use RFC3339 ou 2006-01-02)", r.ActiveUntil)
+	}
+	if !from.IsZero() && !until.IsZero() && !until.After(from) {
+		return errors.New("activeUntil deve ser posterior a activeFrom")
+	}
+	if r.FeatureFlag != nil {
+		return r.FeatureFlag.Validate()
+	}
+	return nil
+}
+
+// ActiveAt indica se o instante está dentro da ativação agendada da rota
+// (activeFrom inclusivo, activeUntil exclusivo). Não considera IsActive.
+func (r *Route) ActiveAt(now time.Time) bool {
+	if from, _ := parseRouteDate(r.ActiveFrom); !from.IsZero() && now.Before(from) {
+		return false
+	}
+	if until, _ := parseRouteDate(r.ActiveUntil); !until.IsZero() && !now.Before(until) {
+		return false
+	}
+	return true
+}
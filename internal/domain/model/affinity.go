@@ -0,0 +1,62 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Origens da chave de afinidade de sessão
+const (
+	AffinityCookie   = "cookie"
+	AffinityHeader   = "header"
+	AffinityClientIP = "ip"
+)
+
+// SessionAffinity mantém cada cliente no mesmo alvo da rota, escolhido por
+// hash consistente de uma chave da requisição. Quando um alvo fica
+// indisponível, apenas os clientes dele são redistribuídos.
+type SessionAffinity struct {
+	Source string // Origem da chave: "cookie", "header" ou "ip"
+	Name   string // Nome do cookie ou do cabeçalho
+	TTL    string // Validade do cookie criado pelo gateway; vazio dura a sessão do navegador
+}
+
+// CookieTTL retorna a validade do cookie de afinidade; zero indica cookie de sessão
+func (a *SessionAffinity) CookieTTL() time.Duration {
+	return parseDurationOr(a.TTL, 0)
+}
+
+// validateAffinity verifica a afinidade de sessão da rota
+func (r *Route) validateAffinity() error {
+	a := r.SessionAffinity
+	if r.Canary != nil {
+		return errors.New("sessionAffinity não pode ser combinada com canary; use canary.stickyHeader ou canary.stickyCookie")
+	}
+
+	switch a.Source {
+	case AffinityCookie, AffinityHeader:
+		if a.Name == "" {
+			return fmt.Errorf("sessionAffinity.name é obrigatório com source %q", a.Source)
+		}
+		if !isToken(a.Name) {
+			return fmt.Errorf("sessionAffinity.name inválido: %q", a.Name)
+		}
+	case AffinityClientIP:
+		if a.Name != "" {
+			return errors.New("sessionAffinity.name não se aplica com source \"ip\"")
+		}
+	default:
+		return fmt.Errorf("sessionAffinity.source inválido: %q", a.Source)
+	}
+
+	if a.TTL != "" {
+		if a.Source != AffinityCookie {
+			return errors.New("sessionAffinity.ttl aplica-se apenas com source \"cookie\"")
+		}
+		if d, err := time.ParseDuration(a.TTL); err != nil || d <= 0 {
+			return fmt.Errorf("sessionAffinity.ttl inválido: %q", a.TTL)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package model
+
+import "time"
+
+// APIKey representa a chave de um consumidor máquina-a-máquina. Apenas o hash
+// da chave é armazenado; o valor completo é exibido somente na criação.
+type APIKey struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`           // Início da chave, para identificá-la sem expor o segredo
+	Routes     []string   `json:"routes,omitempty"` // Rotas permitidas; vazio permite todas
+	Tier       string     `json:"tier,omitempty"`   // Faixa de rate limit configurada em auth.apiKeys.tiers
+	Revoked    bool       `json:"revoked"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+// AllowsRoute indica se a chave pode acessar a rota informada
+func (k *APIKey) AllowsRoute(path string) bool {
+	if len(k.Routes) == 0 {
+		return true
+	}
+	for _, route := range k.Routes {
+		if route == path {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValid indica se a chave não foi revogada nem expirou
+func (k *APIKey) IsValid(now time.Time) bool {
+	return !k.Revoked && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// APIKeyEntity é a representação de banco de dados de uma chave de API
+type APIKeyEntity struct {
+	ID         string `gorm:"primaryKey;type:varchar(36)"`
+	Name       string `gorm:"not null;size:100"`
+	Prefix     string `gorm:"not null;size:16"`
+	KeyHash    string `gorm:"uniqueIndex;not null;size:64"` // HMAC-SHA256 da chave com o pepper (SHA-256 sem pepper), em hexadecimal
+	RoutesJSON string `gorm:"column:routes;type:text"`
+	Tier       string `gorm:"size:50"`
+	Revoked    bool   `gorm:"default:false"`
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+}
+
+// TableName define o nome da tabela
+func (APIKeyEntity) TableName() string {
+	return "api_keys"
+}
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Ações registradas no log de auditoria
+const (
+	AuditRouteCreate      = "route.create"
+	AuditRouteUpdate      = "route.update"
+	AuditRouteDelete      = "route.delete"
+	AuditCacheClear       = "cache.clear"
+	AuditAPIKeyRevoke     = "apikey.revoke"
+	AuditCredentialRevoke = "credential.revoke"
+	AuditTokenRevoke      = "token.revoke"
+	AuditConfigReload     = "config.reload"
+)
+
+// AuditRecord é o registro imutável de uma operação administrativa
+type AuditRecord struct {
+	ID       string                 `json:"id"`
+	Time     time.Time              `json:"time"`
+	Action   string                 `json:"action"`
+	Resource string                 `json:"resource"`          // Alvo da operação (ex.: caminho da rota, ID da chave)
+	ActorID  string                 `json:"actorId,omitempty"` // ID do usuário autenticado
+	Actor    string                 `json:"actor"`             // Nome do usuário ou processo (ex.: system:routefile)
+	SourceIP string                 `json:"sourceIp,omitempty"`
+	Before   json.RawMessage        `json:"before,omitempty"`
+	After    json.RawMessage        `json:"after,omitempty"`
+	Changes  map[string]AuditChange `json:"changes,omitempty"` // Campos alterados entre Before e After
+}
+
+// AuditChange é a alteração de um campo
+type AuditChange struct {
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+}
+
+// AuditEntity é a representação de banco de dados de um registro de auditoria
+type AuditEntity struct {
+	ID          string    `gorm:"primaryKey;type:varchar(36)"`
+	Time        time.Time `gorm:"column:occurred_at;not null"`
+	Action      string    `gorm:"not null;size:50"`
+	Resource    string    `gorm:"size:255"`
+	ActorID     string    `gorm:"size:36"`
+	Actor       string    `gorm:"size:100"`
+	SourceIP    string    `gorm:"size:45"`
+	BeforeJSON  string    `gorm:"column:before_state;type:text"`
+	AfterJSON   string    `gorm:"column:after_state;type:text"`
+	ChangesJSON string    `gorm:"column:changes;type:text"`
+}
+
+// TableName define o nome da tabela
+func (AuditEntity) TableName() string {
+	return "audit_log"
+}
@@ -0,0 +1,100 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// AuthorizationPolicy define o que o consumidor autenticado precisa ter para
+// acessar a rota. Todas as exigências informadas precisam ser atendidas.
+type AuthorizationPolicy struct {
+	RequiredRoles  []string            // Papéis aceitos; basta um deles
+	RequiredScopes []string            // Escopos exigidos (claims scope ou scp); todos são necessários
+	RequiredClaims map[string][]string // Valores aceitos por claim; cada claim precisa ter um deles
+}
+
+// Evaluate decide se o consumidor com o papel e as claims informados pode
+// acessar a rota. Quando o acesso é negado, retorna o motivo.
+func (p *AuthorizationPolicy) Evaluate(role string, claims map[string]interface{}) (bool, string) {
+	if len(p.RequiredRoles) > 0 {
+		roles := append([]string{role}, claimValues(claims["roles"])...)
+		if !containsAnyValue(roles, p.RequiredRoles) {
+			return false, fmt.Sprintf("papel exigido: %s", strings.Join(p.RequiredRoles, " ou "))
+		}
+	}
+
+	if len(p.RequiredScopes) > 0 {
+		scopes := tokenScopes(claims)
+		for _, required := range p.RequiredScopes {
+			if !containsAnyValue(scopes, []string{required}) {
+				return false, fmt.Sprintf("escopo exigido: %s", required)
+			}
+		}
+	}
+
+	for name, accepted := range p.RequiredClaims {
+		if !containsAnyValue(claimValues(claims[name]), accepted) {
+			return false, fmt.Sprintf("claim %s sem valor aceito", name)
+		}
+	}
+
+	return true, ""
+}
+
+// Validate verifica a consistência da política
+func (p *AuthorizationPolicy) Validate() error {
+	if len(p.RequiredRoles) == 0 && len(p.RequiredScopes) == 0 && len(p.RequiredClaims) == 0 {
+		return errors.New("authorization requer ao menos um papel, escopo ou claim")
+	}
+	for name, values := range p.RequiredClaims {
+		if name == "" || len(values) == 0 {
+			return fmt.Errorf("authorization.requiredClaims[%s] requer ao menos um valor", name)
+		}
+	}
+	return nil
+}
+
+// tokenScopes lê os escopos das claims scope (texto separado por espaços,
+// RFC 8693) e scp (lista ou texto, usada por alguns provedores)
+func tokenScopes(claims map[string]interface{}) []string {
+	var scopes []string
+	for _, name := range []string{"scope", "scp"} {
+		for _, value := range claimValues(claims[name]) {
+			scopes = append(scopes, strings.Fields(value)...)
+		}
+	}
+	return scopes
+}
+
+// claimValues converte o valor de uma claim em lista de textos
+func claimValues(value interface{}) []string {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			values = append(values, fmt.Sprint(item))
+		}
+		return values
+	default:
+		return []string{fmt.Sprint(v)}
+	}
+}
+
+// containsAnyValue indica se algum dos valores aceitos está na lista
+func containsAnyValue(values, accepted []string) bool {
+	for _, want := range accepted {
+		for _, value := range values {
+			if value == want {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/diillson/api-gateway-go/pkg/reqtemplate"
+)
+
+// DefaultBodyTransformMaxBytes é o tamanho máximo de corpo transformado quando
+// MaxBytes não é informado
+const DefaultBodyTransformMaxBytes int64 = 1 << 20
+
+// BodyTransforms adapta corpos JSON entre clientes e backends. Cada direção é
+// um template text/template cuja saída deve ser JSON válido, com o corpo
+// original em .Body e os dados da requisição (.Claims, .Params, .Headers,
+// .Consumer, .Tenant, .Labels). A função json serializa valores.
+type BodyTransforms struct {
+	Request  *BodyTransform // Aplicada ao corpo enviado ao backend
+	Response *BodyTransform // Aplicada ao corpo devolvido ao cliente
+}
+
+// BodyTransform é a transformação de uma direção; corpos que não são JSON
+// seguem sem alterações
+type BodyTransform struct {
+	Template string // ex.: {"customerId": {{ json .Body.cliente.id }}}
+	MaxBytes int64  // Tamanho máximo do corpo; 0 usa 1 MB
+}
+
+// Validate verifica se os templates compilam
+func (t *BodyTransforms) Validate() error {
+	if t.Request == nil && t.Response == nil {
+		return errors.New("bodyTransforms requer request ou response")
+	}
+	if t.Request != nil {
+		if err := t.Request.validate("bodyTransforms.request"); err != nil {
+			return err
+		}
+	}
+	if t.Response != nil {
+		if err := t.Response.validate("bodyTransforms.response"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *BodyTransform) validate(field string) error {
+	if t.Template == "" {
+		return fmt.Errorf("%s.template é obrigatório", field)
+	}
+	if t.MaxBytes < 0 {
+		return fmt.Errorf("%s.maxBytes não pode ser negativo", field)
+	}
+	if _, err := reqtemplate.CompileBody(t.Template); err != nil {
+		return fmt.Errorf("%s: %w", field, err)
+	}
+	return nil
+}
+
+// Limit retorna o tamanho máximo do corpo transformado
+func (t *BodyTransform) Limit() int64 {
+	if t.MaxBytes > 0 {
+		return t.MaxBytes
+	}
+	return DefaultBodyTransformMaxBytes
+}
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// defaultBulkheadQueueTimeout é a espera padrão por uma vaga na fila
+const defaultBulkheadQueueTimeout = time.Second
+
+// BulkheadPolicy limita as requisições simultâneas encaminhadas pela rota e a
+// cada alvo, para que um backend lento não esgote as conexões e goroutines do
+// gateway. Acima do limite, as requisições aguardam em uma fila limitada.
+type BulkheadPolicy struct {
+	MaxConcurrent int    // Requisições simultâneas na rota; zero sem limite
+	MaxPerTarget  int    // Requisições simultâneas em cada alvo; zero sem limite
+	MaxQueue      int    // Requisições aguardando vaga; zero rejeita imediatamente
+	QueueTimeout  string // Espera máxima na fila (padrão "1s")
+}
+
+// Validate verifica a consistência da política
+func (p *BulkheadPolicy) Validate() error {
+	if p.MaxConcurrent < 0 || p.MaxPerTarget < 0 || p.MaxQueue < 0 {
+		return errors.New("bulkhead não aceita limites negativos")
+	}
+	if p.MaxConcurrent == 0 && p.MaxPerTarget == 0 {
+		return errors.New("bulkhead requer maxConcurrent ou maxPerTarget")
+	}
+	if p.QueueTimeout != "" {
+		d, err := time.ParseDuration(p.QueueTimeout)
+		if err != nil {
+			return fmt.Errorf("bulkhead.queueTimeout inválido: %w", err)
+		}
+		if d <= 0 {
+			return errors.New("bulkhead.queueTimeout deve ser positivo")
+		}
+	}
+	return nil
+}
+
+// QueueTimeoutDuration retorna a espera máxima na fila
+func (p *BulkheadPolicy) QueueTimeoutDuration() time.Duration {
+	return parseDurationOr(p.QueueTimeout, defaultBulkheadQueueTimeout)
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// defaultCanaryStickyTTL é a validade padrão do cookie de atribuição do canary
+const defaultCanaryStickyTTL = 24 * time.Hour
+
+// CanaryRouting distribui o tráfego da rota entre versões do backend segundo
+// o peso de cada alvo, permitindo liberar uma nova versão gradualmente. Com
+// atribuição fixa, o mesmo cliente permanece na mesma versão.
+type CanaryRouting struct {
+	StickyHeader string // Cabeçalho cujo valor fixa o cliente em uma versão (ex.: "X-User-ID")
+	StickyCookie string // Cookie que guarda a versão atribuída ao cliente
+	StickyTTL    string // Validade do cookie de atribuição (padrão "24h")
+}
+
+// StickyTTLDuration retorna a validade do cookie de atribuição
+func (c *CanaryRouting) StickyTTLDuration() time.Duration {
+	return parseDurationOr(c.StickyTTL, defaultCanaryStickyTTL)
+}
+
+// validateCanary verifica a configuração canary contra os alvos da rota
+func (r *Route) validateCanary() error {
+	c := r.Canary
+	if len(r.Targets) < 2 {
+		return errors.New("canary requer ao menos dois targets")
+	}
+
+	versions := make(map[string]bool, len(r.Targets))
+	for i, target := range r.Targets {
+		if target.Version == "" {
+			return fmt.Errorf("targets[%d]: version é obrigatória com canary", i)
+		}
+		if versions[target.Version] {
+			return fmt.Errorf("targets[%d]: versão duplicada %q", i, target.Version)
+		}
+		versions[target.Version] = true
+	}
+
+	if c.StickyHeader != "" && !isToken(c.StickyHeader) {
+		return fmt.Errorf("canary.stickyHeader inválido: %q", c.StickyHeader)
+	}
+	if c.StickyCookie != "" && !isToken(c.StickyCookie) {
+		return fmt.Errorf("canary.stickyCookie inválido: %q", c.StickyCookie)
+	}
+	if c.StickyTTL != "" {
+		d, err := time.ParseDuration(c.StickyTTL)
+		if err != nil {
+			return fmt.Errorf("canary.stickyTTL inválido: %w", err)
+		}
+		if d <= 0 {
+			return errors.New("canary.stickyTTL deve ser positivo")
+		}
+	}
+	return nil
+}
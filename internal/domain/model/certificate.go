@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// CertificateEntity armazena os dados do ACME (certificados, chave da conta e
+// desafios em andamento), compartilhados entre as instâncias do gateway
+type CertificateEntity struct {
+	Name      string `gorm:"primaryKey;size:255"` // Chave usada pelo autocert
+	Data      string `gorm:"type:text;not null"`  // Conteúdo PEM gravado pelo autocert
+	UpdatedAt time.Time
+}
+
+// TableName define o nome da tabela
+func (CertificateEntity) TableName() string {
+	return "acme_certificates"
+}
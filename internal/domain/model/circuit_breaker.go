@@ -0,0 +1,58 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Valores padrão do circuit breaker por rota
+const (
+	defaultCircuitCoolDown = 30 * time.Second
+	defaultCircuitWindow   = time.Minute
+)
+
+// CircuitBreakerPolicy configura o circuit breaker entre o gateway e os backends
+// da rota. Cada alvo da rota tem seu próprio circuito.
+type CircuitBreakerPolicy struct {
+	ConsecutiveFailures int     // Falhas consecutivas que abrem o circuito (padrão 5)
+	ErrorRate           float64 // Percentual de falhas na janela que abre o circuito; zero desativa
+	MinimumRequests     int     // Requisições mínimas na janela antes de avaliar ErrorRate (padrão 20)
+	Window              string  // Janela de contagem da taxa de erro (ex.: "1m")
+	CoolDown            string  // Tempo com o circuito aberto antes de testar o backend (ex.: "30s")
+	HalfOpenRequests    int     // Requisições de teste permitidas após o cool-down (padrão 1)
+}
+
+// WindowDuration retorna a janela de contagem da taxa de erro
+func (p *CircuitBreakerPolicy) WindowDuration() time.Duration {
+	return parseDurationOr(p.Window, defaultCircuitWindow)
+}
+
+// CoolDownDuration retorna o tempo em que o circuito permanece aberto
+func (p *CircuitBreakerPolicy) CoolDownDuration() time.Duration {
+	return parseDurationOr(p.CoolDown, defaultCircuitCoolDown)
+}
+
+// Validate verifica a consistência da configuração
+func (p *CircuitBreakerPolicy) Validate() error {
+	if p.ConsecutiveFailures < 0 || p.MinimumRequests < 0 || p.HalfOpenRequests < 0 {
+		return errors.New("circuitBreaker: limiares não podem ser negativos")
+	}
+	if p.ErrorRate < 0 || p.ErrorRate > 100 {
+		return fmt.Errorf("circuitBreaker.errorRate deve estar entre 0 e 100: %v", p.ErrorRate)
+	}
+
+	for name, value := range map[string]string{"window": p.Window, "coolDown": p.CoolDown} {
+		if value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("circuitBreaker.%s inválido: %w", name, err)
+		}
+		if d <= 0 {
+			return fmt.Errorf("circuitBreaker.%s deve ser positivo", name)
+		}
+	}
+	return nil
+}
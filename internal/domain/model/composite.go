@@ -0,0 +1,122 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/diillson/api-gateway-go/pkg/reqtemplate"
+)
+
+// Valores padrão das rotas de composição
+const (
+	defaultCompositeTimeout          = 10 * time.Second
+	DefaultCompositeMaxResponseBytes = 1 << 20
+	maxCompositeCalls                = 16
+)
+
+// compositeCallName restringe os nomes das chamadas aos aceitos em .Body.nome
+var compositeCallName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// CompositeRoute faz da rota um backend-for-frontend: o gateway chama os
+// backends em paralelo e combina as respostas JSON em um único corpo. Sem
+// Template, a resposta é um objeto com o corpo de cada chamada pelo nome.
+type CompositeRoute struct {
+	Calls            []CompositeCall // Chamadas feitas em paralelo aos backends
+	Template         string          // Corpo da resposta, com as respostas em .Body pelo nome da chamada (ex.: {"user": {{ json .Body.user }}})
+	Timeout          string          // Tempo máximo de todas as chamadas (padrão "10s")
+	MaxResponseBytes int64           // Tamanho máximo do corpo de cada resposta (padrão 1MB)
+}
+
+// CompositeCall é uma chamada a um backend da composição
+type CompositeCall struct {
+	Name     string            // Nome da resposta no template; letras, números e _, sem começar por número
+	URL      string            // URL do backend; aceita expressões (ex.: http://users/users/{{ .Params.id }})
+	Method   string            // Método HTTP (padrão GET)
+	Headers  map[string]string // Cabeçalhos enviados; aceitam expressões
+	Optional bool              // Falhas resultam em null no lugar da resposta, sem recusar a requisição
+}
+
+// Validate verifica as chamadas e o template da composição
+func (c *CompositeRoute) Validate() error {
+	if len(c.Calls) == 0 {
+		return errors.New("composite.calls requer ao menos uma chamada")
+	}
+	if len(c.Calls) > maxCompositeCalls {
+		return fmt.Errorf("composite.calls aceita no máximo %d chamadas", maxCompositeCalls)
+	}
+	names := make(map[string]bool, len(c.Calls))
+	for i := range c.Calls {
+		call := &c.Calls[i]
+		if err := call.validate(); err != nil {
+			return fmt.Errorf("composite.calls[%d]: %w", i, err)
+		}
+		if names[call.Name] {
+			return fmt.Errorf("composite.calls: nome %q repetido", call.Name)
+		}
+		names[call.Name] = true
+	}
+
+	if c.Template != "" {
+		if _, err := reqtemplate.CompileBody(c.Template); err != nil {
+			return fmt.Errorf("composite.template: %w", err)
+		}
+	}
+	if c.Timeout != "" {
+		if d, err := time.ParseDuration(c.Timeout); err != nil || d <= 0 {
+			return fmt.Errorf("composite.timeout inválido: %q", c.Timeout)
+		}
+	}
+	if c.MaxResponseBytes < 0 {
+		return errors.New("composite.maxResponseBytes não pode ser negativo")
+	}
+	return nil
+}
+
+func (c *CompositeCall) validate() error {
+	if !compositeCallName.MatchString(c.Name) {
+		return fmt.Errorf("name inválido: %q", c.Name)
+	}
+	if !strings.HasPrefix(c.URL, "http://") && !strings.HasPrefix(c.URL, "https://") {
+		return fmt.Errorf("url deve começar com http:// ou https://: %q", c.URL)
+	}
+	if _, err := reqtemplate.Compile(c.URL); err != nil {
+		return fmt.Errorf("url: %w", err)
+	}
+	if c.Method != "" && !validMethods[c.Method] {
+		return fmt.Errorf("método HTTP inválido: %q", c.Method)
+	}
+	for name, value := range c.Headers {
+		if name == "" {
+			return errors.New("headers não aceita nomes vazios")
+		}
+		if _, err := reqtemplate.Compile(value); err != nil {
+			return fmt.Errorf("headers.%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// HTTPMethod retorna o método da chamada
+func (c *CompositeCall) HTTPMethod() string {
+	if c.Method == "" {
+		return http.MethodGet
+	}
+	return c.Method
+}
+
+// TimeoutDuration retorna o tempo máximo das chamadas da composição
+func (c *CompositeRoute) TimeoutDuration() time.Duration {
+	return parseDurationOr(c.Timeout, defaultCompositeTimeout)
+}
+
+// ResponseLimit retorna o tamanho máximo do corpo de cada resposta
+func (c *CompositeRoute) ResponseLimit() int64 {
+	if c.MaxResponseBytes > 0 {
+		return c.MaxResponseBytes
+	}
+	return DefaultCompositeMaxResponseBytes
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"errors"
+	"strings"
+)
+
+// CompressionPolicy ajusta, para a rota, a compressão das respostas
+// configurada em server.compression
+type CompressionPolicy struct {
+	Enabled      *bool    // Habilita ou desabilita a compressão na rota; nil segue o global
+	MinSize      int      // Tamanho mínimo da resposta, em bytes; zero segue o global
+	ContentTypes []string // Tipos MIME comprimidos; vazio segue o global
+}
+
+// Validate verifica a consistência da política
+func (p *CompressionPolicy) Validate() error {
+	if p.MinSize < 0 {
+		return errors.New("compression.minSize não pode ser negativo")
+	}
+	for _, contentType := range p.ContentTypes {
+		if strings.TrimSpace(contentType) == "" {
+			return errors.New("compression.contentTypes não aceita valores vazios")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+	"time"
+)
+
+// Modos de HTTP/2 nas conexões com os backends
+const (
+	HTTP2Enabled   = "enabled"  // Negocia HTTP/2 pelo ALPN com backends https
+	HTTP2Disabled  = "disabled" // Apenas HTTP/1.1
+	HTTP2Cleartext = "h2c"      // HTTP/2 sem TLS com backends http (prior knowledge)
+)
+
+// ConnectionPool ajusta as conexões entre o gateway e os backends da rota.
+// Campos vazios usam os valores de upstream.transport.
+type ConnectionPool struct {
+	MaxIdleConnsPerHost int    // Conexões ociosas mantidas por backend
+	MaxConnsPerHost     int    // Conexões por backend, incluindo as em uso; zero sem limite
+	IdleConnTimeout     string // Tempo até fechar uma conexão ociosa
+	TLSHandshakeTimeout string // Tempo máximo do handshake TLS
+	DialTimeout         string // Tempo máximo para abrir a conexão
+	KeepAlive           string // Intervalo das sondas TCP keep-alive
+	DisableKeepAlives   bool   // Abre uma conexão por requisição
+	HTTP2               string // "enabled", "disabled" ou "h2c"; vazio usa upstream.transport.forceAttemptHTTP2
+	// Servidores DNS (ip ou ip:porta) que resolvem os backends da rota;
+	// vazio usa upstream.resolver.servers
+	DNSServers []string
+}
+
+// Validate verifica a consistência da configuração
+func (p *ConnectionPool) Validate() error {
+	if p.MaxIdleConnsPerHost < 0 || p.MaxConnsPerHost < 0 {
+		return errors.New("connectionPool não aceita limites negativos")
+	}
+	durations := map[string]string{
+		"idleConnTimeout":     p.IdleConnTimeout,
+		"tlsHandshakeTimeout": p.TLSHandshakeTimeout,
+		"dialTimeout":         p.DialTimeout,
+		"keepAlive":           p.KeepAlive,
+	}
+	for name, value := range durations {
+		if value == "" {
+			continue
+		}
+		if d, err := time.ParseDuration(value); err != nil || d <= 0 {
+			return fmt.Errorf("connectionPool.%s inválido: %q", name, value)
+		}
+	}
+	switch p.HTTP2 {
+	case "", HTTP2Enabled, HTTP2Disabled, HTTP2Cleartext:
+	default:
+		return fmt.Errorf("connectionPool.http2 inválido: %q", p.HTTP2)
+	}
+	for _, server := range p.DNSServers {
+		server = strings.TrimSpace(server)
+		if _, err := netip.ParseAddr(strings.Trim(server, "[]")); err == nil {
+			continue
+		}
+		if _, err := netip.ParseAddrPort(server); err != nil {
+			return fmt.Errorf("connectionPool.dnsServers: servidor inválido: %q", server)
+		}
+	}
+	return nil
+}
+
+// ResolverServers retorna os servidores DNS da rota, ou nil para os padrões
+func (p *ConnectionPool) ResolverServers() []string {
+	if p == nil {
+		return nil
+	}
+	return p.DNSServers
+}
+
+// H2C indica se a rota fala HTTP/2 sem TLS com os backends http
+func (p *ConnectionPool) H2C() bool {
+	return p != nil && p.HTTP2 == HTTP2Cleartext
+}
+
+// IdleConnTimeoutOr retorna o tempo até fechar uma conexão ociosa. Como os
+// demais métodos *Or, aceita configuração nil e usa fallback nos campos vazios.
+func (p *ConnectionPool) IdleConnTimeoutOr(fallback time.Duration) time.Duration {
+	if p == nil {
+		return fallback
+	}
+	return parseDurationOr(p.IdleConnTimeout, fallback)
+}
+
+// TLSHandshakeTimeoutOr retorna o tempo máximo do handshake TLS
+func (p *ConnectionPool) TLSHandshakeTimeoutOr(fallback time.Duration) time.Duration {
+	if p == nil {
+		return fallback
+	}
+	return parseDurationOr(p.TLSHandshakeTimeout, fallback)
+}
+
+// DialTimeoutOr retorna o tempo máximo para abrir a conexão
+func (p *ConnectionPool) DialTimeoutOr(fallback time.Duration) time.Duration {
+	if p == nil {
+		return fallback
+	}
+	return parseDurationOr(p.DialTimeout, fallback)
+}
+
+// KeepAliveOr retorna o intervalo das sondas TCP keep-alive
+func (p *ConnectionPool) KeepAliveOr(fallback time.Duration) time.Duration {
+	if p == nil {
+		return fallback
+	}
+	return parseDurationOr(p.KeepAlive, fallback)
+}
@@ -0,0 +1,110 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// CORSPolicy controla o acesso à rota por páginas de outras origens. O
+// gateway responde aos preflights (OPTIONS) e define os cabeçalhos CORS das
+// respostas, substituindo os enviados pelo backend.
+type CORSPolicy struct {
+	AllowOrigins     []string // Origens permitidas; "*" aceita todas e "https://*.example.com" os subdomínios
+	AllowMethods     []string // Métodos permitidos; vazio usa os métodos da rota
+	AllowHeaders     []string // Cabeçalhos permitidos; vazio ou "*" aceita os solicitados no preflight
+	ExposeHeaders    []string // Cabeçalhos da resposta expostos ao JavaScript
+	AllowCredentials bool     // Permite cookies e credenciais nas requisições
+	MaxAge           int      // Validade do preflight no navegador, em segundos; zero não informa
+}
+
+// Validate verifica a consistência da política
+func (p *CORSPolicy) Validate() error {
+	if len(p.AllowOrigins) == 0 {
+		return errors.New("cors.allowOrigins é obrigatório")
+	}
+	for _, origin := range p.AllowOrigins {
+		if origin == "*" {
+			if p.AllowCredentials {
+				return errors.New("cors.allowOrigins \"*\" não pode ser combinado com allowCredentials")
+			}
+			continue
+		}
+		u, err := url.Parse(strings.Replace(origin, "*.", "wildcard.", 1))
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.Path != "" ||
+			strings.Count(origin, "*") > 1 || (strings.Contains(origin, "*") && !strings.Contains(origin, "://*.")) {
+			return fmt.Errorf("cors.allowOrigins inválida: %q (use esquema://host[:porta], com * apenas no início do host)", origin)
+		}
+	}
+	for _, method := range p.AllowMethods {
+		if !validMethods[strings.ToUpper(method)] {
+			return fmt.Errorf("cors.allowMethods contém método inválido: %q", method)
+		}
+	}
+	if p.MaxAge < 0 {
+		return errors.New("cors.maxAge não pode ser negativo")
+	}
+	return nil
+}
+
+// AllowsOrigin indica se a origem é permitida
+func (p *CORSPolicy) AllowsOrigin(origin string) bool {
+	origin = strings.ToLower(origin)
+	for _, allowed := range p.AllowOrigins {
+		allowed = strings.ToLower(allowed)
+		if allowed == "*" || allowed == origin {
+			return true
+		}
+		// https://*.example.com aceita https://api.example.com, mas não https://example.com
+		if prefix, suffix, ok := strings.Cut(allowed, "*"); ok &&
+			strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) &&
+			len(origin) > len(prefix)+len(suffix) &&
+			!strings.ContainsAny(origin[len(prefix):len(origin)-len(suffix)], "/:") {
+			return true
+		}
+	}
+	return false
+}
+
+// AnyOrigin indica se todas as origens são permitidas
+func (p *CORSPolicy) AnyOrigin() bool {
+	for _, allowed := range p.AllowOrigins {
+		if allowed == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// Methods retorna os métodos permitidos; sem allowMethods, os da rota
+func (p *CORSPolicy) Methods(routeMethods []string) []string {
+	if len(p.AllowMethods) == 0 {
+		return routeMethods
+	}
+	methods := make([]string, len(p.AllowMethods))
+	for i, method := range p.AllowMethods {
+		methods[i] = strings.ToUpper(method)
+	}
+	return methods
+}
+
+// AllowsHeaders indica se os cabeçalhos solicitados no preflight são permitidos
+func (p *CORSPolicy) AllowsHeaders(requested []string) bool {
+	if len(p.AllowHeaders) == 0 {
+		return true
+	}
+	allowed := make(map[string]bool, len(p.AllowHeaders))
+	for _, header := range p.AllowHeaders {
+		if header == "*" {
+			return true
+		}
+		allowed[strings.ToLower(header)] = true
+	}
+	for _, header := range requested {
+		if !allowed[strings.ToLower(header)] {
+			return false
+		}
+	}
+	return true
+}
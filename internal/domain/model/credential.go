@@ -0,0 +1,65 @@
+package model
+
+import "time"
+
+// Tipos de credencial de parceiros
+const (
+	CredentialBasic = "basic" // Usuário e senha enviados no HTTP Basic
+	CredentialHMAC  = "hmac"  // Chave compartilhada que assina as requisições
+)
+
+// Credential é a credencial de um parceiro que não usa JWT nem OAuth. A senha
+// das credenciais basic é armazenada apenas como hash; o segredo das
+// credenciais hmac precisa ser recuperável para validar as assinaturas e é
+// cifrado quando a criptografia em repouso está ativa. Os segredos são
+// exibidos somente na criação.
+type Credential struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Type       string     `json:"type"`             // "basic" ou "hmac"
+	Identifier string     `json:"identifier"`       // Usuário (basic) ou ID da chave de assinatura (hmac)
+	Routes     []string   `json:"routes,omitempty"` // Rotas permitidas; vazio permite todas
+	Revoked    bool       `json:"revoked"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+// AllowsRoute indica se a credencial pode acessar a rota informada
+func (c *Credential) AllowsRoute(path string) bool {
+	if len(c.Routes) == 0 {
+		return true
+	}
+	for _, route := range c.Routes {
+		if route == path {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValid indica se a credencial não foi revogada nem expirou
+func (c *Credential) IsValid(now time.Time) bool {
+	return !c.Revoked && (c.ExpiresAt == nil || now.Before(*c.ExpiresAt))
+}
+
+// CredentialEntity é a representação de banco de dados de uma credencial
+type CredentialEntity struct {
+	ID         string `gorm:"primaryKey;type:varchar(36)"`
+	Name       string `gorm:"not null;size:100"`
+	Type       string `gorm:"not null;size:16"`
+	Identifier string `gorm:"uniqueIndex;not null;size:100"`
+	Secret     string `gorm:"type:text;not null"` // Hash bcrypt (basic) ou segredo, cifrado quando a criptografia está ativa (hmac)
+	RoutesJSON string `gorm:"column:routes;type:text"`
+	Revoked    bool   `gorm:"default:false"`
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+}
+
+// TableName define o nome da tabela
+func (CredentialEntity) TableName() string {
+	return "credentials"
+}
@@ -0,0 +1,105 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// routeDateLayouts são os formatos aceitos nas datas das rotas (descontinuação
+// e ativação agendada), em UTC
+var routeDateLayouts = []string{time.RFC3339, "2006-01-02T15:04", "2006-01-02"}
+
+// RouteDeprecation marca a rota como descontinuada. O gateway informa a
+// descontinuação aos clientes nos cabeçalhos Deprecation, Sunset e Link e
+// registra o uso por consumidor; com Enforce, a rota deixa de ser atendida
+// após a data de desativação.
+type RouteDeprecation struct {
+	Since     string // Data da descontinuação (RFC3339 ou "2006-01-02"); vazio considera a rota já descontinuada
+	Sunset    string // Data prevista para a desativação da rota
+	Link      string // Documentação da descontinuação ou do guia de migração
+	Successor string // Rota ou URL que substitui a descontinuada
+	Enforce   bool   // Responde 410 Gone após a data de desativação
+}
+
+// Validate verifica as datas e os links da descontinuação
+func (d *RouteDeprecation) Validate() error {
+	since, err := parseRouteDate(d.Since)
+	if err != nil {
+		return fmt.Errorf("deprecation.since inválido: %q", d.Since)
+	}
+	sunset, err := parseRouteDate(d.Sunset)
+	if err != nil {
+		return fmt.Errorf("deprecation.sunset inválido: %q", d.Sunset)
+	}
+	if !since.IsZero() && !sunset.IsZero() && sunset.Before(since) {
+		return errors.New("deprecation.sunset deve ser posterior a deprecation.since")
+	}
+	if d.Enforce && sunset.IsZero() {
+		return errors.New("deprecation.enforce requer deprecation.sunset")
+	}
+	if err := validateDeprecationLink(d.Link); err != nil {
+		return fmt.Errorf("deprecation.link: %w", err)
+	}
+	if err := validateDeprecationLink(d.Successor); err != nil {
+		return fmt.Errorf("deprecation.successor: %w", err)
+	}
+	return nil
+}
+
+// SinceTime retorna a data da descontinuação; zero quando não informada
+func (d *RouteDeprecation) SinceTime() time.Time {
+	since, _ := parseRouteDate(d.Since)
+	return since
+}
+
+// SunsetTime retorna a data de desativação; zero quando não informada
+func (d *RouteDeprecation) SunsetTime() time.Time {
+	sunset, _ := parseRouteDate(d.Sunset)
+	return sunset
+}
+
+// Deprecated indica se a descontinuação já está em vigor no instante informado
+func (d *RouteDeprecation) Deprecated(now time.Time) bool {
+	since := d.SinceTime()
+	return since.IsZero() || !now.Before(since)
+}
+
+// Sunsetted indica se a rota deve recusar as requisições no instante informado
+func (d *RouteDeprecation) Sunsetted(now time.Time) bool {
+	sunset := d.SunsetTime()
+	return d.Enforce && !sunset.IsZero() && !now.Before(sunset)
+}
+
+// parseRouteDate interpreta a data nos formatos aceitos; vazio retorna zero
+func parseRouteDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range routeDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("data inválida: %q", value)
+}
+
+// validateDeprecationLink aceita URLs absolutas ou caminhos do próprio gateway
+func validateDeprecationLink(link string) error {
+	if link == "" {
+		return nil
+	}
+	if strings.ContainsAny(link, "<>\" \r\n") {
+		return fmt.Errorf("link inválido: %q", link)
+	}
+	if strings.HasPrefix(link, "/") {
+		return nil
+	}
+	u, err := url.Parse(link)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("link inválido: %q (use uma URL http(s) ou um caminho)", link)
+	}
+	return nil
+}
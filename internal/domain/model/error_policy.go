@@ -0,0 +1,116 @@
+package model
+
+import (
+	"fmt"
+	"html/template"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Códigos estáveis das falhas do gateway ao atender pelo backend, enviados no
+// campo "code" do corpo de erro padronizado
+const (
+	ErrorCodeUpstreamTimeout           = "UPSTREAM_TIMEOUT"
+	ErrorCodeUpstreamConnectionRefused = "UPSTREAM_CONNECTION_REFUSED"
+	ErrorCodeUpstreamHostNotFound      = "UPSTREAM_HOST_NOT_FOUND"
+	ErrorCodeUpstreamTLS               = "UPSTREAM_TLS_ERROR"
+	ErrorCodeUpstreamError             = "UPSTREAM_ERROR"
+	ErrorCodeUpstreamServerError       = "UPSTREAM_SERVER_ERROR"
+	ErrorCodeUpstreamUnavailable       = "UPSTREAM_UNAVAILABLE"
+	ErrorCodeCircuitOpen               = "UPSTREAM_CIRCUIT_OPEN"
+	ErrorCodeConcurrencyLimit          = "UPSTREAM_CONCURRENCY_LIMIT"
+	ErrorCodeUpstreamMisconfigured     = "UPSTREAM_MISCONFIGURED"
+	ErrorCodeCompositionFailed         = "COMPOSITION_FAILED"
+	ErrorCodeRequestBodyTooLarge       = "REQUEST_BODY_TOO_LARGE"
+)
+
+// DefaultErrorMessages são as mensagens padrão de cada código de erro
+var DefaultErrorMessages = map[string]string{
+	ErrorCodeUpstreamTimeout:           "Tempo de resposta do serviço de destino esgotado",
+	ErrorCodeUpstreamConnectionRefused: "Serviço de destino recusou a conexão",
+	ErrorCodeUpstreamHostNotFound:      "Serviço de destino não encontrado",
+	ErrorCodeUpstreamTLS:               "Falha na conexão segura com o serviço de destino",
+	ErrorCodeUpstreamError:             "Erro ao encaminhar a requisição ao serviço de destino",
+	ErrorCodeUpstreamServerError:       "Serviço de destino falhou ao processar a requisição",
+	ErrorCodeUpstreamUnavailable:       "Serviço de destino indisponível",
+	ErrorCodeCircuitOpen:               "Serviço de destino temporariamente indisponível",
+	ErrorCodeConcurrencyLimit:          "Limite de requisições simultâneas atingido",
+	ErrorCodeUpstreamMisconfigured:     "Erro ao preparar conexão com o serviço de destino",
+	ErrorCodeCompositionFailed:         "Falha ao compor a resposta",
+	ErrorCodeRequestBodyTooLarge:       "Corpo da requisição excede o limite da rota",
+}
+
+// maxErrorPageBytes limita o tamanho de cada template de página de erro
+const maxErrorPageBytes = 64 << 10
+
+// errorPageKey aceita um status ("503"), uma classe ("5xx") ou um código de erro
+var errorPageKey = regexp.MustCompile(`^([1-5][0-9]{2}|[1-5]xx)$`)
+
+// ErrorPolicy personaliza as respostas de erro da rota quando o backend falha.
+// As falhas são sempre respondidas com um corpo JSON padronizado
+// ({"error", "code", "status", "requestId"}); clientes que aceitam text/html
+// recebem a página da rota, quando houver uma para o erro.
+type ErrorPolicy struct {
+	NormalizeUpstream bool              // Substitui o corpo das respostas 5xx do backend pelo erro padronizado
+	Messages          map[string]string // Mensagens por código de erro (ex.: UPSTREAM_TIMEOUT)
+	Pages             map[string]string // Templates HTML por código de erro, status ("503") ou classe ("5xx")
+}
+
+// ErrorPageData são os campos disponíveis nos templates das páginas de erro
+type ErrorPageData struct {
+	Status     int
+	StatusText string
+	Code       string
+	Message    string
+	RequestID  string
+	Path       string
+}
+
+// Validate verifica os códigos de erro e os templates das páginas
+func (p *ErrorPolicy) Validate() error {
+	for code, message := range p.Messages {
+		if _, ok := DefaultErrorMessages[code]; !ok {
+			return fmt.Errorf("errors.messages: código de erro desconhecido %q", code)
+		}
+		if strings.TrimSpace(message) == "" {
+			return fmt.Errorf("errors.messages[%s]: mensagem vazia", code)
+		}
+	}
+	for key, page := range p.Pages {
+		if _, ok := DefaultErrorMessages[key]; !ok && !errorPageKey.MatchString(key) {
+			return fmt.Errorf("errors.pages: chave inválida %q (use um código de erro, um status ou uma classe como 5xx)", key)
+		}
+		if len(page) > maxErrorPageBytes {
+			return fmt.Errorf("errors.pages[%s]: template excede %d bytes", key, maxErrorPageBytes)
+		}
+		if _, err := template.New(key).Parse(page); err != nil {
+			return fmt.Errorf("errors.pages[%s]: template inválido: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// Message retorna a mensagem do código de erro, personalizada pela rota ou a padrão
+func (p *ErrorPolicy) Message(code string) string {
+	if p != nil {
+		if message, ok := p.Messages[code]; ok {
+			return message
+		}
+	}
+	return DefaultErrorMessages[code]
+}
+
+// Page retorna o template da página do erro, procurando pelo código, pelo
+// status e pela classe do status; vazio quando a rota não tem página
+func (p *ErrorPolicy) Page(status int, code string) (string, string) {
+	if p == nil || len(p.Pages) == 0 {
+		return "", ""
+	}
+	for _, key := range []string{code, strconv.Itoa(status), strconv.Itoa(status/100) + "xx"} {
+		if page, ok := p.Pages[key]; ok {
+			return key, page
+		}
+	}
+	return "", ""
+}
@@ -0,0 +1,67 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// defaultExternalAuthzTimeout limita a espera pela decisão do serviço externo
+const defaultExternalAuthzTimeout = 2 * time.Second
+
+// ExternalAuthz delega a decisão de acesso da rota a um serviço externo (OPA,
+// serviço próprio), no estilo do ext_authz do Envoy
+type ExternalAuthz struct {
+	URL             string   // http(s):// para serviço HTTP; grpc:// ou grpcs:// para o protocolo ext_authz v3 do Envoy
+	Timeout         string   // Tempo máximo pela decisão (padrão "2s")
+	CacheTTL        string   // Tempo em que a decisão fica em cache; vazio não usa cache
+	Headers         []string // Cabeçalhos enviados ao serviço; vazio envia todos
+	UpstreamHeaders []string // Serviço HTTP: cabeçalhos da resposta de permissão repassados ao backend
+	FailOpen        bool     // Permite a requisição quando o serviço está indisponível
+}
+
+// TimeoutDuration retorna o tempo máximo pela decisão
+func (a *ExternalAuthz) TimeoutDuration() time.Duration {
+	return parseDurationOr(a.Timeout, defaultExternalAuthzTimeout)
+}
+
+// CacheTTLDuration retorna o tempo de cache das decisões; zero desabilita o cache
+func (a *ExternalAuthz) CacheTTLDuration() time.Duration {
+	return parseDurationOr(a.CacheTTL, 0)
+}
+
+// IsGRPC indica se o serviço fala o protocolo gRPC do Envoy
+func (a *ExternalAuthz) IsGRPC() bool {
+	u, err := url.Parse(a.URL)
+	return err == nil && (u.Scheme == "grpc" || u.Scheme == "grpcs")
+}
+
+// Validate verifica a consistência da configuração
+func (a *ExternalAuthz) Validate() error {
+	u, err := url.Parse(a.URL)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("externalAuthz.url inválida: %q", a.URL)
+	}
+	switch u.Scheme {
+	case "http", "https", "grpc", "grpcs":
+	default:
+		return fmt.Errorf("externalAuthz.url com esquema não suportado: %q", u.Scheme)
+	}
+	if len(a.UpstreamHeaders) > 0 && a.IsGRPC() {
+		return errors.New("externalAuthz.upstreamHeaders se aplica apenas a serviços HTTP")
+	}
+	for name, value := range map[string]string{"timeout": a.Timeout, "cacheTTL": a.CacheTTL} {
+		if value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("externalAuthz.%s inválido: %w", name, err)
+		}
+		if d <= 0 {
+			return fmt.Errorf("externalAuthz.%s deve ser positivo", name)
+		}
+	}
+	return nil
+}
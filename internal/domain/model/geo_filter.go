@@ -0,0 +1,78 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/diillson/api-gateway-go/pkg/geoip"
+)
+
+// GeoFilter restringe o acesso pelo país do cliente (ISO 3166-1 alfa-2, ex.:
+// "BR"), identificado pela geolocalização do gateway (geo). A lista de
+// bloqueio tem precedência; com a lista de permissão preenchida, apenas os
+// países nela contidos são aceitos.
+type GeoFilter struct {
+	Allow       []string // Países permitidos; vazio permite qualquer país não bloqueado
+	Deny        []string // Países bloqueados
+	DenyUnknown bool     // Recusa clientes sem país identificado mesmo sem lista de permissão
+}
+
+// Validate verifica se todas as entradas são códigos de país
+func (f *GeoFilter) Validate() error {
+	if len(f.Allow) == 0 && len(f.Deny) == 0 && !f.DenyUnknown {
+		return errors.New("geoFilter requer allow, deny ou denyUnknown")
+	}
+	if err := validateCountryCodes(f.Allow); err != nil {
+		return fmt.Errorf("geoFilter.allow: %w", err)
+	}
+	if err := validateCountryCodes(f.Deny); err != nil {
+		return fmt.Errorf("geoFilter.deny: %w", err)
+	}
+	return nil
+}
+
+// Allows informa se o cliente do país pode acessar a rota. Clientes sem país
+// identificado só são aceitos quando não há lista de permissão nem DenyUnknown.
+func (f *GeoFilter) Allows(country string) bool {
+	if country == "" {
+		return len(f.Allow) == 0 && !f.DenyUnknown
+	}
+	if containsFold(f.Deny, country) {
+		return false
+	}
+	return len(f.Allow) == 0 || containsFold(f.Allow, country)
+}
+
+// validateCountryCodes verifica o formato dos códigos ISO 3166-1 alfa-2
+func validateCountryCodes(codes []string) error {
+	for _, code := range codes {
+		if !geoip.IsCountryCode(code) {
+			return fmt.Errorf("código de país inválido %q (use ISO 3166-1 alfa-2, ex.: BR)", code)
+		}
+	}
+	return nil
+}
+
+// validContinents são os códigos de continente da geolocalização
+var validContinents = map[string]bool{"AF": true, "AN": true, "AS": true, "EU": true, "NA": true, "OC": true, "SA": true}
+
+// validateContinentCodes verifica os códigos de continente
+func validateContinentCodes(codes []string) error {
+	for _, code := range codes {
+		if !validContinents[strings.ToUpper(code)] {
+			return fmt.Errorf("código de continente inválido %q (use AF, AN, AS, EU, NA, OC ou SA)", code)
+		}
+	}
+	return nil
+}
+
+// containsFold indica se o valor está na lista, sem diferenciar maiúsculas
+func containsFold(values []string, value string) bool {
+	for _, candidate := range values {
+		if strings.EqualFold(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
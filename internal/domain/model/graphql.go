@@ -0,0 +1,44 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/diillson/api-gateway-go/pkg/graphql"
+)
+
+// GraphQLPolicy marca a rota como GraphQL: as operações são analisadas no
+// gateway, que recusa consultas profundas ou custosas demais e registra
+// métricas por operação, e não apenas pelo caminho /graphql
+type GraphQLPolicy struct {
+	MaxDepth             int               // Profundidade máxima dos campos; zero não limita
+	MaxComplexity        int               // Custo máximo da operação; zero não limita
+	MaxAliases           int               // Máximo de campos com apelido; zero não limita
+	DisableIntrospection bool              // Recusa consultas ao schema (__schema e __type)
+	PersistedOperations  map[string]string // Operações permitidas pelo hash SHA-256 do documento; preenchida, apenas elas são aceitas
+}
+
+// PersistedOperationHash calcula o hash usado em PersistedOperations e no
+// extensions.persistedQuery.sha256Hash enviado pelos clientes
+func PersistedOperationHash(document string) string {
+	sum := sha256.Sum256([]byte(document))
+	return hex.EncodeToString(sum[:])
+}
+
+// Validate verifica os limites e as operações persistidas
+func (p *GraphQLPolicy) Validate() error {
+	if p.MaxDepth < 0 || p.MaxComplexity < 0 || p.MaxAliases < 0 {
+		return errors.New("graphql: maxDepth, maxComplexity e maxAliases não podem ser negativos")
+	}
+	for hash, document := range p.PersistedOperations {
+		if PersistedOperationHash(document) != hash {
+			return fmt.Errorf("graphql.persistedOperations: hash %q não corresponde ao SHA-256 do documento", hash)
+		}
+		if _, err := graphql.Parse(document); err != nil {
+			return fmt.Errorf("graphql.persistedOperations[%s]: documento inválido: %w", hash, err)
+		}
+	}
+	return nil
+}
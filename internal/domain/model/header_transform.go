@@ -0,0 +1,120 @@
+package model
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/diillson/api-gateway-go/pkg/reqtemplate"
+)
+
+// protectedHeaders são cabeçalhos de enquadramento e conexão que as regras não
+// podem alterar, pois são controlados pelo servidor e pelo proxy
+var protectedHeaders = map[string]bool{
+	"Connection":        true,
+	"Content-Length":    true,
+	"Keep-Alive":        true,
+	"Transfer-Encoding": true,
+	"Upgrade":           true,
+	"Te":                true,
+	"Trailer":           true,
+}
+
+// HeaderTransforms altera os cabeçalhos enviados ao backend e os devolvidos ao
+// cliente. Os valores podem ser fixos ou expressões text/template avaliadas
+// sobre a requisição, com acesso a .Claims, .Params, .Headers, .Consumer,
+// .Tenant e .Labels (ex.: `{{ .Claims.tenant_id }}`).
+type HeaderTransforms struct {
+	Request  *HeaderRules // Aplicadas antes do encaminhamento ao backend
+	Response *HeaderRules // Aplicadas antes do envio da resposta ao cliente
+}
+
+// HeaderRules são aplicadas na ordem Remove, Set e Add. Expressões que resultam
+// em valor vazio não alteram o cabeçalho.
+type HeaderRules struct {
+	Remove []string          // Cabeçalhos removidos
+	Set    map[string]string // Cabeçalhos substituídos
+	Add    map[string]string // Valores acrescentados aos existentes
+}
+
+// Validate verifica os nomes dos cabeçalhos e se as expressões compilam
+func (t *HeaderTransforms) Validate() error {
+	if t.Request != nil {
+		if err := t.Request.validate("headerTransforms.request"); err != nil {
+			return err
+		}
+	}
+	if t.Response != nil {
+		if err := t.Response.validate("headerTransforms.response"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *HeaderRules) validate(field string) error {
+	for _, name := range r.Remove {
+		if err := validateHeaderName(name); err != nil {
+			return fmt.Errorf("%s.remove: %w", field, err)
+		}
+	}
+	for _, rules := range []struct {
+		name   string
+		values map[string]string
+	}{{"set", r.Set}, {"add", r.Add}} {
+		for name, expr := range rules.values {
+			if err := validateHeaderName(name); err != nil {
+				return fmt.Errorf("%s.%s: %w", field, rules.name, err)
+			}
+			if _, err := reqtemplate.Compile(expr); err != nil {
+				return fmt.Errorf("%s.%s.%s: %w", field, rules.name, name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func validateHeaderName(name string) error {
+	if !isToken(name) {
+		return fmt.Errorf("nome de cabeçalho inválido: %q", name)
+	}
+	if protectedHeaders[http.CanonicalHeaderKey(name)] {
+		return fmt.Errorf("o cabeçalho %s não pode ser alterado", name)
+	}
+	return nil
+}
+
+// Apply aplica as regras aos cabeçalhos. Expressões que falham são ignoradas e
+// devolvidas em errs, para registro.
+func (r *HeaderRules) Apply(header http.Header, data *reqtemplate.Data) (errs []error) {
+	for _, name := range r.Remove {
+		header.Del(name)
+	}
+	for name, expr := range r.Set {
+		value, err := reqtemplate.Render(expr, data)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if value = sanitizeHeaderValue(value); value != "" {
+			header.Set(name, value)
+		}
+	}
+	for name, expr := range r.Add {
+		value, err := reqtemplate.Render(expr, data)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if value = sanitizeHeaderValue(value); value != "" {
+			header.Add(name, value)
+		}
+	}
+	return errs
+}
+
+// sanitizeHeaderValue remove quebras de linha vindas de valores dinâmicos
+// (claims, cabeçalhos), evitando a injeção de cabeçalhos
+func sanitizeHeaderValue(value string) string {
+	return strings.TrimSpace(strings.NewReplacer("\r", "", "\n", "").Replace(value))
+}
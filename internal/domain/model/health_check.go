@@ -0,0 +1,122 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Tipos de verificação ativa de saúde suportados
+const (
+	HealthCheckGRPC = "grpc" // Protocolo padrão grpc.health.v1
+	HealthCheckHTTP = "http" // Requisição GET a um caminho do backend
+)
+
+// Valores padrão da verificação ativa de saúde
+const (
+	defaultHealthCheckInterval = 10 * time.Second
+	defaultHealthCheckTimeout  = 2 * time.Second
+	defaultHealthyThreshold    = 2
+	defaultUnhealthyThreshold  = 3
+	defaultHealthCheckPath     = "/health"
+)
+
+// HealthCheck configura a verificação ativa de saúde do backend da rota
+type HealthCheck struct {
+	Type               string // Tipo da verificação: "http" ou "grpc"
+	Service            string // Serviço gRPC verificado; vazio verifica o servidor como um todo
+	Path               string // Caminho verificado nas verificações HTTP (padrão "/health")
+	ExpectedStatus     int    // Status HTTP esperado; zero aceita qualquer 2xx
+	Interval           string // Intervalo entre verificações (ex.: "10s")
+	Timeout            string // Tempo máximo de cada verificação (ex.: "2s")
+	HealthyThreshold   int    // Sucessos consecutivos para considerar o backend saudável
+	UnhealthyThreshold int    // Falhas consecutivas para considerar o backend indisponível
+}
+
+// IntervalDuration retorna o intervalo entre verificações
+func (h *HealthCheck) IntervalDuration() time.Duration {
+	return parseDurationOr(h.Interval, defaultHealthCheckInterval)
+}
+
+// TimeoutDuration retorna o tempo máximo de cada verificação
+func (h *HealthCheck) TimeoutDuration() time.Duration {
+	return parseDurationOr(h.Timeout, defaultHealthCheckTimeout)
+}
+
+// HTTPPath retorna o caminho das verificações HTTP
+func (h *HealthCheck) HTTPPath() string {
+	if h.Path == "" {
+		return defaultHealthCheckPath
+	}
+	return h.Path
+}
+
+// IsExpectedStatus indica se o status HTTP recebido indica backend saudável
+func (h *HealthCheck) IsExpectedStatus(status int) bool {
+	if h.ExpectedStatus != 0 {
+		return status == h.ExpectedStatus
+	}
+	return status >= 200 && status < 300
+}
+
+// Thresholds retorna os limiares de sucesso e falha, aplicando os padrões
+func (h *HealthCheck) Thresholds() (healthy, unhealthy int) {
+	healthy, unhealthy = h.HealthyThreshold, h.UnhealthyThreshold
+	if healthy <= 0 {
+		healthy = defaultHealthyThreshold
+	}
+	if unhealthy <= 0 {
+		unhealthy = defaultUnhealthyThreshold
+	}
+	return healthy, unhealthy
+}
+
+// Validate verifica a consistência da configuração
+func (h *HealthCheck) Validate() error {
+	switch h.Type {
+	case HealthCheckGRPC, HealthCheckHTTP:
+	default:
+		return fmt.Errorf("healthCheck.type não suportado: %q", h.Type)
+	}
+
+	if h.Path != "" && !strings.HasPrefix(h.Path, "/") {
+		return errors.New("healthCheck.path deve começar com /")
+	}
+	if h.ExpectedStatus != 0 && (h.ExpectedStatus < 100 || h.ExpectedStatus > 599) {
+		return fmt.Errorf("healthCheck.expectedStatus inválido: %d", h.ExpectedStatus)
+	}
+
+	for name, value := range map[string]string{"interval": h.Interval, "timeout": h.Timeout} {
+		if value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("healthCheck.%s inválido: %w", name, err)
+		}
+		if d <= 0 {
+			return fmt.Errorf("healthCheck.%s deve ser positivo", name)
+		}
+	}
+
+	if h.TimeoutDuration() > h.IntervalDuration() {
+		return errors.New("healthCheck.timeout não pode ser maior que healthCheck.interval")
+	}
+	if h.HealthyThreshold < 0 || h.UnhealthyThreshold < 0 {
+		return errors.New("healthCheck: limiares não podem ser negativos")
+	}
+	return nil
+}
+
+// parseDurationOr interpreta a duração ou retorna o padrão quando vazia ou inválida
+func parseDurationOr(value string, fallback time.Duration) time.Duration {
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return fallback
+	}
+	return d
+}
@@ -0,0 +1,84 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Valores padrão do hedging
+const (
+	defaultHedgePercentile = 99
+	defaultHedgeMinDelay   = 10 * time.Millisecond
+	defaultHedgeMaxDelay   = time.Second
+	maxHedges              = 3
+)
+
+// HedgingPolicy envia requisições GET e HEAD em paralelo a outro alvo da rota
+// quando a primeira demora mais que o percentil de latência observado, usando
+// a primeira resposta recebida. Os hedges consomem o orçamento global de
+// novas tentativas.
+type HedgingPolicy struct {
+	Delay      string  // Atraso fixo antes do hedge; vazio usa o percentil observado
+	Percentile float64 // Percentil da latência da rota usado como atraso (padrão 99)
+	MinDelay   string  // Atraso mínimo (padrão "10ms")
+	MaxDelay   string  // Atraso máximo, usado também enquanto não há amostras suficientes (padrão "1s")
+	MaxHedges  int     // Requisições adicionais por requisição (padrão 1, máximo 3)
+}
+
+// Validate verifica a consistência da política
+func (p *HedgingPolicy) Validate() error {
+	if p.Percentile < 0 || p.Percentile > 100 {
+		return fmt.Errorf("hedging.percentile deve estar entre 0 e 100: %v", p.Percentile)
+	}
+	if p.MaxHedges < 0 || p.MaxHedges > maxHedges {
+		return fmt.Errorf("hedging.maxHedges deve estar entre 0 e %d", maxHedges)
+	}
+	for name, value := range map[string]string{"delay": p.Delay, "minDelay": p.MinDelay, "maxDelay": p.MaxDelay} {
+		if value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("hedging.%s inválido: %w", name, err)
+		}
+		if d <= 0 {
+			return fmt.Errorf("hedging.%s deve ser positivo", name)
+		}
+	}
+	if p.MinDelayDuration() > p.MaxDelayDuration() {
+		return errors.New("hedging.minDelay não pode ser maior que hedging.maxDelay")
+	}
+	return nil
+}
+
+// Hedges retorna o número de requisições adicionais
+func (p *HedgingPolicy) Hedges() int {
+	if p.MaxHedges <= 0 {
+		return 1
+	}
+	return p.MaxHedges
+}
+
+// PercentileValue retorna o percentil usado como atraso
+func (p *HedgingPolicy) PercentileValue() float64 {
+	if p.Percentile <= 0 {
+		return defaultHedgePercentile
+	}
+	return p.Percentile
+}
+
+// FixedDelay retorna o atraso fixo; zero indica o uso do percentil
+func (p *HedgingPolicy) FixedDelay() time.Duration {
+	return parseDurationOr(p.Delay, 0)
+}
+
+// MinDelayDuration retorna o atraso mínimo
+func (p *HedgingPolicy) MinDelayDuration() time.Duration {
+	return parseDurationOr(p.MinDelay, defaultHedgeMinDelay)
+}
+
+// MaxDelayDuration retorna o atraso máximo
+func (p *HedgingPolicy) MaxDelayDuration() time.Duration {
+	return parseDurationOr(p.MaxDelay, defaultHedgeMaxDelay)
+}
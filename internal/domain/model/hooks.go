@@ -0,0 +1,98 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// defaultPreHookTimeout limita a espera pela resposta dos webhooks pré-requisição
+const defaultPreHookTimeout = 2 * time.Second
+
+// RouteHooks chama serviços externos antes do envio ao backend (ex.: análise
+// de fraude) e os notifica após a resposta (ex.: pipelines de analytics). As
+// chamadas são assinadas com HMAC-SHA256, como os webhooks de alteração de rotas.
+type RouteHooks struct {
+	Pre  []PreRequestHook   // Chamados em ordem antes do backend; podem recusar a requisição ou alterar cabeçalhos
+	Post []PostResponseHook // Notificados em segundo plano com os dados da requisição e da resposta
+}
+
+// PreRequestHook é um webhook síncrono consultado antes do envio ao backend
+type PreRequestHook struct {
+	Name     string   // Nome do webhook, usado em logs e métricas
+	URL      string   // Endpoint http(s) que recebe a requisição
+	Secret   string   // Chave HMAC-SHA256 da assinatura
+	Timeout  string   // Tempo máximo pela resposta (padrão "2s")
+	Headers  []string // Cabeçalhos da requisição enviados ao webhook; vazio não envia nenhum
+	FailOpen bool     // Segue para o backend quando o webhook falha ou não responde
+}
+
+// PostResponseHook é um webhook notificado de forma assíncrona após a resposta
+type PostResponseHook struct {
+	Name    string   // Nome do webhook, usado em logs e métricas
+	URL     string   // Endpoint http(s) que recebe a notificação
+	Secret  string   // Chave HMAC-SHA256 da assinatura
+	Headers []string // Cabeçalhos da requisição incluídos na notificação
+}
+
+// TimeoutDuration retorna o tempo máximo pela resposta do webhook
+func (h *PreRequestHook) TimeoutDuration() time.Duration {
+	return parseDurationOr(h.Timeout, defaultPreHookTimeout)
+}
+
+// Redacted retorna uma cópia dos webhooks sem as chaves de assinatura
+func (h *RouteHooks) Redacted() *RouteHooks {
+	copied := &RouteHooks{
+		Pre:  append([]PreRequestHook(nil), h.Pre...),
+		Post: append([]PostResponseHook(nil), h.Post...),
+	}
+	for i := range copied.Pre {
+		copied.Pre[i].Secret = redactedSecret
+	}
+	for i := range copied.Post {
+		copied.Post[i].Secret = redactedSecret
+	}
+	return copied
+}
+
+// Validate verifica os webhooks da rota
+func (h *RouteHooks) Validate() error {
+	if len(h.Pre) == 0 && len(h.Post) == 0 {
+		return errors.New("hooks requer pre ou post")
+	}
+	for i, hook := range h.Pre {
+		prefix := fmt.Sprintf("hooks.pre[%d]", i)
+		if err := validateHookEndpoint(prefix, hook.URL, hook.Secret, hook.Headers); err != nil {
+			return err
+		}
+		if hook.Timeout != "" {
+			if d, err := time.ParseDuration(hook.Timeout); err != nil || d <= 0 {
+				return fmt.Errorf("%s.timeout inválido: %q", prefix, hook.Timeout)
+			}
+		}
+	}
+	for i, hook := range h.Post {
+		prefix := fmt.Sprintf("hooks.post[%d]", i)
+		if err := validateHookEndpoint(prefix, hook.URL, hook.Secret, hook.Headers); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateHookEndpoint(prefix, rawURL, secret string, headers []string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%s.url inválida: %q", prefix, rawURL)
+	}
+	if secret == "" {
+		return fmt.Errorf("%s.secret é obrigatório para assinar as chamadas", prefix)
+	}
+	for _, name := range headers {
+		if !isToken(name) {
+			return fmt.Errorf("%s.headers: cabeçalho inválido %q", prefix, name)
+		}
+	}
+	return nil
+}
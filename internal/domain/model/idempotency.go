@@ -0,0 +1,54 @@
+package model
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultIdempotentMethods são os métodos tratados quando a política não os define
+var defaultIdempotentMethods = []string{http.MethodPost, http.MethodPut}
+
+// IdempotencyPolicy habilita o cabeçalho Idempotency-Key na rota: a primeira
+// resposta para cada chave é armazenada e repetida nas novas tentativas do
+// cliente, sem que a requisição chegue de novo ao backend
+type IdempotencyPolicy struct {
+	Methods  []string // Métodos tratados (padrão POST e PUT)
+	Required bool     // Recusa requisições desses métodos sem a chave
+	TTL      string   // Por quanto tempo a resposta é repetida; vazio usa idempotency.ttl
+}
+
+// Validate verifica a consistência da política
+func (p *IdempotencyPolicy) Validate() error {
+	for _, method := range p.Methods {
+		if !validMethods[strings.ToUpper(method)] {
+			return fmt.Errorf("idempotency.methods contém método inválido: %q", method)
+		}
+	}
+	if p.TTL != "" {
+		if d, err := time.ParseDuration(p.TTL); err != nil || d <= 0 {
+			return fmt.Errorf("idempotency.ttl inválido: %q", p.TTL)
+		}
+	}
+	return nil
+}
+
+// AppliesTo indica se a política trata o método
+func (p *IdempotencyPolicy) AppliesTo(method string) bool {
+	methods := p.Methods
+	if len(methods) == 0 {
+		methods = defaultIdempotentMethods
+	}
+	for _, m := range methods {
+		if strings.EqualFold(m, method) {
+			return true
+		}
+	}
+	return false
+}
+
+// TTLDuration retorna a validade da resposta armazenada; zero usa o padrão global
+func (p *IdempotencyPolicy) TTLDuration() time.Duration {
+	return parseDurationOr(p.TTL, 0)
+}
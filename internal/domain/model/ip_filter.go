@@ -0,0 +1,91 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// IPFilter restringe o acesso pelo endereço de origem do cliente. As entradas
+// são blocos CIDR (ex.: "10.0.0.0/8") ou endereços individuais. A lista de
+// bloqueio tem precedência; com a lista de permissão preenchida, apenas os
+// endereços nela contidos são aceitos.
+type IPFilter struct {
+	Allow []string // Blocos permitidos; vazio permite qualquer origem não bloqueada
+	Deny  []string // Blocos bloqueados
+}
+
+// Validate verifica se todas as entradas são endereços ou blocos CIDR válidos
+func (f *IPFilter) Validate() error {
+	if len(f.Allow) == 0 && len(f.Deny) == 0 {
+		return errors.New("ipFilter requer allow ou deny")
+	}
+	if _, err := ParsePrefixes(f.Allow); err != nil {
+		return fmt.Errorf("ipFilter.allow: %w", err)
+	}
+	if _, err := ParsePrefixes(f.Deny); err != nil {
+		return fmt.Errorf("ipFilter.deny: %w", err)
+	}
+	return nil
+}
+
+// Allows informa se o endereço pode acessar o recurso. Endereços inválidos só
+// são aceitos quando não há lista de permissão.
+func (f *IPFilter) Allows(addr netip.Addr) bool {
+	allow, _ := ParsePrefixes(f.Allow)
+	deny, _ := ParsePrefixes(f.Deny)
+	return AllowsAddr(addr, allow, deny)
+}
+
+// AllowsAddr aplica as listas já convertidas ao endereço
+func AllowsAddr(addr netip.Addr, allow, deny []netip.Prefix) bool {
+	addr = addr.Unmap()
+	if ContainsAddr(deny, addr) {
+		return false
+	}
+	if len(allow) == 0 {
+		return true
+	}
+	return ContainsAddr(allow, addr)
+}
+
+// ContainsAddr informa se o endereço pertence a algum dos blocos
+func ContainsAddr(prefixes []netip.Prefix, addr netip.Addr) bool {
+	if !addr.IsValid() {
+		return false
+	}
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParsePrefixes converte as entradas em blocos CIDR; um endereço individual
+// vira um bloco /32 (IPv4) ou /128 (IPv6)
+func ParsePrefixes(entries []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if strings.Contains(entry, "/") {
+			prefix, err := netip.ParsePrefix(entry)
+			if err != nil {
+				return nil, fmt.Errorf("bloco CIDR inválido: %q", entry)
+			}
+			if prefix.Addr().Is4In6() && prefix.Bits() >= 96 {
+				prefix = netip.PrefixFrom(prefix.Addr().Unmap(), prefix.Bits()-96)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(entry)
+		if err != nil {
+			return nil, fmt.Errorf("endereço IP inválido: %q", entry)
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nil
+}
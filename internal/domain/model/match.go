@@ -0,0 +1,272 @@
+package model
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// RequestInfo reúne os dados da requisição avaliados pelas condições de correspondência
+type RequestInfo struct {
+	Host      string
+	Method    string
+	Header    http.Header
+	Country   string // País do cliente pela geolocalização; vazio quando desconhecido
+	Continent string // Continente do cliente pela geolocalização
+}
+
+// HeaderMatch é uma condição sobre um cabeçalho da requisição. Sem Value e sem
+// Regex, basta o cabeçalho estar presente.
+type HeaderMatch struct {
+	Name  string // Nome do cabeçalho
+	Value string // Valor exato esperado
+	Regex string // Expressão regular aplicada ao valor
+}
+
+// MatchConditions são condições, além do caminho, que a requisição deve
+// satisfazer. Todas as condições informadas precisam ser atendidas.
+type MatchConditions struct {
+	Hosts      []string      // Hosts aceitos; "*.exemplo.com" aceita qualquer subdomínio
+	Methods    []string      // Métodos HTTP aceitos
+	Headers    []HeaderMatch // Condições sobre cabeçalhos
+	Countries  []string      // Países do cliente (ISO 3166-1 alfa-2) aceitos, pela geolocalização
+	Continents []string      // Continentes do cliente aceitos (AF, AN, AS, EU, NA, OC, SA)
+}
+
+// RouteVariant direciona as requisições que satisfazem suas condições a outro
+// backend, permitindo que o mesmo caminho atenda serviços diferentes
+type RouteVariant struct {
+	Match      MatchConditions // Condições da variante
+	ServiceURL string          // Backend da variante (opcional quando Targets é informado)
+	Targets    []Target        // Alvos balanceados da variante
+}
+
+// headerRegexps guarda as expressões regulares já compiladas das condições
+var headerRegexps sync.Map
+
+// compileHeaderRegex compila a expressão uma única vez por processo
+func compileHeaderRegex(expr string) (*regexp.Regexp, error) {
+	if re, ok := headerRegexps.Load(expr); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	headerRegexps.Store(expr, re)
+	return re, nil
+}
+
+// IsEmpty indica se nenhuma condição foi informada
+func (m *MatchConditions) IsEmpty() bool {
+	return len(m.Hosts) == 0 && len(m.Methods) == 0 && len(m.Headers) == 0 &&
+		len(m.Countries) == 0 && len(m.Continents) == 0
+}
+
+// Matches indica se a requisição satisfaz todas as condições
+func (m *MatchConditions) Matches(info RequestInfo) bool {
+	return m.Mismatch(info) == ""
+}
+
+// Mismatch descreve a primeira condição não atendida pela requisição, ou
+// retorna vazio quando todas são satisfeitas
+func (m *MatchConditions) Mismatch(info RequestInfo) string {
+	if len(m.Hosts) > 0 && !matchHost(m.Hosts, info.Host) {
+		return fmt.Sprintf("host %q fora de %v", info.Host, m.Hosts)
+	}
+
+	if len(m.Methods) > 0 {
+		allowed := false
+		for _, method := range m.Methods {
+			if strings.EqualFold(method, info.Method) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Sprintf("método %s fora de %v", info.Method, m.Methods)
+		}
+	}
+
+	// Clientes sem localização não atendem às condições geográficas
+	if len(m.Countries) > 0 && !containsFold(m.Countries, info.Country) {
+		return fmt.Sprintf("país %q fora de %v", info.Country, m.Countries)
+	}
+	if len(m.Continents) > 0 && !containsFold(m.Continents, info.Continent) {
+		return fmt.Sprintf("continente %q fora de %v", info.Continent, m.Continents)
+	}
+
+	for _, header := range m.Headers {
+		values, present := info.Header[http.CanonicalHeaderKey(header.Name)]
+		if !present {
+			return fmt.Sprintf("cabeçalho %s ausente", header.Name)
+		}
+		value := strings.Join(values, ",")
+		if header.Value != "" && value != header.Value {
+			return fmt.Sprintf("cabeçalho %s diferente de %q", header.Name, header.Value)
+		}
+		if header.Regex != "" {
+			re, err := compileHeaderRegex(header.Regex)
+			if err != nil || !re.MatchString(value) {
+				return fmt.Sprintf("cabeçalho %s não corresponde a %q", header.Name, header.Regex)
+			}
+		}
+	}
+	return ""
+}
+
+// matchHost compara o host da requisição, sem porta e sem diferenciar
+// maiúsculas, com os hosts aceitos
+func matchHost(hosts []string, requestHost string) bool {
+	host := requestHost
+	if h, _, err := net.SplitHostPort(requestHost); err == nil {
+		host = h
+	}
+	host = strings.ToLower(host)
+
+	for _, pattern := range hosts {
+		pattern = strings.ToLower(pattern)
+		if suffix, ok := strings.CutPrefix(pattern, "*"); ok {
+			if strings.HasSuffix(host, suffix) && len(host) > len(suffix) {
+				return true
+			}
+			continue
+		}
+		if host == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate verifica a consistência das condições
+func (m *MatchConditions) Validate(prefix string) error {
+	for _, host := range m.Hosts {
+		if host == "" || strings.Contains(host, "/") {
+			return fmt.Errorf("%s.hosts: host inválido %q", prefix, host)
+		}
+		if strings.Contains(host, "*") && !strings.HasPrefix(host, "*.") {
+			return fmt.Errorf("%s.hosts: curinga só é aceito no início (*.dominio): %q", prefix, host)
+		}
+	}
+	for _, method := range m.Methods {
+		if !isToken(method) {
+			return fmt.Errorf("%s.methods: método inválido %q", prefix, method)
+		}
+	}
+	for i, header := range m.Headers {
+		if !isToken(header.Name) {
+			return fmt.Errorf("%s.headers[%d]: nome inválido %q", prefix, i, header.Name)
+		}
+		if header.Value != "" && header.Regex != "" {
+			return fmt.Errorf("%s.headers[%d]: informe value ou regex, não ambos", prefix, i)
+		}
+		if header.Regex != "" {
+			if _, err := compileHeaderRegex(header.Regex); err != nil {
+				return fmt.Errorf("%s.headers[%d]: regex inválida: %w", prefix, i, err)
+			}
+		}
+	}
+	if err := validateCountryCodes(m.Countries); err != nil {
+		return fmt.Errorf("%s.countries: %w", prefix, err)
+	}
+	if err := validateContinentCodes(m.Continents); err != nil {
+		return fmt.Errorf("%s.continents: %w", prefix, err)
+	}
+	return nil
+}
+
+// MatchesRequest indica se a requisição satisfaz as condições da rota
+func (r *Route) MatchesRequest(info RequestInfo) bool {
+	return r.Match == nil || r.Match.Matches(info)
+}
+
+// ForRequest retorna a rota a ser usada para a requisição: a primeira variante
+// cujas condições são satisfeitas substitui o backend da rota. O índice da
+// variante é retornado, ou -1 quando o backend padrão é mantido.
+func (r *Route) ForRequest(info RequestInfo) (*Route, int) {
+	for i := range r.Variants {
+		if r.Variants[i].Match.Matches(info) {
+			return r.withVariant(i), i
+		}
+	}
+	return r, -1
+}
+
+// withVariant retorna uma cópia da rota com o backend da variante. O
+// roteamento canary se aplica apenas ao backend padrão.
+func (r *Route) withVariant(index int) *Route {
+	resolved := *r
+	resolved.ServiceURL = r.Variants[index].ServiceURL
+	resolved.Targets = r.Variants[index].Targets
+	resolved.Canary = nil
+	return &resolved
+}
+
+// AllUpstreams retorna os alvos da rota, de todas as suas variantes e das
+// regras route do filtro de requisições, sem repetição
+func (r *Route) AllUpstreams() []Target {
+	upstreams := append([]Target(nil), r.Upstreams()...)
+	seen := make(map[string]bool, len(upstreams))
+	for _, target := range upstreams {
+		seen[target.URL] = true
+	}
+
+	for i := range r.Variants {
+		for _, target := range r.withVariant(i).Upstreams() {
+			if !seen[target.URL] {
+				seen[target.URL] = true
+				upstreams = append(upstreams, target)
+			}
+		}
+	}
+
+	if r.RequestFilter != nil {
+		for _, rule := range r.RequestFilter.Rules {
+			if rule.Action == FilterRoute && !seen[rule.ServiceURL] {
+				seen[rule.ServiceURL] = true
+				upstreams = append(upstreams, Target{URL: rule.ServiceURL, Weight: 1})
+			}
+		}
+	}
+	return upstreams
+}
+
+// validateMatch verifica as condições da rota e de suas variantes
+func (r *Route) validateMatch() error {
+	if r.Match != nil {
+		if err := r.Match.Validate("match"); err != nil {
+			return err
+		}
+	}
+
+	for i, variant := range r.Variants {
+		prefix := fmt.Sprintf("variants[%d]", i)
+		if variant.Match.IsEmpty() {
+			return fmt.Errorf("%s: ao menos uma condição é obrigatória", prefix)
+		}
+		if err := variant.Match.Validate(prefix + ".match"); err != nil {
+			return err
+		}
+		if variant.ServiceURL == "" && len(variant.Targets) == 0 {
+			return fmt.Errorf("%s: serviceURL ou targets é obrigatório", prefix)
+		}
+		if variant.ServiceURL != "" {
+			if _, err := url.Parse(variant.ServiceURL); err != nil {
+				return fmt.Errorf("%s: serviceURL inválida: %w", prefix, err)
+			}
+		}
+
+		// A estratégia de balanceamento já foi validada na rota
+		resolved := r.withVariant(i)
+		resolved.LoadBalancing = ""
+		if err := resolved.validateTargets(); err != nil {
+			return fmt.Errorf("%s: %w", prefix, err)
+		}
+	}
+	return nil
+}
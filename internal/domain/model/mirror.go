@@ -0,0 +1,58 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Valores padrão do espelhamento de tráfego
+const (
+	defaultMirrorTimeout      = 5 * time.Second
+	DefaultMirrorMaxBodyBytes = 1 << 20
+)
+
+// TrafficMirror envia em segundo plano uma cópia de parte das requisições da
+// rota a um backend sombra, cujas respostas são descartadas. Permite validar
+// uma nova versão do serviço com tráfego real sem afetar os clientes.
+type TrafficMirror struct {
+	URL          string  // URL do backend sombra
+	Percentage   float64 // Porcentagem das requisições espelhadas (0 a 100)
+	Timeout      string  // Tempo máximo da requisição espelhada (padrão "5s")
+	MaxBodyBytes int64   // Corpos maiores não são espelhados (padrão 1MB)
+}
+
+// Validate verifica a consistência do espelhamento
+func (m *TrafficMirror) Validate() error {
+	if m.URL == "" {
+		return errors.New("mirror.url é obrigatória")
+	}
+	if err := validateServiceURL(m.URL); err != nil {
+		return fmt.Errorf("mirror.url inválida: %w", err)
+	}
+	if m.Percentage <= 0 || m.Percentage > 100 {
+		return errors.New("mirror.percentage deve estar entre 0 (exclusivo) e 100")
+	}
+	if m.Timeout != "" {
+		if d, err := time.ParseDuration(m.Timeout); err != nil || d <= 0 {
+			return fmt.Errorf("mirror.timeout inválido: %q", m.Timeout)
+		}
+	}
+	if m.MaxBodyBytes < 0 {
+		return errors.New("mirror.maxBodyBytes não pode ser negativo")
+	}
+	return nil
+}
+
+// TimeoutDuration retorna o tempo máximo da requisição espelhada
+func (m *TrafficMirror) TimeoutDuration() time.Duration {
+	return parseDurationOr(m.Timeout, defaultMirrorTimeout)
+}
+
+// BodyLimit retorna o tamanho máximo do corpo espelhado
+func (m *TrafficMirror) BodyLimit() int64 {
+	if m.MaxBodyBytes > 0 {
+		return m.MaxBodyBytes
+	}
+	return DefaultMirrorMaxBodyBytes
+}
@@ -0,0 +1,122 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// validateOpenAPISpec aceita um caminho no backend (ex.: /openapi.json) ou uma
+// URL absoluta para o documento OpenAPI do serviço
+func (r *Route) validateOpenAPISpec() error {
+	if r.OpenAPISpec == "" || strings.HasPrefix(r.OpenAPISpec, "/") {
+		return nil
+	}
+	u, err := url.Parse(r.OpenAPISpec)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("openAPISpec deve ser um caminho iniciado por / ou uma URL http(s)")
+	}
+	return nil
+}
+
+// OpenAPISpecURL resolve a URL do documento OpenAPI do backend. Caminhos são
+// relativos ao primeiro alvo da rota.
+func (r *Route) OpenAPISpecURL() (string, error) {
+	if r.OpenAPISpec == "" {
+		return "", errors.New("rota sem openAPISpec")
+	}
+	if !strings.HasPrefix(r.OpenAPISpec, "/") {
+		return r.OpenAPISpec, nil
+	}
+	base, err := url.Parse(r.Upstreams()[0].URL)
+	if err != nil || base.Host == "" {
+		return "", fmt.Errorf("URL do backend inválida: %q", r.Upstreams()[0].URL)
+	}
+	return base.Scheme + "://" + base.Host + r.OpenAPISpec, nil
+}
+
+// GatewayPath converte um caminho do backend, no formato do OpenAPI
+// (/users/{id}), no caminho equivalente exposto pelo gateway, desfazendo a
+// reescrita da rota. Retorna false quando o caminho não é alcançável pela
+// rota ou a reescrita não pode ser invertida (regex).
+func (r *Route) GatewayPath(backendPath string) (string, bool) {
+	rewrite := r.PathRewrite
+	if rewrite == nil {
+		rewrite = &PathRewrite{}
+	}
+	if rewrite.Regex != "" {
+		return "", false
+	}
+
+	var path string
+	switch {
+	case rewrite.Template != "":
+		resolved, ok := invertTemplate(r.Path, rewrite.Template, backendPath)
+		if !ok {
+			return "", false
+		}
+		path = resolved
+	case rewrite.StripPrefix:
+		path = staticPrefix(r.Path) + backendPath
+	default:
+		path = backendPath
+	}
+
+	if !MatchRoutePath(r.Path, path) {
+		return "", false
+	}
+	return path, true
+}
+
+// invertTemplate associa os placeholders do template aos segmentos do caminho
+// do backend e os substitui nos parâmetros do caminho da rota
+func invertTemplate(routePath, template, backendPath string) (string, bool) {
+	templateParts := strings.Split(template, "/")
+	backendParts := strings.Split(backendPath, "/")
+
+	values := make(map[string]string)
+	for i, part := range templateParts {
+		if part == "{*}" && i == len(templateParts)-1 {
+			if len(backendParts) < i {
+				return "", false
+			}
+			values["*"] = strings.Join(backendParts[i:], "/")
+			backendParts = backendParts[:i]
+			templateParts = templateParts[:i]
+			break
+		}
+	}
+	if len(templateParts) != len(backendParts) {
+		return "", false
+	}
+	for i, part := range templateParts {
+		if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+			values[part[1:len(part)-1]] = backendParts[i]
+			continue
+		}
+		if part != backendParts[i] {
+			return "", false
+		}
+	}
+
+	routeParts := strings.Split(routePath, "/")
+	for i, part := range routeParts {
+		switch {
+		case part == "*" && i == len(routeParts)-1:
+			routeParts[i] = values["*"]
+		case strings.HasPrefix(part, ":"):
+			name := strings.TrimPrefix(part, ":")
+			if value, ok := values[name]; ok {
+				routeParts[i] = value
+			} else {
+				routeParts[i] = "{" + name + "}"
+			}
+		}
+	}
+	path := strings.TrimSuffix(strings.Join(routeParts, "/"), "/")
+	if path == "" {
+		path = "/"
+	}
+	return path, true
+}
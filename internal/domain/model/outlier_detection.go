@@ -0,0 +1,140 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Valores padrão da detecção de alvos anômalos
+const (
+	defaultOutlierConsecutiveErrors  = 5
+	defaultOutlierInterval           = 10 * time.Second
+	defaultOutlierBaseEjectionTime   = 30 * time.Second
+	defaultOutlierMaxEjectionTime    = 5 * time.Minute
+	defaultOutlierMaxEjectionPercent = 50
+	defaultOutlierMinRequests        = 20
+	defaultOutlierErrorRateMargin    = 0.2
+	defaultOutlierRecoveryTime       = 30 * time.Second
+)
+
+// OutlierDetection retira temporariamente do balanceamento os alvos cujo
+// tráfego real destoa dos demais (verificação passiva de saúde): erros
+// consecutivos ou, a cada intervalo, taxa de erros ou latência muito acima das
+// do restante dos alvos. Complementa a verificação ativa (HealthCheck), que
+// não percebe falhas que afetam apenas parte das requisições.
+type OutlierDetection struct {
+	ConsecutiveErrors  int     // Erros seguidos (5xx ou falha de conexão) que retiram o alvo (padrão 5; -1 desabilita)
+	Interval           string  // Intervalo da comparação entre os alvos (padrão "10s")
+	MinRequests        int     // Requisições mínimas do alvo no intervalo para ser comparado (padrão 20)
+	ErrorRateMargin    float64 // Taxa de erros acima da média dos demais alvos que retira o alvo (padrão 0.2; -1 desabilita)
+	LatencyFactor      float64 // Múltiplo da latência mediana dos alvos que retira o alvo; zero desabilita
+	BaseEjectionTime   string  // Tempo fora do balanceamento, multiplicado pelas retiradas seguidas (padrão "30s")
+	MaxEjectionTime    string  // Tempo máximo fora do balanceamento (padrão "5m")
+	MaxEjectionPercent int     // Percentual máximo de alvos retirados ao mesmo tempo (padrão 50); ao menos um
+	RecoveryTime       string  // Período em que o alvo devolvido recebe tráfego crescente (padrão "30s"; "0s" devolve de uma vez)
+}
+
+// Validate verifica a consistência da configuração
+func (o *OutlierDetection) Validate() error {
+	if o.ConsecutiveErrors < -1 || o.MinRequests < 0 {
+		return errors.New("outlierDetection: consecutiveErrors e minRequests não podem ser negativos")
+	}
+	if o.ErrorRateMargin < -1 || o.ErrorRateMargin > 1 {
+		return errors.New("outlierDetection.errorRateMargin deve estar entre 0 e 1, ou -1 para desabilitar")
+	}
+	if o.LatencyFactor != 0 && o.LatencyFactor <= 1 {
+		return errors.New("outlierDetection.latencyFactor deve ser maior que 1")
+	}
+	if o.MaxEjectionPercent < 0 || o.MaxEjectionPercent > 100 {
+		return errors.New("outlierDetection.maxEjectionPercent deve estar entre 0 e 100")
+	}
+
+	for name, value := range map[string]string{
+		"interval":         o.Interval,
+		"baseEjectionTime": o.BaseEjectionTime,
+		"maxEjectionTime":  o.MaxEjectionTime,
+		"recoveryTime":     o.RecoveryTime,
+	} {
+		if value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("outlierDetection.%s inválido: %w", name, err)
+		}
+		if d < 0 || (d == 0 && name != "recoveryTime") {
+			return fmt.Errorf("outlierDetection.%s deve ser positivo", name)
+		}
+	}
+
+	if o.MaxEjectionDuration() < o.BaseEjectionDuration() {
+		return errors.New("outlierDetection.maxEjectionTime não pode ser menor que baseEjectionTime")
+	}
+	return nil
+}
+
+// ErrorThreshold retorna os erros seguidos que retiram o alvo; zero desabilita
+func (o *OutlierDetection) ErrorThreshold() int {
+	switch {
+	case o.ConsecutiveErrors < 0:
+		return 0
+	case o.ConsecutiveErrors == 0:
+		return defaultOutlierConsecutiveErrors
+	}
+	return o.ConsecutiveErrors
+}
+
+// IntervalDuration retorna o intervalo da comparação entre os alvos
+func (o *OutlierDetection) IntervalDuration() time.Duration {
+	return parseDurationOr(o.Interval, defaultOutlierInterval)
+}
+
+// RequestVolume retorna as requisições mínimas do alvo no intervalo
+func (o *OutlierDetection) RequestVolume() int {
+	if o.MinRequests > 0 {
+		return o.MinRequests
+	}
+	return defaultOutlierMinRequests
+}
+
+// ErrorMargin retorna a margem da taxa de erros; zero desabilita
+func (o *OutlierDetection) ErrorMargin() float64 {
+	switch {
+	case o.ErrorRateMargin < 0:
+		return 0
+	case o.ErrorRateMargin == 0:
+		return defaultOutlierErrorRateMargin
+	}
+	return o.ErrorRateMargin
+}
+
+// BaseEjectionDuration retorna o tempo da primeira retirada do alvo
+func (o *OutlierDetection) BaseEjectionDuration() time.Duration {
+	return parseDurationOr(o.BaseEjectionTime, defaultOutlierBaseEjectionTime)
+}
+
+// MaxEjectionDuration retorna o tempo máximo de uma retirada
+func (o *OutlierDetection) MaxEjectionDuration() time.Duration {
+	return parseDurationOr(o.MaxEjectionTime, defaultOutlierMaxEjectionTime)
+}
+
+// MaxEjectedPercent retorna o percentual máximo de alvos retirados
+func (o *OutlierDetection) MaxEjectedPercent() int {
+	if o.MaxEjectionPercent > 0 {
+		return o.MaxEjectionPercent
+	}
+	return defaultOutlierMaxEjectionPercent
+}
+
+// RecoveryDuration retorna o período de tráfego crescente após a retirada
+func (o *OutlierDetection) RecoveryDuration() time.Duration {
+	if o.RecoveryTime == "" {
+		return defaultOutlierRecoveryTime
+	}
+	d, err := time.ParseDuration(o.RecoveryTime)
+	if err != nil || d < 0 {
+		return defaultOutlierRecoveryTime
+	}
+	return d
+}
@@ -0,0 +1,152 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// PathRewrite altera o caminho encaminhado ao backend, que por padrão é o
+// caminho requisitado sem alterações. As etapas são aplicadas nesta ordem:
+// Template (ou StripPrefix) e depois Regex.
+type PathRewrite struct {
+	// StripPrefix remove a parte fixa do caminho da rota, até o primeiro
+	// parâmetro ou curinga (ex.: /api/users/* encaminha /api/users/42 como /42)
+	StripPrefix bool
+	// Template substitui o caminho; {nome} recebe o parâmetro :nome da rota e
+	// {*} o restante capturado pelo curinga (ex.: /v2/internal/users/{id})
+	Template string
+	// Regex e Replacement reescrevem o caminho resultante com expressão
+	// regular; Replacement aceita referências como $1 e ${nome}
+	Regex       string
+	Replacement string
+}
+
+// placeholderPattern encontra os parâmetros {nome} e {*} do Template
+var placeholderPattern = regexp.MustCompile(`\{(\*|[A-Za-z0-9_]+)\}`)
+
+// compiledRewrites guarda as expressões regulares já compiladas
+var compiledRewrites sync.Map
+
+// Validate verifica o template e a expressão regular
+func (p *PathRewrite) Validate(routePath string) error {
+	if p.StripPrefix && p.Template != "" {
+		return errors.New("pathRewrite: use stripPrefix ou template, não ambos")
+	}
+	if p.Template != "" {
+		if !strings.HasPrefix(p.Template, "/") {
+			return errors.New("pathRewrite.template deve começar com /")
+		}
+		params := make(map[string]bool)
+		for _, part := range strings.Split(routePath, "/") {
+			if strings.HasPrefix(part, ":") {
+				params[strings.TrimPrefix(part, ":")] = true
+			}
+		}
+		for _, match := range placeholderPattern.FindAllStringSubmatch(p.Template, -1) {
+			name := match[1]
+			if name == "*" && !strings.HasSuffix(routePath, "/*") {
+				return fmt.Errorf("pathRewrite.template usa {*}, mas a rota %s não termina com /*", routePath)
+			}
+			if name != "*" && !params[name] {
+				return fmt.Errorf("pathRewrite.template usa {%s}, que não é parâmetro da rota %s", name, routePath)
+			}
+		}
+	}
+	if p.Regex != "" {
+		if _, err := p.regex(); err != nil {
+			return fmt.Errorf("pathRewrite.regex inválida: %w", err)
+		}
+	} else if p.Replacement != "" {
+		return errors.New("pathRewrite.replacement requer regex")
+	}
+	return nil
+}
+
+// Rewrite calcula o caminho do backend para o caminho requisitado
+func (p *PathRewrite) Rewrite(routePath, requestPath string) string {
+	path := requestPath
+
+	switch {
+	case p.Template != "":
+		params := PathParams(routePath, requestPath)
+		remainder := wildcardRemainder(routePath, requestPath)
+		path = placeholderPattern.ReplaceAllStringFunc(p.Template, func(placeholder string) string {
+			name := placeholder[1 : len(placeholder)-1]
+			if name == "*" {
+				return strings.TrimPrefix(remainder, "/")
+			}
+			return params[name]
+		})
+	case p.StripPrefix:
+		path = strings.TrimPrefix(requestPath, staticPrefix(routePath))
+	}
+
+	if p.Regex != "" {
+		if re, err := p.regex(); err == nil {
+			path = re.ReplaceAllString(path, p.Replacement)
+		}
+	}
+
+	// Normalizar barras duplicadas geradas pelas substituições
+	for strings.Contains(path, "//") {
+		path = strings.ReplaceAll(path, "//", "/")
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return path
+}
+
+func (p *PathRewrite) regex() (*regexp.Regexp, error) {
+	if cached, ok := compiledRewrites.Load(p.Regex); ok {
+		return cached.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(p.Regex)
+	if err != nil {
+		return nil, err
+	}
+	compiledRewrites.Store(p.Regex, re)
+	return re, nil
+}
+
+// PathParams extrai os valores dos placeholders (ex.: /users/:id) do caminho requisitado
+func PathParams(registeredPath, requestPath string) map[string]string {
+	params := make(map[string]string)
+	if !strings.Contains(registeredPath, ":") {
+		return params
+	}
+
+	regParts := strings.Split(registeredPath, "/")
+	reqParts := strings.Split(requestPath, "/")
+	if len(regParts) != len(reqParts) {
+		return params
+	}
+
+	for i, part := range regParts {
+		if strings.HasPrefix(part, ":") {
+			params[strings.TrimPrefix(part, ":")] = reqParts[i]
+		}
+	}
+	return params
+}
+
+// staticPrefix retorna a parte fixa do caminho da rota, até o primeiro
+// parâmetro ou curinga
+func staticPrefix(routePath string) string {
+	prefix := strings.TrimSuffix(routePath, "/*")
+	if i := strings.Index(prefix, "/:"); i >= 0 {
+		prefix = prefix[:i]
+	}
+	return strings.TrimSuffix(prefix, "/")
+}
+
+// wildcardRemainder retorna o trecho capturado pelo curinga de rotas /*
+func wildcardRemainder(routePath, requestPath string) string {
+	if !strings.HasSuffix(routePath, "/*") {
+		return ""
+	}
+	return strings.TrimPrefix(requestPath, strings.TrimSuffix(routePath, "/*"))
+}
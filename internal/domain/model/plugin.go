@@ -0,0 +1,45 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/diillson/api-gateway-go/pkg/plugin"
+)
+
+// PluginRef aplica às requisições da rota um plugin registrado no gateway
+type PluginRef struct {
+	Name   string          // Nome do plugin no registro (ex.: "wasm")
+	Order  int             // Posição na cadeia: menores primeiro; empates seguem a ordem da lista
+	Config json.RawMessage // Configuração repassada ao plugin
+}
+
+// validatePlugins verifica se os plugins estão registrados e aceitam a configuração
+func (r *Route) validatePlugins() error {
+	for i, ref := range r.Plugins {
+		if ref.Name == "" {
+			return fmt.Errorf("plugins[%d]: name é obrigatório", i)
+		}
+		if !plugin.Registered(ref.Name) {
+			return fmt.Errorf("plugins[%d]: plugin não registrado: %q (disponíveis: %v)", i, ref.Name, plugin.Names())
+		}
+		if len(ref.Config) > 0 && !json.Valid(ref.Config) {
+			return fmt.Errorf("plugins[%d]: config não é JSON válido", i)
+		}
+		if _, err := plugin.New(ref.Name, ref.Config); err != nil {
+			return fmt.Errorf("plugins[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// PluginChain retorna os plugins da rota na ordem de execução
+func (r *Route) PluginChain() []PluginRef {
+	if len(r.Plugins) == 0 {
+		return nil
+	}
+	chain := append([]PluginRef(nil), r.Plugins...)
+	sort.SliceStable(chain, func(i, j int) bool { return chain[i].Order < chain[j].Order })
+	return chain
+}
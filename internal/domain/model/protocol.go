@@ -0,0 +1,33 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Protocolos de backend suportados pelas rotas
+const (
+	ProtocolHTTP = "http"
+	ProtocolGRPC = "grpc"
+)
+
+// IsGRPC indica se o backend da rota fala gRPC
+func (r *Route) IsGRPC() bool {
+	return r.Protocol == ProtocolGRPC
+}
+
+// validateProtocol verifica o protocolo do backend e as opções que dependem dele
+func (r *Route) validateProtocol() error {
+	switch r.Protocol {
+	case "", ProtocolHTTP, ProtocolGRPC:
+	default:
+		return fmt.Errorf("protocol inválido: %q", r.Protocol)
+	}
+	if r.GRPCWeb && !r.IsGRPC() {
+		return errors.New("grpcWeb requer protocol \"grpc\"")
+	}
+	if r.IsGRPC() && r.ResponseCache != nil {
+		return errors.New("responseCache não é suportado em rotas gRPC")
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// ConsumerUsage é o total de requisições de um consumidor em um período de cota
+type ConsumerUsage struct {
+	Consumer  string    `json:"consumer"` // Ex.: apikey:<id> ou sub:<id>
+	Period    string    `json:"period"`   // Dia (2026-10-16) ou mês (2026-10)
+	Requests  int64     `json:"requests"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// ConsumerUsageEntity é a representação de banco de dados do uso de um consumidor
+type ConsumerUsageEntity struct {
+	Consumer  string `gorm:"primaryKey;size:255"`
+	Period    string `gorm:"primaryKey;size:10"`
+	Requests  int64  `gorm:"not null;default:0"`
+	UpdatedAt time.Time
+}
+
+// TableName define o nome da tabela
+func (ConsumerUsageEntity) TableName() string {
+	return "consumer_usage"
+}
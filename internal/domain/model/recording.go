@@ -0,0 +1,143 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Valores padrão da gravação de tráfego
+const (
+	DefaultRecordingMaxBodyBytes = 64 << 10
+	maxRecordingBodyBytes        = 1 << 20
+)
+
+// RedactedValue substitui os valores removidos das gravações
+const RedactedValue = "[REDACTED]"
+
+// recordingAlwaysRedacted são os cabeçalhos com credenciais, nunca gravados
+var recordingAlwaysRedacted = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// RecordingPolicy grava uma amostra das requisições da rota e das respostas
+// enviadas aos clientes, para depuração e reprodução em outro ambiente.
+// Credenciais em Authorization e cookies são sempre removidas.
+type RecordingPolicy struct {
+	SampleRate    float64  // Fração das requisições gravadas, de 0 a 1
+	RedactHeaders []string // Cabeçalhos da requisição e da resposta cujo valor não é gravado
+	RedactFields  []string // Campos dos corpos JSON cujo valor não é gravado, em qualquer nível
+	MaxBodyBytes  int64    // Bytes gravados de cada corpo (padrão 64KB); o excedente é descartado
+	Methods       []string // Métodos gravados; vazio grava todos
+}
+
+// Validate verifica a consistência da configuração
+func (p *RecordingPolicy) Validate() error {
+	if p.SampleRate <= 0 || p.SampleRate > 1 {
+		return errors.New("recording.sampleRate deve ser maior que 0 e no máximo 1")
+	}
+	if p.MaxBodyBytes < 0 || p.MaxBodyBytes > maxRecordingBodyBytes {
+		return fmt.Errorf("recording.maxBodyBytes deve estar entre 0 e %d", maxRecordingBodyBytes)
+	}
+	for _, header := range p.RedactHeaders {
+		if strings.TrimSpace(header) == "" {
+			return errors.New("recording.redactHeaders não aceita nomes vazios")
+		}
+	}
+	for _, field := range p.RedactFields {
+		if strings.TrimSpace(field) == "" {
+			return errors.New("recording.redactFields não aceita nomes vazios")
+		}
+	}
+	for _, method := range p.Methods {
+		if !validMethods[method] {
+			return fmt.Errorf("recording.methods: método HTTP inválido: %q", method)
+		}
+	}
+	return nil
+}
+
+// Records indica se as requisições com o método são gravadas
+func (p *RecordingPolicy) Records(method string) bool {
+	if len(p.Methods) == 0 {
+		return true
+	}
+	for _, allowed := range p.Methods {
+		if allowed == method {
+			return true
+		}
+	}
+	return false
+}
+
+// BodyLimit retorna os bytes gravados de cada corpo
+func (p *RecordingPolicy) BodyLimit() int64 {
+	if p.MaxBodyBytes > 0 {
+		return p.MaxBodyBytes
+	}
+	return DefaultRecordingMaxBodyBytes
+}
+
+// RedactsHeader indica se o valor do cabeçalho não é gravado
+func (p *RecordingPolicy) RedactsHeader(name string) bool {
+	for _, header := range recordingAlwaysRedacted {
+		if strings.EqualFold(header, name) {
+			return true
+		}
+	}
+	for _, header := range p.RedactHeaders {
+		if strings.EqualFold(header, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Recording é uma requisição gravada com a resposta enviada ao cliente. Os
+// corpos são gravados como recebidos e enviados (inclusive comprimidos),
+// exceto os campos removidos dos corpos JSON.
+type Recording struct {
+	ID                    string      `json:"id"`
+	Time                  time.Time   `json:"time"`
+	Tenant                string      `json:"tenant,omitempty"`
+	Route                 string      `json:"route"`
+	RequestID             string      `json:"requestId,omitempty"`
+	Method                string      `json:"method"`
+	Path                  string      `json:"path"`
+	Query                 string      `json:"query,omitempty"`
+	Host                  string      `json:"host,omitempty"`
+	RequestHeaders        http.Header `json:"requestHeaders,omitempty"`
+	RequestBody           []byte      `json:"requestBody,omitempty"`
+	RequestBodyTruncated  bool        `json:"requestBodyTruncated,omitempty"`
+	Status                int         `json:"status"`
+	ResponseHeaders       http.Header `json:"responseHeaders,omitempty"`
+	ResponseBody          []byte      `json:"responseBody,omitempty"`
+	ResponseBodyTruncated bool        `json:"responseBodyTruncated,omitempty"`
+	DurationMs            float64     `json:"durationMs"`
+}
+
+// RecordingEntity é a representação de banco de dados de uma gravação
+type RecordingEntity struct {
+	ID                    string    `gorm:"primaryKey;type:varchar(36)"`
+	Time                  time.Time `gorm:"column:recorded_at;not null;index"`
+	Tenant                string    `gorm:"size:255"`
+	Route                 string    `gorm:"size:255;index"`
+	RequestID             string    `gorm:"size:255"`
+	Method                string    `gorm:"size:10"`
+	Path                  string    `gorm:"type:text"`
+	Query                 string    `gorm:"type:text"`
+	Host                  string    `gorm:"size:255"`
+	RequestHeadersJSON    string    `gorm:"column:request_headers;type:text"`
+	RequestBody           string    `gorm:"column:request_body;type:text"` // Base64, pois o corpo pode ser binário
+	RequestBodyTruncated  bool      `gorm:"column:request_body_truncated"`
+	Status                int       `gorm:"column:status"`
+	ResponseHeadersJSON   string    `gorm:"column:response_headers;type:text"`
+	ResponseBody          string    `gorm:"column:response_body;type:text"` // Base64
+	ResponseBodyTruncated bool      `gorm:"column:response_body_truncated"`
+	DurationMs            float64   `gorm:"column:duration_ms"`
+}
+
+// TableName define o nome da tabela
+func (RecordingEntity) TableName() string {
+	return "recordings"
+}
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"errors"
+	"mime"
+	"strings"
+)
+
+// RequestBodyPolicy restringe o corpo das requisições aceitas pela rota,
+// protegendo o backend de cargas grandes demais ou inesperadas
+type RequestBodyPolicy struct {
+	MaxBytes     int64    // Tamanho máximo do corpo; zero sem limite
+	ContentTypes []string // Tipos MIME aceitos; vazio aceita todos. "application/*" cobre os subtipos
+}
+
+// Validate verifica a consistência da política
+func (p *RequestBodyPolicy) Validate() error {
+	if p.MaxBytes < 0 {
+		return errors.New("requestBody.maxBytes não pode ser negativo")
+	}
+	for _, contentType := range p.ContentTypes {
+		if strings.TrimSpace(contentType) == "" {
+			return errors.New("requestBody.contentTypes não aceita valores vazios")
+		}
+	}
+	return nil
+}
+
+// AllowsContentType indica se o tipo de conteúdo é aceito pela política
+func (p *RequestBodyPolicy) AllowsContentType(contentType string) bool {
+	if len(p.ContentTypes) == 0 {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, allowed := range p.ContentTypes {
+		allowed = strings.ToLower(strings.TrimSpace(allowed))
+		if allowed == mediaType || allowed == "*/*" {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(allowed, "/*"); ok && strings.HasPrefix(mediaType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,173 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/diillson/api-gateway-go/pkg/expr"
+)
+
+// Ações das regras de filtro por expressão
+const (
+	FilterAllow    = "allow"
+	FilterDeny     = "deny"
+	FilterRoute    = "route"
+	FilterAnnotate = "annotate"
+)
+
+// FilterVariables são as variáveis disponíveis nas expressões dos filtros:
+// request (method, path, host, ip, headers, query, params, body), jwt
+// (claims), consumer, tenant e route (path, labels)
+var FilterVariables = []string{"request", "jwt", "consumer", "tenant", "route"}
+
+// RequestFilter aplica regras com expressões no estilo CEL às requisições da
+// rota, ex.: request.headers["x-beta"] == "true" && jwt.claims.plan == "pro".
+// As regras são avaliadas em ordem: annotate adiciona cabeçalhos e segue para
+// a próxima; allow, deny e route encerram a avaliação. O corpo JSON só é lido
+// (até MaxBodyBytes) quando alguma regra usa request.body.
+type RequestFilter struct {
+	Rules        []FilterRule // Regras avaliadas em ordem
+	Default      string       // Decisão quando nenhuma regra encerra a avaliação: "allow" (padrão) ou "deny"
+	MaxBodyBytes int64        // Tamanho máximo do corpo lido para request.body (padrão 1 MiB)
+}
+
+// defaultFilterBodyBytes é o limite padrão do corpo lido pelas regras
+const defaultFilterBodyBytes = 1 << 20
+
+// FilterRule é uma regra do filtro de requisições
+type FilterRule struct {
+	Name       string            // Nome da regra, usado em logs e métricas
+	When       string            // Expressão avaliada; a regra se aplica quando resulta em true
+	Action     string            // "allow", "deny", "route" ou "annotate"
+	Status     int               // Status da resposta com deny (padrão 403)
+	Message    string            // Mensagem de erro com deny
+	ServiceURL string            // Backend que atende a requisição com route
+	Headers    map[string]string // Cabeçalhos adicionados à requisição com annotate
+}
+
+// Matches avalia a expressão da regra com as variáveis da requisição
+func (r *FilterRule) Matches(vars map[string]interface{}) (bool, error) {
+	program, err := expr.Compile(r.When, FilterVariables...)
+	if err != nil {
+		return false, err
+	}
+	return program.EvalBool(vars)
+}
+
+// DenyStatus retorna o status da resposta com deny
+func (r *FilterRule) DenyStatus() int {
+	if r.Status == 0 {
+		return http.StatusForbidden
+	}
+	return r.Status
+}
+
+// DenyMessage retorna a mensagem da resposta com deny
+func (r *FilterRule) DenyMessage() string {
+	if r.Message == "" {
+		return "Acesso negado pelo filtro da rota"
+	}
+	return r.Message
+}
+
+// InspectsBody indica se alguma regra usa o corpo da requisição (request.body)
+func (f *RequestFilter) InspectsBody() bool {
+	for i := range f.Rules {
+		program, err := expr.Compile(f.Rules[i].When, FilterVariables...)
+		if err == nil && program.Uses("request", "body") {
+			return true
+		}
+	}
+	return false
+}
+
+// BodyLimit retorna o tamanho máximo do corpo lido pelas regras
+func (f *RequestFilter) BodyLimit() int64 {
+	if f.MaxBodyBytes <= 0 {
+		return defaultFilterBodyBytes
+	}
+	return f.MaxBodyBytes
+}
+
+// DeniesByDefault indica se as requisições sem decisão das regras são negadas
+func (f *RequestFilter) DeniesByDefault() bool {
+	return f.Default == FilterDeny
+}
+
+// WithFilterBackend retorna uma cópia da rota atendida pelo backend da regra.
+// Os alvos, o canary e a afinidade de sessão valem apenas para o backend padrão.
+func (r *Route) WithFilterBackend(rule *FilterRule) *Route {
+	resolved := *r
+	resolved.ServiceURL = rule.ServiceURL
+	resolved.Targets = nil
+	resolved.LoadBalancing = ""
+	resolved.SessionAffinity = nil
+	resolved.Canary = nil
+	return &resolved
+}
+
+// Validate verifica as regras e compila as expressões
+func (f *RequestFilter) Validate() error {
+	switch f.Default {
+	case "", FilterAllow, FilterDeny:
+	default:
+		return fmt.Errorf("requestFilter.default inválido: %q", f.Default)
+	}
+	if len(f.Rules) == 0 {
+		return errors.New("requestFilter requer ao menos uma regra")
+	}
+	if f.MaxBodyBytes < 0 {
+		return errors.New("requestFilter.maxBodyBytes não pode ser negativo")
+	}
+
+	for i, rule := range f.Rules {
+		prefix := fmt.Sprintf("requestFilter.rules[%d]", i)
+		if rule.When == "" {
+			return fmt.Errorf("%s: when é obrigatório", prefix)
+		}
+		if _, err := expr.Compile(rule.When, FilterVariables...); err != nil {
+			return fmt.Errorf("%s: expressão inválida: %w", prefix, err)
+		}
+
+		if rule.Status != 0 && rule.Action != FilterDeny {
+			return fmt.Errorf("%s: status aplica-se apenas com action \"deny\"", prefix)
+		}
+		if rule.Message != "" && rule.Action != FilterDeny {
+			return fmt.Errorf("%s: message aplica-se apenas com action \"deny\"", prefix)
+		}
+		if rule.ServiceURL != "" && rule.Action != FilterRoute {
+			return fmt.Errorf("%s: serviceURL aplica-se apenas com action \"route\"", prefix)
+		}
+		if len(rule.Headers) > 0 && rule.Action != FilterAnnotate {
+			return fmt.Errorf("%s: headers aplica-se apenas com action \"annotate\"", prefix)
+		}
+
+		switch rule.Action {
+		case FilterAllow:
+		case FilterDeny:
+			if rule.Status != 0 && (rule.Status < 400 || rule.Status > 599) {
+				return fmt.Errorf("%s: status deve estar entre 400 e 599", prefix)
+			}
+		case FilterRoute:
+			if rule.ServiceURL == "" {
+				return fmt.Errorf("%s: serviceURL é obrigatório com action \"route\"", prefix)
+			}
+			if err := validateServiceURL(rule.ServiceURL); err != nil {
+				return fmt.Errorf("%s: serviceURL inválida: %w", prefix, err)
+			}
+		case FilterAnnotate:
+			if len(rule.Headers) == 0 {
+				return fmt.Errorf("%s: headers é obrigatório com action \"annotate\"", prefix)
+			}
+			for name := range rule.Headers {
+				if !isToken(name) {
+					return fmt.Errorf("%s: cabeçalho inválido %q", prefix, name)
+				}
+			}
+		default:
+			return fmt.Errorf("%s: action inválida: %q", prefix, rule.Action)
+		}
+	}
+	return nil
+}
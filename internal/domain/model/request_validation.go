@@ -0,0 +1,48 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/diillson/api-gateway-go/pkg/jsonschema"
+)
+
+// RequestValidation valida as requisições da rota com JSON Schema antes de
+// encaminhá-las ao backend. Os schemas podem ser informados na rota ou vir de
+// uma operação do documento OpenAPI do backend (openAPISpec).
+type RequestValidation struct {
+	Body      json.RawMessage // JSON Schema do corpo JSON
+	Query     json.RawMessage // JSON Schema de objeto com os parâmetros da query string
+	Operation string          // operationId ou "MÉTODO /caminho" no documento OpenAPI do backend
+	MaxBytes  int64           // Tamanho máximo do corpo lido para validação (padrão 1MB)
+}
+
+// Validate verifica a consistência da validação, compilando os schemas
+func (v *RequestValidation) Validate(openAPISpec string) error {
+	hasSchemas := len(v.Body) > 0 || len(v.Query) > 0
+	operation := strings.TrimSpace(v.Operation)
+	switch {
+	case !hasSchemas && operation == "":
+		return errors.New("validation exige body, query ou operation")
+	case hasSchemas && operation != "":
+		return errors.New("validation.operation não pode ser combinado com body ou query")
+	case operation != "" && openAPISpec == "":
+		return errors.New("validation.operation exige openAPISpec na rota")
+	}
+	if v.MaxBytes < 0 {
+		return errors.New("validation.maxBytes não pode ser negativo")
+	}
+	if len(v.Body) > 0 {
+		if _, err := jsonschema.Compile(v.Body); err != nil {
+			return fmt.Errorf("validation.body: %w", err)
+		}
+	}
+	if len(v.Query) > 0 {
+		if _, err := jsonschema.Compile(v.Query); err != nil {
+			return fmt.Errorf("validation.query: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Valores padrão de timeout e novas tentativas por rota
+const (
+	DefaultUpstreamTimeout = 30 * time.Second
+	defaultRetryBackoff    = 100 * time.Millisecond
+	defaultRetryMaxBackoff = 2 * time.Second
+)
+
+// defaultRetryStatusCodes são os status que indicam falha transitória do backend
+var defaultRetryStatusCodes = []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout}
+
+// defaultRetryMethods são os métodos idempotentes, seguros para repetir
+var defaultRetryMethods = []string{
+	http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete,
+}
+
+// RetryPolicy repete as requisições ao backend após falhas de conexão, timeout
+// da tentativa ou status transitórios, com backoff exponencial e jitter. As
+// tentativas respeitam o Timeout total da rota.
+type RetryPolicy struct {
+	Attempts      int      // Novas tentativas após a primeira
+	StatusCodes   []int    // Status que provocam nova tentativa (padrão 502, 503 e 504)
+	Methods       []string // Métodos repetidos (padrão: GET, HEAD, OPTIONS, PUT e DELETE)
+	PerTryTimeout string   // Tempo máximo de cada tentativa (ex.: "2s"); vazio usa o timeout da rota
+	Backoff       string   // Espera base antes da primeira repetição (padrão "100ms")
+	MaxBackoff    string   // Espera máxima entre tentativas (padrão "2s")
+}
+
+// UpstreamTimeout retorna o tempo máximo de resposta do backend
+func (r *Route) UpstreamTimeout() time.Duration {
+	return parseDurationOr(r.Timeout, DefaultUpstreamTimeout)
+}
+
+// validateTimeout verifica o timeout da rota
+func (r *Route) validateTimeout() error {
+	if r.Timeout == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(r.Timeout)
+	if err != nil {
+		return fmt.Errorf("timeout inválido: %w", err)
+	}
+	if d <= 0 {
+		return errors.New("timeout deve ser positivo")
+	}
+	return nil
+}
+
+// Validate verifica a consistência da política
+func (p *RetryPolicy) Validate() error {
+	if p.Attempts <= 0 {
+		return errors.New("retry.attempts deve ser positivo")
+	}
+	for _, code := range p.StatusCodes {
+		if code < 100 || code > 599 {
+			return fmt.Errorf("retry.statusCodes: status inválido %d", code)
+		}
+	}
+	for _, method := range p.Methods {
+		if method == "" || strings.ToUpper(method) != method {
+			return fmt.Errorf("retry.methods: método inválido %q (use maiúsculas)", method)
+		}
+	}
+	for name, value := range map[string]string{
+		"perTryTimeout": p.PerTryTimeout, "backoff": p.Backoff, "maxBackoff": p.MaxBackoff,
+	} {
+		if value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("retry.%s inválido: %w", name, err)
+		}
+		if d <= 0 {
+			return fmt.Errorf("retry.%s deve ser positivo", name)
+		}
+	}
+	if p.BackoffDuration() > p.MaxBackoffDuration() {
+		return errors.New("retry.backoff não pode ser maior que retry.maxBackoff")
+	}
+	return nil
+}
+
+// RetriesMethod informa se requisições com o método podem ser repetidas
+func (p *RetryPolicy) RetriesMethod(method string) bool {
+	methods := p.Methods
+	if len(methods) == 0 {
+		methods = defaultRetryMethods
+	}
+	for _, allowed := range methods {
+		if allowed == method {
+			return true
+		}
+	}
+	return false
+}
+
+// RetriesStatus informa se o status do backend provoca nova tentativa
+func (p *RetryPolicy) RetriesStatus(status int) bool {
+	codes := p.StatusCodes
+	if len(codes) == 0 {
+		codes = defaultRetryStatusCodes
+	}
+	for _, code := range codes {
+		if code == status {
+			return true
+		}
+	}
+	return false
+}
+
+// PerTryTimeoutDuration retorna o tempo máximo de cada tentativa; zero indica
+// que vale apenas o timeout total da rota
+func (p *RetryPolicy) PerTryTimeoutDuration() time.Duration {
+	return parseDurationOr(p.PerTryTimeout, 0)
+}
+
+// BackoffDuration retorna a espera base entre tentativas
+func (p *RetryPolicy) BackoffDuration() time.Duration {
+	return parseDurationOr(p.Backoff, defaultRetryBackoff)
+}
+
+// MaxBackoffDuration retorna a espera máxima entre tentativas
+func (p *RetryPolicy) MaxBackoffDuration() time.Duration {
+	return parseDurationOr(p.MaxBackoff, defaultRetryMaxBackoff)
+}
+
+// Delay calcula a espera antes da nova tentativa de número attempt (a partir
+// de 1): backoff exponencial limitado a MaxBackoff, com jitter completo
+func (p *RetryPolicy) Delay(attempt int) time.Duration {
+	ceiling := p.MaxBackoffDuration()
+	delay := p.BackoffDuration()
+	for i := 1; i < attempt && delay < ceiling; i++ {
+		delay *= 2
+	}
+	if delay > ceiling {
+		delay = ceiling
+	}
+	return time.Duration(rand.Int63n(int64(delay)) + 1)
+}
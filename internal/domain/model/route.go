@@ -3,24 +3,225 @@ package model
 import (
 	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
 	"strings"
 	"time"
+
+	"github.com/diillson/api-gateway-go/pkg/reqtemplate"
 )
 
 // Route é a representação de domínio de uma rota da API
 type Route struct {
-	Path            string        // O caminho da rota ex: /api/users
-	ServiceURL      string        // A URL do serviço de backend
-	Methods         []string      // Métodos HTTP permitidos
-	Headers         []string      // Cabeçalhos a serem passados
-	Description     string        // Descrição da rota
-	IsActive        bool          // Se a rota está ativa
-	CallCount       int64         // Número de chamadas realizadas
-	TotalResponse   time.Duration // Tempo total de resposta
-	RequiredHeaders []string      // Cabeçalhos obrigatórios
-	CreatedAt       time.Time     // Data de criação
-	UpdatedAt       time.Time     // Data de atualização
+	Tenant           string                // Tenant dono da rota; vazio no escopo padrão. Definido pelo contexto da requisição.
+	Path             string                // O caminho da rota ex: /api/users
+	ServiceURL       string                // A URL do serviço de backend (opcional quando Targets é informado)
+	Methods          []string              // Métodos HTTP permitidos
+	Headers          []string              // Cabeçalhos a serem passados
+	Description      string                // Descrição da rota
+	IsActive         bool                  // Se a rota está ativa
+	ActiveFrom       string                // Início da ativação agendada (RFC3339 ou "2006-01-02"); vazio ativa imediatamente
+	ActiveUntil      string                // Fim da ativação agendada; vazio mantém a rota ativa
+	FeatureFlag      *RouteFeatureFlag     // Flag do provedor que habilita a rota por coorte de consumidores
+	CallCount        int64                 // Número de chamadas realizadas
+	TotalResponse    time.Duration         // Tempo total de resposta
+	RequiredHeaders  []string              // Cabeçalhos obrigatórios
+	UpstreamAuth     *UpstreamAuth         // Credenciais basic-auth enviadas ao backend
+	TLS              *UpstreamTLS          // Configuração TLS da conexão com o backend
+	EgressProxy      string                // Proxy de saída: vazio usa o global, "direct" conecta sem proxy
+	ResponseCache    *ResponseCachePolicy  // Cache das respostas do backend
+	AccessSchedule   *AccessSchedule       // Janelas de horário em que a rota pode ser acessada
+	Labels           map[string]string     // Rótulos livres (ex.: team, product)
+	CostAttribution  *CostAttribution      // Expressões de atribuição de custo
+	HealthCheck      *HealthCheck          // Verificação ativa de saúde do backend
+	OutlierDetection *OutlierDetection     // Retirada temporária dos alvos cujo tráfego destoa dos demais
+	Telemetry        *RouteTelemetry       // Atributos de span e baggage adicionados aos traces
+	Targets          []Target              // Alvos balanceados; vazio usa apenas ServiceURL
+	LoadBalancing    string                // Estratégia de balanceamento entre os alvos
+	SessionAffinity  *SessionAffinity      // Mantém cada cliente no mesmo alvo por hash consistente
+	ConnectionPool   *ConnectionPool       // Pool de conexões e HTTP/2 com os backends
+	CircuitBreaker   *CircuitBreakerPolicy // Circuit breaker entre o gateway e os backends
+	Canary           *CanaryRouting        // Distribuição do tráfego entre versões do backend
+	Match            *MatchConditions      // Condições além do caminho (host, método, cabeçalhos)
+	Variants         []RouteVariant        // Backends alternativos escolhidos por condições da requisição
+	WebSocket        *WebSocketPolicy      // Proxy de conexões WebSocket
+	Protocol         string                // Protocolo do backend: "http" (padrão) ou "grpc"
+	GRPCWeb          bool                  // Traduz requisições gRPC-Web para gRPC (rotas grpc)
+	Auth             *RouteAuth            // Política de autenticação dos consumidores da rota
+	Authorization    *AuthorizationPolicy  // Papéis, escopos e claims exigidos dos consumidores
+	ExternalAuthz    *ExternalAuthz        // Serviço externo que decide o acesso (ext_authz)
+	IPFilter         *IPFilter             // Blocos CIDR permitidos e bloqueados na origem
+	GeoFilter        *GeoFilter            // Países permitidos e bloqueados pela geolocalização do cliente
+	HeaderTransforms *HeaderTransforms     // Cabeçalhos alterados na requisição e na resposta
+	PathRewrite      *PathRewrite          // Reescrita do caminho encaminhado ao backend
+	BodyTransforms   *BodyTransforms       // Transformação dos corpos JSON da requisição e da resposta
+	Timeout          string                // Tempo máximo de resposta do backend, incluindo as novas tentativas (padrão "30s")
+	Retry            *RetryPolicy          // Novas tentativas com backoff exponencial em falhas do backend
+	Hedging          *HedgingPolicy        // Requisições paralelas a outro alvo em respostas lentas (GET e HEAD)
+	Bulkhead         *BulkheadPolicy       // Limite de requisições simultâneas à rota e a cada alvo
+	Compression      *CompressionPolicy    // Compressão das respostas da rota
+	RequestBody      *RequestBodyPolicy    // Tamanho máximo e tipos de conteúdo aceitos no corpo das requisições
+	Validation       *RequestValidation    // Validação do corpo e da query string com JSON Schema
+	CORS             *CORSPolicy           // Acesso por páginas de outras origens e resposta aos preflights
+	Maintenance      *MaintenanceMode      // Resposta fixa enquanto a rota está em manutenção
+	Errors           *ErrorPolicy          // Mensagens e páginas de erro quando o backend falha
+	Stub             *StubResponse         // Resposta simulada no lugar do backend
+	Mirror           *TrafficMirror        // Cópia de parte do tráfego enviada a um backend sombra
+	Idempotency      *IdempotencyPolicy    // Repetição da resposta para a mesma Idempotency-Key
+	RateLimitHeaders string                // Cabeçalhos de limite nas respostas: standard, legacy, both ou none; vazio usa ratelimit.headers
+	Plugins          []PluginRef           // Filtros registrados no gateway, aplicados por ordem
+	RequestFilter    *RequestFilter        // Regras com expressões que permitem, negam, desviam ou anotam requisições
+	Hooks            *RouteHooks           // Webhooks chamados antes do backend e notificados após a resposta
+	GraphQL          *GraphQLPolicy        // Análise das operações GraphQL: limites de profundidade e custo e operações permitidas
+	Composite        *CompositeRoute       // Chamadas paralelas a vários backends combinadas em uma única resposta
+	Recording        *RecordingPolicy      // Gravação de uma amostra das requisições e respostas para depuração
+	Deprecation      *RouteDeprecation     // Descontinuação da rota: cabeçalhos Deprecation e Sunset e desativação após a data
+	OpenAPISpec      string                // Documento OpenAPI do backend: caminho no serviço (ex.: /openapi.json) ou URL
+	CreatedAt        time.Time             // Data de criação
+	UpdatedAt        time.Time             // Data de atualização
+}
+
+// UpstreamAuth contém as credenciais básicas usadas para autenticar no serviço de backend.
+// A senha é armazenada cifrada quando a criptografia em repouso está habilitada.
+type UpstreamAuth struct {
+	Username string
+	Password string
+}
+
+// UpstreamTLS configura a conexão TLS entre o gateway e o serviço de backend
+type UpstreamTLS struct {
+	CAFile             string // Bundle PEM de CAs confiáveis adicionais
+	CertFile           string // Certificado de cliente para mTLS com o backend
+	KeyFile            string // Chave privada do certificado de cliente
+	ServerName         string // Sobrescreve o SNI e o nome verificado no certificado
+	MinVersion         string // Versão mínima do TLS: "1.0", "1.1", "1.2" ou "1.3"
+	InsecureSkipVerify bool   // Desabilita a verificação do certificado (apenas legado)
+	InsecureReason     string // Justificativa obrigatória para InsecureSkipVerify, registrada em log
+}
+
+// validTLSVersions são os valores aceitos em UpstreamTLS.MinVersion
+var validTLSVersions = map[string]bool{"": true, "1.0": true, "1.1": true, "1.2": true, "1.3": true}
+
+// Validate verifica a consistência da configuração TLS
+func (t *UpstreamTLS) Validate() error {
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		return errors.New("tls.certFile e tls.keyFile devem ser informados juntos")
+	}
+	if !validTLSVersions[t.MinVersion] {
+		return fmt.Errorf("tls.minVersion inválida: %s", t.MinVersion)
+	}
+	if t.InsecureSkipVerify && strings.TrimSpace(t.InsecureReason) == "" {
+		return errors.New("tls.insecureSkipVerify requer uma justificativa em tls.insecureReason")
+	}
+	return nil
+}
+
+// ResponseCachePolicy habilita o cache das respostas do backend para a rota
+type ResponseCachePolicy struct {
+	TTL                string   // Duração (ex.: "30s", "5m"); vazio usa o padrão global
+	KeyHeaders         []string // Cabeçalhos da requisição que compõem a chave do cache
+	QueryParams        []string // Parâmetros de query que compõem a chave; vazio considera todos
+	IgnoreCacheControl bool     // Ignora o Cache-Control do cliente e do backend, usando apenas o TTL
+}
+
+// Duration retorna o TTL da política, ou o padrão informado quando não definido
+func (p *ResponseCachePolicy) Duration(defaultTTL time.Duration) time.Duration {
+	if p.TTL == "" {
+		return defaultTTL
+	}
+	ttl, err := time.ParseDuration(p.TTL)
+	if err != nil {
+		return defaultTTL
+	}
+	return ttl
+}
+
+// Validate verifica a consistência da política de cache
+func (p *ResponseCachePolicy) Validate() error {
+	for _, name := range p.KeyHeaders {
+		if !isToken(name) {
+			return fmt.Errorf("responseCache.keyHeaders: cabeçalho inválido %q", name)
+		}
+	}
+	for _, name := range p.QueryParams {
+		if name == "" {
+			return errors.New("responseCache.queryParams: nome vazio")
+		}
+	}
+
+	if p.TTL == "" {
+		return nil
+	}
+	ttl, err := time.ParseDuration(p.TTL)
+	if err != nil {
+		return fmt.Errorf("responseCache.ttl inválido: %w", err)
+	}
+	if ttl <= 0 {
+		return errors.New("responseCache.ttl deve ser positivo")
+	}
+	return nil
+}
+
+// CostAttribution define como o uso da rota é atribuído para showback de custos.
+// Cada campo é uma expressão text/template avaliada sobre a requisição, com acesso
+// a .Labels, .Consumer, .Claims, .Headers e .Tenant.
+type CostAttribution struct {
+	Team    string // ex.: `{{ .Labels.team }}`
+	Product string // ex.: `{{ .Claims.product | default .Labels.product }}`
+}
+
+// Validate verifica se as expressões compilam
+func (c *CostAttribution) Validate() error {
+	for name, expr := range map[string]string{"team": c.Team, "product": c.Product} {
+		if expr == "" {
+			continue
+		}
+		if _, err := reqtemplate.Compile(expr); err != nil {
+			return fmt.Errorf("costAttribution.%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// EgressProxyDirect desabilita o proxy de saída global para a rota
+const EgressProxyDirect = "direct"
+
+// redactedSecret substitui valores sensíveis em respostas da API administrativa
+const redactedSecret = "********"
+
+// Redacted retorna uma cópia da rota sem valores sensíveis, adequada para exibição
+func (r *Route) Redacted() *Route {
+	copied := *r
+	if r.EgressProxy != "" && r.EgressProxy != EgressProxyDirect {
+		if u, err := url.Parse(r.EgressProxy); err == nil {
+			copied.EgressProxy = u.Redacted()
+		}
+	}
+	if r.UpstreamAuth != nil {
+		copied.UpstreamAuth = &UpstreamAuth{
+			Username: r.UpstreamAuth.Username,
+			Password: redactedSecret,
+		}
+	}
+	if r.Hooks != nil {
+		copied.Hooks = r.Hooks.Redacted()
+	}
+	return &copied
+}
+
+// HasSecrets indica se a rota contém valores sensíveis, os mesmos mascarados
+// por Redacted
+func (r *Route) HasSecrets() bool {
+	if r.UpstreamAuth != nil || r.Hooks != nil {
+		return true
+	}
+	if r.EgressProxy != "" && r.EgressProxy != EgressProxyDirect {
+		if u, err := url.Parse(r.EgressProxy); err == nil && u.User != nil {
+			_, hasPassword := u.User.Password()
+			return hasPassword
+		}
+	}
+	return false
 }
 
 // AverageResponseTime calcula o tempo médio de resposta
@@ -56,19 +257,336 @@ func (r *Route) Validate() error {
 	if r.Path == "" {
 		return errors.New("path é obrigatório")
 	}
-	if r.ServiceURL == "" {
-		return errors.New("serviceURL é obrigatório")
+	// Rotas com stub ou composição respondem sem um backend próprio
+	if r.ServiceURL == "" && len(r.Targets) == 0 && r.Stub == nil && r.Composite == nil {
+		return errors.New("serviceURL ou targets é obrigatório")
+	}
+	if !strings.HasPrefix(r.Path, "/") {
+		return errors.New("path deve começar com /")
 	}
 	if len(r.Methods) == 0 {
 		return errors.New("ao menos um método HTTP é obrigatório")
 	}
+	for _, method := range r.Methods {
+		if !validMethods[method] {
+			return fmt.Errorf("método HTTP inválido: %q", method)
+		}
+	}
 
 	// Validar URL do serviço
-	_, err := url.Parse(r.ServiceURL)
-	if err != nil {
-		return fmt.Errorf("serviceURL inválida: %w", err)
+	if r.ServiceURL != "" {
+		if err := validateServiceURL(r.ServiceURL); err != nil {
+			return fmt.Errorf("serviceURL inválida: %w", err)
+		}
+	}
+
+	if err := r.validateTargets(); err != nil {
+		return err
+	}
+
+	if r.Canary != nil {
+		if err := r.validateCanary(); err != nil {
+			return err
+		}
 	}
 
+	if err := r.validateMatch(); err != nil {
+		return err
+	}
+
+	if r.UpstreamAuth != nil && r.UpstreamAuth.Username == "" {
+		return errors.New("upstreamAuth requer username")
+	}
+
+	if r.TLS != nil {
+		if err := r.TLS.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if r.EgressProxy != "" && r.EgressProxy != EgressProxyDirect {
+		proxyURL, err := url.Parse(r.EgressProxy)
+		if err != nil {
+			return fmt.Errorf("egressProxy inválido: %w", err)
+		}
+		switch proxyURL.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return fmt.Errorf("egressProxy com esquema não suportado: %q", proxyURL.Scheme)
+		}
+	}
+
+	if r.ResponseCache != nil {
+		if err := r.ResponseCache.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if r.AccessSchedule != nil {
+		if err := r.AccessSchedule.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if r.CostAttribution != nil {
+		if err := r.CostAttribution.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if r.HealthCheck != nil {
+		if err := r.HealthCheck.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if r.Telemetry != nil {
+		if err := r.Telemetry.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if r.CircuitBreaker != nil {
+		if err := r.CircuitBreaker.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if r.WebSocket != nil {
+		if err := r.WebSocket.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if err := r.validateProtocol(); err != nil {
+		return err
+	}
+
+	if r.Auth != nil {
+		if err := r.Auth.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if r.Authorization != nil {
+		if err := r.Authorization.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if r.ExternalAuthz != nil {
+		if err := r.ExternalAuthz.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if r.IPFilter != nil {
+		if err := r.IPFilter.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if r.GeoFilter != nil {
+		if err := r.GeoFilter.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if r.HeaderTransforms != nil {
+		if err := r.HeaderTransforms.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if r.PathRewrite != nil {
+		if err := r.PathRewrite.Validate(r.Path); err != nil {
+			return err
+		}
+	}
+
+	if r.BodyTransforms != nil {
+		if err := r.BodyTransforms.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if err := r.validateTimeout(); err != nil {
+		return err
+	}
+
+	if r.Retry != nil {
+		if err := r.Retry.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if r.Hedging != nil {
+		if err := r.Hedging.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if r.Bulkhead != nil {
+		if err := r.Bulkhead.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if r.Compression != nil {
+		if err := r.Compression.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if r.RequestBody != nil {
+		if err := r.RequestBody.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if err := r.validateOpenAPISpec(); err != nil {
+		return err
+	}
+
+	if r.Validation != nil {
+		if err := r.Validation.Validate(r.OpenAPISpec); err != nil {
+			return err
+		}
+	}
+
+	if r.CORS != nil {
+		if err := r.CORS.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if r.Maintenance != nil {
+		if err := r.Maintenance.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if r.Stub != nil {
+		if err := r.Stub.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if r.Mirror != nil {
+		if err := r.Mirror.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if r.Idempotency != nil {
+		if err := r.Idempotency.Validate(); err != nil {
+			return err
+		}
+	}
+
+	switch r.RateLimitHeaders {
+	case "", "standard", "legacy", "both", "none":
+	default:
+		return fmt.Errorf("rateLimitHeaders inválido: %q (use standard, legacy, both ou none)", r.RateLimitHeaders)
+	}
+
+	if r.SessionAffinity != nil {
+		if err := r.validateAffinity(); err != nil {
+			return err
+		}
+	}
+
+	if r.ConnectionPool != nil {
+		if err := r.ConnectionPool.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if err := r.validatePlugins(); err != nil {
+		return err
+	}
+
+	if r.RequestFilter != nil {
+		if err := r.RequestFilter.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if r.Hooks != nil {
+		if err := r.Hooks.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if r.GraphQL != nil {
+		if err := r.GraphQL.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if r.Composite != nil {
+		if err := r.Composite.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if r.OutlierDetection != nil {
+		if err := r.OutlierDetection.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if r.Recording != nil {
+		if err := r.Recording.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if r.Errors != nil {
+		if err := r.Errors.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if r.Deprecation != nil {
+		if err := r.Deprecation.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if err := r.validateActivation(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validMethods são os métodos aceitos em Methods, em maiúsculas
+var validMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPost:    true,
+	http.MethodPut:     true,
+	http.MethodPatch:   true,
+	http.MethodDelete:  true,
+	http.MethodOptions: true,
+	http.MethodConnect: true,
+	http.MethodTrace:   true,
+}
+
+// validateServiceURL exige uma URL absoluta, com esquema e host, ou um
+// backend resolvido por descoberta de serviços
+func validateServiceURL(raw string) error {
+	if IsDiscoveryTarget(raw) {
+		return validateDiscoveryTarget(raw)
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return errors.New("esquema e host são obrigatórios")
+	}
 	return nil
 }
 
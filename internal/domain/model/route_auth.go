@@ -0,0 +1,39 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Políticas de autenticação dos consumidores das rotas
+const (
+	RouteAuthOIDC   = "oidc"
+	RouteAuthAPIKey = "apikey"
+	RouteAuthJWT    = "jwt"
+	RouteAuthBasic  = "basic"
+	RouteAuthHMAC   = "hmac"
+)
+
+// RouteAuth define como os consumidores da rota são autenticados
+type RouteAuth struct {
+	Type     string // Política de autenticação: "oidc", "apikey", "jwt", "basic" ou "hmac"
+	Provider string // Provedor OIDC configurado em auth.oidc.providers
+	Login    bool   // Redireciona navegadores sem sessão para o login do provedor
+}
+
+// Validate verifica a consistência da política
+func (a *RouteAuth) Validate() error {
+	switch a.Type {
+	case RouteAuthOIDC:
+		if a.Provider == "" {
+			return errors.New("auth.provider é obrigatório para a política oidc")
+		}
+	case RouteAuthAPIKey, RouteAuthJWT, RouteAuthBasic, RouteAuthHMAC:
+		if a.Login {
+			return errors.New("auth.login só se aplica à política oidc")
+		}
+	default:
+		return fmt.Errorf("auth.type inválido: %q", a.Type)
+	}
+	return nil
+}
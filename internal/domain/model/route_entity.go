@@ -6,19 +6,74 @@ import (
 
 // RouteEntity é a representação de banco de dados de uma rota
 type RouteEntity struct {
-	ID                  uint      `gorm:"primaryKey"`
-	Path                string    `gorm:"uniqueIndex;not null"`
-	ServiceURL          string    `gorm:"not null"`
-	MethodsJSON         string    `gorm:"column:methods;type:text"`
-	HeadersJSON         string    `gorm:"column:headers;type:text"`
-	Description         string    `gorm:"type:text"`
-	IsActive            bool      `gorm:"default:true"`
-	CallCount           int64     `gorm:"default:0"`
-	TotalResponse       int64     `gorm:"default:0"` // Armazenado em nanossegundos
-	RequiredHeadersJSON string    `gorm:"column:required_headers;type:text"`
-	CreatedAt           time.Time `gorm:"autoCreateTime"`
-	UpdatedAt           time.Time `gorm:"autoUpdateTime"`
-	LastUpdatedAt       time.Time
+	ID                   uint      `gorm:"primaryKey"`
+	Tenant               string    `gorm:"uniqueIndex:idx_routes_tenant_path;not null;default:'';size:100"`
+	Path                 string    `gorm:"uniqueIndex:idx_routes_tenant_path;not null;size:255"`
+	ServiceURL           string    `gorm:"not null"`
+	MethodsJSON          string    `gorm:"column:methods;type:text"`
+	HeadersJSON          string    `gorm:"column:headers;type:text"`
+	Description          string    `gorm:"type:text"`
+	IsActive             bool      `gorm:"default:true"`
+	CallCount            int64     `gorm:"default:0"`
+	TotalResponse        int64     `gorm:"default:0"` // Armazenado em nanossegundos
+	RequiredHeadersJSON  string    `gorm:"column:required_headers;type:text"`
+	UpstreamAuthJSON     string    `gorm:"column:upstream_auth;type:text"` // Cifrado quando a criptografia em repouso está ativa
+	UpstreamTLSJSON      string    `gorm:"column:upstream_tls;type:text"`
+	EgressProxy          string    `gorm:"column:egress_proxy"`
+	ResponseCacheJSON    string    `gorm:"column:response_cache;type:text"`
+	AccessScheduleJSON   string    `gorm:"column:access_schedule;type:text"`
+	LabelsJSON           string    `gorm:"column:labels;type:text"`
+	CostAttributionJSON  string    `gorm:"column:cost_attribution;type:text"`
+	HealthCheckJSON      string    `gorm:"column:health_check;type:text"`
+	TelemetryJSON        string    `gorm:"column:telemetry;type:text"`
+	TargetsJSON          string    `gorm:"column:targets;type:text"`
+	LoadBalancing        string    `gorm:"column:load_balancing"`
+	CircuitBreakerJSON   string    `gorm:"column:circuit_breaker;type:text"`
+	CanaryJSON           string    `gorm:"column:canary;type:text"`
+	MatchJSON            string    `gorm:"column:match_conditions;type:text"`
+	VariantsJSON         string    `gorm:"column:variants;type:text"`
+	WebSocketJSON        string    `gorm:"column:websocket;type:text"`
+	Protocol             string    `gorm:"column:protocol"`
+	GRPCWeb              bool      `gorm:"column:grpc_web"`
+	AuthJSON             string    `gorm:"column:auth_policy;type:text"`
+	AuthorizationJSON    string    `gorm:"column:authorization;type:text"`
+	ExternalAuthzJSON    string    `gorm:"column:external_authz;type:text"`
+	IPFilterJSON         string    `gorm:"column:ip_filter;type:text"`
+	HeaderTransformsJSON string    `gorm:"column:header_transforms;type:text"`
+	PathRewriteJSON      string    `gorm:"column:path_rewrite;type:text"`
+	BodyTransformsJSON   string    `gorm:"column:body_transforms;type:text"`
+	Timeout              string    `gorm:"column:upstream_timeout"`
+	RetryJSON            string    `gorm:"column:retry_policy;type:text"`
+	HedgingJSON          string    `gorm:"column:hedging_policy;type:text"`
+	BulkheadJSON         string    `gorm:"column:bulkhead_policy;type:text"`
+	CompressionJSON      string    `gorm:"column:compression;type:text"`
+	RequestBodyJSON      string    `gorm:"column:request_body;type:text"`
+	ValidationJSON       string    `gorm:"column:request_validation;type:text"`
+	CORSJSON             string    `gorm:"column:cors;type:text"`
+	MaintenanceJSON      string    `gorm:"column:maintenance;type:text"`
+	StubJSON             string    `gorm:"column:stub;type:text"`
+	MirrorJSON           string    `gorm:"column:mirror;type:text"`
+	IdempotencyJSON      string    `gorm:"column:idempotency;type:text"`
+	AffinityJSON         string    `gorm:"column:session_affinity;type:text"`
+	PoolJSON             string    `gorm:"column:connection_pool;type:text"`
+	PluginsJSON          string    `gorm:"column:plugins;type:text"`
+	FilterJSON           string    `gorm:"column:request_filter;type:text"`
+	HooksJSON            string    `gorm:"column:hooks;type:text"` // Cifrado quando a criptografia em repouso está ativa
+	GraphQLJSON          string    `gorm:"column:graphql;type:text"`
+	CompositeJSON        string    `gorm:"column:composite;type:text"`
+	OutlierJSON          string    `gorm:"column:outlier_detection;type:text"`
+	RecordingJSON        string    `gorm:"column:recording;type:text"`
+	GeoFilterJSON        string    `gorm:"column:geo_filter;type:text"`
+	ErrorsJSON           string    `gorm:"column:error_policy;type:text"`
+	DeprecationJSON      string    `gorm:"column:deprecation;type:text"`
+	ActiveFrom           string    `gorm:"column:active_from"`
+	ActiveUntil          string    `gorm:"column:active_until"`
+	FeatureFlagJSON      string    `gorm:"column:feature_flag;type:text"`
+	OpenAPISpec          string    `gorm:"column:openapi_spec"`
+	RateLimitHeaders     string    `gorm:"column:ratelimit_headers"`
+	CreatedAt            time.Time `gorm:"autoCreateTime"`
+	UpdatedAt            time.Time `gorm:"autoUpdateTime"`
+	LastUpdatedAt        time.Time
 }
 
 // TableName define o nome da tabela
@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// RouteStatsBucket são as estatísticas de uma rota em um minuto, contadas por
+// uma instância do gateway
+type RouteStatsBucket struct {
+	Node     string           // Instância que atendeu as requisições
+	Tenant   string           // Tenant dono da rota; vazio no escopo padrão
+	Route    string           // Caminho registrado da rota
+	Minute   time.Time        // Início do minuto, em UTC
+	Requests int64            // Requisições atendidas, inclusive as rejeitadas pelo gateway
+	Errors   int64            // Requisições com status 5xx
+	Duration time.Duration    // Soma das durações
+	Status   map[string]int64 // Requisições por classe de status (2xx, 4xx...)
+	Latency  []int64          // Requisições por faixa de latência do histograma
+}
+
+// RouteStatsEntity é a representação de banco de dados das estatísticas de
+// uma rota em um minuto
+type RouteStatsEntity struct {
+	Node        string    `gorm:"primaryKey;size:255"`
+	Tenant      string    `gorm:"primaryKey;size:255"`
+	Route       string    `gorm:"primaryKey;size:255"`
+	Minute      time.Time `gorm:"primaryKey"`
+	Requests    int64     `gorm:"not null;default:0"`
+	Errors      int64     `gorm:"not null;default:0"`
+	DurationNs  int64     `gorm:"column:duration_ns;not null;default:0"`
+	StatusJSON  string    `gorm:"column:status;type:text"`
+	LatencyJSON string    `gorm:"column:latency;type:text"`
+}
+
+// TableName define o nome da tabela
+func (RouteStatsEntity) TableName() string {
+	return "route_stats"
+}
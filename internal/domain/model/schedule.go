@@ -0,0 +1,238 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AccessSchedule restringe o acesso a uma rota a janelas de tempo, avaliadas no
+// fuso horário configurado. Períodos de bloqueio têm precedência sobre as janelas.
+type AccessSchedule struct {
+	Timezone  string                     // Fuso IANA (ex.: "America/Sao_Paulo"); vazio usa UTC
+	Windows   []TimeWindow               // Janelas permitidas; vazio permite qualquer horário
+	Blackouts []BlackoutPeriod           // Períodos em que o acesso é negado
+	Consumers map[string]*AccessSchedule // Agendas específicas por consumidor (ID ou username)
+}
+
+// TimeWindow é uma janela de acesso recorrente
+type TimeWindow struct {
+	Days  []string // Dias da semana ("mon".."sun"); vazio vale para todos os dias
+	Start string   // Início no formato HH:MM
+	End   string   // Fim no formato HH:MM; menor que Start atravessa a meia-noite
+}
+
+// BlackoutPeriod é um intervalo absoluto em que o acesso é negado
+type BlackoutPeriod struct {
+	Start  string // RFC3339 ou "2006-01-02T15:04" no fuso da agenda
+	End    string
+	Reason string
+}
+
+// weekdays mapeia as abreviações aceitas em TimeWindow.Days
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// blackoutLayouts são os formatos aceitos nos limites dos períodos de bloqueio
+var blackoutLayouts = []string{time.RFC3339, "2006-01-02T15:04", "2006-01-02 15:04", "2006-01-02"}
+
+// For retorna a agenda aplicável ao consumidor, ou a própria agenda da rota
+func (s *AccessSchedule) For(consumers ...string) *AccessSchedule {
+	for _, consumer := range consumers {
+		if consumer == "" {
+			continue
+		}
+		if schedule, ok := s.Consumers[consumer]; ok && schedule != nil {
+			return schedule
+		}
+	}
+	return s
+}
+
+// Check avalia se o acesso é permitido no instante informado. Quando negado,
+// retorna uma descrição do motivo adequada para o cliente.
+func (s *AccessSchedule) Check(now time.Time) (bool, string) {
+	loc, err := s.location()
+	if err != nil {
+		return false, err.Error()
+	}
+	local := now.In(loc)
+
+	for _, blackout := range s.Blackouts {
+		start, end, err := blackout.bounds(loc)
+		if err != nil {
+			continue
+		}
+		if !local.Before(start) && local.Before(end) {
+			reason := fmt.Sprintf("acesso bloqueado até %s", end.Format(time.RFC3339))
+			if blackout.Reason != "" {
+				reason += ": " + blackout.Reason
+			}
+			return false, reason
+		}
+	}
+
+	if len(s.Windows) == 0 {
+		return true, ""
+	}
+
+	for _, window := range s.Windows {
+		if window.contains(local) {
+			return true, ""
+		}
+	}
+
+	descriptions := make([]string, 0, len(s.Windows))
+	for _, window := range s.Windows {
+		descriptions = append(descriptions, window.String())
+	}
+	return false, fmt.Sprintf("acesso permitido apenas em %s (%s)", strings.Join(descriptions, "; "), loc.String())
+}
+
+// Validate verifica a consistência da agenda
+func (s *AccessSchedule) Validate() error {
+	return s.validate(true)
+}
+
+func (s *AccessSchedule) validate(allowConsumers bool) error {
+	loc, err := s.location()
+	if err != nil {
+		return err
+	}
+
+	for _, window := range s.Windows {
+		if err := window.validate(); err != nil {
+			return err
+		}
+	}
+
+	for _, blackout := range s.Blackouts {
+		start, end, err := blackout.bounds(loc)
+		if err != nil {
+			return err
+		}
+		if !end.After(start) {
+			return fmt.Errorf("accessSchedule.blackouts: fim (%s) deve ser posterior ao início (%s)", blackout.End, blackout.Start)
+		}
+	}
+
+	if len(s.Consumers) > 0 && !allowConsumers {
+		return errors.New("accessSchedule.consumers não pode ser aninhado")
+	}
+	for consumer, schedule := range s.Consumers {
+		if schedule == nil {
+			return fmt.Errorf("accessSchedule.consumers.%s está vazio", consumer)
+		}
+		if err := schedule.validate(false); err != nil {
+			return fmt.Errorf("accessSchedule.consumers.%s: %w", consumer, err)
+		}
+	}
+	return nil
+}
+
+// location carrega o fuso horário da agenda
+func (s *AccessSchedule) location() (*time.Location, error) {
+	if s.Timezone == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("accessSchedule.timezone inválido: %s", s.Timezone)
+	}
+	return loc, nil
+}
+
+// contains verifica se o instante, já no fuso da agenda, está dentro da janela
+func (w TimeWindow) contains(t time.Time) bool {
+	start, _ := parseClock(w.Start)
+	end, _ := parseClock(w.End)
+	minute := t.Hour()*60 + t.Minute()
+
+	if start <= end {
+		return w.matchesDay(t.Weekday()) && minute >= start && minute < end
+	}
+
+	// Janela que atravessa a meia-noite: pertence ao dia em que começa
+	if minute >= start {
+		return w.matchesDay(t.Weekday())
+	}
+	if minute < end {
+		return w.matchesDay((t.Weekday() + 6) % 7)
+	}
+	return false
+}
+
+// matchesDay verifica se a janela vale para o dia da semana
+func (w TimeWindow) matchesDay(day time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+	for _, d := range w.Days {
+		if weekdays[strings.ToLower(d)] == day {
+			return true
+		}
+	}
+	return false
+}
+
+func (w TimeWindow) validate() error {
+	if _, err := parseClock(w.Start); err != nil {
+		return fmt.Errorf("accessSchedule.windows: início inválido: %w", err)
+	}
+	if _, err := parseClock(w.End); err != nil {
+		return fmt.Errorf("accessSchedule.windows: fim inválido: %w", err)
+	}
+	if w.Start == w.End {
+		return errors.New("accessSchedule.windows: início e fim não podem ser iguais")
+	}
+	for _, d := range w.Days {
+		if _, ok := weekdays[strings.ToLower(d)]; !ok {
+			return fmt.Errorf("accessSchedule.windows: dia da semana inválido: %s", d)
+		}
+	}
+	return nil
+}
+
+// String descreve a janela, ex.: "mon,tue 09:00-18:00"
+func (w TimeWindow) String() string {
+	days := "todos os dias"
+	if len(w.Days) > 0 {
+		days = strings.Join(w.Days, ",")
+	}
+	return fmt.Sprintf("%s %s-%s", days, w.Start, w.End)
+}
+
+// bounds interpreta os limites do período no fuso da agenda
+func (b BlackoutPeriod) bounds(loc *time.Location) (time.Time, time.Time, error) {
+	start, err := parseInstant(b.Start, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("accessSchedule.blackouts: início inválido: %w", err)
+	}
+	end, err := parseInstant(b.End, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("accessSchedule.blackouts: fim inválido: %w", err)
+	}
+	return start, end, nil
+}
+
+// parseClock converte "HH:MM" em minutos desde a meia-noite
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("horário %q deve estar no formato HH:MM", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// parseInstant interpreta um instante absoluto, usando o fuso quando não informado
+func parseInstant(value string, loc *time.Location) (time.Time, error) {
+	for _, layout := range blackoutLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("data %q em formato não suportado", value)
+}
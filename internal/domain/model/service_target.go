@@ -0,0 +1,153 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Esquemas dos backends resolvidos por descoberta de serviços
+const (
+	ServiceScheme = "service" // Service do Kubernetes: service://namespace/nome:porta
+	ConsulScheme  = "consul"  // Catálogo do Consul: consul://servico?tag=...&dc=...
+	SRVScheme     = "srv"     // Registros DNS SRV: srv://_http._tcp.exemplo.internal
+)
+
+// IsDiscoveryTarget indica se a URL do backend é resolvida por descoberta de
+// serviços (Kubernetes, Consul ou DNS SRV) em vez de usada diretamente
+func IsDiscoveryTarget(raw string) bool {
+	scheme, _, ok := strings.Cut(raw, "://")
+	if !ok {
+		return false
+	}
+	switch scheme {
+	case ServiceScheme, ConsulScheme, SRVScheme:
+		return true
+	}
+	return false
+}
+
+// validateDiscoveryTarget verifica a URL de um backend resolvido por descoberta
+func validateDiscoveryTarget(raw string) error {
+	var err error
+	switch {
+	case strings.HasPrefix(raw, ServiceScheme+"://"):
+		_, err = ParseServiceTarget(raw)
+	case strings.HasPrefix(raw, ConsulScheme+"://"):
+		_, err = ParseConsulTarget(raw)
+	default:
+		_, err = ParseSRVTarget(raw)
+	}
+	return err
+}
+
+// ServiceTarget é um Service do Kubernetes usado como backend. O tráfego vai
+// direto aos pods prontos do Service, sem passar pelo kube-proxy.
+type ServiceTarget struct {
+	Namespace string
+	Name      string
+	Port      string // número ou nome da porta do Service
+	Scheme    string // esquema usado nos pods: http (padrão) ou https
+}
+
+// ParseServiceTarget interpreta service://namespace/nome:porta. O esquema dos
+// pods pode ser escolhido com ?scheme=https.
+func ParseServiceTarget(raw string) (ServiceTarget, error) {
+	u, scheme, err := parseDiscoveryURL(raw, ServiceScheme)
+	if err != nil {
+		return ServiceTarget{}, err
+	}
+
+	name, port, _ := strings.Cut(strings.TrimPrefix(u.Path, "/"), ":")
+	if u.Host == "" || name == "" || port == "" || strings.Contains(name, "/") {
+		return ServiceTarget{}, errors.New("use service://namespace/nome:porta")
+	}
+
+	return ServiceTarget{
+		Namespace: u.Host,
+		Name:      name,
+		Port:      port,
+		Scheme:    scheme,
+	}, nil
+}
+
+// String retorna a URL service:// do alvo
+func (t ServiceTarget) String() string {
+	raw := fmt.Sprintf("%s://%s/%s:%s", ServiceScheme, t.Namespace, t.Name, t.Port)
+	if t.Scheme != "" && t.Scheme != "http" {
+		raw += "?scheme=" + t.Scheme
+	}
+	return raw
+}
+
+// ConsulTarget é um serviço do catálogo do Consul usado como backend
+type ConsulTarget struct {
+	Service    string
+	Tag        string // filtra as instâncias pela tag (ex.: blue ou green)
+	Datacenter string // vazio usa o datacenter configurado
+	Scheme     string // esquema usado nas instâncias: http (padrão) ou https
+}
+
+// ParseConsulTarget interpreta consul://servico, com os parâmetros opcionais
+// tag, dc e scheme (ex.: consul://orders?tag=green&dc=dc2)
+func ParseConsulTarget(raw string) (ConsulTarget, error) {
+	u, scheme, err := parseDiscoveryURL(raw, ConsulScheme)
+	if err != nil {
+		return ConsulTarget{}, err
+	}
+	if u.Host == "" || strings.Trim(u.Path, "/") != "" {
+		return ConsulTarget{}, errors.New("use consul://servico")
+	}
+
+	return ConsulTarget{
+		Service:    u.Host,
+		Tag:        u.Query().Get("tag"),
+		Datacenter: u.Query().Get("dc"),
+		Scheme:     scheme,
+	}, nil
+}
+
+// SRVTarget é um nome DNS cujos registros SRV indicam as instâncias do backend
+type SRVTarget struct {
+	Name   string // ex.: _http._tcp.orders.internal
+	Scheme string // esquema usado nas instâncias: http (padrão) ou https
+}
+
+// ParseSRVTarget interpreta srv://nome (ex.: srv://_http._tcp.orders.internal)
+func ParseSRVTarget(raw string) (SRVTarget, error) {
+	u, scheme, err := parseDiscoveryURL(raw, SRVScheme)
+	if err != nil {
+		return SRVTarget{}, err
+	}
+	if u.Host == "" || u.Port() != "" || strings.Trim(u.Path, "/") != "" {
+		return SRVTarget{}, errors.New("use srv://nome, sem porta")
+	}
+
+	return SRVTarget{
+		Name:   u.Host,
+		Scheme: scheme,
+	}, nil
+}
+
+// parseDiscoveryURL interpreta a URL do backend e o esquema das instâncias,
+// escolhido com ?scheme=
+func parseDiscoveryURL(raw, expected string) (*url.URL, string, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, "", err
+	}
+	if u.Scheme != expected {
+		return nil, "", fmt.Errorf("esquema %q não é %s://", u.Scheme, expected)
+	}
+
+	scheme := u.Query().Get("scheme")
+	switch scheme {
+	case "":
+		scheme = "http"
+	case "http", "https":
+	default:
+		return nil, "", fmt.Errorf("scheme inválido: %q (use http ou https)", scheme)
+	}
+	return u, scheme, nil
+}
@@ -0,0 +1,86 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/diillson/api-gateway-go/pkg/reqtemplate"
+)
+
+// StaticResponse é uma resposta definida na rota e devolvida pelo próprio
+// gateway, sem consultar o backend
+type StaticResponse struct {
+	Status  int               // Código HTTP da resposta
+	Headers map[string]string // Cabeçalhos da resposta; aceitam expressões (ex.: {{ .Params.id }})
+	Body    string            // Corpo da resposta; aceita expressões
+}
+
+// Validate verifica o status e as expressões da resposta
+func (r *StaticResponse) Validate(field string) error {
+	if r.Status != 0 && (r.Status < 200 || r.Status > 599) {
+		return fmt.Errorf("%s.status inválido: %d", field, r.Status)
+	}
+	for name, value := range r.Headers {
+		if name == "" {
+			return fmt.Errorf("%s.headers não aceita nomes vazios", field)
+		}
+		if _, err := reqtemplate.Compile(value); err != nil {
+			return fmt.Errorf("%s.headers.%s: %w", field, name, err)
+		}
+	}
+	if _, err := reqtemplate.Compile(r.Body); err != nil {
+		return fmt.Errorf("%s.body: %w", field, err)
+	}
+	return nil
+}
+
+// MaintenanceMode coloca a rota em manutenção: enquanto habilitado, todas as
+// requisições recebem a resposta configurada (padrão 503)
+type MaintenanceMode struct {
+	Enabled bool
+	StaticResponse
+	RetryAfter string // Previsão de retorno informada no Retry-After (ex.: "30m")
+}
+
+// Validate verifica a consistência do modo de manutenção
+func (m *MaintenanceMode) Validate() error {
+	if m.RetryAfter != "" {
+		if d, err := time.ParseDuration(m.RetryAfter); err != nil || d <= 0 {
+			return fmt.Errorf("maintenance.retryAfter inválido: %q", m.RetryAfter)
+		}
+	}
+	return m.StaticResponse.Validate("maintenance")
+}
+
+// RetryAfterSeconds retorna a previsão de retorno em segundos; zero sem previsão
+func (m *MaintenanceMode) RetryAfterSeconds() int {
+	d, err := time.ParseDuration(m.RetryAfter)
+	if err != nil || d <= 0 {
+		return 0
+	}
+	return int((d + time.Second - 1) / time.Second)
+}
+
+// StubResponse substitui o backend da rota por uma resposta fixa, para rotas
+// ainda sem backend (ex.: desenvolvimento do frontend). As demais políticas da
+// rota, como autenticação e cotas, continuam aplicadas.
+type StubResponse struct {
+	StaticResponse
+	Delay string // Atraso simulado antes da resposta (ex.: "200ms")
+}
+
+// Validate verifica a consistência da resposta simulada
+func (s *StubResponse) Validate() error {
+	if s.Delay != "" {
+		if d, err := time.ParseDuration(s.Delay); err != nil || d < 0 {
+			return fmt.Errorf("stub.delay inválido: %q", s.Delay)
+		}
+	}
+	return s.StaticResponse.Validate("stub")
+}
+
+// DelayDuration retorna o atraso simulado; zero sem atraso
+func (s *StubResponse) DelayDuration() time.Duration {
+	d, _ := time.ParseDuration(s.Delay)
+	return d
+}
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/diillson/api-gateway-go/pkg/reqtemplate"
+)
+
+// RouteTelemetry adiciona contexto de negócio aos traces da rota. Os valores
+// podem ser fixos ou expressões text/template avaliadas sobre a requisição,
+// com acesso a .Labels, .Consumer, .Claims, .Headers, .Params e .Tenant.
+type RouteTelemetry struct {
+	Attributes map[string]string // Atributos do span (ex.: "product": `{{ .Labels.product }}`)
+	Baggage    map[string]string // Entradas de baggage propagadas aos backends
+}
+
+// Validate verifica os nomes e se as expressões compilam
+func (t *RouteTelemetry) Validate() error {
+	for name, expr := range t.Attributes {
+		if name == "" {
+			return fmt.Errorf("telemetry.attributes: nome de atributo vazio")
+		}
+		if _, err := reqtemplate.Compile(expr); err != nil {
+			return fmt.Errorf("telemetry.attributes.%s: %w", name, err)
+		}
+	}
+	for key, expr := range t.Baggage {
+		if !isToken(key) {
+			return fmt.Errorf("telemetry.baggage: chave inválida %q", key)
+		}
+		if _, err := reqtemplate.Compile(expr); err != nil {
+			return fmt.Errorf("telemetry.baggage.%s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// isToken indica se s é um token HTTP válido (RFC 7230), formato exigido
+// para as chaves de baggage do W3C
+func isToken(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c < 128 && strings.ContainsRune("!#$%&'*+-.^_`|~", c):
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,75 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Estratégias de balanceamento entre os alvos da rota
+const (
+	LoadBalanceRoundRobin       = "round-robin"
+	LoadBalanceLeastConnections = "least-connections"
+	LoadBalanceWeighted         = "weighted"
+)
+
+// Target é um backend que recebe parte do tráfego da rota
+type Target struct {
+	URL     string // URL do backend
+	Weight  int    // Peso na estratégia weighted e no canary; zero equivale a 1
+	Version string // Versão do backend (ex.: "v2"), usada pelo roteamento canary
+}
+
+// EffectiveWeight retorna o peso do alvo, com mínimo de 1
+func (t Target) EffectiveWeight() int {
+	if t.Weight <= 0 {
+		return 1
+	}
+	return t.Weight
+}
+
+// Upstreams retorna os alvos da rota. Rotas sem Targets usam apenas ServiceURL.
+func (r *Route) Upstreams() []Target {
+	if len(r.Targets) > 0 {
+		return r.Targets
+	}
+	return []Target{{URL: r.ServiceURL, Weight: 1}}
+}
+
+// Balancing retorna a estratégia de balanceamento, round-robin por padrão
+func (r *Route) Balancing() string {
+	if r.LoadBalancing == "" {
+		return LoadBalanceRoundRobin
+	}
+	return r.LoadBalancing
+}
+
+// validateTargets verifica os alvos e a estratégia de balanceamento
+func (r *Route) validateTargets() error {
+	switch r.LoadBalancing {
+	case "", LoadBalanceRoundRobin, LoadBalanceLeastConnections, LoadBalanceWeighted:
+	default:
+		return fmt.Errorf("loadBalancing inválido: %q", r.LoadBalancing)
+	}
+
+	seen := make(map[string]bool, len(r.Targets))
+	for i, target := range r.Targets {
+		if target.URL == "" {
+			return fmt.Errorf("targets[%d]: url é obrigatória", i)
+		}
+		if err := validateServiceURL(target.URL); err != nil {
+			return fmt.Errorf("targets[%d]: url inválida: %w", i, err)
+		}
+		if target.Weight < 0 {
+			return fmt.Errorf("targets[%d]: weight não pode ser negativo", i)
+		}
+		if seen[target.URL] {
+			return fmt.Errorf("targets[%d]: alvo duplicado %s", i, target.URL)
+		}
+		seen[target.URL] = true
+	}
+
+	if r.LoadBalancing != "" && len(r.Targets) == 0 {
+		return errors.New("loadBalancing requer targets")
+	}
+	return nil
+}
@@ -8,6 +8,7 @@ type User struct {
 	Username string `json:"username"`
 	Role     string `json:"role"`
 	Email    string `json:"email,omitempty"`
+	Tenant   string `json:"tenant,omitempty"` // vazio para usuários com acesso a todos os tenants
 }
 
 // UserEntity é a representação de banco de dados de um usuário
@@ -17,6 +18,7 @@ type UserEntity struct {
 	Password  string    `gorm:"not null"`
 	Email     string    `gorm:"uniqueIndex;size:100"`
 	Role      string    `gorm:"default:user;size:20"`
+	Tenant    string    `gorm:"default:'';size:100"`
 	CreatedAt time.Time `gorm:"autoCreateTime"`
 	UpdatedAt time.Time `gorm:"autoUpdateTime"`
 }
@@ -0,0 +1,61 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// defaultWebSocketIdleTimeout encerra conexões WebSocket sem tráfego em nenhum
+// dos sentidos
+const defaultWebSocketIdleTimeout = 5 * time.Minute
+
+// WebSocketPolicy configura o proxy de conexões WebSocket da rota
+type WebSocketPolicy struct {
+	Subprotocols   []string // Subprotocolos aceitos; vazio repassa os oferecidos pelo cliente
+	IdleTimeout    string   // Tempo sem tráfego antes de encerrar a conexão (padrão "5m")
+	MaxConnections int      // Conexões simultâneas por instância; zero não limita
+}
+
+// IdleTimeoutDuration retorna o tempo máximo sem tráfego da conexão
+func (p *WebSocketPolicy) IdleTimeoutDuration() time.Duration {
+	if p == nil {
+		return defaultWebSocketIdleTimeout
+	}
+	return parseDurationOr(p.IdleTimeout, defaultWebSocketIdleTimeout)
+}
+
+// AllowsSubprotocol indica se o subprotocolo pode ser negociado com o backend
+func (p *WebSocketPolicy) AllowsSubprotocol(name string) bool {
+	if p == nil || len(p.Subprotocols) == 0 {
+		return true
+	}
+	for _, allowed := range p.Subprotocols {
+		if allowed == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate verifica a consistência da política
+func (p *WebSocketPolicy) Validate() error {
+	for _, name := range p.Subprotocols {
+		if !isToken(name) {
+			return fmt.Errorf("webSocket.subprotocols: subprotocolo inválido %q", name)
+		}
+	}
+	if p.MaxConnections < 0 {
+		return errors.New("webSocket.maxConnections não pode ser negativo")
+	}
+	if p.IdleTimeout != "" {
+		d, err := time.ParseDuration(p.IdleTimeout)
+		if err != nil {
+			return fmt.Errorf("webSocket.idleTimeout inválido: %w", err)
+		}
+		if d <= 0 {
+			return errors.New("webSocket.idleTimeout deve ser positivo")
+		}
+	}
+	return nil
+}
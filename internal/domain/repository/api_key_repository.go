@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+)
+
+var ErrAPIKeyNotFound = errors.New("api key not found")
+
+// APIKeyRepository define a interface para armazenamento de chaves de API
+type APIKeyRepository interface {
+	// CreateAPIKey armazena uma nova chave com o hash do seu valor
+	CreateAPIKey(ctx context.Context, key *model.APIKey, keyHash string) error
+
+	// GetAPIKeyByHash obtém a chave pelo hash do seu valor
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (*model.APIKey, error)
+
+	// ListAPIKeys retorna todas as chaves, inclusive as revogadas
+	ListAPIKeys(ctx context.Context) ([]*model.APIKey, error)
+
+	// RevokeAPIKey revoga a chave pelo ID e retorna o hash do seu valor
+	RevokeAPIKey(ctx context.Context, id string) (string, error)
+
+	// TouchAPIKey registra o último uso da chave
+	TouchAPIKey(ctx context.Context, id string, usedAt time.Time) error
+
+	// UpdateAPIKeyHash substitui o hash armazenado da chave
+	UpdateAPIKeyHash(ctx context.Context, id, keyHash string) error
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+)
+
+// AuditFilter restringe a consulta do log de auditoria; campos vazios não filtram
+type AuditFilter struct {
+	Action   string
+	Resource string
+	Actor    string
+	From     time.Time
+	To       time.Time
+	Offset   int
+	Limit    int
+}
+
+// AuditRepository armazena o log de auditoria. Não há operações de alteração
+// ou exclusão: os registros são imutáveis.
+type AuditRepository interface {
+	// CreateAuditRecord grava um novo registro
+	CreateAuditRecord(ctx context.Context, record *model.AuditRecord) error
+
+	// ListAuditRecords retorna os registros do filtro, do mais recente ao mais
+	// antigo, e o total sem paginação
+	ListAuditRecords(ctx context.Context, filter AuditFilter) ([]*model.AuditRecord, int64, error)
+}
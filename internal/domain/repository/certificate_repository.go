@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrCertificateNotFound = errors.New("certificate not found")
+
+// CertificateRepository define a interface para armazenamento dos dados do ACME
+type CertificateRepository interface {
+	// GetCertificate obtém os dados gravados na chave
+	GetCertificate(ctx context.Context, key string) ([]byte, error)
+
+	// PutCertificate grava os dados na chave, substituindo os anteriores
+	PutCertificate(ctx context.Context, key string, data []byte) error
+
+	// DeleteCertificate remove os dados da chave
+	DeleteCertificate(ctx context.Context, key string) error
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+)
+
+var (
+	ErrCredentialNotFound = errors.New("credential not found")
+	ErrCredentialExists   = errors.New("credential already exists")
+)
+
+// CredentialRepository define a interface para armazenamento das credenciais
+// de parceiros (HTTP Basic e HMAC)
+type CredentialRepository interface {
+	// CreateCredential armazena uma nova credencial com o seu segredo (hash da
+	// senha ou segredo de assinatura)
+	CreateCredential(ctx context.Context, credential *model.Credential, secret string) error
+
+	// GetCredentialByIdentifier obtém a credencial e o seu segredo pelo usuário
+	// ou ID da chave de assinatura
+	GetCredentialByIdentifier(ctx context.Context, identifier string) (*model.Credential, string, error)
+
+	// ListCredentials retorna todas as credenciais, inclusive as revogadas
+	ListCredentials(ctx context.Context) ([]*model.Credential, error)
+
+	// RevokeCredential revoga a credencial pelo ID e retorna o seu identificador
+	RevokeCredential(ctx context.Context, id string) (string, error)
+
+	// TouchCredential registra o último uso da credencial
+	TouchCredential(ctx context.Context, id string, usedAt time.Time) error
+}
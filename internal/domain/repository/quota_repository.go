@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+)
+
+// QuotaRepository armazena os contadores de uso dos consumidores. O Redis
+// mantém os valores correntes; o banco guarda a cópia durável e o histórico.
+type QuotaRepository interface {
+	// SaveUsage grava os totais informados, substituindo os já gravados
+	SaveUsage(ctx context.Context, usage []model.ConsumerUsage) error
+
+	// GetUsage retorna o total gravado do consumidor no período; 0 se não houver
+	GetUsage(ctx context.Context, consumer, period string) (int64, error)
+
+	// ListUsage retorna os períodos gravados do consumidor, do mais recente ao
+	// mais antigo
+	ListUsage(ctx context.Context, consumer string, limit int) ([]model.ConsumerUsage, error)
+}
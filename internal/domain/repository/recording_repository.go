@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+)
+
+// ErrRecordingNotFound indica que a gravação não existe
+var ErrRecordingNotFound = errors.New("recording not found")
+
+// RecordingFilter restringe a consulta das gravações; campos vazios não filtram
+type RecordingFilter struct {
+	Tenant    string
+	Route     string
+	Method    string
+	MinStatus int
+	From      time.Time
+	To        time.Time
+	Offset    int
+	Limit     int
+}
+
+// RecordingRepository armazena as requisições e respostas gravadas das rotas
+type RecordingRepository interface {
+	// SaveRecordings grava as gravações informadas
+	SaveRecordings(ctx context.Context, recordings []*model.Recording) error
+
+	// ListRecordings retorna as gravações do filtro, da mais recente à mais
+	// antiga, e o total sem paginação
+	ListRecordings(ctx context.Context, filter RecordingFilter) ([]*model.Recording, int64, error)
+
+	// GetRecording retorna a gravação pelo ID
+	GetRecording(ctx context.Context, id string) (*model.Recording, error)
+
+	// DeleteBefore remove as gravações anteriores ao momento informado
+	DeleteBefore(ctx context.Context, before time.Time) error
+}
@@ -35,3 +35,9 @@ type RouteRepository interface {
 	// GetRoutesWithFilters obtém rotas com filtros aplicados (opcional)
 	GetRoutesWithFilters(ctx context.Context, filters map[string]interface{}) ([]*model.Route, error)
 }
+
+// SecretReEncrypter é implementado por repositórios que armazenam campos cifrados e
+// conseguem cifrá-los novamente com a chave ativa após uma rotação
+type SecretReEncrypter interface {
+	ReEncryptSecrets(ctx context.Context) (int, error)
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+)
+
+// RouteStatsRepository armazena as estatísticas por minuto das rotas,
+// gravadas por cada instância do gateway
+type RouteStatsRepository interface {
+	// SaveBuckets grava as estatísticas informadas, substituindo as já gravadas
+	// para a mesma instância, rota e minuto
+	SaveBuckets(ctx context.Context, buckets []model.RouteStatsBucket) error
+
+	// ListBuckets retorna as estatísticas da rota, de todas as instâncias, a
+	// partir do minuto informado
+	ListBuckets(ctx context.Context, tenant, route string, since time.Time) ([]model.RouteStatsBucket, error)
+
+	// DeleteBefore remove as estatísticas anteriores ao minuto informado
+	DeleteBefore(ctx context.Context, before time.Time) error
+}
@@ -3,8 +3,6 @@ package service
 import (
 	"github.com/diillson/api-gateway-go/internal/app/auth"
 	"github.com/diillson/api-gateway-go/internal/app/route"
-	"github.com/diillson/api-gateway-go/internal/domain/repository"
-	"github.com/diillson/api-gateway-go/pkg/cache"
 	"github.com/diillson/api-gateway-go/pkg/security"
 	"go.uber.org/zap"
 )
@@ -15,8 +13,10 @@ type Services struct {
 	AuthService  *auth.AuthService
 }
 
-// NewServices cria todos os serviços necessários
-func NewServices(routeRepo repository.RouteRepository, userRepo auth.UserRepository, cache cache.Cache, logger *zap.Logger) (*Services, error) {
+// NewServices cria todos os serviços necessários. O serviço de rotas é o mesmo
+// usado pela API administrativa, para que o índice de rotas em memória seja
+// invalidado pelas alterações feitas por ela.
+func NewServices(routeService *route.Service, userRepo auth.UserRepository, logger *zap.Logger) (*Services, error) {
 	// Criar gerenciador de chaves
 	keyManager, err := security.NewKeyManager(logger)
 	if err != nil {
@@ -26,9 +26,6 @@ func NewServices(routeRepo repository.RouteRepository, userRepo auth.UserReposit
 	// Criar serviço de autenticação
 	authService := auth.NewAuthService(keyManager, userRepo, logger)
 
-	// Criar serviço de rotas
-	routeService := route.NewService(routeRepo, cache, logger)
-
 	return &Services{
 		RouteService: routeService,
 		AuthService:  authService,
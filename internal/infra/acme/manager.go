@@ -0,0 +1,53 @@
+package acme
+
+import (
+	"crypto/tls"
+	"strings"
+
+	"github.com/diillson/api-gateway-go/pkg/config"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// NewManager cria o gerenciador que emite e renova os certificados dos
+// domínios, atendendo os desafios HTTP-01 e TLS-ALPN-01
+func NewManager(cfg config.ACMEConfig, domains []string, store autocert.Cache) *autocert.Manager {
+	manager := &autocert.Manager{
+		Prompt:      autocert.AcceptTOS,
+		HostPolicy:  autocert.HostWhitelist(domains...),
+		Cache:       store,
+		Email:       cfg.Email,
+		RenewBefore: cfg.RenewBefore,
+	}
+	if cfg.DirectoryURL != "" {
+		manager.Client = &acme.Client{DirectoryURL: cfg.DirectoryURL}
+	}
+	return manager
+}
+
+// ServerTLSConfig completa a configuração TLS do servidor com a seleção do
+// certificado pelo SNI: os domínios do ACME usam os certificados emitidos e os
+// demais nomes usam o certificado próprio, quando houver. O protocolo
+// acme-tls/1 é anunciado para os desafios TLS-ALPN-01.
+func ServerTLSConfig(base *tls.Config, manager *autocert.Manager, domains []string, fallback *tls.Certificate) *tls.Config {
+	tlsConfig := base.Clone()
+	tlsConfig.NextProtos = []string{"h2", "http/1.1", acme.ALPNProto}
+
+	managed := make(map[string]bool, len(domains))
+	for _, domain := range domains {
+		managed[strings.ToLower(domain)] = true
+	}
+
+	tlsConfig.GetCertificate = func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+		if fallback != nil && !managed[strings.ToLower(strings.TrimSuffix(hello.ServerName, "."))] && !isChallenge(hello) {
+			return fallback, nil
+		}
+		return manager.GetCertificate(hello)
+	}
+	return tlsConfig
+}
+
+// isChallenge identifica a conexão de validação do TLS-ALPN-01
+func isChallenge(hello *tls.ClientHelloInfo) bool {
+	return len(hello.SupportedProtos) == 1 && hello.SupportedProtos[0] == acme.ALPNProto
+}
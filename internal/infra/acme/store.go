@@ -0,0 +1,74 @@
+// Package acme integra a emissão automática de certificados (Let's Encrypt) ao
+// servidor do gateway, com armazenamento compartilhado entre as instâncias.
+package acme
+
+import (
+	"context"
+	"errors"
+
+	"github.com/diillson/api-gateway-go/internal/domain/repository"
+	"github.com/diillson/api-gateway-go/pkg/cache"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// cacheKeyPrefix separa os dados do ACME das demais chaves do cache
+const cacheKeyPrefix = "acme:"
+
+// cacheStore guarda certificados, a chave da conta e os desafios HTTP-01 no
+// cache da aplicação. Com Redis, qualquer instância atende os desafios e usa
+// os certificados emitidos pelas demais.
+type cacheStore struct {
+	cache cache.Cache
+}
+
+// NewCacheStore cria o armazenamento do autocert sobre o cache da aplicação
+func NewCacheStore(c cache.Cache) autocert.Cache {
+	return &cacheStore{cache: c}
+}
+
+func (s *cacheStore) Get(ctx context.Context, key string) ([]byte, error) {
+	var data []byte
+	found, err := s.cache.Get(ctx, cacheKeyPrefix+key, &data)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, autocert.ErrCacheMiss
+	}
+	return data, nil
+}
+
+func (s *cacheStore) Put(ctx context.Context, key string, data []byte) error {
+	// Sem expiração: a renovação é controlada pelo autocert
+	return s.cache.Set(ctx, cacheKeyPrefix+key, data, 0)
+}
+
+func (s *cacheStore) Delete(ctx context.Context, key string) error {
+	return s.cache.Delete(ctx, cacheKeyPrefix+key)
+}
+
+// repositoryStore guarda os dados do ACME no banco de dados
+type repositoryStore struct {
+	repo repository.CertificateRepository
+}
+
+// NewRepositoryStore cria o armazenamento do autocert sobre o repositório
+func NewRepositoryStore(repo repository.CertificateRepository) autocert.Cache {
+	return &repositoryStore{repo: repo}
+}
+
+func (s *repositoryStore) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := s.repo.GetCertificate(ctx, key)
+	if errors.Is(err, repository.ErrCertificateNotFound) {
+		return nil, autocert.ErrCacheMiss
+	}
+	return data, err
+}
+
+func (s *repositoryStore) Put(ctx context.Context, key string, data []byte) error {
+	return s.repo.PutCertificate(ctx, key, data)
+}
+
+func (s *repositoryStore) Delete(ctx context.Context, key string) error {
+	return s.repo.DeleteCertificate(ctx, key)
+}
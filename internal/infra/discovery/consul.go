@@ -0,0 +1,144 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/pkg/config"
+	"go.uber.org/zap"
+)
+
+// ConsulSource resolve consul://servico nas instâncias registradas no
+// catálogo do Consul, filtradas pelo estado das verificações de saúde
+type ConsulSource struct {
+	poller      *poller
+	address     string
+	token       string
+	datacenter  string
+	onlyPassing bool
+	client      *http.Client
+}
+
+// NewConsulSource cria a fonte do Consul; as consultas encerram com ctx
+func NewConsulSource(ctx context.Context, cfg config.ConsulDiscoveryConfig, logger *zap.Logger) *ConsulSource {
+	s := &ConsulSource{
+		address:     strings.TrimRight(cfg.Address, "/"),
+		token:       cfg.Token,
+		datacenter:  cfg.Datacenter,
+		onlyPassing: cfg.OnlyPassing,
+		client:      &http.Client{Timeout: fetchTimeout},
+	}
+	s.poller = newPoller(ctx, "consul", s.fetch, cfg.RefreshInterval, logger)
+	return s
+}
+
+// Resolve retorna as instâncias do serviço, com os pesos registrados no Consul
+func (s *ConsulSource) Resolve(ctx context.Context, raw string) ([]model.Target, error) {
+	return s.poller.Resolve(ctx, raw)
+}
+
+// fetch consulta as instâncias do serviço no endpoint de saúde do catálogo
+func (s *ConsulSource) fetch(ctx context.Context, raw string) ([]model.Target, error) {
+	target, err := model.ParseConsulTarget(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	if s.onlyPassing {
+		query.Set("passing", "true")
+	}
+	if target.Tag != "" {
+		query.Set("tag", target.Tag)
+	}
+	if dc := target.Datacenter; dc != "" {
+		query.Set("dc", dc)
+	} else if s.datacenter != "" {
+		query.Set("dc", s.datacenter)
+	}
+
+	endpoint := fmt.Sprintf("%s/v1/health/service/%s?%s", s.address, url.PathEscape(target.Service), query.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	if s.token != "" {
+		req.Header.Set("X-Consul-Token", s.token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao consultar o Consul: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("Consul retornou status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var entries []struct {
+		Node struct {
+			Address string `json:"Address"`
+		} `json:"Node"`
+		Service struct {
+			Address string `json:"Address"`
+			Port    int    `json:"Port"`
+			Weights struct {
+				Passing int `json:"Passing"`
+				Warning int `json:"Warning"`
+			} `json:"Weights"`
+		} `json:"Service"`
+		Checks []struct {
+			Status string `json:"Status"`
+		} `json:"Checks"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("resposta inválida do Consul: %w", err)
+	}
+
+	targets := make([]model.Target, 0, len(entries))
+	for _, entry := range entries {
+		// Sem o filtro de aprovadas, instâncias em estado crítico ficam de fora
+		// e as com alerta recebem o peso de alerta
+		status := "passing"
+		for _, check := range entry.Checks {
+			if check.Status == "critical" {
+				status = "critical"
+				break
+			}
+			if check.Status == "warning" {
+				status = "warning"
+			}
+		}
+		if status == "critical" {
+			continue
+		}
+		weight := entry.Service.Weights.Passing
+		if status == "warning" {
+			weight = entry.Service.Weights.Warning
+		}
+
+		address := entry.Service.Address
+		if address == "" {
+			address = entry.Node.Address
+		}
+		targets = append(targets, model.Target{
+			URL:    target.Scheme + "://" + net.JoinHostPort(address, strconv.Itoa(entry.Service.Port)),
+			Weight: weight,
+		})
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].URL < targets[j].URL
+	})
+	return targets, nil
+}
@@ -0,0 +1,43 @@
+// Package discovery resolve os backends declarados por descoberta de serviços
+// — Services do Kubernetes, catálogo do Consul e registros DNS SRV — nas
+// instâncias que recebem o tráfego.
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+)
+
+// Source resolve os alvos de um esquema nas instâncias do backend
+type Source interface {
+	Resolve(ctx context.Context, raw string) ([]model.Target, error)
+}
+
+// Resolver encaminha cada alvo à fonte do seu esquema
+type Resolver struct {
+	sources map[string]Source
+}
+
+// NewResolver cria um resolvedor sem fontes
+func NewResolver() *Resolver {
+	return &Resolver{sources: make(map[string]Source)}
+}
+
+// Register associa a fonte ao esquema (ex.: model.ConsulScheme). Deve ser
+// chamado antes do uso concorrente.
+func (r *Resolver) Register(scheme string, source Source) {
+	r.sources[scheme] = source
+}
+
+// Resolve retorna as instâncias do alvo, com pesos relativos entre si
+func (r *Resolver) Resolve(ctx context.Context, raw string) ([]model.Target, error) {
+	scheme, _, _ := strings.Cut(raw, "://")
+	source, ok := r.sources[scheme]
+	if !ok {
+		return nil, fmt.Errorf("descoberta de serviços %s:// desabilitada na configuração (upstream)", scheme)
+	}
+	return source.Resolve(ctx, raw)
+}
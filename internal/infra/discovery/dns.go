@@ -0,0 +1,73 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/pkg/config"
+	"go.uber.org/zap"
+)
+
+// SRVSource resolve srv://nome nas instâncias indicadas pelos registros DNS
+// SRV do nome. Apenas os registros de menor prioridade recebem tráfego; os
+// demais são reservas usadas quando os primeiros deixam de ser publicados.
+type SRVSource struct {
+	poller   *poller
+	resolver *net.Resolver
+}
+
+// NewSRVSource cria a fonte de registros SRV; as consultas encerram com ctx
+func NewSRVSource(ctx context.Context, cfg config.DNSDiscoveryConfig, logger *zap.Logger) *SRVSource {
+	s := &SRVSource{resolver: net.DefaultResolver}
+	s.poller = newPoller(ctx, "dns-srv", s.fetch, cfg.RefreshInterval, logger)
+	return s
+}
+
+// Resolve retorna as instâncias do nome, com os pesos dos registros SRV
+func (s *SRVSource) Resolve(ctx context.Context, raw string) ([]model.Target, error) {
+	return s.poller.Resolve(ctx, raw)
+}
+
+// fetch consulta os registros SRV do nome
+func (s *SRVSource) fetch(ctx context.Context, raw string) ([]model.Target, error) {
+	target, err := model.ParseSRVTarget(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	_, records, err := s.resolver.LookupSRV(ctx, "", "", target.Name)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao consultar registros SRV de %s: %w", target.Name, err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	priority := records[0].Priority
+	for _, record := range records {
+		if record.Priority < priority {
+			priority = record.Priority
+		}
+	}
+
+	targets := make([]model.Target, 0, len(records))
+	for _, record := range records {
+		if record.Priority != priority {
+			continue
+		}
+		host := strings.TrimSuffix(record.Target, ".")
+		targets = append(targets, model.Target{
+			URL:    target.Scheme + "://" + net.JoinHostPort(host, strconv.Itoa(int(record.Port))),
+			Weight: int(record.Weight), // peso 0 equivale a 1
+		})
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].URL < targets[j].URL
+	})
+	return targets, nil
+}
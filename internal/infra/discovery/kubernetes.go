@@ -0,0 +1,483 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/pkg/config"
+	"github.com/diillson/api-gateway-go/pkg/kubeapi"
+	"go.uber.org/zap"
+)
+
+const (
+	// watchTimeout é a duração de cada watch; ao fim, os endpoints são listados de novo
+	watchTimeout = 5 * time.Minute
+
+	// Espera entre tentativas após falhas na API, dobrada a cada falha
+	initialBackoff = time.Second
+	maxBackoff     = 30 * time.Second
+)
+
+// KubernetesResolver acompanha as EndpointSlices (ou Endpoints, em clusters
+// sem EndpointSlice) dos Services usados pelas rotas e resolve cada
+// service://namespace/nome:porta nos endereços dos pods prontos. Cada Service
+// passa a ser acompanhado no primeiro uso. A service account precisa das
+// permissões get em services e list/watch em endpointslices ou endpoints.
+type KubernetesResolver struct {
+	ctx         context.Context
+	client      *kubeapi.Client
+	syncTimeout time.Duration
+	logger      *zap.Logger
+
+	mu       sync.Mutex
+	services map[string]*service // namespace/nome -> estado
+}
+
+// service é o estado acompanhado de um Service
+type service struct {
+	namespace string
+	name      string
+	legacy    bool // usa a API Endpoints
+
+	ready     chan struct{} // fechado após a primeira listagem, com ou sem sucesso
+	readyOnce sync.Once
+
+	mu     sync.RWMutex
+	synced bool
+	err    error
+	ports  map[int]string             // porta do Service -> nome da porta
+	groups map[string][]endpointGroup // objeto da API -> endpoints
+}
+
+// endpointGroup são endereços prontos que compartilham as mesmas portas
+type endpointGroup struct {
+	addresses []string
+	ports     map[string]int // nome da porta -> porta no pod
+}
+
+// NewKubernetesResolver cria o resolvedor com as credenciais do pod. Os
+// acompanhamentos são encerrados com ctx.
+func NewKubernetesResolver(ctx context.Context, cfg config.KubernetesDiscoveryConfig, logger *zap.Logger) (*KubernetesResolver, error) {
+	client, err := kubeapi.NewInClusterClient(cfg.APIServer)
+	if err != nil {
+		return nil, err
+	}
+
+	return &KubernetesResolver{
+		ctx:         ctx,
+		client:      client,
+		syncTimeout: cfg.SyncTimeout,
+		logger:      logger,
+		services:    make(map[string]*service),
+	}, nil
+}
+
+// Resolve retorna os pods prontos do Service (service://namespace/nome:porta),
+// em ordem estável e com o mesmo peso. Na primeira consulta de um Service,
+// aguarda a listagem inicial dos endpoints.
+func (r *KubernetesResolver) Resolve(ctx context.Context, raw string) ([]model.Target, error) {
+	target, err := model.ParseServiceTarget(raw)
+	if err != nil {
+		return nil, err
+	}
+	svc := r.watch(target.Namespace, target.Name)
+
+	timer := time.NewTimer(r.syncTimeout)
+	defer timer.Stop()
+	select {
+	case <-svc.ready:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-timer.C:
+		return nil, fmt.Errorf("tempo esgotado aguardando os endpoints do service %s/%s", target.Namespace, target.Name)
+	}
+
+	return svc.resolve(target)
+}
+
+// watch retorna o estado do Service, iniciando seu acompanhamento se necessário
+func (r *KubernetesResolver) watch(namespace, name string) *service {
+	key := namespace + "/" + name
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if svc, ok := r.services[key]; ok {
+		return svc
+	}
+
+	svc := &service{
+		namespace: namespace,
+		name:      name,
+		ready:     make(chan struct{}),
+		groups:    make(map[string][]endpointGroup),
+	}
+	r.services[key] = svc
+	go r.run(svc)
+
+	r.logger.Info("Acompanhando endpoints do service do Kubernetes",
+		zap.String("namespace", namespace),
+		zap.String("service", name))
+	return svc
+}
+
+// run lista e acompanha os endpoints do Service até o contexto ser cancelado
+func (r *KubernetesResolver) run(svc *service) {
+	backoff := initialBackoff
+	for {
+		started := time.Now()
+		err := r.sync(svc)
+		if r.ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			// Watch encerrado pela API: listar de novo, sem repetir em laço
+			// quando a API encerra o watch logo após aberto
+			if time.Since(started) >= maxBackoff {
+				backoff = initialBackoff
+				continue
+			}
+		} else {
+			svc.fail(err)
+			r.logger.Warn("Falha ao acompanhar endpoints do service do Kubernetes",
+				zap.String("namespace", svc.namespace),
+				zap.String("service", svc.name),
+				zap.Duration("retryIn", backoff),
+				zap.Error(err))
+		}
+
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// sync lista os endpoints do Service e acompanha suas alterações até o watch
+// terminar. Retorna nil quando o watch termina normalmente.
+func (r *KubernetesResolver) sync(svc *service) error {
+	ports, err := r.servicePorts(svc)
+	if err != nil {
+		return err
+	}
+
+	groups, resourceVersion, err := r.list(svc)
+	if errors.Is(err, kubeapi.ErrNotFound) && !svc.legacy {
+		// Cluster sem a API EndpointSlice
+		svc.legacy = true
+		groups, resourceVersion, err = r.list(svc)
+	}
+	if err != nil {
+		return err
+	}
+	svc.replace(ports, groups)
+
+	return r.watchChanges(svc, resourceVersion)
+}
+
+// servicePorts lê as portas do Service, usadas para traduzir o número da
+// porta do Service no nome que identifica a porta dos endpoints
+func (r *KubernetesResolver) servicePorts(svc *service) (map[int]string, error) {
+	ctx, cancel := context.WithTimeout(r.ctx, r.syncTimeout)
+	defer cancel()
+
+	resp, err := r.client.Get(ctx, fmt.Sprintf("/api/v1/namespaces/%s/services/%s",
+		url.PathEscape(svc.namespace), url.PathEscape(svc.name)), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var object struct {
+		Spec struct {
+			Ports []struct {
+				Name string `json:"name"`
+				Port int    `json:"port"`
+			} `json:"ports"`
+		} `json:"spec"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&object); err != nil {
+		return nil, fmt.Errorf("resposta inválida da API do Kubernetes: %w", err)
+	}
+
+	ports := make(map[int]string, len(object.Spec.Ports))
+	for _, port := range object.Spec.Ports {
+		ports[port.Port] = port.Name
+	}
+	return ports, nil
+}
+
+// endpointsPath retorna o caminho e o filtro da listagem dos endpoints do Service
+func (svc *service) endpointsPath() (string, url.Values) {
+	if svc.legacy {
+		return fmt.Sprintf("/api/v1/namespaces/%s/endpoints", url.PathEscape(svc.namespace)),
+			url.Values{"fieldSelector": {"metadata.name=" + svc.name}}
+	}
+	return fmt.Sprintf("/apis/discovery.k8s.io/v1/namespaces/%s/endpointslices", url.PathEscape(svc.namespace)),
+		url.Values{"labelSelector": {"kubernetes.io/service-name=" + svc.name}}
+}
+
+// list lê os endpoints atuais do Service
+func (r *KubernetesResolver) list(svc *service) (map[string][]endpointGroup, string, error) {
+	ctx, cancel := context.WithTimeout(r.ctx, r.syncTimeout)
+	defer cancel()
+
+	path, query := svc.endpointsPath()
+	resp, err := r.client.Get(ctx, path, query)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	var list struct {
+		Metadata struct {
+			ResourceVersion string `json:"resourceVersion"`
+		} `json:"metadata"`
+		Items []json.RawMessage `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, "", fmt.Errorf("resposta inválida da API do Kubernetes: %w", err)
+	}
+
+	groups := make(map[string][]endpointGroup, len(list.Items))
+	for _, item := range list.Items {
+		name, itemGroups, err := decodeGroups(item, svc.legacy)
+		if err != nil {
+			return nil, "", err
+		}
+		groups[name] = itemGroups
+	}
+	return groups, list.Metadata.ResourceVersion, nil
+}
+
+// watchChanges aplica as alterações dos endpoints a partir da versão listada
+func (r *KubernetesResolver) watchChanges(svc *service, resourceVersion string) error {
+	path, query := svc.endpointsPath()
+	query.Set("watch", "true")
+	query.Set("resourceVersion", resourceVersion)
+	query.Set("timeoutSeconds", strconv.Itoa(int(watchTimeout.Seconds())))
+
+	// Margem para a API encerrar o watch antes do prazo local
+	ctx, cancel := context.WithTimeout(r.ctx, watchTimeout+30*time.Second)
+	defer cancel()
+
+	resp, err := r.client.Get(ctx, path, query)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var event struct {
+			Type   string          `json:"type"`
+			Object json.RawMessage `json:"object"`
+		}
+		if err := decoder.Decode(&event); err != nil {
+			// Fim do watch pela API ou pelo prazo local
+			if errors.Is(err, io.EOF) || r.ctx.Err() != nil || ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("watch de endpoints interrompido: %w", err)
+		}
+
+		switch event.Type {
+		case "ADDED", "MODIFIED", "DELETED":
+			name, groups, err := decodeGroups(event.Object, svc.legacy)
+			if err != nil {
+				return err
+			}
+			svc.update(name, groups, event.Type == "DELETED")
+		case "ERROR":
+			var status struct {
+				Code    int    `json:"code"`
+				Message string `json:"message"`
+			}
+			_ = json.Unmarshal(event.Object, &status)
+			// 410: a versão listada expirou; listar de novo
+			if status.Code == 410 {
+				return nil
+			}
+			return fmt.Errorf("watch de endpoints retornou erro %d: %s", status.Code, status.Message)
+		}
+	}
+}
+
+// decodeGroups extrai os endereços prontos de uma EndpointSlice ou de um Endpoints
+func decodeGroups(raw json.RawMessage, legacy bool) (string, []endpointGroup, error) {
+	if legacy {
+		var object struct {
+			Metadata struct {
+				Name string `json:"name"`
+			} `json:"metadata"`
+			Subsets []struct {
+				Addresses []struct {
+					IP string `json:"ip"`
+				} `json:"addresses"`
+				Ports []struct {
+					Name string `json:"name"`
+					Port int    `json:"port"`
+				} `json:"ports"`
+			} `json:"subsets"`
+		}
+		if err := json.Unmarshal(raw, &object); err != nil {
+			return "", nil, fmt.Errorf("endpoints inválido: %w", err)
+		}
+
+		groups := make([]endpointGroup, 0, len(object.Subsets))
+		for _, subset := range object.Subsets {
+			group := endpointGroup{ports: make(map[string]int, len(subset.Ports))}
+			for _, address := range subset.Addresses {
+				group.addresses = append(group.addresses, address.IP)
+			}
+			for _, port := range subset.Ports {
+				group.ports[port.Name] = port.Port
+			}
+			groups = append(groups, group)
+		}
+		return object.Metadata.Name, groups, nil
+	}
+
+	var slice struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		AddressType string `json:"addressType"`
+		Endpoints   []struct {
+			Addresses  []string `json:"addresses"`
+			Conditions struct {
+				Ready *bool `json:"ready"`
+			} `json:"conditions"`
+		} `json:"endpoints"`
+		Ports []struct {
+			Name *string `json:"name"`
+			Port *int    `json:"port"`
+		} `json:"ports"`
+	}
+	if err := json.Unmarshal(raw, &slice); err != nil {
+		return "", nil, fmt.Errorf("endpointslice inválida: %w", err)
+	}
+	if slice.AddressType == "FQDN" {
+		return slice.Metadata.Name, nil, nil
+	}
+
+	group := endpointGroup{ports: make(map[string]int, len(slice.Ports))}
+	for _, port := range slice.Ports {
+		if port.Port == nil {
+			continue
+		}
+		name := ""
+		if port.Name != nil {
+			name = *port.Name
+		}
+		group.ports[name] = *port.Port
+	}
+	for _, endpoint := range slice.Endpoints {
+		// Condição ausente deve ser tratada como pronta
+		if endpoint.Conditions.Ready != nil && !*endpoint.Conditions.Ready {
+			continue
+		}
+		// Os endereços de um endpoint são do mesmo pod; basta o primeiro
+		if len(endpoint.Addresses) > 0 {
+			group.addresses = append(group.addresses, endpoint.Addresses[0])
+		}
+	}
+	return slice.Metadata.Name, []endpointGroup{group}, nil
+}
+
+// replace substitui os endpoints após uma listagem
+func (svc *service) replace(ports map[int]string, groups map[string][]endpointGroup) {
+	svc.mu.Lock()
+	svc.ports = ports
+	svc.groups = groups
+	svc.synced = true
+	svc.err = nil
+	svc.mu.Unlock()
+
+	svc.readyOnce.Do(func() { close(svc.ready) })
+}
+
+// update aplica a alteração de um objeto de endpoints
+func (svc *service) update(name string, groups []endpointGroup, deleted bool) {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+
+	if deleted {
+		delete(svc.groups, name)
+		return
+	}
+	svc.groups[name] = groups
+}
+
+// fail registra a falha do acompanhamento. Endpoints já conhecidos continuam
+// em uso até a próxima listagem.
+func (svc *service) fail(err error) {
+	svc.mu.Lock()
+	svc.err = err
+	svc.mu.Unlock()
+
+	svc.readyOnce.Do(func() { close(svc.ready) })
+}
+
+// resolve monta os alvos dos pods prontos na porta do alvo
+func (svc *service) resolve(target model.ServiceTarget) ([]model.Target, error) {
+	svc.mu.RLock()
+	defer svc.mu.RUnlock()
+
+	if !svc.synced {
+		return nil, fmt.Errorf("endpoints do service %s/%s indisponíveis: %w", svc.namespace, svc.name, svc.err)
+	}
+
+	// A porta numérica do Service é traduzida no nome da porta dos endpoints;
+	// números que não são portas do Service são usados direto nos pods
+	portName, podPort := target.Port, 0
+	if number, err := strconv.Atoi(target.Port); err == nil {
+		if name, ok := svc.ports[number]; ok {
+			portName = name
+		} else {
+			podPort = number
+		}
+	}
+
+	seen := make(map[string]bool)
+	var urls []string
+	for _, groups := range svc.groups {
+		for _, group := range groups {
+			port := podPort
+			if port == 0 {
+				var ok bool
+				if port, ok = group.ports[portName]; !ok {
+					continue
+				}
+			}
+			for _, address := range group.addresses {
+				endpoint := target.Scheme + "://" + net.JoinHostPort(address, strconv.Itoa(port))
+				if !seen[endpoint] {
+					seen[endpoint] = true
+					urls = append(urls, endpoint)
+				}
+			}
+		}
+	}
+	sort.Strings(urls)
+
+	targets := make([]model.Target, 0, len(urls))
+	for _, endpoint := range urls {
+		targets = append(targets, model.Target{URL: endpoint, Weight: 1})
+	}
+	return targets, nil
+}
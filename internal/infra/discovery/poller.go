@@ -0,0 +1,152 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"go.uber.org/zap"
+)
+
+// fetchTimeout limita cada consulta à fonte e a espera pela primeira
+// consulta de um alvo
+const fetchTimeout = 5 * time.Second
+
+// fetchFunc consulta as instâncias atuais de um alvo
+type fetchFunc func(ctx context.Context, raw string) ([]model.Target, error)
+
+// poller mantém as instâncias de cada alvo, consultadas de novo a cada
+// intervalo a partir do primeiro uso. Em falhas, as últimas instâncias
+// conhecidas continuam em uso.
+type poller struct {
+	ctx      context.Context
+	source   string // identifica a fonte nos logs
+	fetch    fetchFunc
+	interval time.Duration
+	logger   *zap.Logger
+
+	mu      sync.Mutex
+	entries map[string]*pollEntry
+}
+
+// pollEntry é o estado de um alvo consultado periodicamente
+type pollEntry struct {
+	ready     chan struct{} // fechado após a primeira consulta, com ou sem sucesso
+	readyOnce sync.Once
+
+	mu      sync.RWMutex
+	synced  bool
+	err     error
+	targets []model.Target
+}
+
+// newPoller cria o consultor periódico; as consultas encerram com ctx
+func newPoller(ctx context.Context, source string, fetch fetchFunc, interval time.Duration, logger *zap.Logger) *poller {
+	return &poller{
+		ctx:      ctx,
+		source:   source,
+		fetch:    fetch,
+		interval: interval,
+		logger:   logger,
+		entries:  make(map[string]*pollEntry),
+	}
+}
+
+// Resolve retorna as últimas instâncias conhecidas do alvo. Na primeira
+// consulta de um alvo, aguarda o resultado.
+func (p *poller) Resolve(ctx context.Context, raw string) ([]model.Target, error) {
+	entry := p.entry(raw)
+
+	timer := time.NewTimer(fetchTimeout)
+	defer timer.Stop()
+	select {
+	case <-entry.ready:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-timer.C:
+	}
+
+	entry.mu.RLock()
+	defer entry.mu.RUnlock()
+	if !entry.synced {
+		if entry.err != nil {
+			return nil, entry.err
+		}
+		return nil, fmt.Errorf("tempo esgotado aguardando a primeira consulta de %s", raw)
+	}
+	return entry.targets, nil
+}
+
+// entry retorna o estado do alvo, iniciando suas consultas se necessário
+func (p *poller) entry(raw string) *pollEntry {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if entry, ok := p.entries[raw]; ok {
+		return entry
+	}
+	entry := &pollEntry{ready: make(chan struct{})}
+	p.entries[raw] = entry
+	go p.run(raw, entry)
+	return entry
+}
+
+// run consulta o alvo a cada intervalo até o contexto ser cancelado
+func (p *poller) run(raw string, entry *pollEntry) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.refresh(raw, entry)
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// refresh consulta a fonte e atualiza as instâncias do alvo
+func (p *poller) refresh(raw string, entry *pollEntry) {
+	ctx, cancel := context.WithTimeout(p.ctx, fetchTimeout)
+	targets, err := p.fetch(ctx, raw)
+	cancel()
+
+	entry.mu.Lock()
+	if err != nil {
+		entry.err = err
+		p.logger.Warn("Falha ao consultar instâncias do backend",
+			zap.String("source", p.source),
+			zap.String("target", raw),
+			zap.Bool("usingLastKnown", entry.synced),
+			zap.Error(err))
+	} else {
+		if !entry.synced || !sameTargets(entry.targets, targets) {
+			p.logger.Info("Instâncias do backend atualizadas",
+				zap.String("source", p.source),
+				zap.String("target", raw),
+				zap.Int("instances", len(targets)))
+		}
+		entry.targets = targets
+		entry.synced = true
+		entry.err = nil
+	}
+	entry.mu.Unlock()
+
+	entry.readyOnce.Do(func() { close(entry.ready) })
+}
+
+// sameTargets compara duas listas de instâncias em ordem estável
+func sameTargets(a, b []model.Target) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,172 @@
+package extauthz
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// O descritor abaixo reproduz, com os mesmos números de campo, o subconjunto
+// do envoy/service/auth/v3/external_auth.proto usado pelo gateway. Os tipos de
+// outros pacotes do Envoy (Address, HeaderValueOption, HttpStatus) e o
+// google.rpc.Status são declarados localmente: o formato de fio depende apenas
+// dos números e tipos dos campos, e assim o gateway não precisa dos arquivos
+// gerados do Envoy. O descritor não é registrado globalmente.
+
+const checkPackage = "envoy.service.auth.v3"
+
+var (
+	typeString  = descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum()
+	typeBool    = descriptorpb.FieldDescriptorProto_TYPE_BOOL.Enum()
+	typeInt32   = descriptorpb.FieldDescriptorProto_TYPE_INT32.Enum()
+	typeInt64   = descriptorpb.FieldDescriptorProto_TYPE_INT64.Enum()
+	typeMessage = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+
+	labelOptional = descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()
+	labelRepeated = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+)
+
+// field cria a definição de um campo escalar ou de mensagem
+func field(name string, number int32, fieldType *descriptorpb.FieldDescriptorProto_Type, typeName string) *descriptorpb.FieldDescriptorProto {
+	f := &descriptorpb.FieldDescriptorProto{
+		Name:   proto.String(name),
+		Number: proto.Int32(number),
+		Label:  labelOptional,
+		Type:   fieldType,
+	}
+	if typeName != "" {
+		f.TypeName = proto.String("." + checkPackage + "." + typeName)
+	}
+	return f
+}
+
+// repeated marca o campo como lista
+func repeated(f *descriptorpb.FieldDescriptorProto) *descriptorpb.FieldDescriptorProto {
+	f.Label = labelRepeated
+	return f
+}
+
+// message cria a definição de uma mensagem
+func message(name string, fields ...*descriptorpb.FieldDescriptorProto) *descriptorpb.DescriptorProto {
+	return &descriptorpb.DescriptorProto{Name: proto.String(name), Field: fields}
+}
+
+// withStringMap adiciona à mensagem um campo map<string, string>
+func withStringMap(m *descriptorpb.DescriptorProto, name, entry string, number int32) *descriptorpb.DescriptorProto {
+	entryType := message(entry,
+		field("key", 1, typeString, ""),
+		field("value", 2, typeString, ""),
+	)
+	entryType.Options = &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)}
+	m.NestedType = append(m.NestedType, entryType)
+	m.Field = append(m.Field, repeated(field(name, number, typeMessage, m.GetName()+"."+entry)))
+	return m
+}
+
+// checkFileDescriptorProto monta o descritor do serviço de autorização
+func checkFileDescriptorProto() *descriptorpb.FileDescriptorProto {
+	return &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("api-gateway/extauthz/external_auth.proto"),
+		Package: proto.String(checkPackage),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			// envoy.config.core.v3.SocketAddress e Address
+			message("SocketAddress", field("address", 2, typeString, "")),
+			message("Address", field("socket_address", 1, typeMessage, "SocketAddress")),
+			// AttributeContext e seus tipos aninhados
+			message("Peer",
+				field("address", 1, typeMessage, "Address"),
+				field("principal", 4, typeString, ""),
+			),
+			withStringMap(message("HttpRequest",
+				field("id", 1, typeString, ""),
+				field("method", 2, typeString, ""),
+				field("path", 4, typeString, ""),
+				field("host", 5, typeString, ""),
+				field("scheme", 6, typeString, ""),
+				field("query", 7, typeString, ""),
+				field("fragment", 8, typeString, ""),
+				field("size", 9, typeInt64, ""),
+				field("protocol", 10, typeString, ""),
+			), "headers", "HeadersEntry", 3),
+			message("Request", field("http", 2, typeMessage, "HttpRequest")),
+			withStringMap(message("AttributeContext",
+				field("source", 1, typeMessage, "Peer"),
+				field("destination", 2, typeMessage, "Peer"),
+				field("request", 4, typeMessage, "Request"),
+			), "context_extensions", "ContextExtensionsEntry", 10),
+			message("CheckRequest", field("attributes", 1, typeMessage, "AttributeContext")),
+			// google.rpc.Status, google.protobuf.BoolValue e envoy.type.v3.HttpStatus
+			message("Status",
+				field("code", 1, typeInt32, ""),
+				field("message", 2, typeString, ""),
+			),
+			message("BoolValue", field("value", 1, typeBool, "")),
+			message("HttpStatus", field("code", 1, typeInt32, "")),
+			// envoy.config.core.v3.HeaderValue e HeaderValueOption
+			message("HeaderValue",
+				field("key", 1, typeString, ""),
+				field("value", 2, typeString, ""),
+			),
+			message("HeaderValueOption",
+				field("header", 1, typeMessage, "HeaderValue"),
+				field("append", 2, typeMessage, "BoolValue"),
+			),
+			message("DeniedHttpResponse",
+				field("status", 1, typeMessage, "HttpStatus"),
+				repeated(field("headers", 2, typeMessage, "HeaderValueOption")),
+				field("body", 3, typeString, ""),
+			),
+			message("OkHttpResponse",
+				repeated(field("headers", 2, typeMessage, "HeaderValueOption")),
+				repeated(field("headers_to_remove", 5, typeString, "")),
+			),
+			message("CheckResponse",
+				field("status", 1, typeMessage, "Status"),
+				field("denied_response", 2, typeMessage, "DeniedHttpResponse"),
+				field("ok_response", 3, typeMessage, "OkHttpResponse"),
+			),
+		},
+	}
+}
+
+// checkSchema reúne os descritores resolvidos usados pelo cliente
+type checkSchema struct {
+	checkRequestDesc protoreflect.MessageDescriptor
+	checkResponse    protoreflect.MessageDescriptor
+	attributeContext protoreflect.MessageDescriptor
+	request          protoreflect.MessageDescriptor
+	httpRequest      protoreflect.MessageDescriptor
+	peer             protoreflect.MessageDescriptor
+	address          protoreflect.MessageDescriptor
+	socketAddress    protoreflect.MessageDescriptor
+}
+
+// loadCheckSchema resolve o descritor sem registrá-lo globalmente
+func loadCheckSchema() (*checkSchema, error) {
+	file, err := protodesc.NewFile(checkFileDescriptorProto(), new(protoregistry.Files))
+	if err != nil {
+		return nil, fmt.Errorf("falha ao montar descritor do ext_authz: %w", err)
+	}
+	messages := file.Messages()
+	return &checkSchema{
+		checkRequestDesc: messages.ByName("CheckRequest"),
+		checkResponse:    messages.ByName("CheckResponse"),
+		attributeContext: messages.ByName("AttributeContext"),
+		request:          messages.ByName("Request"),
+		httpRequest:      messages.ByName("HttpRequest"),
+		peer:             messages.ByName("Peer"),
+		address:          messages.ByName("Address"),
+		socketAddress:    messages.ByName("SocketAddress"),
+	}, nil
+}
+
+// newMessage cria uma mensagem vazia do tipo informado
+func (s *checkSchema) newMessage(desc protoreflect.MessageDescriptor) *dynamicpb.Message {
+	return dynamicpb.NewMessage(desc)
+}
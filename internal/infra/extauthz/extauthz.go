@@ -0,0 +1,144 @@
+package extauthz
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/pkg/cache"
+	"go.uber.org/zap"
+)
+
+// ErrUnavailable indica que o serviço de autorização não respondeu com uma decisão
+var ErrUnavailable = errors.New("serviço de autorização externo indisponível")
+
+// Request contém os metadados da requisição enviados ao serviço de autorização
+type Request struct {
+	Route    string
+	Method   string
+	Scheme   string
+	Host     string
+	Path     string // Caminho com a query string, como na linha de requisição
+	Query    string
+	Protocol string
+	ClientIP string
+	Headers  map[string]string // Nomes em minúsculas
+}
+
+// Decision é a resposta do serviço de autorização
+type Decision struct {
+	Allowed bool
+
+	// Alterações aplicadas à requisição encaminhada ao backend, quando permitida
+	UpstreamHeaders map[string]string
+	RemoveHeaders   []string
+
+	// Resposta devolvida ao cliente, quando negada
+	Status  int
+	Headers http.Header
+	Body    string
+}
+
+// checker consulta um serviço de autorização em um protocolo específico
+type checker interface {
+	check(ctx context.Context, policy *model.ExternalAuthz, req *Request) (*Decision, error)
+}
+
+// Authorizer consulta os serviços de autorização externos das rotas e mantém
+// as decisões em cache pelo tempo configurado em cada rota
+type Authorizer struct {
+	http   checker
+	grpc   *grpcChecker
+	cache  cache.Cache
+	logger *zap.Logger
+}
+
+// NewAuthorizer cria o autorizador usando o cache para as decisões
+func NewAuthorizer(c cache.Cache, logger *zap.Logger) *Authorizer {
+	return &Authorizer{
+		http:   newHTTPChecker(),
+		grpc:   newGRPCChecker(),
+		cache:  c,
+		logger: logger,
+	}
+}
+
+// Check obtém a decisão para a requisição, do cache ou do serviço da rota.
+// O segundo retorno indica se a decisão veio do cache.
+func (a *Authorizer) Check(ctx context.Context, policy *model.ExternalAuthz, req *Request) (*Decision, bool, error) {
+	req.Headers = selectHeaders(req.Headers, policy.Headers)
+
+	ttl := policy.CacheTTLDuration()
+	key := cacheKey(policy, req)
+	if ttl > 0 {
+		var cached Decision
+		if found, err := a.cache.Get(ctx, key, &cached); err == nil && found {
+			return &cached, true, nil
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, policy.TimeoutDuration())
+	defer cancel()
+
+	var c checker = a.http
+	if policy.IsGRPC() {
+		c = a.grpc
+	}
+	decision, err := c.check(ctx, policy, req)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if ttl > 0 {
+		if err := a.cache.Set(ctx, key, decision, ttl); err != nil {
+			a.logger.Warn("Falha ao armazenar decisão de autorização externa", zap.Error(err))
+		}
+	}
+	return decision, false, nil
+}
+
+// Close encerra as conexões gRPC mantidas com os serviços
+func (a *Authorizer) Close() error {
+	return a.grpc.Close()
+}
+
+// selectHeaders mantém apenas os cabeçalhos configurados; sem lista, todos
+func selectHeaders(headers map[string]string, names []string) map[string]string {
+	if len(names) == 0 {
+		return headers
+	}
+	selected := make(map[string]string, len(names))
+	for _, name := range names {
+		if value, ok := headers[strings.ToLower(name)]; ok {
+			selected[strings.ToLower(name)] = value
+		}
+	}
+	return selected
+}
+
+// cacheKey identifica a decisão pelo serviço e pelos metadados enviados a ele
+func cacheKey(policy *model.ExternalAuthz, req *Request) string {
+	names := make([]string, 0, len(req.Headers))
+	for name := range req.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	h := sha256.New()
+	for _, part := range []string{policy.URL, req.Route, req.Method, req.Host, req.Path} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	for _, name := range names {
+		h.Write([]byte(name))
+		h.Write([]byte{0})
+		h.Write([]byte(req.Headers[name]))
+		h.Write([]byte{0})
+	}
+	return "extauthz:" + hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,199 @@
+package extauthz
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// checkMethod é o método do serviço envoy.service.auth.v3.Authorization
+const checkMethod = "/envoy.service.auth.v3.Authorization/Check"
+
+// grpcChecker consulta serviços que implementam o ext_authz v3 do Envoy, como
+// o plugin opa-envoy. As conexões são mantidas entre as consultas.
+type grpcChecker struct {
+	mu     sync.Mutex
+	conns  map[string]*grpc.ClientConn
+	schema *checkSchema
+	err    error
+}
+
+// newGRPCChecker cria o cliente dos serviços gRPC
+func newGRPCChecker() *grpcChecker {
+	schema, err := loadCheckSchema()
+	return &grpcChecker{
+		conns:  make(map[string]*grpc.ClientConn),
+		schema: schema,
+		err:    err,
+	}
+}
+
+func (c *grpcChecker) check(ctx context.Context, policy *model.ExternalAuthz, req *Request) (*Decision, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	conn, err := c.conn(policy.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	checkReq := c.schema.checkRequest(req)
+	checkRes := dynamicpb.NewMessage(c.schema.checkResponse)
+	if err := conn.Invoke(ctx, checkMethod, checkReq, checkRes); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	return c.schema.decision(checkRes), nil
+}
+
+// Close encerra as conexões mantidas
+func (c *grpcChecker) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for address, conn := range c.conns {
+		conn.Close()
+		delete(c.conns, address)
+	}
+	return nil
+}
+
+// conn obtém ou cria a conexão com o serviço
+func (c *grpcChecker) conn(rawURL string) (*grpc.ClientConn, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if conn, ok := c.conns[rawURL]; ok {
+		return conn, nil
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("URL do serviço de autorização inválida: %w", err)
+	}
+	creds := insecure.NewCredentials()
+	defaultPort := "80"
+	if u.Scheme == "grpcs" {
+		creds = credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+		defaultPort = "443"
+	}
+	port := u.Port()
+	if port == "" {
+		port = defaultPort
+	}
+
+	conn, err := grpc.NewClient(net.JoinHostPort(u.Hostname(), port), grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, fmt.Errorf("falha ao criar conexão gRPC com o serviço de autorização: %w", err)
+	}
+	c.conns[rawURL] = conn
+	return conn, nil
+}
+
+// checkRequest monta o CheckRequest com os atributos da requisição
+func (s *checkSchema) checkRequest(req *Request) *dynamicpb.Message {
+	httpReq := s.newMessage(s.httpRequest)
+	setString(httpReq, "method", req.Method)
+	setString(httpReq, "path", req.Path)
+	setString(httpReq, "host", req.Host)
+	setString(httpReq, "scheme", req.Scheme)
+	setString(httpReq, "query", req.Query)
+	setString(httpReq, "protocol", req.Protocol)
+	headers := httpReq.Mutable(s.httpRequest.Fields().ByName("headers")).Map()
+	for name, value := range req.Headers {
+		headers.Set(protoreflect.ValueOfString(name).MapKey(), protoreflect.ValueOfString(value))
+	}
+
+	request := s.newMessage(s.request)
+	request.Set(s.request.Fields().ByName("http"), protoreflect.ValueOfMessage(httpReq))
+
+	attributes := s.newMessage(s.attributeContext)
+	attributes.Set(s.attributeContext.Fields().ByName("request"), protoreflect.ValueOfMessage(request))
+	if req.ClientIP != "" {
+		socket := s.newMessage(s.socketAddress)
+		setString(socket, "address", req.ClientIP)
+		address := s.newMessage(s.address)
+		address.Set(s.address.Fields().ByName("socket_address"), protoreflect.ValueOfMessage(socket))
+		source := s.newMessage(s.peer)
+		source.Set(s.peer.Fields().ByName("address"), protoreflect.ValueOfMessage(address))
+		attributes.Set(s.attributeContext.Fields().ByName("source"), protoreflect.ValueOfMessage(source))
+	}
+	extensions := attributes.Mutable(s.attributeContext.Fields().ByName("context_extensions")).Map()
+	extensions.Set(protoreflect.ValueOfString("route").MapKey(), protoreflect.ValueOfString(req.Route))
+
+	checkReq := s.newMessage(s.checkRequestDesc)
+	checkReq.Set(s.checkRequestDesc.Fields().ByName("attributes"), protoreflect.ValueOfMessage(attributes))
+	return checkReq
+}
+
+// decision converte o CheckResponse. Código de status zero (OK) permite.
+func (s *checkSchema) decision(res *dynamicpb.Message) *Decision {
+	fields := s.checkResponse.Fields()
+	status := res.Get(fields.ByName("status")).Message()
+	code := status.Get(status.Descriptor().Fields().ByName("code")).Int()
+
+	if code == 0 {
+		decision := &Decision{Allowed: true}
+		ok := res.Get(fields.ByName("ok_response")).Message()
+		okFields := ok.Descriptor().Fields()
+		headers := ok.Get(okFields.ByName("headers")).List()
+		for i := 0; i < headers.Len(); i++ {
+			name, value := headerOption(headers.Get(i).Message())
+			if name == "" {
+				continue
+			}
+			if decision.UpstreamHeaders == nil {
+				decision.UpstreamHeaders = make(map[string]string)
+			}
+			decision.UpstreamHeaders[http.CanonicalHeaderKey(name)] = value
+		}
+		remove := ok.Get(okFields.ByName("headers_to_remove")).List()
+		for i := 0; i < remove.Len(); i++ {
+			decision.RemoveHeaders = append(decision.RemoveHeaders, remove.Get(i).String())
+		}
+		return decision
+	}
+
+	decision := &Decision{Status: http.StatusForbidden, Headers: make(http.Header)}
+	denied := res.Get(fields.ByName("denied_response")).Message()
+	deniedFields := denied.Descriptor().Fields()
+	httpStatus := denied.Get(deniedFields.ByName("status")).Message()
+	if value := httpStatus.Get(httpStatus.Descriptor().Fields().ByName("code")).Int(); value >= 100 && value <= 599 {
+		decision.Status = int(value)
+	}
+	headers := denied.Get(deniedFields.ByName("headers")).List()
+	for i := 0; i < headers.Len(); i++ {
+		if name, value := headerOption(headers.Get(i).Message()); name != "" {
+			decision.Headers.Add(name, value)
+		}
+	}
+	decision.Body = denied.Get(deniedFields.ByName("body")).String()
+	if decision.Body == "" {
+		decision.Body = status.Get(status.Descriptor().Fields().ByName("message")).String()
+	}
+	return decision
+}
+
+// headerOption lê o nome e o valor de um HeaderValueOption
+func headerOption(option protoreflect.Message) (string, string) {
+	header := option.Get(option.Descriptor().Fields().ByName("header")).Message()
+	fields := header.Descriptor().Fields()
+	return header.Get(fields.ByName("key")).String(), header.Get(fields.ByName("value")).String()
+}
+
+// setString preenche um campo de texto da mensagem
+func setString(m *dynamicpb.Message, name, value string) {
+	if value != "" {
+		m.Set(m.Descriptor().Fields().ByName(protoreflect.Name(name)), protoreflect.ValueOfString(value))
+	}
+}
@@ -0,0 +1,102 @@
+package extauthz
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+)
+
+// maxDeniedBodySize limita o corpo da recusa repassado ao cliente
+const maxDeniedBodySize = 64 << 10
+
+// hopHeaders não são repassados entre o gateway e o serviço de autorização
+var hopHeaders = map[string]bool{
+	"Connection":        true,
+	"Keep-Alive":        true,
+	"Te":                true,
+	"Trailer":           true,
+	"Transfer-Encoding": true,
+	"Upgrade":           true,
+	"Content-Length":    true,
+	"Host":              true,
+}
+
+// httpChecker consulta serviços HTTP. A requisição usa o método original, os
+// cabeçalhos selecionados e X-Forwarded-* com a origem. Respostas 2xx permitem,
+// respostas 5xx indicam indisponibilidade e as demais são devolvidas ao cliente
+// como recusa.
+type httpChecker struct {
+	client *http.Client
+}
+
+// newHTTPChecker cria o cliente dos serviços HTTP
+func newHTTPChecker() *httpChecker {
+	return &httpChecker{
+		client: &http.Client{
+			// Redirecionamentos do serviço são repassados ao cliente (ex.: login)
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+func (c *httpChecker) check(ctx context.Context, policy *model.ExternalAuthz, req *Request) (*Decision, error) {
+	authzReq, err := http.NewRequestWithContext(ctx, req.Method, policy.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao montar requisição de autorização: %w", err)
+	}
+	for name, value := range req.Headers {
+		if !hopHeaders[http.CanonicalHeaderKey(name)] {
+			authzReq.Header.Set(name, value)
+		}
+	}
+	authzReq.Header.Set("X-Forwarded-Method", req.Method)
+	authzReq.Header.Set("X-Forwarded-Uri", req.Path)
+	authzReq.Header.Set("X-Forwarded-Host", req.Host)
+	authzReq.Header.Set("X-Forwarded-Proto", req.Scheme)
+	authzReq.Header.Set("X-Forwarded-For", req.ClientIP)
+	authzReq.Header.Set("X-Gateway-Route", req.Route)
+
+	res, err := c.client.Do(authzReq)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 500 {
+		return nil, fmt.Errorf("%w: status %d", ErrUnavailable, res.StatusCode)
+	}
+
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		decision := &Decision{Allowed: true}
+		for _, name := range policy.UpstreamHeaders {
+			if value := res.Header.Get(name); value != "" {
+				if decision.UpstreamHeaders == nil {
+					decision.UpstreamHeaders = make(map[string]string)
+				}
+				decision.UpstreamHeaders[http.CanonicalHeaderKey(name)] = value
+			}
+		}
+		return decision, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxDeniedBodySize))
+	if err != nil {
+		return nil, fmt.Errorf("%w: falha ao ler recusa: %v", ErrUnavailable, err)
+	}
+	decision := &Decision{
+		Status:  res.StatusCode,
+		Headers: make(http.Header),
+		Body:    string(body),
+	}
+	for name, values := range res.Header {
+		if !hopHeaders[name] {
+			decision.Headers[name] = values
+		}
+	}
+	return decision, nil
+}
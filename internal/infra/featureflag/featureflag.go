@@ -0,0 +1,52 @@
+// Package featureflag avalia as feature flags que habilitam as rotas para
+// coortes de consumidores. O provedor é escolhido na configuração (flags): as
+// flags podem ser definidas no próprio gateway ou avaliadas por um serviço
+// externo compatível com o OpenFeature Remote Evaluation Protocol (OFREP).
+package featureflag
+
+import (
+	"context"
+	"errors"
+
+	"github.com/diillson/api-gateway-go/pkg/config"
+	"go.uber.org/zap"
+)
+
+// ErrFlagNotFound indica que a flag não existe no provedor
+var ErrFlagNotFound = errors.New("feature flag não encontrada")
+
+// Subject identifica quem faz a requisição na avaliação das flags
+type Subject struct {
+	Route    string
+	Consumer string // Username (ou ID) do consumidor autenticado
+	Tenant   string
+	ClientIP string
+	Country  string
+}
+
+// TargetingKey retorna a chave que distribui os clientes entre as coortes: o
+// consumidor, ou o endereço dos clientes anônimos
+func (s Subject) TargetingKey() string {
+	if s.Consumer != "" {
+		return s.Consumer
+	}
+	return s.ClientIP
+}
+
+// Provider avalia uma flag booleana para o sujeito da requisição
+type Provider interface {
+	Evaluate(ctx context.Context, flag string, subject Subject) (bool, error)
+}
+
+// New cria o provedor configurado; nil quando nenhum está configurado, e as
+// rotas usam o fallback da sua flag
+func New(cfg config.FeatureFlagsConfig, logger *zap.Logger) Provider {
+	switch cfg.Provider {
+	case "static":
+		return NewStaticProvider(cfg.Flags)
+	case "ofrep":
+		return NewOFREPProvider(cfg, logger)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,167 @@
+package featureflag
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/diillson/api-gateway-go/pkg/config"
+	"go.uber.org/zap"
+)
+
+// maxCachedEvaluations limita as avaliações mantidas em cache pelo provedor
+const maxCachedEvaluations = 10000
+
+// ofrepEvaluation é a resposta do serviço para a avaliação de uma flag
+type ofrepEvaluation struct {
+	Key          string          `json:"key"`
+	Value        json.RawMessage `json:"value"`
+	Reason       string          `json:"reason"`
+	Variant      string          `json:"variant"`
+	ErrorCode    string          `json:"errorCode"`
+	ErrorDetails string          `json:"errorDetails"`
+}
+
+// cachedEvaluation é uma avaliação reaproveitada até expirar
+type cachedEvaluation struct {
+	enabled bool
+	expires time.Time
+}
+
+// OFREPProvider avalia as flags em um serviço compatível com o OpenFeature
+// Remote Evaluation Protocol (POST /ofrep/v1/evaluate/flags/{flag}). O
+// contexto de avaliação leva o consumidor como targetingKey e os dados da
+// requisição (rota, tenant, país e endereço do cliente).
+type OFREPProvider struct {
+	baseURL string
+	headers map[string]string
+	ttl     time.Duration
+	client  *http.Client
+	logger  *zap.Logger
+
+	mu    sync.Mutex
+	cache map[string]cachedEvaluation
+}
+
+// NewOFREPProvider cria o provedor a partir da configuração já validada
+func NewOFREPProvider(cfg config.FeatureFlagsConfig, logger *zap.Logger) *OFREPProvider {
+	return &OFREPProvider{
+		baseURL: strings.TrimSuffix(cfg.URL, "/"),
+		headers: cfg.Headers,
+		ttl:     cfg.CacheTTL,
+		client:  &http.Client{Timeout: cfg.Timeout},
+		logger:  logger,
+		cache:   make(map[string]cachedEvaluation),
+	}
+}
+
+// Evaluate consulta o serviço, reaproveitando a avaliação do mesmo sujeito
+// enquanto estiver no cache
+func (p *OFREPProvider) Evaluate(ctx context.Context, flag string, subject Subject) (bool, error) {
+	key := strings.Join([]string{flag, subject.TargetingKey(), subject.Route, subject.Tenant, subject.Country}, "\x00")
+	now := time.Now()
+	if p.ttl > 0 {
+		p.mu.Lock()
+		cached, ok := p.cache[key]
+		p.mu.Unlock()
+		if ok && now.Before(cached.expires) {
+			return cached.enabled, nil
+		}
+	}
+
+	enabled, err := p.evaluate(ctx, flag, subject)
+	if err != nil {
+		return false, err
+	}
+
+	if p.ttl > 0 {
+		p.mu.Lock()
+		if len(p.cache) >= maxCachedEvaluations {
+			p.evictExpired(now)
+		}
+		p.cache[key] = cachedEvaluation{enabled: enabled, expires: now.Add(p.ttl)}
+		p.mu.Unlock()
+	}
+	return enabled, nil
+}
+
+func (p *OFREPProvider) evaluate(ctx context.Context, flag string, subject Subject) (bool, error) {
+	evaluationContext := map[string]string{"targetingKey": subject.TargetingKey()}
+	for name, value := range map[string]string{
+		"route":    subject.Route,
+		"consumer": subject.Consumer,
+		"tenant":   subject.Tenant,
+		"clientIp": subject.ClientIP,
+		"country":  subject.Country,
+	} {
+		if value != "" {
+			evaluationContext[name] = value
+		}
+	}
+	body, err := json.Marshal(map[string]interface{}{"context": evaluationContext})
+	if err != nil {
+		return false, err
+	}
+
+	endpoint := p.baseURL + "/ofrep/v1/evaluate/flags/" + url.PathEscape(flag)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("falha ao montar avaliação da flag: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	for name, value := range p.headers {
+		req.Header.Set(name, value)
+	}
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("falha ao consultar o provedor de feature flags: %w", err)
+	}
+	defer res.Body.Close()
+
+	var evaluation ofrepEvaluation
+	decodeErr := json.NewDecoder(io.LimitReader(res.Body, 64<<10)).Decode(&evaluation)
+
+	switch {
+	case res.StatusCode == http.StatusNotFound:
+		return false, ErrFlagNotFound
+	case res.StatusCode != http.StatusOK:
+		return false, fmt.Errorf("provedor de feature flags respondeu %d: %s %s",
+			res.StatusCode, evaluation.ErrorCode, evaluation.ErrorDetails)
+	case decodeErr != nil:
+		return false, fmt.Errorf("resposta inválida do provedor de feature flags: %w", decodeErr)
+	}
+
+	var enabled bool
+	if err := json.Unmarshal(evaluation.Value, &enabled); err != nil {
+		return false, fmt.Errorf("flag %q não é booleana", flag)
+	}
+	p.logger.Debug("Feature flag avaliada",
+		zap.String("flag", flag),
+		zap.String("targetingKey", subject.TargetingKey()),
+		zap.Bool("enabled", enabled),
+		zap.String("reason", evaluation.Reason),
+		zap.String("variant", evaluation.Variant))
+	return enabled, nil
+}
+
+// evictExpired remove as avaliações expiradas; sem nenhuma expirada, esvazia
+// o cache para manter o limite
+func (p *OFREPProvider) evictExpired(now time.Time) {
+	for key, cached := range p.cache {
+		if !now.Before(cached.expires) {
+			delete(p.cache, key)
+		}
+	}
+	if len(p.cache) >= maxCachedEvaluations {
+		p.cache = make(map[string]cachedEvaluation)
+	}
+}
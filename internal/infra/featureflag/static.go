@@ -0,0 +1,78 @@
+package featureflag
+
+import (
+	"context"
+	"hash/fnv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/diillson/api-gateway-go/pkg/config"
+)
+
+// StaticProvider avalia as flags definidas na configuração do gateway. As
+// flags são trocadas a cada recarga da configuração.
+type StaticProvider struct {
+	flags atomic.Pointer[map[string]config.FeatureFlagConfig]
+}
+
+// NewStaticProvider cria o provedor com as flags configuradas
+func NewStaticProvider(flags map[string]config.FeatureFlagConfig) *StaticProvider {
+	p := &StaticProvider{}
+	p.SetFlags(flags)
+	return p
+}
+
+// SetFlags substitui as flags avaliadas. Os nomes não diferenciam maiúsculas,
+// já que a configuração os normaliza para minúsculas.
+func (p *StaticProvider) SetFlags(flags map[string]config.FeatureFlagConfig) {
+	normalized := make(map[string]config.FeatureFlagConfig, len(flags))
+	for name, flag := range flags {
+		normalized[strings.ToLower(name)] = flag
+	}
+	p.flags.Store(&normalized)
+}
+
+// Evaluate habilita a flag para os consumidores e tenants listados e, aos
+// demais, conforme a porcentagem. A distribuição é estável: o mesmo
+// consumidor recebe sempre o mesmo resultado para a mesma porcentagem.
+func (p *StaticProvider) Evaluate(_ context.Context, name string, subject Subject) (bool, error) {
+	flag, ok := (*p.flags.Load())[strings.ToLower(name)]
+	if !ok {
+		return false, ErrFlagNotFound
+	}
+	if !flag.Enabled {
+		return false, nil
+	}
+	if subject.Consumer != "" && containsFold(flag.Consumers, subject.Consumer) {
+		return true, nil
+	}
+	if subject.Tenant != "" && containsFold(flag.Tenants, subject.Tenant) {
+		return true, nil
+	}
+	if flag.Percentage >= 100 {
+		return true, nil
+	}
+	if flag.Percentage <= 0 {
+		return false, nil
+	}
+	return bucket(name, subject.TargetingKey()) < flag.Percentage, nil
+}
+
+// bucket distribui a chave entre 0 e 99, separadamente para cada flag
+func bucket(flag, key string) int {
+	h := fnv.New32a()
+	h.Write([]byte(strings.ToLower(flag)))
+	h.Write([]byte{0})
+	h.Write([]byte(key))
+	return int(h.Sum32() % 100)
+}
+
+// containsFold indica se o valor está na lista, sem diferenciar maiúsculas
+func containsFold(values []string, value string) bool {
+	for _, candidate := range values {
+		if strings.EqualFold(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,166 @@
+package healthcheck
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"go.uber.org/zap"
+)
+
+// Prober executa uma verificação ativa contra um alvo da rota
+type Prober interface {
+	Probe(ctx context.Context, route *model.Route, target string) error
+}
+
+// RouteSource fornece as rotas cujos backends devem ser verificados
+type RouteSource interface {
+	GetRoutes(ctx context.Context) ([]*model.Route, error)
+}
+
+// Checker verifica periodicamente todos os alvos das rotas com HealthCheck configurado,
+// respeitando o intervalo de cada rota, e publica os resultados no Registry
+type Checker struct {
+	routes   RouteSource
+	registry *Registry
+	probers  map[string]Prober
+	tick     time.Duration
+	logger   *zap.Logger
+
+	mu      sync.Mutex
+	nextDue map[string]time.Time
+	running map[string]bool
+}
+
+// NewChecker cria um verificador. tick define a frequência com que as rotas são
+// reavaliadas; cada rota é verificada no seu próprio intervalo.
+func NewChecker(routes RouteSource, registry *Registry, tick time.Duration, logger *zap.Logger) *Checker {
+	if tick <= 0 {
+		tick = time.Second
+	}
+	return &Checker{
+		routes:   routes,
+		registry: registry,
+		probers:  make(map[string]Prober),
+		tick:     tick,
+		logger:   logger,
+		nextDue:  make(map[string]time.Time),
+		running:  make(map[string]bool),
+	}
+}
+
+// RegisterProber associa um verificador a um tipo de HealthCheck
+func (c *Checker) RegisterProber(checkType string, prober Prober) {
+	c.probers[checkType] = prober
+}
+
+// Start executa as verificações até o contexto ser cancelado
+func (c *Checker) Start(ctx context.Context) {
+	ticker := time.NewTicker(c.tick)
+	defer ticker.Stop()
+
+	c.logger.Info("Verificação ativa de saúde dos backends iniciada",
+		zap.Duration("tick", c.tick))
+
+	for {
+		c.runDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			c.closeProbers()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// closeProbers libera os recursos dos verificadores que mantêm conexões
+func (c *Checker) closeProbers() {
+	for checkType, prober := range c.probers {
+		if closer, ok := prober.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				c.logger.Warn("Falha ao encerrar verificador de saúde",
+					zap.String("type", checkType), zap.Error(err))
+			}
+		}
+	}
+}
+
+// runDue dispara as verificações das rotas cujo intervalo venceu
+func (c *Checker) runDue(ctx context.Context) {
+	routes, err := c.routes.GetRoutes(ctx)
+	if err != nil {
+		c.logger.Warn("Falha ao obter rotas para verificação de saúde", zap.Error(err))
+		return
+	}
+
+	now := time.Now()
+	active := make(map[string]bool)
+
+	for _, route := range routes {
+		if route.HealthCheck == nil || !route.IsActive {
+			continue
+		}
+		prober, ok := c.probers[route.HealthCheck.Type]
+		if !ok {
+			continue
+		}
+
+		for _, upstream := range route.AllUpstreams() {
+			// As instâncias de backends resolvidos por descoberta de serviços
+			// mudam com o tempo e são acompanhadas pelos circuit breakers
+			if model.IsDiscoveryTarget(upstream.URL) {
+				continue
+			}
+			target := upstream.URL
+			key := targetKey(route.Path, target)
+			active[key] = true
+
+			c.mu.Lock()
+			due := !c.running[key] && !now.Before(c.nextDue[key])
+			if due {
+				c.running[key] = true
+				c.nextDue[key] = now.Add(route.HealthCheck.IntervalDuration())
+			}
+			c.mu.Unlock()
+
+			if due {
+				go c.probe(ctx, prober, route, target, key)
+			}
+		}
+	}
+
+	// Descartar o estado de rotas removidas ou sem verificação
+	c.registry.Retain(func(route, target string) bool {
+		return active[targetKey(route, target)]
+	})
+	c.mu.Lock()
+	for key := range c.nextDue {
+		if !active[key] {
+			delete(c.nextDue, key)
+		}
+	}
+	c.mu.Unlock()
+}
+
+// probe executa uma verificação e registra o resultado
+func (c *Checker) probe(ctx context.Context, prober Prober, route *model.Route, target, key string) {
+	defer func() {
+		c.mu.Lock()
+		delete(c.running, key)
+		c.mu.Unlock()
+	}()
+
+	probeCtx, cancel := context.WithTimeout(ctx, route.HealthCheck.TimeoutDuration())
+	defer cancel()
+
+	err := prober.Probe(probeCtx, route, target)
+	if ctx.Err() != nil {
+		return
+	}
+
+	healthy, unhealthy := route.HealthCheck.Thresholds()
+	c.registry.Report(route.Path, target, route.HealthCheck.Type, err, healthy, unhealthy)
+}
@@ -0,0 +1,42 @@
+package healthcheck
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/diillson/api-gateway-go/pkg/cluster"
+	"go.uber.org/zap"
+)
+
+// clusterTopic é o tópico do cluster usado para compartilhar o estado de saúde
+const clusterTopic = "healthcheck"
+
+// ShareWithCluster replica o estado de saúde entre as instâncias. Apenas o líder
+// executa as verificações ativas; as mudanças de estado que ele observa são
+// publicadas e aplicadas no registro das demais instâncias.
+func ShareWithCluster(registry *Registry, node *cluster.Node, logger *zap.Logger) {
+	registry.OnTransition(func(health TargetHealth, _ Status) {
+		if !node.IsLeader() {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		if err := node.Publish(ctx, clusterTopic, health); err != nil {
+			logger.Warn("Falha ao compartilhar estado de saúde com o cluster",
+				zap.String("route", health.Route),
+				zap.String("target", health.Target),
+				zap.Error(err))
+		}
+	})
+
+	node.Subscribe(clusterTopic, func(payload []byte) {
+		var health TargetHealth
+		if err := json.Unmarshal(payload, &health); err != nil {
+			logger.Warn("Estado de saúde do cluster inválido", zap.Error(err))
+			return
+		}
+		registry.Apply(health)
+	})
+}
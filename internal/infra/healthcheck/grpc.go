@@ -0,0 +1,127 @@
+package healthcheck
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"sync"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// TLSConfigFunc monta a configuração TLS usada para falar com o backend da rota
+type TLSConfigFunc func(route *model.Route) (*tls.Config, error)
+
+// GRPCProber verifica backends gRPC com o protocolo padrão grpc.health.v1.
+// As conexões são mantidas entre verificações, como faria um cliente real.
+type GRPCProber struct {
+	mu        sync.Mutex
+	conns     map[string]*grpc.ClientConn
+	tlsConfig TLSConfigFunc
+}
+
+// NewGRPCProber cria um verificador gRPC. tlsConfig pode ser nil, caso em que
+// alvos TLS usam as CAs do sistema.
+func NewGRPCProber(tlsConfig TLSConfigFunc) *GRPCProber {
+	return &GRPCProber{
+		conns:     make(map[string]*grpc.ClientConn),
+		tlsConfig: tlsConfig,
+	}
+}
+
+// Probe consulta o serviço de saúde do backend. Apenas SERVING é considerado saudável.
+func (p *GRPCProber) Probe(ctx context.Context, route *model.Route, target string) error {
+	conn, err := p.conn(route, target)
+	if err != nil {
+		return err
+	}
+
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{
+		Service: route.HealthCheck.Service,
+	})
+	if err != nil {
+		return fmt.Errorf("falha na verificação grpc.health.v1: %w", err)
+	}
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("serviço %q reportou status %s", route.HealthCheck.Service, resp.GetStatus())
+	}
+	return nil
+}
+
+// Close encerra as conexões mantidas pelo verificador
+func (p *GRPCProber) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for key, conn := range p.conns {
+		conn.Close()
+		delete(p.conns, key)
+	}
+	return nil
+}
+
+// conn obtém ou cria a conexão com o alvo
+func (p *GRPCProber) conn(route *model.Route, target string) (*grpc.ClientConn, error) {
+	address, useTLS, err := grpcAddress(target)
+	if err != nil {
+		return nil, err
+	}
+
+	key := route.Path + "|" + target
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if conn, ok := p.conns[key]; ok {
+		return conn, nil
+	}
+
+	creds := insecure.NewCredentials()
+	if useTLS {
+		tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+		if p.tlsConfig != nil {
+			if tlsConfig, err = p.tlsConfig(route); err != nil {
+				return nil, fmt.Errorf("falha ao configurar TLS da verificação: %w", err)
+			}
+		}
+		creds = credentials.NewTLS(tlsConfig)
+	}
+
+	conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, fmt.Errorf("falha ao criar conexão gRPC com %s: %w", address, err)
+	}
+	p.conns[key] = conn
+	return conn, nil
+}
+
+// grpcAddress converte a URL do backend em host:porta e indica se usa TLS.
+// Esquemas aceitos: https/grpcs (TLS) e http/grpc/h2c (texto claro).
+func grpcAddress(target string) (string, bool, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return "", false, fmt.Errorf("URL do backend inválida: %w", err)
+	}
+
+	var useTLS bool
+	var defaultPort string
+	switch u.Scheme {
+	case "https", "grpcs":
+		useTLS, defaultPort = true, "443"
+	case "http", "grpc", "h2c":
+		useTLS, defaultPort = false, "80"
+	default:
+		return "", false, fmt.Errorf("esquema não suportado para verificação gRPC: %q", u.Scheme)
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = defaultPort
+	}
+	return net.JoinHostPort(u.Hostname(), port), useTLS, nil
+}
@@ -0,0 +1,59 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+)
+
+// maxHealthBodyBytes limita a leitura do corpo da resposta de verificação
+const maxHealthBodyBytes = 64 * 1024
+
+// TransportFunc fornece o transporte usado para alcançar o backend da rota,
+// com a mesma configuração de TLS e proxy de saída do tráfego real
+type TransportFunc func(route *model.Route) (http.RoundTripper, error)
+
+// HTTPProber verifica backends com uma requisição GET ao caminho configurado
+type HTTPProber struct {
+	transport TransportFunc
+}
+
+// NewHTTPProber cria um verificador HTTP. transport pode ser nil, caso em que
+// o transporte padrão é usado.
+func NewHTTPProber(transport TransportFunc) *HTTPProber {
+	return &HTTPProber{transport: transport}
+}
+
+// Probe requisita o caminho de verificação do alvo e confere o status da resposta
+func (p *HTTPProber) Probe(ctx context.Context, route *model.Route, target string) error {
+	checkURL := strings.TrimSuffix(target, "/") + route.HealthCheck.HTTPPath()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, checkURL, nil)
+	if err != nil {
+		return fmt.Errorf("URL de verificação inválida: %w", err)
+	}
+	req.Header.Set("User-Agent", "api-gateway-healthcheck")
+
+	var transport http.RoundTripper = http.DefaultTransport
+	if p.transport != nil {
+		if transport, err = p.transport(route); err != nil {
+			return fmt.Errorf("falha ao preparar conexão da verificação: %w", err)
+		}
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return fmt.Errorf("falha na verificação HTTP: %w", err)
+	}
+	defer resp.Body.Close()
+	// Consumir o corpo para permitir o reuso da conexão
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxHealthBodyBytes))
+
+	if !route.HealthCheck.IsExpectedStatus(resp.StatusCode) {
+		return fmt.Errorf("verificação HTTP retornou status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package healthcheck
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Status é o estado de saúde de um alvo
+type Status string
+
+const (
+	StatusUnknown   Status = "unknown"
+	StatusHealthy   Status = "healthy"
+	StatusUnhealthy Status = "unhealthy"
+)
+
+// TargetHealth é o estado de saúde de um alvo (backend) de uma rota
+type TargetHealth struct {
+	Route                string    `json:"route"`
+	Target               string    `json:"target"`
+	Checker              string    `json:"checker"`
+	Status               Status    `json:"status"`
+	ConsecutiveSuccesses int       `json:"consecutive_successes"`
+	ConsecutiveFailures  int       `json:"consecutive_failures"`
+	LastCheck            time.Time `json:"last_check"`
+	LastError            string    `json:"last_error,omitempty"`
+	LastTransition       time.Time `json:"last_transition,omitempty"`
+}
+
+// TransitionFunc é chamada quando o estado de um alvo muda
+type TransitionFunc func(health TargetHealth, previous Status)
+
+// Registry mantém o estado de saúde dos alvos, aplicando os limiares de
+// sucessos e falhas consecutivos antes de mudar o estado
+type Registry struct {
+	mu           sync.RWMutex
+	targets      map[string]*TargetHealth
+	onTransition []TransitionFunc
+}
+
+// NewRegistry cria um registro de saúde vazio
+func NewRegistry() *Registry {
+	return &Registry{
+		targets: make(map[string]*TargetHealth),
+	}
+}
+
+// OnTransition registra uma função chamada a cada mudança de estado
+func (r *Registry) OnTransition(fn TransitionFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onTransition = append(r.onTransition, fn)
+}
+
+// Report registra o resultado de uma verificação. Um alvo novo começa em
+// StatusUnknown e só muda de estado ao atingir o limiar correspondente.
+func (r *Registry) Report(route, target, checker string, err error, healthyThreshold, unhealthyThreshold int) TargetHealth {
+	r.mu.Lock()
+
+	key := targetKey(route, target)
+	health, ok := r.targets[key]
+	if !ok {
+		health = &TargetHealth{Route: route, Target: target, Status: StatusUnknown}
+		r.targets[key] = health
+	}
+
+	previous := health.Status
+	health.Checker = checker
+	health.LastCheck = time.Now()
+
+	if err == nil {
+		health.ConsecutiveSuccesses++
+		health.ConsecutiveFailures = 0
+		health.LastError = ""
+		if health.Status != StatusHealthy && health.ConsecutiveSuccesses >= healthyThreshold {
+			health.Status = StatusHealthy
+		}
+	} else {
+		health.ConsecutiveFailures++
+		health.ConsecutiveSuccesses = 0
+		health.LastError = err.Error()
+		if health.Status != StatusUnhealthy && health.ConsecutiveFailures >= unhealthyThreshold {
+			health.Status = StatusUnhealthy
+		}
+	}
+
+	if health.Status != previous {
+		health.LastTransition = health.LastCheck
+	}
+
+	snapshot := *health
+	callbacks := r.onTransition
+	r.mu.Unlock()
+
+	if snapshot.Status != previous {
+		for _, fn := range callbacks {
+			fn(snapshot, previous)
+		}
+	}
+	return snapshot
+}
+
+// Apply substitui o estado de um alvo por um estado calculado em outra instância,
+// notificando a mudança como em Report
+func (r *Registry) Apply(health TargetHealth) {
+	r.mu.Lock()
+
+	key := targetKey(health.Route, health.Target)
+	previous := StatusUnknown
+	if current, ok := r.targets[key]; ok {
+		previous = current.Status
+	}
+	applied := health
+	r.targets[key] = &applied
+	callbacks := r.onTransition
+	r.mu.Unlock()
+
+	if health.Status != previous {
+		for _, fn := range callbacks {
+			fn(health, previous)
+		}
+	}
+}
+
+// IsAvailable indica se o alvo pode receber tráfego. Alvos sem verificação
+// ou ainda sem estado definido são considerados disponíveis.
+func (r *Registry) IsAvailable(route, target string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	health, ok := r.targets[targetKey(route, target)]
+	return !ok || health.Status != StatusUnhealthy
+}
+
+// Get retorna o estado de um alvo
+func (r *Registry) Get(route, target string) (TargetHealth, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	health, ok := r.targets[targetKey(route, target)]
+	if !ok {
+		return TargetHealth{}, false
+	}
+	return *health, true
+}
+
+// Retain remove os alvos que não pertencem mais a rotas verificadas
+func (r *Registry) Retain(keep func(route, target string) bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, health := range r.targets {
+		if !keep(health.Route, health.Target) {
+			delete(r.targets, key)
+		}
+	}
+}
+
+// Snapshot retorna o estado de todos os alvos, ordenado por rota e alvo
+func (r *Registry) Snapshot() []TargetHealth {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]TargetHealth, 0, len(r.targets))
+	for _, health := range r.targets {
+		result = append(result, *health)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Route != result[j].Route {
+			return result[i].Route < result[j].Route
+		}
+		return result[i].Target < result[j].Target
+	})
+	return result
+}
+
+// targetKey identifica um alvo de uma rota
+func targetKey(route, target string) string {
+	return route + "|" + target
+}
@@ -1,6 +1,8 @@
 package metrics
 
 import (
+	"database/sql"
+	"strconv"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -9,17 +11,51 @@ import (
 
 // APIMetrics gerencia métricas relacionadas à API
 type APIMetrics struct {
-	requestCounter     *prometheus.CounterVec
-	requestDuration    *prometheus.HistogramVec
-	requestSize        *prometheus.SummaryVec
-	responseSize       *prometheus.SummaryVec
-	activeRequests     *prometheus.GaugeVec
-	errorsTotal        *prometheus.CounterVec
-	circuitBreakerOpen *prometheus.GaugeVec
-	rateLimited        *prometheus.CounterVec
-	cacheHitRatio      *prometheus.GaugeVec
+	requestCounter         *prometheus.CounterVec
+	requestDuration        *prometheus.HistogramVec
+	requestSize            *prometheus.SummaryVec
+	responseSize           *prometheus.SummaryVec
+	activeRequests         *prometheus.GaugeVec
+	errorsTotal            *prometheus.CounterVec
+	circuitBreakerOpen     *prometheus.GaugeVec
+	circuitBreakerState    *prometheus.GaugeVec
+	circuitBreakerRejected *prometheus.CounterVec
+	rateLimited            *prometheus.CounterVec
+	cacheHitRatio          *prometheus.GaugeVec
+	dbOpenConns            *prometheus.GaugeVec
+	dbInUseConns           *prometheus.GaugeVec
+	dbIdleConns            *prometheus.GaugeVec
+	dbWaitCount            *prometheus.GaugeVec
+	dbWaitDuration         *prometheus.GaugeVec
+	rejectedRequests       *prometheus.CounterVec
+	costRequests           *prometheus.CounterVec
+	costDuration           *prometheus.CounterVec
+	costBytes              *prometheus.CounterVec
+	upstreamHealth         *prometheus.GaugeVec
+	clusterLeader          *prometheus.GaugeVec
+	websocketConnections   *prometheus.GaugeVec
+	routeRequests          *prometheus.CounterVec
+	routeDuration          *prometheus.HistogramVec
+	upstreamErrors         *prometheus.CounterVec
+	upstreamEjections      *prometheus.CounterVec
+	routeCache             *prometheus.CounterVec
+	mirrorRequests         *prometheus.CounterVec
+	graphqlOperations      *prometheus.CounterVec
+	graphqlDuration        *prometheus.HistogramVec
+	graphqlComplexity      *prometheus.HistogramVec
+	compositeCalls         *prometheus.CounterVec
+	compositeCallDuration  *prometheus.HistogramVec
+	recordings             *prometheus.CounterVec
+	deprecatedRequests     *prometheus.CounterVec
+	concurrencyLimit       prometheus.Gauge
+	concurrencyInFlight    prometheus.Gauge
+	concurrencyQueued      prometheus.Gauge
 }
 
+// routeDurationBuckets cobre de respostas do cache a backends lentos, até o
+// timeout padrão das rotas
+var routeDurationBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30}
+
 var (
 	// DefaultRegistry é o registro padrão para métricas
 	DefaultRegistry = prometheus.NewRegistry()
@@ -91,12 +127,28 @@ func NewAPIMetrics() *APIMetrics {
 			[]string{"service"},
 		),
 
+		circuitBreakerState: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "api_gateway_circuit_breaker_state",
+				Help: "Current circuit breaker state: closed (0), open (1) or half-open (2)",
+			},
+			[]string{"service"},
+		),
+
+		circuitBreakerRejected: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "api_gateway_circuit_breaker_rejected_total",
+				Help: "Total number of requests rejected by an open circuit breaker",
+			},
+			[]string{"service"},
+		),
+
 		rateLimited: promauto.NewCounterVec(
 			prometheus.CounterOpts{
 				Name: "api_gateway_rate_limited_requests_total",
 				Help: "Total number of rate limited requests",
 			},
-			[]string{"path", "method", "limit_type"},
+			[]string{"tenant", "path", "method", "limit_type"},
 		),
 
 		cacheHitRatio: promauto.NewGaugeVec(
@@ -106,6 +158,231 @@ func NewAPIMetrics() *APIMetrics {
 			},
 			[]string{"cache_type"},
 		),
+
+		dbOpenConns: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "api_gateway_db_pool_open_connections",
+				Help: "Number of established connections to the database (in use and idle)",
+			},
+			[]string{"database"},
+		),
+
+		dbInUseConns: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "api_gateway_db_pool_in_use_connections",
+				Help: "Number of database connections currently in use",
+			},
+			[]string{"database"},
+		),
+
+		dbIdleConns: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "api_gateway_db_pool_idle_connections",
+				Help: "Number of idle database connections",
+			},
+			[]string{"database"},
+		),
+
+		dbWaitCount: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "api_gateway_db_pool_wait_count",
+				Help: "Total number of connections waited for since the pool was created",
+			},
+			[]string{"database"},
+		),
+
+		dbWaitDuration: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "api_gateway_db_pool_wait_duration_seconds",
+				Help: "Total time blocked waiting for a new database connection",
+			},
+			[]string{"database"},
+		),
+
+		rejectedRequests: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "api_gateway_rejected_requests_total",
+				Help: "Total number of requests rejected before routing, by reason",
+			},
+			[]string{"reason"},
+		),
+
+		costRequests: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "api_gateway_cost_requests_total",
+				Help: "Total number of proxied requests by cost attribution",
+			},
+			[]string{"team", "product", "consumer"},
+		),
+
+		costDuration: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "api_gateway_cost_request_duration_seconds_total",
+				Help: "Total time spent serving proxied requests by cost attribution",
+			},
+			[]string{"team", "product", "consumer"},
+		),
+
+		costBytes: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "api_gateway_cost_bytes_total",
+				Help: "Total bytes transferred by cost attribution and direction",
+			},
+			[]string{"team", "product", "consumer", "direction"},
+		),
+
+		upstreamHealth: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "api_gateway_upstream_healthy",
+				Help: "Upstream health from active checks (1 = healthy, 0 = unhealthy)",
+			},
+			[]string{"route", "target", "checker"},
+		),
+
+		clusterLeader: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "api_gateway_cluster_leader",
+				Help: "Whether this instance holds the cluster leadership (1 = leader, 0 = follower)",
+			},
+			[]string{"node"},
+		),
+
+		websocketConnections: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "api_gateway_websocket_connections",
+				Help: "Number of open WebSocket connections",
+			},
+			[]string{"route"},
+		),
+
+		routeRequests: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "api_gateway_route_requests_total",
+				Help: "Total number of proxied requests by tenant, route, method and status class",
+			},
+			[]string{"tenant", "route", "method", "status_class"},
+		),
+
+		routeDuration: promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "api_gateway_route_request_duration_seconds",
+				Help:    "Proxied request duration in seconds by tenant, route and method",
+				Buckets: routeDurationBuckets,
+			},
+			[]string{"tenant", "route", "method"},
+		),
+
+		upstreamEjections: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "api_gateway_upstream_ejections_total",
+				Help: "Total number of upstream targets ejected from load balancing by outlier detection by route, target and reason",
+			},
+			[]string{"route", "target", "reason"},
+		),
+
+		upstreamErrors: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "api_gateway_upstream_errors_total",
+				Help: "Total number of upstream connection errors by route, method and type",
+			},
+			[]string{"route", "method", "error_type"},
+		),
+
+		routeCache: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "api_gateway_route_cache_requests_total",
+				Help: "Total number of response cache lookups by route and result",
+			},
+			[]string{"route", "result"},
+		),
+
+		mirrorRequests: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "api_gateway_mirror_requests_total",
+				Help: "Total number of requests mirrored to shadow upstreams by route and result",
+			},
+			[]string{"route", "result"},
+		),
+
+		graphqlOperations: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "api_gateway_graphql_operations_total",
+				Help: "Total number of GraphQL operations by route, operation, type and result",
+			},
+			[]string{"route", "operation", "type", "result"},
+		),
+
+		graphqlDuration: promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "api_gateway_graphql_operation_duration_seconds",
+				Help:    "Duration of proxied GraphQL operations by route and operation",
+				Buckets: routeDurationBuckets,
+			},
+			[]string{"route", "operation"},
+		),
+
+		graphqlComplexity: promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "api_gateway_graphql_operation_complexity",
+				Help:    "Estimated complexity of GraphQL operations by route and operation",
+				Buckets: prometheus.ExponentialBuckets(1, 4, 10),
+			},
+			[]string{"route", "operation"},
+		),
+
+		compositeCalls: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "api_gateway_composite_calls_total",
+				Help: "Total number of upstream calls made by composite routes by route, call and result",
+			},
+			[]string{"route", "call", "result"},
+		),
+
+		compositeCallDuration: promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "api_gateway_composite_call_duration_seconds",
+				Help:    "Duration of upstream calls made by composite routes by route and call",
+				Buckets: routeDurationBuckets,
+			},
+			[]string{"route", "call"},
+		),
+
+		recordings: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "api_gateway_recordings_total",
+				Help: "Total number of captured request/response recordings by route and result",
+			},
+			[]string{"route", "result"},
+		),
+
+		deprecatedRequests: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "api_gateway_deprecated_requests_total",
+				Help: "Total number of requests to deprecated routes by route, consumer and whether the route was already sunset",
+			},
+			[]string{"route", "consumer", "sunset"},
+		),
+
+		concurrencyLimit: promauto.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "api_gateway_concurrency_limit",
+				Help: "Current adaptive limit of concurrent proxied requests",
+			},
+		),
+
+		concurrencyInFlight: promauto.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "api_gateway_concurrency_in_flight",
+				Help: "Number of proxied requests holding a slot of the adaptive concurrency limit",
+			},
+		),
+
+		concurrencyQueued: promauto.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "api_gateway_concurrency_queued",
+				Help: "Number of proxied requests waiting for a slot of the adaptive concurrency limit",
+			},
+		),
 	}
 }
 
@@ -137,12 +414,163 @@ func (m *APIMetrics) CircuitBreakerStateChanged(service string, isOpen bool) {
 	m.circuitBreakerOpen.WithLabelValues(service).Set(value)
 }
 
-// RateLimitExceeded registra quando um limite de taxa é excedido
-func (m *APIMetrics) RateLimitExceeded(path, method, limitType string) {
-	m.rateLimited.WithLabelValues(path, method, limitType).Inc()
+// CircuitBreakerStateSet registra o estado atual de um circuit breaker
+// (0 fechado, 1 aberto, 2 meio-aberto)
+func (m *APIMetrics) CircuitBreakerStateSet(service string, state int) {
+	m.circuitBreakerState.WithLabelValues(service).Set(float64(state))
+}
+
+// CircuitBreakerRejected registra uma requisição rejeitada pelo circuito aberto
+func (m *APIMetrics) CircuitBreakerRejected(service string) {
+	m.circuitBreakerRejected.WithLabelValues(service).Inc()
+}
+
+// RateLimitExceeded registra quando um limite de taxa é excedido. tenant é
+// vazio no escopo padrão.
+func (m *APIMetrics) RateLimitExceeded(tenant, path, method, limitType string) {
+	m.rateLimited.WithLabelValues(tenant, path, method, limitType).Inc()
 }
 
 // UpdateCacheHitRatio atualiza a taxa de acertos do cache
 func (m *APIMetrics) UpdateCacheHitRatio(cacheType string, hitRatio float64) {
 	m.cacheHitRatio.WithLabelValues(cacheType).Set(hitRatio)
 }
+
+// UpdateDBPoolStats atualiza as métricas do pool de conexões do banco de dados
+func (m *APIMetrics) UpdateDBPoolStats(database string, stats sql.DBStats) {
+	m.dbOpenConns.WithLabelValues(database).Set(float64(stats.OpenConnections))
+	m.dbInUseConns.WithLabelValues(database).Set(float64(stats.InUse))
+	m.dbIdleConns.WithLabelValues(database).Set(float64(stats.Idle))
+	m.dbWaitCount.WithLabelValues(database).Set(float64(stats.WaitCount))
+	m.dbWaitDuration.WithLabelValues(database).Set(stats.WaitDuration.Seconds())
+}
+
+// RequestRejected registra uma requisição rejeitada antes do roteamento
+func (m *APIMetrics) RequestRejected(reason string) {
+	m.rejectedRequests.WithLabelValues(reason).Inc()
+}
+
+// RecordCost registra o uso de uma requisição para a atribuição de custos
+func (m *APIMetrics) RecordCost(team, product, consumer string, duration time.Duration, requestSize, responseSize int) {
+	m.costRequests.WithLabelValues(team, product, consumer).Inc()
+	m.costDuration.WithLabelValues(team, product, consumer).Add(duration.Seconds())
+	if requestSize > 0 {
+		m.costBytes.WithLabelValues(team, product, consumer, "in").Add(float64(requestSize))
+	}
+	if responseSize > 0 {
+		m.costBytes.WithLabelValues(team, product, consumer, "out").Add(float64(responseSize))
+	}
+}
+
+// UpstreamHealthChanged registra o estado de saúde de um backend
+func (m *APIMetrics) UpstreamHealthChanged(route, target, checker string, healthy bool) {
+	value := 0.0
+	if healthy {
+		value = 1.0
+	}
+	m.upstreamHealth.WithLabelValues(route, target, checker).Set(value)
+}
+
+// ClusterLeadershipChanged registra se a instância é a líder do cluster
+func (m *APIMetrics) ClusterLeadershipChanged(node string, leader bool) {
+	value := 0.0
+	if leader {
+		value = 1.0
+	}
+	m.clusterLeader.WithLabelValues(node).Set(value)
+}
+
+// WebSocketConnectionOpened registra uma conexão WebSocket aberta na rota
+func (m *APIMetrics) WebSocketConnectionOpened(route string) {
+	m.websocketConnections.WithLabelValues(route).Inc()
+}
+
+// WebSocketConnectionClosed registra o encerramento de uma conexão WebSocket da rota
+func (m *APIMetrics) WebSocketConnectionClosed(route string) {
+	m.websocketConnections.WithLabelValues(route).Dec()
+}
+
+// RouteRequestCompleted registra uma requisição encaminhada pela rota. O
+// rótulo route é o caminho registrado, e não o da requisição, para manter a
+// cardinalidade limitada. Rotas de tenants diferentes podem ter o mesmo
+// caminho e são distinguidas pelo rótulo tenant.
+func (m *APIMetrics) RouteRequestCompleted(tenant, route, method string, status int, duration time.Duration) {
+	m.routeRequests.WithLabelValues(tenant, route, method, StatusClass(status)).Inc()
+	m.routeDuration.WithLabelValues(tenant, route, method).Observe(duration.Seconds())
+}
+
+// UpstreamEjected registra a retirada de um alvo do balanceamento pela
+// detecção de alvos anômalos
+func (m *APIMetrics) UpstreamEjected(route, target, reason string) {
+	m.upstreamEjections.WithLabelValues(route, target, reason).Inc()
+}
+
+// UpstreamError registra uma falha de conexão com o backend da rota
+func (m *APIMetrics) UpstreamError(route, method, errorType string) {
+	m.upstreamErrors.WithLabelValues(route, method, errorType).Inc()
+}
+
+// RouteCacheLookup registra uma consulta ao cache de respostas da rota
+func (m *APIMetrics) RouteCacheLookup(route string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	m.routeCache.WithLabelValues(route, result).Inc()
+}
+
+// MirrorRequest registra uma requisição espelhada para o backend sombra da
+// rota. O resultado é a classe do status da resposta ou o motivo da falha.
+func (m *APIMetrics) MirrorRequest(route, result string) {
+	m.mirrorRequests.WithLabelValues(route, result).Inc()
+}
+
+// GraphQLOperation registra uma operação GraphQL analisada pelo gateway. O
+// resultado é "accepted" ou o motivo da recusa; a complexidade é registrada
+// apenas para as operações analisadas.
+func (m *APIMetrics) GraphQLOperation(route, operation, operationType, result string, complexity int) {
+	m.graphqlOperations.WithLabelValues(route, operation, operationType, result).Inc()
+	if complexity > 0 {
+		m.graphqlComplexity.WithLabelValues(route, operation).Observe(float64(complexity))
+	}
+}
+
+// GraphQLCompleted registra a duração de uma operação GraphQL encaminhada
+func (m *APIMetrics) GraphQLCompleted(route, operation string, duration time.Duration) {
+	m.graphqlDuration.WithLabelValues(route, operation).Observe(duration.Seconds())
+}
+
+// CompositeCall registra uma chamada de uma rota de composição. O resultado é
+// a classe do status da resposta ou o motivo da falha.
+func (m *APIMetrics) CompositeCall(route, call, result string, duration time.Duration) {
+	m.compositeCalls.WithLabelValues(route, call, result).Inc()
+	m.compositeCallDuration.WithLabelValues(route, call).Observe(duration.Seconds())
+}
+
+// RecordingCaptured registra uma requisição capturada para gravação. O
+// resultado é queued (na fila do banco) ou dropped (fila cheia).
+func (m *APIMetrics) RecordingCaptured(route, result string) {
+	m.recordings.WithLabelValues(route, result).Inc()
+}
+
+// DeprecatedRouteUsed registra uma requisição a uma rota descontinuada pelo
+// consumidor, indicando se a data de desativação já passou
+func (m *APIMetrics) DeprecatedRouteUsed(route, consumer string, sunset bool) {
+	m.deprecatedRequests.WithLabelValues(route, consumer, strconv.FormatBool(sunset)).Inc()
+}
+
+// ConcurrencyLimitUpdated registra o limite adaptativo de concorrência, as
+// requisições em andamento e as que aguardam na fila
+func (m *APIMetrics) ConcurrencyLimitUpdated(limit, inFlight, queued int) {
+	m.concurrencyLimit.Set(float64(limit))
+	m.concurrencyInFlight.Set(float64(inFlight))
+	m.concurrencyQueued.Set(float64(queued))
+}
+
+// StatusClass agrupa o status HTTP na sua classe (ex.: 404 -> "4xx")
+func StatusClass(status int) string {
+	if status < 100 || status > 599 {
+		return "unknown"
+	}
+	return strconv.Itoa(status/100) + "xx"
+}
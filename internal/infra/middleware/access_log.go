@@ -0,0 +1,294 @@
+package middleware
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/internal/infra/usage"
+	"github.com/diillson/api-gateway-go/pkg/config"
+	"github.com/diillson/api-gateway-go/pkg/geoip"
+	"github.com/diillson/api-gateway-go/pkg/logging"
+	"github.com/diillson/api-gateway-go/pkg/requestid"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// combinedTimeFormat é o formato de data do Apache combined
+const combinedTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+// AccessLogMiddleware registra uma entrada por requisição, em JSON estruturado
+// ou no formato Apache combined. Requisições bem-sucedidas podem ser
+// amostradas por rota; respostas com status >= 400 são sempre registradas.
+type AccessLogMiddleware struct {
+	enabled  bool
+	format   string
+	sampling atomic.Pointer[accessLogSampling] // trocada nas recargas da configuração
+	logger   *zap.Logger
+
+	mu          sync.RWMutex
+	subscribers map[int]chan AccessLogEntry
+	nextID      int
+}
+
+// accessLogSampling são as frações registradas das requisições bem-sucedidas
+type accessLogSampling struct {
+	sampleRate float64
+	routeRates map[string]float64
+}
+
+// AccessLogEntry é uma requisição concluída, entregue aos assinantes do log
+// de acesso em tempo real
+type AccessLogEntry struct {
+	Time      time.Time `json:"time"`
+	Method    string    `json:"method"`
+	Path      string    `json:"path"`
+	Route     string    `json:"route,omitempty"`
+	Status    int       `json:"status"`
+	LatencyMs float64   `json:"latency_ms"`
+	Bytes     int       `json:"bytes"`
+	IP        string    `json:"ip"`
+	Country   string    `json:"country,omitempty"`
+	RequestID string    `json:"request_id,omitempty"`
+	TraceID   string    `json:"trace_id,omitempty"`
+	Username  string    `json:"username,omitempty"`
+}
+
+// NewAccessLogMiddleware cria o middleware de log de acesso. Sem outputPath,
+// as entradas usam o logger da aplicação.
+func NewAccessLogMiddleware(cfg config.AccessLogConfig, logger *zap.Logger) *AccessLogMiddleware {
+	m := &AccessLogMiddleware{
+		enabled:     cfg.Enabled,
+		format:      cfg.Format,
+		logger:      logger,
+		subscribers: make(map[int]chan AccessLogEntry),
+	}
+	if m.format == "" {
+		m.format = "json"
+	}
+	m.Reload(cfg)
+
+	if cfg.Enabled && cfg.OutputPath != "" {
+		accessLogger, err := logging.NewAccessLogger(m.format, cfg.OutputPath)
+		if err != nil {
+			logger.Error("Falha ao criar o log de acesso, usando o logger da aplicação",
+				zap.String("outputPath", cfg.OutputPath),
+				zap.Error(err))
+		} else {
+			m.logger = accessLogger
+		}
+	}
+	return m
+}
+
+// Reload aplica a amostragem recarregada; o formato e o destino do log só
+// mudam com reinício
+func (m *AccessLogMiddleware) Reload(cfg config.AccessLogConfig) {
+	sampling := &accessLogSampling{
+		sampleRate: cfg.SampleRate,
+		routeRates: make(map[string]float64, len(cfg.Routes)),
+	}
+	for _, route := range cfg.Routes {
+		sampling.routeRates[route.Path] = route.SampleRate
+	}
+	m.sampling.Store(sampling)
+}
+
+// Middleware registra a requisição após o processamento
+func (m *AccessLogMiddleware) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !m.enabled && !m.hasSubscribers() {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		path := c.Request.URL.Path
+		requestURI := c.Request.RequestURI
+
+		// Processar requisição
+		c.Next()
+
+		route := c.GetString("route")
+		status := c.Writer.Status()
+
+		// Os assinantes recebem todas as requisições, sem amostragem
+		if m.hasSubscribers() {
+			m.publish(m.entry(c, start, path, route, status))
+		}
+
+		if !m.enabled || (status < 400 && !m.sampled(route)) {
+			return
+		}
+
+		if m.format == "combined" {
+			m.logger.Info(m.combinedLine(c, start, requestURI, status))
+			return
+		}
+
+		fields := []zap.Field{
+			zap.String("method", c.Request.Method),
+			zap.String("path", path),
+			zap.String("route", route),
+			zap.Int("status", status),
+			zap.Duration("latency", time.Since(start)),
+			zap.Int("bytes", responseBytes(c)),
+			zap.String("ip", clientIP(c)),
+		}
+		if country := geoip.FromContext(c.Request.Context()).Country; country != "" {
+			fields = append(fields, zap.String("country", country))
+		}
+		if id := requestid.FromContext(c.Request.Context()); id != "" {
+			fields = append(fields, zap.String("request_id", id))
+		}
+		if spanContext := trace.SpanFromContext(c.Request.Context()).SpanContext(); spanContext.IsValid() {
+			fields = append(fields, zap.String("trace_id", spanContext.TraceID().String()))
+		}
+		if user := contextUser(c); user != nil {
+			fields = append(fields,
+				zap.String("user_id", user.ID),
+				zap.String("username", user.Username))
+		}
+
+		// Incluir a atribuição de custo definida pelo handler da rota
+		if value, exists := c.Get(usage.ContextKey); exists {
+			if attribution, ok := value.(usage.Attribution); ok {
+				fields = append(fields,
+					zap.String("cost_team", attribution.Team),
+					zap.String("cost_product", attribution.Product),
+					zap.String("cost_consumer", attribution.Consumer))
+			}
+		}
+
+		m.logger.Info("request completed", fields...)
+	}
+}
+
+// Subscribe assina as requisições concluídas, com o buffer informado. A
+// função retornada cancela a assinatura e fecha o canal.
+func (m *AccessLogMiddleware) Subscribe(buffer int) (<-chan AccessLogEntry, func()) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	id := m.nextID
+	m.nextID++
+	ch := make(chan AccessLogEntry, buffer)
+	m.subscribers[id] = ch
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			delete(m.subscribers, id)
+			close(ch)
+		})
+	}
+}
+
+func (m *AccessLogMiddleware) hasSubscribers() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.subscribers) > 0
+}
+
+// publish entrega a entrada sem bloquear; assinantes lentos perdem entradas
+func (m *AccessLogMiddleware) publish(entry AccessLogEntry) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, ch := range m.subscribers {
+		select {
+		case ch <- entry:
+		default:
+		}
+	}
+}
+
+// entry monta a entrada entregue aos assinantes
+func (m *AccessLogMiddleware) entry(c *gin.Context, start time.Time, path, route string, status int) AccessLogEntry {
+	entry := AccessLogEntry{
+		Time:      start,
+		Method:    c.Request.Method,
+		Path:      path,
+		Route:     route,
+		Status:    status,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+		Bytes:     responseBytes(c),
+		IP:        clientIP(c),
+		Country:   geoip.FromContext(c.Request.Context()).Country,
+		RequestID: requestid.FromContext(c.Request.Context()),
+	}
+	if spanContext := trace.SpanFromContext(c.Request.Context()).SpanContext(); spanContext.IsValid() {
+		entry.TraceID = spanContext.TraceID().String()
+	}
+	if user := contextUser(c); user != nil {
+		entry.Username = user.Username
+	}
+	return entry
+}
+
+// sampled decide se a requisição bem-sucedida entra no log, conforme a taxa
+// de amostragem da rota ou a global
+func (m *AccessLogMiddleware) sampled(route string) bool {
+	sampling := m.sampling.Load()
+	rate, ok := sampling.routeRates[route]
+	if !ok {
+		rate = sampling.sampleRate
+	}
+	if rate >= 1 {
+		return true
+	}
+	return rand.Float64() < rate
+}
+
+// combinedLine monta a entrada no formato Apache combined
+func (m *AccessLogMiddleware) combinedLine(c *gin.Context, start time.Time, requestURI string, status int) string {
+	username := "-"
+	if user := contextUser(c); user != nil && user.Username != "" {
+		username = user.Username
+	}
+	bytes := "-"
+	if size := responseBytes(c); size > 0 {
+		bytes = strconv.Itoa(size)
+	}
+	return fmt.Sprintf("%s - %s [%s] %q %d %s %q %q",
+		orDash(clientIP(c)),
+		username,
+		start.Format(combinedTimeFormat),
+		c.Request.Method+" "+requestURI+" "+c.Request.Proto,
+		status,
+		bytes,
+		orDash(c.Request.Referer()),
+		orDash(c.Request.UserAgent()))
+}
+
+// contextUser retorna o usuário autenticado pela requisição, quando houver
+func contextUser(c *gin.Context) *model.User {
+	value, exists := c.Get("user")
+	if !exists {
+		return nil
+	}
+	user, _ := value.(*model.User)
+	return user
+}
+
+// responseBytes retorna o tamanho do corpo enviado ao cliente
+func responseBytes(c *gin.Context) int {
+	if size := c.Writer.Size(); size > 0 {
+		return size
+	}
+	return 0
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
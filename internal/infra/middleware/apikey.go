@@ -0,0 +1,132 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/app/auth"
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/internal/infra/metrics"
+	"github.com/diillson/api-gateway-go/pkg/ratelimit"
+	"github.com/diillson/api-gateway-go/pkg/tenant"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// APIKeyMiddleware autentica consumidores pela chave de API e aplica a faixa de
+// rate limit da chave
+type APIKeyMiddleware struct {
+	service *auth.APIKeyService
+	header  string
+	limiter *ratelimit.RedisLimiter
+	headers atomic.Pointer[string] // Estilo dos cabeçalhos de limite (ratelimit.headers)
+	metrics *metrics.APIMetrics
+	logger  *zap.Logger
+}
+
+// NewAPIKeyMiddleware cria um novo middleware de chaves de API
+func NewAPIKeyMiddleware(service *auth.APIKeyService, header string, limiter *ratelimit.RedisLimiter,
+	headers string, metrics *metrics.APIMetrics, logger *zap.Logger) *APIKeyMiddleware {
+	m := &APIKeyMiddleware{
+		service: service,
+		header:  header,
+		limiter: limiter,
+		metrics: metrics,
+		logger:  logger,
+	}
+	m.SetHeaders(headers)
+	return m
+}
+
+// SetHeaders troca o estilo dos cabeçalhos de limite, nas recargas da configuração
+func (m *APIKeyMiddleware) SetHeaders(style string) {
+	m.headers.Store(&style)
+}
+
+// Authenticate valida a chave do cabeçalho configurado. Requisições sem a chave
+// seguem adiante; a exigência da chave é definida pela política de cada rota.
+func (m *APIKeyMiddleware) Authenticate(c *gin.Context) {
+	value := c.GetHeader(m.header)
+	if value == "" {
+		c.Next()
+		return
+	}
+	// A chave não é repassada aos backends
+	c.Request.Header.Del(m.header)
+
+	key, err := m.service.Authenticate(c.Request.Context(), value)
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidAPIKey) {
+			m.logger.Warn("Chave de API inválida", zap.String("ip", clientIP(c)))
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Chave de API inválida ou revogada"})
+			return
+		}
+		m.logger.Error("Falha ao validar chave de API", zap.Error(err))
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "Falha ao validar chave de API"})
+		return
+	}
+
+	if !m.allow(c, key) {
+		return
+	}
+
+	// Armazena o consumidor no contexto, como na autenticação por JWT
+	c.Set(auth.APIKeyContextKey, key)
+	c.Set("user", &model.User{ID: key.ID, Username: key.Name, Role: "apikey"})
+	c.Set("claims", map[string]interface{}{"api_key_id": key.ID, "name": key.Name, "tier": key.Tier})
+	c.Next()
+}
+
+// allow aplica a faixa de rate limit da chave. Retorna false quando a
+// requisição foi recusada.
+func (m *APIKeyMiddleware) allow(c *gin.Context, key *model.APIKey) bool {
+	if key.Tier == "" || m.limiter == nil {
+		return true
+	}
+	tier, ok := m.service.Tier(key.Tier)
+	if !ok {
+		// Faixa removida da configuração: a chave segue sem limite próprio
+		m.logger.Warn("Faixa de rate limit da chave não configurada",
+			zap.String("id", key.ID), zap.String("tier", key.Tier))
+		return true
+	}
+
+	allowed, limit, remaining, resetAfter, err := m.limiter.Allow(c.Request.Context(), ratelimit.LimitConfig{
+		Key:         "apikey:" + key.ID,
+		Limit:       tier.Limit,
+		Period:      tier.Period,
+		BurstFactor: 1.0,
+	})
+	if err != nil {
+		m.logger.Error("erro ao verificar rate limit da chave de API", zap.Error(err))
+		return true // Em caso de erro, permite a requisição
+	}
+
+	now := time.Now()
+	resetAt := now.Add(resetAfter)
+	AddRateLimitState(c, *m.headers.Load(), ratelimit.State{
+		Legacy:    "X-RateLimit",
+		Limit:     int64(limit),
+		Remaining: int64(remaining),
+		ResetAt:   resetAt,
+		Window:    tier.Period,
+	})
+
+	if !allowed {
+		if m.metrics != nil {
+			m.metrics.RateLimitExceeded(tenant.FromContext(c.Request.Context()), c.Request.URL.Path, c.Request.Method, "apikey_tier")
+		}
+		retryAfter := ratelimit.RetryAfter(resetAt, now)
+		c.Header("Retry-After", strconv.Itoa(retryAfter))
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+			"error":       "taxa de requisições da chave de API excedida",
+			"tier":        key.Tier,
+			"retry_after": retryAfter,
+		})
+		return false
+	}
+	return true
+}
@@ -1,11 +1,14 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
+	"github.com/diillson/api-gateway-go/internal/app/audit"
 	"github.com/diillson/api-gateway-go/internal/app/auth"
 	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/pkg/tenant"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
@@ -32,58 +35,89 @@ func (m *AuthMiddleware) Authenticate(c *gin.Context) {
 		return
 	}
 
-	authHeader := c.GetHeader("Authorization")
-	if authHeader == "" {
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization header não fornecido"})
+	if _, ok := m.authenticate(c); !ok {
 		return
 	}
+	c.Next()
+}
 
-	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-	if tokenString == authHeader {
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Formato inválido do token"})
+// AuthenticateAdmin verifica se o usuário é um administrador. A permissão é
+// conferida antes de seguir para os próximos handlers da cadeia.
+func (m *AuthMiddleware) AuthenticateAdmin(c *gin.Context) {
+	user, ok := m.authenticate(c)
+	if !ok {
 		return
 	}
 
-	user, err := m.authService.ValidateToken(tokenString)
-	if err != nil {
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token inválido ou expirado"})
+	// Verifica se o usuário é administrador
+	if !m.authService.IsAdmin(user) {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Acesso negado: permissão de administrador necessária"})
 		return
 	}
 
-	// Armazena o usuário no contexto para uso posterior
-	c.Set("user", user)
 	c.Next()
 }
 
-// AuthenticateAdmin verifica se o usuário é um administrador
-func (m *AuthMiddleware) AuthenticateAdmin(c *gin.Context) {
-	// Primeiro autentica o usuário
-	m.Authenticate(c)
+// authenticate valida o token da requisição e guarda o usuário, as claims e o
+// tenant no contexto, sem seguir para os próximos handlers. Retorna false com
+// a requisição já abortada quando o token é recusado.
+func (m *AuthMiddleware) authenticate(c *gin.Context) (*model.User, bool) {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization header não fornecido"})
+		return nil, false
+	}
 
-	// Se o fluxo foi abortado no middleware anterior, retorna
-	if c.IsAborted() {
-		return
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+	if tokenString == authHeader {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Formato inválido do token"})
+		return nil, false
 	}
 
-	// Obtém o usuário do contexto
-	userValue, exists := c.Get("user")
-	if !exists {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Falha ao obter informações do usuário"})
-		return
+	user, claims, err := m.authService.ValidateTokenClaims(tokenString)
+	if errors.Is(err, auth.ErrTokenRevoked) {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token revogado"})
+		return nil, false
+	}
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token inválido ou expirado"})
+		return nil, false
 	}
 
-	user, ok := userValue.(*model.User)
-	if !ok {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Falha ao processar informações do usuário"})
-		return
+	// Usuários de um tenant só operam sobre ele: o tenant do cadastro ou da
+	// claim prevalece e um cabeçalho apontando para outro tenant é recusado
+	ctx := c.Request.Context()
+	if user.Tenant != "" {
+		if requested := tenant.FromContext(ctx); requested != "" && requested != user.Tenant {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Acesso negado: tenant fora do escopo do usuário"})
+			return nil, false
+		}
+		ctx = tenant.WithTenant(ctx, user.Tenant)
+		c.Set("tenant", user.Tenant)
 	}
 
-	// Verifica se o usuário é administrador
-	if !m.authService.IsAdmin(user) {
-		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Acesso negado: permissão de administrador necessária"})
+	// Armazena o usuário e suas claims no contexto para uso posterior
+	c.Set("user", user)
+	c.Set("claims", claims)
+
+	// Identifica o autor das operações administrativas no log de auditoria
+	c.Request = c.Request.WithContext(audit.WithActor(ctx, audit.Actor{
+		ID:       user.ID,
+		Username: user.Username,
+		IP:       c.ClientIP(),
+	}))
+	return user, true
+}
+
+// RequireGlobalAdmin restringe a rota aos administradores de todos os tenants.
+// Deve ser usado após Authenticate, nas operações que afetam o gateway inteiro.
+func (m *AuthMiddleware) RequireGlobalAdmin(c *gin.Context) {
+	userValue, _ := c.Get("user")
+	user, _ := userValue.(*model.User)
+	if !m.authService.IsGlobalAdmin(user) {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Acesso negado: operação restrita a administradores de todos os tenants"})
 		return
 	}
-
 	c.Next()
 }
 
@@ -91,6 +125,8 @@ func (m *AuthMiddleware) AuthenticateAdmin(c *gin.Context) {
 func isPublicRoute(path string) bool {
 	publicPaths := []string{
 		"/health",
+		"/livez",
+		"/readyz",
 		"/login",
 		"/swagger",
 	}
@@ -0,0 +1,158 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/app/auth"
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/pkg/security"
+	"github.com/diillson/api-gateway-go/pkg/tenant"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// stubUsers é um repositório de usuários em memória
+type stubUsers map[string]*model.User
+
+func (u stubUsers) GetUserByCredentials(username, password string) (*model.User, error) {
+	return nil, errors.New("não suportado")
+}
+
+func (u stubUsers) GetUserByID(id string) (*model.User, error) {
+	if user, ok := u[id]; ok {
+		return user, nil
+	}
+	return nil, errors.New("usuário não encontrado")
+}
+
+// newTestAuth cria o middleware de autenticação e os tokens dos usuários
+func newTestAuth(t *testing.T, users ...*model.User) (*AuthMiddleware, map[string]string) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	t.Setenv("JWT_SECRET_KEY", "segredo-de-teste-com-mais-de-32-caracteres")
+
+	keyManager, err := security.NewKeyManager(zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewKeyManager: %v", err)
+	}
+	repo := stubUsers{}
+	tokens := make(map[string]string)
+	for _, user := range users {
+		repo[user.ID] = user
+		token, err := keyManager.GenerateToken(user.ID, user.Role, time.Hour)
+		if err != nil {
+			t.Fatalf("GenerateToken: %v", err)
+		}
+		tokens[user.ID] = token
+	}
+	return NewAuthMiddleware(auth.NewAuthService(keyManager, repo, zap.NewNop()), zap.NewNop()), tokens
+}
+
+// serveAdmin executa uma requisição na cadeia AuthenticateAdmin → handler e
+// indica se o handler foi executado
+func serveAdmin(m *AuthMiddleware, req *http.Request) (*httptest.ResponseRecorder, bool) {
+	reached := false
+	router := gin.New()
+	router.POST("/admin/api/v1/routes", m.AuthenticateAdmin, func(c *gin.Context) {
+		reached = true
+		c.JSON(http.StatusCreated, gin.H{"ok": true})
+	})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec, reached
+}
+
+func newAdminRequest(token string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/admin/api/v1/routes", nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return req
+}
+
+func TestAuthenticateAdminRejectsNonAdminBeforeHandler(t *testing.T) {
+	m, tokens := newTestAuth(t, &model.User{ID: "u1", Username: "comum", Role: "user"})
+
+	rec, reached := serveAdmin(m, newAdminRequest(tokens["u1"]))
+	if reached {
+		t.Fatal("o handler foi executado para um usuário sem papel admin")
+	}
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, esperado %d", rec.Code, http.StatusForbidden)
+	}
+	if body := rec.Body.String(); body != `{"error":"Acesso negado: permissão de administrador necessária"}` {
+		t.Fatalf("corpo = %s, esperada apenas a mensagem de erro", body)
+	}
+}
+
+func TestAuthenticateAdminAllowsAdmin(t *testing.T) {
+	m, tokens := newTestAuth(t, &model.User{ID: "a1", Username: "admin", Role: "admin"})
+
+	rec, reached := serveAdmin(m, newAdminRequest(tokens["a1"]))
+	if !reached || rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, handler executado = %v; esperado 201 e handler executado", rec.Code, reached)
+	}
+}
+
+func TestAuthenticateAdminRejectsInvalidTokens(t *testing.T) {
+	m, _ := newTestAuth(t)
+
+	tests := map[string]*http.Request{
+		"sem token":      newAdminRequest(""),
+		"token inválido": newAdminRequest("nao-e-um-jwt"),
+		"formato inválido": func() *http.Request {
+			req := newAdminRequest("")
+			req.Header.Set("Authorization", "Basic dXNlcjpzZW5oYQ==")
+			return req
+		}(),
+	}
+	for name, req := range tests {
+		t.Run(name, func(t *testing.T) {
+			rec, reached := serveAdmin(m, req)
+			if reached {
+				t.Fatal("o handler foi executado sem token válido")
+			}
+			if rec.Code != http.StatusUnauthorized {
+				t.Fatalf("status = %d, esperado %d", rec.Code, http.StatusUnauthorized)
+			}
+		})
+	}
+}
+
+func TestAuthenticateAdminRejectsOtherTenant(t *testing.T) {
+	m, tokens := newTestAuth(t, &model.User{ID: "a1", Username: "admin-a", Role: "admin", Tenant: "tenant-a"})
+
+	req := newAdminRequest(tokens["a1"])
+	req = req.WithContext(tenant.WithTenant(req.Context(), "tenant-b"))
+	rec, reached := serveAdmin(m, req)
+	if reached {
+		t.Fatal("o handler foi executado para um tenant fora do escopo do administrador")
+	}
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, esperado %d", rec.Code, http.StatusForbidden)
+	}
+}
+
+func TestAuthenticateRunsHandlerOnce(t *testing.T) {
+	m, tokens := newTestAuth(t, &model.User{ID: "u1", Username: "comum", Role: "user"})
+
+	calls := 0
+	router := gin.New()
+	router.GET("/admin/apis", m.Authenticate, func(c *gin.Context) {
+		calls++
+		c.Status(http.StatusOK)
+	})
+	req := httptest.NewRequest(http.MethodGet, "/admin/apis", nil)
+	req.Header.Set("Authorization", "Bearer "+tokens["u1"])
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK || calls != 1 {
+		t.Fatalf("status = %d, handler executado %d vezes; esperado 200 e uma execução", rec.Code, calls)
+	}
+}
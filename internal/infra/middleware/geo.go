@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"github.com/diillson/api-gateway-go/pkg/clientip"
+	"github.com/diillson/api-gateway-go/pkg/config"
+	"github.com/diillson/api-gateway-go/pkg/geoip"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// GeoMiddleware localiza o cliente pelo endereço resolvido pelo middleware
+// ClientIP e associa a localização ao contexto da requisição, para as
+// restrições por país e a escolha de backends por região das rotas
+type GeoMiddleware struct {
+	resolver geoip.Resolver
+	logger   *zap.Logger
+}
+
+// NewGeoMiddleware cria o middleware a partir da configuração já validada. O
+// país informado pela CDN em geo.countryHeader tem precedência sobre a base.
+// Sem base carregada, os clientes seguem sem localização.
+func NewGeoMiddleware(cfg config.GeoConfig, proxies *clientip.Resolver, logger *zap.Logger) *GeoMiddleware {
+	m := &GeoMiddleware{logger: logger}
+	if !cfg.Enabled {
+		return m
+	}
+
+	var chain geoip.Chain
+	if cfg.CountryHeader != "" {
+		chain = append(chain, &geoip.HeaderResolver{Header: cfg.CountryHeader, Trusted: proxies.FromTrustedProxy})
+	}
+	if cfg.Database != "" {
+		db, err := geoip.OpenDatabase(cfg.Database)
+		if err != nil {
+			logger.Error("Falha ao carregar a base de geolocalização",
+				zap.String("database", cfg.Database),
+				zap.Error(err))
+		} else {
+			chain = append(chain, db)
+			logger.Info("Base de geolocalização carregada",
+				zap.String("database", cfg.Database),
+				zap.String("type", db.DatabaseType()),
+				zap.Time("buildTime", db.BuildTime()))
+		}
+	}
+	if len(chain) > 0 {
+		m.resolver = chain
+	}
+	return m
+}
+
+// SetResolver substitui o resolvedor configurado por uma implementação própria
+func (m *GeoMiddleware) SetResolver(resolver geoip.Resolver) {
+	m.resolver = resolver
+}
+
+// Middleware associa a localização do cliente ao contexto da requisição
+func (m *GeoMiddleware) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if m.resolver == nil {
+			c.Next()
+			return
+		}
+
+		addr, _ := clientip.FromContext(c.Request.Context())
+		if location, ok := m.resolver.Locate(c.Request, addr); ok {
+			c.Request = c.Request.WithContext(geoip.WithLocation(c.Request.Context(), location))
+			c.Set("geo_country", location.Country)
+		}
+		c.Next()
+	}
+}
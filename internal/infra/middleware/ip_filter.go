@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/netip"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/internal/infra/metrics"
+	"github.com/diillson/api-gateway-go/pkg/clientip"
+	"github.com/diillson/api-gateway-go/pkg/config"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// IPFilterMiddleware resolve o endereço do cliente, considerando os proxies
+// confiáveis, e restringe as origens das rotas administrativas
+type IPFilterMiddleware struct {
+	resolver   *clientip.Resolver
+	adminAllow []netip.Prefix
+	adminDeny  []netip.Prefix
+	metrics    *metrics.APIMetrics
+	logger     *zap.Logger
+}
+
+// NewIPFilterMiddleware cria o middleware a partir da configuração. Listas
+// inválidas são recusadas, pois ignorá-las liberaria as rotas administrativas
+// a qualquer origem.
+func NewIPFilterMiddleware(server config.ServerConfig, admin config.IPFilterConfig, metrics *metrics.APIMetrics, logger *zap.Logger) (*IPFilterMiddleware, error) {
+	resolver, err := clientip.NewResolver(server.TrustedProxies, server.ForwardedForDepth)
+	if err != nil {
+		return nil, fmt.Errorf("server.trustedProxies inválido: %w", err)
+	}
+
+	adminAllow, err := model.ParsePrefixes(admin.Allow)
+	if err != nil {
+		return nil, fmt.Errorf("admin.ipFilter.allow inválido: %w", err)
+	}
+	adminDeny, err := model.ParsePrefixes(admin.Deny)
+	if err != nil {
+		return nil, fmt.Errorf("admin.ipFilter.deny inválido: %w", err)
+	}
+
+	return &IPFilterMiddleware{
+		resolver:   resolver,
+		adminAllow: adminAllow,
+		adminDeny:  adminDeny,
+		metrics:    metrics,
+		logger:     logger,
+	}, nil
+}
+
+// Resolve associa o endereço do cliente ao contexto da requisição
+func (m *IPFilterMiddleware) Resolve() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		addr := m.resolver.Resolve(c.Request)
+		c.Request = c.Request.WithContext(clientip.WithAddr(c.Request.Context(), addr))
+		if addr.IsValid() {
+			c.Set("client_ip", addr.String())
+		}
+		c.Next()
+	}
+}
+
+// Admin recusa as origens fora de admin.ipFilter
+func (m *IPFilterMiddleware) Admin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if len(m.adminAllow) == 0 && len(m.adminDeny) == 0 {
+			c.Next()
+			return
+		}
+
+		addr, ok := clientip.FromContext(c.Request.Context())
+		if !ok {
+			addr = m.resolver.Resolve(c.Request)
+		}
+		if !model.AllowsAddr(addr, m.adminAllow, m.adminDeny) {
+			m.logger.Warn("Acesso administrativo recusado pela origem",
+				zap.String("path", c.Request.URL.Path),
+				zap.String("ip", addr.String()))
+			trace.SpanFromContext(c.Request.Context()).SetAttributes(
+				attribute.String("ip_filter.decision", "deny"),
+			)
+			if m.metrics != nil {
+				m.metrics.RequestError(c.Request.URL.Path, c.Request.Method, "ip_denied")
+			}
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Acesso negado para o endereço de origem"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// AllowsAdmin aplica admin.ipFilter a um endereço já resolvido, como o de
+// conexões da API gRPC
+func (m *IPFilterMiddleware) AllowsAdmin(addr netip.Addr) bool {
+	return model.AllowsAddr(addr, m.adminAllow, m.adminDeny)
+}
+
+// clientIP retorna o endereço resolvido pelo middleware ClientIP, ou o do Gin
+// quando ele não foi executado
+func clientIP(c *gin.Context) string {
+	if ip := c.GetString("client_ip"); ip != "" {
+		return ip
+	}
+	return c.ClientIP()
+}
@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/diillson/api-gateway-go/pkg/config"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+func TestNewIPFilterMiddlewareRejectsInvalidConfig(t *testing.T) {
+	tests := map[string]struct {
+		server config.ServerConfig
+		admin  config.IPFilterConfig
+	}{
+		"allow inválido":           {admin: config.IPFilterConfig{Allow: []string{"10.0.0.0/8", "rede-interna"}}},
+		"deny inválido":            {admin: config.IPFilterConfig{Deny: []string{"300.0.0.1"}}},
+		"proxy confiável inválido": {server: config.ServerConfig{TrustedProxies: []string{"10.0.0.0/40"}}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewIPFilterMiddleware(tt.server, tt.admin, nil, zap.NewNop()); err == nil {
+				t.Fatal("configuração inválida aceita; o filtro administrativo ficaria aberto")
+			}
+		})
+	}
+}
+
+func TestIPFilterAdminUsesTrustedProxies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m, err := NewIPFilterMiddleware(
+		config.ServerConfig{TrustedProxies: []string{"10.0.0.0/8"}},
+		config.IPFilterConfig{Allow: []string{"203.0.113.0/24"}},
+		nil, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewIPFilterMiddleware: %v", err)
+	}
+
+	router := gin.New()
+	router.Use(m.Resolve())
+	router.GET("/admin/apis", m.Admin(), func(c *gin.Context) {
+		c.String(http.StatusOK, clientIP(c))
+	})
+
+	tests := []struct {
+		name   string
+		remote string
+		xff    string
+		want   int
+	}{
+		{"cliente permitido atrás do proxy", "10.0.0.1:4000", "203.0.113.7", http.StatusOK},
+		{"cliente recusado atrás do proxy", "10.0.0.1:4000", "198.51.100.1", http.StatusForbidden},
+		{"cabeçalho forjado sem proxy confiável", "198.51.100.1:4000", "203.0.113.7", http.StatusForbidden},
+		{"entrada forjada à esquerda", "10.0.0.1:4000", "203.0.113.7, 198.51.100.1", http.StatusForbidden},
+		{"conexão direta permitida", "203.0.113.9:4000", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/admin/apis", nil)
+			req.RemoteAddr = tt.remote
+			if tt.xff != "" {
+				req.Header.Set("X-Forwarded-For", tt.xff)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, esperado %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
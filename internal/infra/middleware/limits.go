@@ -0,0 +1,165 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/diillson/api-gateway-go/internal/infra/metrics"
+	"github.com/diillson/api-gateway-go/pkg/config"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// RequestLimitsMiddleware rejeita requisições com cabeçalhos ou linha de requisição
+// fora dos limites configurados e padrões conhecidos de request smuggling
+type RequestLimitsMiddleware struct {
+	config         atomic.Pointer[config.RequestLimitsConfig] // trocada nas recargas da configuração
+	maxHeaderBytes int
+	metrics        *metrics.APIMetrics
+	logger         *zap.Logger
+}
+
+// NewRequestLimitsMiddleware cria o middleware de limites de requisição
+func NewRequestLimitsMiddleware(cfg config.RequestLimitsConfig, maxHeaderBytes int, metrics *metrics.APIMetrics, logger *zap.Logger) *RequestLimitsMiddleware {
+	m := &RequestLimitsMiddleware{
+		maxHeaderBytes: maxHeaderBytes,
+		metrics:        metrics,
+		logger:         logger,
+	}
+	m.config.Store(&cfg)
+	return m
+}
+
+// Reload aplica os limites recarregados às próximas requisições
+func (m *RequestLimitsMiddleware) Reload(cfg config.RequestLimitsConfig) {
+	m.config.Store(&cfg)
+}
+
+// Middleware retorna o handler do Gin
+func (m *RequestLimitsMiddleware) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !m.config.Load().Enabled {
+			c.Next()
+			return
+		}
+
+		if status, reason, message := m.check(c.Request); status != 0 {
+			m.logger.Warn("Requisição rejeitada por limite de cabeçalho ou linha de requisição",
+				zap.String("reason", reason),
+				zap.String("ip", clientIP(c)),
+				zap.String("method", c.Request.Method),
+				zap.Int("url_length", len(c.Request.RequestURI)))
+
+			if m.metrics != nil {
+				m.metrics.RequestRejected(reason)
+			}
+
+			// Conexões com enquadramento ambíguo não devem ser reutilizadas
+			if reason == "request_smuggling" {
+				c.Header("Connection", "close")
+			}
+
+			c.AbortWithStatusJSON(status, gin.H{"error": message})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// check aplica os limites e retorna o status, o motivo e a mensagem da rejeição.
+// Status zero indica que a requisição está dentro dos limites.
+func (m *RequestLimitsMiddleware) check(r *http.Request) (int, string, string) {
+	limits := m.config.Load()
+	if limits.MaxURLLength > 0 && len(r.RequestURI) > limits.MaxURLLength {
+		return http.StatusRequestURITooLong, "url_too_long", "URI da requisição excede o tamanho máximo permitido"
+	}
+
+	headerCount := 0
+	headerBytes := 0
+	for name, values := range r.Header {
+		for _, value := range values {
+			headerCount++
+			// nome + ": " + valor + CRLF
+			headerBytes += len(name) + len(value) + 4
+
+			if limits.MaxHeaderValueBytes > 0 && len(value) > limits.MaxHeaderValueBytes {
+				return http.StatusRequestHeaderFieldsTooLarge, "header_value_too_large",
+					"Cabeçalho " + name + " excede o tamanho máximo permitido"
+			}
+		}
+	}
+
+	if limits.MaxHeaderCount > 0 && headerCount > limits.MaxHeaderCount {
+		return http.StatusRequestHeaderFieldsTooLarge, "too_many_headers", "Quantidade de cabeçalhos excede o limite permitido"
+	}
+
+	if m.maxHeaderBytes > 0 && headerBytes > m.maxHeaderBytes {
+		return http.StatusRequestHeaderFieldsTooLarge, "headers_too_large", "Tamanho total dos cabeçalhos excede o limite permitido"
+	}
+
+	if limits.RejectSmuggling && isAmbiguousFraming(r) {
+		return http.StatusBadRequest, "request_smuggling", "Enquadramento da requisição ambíguo"
+	}
+
+	if limits.RejectSmuggling && hidesEndToEndHeaders(r) {
+		return http.StatusBadRequest, "request_smuggling", "Cabeçalho Connection inválido"
+	}
+
+	return 0, "", ""
+}
+
+// isAmbiguousFraming detecta combinações de Transfer-Encoding e Content-Length que
+// podem ser interpretadas de forma diferente pelo gateway e pelo backend
+func isAmbiguousFraming(r *http.Request) bool {
+	// Transfer-Encoding só é aceito como um único "chunked"
+	if len(r.TransferEncoding) > 1 {
+		return true
+	}
+	if len(r.TransferEncoding) == 1 && !strings.EqualFold(r.TransferEncoding[0], "chunked") {
+		return true
+	}
+
+	// O servidor HTTP remove o cabeçalho após interpretá-lo; se ainda estiver presente,
+	// o valor não foi reconhecido (ex.: "chunked " ou "xchunked")
+	if len(r.Header.Values("Transfer-Encoding")) > 0 {
+		return true
+	}
+
+	// Content-Length junto com chunked, ou repetido
+	contentLengths := r.Header.Values("Content-Length")
+	if len(r.TransferEncoding) > 0 && len(contentLengths) > 0 {
+		return true
+	}
+	if len(contentLengths) > 1 || (len(contentLengths) == 1 && strings.Contains(contentLengths[0], ",")) {
+		return true
+	}
+
+	return false
+}
+
+// connectionTokens são as opções aceitas no cabeçalho Connection
+var connectionTokens = map[string]bool{
+	"keep-alive":     true,
+	"close":          true,
+	"upgrade":        true,
+	"te":             true,
+	"http2-settings": true,
+}
+
+// hidesEndToEndHeaders detecta um cabeçalho Connection que lista outros
+// cabeçalhos. O proxy remove os cabeçalhos listados antes de encaminhar, o que
+// permitiria ao cliente retirar os que o gateway envia ao backend, como
+// X-Forwarded-For ou os de identidade.
+func hidesEndToEndHeaders(r *http.Request) bool {
+	for _, value := range r.Header.Values("Connection") {
+		for _, token := range strings.Split(value, ",") {
+			token = strings.ToLower(strings.TrimSpace(token))
+			if token != "" && !connectionTokens[token] {
+				return true
+			}
+		}
+	}
+	return false
+}
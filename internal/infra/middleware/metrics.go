@@ -28,6 +28,7 @@ func NewMetricsMiddleware(metrics *metrics.APIMetrics, logger *zap.Logger) *Metr
 type MetricsHandler struct {
 	Metrics *metrics.APIMetrics
 	Logger  *zap.Logger
+	Path    string // Caminho do endpoint; vazio usa /metrics
 }
 
 // GetMetrics retorna o objeto APIMetrics para uso em outras partes da aplicação
@@ -37,8 +38,12 @@ func (h *MetricsHandler) GetMetrics() *metrics.APIMetrics {
 
 // RegisterEndpoint registra o endpoint para expor métricas do Prometheus
 func (h *MetricsHandler) RegisterEndpoint(router *gin.Engine) {
-	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
-	h.Logger.Info("Endpoint de métricas Prometheus registrado em /metrics")
+	path := h.Path
+	if path == "" {
+		path = "/metrics"
+	}
+	router.GET(path, gin.WrapH(promhttp.Handler()))
+	h.Logger.Info("Endpoint de métricas Prometheus registrado", zap.String("path", path))
 }
 
 // NewMetricsHandler cria um novo handler de métricas
@@ -6,11 +6,13 @@ import (
 	"github.com/diillson/api-gateway-go/internal/infra/metrics"
 	"github.com/diillson/api-gateway-go/pkg/cache"
 	"github.com/diillson/api-gateway-go/pkg/config"
+	"github.com/diillson/api-gateway-go/pkg/geoip"
 	"github.com/diillson/api-gateway-go/pkg/ratelimit"
 	"github.com/gin-gonic/gin"
 	"github.com/go-redis/redis/v8"
 	"go.uber.org/zap"
 	"net/http"
+	"net/netip"
 	"time"
 )
 
@@ -23,10 +25,20 @@ type Middleware struct {
 	tracingMiddleware   *TracingMiddleware
 	metricsMiddleware   *MetricsMiddleware
 	rateLimitMiddleware *RateLimitMiddleware
+	tenantMiddleware    *TenantMiddleware
+	limitsMiddleware    *RequestLimitsMiddleware
+	apiKeyMiddleware    *APIKeyMiddleware
+	ipFilterMiddleware  *IPFilterMiddleware
+	accessLogMiddleware *AccessLogMiddleware
+	requestIDMiddleware *RequestIDMiddleware
+	overloadMiddleware  *OverloadMiddleware
+	geoMiddleware       *GeoMiddleware
+	metrics             *metrics.APIMetrics
+	rateLimitHeaders    string // Estilo dos cabeçalhos de limite (ratelimit.headers)
 }
 
 // NewMiddleware cria um novo conjunto de middlewares
-func NewMiddleware(logger *zap.Logger, authService *auth.AuthService, apiMetrics *metrics.APIMetrics) *Middleware {
+func NewMiddleware(logger *zap.Logger, authService *auth.AuthService, apiMetrics *metrics.APIMetrics) (*Middleware, error) {
 	// Carregar configuração
 	cfg, err := config.LoadConfig("./config")
 	serviceName := "api-gateway"
@@ -34,6 +46,29 @@ func NewMiddleware(logger *zap.Logger, authService *auth.AuthService, apiMetrics
 		serviceName = cfg.Tracing.ServiceName
 	}
 
+	tenantHeader := ""
+	var tenantHosts []config.TenantHostConfig
+	var limitsConfig config.RequestLimitsConfig
+	var securityHeaders config.SecurityHeadersConfig
+	maxHeaderBytes := 0
+	var serverConfig config.ServerConfig
+	var adminIPFilter config.IPFilterConfig
+	var geoConfig config.GeoConfig
+	accessLogConfig := config.AccessLogConfig{Enabled: true, Format: "json", SampleRate: 1}
+	rateLimitHeaders := ratelimit.HeadersBoth
+	if err == nil {
+		tenantHeader = cfg.Database.TenantHeader
+		tenantHosts = cfg.Database.TenantHosts
+		limitsConfig = cfg.Server.Limits
+		securityHeaders = cfg.Server.SecurityHeaders
+		maxHeaderBytes = cfg.Server.MaxHeaderBytes
+		serverConfig = cfg.Server
+		adminIPFilter = cfg.Admin.IPFilter
+		geoConfig = cfg.Geo
+		accessLogConfig = cfg.Logging.Access
+		rateLimitHeaders = cfg.RateLimit.Headers
+	}
+
 	// Criar um cliente Redis com a configuração correta
 	var redisClient *redis.Client
 
@@ -77,16 +112,61 @@ func NewMiddleware(logger *zap.Logger, authService *auth.AuthService, apiMetrics
 	}
 
 	// Inicializar o middleware de rate limit
-	rateLimitMiddleware := NewRateLimitMiddleware(limiter, apiMetrics, logger)
+	rateLimitMiddleware := NewRateLimitMiddleware(limiter, rateLimitHeaders, apiMetrics, logger)
 	tracingMiddleware := NewTracingMiddleware(logger, serviceName)
+	ipFilterMiddleware, err := NewIPFilterMiddleware(serverConfig, adminIPFilter, apiMetrics, logger)
+	if err != nil {
+		return nil, err
+	}
 
 	return &Middleware{
 		logger:              logger,
 		authMiddleware:      NewAuthMiddleware(authService, logger),
 		recoveryMiddleware:  NewRecoveryMiddleware(logger),
-		securityMiddleware:  NewSecurityMiddleware(logger),
+		securityMiddleware:  NewSecurityMiddleware(securityHeaders, logger),
 		tracingMiddleware:   tracingMiddleware,
 		rateLimitMiddleware: rateLimitMiddleware,
+		tenantMiddleware:    NewTenantMiddleware(tenantHeader, tenantHosts),
+		limitsMiddleware:    NewRequestLimitsMiddleware(limitsConfig, maxHeaderBytes, apiMetrics, logger),
+		ipFilterMiddleware:  ipFilterMiddleware,
+		accessLogMiddleware: NewAccessLogMiddleware(accessLogConfig, logger),
+		requestIDMiddleware: NewRequestIDMiddleware(serverConfig.RequestIDHeader),
+		overloadMiddleware:  NewOverloadMiddleware(serverConfig.Overload, apiMetrics, logger),
+		geoMiddleware:       NewGeoMiddleware(geoConfig, ipFilterMiddleware.resolver, logger),
+		metrics:             apiMetrics,
+		rateLimitHeaders:    rateLimitHeaders,
+	}, nil
+}
+
+// SetAPIKeys habilita a autenticação por chaves de API
+func (m *Middleware) SetAPIKeys(service *auth.APIKeyService, header string) {
+	m.apiKeyMiddleware = NewAPIKeyMiddleware(service, header, m.rateLimitMiddleware.limiter, m.rateLimitHeaders, m.metrics, m.logger)
+}
+
+// APIKeyAuth retorna o middleware de chaves de API
+func (m *Middleware) APIKeyAuth() gin.HandlerFunc {
+	if m.apiKeyMiddleware != nil {
+		return m.apiKeyMiddleware.Authenticate
+	}
+	return func(c *gin.Context) {
+		c.Next() // No-op se não configurado
+	}
+}
+
+// SubscribeAccessLog assina as requisições concluídas, para acompanhamento
+// do log de acesso em tempo real
+func (m *Middleware) SubscribeAccessLog(buffer int) (<-chan AccessLogEntry, func()) {
+	return m.accessLogMiddleware.Subscribe(buffer)
+}
+
+// Reload aplica aos middlewares as configurações recarregadas sem reinício
+func (m *Middleware) Reload(cfg *config.Config) {
+	m.limitsMiddleware.Reload(cfg.Server.Limits)
+	m.securityMiddleware.Reload(cfg.Server.SecurityHeaders)
+	m.accessLogMiddleware.Reload(cfg.Logging.Access)
+	m.rateLimitMiddleware.SetHeaders(cfg.RateLimit.Headers)
+	if m.apiKeyMiddleware != nil {
+		m.apiKeyMiddleware.SetHeaders(cfg.RateLimit.Headers)
 	}
 }
 
@@ -115,6 +195,12 @@ func (m *Middleware) AuthenticateAdmin(c *gin.Context) {
 	m.authMiddleware.AuthenticateAdmin(c)
 }
 
+// RequireGlobalAdmin middleware que restringe a rota aos administradores de
+// todos os tenants
+func (m *Middleware) RequireGlobalAdmin(c *gin.Context) {
+	m.authMiddleware.RequireGlobalAdmin(c)
+}
+
 // Recovery middleware para recuperação de pânicos
 func (m *Middleware) Recovery() gin.HandlerFunc {
 	return m.recoveryMiddleware.Recovery()
@@ -132,29 +218,14 @@ func (m *Middleware) IgnoreFavicon() gin.HandlerFunc {
 	}
 }
 
-// Logger middleware para logging de requisições
-func (m *Middleware) Logger() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		start := time.Now()
-		path := c.Request.URL.Path
-
-		// Processar requisição
-		c.Next()
+// RequestID garante o identificador da requisição e o devolve ao cliente
+func (m *Middleware) RequestID() gin.HandlerFunc {
+	return m.requestIDMiddleware.Middleware()
+}
 
-		// Depois de processada
-		latency := time.Since(start)
-		status := c.Writer.Status()
-		clientIP := c.ClientIP()
-		method := c.Request.Method
-
-		m.logger.Info("request completed",
-			zap.String("path", path),
-			zap.String("method", method),
-			zap.Int("status", status),
-			zap.Duration("latency", latency),
-			zap.String("ip", clientIP),
-		)
-	}
+// Logger registra o log de acesso de cada requisição
+func (m *Middleware) Logger() gin.HandlerFunc {
+	return m.accessLogMiddleware.Middleware()
 }
 
 // SecurityHeaders middleware para adicionar cabeçalhos de segurança
@@ -167,6 +238,57 @@ func (m *Middleware) CORS() gin.HandlerFunc {
 	return m.securityMiddleware.CORS()
 }
 
+// RequestLimits retorna o middleware de limites de cabeçalho e linha de requisição
+func (m *Middleware) RequestLimits() gin.HandlerFunc {
+	return m.limitsMiddleware.Middleware()
+}
+
+// Overload retorna o middleware que descarta as requisições às rotas acima do
+// limite adaptativo de concorrência (server.overload)
+func (m *Middleware) Overload() gin.HandlerFunc {
+	return m.overloadMiddleware.Middleware()
+}
+
+// Tenant retorna o middleware que identifica o tenant da requisição
+func (m *Middleware) Tenant() gin.HandlerFunc {
+	return m.tenantMiddleware.Middleware()
+}
+
+// TenantHeader retorna o middleware que aceita o tenant do cabeçalho, usado
+// apenas nas APIs administrativas
+func (m *Middleware) TenantHeader() gin.HandlerFunc {
+	return m.tenantMiddleware.Header()
+}
+
+// ClientIP retorna o middleware que resolve o endereço do cliente considerando
+// os proxies confiáveis (server.trustedProxies e server.forwardedForDepth)
+func (m *Middleware) ClientIP() gin.HandlerFunc {
+	return m.ipFilterMiddleware.Resolve()
+}
+
+// Geo retorna o middleware que localiza o cliente pelo endereço de origem
+// (geo); deve ser aplicado após ClientIP
+func (m *Middleware) Geo() gin.HandlerFunc {
+	return m.geoMiddleware.Middleware()
+}
+
+// SetGeoResolver substitui a localização dos clientes por um resolvedor
+// próprio, no lugar da base MaxMind
+func (m *Middleware) SetGeoResolver(resolver geoip.Resolver) {
+	m.geoMiddleware.SetResolver(resolver)
+}
+
+// AdminIPFilter retorna o middleware que restringe as rotas administrativas às
+// origens de admin.ipFilter
+func (m *Middleware) AdminIPFilter() gin.HandlerFunc {
+	return m.ipFilterMiddleware.Admin()
+}
+
+// AllowsAdminAddr aplica admin.ipFilter ao endereço informado
+func (m *Middleware) AllowsAdminAddr(addr netip.Addr) bool {
+	return m.ipFilterMiddleware.AllowsAdmin(addr)
+}
+
 // Tracing retorna o middleware de tracing
 func (m *Middleware) Tracing() gin.HandlerFunc {
 	return m.tracingMiddleware.Middleware()
@@ -6,9 +6,11 @@ import (
 	"github.com/diillson/api-gateway-go/internal/infra/metrics"
 	"net/http"
 	"strconv"
+	"sync/atomic"
 	"time"
 
 	"github.com/diillson/api-gateway-go/pkg/ratelimit"
+	"github.com/diillson/api-gateway-go/pkg/tenant"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
@@ -16,18 +18,42 @@ import (
 // RateLimitMiddleware gerencia rate limiting
 type RateLimitMiddleware struct {
 	limiter             *ratelimit.RedisLimiter
+	headers             atomic.Pointer[string] // Estilo dos cabeçalhos de limite (ratelimit.headers)
 	logger              *zap.Logger
 	metrics             *metrics.APIMetrics
 	rateLimitMiddleware *RateLimitMiddleware
 }
 
 // NewRateLimitMiddleware cria um novo middleware de rate limiting
-func NewRateLimitMiddleware(limiter *ratelimit.RedisLimiter, metrics *metrics.APIMetrics, logger *zap.Logger) *RateLimitMiddleware {
-	return &RateLimitMiddleware{
+func NewRateLimitMiddleware(limiter *ratelimit.RedisLimiter, headers string, metrics *metrics.APIMetrics, logger *zap.Logger) *RateLimitMiddleware {
+	m := &RateLimitMiddleware{
 		limiter: limiter,
 		logger:  logger,
 		metrics: metrics,
 	}
+	m.SetHeaders(headers)
+	return m
+}
+
+// SetHeaders troca o estilo dos cabeçalhos de limite, nas recargas da configuração
+func (m *RateLimitMiddleware) SetHeaders(style string) {
+	m.headers.Store(&style)
+}
+
+// limitExceeded registra o estado do limite nos cabeçalhos e, se a requisição
+// excedeu o limite, a recusa com Retry-After. Retorna false quando recusada.
+func (m *RateLimitMiddleware) limitExceeded(c *gin.Context, state ratelimit.State, allowed bool, message string) bool {
+	AddRateLimitState(c, *m.headers.Load(), state)
+	if allowed {
+		return false
+	}
+	retryAfter := ratelimit.RetryAfter(state.ResetAt, time.Now())
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+		"error":       message,
+		"retry_after": retryAfter,
+	})
+	return true
 }
 
 // IPRateLimit limita requisições por IP
@@ -37,17 +63,17 @@ func (m *RateLimitMiddleware) IPRateLimit() gin.HandlerFunc {
 	}
 	return func(c *gin.Context) {
 		// Obtém o IP do cliente
-		clientIP := c.ClientIP()
+		ip := clientIP(c)
 
 		// Configuração do limitador para este IP
 		config := ratelimit.LimitConfig{
-			Key:         clientIP,
+			Key:         ip,
 			Limit:       100,         // 100 requisições
 			Period:      time.Minute, // por minuto
 			BurstFactor: 1.5,         // permite até 50% mais em picos
 		}
 
-		blockKey := fmt.Sprintf("ratelimit:blocked:%s", clientIP)
+		blockKey := fmt.Sprintf("ratelimit:blocked:%s", ip)
 		blocked, _ := m.limiter.RedisClient.Get(c, blockKey).Bool()
 		if blocked {
 			c.Header("Retry-After", "600") // 10 minutos
@@ -74,15 +100,15 @@ func (m *RateLimitMiddleware) IPRateLimit() gin.HandlerFunc {
 				if path == "" {
 					path = c.Request.URL.Path
 				}
-				m.metrics.RateLimitExceeded(path, c.Request.Method, "ip_limit")
+				m.metrics.RateLimitExceeded(tenant.FromContext(c.Request.Context()), path, c.Request.Method, "ip_limit")
 			}
 			m.logger.Warn("Possível ataque detectado - alto volume de requisições",
-				zap.String("ip", clientIP),
+				zap.String("ip", ip),
 				zap.Int("requests", limit-remaining),
 				zap.Int("threshold", limit*3))
 
 			// Bloquear IP por período mais longo (10 minutos)
-			blockKey := fmt.Sprintf("ratelimit:blocked:%s", clientIP)
+			blockKey := fmt.Sprintf("ratelimit:blocked:%s", ip)
 			m.limiter.RedisClient.Set(c, blockKey, true, 10*time.Minute)
 
 			c.Header("Retry-After", "600") // 10 minutos
@@ -94,16 +120,9 @@ func (m *RateLimitMiddleware) IPRateLimit() gin.HandlerFunc {
 		}
 
 		// Adiciona cabeçalhos de rate limit
-		c.Header("X-RateLimit-Limit", strconv.Itoa(limit))
-		c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
-		c.Header("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(resetAfter).Unix(), 10))
-
-		if !allowed {
-			c.Header("Retry-After", strconv.Itoa(int(resetAfter.Seconds())))
-			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
-				"error":       "taxa de requisições excedida",
-				"retry_after": int(resetAfter.Seconds()),
-			})
+		state := ratelimit.State{Legacy: "X-RateLimit", Limit: int64(limit), Remaining: int64(remaining),
+			ResetAt: time.Now().Add(resetAfter), Window: config.Period}
+		if m.limitExceeded(c, state, allowed, "taxa de requisições excedida") {
 			return
 		}
 
@@ -119,7 +138,7 @@ func (m *RateLimitMiddleware) APIRateLimit(limit int, period time.Duration) gin.
 		if path == "" {
 			path = c.Request.URL.Path
 		}
-		m.metrics.RateLimitExceeded(path, c.Request.Method, "ip_limit")
+		m.metrics.RateLimitExceeded(tenant.FromContext(c.Request.Context()), path, c.Request.Method, "ip_limit")
 
 		// Configuração do limitador para esta API
 		config := ratelimit.LimitConfig{
@@ -138,16 +157,9 @@ func (m *RateLimitMiddleware) APIRateLimit(limit int, period time.Duration) gin.
 		}
 
 		// Adiciona cabeçalhos de rate limit
-		c.Header("X-RateLimit-Limit", strconv.Itoa(limit))
-		c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
-		c.Header("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(resetAfter).Unix(), 10))
-
-		if !allowed {
-			c.Header("Retry-After", strconv.Itoa(int(resetAfter.Seconds())))
-			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
-				"error":       "taxa de requisições para esta API excedida",
-				"retry_after": int(resetAfter.Seconds()),
-			})
+		state := ratelimit.State{Legacy: "X-RateLimit", Limit: int64(limit), Remaining: int64(remaining),
+			ResetAt: time.Now().Add(resetAfter), Window: period}
+		if m.limitExceeded(c, state, allowed, "taxa de requisições para esta API excedida") {
 			return
 		}
 
@@ -188,16 +200,9 @@ func (m *RateLimitMiddleware) UserRateLimit() gin.HandlerFunc {
 		}
 
 		// Adiciona cabeçalhos de rate limit
-		c.Header("X-RateLimit-User-Limit", strconv.Itoa(limit))
-		c.Header("X-RateLimit-User-Remaining", strconv.Itoa(remaining))
-		c.Header("X-RateLimit-User-Reset", strconv.FormatInt(time.Now().Add(resetAfter).Unix(), 10))
-
-		if !allowed {
-			c.Header("Retry-After", strconv.Itoa(int(resetAfter.Seconds())))
-			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
-				"error":       "taxa de requisições do usuário excedida",
-				"retry_after": int(resetAfter.Seconds()),
-			})
+		state := ratelimit.State{Legacy: "X-RateLimit-User", Limit: int64(limit), Remaining: int64(remaining),
+			ResetAt: time.Now().Add(resetAfter), Window: config.Period}
+		if m.limitExceeded(c, state, allowed, "taxa de requisições do usuário excedida") {
 			return
 		}
 
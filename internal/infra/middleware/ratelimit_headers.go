@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/diillson/api-gateway-go/pkg/ratelimit"
+	"github.com/gin-gonic/gin"
+)
+
+// rateLimitStatesKey guarda no contexto os limites contabilizados na requisição
+const rateLimitStatesKey = "ratelimit.states"
+
+// AddRateLimitState registra o estado de um limite aplicado à requisição e
+// reescreve os cabeçalhos de todos os limites registrados no estilo informado
+func AddRateLimitState(c *gin.Context, style string, state ratelimit.State) {
+	states := append(rateLimitStates(c), state)
+	c.Set(rateLimitStatesKey, states)
+	ratelimit.WriteHeaders(c.Writer.Header(), style, states, time.Now())
+}
+
+// ApplyRateLimitHeaders reescreve os cabeçalhos dos limites já registrados no
+// estilo informado, usado quando a rota define um estilo próprio
+func ApplyRateLimitHeaders(c *gin.Context, style string) {
+	if states := rateLimitStates(c); len(states) > 0 {
+		ratelimit.WriteHeaders(c.Writer.Header(), style, states, time.Now())
+	}
+}
+
+func rateLimitStates(c *gin.Context) []ratelimit.State {
+	value, _ := c.Get(rateLimitStatesKey)
+	states, _ := value.([]ratelimit.State)
+	return states
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"github.com/diillson/api-gateway-go/pkg/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDMiddleware garante um identificador em cada requisição: reaproveita
+// o recebido do cliente, quando válido, ou gera um novo. O identificador segue
+// no contexto, na requisição encaminhada ao backend e na resposta.
+type RequestIDMiddleware struct {
+	header string
+}
+
+// NewRequestIDMiddleware cria o middleware com o cabeçalho informado
+func NewRequestIDMiddleware(header string) *RequestIDMiddleware {
+	if header == "" {
+		header = requestid.DefaultHeader
+	}
+	return &RequestIDMiddleware{header: header}
+}
+
+// Middleware retorna o handler do Gin
+func (m *RequestIDMiddleware) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(m.header)
+		if !requestid.Valid(id) {
+			id = requestid.New()
+		}
+
+		c.Request.Header.Set(m.header, id)
+		c.Request = c.Request.WithContext(requestid.WithID(c.Request.Context(), id))
+		c.Set("request_id", id)
+		c.Header(m.header, id)
+
+		c.Next()
+	}
+}
@@ -1,63 +1,139 @@
 package middleware
 
 import (
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/diillson/api-gateway-go/pkg/config"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
-	"net/http"
 )
 
 // SecurityMiddleware implementa proteções de segurança
 type SecurityMiddleware struct {
+	policy atomic.Pointer[securityPolicy] // trocada nas recargas da configuração
 	logger *zap.Logger
 }
 
+// securityPolicy são os cabeçalhos de segurança montados a partir da configuração
+type securityPolicy struct {
+	enabled     bool
+	static      map[string]string // cabeçalhos com valor fixo
+	hsts        string            // enviado apenas em requisições HTTPS
+	csp         string            // modelo da CSP, com {nonce} e {host}
+	nonceHeader string
+}
+
 // NewSecurityMiddleware cria uma nova instância do middleware de segurança
-func NewSecurityMiddleware(logger *zap.Logger) *SecurityMiddleware {
-	return &SecurityMiddleware{
+func NewSecurityMiddleware(cfg config.SecurityHeadersConfig, logger *zap.Logger) *SecurityMiddleware {
+	m := &SecurityMiddleware{
 		logger: logger,
 	}
+	m.Reload(cfg)
+	return m
 }
 
-// Headers adiciona cabeçalhos de segurança
-func (m *SecurityMiddleware) Headers() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		// Proteção contra clickjacking
-		c.Header("X-Frame-Options", "DENY")
-
-		// Proteção contra MIME-sniffing
-		c.Header("X-Content-Type-Options", "nosniff")
-
-		// Proteção contra XSS
-		c.Header("X-XSS-Protection", "1; mode=block")
+// Reload aplica os cabeçalhos recarregados às próximas requisições
+func (m *SecurityMiddleware) Reload(cfg config.SecurityHeadersConfig) {
+	m.policy.Store(newSecurityPolicy(cfg))
+}
 
-		// Política de segurança de conteúdo
-		c.Header("Content-Security-Policy", "default-src 'self'")
+func newSecurityPolicy(cfg config.SecurityHeadersConfig) *securityPolicy {
+	policy := &securityPolicy{
+		enabled:     cfg.Enabled,
+		static:      make(map[string]string),
+		csp:         cfg.ContentSecurityPolicy,
+		nonceHeader: cfg.NonceHeader,
+	}
 
-		// HTTP Strict Transport Security (HSTS)
-		c.Header("Strict-Transport-Security", "max-age=31536000; includeSubDomains; preload")
+	if cfg.FrameOptions != "" {
+		policy.static["X-Frame-Options"] = strings.ToUpper(cfg.FrameOptions)
+	}
+	if cfg.ContentTypeOptions {
+		policy.static["X-Content-Type-Options"] = "nosniff"
+	}
+	if cfg.ReferrerPolicy != "" {
+		policy.static["Referrer-Policy"] = cfg.ReferrerPolicy
+	}
+	if cfg.PermissionsPolicy != "" {
+		policy.static["Permissions-Policy"] = cfg.PermissionsPolicy
+	}
 
-		// Não expõe informações de versão do servidor
-		c.Header("Server", "API Gateway")
+	if cfg.HSTS.Enabled {
+		hsts := "max-age=" + strconv.FormatInt(int64(cfg.HSTS.MaxAge.Seconds()), 10)
+		if cfg.HSTS.IncludeSubDomains {
+			hsts += "; includeSubDomains"
+		}
+		if cfg.HSTS.Preload {
+			hsts += "; preload"
+		}
+		policy.hsts = hsts
+	}
 
-		// Proteção contra redirecionamento de URL
-		c.Header("Referrer-Policy", "strict-origin-when-cross-origin")
+	return policy
+}
 
-		// Proteção contra rastreamento
-		c.Header("Permissions-Policy", "geolocation=(), microphone=(), camera=(), payment=()")
+// Headers adiciona os cabeçalhos de segurança configurados. Eles prevalecem
+// sobre os enviados pelos backends; handlers do próprio gateway, como o
+// painel administrativo, ainda podem substituí-los.
+func (m *SecurityMiddleware) Headers() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		policy := m.policy.Load()
+		if !policy.enabled {
+			c.Next()
+			return
+		}
 
-		// Proteção contra ataques de injeção de código
-		c.Header("Cross-Origin-Embedder-Policy", "require-corp")
+		header := c.Writer.Header()
+		for name, value := range policy.static {
+			header.Set(name, value)
+		}
 
-		// Proteção contra ataques de injeção de recursos
-		c.Header("Cross-Origin-Opener-Policy", "same-origin")
+		// O HSTS só tem efeito quando recebido por HTTPS
+		if policy.hsts != "" && isSecureRequest(c.Request) {
+			header.Set("Strict-Transport-Security", policy.hsts)
+		}
 
-		// Proteção contra vazamento de informações
-		c.Header("Cross-Origin-Resource-Policy", "same-origin")
+		if policy.csp != "" {
+			csp := policy.csp
+			if strings.Contains(csp, "{nonce}") {
+				nonce, err := newCSPNonce()
+				if err != nil {
+					m.logger.Error("Falha ao gerar nonce da CSP", zap.Error(err))
+					c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Erro interno do servidor"})
+					return
+				}
+				// O backend recebe o nonce para aplicá-lo aos scripts e estilos
+				// da página; um valor enviado pelo cliente é substituído
+				c.Request.Header.Set(policy.nonceHeader, nonce)
+				csp = strings.ReplaceAll(csp, "{nonce}", nonce)
+			}
+			csp = strings.ReplaceAll(csp, "{host}", c.Request.Host)
+			header.Set("Content-Security-Policy", csp)
+		}
 
 		c.Next()
 	}
 }
 
+// newCSPNonce gera um nonce aleatório de 128 bits
+func newCSPNonce() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(buf), nil
+}
+
+// isSecureRequest indica se o cliente acessou o gateway por HTTPS
+func isSecureRequest(r *http.Request) bool {
+	return r.TLS != nil || strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https")
+}
+
 // CORS configura Cross-Origin Resource Sharing
 func (m *SecurityMiddleware) CORS() gin.HandlerFunc {
 	return func(c *gin.Context) {
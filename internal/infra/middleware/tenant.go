@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"net"
+	"strings"
+
+	"github.com/diillson/api-gateway-go/pkg/config"
+	"github.com/diillson/api-gateway-go/pkg/tenant"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TenantMiddleware identifica o tenant da requisição e o propaga pelo contexto.
+// No plano de dados o tenant vem apenas do host da requisição, associado em
+// database.tenantHosts; o cabeçalho só é aceito nas APIs administrativas, onde
+// a autenticação confere o escopo do usuário.
+type TenantMiddleware struct {
+	header string
+	hosts  map[string]string // host em minúsculas, sem porta -> tenant
+}
+
+// NewTenantMiddleware cria um middleware que resolve o tenant pelo host e, nas
+// APIs administrativas, pelo cabeçalho informado
+func NewTenantMiddleware(header string, hosts []config.TenantHostConfig) *TenantMiddleware {
+	if header == "" {
+		header = "X-Tenant-ID"
+	}
+	m := &TenantMiddleware{header: header, hosts: make(map[string]string, len(hosts))}
+	for _, host := range hosts {
+		m.hosts[strings.ToLower(host.Host)] = host.Tenant
+	}
+	return m
+}
+
+// Middleware retorna o handler do Gin que resolve o tenant pelo host. O
+// cabeçalho é ignorado: clientes do plano de dados não escolhem o tenant.
+func (m *TenantMiddleware) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		host := c.Request.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if tenantID, ok := m.hosts[strings.ToLower(host)]; ok {
+			m.setTenant(c, tenantID)
+		}
+		c.Next()
+	}
+}
+
+// Header retorna o handler do Gin que aceita o tenant do cabeçalho, usado nas
+// APIs administrativas antes da autenticação. O cabeçalho prevalece sobre o
+// tenant do host.
+func (m *TenantMiddleware) Header() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if tenantID := strings.TrimSpace(c.GetHeader(m.header)); tenantID != "" {
+			m.setTenant(c, tenantID)
+		}
+		c.Next()
+	}
+}
+
+// setTenant associa o tenant ao contexto da requisição
+func (m *TenantMiddleware) setTenant(c *gin.Context, tenantID string) {
+	ctx := tenant.WithTenant(c.Request.Context(), tenantID)
+	c.Request = c.Request.WithContext(ctx)
+	c.Set("tenant", tenantID)
+
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("tenant.id", tenantID))
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/diillson/api-gateway-go/pkg/config"
+	"github.com/diillson/api-gateway-go/pkg/tenant"
+	"github.com/gin-gonic/gin"
+)
+
+// serveTenant executa uma requisição pelos handlers informados e devolve o
+// tenant visto pelo handler final
+func serveTenant(host, header string, handlers ...gin.HandlerFunc) string {
+	gin.SetMode(gin.TestMode)
+	seen := ""
+	router := gin.New()
+	router.GET("/recurso", append(handlers, func(c *gin.Context) {
+		seen = tenant.FromContext(c.Request.Context())
+		c.Status(http.StatusOK)
+	})...)
+
+	req := httptest.NewRequest(http.MethodGet, "/recurso", nil)
+	req.Host = host
+	if header != "" {
+		req.Header.Set("X-Tenant-ID", header)
+	}
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	return seen
+}
+
+func TestTenantMiddlewareDataPlane(t *testing.T) {
+	m := NewTenantMiddleware("", []config.TenantHostConfig{{Host: "Pagamentos.Exemplo.com", Tenant: "time-pagamentos"}})
+
+	tests := []struct {
+		name   string
+		host   string
+		header string
+		want   string
+	}{
+		{"host associado", "pagamentos.exemplo.com", "", "time-pagamentos"},
+		{"host com porta", "PAGAMENTOS.exemplo.com:8080", "", "time-pagamentos"},
+		{"cabeçalho ignorado", "outro.exemplo.com", "time-pagamentos", ""},
+		{"cabeçalho não troca o tenant do host", "pagamentos.exemplo.com", "outro-time", "time-pagamentos"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := serveTenant(tt.host, tt.header, m.Middleware()); got != tt.want {
+				t.Fatalf("tenant = %q, esperado %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTenantMiddlewareAdminHeader(t *testing.T) {
+	m := NewTenantMiddleware("", []config.TenantHostConfig{{Host: "pagamentos.exemplo.com", Tenant: "time-pagamentos"}})
+
+	if got := serveTenant("outro.exemplo.com", "time-b", m.Middleware(), m.Header()); got != "time-b" {
+		t.Fatalf("tenant = %q, esperado o do cabeçalho", got)
+	}
+	if got := serveTenant("pagamentos.exemplo.com", "time-b", m.Middleware(), m.Header()); got != "time-b" {
+		t.Fatalf("tenant = %q, esperado que o cabeçalho prevaleça sobre o host", got)
+	}
+	if got := serveTenant("pagamentos.exemplo.com", "", m.Middleware(), m.Header()); got != "time-pagamentos" {
+		t.Fatalf("tenant = %q, esperado o do host sem cabeçalho", got)
+	}
+}
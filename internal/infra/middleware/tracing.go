@@ -2,6 +2,9 @@ package middleware
 
 import (
 	"fmt"
+	"github.com/diillson/api-gateway-go/pkg/geoip"
+	"github.com/diillson/api-gateway-go/pkg/requestid"
+	"github.com/diillson/api-gateway-go/pkg/telemetry"
 	"github.com/gin-gonic/gin"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -50,20 +53,39 @@ func (m *TracingMiddleware) Middleware() gin.HandlerFunc {
 			ctx,
 			spanName,
 			trace.WithSpanKind(trace.SpanKindServer),
+			// Disponíveis ao amostrador, que decide na criação do span
+			trace.WithAttributes(
+				attribute.String(telemetry.PathAttribute, c.Request.URL.Path),
+				attribute.String("http.method", c.Request.Method),
+			),
 		)
 		defer span.End()
 
 		// Adicionar atributos ao span
 		span.SetAttributes(
-			attribute.String("http.method", c.Request.Method),
 			attribute.String("http.url", c.Request.URL.String()),
 			attribute.String("http.scheme", c.Request.URL.Scheme),
 			attribute.String("http.host", c.Request.Host),
 			attribute.String("http.user_agent", c.Request.UserAgent()),
-			attribute.String("http.client_ip", c.ClientIP()),
+			attribute.String("http.client_ip", clientIP(c)),
 			attribute.String("http.flavor", fmt.Sprintf("%d.%d", c.Request.ProtoMajor, c.Request.ProtoMinor)),
 		)
 
+		if id := requestid.FromContext(ctx); id != "" {
+			span.SetAttributes(attribute.String("http.request_id", id))
+		}
+
+		// Localização do cliente, com os nomes de atributo geo.* do OpenTelemetry
+		if location := geoip.FromContext(ctx); !location.IsZero() {
+			span.SetAttributes(
+				attribute.String("geo.country.iso_code", location.Country),
+				attribute.String("geo.continent.code", location.Continent),
+			)
+			if location.Subdivision != "" {
+				span.SetAttributes(attribute.String("geo.region.iso_code", location.Country+"-"+location.Subdivision))
+			}
+		}
+
 		// Adicionar atributos de headers importantes para o contexto
 		// Exemplo: headers de autorização de maneira segura
 		if c.Request.Header.Get("Authorization") != "" {
@@ -0,0 +1,508 @@
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+// Valores padrão do catálogo agregado
+const (
+	defaultTitle    = "API Gateway"
+	defaultCacheTTL = 5 * time.Minute
+	defaultTimeout  = 10 * time.Second
+	maxSpecSize     = 10 << 20
+)
+
+// httpMethods são as chaves de operações de um path item
+var httpMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// componentTypes são as seções de components renomeadas por serviço
+var componentTypes = []string{
+	"schemas", "responses", "parameters", "examples", "requestBodies",
+	"headers", "securitySchemes", "links", "callbacks",
+}
+
+// RouteLister fornece as rotas cujos documentos são agregados
+type RouteLister interface {
+	ListAllRoutes(ctx context.Context) ([]*model.Route, error)
+}
+
+// Aggregator monta um documento OpenAPI único a partir dos documentos dos
+// backends das rotas com openAPISpec, com os caminhos e servidores do gateway
+type Aggregator struct {
+	routes   RouteLister
+	client   *http.Client
+	logger   *zap.Logger
+	title    string
+	cacheTTL time.Duration
+
+	mu         sync.Mutex
+	specs      map[string]*cachedSpec      // documentos obtidos, por caminho da rota
+	operations map[string]*cachedOperation // schemas das operações validadas, por rota e operação
+}
+
+// cachedSpec é o documento de um backend obtido recentemente
+type cachedSpec struct {
+	url       string
+	data      []byte
+	err       error
+	fetchedAt time.Time
+}
+
+// Source descreve a obtenção do documento de uma rota
+type Source struct {
+	Route     string     `json:"route"`
+	Spec      string     `json:"spec"`
+	Status    string     `json:"status"`
+	Error     string     `json:"error,omitempty"`
+	FetchedAt *time.Time `json:"fetchedAt,omitempty"`
+}
+
+// NewAggregator cria o agregador de documentos OpenAPI
+func NewAggregator(routes RouteLister, logger *zap.Logger) *Aggregator {
+	return &Aggregator{
+		routes:     routes,
+		client:     &http.Client{Timeout: defaultTimeout},
+		logger:     logger,
+		title:      defaultTitle,
+		cacheTTL:   defaultCacheTTL,
+		specs:      make(map[string]*cachedSpec),
+		operations: make(map[string]*cachedOperation),
+	}
+}
+
+// SetTitle define o título do documento agregado
+func (a *Aggregator) SetTitle(title string) {
+	if title != "" {
+		a.title = title
+	}
+}
+
+// SetCacheTTL define por quanto tempo o documento de cada backend é reutilizado
+func (a *Aggregator) SetCacheTTL(ttl time.Duration) {
+	if ttl > 0 {
+		a.cacheTTL = ttl
+	}
+}
+
+// SetTimeout define o prazo para obter o documento de um backend
+func (a *Aggregator) SetTimeout(timeout time.Duration) {
+	if timeout > 0 {
+		a.client.Timeout = timeout
+	}
+}
+
+// Build monta o documento agregado com serverURL como servidor. Com refresh,
+// os documentos são obtidos novamente mesmo dentro da validade do cache.
+// Backends indisponíveis não impedem a montagem e aparecem em
+// x-gateway-sources; se houver um documento anterior, ele é reaproveitado.
+func (a *Aggregator) Build(ctx context.Context, serverURL string, refresh bool) (map[string]interface{}, error) {
+	all, err := a.routes.ListAllRoutes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar as rotas: %w", err)
+	}
+
+	var routes []*model.Route
+	for _, route := range all {
+		if route.IsActive && route.OpenAPISpec != "" {
+			routes = append(routes, route)
+		}
+	}
+	a.prune(routes)
+
+	specs := make([]*cachedSpec, len(routes))
+	var wg sync.WaitGroup
+	for i, route := range routes {
+		wg.Add(1)
+		go func(i int, route *model.Route) {
+			defer wg.Done()
+			specs[i] = a.spec(ctx, route, refresh)
+		}(i, route)
+	}
+	wg.Wait()
+
+	merged := &mergedDocument{
+		paths:      make(map[string]interface{}),
+		components: make(map[string]map[string]interface{}),
+		tags:       []interface{}{},
+		namespaces: make(map[string]bool),
+	}
+	sources := make([]Source, 0, len(routes))
+	for i, route := range routes {
+		source := Source{Route: route.Path, Spec: specs[i].url, Status: "ok"}
+		if !specs[i].fetchedAt.IsZero() {
+			source.FetchedAt = &specs[i].fetchedAt
+		}
+		if specs[i].err != nil {
+			source.Status = "error"
+			source.Error = specs[i].err.Error()
+		}
+		if specs[i].data != nil {
+			if err := merged.add(route, specs[i].data); err != nil {
+				source.Status = "error"
+				source.Error = err.Error()
+			}
+		}
+		sources = append(sources, source)
+	}
+
+	components := make(map[string]interface{}, len(merged.components))
+	for kind, items := range merged.components {
+		components[kind] = items
+	}
+	return map[string]interface{}{
+		"openapi":           "3.0.3",
+		"info":              map[string]interface{}{"title": a.title, "version": "1.0.0"},
+		"servers":           []interface{}{map[string]interface{}{"url": serverURL}},
+		"paths":             merged.paths,
+		"components":        components,
+		"tags":              merged.tags,
+		"x-gateway-sources": sources,
+	}, nil
+}
+
+// prune descarta do cache os documentos de rotas removidas ou sem openAPISpec
+func (a *Aggregator) prune(routes []*model.Route) {
+	current := make(map[string]bool, len(routes))
+	for _, route := range routes {
+		current[route.Path] = true
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for path := range a.specs {
+		if !current[path] {
+			delete(a.specs, path)
+		}
+	}
+	for key := range a.operations {
+		if path, _, _ := strings.Cut(key, " "); !current[path] {
+			delete(a.operations, key)
+		}
+	}
+}
+
+// spec retorna o documento da rota, do cache quando ainda válido
+func (a *Aggregator) spec(ctx context.Context, route *model.Route, refresh bool) *cachedSpec {
+	specURL, err := route.OpenAPISpecURL()
+	if err != nil {
+		return &cachedSpec{url: route.OpenAPISpec, err: err}
+	}
+
+	a.mu.Lock()
+	cached := a.specs[route.Path]
+	a.mu.Unlock()
+	if cached != nil && cached.url == specURL && !refresh && time.Since(cached.fetchedAt) < a.cacheTTL {
+		return cached
+	}
+
+	data, err := a.fetch(ctx, route, specURL)
+	if err != nil {
+		a.logger.Warn("Falha ao obter o documento OpenAPI do backend",
+			zap.String("route", route.Path),
+			zap.String("spec", specURL),
+			zap.Error(err))
+		stale := &cachedSpec{url: specURL, err: err}
+		if cached != nil && cached.url == specURL {
+			stale.data = cached.data
+			stale.fetchedAt = cached.fetchedAt
+		}
+		return stale
+	}
+
+	fetched := &cachedSpec{url: specURL, data: data, fetchedAt: time.Now()}
+	a.mu.Lock()
+	a.specs[route.Path] = fetched
+	a.mu.Unlock()
+	return fetched
+}
+
+// fetch obtém o documento no backend, com as credenciais da rota
+func (a *Aggregator) fetch(ctx context.Context, route *model.Route, specURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, specURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json, application/yaml;q=0.9")
+	if route.UpstreamAuth != nil && strings.HasPrefix(route.OpenAPISpec, "/") {
+		req.SetBasicAuth(route.UpstreamAuth.Username, route.UpstreamAuth.Password)
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status inesperado: %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSpecSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxSpecSize {
+		return nil, fmt.Errorf("documento maior que %d bytes", maxSpecSize)
+	}
+	if _, err := decode(data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// decode converte um documento YAML ou JSON em mapa, validando a versão
+func decode(data []byte) (map[string]interface{}, error) {
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("documento OpenAPI inválido: %w", err)
+	}
+	converted, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("documento OpenAPI inválido: %w", err)
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(converted, &doc); err != nil {
+		return nil, fmt.Errorf("documento OpenAPI inválido: %w", err)
+	}
+	if version, _ := doc["openapi"].(string); !strings.HasPrefix(version, "3.") {
+		return nil, fmt.Errorf("versão do OpenAPI não suportada: %q (use 3.x)", version)
+	}
+	return doc, nil
+}
+
+// mergedDocument acumula os caminhos e componentes dos documentos agregados
+type mergedDocument struct {
+	paths      map[string]interface{}
+	components map[string]map[string]interface{}
+	tags       []interface{}
+	namespaces map[string]bool
+}
+
+// add incorpora o documento de um backend. Os componentes recebem o prefixo
+// do serviço para não colidirem, e os caminhos são convertidos nos do gateway.
+func (m *mergedDocument) add(route *model.Route, data []byte) error {
+	doc, err := decode(data)
+	if err != nil {
+		return err
+	}
+
+	info, _ := doc["info"].(map[string]interface{})
+	title, _ := info["title"].(string)
+	if title == "" {
+		title = route.Path
+	}
+	namespace := m.namespace(title)
+
+	// Renomear os componentes e as referências a eles
+	refs := make(map[string]string)
+	schemes := make(map[string]string)
+	components, _ := doc["components"].(map[string]interface{})
+	for _, kind := range componentTypes {
+		items, _ := components[kind].(map[string]interface{})
+		for name := range items {
+			renamed := namespace + "_" + name
+			refs["#/components/"+kind+"/"+name] = "#/components/" + kind + "/" + renamed
+			if kind == "securitySchemes" {
+				schemes[name] = renamed
+			}
+		}
+	}
+	rewriteRefs(doc, refs)
+
+	for _, kind := range componentTypes {
+		items, _ := components[kind].(map[string]interface{})
+		if len(items) == 0 {
+			continue
+		}
+		if m.components[kind] == nil {
+			m.components[kind] = make(map[string]interface{})
+		}
+		for name, item := range items {
+			m.components[kind][namespace+"_"+name] = item
+		}
+	}
+
+	globalSecurity, hasGlobalSecurity := doc["security"].([]interface{})
+	basePath := serverBasePath(doc)
+	paths, _ := doc["paths"].(map[string]interface{})
+	names := make([]string, 0, len(paths))
+	for path := range paths {
+		names = append(names, path)
+	}
+	sort.Strings(names)
+
+	added := 0
+	for _, path := range names {
+		item, _ := paths[path].(map[string]interface{})
+		gatewayPath, ok := route.GatewayPath(basePath + path)
+		if !ok || item == nil {
+			continue
+		}
+		delete(item, "servers")
+
+		target, _ := m.paths[gatewayPath].(map[string]interface{})
+		if target == nil {
+			target = make(map[string]interface{})
+		}
+		for _, method := range httpMethods {
+			op, _ := item[method].(map[string]interface{})
+			delete(item, method)
+			if op == nil || !route.IsMethodAllowed(strings.ToUpper(method)) {
+				continue
+			}
+			// A primeira rota que publica a operação prevalece
+			if _, exists := target[method]; exists {
+				continue
+			}
+
+			delete(op, "servers")
+			if _, ok := op["security"]; !ok && hasGlobalSecurity {
+				op["security"] = globalSecurity
+			}
+			if security, ok := op["security"].([]interface{}); ok {
+				op["security"] = renameSecurity(security, schemes)
+			}
+			if tags, _ := op["tags"].([]interface{}); len(tags) == 0 {
+				op["tags"] = []interface{}{title}
+			}
+			// Rotas descontinuadas publicam as operações como obsoletas
+			if route.Deprecation != nil {
+				op["deprecated"] = true
+			}
+			target[method] = op
+			added++
+		}
+		for key, value := range item {
+			if _, exists := target[key]; !exists {
+				target[key] = value
+			}
+		}
+		if hasOperations(target) {
+			m.paths[gatewayPath] = target
+		}
+	}
+
+	if added > 0 {
+		tag := map[string]interface{}{"name": title}
+		if description, _ := info["description"].(string); description != "" {
+			tag["description"] = description
+		}
+		m.tags = append(m.tags, tag)
+	}
+	return nil
+}
+
+// invalidNamespaceChars são os caracteres removidos dos prefixos de componentes
+var invalidNamespaceChars = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// namespace retorna um prefixo único para os componentes do serviço
+func (m *mergedDocument) namespace(title string) string {
+	base := strings.Trim(invalidNamespaceChars.ReplaceAllString(title, "_"), "_")
+	if base == "" {
+		base = "service"
+	}
+	namespace := base
+	for i := 2; m.namespaces[namespace]; i++ {
+		namespace = fmt.Sprintf("%s_%d", base, i)
+	}
+	m.namespaces[namespace] = true
+	return namespace
+}
+
+// hasOperations indica se o path item tem ao menos uma operação
+func hasOperations(item map[string]interface{}) bool {
+	for _, method := range httpMethods {
+		if _, ok := item[method]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// rewriteRefs substitui as referências $ref internas renomeadas
+func rewriteRefs(node interface{}, refs map[string]string) {
+	switch value := node.(type) {
+	case map[string]interface{}:
+		for key, child := range value {
+			if ref, ok := child.(string); ok && key == "$ref" {
+				value[key] = renameRef(ref, refs)
+				continue
+			}
+			rewriteRefs(child, refs)
+		}
+	case []interface{}:
+		for _, child := range value {
+			rewriteRefs(child, refs)
+		}
+	}
+}
+
+// renameRef aplica a renomeação à referência ou ao seu prefixo
+// (ex.: #/components/schemas/User/properties/id)
+func renameRef(ref string, refs map[string]string) string {
+	if renamed, ok := refs[ref]; ok {
+		return renamed
+	}
+	for old, renamed := range refs {
+		if strings.HasPrefix(ref, old+"/") {
+			return renamed + strings.TrimPrefix(ref, old)
+		}
+	}
+	return ref
+}
+
+// renameSecurity aplica os nomes prefixados dos esquemas aos requisitos
+func renameSecurity(security []interface{}, schemes map[string]string) []interface{} {
+	renamed := make([]interface{}, 0, len(security))
+	for _, entry := range security {
+		requirement, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		converted := make(map[string]interface{}, len(requirement))
+		for name, scopes := range requirement {
+			if newName, ok := schemes[name]; ok {
+				name = newName
+			}
+			converted[name] = scopes
+		}
+		renamed = append(renamed, converted)
+	}
+	return renamed
+}
+
+// serverBasePath retorna o caminho base do primeiro servidor do documento
+func serverBasePath(doc map[string]interface{}) string {
+	servers, _ := doc["servers"].([]interface{})
+	if len(servers) == 0 {
+		return ""
+	}
+	server, _ := servers[0].(map[string]interface{})
+	raw, _ := server["url"].(string)
+	variables, _ := server["variables"].(map[string]interface{})
+	raw = serverVariable.ReplaceAllStringFunc(raw, func(match string) string {
+		variable, _ := variables[strings.Trim(match, "{}")].(map[string]interface{})
+		value, _ := variable["default"].(string)
+		return value
+	})
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(u.Path, "/")
+}
@@ -0,0 +1,360 @@
+// Package openapi converte documentos OpenAPI 3 em rotas do gateway e agrega
+// os documentos dos backends em um catálogo único.
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/internal/domain/repository"
+	"gopkg.in/yaml.v3"
+)
+
+// Document contém as partes do OpenAPI 3 usadas na geração das rotas
+type Document struct {
+	OpenAPI    string                `json:"openapi"`
+	Info       Info                  `json:"info"`
+	Servers    []Server              `json:"servers"`
+	Paths      map[string]PathItem   `json:"paths"`
+	Security   []map[string][]string `json:"security"`
+	Components Components            `json:"components"`
+}
+
+// Info identifica a API
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// Server é um endereço do serviço, com variáveis {nome}
+type Server struct {
+	URL       string                    `json:"url"`
+	Variables map[string]ServerVariable `json:"variables"`
+}
+
+// ServerVariable é uma variável da URL do servidor
+type ServerVariable struct {
+	Default string `json:"default"`
+}
+
+// PathItem contém as operações de um caminho
+type PathItem struct {
+	Summary string     `json:"summary"`
+	Get     *Operation `json:"get"`
+	Put     *Operation `json:"put"`
+	Post    *Operation `json:"post"`
+	Delete  *Operation `json:"delete"`
+	Options *Operation `json:"options"`
+	Head    *Operation `json:"head"`
+	Patch   *Operation `json:"patch"`
+	Trace   *Operation `json:"trace"`
+	Servers []Server   `json:"servers"`
+}
+
+// Operation é uma operação de um caminho. Security nil herda o requisito do
+// documento; uma lista vazia torna a operação pública.
+type Operation struct {
+	OperationID string                 `json:"operationId"`
+	Summary     string                 `json:"summary"`
+	Security    *[]map[string][]string `json:"security"`
+	Servers     []Server               `json:"servers"`
+}
+
+// Components contém os esquemas de segurança do documento
+type Components struct {
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes"`
+}
+
+// SecurityScheme é um esquema de autenticação do OpenAPI
+type SecurityScheme struct {
+	Type   string `json:"type"`   // apiKey, http, oauth2 ou openIdConnect
+	Scheme string `json:"scheme"` // bearer ou basic, para o tipo http
+}
+
+// Options controla a geração das rotas
+type Options struct {
+	Prefix   string // prefixo acrescentado aos caminhos no gateway (ex.: /api/orders)
+	Upstream string // URL do backend; vazio usa o primeiro servidor do documento
+	Inactive bool   // cria as rotas desativadas, para revisão antes da publicação
+}
+
+// Parse decodifica um documento OpenAPI 3 em YAML ou JSON
+func Parse(data []byte) (*Document, error) {
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("documento OpenAPI inválido: %w", err)
+	}
+	converted, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("documento OpenAPI inválido: %w", err)
+	}
+
+	var doc Document
+	if err := json.Unmarshal(converted, &doc); err != nil {
+		return nil, fmt.Errorf("documento OpenAPI inválido: %w", err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		return nil, fmt.Errorf("versão do OpenAPI não suportada: %q (use 3.x)", doc.OpenAPI)
+	}
+	if len(doc.Paths) == 0 {
+		return nil, errors.New("documento OpenAPI sem paths")
+	}
+	return &doc, nil
+}
+
+// Routes gera uma rota por caminho do documento, com os métodos das suas
+// operações, o backend do servidor e a autenticação exigida pelas operações
+func (d *Document) Routes(opts Options) ([]*model.Route, []string, error) {
+	var warnings []string
+	paths := make([]string, 0, len(d.Paths))
+	for path := range d.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	routes := make([]*model.Route, 0, len(paths))
+	for _, path := range paths {
+		item := d.Paths[path]
+		operations := item.operations()
+		if len(operations) == 0 {
+			continue
+		}
+
+		methods := make([]string, 0, len(operations))
+		for method := range operations {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+
+		servers := d.Servers
+		if len(item.Servers) > 0 {
+			servers = item.Servers
+		}
+		if op := operations[methods[0]]; len(op.Servers) > 0 {
+			servers = op.Servers
+		}
+		serviceURL, basePath, err := upstream(opts.Upstream, servers)
+		if err != nil {
+			return nil, warnings, fmt.Errorf("%s: %w", path, err)
+		}
+
+		routePath, template := convertPath(opts.Prefix, basePath, path)
+		route := &model.Route{
+			Path:        routePath,
+			ServiceURL:  serviceURL,
+			Methods:     methods,
+			Description: d.describe(item, operations, methods),
+			IsActive:    !opts.Inactive,
+			Labels:      map[string]string{"openapi": d.Info.Title},
+		}
+		if template != "" {
+			route.PathRewrite = &model.PathRewrite{Template: template}
+		}
+
+		auth, warning := d.routeAuth(path, operations, methods)
+		route.Auth = auth
+		if warning != "" {
+			warnings = append(warnings, warning)
+		}
+
+		if err := route.Validate(); err != nil {
+			return nil, warnings, fmt.Errorf("%s: %w", path, err)
+		}
+		routes = append(routes, route)
+	}
+	return routes, warnings, nil
+}
+
+// operations retorna as operações do caminho indexadas pelo método HTTP
+func (p PathItem) operations() map[string]*Operation {
+	all := map[string]*Operation{
+		"GET": p.Get, "PUT": p.Put, "POST": p.Post, "DELETE": p.Delete,
+		"OPTIONS": p.Options, "HEAD": p.Head, "PATCH": p.Patch, "TRACE": p.Trace,
+	}
+	for method, op := range all {
+		if op == nil {
+			delete(all, method)
+		}
+	}
+	return all
+}
+
+// describe usa o resumo do caminho ou os das operações como descrição
+func (d *Document) describe(item PathItem, operations map[string]*Operation, methods []string) string {
+	if item.Summary != "" {
+		return item.Summary
+	}
+	var parts []string
+	for _, method := range methods {
+		op := operations[method]
+		switch {
+		case op.Summary != "":
+			parts = append(parts, method+": "+op.Summary)
+		case op.OperationID != "":
+			parts = append(parts, method+": "+op.OperationID)
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+// routeAuth traduz os requisitos de segurança das operações para a política
+// da rota. Como a política vale para todos os métodos, basta uma operação
+// protegida para a rota exigir autenticação; a diferença é reportada.
+func (d *Document) routeAuth(path string, operations map[string]*Operation, methods []string) (*model.RouteAuth, string) {
+	var auth *model.RouteAuth
+	var public []string
+	for _, method := range methods {
+		requirements := d.Security
+		if op := operations[method]; op.Security != nil {
+			requirements = *op.Security
+		}
+		policy := d.policyFor(requirements)
+		if policy == nil {
+			public = append(public, method)
+			continue
+		}
+		if auth == nil {
+			auth = policy
+		}
+	}
+
+	if auth != nil && len(public) > 0 {
+		return auth, fmt.Sprintf("%s: métodos sem autenticação no documento (%s) passam a exigir %s",
+			path, strings.Join(public, ", "), auth.Type)
+	}
+	return auth, ""
+}
+
+// policyFor escolhe a política do primeiro requisito que o gateway suporta;
+// nil indica operação pública
+func (d *Document) policyFor(requirements []map[string][]string) *model.RouteAuth {
+	for _, requirement := range requirements {
+		if len(requirement) == 0 {
+			// Requisito vazio ({}) torna a autenticação opcional
+			return nil
+		}
+		names := make([]string, 0, len(requirement))
+		for name := range requirement {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			scheme, ok := d.Components.SecuritySchemes[name]
+			if !ok {
+				continue
+			}
+			switch {
+			case scheme.Type == "apiKey":
+				return &model.RouteAuth{Type: model.RouteAuthAPIKey}
+			case scheme.Type == "http" && strings.EqualFold(scheme.Scheme, "bearer"),
+				scheme.Type == "oauth2", scheme.Type == "openIdConnect":
+				return &model.RouteAuth{Type: model.RouteAuthJWT}
+			}
+		}
+	}
+	if len(requirements) > 0 {
+		// Esquema não suportado (ex.: basic): exigir JWT em vez de expor a rota
+		return &model.RouteAuth{Type: model.RouteAuthJWT}
+	}
+	return nil
+}
+
+// serverVariable encontra as variáveis {nome} da URL do servidor
+var serverVariable = regexp.MustCompile(`\{([^}]+)\}`)
+
+// upstream resolve a URL do backend e o caminho base do servidor
+func upstream(override string, servers []Server) (string, string, error) {
+	raw := override
+	if raw == "" {
+		if len(servers) == 0 {
+			return "", "", errors.New("documento sem servers; informe a URL do backend")
+		}
+		server := servers[0]
+		raw = serverVariable.ReplaceAllStringFunc(server.URL, func(match string) string {
+			return server.Variables[strings.Trim(match, "{}")].Default
+		})
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "", "", fmt.Errorf("URL do backend inválida: %q", raw)
+	}
+	return u.Scheme + "://" + u.Host, strings.TrimSuffix(u.Path, "/"), nil
+}
+
+// invalidParamChars são os caracteres não aceitos nos nomes de parâmetros
+var invalidParamChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// convertPath converte o caminho do OpenAPI (/users/{id}) no caminho da rota
+// (/users/:id). Quando o caminho no gateway difere do enviado ao backend, por
+// prefixo ou caminho base do servidor, retorna o template de reescrita.
+func convertPath(prefix, basePath, path string) (string, string) {
+	segments := strings.Split(path, "/")
+	templateSegments := make([]string, len(segments))
+	for i, segment := range segments {
+		templateSegments[i] = segment
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			name := invalidParamChars.ReplaceAllString(strings.Trim(segment, "{}"), "_")
+			segments[i] = ":" + name
+			templateSegments[i] = "{" + name + "}"
+		}
+	}
+
+	prefix = strings.TrimSuffix(prefix, "/")
+	routePath := prefix + strings.Join(segments, "/")
+	if prefix == basePath {
+		// Sem reescrita, o backend recebe o caminho requisitado ao gateway
+		return routePath, ""
+	}
+	return routePath, basePath + strings.Join(templateSegments, "/")
+}
+
+// RouteStore é onde as rotas importadas são gravadas
+type RouteStore interface {
+	GetRoute(ctx context.Context, path string) (*model.Route, error)
+	AddRoute(ctx context.Context, route *model.Route) error
+	UpdateRoute(ctx context.Context, route *model.Route) error
+}
+
+// Result resume a importação
+type Result struct {
+	Created []string
+	Updated []string
+}
+
+// Import cria as rotas novas e atualiza as já cadastradas. Nas atualizações,
+// a configuração existente fora do que o documento define é preservada.
+func Import(ctx context.Context, store RouteStore, routes []*model.Route) (Result, error) {
+	var result Result
+	for _, route := range routes {
+		existing, err := store.GetRoute(ctx, route.Path)
+		switch {
+		case errors.Is(err, repository.ErrRouteNotFound):
+			if err := store.AddRoute(ctx, route); err != nil {
+				return result, fmt.Errorf("%s: %w", route.Path, err)
+			}
+			result.Created = append(result.Created, route.Path)
+		case err != nil:
+			return result, fmt.Errorf("%s: %w", route.Path, err)
+		default:
+			existing.ServiceURL = route.ServiceURL
+			existing.Targets = nil
+			existing.Methods = route.Methods
+			existing.Description = route.Description
+			existing.PathRewrite = route.PathRewrite
+			existing.Auth = route.Auth
+			if err := store.UpdateRoute(ctx, existing); err != nil {
+				return result, fmt.Errorf("%s: %w", route.Path, err)
+			}
+			result.Updated = append(result.Updated, route.Path)
+		}
+	}
+	return result, nil
+}
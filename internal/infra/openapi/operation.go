@@ -0,0 +1,215 @@
+package openapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/pkg/jsonschema"
+)
+
+// operationRetry é o intervalo entre tentativas de obter os schemas de uma
+// operação cujo documento estava indisponível
+const operationRetry = 30 * time.Second
+
+// ErrOperationNotFound indica uma operação ausente do documento do backend
+var ErrOperationNotFound = errors.New("operação não encontrada no documento OpenAPI")
+
+// RequestSchemas são os schemas que validam as requisições de uma operação
+type RequestSchemas struct {
+	Body         *jsonschema.Schema // Corpo JSON; nil quando a operação não declara
+	BodyRequired bool               // requestBody.required da operação
+	Query        *jsonschema.Schema // Parâmetros de query; nil quando a operação não declara
+}
+
+// cachedOperation são os schemas compilados de uma operação
+type cachedOperation struct {
+	url       string
+	fetchedAt time.Time
+	checkedAt time.Time
+	schemas   *RequestSchemas
+	err       error
+}
+
+// RequestSchemas retorna os schemas da operação no documento OpenAPI da rota.
+// A operação é o operationId ou "MÉTODO /caminho", com o caminho do backend
+// (ex.: "POST /users/{id}"). Os schemas compilados são reutilizados enquanto o
+// documento estiver em cache.
+func (a *Aggregator) RequestSchemas(ctx context.Context, route *model.Route, operation string) (*RequestSchemas, error) {
+	key := route.Path + " " + operation
+
+	a.mu.Lock()
+	cached := a.operations[key]
+	a.mu.Unlock()
+	if cached != nil {
+		validity := a.cacheTTL
+		if cached.err != nil {
+			validity = operationRetry
+		}
+		if time.Since(cached.checkedAt) < validity {
+			return cached.schemas, cached.err
+		}
+	}
+
+	spec := a.spec(ctx, route, false)
+	entry := &cachedOperation{url: spec.url, fetchedAt: spec.fetchedAt, checkedAt: time.Now()}
+	switch {
+	case spec.data == nil:
+		entry.err = spec.err
+	case cached != nil && cached.err == nil && cached.url == spec.url && cached.fetchedAt.Equal(spec.fetchedAt):
+		entry.schemas = cached.schemas
+	default:
+		entry.schemas, entry.err = compileOperation(spec.data, operation)
+	}
+
+	a.mu.Lock()
+	a.operations[key] = entry
+	a.mu.Unlock()
+	return entry.schemas, entry.err
+}
+
+// compileOperation localiza a operação no documento e compila os schemas do
+// corpo JSON e dos parâmetros de query
+func compileOperation(data []byte, operation string) (*RequestSchemas, error) {
+	doc, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+	op, pathItem := findOperation(doc, operation)
+	if op == nil {
+		return nil, fmt.Errorf("%w: %s", ErrOperationNotFound, operation)
+	}
+
+	schemas := &RequestSchemas{}
+	if body := resolveRef(doc, op["requestBody"]); body != nil {
+		schemas.BodyRequired, _ = body["required"].(bool)
+		content, _ := body["content"].(map[string]interface{})
+		if schema, ok := jsonContent(content); ok {
+			if schemas.Body, err = jsonschema.CompileValue(doc, schema); err != nil {
+				return nil, fmt.Errorf("schema do corpo de %s: %w", operation, err)
+			}
+		}
+	}
+
+	// Os parâmetros da operação substituem os do caminho com o mesmo nome
+	params := make(map[string]map[string]interface{})
+	var names []string
+	for _, list := range [][]interface{}{sliceOf(pathItem["parameters"]), sliceOf(op["parameters"])} {
+		for _, raw := range list {
+			param := resolveRef(doc, raw)
+			if in, _ := param["in"].(string); in != "query" {
+				continue
+			}
+			name, _ := param["name"].(string)
+			if name == "" {
+				continue
+			}
+			if _, ok := params[name]; !ok {
+				names = append(names, name)
+			}
+			params[name] = param
+		}
+	}
+	if len(names) > 0 {
+		properties := make(map[string]interface{}, len(names))
+		var required []interface{}
+		for _, name := range names {
+			schema, ok := params[name]["schema"]
+			if !ok {
+				schema = map[string]interface{}{}
+			}
+			properties[name] = schema
+			if r, _ := params[name]["required"].(bool); r {
+				required = append(required, name)
+			}
+		}
+		query := map[string]interface{}{"type": "object", "properties": properties}
+		if len(required) > 0 {
+			query["required"] = required
+		}
+		if schemas.Query, err = jsonschema.CompileValue(doc, query); err != nil {
+			return nil, fmt.Errorf("schema da query de %s: %w", operation, err)
+		}
+	}
+	return schemas, nil
+}
+
+// findOperation procura a operação pelo operationId ou por "MÉTODO /caminho"
+func findOperation(doc map[string]interface{}, operation string) (map[string]interface{}, map[string]interface{}) {
+	method, path, byRoute := strings.Cut(strings.TrimSpace(operation), " ")
+	path = strings.TrimSpace(path)
+
+	paths, _ := doc["paths"].(map[string]interface{})
+	for itemPath, raw := range paths {
+		item, _ := raw.(map[string]interface{})
+		for _, m := range httpMethods {
+			op, ok := item[m].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if byRoute && strings.EqualFold(method, m) && path == itemPath {
+				return op, item
+			}
+			if id, _ := op["operationId"].(string); id != "" && id == operation {
+				return op, item
+			}
+		}
+	}
+	return nil, nil
+}
+
+// jsonContent retorna o schema do tipo de conteúdo JSON do corpo, dando
+// preferência a application/json sobre os tipos +json
+func jsonContent(content map[string]interface{}) (interface{}, bool) {
+	mediaTypes := make([]string, 0, len(content))
+	for mediaType := range content {
+		mediaTypes = append(mediaTypes, mediaType)
+	}
+	sort.Strings(mediaTypes)
+	for _, preferred := range []bool{true, false} {
+		for _, mediaType := range mediaTypes {
+			lower := strings.ToLower(mediaType)
+			if preferred != (lower == "application/json") {
+				continue
+			}
+			if lower != "application/json" && !strings.HasSuffix(lower, "+json") {
+				continue
+			}
+			media, _ := content[mediaType].(map[string]interface{})
+			if schema, ok := media["schema"]; ok {
+				return schema, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// resolveRef segue as referências locais de parâmetros e corpos
+// (#/components/...) até o objeto referenciado
+func resolveRef(doc map[string]interface{}, value interface{}) map[string]interface{} {
+	object, _ := value.(map[string]interface{})
+	for depth := 0; object != nil && depth < 10; depth++ {
+		ref, ok := object["$ref"].(string)
+		if !ok {
+			return object
+		}
+		var target interface{} = doc
+		for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+			part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+			parent, _ := target.(map[string]interface{})
+			target = parent[part]
+		}
+		object, _ = target.(map[string]interface{})
+	}
+	return object
+}
+
+// sliceOf converte o valor em lista, vazia quando não é uma lista
+func sliceOf(value interface{}) []interface{} {
+	list, _ := value.([]interface{})
+	return list
+}
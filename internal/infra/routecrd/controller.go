@@ -0,0 +1,515 @@
+// Package routecrd sincroniza as rotas declaradas como recursos do Kubernetes
+// — GatewayRoute e, opcionalmente, HTTPRoute da Gateway API — com o serviço de
+// rotas, permitindo gerenciá-las com kubectl apply.
+package routecrd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/internal/infra/routefile"
+	"github.com/diillson/api-gateway-go/pkg/config"
+	"github.com/diillson/api-gateway-go/pkg/kubeapi"
+	"go.uber.org/zap"
+)
+
+// SourceLabel identifica, nas rotas criadas pelo controlador, o recurso que as
+// declarou (ex.: GatewayRoute/shop/orders). Rotas sem o rótulo, cadastradas
+// pela API administrativa ou pelo arquivo de rotas, nunca são alteradas.
+const SourceLabel = "apigateway.diillson.io/source"
+
+const (
+	// watchTimeout é a duração de cada watch; ao fim, os recursos são listados de novo
+	watchTimeout = 5 * time.Minute
+
+	// requestTimeout limita as listagens e as atualizações de status
+	requestTimeout = 30 * time.Second
+
+	// Espera entre tentativas após falhas na API, dobrada a cada falha
+	initialBackoff = time.Second
+	maxBackoff     = 30 * time.Second
+)
+
+// RouteStore é onde as rotas dos recursos são aplicadas
+type RouteStore interface {
+	ListAllRoutes(ctx context.Context) ([]*model.Route, error)
+	AddRoute(ctx context.Context, route *model.Route) error
+	UpdateRoute(ctx context.Context, route *model.Route) error
+	DeleteRoute(ctx context.Context, path string) error
+}
+
+// Controller acompanha os recursos de rota do cluster e reconcilia as rotas
+// cadastradas a cada alteração e periodicamente, desfazendo alterações feitas
+// por fora nas rotas que ele gerencia.
+type Controller struct {
+	client      *kubeapi.Client
+	store       RouteStore
+	namespace   string
+	gatewayName string
+	resync      time.Duration
+	kinds       []*kind
+	logger      *zap.Logger
+
+	mu        sync.Mutex
+	resources map[string]*resource // Kind/namespace/nome -> recurso
+	synced    map[*kind]bool       // tipos já listados ao menos uma vez
+	statuses  map[string]string    // último status publicado de cada recurso
+
+	changed chan struct{}
+}
+
+// resource é um recurso de rota acompanhado e as rotas que ele declara
+type resource struct {
+	kind       *kind
+	key        string
+	namespace  string
+	name       string
+	generation int64
+	created    string // metadata.creationTimestamp; o mais antigo vence conflitos
+	routes     []*model.Route
+	err        error // declaração inválida
+}
+
+// result é o resultado da reconciliação de um recurso
+type result struct {
+	reason  string
+	message string
+}
+
+// Motivos publicados na condição Accepted dos recursos
+const (
+	reasonAccepted = "Accepted"
+	reasonInvalid  = "Invalid"
+	reasonConflict = "Conflict"
+	reasonFailed   = "ApplyFailed"
+)
+
+// NewController cria o controlador com as credenciais do pod
+func NewController(cfg config.RoutesKubernetesConfig, store RouteStore, logger *zap.Logger) (*Controller, error) {
+	client, err := kubeapi.NewInClusterClient(cfg.APIServer)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Controller{
+		client:      client,
+		store:       store,
+		namespace:   cfg.Namespace,
+		gatewayName: cfg.GatewayName,
+		resync:      cfg.ResyncInterval,
+		kinds:       []*kind{gatewayRouteKind},
+		logger:      logger,
+		resources:   make(map[string]*resource),
+		synced:      make(map[*kind]bool),
+		statuses:    make(map[string]string),
+		changed:     make(chan struct{}, 1),
+	}
+	if cfg.HTTPRoute {
+		c.kinds = append(c.kinds, httpRouteKind)
+	}
+	return c, nil
+}
+
+// Run acompanha os recursos e reconcilia as rotas até o contexto ser
+// cancelado. A primeira reconciliação espera a listagem de todos os tipos,
+// para não remover rotas de recursos ainda não lidos.
+func (c *Controller) Run(ctx context.Context) {
+	for _, k := range c.kinds {
+		go c.watchKind(ctx, k)
+	}
+
+	c.logger.Info("Sincronizando rotas declaradas no Kubernetes",
+		zap.String("namespace", c.namespace),
+		zap.Int("kinds", len(c.kinds)))
+
+	ticker := time.NewTicker(c.resync)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.changed:
+		case <-ticker.C:
+		}
+		if c.ready() {
+			c.reconcile(ctx)
+		}
+	}
+}
+
+// ready indica se todos os tipos de recurso já foram listados
+func (c *Controller) ready() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.synced) == len(c.kinds)
+}
+
+// notify agenda uma reconciliação; alterações próximas são agrupadas
+func (c *Controller) notify() {
+	select {
+	case c.changed <- struct{}{}:
+	default:
+	}
+}
+
+// watchKind lista e acompanha os recursos do tipo até o contexto ser cancelado
+func (c *Controller) watchKind(ctx context.Context, k *kind) {
+	backoff := initialBackoff
+	for {
+		started := time.Now()
+		err := c.sync(ctx, k)
+		if ctx.Err() != nil {
+			return
+		}
+		if errors.Is(err, kubeapi.ErrNotFound) {
+			// CRD não instalada: o tipo é tratado como vazio até ser instalada
+			c.replace(k, nil)
+			c.logger.Warn("Tipo de recurso de rota não instalado no cluster",
+				zap.String("kind", k.name),
+				zap.String("apiVersion", k.group+"/"+k.version))
+			backoff = maxBackoff
+		} else if err == nil {
+			// Watch encerrado pela API: listar de novo, sem repetir em laço
+			// quando a API encerra o watch logo após aberto
+			if time.Since(started) >= maxBackoff {
+				backoff = initialBackoff
+				continue
+			}
+		} else {
+			c.logger.Warn("Falha ao acompanhar recursos de rota do Kubernetes",
+				zap.String("kind", k.name),
+				zap.Duration("retryIn", backoff),
+				zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// sync lista os recursos do tipo e acompanha suas alterações até o watch
+// terminar. Retorna nil quando o watch termina normalmente.
+func (c *Controller) sync(ctx context.Context, k *kind) error {
+	listCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+	resp, err := c.client.Get(listCtx, k.path(c.namespace), nil)
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	var list struct {
+		Metadata struct {
+			ResourceVersion string `json:"resourceVersion"`
+		} `json:"metadata"`
+		Items []object `json:"items"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&list)
+	resp.Body.Close()
+	cancel()
+	if err != nil {
+		return fmt.Errorf("resposta inválida da API do Kubernetes: %w", err)
+	}
+
+	resources := make([]*resource, 0, len(list.Items))
+	for _, item := range list.Items {
+		resources = append(resources, c.decode(k, item))
+	}
+	c.replace(k, resources)
+
+	return c.watchChanges(ctx, k, list.Metadata.ResourceVersion)
+}
+
+// watchChanges aplica as alterações dos recursos a partir da versão listada
+func (c *Controller) watchChanges(ctx context.Context, k *kind, resourceVersion string) error {
+	query := url.Values{
+		"watch":           {"true"},
+		"resourceVersion": {resourceVersion},
+		"timeoutSeconds":  {strconv.Itoa(int(watchTimeout.Seconds()))},
+	}
+
+	// Margem para a API encerrar o watch antes do prazo local
+	watchCtx, cancel := context.WithTimeout(ctx, watchTimeout+30*time.Second)
+	defer cancel()
+
+	resp, err := c.client.Get(watchCtx, k.path(c.namespace), query)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var event struct {
+			Type   string          `json:"type"`
+			Object json.RawMessage `json:"object"`
+		}
+		if err := decoder.Decode(&event); err != nil {
+			// Fim do watch pela API ou pelo prazo local
+			if errors.Is(err, io.EOF) || ctx.Err() != nil || watchCtx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("watch de %s interrompido: %w", k.plural, err)
+		}
+
+		switch event.Type {
+		case "ADDED", "MODIFIED", "DELETED":
+			var item object
+			if err := json.Unmarshal(event.Object, &item); err != nil {
+				return fmt.Errorf("evento inválido da API do Kubernetes: %w", err)
+			}
+			c.update(c.decode(k, item), event.Type == "DELETED")
+		case "ERROR":
+			var status struct {
+				Code    int    `json:"code"`
+				Message string `json:"message"`
+			}
+			_ = json.Unmarshal(event.Object, &status)
+			// 410: a versão listada expirou; listar de novo
+			if status.Code == 410 {
+				return nil
+			}
+			return fmt.Errorf("watch de %s retornou erro %d: %s", k.plural, status.Code, status.Message)
+		}
+	}
+}
+
+// decode converte o recurso nas rotas que ele declara
+func (c *Controller) decode(k *kind, item object) *resource {
+	res := &resource{
+		kind:       k,
+		key:        k.name + "/" + item.Metadata.Namespace + "/" + item.Metadata.Name,
+		namespace:  item.Metadata.Namespace,
+		name:       item.Metadata.Name,
+		generation: item.Metadata.Generation,
+		created:    item.Metadata.CreationTimestamp,
+	}
+	res.routes, res.err = k.convert(item, c.gatewayName)
+	for _, route := range res.routes {
+		if route.Labels == nil {
+			route.Labels = make(map[string]string)
+		}
+		route.Labels[SourceLabel] = res.key
+	}
+	return res
+}
+
+// replace substitui os recursos do tipo pelos listados
+func (c *Controller) replace(k *kind, resources []*resource) {
+	c.mu.Lock()
+	for key, res := range c.resources {
+		if res.kind == k {
+			delete(c.resources, key)
+		}
+	}
+	for _, res := range resources {
+		c.resources[res.key] = res
+	}
+	c.synced[k] = true
+	c.mu.Unlock()
+	c.notify()
+}
+
+// update aplica um evento do watch
+func (c *Controller) update(res *resource, deleted bool) {
+	c.mu.Lock()
+	if deleted {
+		delete(c.resources, res.key)
+		delete(c.statuses, res.key)
+	} else {
+		c.resources[res.key] = res
+	}
+	c.mu.Unlock()
+	c.notify()
+}
+
+// reconcile aplica as rotas dos recursos ao armazenamento. Rotas de recursos
+// inválidos são mantidas como estavam. Um caminho disputado fica com o recurso
+// que já o aplicou ou, entre recursos novos, com o mais antigo.
+func (c *Controller) reconcile(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	c.mu.Lock()
+	resources := make([]*resource, 0, len(c.resources))
+	for _, res := range c.resources {
+		resources = append(resources, res)
+	}
+	c.mu.Unlock()
+	sort.Slice(resources, func(i, j int) bool {
+		if resources[i].created != resources[j].created {
+			return resources[i].created < resources[j].created
+		}
+		return resources[i].key < resources[j].key
+	})
+
+	existing, err := c.store.ListAllRoutes(ctx)
+	if err != nil {
+		c.logger.Error("Falha ao listar as rotas cadastradas", zap.Error(err))
+		return
+	}
+	current := make(map[string]*model.Route, len(existing))
+	for _, route := range existing {
+		current[route.Path] = route
+	}
+
+	results := make(map[string]result, len(resources))
+	owners := make(map[string]string) // caminho -> recurso que o declara
+	keep := make(map[string]bool)     // recursos inválidos, cujas rotas são mantidas
+	var added, updated, removed int
+	for _, res := range resources {
+		if res.err != nil {
+			keep[res.key] = true
+			results[res.key] = result{reasonInvalid, res.err.Error()}
+			continue
+		}
+		if conflict := c.conflict(res, current, owners); conflict != "" {
+			keep[res.key] = true
+			results[res.key] = result{reasonConflict, conflict}
+			continue
+		}
+
+		var errs []error
+		for _, route := range res.routes {
+			owners[route.Path] = res.key
+			stored, ok := current[route.Path]
+			switch {
+			case !ok:
+				if err := c.store.AddRoute(ctx, route); err != nil {
+					errs = append(errs, fmt.Errorf("%s: %w", route.Path, err))
+					continue
+				}
+				added++
+			case !routefile.SameConfig(stored, route):
+				if err := c.store.UpdateRoute(ctx, route); err != nil {
+					errs = append(errs, fmt.Errorf("%s: %w", route.Path, err))
+					continue
+				}
+				updated++
+			}
+		}
+		if err := errors.Join(errs...); err != nil {
+			results[res.key] = result{reasonFailed, err.Error()}
+		} else {
+			results[res.key] = result{reasonAccepted, fmt.Sprintf("%d rota(s) aplicada(s)", len(res.routes))}
+		}
+	}
+
+	for path, route := range current {
+		source, managed := route.Labels[SourceLabel]
+		if !managed || owners[path] != "" || keep[source] {
+			continue
+		}
+		if err := c.store.DeleteRoute(ctx, path); err != nil {
+			c.logger.Error("Falha ao remover rota de recurso do Kubernetes",
+				zap.String("path", path),
+				zap.String("source", source),
+				zap.Error(err))
+			continue
+		}
+		removed++
+	}
+
+	if added+updated+removed > 0 {
+		c.logger.Info("Rotas do Kubernetes aplicadas",
+			zap.Int("added", added),
+			zap.Int("updated", updated),
+			zap.Int("removed", removed))
+	}
+
+	for _, res := range resources {
+		c.report(ctx, res, results[res.key])
+	}
+}
+
+// conflict verifica se os caminhos do recurso já pertencem a outro recurso ou
+// a uma rota cadastrada por fora do Kubernetes
+func (c *Controller) conflict(res *resource, current map[string]*model.Route, owners map[string]string) string {
+	for _, route := range res.routes {
+		if owner := owners[route.Path]; owner != "" {
+			return fmt.Sprintf("caminho %s já declarado por %s", route.Path, owner)
+		}
+		stored, ok := current[route.Path]
+		if !ok {
+			continue
+		}
+		if source := stored.Labels[SourceLabel]; source == "" {
+			return fmt.Sprintf("caminho %s já cadastrado fora do Kubernetes", route.Path)
+		} else if source != res.key && c.declared(source) {
+			return fmt.Sprintf("caminho %s já declarado por %s", route.Path, source)
+		}
+	}
+	return ""
+}
+
+// declared indica se o recurso ainda existe no cluster
+func (c *Controller) declared(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.resources[key]
+	return ok
+}
+
+// report registra o resultado do recurso e o publica no status, quando o tipo
+// tem status próprio. Resultados iguais ao último publicado são ignorados.
+func (c *Controller) report(ctx context.Context, res *resource, outcome result) {
+	fingerprint := fmt.Sprintf("%d/%s/%s", res.generation, outcome.reason, outcome.message)
+	c.mu.Lock()
+	unchanged := c.statuses[res.key] == fingerprint
+	c.mu.Unlock()
+	if unchanged {
+		return
+	}
+
+	if outcome.reason != reasonAccepted {
+		c.logger.Warn("Recurso de rota do Kubernetes não aplicado",
+			zap.String("resource", res.key),
+			zap.String("reason", outcome.reason),
+			zap.String("message", outcome.message))
+	}
+
+	if res.kind.status {
+		condition := map[string]interface{}{
+			"type":               "Accepted",
+			"status":             "True",
+			"reason":             outcome.reason,
+			"message":            outcome.message,
+			"observedGeneration": res.generation,
+			"lastTransitionTime": time.Now().UTC().Format(time.RFC3339),
+		}
+		if outcome.reason != reasonAccepted {
+			condition["status"] = "False"
+		}
+		patch, _ := json.Marshal(map[string]interface{}{
+			"status": map[string]interface{}{
+				"observedGeneration": res.generation,
+				"conditions":         []interface{}{condition},
+			},
+		})
+		if err := c.client.MergePatch(ctx, res.kind.statusPath(res.namespace, res.name), patch); err != nil {
+			c.logger.Warn("Falha ao atualizar o status do recurso de rota",
+				zap.String("resource", res.key),
+				zap.Error(err))
+			return
+		}
+	}
+
+	c.mu.Lock()
+	c.statuses[res.key] = fingerprint
+	c.mu.Unlock()
+}
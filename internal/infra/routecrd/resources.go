@@ -0,0 +1,363 @@
+package routecrd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+)
+
+// Grupo e versão da CRD GatewayRoute (deploy/kubernetes/gatewayroute-crd.yaml)
+const (
+	Group   = "apigateway.diillson.io"
+	Version = "v1alpha1"
+)
+
+// kind é um tipo de recurso do cluster que declara rotas
+type kind struct {
+	name    string // ex.: GatewayRoute
+	group   string
+	version string
+	plural  string
+	status  bool // publica o resultado no subrecurso status
+	convert func(item object, gatewayName string) ([]*model.Route, error)
+}
+
+// path retorna o caminho da listagem; namespace vazio lista todos os namespaces
+func (k *kind) path(namespace string) string {
+	if namespace == "" {
+		return fmt.Sprintf("/apis/%s/%s/%s", k.group, k.version, k.plural)
+	}
+	return fmt.Sprintf("/apis/%s/%s/namespaces/%s/%s", k.group, k.version, url.PathEscape(namespace), k.plural)
+}
+
+// statusPath retorna o caminho do subrecurso status de um recurso
+func (k *kind) statusPath(namespace, name string) string {
+	return k.path(namespace) + "/" + url.PathEscape(name) + "/status"
+}
+
+// object é um recurso de rota como retornado pela API do cluster
+type object struct {
+	Metadata struct {
+		Name              string `json:"name"`
+		Namespace         string `json:"namespace"`
+		Generation        int64  `json:"generation"`
+		CreationTimestamp string `json:"creationTimestamp"`
+	} `json:"metadata"`
+	Spec json.RawMessage `json:"spec"`
+}
+
+// gatewayRouteKind declara uma rota do gateway por recurso. O spec usa os
+// campos do JSON das rotas (ex.: path, serviceURL, methods, retry).
+var gatewayRouteKind = &kind{
+	name:    "GatewayRoute",
+	group:   Group,
+	version: Version,
+	plural:  "gatewayroutes",
+	status:  true,
+	convert: convertGatewayRoute,
+}
+
+// convertGatewayRoute decodifica e valida a rota do spec. Sem isActive, a
+// rota é criada ativa.
+func convertGatewayRoute(item object, _ string) ([]*model.Route, error) {
+	route := &model.Route{IsActive: true}
+	if err := json.Unmarshal(item.Spec, route); err != nil {
+		return nil, fmt.Errorf("spec inválido: %w", err)
+	}
+	route.CallCount = 0
+	route.TotalResponse = 0
+	if err := route.Validate(); err != nil {
+		return nil, err
+	}
+	return []*model.Route{route}, nil
+}
+
+// httpRouteKind traduz HTTPRoutes da Gateway API em rotas do gateway
+var httpRouteKind = &kind{
+	name:    "HTTPRoute",
+	group:   "gateway.networking.k8s.io",
+	version: "v1",
+	plural:  "httproutes",
+	convert: convertHTTPRoute,
+}
+
+// allMethods são os métodos aceitos por regras da HTTPRoute sem filtro de método
+var allMethods = []string{
+	http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+	http.MethodPatch, http.MethodDelete, http.MethodOptions,
+}
+
+type httpRouteSpec struct {
+	ParentRefs []struct {
+		Name string `json:"name"`
+	} `json:"parentRefs"`
+	Hostnames []string        `json:"hostnames"`
+	Rules     []httpRouteRule `json:"rules"`
+}
+
+type httpRouteRule struct {
+	Matches     []httpRouteMatch  `json:"matches"`
+	Filters     []httpRouteFilter `json:"filters"`
+	BackendRefs []struct {
+		Group     string `json:"group"`
+		Kind      string `json:"kind"`
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+		Port      int    `json:"port"`
+		Weight    *int   `json:"weight"`
+	} `json:"backendRefs"`
+	Timeouts *struct {
+		Request string `json:"request"`
+	} `json:"timeouts"`
+}
+
+type httpRouteMatch struct {
+	Path *struct {
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	} `json:"path"`
+	Method  string `json:"method"`
+	Headers []struct {
+		Type  string `json:"type"`
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"headers"`
+	QueryParams []json.RawMessage `json:"queryParams"`
+}
+
+type httpRouteFilter struct {
+	Type       string `json:"type"`
+	URLRewrite *struct {
+		Path *struct {
+			Type               string `json:"type"`
+			ReplaceFullPath    string `json:"replaceFullPath"`
+			ReplacePrefixMatch string `json:"replacePrefixMatch"`
+		} `json:"path"`
+	} `json:"urlRewrite"`
+	RequestHeaderModifier  *httpHeaderModifier `json:"requestHeaderModifier"`
+	ResponseHeaderModifier *httpHeaderModifier `json:"responseHeaderModifier"`
+}
+
+type httpHeaderModifier struct {
+	Set []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"set"`
+	Add []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"add"`
+	Remove []string `json:"remove"`
+}
+
+// convertHTTPRoute traduz cada par regra/correspondência da HTTPRoute em uma
+// rota. Os backends são Services do cluster (service://), resolvidos pela
+// descoberta do Kubernetes. Com gatewayName, apenas HTTPRoutes ligadas ao
+// Gateway de mesmo nome são aceitas; as demais não declaram rotas.
+func convertHTTPRoute(item object, gatewayName string) ([]*model.Route, error) {
+	var spec httpRouteSpec
+	if err := json.Unmarshal(item.Spec, &spec); err != nil {
+		return nil, fmt.Errorf("spec inválido: %w", err)
+	}
+	if gatewayName != "" && !referencesGateway(spec, gatewayName) {
+		return nil, nil
+	}
+
+	var routes []*model.Route
+	seen := make(map[string]bool)
+	for i, rule := range spec.Rules {
+		base, replacePrefix, err := convertHTTPRule(item.Metadata.Namespace, rule)
+		if err != nil {
+			return nil, fmt.Errorf("rules[%d]: %w", i, err)
+		}
+
+		matches := rule.Matches
+		if len(matches) == 0 {
+			matches = []httpRouteMatch{{}}
+		}
+		for j, match := range matches {
+			route := cloneRoute(base)
+			route.Description = fmt.Sprintf("HTTPRoute %s/%s", item.Metadata.Namespace, item.Metadata.Name)
+
+			pathType, value := "PathPrefix", "/"
+			if match.Path != nil {
+				if match.Path.Type != "" {
+					pathType = match.Path.Type
+				}
+				if match.Path.Value != "" {
+					value = match.Path.Value
+				}
+			}
+			switch pathType {
+			case "Exact":
+				route.Path = value
+			case "PathPrefix":
+				route.Path = strings.TrimSuffix(value, "/") + "/*"
+			default:
+				return nil, fmt.Errorf("rules[%d].matches[%d]: path do tipo %s não suportado", i, j, pathType)
+			}
+			if len(match.QueryParams) > 0 {
+				return nil, fmt.Errorf("rules[%d].matches[%d]: queryParams não suportado", i, j)
+			}
+
+			conditions := &model.MatchConditions{Hosts: spec.Hostnames}
+			if match.Method != "" {
+				route.Methods = []string{match.Method}
+			}
+			for _, header := range match.Headers {
+				switch header.Type {
+				case "", "Exact":
+					conditions.Headers = append(conditions.Headers, model.HeaderMatch{Name: header.Name, Value: header.Value})
+				case "RegularExpression":
+					conditions.Headers = append(conditions.Headers, model.HeaderMatch{Name: header.Name, Regex: header.Value})
+				default:
+					return nil, fmt.Errorf("rules[%d].matches[%d]: header do tipo %s não suportado", i, j, header.Type)
+				}
+			}
+			if len(conditions.Hosts) > 0 || len(conditions.Headers) > 0 {
+				route.Match = conditions
+			}
+
+			if replacePrefix != "" {
+				// O restante do caminho segue o novo prefixo
+				if pathType != "PathPrefix" {
+					return nil, fmt.Errorf("rules[%d]: replacePrefixMatch requer path do tipo PathPrefix", i)
+				}
+				route.PathRewrite = &model.PathRewrite{Template: strings.TrimSuffix(replacePrefix, "/") + "/{*}"}
+			}
+
+			if seen[route.Path] {
+				return nil, fmt.Errorf("rules[%d].matches[%d]: caminho %s repetido; condições diferentes no mesmo caminho não são suportadas", i, j, route.Path)
+			}
+			seen[route.Path] = true
+
+			if err := route.Validate(); err != nil {
+				return nil, fmt.Errorf("rules[%d].matches[%d]: %w", i, j, err)
+			}
+			routes = append(routes, route)
+		}
+	}
+	return routes, nil
+}
+
+// convertHTTPRule traduz os backends, filtros e timeouts comuns às
+// correspondências da regra. O prefixo de replacePrefixMatch é retornado à
+// parte, pois a reescrita depende do caminho de cada correspondência.
+func convertHTTPRule(namespace string, rule httpRouteRule) (*model.Route, string, error) {
+	var replacePrefix string
+	route := &model.Route{
+		Methods:  allMethods,
+		IsActive: true,
+	}
+
+	for _, ref := range rule.BackendRefs {
+		if (ref.Group != "" && ref.Group != "core") || (ref.Kind != "" && ref.Kind != "Service") {
+			return nil, "", fmt.Errorf("backendRef %s: apenas Services são suportados", ref.Name)
+		}
+		if ref.Port == 0 {
+			return nil, "", fmt.Errorf("backendRef %s: port é obrigatório", ref.Name)
+		}
+		weight := 1
+		if ref.Weight != nil {
+			weight = *ref.Weight
+		}
+		if weight == 0 {
+			continue
+		}
+		refNamespace := ref.Namespace
+		if refNamespace == "" {
+			refNamespace = namespace
+		}
+		route.Targets = append(route.Targets, model.Target{
+			URL:    model.ServiceTarget{Namespace: refNamespace, Name: ref.Name, Port: fmt.Sprint(ref.Port)}.String(),
+			Weight: weight,
+		})
+	}
+	if len(route.Targets) == 0 {
+		return nil, "", errors.New("nenhum backendRef com peso positivo")
+	}
+	if len(route.Targets) > 1 {
+		route.LoadBalancing = model.LoadBalanceWeighted
+	}
+
+	for _, filter := range rule.Filters {
+		switch filter.Type {
+		case "URLRewrite":
+			if filter.URLRewrite == nil || filter.URLRewrite.Path == nil {
+				continue
+			}
+			switch path := filter.URLRewrite.Path; path.Type {
+			case "ReplaceFullPath":
+				route.PathRewrite = &model.PathRewrite{Template: path.ReplaceFullPath}
+			case "ReplacePrefixMatch":
+				replacePrefix = path.ReplacePrefixMatch
+				if replacePrefix == "" {
+					replacePrefix = "/"
+				}
+			default:
+				return nil, "", fmt.Errorf("urlRewrite do tipo %s não suportado", path.Type)
+			}
+		case "RequestHeaderModifier":
+			if route.HeaderTransforms == nil {
+				route.HeaderTransforms = &model.HeaderTransforms{}
+			}
+			route.HeaderTransforms.Request = headerRules(filter.RequestHeaderModifier)
+		case "ResponseHeaderModifier":
+			if route.HeaderTransforms == nil {
+				route.HeaderTransforms = &model.HeaderTransforms{}
+			}
+			route.HeaderTransforms.Response = headerRules(filter.ResponseHeaderModifier)
+		default:
+			return nil, "", fmt.Errorf("filtro %s não suportado", filter.Type)
+		}
+	}
+
+	if rule.Timeouts != nil && rule.Timeouts.Request != "" {
+		route.Timeout = rule.Timeouts.Request
+	}
+	return route, replacePrefix, nil
+}
+
+// headerRules traduz um filtro de cabeçalhos da HTTPRoute
+func headerRules(modifier *httpHeaderModifier) *model.HeaderRules {
+	rules := &model.HeaderRules{}
+	if modifier == nil {
+		return rules
+	}
+	for _, header := range modifier.Set {
+		if rules.Set == nil {
+			rules.Set = make(map[string]string)
+		}
+		rules.Set[header.Name] = header.Value
+	}
+	for _, header := range modifier.Add {
+		if rules.Add == nil {
+			rules.Add = make(map[string]string)
+		}
+		rules.Add[header.Name] = header.Value
+	}
+	rules.Remove = modifier.Remove
+	return rules
+}
+
+// referencesGateway indica se a HTTPRoute está ligada ao Gateway informado
+func referencesGateway(spec httpRouteSpec, gatewayName string) bool {
+	for _, ref := range spec.ParentRefs {
+		if ref.Name == gatewayName {
+			return true
+		}
+	}
+	return false
+}
+
+// cloneRoute copia a rota base da regra para uma correspondência
+func cloneRoute(base *model.Route) *model.Route {
+	route := *base
+	route.Methods = append([]string(nil), base.Methods...)
+	return &route
+}
@@ -0,0 +1,202 @@
+// Package routefile aplica rotas declaradas em um arquivo YAML ou JSON,
+// acompanhando as alterações do arquivo para implantações no estilo GitOps.
+package routefile
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+// RouteStore é onde as rotas do arquivo são aplicadas
+type RouteStore interface {
+	ListAllRoutes(ctx context.Context) ([]*model.Route, error)
+	AddRoute(ctx context.Context, route *model.Route) error
+	UpdateRoute(ctx context.Context, route *model.Route) error
+	DeleteRoute(ctx context.Context, path string) error
+}
+
+// Result resume as alterações aplicadas por um carregamento
+type Result struct {
+	Added   int
+	Updated int
+	Removed int
+}
+
+// Source sincroniza as rotas do arquivo com o armazenamento. Sem prune, apenas
+// as rotas retiradas do arquivo desde o último carregamento são removidas; com
+// prune, o arquivo é a fonte completa e as demais rotas também são removidas.
+type Source struct {
+	path   string
+	store  RouteStore
+	prune  bool
+	logger *zap.Logger
+
+	mu      sync.Mutex
+	digest  [sha256.Size]byte
+	managed map[string]bool // caminhos definidos no último arquivo aplicado
+}
+
+// NewSource cria a fonte de rotas do arquivo informado
+func NewSource(path string, store RouteStore, prune bool, logger *zap.Logger) *Source {
+	return &Source{
+		path:    path,
+		store:   store,
+		prune:   prune,
+		logger:  logger,
+		managed: make(map[string]bool),
+	}
+}
+
+// Load lê, valida e aplica o arquivo. Um arquivo inválido é rejeitado por
+// inteiro, mantendo as rotas atuais; um arquivo sem alterações desde o último
+// carregamento não é reaplicado.
+func (s *Source) Load(ctx context.Context) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return Result{}, fmt.Errorf("falha ao ler o arquivo de rotas: %w", err)
+	}
+	digest := sha256.Sum256(data)
+	if digest == s.digest {
+		return Result{}, nil
+	}
+
+	routes, err := Parse(data, filepath.Ext(s.path))
+	if err != nil {
+		return Result{}, err
+	}
+
+	result, err := s.apply(ctx, routes)
+	if err != nil {
+		return result, err
+	}
+	s.digest = digest
+	return result, nil
+}
+
+// apply compara as rotas do arquivo com as cadastradas e aplica as diferenças
+func (s *Source) apply(ctx context.Context, routes []*model.Route) (Result, error) {
+	var result Result
+
+	existing, err := s.store.ListAllRoutes(ctx)
+	if err != nil {
+		return result, fmt.Errorf("falha ao listar as rotas cadastradas: %w", err)
+	}
+	current := make(map[string]*model.Route, len(existing))
+	for _, route := range existing {
+		current[route.Path] = route
+	}
+
+	declared := make(map[string]bool, len(routes))
+	var errs []error
+	for _, route := range routes {
+		declared[route.Path] = true
+
+		stored, ok := current[route.Path]
+		switch {
+		case !ok:
+			if err := s.store.AddRoute(ctx, route); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", route.Path, err))
+				continue
+			}
+			result.Added++
+		case !SameConfig(stored, route):
+			if err := s.store.UpdateRoute(ctx, route); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", route.Path, err))
+				continue
+			}
+			result.Updated++
+		}
+	}
+
+	for path := range current {
+		if declared[path] || (!s.prune && !s.managed[path]) {
+			continue
+		}
+		if err := s.store.DeleteRoute(ctx, path); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+			continue
+		}
+		result.Removed++
+	}
+
+	s.managed = declared
+	return result, errors.Join(errs...)
+}
+
+// Parse decodifica as rotas de um documento YAML ou JSON e as valida. O
+// documento pode ser uma lista de rotas ou um objeto com a chave "routes"; os
+// campos seguem os nomes do JSON das rotas (ex.: serviceURL, methods).
+func Parse(data []byte, ext string) ([]*model.Route, error) {
+	if strings.EqualFold(ext, ".yaml") || strings.EqualFold(ext, ".yml") {
+		var document interface{}
+		if err := yaml.Unmarshal(data, &document); err != nil {
+			return nil, fmt.Errorf("arquivo de rotas inválido: %w", err)
+		}
+		converted, err := json.Marshal(document)
+		if err != nil {
+			return nil, fmt.Errorf("arquivo de rotas inválido: %w", err)
+		}
+		data = converted
+	}
+
+	var routes []*model.Route
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		var document struct {
+			Routes []*model.Route
+		}
+		if err := json.Unmarshal(trimmed, &document); err != nil {
+			return nil, fmt.Errorf("arquivo de rotas inválido: %w", err)
+		}
+		routes = document.Routes
+	} else if len(trimmed) > 0 && !bytes.Equal(trimmed, []byte("null")) {
+		if err := json.Unmarshal(trimmed, &routes); err != nil {
+			return nil, fmt.Errorf("arquivo de rotas inválido: %w", err)
+		}
+	}
+
+	seen := make(map[string]bool, len(routes))
+	for i, route := range routes {
+		if route == nil {
+			return nil, fmt.Errorf("routes[%d]: rota vazia", i)
+		}
+		if err := route.Validate(); err != nil {
+			return nil, fmt.Errorf("routes[%d] (%s): %w", i, route.Path, err)
+		}
+		if seen[route.Path] {
+			return nil, fmt.Errorf("routes[%d]: caminho duplicado %s", i, route.Path)
+		}
+		seen[route.Path] = true
+	}
+	return routes, nil
+}
+
+// SameConfig compara a configuração das rotas, ignorando métricas e datas
+func SameConfig(a, b *model.Route) bool {
+	return bytes.Equal(configJSON(a), configJSON(b))
+}
+
+func configJSON(route *model.Route) []byte {
+	copied := *route
+	copied.CallCount = 0
+	copied.TotalResponse = 0
+	copied.CreatedAt = time.Time{}
+	copied.UpdatedAt = time.Time{}
+	data, _ := json.Marshal(copied)
+	return data
+}
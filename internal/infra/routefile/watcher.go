@@ -0,0 +1,92 @@
+package routefile
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+)
+
+// defaultDebounce agrupa os eventos de uma mesma gravação do arquivo
+const defaultDebounce = 500 * time.Millisecond
+
+// loadTimeout limita cada aplicação do arquivo
+const loadTimeout = 30 * time.Second
+
+// Apply carrega o arquivo e registra o resultado
+func (s *Source) Apply(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, loadTimeout)
+	defer cancel()
+
+	result, err := s.Load(ctx)
+	if err != nil {
+		s.logger.Error("Falha ao aplicar o arquivo de rotas; as rotas atuais foram mantidas",
+			zap.String("file", s.path),
+			zap.Error(err))
+		return err
+	}
+	if result != (Result{}) {
+		s.logger.Info("Arquivo de rotas aplicado",
+			zap.String("file", s.path),
+			zap.Int("added", result.Added),
+			zap.Int("updated", result.Updated),
+			zap.Int("removed", result.Removed))
+	}
+	return nil
+}
+
+// Watch reaplica o arquivo a cada alteração até o contexto ser cancelado. O
+// diretório é observado, e não o arquivo, para acompanhar editores que
+// substituem o arquivo e ConfigMaps do Kubernetes, atualizados por symlink.
+func (s *Source) Watch(ctx context.Context, debounce time.Duration) {
+	if debounce <= 0 {
+		debounce = defaultDebounce
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		s.logger.Error("Falha ao observar o arquivo de rotas", zap.Error(err))
+		return
+	}
+	defer watcher.Close()
+
+	dir := filepath.Dir(s.path)
+	if err := watcher.Add(dir); err != nil {
+		s.logger.Error("Falha ao observar o diretório do arquivo de rotas",
+			zap.String("dir", dir),
+			zap.Error(err))
+		return
+	}
+
+	s.logger.Info("Observando alterações no arquivo de rotas", zap.String("file", s.path))
+
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Has(fsnotify.Chmod) {
+				continue
+			}
+			timer.Reset(debounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			s.logger.Warn("Erro ao observar o arquivo de rotas", zap.Error(err))
+		case <-timer.C:
+			// O conteúdo é comparado em Load; eventos de outros arquivos do
+			// diretório não reaplicam as rotas
+			s.Apply(ctx)
+		}
+	}
+}
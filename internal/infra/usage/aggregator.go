@@ -0,0 +1,155 @@
+package usage
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/pkg/reqtemplate"
+)
+
+// ContextKey é a chave do gin.Context onde a atribuição da requisição é armazenada
+const ContextKey = "cost_attribution"
+
+// Unattributed é usado quando não é possível determinar a dimensão de custo
+const Unattributed = "unattributed"
+
+// Attribution identifica a quem o uso de uma requisição é atribuído
+type Attribution struct {
+	Team     string `json:"team"`
+	Product  string `json:"product"`
+	Consumer string `json:"consumer"`
+}
+
+// Resolve avalia as expressões de atribuição da rota. Dimensões sem expressão
+// caem nos rótulos "team" e "product" da rota; falhas resultam em Unattributed.
+func Resolve(route *model.Route, data *reqtemplate.Data) Attribution {
+	attribution := Attribution{
+		Team:     route.Labels["team"],
+		Product:  route.Labels["product"],
+		Consumer: data.Consumer,
+	}
+
+	if route.CostAttribution != nil {
+		if route.CostAttribution.Team != "" {
+			attribution.Team, _ = reqtemplate.Render(route.CostAttribution.Team, data)
+		}
+		if route.CostAttribution.Product != "" {
+			attribution.Product, _ = reqtemplate.Render(route.CostAttribution.Product, data)
+		}
+	}
+
+	if attribution.Team == "" {
+		attribution.Team = Unattributed
+	}
+	if attribution.Product == "" {
+		attribution.Product = Unattributed
+	}
+	if attribution.Consumer == "" {
+		attribution.Consumer = "anonymous"
+	}
+	return attribution
+}
+
+// Record é o uso agregado de uma combinação de rota, tenant e atribuição
+type Record struct {
+	Route           string  `json:"route"`
+	Tenant          string  `json:"tenant,omitempty"`
+	Team            string  `json:"team"`
+	Product         string  `json:"product"`
+	Consumer        string  `json:"consumer"`
+	Requests        int64   `json:"requests"`
+	Errors          int64   `json:"errors"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	BytesIn         int64   `json:"bytes_in"`
+	BytesOut        int64   `json:"bytes_out"`
+}
+
+type recordKey struct {
+	route, tenant string
+	Attribution
+}
+
+// Aggregator acumula o uso em memória até a exportação
+type Aggregator struct {
+	mu      sync.Mutex
+	since   time.Time
+	records map[recordKey]*Record
+}
+
+// NewAggregator cria um agregador de uso vazio
+func NewAggregator() *Aggregator {
+	return &Aggregator{
+		since:   time.Now(),
+		records: make(map[recordKey]*Record),
+	}
+}
+
+// Record contabiliza uma requisição concluída
+func (a *Aggregator) Record(route, tenant string, attribution Attribution, status int, duration time.Duration, bytesIn, bytesOut int) {
+	key := recordKey{route: route, tenant: tenant, Attribution: attribution}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	record, ok := a.records[key]
+	if !ok {
+		record = &Record{
+			Route:    route,
+			Tenant:   tenant,
+			Team:     attribution.Team,
+			Product:  attribution.Product,
+			Consumer: attribution.Consumer,
+		}
+		a.records[key] = record
+	}
+
+	record.Requests++
+	if status >= 500 {
+		record.Errors++
+	}
+	record.DurationSeconds += duration.Seconds()
+	if bytesIn > 0 {
+		record.BytesIn += int64(bytesIn)
+	}
+	if bytesOut > 0 {
+		record.BytesOut += int64(bytesOut)
+	}
+}
+
+// Snapshot retorna o período e os registros acumulados, ordenados por time,
+// produto, consumidor e rota. Com reset, inicia um novo período.
+func (a *Aggregator) Snapshot(reset bool) (time.Time, time.Time, []Record) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	from, to := a.since, time.Now()
+	records := make([]Record, 0, len(a.records))
+	for _, record := range a.records {
+		records = append(records, *record)
+	}
+
+	if reset {
+		a.since = to
+		a.records = make(map[recordKey]*Record)
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		ri, rj := records[i], records[j]
+		if ri.Team != rj.Team {
+			return ri.Team < rj.Team
+		}
+		if ri.Product != rj.Product {
+			return ri.Product < rj.Product
+		}
+		if ri.Consumer != rj.Consumer {
+			return ri.Consumer < rj.Consumer
+		}
+		if ri.Route != rj.Route {
+			return ri.Route < rj.Route
+		}
+		return ri.Tenant < rj.Tenant
+	})
+	return from, to, records
+}
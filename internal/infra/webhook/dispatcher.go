@@ -0,0 +1,215 @@
+// Package webhook entrega as alterações de rotas e os eventos dos webhooks
+// pré-requisição e pós-resposta das rotas a sistemas externos por requisições
+// HTTP assinadas.
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/app/route"
+	"github.com/diillson/api-gateway-go/pkg/config"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// eventBuffer é a capacidade da fila de cada destino; eventos excedentes
+	// de destinos lentos são descartados
+	eventBuffer = 256
+
+	// initialBackoff é a espera antes da primeira nova tentativa, dobrada a cada falha
+	initialBackoff = time.Second
+
+	// Cabeçalhos das entregas
+	HeaderEvent     = "X-Gateway-Event"
+	HeaderDelivery  = "X-Gateway-Delivery"
+	HeaderTimestamp = "X-Gateway-Timestamp"
+	HeaderSignature = "X-Gateway-Signature"
+)
+
+// EventSource fornece as alterações de rotas
+type EventSource interface {
+	Subscribe(buffer int) (<-chan route.Event, func())
+}
+
+// endpoint é um destino das notificações
+type endpoint struct {
+	url    string
+	secret []byte
+	events map[route.EventType]bool // vazio recebe todos
+	queue  chan delivery
+}
+
+// delivery é uma notificação pendente
+type delivery struct {
+	id    string
+	event route.EventType
+	body  []byte
+}
+
+// Dispatcher entrega as alterações de rotas aos destinos configurados. Cada
+// destino tem sua própria fila, preservando a ordem dos eventos.
+type Dispatcher struct {
+	endpoints  []*endpoint
+	client     *http.Client
+	maxRetries int
+	logger     *zap.Logger
+}
+
+// NewDispatcher cria o despachante com os destinos da configuração
+func NewDispatcher(cfg config.RouteWebhooksConfig, logger *zap.Logger) *Dispatcher {
+	endpoints := make([]*endpoint, 0, len(cfg.Endpoints))
+	for _, e := range cfg.Endpoints {
+		events := make(map[route.EventType]bool, len(e.Events))
+		for _, event := range e.Events {
+			events[route.EventType(event)] = true
+		}
+		endpoints = append(endpoints, &endpoint{
+			url:    e.URL,
+			secret: []byte(e.Secret),
+			events: events,
+			queue:  make(chan delivery, eventBuffer),
+		})
+	}
+
+	return &Dispatcher{
+		endpoints:  endpoints,
+		client:     &http.Client{Timeout: cfg.Timeout},
+		maxRetries: cfg.MaxRetries,
+		logger:     logger,
+	}
+}
+
+// Start assina as alterações de rotas e as entrega em segundo plano até o
+// contexto ser cancelado. A assinatura é feita antes do retorno, então
+// nenhuma alteração posterior à chamada é perdida. Apenas as alterações
+// feitas por esta instância são entregues.
+func (d *Dispatcher) Start(ctx context.Context, source EventSource) {
+	if len(d.endpoints) == 0 {
+		return
+	}
+
+	events, unsubscribe := source.Subscribe(eventBuffer)
+	for _, e := range d.endpoints {
+		go d.worker(ctx, e)
+	}
+
+	go func() {
+		defer unsubscribe()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				d.enqueue(event)
+			}
+		}
+	}()
+}
+
+// enqueue serializa o evento e o coloca na fila dos destinos interessados
+func (d *Dispatcher) enqueue(event route.Event) {
+	body, err := json.Marshal(event.Redacted())
+	if err != nil {
+		d.logger.Error("Falha ao serializar evento de rota", zap.String("path", event.Path), zap.Error(err))
+		return
+	}
+
+	for _, e := range d.endpoints {
+		if len(e.events) > 0 && !e.events[event.Type] {
+			continue
+		}
+		select {
+		case e.queue <- delivery{id: uuid.New().String(), event: event.Type, body: body}:
+		default:
+			d.logger.Warn("Fila de webhook cheia, evento de rota descartado",
+				zap.String("url", e.url),
+				zap.String("path", event.Path))
+		}
+	}
+}
+
+// worker entrega em ordem as notificações de um destino
+func (d *Dispatcher) worker(ctx context.Context, e *endpoint) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case item := <-e.queue:
+			d.deliver(ctx, e, item)
+		}
+	}
+}
+
+// deliver envia a notificação, com novas tentativas e espera exponencial
+// para falhas de rede, 429 e 5xx
+func (d *Dispatcher) deliver(ctx context.Context, e *endpoint, item delivery) {
+	backoff := initialBackoff
+	for attempt := 0; ; attempt++ {
+		retry, err := d.send(ctx, e, item)
+		if err == nil {
+			d.logger.Debug("Webhook de rota entregue",
+				zap.String("url", e.url),
+				zap.String("delivery", item.id))
+			return
+		}
+		if !retry || attempt >= d.maxRetries {
+			d.logger.Error("Falha ao entregar webhook de rota",
+				zap.String("url", e.url),
+				zap.String("delivery", item.id),
+				zap.Int("attempts", attempt+1),
+				zap.Error(err))
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// send faz uma tentativa de entrega e indica se uma nova tentativa é cabível
+func (d *Dispatcher) send(ctx context.Context, e *endpoint, item delivery) (bool, error) {
+	req, err := newSignedRequest(ctx, e.url, e.secret, "route."+string(item.event), item.id, item.body)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("destino respondeu com status %d", resp.StatusCode)
+}
+
+// Sign calcula a assinatura HMAC-SHA256, em hexadecimal, de timestamp + "." +
+// corpo. O destino recalcula a assinatura com o segredo compartilhado e
+// rejeita timestamps antigos para evitar reenvios.
+func Sign(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
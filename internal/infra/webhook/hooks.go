@@ -0,0 +1,245 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/pkg/config"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Eventos das chamadas aos webhooks das rotas, enviados em X-Gateway-Event
+const (
+	EventPreRequest   = "request.pre"
+	EventPostResponse = "response.post"
+)
+
+// maxHookResponseBytes limita a resposta lida dos webhooks pré-requisição
+const maxHookResponseBytes = 64 << 10
+
+// HookRequest descreve a requisição enviada aos webhooks da rota
+type HookRequest struct {
+	Route     string            `json:"route"`
+	Method    string            `json:"method"`
+	Path      string            `json:"path"`
+	Query     string            `json:"query,omitempty"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	ClientIP  string            `json:"clientIp,omitempty"`
+	Consumer  string            `json:"consumer,omitempty"`
+	Tenant    string            `json:"tenant,omitempty"`
+	RequestID string            `json:"requestId,omitempty"`
+}
+
+// HookResponse é a notificação dos webhooks pós-resposta
+type HookResponse struct {
+	HookRequest
+	Status        int       `json:"status"`
+	DurationMs    float64   `json:"durationMs"`
+	ResponseBytes int       `json:"responseBytes"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+// PreHookResult é a decisão de um webhook pré-requisição. Respostas 2xx
+// permitem a requisição, opcionalmente com cabeçalhos alterados; as demais
+// abaixo de 500 a recusam e são devolvidas ao cliente.
+type PreHookResult struct {
+	Allowed       bool
+	SetHeaders    map[string]string // Cabeçalhos definidos na requisição enviada ao backend
+	RemoveHeaders []string          // Cabeçalhos removidos da requisição
+	Status        int               // Status da recusa
+	Header        http.Header       // Cabeçalhos da resposta de recusa
+	Body          []byte            // Corpo da resposta de recusa
+}
+
+// preHookMutation é o corpo opcional das respostas 2xx dos webhooks pré-requisição
+type preHookMutation struct {
+	SetHeaders    map[string]string `json:"setHeaders"`
+	RemoveHeaders []string          `json:"removeHeaders"`
+}
+
+// postDelivery é uma notificação pós-resposta pendente
+type postDelivery struct {
+	id     string
+	url    string
+	secret []byte
+	body   []byte
+}
+
+// HookClient chama os webhooks pré-requisição das rotas e entrega em segundo
+// plano as notificações pós-resposta, por uma fila compartilhada
+type HookClient struct {
+	client     *http.Client
+	queue      chan postDelivery
+	workers    int
+	timeout    time.Duration
+	maxRetries int
+	logger     *zap.Logger
+}
+
+// NewHookClient cria o cliente dos webhooks das rotas
+func NewHookClient(cfg config.RouteHooksConfig, logger *zap.Logger) *HookClient {
+	return &HookClient{
+		client:     &http.Client{},
+		queue:      make(chan postDelivery, cfg.QueueSize),
+		workers:    cfg.Workers,
+		timeout:    cfg.Timeout,
+		maxRetries: cfg.MaxRetries,
+		logger:     logger,
+	}
+}
+
+// Start inicia a entrega das notificações pós-resposta até o contexto ser cancelado
+func (h *HookClient) Start(ctx context.Context) {
+	for i := 0; i < h.workers; i++ {
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case item := <-h.queue:
+					h.deliver(ctx, item)
+				}
+			}
+		}()
+	}
+}
+
+// CallPre consulta o webhook pré-requisição. Erros de rede, tempo esgotado e
+// respostas 5xx são retornados como erro.
+func (h *HookClient) CallPre(ctx context.Context, hook *model.PreRequestHook, request *HookRequest) (*PreHookResult, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, hook.TimeoutDuration())
+	defer cancel()
+
+	req, err := newSignedRequest(ctx, hook.URL, []byte(hook.Secret), EventPreRequest, uuid.New().String(), body)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxHookResponseBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode >= 500:
+		return nil, fmt.Errorf("webhook respondeu com status %d", resp.StatusCode)
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		result := &PreHookResult{Allowed: true}
+		if len(bytes.TrimSpace(data)) > 0 {
+			var mutation preHookMutation
+			if err := json.Unmarshal(data, &mutation); err != nil {
+				return nil, fmt.Errorf("resposta do webhook inválida: %w", err)
+			}
+			result.SetHeaders = mutation.SetHeaders
+			result.RemoveHeaders = mutation.RemoveHeaders
+		}
+		return result, nil
+	}
+
+	header := make(http.Header)
+	if contentType := resp.Header.Get("Content-Type"); contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	return &PreHookResult{Status: resp.StatusCode, Header: header, Body: data}, nil
+}
+
+// NotifyPost coloca a notificação na fila de entrega dos webhooks. Com a fila
+// cheia, a notificação é descartada para não atrasar as requisições.
+func (h *HookClient) NotifyPost(hooks []model.PostResponseHook, event *HookResponse) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		h.logger.Error("Falha ao serializar notificação pós-resposta", zap.String("path", event.Route), zap.Error(err))
+		return
+	}
+	for _, hook := range hooks {
+		select {
+		case h.queue <- postDelivery{id: uuid.New().String(), url: hook.URL, secret: []byte(hook.Secret), body: body}:
+		default:
+			h.logger.Warn("Fila de webhooks pós-resposta cheia, notificação descartada",
+				zap.String("url", hook.URL),
+				zap.String("path", event.Route))
+		}
+	}
+}
+
+// deliver envia a notificação, com novas tentativas e espera exponencial
+// para falhas de rede, 429 e 5xx
+func (h *HookClient) deliver(ctx context.Context, item postDelivery) {
+	backoff := initialBackoff
+	for attempt := 0; ; attempt++ {
+		retry, err := h.send(ctx, item)
+		if err == nil {
+			return
+		}
+		if !retry || attempt >= h.maxRetries {
+			h.logger.Warn("Falha ao entregar webhook pós-resposta",
+				zap.String("url", item.url),
+				zap.String("delivery", item.id),
+				zap.Int("attempts", attempt+1),
+				zap.Error(err))
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (h *HookClient) send(ctx context.Context, item postDelivery) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	req, err := newSignedRequest(ctx, item.url, item.secret, EventPostResponse, item.id, item.body)
+	if err != nil {
+		return false, err
+	}
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxHookResponseBytes))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("destino respondeu com status %d", resp.StatusCode)
+}
+
+// newSignedRequest monta o POST JSON assinado com os cabeçalhos das entregas
+func newSignedRequest(ctx context.Context, url string, secret []byte, event, id string, body []byte) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "api-gateway-webhook")
+	req.Header.Set(HeaderEvent, event)
+	req.Header.Set(HeaderDelivery, id)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, "sha256="+Sign(secret, timestamp, body))
+	return req, nil
+}
@@ -0,0 +1,19 @@
+// Package migrations embute no binário as migrações SQL versionadas do banco.
+package migrations
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed migrations/*.sql
+var files embed.FS
+
+// FS retorna os arquivos de migração embutidos
+func FS() fs.FS {
+	sub, err := fs.Sub(files, "migrations")
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
@@ -0,0 +1,47 @@
+-- Esquema base da tabela de rotas. Em bancos criados antes das migrações
+-- versionadas a tabela já existe e esta migração apenas é registrada.
+CREATE TABLE IF NOT EXISTS routes (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    path VARCHAR(255) NOT NULL UNIQUE,
+    service_url TEXT NOT NULL,
+    methods TEXT,
+    headers TEXT,
+    description TEXT,
+    is_active BOOLEAN DEFAULT TRUE,
+    call_count BIGINT DEFAULT 0,
+    total_response BIGINT DEFAULT 0,
+    required_headers TEXT,
+    upstream_auth TEXT,
+    upstream_tls TEXT,
+    egress_proxy TEXT,
+    response_cache TEXT,
+    access_schedule TEXT,
+    labels TEXT,
+    cost_attribution TEXT,
+    health_check TEXT,
+    telemetry TEXT,
+    targets TEXT,
+    load_balancing TEXT,
+    circuit_breaker TEXT,
+    canary TEXT,
+    match_conditions TEXT,
+    variants TEXT,
+    websocket TEXT,
+    protocol TEXT,
+    grpc_web BOOLEAN DEFAULT FALSE,
+    auth_policy TEXT,
+    authorization TEXT,
+    external_authz TEXT,
+    ip_filter TEXT,
+    header_transforms TEXT,
+    path_rewrite TEXT,
+    body_transforms TEXT,
+    upstream_timeout TEXT,
+    retry_policy TEXT,
+    hedging_policy TEXT,
+    bulkhead_policy TEXT,
+    openapi_spec TEXT,
+    created_at DATETIME(3),
+    updated_at DATETIME(3),
+    last_updated_at DATETIME(3)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,10 @@
+-- O MySQL exige tamanho nas colunas com índice único
+CREATE TABLE IF NOT EXISTS users (
+                                     id VARCHAR(36) PRIMARY KEY,
+                                     username VARCHAR(50) UNIQUE NOT NULL,
+                                     password TEXT NOT NULL,
+                                     email VARCHAR(100) UNIQUE,
+                                     role VARCHAR(20) DEFAULT 'user',
+                                     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+                                     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
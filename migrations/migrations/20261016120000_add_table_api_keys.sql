@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS api_keys (
+                                     id VARCHAR(36) PRIMARY KEY,
+                                     name VARCHAR(100) NOT NULL,
+                                     prefix VARCHAR(16) NOT NULL,
+                                     key_hash VARCHAR(64) UNIQUE NOT NULL,
+                                     routes TEXT,
+                                     tier VARCHAR(50),
+                                     revoked BOOLEAN DEFAULT FALSE,
+                                     expires_at TIMESTAMP NULL,
+                                     last_used_at TIMESTAMP NULL,
+                                     revoked_at TIMESTAMP NULL,
+                                     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- migrate:down
+DROP TABLE api_keys;
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS acme_certificates (
+                                     name VARCHAR(255) PRIMARY KEY,
+                                     data TEXT NOT NULL,
+                                     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- migrate:down
+DROP TABLE acme_certificates;
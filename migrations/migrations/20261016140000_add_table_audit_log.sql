@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS audit_log (
+                                     id VARCHAR(36) PRIMARY KEY,
+                                     occurred_at TIMESTAMP NOT NULL,
+                                     action VARCHAR(50) NOT NULL,
+                                     resource VARCHAR(255),
+                                     actor_id VARCHAR(36),
+                                     actor VARCHAR(100),
+                                     source_ip VARCHAR(45),
+                                     before_state TEXT,
+                                     after_state TEXT,
+                                     changes TEXT
+);
+
+CREATE INDEX idx_audit_log_occurred_at ON audit_log (occurred_at);
+CREATE INDEX idx_audit_log_resource ON audit_log (resource);
+
+-- migrate:down
+DROP TABLE audit_log;
@@ -0,0 +1,7 @@
+-- Rotas e usuários passam a pertencer a um tenant. O caminho da rota deixa de
+-- ser único no gateway e passa a ser único dentro do tenant.
+ALTER TABLE routes ADD COLUMN tenant VARCHAR(100) NOT NULL DEFAULT '';
+CREATE UNIQUE INDEX idx_routes_tenant_path ON routes (tenant, path);
+ALTER TABLE routes DROP INDEX path;
+
+ALTER TABLE users ADD COLUMN tenant VARCHAR(100) DEFAULT '';
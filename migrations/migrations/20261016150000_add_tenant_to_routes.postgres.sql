@@ -0,0 +1,8 @@
+-- Rotas e usuários passam a pertencer a um tenant. O caminho da rota deixa de
+-- ser único no gateway e passa a ser único dentro do tenant.
+ALTER TABLE routes ADD COLUMN IF NOT EXISTS tenant VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE routes DROP CONSTRAINT IF EXISTS routes_path_key;
+DROP INDEX IF EXISTS idx_routes_path;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_routes_tenant_path ON routes (tenant, path);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS tenant VARCHAR(100) DEFAULT '';
@@ -0,0 +1,77 @@
+-- Rotas e usuários passam a pertencer a um tenant. O caminho da rota deixa de
+-- ser único no gateway e passa a ser único dentro do tenant. O SQLite não
+-- remove a restrição UNIQUE de uma coluna, então a tabela é recriada.
+CREATE TABLE routes_tenant (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    tenant VARCHAR(100) NOT NULL DEFAULT '',
+    path VARCHAR(255) NOT NULL,
+    service_url TEXT NOT NULL,
+    methods TEXT,
+    headers TEXT,
+    description TEXT,
+    is_active BOOLEAN DEFAULT TRUE,
+    call_count BIGINT DEFAULT 0,
+    total_response BIGINT DEFAULT 0,
+    required_headers TEXT,
+    upstream_auth TEXT,
+    upstream_tls TEXT,
+    egress_proxy TEXT,
+    response_cache TEXT,
+    access_schedule TEXT,
+    labels TEXT,
+    cost_attribution TEXT,
+    health_check TEXT,
+    telemetry TEXT,
+    targets TEXT,
+    load_balancing TEXT,
+    circuit_breaker TEXT,
+    canary TEXT,
+    match_conditions TEXT,
+    variants TEXT,
+    websocket TEXT,
+    protocol TEXT,
+    grpc_web BOOLEAN DEFAULT FALSE,
+    auth_policy TEXT,
+    authorization TEXT,
+    external_authz TEXT,
+    ip_filter TEXT,
+    header_transforms TEXT,
+    path_rewrite TEXT,
+    body_transforms TEXT,
+    upstream_timeout TEXT,
+    retry_policy TEXT,
+    hedging_policy TEXT,
+    bulkhead_policy TEXT,
+    openapi_spec TEXT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    last_updated_at DATETIME,
+    rate_limit_per_minute INT DEFAULT 0,
+    rate_limit_per_hour INT DEFAULT 0,
+    rate_limit_burst_factor REAL DEFAULT 1.5
+);
+
+INSERT INTO routes_tenant (id, path, service_url, methods, headers, description, is_active, call_count,
+    total_response, required_headers, upstream_auth, upstream_tls, egress_proxy, response_cache,
+    access_schedule, labels, cost_attribution, health_check, telemetry, targets, load_balancing,
+    circuit_breaker, canary, match_conditions, variants, websocket, protocol, grpc_web, auth_policy,
+    authorization, external_authz, ip_filter, header_transforms, path_rewrite, body_transforms,
+    upstream_timeout, retry_policy, hedging_policy, bulkhead_policy, openapi_spec, created_at,
+    updated_at, last_updated_at, rate_limit_per_minute, rate_limit_per_hour, rate_limit_burst_factor)
+SELECT id, path, service_url, methods, headers, description, is_active, call_count,
+    total_response, required_headers, upstream_auth, upstream_tls, egress_proxy, response_cache,
+    access_schedule, labels, cost_attribution, health_check, telemetry, targets, load_balancing,
+    circuit_breaker, canary, match_conditions, variants, websocket, protocol, grpc_web, auth_policy,
+    authorization, external_authz, ip_filter, header_transforms, path_rewrite, body_transforms,
+    upstream_timeout, retry_policy, hedging_policy, bulkhead_policy, openapi_spec, created_at,
+    updated_at, last_updated_at, rate_limit_per_minute, rate_limit_per_hour, rate_limit_burst_factor
+FROM routes;
+
+DROP TABLE routes;
+ALTER TABLE routes_tenant RENAME TO routes;
+
+CREATE UNIQUE INDEX idx_routes_tenant_path ON routes (tenant, path);
+CREATE INDEX IF NOT EXISTS idx_routes_call_count ON routes(call_count);
+CREATE INDEX IF NOT EXISTS idx_routes_is_active ON routes(is_active);
+
+ALTER TABLE users ADD COLUMN tenant VARCHAR(100) DEFAULT '';
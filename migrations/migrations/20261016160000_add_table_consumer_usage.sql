@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS consumer_usage (
+                                          consumer VARCHAR(255) NOT NULL,
+                                          period VARCHAR(10) NOT NULL,
+                                          requests BIGINT NOT NULL DEFAULT 0,
+                                          updated_at TIMESTAMP,
+                                          PRIMARY KEY (consumer, period)
+);
+
+-- migrate:down
+DROP TABLE consumer_usage;
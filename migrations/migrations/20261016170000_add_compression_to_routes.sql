@@ -0,0 +1,5 @@
+-- Política de compressão das respostas de cada rota
+ALTER TABLE routes ADD COLUMN compression TEXT;
+
+-- migrate:down
+ALTER TABLE routes DROP COLUMN compression;
@@ -0,0 +1,5 @@
+-- Limites de tamanho e tipos de conteúdo do corpo das requisições de cada rota
+ALTER TABLE routes ADD COLUMN request_body TEXT;
+
+-- migrate:down
+ALTER TABLE routes DROP COLUMN request_body;
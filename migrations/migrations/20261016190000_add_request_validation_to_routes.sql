@@ -0,0 +1,5 @@
+-- Validação do corpo e da query string das requisições de cada rota com JSON Schema
+ALTER TABLE routes ADD COLUMN request_validation TEXT;
+
+-- migrate:down
+ALTER TABLE routes DROP COLUMN request_validation;
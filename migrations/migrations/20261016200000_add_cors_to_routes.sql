@@ -0,0 +1,5 @@
+-- Política CORS de cada rota
+ALTER TABLE routes ADD COLUMN cors TEXT;
+
+-- migrate:down
+ALTER TABLE routes DROP COLUMN cors;
@@ -0,0 +1,7 @@
+-- Modo de manutenção e respostas simuladas de cada rota
+ALTER TABLE routes ADD COLUMN maintenance TEXT;
+ALTER TABLE routes ADD COLUMN stub TEXT;
+
+-- migrate:down
+ALTER TABLE routes DROP COLUMN stub;
+ALTER TABLE routes DROP COLUMN maintenance;
@@ -0,0 +1,5 @@
+-- Espelhamento de parte do tráfego de cada rota para um backend sombra
+ALTER TABLE routes ADD COLUMN mirror TEXT;
+
+-- migrate:down
+ALTER TABLE routes DROP COLUMN mirror;
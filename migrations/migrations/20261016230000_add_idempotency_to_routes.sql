@@ -0,0 +1,5 @@
+-- Política de idempotência de cada rota
+ALTER TABLE routes ADD COLUMN idempotency TEXT;
+
+-- migrate:down
+ALTER TABLE routes DROP COLUMN idempotency;
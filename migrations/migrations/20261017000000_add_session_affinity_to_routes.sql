@@ -0,0 +1,5 @@
+-- Afinidade de sessão entre os alvos de cada rota
+ALTER TABLE routes ADD COLUMN session_affinity TEXT;
+
+-- migrate:down
+ALTER TABLE routes DROP COLUMN session_affinity;
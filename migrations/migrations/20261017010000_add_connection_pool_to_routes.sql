@@ -0,0 +1,5 @@
+-- Ajustes das conexões com os backends de cada rota
+ALTER TABLE routes ADD COLUMN connection_pool TEXT;
+
+-- migrate:down
+ALTER TABLE routes DROP COLUMN connection_pool;
@@ -0,0 +1,5 @@
+-- Plugins aplicados às requisições de cada rota
+ALTER TABLE routes ADD COLUMN plugins TEXT;
+
+-- migrate:down
+ALTER TABLE routes DROP COLUMN plugins;
@@ -0,0 +1,5 @@
+-- Filtro de requisições por expressões de cada rota
+ALTER TABLE routes ADD COLUMN request_filter TEXT;
+
+-- migrate:down
+ALTER TABLE routes DROP COLUMN request_filter;
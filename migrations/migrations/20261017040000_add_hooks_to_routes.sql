@@ -0,0 +1,5 @@
+-- Webhooks pré-requisição e pós-resposta de cada rota
+ALTER TABLE routes ADD COLUMN hooks TEXT;
+
+-- migrate:down
+ALTER TABLE routes DROP COLUMN hooks;
@@ -0,0 +1,5 @@
+-- Política GraphQL (limites e operações persistidas) de cada rota
+ALTER TABLE routes ADD COLUMN graphql TEXT;
+
+-- migrate:down
+ALTER TABLE routes DROP COLUMN graphql;
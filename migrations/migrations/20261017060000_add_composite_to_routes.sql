@@ -0,0 +1,5 @@
+-- Composição de respostas (chamadas paralelas e template) de cada rota
+ALTER TABLE routes ADD COLUMN composite TEXT;
+
+-- migrate:down
+ALTER TABLE routes DROP COLUMN composite;
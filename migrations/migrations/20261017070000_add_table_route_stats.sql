@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS route_stats (
+                                       node VARCHAR(255) NOT NULL,
+                                       tenant VARCHAR(255) NOT NULL DEFAULT '',
+                                       route VARCHAR(255) NOT NULL,
+                                       minute TIMESTAMP NOT NULL,
+                                       requests BIGINT NOT NULL DEFAULT 0,
+                                       errors BIGINT NOT NULL DEFAULT 0,
+                                       duration_ns BIGINT NOT NULL DEFAULT 0,
+                                       status TEXT,
+                                       latency TEXT,
+                                       PRIMARY KEY (node, tenant, route, minute)
+);
+
+CREATE INDEX idx_route_stats_route_minute ON route_stats (tenant, route, minute);
+
+-- migrate:down
+DROP TABLE route_stats;
@@ -0,0 +1,5 @@
+-- Detecção de alvos anômalos (verificação passiva de saúde) de cada rota
+ALTER TABLE routes ADD COLUMN outlier_detection TEXT;
+
+-- migrate:down
+ALTER TABLE routes DROP COLUMN outlier_detection;
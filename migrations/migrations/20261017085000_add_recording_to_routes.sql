@@ -0,0 +1,5 @@
+-- Política de gravação de requisições e respostas de cada rota
+ALTER TABLE routes ADD COLUMN recording TEXT;
+
+-- migrate:down
+ALTER TABLE routes DROP COLUMN recording;
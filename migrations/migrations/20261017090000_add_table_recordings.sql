@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS recordings (
+                                      id VARCHAR(36) PRIMARY KEY,
+                                      recorded_at TIMESTAMP NOT NULL,
+                                      tenant VARCHAR(255),
+                                      route VARCHAR(255),
+                                      request_id VARCHAR(255),
+                                      method VARCHAR(10),
+                                      path TEXT,
+                                      query TEXT,
+                                      host VARCHAR(255),
+                                      request_headers TEXT,
+                                      request_body TEXT,
+                                      request_body_truncated BOOLEAN NOT NULL DEFAULT FALSE,
+                                      status INTEGER,
+                                      response_headers TEXT,
+                                      response_body TEXT,
+                                      response_body_truncated BOOLEAN NOT NULL DEFAULT FALSE,
+                                      duration_ms DOUBLE PRECISION
+);
+
+CREATE INDEX idx_recordings_recorded_at ON recordings (recorded_at);
+CREATE INDEX idx_recordings_route ON recordings (route, recorded_at);
+
+-- migrate:down
+DROP TABLE recordings;
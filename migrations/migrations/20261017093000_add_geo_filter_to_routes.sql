@@ -0,0 +1,5 @@
+-- Países permitidos e bloqueados de cada rota pela geolocalização do cliente
+ALTER TABLE routes ADD COLUMN geo_filter TEXT;
+
+-- migrate:down
+ALTER TABLE routes DROP COLUMN geo_filter;
@@ -0,0 +1,5 @@
+-- Mensagens e páginas de erro de cada rota quando o backend falha
+ALTER TABLE routes ADD COLUMN error_policy TEXT;
+
+-- migrate:down
+ALTER TABLE routes DROP COLUMN error_policy;
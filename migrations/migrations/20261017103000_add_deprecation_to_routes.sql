@@ -0,0 +1,5 @@
+-- Descontinuação de cada rota: datas, links e desativação após o sunset
+ALTER TABLE routes ADD COLUMN deprecation TEXT;
+
+-- migrate:down
+ALTER TABLE routes DROP COLUMN deprecation;
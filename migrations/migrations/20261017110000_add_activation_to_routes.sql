@@ -0,0 +1,9 @@
+-- Ativação agendada das rotas e feature flag que as habilita por coorte
+ALTER TABLE routes ADD COLUMN active_from TEXT;
+ALTER TABLE routes ADD COLUMN active_until TEXT;
+ALTER TABLE routes ADD COLUMN feature_flag TEXT;
+
+-- migrate:down
+ALTER TABLE routes DROP COLUMN feature_flag;
+ALTER TABLE routes DROP COLUMN active_until;
+ALTER TABLE routes DROP COLUMN active_from;
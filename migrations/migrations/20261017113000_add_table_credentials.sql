@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS credentials (
+                                     id VARCHAR(36) PRIMARY KEY,
+                                     name VARCHAR(100) NOT NULL,
+                                     type VARCHAR(16) NOT NULL,
+                                     identifier VARCHAR(100) UNIQUE NOT NULL,
+                                     secret TEXT NOT NULL,
+                                     routes TEXT,
+                                     revoked BOOLEAN DEFAULT FALSE,
+                                     expires_at TIMESTAMP NULL,
+                                     last_used_at TIMESTAMP NULL,
+                                     revoked_at TIMESTAMP NULL,
+                                     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- migrate:down
+DROP TABLE credentials;
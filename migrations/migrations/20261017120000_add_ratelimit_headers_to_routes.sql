@@ -0,0 +1,5 @@
+-- Estilo dos cabeçalhos de limite (RateLimit-* e X-RateLimit-*) nas respostas da rota
+ALTER TABLE routes ADD COLUMN ratelimit_headers TEXT;
+
+-- migrate:down
+ALTER TABLE routes DROP COLUMN ratelimit_headers;
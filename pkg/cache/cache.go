@@ -2,9 +2,14 @@ package cache
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
+// ErrClearUnsupported indica um cache que não consegue limpar apenas as chaves
+// do gateway sem descartar as de outras aplicações
+var ErrClearUnsupported = errors.New("o cache não permite limpar apenas as chaves do gateway")
+
 // Cache define a interface para operações de cache
 type Cache interface {
 	// Set armazena um valor no cache com tempo de expiração
@@ -22,3 +27,10 @@ type Cache interface {
 	// Ping verifica se o cache está acessível
 	Ping(ctx context.Context) error
 }
+
+// Adder é implementado pelos caches capazes de armazenar um valor apenas
+// quando a chave ainda não existe, de forma atômica (ex.: nonces de uso único)
+type Adder interface {
+	// Add armazena o valor e retorna false quando a chave já existe
+	Add(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error)
+}
@@ -0,0 +1,291 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/diillson/api-gateway-go/internal/infra/metrics"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// localShards é o número de partições do cache local. Cada partição tem o
+// próprio lock, reduzindo a disputa entre requisições concorrentes.
+const localShards = 64
+
+// LocalCache implementa a interface Cache em memória, com limite de itens e de
+// bytes. Os valores são guardados serializados, como no Redis, e os menos
+// usados recentemente são descartados quando um limite é atingido.
+//
+// Bibliotecas como ristretto e bigcache não atendem ao contrato do Cache: a
+// política de admissão do ristretto pode recusar um Set recém-feito, o que
+// quebra o Add dos nonces, e o bigcache usa um único prazo de vida para todas
+// as chaves, sem expiração por item nem inserção atômica.
+type LocalCache struct {
+	shards            [localShards]*localShard
+	defaultExpiration time.Duration
+	logger            *zap.Logger
+	hits              int64
+	misses            int64
+	metrics           *metrics.APIMetrics
+	tracer            trace.Tracer
+}
+
+// localShard é uma partição do cache, com a ordem de uso das chaves
+type localShard struct {
+	mutex    sync.Mutex
+	items    map[string]*list.Element
+	order    *list.List // Mais recente na frente
+	bytes    int64
+	maxItems int
+	maxBytes int64
+}
+
+// localEntry é um valor armazenado no cache local
+type localEntry struct {
+	key       string
+	data      []byte
+	expiresAt time.Time // Zero quando não expira
+}
+
+// NewLocalCache cria uma nova instância de LocalCache. Os limites são divididos
+// entre as partições; zero deixa o limite correspondente desativado.
+func NewLocalCache(defaultExpiration time.Duration, maxItems, maxMemoryMB int, metrics *metrics.APIMetrics, logger *zap.Logger) *LocalCache {
+	c := &LocalCache{
+		defaultExpiration: defaultExpiration,
+		logger:            logger,
+		metrics:           metrics,
+		tracer:            otel.GetTracerProvider().Tracer("api-gateway.cache.local"),
+	}
+
+	shardItems := 0
+	if maxItems > 0 {
+		shardItems = (maxItems + localShards - 1) / localShards
+	}
+	var shardBytes int64
+	if maxMemoryMB > 0 {
+		shardBytes = (int64(maxMemoryMB)<<20 + localShards - 1) / localShards
+	}
+	for i := range c.shards {
+		c.shards[i] = &localShard{
+			items:    make(map[string]*list.Element),
+			order:    list.New(),
+			maxItems: shardItems,
+			maxBytes: shardBytes,
+		}
+	}
+	return c
+}
+
+// shard retorna a partição da chave
+func (c *LocalCache) shard(key string) *localShard {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return c.shards[h.Sum32()%localShards]
+}
+
+// expiresAt calcula o vencimento. Zero usa a expiração padrão e valores
+// negativos não expiram, como no cache em memória.
+func (c *LocalCache) expiresAt(expiration time.Duration) time.Time {
+	if expiration == 0 {
+		expiration = c.defaultExpiration
+	}
+	if expiration <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(expiration)
+}
+
+// Set armazena um valor no cache
+func (c *LocalCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	// Criar span para a operação de cache
+	ctx, span := c.tracer.Start(
+		ctx,
+		"LocalCache.Set",
+		trace.WithAttributes(
+			attribute.String("cache.key", key),
+			attribute.String("cache.operation", "set"),
+			attribute.Int64("cache.expiration_ms", expiration.Milliseconds()),
+		),
+	)
+	defer span.End()
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		c.logger.Error("falha ao serializar para cache", zap.Error(err))
+		span.SetStatus(codes.Error, "serialization failure")
+		span.SetAttributes(
+			attribute.Bool("error", true),
+			attribute.String("error.message", err.Error()),
+		)
+		return err
+	}
+	span.SetAttributes(attribute.Int("cache.data_size_bytes", len(data)))
+
+	stored := c.shard(key).set(key, data, c.expiresAt(expiration), false)
+	span.SetAttributes(attribute.Bool("cache.stored", stored))
+	span.SetStatus(codes.Ok, "")
+	return nil
+}
+
+// Add armazena o valor apenas quando a chave ainda não existe
+func (c *LocalCache) Add(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false, err
+	}
+	return c.shard(key).set(key, data, c.expiresAt(expiration), true), nil
+}
+
+// Get recupera um valor do cache
+func (c *LocalCache) Get(ctx context.Context, key string, dest interface{}) (bool, error) {
+	// Criar span para a operação de cache
+	ctx, span := c.tracer.Start(
+		ctx,
+		"LocalCache.Get",
+		trace.WithAttributes(
+			attribute.String("cache.key", key),
+			attribute.String("cache.operation", "get"),
+		),
+	)
+	defer span.End()
+
+	data, found := c.shard(key).get(key, time.Now())
+	if !found {
+		atomic.AddInt64(&c.misses, 1)
+		updateCacheMetrics(atomic.LoadInt64(&c.hits), atomic.LoadInt64(&c.misses), "local", c.metrics)
+		recordLookup(ctx, "local", false)
+
+		span.SetAttributes(attribute.Bool("cache.hit", false))
+		span.SetStatus(codes.Ok, "cache miss")
+		return false, nil
+	}
+
+	atomic.AddInt64(&c.hits, 1)
+	updateCacheMetrics(atomic.LoadInt64(&c.hits), atomic.LoadInt64(&c.misses), "local", c.metrics)
+	recordLookup(ctx, "local", true)
+	span.SetAttributes(
+		attribute.Bool("cache.hit", true),
+		attribute.Int("cache.data_size_bytes", len(data)),
+	)
+
+	if err := json.Unmarshal(data, dest); err != nil {
+		c.logger.Error("falha ao deserializar do cache",
+			zap.String("key", key),
+			zap.Error(err))
+
+		span.SetStatus(codes.Error, "deserialization failure")
+		span.SetAttributes(
+			attribute.Bool("error", true),
+			attribute.String("error.message", err.Error()),
+		)
+		return false, err
+	}
+
+	span.SetStatus(codes.Ok, "cache hit")
+	return true, nil
+}
+
+// Delete remove um valor do cache
+func (c *LocalCache) Delete(ctx context.Context, key string) error {
+	shard := c.shard(key)
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+
+	if element, ok := shard.items[key]; ok {
+		shard.remove(element)
+	}
+	return nil
+}
+
+// Clear remove todos os valores do cache
+func (c *LocalCache) Clear(ctx context.Context) error {
+	for _, shard := range c.shards {
+		shard.mutex.Lock()
+		shard.items = make(map[string]*list.Element)
+		shard.order.Init()
+		shard.bytes = 0
+		shard.mutex.Unlock()
+	}
+	return nil
+}
+
+// Ping verifica se o cache está funcionando
+func (c *LocalCache) Ping(ctx context.Context) error {
+	return nil // O cache local está sempre disponível
+}
+
+// set armazena o valor na partição. Com onlyNew, uma chave existente e não
+// vencida é mantida. Valores maiores que o limite da partição são descartados,
+// junto com o valor anterior da chave.
+func (s *localShard) set(key string, data []byte, expiresAt time.Time, onlyNew bool) bool {
+	size := int64(len(key) + len(data))
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if element, ok := s.items[key]; ok {
+		entry := element.Value.(*localEntry)
+		if onlyNew && !entry.expired(time.Now()) {
+			return false
+		}
+		if s.maxBytes > 0 && size > s.maxBytes {
+			s.remove(element)
+			return false
+		}
+		s.bytes += int64(len(data) - len(entry.data))
+		entry.data = data
+		entry.expiresAt = expiresAt
+		s.order.MoveToFront(element)
+	} else {
+		if s.maxBytes > 0 && size > s.maxBytes {
+			return false
+		}
+		s.items[key] = s.order.PushFront(&localEntry{key: key, data: data, expiresAt: expiresAt})
+		s.bytes += size
+	}
+
+	// Descartar os itens menos usados recentemente até respeitar os limites
+	for (s.maxItems > 0 && len(s.items) > s.maxItems) || (s.maxBytes > 0 && s.bytes > s.maxBytes) {
+		s.remove(s.order.Back())
+	}
+	return true
+}
+
+// get retorna o valor da chave e a marca como usada recentemente
+func (s *localShard) get(key string, now time.Time) ([]byte, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	element, ok := s.items[key]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*localEntry)
+	if entry.expired(now) {
+		s.remove(element)
+		return nil, false
+	}
+	s.order.MoveToFront(element)
+	return entry.data, true
+}
+
+// remove descarta o item da partição
+func (s *localShard) remove(element *list.Element) {
+	entry := s.order.Remove(element).(*localEntry)
+	delete(s.items, entry.key)
+	s.bytes -= int64(len(entry.key) + len(entry.data))
+}
+
+// expired indica se o item venceu
+func (e *localEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && now.After(e.expiresAt)
+}
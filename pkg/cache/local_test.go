@@ -0,0 +1,237 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// sameShardKeys retorna n chaves que caem na mesma partição
+func sameShardKeys(c *LocalCache, n int) []string {
+	first := "chave-0"
+	keys := []string{first}
+	for i := 1; len(keys) < n; i++ {
+		key := fmt.Sprintf("chave-%d", i)
+		if c.shard(key) == c.shard(first) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func TestLocalCacheSetGet(t *testing.T) {
+	ctx := context.Background()
+	c := NewLocalCache(time.Minute, 0, 0, nil, zap.NewNop())
+
+	type value struct {
+		Name  string
+		Count int
+	}
+	if err := c.Set(ctx, "rota", value{Name: "users", Count: 3}, 0); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	var got value
+	found, err := c.Get(ctx, "rota", &got)
+	if err != nil || !found {
+		t.Fatalf("Get = %v, %v; esperado valor encontrado", found, err)
+	}
+	if got != (value{Name: "users", Count: 3}) {
+		t.Fatalf("Get retornou %+v", got)
+	}
+
+	if found, err := c.Get(ctx, "ausente", &got); err != nil || found {
+		t.Fatalf("Get de chave ausente = %v, %v", found, err)
+	}
+
+	if err := c.Delete(ctx, "rota"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if found, _ := c.Get(ctx, "rota", &got); found {
+		t.Fatal("chave removida continua no cache")
+	}
+}
+
+func TestLocalCacheExpiration(t *testing.T) {
+	ctx := context.Background()
+	c := NewLocalCache(time.Minute, 0, 0, nil, zap.NewNop())
+
+	c.Set(ctx, "padrao", 1, 0)
+	c.Set(ctx, "curta", 1, time.Second)
+	c.Set(ctx, "permanente", 1, -1)
+
+	later := time.Now().Add(time.Hour)
+	for key, want := range map[string]bool{"padrao": false, "curta": false, "permanente": true} {
+		if _, found := c.shard(key).get(key, later); found != want {
+			t.Errorf("get(%q) uma hora depois = %v, esperado %v", key, found, want)
+		}
+	}
+	if _, found := c.shard("curta").items["curta"]; found {
+		t.Error("item vencido não foi descartado na leitura")
+	}
+}
+
+func TestLocalCacheAdd(t *testing.T) {
+	ctx := context.Background()
+	c := NewLocalCache(time.Minute, 0, 0, nil, zap.NewNop())
+
+	if added, err := c.Add(ctx, "nonce", true, time.Minute); err != nil || !added {
+		t.Fatalf("primeiro Add = %v, %v; esperado true", added, err)
+	}
+	if added, _ := c.Add(ctx, "nonce", true, time.Minute); added {
+		t.Fatal("Add aceitou chave existente")
+	}
+
+	// Uma chave vencida, ainda não descartada, pode ser registrada de novo
+	c.shard("vencida").set("vencida", []byte("true"), time.Now().Add(-time.Second), false)
+	if added, _ := c.Add(ctx, "vencida", true, time.Minute); !added {
+		t.Fatal("Add recusou chave vencida")
+	}
+}
+
+func TestLocalCacheAddConcurrent(t *testing.T) {
+	ctx := context.Background()
+	c := NewLocalCache(time.Minute, 0, 0, nil, zap.NewNop())
+
+	var added int32
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if ok, _ := c.Add(ctx, "nonce", true, time.Minute); ok {
+				atomic.AddInt32(&added, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if added != 1 {
+		t.Fatalf("%d chamadas concorrentes de Add registraram a chave, esperada 1", added)
+	}
+}
+
+func TestLocalCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	// Duas chaves por partição
+	c := NewLocalCache(time.Minute, 2*localShards, 0, nil, zap.NewNop())
+	keys := sameShardKeys(c, 3)
+
+	c.Set(ctx, keys[0], 0, 0)
+	c.Set(ctx, keys[1], 1, 0)
+	var v int
+	c.Get(ctx, keys[0], &v) // keys[1] passa a ser a menos usada
+	c.Set(ctx, keys[2], 2, 0)
+
+	for i, want := range []bool{true, false, true} {
+		if found, _ := c.Get(ctx, keys[i], &v); found != want {
+			t.Errorf("Get(%q) = %v, esperado %v", keys[i], found, want)
+		}
+	}
+}
+
+func TestLocalCacheMemoryLimit(t *testing.T) {
+	ctx := context.Background()
+	c := NewLocalCache(time.Minute, 0, 1, nil, zap.NewNop())
+	shard := c.shard("grande")
+	limit := shard.maxBytes
+
+	c.Set(ctx, "grande", "pequeno", 0)
+	// Um valor maior que a partição é descartado junto com o anterior
+	c.Set(ctx, "grande", strings.Repeat("a", int(limit)), 0)
+	var v string
+	if found, _ := c.Get(ctx, "grande", &v); found {
+		t.Fatal("valor acima do limite da partição foi armazenado")
+	}
+	if shard.bytes != 0 {
+		t.Fatalf("partição contabiliza %d bytes após descartar o valor", shard.bytes)
+	}
+
+	// Valores que somados passam do limite descartam os menos usados
+	keys := sameShardKeys(c, 3)
+	value := strings.Repeat("a", int(limit/3))
+	for _, key := range keys {
+		c.Set(ctx, key, value, 0)
+	}
+	if shard := c.shard(keys[0]); shard.bytes > shard.maxBytes {
+		t.Fatalf("partição com %d bytes, acima do limite de %d", shard.bytes, shard.maxBytes)
+	}
+	if found, _ := c.Get(ctx, keys[0], &v); found {
+		t.Fatal("o item menos usado não foi descartado")
+	}
+	if found, _ := c.Get(ctx, keys[2], &v); !found {
+		t.Fatal("o item mais recente foi descartado")
+	}
+}
+
+func TestLocalCacheByteAccounting(t *testing.T) {
+	ctx := context.Background()
+	c := NewLocalCache(time.Minute, 0, 0, nil, zap.NewNop())
+	shard := c.shard("chave")
+
+	c.Set(ctx, "chave", "curto", 0)
+	c.Set(ctx, "chave", "um valor bem mais longo", 0)
+	if want := int64(len("chave") + len(`"um valor bem mais longo"`)); shard.bytes != want {
+		t.Fatalf("partição contabiliza %d bytes, esperado %d", shard.bytes, want)
+	}
+
+	c.Delete(ctx, "chave")
+	if shard.bytes != 0 {
+		t.Fatalf("partição contabiliza %d bytes após Delete", shard.bytes)
+	}
+
+	c.Set(ctx, "chave", 1, 0)
+	if err := c.Clear(ctx); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+	if shard.bytes != 0 || len(shard.items) != 0 || shard.order.Len() != 0 {
+		t.Fatal("Clear manteve itens na partição")
+	}
+}
+
+func BenchmarkLocalCacheGet(b *testing.B) {
+	ctx := context.Background()
+	c := NewLocalCache(time.Minute, 10000, 0, nil, zap.NewNop())
+	keys := make([]string, 1000)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("route:/api/service%d", i)
+		c.Set(ctx, keys[i], map[string]string{"path": keys[i]}, 0)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		var v map[string]string
+		i := 0
+		for pb.Next() {
+			c.Get(ctx, keys[i%len(keys)], &v)
+			i++
+		}
+	})
+}
+
+func BenchmarkLocalCacheSet(b *testing.B) {
+	ctx := context.Background()
+	// Limite menor que o número de chaves, para incluir os descartes
+	c := NewLocalCache(time.Minute, 1000, 0, nil, zap.NewNop())
+	keys := make([]string, 5000)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("route:/api/service%d", i)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			c.Set(ctx, keys[i%len(keys)], i, 0)
+			i++
+		}
+	})
+}
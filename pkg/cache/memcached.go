@@ -0,0 +1,434 @@
+package cache
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// memcachedMaxRelative é o maior prazo que o Memcached aceita como duração;
+// prazos maiores precisam ser enviados como timestamp Unix
+const memcachedMaxRelative = 30 * 24 * time.Hour
+
+// memcachedMaxKey é o tamanho máximo de uma chave no Memcached
+const memcachedMaxKey = 250
+
+// ErrMemcachedServer indica uma resposta de erro do servidor Memcached
+var ErrMemcachedServer = errors.New("erro do servidor memcached")
+
+// MemcachedCache implementa a interface Cache usando Memcached pelo protocolo
+// texto. As chaves são distribuídas entre os servidores pelo hash.
+//
+// O cliente é próprio porque usa só set, add, get, delete e version, e assim
+// respeita o prazo e o cancelamento do contexto de cada requisição, o que o
+// gomemcache não oferece, sem acrescentar outra dependência ao gateway.
+type MemcachedCache struct {
+	servers []*memcachedServer
+	timeout time.Duration
+	logger  *zap.Logger
+	tracer  trace.Tracer
+}
+
+// memcachedServer guarda as conexões ociosas de um servidor
+type memcachedServer struct {
+	addr string
+	idle chan *memcachedConn
+}
+
+// memcachedConn é uma conexão com leitura e escrita bufferizadas
+type memcachedConn struct {
+	net.Conn
+	rw *bufio.ReadWriter
+}
+
+// NewMemcachedCache cria uma nova instância de MemcachedCache e verifica a
+// conexão com todos os servidores
+func NewMemcachedCache(servers []string, timeout time.Duration, maxIdleConns int, logger *zap.Logger) (*MemcachedCache, error) {
+	if len(servers) == 0 {
+		return nil, errors.New("nenhum servidor memcached configurado")
+	}
+	if timeout <= 0 {
+		timeout = time.Second
+	}
+	if maxIdleConns <= 0 {
+		maxIdleConns = 2
+	}
+
+	c := &MemcachedCache{
+		timeout: timeout,
+		logger:  logger,
+		tracer:  otel.GetTracerProvider().Tracer("api-gateway.cache.memcached"),
+	}
+	for _, addr := range servers {
+		c.servers = append(c.servers, &memcachedServer{addr: addr, idle: make(chan *memcachedConn, maxIdleConns)})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := c.Ping(ctx); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Set armazena um valor no cache
+func (c *MemcachedCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	// Criar span para a operação
+	ctx, span := c.tracer.Start(
+		ctx,
+		"MemcachedCache.Set",
+		trace.WithAttributes(
+			attribute.String("cache.key", key),
+			attribute.String("cache.operation", "set"),
+			attribute.Int64("cache.expiration_ms", expiration.Milliseconds()),
+		),
+	)
+	defer span.End()
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		c.logger.Error("falha ao serializar para cache", zap.Error(err))
+		span.SetStatus(codes.Error, "serialization failure")
+		span.SetAttributes(
+			attribute.Bool("error", true),
+			attribute.String("error.message", err.Error()),
+		)
+		return err
+	}
+	span.SetAttributes(attribute.Int("cache.data_size_bytes", len(data)))
+
+	if _, err := c.store(ctx, "set", key, data, expiration); err != nil {
+		c.logger.Error("falha ao armazenar no Memcached",
+			zap.String("key", key),
+			zap.Error(err))
+		span.SetStatus(codes.Error, "memcached error")
+		span.SetAttributes(
+			attribute.Bool("error", true),
+			attribute.String("error.message", err.Error()),
+		)
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return nil
+}
+
+// Add armazena o valor apenas quando a chave ainda não existe (comando add)
+func (c *MemcachedCache) Add(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false, err
+	}
+	added, err := c.store(ctx, "add", key, data, expiration)
+	if err != nil {
+		c.logger.Error("falha ao armazenar no Memcached",
+			zap.String("key", key),
+			zap.Error(err))
+		return false, err
+	}
+	return added, nil
+}
+
+// Get recupera um valor do cache
+func (c *MemcachedCache) Get(ctx context.Context, key string, dest interface{}) (bool, error) {
+	// Criar span para a operação
+	ctx, span := c.tracer.Start(
+		ctx,
+		"MemcachedCache.Get",
+		trace.WithAttributes(
+			attribute.String("cache.key", key),
+			attribute.String("cache.operation", "get"),
+		),
+	)
+	defer span.End()
+
+	var data []byte
+	found := false
+	err := c.do(ctx, key, func(conn *memcachedConn) error {
+		fmt.Fprintf(conn.rw, "get %s\r\n", memcachedKey(key))
+		if err := conn.rw.Flush(); err != nil {
+			return err
+		}
+		var err error
+		data, found, err = readMemcachedValue(conn.rw.Reader)
+		return err
+	})
+	if err != nil {
+		c.logger.Error("falha ao recuperar do cache",
+			zap.String("key", key),
+			zap.Error(err))
+		span.SetStatus(codes.Error, "memcached error")
+		span.SetAttributes(
+			attribute.Bool("error", true),
+			attribute.Bool("cache.hit", false),
+			attribute.String("error.message", err.Error()),
+		)
+		return false, err
+	}
+
+	recordLookup(ctx, "memcached", found)
+	span.SetAttributes(attribute.Bool("cache.hit", found))
+	if !found {
+		span.SetStatus(codes.Ok, "cache miss")
+		return false, nil
+	}
+	span.SetAttributes(attribute.Int("cache.data_size_bytes", len(data)))
+
+	if err := json.Unmarshal(data, dest); err != nil {
+		c.logger.Error("falha ao deserializar do cache",
+			zap.String("key", key),
+			zap.Error(err))
+		span.SetStatus(codes.Error, "deserialization failure")
+		span.SetAttributes(
+			attribute.Bool("error", true),
+			attribute.String("error.message", err.Error()),
+		)
+		return false, err
+	}
+
+	span.SetStatus(codes.Ok, "cache hit")
+	return true, nil
+}
+
+// Delete remove um valor do cache
+func (c *MemcachedCache) Delete(ctx context.Context, key string) error {
+	return c.do(ctx, key, func(conn *memcachedConn) error {
+		fmt.Fprintf(conn.rw, "delete %s\r\n", memcachedKey(key))
+		if err := conn.rw.Flush(); err != nil {
+			return err
+		}
+		line, err := readMemcachedLine(conn.rw.Reader)
+		if err != nil {
+			return err
+		}
+		if line != "DELETED" && line != "NOT_FOUND" {
+			return fmt.Errorf("%w: %s", ErrMemcachedServer, line)
+		}
+		return nil
+	})
+}
+
+// Clear não é suportado: o Memcached não lista nem filtra chaves, e o
+// flush_all descartaria também os itens de outras aplicações que compartilham
+// os servidores. As chaves do gateway expiram pelo TTL ou são removidas uma a
+// uma, como na limpeza do cache de rotas.
+func (c *MemcachedCache) Clear(ctx context.Context) error {
+	return ErrClearUnsupported
+}
+
+// Ping verifica se todos os servidores estão acessíveis
+func (c *MemcachedCache) Ping(ctx context.Context) error {
+	return c.each(ctx, func(conn *memcachedConn) error {
+		return c.simpleCommand(conn, "version", "VERSION")
+	})
+}
+
+// simpleCommand envia um comando sem argumentos e confere o início da resposta
+func (c *MemcachedCache) simpleCommand(conn *memcachedConn, command, expected string) error {
+	fmt.Fprintf(conn.rw, "%s\r\n", command)
+	if err := conn.rw.Flush(); err != nil {
+		return err
+	}
+	line, err := readMemcachedLine(conn.rw.Reader)
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(line, expected) {
+		return fmt.Errorf("%w: %s", ErrMemcachedServer, line)
+	}
+	return nil
+}
+
+// store executa set ou add e indica se o valor foi armazenado
+func (c *MemcachedCache) store(ctx context.Context, command, key string, data []byte, expiration time.Duration) (bool, error) {
+	stored := false
+	err := c.do(ctx, key, func(conn *memcachedConn) error {
+		fmt.Fprintf(conn.rw, "%s %s 0 %d %d\r\n", command, memcachedKey(key), memcachedExpiration(expiration), len(data))
+		conn.rw.Write(data)
+		conn.rw.WriteString("\r\n")
+		if err := conn.rw.Flush(); err != nil {
+			return err
+		}
+		line, err := readMemcachedLine(conn.rw.Reader)
+		if err != nil {
+			return err
+		}
+		switch line {
+		case "STORED":
+			stored = true
+		case "NOT_STORED":
+		default:
+			return fmt.Errorf("%w: %s", ErrMemcachedServer, line)
+		}
+		return nil
+	})
+	return stored, err
+}
+
+// do executa a operação no servidor responsável pela chave
+func (c *MemcachedCache) do(ctx context.Context, key string, op func(conn *memcachedConn) error) error {
+	server := c.servers[0]
+	if len(c.servers) > 1 {
+		server = c.servers[crc32.ChecksumIEEE([]byte(key))%uint32(len(c.servers))]
+	}
+	return c.withConn(ctx, server, op)
+}
+
+// each executa a operação em todos os servidores
+func (c *MemcachedCache) each(ctx context.Context, op func(conn *memcachedConn) error) error {
+	for _, server := range c.servers {
+		if err := c.withConn(ctx, server, op); err != nil {
+			return fmt.Errorf("memcached %s: %w", server.addr, err)
+		}
+	}
+	return nil
+}
+
+// withConn obtém uma conexão do servidor, executa a operação e devolve a
+// conexão ao pool. Conexões com erro são fechadas.
+func (c *MemcachedCache) withConn(ctx context.Context, server *memcachedServer, op func(conn *memcachedConn) error) error {
+	conn, err := c.conn(ctx, server)
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(c.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetDeadline(deadline)
+
+	if err := op(conn); err != nil {
+		// Após um erro, a conexão pode ter dados pendentes de leitura
+		conn.Close()
+		return err
+	}
+	server.release(conn)
+	return nil
+}
+
+// conn reutiliza uma conexão ociosa ou abre uma nova
+func (c *MemcachedCache) conn(ctx context.Context, server *memcachedServer) (*memcachedConn, error) {
+	select {
+	case conn := <-server.idle:
+		return conn, nil
+	default:
+	}
+
+	dialer := net.Dialer{Timeout: c.timeout}
+	nc, err := dialer.DialContext(ctx, "tcp", server.addr)
+	if err != nil {
+		return nil, err
+	}
+	return &memcachedConn{Conn: nc, rw: bufio.NewReadWriter(bufio.NewReader(nc), bufio.NewWriter(nc))}, nil
+}
+
+// release devolve a conexão ao pool ou a fecha quando o pool está cheio
+func (s *memcachedServer) release(conn *memcachedConn) {
+	select {
+	case s.idle <- conn:
+	default:
+		conn.Close()
+	}
+}
+
+// memcachedKey adapta a chave às regras do Memcached: chaves longas ou com
+// espaços e caracteres de controle são substituídas pelo hash
+func memcachedKey(key string) string {
+	valid := len(key) > 0 && len(key) <= memcachedMaxKey
+	for i := 0; valid && i < len(key); i++ {
+		if key[i] <= ' ' || key[i] == 0x7f {
+			valid = false
+		}
+	}
+	if valid {
+		return key
+	}
+	sum := sha256.Sum256([]byte(key))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// memcachedExpiration converte a expiração para o formato do Memcached: zero
+// não expira, prazos até 30 dias são enviados em segundos e os maiores, como
+// timestamp Unix
+func memcachedExpiration(expiration time.Duration) int64 {
+	if expiration <= 0 {
+		return 0
+	}
+	if expiration > memcachedMaxRelative {
+		return time.Now().Add(expiration).Unix()
+	}
+	seconds := int64(expiration / time.Second)
+	if expiration%time.Second != 0 {
+		seconds++
+	}
+	return seconds
+}
+
+// readMemcachedLine lê uma linha de resposta e converte os erros do servidor
+func readMemcachedLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "ERROR" || strings.HasPrefix(line, "CLIENT_ERROR") || strings.HasPrefix(line, "SERVER_ERROR") {
+		return "", fmt.Errorf("%w: %s", ErrMemcachedServer, line)
+	}
+	return line, nil
+}
+
+// readMemcachedValue lê a resposta de get: VALUE <chave> <flags> <bytes>,
+// os dados e END, ou apenas END quando a chave não existe
+func readMemcachedValue(r *bufio.Reader) ([]byte, bool, error) {
+	line, err := readMemcachedLine(r)
+	if err != nil {
+		return nil, false, err
+	}
+	if line == "END" {
+		return nil, false, nil
+	}
+
+	fields := strings.Fields(line)
+	if len(fields) < 4 || fields[0] != "VALUE" {
+		return nil, false, fmt.Errorf("resposta inesperada do memcached: %q", line)
+	}
+	size, err := strconv.Atoi(fields[3])
+	if err != nil || size < 0 {
+		return nil, false, fmt.Errorf("tamanho inválido na resposta do memcached: %q", line)
+	}
+
+	data := make([]byte, size+2)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, false, err
+	}
+	if !bytes.HasSuffix(data, []byte("\r\n")) {
+		return nil, false, fmt.Errorf("dados mal formados na resposta do memcached")
+	}
+
+	end, err := readMemcachedLine(r)
+	if err != nil {
+		return nil, false, err
+	}
+	if end != "END" {
+		return nil, false, fmt.Errorf("resposta inesperada do memcached: %q", end)
+	}
+	return data[:size], true, nil
+}
@@ -0,0 +1,378 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// fakeMemcached atende o subconjunto do protocolo texto usado pelo
+// MemcachedCache. Chaves iniciadas por "erro:" recebem SERVER_ERROR.
+type fakeMemcached struct {
+	ln       net.Listener
+	mu       sync.Mutex
+	items    map[string][]byte
+	commands []string
+	conns    int
+}
+
+func newFakeMemcached(tb testing.TB) *fakeMemcached {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		tb.Fatalf("falha ao abrir o servidor memcached de teste: %v", err)
+	}
+	f := &fakeMemcached{ln: ln, items: make(map[string][]byte)}
+	go f.serve()
+	tb.Cleanup(func() { ln.Close() })
+	return f
+}
+
+func (f *fakeMemcached) addr() string {
+	return f.ln.Addr().String()
+}
+
+func (f *fakeMemcached) serve() {
+	for {
+		conn, err := f.ln.Accept()
+		if err != nil {
+			return
+		}
+		f.mu.Lock()
+		f.conns++
+		f.mu.Unlock()
+		go f.handle(conn)
+	}
+}
+
+func (f *fakeMemcached) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			fmt.Fprint(conn, "ERROR\r\n")
+			continue
+		}
+
+		f.mu.Lock()
+		f.commands = append(f.commands, fields[0])
+		f.mu.Unlock()
+
+		switch fields[0] {
+		case "version":
+			fmt.Fprint(conn, "VERSION 1.6.21\r\n")
+		case "get":
+			f.mu.Lock()
+			data, ok := f.items[fields[1]]
+			f.mu.Unlock()
+			if ok {
+				fmt.Fprintf(conn, "VALUE %s 0 %d\r\n%s\r\n", fields[1], len(data), data)
+			}
+			fmt.Fprint(conn, "END\r\n")
+		case "set", "add":
+			size, _ := strconv.Atoi(fields[4])
+			data := make([]byte, size+2)
+			if _, err := io.ReadFull(r, data); err != nil {
+				return
+			}
+			if strings.HasPrefix(fields[1], "erro:") {
+				fmt.Fprint(conn, "SERVER_ERROR out of memory storing object\r\n")
+				continue
+			}
+			f.mu.Lock()
+			_, exists := f.items[fields[1]]
+			if fields[0] == "add" && exists {
+				f.mu.Unlock()
+				fmt.Fprint(conn, "NOT_STORED\r\n")
+				continue
+			}
+			f.items[fields[1]] = data[:size]
+			f.mu.Unlock()
+			fmt.Fprint(conn, "STORED\r\n")
+		case "delete":
+			f.mu.Lock()
+			_, exists := f.items[fields[1]]
+			delete(f.items, fields[1])
+			f.mu.Unlock()
+			if exists {
+				fmt.Fprint(conn, "DELETED\r\n")
+			} else {
+				fmt.Fprint(conn, "NOT_FOUND\r\n")
+			}
+		default:
+			fmt.Fprint(conn, "ERROR\r\n")
+		}
+	}
+}
+
+func newTestMemcachedCache(tb testing.TB, servers ...*fakeMemcached) *MemcachedCache {
+	addrs := make([]string, len(servers))
+	for i, server := range servers {
+		addrs[i] = server.addr()
+	}
+	c, err := NewMemcachedCache(addrs, time.Second, 4, zap.NewNop())
+	if err != nil {
+		tb.Fatalf("NewMemcachedCache: %v", err)
+	}
+	return c
+}
+
+func TestNewMemcachedCache(t *testing.T) {
+	if _, err := NewMemcachedCache(nil, time.Second, 2, zap.NewNop()); err == nil {
+		t.Fatal("NewMemcachedCache aceitou lista de servidores vazia")
+	}
+
+	// Porta sem servidor: a verificação inicial deve falhar
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+	if _, err := NewMemcachedCache([]string{addr}, 100*time.Millisecond, 2, zap.NewNop()); err == nil {
+		t.Fatal("NewMemcachedCache aceitou servidor inacessível")
+	}
+}
+
+func TestMemcachedCacheSetGetDelete(t *testing.T) {
+	ctx := context.Background()
+	c := newTestMemcachedCache(t, newFakeMemcached(t))
+
+	type value struct {
+		Name  string
+		Count int
+	}
+	if err := c.Set(ctx, "rota", value{Name: "users", Count: 3}, time.Minute); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	var got value
+	found, err := c.Get(ctx, "rota", &got)
+	if err != nil || !found {
+		t.Fatalf("Get = %v, %v; esperado valor encontrado", found, err)
+	}
+	if got != (value{Name: "users", Count: 3}) {
+		t.Fatalf("Get retornou %+v", got)
+	}
+
+	if err := c.Delete(ctx, "rota"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if found, err := c.Get(ctx, "rota", &got); err != nil || found {
+		t.Fatalf("Get após Delete = %v, %v", found, err)
+	}
+	// Remover uma chave ausente não é erro
+	if err := c.Delete(ctx, "rota"); err != nil {
+		t.Fatalf("Delete de chave ausente: %v", err)
+	}
+}
+
+func TestMemcachedCacheAdd(t *testing.T) {
+	ctx := context.Background()
+	c := newTestMemcachedCache(t, newFakeMemcached(t))
+
+	if added, err := c.Add(ctx, "nonce", true, time.Minute); err != nil || !added {
+		t.Fatalf("primeiro Add = %v, %v; esperado true", added, err)
+	}
+	if added, err := c.Add(ctx, "nonce", true, time.Minute); err != nil || added {
+		t.Fatalf("segundo Add = %v, %v; esperado false", added, err)
+	}
+}
+
+func TestMemcachedCacheServerError(t *testing.T) {
+	ctx := context.Background()
+	server := newFakeMemcached(t)
+	c := newTestMemcachedCache(t, server)
+
+	err := c.Set(ctx, "erro:grande", "valor", time.Minute)
+	if !errors.Is(err, ErrMemcachedServer) {
+		t.Fatalf("Set = %v, esperado ErrMemcachedServer", err)
+	}
+
+	// A conexão com erro é descartada e as operações seguintes usam outra
+	if err := c.Set(ctx, "ok", "valor", time.Minute); err != nil {
+		t.Fatalf("Set após erro: %v", err)
+	}
+}
+
+func TestMemcachedCacheClearUnsupported(t *testing.T) {
+	server := newFakeMemcached(t)
+	c := newTestMemcachedCache(t, server)
+
+	if err := c.Clear(context.Background()); !errors.Is(err, ErrClearUnsupported) {
+		t.Fatalf("Clear = %v, esperado ErrClearUnsupported", err)
+	}
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	for _, command := range server.commands {
+		if command == "flush_all" {
+			t.Fatal("Clear enviou flush_all ao servidor")
+		}
+	}
+}
+
+func TestMemcachedCacheDistributesKeys(t *testing.T) {
+	ctx := context.Background()
+	servers := []*fakeMemcached{newFakeMemcached(t), newFakeMemcached(t)}
+	c := newTestMemcachedCache(t, servers...)
+
+	for i := 0; i < 50; i++ {
+		if err := c.Set(ctx, fmt.Sprintf("chave-%d", i), i, time.Minute); err != nil {
+			t.Fatalf("Set: %v", err)
+		}
+	}
+	for i := 0; i < 50; i++ {
+		var v int
+		if found, err := c.Get(ctx, fmt.Sprintf("chave-%d", i), &v); err != nil || !found || v != i {
+			t.Fatalf("Get(chave-%d) = %d, %v, %v", i, v, found, err)
+		}
+	}
+	for i, server := range servers {
+		server.mu.Lock()
+		items := len(server.items)
+		server.mu.Unlock()
+		if items == 0 {
+			t.Errorf("servidor %d não recebeu nenhuma chave", i)
+		}
+	}
+}
+
+func TestMemcachedCacheReusesConnections(t *testing.T) {
+	ctx := context.Background()
+	server := newFakeMemcached(t)
+	c := newTestMemcachedCache(t, server)
+
+	for i := 0; i < 20; i++ {
+		c.Set(ctx, "chave", i, time.Minute)
+	}
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if server.conns != 1 {
+		t.Fatalf("%d conexões abertas para operações sequenciais, esperada 1", server.conns)
+	}
+}
+
+func TestMemcachedKey(t *testing.T) {
+	long := strings.Repeat("a", memcachedMaxKey+1)
+	tests := []struct {
+		key    string
+		hashed bool
+	}{
+		{"routes:tenant-a", false},
+		{strings.Repeat("a", memcachedMaxKey), false},
+		{long, true},
+		{"com espaço", true},
+		{"quebra\nde linha", true},
+		{"", true},
+	}
+	for _, tt := range tests {
+		got := memcachedKey(tt.key)
+		if hashed := got != tt.key; hashed != tt.hashed {
+			t.Errorf("memcachedKey(%q) = %q, esperado hash: %v", tt.key, got, tt.hashed)
+		}
+		if len(got) > memcachedMaxKey || strings.ContainsAny(got, " \r\n") {
+			t.Errorf("memcachedKey(%q) = %q, chave inválida para o memcached", tt.key, got)
+		}
+	}
+	if memcachedKey(long) == memcachedKey(long+"b") {
+		t.Error("chaves diferentes produziram o mesmo hash")
+	}
+}
+
+func TestMemcachedExpiration(t *testing.T) {
+	tests := []struct {
+		expiration time.Duration
+		want       int64
+	}{
+		{0, 0},
+		{-time.Second, 0},
+		{time.Second, 1},
+		{1500 * time.Millisecond, 2},
+		{memcachedMaxRelative, int64(memcachedMaxRelative / time.Second)},
+	}
+	for _, tt := range tests {
+		if got := memcachedExpiration(tt.expiration); got != tt.want {
+			t.Errorf("memcachedExpiration(%v) = %d, esperado %d", tt.expiration, got, tt.want)
+		}
+	}
+
+	// Prazos acima de 30 dias são enviados como timestamp Unix
+	long := memcachedMaxRelative + time.Hour
+	if got, want := memcachedExpiration(long), time.Now().Add(long).Unix(); got < want-1 || got > want+1 {
+		t.Errorf("memcachedExpiration(%v) = %d, esperado timestamp próximo de %d", long, got, want)
+	}
+}
+
+func TestReadMemcachedValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     string
+		found    bool
+		wantErr  bool
+	}{
+		{"encontrado", "VALUE k 0 5\r\nvalor\r\nEND\r\n", "valor", true, false},
+		{"ausente", "END\r\n", "", false, false},
+		{"dados com quebra de linha", "VALUE k 0 7\r\nlinha\r\n\r\nEND\r\n", "linha\r\n", true, false},
+		{"tamanho menor que os dados", "VALUE k 0 3\r\nvalor\r\nEND\r\n", "", false, true},
+		{"sem END", "VALUE k 0 5\r\nvalor\r\nVALUE\r\n", "", false, true},
+		{"erro do servidor", "SERVER_ERROR falha\r\n", "", false, true},
+		{"resposta truncada", "VALUE k 0 5\r\nva", "", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, found, err := readMemcachedValue(bufio.NewReader(strings.NewReader(tt.response)))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("erro = %v, esperado erro: %v", err, tt.wantErr)
+			}
+			if found != tt.found || string(data) != tt.want {
+				t.Fatalf("readMemcachedValue = %q, %v; esperado %q, %v", data, found, tt.want, tt.found)
+			}
+		})
+	}
+}
+
+func BenchmarkMemcachedCacheGet(b *testing.B) {
+	ctx := context.Background()
+	c := newTestMemcachedCache(b, newFakeMemcached(b))
+	c.Set(ctx, "route:/api/users", map[string]string{"path": "/api/users"}, time.Minute)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	var v map[string]string
+	for i := 0; i < b.N; i++ {
+		if _, err := c.Get(ctx, "route:/api/users", &v); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkReadMemcachedValue(b *testing.B) {
+	response := "VALUE route:/api/users 0 64\r\n" + strings.Repeat("x", 64) + "\r\nEND\r\n"
+	reader := strings.NewReader(response)
+	br := bufio.NewReader(reader)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		reader.Reset(response)
+		br.Reset(reader)
+		if _, _, err := readMemcachedValue(br); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
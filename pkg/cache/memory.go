@@ -83,6 +83,7 @@ func (c *MemoryCache) Get(ctx context.Context, key string, dest interface{}) (bo
 	if !found {
 		atomic.AddInt64(&c.misses, 1)
 		updateCacheMetrics(c.hits, c.misses, "memory", c.metrics)
+		recordLookup(ctx, "memory", false)
 
 		// Registrar cache miss no span
 		span.SetAttributes(attribute.Bool("cache.hit", false))
@@ -92,6 +93,7 @@ func (c *MemoryCache) Get(ctx context.Context, key string, dest interface{}) (bo
 
 	atomic.AddInt64(&c.hits, 1)
 	updateCacheMetrics(c.hits, c.misses, "memory", c.metrics)
+	recordLookup(ctx, "memory", true)
 
 	// Registrar cache hit no span
 	span.SetAttributes(attribute.Bool("cache.hit", true))
@@ -171,6 +173,14 @@ func (c *MemoryCache) Delete(ctx context.Context, key string) error {
 	return nil
 }
 
+// Add armazena o valor apenas quando a chave ainda não existe
+func (c *MemoryCache) Add(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.cache.Add(key, value, expiration) == nil, nil
+}
+
 // Clear remove todos os valores do cache
 func (c *MemoryCache) Clear(ctx context.Context) error {
 	// Criar span para a operação de cache
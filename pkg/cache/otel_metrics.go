@@ -0,0 +1,28 @@
+package cache
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// cacheLookups conta as consultas ao cache por tipo e resultado. Sem a
+// exportação OpenTelemetry habilitada, o registro não tem efeito.
+var cacheLookups, _ = otel.Meter("api-gateway.cache").Int64Counter(
+	"api_gateway.cache.lookups",
+	metric.WithDescription("Consultas ao cache por tipo e resultado (hit ou miss)"),
+)
+
+// recordLookup registra uma consulta ao cache
+func recordLookup(ctx context.Context, cacheType string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	cacheLookups.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("cache.type", cacheType),
+		attribute.String("cache.result", result),
+	))
+}
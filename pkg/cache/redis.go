@@ -152,6 +152,7 @@ func (c *RedisCache) Get(ctx context.Context, key string, dest interface{}) (boo
 	if err != nil {
 		if err == redis.Nil {
 			// Cache miss não é erro, é comportamento normal
+			recordLookup(ctx, "redis", false)
 			span.SetStatus(codes.Ok, "cache miss")
 			span.SetAttributes(attribute.Bool("cache.hit", false))
 			return false, nil // Cache miss, não é erro
@@ -171,6 +172,7 @@ func (c *RedisCache) Get(ctx context.Context, key string, dest interface{}) (boo
 	}
 
 	// Registrar tamanho dos dados recuperados
+	recordLookup(ctx, "redis", true)
 	span.SetAttributes(
 		attribute.Bool("cache.hit", true),
 		attribute.Int("cache.data_size_bytes", len(data)),
@@ -231,6 +233,22 @@ func (c *RedisCache) Delete(ctx context.Context, key string) error {
 	return nil
 }
 
+// Add armazena o valor apenas quando a chave ainda não existe (SET NX)
+func (c *RedisCache) Add(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false, err
+	}
+	added, err := c.client.SetNX(ctx, key, data, expiration).Result()
+	if err != nil {
+		c.logger.Error("falha ao armazenar no Redis",
+			zap.String("key", key),
+			zap.Error(err))
+		return false, err
+	}
+	return added, nil
+}
+
 // Clear remove todos os valores do cache
 func (c *RedisCache) Clear(ctx context.Context) error {
 	// usar padrão "apigateway:*" como default
@@ -0,0 +1,144 @@
+// Package clientip determina o endereço do cliente quando o gateway está atrás
+// de proxies reversos ou balanceadores que acrescentam o X-Forwarded-For.
+package clientip
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// Resolver extrai o endereço do cliente da conexão e do X-Forwarded-For.
+// O cabeçalho só é considerado conforme a confiança configurada, já que o
+// cliente pode enviá-lo com qualquer valor:
+//   - depth > 0: o gateway está atrás de exatamente depth proxies; o cliente é a
+//     entrada nessa posição a partir da direita (a conexão conta como a última)
+//   - proxies confiáveis: a cadeia é percorrida da direita para a esquerda,
+//     ignorando os endereços confiáveis; o primeiro não confiável é o cliente
+//   - sem nenhum dos dois, vale apenas o endereço da conexão
+type Resolver struct {
+	trusted []netip.Prefix
+	depth   int
+}
+
+// NewResolver cria o resolvedor com os blocos CIDR (ou endereços) dos proxies
+// confiáveis e a profundidade do X-Forwarded-For
+func NewResolver(trustedProxies []string, depth int) (*Resolver, error) {
+	if depth < 0 {
+		return nil, fmt.Errorf("profundidade do X-Forwarded-For inválida: %d", depth)
+	}
+	trusted := make([]netip.Prefix, 0, len(trustedProxies))
+	for _, entry := range trustedProxies {
+		entry = strings.TrimSpace(entry)
+		if !strings.Contains(entry, "/") {
+			addr, err := netip.ParseAddr(entry)
+			if err != nil {
+				return nil, fmt.Errorf("proxy confiável inválido: %q", entry)
+			}
+			addr = addr.Unmap()
+			trusted = append(trusted, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return nil, fmt.Errorf("proxy confiável inválido: %q", entry)
+		}
+		trusted = append(trusted, prefix.Masked())
+	}
+	return &Resolver{trusted: trusted, depth: depth}, nil
+}
+
+// Resolve retorna o endereço do cliente; o endereço inválido indica que nem a
+// conexão pôde ser interpretada
+func (r *Resolver) Resolve(req *http.Request) netip.Addr {
+	remote := remoteAddr(req)
+	if r == nil || (r.depth == 0 && len(r.trusted) == 0) {
+		return remote
+	}
+
+	chain := append(forwardedFor(req), remote)
+
+	if r.depth > 0 {
+		index := len(chain) - 1 - r.depth
+		if index < 0 {
+			// A requisição não passou por todos os proxies esperados
+			return remote
+		}
+		return chain[index]
+	}
+
+	for i := len(chain) - 1; i >= 0; i-- {
+		if !chain[i].IsValid() {
+			// Entrada malformada: não é possível seguir a cadeia com segurança
+			return netip.Addr{}
+		}
+		if !r.isTrusted(chain[i]) {
+			return chain[i]
+		}
+	}
+	// Todos os saltos são confiáveis: o cliente é o primeiro da cadeia
+	return chain[0]
+}
+
+// String retorna o endereço do cliente como texto, ou vazio quando inválido
+func (r *Resolver) String(req *http.Request) string {
+	addr := r.Resolve(req)
+	if !addr.IsValid() {
+		return ""
+	}
+	return addr.String()
+}
+
+// FromTrustedProxy indica se a conexão vem de um dos proxies confiáveis, que
+// podem informar dados do cliente em cabeçalhos
+func (r *Resolver) FromTrustedProxy(req *http.Request) bool {
+	return r != nil && r.isTrusted(remoteAddr(req))
+}
+
+func (r *Resolver) isTrusted(addr netip.Addr) bool {
+	for _, prefix := range r.trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// remoteAddr interpreta o endereço da conexão
+func remoteAddr(req *http.Request) netip.Addr {
+	host, _, err := net.SplitHostPort(strings.TrimSpace(req.RemoteAddr))
+	if err != nil {
+		host = strings.TrimSpace(req.RemoteAddr)
+	}
+	return parseAddr(host)
+}
+
+// forwardedFor junta as entradas de todos os cabeçalhos X-Forwarded-For, na
+// ordem em que foram acrescentadas
+func forwardedFor(req *http.Request) []netip.Addr {
+	var chain []netip.Addr
+	for _, header := range req.Header.Values("X-Forwarded-For") {
+		for _, entry := range strings.Split(header, ",") {
+			entry = strings.TrimSpace(entry)
+			if entry == "" {
+				continue
+			}
+			chain = append(chain, parseAddr(entry))
+		}
+	}
+	return chain
+}
+
+// parseAddr aceita endereços com porta e IPv6 entre colchetes
+func parseAddr(value string) netip.Addr {
+	if addrPort, err := netip.ParseAddrPort(value); err == nil {
+		return addrPort.Addr().Unmap()
+	}
+	addr, err := netip.ParseAddr(strings.Trim(value, "[]"))
+	if err != nil {
+		return netip.Addr{}
+	}
+	return addr.WithZone("").Unmap()
+}
@@ -0,0 +1,58 @@
+package clientip
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name    string
+		trusted []string
+		depth   int
+		remote  string
+		xff     []string
+		want    string
+	}{
+		{"sem confiança ignora o cabeçalho", nil, 0, "198.51.100.1:4000", []string{"203.0.113.7"}, "198.51.100.1"},
+		{"proxy confiável", []string{"10.0.0.0/8"}, 0, "10.0.0.1:4000", []string{"203.0.113.7"}, "203.0.113.7"},
+		{"cadeia de proxies confiáveis", []string{"10.0.0.0/8"}, 0, "10.0.0.1:4000", []string{"203.0.113.7, 10.0.0.2"}, "203.0.113.7"},
+		{"entrada forjada à esquerda", []string{"10.0.0.0/8"}, 0, "10.0.0.1:4000", []string{"192.0.2.99, 203.0.113.7"}, "203.0.113.7"},
+		{"conexão não confiável", []string{"10.0.0.0/8"}, 0, "198.51.100.1:4000", []string{"203.0.113.7"}, "198.51.100.1"},
+		{"vários cabeçalhos", []string{"10.0.0.0/8"}, 0, "10.0.0.1:4000", []string{"203.0.113.7", "10.0.0.2"}, "203.0.113.7"},
+		{"todos confiáveis", []string{"10.0.0.0/8"}, 0, "10.0.0.1:4000", []string{"10.0.0.3, 10.0.0.2"}, "10.0.0.3"},
+		{"entrada malformada", []string{"10.0.0.0/8"}, 0, "10.0.0.1:4000", []string{"203.0.113.7, lixo"}, ""},
+		{"endereço único confiável", []string{"10.0.0.1"}, 0, "10.0.0.1:4000", []string{"203.0.113.7"}, "203.0.113.7"},
+		{"ipv6 com porta", []string{"2001:db8::/32"}, 0, "[2001:db8::1]:4000", []string{"[2001:db8:ffff::7]:1234, 203.0.113.7"}, "203.0.113.7"},
+		{"profundidade", nil, 1, "10.0.0.1:4000", []string{"192.0.2.99, 203.0.113.7"}, "203.0.113.7"},
+		{"profundidade maior que a cadeia", nil, 2, "10.0.0.1:4000", []string{"203.0.113.7"}, "10.0.0.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver, err := NewResolver(tt.trusted, tt.depth)
+			if err != nil {
+				t.Fatalf("NewResolver: %v", err)
+			}
+			req := httptest.NewRequest("GET", "/", nil)
+			req.RemoteAddr = tt.remote
+			for _, value := range tt.xff {
+				req.Header.Add("X-Forwarded-For", value)
+			}
+			if got := resolver.String(req); got != tt.want {
+				t.Fatalf("Resolve = %q, esperado %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewResolverRejectsInvalidConfig(t *testing.T) {
+	if _, err := NewResolver([]string{"10.0.0.0/33"}, 0); err == nil {
+		t.Fatal("bloco CIDR inválido aceito")
+	}
+	if _, err := NewResolver([]string{"proxy.interno"}, 0); err == nil {
+		t.Fatal("endereço inválido aceito")
+	}
+	if _, err := NewResolver(nil, -1); err == nil {
+		t.Fatal("profundidade negativa aceita")
+	}
+}
@@ -0,0 +1,23 @@
+package clientip
+
+import (
+	"context"
+	"net/netip"
+)
+
+type contextKey struct{}
+
+// WithAddr retorna um contexto associado ao endereço do cliente resolvido
+func WithAddr(ctx context.Context, addr netip.Addr) context.Context {
+	return context.WithValue(ctx, contextKey{}, addr)
+}
+
+// FromContext retorna o endereço do cliente associado ao contexto; false
+// quando nenhum foi resolvido
+func FromContext(ctx context.Context) (netip.Addr, bool) {
+	if ctx == nil {
+		return netip.Addr{}, false
+	}
+	addr, ok := ctx.Value(contextKey{}).(netip.Addr)
+	return addr, ok
+}
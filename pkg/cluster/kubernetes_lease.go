@@ -0,0 +1,214 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/diillson/api-gateway-go/pkg/kubeapi"
+)
+
+// microTimeLayout é o formato dos campos MicroTime da API do Kubernetes
+const microTimeLayout = "2006-01-02T15:04:05.000000Z07:00"
+
+// KubernetesLease é a liderança como um recurso Lease (coordination.k8s.io/v1),
+// dispensando o Redis para a eleição. As escritas usam o resourceVersion lido,
+// de modo que apenas uma instância vence uma disputa simultânea.
+type KubernetesLease struct {
+	client    *kubeapi.Client
+	namespace string
+	name      string
+}
+
+// leaseObject é o recurso Lease, com os campos usados na eleição. Os metadados
+// são mantidos como lidos (inclusive o resourceVersion e os rótulos), pois a
+// atualização substitui o recurso inteiro.
+type leaseObject struct {
+	APIVersion string                 `json:"apiVersion"`
+	Kind       string                 `json:"kind"`
+	Metadata   map[string]interface{} `json:"metadata"`
+	Spec       leaseSpec              `json:"spec"`
+}
+
+type leaseSpec struct {
+	HolderIdentity       *string `json:"holderIdentity,omitempty"`
+	LeaseDurationSeconds *int32  `json:"leaseDurationSeconds,omitempty"`
+	AcquireTime          string  `json:"acquireTime,omitempty"`
+	RenewTime            string  `json:"renewTime,omitempty"`
+	LeaseTransitions     *int32  `json:"leaseTransitions,omitempty"`
+}
+
+// NewKubernetesLease cria a liderança no Lease informado. Com namespace vazio,
+// usa o namespace do pod.
+func NewKubernetesLease(client *kubeapi.Client, namespace, name string) (*KubernetesLease, error) {
+	if name == "" {
+		return nil, errors.New("nome do Lease é obrigatório")
+	}
+	if namespace == "" {
+		var err error
+		if namespace, err = kubeapi.PodNamespace(); err != nil {
+			return nil, fmt.Errorf("namespace do Lease não configurado e não obtido do pod: %w", err)
+		}
+	}
+	return &KubernetesLease{client: client, namespace: namespace, name: name}, nil
+}
+
+// Acquire implementa Lease
+func (l *KubernetesLease) Acquire(ctx context.Context, holder string, ttl time.Duration) (bool, error) {
+	lease, err := l.get(ctx)
+	if errors.Is(err, kubeapi.ErrNotFound) {
+		lease = l.newLease()
+		l.hold(lease, holder, ttl, true)
+		err = l.client.Create(ctx, l.collectionPath(), l.encode(lease))
+		if errors.Is(err, kubeapi.ErrConflict) {
+			return false, nil // outra instância criou o Lease primeiro
+		}
+		return err == nil, err
+	}
+	if err != nil {
+		return false, err
+	}
+
+	current := leaseHolder(lease)
+	if current != "" && current != holder && !leaseExpired(lease, time.Now()) {
+		return false, nil
+	}
+	l.hold(lease, holder, ttl, current != holder)
+	return l.update(ctx, lease)
+}
+
+// Renew implementa Lease
+func (l *KubernetesLease) Renew(ctx context.Context, holder string, ttl time.Duration) (bool, error) {
+	lease, err := l.get(ctx)
+	if errors.Is(err, kubeapi.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if leaseHolder(lease) != holder {
+		return false, nil
+	}
+	l.hold(lease, holder, ttl, false)
+	return l.update(ctx, lease)
+}
+
+// Release implementa Lease
+func (l *KubernetesLease) Release(ctx context.Context, holder string) error {
+	lease, err := l.get(ctx)
+	if errors.Is(err, kubeapi.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if leaseHolder(lease) != holder {
+		return nil
+	}
+
+	// Sem titular e com duração mínima, outra instância assume no próximo
+	// heartbeat
+	empty, seconds := "", int32(1)
+	lease.Spec.HolderIdentity = &empty
+	lease.Spec.LeaseDurationSeconds = &seconds
+	_, err = l.update(ctx, lease)
+	return err
+}
+
+// Holder implementa Lease
+func (l *KubernetesLease) Holder(ctx context.Context) (string, error) {
+	lease, err := l.get(ctx)
+	if errors.Is(err, kubeapi.ErrNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("falha ao consultar líder do cluster: %w", err)
+	}
+	if leaseExpired(lease, time.Now()) {
+		return "", nil
+	}
+	return leaseHolder(lease), nil
+}
+
+// get lê o Lease atual
+func (l *KubernetesLease) get(ctx context.Context) (*leaseObject, error) {
+	resp, err := l.client.Get(ctx, l.collectionPath()+"/"+l.name, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var lease leaseObject
+	if err := json.NewDecoder(resp.Body).Decode(&lease); err != nil {
+		return nil, fmt.Errorf("recurso Lease inválido: %w", err)
+	}
+	return &lease, nil
+}
+
+// update grava o Lease; um conflito indica que outra instância o alterou
+func (l *KubernetesLease) update(ctx context.Context, lease *leaseObject) (bool, error) {
+	err := l.client.Update(ctx, l.collectionPath()+"/"+l.name, l.encode(lease))
+	if errors.Is(err, kubeapi.ErrConflict) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// hold marca a instância como titular, renovando o prazo
+func (l *KubernetesLease) hold(lease *leaseObject, holder string, ttl time.Duration, transition bool) {
+	now := time.Now().UTC().Format(microTimeLayout)
+	seconds := int32((ttl + time.Second - 1) / time.Second)
+	lease.Spec.HolderIdentity = &holder
+	lease.Spec.LeaseDurationSeconds = &seconds
+	lease.Spec.RenewTime = now
+	if transition {
+		lease.Spec.AcquireTime = now
+		transitions := int32(0)
+		if lease.Spec.LeaseTransitions != nil {
+			transitions = *lease.Spec.LeaseTransitions + 1
+		}
+		lease.Spec.LeaseTransitions = &transitions
+	}
+}
+
+// newLease monta um Lease ainda não criado
+func (l *KubernetesLease) newLease() *leaseObject {
+	return &leaseObject{
+		APIVersion: "coordination.k8s.io/v1",
+		Kind:       "Lease",
+		Metadata:   map[string]interface{}{"name": l.name, "namespace": l.namespace},
+	}
+}
+
+func (l *KubernetesLease) encode(lease *leaseObject) []byte {
+	data, _ := json.Marshal(lease) // a estrutura sempre é serializável
+	return data
+}
+
+func (l *KubernetesLease) collectionPath() string {
+	return "/apis/coordination.k8s.io/v1/namespaces/" + l.namespace + "/leases"
+}
+
+// leaseHolder retorna o titular registrado no Lease
+func leaseHolder(lease *leaseObject) string {
+	if lease.Spec.HolderIdentity == nil {
+		return ""
+	}
+	return *lease.Spec.HolderIdentity
+}
+
+// leaseExpired indica se o titular deixou de renovar o Lease no prazo. O prazo
+// é medido pelo relógio desta instância; relógios dessincronizados antecipam
+// ou atrasam a troca de líder.
+func leaseExpired(lease *leaseObject, now time.Time) bool {
+	if lease.Spec.LeaseDurationSeconds == nil || lease.Spec.RenewTime == "" {
+		return true
+	}
+	renewed, err := time.Parse(time.RFC3339Nano, lease.Spec.RenewTime)
+	if err != nil {
+		return true
+	}
+	return now.After(renewed.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second))
+}
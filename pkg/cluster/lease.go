@@ -0,0 +1,71 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Lease é o mecanismo que garante um único líder no cluster. A liderança
+// pertence a uma instância até expirar sem renovação ou ser liberada.
+type Lease interface {
+	// Acquire obtém a liderança se ela estiver livre ou expirada
+	Acquire(ctx context.Context, holder string, ttl time.Duration) (bool, error)
+	// Renew prolonga a liderança; false indica que ela pertence a outra instância
+	Renew(ctx context.Context, holder string, ttl time.Duration) (bool, error)
+	// Release libera a liderança se ela ainda pertence à instância
+	Release(ctx context.Context, holder string) error
+	// Holder retorna o líder atual, ou vazio se não houver
+	Holder(ctx context.Context) (string, error)
+}
+
+// renewLeaseScript renova a liderança apenas se ela ainda pertence à instância
+var renewLeaseScript = redis.NewScript(`
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("pexpire", KEYS[1], ARGV[2])
+end
+return 0`)
+
+// releaseLeaseScript libera a liderança apenas se ela ainda pertence à instância
+var releaseLeaseScript = redis.NewScript(`
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("del", KEYS[1])
+end
+return 0`)
+
+// redisLease é a liderança como uma chave do Redis (SET NX PX)
+type redisLease struct {
+	client *redis.Client
+	key    string
+}
+
+// Acquire implementa Lease
+func (l *redisLease) Acquire(ctx context.Context, holder string, ttl time.Duration) (bool, error) {
+	return l.client.SetNX(ctx, l.key, holder, ttl).Result()
+}
+
+// Renew implementa Lease
+func (l *redisLease) Renew(ctx context.Context, holder string, ttl time.Duration) (bool, error) {
+	renewed, err := renewLeaseScript.Run(ctx, l.client, []string{l.key}, holder, ttl.Milliseconds()).Int()
+	return renewed != 0, err
+}
+
+// Release implementa Lease
+func (l *redisLease) Release(ctx context.Context, holder string) error {
+	return releaseLeaseScript.Run(ctx, l.client, []string{l.key}, holder).Err()
+}
+
+// Holder implementa Lease
+func (l *redisLease) Holder(ctx context.Context) (string, error) {
+	leader, err := l.client.Get(ctx, l.key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("falha ao consultar líder do cluster: %w", err)
+	}
+	return leader, nil
+}
@@ -0,0 +1,78 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// MessageHandler trata uma mensagem publicada por outra instância do cluster
+type MessageHandler func(payload []byte)
+
+// envelope é o formato das mensagens trocadas entre as instâncias
+type envelope struct {
+	Origin  string          `json:"origin"`
+	Topic   string          `json:"topic"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// Publish envia uma mensagem aos demais membros do cluster. A entrega é de
+// melhor esforço: membros desconectados no momento perdem a mensagem.
+func (n *Node) Publish(ctx context.Context, topic string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("falha ao serializar mensagem do cluster: %w", err)
+	}
+	message, err := json.Marshal(envelope{Origin: n.cfg.NodeID, Topic: topic, Payload: data})
+	if err != nil {
+		return fmt.Errorf("falha ao serializar mensagem do cluster: %w", err)
+	}
+	if err := n.client.Publish(ctx, n.key("messages"), message).Err(); err != nil {
+		return fmt.Errorf("falha ao publicar mensagem no cluster: %w", err)
+	}
+	return nil
+}
+
+// Subscribe registra um tratador para as mensagens do tópico enviadas por
+// outras instâncias. Deve ser chamado antes de Start.
+func (n *Node) Subscribe(topic string, handler MessageHandler) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.handlers[topic] = append(n.handlers[topic], handler)
+}
+
+// listen recebe as mensagens do cluster até o contexto ser cancelado
+func (n *Node) listen(ctx context.Context) {
+	pubsub := n.client.Subscribe(ctx, n.key("messages"))
+	defer pubsub.Close()
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+
+			var env envelope
+			if err := json.Unmarshal([]byte(msg.Payload), &env); err != nil {
+				n.logger.Warn("Mensagem do cluster inválida", zap.Error(err))
+				continue
+			}
+			if env.Origin == n.cfg.NodeID {
+				continue
+			}
+
+			n.mu.RLock()
+			handlers := n.handlers[env.Topic]
+			n.mu.RUnlock()
+			for _, handler := range handlers {
+				handler(env.Payload)
+			}
+		}
+	}
+}
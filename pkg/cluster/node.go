@@ -0,0 +1,382 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+)
+
+// Config configura a participação da instância no cluster
+type Config struct {
+	Name              string        // Instâncias com o mesmo nome formam um cluster
+	NodeID            string        // Identificador único da instância
+	Address           string        // Endereço anunciado aos demais membros (informativo)
+	HeartbeatInterval time.Duration // Frequência do heartbeat e da renovação da liderança
+	MemberTTL         time.Duration // Sem heartbeat por este período, o membro é considerado ausente
+	LeaseTTL          time.Duration // Validade da liderança sem renovação
+}
+
+// Member descreve uma instância do cluster
+type Member struct {
+	ID        string    `json:"id"`
+	Address   string    `json:"address,omitempty"`
+	StartedAt time.Time `json:"started_at"`
+	LastSeen  time.Time `json:"last_seen"`
+	Leader    bool      `json:"leader"`
+	Self      bool      `json:"self"`
+}
+
+// LeadershipFunc é chamada quando a instância assume ou perde a liderança
+type LeadershipFunc func(leader bool)
+
+// Node representa esta instância no cluster. A descoberta dos membros usa
+// heartbeats no Redis e a liderança é um lease (por padrão SET NX PX no Redis)
+// renovado a cada heartbeat; apenas o líder executa as tarefas que devem rodar
+// uma única vez.
+type Node struct {
+	client    *redis.Client
+	lease     Lease
+	cfg       Config
+	logger    *zap.Logger
+	startedAt time.Time
+
+	mu           sync.RWMutex
+	leader       bool
+	lastRenewal  time.Time
+	onLeadership []LeadershipFunc
+	handlers     map[string][]MessageHandler
+	jobs         map[string]bool // tarefas exclusivas do líder -> em execução nesta instância
+}
+
+// NewNode cria a representação da instância no cluster
+func NewNode(client *redis.Client, cfg Config, logger *zap.Logger) (*Node, error) {
+	if cfg.Name == "" || cfg.NodeID == "" {
+		return nil, errors.New("nome do cluster e identificador da instância são obrigatórios")
+	}
+	if cfg.HeartbeatInterval <= 0 {
+		cfg.HeartbeatInterval = 2 * time.Second
+	}
+	if cfg.MemberTTL <= cfg.HeartbeatInterval {
+		cfg.MemberTTL = 5 * cfg.HeartbeatInterval
+	}
+	if cfg.LeaseTTL <= cfg.HeartbeatInterval {
+		cfg.LeaseTTL = 5 * cfg.HeartbeatInterval
+	}
+
+	n := &Node{
+		client:    client,
+		cfg:       cfg,
+		logger:    logger.With(zap.String("cluster", cfg.Name), zap.String("node", cfg.NodeID)),
+		startedAt: time.Now(),
+		handlers:  make(map[string][]MessageHandler),
+		jobs:      make(map[string]bool),
+	}
+	n.lease = &redisLease{client: client, key: n.key("leader")}
+	return n, nil
+}
+
+// SetLease substitui o mecanismo de liderança (ex.: Lease do Kubernetes). Deve
+// ser chamado antes de Start.
+func (n *Node) SetLease(lease Lease) {
+	n.lease = lease
+}
+
+// ID retorna o identificador da instância
+func (n *Node) ID() string {
+	return n.cfg.NodeID
+}
+
+// IsLeader indica se esta instância detém a liderança
+func (n *Node) IsLeader() bool {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.leader
+}
+
+// OnLeadershipChange registra uma função chamada a cada mudança de liderança
+func (n *Node) OnLeadershipChange(fn LeadershipFunc) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.onLeadership = append(n.onLeadership, fn)
+}
+
+// Start participa do cluster até o contexto ser cancelado
+func (n *Node) Start(ctx context.Context) {
+	n.logger.Info("Participação no cluster iniciada",
+		zap.Duration("heartbeat", n.cfg.HeartbeatInterval),
+		zap.Duration("leaseTTL", n.cfg.LeaseTTL))
+
+	go n.listen(ctx)
+
+	ticker := time.NewTicker(n.cfg.HeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := n.heartbeat(ctx); err != nil && ctx.Err() == nil {
+			n.logger.Warn("Falha ao enviar heartbeat do cluster", zap.Error(err))
+		}
+		n.campaign(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Leave remove a instância do cluster e libera a liderança, permitindo que
+// outra instância assuma sem esperar a expiração do lease
+func (n *Node) Leave(ctx context.Context) error {
+	n.setLeader(false)
+
+	pipe := n.client.TxPipeline()
+	pipe.ZRem(ctx, n.key("members"), n.cfg.NodeID)
+	pipe.Del(ctx, n.memberKey(n.cfg.NodeID))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("falha ao remover instância do cluster: %w", err)
+	}
+	if err := n.lease.Release(ctx, n.cfg.NodeID); err != nil {
+		return fmt.Errorf("falha ao liberar liderança do cluster: %w", err)
+	}
+
+	n.logger.Info("Instância removida do cluster")
+	return nil
+}
+
+// heartbeat anuncia a instância e descarta membros sem heartbeat recente
+func (n *Node) heartbeat(ctx context.Context) error {
+	now := time.Now()
+	data, err := json.Marshal(Member{
+		ID:        n.cfg.NodeID,
+		Address:   n.cfg.Address,
+		StartedAt: n.startedAt,
+		LastSeen:  now,
+	})
+	if err != nil {
+		return err
+	}
+
+	pipe := n.client.TxPipeline()
+	pipe.Set(ctx, n.memberKey(n.cfg.NodeID), data, n.cfg.MemberTTL)
+	pipe.ZAdd(ctx, n.key("members"), &redis.Z{Score: float64(now.UnixMilli()), Member: n.cfg.NodeID})
+	pipe.ZRemRangeByScore(ctx, n.key("members"), "-inf",
+		fmt.Sprintf("(%d", now.Add(-n.cfg.MemberTTL).UnixMilli()))
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// campaign renova a liderança atual ou tenta adquiri-la
+func (n *Node) campaign(ctx context.Context) {
+	now := time.Now()
+
+	if n.IsLeader() {
+		renewed, err := n.lease.Renew(ctx, n.cfg.NodeID, n.cfg.LeaseTTL)
+		switch {
+		case err != nil:
+			// Sem contato com o Redis, a liderança só é mantida enquanto o lease
+			// certamente não expirou, evitando dois líderes simultâneos
+			n.mu.RLock()
+			expired := now.Sub(n.lastRenewal) >= n.cfg.LeaseTTL
+			n.mu.RUnlock()
+			if expired {
+				n.logger.Warn("Liderança abandonada por falha na renovação do lease", zap.Error(err))
+				n.setLeader(false)
+			}
+		case !renewed:
+			n.logger.Warn("Liderança perdida para outra instância")
+			n.setLeader(false)
+		default:
+			n.mu.Lock()
+			n.lastRenewal = now
+			n.mu.Unlock()
+		}
+		return
+	}
+
+	acquired, err := n.lease.Acquire(ctx, n.cfg.NodeID, n.cfg.LeaseTTL)
+	if err != nil {
+		if ctx.Err() == nil {
+			n.logger.Warn("Falha ao disputar liderança do cluster", zap.Error(err))
+		}
+		return
+	}
+	if acquired {
+		n.mu.Lock()
+		n.lastRenewal = now
+		n.mu.Unlock()
+		n.setLeader(true)
+	}
+}
+
+// setLeader atualiza o estado de liderança e notifica os interessados
+func (n *Node) setLeader(leader bool) {
+	n.mu.Lock()
+	if n.leader == leader {
+		n.mu.Unlock()
+		return
+	}
+	n.leader = leader
+	callbacks := n.onLeadership
+	n.mu.Unlock()
+
+	if leader {
+		n.logger.Info("Instância assumiu a liderança do cluster")
+	} else {
+		n.logger.Info("Instância deixou a liderança do cluster")
+	}
+	for _, fn := range callbacks {
+		fn(leader)
+	}
+}
+
+// Leader retorna o identificador do líder atual, ou vazio se não houver
+func (n *Node) Leader(ctx context.Context) (string, error) {
+	return n.lease.Holder(ctx)
+}
+
+// Members retorna as instâncias ativas do cluster, ordenadas pelo identificador
+func (n *Node) Members(ctx context.Context) ([]Member, error) {
+	since := time.Now().Add(-n.cfg.MemberTTL).UnixMilli()
+	ids, err := n.client.ZRangeByScore(ctx, n.key("members"), &redis.ZRangeBy{
+		Min: fmt.Sprintf("%d", since),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar membros do cluster: %w", err)
+	}
+
+	leader, err := n.Leader(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]Member, 0, len(ids))
+	if len(ids) == 0 {
+		return members, nil
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = n.memberKey(id)
+	}
+	values, err := n.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter membros do cluster: %w", err)
+	}
+
+	for i, value := range values {
+		raw, ok := value.(string)
+		if !ok {
+			continue // membro expirou entre as consultas
+		}
+		var member Member
+		if err := json.Unmarshal([]byte(raw), &member); err != nil {
+			n.logger.Warn("Registro de membro do cluster inválido",
+				zap.String("member", ids[i]), zap.Error(err))
+			continue
+		}
+		member.Leader = member.ID == leader
+		member.Self = member.ID == n.cfg.NodeID
+		members = append(members, member)
+	}
+
+	sort.Slice(members, func(i, j int) bool { return members[i].ID < members[j].ID })
+	return members, nil
+}
+
+// RunAsLeader executa fn enquanto esta instância for líder. O contexto passado
+// a fn é cancelado quando a liderança é perdida, e fn é iniciada novamente se
+// a liderança for reconquistada.
+func (n *Node) RunAsLeader(ctx context.Context, job string, fn func(ctx context.Context)) {
+	n.setJobRunning(job, false)
+	changes := make(chan bool, 1)
+	n.OnLeadershipChange(func(leader bool) {
+		// Manter apenas a mudança mais recente
+		select {
+		case <-changes:
+		default:
+		}
+		changes <- leader
+	})
+
+	go func() {
+		var cancel context.CancelFunc
+		var done chan struct{}
+
+		start := func() {
+			var jobCtx context.Context
+			jobCtx, cancel = context.WithCancel(ctx)
+			done = make(chan struct{})
+			n.logger.Info("Tarefa exclusiva do líder iniciada", zap.String("job", job))
+			n.setJobRunning(job, true)
+			go func() {
+				defer close(done)
+				fn(jobCtx)
+			}()
+		}
+		stop := func() {
+			if cancel == nil {
+				return
+			}
+			cancel()
+			<-done
+			cancel = nil
+			n.setJobRunning(job, false)
+			n.logger.Info("Tarefa exclusiva do líder encerrada", zap.String("job", job))
+		}
+
+		if n.IsLeader() {
+			start()
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				stop()
+				return
+			case leader := <-changes:
+				if leader && cancel == nil {
+					start()
+				} else if !leader {
+					stop()
+				}
+			}
+		}
+	}()
+}
+
+// Jobs retorna as tarefas exclusivas do líder registradas e se cada uma está em
+// execução nesta instância
+func (n *Node) Jobs() map[string]bool {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	jobs := make(map[string]bool, len(n.jobs))
+	for job, running := range n.jobs {
+		jobs[job] = running
+	}
+	return jobs
+}
+
+// setJobRunning registra o estado da tarefa exclusiva do líder
+func (n *Node) setJobRunning(job string, running bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.jobs[job] = running
+}
+
+// key monta uma chave do Redis no espaço do cluster
+func (n *Node) key(name string) string {
+	return "cluster:" + n.cfg.Name + ":" + name
+}
+
+// memberKey monta a chave do registro de um membro
+func (n *Node) memberKey(id string) string {
+	return n.key("member:" + id)
+}
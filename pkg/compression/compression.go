@@ -0,0 +1,207 @@
+package compression
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Codificações de conteúdo suportadas
+const (
+	Identity = "identity"
+	Gzip     = "gzip"
+	Deflate  = "deflate"
+	Brotli   = "br"
+	Zstd     = "zstd"
+)
+
+// Supported lista as codificações suportadas, na ordem de preferência do gateway
+var Supported = []string{Brotli, Zstd, Gzip, Deflate}
+
+// ErrTooLarge indica que o conteúdo descomprimido excede o limite informado
+var ErrTooLarge = errors.New("conteúdo descomprimido excede o limite")
+
+// IsSupported indica se a codificação pode ser comprimida e descomprimida
+func IsSupported(encoding string) bool {
+	switch normalize(encoding) {
+	case Identity, Gzip, Deflate, Brotli, Zstd:
+		return true
+	default:
+		return false
+	}
+}
+
+// Negotiate escolhe a codificação da resposta a partir do cabeçalho Accept-Encoding,
+// respeitando os pesos (q) do cliente e, em caso de empate, a ordem de supported.
+// Retorna Identity quando nenhuma codificação suportada é aceita.
+func Negotiate(acceptEncoding string, supported []string) string {
+	if strings.TrimSpace(acceptEncoding) == "" {
+		return Identity
+	}
+
+	weights := make(map[string]float64)
+	wildcard := -1.0
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, q := parseCoding(part)
+		if name == "" {
+			continue
+		}
+		if name == "*" {
+			wildcard = q
+			continue
+		}
+		weights[name] = q
+	}
+
+	best := Identity
+	bestQ := 0.0
+	for _, encoding := range supported {
+		q, ok := weights[encoding]
+		if !ok {
+			if wildcard < 0 {
+				continue
+			}
+			q = wildcard
+		}
+		if q > bestQ {
+			best, bestQ = encoding, q
+		}
+	}
+	return best
+}
+
+// parseCoding interpreta um item do Accept-Encoding, como "gzip;q=0.8"
+func parseCoding(part string) (string, float64) {
+	fields := strings.Split(part, ";")
+	name := normalize(fields[0])
+	q := 1.0
+	for _, param := range fields[1:] {
+		param = strings.TrimSpace(param)
+		if !strings.HasPrefix(param, "q=") {
+			continue
+		}
+		if parsed, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64); err == nil {
+			q = parsed
+		}
+	}
+	return name, q
+}
+
+// normalize padroniza o nome da codificação
+func normalize(encoding string) string {
+	encoding = strings.ToLower(strings.TrimSpace(encoding))
+	switch encoding {
+	case "", Identity:
+		return Identity
+	case "x-gzip":
+		return Gzip
+	default:
+		return encoding
+	}
+}
+
+// Writer comprime o conteúdo escrito. Flush envia ao destino o que já foi
+// comprimido, para respostas entregues aos poucos.
+type Writer interface {
+	io.WriteCloser
+	Flush() error
+}
+
+// NewWriter cria o compressor da codificação informada, escrevendo em w
+func NewWriter(encoding string, w io.Writer) (Writer, error) {
+	switch normalize(encoding) {
+	case Gzip:
+		return gzip.NewWriter(w), nil
+	case Deflate:
+		// "deflate" no HTTP é o formato zlib (RFC 9110)
+		return zlib.NewWriter(w), nil
+	case Brotli:
+		return brotli.NewWriter(w), nil
+	case Zstd:
+		return zstd.NewWriter(w)
+	default:
+		return nil, fmt.Errorf("codificação não suportada: %s", encoding)
+	}
+}
+
+// Encode comprime os dados na codificação informada
+func Encode(encoding string, data []byte) ([]byte, error) {
+	if normalize(encoding) == Identity {
+		return data, nil
+	}
+
+	var buf bytes.Buffer
+	writer, err := NewWriter(encoding, &buf)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := writer.Write(data); err != nil {
+		return nil, fmt.Errorf("falha ao comprimir com %s: %w", encoding, err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("falha ao finalizar compressão %s: %w", encoding, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Decode descomprime os dados, limitando o resultado a maxBytes para evitar
+// bombas de descompressão. Com maxBytes <= 0 não há limite.
+func Decode(encoding string, data []byte, maxBytes int64) ([]byte, error) {
+	var reader io.Reader
+
+	switch normalize(encoding) {
+	case Identity:
+		if maxBytes > 0 && int64(len(data)) > maxBytes {
+			return nil, ErrTooLarge
+		}
+		return data, nil
+	case Gzip:
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("falha ao ler conteúdo gzip: %w", err)
+		}
+		defer r.Close()
+		reader = r
+	case Deflate:
+		// Alguns servidores enviam deflate sem o envelope zlib
+		r, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			r = flate.NewReader(bytes.NewReader(data))
+		}
+		defer r.Close()
+		reader = r
+	case Brotli:
+		reader = brotli.NewReader(bytes.NewReader(data))
+	case Zstd:
+		r, err := zstd.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("falha ao ler conteúdo zstd: %w", err)
+		}
+		defer r.Close()
+		reader = r
+	default:
+		return nil, fmt.Errorf("codificação não suportada: %s", encoding)
+	}
+
+	if maxBytes > 0 {
+		reader = io.LimitReader(reader, maxBytes+1)
+	}
+
+	decoded, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao descomprimir %s: %w", encoding, err)
+	}
+	if maxBytes > 0 && int64(len(decoded)) > maxBytes {
+		return nil, ErrTooLarge
+	}
+	return decoded, nil
+}
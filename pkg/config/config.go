@@ -2,37 +2,154 @@ package config
 
 import (
 	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/spf13/viper"
+	"go.uber.org/zap/zapcore"
 )
 
 // Config representa a configuração completa da aplicação
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Cache    CacheConfig
-	Auth     AuthConfig
-	Metrics  MetricsConfig
-	Logging  LoggingConfig
-	Tracing  TracingConfig
-	Features FeaturesConfig
+	Server      ServerConfig
+	Database    DatabaseConfig
+	Cache       CacheConfig
+	Auth        AuthConfig
+	Metrics     MetricsConfig
+	Logging     LoggingConfig
+	Tracing     TracingConfig
+	Features    FeaturesConfig
+	Security    SecurityConfig
+	Upstream    UpstreamConfig
+	Admin       AdminConfig
+	Cluster     ClusterConfig
+	Quotas      QuotasConfig
+	RateLimit   RateLimitConfig
+	Idempotency IdempotencyConfig
+	Recording   RecordingConfig
+	Geo         GeoConfig
+	Flags       FeatureFlagsConfig
+	Health      HealthConfig
+	Routes      RoutesConfig
+	OpenAPI     OpenAPIConfig
 }
 
 // ServerConfig contém configurações do servidor HTTP
 type ServerConfig struct {
-	Port           int
-	Host           string
-	ReadTimeout    time.Duration
-	WriteTimeout   time.Duration
-	IdleTimeout    time.Duration
-	MaxHeaderBytes int
-	TLS            bool
-	CertFile       string
-	KeyFile        string
-	BaseURL        string
-	Domains        []string
+	Port            int
+	Host            string
+	ReadTimeout     time.Duration
+	WriteTimeout    time.Duration
+	IdleTimeout     time.Duration
+	MaxHeaderBytes  int
+	TLS             bool
+	H2C             bool // Aceita HTTP/2 sem TLS, necessário para clientes gRPC sem TLS
+	CertFile        string
+	KeyFile         string
+	BaseURL         string
+	Domains         []string
+	ACME            ACMEConfig
+	Limits          RequestLimitsConfig
+	SecurityHeaders SecurityHeadersConfig
+	Compression     CompressionConfig
+	HTTP3           HTTP3Config
+	Overload        OverloadConfig
+	// Proxies (CIDR ou endereço) cujo X-Forwarded-For é aceito na identificação do cliente
+	TrustedProxies []string
+	// Número fixo de proxies à frente do gateway; > 0 tem precedência sobre TrustedProxies
+	ForwardedForDepth int
+	// Cabeçalho do identificador da requisição, gerado quando ausente
+	RequestIDHeader string
+}
+
+// HTTP3Config habilita o listener HTTP/3 (QUIC) ao lado do HTTPS, anunciado
+// aos clientes pelo cabeçalho Alt-Svc. Requer server.tls e um binário
+// compilado com a tag http3.
+type HTTP3Config struct {
+	Enabled      bool
+	Port         int           // Porta UDP; 0 usa a mesma porta do HTTPS
+	AltSvcMaxAge time.Duration // Por quanto tempo os clientes lembram do anúncio
+}
+
+// OverloadConfig protege o gateway de sobrecarga com um limite adaptativo de
+// requisições simultâneas às rotas: o limite cresce enquanto a latência se
+// mantém e é reduzido quando ela sobe. Acima do limite, as requisições
+// aguardam na fila e, com a fila cheia, recebem 503 com Retry-After.
+type OverloadConfig struct {
+	Enabled       bool
+	InitialLimit  int           // limite inicial de requisições simultâneas
+	MinLimit      int           // limite mínimo
+	MaxLimit      int           // limite máximo
+	TargetLatency time.Duration // latência acima da qual o limite é reduzido; 0 usa tolerance sobre a latência mínima
+	Tolerance     float64       // múltiplo da latência mínima aceito antes de reduzir o limite
+	BackoffRatio  float64       // fator aplicado ao limite em cada redução
+	MaxQueue      int           // requisições aguardando vaga
+	QueueTimeout  time.Duration // espera máxima na fila
+	RetryAfter    time.Duration // informada no Retry-After das requisições descartadas
+}
+
+// ACMEConfig configura a emissão e a renovação automáticas de certificados
+// (Let's Encrypt) para os domínios de server.domains
+type ACMEConfig struct {
+	Email        string        // Contato da conta ACME; vazio usa LETSENCRYPT_EMAIL
+	DirectoryURL string        // Diretório ACME; vazio usa o Let's Encrypt de produção
+	Storage      string        // dir (padrão), cache ou database; cache (Redis) e database compartilham os certificados entre instâncias
+	CacheDir     string        // Diretório dos certificados com storage "dir"
+	RenewBefore  time.Duration // Antecedência da renovação em relação à expiração
+}
+
+// RequestLimitsConfig contém limites aplicados ao cabeçalho e à linha de requisição
+type RequestLimitsConfig struct {
+	Enabled             bool
+	MaxHeaderCount      int  // número máximo de cabeçalhos
+	MaxHeaderValueBytes int  // tamanho máximo de um valor de cabeçalho
+	MaxURLLength        int  // tamanho máximo da URI requisitada
+	RejectSmuggling     bool // rejeitar Transfer-Encoding/Content-Length ambíguos e Connection listando cabeçalhos
+}
+
+// SecurityHeadersConfig define os cabeçalhos de segurança incluídos nas
+// respostas e os cabeçalhos internos retirados das respostas dos backends.
+// Os cabeçalhos definidos aqui prevalecem sobre os enviados pelo backend.
+type SecurityHeadersConfig struct {
+	Enabled            bool
+	HSTS               HSTSConfig
+	FrameOptions       string // X-Frame-Options: DENY, SAMEORIGIN ou vazio para omitir
+	ContentTypeOptions bool   // X-Content-Type-Options: nosniff
+	ReferrerPolicy     string // Vazio omite o cabeçalho
+	PermissionsPolicy  string // Vazio omite o cabeçalho
+	// ContentSecurityPolicy é o modelo da CSP; vazio omite o cabeçalho.
+	// {nonce} é trocado por um valor aleatório por requisição, repassado ao
+	// backend em NonceHeader, e {host} pelo host requisitado.
+	ContentSecurityPolicy string
+	NonceHeader           string
+	// Cabeçalhos retirados das respostas dos backends, como os que revelam
+	// o servidor ou a tecnologia usada
+	StripResponseHeaders []string
+}
+
+// HSTSConfig controla o Strict-Transport-Security, enviado apenas nas
+// requisições recebidas por HTTPS
+type HSTSConfig struct {
+	Enabled           bool
+	MaxAge            time.Duration
+	IncludeSubDomains bool
+	Preload           bool
+}
+
+// CompressionConfig controla a compressão das respostas das rotas, negociada
+// pelo Accept-Encoding do cliente. Respostas já comprimidas pelo backend são
+// repassadas sem alteração.
+type CompressionConfig struct {
+	Enabled      bool
+	Encodings    []string // Codificações oferecidas, na ordem de preferência: br, zstd, gzip, deflate
+	MinSize      int      // Tamanho mínimo da resposta, em bytes, para comprimir
+	ContentTypes []string // Tipos MIME comprimidos; "text/*" cobre todos os subtipos
 }
 
 // DatabaseConfig contém configurações do banco de dados
@@ -42,10 +159,34 @@ type DatabaseConfig struct {
 	MaxIdleConns    int
 	MaxOpenConns    int
 	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+	PingTimeout     time.Duration // timeout do ping usado no readiness
+	QueryTimeout    time.Duration // prazo de cada operação no banco; 0 desabilita
 	LogLevel        string
 	SlowThreshold   time.Duration
-	MigrationDir    string
+	MigrationDir    string // vazio usa as migrações embutidas no binário
 	SkipMigrations  bool
+
+	// Sharding por tenant: o banco principal é o shard "primary"
+	Shards                []DatabaseShardConfig
+	TenantHeader          string             // cabeçalho que identifica o tenant nas APIs administrativas
+	TenantHosts           []TenantHostConfig // hosts que identificam o tenant no plano de dados
+	HashUnassignedTenants bool               // distribui tenants sem shard explícito por hash
+}
+
+// TenantHostConfig associa um host das requisições do plano de dados a um
+// tenant. Requisições para outros hosts usam o escopo padrão.
+type TenantHostConfig struct {
+	Host   string
+	Tenant string
+}
+
+// DatabaseShardConfig descreve um banco adicional que armazena as rotas de um grupo de tenants
+type DatabaseShardConfig struct {
+	Name    string
+	Driver  string
+	DSN     string
+	Tenants []string // tenants atribuídos explicitamente a este shard
 }
 
 // RedisOptions contém configurações específicas para Redis
@@ -68,11 +209,31 @@ type RedisOptions struct {
 // CacheConfig contém configurações do cache
 type CacheConfig struct {
 	Enabled     bool
-	Type        string // redis, memory
+	Type        string // memory, local, redis ou memcached
 	TTL         time.Duration
-	MaxItems    int // apenas para cache em memória
-	MaxMemoryMB int // apenas para cache em memória
-	Redis       RedisOptions
+	MaxItems    int // apenas para cache local
+	MaxMemoryMB int // apenas para cache local
+	// Tempo em que a lista de rotas expirada ainda é servida enquanto é
+	// recarregada em segundo plano; zero desativa
+	StaleWhileRevalidate time.Duration
+	Redis                RedisOptions
+	Memcached            MemcachedOptions
+	Response             ResponseCacheConfig
+}
+
+// MemcachedOptions contém as configurações do cache Memcached
+type MemcachedOptions struct {
+	Servers      []string      // Servidores (host:porta); as chaves são distribuídas pelo hash
+	Timeout      time.Duration // Prazo de conexão e de cada operação
+	MaxIdleConns int           // Conexões ociosas mantidas por servidor
+}
+
+// ResponseCacheConfig contém configurações do cache de respostas dos backends.
+// O cache só se aplica às rotas com política de cache definida.
+type ResponseCacheConfig struct {
+	Enabled      bool
+	DefaultTTL   time.Duration
+	MaxBodyBytes int64 // respostas maiores não são armazenadas
 }
 
 // AuthConfig contém configurações de autenticação
@@ -86,6 +247,90 @@ type AuthConfig struct {
 	AdminUsers       []string
 	PasswordMinLen   int
 	RequireTwoFactor bool
+	JWKS             JWKSConfig
+	OIDC             OIDCConfig
+	APIKeys          APIKeysConfig
+	Credentials      CredentialsConfig
+	Revocation       RevocationConfig
+}
+
+// JWKSConfig habilita tokens de um provedor de identidade externo (Keycloak,
+// Auth0, Cognito), verificados pelas chaves publicadas no endpoint JWKS
+type JWKSConfig struct {
+	URL             string        // Endpoint JWKS; vazio aceita apenas tokens do próprio gateway
+	RefreshInterval time.Duration // Intervalo de atualização das chaves
+	Issuer          string        // Valor exigido na claim iss; vazio não verifica
+	Audience        []string      // Valores aceitos na claim aud; vazio não verifica
+}
+
+// OIDCConfig define os provedores OpenID Connect disponíveis para as rotas
+// protegidas pela política "oidc"
+type OIDCConfig struct {
+	Providers  []OIDCProviderConfig
+	SessionTTL time.Duration // Duração máxima da sessão criada pelo login no navegador
+	Session    OIDCSessionConfig
+}
+
+// OIDCSessionConfig habilita a camada de sessão dos navegadores: o cookie de
+// sessão é cifrado com as chaves de security.encryption e os métodos inseguros
+// exigem o token CSRF, devolvido pelo SPA em um cabeçalho
+type OIDCSessionConfig struct {
+	Enabled    bool
+	CSRFHeader string // Cabeçalho com o token CSRF (padrão X-CSRF-Token)
+	CSRFCookie string // Cookie legível pelo JavaScript com o token CSRF (padrão ag_csrf)
+	SameSite   string // SameSite do cookie de sessão: lax (padrão), strict ou none
+}
+
+// OIDCProviderConfig configura um provedor OpenID Connect. As credenciais do
+// cliente só são necessárias para o login de navegadores (authorization code).
+type OIDCProviderConfig struct {
+	Name         string   // Nome referenciado pelas rotas
+	Issuer       string   // Emissor; o documento de descoberta é obtido em {issuer}/.well-known/openid-configuration
+	Audience     []string // Valores aceitos na claim aud dos access tokens; vazio não verifica
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string   // Deve apontar para /auth/oidc/{name}/callback no gateway
+	Scopes       []string // Escopos pedidos no login; "openid" é sempre incluído
+}
+
+// APIKeysConfig habilita a autenticação de consumidores máquina-a-máquina por
+// chaves de API armazenadas no repositório
+type APIKeysConfig struct {
+	Enabled  bool
+	Header   string        // Cabeçalho com a chave (padrão X-Api-Key)
+	CacheTTL time.Duration // Tempo em que a chave validada fica em cache
+	Tiers    []APIKeyTierConfig
+}
+
+// CredentialsConfig habilita as credenciais de parceiros que não usam JWT nem
+// OAuth: usuário e senha (HTTP Basic) e chaves de assinatura HMAC das
+// requisições, armazenadas no repositório e exigidas pelas rotas com as
+// políticas basic e hmac
+type CredentialsConfig struct {
+	Enabled    bool
+	Realm      string        // Realm informado no WWW-Authenticate das rotas basic
+	CacheTTL   time.Duration // Tempo em que a credencial validada fica em cache em cada instância
+	MaxSkew    time.Duration // Diferença máxima entre o horário da assinatura HMAC e o do gateway
+	MaxBodyKiB int64         // Tamanho máximo do corpo das requisições assinadas
+}
+
+// RevocationConfig controla a lista de tokens JWT revogados, mantida no cache
+// compartilhado (Redis ou Memcached); com outros caches a revogação fica
+// desabilitada.
+type RevocationConfig struct {
+	// MaxTokenLifetime é a maior validade (exp - iat) aceita nos tokens do
+	// provedor de auth.jwks. As revogações por subject e por jti sem expiração
+	// conhecida duram o maior valor entre ele e a validade dos tokens do gateway.
+	MaxTokenLifetime time.Duration
+}
+
+// APIKeyTierConfig define uma faixa de rate limit atribuível às chaves
+type APIKeyTierConfig struct {
+	Name         string
+	Limit        int           // Requisições permitidas por período
+	Period       time.Duration // Janela do limite
+	DailyQuota   int64         // Cota diária das chaves da faixa; 0 usa quotas.daily
+	MonthlyQuota int64         // Cota mensal das chaves da faixa; 0 usa quotas.monthly
 }
 
 // MetricsConfig contém configurações de métricas
@@ -93,6 +338,24 @@ type MetricsConfig struct {
 	Enabled        bool
 	PrometheusPath string
 	ReportInterval time.Duration
+	OTLP           OTLPMetricsConfig
+	RouteStats     RouteStatsConfig
+}
+
+// RouteStatsConfig configura as estatísticas por rota em janelas de tempo
+// (1m, 5m e 1h), consultadas pela API administrativa
+type RouteStatsConfig struct {
+	Enabled       bool
+	FlushInterval time.Duration // intervalo de gravação das estatísticas no banco
+	Retention     time.Duration // tempo mantido no banco; no mínimo 1h
+}
+
+// OTLPMetricsConfig configura a exportação das métricas OpenTelemetry para um
+// coletor OTLP (gRPC), independente do endpoint do Prometheus
+type OTLPMetricsConfig struct {
+	Enabled  bool
+	Endpoint string        // vazio usa tracing.endpoint
+	Interval time.Duration // intervalo de exportação
 }
 
 // LoggingConfig contém configurações de logging
@@ -102,6 +365,22 @@ type LoggingConfig struct {
 	OutputPath string // stdout, file path
 	ErrorPath  string
 	Production bool
+	Access     AccessLogConfig
+}
+
+// AccessLogConfig configura o log de acesso, com uma entrada por requisição
+type AccessLogConfig struct {
+	Enabled    bool
+	Format     string                 // json ou combined (formato Apache combined)
+	OutputPath string                 // stdout, stderr ou arquivo; vazio usa a saída do logger da aplicação
+	SampleRate float64                // fração das requisições bem-sucedidas registradas (1 = todas)
+	Routes     []AccessLogRouteConfig // amostragem específica de rotas com muito tráfego
+}
+
+// AccessLogRouteConfig define a amostragem do log de acesso de uma rota
+type AccessLogRouteConfig struct {
+	Path       string  // caminho registrado da rota (ex.: /api/users/:id)
+	SampleRate float64 // fração das requisições bem-sucedidas registradas
 }
 
 // TracingConfig contém configurações de rastreamento
@@ -111,6 +390,35 @@ type TracingConfig struct {
 	Endpoint      string
 	ServiceName   string
 	SamplingRatio float64
+	// Formatos de propagação do contexto: tracecontext, baggage, b3, b3multi e jaeger
+	Propagators []string
+	// Amostrador: parentbased_ratio (padrão), ratio, always_on ou always_off
+	Sampler string
+	// Atributos adicionados ao recurso dos spans (ex.: deployment.environment).
+	// As chaves com ponto chegam aninhadas pelo viper e são recompostas na
+	// criação do recurso.
+	ResourceAttributes map[string]interface{}
+	// Cabeçalhos enviados ao coletor OTLP (ex.: chave de API do fornecedor)
+	Headers map[string]string
+	// Conexão com o coletor por TLS; sem ela, a conexão não é cifrada
+	TLS bool
+	// Amostragem específica de rotas, pelo caminho registrado
+	Routes []TracingRouteConfig
+	// Atributos mascarados antes da exportação dos spans
+	Redact TracingRedactConfig
+}
+
+// TracingRouteConfig define a amostragem dos traces de uma rota
+type TracingRouteConfig struct {
+	Path          string  // caminho registrado da rota (ex.: /api/users/:id ou /api/*)
+	SamplingRatio float64 // fração das requisições amostradas
+}
+
+// TracingRedactConfig define os atributos de span que nunca são exportados
+// com o valor original
+type TracingRedactConfig struct {
+	Attributes []string // chaves mascaradas; "*" no fim aceita qualquer sufixo
+	Patterns   []string // expressões regulares mascaradas nos valores texto
 }
 
 // FeaturesConfig contém flags de recursos
@@ -125,6 +433,340 @@ type FeaturesConfig struct {
 	AutoRouteRegister bool
 }
 
+// SecurityConfig contém configurações de segurança dos dados armazenados
+type SecurityConfig struct {
+	Encryption EncryptionConfig
+	Secrets    SecretsConfig
+}
+
+// SecretsConfig define o provedor de onde vêm os segredos do gateway
+type SecretsConfig struct {
+	Provider        string        // env (padrão), file, vault, aws ou kubernetes
+	RefreshInterval time.Duration // Intervalo de atualização dos segredos; zero não atualiza
+	// JWTSecret é a referência do segredo JWT no provedor. Vazio mantém as
+	// fontes padrão: JWT_SECRET_KEY, AG_AUTH_JWT_SECRET_KEY e auth.jwtSecret
+	JWTSecret string
+	// APIKeyPepper é a referência do pepper do hash das chaves de API no
+	// provedor. Vazio mantém o SHA-256 sem pepper
+	APIKeyPepper string
+	// AllowInsecureFallback permite o segredo JWT fixo de desenvolvimento
+	// quando nenhuma fonte define o segredo; nunca use em produção
+	AllowInsecureFallback bool
+	Vault                 VaultSecretsConfig
+	AWS                   AWSSecretsConfig
+	Kubernetes            KubernetesSecretsConfig
+}
+
+// VaultSecretsConfig configura o acesso à engine KV do HashiCorp Vault
+type VaultSecretsConfig struct {
+	Address   string // Vazio usa VAULT_ADDR
+	Token     string // Vazio usa TokenFile ou VAULT_TOKEN
+	TokenFile string // Arquivo com o token, relido a cada consulta (ex.: Vault Agent)
+	Mount     string // Mount da engine KV (padrão "secret")
+	KVVersion int    // Versão da engine KV: 1 ou 2 (padrão)
+	Namespace string // Namespace do Vault Enterprise
+}
+
+// AWSSecretsConfig configura o acesso ao AWS Secrets Manager
+type AWSSecretsConfig struct {
+	Region          string // Vazio usa AWS_REGION
+	Endpoint        string // Endpoint alternativo (ex.: VPC endpoint, LocalStack)
+	AccessKeyID     string // Vazio usa AWS_ACCESS_KEY_ID
+	SecretAccessKey string // Vazio usa AWS_SECRET_ACCESS_KEY
+	SessionToken    string // Vazio usa AWS_SESSION_TOKEN
+}
+
+// KubernetesSecretsConfig configura a leitura de Secrets pela API do cluster
+type KubernetesSecretsConfig struct {
+	APIServer string // Vazio usa o serviço do cluster visto de dentro do pod
+	Namespace string // Vazio usa o namespace do pod
+}
+
+// EncryptionConfig contém configurações da criptografia em repouso
+type EncryptionConfig struct {
+	Enabled   bool
+	ActiveKey string            // identificador da chave usada para novas cifragens
+	Keys      map[string]string // id -> chave de 32 bytes em base64
+	KeySpec   string            // alternativa para env: "id1:base64,id2:base64"
+	// KeysSecret é a referência, no provedor de security.secrets, das chaves no
+	// formato de KeySpec. Preferível a Keys e KeySpec, que ficam na configuração
+	KeysSecret string
+}
+
+// UpstreamConfig contém configurações das conexões com os serviços de backend
+type UpstreamConfig struct {
+	EgressProxy EgressProxyConfig
+	Transport   TransportConfig
+	RetryBudget RetryBudgetConfig
+	Kubernetes  KubernetesDiscoveryConfig
+	Consul      ConsulDiscoveryConfig
+	DNS         DNSDiscoveryConfig
+	Resolver    UpstreamResolverConfig
+}
+
+// KubernetesDiscoveryConfig habilita backends declarados como Services do
+// Kubernetes (service://namespace/nome:porta), balanceados direto entre os pods prontos
+type KubernetesDiscoveryConfig struct {
+	Enabled     bool
+	APIServer   string        // Vazio usa o serviço do cluster visto de dentro do pod
+	SyncTimeout time.Duration // espera pela primeira listagem dos endpoints de um Service
+}
+
+// ConsulDiscoveryConfig habilita backends declarados como serviços do catálogo
+// do Consul (consul://servico?tag=...), consultados periodicamente
+type ConsulDiscoveryConfig struct {
+	Enabled         bool
+	Address         string        // URL do agente (ex.: http://localhost:8500)
+	Token           string        // ACL token enviado em X-Consul-Token
+	Datacenter      string        // Vazio usa o datacenter do agente
+	RefreshInterval time.Duration // intervalo entre consultas ao catálogo
+	OnlyPassing     bool          // Considera apenas instâncias com todas as verificações aprovadas
+}
+
+// DNSDiscoveryConfig habilita backends declarados por registros DNS SRV
+// (srv://_http._tcp.nome), consultados periodicamente
+type DNSDiscoveryConfig struct {
+	Enabled         bool
+	RefreshInterval time.Duration
+}
+
+// UpstreamResolverConfig controla a resolução DNS dos backends. Os endereços
+// ficam em cache até o TTL e são consultados de novo em segundo plano; quando
+// mudam, as conexões com os backends são renovadas.
+type UpstreamResolverConfig struct {
+	Enabled bool
+	// Servidores DNS (ip ou ip:porta); vazio usa o resolvedor do sistema,
+	// com /etc/hosts e os domínios de busca
+	Servers         []string
+	TTL             time.Duration // validade das respostas do resolvedor do sistema, que não informa o TTL
+	MinTTL          time.Duration // limites aplicados ao TTL dos registros consultados em Servers
+	MaxTTL          time.Duration
+	Timeout         time.Duration // tempo máximo de cada consulta
+	RefreshInterval time.Duration // intervalo de verificação das entradas expiradas
+}
+
+// RetryBudgetConfig limita as novas tentativas e os hedges de todas as rotas a
+// uma fração das requisições recentes, evitando que amplifiquem uma indisponibilidade
+type RetryBudgetConfig struct {
+	Enabled             bool
+	Ratio               float64       // fração das requisições da janela que pode ser repetida (ex.: 0.2)
+	MinRetriesPerSecond int           // reserva mínima de repetições com pouco tráfego
+	Window              time.Duration // janela de contagem
+}
+
+// TransportConfig ajusta o pool de conexões com os backends. O padrão do Go
+// mantém apenas 2 conexões ociosas por backend, o que força a abertura de
+// novas conexões em rotas com muito tráfego. As rotas podem substituir cada
+// valor em connectionPool.
+type TransportConfig struct {
+	MaxIdleConns        int           // conexões ociosas somando todos os backends
+	MaxIdleConnsPerHost int           // conexões ociosas mantidas por backend
+	MaxConnsPerHost     int           // conexões por backend, incluindo as em uso; 0 sem limite
+	IdleConnTimeout     time.Duration // tempo até fechar uma conexão ociosa
+	TLSHandshakeTimeout time.Duration
+	DialTimeout         time.Duration
+	KeepAlive           time.Duration // intervalo das sondas TCP keep-alive
+	DisableKeepAlives   bool          // abre uma conexão por requisição
+	ForceAttemptHTTP2   bool          // negocia HTTP/2 pelo ALPN com backends https
+}
+
+// EgressProxyConfig configura o proxy corporativo de saída usado para alcançar os backends
+type EgressProxyConfig struct {
+	URL     string   // http://, https:// ou socks5://; vazio usa HTTP_PROXY/HTTPS_PROXY do ambiente
+	NoProxy []string // hosts, domínios (.exemplo.com) e CIDRs acessados sem proxy
+}
+
+// AdminConfig contém configurações das interfaces administrativas
+type AdminConfig struct {
+	GRPC     GRPCAdminConfig
+	IPFilter IPFilterConfig // Origens permitidas nas rotas /admin e na API gRPC
+	UI       AdminUIConfig
+}
+
+// AdminUIConfig configura o painel administrativo servido em /admin/ui
+type AdminUIConfig struct {
+	Enabled bool
+}
+
+// IPFilterConfig contém listas de blocos CIDR (ou endereços) permitidos e
+// bloqueados; a lista de bloqueio tem precedência e, com allow preenchida,
+// apenas as origens nela contidas são aceitas
+type IPFilterConfig struct {
+	Allow []string
+	Deny  []string
+}
+
+// GRPCAdminConfig configura a API administrativa gRPC (api/proto/admin/v1/admin.proto)
+type GRPCAdminConfig struct {
+	Enabled bool
+	Address string // endereço de escuta, ex.: ":9090"
+}
+
+// ClusterConfig configura a operação em cluster de várias instâncias, coordenada
+// pelo Redis configurado em cache.redis
+type ClusterConfig struct {
+	Enabled           bool
+	Name              string        // instâncias com o mesmo nome formam um cluster
+	NodeID            string        // identificador da instância; vazio usa o hostname
+	AdvertiseAddress  string        // endereço exibido aos demais membros
+	HeartbeatInterval time.Duration // frequência do heartbeat e da renovação da liderança
+	MemberTTL         time.Duration // ausência de heartbeat após a qual o membro é descartado
+	LeaseTTL          time.Duration // validade da liderança sem renovação
+	// Mecanismo da eleição do líder: redis (padrão) ou kubernetes (recurso Lease)
+	Election   string
+	Kubernetes ClusterKubernetesConfig
+}
+
+// ClusterKubernetesConfig configura a eleição do líder por um recurso Lease
+// (coordination.k8s.io/v1)
+type ClusterKubernetesConfig struct {
+	APIServer string // Vazio usa o serviço da API visto de dentro do pod
+	Namespace string // Vazio usa o namespace do pod
+	LeaseName string // Vazio usa "<cluster.name>-leader"
+}
+
+// QuotasConfig configura as cotas de uso diárias e mensais por consumidor
+// (chave de API ou subject do JWT). Os contadores ficam no Redis configurado
+// em cache.redis e são gravados periodicamente no banco.
+type QuotasConfig struct {
+	Enabled       bool
+	Daily         int64         // Requisições por dia de cada consumidor; 0 sem limite
+	Monthly       int64         // Requisições por mês de cada consumidor; 0 sem limite
+	FlushInterval time.Duration // Frequência de gravação dos contadores no banco
+}
+
+// RateLimitConfig configura como os limites de requisições e as cotas são
+// informados aos clientes nas respostas
+type RateLimitConfig struct {
+	// Cabeçalhos dos limites: standard (RateLimit-*), legacy (X-RateLimit-* e
+	// X-Quota-*), both ou none. Cada rota pode definir o próprio estilo.
+	Headers string
+}
+
+// IdempotencyConfig configura o tratamento do cabeçalho Idempotency-Key nas
+// rotas com política de idempotência. As respostas ficam no Redis configurado
+// em cache.redis, compartilhadas entre as instâncias.
+type IdempotencyConfig struct {
+	Enabled      bool
+	Header       string        // Cabeçalho com a chave enviada pelo cliente
+	TTL          time.Duration // Por quanto tempo a resposta é repetida para a mesma chave
+	LockTimeout  time.Duration // Tempo máximo de uma requisição em andamento com a chave
+	MaxBodyBytes int64         // Tamanho máximo do corpo da requisição e da resposta armazenada
+}
+
+// RecordingConfig configura a gravação das requisições e respostas das rotas
+// com política de gravação, usada para reproduzir problemas com o tráfego real.
+// As gravações ficam no banco e são consultadas pela API administrativa.
+type RecordingConfig struct {
+	Enabled   bool
+	QueueSize int           // Gravações aguardando o banco; com a fila cheia, são descartadas
+	Retention time.Duration // Por quanto tempo as gravações são mantidas
+}
+
+// GeoConfig configura a localização dos clientes pelo endereço de origem,
+// usada nas restrições por país e na escolha de backends por região das rotas
+// e registrada nos traces e no log de acesso. A base é carregada na
+// inicialização; uma base atualizada exige reinício.
+type GeoConfig struct {
+	Enabled       bool
+	Database      string // Base MaxMind DB (.mmdb), ex.: GeoLite2-Country ou GeoIP2-City
+	CountryHeader string // Cabeçalho com o país definido pela CDN (ex.: CF-IPCountry), aceito apenas de server.trustedProxies
+}
+
+// FeatureFlagsConfig configura o provedor das feature flags que habilitam as
+// rotas com featureFlag. Com o provedor "static", as flags são definidas aqui e
+// recarregadas sem reinício; com "ofrep", são avaliadas por um serviço
+// compatível com o OpenFeature Remote Evaluation Protocol (flagd, GO Feature
+// Flag...), com o resultado mantido em cache pelo tempo configurado.
+type FeatureFlagsConfig struct {
+	Provider string                       // "static" ou "ofrep"; vazio usa o fallback de cada rota
+	URL      string                       // Endereço base do serviço OFREP (ex.: http://flagd:8016)
+	Headers  map[string]string            // Cabeçalhos enviados ao serviço OFREP (ex.: Authorization)
+	Timeout  time.Duration                // Prazo de cada avaliação no serviço OFREP
+	CacheTTL time.Duration                // Por quanto tempo cada avaliação é reaproveitada
+	Flags    map[string]FeatureFlagConfig // Flags do provedor static, por nome (sem diferenciar maiúsculas)
+}
+
+// FeatureFlagConfig define uma flag do provedor static. Consumidores e tenants
+// listados sempre recebem a flag; os demais, na porcentagem configurada.
+type FeatureFlagConfig struct {
+	Enabled    bool
+	Percentage int      // Porcentagem dos consumidores (ou clientes anônimos) com a flag, de 0 a 100
+	Consumers  []string // Consumidores (ID ou username) sempre habilitados
+	Tenants    []string // Tenants sempre habilitados
+}
+
+// HealthConfig configura as verificações de /readyz (e /health/readiness)
+type HealthConfig struct {
+	Timeout time.Duration // prazo das verificações das dependências
+	// Rotas cujos backends são críticos: sem nenhum alvo disponível nas
+	// verificações ativas, a instância deixa de estar pronta
+	CriticalUpstreams []string
+}
+
+// RoutesConfig configura as rotas declaradas em arquivo (YAML ou JSON) ou
+// como recursos do Kubernetes
+type RoutesConfig struct {
+	File       string        // arquivo de rotas; vazio carrega ./config/routes.json, se existir
+	Watch      bool          // reaplica o arquivo a cada alteração, sem reinício
+	Prune      bool          // remove as rotas cadastradas que não estão no arquivo
+	Debounce   time.Duration // espera após uma alteração antes de reaplicar o arquivo
+	Webhooks   RouteWebhooksConfig
+	Hooks      RouteHooksConfig
+	Kubernetes RoutesKubernetesConfig
+
+	// Carrega as rotas no cache e no índice antes de a instância ficar pronta
+	Warmup bool
+	// Intervalo das novas tentativas quando a carga inicial falha
+	WarmupRetryInterval time.Duration
+	// Cópia local da última lista de rotas carregada, servida quando o banco
+	// está indisponível; vazio desativa
+	SnapshotFile string
+}
+
+// RoutesKubernetesConfig habilita a sincronização das rotas declaradas como
+// recursos GatewayRoute (e, opcionalmente, HTTPRoute) do cluster
+type RoutesKubernetesConfig struct {
+	Enabled        bool
+	APIServer      string        // Vazio usa o serviço da API visto de dentro do pod
+	Namespace      string        // Vazio acompanha todos os namespaces
+	HTTPRoute      bool          // Também traduz HTTPRoutes da Gateway API
+	GatewayName    string        // Aceita apenas HTTPRoutes ligadas a este Gateway; vazio aceita todas
+	ResyncInterval time.Duration // intervalo da reconciliação completa, que desfaz alterações feitas por fora
+}
+
+// RouteWebhooksConfig configura a notificação das alterações de rotas a
+// sistemas externos
+type RouteWebhooksConfig struct {
+	Endpoints  []RouteWebhookEndpointConfig
+	Timeout    time.Duration // prazo de cada tentativa de entrega
+	MaxRetries int           // novas tentativas após falha, com espera exponencial
+}
+
+// RouteHooksConfig configura a entrega das notificações dos webhooks
+// pós-resposta das rotas. Os webhooks são definidos em cada rota.
+type RouteHooksConfig struct {
+	QueueSize  int           // notificações aguardando entrega; excedentes são descartadas
+	Workers    int           // entregas simultâneas
+	Timeout    time.Duration // prazo de cada tentativa de entrega
+	MaxRetries int           // novas tentativas após falha, com espera exponencial
+}
+
+// RouteWebhookEndpointConfig define um destino das notificações
+type RouteWebhookEndpointConfig struct {
+	URL    string
+	Secret string   // chave HMAC-SHA256 da assinatura das entregas
+	Events []string // created, updated, deleted; vazio recebe todos
+}
+
+// OpenAPIConfig configura o catálogo OpenAPI que agrega os documentos dos backends
+type OpenAPIConfig struct {
+	Title     string        // título do documento agregado
+	PublicURL string        // URL pública do gateway; vazio usa o host da requisição
+	CacheTTL  time.Duration // validade do documento obtido de cada backend
+	Timeout   time.Duration // prazo para obter o documento de um backend
+}
+
 // LoadConfig carrega a configuração de diversas fontes (arquivos, env, defaults)
 func LoadConfig(configPath string) (*Config, error) {
 	v := viper.New()
@@ -181,6 +823,51 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("server.idleTimeout", "30s")
 	v.SetDefault("server.maxHeaderBytes", 1<<20) // 1 MB
 	v.SetDefault("server.tls", false)
+	v.SetDefault("server.h2c", false)
+	v.SetDefault("server.acme.storage", "dir")
+	v.SetDefault("server.acme.cacheDir", "./certs")
+	v.SetDefault("server.acme.renewBefore", "720h") // 30 dias
+	v.SetDefault("server.limits.enabled", true)
+	v.SetDefault("server.limits.maxHeaderCount", 100)
+	v.SetDefault("server.limits.maxHeaderValueBytes", 8192)
+	v.SetDefault("server.limits.maxURLLength", 8192)
+	v.SetDefault("server.limits.rejectSmuggling", true)
+	v.SetDefault("server.securityHeaders.enabled", true)
+	v.SetDefault("server.securityHeaders.hsts.enabled", true)
+	v.SetDefault("server.securityHeaders.hsts.maxAge", "8760h") // 1 ano
+	v.SetDefault("server.securityHeaders.hsts.includeSubDomains", true)
+	v.SetDefault("server.securityHeaders.hsts.preload", false)
+	v.SetDefault("server.securityHeaders.frameOptions", "DENY")
+	v.SetDefault("server.securityHeaders.contentTypeOptions", true)
+	v.SetDefault("server.securityHeaders.referrerPolicy", "strict-origin-when-cross-origin")
+	v.SetDefault("server.securityHeaders.permissionsPolicy", "")
+	v.SetDefault("server.securityHeaders.contentSecurityPolicy", "")
+	v.SetDefault("server.securityHeaders.nonceHeader", "X-CSP-Nonce")
+	v.SetDefault("server.securityHeaders.stripResponseHeaders", []string{
+		"Server", "X-Powered-By", "X-AspNet-Version", "X-AspNetMvc-Version",
+	})
+	v.SetDefault("server.compression.enabled", false)
+	v.SetDefault("server.compression.encodings", []string{"br", "zstd", "gzip"})
+	v.SetDefault("server.compression.minSize", 1024)
+	v.SetDefault("server.compression.contentTypes", []string{
+		"text/*", "application/json", "application/javascript", "application/xml",
+		"application/problem+json", "image/svg+xml",
+	})
+	v.SetDefault("server.http3.enabled", false)
+	v.SetDefault("server.http3.port", 0)
+	v.SetDefault("server.http3.altSvcMaxAge", "24h")
+	v.SetDefault("server.overload.enabled", false)
+	v.SetDefault("server.overload.initialLimit", 100)
+	v.SetDefault("server.overload.minLimit", 10)
+	v.SetDefault("server.overload.maxLimit", 2000)
+	v.SetDefault("server.overload.targetLatency", "0s")
+	v.SetDefault("server.overload.tolerance", 2.0)
+	v.SetDefault("server.overload.backoffRatio", 0.9)
+	v.SetDefault("server.overload.maxQueue", 100)
+	v.SetDefault("server.overload.queueTimeout", "100ms")
+	v.SetDefault("server.overload.retryAfter", "1s")
+	v.SetDefault("server.forwardedForDepth", 0)
+	v.SetDefault("server.requestIDHeader", "X-Request-ID")
 
 	// Banco de dados
 	v.SetDefault("database.driver", "postgres")
@@ -188,9 +875,14 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("database.maxIdleConns", 10)
 	v.SetDefault("database.maxOpenConns", 50)
 	v.SetDefault("database.connMaxLifetime", "1h")
+	v.SetDefault("database.connMaxIdleTime", "10m")
+	v.SetDefault("database.pingTimeout", "2s")
+	v.SetDefault("database.queryTimeout", "5s")
 	v.SetDefault("database.logLevel", "warn")
 	v.SetDefault("database.slowThreshold", "200ms")
-	v.SetDefault("database.migrationDir", "./migrations")
+	v.SetDefault("database.migrationDir", "")
+	v.SetDefault("database.tenantHeader", "X-Tenant-ID")
+	v.SetDefault("database.hashUnassignedTenants", false)
 
 	// Redis
 	v.SetDefault("cache.redis.address", "localhost:6379")
@@ -211,6 +903,13 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("cache.ttl", "5m")
 	v.SetDefault("cache.maxItems", 10000)
 	v.SetDefault("cache.maxMemoryMB", 100)
+	v.SetDefault("cache.memcached.servers", []string{"localhost:11211"})
+	v.SetDefault("cache.memcached.timeout", "500ms")
+	v.SetDefault("cache.memcached.maxIdleConns", 10)
+	v.SetDefault("cache.staleWhileRevalidate", "1m")
+	v.SetDefault("cache.response.enabled", false)
+	v.SetDefault("cache.response.defaultTTL", "1m")
+	v.SetDefault("cache.response.maxBodyBytes", 1<<20) // 1 MB
 
 	// Autenticação
 	v.SetDefault("auth.enabled", true)
@@ -219,11 +918,31 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("auth.refreshDuration", "168h") // 7 dias
 	v.SetDefault("auth.passwordMinLen", 8)
 	v.SetDefault("auth.requireTwoFactor", false)
+	v.SetDefault("auth.jwks.refreshInterval", "15m")
+	v.SetDefault("auth.oidc.sessionTTL", "8h")
+	v.SetDefault("auth.oidc.session.enabled", false)
+	v.SetDefault("auth.oidc.session.csrfHeader", "X-CSRF-Token")
+	v.SetDefault("auth.oidc.session.csrfCookie", "ag_csrf")
+	v.SetDefault("auth.oidc.session.sameSite", "lax")
+	v.SetDefault("auth.apiKeys.enabled", false)
+	v.SetDefault("auth.apiKeys.header", "X-Api-Key")
+	v.SetDefault("auth.apiKeys.cacheTTL", "1m")
+	v.SetDefault("auth.credentials.enabled", false)
+	v.SetDefault("auth.credentials.realm", "api-gateway")
+	v.SetDefault("auth.credentials.cacheTTL", "1m")
+	v.SetDefault("auth.credentials.maxSkew", "5m")
+	v.SetDefault("auth.credentials.maxBodyKiB", 10240)
+	v.SetDefault("auth.revocation.maxTokenLifetime", "24h")
 
 	// Métricas
 	v.SetDefault("metrics.enabled", true)
 	v.SetDefault("metrics.prometheusPath", "/metrics")
 	v.SetDefault("metrics.reportInterval", "15s")
+	v.SetDefault("metrics.otlp.enabled", false)
+	v.SetDefault("metrics.otlp.interval", "30s")
+	v.SetDefault("metrics.routeStats.enabled", true)
+	v.SetDefault("metrics.routeStats.flushInterval", "30s")
+	v.SetDefault("metrics.routeStats.retention", "24h")
 
 	// Logging
 	v.SetDefault("logging.level", "info")
@@ -231,12 +950,147 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("logging.outputPath", "stdout")
 	v.SetDefault("logging.errorPath", "stderr")
 	v.SetDefault("logging.production", true)
+	v.SetDefault("logging.access.enabled", true)
+	v.SetDefault("logging.access.format", "json")
+	v.SetDefault("logging.access.sampleRate", 1.0)
 
 	// Tracing
 	v.SetDefault("tracing.enabled", false)
 	v.SetDefault("tracing.provider", "opentelemetry")
 	v.SetDefault("tracing.samplingRatio", 0.1) // 10% das requisições
 	v.SetDefault("tracing.serviceName", "api-gateway")
+	v.SetDefault("tracing.propagators", []string{"tracecontext", "baggage"})
+	v.SetDefault("tracing.sampler", "parentbased_ratio")
+	v.SetDefault("tracing.tls", false)
+	v.SetDefault("tracing.redact.attributes", []string{
+		"http.request.header.authorization",
+		"http.request.header.proxy-authorization",
+		"http.request.header.cookie",
+		"http.request.header.x-api-key",
+		"http.response.header.set-cookie",
+	})
+
+	// Segurança
+	v.SetDefault("security.encryption.enabled", false)
+	v.SetDefault("security.encryption.activeKey", "")
+	v.SetDefault("security.encryption.keySpec", "")
+	v.SetDefault("security.secrets.provider", "env")
+	v.SetDefault("security.secrets.refreshInterval", "5m")
+	v.SetDefault("security.secrets.allowInsecureFallback", false)
+	v.SetDefault("security.secrets.vault.mount", "secret")
+	v.SetDefault("security.secrets.vault.kvVersion", 2)
+
+	// Upstream
+	v.SetDefault("upstream.egressProxy.url", "")
+	v.SetDefault("upstream.egressProxy.noProxy", []string{})
+	v.SetDefault("upstream.transport.maxIdleConns", 512)
+	v.SetDefault("upstream.transport.maxIdleConnsPerHost", 64)
+	v.SetDefault("upstream.transport.maxConnsPerHost", 0)
+	v.SetDefault("upstream.transport.idleConnTimeout", "90s")
+	v.SetDefault("upstream.transport.tlsHandshakeTimeout", "10s")
+	v.SetDefault("upstream.transport.dialTimeout", "30s")
+	v.SetDefault("upstream.transport.keepAlive", "30s")
+	v.SetDefault("upstream.transport.disableKeepAlives", false)
+	v.SetDefault("upstream.transport.forceAttemptHTTP2", true)
+	v.SetDefault("upstream.retryBudget.enabled", true)
+	v.SetDefault("upstream.retryBudget.ratio", 0.2)
+	v.SetDefault("upstream.retryBudget.minRetriesPerSecond", 3)
+	v.SetDefault("upstream.retryBudget.window", "10s")
+	v.SetDefault("upstream.kubernetes.enabled", false)
+	v.SetDefault("upstream.kubernetes.apiServer", "")
+	v.SetDefault("upstream.kubernetes.syncTimeout", "5s")
+	v.SetDefault("upstream.consul.enabled", false)
+	v.SetDefault("upstream.consul.address", "http://localhost:8500")
+	v.SetDefault("upstream.consul.token", "")
+	v.SetDefault("upstream.consul.datacenter", "")
+	v.SetDefault("upstream.consul.refreshInterval", "10s")
+	v.SetDefault("upstream.consul.onlyPassing", true)
+	v.SetDefault("upstream.dns.enabled", false)
+	v.SetDefault("upstream.dns.refreshInterval", "30s")
+	v.SetDefault("upstream.resolver.enabled", true)
+	v.SetDefault("upstream.resolver.servers", []string{})
+	v.SetDefault("upstream.resolver.ttl", "30s")
+	v.SetDefault("upstream.resolver.minTTL", "1s")
+	v.SetDefault("upstream.resolver.maxTTL", "5m")
+	v.SetDefault("upstream.resolver.timeout", "2s")
+	v.SetDefault("upstream.resolver.refreshInterval", "1s")
+
+	// Administração
+	v.SetDefault("admin.grpc.enabled", false)
+	v.SetDefault("admin.grpc.address", ":9090")
+	v.SetDefault("admin.ui.enabled", true)
+
+	// Cluster
+	v.SetDefault("cluster.enabled", false)
+	v.SetDefault("cluster.name", "api-gateway")
+	v.SetDefault("cluster.nodeID", "")
+	v.SetDefault("cluster.advertiseAddress", "")
+	v.SetDefault("cluster.heartbeatInterval", "2s")
+	v.SetDefault("cluster.memberTTL", "10s")
+	v.SetDefault("cluster.leaseTTL", "15s")
+	v.SetDefault("cluster.election", "redis")
+	v.SetDefault("cluster.kubernetes.apiServer", "")
+	v.SetDefault("cluster.kubernetes.namespace", "")
+	v.SetDefault("cluster.kubernetes.leaseName", "")
+
+	// Cotas de uso
+	v.SetDefault("quotas.enabled", false)
+	v.SetDefault("quotas.daily", 0)
+	v.SetDefault("quotas.monthly", 0)
+	v.SetDefault("quotas.flushInterval", "1m")
+
+	// Cabeçalhos dos limites
+	v.SetDefault("ratelimit.headers", "both")
+
+	v.SetDefault("idempotency.enabled", false)
+	v.SetDefault("idempotency.header", "Idempotency-Key")
+	v.SetDefault("idempotency.ttl", "24h")
+	v.SetDefault("idempotency.lockTimeout", "1m")
+	v.SetDefault("idempotency.maxBodyBytes", 1048576)
+
+	v.SetDefault("recording.enabled", false)
+	v.SetDefault("recording.queueSize", 1000)
+	v.SetDefault("recording.retention", "72h")
+
+	v.SetDefault("geo.enabled", false)
+	v.SetDefault("geo.database", "")
+	v.SetDefault("geo.countryHeader", "")
+
+	v.SetDefault("flags.provider", "")
+	v.SetDefault("flags.url", "")
+	v.SetDefault("flags.timeout", "2s")
+	v.SetDefault("flags.cacheTTL", "30s")
+
+	// Health checks
+	v.SetDefault("health.timeout", "5s")
+	v.SetDefault("health.criticalUpstreams", []string{})
+
+	// Rotas declaradas em arquivo
+	v.SetDefault("routes.file", "")
+	v.SetDefault("routes.watch", true)
+	v.SetDefault("routes.prune", false)
+	v.SetDefault("routes.debounce", "500ms")
+	v.SetDefault("routes.warmup", true)
+	v.SetDefault("routes.warmupRetryInterval", "5s")
+	v.SetDefault("routes.snapshotFile", "")
+	v.SetDefault("routes.webhooks.timeout", "10s")
+	v.SetDefault("routes.webhooks.maxRetries", 3)
+	v.SetDefault("routes.hooks.queueSize", 1024)
+	v.SetDefault("routes.hooks.workers", 8)
+	v.SetDefault("routes.hooks.timeout", "5s")
+	v.SetDefault("routes.hooks.maxRetries", 2)
+	v.SetDefault("routes.kubernetes.enabled", false)
+	v.SetDefault("routes.kubernetes.apiServer", "")
+	v.SetDefault("routes.kubernetes.namespace", "")
+	v.SetDefault("routes.kubernetes.httpRoute", false)
+	v.SetDefault("routes.kubernetes.gatewayName", "")
+	v.SetDefault("routes.kubernetes.resyncInterval", "5m")
+
+	// Catálogo OpenAPI
+	v.SetDefault("openapi.title", "API Gateway")
+	v.SetDefault("openapi.publicURL", "")
+	v.SetDefault("openapi.cacheTTL", "5m")
+	v.SetDefault("openapi.timeout", "10s")
 
 	// Features
 	v.SetDefault("features.rateLimiter", true)
@@ -252,15 +1106,330 @@ func setDefaults(v *viper.Viper) {
 // validateConfig valida a configuração
 func validateConfig(config *Config) error {
 	// Validar JWT Secret
-	if config.Auth.Enabled && config.Auth.JWTSecret == "" {
+	if config.Auth.Enabled && config.Auth.JWTSecret == "" && config.Security.Secrets.JWTSecret == "" &&
+		os.Getenv("JWT_SECRET_KEY") == "" {
 		// Gerar um aviso se o segredo JWT não estiver definido
-		fmt.Println("AVISO: JWT_SECRET_KEY não está definido. Uma chave temporária será gerada, mas isso não é recomendado para produção.")
+		fmt.Println("AVISO: segredo JWT não definido. Configure JWT_SECRET_KEY, auth.jwtSecret ou security.secrets.jwtSecret.")
+	}
+
+	if config.Auth.JWKS.URL != "" {
+		jwksURL, err := url.Parse(config.Auth.JWKS.URL)
+		if err != nil || (jwksURL.Scheme != "http" && jwksURL.Scheme != "https") || jwksURL.Host == "" {
+			return fmt.Errorf("auth.jwks.url inválida: %s", config.Auth.JWKS.URL)
+		}
+		if config.Auth.JWKS.RefreshInterval <= 0 {
+			return fmt.Errorf("auth.jwks.refreshInterval deve ser positivo")
+		}
+	}
+
+	if config.Metrics.OTLP.Enabled {
+		if config.Metrics.OTLP.Endpoint == "" && config.Tracing.Endpoint == "" {
+			return fmt.Errorf("metrics.otlp.endpoint é obrigatório quando tracing.endpoint não está definido")
+		}
+		if config.Metrics.OTLP.Interval <= 0 {
+			return fmt.Errorf("metrics.otlp.interval deve ser positivo")
+		}
+	}
+
+	if config.Metrics.RouteStats.Enabled {
+		if config.Metrics.RouteStats.FlushInterval <= 0 {
+			return fmt.Errorf("metrics.routeStats.flushInterval deve ser positivo")
+		}
+		if config.Metrics.RouteStats.Retention < time.Hour {
+			return fmt.Errorf("metrics.routeStats.retention deve ser de ao menos 1h")
+		}
+	}
+
+	for _, propagator := range config.Tracing.Propagators {
+		switch strings.ToLower(strings.TrimSpace(propagator)) {
+		case "tracecontext", "baggage", "b3", "b3multi", "jaeger":
+		default:
+			return fmt.Errorf("tracing.propagators: formato desconhecido: %q (use tracecontext, baggage, b3, b3multi ou jaeger)", propagator)
+		}
+	}
+
+	switch config.Tracing.Sampler {
+	case "", "parentbased_ratio", "ratio", "always_on", "always_off":
+	default:
+		return fmt.Errorf("tracing.sampler desconhecido: %q (use parentbased_ratio, ratio, always_on ou always_off)", config.Tracing.Sampler)
+	}
+	if config.Tracing.SamplingRatio < 0 || config.Tracing.SamplingRatio > 1 {
+		return fmt.Errorf("tracing.samplingRatio deve estar entre 0 e 1")
+	}
+	for _, route := range config.Tracing.Routes {
+		if !strings.HasPrefix(route.Path, "/") {
+			return fmt.Errorf("tracing.routes: caminho inválido: %q", route.Path)
+		}
+		if route.SamplingRatio < 0 || route.SamplingRatio > 1 {
+			return fmt.Errorf("tracing.routes: samplingRatio de %s deve estar entre 0 e 1", route.Path)
+		}
+	}
+	for _, pattern := range config.Tracing.Redact.Patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("tracing.redact.patterns: expressão inválida %q: %w", pattern, err)
+		}
+	}
+
+	if _, err := zapcore.ParseLevel(config.Logging.Level); err != nil {
+		return fmt.Errorf("logging.level inválido: %s (use debug, info, warn ou error)", config.Logging.Level)
+	}
+	if config.Logging.Access.Enabled {
+		if format := config.Logging.Access.Format; format != "json" && format != "combined" {
+			return fmt.Errorf("logging.access.format inválido: %s (use json ou combined)", format)
+		}
+		if rate := config.Logging.Access.SampleRate; rate < 0 || rate > 1 {
+			return fmt.Errorf("logging.access.sampleRate deve estar entre 0 e 1")
+		}
+		for _, route := range config.Logging.Access.Routes {
+			if route.Path == "" {
+				return fmt.Errorf("logging.access.routes: path é obrigatório")
+			}
+			if route.SampleRate < 0 || route.SampleRate > 1 {
+				return fmt.Errorf("logging.access.routes: sampleRate de %s deve estar entre 0 e 1", route.Path)
+			}
+		}
+	}
+
+	oidcProviders := make(map[string]bool)
+	for _, provider := range config.Auth.OIDC.Providers {
+		if provider.Name == "" || oidcProviders[provider.Name] {
+			return fmt.Errorf("auth.oidc.providers: nome vazio ou duplicado: %q", provider.Name)
+		}
+		// O nome compõe o cookie de sessão e o caminho do callback
+		if strings.Trim(provider.Name, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_-") != "" {
+			return fmt.Errorf("auth.oidc.providers: nome deve conter apenas letras, números, '_' e '-': %q", provider.Name)
+		}
+		oidcProviders[provider.Name] = true
+		issuer, err := url.Parse(provider.Issuer)
+		if err != nil || (issuer.Scheme != "http" && issuer.Scheme != "https") || issuer.Host == "" {
+			return fmt.Errorf("auth.oidc.providers[%s].issuer inválido: %s", provider.Name, provider.Issuer)
+		}
+		if provider.ClientID != "" && provider.RedirectURL == "" {
+			return fmt.Errorf("auth.oidc.providers[%s].redirectURL é obrigatório com clientID", provider.Name)
+		}
+	}
+	if len(config.Auth.OIDC.Providers) > 0 && config.Auth.OIDC.SessionTTL <= 0 {
+		return fmt.Errorf("auth.oidc.sessionTTL deve ser positivo")
+	}
+	if session := config.Auth.OIDC.Session; session.Enabled {
+		if !config.Security.Encryption.Enabled {
+			return fmt.Errorf("auth.oidc.session exige security.encryption habilitada para cifrar o cookie de sessão")
+		}
+		if session.CSRFHeader == "" || session.CSRFCookie == "" {
+			return fmt.Errorf("auth.oidc.session: csrfHeader e csrfCookie são obrigatórios")
+		}
+		switch strings.ToLower(session.SameSite) {
+		case "lax", "strict", "none":
+		default:
+			return fmt.Errorf("auth.oidc.session.sameSite inválido: %q (use lax, strict ou none)", session.SameSite)
+		}
+	}
+
+	if config.Auth.APIKeys.Enabled {
+		if config.Auth.APIKeys.Header == "" {
+			return fmt.Errorf("auth.apiKeys.header é obrigatório")
+		}
+		if config.Auth.APIKeys.CacheTTL < 0 {
+			return fmt.Errorf("auth.apiKeys.cacheTTL não pode ser negativo")
+		}
+		tiers := make(map[string]bool)
+		for _, tier := range config.Auth.APIKeys.Tiers {
+			if tier.Name == "" || tiers[tier.Name] {
+				return fmt.Errorf("auth.apiKeys.tiers: nome vazio ou duplicado: %q", tier.Name)
+			}
+			tiers[tier.Name] = true
+			if tier.Limit <= 0 || tier.Period <= 0 {
+				return fmt.Errorf("auth.apiKeys.tiers[%s]: limit e period devem ser positivos", tier.Name)
+			}
+			if tier.DailyQuota < 0 || tier.MonthlyQuota < 0 {
+				return fmt.Errorf("auth.apiKeys.tiers[%s]: dailyQuota e monthlyQuota não podem ser negativos", tier.Name)
+			}
+		}
+	}
+
+	if config.Auth.Credentials.Enabled {
+		// Os nonces das assinaturas hmac são registrados no cache
+		if !config.Cache.Enabled {
+			return fmt.Errorf("auth.credentials.enabled requer cache.enabled: os nonces das assinaturas hmac são registrados no cache")
+		}
+		if config.Auth.Credentials.CacheTTL < 0 {
+			return fmt.Errorf("auth.credentials.cacheTTL não pode ser negativo")
+		}
+		if config.Auth.Credentials.MaxSkew <= 0 {
+			return fmt.Errorf("auth.credentials.maxSkew deve ser positivo")
+		}
+		if config.Auth.Credentials.MaxBodyKiB <= 0 {
+			return fmt.Errorf("auth.credentials.maxBodyKiB deve ser positivo")
+		}
+	}
+
+	if config.Auth.Revocation.MaxTokenLifetime <= 0 {
+		return fmt.Errorf("auth.revocation.maxTokenLifetime deve ser positivo")
+	}
+
+	switch config.Server.ACME.Storage {
+	case "", "dir", "cache", "database":
+	default:
+		return fmt.Errorf("server.acme.storage desconhecido: %q", config.Server.ACME.Storage)
+	}
+	if config.Server.ACME.DirectoryURL != "" {
+		if u, err := url.Parse(config.Server.ACME.DirectoryURL); err != nil || u.Scheme != "https" {
+			return fmt.Errorf("server.acme.directoryURL inválida: %s", config.Server.ACME.DirectoryURL)
+		}
+	}
+
+	if config.Health.Timeout <= 0 {
+		return fmt.Errorf("health.timeout deve ser positivo")
+	}
+	for _, path := range config.Health.CriticalUpstreams {
+		if !strings.HasPrefix(path, "/") {
+			return fmt.Errorf("health.criticalUpstreams: caminho de rota inválido: %q", path)
+		}
+	}
+
+	if config.Routes.File != "" {
+		switch strings.ToLower(filepath.Ext(config.Routes.File)) {
+		case ".yaml", ".yml", ".json":
+		default:
+			return fmt.Errorf("routes.file deve ter extensão .yaml, .yml ou .json: %s", config.Routes.File)
+		}
+		if config.Routes.Debounce < 0 {
+			return fmt.Errorf("routes.debounce não pode ser negativo")
+		}
+	}
+
+	if config.Routes.Warmup && config.Routes.WarmupRetryInterval <= 0 {
+		return fmt.Errorf("routes.warmupRetryInterval deve ser positivo")
+	}
+
+	if config.Routes.Kubernetes.Enabled && config.Routes.Kubernetes.ResyncInterval <= 0 {
+		return fmt.Errorf("routes.kubernetes.resyncInterval deve ser positivo")
+	}
+	if config.Routes.Webhooks.Timeout <= 0 {
+		return fmt.Errorf("routes.webhooks.timeout deve ser positivo")
+	}
+	if config.Routes.Webhooks.MaxRetries < 0 {
+		return fmt.Errorf("routes.webhooks.maxRetries não pode ser negativo")
+	}
+	if config.Routes.Hooks.QueueSize <= 0 || config.Routes.Hooks.Workers <= 0 {
+		return fmt.Errorf("routes.hooks.queueSize e routes.hooks.workers devem ser positivos")
+	}
+	if config.Routes.Hooks.Timeout <= 0 {
+		return fmt.Errorf("routes.hooks.timeout deve ser positivo")
+	}
+	if config.Routes.Hooks.MaxRetries < 0 {
+		return fmt.Errorf("routes.hooks.maxRetries não pode ser negativo")
+	}
+	for i, endpoint := range config.Routes.Webhooks.Endpoints {
+		if u, err := url.Parse(endpoint.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("routes.webhooks.endpoints[%d]: url inválida: %s", i, endpoint.URL)
+		}
+		if endpoint.Secret == "" {
+			return fmt.Errorf("routes.webhooks.endpoints[%d]: secret obrigatório para assinar as entregas", i)
+		}
+		for _, event := range endpoint.Events {
+			switch event {
+			case "created", "updated", "deleted":
+			default:
+				return fmt.Errorf("routes.webhooks.endpoints[%d]: evento desconhecido: %q", i, event)
+			}
+		}
+	}
+
+	if config.OpenAPI.PublicURL != "" {
+		if u, err := url.Parse(config.OpenAPI.PublicURL); err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("openapi.publicURL inválida: %s", config.OpenAPI.PublicURL)
+		}
+	}
+	if config.OpenAPI.CacheTTL <= 0 || config.OpenAPI.Timeout <= 0 {
+		return fmt.Errorf("openapi.cacheTTL e openapi.timeout devem ser positivos")
+	}
+
+	if config.Server.ForwardedForDepth < 0 {
+		return fmt.Errorf("server.forwardedForDepth não pode ser negativo")
+	}
+	if err := validateCIDRs(config.Server.TrustedProxies); err != nil {
+		return fmt.Errorf("server.trustedProxies: %w", err)
+	}
+
+	// Validar cabeçalhos de segurança
+	if headers := config.Server.SecurityHeaders; headers.Enabled {
+		switch strings.ToUpper(headers.FrameOptions) {
+		case "", "DENY", "SAMEORIGIN":
+		default:
+			return fmt.Errorf("server.securityHeaders.frameOptions inválido: %q", headers.FrameOptions)
+		}
+		if headers.HSTS.Enabled && headers.HSTS.MaxAge < 0 {
+			return fmt.Errorf("server.securityHeaders.hsts.maxAge não pode ser negativo")
+		}
+		if headers.HSTS.Preload && (headers.HSTS.MaxAge < 365*24*time.Hour || !headers.HSTS.IncludeSubDomains) {
+			return fmt.Errorf("server.securityHeaders.hsts.preload exige maxAge de pelo menos 1 ano e includeSubDomains")
+		}
+		if strings.Contains(headers.ContentSecurityPolicy, "{nonce}") && headers.NonceHeader == "" {
+			return fmt.Errorf("server.securityHeaders.nonceHeader é obrigatório quando a CSP usa {nonce}")
+		}
+	}
+
+	// Validar compressão das respostas
+	if config.Server.Compression.Enabled {
+		validEncodings := map[string]bool{"br": true, "zstd": true, "gzip": true, "deflate": true}
+		if len(config.Server.Compression.Encodings) == 0 {
+			return fmt.Errorf("server.compression.encodings não pode ser vazio")
+		}
+		for _, encoding := range config.Server.Compression.Encodings {
+			if !validEncodings[encoding] {
+				return fmt.Errorf("server.compression.encodings: codificação inválida: %s", encoding)
+			}
+		}
+		if config.Server.Compression.MinSize < 0 {
+			return fmt.Errorf("server.compression.minSize não pode ser negativo")
+		}
+	}
+
+	// Validar listener HTTP/3
+	if config.Server.HTTP3.Enabled {
+		if !config.Server.TLS {
+			return fmt.Errorf("server.http3 requer server.tls")
+		}
+		if config.Server.HTTP3.Port < 0 || config.Server.HTTP3.Port > 65535 {
+			return fmt.Errorf("server.http3.port inválida: %d", config.Server.HTTP3.Port)
+		}
+		if config.Server.HTTP3.AltSvcMaxAge <= 0 {
+			return fmt.Errorf("server.http3.altSvcMaxAge deve ser positivo")
+		}
+	}
+
+	// Validar proteção contra sobrecarga
+	if overload := config.Server.Overload; overload.Enabled {
+		if overload.MinLimit <= 0 || overload.MaxLimit < overload.MinLimit {
+			return fmt.Errorf("server.overload: minLimit deve ser positivo e maxLimit não pode ser menor que minLimit")
+		}
+		if overload.InitialLimit < overload.MinLimit || overload.InitialLimit > overload.MaxLimit {
+			return fmt.Errorf("server.overload.initialLimit deve estar entre minLimit e maxLimit")
+		}
+		if overload.TargetLatency < 0 {
+			return fmt.Errorf("server.overload.targetLatency não pode ser negativo")
+		}
+		if overload.Tolerance < 1 {
+			return fmt.Errorf("server.overload.tolerance deve ser de ao menos 1")
+		}
+		if overload.BackoffRatio <= 0 || overload.BackoffRatio >= 1 {
+			return fmt.Errorf("server.overload.backoffRatio deve estar entre 0 e 1 (exclusivos)")
+		}
+		if overload.MaxQueue < 0 || overload.QueueTimeout < 0 {
+			return fmt.Errorf("server.overload: maxQueue e queueTimeout não podem ser negativos")
+		}
+		if overload.RetryAfter < time.Second {
+			return fmt.Errorf("server.overload.retryAfter deve ser de ao menos 1s")
+		}
 	}
 
 	// Validar configuração de TLS
 	if config.Server.TLS {
-		if config.Server.CertFile == "" || config.Server.KeyFile == "" {
-			return fmt.Errorf("TLS habilitado, mas CertFile ou KeyFile não estão definidos")
+		// Sem certificados próprios, os certificados vêm do ACME para os domínios configurados
+		hasACMEDomains := len(config.Server.Domains) > 0 || os.Getenv("SERVER_DOMAINS") != ""
+		if (config.Server.CertFile == "" || config.Server.KeyFile == "") && !hasACMEDomains {
+			return fmt.Errorf("TLS habilitado, mas CertFile/KeyFile ou domínios para o ACME não estão definidos")
 		}
 	}
 
@@ -269,10 +1438,234 @@ func validateConfig(config *Config) error {
 	if !validDrivers[config.Database.Driver] {
 		return fmt.Errorf("driver de banco de dados inválido: %s", config.Database.Driver)
 	}
+	if config.Database.QueryTimeout < 0 {
+		return fmt.Errorf("database.queryTimeout não pode ser negativo")
+	}
+
+	shardNames := map[string]bool{"primary": true}
+	assignedTenants := make(map[string]string)
+	for _, shard := range config.Database.Shards {
+		if shard.Name == "" || shardNames[shard.Name] {
+			return fmt.Errorf("nome de shard inválido ou duplicado: %q", shard.Name)
+		}
+		shardNames[shard.Name] = true
+
+		if !validDrivers[shard.Driver] || shard.DSN == "" {
+			return fmt.Errorf("shard %s requer driver válido e DSN", shard.Name)
+		}
+
+		for _, tenantID := range shard.Tenants {
+			if previous, ok := assignedTenants[tenantID]; ok {
+				return fmt.Errorf("tenant %s atribuído aos shards %s e %s", tenantID, previous, shard.Name)
+			}
+			assignedTenants[tenantID] = shard.Name
+		}
+	}
+
+	tenantHosts := make(map[string]bool)
+	for _, host := range config.Database.TenantHosts {
+		name := strings.ToLower(host.Host)
+		if name == "" || host.Tenant == "" || tenantHosts[name] {
+			return fmt.Errorf("database.tenantHosts: host vazio, duplicado ou sem tenant: %q", host.Host)
+		}
+		tenantHosts[name] = true
+	}
+
+	if config.Database.MaxOpenConns < 0 || config.Database.MaxIdleConns < 0 {
+		return fmt.Errorf("tamanho do pool de conexões não pode ser negativo")
+	}
+
+	if config.Database.MaxOpenConns > 0 && config.Database.MaxIdleConns > config.Database.MaxOpenConns {
+		return fmt.Errorf("database.maxIdleConns (%d) não pode ser maior que database.maxOpenConns (%d)",
+			config.Database.MaxIdleConns, config.Database.MaxOpenConns)
+	}
+
+	// Validar configuração de criptografia em repouso
+	if config.Security.Encryption.Enabled &&
+		len(config.Security.Encryption.Keys) == 0 && config.Security.Encryption.KeySpec == "" &&
+		config.Security.Encryption.KeysSecret == "" {
+		return fmt.Errorf("criptografia em repouso habilitada, mas nenhuma chave foi configurada")
+	}
+
+	switch config.Security.Secrets.Provider {
+	case "", "env", "file", "vault", "aws", "kubernetes":
+	default:
+		return fmt.Errorf("security.secrets.provider desconhecido: %q", config.Security.Secrets.Provider)
+	}
+	if config.Security.Secrets.RefreshInterval < 0 {
+		return fmt.Errorf("security.secrets.refreshInterval não pode ser negativo")
+	}
+	if v := config.Security.Secrets.Vault.KVVersion; v != 0 && v != 1 && v != 2 {
+		return fmt.Errorf("security.secrets.vault.kvVersion deve ser 1 ou 2")
+	}
+
+	// Validar proxy de saída
+	if config.Upstream.EgressProxy.URL != "" {
+		if err := validateProxyURL(config.Upstream.EgressProxy.URL); err != nil {
+			return fmt.Errorf("upstream.egressProxy.url inválida: %w", err)
+		}
+	}
+	if t := config.Upstream.Transport; t.MaxIdleConns < 0 || t.MaxIdleConnsPerHost < 0 || t.MaxConnsPerHost < 0 {
+		return fmt.Errorf("upstream.transport não aceita limites de conexões negativos")
+	}
+	if t := config.Upstream.Transport; t.IdleConnTimeout <= 0 || t.TLSHandshakeTimeout <= 0 || t.DialTimeout <= 0 || t.KeepAlive <= 0 {
+		return fmt.Errorf("upstream.transport: idleConnTimeout, tlsHandshakeTimeout, dialTimeout e keepAlive devem ser positivos")
+	}
+	if config.Upstream.RetryBudget.Enabled {
+		if config.Upstream.RetryBudget.Ratio < 0 || config.Upstream.RetryBudget.Ratio > 1 {
+			return fmt.Errorf("upstream.retryBudget.ratio deve estar entre 0 e 1")
+		}
+		if config.Upstream.RetryBudget.MinRetriesPerSecond < 0 {
+			return fmt.Errorf("upstream.retryBudget.minRetriesPerSecond não pode ser negativo")
+		}
+		if config.Upstream.RetryBudget.Window <= 0 {
+			return fmt.Errorf("upstream.retryBudget.window deve ser positivo")
+		}
+	}
+	if config.Upstream.Kubernetes.Enabled && config.Upstream.Kubernetes.SyncTimeout <= 0 {
+		return fmt.Errorf("upstream.kubernetes.syncTimeout deve ser positivo")
+	}
+	if config.Upstream.Consul.Enabled {
+		u, err := url.Parse(config.Upstream.Consul.Address)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("upstream.consul.address deve ser uma URL http(s)")
+		}
+		if config.Upstream.Consul.RefreshInterval <= 0 {
+			return fmt.Errorf("upstream.consul.refreshInterval deve ser positivo")
+		}
+	}
+	if config.Upstream.DNS.Enabled && config.Upstream.DNS.RefreshInterval <= 0 {
+		return fmt.Errorf("upstream.dns.refreshInterval deve ser positivo")
+	}
+	if resolver := config.Upstream.Resolver; resolver.Enabled {
+		if resolver.TTL <= 0 || resolver.MinTTL <= 0 || resolver.Timeout <= 0 || resolver.RefreshInterval <= 0 {
+			return fmt.Errorf("upstream.resolver: ttl, minTTL, timeout e refreshInterval devem ser positivos")
+		}
+		if resolver.MaxTTL < resolver.MinTTL {
+			return fmt.Errorf("upstream.resolver.maxTTL não pode ser menor que minTTL")
+		}
+		for _, server := range resolver.Servers {
+			if !validDNSServer(server) {
+				return fmt.Errorf("upstream.resolver.servers: servidor inválido: %q", server)
+			}
+		}
+	}
+
+	// Validar API administrativa gRPC
+	if config.Admin.GRPC.Enabled && config.Admin.GRPC.Address == "" {
+		return fmt.Errorf("admin.grpc.address é obrigatório quando admin.grpc.enabled está ativo")
+	}
+	if err := validateCIDRs(config.Admin.IPFilter.Allow); err != nil {
+		return fmt.Errorf("admin.ipFilter.allow: %w", err)
+	}
+	if err := validateCIDRs(config.Admin.IPFilter.Deny); err != nil {
+		return fmt.Errorf("admin.ipFilter.deny: %w", err)
+	}
+
+	// Validar cluster
+	if config.Cluster.Enabled {
+		if config.Cache.Redis.Address == "" {
+			return fmt.Errorf("cluster.enabled requer cache.redis.address")
+		}
+		if config.Cluster.Name == "" {
+			return fmt.Errorf("cluster.name é obrigatório")
+		}
+		if config.Cluster.HeartbeatInterval <= 0 {
+			return fmt.Errorf("cluster.heartbeatInterval deve ser positivo")
+		}
+		if config.Cluster.MemberTTL <= config.Cluster.HeartbeatInterval {
+			return fmt.Errorf("cluster.memberTTL deve ser maior que cluster.heartbeatInterval")
+		}
+		if config.Cluster.LeaseTTL <= config.Cluster.HeartbeatInterval {
+			return fmt.Errorf("cluster.leaseTTL deve ser maior que cluster.heartbeatInterval")
+		}
+		switch config.Cluster.Election {
+		case "", "redis", "kubernetes":
+		default:
+			return fmt.Errorf("cluster.election desconhecido: %q (use redis ou kubernetes)", config.Cluster.Election)
+		}
+	}
+
+	// Validar cotas de uso
+	if config.Quotas.Enabled {
+		if config.Cache.Redis.Address == "" {
+			return fmt.Errorf("quotas.enabled requer cache.redis.address")
+		}
+		if config.Quotas.Daily < 0 || config.Quotas.Monthly < 0 {
+			return fmt.Errorf("quotas.daily e quotas.monthly não podem ser negativos")
+		}
+		if config.Quotas.FlushInterval <= 0 {
+			return fmt.Errorf("quotas.flushInterval deve ser positivo")
+		}
+	}
+
+	switch config.RateLimit.Headers {
+	case "standard", "legacy", "both", "none":
+	default:
+		return fmt.Errorf("ratelimit.headers desconhecido: %q (use standard, legacy, both ou none)", config.RateLimit.Headers)
+	}
+
+	// Validar idempotência
+	if config.Idempotency.Enabled {
+		if config.Cache.Redis.Address == "" {
+			return fmt.Errorf("idempotency.enabled requer cache.redis.address")
+		}
+		if config.Idempotency.Header == "" {
+			return fmt.Errorf("idempotency.header é obrigatório")
+		}
+		if config.Idempotency.TTL <= 0 || config.Idempotency.LockTimeout <= 0 {
+			return fmt.Errorf("idempotency.ttl e idempotency.lockTimeout devem ser positivos")
+		}
+		if config.Idempotency.MaxBodyBytes <= 0 {
+			return fmt.Errorf("idempotency.maxBodyBytes deve ser positivo")
+		}
+	}
+
+	// Validar gravação de tráfego
+	if config.Recording.Enabled {
+		if config.Recording.QueueSize <= 0 {
+			return fmt.Errorf("recording.queueSize deve ser positivo")
+		}
+		if config.Recording.Retention < time.Hour {
+			return fmt.Errorf("recording.retention deve ser de ao menos 1h")
+		}
+	}
+
+	// Validar geolocalização dos clientes
+	if config.Geo.Enabled {
+		if config.Geo.Database == "" && config.Geo.CountryHeader == "" {
+			return fmt.Errorf("geo requer database ou countryHeader")
+		}
+		if config.Geo.CountryHeader != "" && len(config.Server.TrustedProxies) == 0 {
+			return fmt.Errorf("geo.countryHeader requer server.trustedProxies")
+		}
+	}
+
+	// Validar provedor das feature flags das rotas
+	switch config.Flags.Provider {
+	case "", "static":
+	case "ofrep":
+		if config.Flags.URL == "" {
+			return fmt.Errorf("flags.url é obrigatório com o provedor ofrep")
+		}
+		if config.Flags.Timeout <= 0 {
+			return fmt.Errorf("flags.timeout deve ser positivo")
+		}
+	default:
+		return fmt.Errorf("flags.provider inválido: %q (use static ou ofrep)", config.Flags.Provider)
+	}
+	if config.Flags.CacheTTL < 0 {
+		return fmt.Errorf("flags.cacheTTL não pode ser negativo")
+	}
+	for name, flag := range config.Flags.Flags {
+		if flag.Percentage < 0 || flag.Percentage > 100 {
+			return fmt.Errorf("flags.flags.%s.percentage deve estar entre 0 e 100", name)
+		}
+	}
 
 	// Validar configuração de cache
 	if config.Cache.Enabled {
-		validTypes := map[string]bool{"memory": true, "redis": true}
+		validTypes := map[string]bool{"memory": true, "local": true, "redis": true, "memcached": true}
 		if !validTypes[config.Cache.Type] {
 			return fmt.Errorf("tipo de cache inválido: %s", config.Cache.Type)
 		}
@@ -280,7 +1673,79 @@ func validateConfig(config *Config) error {
 		if config.Cache.Type == "redis" && config.Cache.Redis.Address == "" {
 			return fmt.Errorf("tipo de cache redis requer um endereço")
 		}
+
+		if config.Cache.Type == "memcached" {
+			if len(config.Cache.Memcached.Servers) == 0 {
+				return fmt.Errorf("tipo de cache memcached requer ao menos um servidor")
+			}
+			for _, server := range config.Cache.Memcached.Servers {
+				if _, _, err := net.SplitHostPort(server); err != nil {
+					return fmt.Errorf("cache.memcached.servers: endereço inválido %q: %w", server, err)
+				}
+			}
+			if config.Cache.Memcached.Timeout < 0 {
+				return fmt.Errorf("cache.memcached.timeout não pode ser negativo")
+			}
+		}
+
+		if config.Cache.MaxItems < 0 || config.Cache.MaxMemoryMB < 0 {
+			return fmt.Errorf("cache.maxItems e cache.maxMemoryMB não podem ser negativos")
+		}
+	}
+
+	if config.Cache.StaleWhileRevalidate < 0 {
+		return fmt.Errorf("cache.staleWhileRevalidate não pode ser negativo")
+	}
+
+	if config.Cache.Response.Enabled {
+		if config.Cache.Response.DefaultTTL <= 0 {
+			return fmt.Errorf("cache.response.defaultTTL deve ser positivo")
+		}
+		if config.Cache.Response.MaxBodyBytes <= 0 {
+			return fmt.Errorf("cache.response.maxBodyBytes deve ser positivo")
+		}
 	}
 
 	return nil
 }
+
+// validateProxyURL verifica se a URL do proxy usa um esquema suportado
+func validateProxyURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+		return nil
+	default:
+		return fmt.Errorf("esquema de proxy não suportado: %q", u.Scheme)
+	}
+}
+
+// validateCIDRs verifica se as entradas são blocos CIDR ou endereços IP
+func validateCIDRs(entries []string) error {
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if strings.Contains(entry, "/") {
+			if _, err := netip.ParsePrefix(entry); err != nil {
+				return fmt.Errorf("bloco CIDR inválido: %q", entry)
+			}
+			continue
+		}
+		if _, err := netip.ParseAddr(entry); err != nil {
+			return fmt.Errorf("endereço IP inválido: %q", entry)
+		}
+	}
+	return nil
+}
+
+// validDNSServer aceita um servidor DNS como ip ou ip:porta
+func validDNSServer(server string) bool {
+	server = strings.TrimSpace(server)
+	if _, err := netip.ParseAddr(strings.Trim(server, "[]")); err == nil {
+		return true
+	}
+	_, err := netip.ParseAddrPort(server)
+	return err == nil
+}
@@ -0,0 +1,165 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"unicode"
+)
+
+// ReloadableKeys são as configurações aplicadas sem reinício. Alterações nas
+// demais chaves são informadas na recarga e só valem após reiniciar o gateway.
+var ReloadableKeys = []string{
+	"logging.level",
+	"logging.access.sampleRate",
+	"logging.access.routes",
+	"server.limits",
+	"server.securityHeaders",
+	"server.compression",
+	"health.timeout",
+	"rateLimit.headers",
+	"auth.jwtSecret",
+	"auth.jwks.issuer",
+	"auth.jwks.audience",
+	"auth.apiKeys.tiers",
+	"auth.credentials.realm",
+	"auth.credentials.cacheTTL",
+	"auth.credentials.maxSkew",
+	"auth.credentials.maxBodyKiB",
+	"quotas.daily",
+	"quotas.monthly",
+	"flags.flags",
+}
+
+// ReloadFunc aplica a configuração recarregada a um componente
+type ReloadFunc func(cfg *Config)
+
+// ReloadResult resume as chaves alteradas em uma recarga
+type ReloadResult struct {
+	Applied         []string `json:"applied"`         // aplicadas sem reinício
+	RestartRequired []string `json:"restartRequired"` // ignoradas até o próximo reinício
+}
+
+// Snapshot mantém a configuração em vigor. Cada recarga lê o arquivo e as
+// variáveis de ambiente de novo, valida o resultado e troca a configuração de
+// forma atômica; quem lê a configuração vê sempre uma versão completa.
+type Snapshot struct {
+	path    string
+	current atomic.Pointer[Config]
+
+	mu       sync.Mutex // serializa as recargas
+	handlers []ReloadFunc
+}
+
+// NewSnapshot cria o snapshot com a configuração carregada de path
+func NewSnapshot(path string, cfg *Config) *Snapshot {
+	s := &Snapshot{path: path}
+	s.current.Store(cfg)
+	return s
+}
+
+// Load retorna a configuração em vigor. O valor retornado não deve ser alterado.
+func (s *Snapshot) Load() *Config {
+	return s.current.Load()
+}
+
+// OnReload registra uma função chamada após cada recarga com alterações
+// aplicáveis. Deve ser chamado antes da primeira recarga.
+func (s *Snapshot) OnReload(fn ReloadFunc) {
+	s.handlers = append(s.handlers, fn)
+}
+
+// Reload lê a configuração de novo e aplica as chaves de ReloadableKeys. Uma
+// configuração inválida é rejeitada por inteiro, mantendo a atual.
+func (s *Snapshot) Reload() (ReloadResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	loaded, err := LoadConfig(s.path)
+	if err != nil {
+		return ReloadResult{}, err
+	}
+
+	previous := s.current.Load()
+	result := ReloadResult{Applied: []string{}, RestartRequired: []string{}}
+	var changed []string
+	diffKeys("", reflect.ValueOf(previous).Elem(), reflect.ValueOf(loaded).Elem(), &changed)
+
+	// A configuração em vigor recebe apenas as chaves recarregáveis, para que
+	// reflita o que de fato vale até o reinício
+	next := *previous
+	for _, key := range changed {
+		if !reloadable(key) {
+			result.RestartRequired = append(result.RestartRequired, key)
+			continue
+		}
+		result.Applied = append(result.Applied, key)
+		fieldByKey(reflect.ValueOf(&next).Elem(), key).Set(fieldByKey(reflect.ValueOf(loaded).Elem(), key))
+	}
+	if len(result.Applied) == 0 {
+		return result, nil
+	}
+
+	s.current.Store(&next)
+	for _, handler := range s.handlers {
+		handler(&next)
+	}
+	return result, nil
+}
+
+// reloadable indica se a chave pertence a uma configuração recarregável
+func reloadable(key string) bool {
+	for _, prefix := range ReloadableKeys {
+		if key == prefix || strings.HasPrefix(key, prefix+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// diffKeys lista as chaves cujos valores diferem entre as configurações. Em
+// estruturas, compara campo a campo; demais valores são comparados inteiros.
+func diffKeys(prefix string, a, b reflect.Value, out *[]string) {
+	if a.Kind() != reflect.Struct {
+		if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+			*out = append(*out, prefix)
+		}
+		return
+	}
+	for i := 0; i < a.NumField(); i++ {
+		field := a.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		key := keyName(field.Name)
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		diffKeys(key, a.Field(i), b.Field(i), out)
+	}
+}
+
+// fieldByKey retorna o campo da chave (ex.: "server.limits")
+func fieldByKey(v reflect.Value, key string) reflect.Value {
+	for _, part := range strings.Split(key, ".") {
+		v = v.FieldByNameFunc(func(name string) bool {
+			return strings.EqualFold(name, part)
+		})
+	}
+	return v
+}
+
+// keyName converte o nome do campo no formato das chaves do arquivo de
+// configuração (ex.: JWTSecret -> jwtSecret, MaxURLLength -> maxURLLength)
+func keyName(name string) string {
+	runes := []rune(name)
+	upper := 0
+	for upper < len(runes) && unicode.IsUpper(runes[upper]) {
+		upper++
+	}
+	if upper > 1 && upper < len(runes) {
+		upper--
+	}
+	return strings.ToLower(string(runes[:upper])) + string(runes[upper:])
+}
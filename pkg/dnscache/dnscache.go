@@ -0,0 +1,371 @@
+// Package dnscache resolve os nomes dos backends com cache e nova consulta ao
+// expirar, para que mudanças no DNS cheguem ao gateway sem reinício.
+package dnscache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/diillson/api-gateway-go/pkg/config"
+	"go.uber.org/zap"
+)
+
+// idleEntryTimeout é o tempo sem uso após o qual um nome deixa de ser
+// consultado e sai do cache
+const idleEntryTimeout = 10 * time.Minute
+
+// Resolver mantém os endereços de cada nome até o TTL expirar. As entradas
+// expiradas são consultadas de novo em segundo plano; enquanto isso, e em
+// falhas da consulta, os últimos endereços conhecidos continuam em uso.
+type Resolver struct {
+	cfg     config.UpstreamResolverConfig
+	servers []string // servidores padrão, com porta
+	system  *net.Resolver
+	logger  *zap.Logger
+
+	mu       sync.Mutex
+	entries  map[string]*entry
+	onChange []func(host string)
+}
+
+// entry é o estado de um nome consultado em um conjunto de servidores
+type entry struct {
+	host    string
+	servers []string
+
+	ready      chan struct{} // fechado após a primeira consulta, com ou sem sucesso
+	readyOnce  sync.Once
+	refreshing atomic.Bool
+	lastUsed   atomic.Int64 // unix nano
+	next       atomic.Uint32
+
+	mu      sync.RWMutex
+	addrs   []netip.Addr
+	expires time.Time
+	err     error
+}
+
+// New cria o resolvedor. Sem servidores configurados, as consultas usam o
+// resolvedor do sistema, que respeita /etc/hosts e os domínios de busca.
+func New(cfg config.UpstreamResolverConfig, logger *zap.Logger) (*Resolver, error) {
+	servers, err := NormalizeServers(cfg.Servers)
+	if err != nil {
+		return nil, err
+	}
+	return &Resolver{
+		cfg:     cfg,
+		servers: servers,
+		system:  net.DefaultResolver,
+		logger:  logger,
+		entries: make(map[string]*entry),
+	}, nil
+}
+
+// NormalizeServers valida os servidores DNS (ip ou ip:porta) e acrescenta a
+// porta 53 quando omitida
+func NormalizeServers(servers []string) ([]string, error) {
+	normalized := make([]string, 0, len(servers))
+	for _, server := range servers {
+		server = strings.TrimSpace(server)
+		if addr, err := netip.ParseAddr(strings.Trim(server, "[]")); err == nil {
+			normalized = append(normalized, netip.AddrPortFrom(addr, 53).String())
+			continue
+		}
+		addrPort, err := netip.ParseAddrPort(server)
+		if err != nil {
+			return nil, fmt.Errorf("servidor DNS inválido: %q", server)
+		}
+		normalized = append(normalized, addrPort.String())
+	}
+	return normalized, nil
+}
+
+// OnChange registra uma função chamada quando os endereços de um nome mudam
+func (r *Resolver) OnChange(fn func(host string)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onChange = append(r.onChange, fn)
+}
+
+// Lookup retorna os endereços do nome, consultados nos servidores informados
+// ou, sem eles, nos servidores padrão. Apenas a primeira consulta de um nome
+// aguarda a resposta; as seguintes usam o cache.
+func (r *Resolver) Lookup(ctx context.Context, host string, servers []string) ([]netip.Addr, error) {
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return []netip.Addr{addr}, nil
+	}
+	_, addrs, err := r.lookup(ctx, host, servers)
+	return addrs, err
+}
+
+func (r *Resolver) lookup(ctx context.Context, host string, servers []string) (*entry, []netip.Addr, error) {
+	e, created := r.entry(host, servers)
+	if created {
+		r.refresh(e)
+		e.refreshing.Store(false)
+	}
+	select {
+	case <-e.ready:
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if len(e.addrs) == 0 {
+		return nil, nil, e.err
+	}
+	return e, e.addrs, nil
+}
+
+// entry retorna o estado do nome, criando-o se necessário
+func (r *Resolver) entry(host string, servers []string) (*entry, bool) {
+	if len(servers) == 0 {
+		servers = r.servers
+	}
+	key := strings.ToLower(host) + "@" + strings.Join(servers, ",")
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.entries[key]
+	if !ok {
+		e = &entry{host: host, servers: servers, ready: make(chan struct{})}
+		e.refreshing.Store(true) // a primeira consulta é feita por quem criou a entrada
+		r.entries[key] = e
+	}
+	e.lastUsed.Store(time.Now().UnixNano())
+	return e, !ok
+}
+
+// Run consulta de novo os nomes expirados a cada intervalo até ctx ser
+// cancelado, descartando os que deixaram de ser usados
+func (r *Resolver) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			for _, e := range r.expired(now) {
+				if !e.refreshing.CompareAndSwap(false, true) {
+					continue // consulta anterior ainda em andamento
+				}
+				go func(e *entry) {
+					defer e.refreshing.Store(false)
+					r.refresh(e)
+				}(e)
+			}
+		}
+	}
+}
+
+// expired retorna as entradas com TTL vencido, removendo as sem uso recente
+func (r *Resolver) expired(now time.Time) []*entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var expired []*entry
+	for key, e := range r.entries {
+		if now.Sub(time.Unix(0, e.lastUsed.Load())) > idleEntryTimeout {
+			delete(r.entries, key)
+			continue
+		}
+		e.mu.RLock()
+		due := !now.Before(e.expires)
+		e.mu.RUnlock()
+		if due {
+			expired = append(expired, e)
+		}
+	}
+	return expired
+}
+
+// refresh consulta o nome e atualiza seus endereços
+func (r *Resolver) refresh(e *entry) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.cfg.Timeout)
+	addrs, ttl, err := r.resolve(ctx, e.host, e.servers)
+	cancel()
+
+	changed := false
+	e.mu.Lock()
+	if err != nil {
+		// Nova tentativa após minTTL, mantendo os últimos endereços; apenas a
+		// primeira falha seguida é registrada como aviso
+		level := zap.WarnLevel
+		if e.err != nil {
+			level = zap.DebugLevel
+		}
+		r.logger.Log(level, "Falha ao resolver endereço do backend",
+			zap.String("host", e.host),
+			zap.Bool("usingLastKnown", len(e.addrs) > 0),
+			zap.Error(err))
+		e.err = err
+		e.expires = time.Now().Add(r.cfg.MinTTL)
+	} else {
+		changed = len(e.addrs) > 0 && !sameAddrs(e.addrs, addrs)
+		e.addrs = addrs
+		e.err = nil
+		e.expires = time.Now().Add(ttl)
+	}
+	e.mu.Unlock()
+
+	e.readyOnce.Do(func() { close(e.ready) })
+
+	if changed {
+		r.logger.Info("Endereços do backend alterados no DNS",
+			zap.String("host", e.host),
+			zap.Int("addresses", len(addrs)),
+			zap.Duration("ttl", ttl))
+
+		r.mu.Lock()
+		callbacks := append([]func(string){}, r.onChange...)
+		r.mu.Unlock()
+		for _, fn := range callbacks {
+			fn(e.host)
+		}
+	}
+}
+
+// resolve consulta os endereços do nome e o tempo pelo qual valem
+func (r *Resolver) resolve(ctx context.Context, host string, servers []string) ([]netip.Addr, time.Duration, error) {
+	if len(servers) == 0 {
+		// O resolvedor do sistema não informa o TTL dos registros
+		addrs, err := r.system.LookupNetIP(ctx, "ip", host)
+		if err != nil {
+			return nil, 0, err
+		}
+		return unmapAll(addrs), r.cfg.TTL, nil
+	}
+
+	addrs, ttl, err := lookupServers(ctx, host, servers)
+	if err != nil {
+		return nil, 0, err
+	}
+	if ttl < r.cfg.MinTTL {
+		ttl = r.cfg.MinTTL
+	}
+	if r.cfg.MaxTTL > 0 && ttl > r.cfg.MaxTTL {
+		ttl = r.cfg.MaxTTL
+	}
+	return addrs, ttl, nil
+}
+
+// DialContext retorna uma função de conexão para http.Transport que resolve o
+// nome pelo cache. As conexões novas se alternam entre os endereços; em caso
+// de falha, os demais são tentados dentro do prazo do dialer.
+func (r *Resolver) DialContext(dialer *net.Dialer, servers []string) func(ctx context.Context, network, address string) (net.Conn, error) {
+	if normalized, err := NormalizeServers(servers); err == nil {
+		servers = normalized
+	}
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		host, portText, err := net.SplitHostPort(address)
+		if err != nil {
+			return dialer.DialContext(ctx, network, address)
+		}
+		port, err := strconv.ParseUint(portText, 10, 16)
+		if err != nil {
+			return dialer.DialContext(ctx, network, address)
+		}
+
+		if _, err := netip.ParseAddr(host); err == nil {
+			return dialer.DialContext(ctx, network, address)
+		}
+
+		e, addrs, err := r.lookup(ctx, host, servers)
+		if err != nil {
+			return nil, &net.OpError{Op: "dial", Net: network, Err: err}
+		}
+		addrs = filterNetwork(addrs, network)
+		if len(addrs) == 0 {
+			return nil, &net.OpError{Op: "dial", Net: network, Err: &net.DNSError{
+				Err: "nenhum endereço compatível com " + network, Name: host, IsNotFound: true,
+			}}
+		}
+
+		if dialer.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, dialer.Timeout)
+			defer cancel()
+		}
+
+		start := int(e.next.Add(1))
+		var firstErr error
+		for i := range addrs {
+			addr := addrs[(start+i)%len(addrs)]
+			conn, err := dialer.DialContext(ctx, network, netip.AddrPortFrom(addr, uint16(port)).String())
+			if err == nil {
+				return conn, nil
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+			if ctx.Err() != nil {
+				break
+			}
+		}
+		return nil, firstErr
+	}
+}
+
+// filterNetwork mantém os endereços da família exigida pela rede
+func filterNetwork(addrs []netip.Addr, network string) []netip.Addr {
+	switch network {
+	case "tcp4", "udp4":
+		return filterAddrs(addrs, netip.Addr.Is4)
+	case "tcp6", "udp6":
+		return filterAddrs(addrs, netip.Addr.Is6)
+	default:
+		return addrs
+	}
+}
+
+func filterAddrs(addrs []netip.Addr, keep func(netip.Addr) bool) []netip.Addr {
+	filtered := make([]netip.Addr, 0, len(addrs))
+	for _, addr := range addrs {
+		if keep(addr) {
+			filtered = append(filtered, addr)
+		}
+	}
+	return filtered
+}
+
+func unmapAll(addrs []netip.Addr) []netip.Addr {
+	unmapped := make([]netip.Addr, len(addrs))
+	for i, addr := range addrs {
+		unmapped[i] = addr.Unmap()
+	}
+	return unmapped
+}
+
+// sameAddrs compara dois conjuntos de endereços, ignorando a ordem, que
+// muda a cada resposta em servidores com round-robin
+func sameAddrs(a, b []netip.Addr) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sortedA := append([]netip.Addr(nil), a...)
+	sortedB := append([]netip.Addr(nil), b...)
+	sort.Slice(sortedA, func(i, j int) bool { return sortedA[i].Less(sortedA[j]) })
+	sort.Slice(sortedB, func(i, j int) bool { return sortedB[i].Less(sortedB[j]) })
+	for i := range sortedA {
+		if sortedA[i] != sortedB[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// errNoAddresses indica uma resposta sem registros A ou AAAA
+var errNoAddresses = errors.New("nenhum endereço encontrado")
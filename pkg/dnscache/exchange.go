@@ -0,0 +1,185 @@
+package dnscache
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net"
+	"net/netip"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// lookupServers consulta os registros A e AAAA do nome nos servidores, em
+// ordem, até um deles responder. O TTL é o menor entre os registros da
+// resposta, incluindo os CNAME da cadeia. O nome é consultado como absoluto,
+// sem os domínios de busca do sistema.
+func lookupServers(ctx context.Context, host string, servers []string) ([]netip.Addr, time.Duration, error) {
+	fqdn := host
+	if !strings.HasSuffix(fqdn, ".") {
+		fqdn += "."
+	}
+	name, err := dnsmessage.NewName(fqdn)
+	if err != nil {
+		return nil, 0, &net.DNSError{Err: "nome inválido", Name: host}
+	}
+
+	var lastErr error
+	for _, server := range servers {
+		addrs, ttl, err := lookupServer(ctx, host, name, server)
+		if err == nil {
+			return addrs, ttl, nil
+		}
+		lastErr = err
+
+		// Nome inexistente é uma resposta definitiva; não há por que
+		// consultar os demais servidores
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			break
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, 0, lastErr
+}
+
+// lookupServer consulta os registros A e AAAA do nome em um servidor
+func lookupServer(ctx context.Context, host string, name dnsmessage.Name, server string) ([]netip.Addr, time.Duration, error) {
+	var addrs []netip.Addr
+	minTTL := uint32(math.MaxUint32)
+
+	for _, qtype := range []dnsmessage.Type{dnsmessage.TypeA, dnsmessage.TypeAAAA} {
+		msg, err := exchange(ctx, server, name, qtype)
+		if err != nil {
+			return nil, 0, &net.DNSError{Err: err.Error(), Name: host, Server: server, IsTemporary: true}
+		}
+		switch msg.RCode {
+		case dnsmessage.RCodeSuccess:
+		case dnsmessage.RCodeNameError:
+			return nil, 0, &net.DNSError{Err: "nome inexistente", Name: host, Server: server, IsNotFound: true}
+		default:
+			return nil, 0, &net.DNSError{
+				Err: "servidor DNS respondeu " + msg.RCode.String(), Name: host, Server: server, IsTemporary: true,
+			}
+		}
+
+		for _, answer := range msg.Answers {
+			switch body := answer.Body.(type) {
+			case *dnsmessage.AResource:
+				addrs = append(addrs, netip.AddrFrom4(body.A))
+			case *dnsmessage.AAAAResource:
+				addrs = append(addrs, netip.AddrFrom16(body.AAAA).Unmap())
+			case *dnsmessage.CNAMEResource:
+			default:
+				continue
+			}
+			if answer.Header.TTL < minTTL {
+				minTTL = answer.Header.TTL
+			}
+		}
+	}
+
+	if len(addrs) == 0 {
+		return nil, 0, &net.DNSError{Err: errNoAddresses.Error(), Name: host, Server: server, IsNotFound: true}
+	}
+	return addrs, time.Duration(minTTL) * time.Second, nil
+}
+
+// exchange envia a consulta por UDP e a repete por TCP quando a resposta vem
+// truncada
+func exchange(ctx context.Context, server string, name dnsmessage.Name, qtype dnsmessage.Type) (*dnsmessage.Message, error) {
+	id := uint16(rand.Uint32())
+	query := dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: id, RecursionDesired: true},
+		Questions: []dnsmessage.Question{{Name: name, Type: qtype, Class: dnsmessage.ClassINET}},
+	}
+	packed, err := query.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := exchangeUDP(ctx, server, packed, id)
+	if err == nil && msg.Truncated {
+		msg, err = exchangeTCP(ctx, server, packed, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(msg.Questions) != 1 || msg.Questions[0].Type != qtype ||
+		!strings.EqualFold(msg.Questions[0].Name.String(), name.String()) {
+		return nil, errors.New("resposta DNS não corresponde à consulta")
+	}
+	return msg, nil
+}
+
+func exchangeUDP(ctx context.Context, server string, query []byte, id uint16) (*dnsmessage.Message, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "udp", server)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	if _, err := conn.Write(query); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, 1232)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return nil, err
+		}
+		var msg dnsmessage.Message
+		if err := msg.Unpack(buf[:n]); err != nil || msg.ID != id || !msg.Response {
+			continue // resposta de outra consulta ou inválida
+		}
+		return &msg, nil
+	}
+}
+
+func exchangeTCP(ctx context.Context, server string, query []byte, id uint16) (*dnsmessage.Message, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", server)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	framed := make([]byte, 2+len(query))
+	binary.BigEndian.PutUint16(framed, uint16(len(query)))
+	copy(framed[2:], query)
+	if _, err := conn.Write(framed); err != nil {
+		return nil, err
+	}
+
+	var length [2]byte
+	if _, err := io.ReadFull(conn, length[:]); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, binary.BigEndian.Uint16(length[:]))
+	if _, err := io.ReadFull(conn, buf); err != nil {
+		return nil, err
+	}
+	var msg dnsmessage.Message
+	if err := msg.Unpack(buf); err != nil {
+		return nil, fmt.Errorf("resposta DNS inválida: %w", err)
+	}
+	if msg.ID != id || !msg.Response {
+		return nil, errors.New("resposta DNS não corresponde à consulta")
+	}
+	return &msg, nil
+}
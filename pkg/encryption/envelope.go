@@ -0,0 +1,217 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Prefixo que identifica valores cifrados pelo envelope
+const valuePrefix = "enc:v1:"
+
+var (
+	// ErrKeyNotFound indica que a chave usada para cifrar um valor não está disponível
+	ErrKeyNotFound = errors.New("chave de criptografia não encontrada")
+	// ErrInvalidCiphertext indica que o valor cifrado está corrompido ou em formato inválido
+	ErrInvalidCiphertext = errors.New("valor cifrado inválido")
+)
+
+// KeyProvider fornece as chaves mestras (KEKs) usadas para proteger as chaves de dados
+type KeyProvider interface {
+	// ActiveKeyID retorna o identificador da chave usada para novas cifragens
+	ActiveKeyID() string
+
+	// Key retorna a chave mestra associada ao identificador
+	Key(id string) ([]byte, error)
+}
+
+// Envelope implementa criptografia envelope: cada valor é cifrado com uma chave de
+// dados aleatória (DEK), que por sua vez é cifrada com a chave mestra ativa (KEK).
+//
+// Formato armazenado: enc:v1:<id da chave>:<DEK cifrada>:<valor cifrado>
+type Envelope struct {
+	keys KeyProvider
+}
+
+// NewEnvelope cria um novo envelope de criptografia
+func NewEnvelope(keys KeyProvider) (*Envelope, error) {
+	if keys == nil {
+		return nil, errors.New("provedor de chaves é obrigatório")
+	}
+
+	activeKey, err := keys.Key(keys.ActiveKeyID())
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter chave ativa: %w", err)
+	}
+	if len(activeKey) != 32 {
+		return nil, fmt.Errorf("chave ativa deve ter 32 bytes, recebido %d", len(activeKey))
+	}
+
+	return &Envelope{keys: keys}, nil
+}
+
+// IsEncrypted verifica se o valor está no formato cifrado do envelope
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, valuePrefix)
+}
+
+// EncryptString cifra um texto com a chave ativa
+func (e *Envelope) EncryptString(plaintext string) (string, error) {
+	return e.Encrypt([]byte(plaintext))
+}
+
+// Encrypt cifra um valor com uma nova chave de dados protegida pela chave ativa
+func (e *Envelope) Encrypt(plaintext []byte) (string, error) {
+	keyID := e.keys.ActiveKeyID()
+	kek, err := e.keys.Key(keyID)
+	if err != nil {
+		return "", fmt.Errorf("falha ao obter chave %s: %w", keyID, err)
+	}
+
+	dek := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, dek); err != nil {
+		return "", fmt.Errorf("falha ao gerar chave de dados: %w", err)
+	}
+
+	wrappedKey, err := seal(kek, dek)
+	if err != nil {
+		return "", fmt.Errorf("falha ao cifrar chave de dados: %w", err)
+	}
+
+	ciphertext, err := seal(dek, plaintext)
+	if err != nil {
+		return "", fmt.Errorf("falha ao cifrar valor: %w", err)
+	}
+
+	return valuePrefix + keyID + ":" +
+		base64.RawStdEncoding.EncodeToString(wrappedKey) + ":" +
+		base64.RawStdEncoding.EncodeToString(ciphertext), nil
+}
+
+// DecryptString decifra um valor. Valores que não estão cifrados são retornados
+// sem alteração, o que permite migrar dados legados gradualmente.
+func (e *Envelope) DecryptString(value string) (string, error) {
+	plaintext, err := e.Decrypt(value)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// Decrypt decifra um valor produzido por Encrypt
+func (e *Envelope) Decrypt(value string) ([]byte, error) {
+	if !IsEncrypted(value) {
+		return []byte(value), nil
+	}
+
+	keyID, wrappedKey, ciphertext, err := parseValue(value)
+	if err != nil {
+		return nil, err
+	}
+
+	kek, err := e.keys.Key(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter chave %s: %w", keyID, err)
+	}
+
+	dek, err := open(kek, wrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao decifrar chave de dados: %w", err)
+	}
+
+	plaintext, err := open(dek, ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao decifrar valor: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+// NeedsReEncryption indica se o valor deve ser cifrado novamente com a chave ativa,
+// seja por estar em texto puro ou por ter sido cifrado com uma chave antiga
+func (e *Envelope) NeedsReEncryption(value string) bool {
+	if value == "" {
+		return false
+	}
+	if !IsEncrypted(value) {
+		return true
+	}
+
+	keyID, _, _, err := parseValue(value)
+	if err != nil {
+		return false
+	}
+	return keyID != e.keys.ActiveKeyID()
+}
+
+// ReEncryptString decifra o valor e o cifra novamente com a chave ativa
+func (e *Envelope) ReEncryptString(value string) (string, error) {
+	plaintext, err := e.Decrypt(value)
+	if err != nil {
+		return "", err
+	}
+	return e.Encrypt(plaintext)
+}
+
+// parseValue separa os componentes de um valor cifrado
+func parseValue(value string) (string, []byte, []byte, error) {
+	parts := strings.Split(strings.TrimPrefix(value, valuePrefix), ":")
+	if len(parts) != 3 || parts[0] == "" {
+		return "", nil, nil, ErrInvalidCiphertext
+	}
+
+	wrappedKey, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", nil, nil, ErrInvalidCiphertext
+	}
+
+	ciphertext, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", nil, nil, ErrInvalidCiphertext
+	}
+
+	return parts[0], wrappedKey, ciphertext, nil
+}
+
+// seal cifra os dados com AES-256-GCM, prefixando o nonce
+func seal(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// open decifra dados produzidos por seal
+func open(key, data []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < gcm.NonceSize() {
+		return nil, ErrInvalidCiphertext
+	}
+
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
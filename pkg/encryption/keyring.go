@@ -0,0 +1,94 @@
+package encryption
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Keyring é um KeyProvider em memória com suporte a múltiplas chaves, permitindo
+// rotação: novas cifragens usam a chave ativa e as antigas continuam legíveis
+type Keyring struct {
+	mu       sync.RWMutex
+	activeID string
+	keys     map[string][]byte
+}
+
+// NewKeyring cria um keyring a partir de chaves codificadas em base64
+func NewKeyring(activeID string, encodedKeys map[string]string) (*Keyring, error) {
+	keys := make(map[string][]byte, len(encodedKeys))
+	for id, encoded := range encodedKeys {
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil {
+			return nil, fmt.Errorf("chave %s não está em base64 válido: %w", id, err)
+		}
+		if len(key) != 32 {
+			return nil, fmt.Errorf("chave %s deve ter 32 bytes, recebido %d", id, len(key))
+		}
+		keys[id] = key
+	}
+
+	if activeID == "" && len(keys) == 1 {
+		for id := range keys {
+			activeID = id
+		}
+	}
+
+	if _, ok := keys[activeID]; !ok {
+		return nil, fmt.Errorf("chave ativa %q não está entre as chaves configuradas", activeID)
+	}
+
+	return &Keyring{activeID: activeID, keys: keys}, nil
+}
+
+// ParseKeySpec interpreta chaves no formato "id1:base64,id2:base64", usado em
+// variáveis de ambiente onde mapas não podem ser expressos diretamente
+func ParseKeySpec(spec string) (map[string]string, error) {
+	keys := make(map[string]string)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, ":", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("entrada de chave inválida: %q", entry)
+		}
+		keys[parts[0]] = parts[1]
+	}
+	return keys, nil
+}
+
+// ActiveKeyID implementa KeyProvider
+func (k *Keyring) ActiveKeyID() string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.activeID
+}
+
+// Key implementa KeyProvider
+func (k *Keyring) Key(id string) ([]byte, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	key, ok := k.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, id)
+	}
+	return key, nil
+}
+
+// KeyIDs retorna os identificadores das chaves conhecidas
+func (k *Keyring) KeyIDs() []string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	ids := make([]string, 0, len(k.keys))
+	for id := range k.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
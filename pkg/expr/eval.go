@@ -0,0 +1,598 @@
+package expr
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// node é um nó da árvore da expressão
+type node interface {
+	eval(vars map[string]interface{}) (interface{}, error)
+	children() []node
+}
+
+type literalNode struct{ value interface{} }
+
+type identNode struct{ name string }
+
+type listNode struct{ items []node }
+
+type selectNode struct {
+	operand node
+	field   string
+}
+
+type indexNode struct {
+	operand node
+	index   node
+}
+
+type unaryNode struct {
+	op      string
+	operand node
+}
+
+type binaryNode struct {
+	op          string
+	left, right node
+}
+
+type logicalNode struct {
+	op          string
+	left, right node
+}
+
+type conditionalNode struct {
+	cond, then, otherwise node
+}
+
+// callNode é uma função (target nil) ou um método sobre target
+type callNode struct {
+	name   string
+	target node
+	args   []node
+}
+
+func (n *literalNode) children() []node     { return nil }
+func (n *identNode) children() []node       { return nil }
+func (n *listNode) children() []node        { return n.items }
+func (n *selectNode) children() []node      { return []node{n.operand} }
+func (n *indexNode) children() []node       { return []node{n.operand, n.index} }
+func (n *unaryNode) children() []node       { return []node{n.operand} }
+func (n *binaryNode) children() []node      { return []node{n.left, n.right} }
+func (n *logicalNode) children() []node     { return []node{n.left, n.right} }
+func (n *conditionalNode) children() []node { return []node{n.cond, n.then, n.otherwise} }
+
+func (n *callNode) children() []node {
+	if n.target == nil {
+		return n.args
+	}
+	return append([]node{n.target}, n.args...)
+}
+
+func walk(n node, visit func(node)) {
+	visit(n)
+	for _, child := range n.children() {
+		walk(child, visit)
+	}
+}
+
+// Eval avalia a expressão com as variáveis informadas
+func (p *Program) Eval(vars map[string]interface{}) (interface{}, error) {
+	return p.root.eval(vars)
+}
+
+// EvalBool avalia a expressão, que deve resultar em um booleano
+func (p *Program) EvalBool(vars map[string]interface{}) (bool, error) {
+	value, err := p.Eval(vars)
+	if err != nil {
+		return false, err
+	}
+	result, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("a expressão resultou em %s, esperado bool", typeName(value))
+	}
+	return result, nil
+}
+
+func (n *literalNode) eval(map[string]interface{}) (interface{}, error) {
+	return n.value, nil
+}
+
+func (n *identNode) eval(vars map[string]interface{}) (interface{}, error) {
+	value, ok := vars[n.name]
+	if !ok {
+		return nil, fmt.Errorf("variável desconhecida %q", n.name)
+	}
+	return normalize(value), nil
+}
+
+func (n *listNode) eval(vars map[string]interface{}) (interface{}, error) {
+	items := make([]interface{}, 0, len(n.items))
+	for _, item := range n.items {
+		value, err := item.eval(vars)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, value)
+	}
+	return items, nil
+}
+
+func (n *selectNode) eval(vars map[string]interface{}) (interface{}, error) {
+	operand, err := n.operand.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	return lookup(operand, n.field)
+}
+
+func (n *indexNode) eval(vars map[string]interface{}) (interface{}, error) {
+	operand, err := n.operand.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	index, err := n.index.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	if list, ok := operand.([]interface{}); ok {
+		i, ok := index.(float64)
+		if !ok || i != math.Trunc(i) {
+			return nil, fmt.Errorf("índice de lista inválido: %v", index)
+		}
+		if i < 0 || int(i) >= len(list) {
+			return nil, nil
+		}
+		return list[int(i)], nil
+	}
+	key, ok := index.(string)
+	if !ok {
+		return nil, fmt.Errorf("chave de mapa inválida: %v", index)
+	}
+	return lookup(operand, key)
+}
+
+// lookup busca o campo no mapa; campos ausentes e valores nulos resultam em null
+func lookup(operand interface{}, field string) (interface{}, error) {
+	switch m := operand.(type) {
+	case nil:
+		return nil, nil
+	case map[string]interface{}:
+		return normalize(m[field]), nil
+	}
+	return nil, fmt.Errorf("não é possível acessar %q em %s", field, typeName(operand))
+}
+
+func (n *unaryNode) eval(vars map[string]interface{}) (interface{}, error) {
+	value, err := n.operand.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "!":
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("operador ! aplicado a %s", typeName(value))
+		}
+		return !b, nil
+	default:
+		f, ok := value.(float64)
+		if !ok {
+			return nil, fmt.Errorf("operador - aplicado a %s", typeName(value))
+		}
+		return -f, nil
+	}
+}
+
+func (n *logicalNode) eval(vars map[string]interface{}) (interface{}, error) {
+	left, err := n.left.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	l, ok := left.(bool)
+	if !ok {
+		return nil, fmt.Errorf("operador %s aplicado a %s", n.op, typeName(left))
+	}
+	if (n.op == "&&" && !l) || (n.op == "||" && l) {
+		return l, nil
+	}
+	right, err := n.right.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	r, ok := right.(bool)
+	if !ok {
+		return nil, fmt.Errorf("operador %s aplicado a %s", n.op, typeName(right))
+	}
+	return r, nil
+}
+
+func (n *conditionalNode) eval(vars map[string]interface{}) (interface{}, error) {
+	cond, err := n.cond.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	b, ok := cond.(bool)
+	if !ok {
+		return nil, fmt.Errorf("condição resultou em %s, esperado bool", typeName(cond))
+	}
+	if b {
+		return n.then.eval(vars)
+	}
+	return n.otherwise.eval(vars)
+}
+
+func (n *binaryNode) eval(vars map[string]interface{}) (interface{}, error) {
+	left, err := n.left.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "==":
+		return equal(left, right), nil
+	case "!=":
+		return !equal(left, right), nil
+	case "in":
+		return contains(right, left)
+	case "+":
+		if l, ok := left.(string); ok {
+			if r, ok := right.(string); ok {
+				return l + r, nil
+			}
+		}
+		if l, ok := left.([]interface{}); ok {
+			if r, ok := right.([]interface{}); ok {
+				return append(append([]interface{}{}, l...), r...), nil
+			}
+		}
+	}
+
+	if l, ok := left.(string); ok {
+		if r, ok := right.(string); ok {
+			switch n.op {
+			case "<":
+				return l < r, nil
+			case "<=":
+				return l <= r, nil
+			case ">":
+				return l > r, nil
+			case ">=":
+				return l >= r, nil
+			}
+		}
+	}
+
+	l, lok := left.(float64)
+	r, rok := right.(float64)
+	if !lok || !rok {
+		return nil, fmt.Errorf("operador %s aplicado a %s e %s", n.op, typeName(left), typeName(right))
+	}
+	switch n.op {
+	case "<":
+		return l < r, nil
+	case "<=":
+		return l <= r, nil
+	case ">":
+		return l > r, nil
+	case ">=":
+		return l >= r, nil
+	case "+":
+		return l + r, nil
+	case "-":
+		return l - r, nil
+	case "*":
+		return l * r, nil
+	case "/":
+		if r == 0 {
+			return nil, fmt.Errorf("divisão por zero")
+		}
+		return l / r, nil
+	default:
+		if r == 0 {
+			return nil, fmt.Errorf("divisão por zero")
+		}
+		return math.Mod(l, r), nil
+	}
+}
+
+// equal compara dois valores; tipos diferentes nunca são iguais
+func equal(a, b interface{}) bool {
+	return reflect.DeepEqual(a, b)
+}
+
+// contains implementa o operador in sobre listas, mapas (chaves) e strings
+func contains(container, item interface{}) (bool, error) {
+	switch c := container.(type) {
+	case nil:
+		return false, nil
+	case []interface{}:
+		for _, element := range c {
+			if equal(element, item) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case map[string]interface{}:
+		key, ok := item.(string)
+		if !ok {
+			return false, nil
+		}
+		_, found := c[key]
+		return found, nil
+	}
+	return false, fmt.Errorf("operador in aplicado a %s", typeName(container))
+}
+
+// function é uma função ou método disponível nas expressões
+type function func(args []interface{}) (interface{}, error)
+
+// globalFunctions são as funções chamadas sem alvo, ex.: size(x)
+var globalFunctions = map[string]function{
+	"size": func(args []interface{}) (interface{}, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("size espera 1 argumento")
+		}
+		return size(args[0])
+	},
+	"int": func(args []interface{}) (interface{}, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("int espera 1 argumento")
+		}
+		switch v := args[0].(type) {
+		case float64:
+			return math.Trunc(v), nil
+		case string:
+			i, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("int: %q não é um inteiro", v)
+			}
+			return float64(i), nil
+		}
+		return nil, fmt.Errorf("int aplicado a %s", typeName(args[0]))
+	},
+	"double": func(args []interface{}) (interface{}, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("double espera 1 argumento")
+		}
+		switch v := args[0].(type) {
+		case float64:
+			return v, nil
+		case string:
+			f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil {
+				return nil, fmt.Errorf("double: %q não é um número", v)
+			}
+			return f, nil
+		}
+		return nil, fmt.Errorf("double aplicado a %s", typeName(args[0]))
+	},
+	"string": func(args []interface{}) (interface{}, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("string espera 1 argumento")
+		}
+		switch v := args[0].(type) {
+		case string:
+			return v, nil
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64), nil
+		case bool:
+			return strconv.FormatBool(v), nil
+		}
+		return nil, fmt.Errorf("string aplicado a %s", typeName(args[0]))
+	},
+}
+
+// methods são as funções chamadas sobre um alvo, ex.: path.startsWith("/v1")
+var methods = map[string]function{
+	"startsWith": stringMethod("startsWith", strings.HasPrefix),
+	"endsWith":   stringMethod("endsWith", strings.HasSuffix),
+	"contains":   stringMethod("contains", strings.Contains),
+	"matches": func(args []interface{}) (interface{}, error) {
+		s, pattern, err := stringArgs("matches", args)
+		if err != nil || args[0] == nil {
+			return false, err
+		}
+		re, err := compileRegexp(pattern)
+		if err != nil {
+			return nil, err
+		}
+		return re.MatchString(s), nil
+	},
+	"lowerAscii": func(args []interface{}) (interface{}, error) {
+		s, ok := args[0].(string)
+		if len(args) != 1 || !ok {
+			return nil, fmt.Errorf("lowerAscii aplicado a %s", typeName(args[0]))
+		}
+		return strings.ToLower(s), nil
+	},
+	"upperAscii": func(args []interface{}) (interface{}, error) {
+		s, ok := args[0].(string)
+		if len(args) != 1 || !ok {
+			return nil, fmt.Errorf("upperAscii aplicado a %s", typeName(args[0]))
+		}
+		return strings.ToUpper(s), nil
+	},
+	"size": func(args []interface{}) (interface{}, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("size não recebe argumentos")
+		}
+		return size(args[0])
+	},
+}
+
+func (n *callNode) eval(vars map[string]interface{}) (interface{}, error) {
+	if n.target == nil && n.name == "has" {
+		// has(a.b) informa se o campo existe, sem falhar com alvos ausentes
+		if len(n.args) != 1 {
+			return nil, fmt.Errorf("has espera 1 argumento")
+		}
+		value, err := n.args[0].eval(vars)
+		if err != nil {
+			return false, nil
+		}
+		return value != nil, nil
+	}
+
+	var args []interface{}
+	fn := globalFunctions[n.name]
+	if n.target != nil {
+		target, err := n.target.eval(vars)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, target)
+		var ok bool
+		if fn, ok = methods[n.name]; !ok {
+			return nil, fmt.Errorf("método desconhecido %q", n.name)
+		}
+	}
+	for _, arg := range n.args {
+		value, err := arg.eval(vars)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, value)
+	}
+	return fn(args)
+}
+
+func stringMethod(name string, fn func(string, string) bool) function {
+	return func(args []interface{}) (interface{}, error) {
+		s, arg, err := stringArgs(name, args)
+		if err != nil || args[0] == nil {
+			// Campos ausentes não casam com nenhum texto
+			return false, err
+		}
+		return fn(s, arg), nil
+	}
+}
+
+func stringArgs(name string, args []interface{}) (string, string, error) {
+	if len(args) != 2 {
+		return "", "", fmt.Errorf("%s espera 1 argumento", name)
+	}
+	s, ok1 := args[0].(string)
+	arg, ok2 := args[1].(string)
+	if (!ok1 && args[0] != nil) || !ok2 {
+		return "", "", fmt.Errorf("%s aplicado a %s", name, typeName(args[0]))
+	}
+	return s, arg, nil
+}
+
+// regexps guarda as expressões regulares já compiladas pelo método matches
+var regexps sync.Map
+
+func compileRegexp(pattern string) (*regexp.Regexp, error) {
+	if cached, ok := regexps.Load(pattern); ok {
+		return cached.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("matches: expressão regular inválida: %w", err)
+	}
+	regexps.Store(pattern, re)
+	return re, nil
+}
+
+func size(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case nil:
+		return float64(0), nil
+	case string:
+		return float64(len([]rune(v))), nil
+	case []interface{}:
+		return float64(len(v)), nil
+	case map[string]interface{}:
+		return float64(len(v)), nil
+	}
+	return nil, fmt.Errorf("size aplicado a %s", typeName(value))
+}
+
+// normalize converte os valores das variáveis para os tipos da linguagem:
+// números em float64, mapas com chaves string e listas genéricas
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil, bool, string, float64, []interface{}, map[string]interface{}:
+		return v
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case int32:
+		return float64(v)
+	case float32:
+		return float64(v)
+	case []string:
+		items := make([]interface{}, len(v))
+		for i, s := range v {
+			items[i] = s
+		}
+		return items
+	case map[string]string:
+		m := make(map[string]interface{}, len(v))
+		for k, s := range v {
+			m[k] = s
+		}
+		return m
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			break
+		}
+		m := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			m[iter.Key().String()] = iter.Value().Interface()
+		}
+		return m
+	case reflect.Slice, reflect.Array:
+		items := make([]interface{}, rv.Len())
+		for i := range items {
+			items[i] = rv.Index(i).Interface()
+		}
+		return items
+	case reflect.String:
+		return rv.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	case reflect.Bool:
+		return rv.Bool()
+	}
+	return fmt.Sprint(value)
+}
+
+func typeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "bool"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case []interface{}:
+		return "list"
+	case map[string]interface{}:
+		return "map"
+	}
+	return fmt.Sprintf("%T", value)
+}
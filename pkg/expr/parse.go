@@ -0,0 +1,486 @@
+// Package expr implementa um subconjunto da linguagem de expressões do CEL
+// (Common Expression Language) para políticas simples sobre as requisições,
+// ex.: request.headers["x-beta"] == "true" && jwt.claims.plan == "pro".
+//
+// São aceitos literais (strings, números, true, false, null e listas),
+// acesso a campos (a.b) e índices (a["b"], a[0]), os operadores ! - * / % + -
+// == != < <= > >= in && || e o condicional c ? a : b, as funções size, has,
+// int, double e string e os métodos startsWith, endsWith, contains, matches,
+// lowerAscii, upperAscii e size. Campos ausentes em mapas valem null.
+package expr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Program é uma expressão compilada, segura para uso concorrente
+type Program struct {
+	source string
+	root   node
+}
+
+// programs guarda as expressões já compiladas
+var programs sync.Map
+
+// Compile analisa a expressão. As variáveis usadas devem estar entre as
+// informadas em variables; sem elas, qualquer variável é aceita.
+func Compile(source string, variables ...string) (*Program, error) {
+	key := source + "\x00" + strings.Join(variables, ",")
+	if cached, ok := programs.Load(key); ok {
+		return cached.(*Program), nil
+	}
+
+	p := &parser{source: source}
+	if err := p.tokenize(); err != nil {
+		return nil, err
+	}
+	root, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, p.errorAt(tok, "símbolo inesperado %q", tok.text)
+	}
+	if len(variables) > 0 {
+		if err := checkVariables(root, variables); err != nil {
+			return nil, err
+		}
+	}
+
+	program := &Program{source: source, root: root}
+	programs.Store(key, program)
+	return program, nil
+}
+
+// String retorna o texto original da expressão
+func (p *Program) String() string {
+	return p.source
+}
+
+// Uses indica se a expressão acessa o campo field da variável, por seleção
+// (request.body) ou índice (request["body"])
+func (p *Program) Uses(variable, field string) bool {
+	used := false
+	walk(p.root, func(n node) {
+		var operand node
+		switch n := n.(type) {
+		case *selectNode:
+			if n.field == field {
+				operand = n.operand
+			}
+		case *indexNode:
+			if key, ok := n.index.(*literalNode); ok && key.value == field {
+				operand = n.operand
+			}
+		}
+		if ident, ok := operand.(*identNode); ok && ident.name == variable {
+			used = true
+		}
+	})
+	return used
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenNumber
+	tokenString
+	tokenOperator
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+type parser struct {
+	source string
+	tokens []token
+	next   int
+}
+
+// operators são os operadores reconhecidos, os de dois caracteres primeiro
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "!", "<", ">", "+", "-", "*", "/", "%", "(", ")", "[", "]", ".", ",", "?", ":"}
+
+func (p *parser) tokenize() error {
+	s := p.source
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '"' || c == '\'':
+			value, end, err := readString(s, i)
+			if err != nil {
+				return err
+			}
+			p.tokens = append(p.tokens, token{kind: tokenString, text: value, pos: i})
+			i = end
+		case c >= '0' && c <= '9':
+			start := i
+			for i < len(s) && (s[i] >= '0' && s[i] <= '9' || s[i] == '.') {
+				i++
+			}
+			p.tokens = append(p.tokens, token{kind: tokenNumber, text: s[start:i], pos: start})
+		case c == '_' || unicode.IsLetter(rune(c)):
+			start := i
+			for i < len(s) && (s[i] == '_' || unicode.IsLetter(rune(s[i])) || unicode.IsDigit(rune(s[i]))) {
+				i++
+			}
+			p.tokens = append(p.tokens, token{kind: tokenIdent, text: s[start:i], pos: start})
+		default:
+			matched := false
+			for _, op := range operators {
+				if strings.HasPrefix(s[i:], op) {
+					p.tokens = append(p.tokens, token{kind: tokenOperator, text: op, pos: i})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return fmt.Errorf("caractere inesperado %q na posição %d", c, i)
+			}
+		}
+	}
+	p.tokens = append(p.tokens, token{kind: tokenEOF, pos: len(s)})
+	return nil
+}
+
+// readString lê um literal entre aspas simples ou duplas a partir de start
+func readString(s string, start int) (string, int, error) {
+	quote := s[start]
+	var b strings.Builder
+	for i := start + 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == quote:
+			return b.String(), i + 1, nil
+		case c == '\\' && i+1 < len(s):
+			i++
+			switch s[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			default:
+				b.WriteByte(s[i])
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", 0, fmt.Errorf("string sem fechamento na posição %d", start)
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+func (p *parser) advance() token {
+	tok := p.tokens[p.next]
+	if tok.kind != tokenEOF {
+		p.next++
+	}
+	return tok
+}
+
+// accept consome o operador informado, se for o próximo
+func (p *parser) accept(op string) bool {
+	if tok := p.peek(); tok.kind == tokenOperator && tok.text == op {
+		p.next++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(op string) error {
+	if !p.accept(op) {
+		tok := p.peek()
+		return p.errorAt(tok, "esperado %q", op)
+	}
+	return nil
+}
+
+func (p *parser) errorAt(tok token, format string, args ...interface{}) error {
+	return fmt.Errorf("%s na posição %d", fmt.Sprintf(format, args...), tok.pos)
+}
+
+// parseExpression trata o condicional, de menor precedência
+func (p *parser) parseExpression() (node, error) {
+	cond, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.accept("?") {
+		return cond, nil
+	}
+	then, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	otherwise, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	return &conditionalNode{cond: cond, then: then, otherwise: otherwise}, nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{op: "||", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseRelation()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("&&") {
+		right, err := p.parseRelation()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{op: "&&", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseRelation() (node, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	tok := p.peek()
+	isRelation := tok.kind == tokenOperator && (tok.text == "==" || tok.text == "!=" ||
+		tok.text == "<" || tok.text == "<=" || tok.text == ">" || tok.text == ">=")
+	if !isRelation && !(tok.kind == tokenIdent && tok.text == "in") {
+		return left, nil
+	}
+	p.advance()
+	right, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	return &binaryNode{op: tok.text, left: left, right: right}, nil
+}
+
+func (p *parser) parseAdditive() (node, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		if tok.kind != tokenOperator || (tok.text != "+" && tok.text != "-") {
+			return left, nil
+		}
+		p.advance()
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: tok.text, left: left, right: right}
+	}
+}
+
+func (p *parser) parseMultiplicative() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		if tok.kind != tokenOperator || (tok.text != "*" && tok.text != "/" && tok.text != "%") {
+			return left, nil
+		}
+		p.advance()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: tok.text, left: left, right: right}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.accept("!") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{op: "!", operand: operand}, nil
+	}
+	if p.accept("-") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{op: "-", operand: operand}, nil
+	}
+	return p.parsePostfix()
+}
+
+func (p *parser) parsePostfix() (node, error) {
+	current, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.accept("."):
+			tok := p.advance()
+			if tok.kind != tokenIdent {
+				return nil, p.errorAt(tok, "esperado nome de campo")
+			}
+			if p.accept("(") {
+				if _, ok := methods[tok.text]; !ok {
+					return nil, p.errorAt(tok, "método desconhecido %q", tok.text)
+				}
+				args, err := p.parseArguments()
+				if err != nil {
+					return nil, err
+				}
+				current = &callNode{name: tok.text, target: current, args: args}
+				continue
+			}
+			current = &selectNode{operand: current, field: tok.text}
+		case p.accept("["):
+			index, err := p.parseExpression()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			current = &indexNode{operand: current, index: index}
+		default:
+			return current, nil
+		}
+	}
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.advance()
+	switch tok.kind {
+	case tokenString:
+		return &literalNode{value: tok.text}, nil
+	case tokenNumber:
+		value, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, p.errorAt(tok, "número inválido %q", tok.text)
+		}
+		return &literalNode{value: value}, nil
+	case tokenIdent:
+		switch tok.text {
+		case "true":
+			return &literalNode{value: true}, nil
+		case "false":
+			return &literalNode{value: false}, nil
+		case "null":
+			return &literalNode{value: nil}, nil
+		}
+		if p.accept("(") {
+			args, err := p.parseArguments()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := globalFunctions[tok.text]; !ok && tok.text != "has" {
+				return nil, p.errorAt(tok, "função desconhecida %q", tok.text)
+			}
+			return &callNode{name: tok.text, args: args}, nil
+		}
+		return &identNode{name: tok.text}, nil
+	case tokenOperator:
+		switch tok.text {
+		case "(":
+			inner, err := p.parseExpression()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return inner, nil
+		case "[":
+			var items []node
+			if !p.accept("]") {
+				for {
+					item, err := p.parseExpression()
+					if err != nil {
+						return nil, err
+					}
+					items = append(items, item)
+					if p.accept("]") {
+						break
+					}
+					if err := p.expect(","); err != nil {
+						return nil, err
+					}
+				}
+			}
+			return &listNode{items: items}, nil
+		}
+	case tokenEOF:
+		return nil, p.errorAt(tok, "expressão incompleta")
+	}
+	return nil, p.errorAt(tok, "símbolo inesperado %q", tok.text)
+}
+
+// parseArguments lê os argumentos de uma chamada após o "("
+func (p *parser) parseArguments() ([]node, error) {
+	var args []node
+	if p.accept(")") {
+		return args, nil
+	}
+	for {
+		arg, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+		if p.accept(")") {
+			return args, nil
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// checkVariables verifica se a expressão usa apenas as variáveis conhecidas
+func checkVariables(root node, variables []string) error {
+	known := make(map[string]bool, len(variables))
+	for _, name := range variables {
+		known[name] = true
+	}
+	var err error
+	walk(root, func(n node) {
+		if ident, ok := n.(*identNode); ok && !known[ident.name] && err == nil {
+			err = fmt.Errorf("variável desconhecida %q (disponíveis: %s)", ident.name, strings.Join(variables, ", "))
+		}
+	})
+	return err
+}
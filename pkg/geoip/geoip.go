@@ -0,0 +1,98 @@
+// Package geoip localiza os clientes pelo endereço de origem, para restringir
+// o acesso por país e escolher backends por região (residência de dados).
+package geoip
+
+import (
+	"context"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// Location é a localização do cliente. Os códigos seguem a ISO 3166-1
+// alfa-2 (país), os códigos de continente da MaxMind (AF, AN, AS, EU, NA, OC,
+// SA) e a ISO 3166-2 sem o país (subdivisão).
+type Location struct {
+	Country     string `json:"country,omitempty"`
+	Continent   string `json:"continent,omitempty"`
+	Subdivision string `json:"subdivision,omitempty"`
+}
+
+// IsZero indica que a localização não foi identificada
+func (l Location) IsZero() bool {
+	return l.Country == "" && l.Continent == ""
+}
+
+// Resolver localiza o cliente da requisição. Implementações próprias podem ser
+// registradas no gateway no lugar da base MaxMind.
+type Resolver interface {
+	// Locate retorna a localização do endereço; false quando desconhecida
+	Locate(req *http.Request, addr netip.Addr) (Location, bool)
+}
+
+// Chain consulta os resolvedores na ordem, retornando a primeira localização
+// identificada
+type Chain []Resolver
+
+// Locate implementa Resolver
+func (c Chain) Locate(req *http.Request, addr netip.Addr) (Location, bool) {
+	for _, resolver := range c {
+		if location, ok := resolver.Locate(req, addr); ok {
+			return location, true
+		}
+	}
+	return Location{}, false
+}
+
+// HeaderResolver lê o país de um cabeçalho definido por uma CDN ou
+// balanceador (ex.: CF-IPCountry). O cabeçalho só é aceito quando Trusted
+// confirma que a conexão vem de um proxy confiável, pois o cliente pode
+// enviá-lo com qualquer valor.
+type HeaderResolver struct {
+	Header  string
+	Trusted func(req *http.Request) bool
+}
+
+// Locate implementa Resolver
+func (r *HeaderResolver) Locate(req *http.Request, _ netip.Addr) (Location, bool) {
+	if r.Trusted == nil || !r.Trusted(req) {
+		return Location{}, false
+	}
+	country := strings.ToUpper(strings.TrimSpace(req.Header.Get(r.Header)))
+	// CDNs usam XX ou T1 (Tor) quando não identificam o país
+	if !IsCountryCode(country) || country == "XX" || country == "T1" {
+		return Location{}, false
+	}
+	return Location{Country: country}, true
+}
+
+// IsCountryCode indica se o valor tem o formato de um código ISO 3166-1
+// alfa-2, sem diferenciar maiúsculas
+func IsCountryCode(value string) bool {
+	if len(value) != 2 {
+		return false
+	}
+	for _, r := range strings.ToUpper(value) {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+type contextKey struct{}
+
+// WithLocation retorna um contexto associado à localização do cliente
+func WithLocation(ctx context.Context, location Location) context.Context {
+	return context.WithValue(ctx, contextKey{}, location)
+}
+
+// FromContext retorna a localização do cliente associada ao contexto; a
+// localização vazia indica que ela é desconhecida
+func FromContext(ctx context.Context) Location {
+	if ctx == nil {
+		return Location{}
+	}
+	location, _ := ctx.Value(contextKey{}).(Location)
+	return location
+}
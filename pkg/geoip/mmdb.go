@@ -0,0 +1,404 @@
+package geoip
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/netip"
+	"os"
+	"sync"
+	"time"
+)
+
+// metadataMarker precede os metadados, no fim do arquivo .mmdb
+var metadataMarker = []byte("\xAB\xCD\xEFMaxMind.com")
+
+// errCorrupt indica um arquivo .mmdb inconsistente
+var errCorrupt = errors.New("base MaxMind DB corrompida")
+
+const (
+	// dataSectionSeparator são os bytes nulos entre a árvore e os dados
+	dataSectionSeparator = 16
+	// maxDecodeDepth limita o aninhamento e os ponteiros seguidos na decodificação
+	maxDecodeDepth = 32
+	// maxCachedLocations limita as localizações já decodificadas em memória
+	maxCachedLocations = 65536
+)
+
+// Tipos de dado do formato MaxMind DB
+const (
+	typeExtended  = 0
+	typePointer   = 1
+	typeString    = 2
+	typeDouble    = 3
+	typeBytes     = 4
+	typeUint16    = 5
+	typeUint32    = 6
+	typeMap       = 7
+	typeInt32     = 8
+	typeUint64    = 9
+	typeUint128   = 10
+	typeArray     = 11
+	typeContainer = 12
+	typeEnd       = 13
+	typeBool      = 14
+	typeFloat     = 15
+)
+
+// DatabaseResolver localiza os endereços numa base MaxMind DB (.mmdb), como a
+// GeoLite2-Country ou a GeoIP2-City. O arquivo é carregado inteiro em memória
+// e as localizações já decodificadas são reaproveitadas.
+type DatabaseResolver struct {
+	tree         []byte
+	data         decoder
+	nodeCount    uint
+	recordSize   uint
+	ipVersion    uint
+	ipv4Start    uint
+	databaseType string
+	buildTime    time.Time
+
+	mu    sync.RWMutex
+	cache map[uint]Location // posição do registro na seção de dados -> localização
+}
+
+// OpenDatabase carrega a base MaxMind DB do arquivo
+func OpenDatabase(path string) (*DatabaseResolver, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao ler base de geolocalização: %w", err)
+	}
+	return NewDatabase(content)
+}
+
+// NewDatabase interpreta o conteúdo de uma base MaxMind DB
+func NewDatabase(content []byte) (*DatabaseResolver, error) {
+	index := bytes.LastIndex(content, metadataMarker)
+	if index < 0 {
+		return nil, errors.New("arquivo não é uma base MaxMind DB")
+	}
+	meta := decoder{buf: content[index+len(metadataMarker):]}
+	value, _, err := meta.decode(0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("metadados inválidos: %w", err)
+	}
+	metadata, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("metadados inválidos: %w", errCorrupt)
+	}
+
+	db := &DatabaseResolver{
+		nodeCount:  uint(toUint(metadata["node_count"])),
+		recordSize: uint(toUint(metadata["record_size"])),
+		ipVersion:  uint(toUint(metadata["ip_version"])),
+		cache:      make(map[uint]Location),
+	}
+	db.databaseType, _ = metadata["database_type"].(string)
+	if epoch := toUint(metadata["build_epoch"]); epoch > 0 {
+		db.buildTime = time.Unix(int64(epoch), 0).UTC()
+	}
+	if db.recordSize != 24 && db.recordSize != 28 && db.recordSize != 32 {
+		return nil, fmt.Errorf("tamanho de registro não suportado: %d", db.recordSize)
+	}
+	if db.ipVersion != 4 && db.ipVersion != 6 {
+		return nil, fmt.Errorf("versão de IP não suportada: %d", db.ipVersion)
+	}
+
+	treeSize := db.nodeCount * db.recordSize / 4
+	if treeSize+dataSectionSeparator > uint(index) {
+		return nil, errCorrupt
+	}
+	db.tree = content[:treeSize]
+	db.data = decoder{buf: content[treeSize+dataSectionSeparator : index]}
+
+	// Em bases IPv6, os endereços IPv4 ficam sob o prefixo ::/96
+	if db.ipVersion == 6 {
+		node := uint(0)
+		for i := 0; i < 96 && node < db.nodeCount; i++ {
+			node = db.record(node, 0)
+		}
+		db.ipv4Start = node
+	}
+	return db, nil
+}
+
+// DatabaseType retorna o tipo da base (ex.: GeoLite2-Country)
+func (db *DatabaseResolver) DatabaseType() string {
+	return db.databaseType
+}
+
+// BuildTime retorna a data de geração da base
+func (db *DatabaseResolver) BuildTime() time.Time {
+	return db.buildTime
+}
+
+// Locate implementa Resolver
+func (db *DatabaseResolver) Locate(_ *http.Request, addr netip.Addr) (Location, bool) {
+	offset, ok := db.lookup(addr)
+	if !ok {
+		return Location{}, false
+	}
+
+	db.mu.RLock()
+	location, cached := db.cache[offset]
+	db.mu.RUnlock()
+	if cached {
+		return location, !location.IsZero()
+	}
+
+	value, _, err := db.data.decode(offset, 0)
+	if err != nil {
+		return Location{}, false
+	}
+	location = locationFromRecord(value)
+
+	db.mu.Lock()
+	if len(db.cache) < maxCachedLocations {
+		db.cache[offset] = location
+	}
+	db.mu.Unlock()
+	return location, !location.IsZero()
+}
+
+// lookup percorre a árvore de busca com os bits do endereço e retorna a
+// posição do registro na seção de dados
+func (db *DatabaseResolver) lookup(addr netip.Addr) (uint, bool) {
+	if !addr.IsValid() {
+		return 0, false
+	}
+	addr = addr.Unmap()
+
+	var ip []byte
+	node := uint(0)
+	if addr.Is4() {
+		octets := addr.As4()
+		ip = octets[:]
+		if db.ipVersion == 6 {
+			node = db.ipv4Start
+		}
+	} else {
+		if db.ipVersion == 4 {
+			return 0, false
+		}
+		octets := addr.As16()
+		ip = octets[:]
+	}
+
+	for i := uint(0); i < uint(len(ip))*8 && node < db.nodeCount; i++ {
+		bit := uint(ip[i>>3]>>(7-i&7)) & 1
+		node = db.record(node, bit)
+	}
+	// O valor igual ao número de nós indica um endereço sem registro
+	if node <= db.nodeCount {
+		return 0, false
+	}
+	offset := node - db.nodeCount - dataSectionSeparator
+	if offset >= uint(len(db.data.buf)) {
+		return 0, false
+	}
+	return offset, true
+}
+
+// record retorna o registro esquerdo (bit 0) ou direito (bit 1) do nó
+func (db *DatabaseResolver) record(node, bit uint) uint {
+	switch db.recordSize {
+	case 24:
+		b := db.tree[node*6+bit*3:]
+		return uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])
+	case 28:
+		b := db.tree[node*7:]
+		if bit == 0 {
+			return uint(b[3]&0xF0)<<20 | uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])
+		}
+		return uint(b[3]&0x0F)<<24 | uint(b[4])<<16 | uint(b[5])<<8 | uint(b[6])
+	default:
+		return uint(binary.BigEndian.Uint32(db.tree[node*8+bit*4:]))
+	}
+}
+
+// locationFromRecord extrai a localização de um registro das bases Country e
+// City. Sem o país da conexão, usa o país de registro do bloco.
+func locationFromRecord(value interface{}) Location {
+	record, _ := value.(map[string]interface{})
+	location := Location{
+		Country:   nestedString(record, "country", "iso_code"),
+		Continent: nestedString(record, "continent", "code"),
+	}
+	if location.Country == "" {
+		location.Country = nestedString(record, "registered_country", "iso_code")
+	}
+	if subdivisions, ok := record["subdivisions"].([]interface{}); ok && len(subdivisions) > 0 {
+		if first, ok := subdivisions[0].(map[string]interface{}); ok {
+			location.Subdivision, _ = first["iso_code"].(string)
+		}
+	}
+	return location
+}
+
+func nestedString(record map[string]interface{}, key, field string) string {
+	nested, _ := record[key].(map[string]interface{})
+	value, _ := nested[field].(string)
+	return value
+}
+
+func toUint(value interface{}) uint64 {
+	switch v := value.(type) {
+	case uint64:
+		return v
+	case int32:
+		if v > 0 {
+			return uint64(v)
+		}
+	}
+	return 0
+}
+
+// decoder decodifica a seção de dados do formato MaxMind DB. Os ponteiros são
+// relativos ao início do buffer.
+type decoder struct {
+	buf []byte
+}
+
+// decode retorna o valor na posição e a posição seguinte a ele
+func (d *decoder) decode(offset uint, depth int) (interface{}, uint, error) {
+	if depth > maxDecodeDepth || offset >= uint(len(d.buf)) {
+		return nil, 0, errCorrupt
+	}
+	ctrl := d.buf[offset]
+	offset++
+
+	kind := uint(ctrl >> 5)
+	if kind == typePointer {
+		pointer, next, err := d.pointer(ctrl, offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		value, _, err := d.decode(pointer, depth+1)
+		return value, next, err
+	}
+	if kind == typeExtended {
+		if offset >= uint(len(d.buf)) {
+			return nil, 0, errCorrupt
+		}
+		kind = 7 + uint(d.buf[offset])
+		offset++
+	}
+
+	size := uint(ctrl & 0x1f)
+	if size >= 29 {
+		n := size - 28
+		if offset+n > uint(len(d.buf)) {
+			return nil, 0, errCorrupt
+		}
+		extra := uint(0)
+		for _, b := range d.buf[offset : offset+n] {
+			extra = extra<<8 | uint(b)
+		}
+		offset += n
+		switch size {
+		case 29:
+			size = 29 + extra
+		case 30:
+			size = 285 + extra
+		default:
+			size = 65821 + extra
+		}
+	}
+
+	switch kind {
+	case typeMap:
+		values := make(map[string]interface{}, min(size, 64))
+		for i := uint(0); i < size; i++ {
+			key, next, err := d.decode(offset, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			name, ok := key.(string)
+			if !ok {
+				return nil, 0, errCorrupt
+			}
+			value, next, err := d.decode(next, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			values[name] = value
+			offset = next
+		}
+		return values, offset, nil
+	case typeArray:
+		values := make([]interface{}, 0, min(size, 64))
+		for i := uint(0); i < size; i++ {
+			value, next, err := d.decode(offset, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			values = append(values, value)
+			offset = next
+		}
+		return values, offset, nil
+	case typeBool:
+		return size != 0, offset, nil
+	case typeContainer, typeEnd:
+		return nil, offset, nil
+	}
+
+	if offset+size > uint(len(d.buf)) {
+		return nil, 0, errCorrupt
+	}
+	payload := d.buf[offset : offset+size]
+	next := offset + size
+	switch kind {
+	case typeString:
+		return string(payload), next, nil
+	case typeDouble:
+		if size != 8 {
+			return nil, 0, errCorrupt
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(payload)), next, nil
+	case typeFloat:
+		if size != 4 {
+			return nil, 0, errCorrupt
+		}
+		return math.Float32frombits(binary.BigEndian.Uint32(payload)), next, nil
+	case typeBytes, typeUint128:
+		return bytes.Clone(payload), next, nil
+	case typeUint16, typeUint32, typeUint64, typeInt32:
+		if size > 8 {
+			return nil, 0, errCorrupt
+		}
+		var value uint64
+		for _, b := range payload {
+			value = value<<8 | uint64(b)
+		}
+		if kind == typeInt32 {
+			return int32(uint32(value)), next, nil
+		}
+		return value, next, nil
+	}
+	return nil, 0, fmt.Errorf("tipo de dado desconhecido %d: %w", kind, errCorrupt)
+}
+
+// pointer decodifica o destino do ponteiro, cujo tamanho vem do byte de controle
+func (d *decoder) pointer(ctrl byte, offset uint) (uint, uint, error) {
+	size := uint(ctrl>>3&0x3) + 1
+	if offset+size > uint(len(d.buf)) {
+		return 0, 0, errCorrupt
+	}
+	b := d.buf[offset : offset+size]
+	high := uint(ctrl & 0x7)
+	var pointer uint
+	switch size {
+	case 1:
+		pointer = high<<8 | uint(b[0])
+	case 2:
+		pointer = (high<<16 | uint(b[0])<<8 | uint(b[1])) + 2048
+	case 3:
+		pointer = (high<<24 | uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])) + 526336
+	default:
+		pointer = uint(binary.BigEndian.Uint32(b))
+	}
+	return pointer, offset + size, nil
+}
@@ -0,0 +1,150 @@
+package graphql
+
+import (
+	"fmt"
+	"math"
+)
+
+// maxCost limita a soma dos custos, evitando estouro com multiplicadores grandes
+const maxCost = math.MaxInt32
+
+// PaginationArguments são os argumentos que multiplicam o custo das seleções
+// do campo, como em users(first: 100) { ... }
+var PaginationArguments = []string{"first", "last", "limit"}
+
+// Analysis resume a profundidade e o custo de uma operação
+type Analysis struct {
+	Depth         int  // Maior aninhamento de campos; campos da raiz têm profundidade 1
+	Complexity    int  // Custo estimado: cada campo vale 1 mais as seleções multiplicadas pela paginação
+	Aliases       int  // Campos com apelido, usados para repetir um campo na mesma consulta
+	Introspection bool // Consulta o schema (__schema ou __type)
+}
+
+// Analyze calcula a profundidade e o custo da operação, resolvendo os
+// fragmentos do documento. As variáveis informam os argumentos de paginação
+// passados por variável.
+func (d *Document) Analyze(op *Operation, variables map[string]interface{}) (*Analysis, error) {
+	a := &analyzer{
+		doc:       d,
+		variables: variables,
+		fragments: make(map[string]*Analysis),
+		visiting:  make(map[string]bool),
+	}
+	return a.selections(op.Selections)
+}
+
+type analyzer struct {
+	doc       *Document
+	variables map[string]interface{}
+	fragments map[string]*Analysis // Resultado de cada fragmento, calculado uma única vez
+	visiting  map[string]bool
+}
+
+func (a *analyzer) selections(selections []*Selection) (*Analysis, error) {
+	total := &Analysis{}
+	for _, selection := range selections {
+		var item *Analysis
+		var err error
+		switch selection.Kind {
+		case SelectionField:
+			item, err = a.field(selection)
+		case SelectionInlineFragment:
+			item, err = a.selections(selection.Selections)
+		default:
+			item, err = a.fragment(selection.Name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		total.Depth = max(total.Depth, item.Depth)
+		total.Complexity = addCost(total.Complexity, item.Complexity)
+		total.Aliases = addCost(total.Aliases, item.Aliases)
+		total.Introspection = total.Introspection || item.Introspection
+	}
+	return total, nil
+}
+
+func (a *analyzer) field(field *Selection) (*Analysis, error) {
+	result := &Analysis{Depth: 1, Complexity: 1}
+	if field.Alias != "" {
+		result.Aliases = 1
+	}
+	if field.Name == "__schema" || field.Name == "__type" {
+		result.Introspection = true
+	}
+	if len(field.Selections) == 0 {
+		return result, nil
+	}
+
+	children, err := a.selections(field.Selections)
+	if err != nil {
+		return nil, err
+	}
+	result.Depth += children.Depth
+	result.Complexity = addCost(1, mulCost(children.Complexity, a.multiplier(field)))
+	result.Aliases = addCost(result.Aliases, children.Aliases)
+	result.Introspection = result.Introspection || children.Introspection
+	return result, nil
+}
+
+// fragment analisa o fragmento uma única vez, recusando ciclos
+func (a *analyzer) fragment(name string) (*Analysis, error) {
+	if cached, ok := a.fragments[name]; ok {
+		return cached, nil
+	}
+	fragment, ok := a.doc.Fragments[name]
+	if !ok {
+		return nil, fmt.Errorf("fragmento %q não definido", name)
+	}
+	if a.visiting[name] {
+		return nil, fmt.Errorf("fragmento %q usa a si mesmo", name)
+	}
+
+	a.visiting[name] = true
+	result, err := a.selections(fragment.Selections)
+	delete(a.visiting, name)
+	if err != nil {
+		return nil, err
+	}
+	a.fragments[name] = result
+	return result, nil
+}
+
+// multiplier retorna o maior argumento de paginação do campo, no mínimo 1
+func (a *analyzer) multiplier(field *Selection) int {
+	result := 1
+	for _, name := range PaginationArguments {
+		value, ok := field.Arguments[name]
+		if !ok {
+			continue
+		}
+		if variable, isVariable := value.(Variable); isVariable {
+			value = a.variables[string(variable)]
+		}
+		var n int64
+		switch v := value.(type) {
+		case int64:
+			n = v
+		case float64:
+			n = int64(v)
+		}
+		if n > int64(result) {
+			result = int(min(n, maxCost))
+		}
+	}
+	return result
+}
+
+func addCost(a, b int) int {
+	if a > maxCost-b {
+		return maxCost
+	}
+	return a + b
+}
+
+func mulCost(a, b int) int {
+	if a != 0 && b > maxCost/a {
+		return maxCost
+	}
+	return a * b
+}
@@ -0,0 +1,640 @@
+// Package graphql analisa os documentos executáveis do GraphQL (operações e
+// fragmentos) o suficiente para o gateway medir a profundidade e o custo das
+// consultas antes de encaminhá-las. Tipos e validação do schema ficam a cargo
+// do backend.
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Tipos de operação
+const (
+	OperationQuery        = "query"
+	OperationMutation     = "mutation"
+	OperationSubscription = "subscription"
+)
+
+// Document é um documento GraphQL analisado
+type Document struct {
+	Operations []*Operation
+	Fragments  map[string]*Fragment
+}
+
+// Operation é uma operação do documento
+type Operation struct {
+	Type       string // query, mutation ou subscription
+	Name       string // vazio em operações anônimas
+	Selections []*Selection
+}
+
+// Fragment é um fragmento nomeado do documento
+type Fragment struct {
+	Name       string
+	Selections []*Selection
+}
+
+// Tipos de seleção
+const (
+	SelectionField          = "field"
+	SelectionFragmentSpread = "spread"
+	SelectionInlineFragment = "inline"
+)
+
+// Selection é um campo, um uso de fragmento (...Nome) ou um fragmento inline
+type Selection struct {
+	Kind       string
+	Alias      string                 // Apelido do campo, quando informado
+	Name       string                 // Nome do campo ou do fragmento usado
+	Arguments  map[string]interface{} // Argumentos do campo; variáveis são do tipo Variable
+	Selections []*Selection
+}
+
+// Variable é uma referência a uma variável da operação nos argumentos
+type Variable string
+
+// Parse analisa o documento
+func Parse(source string) (*Document, error) {
+	p := &parser{lexer: lexer{source: source}}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	doc := &Document{Fragments: make(map[string]*Fragment)}
+	for p.tok.kind != tokenEOF {
+		switch {
+		case p.peekPunct("{"):
+			selections, err := p.parseSelectionSet()
+			if err != nil {
+				return nil, err
+			}
+			doc.Operations = append(doc.Operations, &Operation{Type: OperationQuery, Selections: selections})
+		case p.peekName("query"), p.peekName("mutation"), p.peekName("subscription"):
+			op, err := p.parseOperation()
+			if err != nil {
+				return nil, err
+			}
+			doc.Operations = append(doc.Operations, op)
+		case p.peekName("fragment"):
+			fragment, err := p.parseFragment()
+			if err != nil {
+				return nil, err
+			}
+			if _, exists := doc.Fragments[fragment.Name]; exists {
+				return nil, fmt.Errorf("fragmento %q definido mais de uma vez", fragment.Name)
+			}
+			doc.Fragments[fragment.Name] = fragment
+		default:
+			return nil, p.unexpected()
+		}
+	}
+
+	if len(doc.Operations) == 0 {
+		return nil, fmt.Errorf("o documento não contém operações")
+	}
+	return doc, nil
+}
+
+// Operation retorna a operação a executar: a de nome informado ou, sem nome,
+// a única operação do documento
+func (d *Document) Operation(name string) (*Operation, error) {
+	if name == "" {
+		if len(d.Operations) > 1 {
+			return nil, fmt.Errorf("operationName é obrigatório em documentos com várias operações")
+		}
+		return d.Operations[0], nil
+	}
+	for _, op := range d.Operations {
+		if op.Name == name {
+			return op, nil
+		}
+	}
+	return nil, fmt.Errorf("operação %q não encontrada no documento", name)
+}
+
+// maxNesting limita o aninhamento de seleções e valores analisados, evitando
+// o esgotamento da pilha com documentos maliciosos
+const maxNesting = 256
+
+type parser struct {
+	lexer lexer
+	tok   token
+	depth int
+}
+
+// enter registra um nível de aninhamento; leave deve ser chamado ao sair
+func (p *parser) enter() error {
+	p.depth++
+	if p.depth > maxNesting {
+		return fmt.Errorf("documento excede o aninhamento máximo de %d níveis", maxNesting)
+	}
+	return nil
+}
+
+func (p *parser) leave() {
+	p.depth--
+}
+
+func (p *parser) advance() error {
+	tok, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *parser) peekPunct(value string) bool {
+	return p.tok.kind == tokenPunct && p.tok.value == value
+}
+
+func (p *parser) peekName(value string) bool {
+	return p.tok.kind == tokenName && p.tok.value == value
+}
+
+func (p *parser) unexpected() error {
+	if p.tok.kind == tokenEOF {
+		return fmt.Errorf("fim inesperado do documento")
+	}
+	return fmt.Errorf("símbolo inesperado %q na posição %d", p.tok.value, p.tok.pos)
+}
+
+// acceptPunct consome o sinal informado, se for o próximo
+func (p *parser) acceptPunct(value string) (bool, error) {
+	if !p.peekPunct(value) {
+		return false, nil
+	}
+	return true, p.advance()
+}
+
+func (p *parser) expectPunct(value string) error {
+	if !p.peekPunct(value) {
+		return fmt.Errorf("esperado %q na posição %d", value, p.tok.pos)
+	}
+	return p.advance()
+}
+
+func (p *parser) expectName() (string, error) {
+	if p.tok.kind != tokenName {
+		return "", p.unexpected()
+	}
+	name := p.tok.value
+	return name, p.advance()
+}
+
+func (p *parser) parseOperation() (*Operation, error) {
+	op := &Operation{Type: p.tok.value}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind == tokenName {
+		op.Name = p.tok.value
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	if p.peekPunct("(") {
+		if err := p.skipVariableDefinitions(); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.skipDirectives(); err != nil {
+		return nil, err
+	}
+	selections, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+	op.Selections = selections
+	return op, nil
+}
+
+func (p *parser) parseFragment() (*Fragment, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	if !p.peekName("on") {
+		return nil, fmt.Errorf("esperado \"on\" no fragmento %q", name)
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if _, err := p.expectName(); err != nil {
+		return nil, err
+	}
+	if err := p.skipDirectives(); err != nil {
+		return nil, err
+	}
+	selections, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+	return &Fragment{Name: name, Selections: selections}, nil
+}
+
+// skipVariableDefinitions descarta as definições de variáveis: ($id: ID! = 1, ...)
+func (p *parser) skipVariableDefinitions() error {
+	if err := p.expectPunct("("); err != nil {
+		return err
+	}
+	for !p.peekPunct(")") {
+		if err := p.expectPunct("$"); err != nil {
+			return err
+		}
+		if _, err := p.expectName(); err != nil {
+			return err
+		}
+		if err := p.expectPunct(":"); err != nil {
+			return err
+		}
+		if err := p.skipType(); err != nil {
+			return err
+		}
+		if ok, err := p.acceptPunct("="); err != nil {
+			return err
+		} else if ok {
+			if _, err := p.parseValue(); err != nil {
+				return err
+			}
+		}
+		if err := p.skipDirectives(); err != nil {
+			return err
+		}
+	}
+	return p.advance()
+}
+
+func (p *parser) skipType() error {
+	if ok, err := p.acceptPunct("["); err != nil {
+		return err
+	} else if ok {
+		if err := p.skipType(); err != nil {
+			return err
+		}
+		if err := p.expectPunct("]"); err != nil {
+			return err
+		}
+	} else if _, err := p.expectName(); err != nil {
+		return err
+	}
+	_, err := p.acceptPunct("!")
+	return err
+}
+
+func (p *parser) skipDirectives() error {
+	for p.peekPunct("@") {
+		if err := p.advance(); err != nil {
+			return err
+		}
+		if _, err := p.expectName(); err != nil {
+			return err
+		}
+		if p.peekPunct("(") {
+			if _, err := p.parseArguments(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (p *parser) parseSelectionSet() ([]*Selection, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+	if err := p.expectPunct("{"); err != nil {
+		return nil, err
+	}
+	var selections []*Selection
+	for !p.peekPunct("}") {
+		selection, err := p.parseSelection()
+		if err != nil {
+			return nil, err
+		}
+		selections = append(selections, selection)
+	}
+	if len(selections) == 0 {
+		return nil, fmt.Errorf("conjunto de seleção vazio na posição %d", p.tok.pos)
+	}
+	return selections, p.advance()
+}
+
+func (p *parser) parseSelection() (*Selection, error) {
+	if ok, err := p.acceptPunct("..."); err != nil {
+		return nil, err
+	} else if ok {
+		if p.tok.kind == tokenName && p.tok.value != "on" {
+			name := p.tok.value
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			return &Selection{Kind: SelectionFragmentSpread, Name: name}, p.skipDirectives()
+		}
+		if p.peekName("on") {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if _, err := p.expectName(); err != nil {
+				return nil, err
+			}
+		}
+		if err := p.skipDirectives(); err != nil {
+			return nil, err
+		}
+		selections, err := p.parseSelectionSet()
+		if err != nil {
+			return nil, err
+		}
+		return &Selection{Kind: SelectionInlineFragment, Selections: selections}, nil
+	}
+
+	field := &Selection{Kind: SelectionField}
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	field.Name = name
+	if ok, err := p.acceptPunct(":"); err != nil {
+		return nil, err
+	} else if ok {
+		field.Alias = name
+		if field.Name, err = p.expectName(); err != nil {
+			return nil, err
+		}
+	}
+	if p.peekPunct("(") {
+		if field.Arguments, err = p.parseArguments(); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.skipDirectives(); err != nil {
+		return nil, err
+	}
+	if p.peekPunct("{") {
+		if field.Selections, err = p.parseSelectionSet(); err != nil {
+			return nil, err
+		}
+	}
+	return field, nil
+}
+
+func (p *parser) parseArguments() (map[string]interface{}, error) {
+	if err := p.expectPunct("("); err != nil {
+		return nil, err
+	}
+	arguments := make(map[string]interface{})
+	for !p.peekPunct(")") {
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectPunct(":"); err != nil {
+			return nil, err
+		}
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		arguments[name] = value
+	}
+	return arguments, p.advance()
+}
+
+// parseValue lê um valor: variável, número, string, booleano, null, enum,
+// lista ou objeto
+func (p *parser) parseValue() (interface{}, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+	tok := p.tok
+	switch tok.kind {
+	case tokenInt:
+		n, err := strconv.ParseInt(tok.value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("inteiro inválido %q", tok.value)
+		}
+		return n, p.advance()
+	case tokenFloat:
+		f, err := strconv.ParseFloat(tok.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("número inválido %q", tok.value)
+		}
+		return f, p.advance()
+	case tokenString:
+		return tok.value, p.advance()
+	case tokenName:
+		var value interface{} = tok.value
+		switch tok.value {
+		case "true":
+			value = true
+		case "false":
+			value = false
+		case "null":
+			value = nil
+		}
+		return value, p.advance()
+	case tokenPunct:
+		switch tok.value {
+		case "$":
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			name, err := p.expectName()
+			return Variable(name), err
+		case "[":
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			var items []interface{}
+			for !p.peekPunct("]") {
+				item, err := p.parseValue()
+				if err != nil {
+					return nil, err
+				}
+				items = append(items, item)
+			}
+			return items, p.advance()
+		case "{":
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			object := make(map[string]interface{})
+			for !p.peekPunct("}") {
+				name, err := p.expectName()
+				if err != nil {
+					return nil, err
+				}
+				if err := p.expectPunct(":"); err != nil {
+					return nil, err
+				}
+				if object[name], err = p.parseValue(); err != nil {
+					return nil, err
+				}
+			}
+			return object, p.advance()
+		}
+	}
+	return nil, p.unexpected()
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenPunct
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+type lexer struct {
+	source string
+	pos    int
+}
+
+// next retorna o próximo símbolo, ignorando espaços, vírgulas e comentários
+func (l *lexer) next() (token, error) {
+	s := l.source
+	for l.pos < len(s) {
+		c := s[l.pos]
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',' {
+			l.pos++
+			continue
+		}
+		if c == '#' {
+			for l.pos < len(s) && s[l.pos] != '\n' && s[l.pos] != '\r' {
+				l.pos++
+			}
+			continue
+		}
+		break
+	}
+	if l.pos >= len(s) {
+		return token{kind: tokenEOF, pos: l.pos}, nil
+	}
+
+	start := l.pos
+	c := s[start]
+	switch {
+	case strings.HasPrefix(s[start:], "..."):
+		l.pos += 3
+		return token{kind: tokenPunct, value: "...", pos: start}, nil
+	case strings.IndexByte("!$&():=@[]{}|", c) >= 0:
+		l.pos++
+		return token{kind: tokenPunct, value: string(c), pos: start}, nil
+	case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+		for l.pos < len(s) && isNameChar(s[l.pos]) {
+			l.pos++
+		}
+		return token{kind: tokenName, value: s[start:l.pos], pos: start}, nil
+	case c == '-' || c >= '0' && c <= '9':
+		return l.number()
+	case c == '"':
+		return l.string()
+	}
+	return token{}, fmt.Errorf("caractere inesperado %q na posição %d", c, start)
+}
+
+func isNameChar(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+func (l *lexer) number() (token, error) {
+	s := l.source
+	start := l.pos
+	kind := tokenInt
+	if s[l.pos] == '-' {
+		l.pos++
+	}
+	for l.pos < len(s) {
+		c := s[l.pos]
+		switch {
+		case c >= '0' && c <= '9':
+		case c == '.' || c == 'e' || c == 'E':
+			kind = tokenFloat
+		case (c == '+' || c == '-') && (s[l.pos-1] == 'e' || s[l.pos-1] == 'E'):
+		default:
+			return token{kind: kind, value: s[start:l.pos], pos: start}, nil
+		}
+		l.pos++
+	}
+	return token{kind: kind, value: s[start:l.pos], pos: start}, nil
+}
+
+func (l *lexer) string() (token, error) {
+	s := l.source
+	start := l.pos
+	if strings.HasPrefix(s[start:], `"""`) {
+		end := strings.Index(s[start+3:], `"""`)
+		for end >= 0 && s[start+3+end-1] == '\\' {
+			next := strings.Index(s[start+3+end+3:], `"""`)
+			if next < 0 {
+				end = -1
+				break
+			}
+			end += 3 + next
+		}
+		if end < 0 {
+			return token{}, fmt.Errorf("string sem fechamento na posição %d", start)
+		}
+		l.pos = start + 3 + end + 3
+		return token{kind: tokenString, value: s[start+3 : start+3+end], pos: start}, nil
+	}
+
+	var b strings.Builder
+	for i := start + 1; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"':
+			l.pos = i + 1
+			return token{kind: tokenString, value: b.String(), pos: start}, nil
+		case '\n', '\r':
+			return token{}, fmt.Errorf("string sem fechamento na posição %d", start)
+		case '\\':
+			if i+1 >= len(s) {
+				break
+			}
+			i++
+			switch s[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case 'u':
+				if i+4 < len(s) {
+					if r, err := strconv.ParseUint(s[i+1:i+5], 16, 32); err == nil {
+						b.WriteRune(rune(r))
+						i += 4
+						continue
+					}
+				}
+				return token{}, fmt.Errorf("escape unicode inválido na posição %d", i)
+			default:
+				b.WriteByte(s[i])
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return token{}, fmt.Errorf("string sem fechamento na posição %d", start)
+}
@@ -0,0 +1,134 @@
+package jsonschema
+
+import (
+	"math"
+	"net/url"
+	"strconv"
+)
+
+// ValidateQuery valida os parâmetros da query string com um schema de objeto.
+// Os valores, sempre texto na URL, são convertidos para o tipo declarado na
+// propriedade (ex.: "10" para integer). Propriedades do tipo array aceitam o
+// parâmetro repetido (?tag=a&tag=b).
+func (s *Schema) ValidateQuery(values url.Values) []Error {
+	object := make(map[string]interface{}, len(values))
+	for name, list := range values {
+		object[name] = s.root.coerceParam(name, list)
+	}
+	return s.Validate(object)
+}
+
+// coerceParam converte os valores do parâmetro conforme a propriedade
+// declarada no schema
+func (n *node) coerceParam(name string, list []string) interface{} {
+	prop := n.property(name)
+	var types []string
+	if prop != nil {
+		types = prop.declaredTypes()
+	}
+
+	if contains(types, "array") {
+		var itemTypes []string
+		if items := prop.itemsNode(); items != nil {
+			itemTypes = items.declaredTypes()
+		}
+		array := make([]interface{}, len(list))
+		for i, value := range list {
+			array[i] = coerce(value, itemTypes)
+		}
+		return array
+	}
+	if len(list) > 1 && len(types) == 0 {
+		array := make([]interface{}, len(list))
+		for i, value := range list {
+			array[i] = value
+		}
+		return array
+	}
+	if len(list) == 0 {
+		return ""
+	}
+	return coerce(list[0], types)
+}
+
+// property procura a propriedade no schema, em referências e em allOf
+func (n *node) property(name string) *node {
+	if n == nil {
+		return nil
+	}
+	if prop, ok := n.properties[name]; ok {
+		return prop
+	}
+	if n.ref != nil {
+		if prop := n.ref.property(name); prop != nil {
+			return prop
+		}
+	}
+	for _, sub := range n.allOf {
+		if prop := sub.property(name); prop != nil {
+			return prop
+		}
+	}
+	if n.additionalProperties != nil && n.additionalProperties.always == nil {
+		return n.additionalProperties
+	}
+	return nil
+}
+
+// declaredTypes retorna os tipos declarados no schema ou em sua referência
+func (n *node) declaredTypes() []string {
+	for depth := 0; n != nil && depth < 32; depth++ {
+		if len(n.types) > 0 {
+			return n.types
+		}
+		n = n.ref
+	}
+	return nil
+}
+
+// itemsNode retorna o schema dos itens declarado no schema ou em sua referência
+func (n *node) itemsNode() *node {
+	for depth := 0; n != nil && depth < 32; depth++ {
+		if n.items != nil {
+			return n.items
+		}
+		n = n.ref
+	}
+	return nil
+}
+
+// coerce converte o texto no primeiro tipo declarado compatível. Sem tipo
+// compatível, o texto é mantido e a validação aponta o tipo inválido.
+func coerce(value string, types []string) interface{} {
+	for _, t := range types {
+		switch t {
+		case "integer":
+			if number, err := strconv.ParseFloat(value, 64); err == nil && number == math.Trunc(number) {
+				return number
+			}
+		case "number":
+			if number, err := strconv.ParseFloat(value, 64); err == nil {
+				return number
+			}
+		case "boolean":
+			if value == "true" || value == "false" {
+				return value == "true"
+			}
+		case "null":
+			if value == "" {
+				return nil
+			}
+		}
+	}
+	return value
+}
+
+// contains indica se a lista contém o item
+func contains(list []string, item string) bool {
+	for _, value := range list {
+		if value == item {
+			return true
+		}
+	}
+	return false
+}
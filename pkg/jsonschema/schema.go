@@ -0,0 +1,609 @@
+// Package jsonschema valida documentos JSON com o subconjunto do JSON Schema
+// usado em contratos de APIs: o vocabulário de validação do draft 2020-12 e os
+// schemas do OpenAPI 3 (incluindo nullable e $ref para o próprio documento).
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// validTypes são os tipos aceitos na palavra-chave type
+var validTypes = map[string]bool{
+	"null": true, "boolean": true, "object": true, "array": true,
+	"number": true, "integer": true, "string": true,
+}
+
+// Error é uma violação do schema
+type Error struct {
+	Path    string `json:"path"` // JSON Pointer do valor inválido; vazio é a raiz
+	Message string `json:"message"`
+}
+
+// Schema é um JSON Schema compilado, pronto para validar valores
+type Schema struct {
+	root *node
+}
+
+// node é um schema ou subschema compilado
+type node struct {
+	always *bool // schema booleano: true aceita e false recusa qualquer valor
+	ref    *node // destino de $ref, resolvido na compilação
+
+	types    []string
+	nullable bool
+	enum     []interface{}
+	constant interface{}
+	hasConst bool
+
+	properties           map[string]*node
+	required             []string
+	additionalProperties *node
+	minProperties        *int
+	maxProperties        *int
+
+	items       *node
+	minItems    *int
+	maxItems    *int
+	uniqueItems bool
+
+	minLength *int
+	maxLength *int
+	pattern   *regexp.Regexp
+	format    string
+
+	minimum          *float64
+	maximum          *float64
+	exclusiveMinimum *float64
+	exclusiveMaximum *float64
+	multipleOf       *float64
+
+	allOf []*node
+	anyOf []*node
+	oneOf []*node
+	not   *node
+}
+
+// compiler resolve as referências de um documento durante a compilação
+type compiler struct {
+	document interface{}
+	refs     map[string]*node
+}
+
+// Compile compila o schema em JSON. Referências ($ref) são resolvidas no
+// próprio schema (ex.: #/$defs/item).
+func Compile(data []byte) (*Schema, error) {
+	var document interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("schema não é JSON válido: %w", err)
+	}
+	return CompileValue(document, document)
+}
+
+// CompileValue compila o schema já decodificado. Referências ($ref) são
+// resolvidas em document, que pode ser o documento OpenAPI que contém o
+// schema (ex.: #/components/schemas/Pet).
+func CompileValue(document, schema interface{}) (*Schema, error) {
+	c := &compiler{document: document, refs: make(map[string]*node)}
+	root, err := c.compile(schema, "")
+	if err != nil {
+		return nil, err
+	}
+	return &Schema{root: root}, nil
+}
+
+func (c *compiler) compile(value interface{}, at string) (*node, error) {
+	if b, ok := value.(bool); ok {
+		return &node{always: &b}, nil
+	}
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("schema em %q deve ser um objeto ou booleano", pointerOrRoot(at))
+	}
+
+	n := &node{}
+	if ref, ok := m["$ref"].(string); ok {
+		target, err := c.resolve(ref)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", pointerOrRoot(at), err)
+		}
+		n.ref = target
+	}
+
+	switch t := m["type"].(type) {
+	case nil:
+	case string:
+		n.types = []string{t}
+	case []interface{}:
+		for _, item := range t {
+			name, _ := item.(string)
+			n.types = append(n.types, name)
+		}
+	default:
+		return nil, fmt.Errorf("%s: type inválido", pointerOrRoot(at))
+	}
+	for _, t := range n.types {
+		if !validTypes[t] {
+			return nil, fmt.Errorf("%s: type desconhecido: %q", pointerOrRoot(at), t)
+		}
+	}
+	n.nullable, _ = m["nullable"].(bool)
+
+	if enum, ok := m["enum"]; ok {
+		list, ok := enum.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s: enum deve ser uma lista", pointerOrRoot(at))
+		}
+		n.enum = list
+	}
+	if constant, ok := m["const"]; ok {
+		n.constant, n.hasConst = constant, true
+	}
+
+	var err error
+	if props, ok := m["properties"].(map[string]interface{}); ok {
+		n.properties = make(map[string]*node, len(props))
+		for name, prop := range props {
+			if n.properties[name], err = c.compile(prop, at+"/properties/"+escape(name)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if required, ok := m["required"].([]interface{}); ok {
+		for _, item := range required {
+			if name, ok := item.(string); ok {
+				n.required = append(n.required, name)
+			}
+		}
+	}
+	if additional, ok := m["additionalProperties"]; ok {
+		if n.additionalProperties, err = c.compile(additional, at+"/additionalProperties"); err != nil {
+			return nil, err
+		}
+	}
+	if items, ok := m["items"]; ok {
+		if n.items, err = c.compile(items, at+"/items"); err != nil {
+			return nil, err
+		}
+	}
+	if not, ok := m["not"]; ok {
+		if n.not, err = c.compile(not, at+"/not"); err != nil {
+			return nil, err
+		}
+	}
+	for keyword, target := range map[string]*[]*node{"allOf": &n.allOf, "anyOf": &n.anyOf, "oneOf": &n.oneOf} {
+		raw, ok := m[keyword]
+		if !ok {
+			continue
+		}
+		list, ok := raw.([]interface{})
+		if !ok || len(list) == 0 {
+			return nil, fmt.Errorf("%s: %s deve ser uma lista não vazia", pointerOrRoot(at), keyword)
+		}
+		for i, item := range list {
+			sub, err := c.compile(item, at+"/"+keyword+"/"+strconv.Itoa(i))
+			if err != nil {
+				return nil, err
+			}
+			*target = append(*target, sub)
+		}
+	}
+
+	n.uniqueItems, _ = m["uniqueItems"].(bool)
+	n.format, _ = m["format"].(string)
+	if pattern, ok := m["pattern"].(string); ok {
+		if n.pattern, err = regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("%s: pattern inválido: %w", pointerOrRoot(at), err)
+		}
+	}
+
+	for keyword, target := range map[string]**int{
+		"minLength": &n.minLength, "maxLength": &n.maxLength,
+		"minItems": &n.minItems, "maxItems": &n.maxItems,
+		"minProperties": &n.minProperties, "maxProperties": &n.maxProperties,
+	} {
+		if raw, ok := m[keyword]; ok {
+			number, ok := raw.(float64)
+			if !ok || number < 0 || number != math.Trunc(number) {
+				return nil, fmt.Errorf("%s: %s deve ser um inteiro não negativo", pointerOrRoot(at), keyword)
+			}
+			value := int(number)
+			*target = &value
+		}
+	}
+	for keyword, target := range map[string]**float64{
+		"minimum": &n.minimum, "maximum": &n.maximum, "multipleOf": &n.multipleOf,
+	} {
+		if raw, ok := m[keyword]; ok {
+			number, ok := raw.(float64)
+			if !ok {
+				return nil, fmt.Errorf("%s: %s deve ser um número", pointerOrRoot(at), keyword)
+			}
+			*target = &number
+		}
+	}
+	if n.multipleOf != nil && *n.multipleOf <= 0 {
+		return nil, fmt.Errorf("%s: multipleOf deve ser positivo", pointerOrRoot(at))
+	}
+
+	// exclusiveMinimum/exclusiveMaximum são números no JSON Schema e booleanos
+	// que modificam minimum/maximum no OpenAPI 3.0
+	for keyword, target := range map[string]struct {
+		exclusive **float64
+		bound     *float64
+	}{
+		"exclusiveMinimum": {&n.exclusiveMinimum, n.minimum},
+		"exclusiveMaximum": {&n.exclusiveMaximum, n.maximum},
+	} {
+		switch raw := m[keyword].(type) {
+		case nil:
+		case float64:
+			*target.exclusive = &raw
+		case bool:
+			if raw && target.bound != nil {
+				bound := *target.bound
+				*target.exclusive = &bound
+			}
+		default:
+			return nil, fmt.Errorf("%s: %s inválido", pointerOrRoot(at), keyword)
+		}
+	}
+	if n.exclusiveMinimum != nil && n.minimum != nil && *n.exclusiveMinimum == *n.minimum {
+		n.minimum = nil
+	}
+	if n.exclusiveMaximum != nil && n.maximum != nil && *n.exclusiveMaximum == *n.maximum {
+		n.maximum = nil
+	}
+
+	return n, nil
+}
+
+// resolve compila o destino de uma referência local. O nó é registrado antes
+// da compilação para que referências recursivas apontem para ele.
+func (c *compiler) resolve(ref string) (*node, error) {
+	if target, ok := c.refs[ref]; ok {
+		return target, nil
+	}
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("$ref externo não suportado: %s", ref)
+	}
+
+	value := c.document
+	pointer := strings.TrimPrefix(ref, "#")
+	if pointer != "" {
+		for _, part := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+			part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+			if unescaped, err := url.PathUnescape(part); err == nil {
+				part = unescaped
+			}
+			object, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("$ref não encontrado: %s", ref)
+			}
+			if value, ok = object[part]; !ok {
+				return nil, fmt.Errorf("$ref não encontrado: %s", ref)
+			}
+		}
+	}
+
+	target := &node{}
+	c.refs[ref] = target
+	compiled, err := c.compile(value, pointer)
+	if err != nil {
+		return nil, err
+	}
+	*target = *compiled
+	return target, nil
+}
+
+// Validate valida o valor, decodificado de JSON (encoding/json com
+// interface{}), e retorna as violações encontradas
+func (s *Schema) Validate(value interface{}) []Error {
+	var errs []Error
+	s.root.validate(value, "", &errs)
+	return errs
+}
+
+func (n *node) validate(value interface{}, path string, errs *[]Error) {
+	if n.always != nil {
+		if !*n.always {
+			*errs = append(*errs, Error{Path: path, Message: "valor não permitido"})
+		}
+		return
+	}
+	if n.ref != nil {
+		n.ref.validate(value, path, errs)
+	}
+	if value == nil && n.nullable {
+		return
+	}
+
+	if len(n.types) > 0 && !matchesType(value, n.types) {
+		*errs = append(*errs, Error{Path: path, Message: "tipo inválido: esperado " + strings.Join(n.types, " ou ") + ", recebido " + typeOf(value)})
+		return
+	}
+	if n.enum != nil && !containsValue(n.enum, value) {
+		*errs = append(*errs, Error{Path: path, Message: "valor fora da lista permitida"})
+	}
+	if n.hasConst && !equal(n.constant, value) {
+		*errs = append(*errs, Error{Path: path, Message: "valor diferente do esperado"})
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		n.validateObject(v, path, errs)
+	case []interface{}:
+		n.validateArray(v, path, errs)
+	case string:
+		n.validateString(v, path, errs)
+	case float64:
+		n.validateNumber(v, path, errs)
+	}
+
+	for _, sub := range n.allOf {
+		sub.validate(value, path, errs)
+	}
+	if len(n.anyOf) > 0 {
+		matched := false
+		for _, sub := range n.anyOf {
+			if sub.matches(value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			*errs = append(*errs, Error{Path: path, Message: "valor não corresponde a nenhum dos schemas de anyOf"})
+		}
+	}
+	if len(n.oneOf) > 0 {
+		matched := 0
+		for _, sub := range n.oneOf {
+			if sub.matches(value) {
+				matched++
+			}
+		}
+		if matched != 1 {
+			*errs = append(*errs, Error{Path: path, Message: fmt.Sprintf("valor deve corresponder a exatamente um schema de oneOf (corresponde a %d)", matched)})
+		}
+	}
+	if n.not != nil && n.not.matches(value) {
+		*errs = append(*errs, Error{Path: path, Message: "valor não permitido pelo schema de not"})
+	}
+}
+
+// matches indica se o valor é válido no schema
+func (n *node) matches(value interface{}) bool {
+	var errs []Error
+	n.validate(value, "", &errs)
+	return len(errs) == 0
+}
+
+func (n *node) validateObject(object map[string]interface{}, path string, errs *[]Error) {
+	for _, name := range n.required {
+		if _, ok := object[name]; !ok {
+			*errs = append(*errs, Error{Path: path + "/" + escape(name), Message: "campo obrigatório ausente"})
+		}
+	}
+	if n.minProperties != nil && len(object) < *n.minProperties {
+		*errs = append(*errs, Error{Path: path, Message: fmt.Sprintf("mínimo de %d propriedades", *n.minProperties)})
+	}
+	if n.maxProperties != nil && len(object) > *n.maxProperties {
+		*errs = append(*errs, Error{Path: path, Message: fmt.Sprintf("máximo de %d propriedades", *n.maxProperties)})
+	}
+
+	// Ordem estável das mensagens
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		child := path + "/" + escape(name)
+		if prop, ok := n.properties[name]; ok {
+			prop.validate(object[name], child, errs)
+			continue
+		}
+		if n.additionalProperties != nil {
+			if n.additionalProperties.always != nil && !*n.additionalProperties.always {
+				*errs = append(*errs, Error{Path: child, Message: "propriedade não permitida"})
+				continue
+			}
+			n.additionalProperties.validate(object[name], child, errs)
+		}
+	}
+}
+
+func (n *node) validateArray(array []interface{}, path string, errs *[]Error) {
+	if n.minItems != nil && len(array) < *n.minItems {
+		*errs = append(*errs, Error{Path: path, Message: fmt.Sprintf("mínimo de %d itens", *n.minItems)})
+	}
+	if n.maxItems != nil && len(array) > *n.maxItems {
+		*errs = append(*errs, Error{Path: path, Message: fmt.Sprintf("máximo de %d itens", *n.maxItems)})
+	}
+	if n.uniqueItems {
+		for i := range array {
+			for j := i + 1; j < len(array); j++ {
+				if equal(array[i], array[j]) {
+					*errs = append(*errs, Error{Path: path, Message: "itens repetidos não são permitidos"})
+					i, j = len(array), len(array)
+				}
+			}
+		}
+	}
+	if n.items != nil {
+		for i, item := range array {
+			n.items.validate(item, path+"/"+strconv.Itoa(i), errs)
+		}
+	}
+}
+
+func (n *node) validateString(s string, path string, errs *[]Error) {
+	length := len([]rune(s))
+	if n.minLength != nil && length < *n.minLength {
+		*errs = append(*errs, Error{Path: path, Message: fmt.Sprintf("mínimo de %d caracteres", *n.minLength)})
+	}
+	if n.maxLength != nil && length > *n.maxLength {
+		*errs = append(*errs, Error{Path: path, Message: fmt.Sprintf("máximo de %d caracteres", *n.maxLength)})
+	}
+	if n.pattern != nil && !n.pattern.MatchString(s) {
+		*errs = append(*errs, Error{Path: path, Message: "valor não corresponde ao padrão " + n.pattern.String()})
+	}
+	if n.format != "" && !validFormat(n.format, s) {
+		*errs = append(*errs, Error{Path: path, Message: "formato inválido: esperado " + n.format})
+	}
+}
+
+func (n *node) validateNumber(number float64, path string, errs *[]Error) {
+	if n.minimum != nil && number < *n.minimum {
+		*errs = append(*errs, Error{Path: path, Message: "valor menor que o mínimo " + formatNumber(*n.minimum)})
+	}
+	if n.maximum != nil && number > *n.maximum {
+		*errs = append(*errs, Error{Path: path, Message: "valor maior que o máximo " + formatNumber(*n.maximum)})
+	}
+	if n.exclusiveMinimum != nil && number <= *n.exclusiveMinimum {
+		*errs = append(*errs, Error{Path: path, Message: "valor deve ser maior que " + formatNumber(*n.exclusiveMinimum)})
+	}
+	if n.exclusiveMaximum != nil && number >= *n.exclusiveMaximum {
+		*errs = append(*errs, Error{Path: path, Message: "valor deve ser menor que " + formatNumber(*n.exclusiveMaximum)})
+	}
+	if n.multipleOf != nil {
+		quotient := number / *n.multipleOf
+		if math.Abs(quotient-math.Round(quotient)) > 1e-9 {
+			*errs = append(*errs, Error{Path: path, Message: "valor deve ser múltiplo de " + formatNumber(*n.multipleOf)})
+		}
+	}
+}
+
+// matchesType indica se o valor é de um dos tipos informados
+func matchesType(value interface{}, types []string) bool {
+	actual := typeOf(value)
+	for _, t := range types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+// typeOf retorna o tipo JSON do valor; números inteiros são "integer"
+func typeOf(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		if v == math.Trunc(v) && !math.IsInf(v, 0) {
+			return "integer"
+		}
+		return "number"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// validFormat verifica os formatos mais usados em APIs; formatos desconhecidos
+// são aceitos, como recomenda a especificação
+func validFormat(format, s string) bool {
+	switch format {
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, s)
+		return err == nil
+	case "date":
+		_, err := time.Parse("2006-01-02", s)
+		return err == nil
+	case "time":
+		_, err := time.Parse("15:04:05Z07:00", s)
+		return err == nil
+	case "email":
+		addr, err := mail.ParseAddress(s)
+		return err == nil && addr.Address == s
+	case "uuid":
+		return uuidPattern.MatchString(s)
+	case "uri":
+		u, err := url.Parse(s)
+		return err == nil && u.Scheme != ""
+	case "ipv4":
+		return ipv4Pattern.MatchString(s)
+	default:
+		return true
+	}
+}
+
+var (
+	uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	ipv4Pattern = regexp.MustCompile(`^((25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)\.){3}(25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)$`)
+)
+
+// containsValue indica se a lista contém o valor
+func containsValue(list []interface{}, value interface{}) bool {
+	for _, item := range list {
+		if equal(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// equal compara valores JSON decodificados
+func equal(a, b interface{}) bool {
+	switch av := a.(type) {
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for key, value := range av {
+			other, ok := bv[key]
+			if !ok || !equal(value, other) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !equal(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return a == b
+	}
+}
+
+// escape codifica o nome como segmento de JSON Pointer
+func escape(name string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1")
+}
+
+// pointerOrRoot descreve a posição no schema nas mensagens de erro
+func pointerOrRoot(at string) string {
+	if at == "" {
+		return "schema"
+	}
+	return "schema" + at
+}
+
+// formatNumber formata limites numéricos nas mensagens
+func formatNumber(number float64) string {
+	return strconv.FormatFloat(number, 'f', -1, 64)
+}
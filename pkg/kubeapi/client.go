@@ -0,0 +1,163 @@
+// Package kubeapi acessa a API do Kubernetes com as credenciais da service
+// account montadas no pod, sem depender do client-go.
+package kubeapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// ServiceAccountDir contém as credenciais montadas pelo Kubernetes nos pods
+const ServiceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+// ErrNotFound indica que o recurso não existe no cluster
+var ErrNotFound = errors.New("recurso não encontrado no Kubernetes")
+
+// ErrConflict indica que o recurso já existe ou foi alterado desde a leitura
+// (resourceVersion desatualizado)
+var ErrConflict = errors.New("conflito ao gravar recurso no Kubernetes")
+
+// Client faz requisições autenticadas à API do cluster. As requisições não têm
+// timeout próprio, para permitir watches; o prazo vem do contexto.
+type Client struct {
+	apiServer string
+	tokenFile string
+	http      *http.Client
+}
+
+// NewInClusterClient cria o cliente a partir do ambiente do pod. Com apiServer
+// vazio, usa o serviço da API visto de dentro do cluster.
+func NewInClusterClient(apiServer string) (*Client, error) {
+	if apiServer == "" {
+		host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+		if host == "" || port == "" {
+			return nil, errors.New("API do Kubernetes não encontrada: o gateway não está em um pod e o endereço da API não foi configurado")
+		}
+		apiServer = "https://" + net.JoinHostPort(host, port)
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if ca, err := os.ReadFile(ServiceAccountDir + "/ca.crt"); err == nil {
+		pool := x509.NewCertPool()
+		pool.AppendCertsFromPEM(ca)
+		tlsConfig.RootCAs = pool
+	}
+
+	return &Client{
+		apiServer: strings.TrimRight(apiServer, "/"),
+		tokenFile: ServiceAccountDir + "/token",
+		http: &http.Client{
+			Transport: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: tlsConfig,
+			},
+		},
+	}, nil
+}
+
+// PodNamespace retorna o namespace do pod em que o gateway executa
+func PodNamespace() (string, error) {
+	data, err := os.ReadFile(ServiceAccountDir + "/namespace")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// Get consulta o caminho da API (ex.: /api/v1/namespaces/default/services/web).
+// Respostas fora de 2xx viram erro; ErrNotFound para 404. Com sucesso, o
+// chamador fecha o corpo da resposta.
+func (c *Client) Get(ctx context.Context, path string, query url.Values) (*http.Response, error) {
+	return c.do(ctx, http.MethodGet, path, query, "", nil)
+}
+
+// MergePatch aplica um JSON merge patch ao recurso (ex.: o subrecurso status)
+func (c *Client) MergePatch(ctx context.Context, path string, patch []byte) error {
+	resp, err := c.do(ctx, http.MethodPatch, path, nil, "application/merge-patch+json", bytes.NewReader(patch))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Create cria o recurso na coleção informada (ex.: .../namespaces/default/leases).
+// ErrConflict indica que o recurso já existe.
+func (c *Client) Create(ctx context.Context, path string, body []byte) error {
+	resp, err := c.do(ctx, http.MethodPost, path, nil, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Update substitui o recurso. Com o resourceVersion lido no corpo, ErrConflict
+// indica que outra escrita aconteceu desde a leitura.
+func (c *Client) Update(ctx context.Context, path string, body []byte) error {
+	resp, err := c.do(ctx, http.MethodPut, path, nil, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// do envia a requisição autenticada e converte as respostas de erro
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, contentType string, body io.Reader) (*http.Response, error) {
+	endpoint := c.apiServer + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	// O token da service account é renovado pelo kubelet; lido a cada consulta
+	if token, err := os.ReadFile(c.tokenFile); err == nil {
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao consultar a API do Kubernetes: %w", err)
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, path)
+	}
+	if resp.StatusCode == http.StatusConflict {
+		return nil, fmt.Errorf("%w: %s", ErrConflict, path)
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return nil, &StatusError{Code: resp.StatusCode, Message: strings.TrimSpace(string(data))}
+}
+
+// StatusError é uma resposta de erro da API do Kubernetes
+type StatusError struct {
+	Code    int
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("API do Kubernetes retornou status %d: %s", e.Code, e.Message)
+}
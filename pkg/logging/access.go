@@ -0,0 +1,32 @@
+package logging
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// NewAccessLogger cria o logger dedicado ao log de acesso. No formato json,
+// cada entrada tem os campos da requisição; no formato combined, a saída
+// contém apenas a linha no formato Apache combined.
+func NewAccessLogger(format, outputPath string) (*zap.Logger, error) {
+	config := zap.NewProductionConfig()
+	config.Sampling = nil
+	config.DisableCaller = true
+	config.DisableStacktrace = true
+	config.OutputPaths = []string{outputPath}
+	config.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	config.EncoderConfig.EncodeDuration = zapcore.StringDurationEncoder
+
+	switch format {
+	case "json":
+	case "combined":
+		config.Encoding = "console"
+		config.EncoderConfig = zapcore.EncoderConfig{MessageKey: "message", LineEnding: zapcore.DefaultLineEnding}
+	default:
+		return nil, fmt.Errorf("formato de log de acesso inválido: %s", format)
+	}
+
+	return config.Build()
+}
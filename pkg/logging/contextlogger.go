@@ -3,6 +3,7 @@ package logging
 import (
 	"context"
 
+	"github.com/diillson/api-gateway-go/pkg/requestid"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -13,8 +14,23 @@ type ContextLogger struct {
 	*zap.Logger
 }
 
+// level é o nível dos loggers criados por NewLogger, alterável em execução
+var level = zap.NewAtomicLevelAt(zapcore.InfoLevel)
+
+// SetLevel altera o nível dos loggers criados por NewLogger, inclusive os já
+// em uso (ex.: "debug", "info", "warn", "error")
+func SetLevel(name string) error {
+	parsed, err := zapcore.ParseLevel(name)
+	if err != nil {
+		return err
+	}
+	level.SetLevel(parsed)
+	return nil
+}
+
 func NewLogger() (*zap.Logger, error) {
 	config := zap.NewProductionConfig()
+	config.Level = level
 	config.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
 	config.EncoderConfig.EncodeDuration = zapcore.StringDurationEncoder
 
@@ -70,5 +86,9 @@ func (l *ContextLogger) addTraceFields(ctx context.Context, fields []zap.Field)
 		)
 	}
 
+	if id := requestid.FromContext(ctx); id != "" {
+		fields = append(fields, zap.String("request_id", id))
+	}
+
 	return fields
 }
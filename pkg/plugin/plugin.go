@@ -0,0 +1,108 @@
+// Package plugin define os filtros de requisição que podem ser adicionados às
+// rotas sem alterar o handler do proxy. Os plugins são registrados em tempo de
+// compilação, normalmente no init() de um pacote importado pelo binário, e
+// referenciados pelo nome na configuração das rotas.
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Filter é um filtro instanciado com a configuração de uma rota. A mesma
+// instância atende requisições simultâneas, então as implementações devem ser
+// seguras para uso concorrente.
+type Filter interface {
+	// OnRequest é chamado antes do envio ao backend, após a autenticação e as
+	// demais políticas da rota. Retorna false quando o filtro já respondeu à
+	// requisição (ex.: c.JSON com um erro).
+	OnRequest(c *gin.Context) bool
+}
+
+// ResponseFilter é implementado pelos filtros que alteram os cabeçalhos da
+// resposta antes do envio ao cliente
+type ResponseFilter interface {
+	Filter
+	OnResponse(c *gin.Context, header http.Header)
+}
+
+// Factory cria um filtro a partir da configuração informada na rota
+type Factory func(config json.RawMessage) (Filter, error)
+
+var (
+	mu        sync.RWMutex
+	factories = make(map[string]Factory)
+	logger    = zap.NewNop()
+)
+
+// SetLogger define o logger disponível aos plugins
+func SetLogger(l *zap.Logger) {
+	mu.Lock()
+	defer mu.Unlock()
+	logger = l
+}
+
+// Logger retorna o logger do gateway para uso dos plugins
+func Logger() *zap.Logger {
+	mu.RLock()
+	defer mu.RUnlock()
+	return logger
+}
+
+// Register registra um plugin pelo nome. Deve ser chamado durante a
+// inicialização; nomes vazios ou repetidos causam panic, como em database/sql.
+func Register(name string, factory Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if name == "" || factory == nil {
+		panic("plugin: nome e factory são obrigatórios")
+	}
+	if _, exists := factories[name]; exists {
+		panic("plugin: plugin registrado duas vezes: " + name)
+	}
+	factories[name] = factory
+}
+
+// Registered indica se há um plugin registrado com o nome
+func Registered(name string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	_, ok := factories[name]
+	return ok
+}
+
+// Names retorna os nomes dos plugins registrados, em ordem alfabética
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New instancia o plugin com a configuração informada
+func New(name string, config json.RawMessage) (Filter, error) {
+	mu.RLock()
+	factory, ok := factories[name]
+	mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("plugin não registrado: %q", name)
+	}
+
+	filter, err := factory(config)
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s: %w", name, err)
+	}
+	return filter, nil
+}
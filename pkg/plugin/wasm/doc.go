@@ -0,0 +1,23 @@
+// Package wasm registra o plugin "wasm", que executa filtros compilados para
+// WebAssembly, permitindo que equipes adicionem lógica às rotas sem recompilar
+// o gateway. O runtime (wazero) só é incluído nos binários compilados com a
+// tag wasmfilter; nos demais, o plugin é registrado mas recusa as rotas que o
+// usam. A tag não se chama wasm porque esse nome é reservado ao GOARCH.
+//
+// O módulo exporta memory e on_request() i32, que retorna 0 para seguir com a
+// requisição e 1 para interrompê-la, e pode importar do módulo "gateway":
+//
+//	get_method(buf, cap) i32
+//	get_path(buf, cap) i32
+//	get_request_header(name, name_len, buf, cap) i32  // -1 quando ausente
+//	set_request_header(name, name_len, value, value_len)
+//	get_config(buf, cap) i32
+//	send_response(status, body, body_len)
+//	log(msg, msg_len)
+//
+// As funções get_* copiam até cap bytes para buf e retornam o tamanho total
+// do valor, para que o módulo repita a chamada com um buffer maior.
+package wasm
+
+// Name é o nome do plugin no registro
+const Name = "wasm"
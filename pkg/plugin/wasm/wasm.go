@@ -0,0 +1,289 @@
+//go:build wasmfilter
+
+package wasm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/diillson/api-gateway-go/pkg/plugin"
+	"github.com/gin-gonic/gin"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	"go.uber.org/zap"
+)
+
+// defaultTimeout limita a execução de on_request em cada requisição
+const defaultTimeout = 100 * time.Millisecond
+
+func init() {
+	plugin.Register(Name, newFilter)
+}
+
+// Config é a configuração do plugin na rota
+type Config struct {
+	Module  string          // Caminho do arquivo .wasm
+	Config  json.RawMessage // Configuração entregue ao módulo por get_config
+	Timeout string          // Tempo máximo de on_request (padrão "100ms")
+}
+
+// filter executa o módulo em instâncias reaproveitadas entre as requisições;
+// cada instância atende uma requisição por vez
+type filter struct {
+	runtime  wazero.Runtime
+	compiled wazero.CompiledModule
+	module   string
+	config   []byte
+	timeout  time.Duration
+
+	mu        sync.Mutex
+	instances []api.Module
+}
+
+// callState é a requisição em execução, acessada pelas funções do host
+type callState struct {
+	c      *gin.Context
+	module string
+	config []byte
+	status int
+	body   []byte
+}
+
+type stateKey struct{}
+
+func newFilter(raw json.RawMessage) (plugin.Filter, error) {
+	var cfg Config
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &cfg); err != nil {
+			return nil, fmt.Errorf("configuração inválida: %w", err)
+		}
+	}
+	if cfg.Module == "" {
+		return nil, errors.New("config.module é obrigatório")
+	}
+	timeout := defaultTimeout
+	if cfg.Timeout != "" {
+		d, err := time.ParseDuration(cfg.Timeout)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("config.timeout inválido: %q", cfg.Timeout)
+		}
+		timeout = d
+	}
+
+	code, err := os.ReadFile(cfg.Module)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao ler módulo: %w", err)
+	}
+
+	ctx := context.Background()
+	// Execuções que excedem o tempo máximo são interrompidas
+	runtime := wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfig().WithCloseOnContextDone(true))
+	wasi_snapshot_preview1.MustInstantiate(ctx, runtime)
+	if err := instantiateHost(ctx, runtime); err != nil {
+		runtime.Close(ctx)
+		return nil, fmt.Errorf("falha ao registrar funções do gateway: %w", err)
+	}
+	compiled, err := runtime.CompileModule(ctx, code)
+	if err != nil {
+		runtime.Close(ctx)
+		return nil, fmt.Errorf("falha ao compilar módulo: %w", err)
+	}
+	if _, ok := compiled.ExportedFunctions()["on_request"]; !ok {
+		runtime.Close(ctx)
+		return nil, errors.New("o módulo não exporta on_request")
+	}
+
+	f := &filter{runtime: runtime, compiled: compiled, module: cfg.Module, config: cfg.Config, timeout: timeout}
+	// Instancia uma vez para detectar falhas de inicialização na validação da rota
+	module, err := f.instantiate(ctx)
+	if err != nil {
+		runtime.Close(ctx)
+		return nil, err
+	}
+	f.release(module)
+	return f, nil
+}
+
+// OnRequest executa on_request do módulo
+func (f *filter) OnRequest(c *gin.Context) bool {
+	state := &callState{c: c, module: f.module, config: f.config}
+	ctx, cancel := context.WithTimeout(context.WithValue(c.Request.Context(), stateKey{}, state), f.timeout)
+	defer cancel()
+
+	module, err := f.acquire()
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Falha ao executar plugin WASM"})
+		return false
+	}
+	results, err := module.ExportedFunction("on_request").Call(ctx)
+	if err != nil {
+		// A instância pode ter sido interrompida no meio da execução e é descartada
+		module.Close(context.Background())
+		c.Error(fmt.Errorf("plugin wasm: %w", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Falha ao executar plugin WASM"})
+		return false
+	}
+	f.release(module)
+
+	if state.status != 0 {
+		c.Data(state.status, http.DetectContentType(state.body), state.body)
+		return false
+	}
+	return len(results) == 0 || results[0] == 0
+}
+
+// acquire retorna uma instância livre do módulo, criando uma nova se preciso
+func (f *filter) acquire() (api.Module, error) {
+	f.mu.Lock()
+	if n := len(f.instances); n > 0 {
+		module := f.instances[n-1]
+		f.instances = f.instances[:n-1]
+		f.mu.Unlock()
+		return module, nil
+	}
+	f.mu.Unlock()
+	return f.instantiate(context.Background())
+}
+
+// release devolve a instância para as próximas requisições
+func (f *filter) release(module api.Module) {
+	f.mu.Lock()
+	f.instances = append(f.instances, module)
+	f.mu.Unlock()
+}
+
+// instantiate cria uma instância anônima, executando _initialize nos módulos
+// que a exportam (reactors do WASI)
+func (f *filter) instantiate(ctx context.Context) (api.Module, error) {
+	module, err := f.runtime.InstantiateModule(ctx, f.compiled,
+		wazero.NewModuleConfig().WithName("").WithStartFunctions("_initialize"))
+	if err != nil {
+		return nil, fmt.Errorf("falha ao instanciar módulo: %w", err)
+	}
+	return module, nil
+}
+
+// instantiateHost registra as funções do módulo "gateway" importadas pelos plugins
+func instantiateHost(ctx context.Context, runtime wazero.Runtime) error {
+	_, err := runtime.NewHostModuleBuilder("gateway").
+		NewFunctionBuilder().WithFunc(getMethod).Export("get_method").
+		NewFunctionBuilder().WithFunc(getPath).Export("get_path").
+		NewFunctionBuilder().WithFunc(getRequestHeader).Export("get_request_header").
+		NewFunctionBuilder().WithFunc(setRequestHeader).Export("set_request_header").
+		NewFunctionBuilder().WithFunc(getConfig).Export("get_config").
+		NewFunctionBuilder().WithFunc(sendResponse).Export("send_response").
+		NewFunctionBuilder().WithFunc(logMessage).Export("log").
+		Instantiate(ctx)
+	return err
+}
+
+func stateFrom(ctx context.Context) *callState {
+	state, _ := ctx.Value(stateKey{}).(*callState)
+	return state
+}
+
+func getMethod(ctx context.Context, m api.Module, buf, capacity uint32) int32 {
+	state := stateFrom(ctx)
+	if state == nil {
+		return -1
+	}
+	return writeValue(m, buf, capacity, state.c.Request.Method)
+}
+
+func getPath(ctx context.Context, m api.Module, buf, capacity uint32) int32 {
+	state := stateFrom(ctx)
+	if state == nil {
+		return -1
+	}
+	return writeValue(m, buf, capacity, state.c.Request.URL.RequestURI())
+}
+
+func getRequestHeader(ctx context.Context, m api.Module, name, nameLen, buf, capacity uint32) int32 {
+	state := stateFrom(ctx)
+	key, ok := readString(m, name, nameLen)
+	if state == nil || !ok {
+		return -1
+	}
+	values := state.c.Request.Header.Values(key)
+	if len(values) == 0 {
+		return -1
+	}
+	return writeValue(m, buf, capacity, values[0])
+}
+
+func setRequestHeader(ctx context.Context, m api.Module, name, nameLen, value, valueLen uint32) {
+	state := stateFrom(ctx)
+	key, ok := readString(m, name, nameLen)
+	if state == nil || !ok {
+		return
+	}
+	if valueLen == 0 {
+		state.c.Request.Header.Del(key)
+		return
+	}
+	if v, ok := readString(m, value, valueLen); ok {
+		state.c.Request.Header.Set(key, v)
+	}
+}
+
+func getConfig(ctx context.Context, m api.Module, buf, capacity uint32) int32 {
+	state := stateFrom(ctx)
+	if state == nil {
+		return -1
+	}
+	return writeValue(m, buf, capacity, string(state.config))
+}
+
+func sendResponse(ctx context.Context, m api.Module, status, body, bodyLen uint32) {
+	state := stateFrom(ctx)
+	if state == nil || status < 100 || status > 599 {
+		return
+	}
+	data, ok := m.Memory().Read(body, bodyLen)
+	if !ok {
+		return
+	}
+	state.status = int(status)
+	state.body = append([]byte(nil), data...)
+}
+
+func logMessage(ctx context.Context, m api.Module, msg, msgLen uint32) {
+	state := stateFrom(ctx)
+	text, ok := readString(m, msg, msgLen)
+	if state == nil || !ok {
+		return
+	}
+	plugin.Logger().Info("Mensagem do plugin WASM",
+		zap.String("module", state.module),
+		zap.String("path", state.c.Request.URL.Path),
+		zap.String("message", text))
+}
+
+// readString lê um texto da memória do módulo
+func readString(m api.Module, ptr, length uint32) (string, bool) {
+	data, ok := m.Memory().Read(ptr, length)
+	if !ok {
+		return "", false
+	}
+	return string(data), true
+}
+
+// writeValue copia até capacity bytes do valor para a memória do módulo e
+// retorna o tamanho total do valor
+func writeValue(m api.Module, buf, capacity uint32, value string) int32 {
+	data := []byte(value)
+	if uint32(len(data)) <= capacity {
+		m.Memory().Write(buf, data)
+	} else {
+		m.Memory().Write(buf, data[:capacity])
+	}
+	return int32(len(data))
+}
@@ -0,0 +1,16 @@
+//go:build !wasmfilter
+
+package wasm
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/diillson/api-gateway-go/pkg/plugin"
+)
+
+func init() {
+	plugin.Register(Name, func(json.RawMessage) (plugin.Filter, error) {
+		return nil, errors.New("binário compilado sem suporte a WASM; recompile com -tags wasmfilter")
+	})
+}
@@ -0,0 +1,107 @@
+package ratelimit
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Estilos dos cabeçalhos de limite nas respostas
+const (
+	// HeadersStandard usa RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset
+	// (segundos até o reinício) e RateLimit-Policy
+	HeadersStandard = "standard"
+	// HeadersLegacy usa os cabeçalhos X-RateLimit-* e X-Quota-*, com o
+	// reinício em Unix timestamp
+	HeadersLegacy = "legacy"
+	// HeadersBoth envia os dois estilos
+	HeadersBoth = "both"
+	// HeadersNone não informa os limites; o Retry-After das recusas é mantido
+	HeadersNone = "none"
+)
+
+// ValidHeaders indica se o estilo de cabeçalhos é conhecido
+func ValidHeaders(style string) bool {
+	switch style {
+	case HeadersStandard, HeadersLegacy, HeadersBoth, HeadersNone:
+		return true
+	}
+	return false
+}
+
+// State é o estado de um limite após contabilizar a requisição
+type State struct {
+	Legacy    string        // Prefixo dos cabeçalhos legados (ex.: X-RateLimit, X-Quota)
+	Limit     int64         // Requisições permitidas na janela
+	Remaining int64         // Requisições restantes; negativo após exceder o limite
+	ResetAt   time.Time     // Reinício da janela
+	Window    time.Duration // Duração da janela, informada em RateLimit-Policy
+}
+
+// WriteHeaders escreve os cabeçalhos dos limites no estilo informado,
+// substituindo os escritos antes. Os cabeçalhos padronizados informam o limite
+// mais restritivo; RateLimit-Policy lista todos eles.
+func WriteHeaders(h http.Header, style string, states []State, now time.Time) {
+	h.Del("RateLimit-Limit")
+	h.Del("RateLimit-Remaining")
+	h.Del("RateLimit-Reset")
+	h.Del("RateLimit-Policy")
+	for _, s := range states {
+		h.Del(s.Legacy + "-Limit")
+		h.Del(s.Legacy + "-Remaining")
+		h.Del(s.Legacy + "-Reset")
+	}
+	if len(states) == 0 || style == HeadersNone {
+		return
+	}
+
+	if style == HeadersLegacy || style == HeadersBoth {
+		for _, s := range states {
+			h.Set(s.Legacy+"-Limit", strconv.FormatInt(s.Limit, 10))
+			h.Set(s.Legacy+"-Remaining", strconv.FormatInt(s.remaining(), 10))
+			h.Set(s.Legacy+"-Reset", strconv.FormatInt(s.ResetAt.Unix(), 10))
+		}
+	}
+
+	if style == HeadersStandard || style == HeadersBoth {
+		tightest := states[0]
+		policies := make([]string, 0, len(states))
+		for _, s := range states {
+			if s.remaining() < tightest.remaining() ||
+				(s.remaining() == tightest.remaining() && s.ResetAt.After(tightest.ResetAt)) {
+				tightest = s
+			}
+			policies = append(policies, fmt.Sprintf("%d;w=%d", s.Limit, int64(s.Window/time.Second)))
+		}
+		h.Set("RateLimit-Limit", strconv.FormatInt(tightest.Limit, 10))
+		h.Set("RateLimit-Remaining", strconv.FormatInt(tightest.remaining(), 10))
+		h.Set("RateLimit-Reset", strconv.FormatInt(secondsUntil(tightest.ResetAt, now, 0), 10))
+		h.Set("RateLimit-Policy", strings.Join(policies, ", "))
+	}
+}
+
+// RetryAfter retorna os segundos até o reinício da janela, para o Retry-After
+// e o corpo das recusas. O mínimo é 1, pois 0 induziria o cliente a repetir a
+// requisição imediatamente.
+func RetryAfter(resetAt, now time.Time) int {
+	return int(secondsUntil(resetAt, now, 1))
+}
+
+// remaining limita as requisições restantes a zero
+func (s State) remaining() int64 {
+	if s.Remaining < 0 {
+		return 0
+	}
+	return s.Remaining
+}
+
+// secondsUntil arredonda para cima os segundos até o instante, com um mínimo
+func secondsUntil(at, now time.Time, minimum int64) int64 {
+	seconds := int64((at.Sub(now) + time.Second - 1) / time.Second)
+	if seconds < minimum {
+		return minimum
+	}
+	return seconds
+}
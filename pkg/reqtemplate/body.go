@@ -0,0 +1,113 @@
+package reqtemplate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// ErrInvalidBody indica que o corpo original não é JSON válido
+var ErrInvalidBody = errors.New("corpo não é JSON válido")
+
+// BodyData são os valores disponíveis nos templates de corpo: os dados da
+// requisição e o corpo JSON original em .Body
+type BodyData struct {
+	*Data
+	Body interface{}
+}
+
+// bodyFuncs acrescenta às funções das expressões as auxiliares de JSON
+var bodyFuncs = template.FuncMap{
+	"json": func(value interface{}) (string, error) {
+		data, err := json.Marshal(value)
+		return string(data), err
+	},
+}
+
+// BodyTemplate transforma corpos JSON com um template text/template cuja saída
+// deve ser JSON válido, ex.: `{"id": {{ json .Body.codigo }}, "tenant": {{ json .Tenant }}}`
+type BodyTemplate struct {
+	source string
+	tmpl   *template.Template
+}
+
+// compiledBodies guarda os templates de corpo já compilados
+var compiledBodies sync.Map
+
+// CompileBody interpreta o template de corpo
+func CompileBody(source string) (*BodyTemplate, error) {
+	if cached, ok := compiledBodies.Load(source); ok {
+		return cached.(*BodyTemplate), nil
+	}
+
+	tmpl, err := template.New("body").Option("missingkey=zero").Funcs(funcs).Funcs(bodyFuncs).Parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("template de corpo inválido: %w", err)
+	}
+
+	t := &BodyTemplate{source: source, tmpl: tmpl}
+	compiledBodies.Store(source, t)
+	return t, nil
+}
+
+// Transform aplica o template ao corpo JSON. Um corpo vazio é exposto como
+// nil em .Body. O resultado é validado e compactado.
+func (t *BodyTemplate) Transform(body []byte, data *Data) ([]byte, error) {
+	var parsed interface{}
+	if len(bytes.TrimSpace(body)) > 0 {
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.UseNumber()
+		if err := decoder.Decode(&parsed); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidBody, err)
+		}
+	}
+	return t.Apply(parsed, data)
+}
+
+// Apply aplica o template a um valor já decodificado, exposto em .Body. O
+// resultado é validado e compactado.
+func (t *BodyTemplate) Apply(body interface{}, data *Data) ([]byte, error) {
+	if data == nil {
+		data = &Data{}
+	}
+
+	var buf bytes.Buffer
+	if err := t.tmpl.Execute(&buf, &BodyData{Data: data, Body: body}); err != nil {
+		return nil, fmt.Errorf("falha ao aplicar template de corpo: %w", err)
+	}
+
+	var out bytes.Buffer
+	if err := json.Compact(&out, buf.Bytes()); err != nil {
+		return nil, fmt.Errorf("template de corpo não produziu JSON válido: %w", err)
+	}
+	return out.Bytes(), nil
+}
+
+// IsJSON informa se o Content-Type é JSON (application/json ou +json)
+func IsJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+type dataContextKey struct{}
+
+// WithData associa os dados da requisição ao contexto, para etapas posteriores
+// do encaminhamento (ex.: a transformação da resposta no proxy)
+func WithData(ctx context.Context, data *Data) context.Context {
+	return context.WithValue(ctx, dataContextKey{}, data)
+}
+
+// DataFromContext retorna os dados da requisição associados ao contexto
+func DataFromContext(ctx context.Context) *Data {
+	data, _ := ctx.Value(dataContextKey{}).(*Data)
+	return data
+}
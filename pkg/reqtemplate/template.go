@@ -0,0 +1,93 @@
+package reqtemplate
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// Data são os valores da requisição disponíveis nas expressões
+type Data struct {
+	Route    string                 // Caminho registrado da rota
+	Method   string                 // Método HTTP
+	Path     string                 // Caminho requisitado
+	ClientIP string                 // IP do cliente
+	Tenant   string                 // Tenant identificado na requisição
+	Consumer string                 // Identificador do consumidor autenticado
+	Labels   map[string]string      // Rótulos da rota
+	Headers  map[string]string      // Cabeçalhos da requisição (nome canônico)
+	Params   map[string]string      // Parâmetros de caminho (ex.: :id)
+	Claims   map[string]interface{} // Claims do token do consumidor
+}
+
+// funcs são as funções auxiliares disponíveis nas expressões
+var funcs = template.FuncMap{
+	"default": func(fallback string, value interface{}) string {
+		if value == nil {
+			return fallback
+		}
+		if s := strings.TrimSpace(fmt.Sprint(value)); s != "" {
+			return s
+		}
+		return fallback
+	},
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"trim":  strings.TrimSpace,
+}
+
+// Template é uma expressão compilada, segura para uso concorrente
+type Template struct {
+	source string
+	tmpl   *template.Template
+}
+
+// compiled guarda as expressões já compiladas, indexadas pelo texto
+var compiled sync.Map
+
+// Compile interpreta a expressão no formato text/template do Go,
+// ex.: `{{ .Labels.team | default "platform" }}`
+func Compile(expr string) (*Template, error) {
+	if cached, ok := compiled.Load(expr); ok {
+		return cached.(*Template), nil
+	}
+
+	tmpl, err := template.New("expr").Option("missingkey=zero").Funcs(funcs).Parse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("expressão inválida %q: %w", expr, err)
+	}
+
+	t := &Template{source: expr, tmpl: tmpl}
+	compiled.Store(expr, t)
+	return t, nil
+}
+
+// Render avalia a expressão sobre os dados da requisição
+func (t *Template) Render(data *Data) (string, error) {
+	var buf bytes.Buffer
+	if err := t.tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("falha ao avaliar expressão %q: %w", t.source, err)
+	}
+
+	result := strings.TrimSpace(buf.String())
+	if result == "<no value>" {
+		return "", nil
+	}
+	return result, nil
+}
+
+// String retorna a expressão original
+func (t *Template) String() string {
+	return t.source
+}
+
+// Render compila (com cache) e avalia a expressão
+func Render(expr string, data *Data) (string, error) {
+	t, err := Compile(expr)
+	if err != nil {
+		return "", err
+	}
+	return t.Render(data)
+}
@@ -0,0 +1,51 @@
+// Package requestid gera e propaga o identificador de cada requisição, usado
+// para correlacionar os logs do gateway com os dos backends.
+package requestid
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// DefaultHeader é o cabeçalho padrão do identificador da requisição
+const DefaultHeader = "X-Request-ID"
+
+// maxLength limita o tamanho do identificador aceito do cliente
+const maxLength = 128
+
+type contextKey struct{}
+
+// New gera um novo identificador
+func New() string {
+	return uuid.NewString()
+}
+
+// Valid informa se o identificador recebido pode ser reaproveitado: não vazio,
+// limitado em tamanho e apenas com caracteres ASCII visíveis, evitando a
+// injeção de conteúdo nos logs
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// WithID retorna um contexto associado ao identificador da requisição
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext retorna o identificador associado ao contexto; vazio quando não há
+func FromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
@@ -0,0 +1,181 @@
+package resilience
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+// ErrLimitExceeded indica que o limite adaptativo de requisições simultâneas
+// foi atingido e não há vaga na fila de espera
+var ErrLimitExceeded = errors.New("limite adaptativo de concorrência atingido")
+
+// minRTTWindow é o período de cada janela da latência mínima. A referência é a
+// menor latência da janela atual e da anterior, acompanhando mudanças
+// duradouras no tempo de resposta.
+const minRTTWindow = 30 * time.Second
+
+// AdaptiveLimitConfig configura o limite adaptativo de concorrência
+type AdaptiveLimitConfig struct {
+	InitialLimit  int
+	MinLimit      int
+	MaxLimit      int
+	TargetLatency time.Duration // Latência acima da qual o limite é reduzido; zero usa Tolerance
+	Tolerance     float64       // Múltiplo da latência mínima aceito antes de reduzir o limite
+	BackoffRatio  float64       // Fator aplicado ao limite em cada redução
+	MaxQueue      int           // Requisições aguardando vaga
+}
+
+// AdaptiveLimiter limita as requisições simultâneas a um valor ajustado pela
+// latência observada (AIMD no estilo do TCP Vegas): enquanto a latência fica
+// abaixo do alvo e o limite está em uso, ele cresce cerca de uma vaga por
+// ciclo; quando a latência passa do alvo, ele é reduzido multiplicativamente,
+// no máximo uma vez por ciclo.
+type AdaptiveLimiter struct {
+	cfg AdaptiveLimitConfig
+
+	mu           sync.Mutex
+	limit        float64
+	inFlight     int
+	waiters      *list.List // chan struct{} de cada requisição na fila, em ordem de chegada
+	minRTT       time.Duration
+	prevMinRTT   time.Duration
+	windowStart  time.Time
+	lastDecrease time.Time
+}
+
+// NewAdaptiveLimiter cria o limitador com o limite inicial da configuração
+func NewAdaptiveLimiter(cfg AdaptiveLimitConfig) *AdaptiveLimiter {
+	if cfg.MinLimit <= 0 {
+		cfg.MinLimit = 1
+	}
+	if cfg.MaxLimit < cfg.MinLimit {
+		cfg.MaxLimit = cfg.MinLimit
+	}
+	if cfg.Tolerance < 1 {
+		cfg.Tolerance = 2
+	}
+	if cfg.BackoffRatio <= 0 || cfg.BackoffRatio >= 1 {
+		cfg.BackoffRatio = 0.9
+	}
+	limit := float64(min(max(cfg.InitialLimit, cfg.MinLimit), cfg.MaxLimit))
+	return &AdaptiveLimiter{cfg: cfg, limit: limit, waiters: list.New()}
+}
+
+// Acquire reserva uma vaga, aguardando na fila por até timeout. A função
+// retornada libera a vaga informando a latência da requisição; latência zero
+// libera a vaga sem ajustar o limite.
+func (l *AdaptiveLimiter) Acquire(ctx context.Context, timeout time.Duration) (func(time.Duration), error) {
+	l.mu.Lock()
+	if l.inFlight < l.currentLimit() && l.waiters.Len() == 0 {
+		l.inFlight++
+		l.mu.Unlock()
+		return l.releaser(), nil
+	}
+	if l.waiters.Len() >= l.cfg.MaxQueue || timeout <= 0 {
+		l.mu.Unlock()
+		return nil, ErrLimitExceeded
+	}
+	granted := make(chan struct{})
+	element := l.waiters.PushBack(granted)
+	l.mu.Unlock()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	var err error
+	select {
+	case <-granted:
+		return l.releaser(), nil
+	case <-timer.C:
+		err = ErrLimitExceeded
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	l.mu.Lock()
+	select {
+	case <-granted:
+		// A vaga foi concedida junto com a desistência: devolvê-la
+		l.inFlight--
+		l.grant()
+	default:
+		l.waiters.Remove(element)
+	}
+	l.mu.Unlock()
+	return nil, err
+}
+
+// Snapshot retorna o limite atual, as requisições em andamento e as na fila
+func (l *AdaptiveLimiter) Snapshot() (limit, inFlight, queued int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.currentLimit(), l.inFlight, l.waiters.Len()
+}
+
+func (l *AdaptiveLimiter) releaser() func(time.Duration) {
+	var once sync.Once
+	return func(rtt time.Duration) {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			if rtt > 0 {
+				l.observe(rtt, time.Now())
+			}
+			l.inFlight--
+			l.grant()
+		})
+	}
+}
+
+// observe ajusta o limite pela latência de uma requisição concluída
+func (l *AdaptiveLimiter) observe(rtt time.Duration, now time.Time) {
+	if now.Sub(l.windowStart) >= minRTTWindow {
+		l.prevMinRTT, l.minRTT = l.minRTT, 0
+		l.windowStart = now
+	}
+	if l.minRTT == 0 || rtt < l.minRTT {
+		l.minRTT = rtt
+	}
+
+	baseline := l.minRTT
+	if l.prevMinRTT > 0 && l.prevMinRTT < baseline {
+		baseline = l.prevMinRTT
+	}
+	threshold := time.Duration(float64(baseline) * l.cfg.Tolerance)
+	if l.cfg.TargetLatency > 0 {
+		threshold = l.cfg.TargetLatency
+	}
+
+	if rtt > threshold {
+		// Uma redução por ciclo, para que as requisições do mesmo pico não
+		// derrubem o limite de uma só vez
+		if now.Sub(l.lastDecrease) >= rtt {
+			l.limit = math.Max(float64(l.cfg.MinLimit), l.limit*l.cfg.BackoffRatio)
+			l.lastDecrease = now
+		}
+		return
+	}
+
+	// O limite só cresce quando está em uso; ociosidade não indica capacidade
+	if float64(l.inFlight)*2 >= l.limit {
+		l.limit = math.Min(float64(l.cfg.MaxLimit), l.limit+1/l.limit)
+	}
+}
+
+// grant entrega as vagas livres às requisições da fila, por ordem de chegada
+func (l *AdaptiveLimiter) grant() {
+	for l.waiters.Len() > 0 && l.inFlight < l.currentLimit() {
+		front := l.waiters.Front()
+		l.waiters.Remove(front)
+		l.inFlight++
+		close(front.Value.(chan struct{}))
+	}
+}
+
+func (l *AdaptiveLimiter) currentLimit() int {
+	return int(l.limit)
+}
@@ -0,0 +1,71 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrBulkheadFull indica que o limite de requisições simultâneas foi atingido
+// e não há vaga na fila de espera
+var ErrBulkheadFull = errors.New("limite de requisições simultâneas atingido")
+
+// ErrBulkheadTimeout indica que a requisição esperou na fila além do permitido
+var ErrBulkheadTimeout = errors.New("tempo de espera por vaga esgotado")
+
+// Bulkhead limita as execuções simultâneas, com uma fila de espera limitada
+type Bulkhead struct {
+	slots    chan struct{}
+	mu       sync.Mutex
+	waiting  int
+	maxQueue int
+}
+
+// NewBulkhead cria o limitador com maxConcurrent execuções simultâneas e até
+// maxQueue requisições aguardando vaga
+func NewBulkhead(maxConcurrent, maxQueue int) *Bulkhead {
+	return &Bulkhead{slots: make(chan struct{}, maxConcurrent), maxQueue: maxQueue}
+}
+
+// Acquire reserva uma vaga, aguardando na fila por até timeout. A função
+// retornada libera a vaga e pode ser chamada mais de uma vez.
+func (b *Bulkhead) Acquire(ctx context.Context, timeout time.Duration) (func(), error) {
+	select {
+	case b.slots <- struct{}{}:
+		return b.releaser(), nil
+	default:
+	}
+
+	b.mu.Lock()
+	if b.waiting >= b.maxQueue {
+		b.mu.Unlock()
+		return nil, ErrBulkheadFull
+	}
+	b.waiting++
+	b.mu.Unlock()
+
+	defer func() {
+		b.mu.Lock()
+		b.waiting--
+		b.mu.Unlock()
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case b.slots <- struct{}{}:
+		return b.releaser(), nil
+	case <-timer.C:
+		return nil, ErrBulkheadTimeout
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (b *Bulkhead) releaser() func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() { <-b.slots })
+	}
+}
@@ -31,13 +31,38 @@ const (
 
 // CircuitBreakerConfig contém a configuração do circuit breaker
 type CircuitBreakerConfig struct {
-	Name            string
-	MaxRequestsFail int           // Número máximo de falhas antes de abrir o circuito
-	Interval        time.Duration // Intervalo no qual contar falhas
-	Timeout         time.Duration // Tempo que o circuito fica aberto antes de tentar half-open
-	MaxRequests     int           // Número máximo de requisições no estado half-open
+	Name               string
+	MaxRequestsFail    int           // Número máximo de falhas consecutivas antes de abrir o circuito
+	Interval           time.Duration // Intervalo no qual contar falhas para a taxa de erro
+	Timeout            time.Duration // Tempo que o circuito fica aberto antes de tentar half-open
+	MaxRequests        int           // Número máximo de requisições no estado half-open
+	ErrorRateThreshold float64       // Percentual de falhas no intervalo que abre o circuito; zero desativa
+	MinimumRequests    int           // Requisições mínimas no intervalo antes de avaliar a taxa de erro
 }
 
+// withDefaults aplica os valores padrão aos campos não especificados
+func (config CircuitBreakerConfig) withDefaults() CircuitBreakerConfig {
+	if config.MaxRequestsFail <= 0 {
+		config.MaxRequestsFail = 5
+	}
+	if config.Interval <= 0 {
+		config.Interval = time.Minute
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 30 * time.Second
+	}
+	if config.MaxRequests <= 0 {
+		config.MaxRequests = 1
+	}
+	if config.MinimumRequests <= 0 {
+		config.MinimumRequests = 20
+	}
+	return config
+}
+
+// StateChangeFunc é chamada quando o circuito abre ou fecha por decisão local
+type StateChangeFunc func(name string, state CircuitState, changedAt, nextAttempt time.Time)
+
 // CircuitBreaker implementa o pattern Circuit Breaker
 type CircuitBreaker struct {
 	name        string
@@ -45,6 +70,8 @@ type CircuitBreaker struct {
 	interval    time.Duration
 	timeout     time.Duration
 	maxRequests int
+	errorRate   float64
+	minRequests int
 
 	mutex               sync.RWMutex
 	state               CircuitState
@@ -52,45 +79,62 @@ type CircuitBreaker struct {
 	lastStateChangeTime time.Time
 	nextAttemptTime     time.Time
 	halfOpenRequests    int
-
-	logger  *zap.Logger
-	metrics *metrics.APIMetrics
-	tracer  trace.Tracer
+	windowStart         time.Time
+	windowRequests      int
+	windowFailures      int
+
+	logger        *zap.Logger
+	metrics       *metrics.APIMetrics
+	tracer        trace.Tracer
+	onStateChange StateChangeFunc
 }
 
 // NewCircuitBreaker cria um novo circuit breaker
 func NewCircuitBreaker(config CircuitBreakerConfig, logger *zap.Logger, metrics *metrics.APIMetrics) *CircuitBreaker {
 	// Valores padrão se não especificados
-	if config.MaxRequestsFail <= 0 {
-		config.MaxRequestsFail = 5
-	}
-	if config.Interval <= 0 {
-		config.Interval = time.Minute
-	}
-	if config.Timeout <= 0 {
-		config.Timeout = 30 * time.Second
-	}
-	if config.MaxRequests <= 0 {
-		config.MaxRequests = 1
-	}
+	config = config.withDefaults()
 
 	// Obter tracer para o Circuit Breaker
 	tracer := otel.GetTracerProvider().Tracer("api-gateway.circuit_breaker")
 
+	now := time.Now()
 	return &CircuitBreaker{
 		name:                config.Name,
 		maxFails:            config.MaxRequestsFail,
 		interval:            config.Interval,
 		timeout:             config.Timeout,
 		maxRequests:         config.MaxRequests,
+		errorRate:           config.ErrorRateThreshold,
+		minRequests:         config.MinimumRequests,
 		state:               StateClose,
-		lastStateChangeTime: time.Now(),
+		lastStateChangeTime: now,
+		windowStart:         now,
 		logger:              logger,
 		metrics:             metrics,
 		tracer:              tracer,
 	}
 }
 
+// Configure atualiza os limiares do circuit breaker sem alterar o estado atual,
+// permitindo aplicar mudanças na política da rota sem descartar o histórico
+func (cb *CircuitBreaker) Configure(config CircuitBreakerConfig) {
+	config = config.withDefaults()
+
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+	cb.maxFails = config.MaxRequestsFail
+	cb.interval = config.Interval
+	cb.timeout = config.Timeout
+	cb.maxRequests = config.MaxRequests
+	cb.errorRate = config.ErrorRateThreshold
+	cb.minRequests = config.MinimumRequests
+}
+
+// Name retorna o nome do circuit breaker
+func (cb *CircuitBreaker) Name() string {
+	return cb.name
+}
+
 // Execute executa a função com circuit breaker
 func (cb *CircuitBreaker) Execute(ctx context.Context, fn func(context.Context) (interface{}, error)) (interface{}, error) {
 	// Criar span para a execução do Circuit Breaker
@@ -99,8 +143,7 @@ func (cb *CircuitBreaker) Execute(ctx context.Context, fn func(context.Context)
 		fmt.Sprintf("CircuitBreaker:%s", cb.name),
 		trace.WithAttributes(
 			attribute.String("circuit_breaker.name", cb.name),
-			attribute.Int("circuit_breaker.max_fails", cb.maxFails),
-			attribute.String("circuit_breaker.state", getStateString(cb.state)),
+			attribute.String("circuit_breaker.state", cb.GetState().String()),
 		),
 	)
 	defer span.End()
@@ -110,8 +153,11 @@ func (cb *CircuitBreaker) Execute(ctx context.Context, fn func(context.Context)
 		span.SetStatus(codes.Error, "circuit breaker is open")
 		span.SetAttributes(
 			attribute.Bool("circuit_breaker.request_rejected", true),
-			attribute.String("circuit_breaker.state", getStateString(cb.state)),
+			attribute.String("circuit_breaker.state", cb.GetState().String()),
 		)
+		if cb.metrics != nil {
+			cb.metrics.CircuitBreakerRejected(cb.name)
+		}
 		return nil, ErrCircuitOpen
 	}
 
@@ -138,7 +184,7 @@ func (cb *CircuitBreaker) Execute(ctx context.Context, fn func(context.Context)
 
 	// Adicionar informações finais ao span principal
 	span.SetAttributes(
-		attribute.String("circuit_breaker.final_state", getStateString(cb.state)),
+		attribute.String("circuit_breaker.final_state", cb.GetState().String()),
 		attribute.Bool("circuit_breaker.operation_successful", err == nil),
 	)
 
@@ -151,8 +197,8 @@ func (cb *CircuitBreaker) Execute(ctx context.Context, fn func(context.Context)
 	return result, err
 }
 
-// Função auxiliar para converter estado em string para telemetria
-func getStateString(state CircuitState) string {
+// String retorna o nome do estado usado em logs e telemetria
+func (state CircuitState) String() string {
 	switch state {
 	case StateClose:
 		return "closed"
@@ -167,8 +213,8 @@ func getStateString(state CircuitState) string {
 
 // allowRequest verifica se a requisição deve ser permitida com base no estado atual
 func (cb *CircuitBreaker) allowRequest() bool {
-	cb.mutex.RLock()
-	defer cb.mutex.RUnlock()
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
 
 	now := time.Now()
 
@@ -178,24 +224,20 @@ func (cb *CircuitBreaker) allowRequest() bool {
 
 	case StateOpen:
 		// Verificar se o tempo de timeout passou para tentar half-open
-		if now.After(cb.nextAttemptTime) {
-			// Precisamos adquirir um lock de escrita para mudar o estado
-			cb.mutex.RUnlock()
-			cb.mutex.Lock()
-			defer cb.mutex.Unlock()
-
-			// Verificar novamente para evitar race condition
-			if cb.state == StateOpen && now.After(cb.nextAttemptTime) {
-				cb.toHalfOpen(now)
-				return true
-			}
+		if !now.After(cb.nextAttemptTime) {
 			return false
 		}
-		return false
+		cb.toHalfOpen(now)
+		cb.halfOpenRequests++
+		return true
 
 	case StateHalfOpen:
 		// Permitir um número limitado de requisições no estado half-open
-		return cb.halfOpenRequests < cb.maxRequests
+		if cb.halfOpenRequests >= cb.maxRequests {
+			return false
+		}
+		cb.halfOpenRequests++
+		return true
 	}
 
 	return false
@@ -210,7 +252,14 @@ func (cb *CircuitBreaker) recordResult(success bool) {
 
 	switch cb.state {
 	case StateClose:
+		// Reiniciar a janela da taxa de erro ao fim de cada intervalo
+		if now.Sub(cb.windowStart) >= cb.interval {
+			cb.resetWindow(now)
+		}
+		cb.windowRequests++
 		if !success {
+			cb.windowFailures++
+
 			// Incrementar contador de falhas
 			cb.failCount++
 			cb.logger.Debug("circuit breaker registrou falha",
@@ -219,7 +268,7 @@ func (cb *CircuitBreaker) recordResult(success bool) {
 				zap.Int("maxFails", cb.maxFails))
 
 			// Se passar do limite, abrir o circuito
-			if cb.failCount >= cb.maxFails {
+			if cb.failCount >= cb.maxFails || cb.errorRateExceeded() {
 				cb.toOpen(now)
 			}
 		} else {
@@ -238,20 +287,41 @@ func (cb *CircuitBreaker) recordResult(success bool) {
 	}
 }
 
+// errorRateExceeded indica se a taxa de falhas da janela atual atingiu o limiar
+func (cb *CircuitBreaker) errorRateExceeded() bool {
+	if cb.errorRate <= 0 || cb.windowRequests < cb.minRequests {
+		return false
+	}
+	return float64(cb.windowFailures)*100 >= cb.errorRate*float64(cb.windowRequests)
+}
+
+// resetWindow inicia uma nova janela de contagem da taxa de erro
+func (cb *CircuitBreaker) resetWindow(now time.Time) {
+	cb.windowStart = now
+	cb.windowRequests = 0
+	cb.windowFailures = 0
+}
+
 // toOpen muda o estado para open
 func (cb *CircuitBreaker) toOpen(now time.Time) {
 	cb.state = StateOpen
 	cb.lastStateChangeTime = now
 	cb.nextAttemptTime = now.Add(cb.timeout)
+	cb.resetWindow(now)
 
 	// Registrar a mudança de estado nas métricas
 	if cb.metrics != nil {
 		cb.metrics.CircuitBreakerStateChanged(cb.name, true)
+		cb.metrics.CircuitBreakerStateSet(cb.name, int(StateOpen))
 	}
 
 	cb.logger.Info("circuit breaker mudou para estado aberto",
 		zap.String("name", cb.name),
 		zap.Time("nextAttempt", cb.nextAttemptTime))
+
+	if cb.onStateChange != nil {
+		go cb.onStateChange(cb.name, StateOpen, now, cb.nextAttemptTime)
+	}
 }
 
 // toHalfOpen muda o estado para half-open
@@ -259,6 +329,9 @@ func (cb *CircuitBreaker) toHalfOpen(now time.Time) {
 	cb.state = StateHalfOpen
 	cb.lastStateChangeTime = now
 	cb.halfOpenRequests = 0
+	if cb.metrics != nil {
+		cb.metrics.CircuitBreakerStateSet(cb.name, int(StateHalfOpen))
+	}
 	cb.logger.Info("circuit breaker mudou para estado meio-aberto", zap.String("name", cb.name))
 }
 
@@ -267,13 +340,59 @@ func (cb *CircuitBreaker) toClose(now time.Time) {
 	cb.state = StateClose
 	cb.lastStateChangeTime = now
 	cb.failCount = 0
+	cb.resetWindow(now)
 
 	// Registrar a mudança de estado nas métricas
 	if cb.metrics != nil {
 		cb.metrics.CircuitBreakerStateChanged(cb.name, false)
+		cb.metrics.CircuitBreakerStateSet(cb.name, int(StateClose))
 	}
 
 	cb.logger.Info("circuit breaker mudou para estado fechado", zap.String("name", cb.name))
+
+	if cb.onStateChange != nil {
+		go cb.onStateChange(cb.name, StateClose, now, time.Time{})
+	}
+}
+
+// OnStateChange registra a função chamada quando o circuito abre ou fecha.
+// Estados aplicados com ApplyState não disparam a função.
+func (cb *CircuitBreaker) OnStateChange(fn StateChangeFunc) {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+	cb.onStateChange = fn
+}
+
+// ApplyState aplica um estado decidido por outra instância do cluster. Estados
+// mais antigos que a última mudança local são ignorados.
+func (cb *CircuitBreaker) ApplyState(state CircuitState, changedAt, nextAttempt time.Time) {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	if changedAt.Before(cb.lastStateChangeTime) || state == cb.state {
+		return
+	}
+
+	switch state {
+	case StateOpen:
+		cb.nextAttemptTime = nextAttempt
+	case StateClose:
+		cb.failCount = 0
+		cb.resetWindow(changedAt)
+	default:
+		return
+	}
+	cb.state = state
+	cb.lastStateChangeTime = changedAt
+
+	if cb.metrics != nil {
+		cb.metrics.CircuitBreakerStateChanged(cb.name, state == StateOpen)
+		cb.metrics.CircuitBreakerStateSet(cb.name, int(state))
+	}
+
+	cb.logger.Info("circuit breaker sincronizado com o cluster",
+		zap.String("name", cb.name),
+		zap.String("state", state.String()))
 }
 
 // GetState retorna o estado atual do circuit breaker
@@ -283,6 +402,19 @@ func (cb *CircuitBreaker) GetState() CircuitState {
 	return cb.state
 }
 
+// RetryAfter retorna quanto tempo falta para o circuito aberto voltar a testar o backend
+func (cb *CircuitBreaker) RetryAfter() time.Duration {
+	cb.mutex.RLock()
+	defer cb.mutex.RUnlock()
+	if cb.state != StateOpen {
+		return 0
+	}
+	if wait := time.Until(cb.nextAttemptTime); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
 // Reset reseta o circuit breaker para o estado fechado
 func (cb *CircuitBreaker) Reset() {
 	cb.mutex.Lock()
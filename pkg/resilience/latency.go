@@ -0,0 +1,75 @@
+package resilience
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// latencySamples é o número de amostras mantidas por chave
+const latencySamples = 512
+
+// LatencyTracker guarda as latências recentes por chave (ex.: rota) e calcula
+// percentis sobre elas, usados para definir o atraso do hedging
+type LatencyTracker struct {
+	mu      sync.Mutex
+	windows map[string]*latencyWindow
+}
+
+type latencyWindow struct {
+	samples []time.Duration
+	next    int
+	filled  bool
+}
+
+// NewLatencyTracker cria o rastreador de latências
+func NewLatencyTracker() *LatencyTracker {
+	return &LatencyTracker{windows: make(map[string]*latencyWindow)}
+}
+
+// Observe registra uma latência
+func (t *LatencyTracker) Observe(key string, latency time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	window, ok := t.windows[key]
+	if !ok {
+		window = &latencyWindow{samples: make([]time.Duration, latencySamples)}
+		t.windows[key] = window
+	}
+	window.samples[window.next] = latency
+	window.next = (window.next + 1) % latencySamples
+	if window.next == 0 {
+		window.filled = true
+	}
+}
+
+// Percentile retorna o percentil (0-100) das latências da chave; false quando
+// há menos amostras que o mínimo informado
+func (t *LatencyTracker) Percentile(key string, percentile float64, minSamples int) (time.Duration, bool) {
+	t.mu.Lock()
+	window, ok := t.windows[key]
+	var samples []time.Duration
+	if ok {
+		count := window.next
+		if window.filled {
+			count = latencySamples
+		}
+		samples = append(samples, window.samples[:count]...)
+	}
+	t.mu.Unlock()
+
+	if len(samples) == 0 || len(samples) < minSamples {
+		return 0, false
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+	index := int(math.Ceil(percentile/100*float64(len(samples)))) - 1
+	if index < 0 {
+		index = 0
+	}
+	if index >= len(samples) {
+		index = len(samples) - 1
+	}
+	return samples[index], true
+}
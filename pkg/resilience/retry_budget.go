@@ -0,0 +1,99 @@
+package resilience
+
+import (
+	"sync"
+	"time"
+)
+
+// RetryBudget limita as novas tentativas (retries e hedges) a uma fração das
+// requisições recentes, para que as repetições não multipliquem a carga sobre
+// backends já degradados. Uma reserva mínima por segundo permite repetir
+// mesmo com pouco tráfego.
+type RetryBudget struct {
+	mu            sync.Mutex
+	ratio         float64
+	minPerSecond  int
+	window        time.Duration
+	bucketSize    time.Duration
+	requests      []int64
+	retries       []int64
+	bucketStarted []time.Time
+	now           func() time.Time
+}
+
+// retryBudgetBuckets é o número de subdivisões da janela
+const retryBudgetBuckets = 10
+
+// NewRetryBudget cria o orçamento: ratio é a fração das requisições da janela
+// que pode ser repetida (ex.: 0.2) e minPerSecond a reserva mínima
+func NewRetryBudget(ratio float64, minPerSecond int, window time.Duration) *RetryBudget {
+	if window <= 0 {
+		window = 10 * time.Second
+	}
+	return &RetryBudget{
+		ratio:         ratio,
+		minPerSecond:  minPerSecond,
+		window:        window,
+		bucketSize:    window / retryBudgetBuckets,
+		requests:      make([]int64, retryBudgetBuckets),
+		retries:       make([]int64, retryBudgetBuckets),
+		bucketStarted: make([]time.Time, retryBudgetBuckets),
+		now:           time.Now,
+	}
+}
+
+// RecordRequest contabiliza uma requisição original
+func (b *RetryBudget) RecordRequest() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.requests[b.bucket()]++
+}
+
+// TryRetry reserva uma nova tentativa; false indica que o orçamento se esgotou
+func (b *RetryBudget) TryRetry() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	current := b.bucket()
+	var requests, retries int64
+	for i := range b.requests {
+		requests += b.requests[i]
+		retries += b.retries[i]
+	}
+
+	allowed := int64(b.ratio * float64(requests))
+	if reserve := int64(float64(b.minPerSecond) * b.window.Seconds()); reserve > allowed {
+		allowed = reserve
+	}
+	if retries >= allowed {
+		return false
+	}
+	b.retries[current]++
+	return true
+}
+
+// bucket retorna o índice da subdivisão atual, zerando as que saíram da janela
+func (b *RetryBudget) bucket() int {
+	now := b.now()
+	index := int(now.UnixNano()/int64(b.bucketSize)) % retryBudgetBuckets
+	start := now.Truncate(b.bucketSize)
+	if !b.bucketStarted[index].Equal(start) {
+		b.bucketStarted[index] = start
+		b.requests[index] = 0
+		b.retries[index] = 0
+	}
+	// Subdivisões não atualizadas há mais de uma janela também expiram
+	for i, started := range b.bucketStarted {
+		if i != index && now.Sub(started) >= b.window {
+			b.requests[i] = 0
+			b.retries[i] = 0
+		}
+	}
+	return index
+}
@@ -0,0 +1,210 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/diillson/api-gateway-go/pkg/config"
+)
+
+// awsService é o nome do serviço usado na assinatura das requisições
+const awsService = "secretsmanager"
+
+// AWSProvider lê segredos do AWS Secrets Manager. A referência é
+// "id-ou-arn#chave"; com chave, o SecretString é lido como JSON.
+// As credenciais vêm da configuração ou das variáveis AWS_ACCESS_KEY_ID,
+// AWS_SECRET_ACCESS_KEY e AWS_SESSION_TOKEN.
+type AWSProvider struct {
+	region       string
+	endpoint     string
+	accessKey    string
+	secretKey    string
+	sessionToken string
+	client       *http.Client
+}
+
+// NewAWSProvider cria o provedor do Secrets Manager
+func NewAWSProvider(cfg config.AWSSecretsConfig) (*AWSProvider, error) {
+	region := cfg.Region
+	if region == "" {
+		region = os.Getenv("AWS_REGION")
+	}
+	if region == "" {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	if region == "" {
+		return nil, errors.New("região da AWS não configurada")
+	}
+
+	accessKey, secretKey, sessionToken := cfg.AccessKeyID, cfg.SecretAccessKey, cfg.SessionToken
+	if accessKey == "" {
+		accessKey = os.Getenv("AWS_ACCESS_KEY_ID")
+		secretKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+		sessionToken = os.Getenv("AWS_SESSION_TOKEN")
+	}
+	if accessKey == "" || secretKey == "" {
+		return nil, errors.New("credenciais da AWS não configuradas")
+	}
+
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.%s.amazonaws.com", awsService, region)
+	}
+
+	return &AWSProvider{
+		region:       region,
+		endpoint:     strings.TrimRight(endpoint, "/"),
+		accessKey:    accessKey,
+		secretKey:    secretKey,
+		sessionToken: sessionToken,
+		client:       &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// Name identifica o provedor
+func (p *AWSProvider) Name() string {
+	return "aws"
+}
+
+// GetSecret obtém a versão atual (AWSCURRENT) do segredo
+func (p *AWSProvider) GetSecret(ctx context.Context, ref string) (string, error) {
+	id, key := splitRef(ref)
+	body, err := json.Marshal(map[string]string{"SecretId": id})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint+"/", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	p.sign(req, body, time.Now().UTC())
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("falha ao consultar o Secrets Manager: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("falha ao ler resposta do Secrets Manager: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Type    string `json:"__type"`
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(data, &apiErr)
+		if strings.HasSuffix(apiErr.Type, "ResourceNotFoundException") {
+			return "", fmt.Errorf("%w: %s", ErrNotFound, id)
+		}
+		return "", fmt.Errorf("secrets manager retornou status %d: %s %s", resp.StatusCode, apiErr.Type, apiErr.Message)
+	}
+
+	var payload struct {
+		SecretString *string `json:"SecretString"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return "", fmt.Errorf("resposta inválida do Secrets Manager: %w", err)
+	}
+	if payload.SecretString == nil {
+		return "", fmt.Errorf("segredo %s não contém SecretString", id)
+	}
+	return selectKey(*payload.SecretString, key)
+}
+
+// sign assina a requisição com AWS Signature Version 4
+func (p *AWSProvider) sign(req *http.Request, body []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("Host", req.URL.Host)
+	req.Header.Set("X-Amz-Date", amzDate)
+	if p.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", p.sessionToken)
+	}
+
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, strings.ToLower(name))
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name)
+		canonicalHeaders.WriteString(":")
+		canonicalHeaders.WriteString(strings.TrimSpace(req.Header.Get(name)))
+		canonicalHeaders.WriteString("\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		sha256Hex(body),
+	}, "\n")
+
+	scope := strings.Join([]string{date, p.region, awsService, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+p.secretKey), date)
+	key = hmacSHA256(key, p.region)
+	key = hmacSHA256(key, awsService)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		p.accessKey, scope, signedHeaders, signature))
+	// O Host é enviado pelo cliente HTTP a partir da URL
+	req.Header.Del("Host")
+	req.Host = req.URL.Host
+}
+
+// canonicalQuery ordena os parâmetros da query string para a assinatura
+func canonicalQuery(query url.Values) string {
+	if len(query) == 0 {
+		return ""
+	}
+	return strings.ReplaceAll(query.Encode(), "+", "%20")
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,58 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// EnvProvider lê segredos de variáveis de ambiente. A referência é o nome da
+// variável.
+type EnvProvider struct{}
+
+// NewEnvProvider cria o provedor de variáveis de ambiente
+func NewEnvProvider() *EnvProvider {
+	return &EnvProvider{}
+}
+
+// Name identifica o provedor
+func (p *EnvProvider) Name() string {
+	return "env"
+}
+
+// GetSecret retorna o valor da variável de ambiente
+func (p *EnvProvider) GetSecret(_ context.Context, ref string) (string, error) {
+	value, ok := os.LookupEnv(ref)
+	if !ok {
+		return "", fmt.Errorf("%w: variável %s", ErrNotFound, ref)
+	}
+	return value, nil
+}
+
+// FileProvider lê segredos de arquivos, como os volumes de Secrets do
+// Kubernetes e os arquivos do Vault Agent. A referência é o caminho do
+// arquivo; o arquivo é lido novamente a cada atualização.
+type FileProvider struct{}
+
+// NewFileProvider cria o provedor de arquivos
+func NewFileProvider() *FileProvider {
+	return &FileProvider{}
+}
+
+// Name identifica o provedor
+func (p *FileProvider) Name() string {
+	return "file"
+}
+
+// GetSecret retorna o conteúdo do arquivo sem a quebra de linha final
+func (p *FileProvider) GetSecret(_ context.Context, ref string) (string, error) {
+	data, err := os.ReadFile(ref)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("%w: arquivo %s", ErrNotFound, ref)
+		}
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
@@ -0,0 +1,94 @@
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/diillson/api-gateway-go/pkg/config"
+	"github.com/diillson/api-gateway-go/pkg/kubeapi"
+)
+
+// requestTimeout limita cada consulta de Secret à API do cluster
+const requestTimeout = 10 * time.Second
+
+// KubernetesProvider lê Secrets pela API do cluster, com as credenciais da
+// service account do pod. A referência é "nome#chave", ou
+// "namespace/nome#chave" para outro namespace. A service account precisa da
+// permissão get em secrets.
+type KubernetesProvider struct {
+	client    *kubeapi.Client
+	namespace string
+}
+
+// NewKubernetesProvider cria o provedor a partir do ambiente do pod
+func NewKubernetesProvider(cfg config.KubernetesSecretsConfig) (*KubernetesProvider, error) {
+	client, err := kubeapi.NewInClusterClient(cfg.APIServer)
+	if err != nil {
+		return nil, err
+	}
+
+	namespace := cfg.Namespace
+	if namespace == "" {
+		namespace, err = kubeapi.PodNamespace()
+		if err != nil {
+			return nil, fmt.Errorf("namespace do Kubernetes não configurado: %w", err)
+		}
+	}
+
+	return &KubernetesProvider{
+		client:    client,
+		namespace: namespace,
+	}, nil
+}
+
+// Name identifica o provedor
+func (p *KubernetesProvider) Name() string {
+	return "kubernetes"
+}
+
+// GetSecret lê a chave do Secret
+func (p *KubernetesProvider) GetSecret(ctx context.Context, ref string) (string, error) {
+	name, key := splitRef(ref)
+	if key == "" {
+		return "", fmt.Errorf("referência do Secret sem chave: %q (use nome#chave)", ref)
+	}
+	namespace := p.namespace
+	if ns, secretName, ok := strings.Cut(name, "/"); ok {
+		namespace, name = ns, secretName
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	resp, err := p.client.Get(ctx, fmt.Sprintf("/api/v1/namespaces/%s/secrets/%s",
+		url.PathEscape(namespace), url.PathEscape(name)), nil)
+	if errors.Is(err, kubeapi.ErrNotFound) {
+		return "", fmt.Errorf("%w: secret %s/%s", ErrNotFound, namespace, name)
+	}
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var secret struct {
+		Data map[string]string `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&secret); err != nil {
+		return "", fmt.Errorf("resposta inválida da API do Kubernetes: %w", err)
+	}
+	encoded, ok := secret.Data[key]
+	if !ok {
+		return "", fmt.Errorf("%w: chave %q do secret %s/%s", ErrNotFound, key, namespace, name)
+	}
+	value, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("valor do secret não está em base64: %w", err)
+	}
+	return string(value), nil
+}
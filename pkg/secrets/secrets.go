@@ -0,0 +1,166 @@
+// Package secrets obtém segredos do gateway (como o segredo JWT) de provedores
+// externos — variáveis de ambiente, arquivos, HashiCorp Vault, AWS Secrets
+// Manager e Kubernetes — e os mantém atualizados.
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/diillson/api-gateway-go/pkg/config"
+	"go.uber.org/zap"
+)
+
+// ErrNotFound indica que o segredo não existe no provedor
+var ErrNotFound = errors.New("segredo não encontrado")
+
+// Provider obtém o valor atual de um segredo pela referência. O formato da
+// referência depende do provedor; em geral "nome#chave", onde a chave
+// seleciona um campo de segredos com vários valores.
+type Provider interface {
+	Name() string
+	GetSecret(ctx context.Context, ref string) (string, error)
+}
+
+// NewProvider cria o provedor configurado
+func NewProvider(cfg config.SecretsConfig) (Provider, error) {
+	switch cfg.Provider {
+	case "", "env":
+		return NewEnvProvider(), nil
+	case "file":
+		return NewFileProvider(), nil
+	case "vault":
+		return NewVaultProvider(cfg.Vault)
+	case "aws":
+		return NewAWSProvider(cfg.AWS)
+	case "kubernetes":
+		return NewKubernetesProvider(cfg.Kubernetes)
+	default:
+		return nil, fmt.Errorf("provedor de segredos desconhecido: %q", cfg.Provider)
+	}
+}
+
+// Secret mantém em memória o valor de um segredo, atualizado periodicamente.
+// O valor anterior é preservado após uma rotação para que credenciais emitidas
+// com ele (como tokens já assinados) continuem válidas até expirarem.
+type Secret struct {
+	provider Provider
+	ref      string
+	logger   *zap.Logger
+
+	mu       sync.RWMutex
+	current  string
+	previous string
+}
+
+// NewSecret obtém o segredo do provedor. Falha quando o segredo não existe ou
+// está vazio.
+func NewSecret(ctx context.Context, provider Provider, ref string, logger *zap.Logger) (*Secret, error) {
+	s := &Secret{
+		provider: provider,
+		ref:      ref,
+		logger:   logger,
+	}
+	if err := s.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Value retorna o valor atual do segredo
+func (s *Secret) Value() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.current
+}
+
+// Previous retorna o valor anterior à última rotação; vazio sem rotação
+func (s *Secret) Previous() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.previous
+}
+
+// Refresh obtém o valor atual do provedor. Em caso de falha, o valor em
+// memória é mantido.
+func (s *Secret) Refresh(ctx context.Context) error {
+	value, err := s.provider.GetSecret(ctx, s.ref)
+	if err != nil {
+		return fmt.Errorf("falha ao obter segredo %q do provedor %s: %w", s.ref, s.provider.Name(), err)
+	}
+	if value == "" {
+		return fmt.Errorf("segredo %q vazio no provedor %s", s.ref, s.provider.Name())
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if value != s.current {
+		if s.current != "" {
+			s.previous = s.current
+			s.logger.Info("Segredo rotacionado",
+				zap.String("provider", s.provider.Name()),
+				zap.String("ref", s.ref))
+		}
+		s.current = value
+	}
+	return nil
+}
+
+// Start atualiza o segredo periodicamente até o contexto ser cancelado
+func (s *Secret) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Refresh(ctx); err != nil && ctx.Err() == nil {
+				s.logger.Warn("Falha ao atualizar segredo; mantendo o valor anterior",
+					zap.String("provider", s.provider.Name()),
+					zap.String("ref", s.ref),
+					zap.Error(err))
+			}
+		}
+	}
+}
+
+// splitRef separa a referência "nome#chave"
+func splitRef(ref string) (string, string) {
+	name, key, _ := strings.Cut(ref, "#")
+	return name, key
+}
+
+// selectKey extrai a chave de um segredo em JSON; sem chave, retorna o valor inteiro
+func selectKey(value, key string) (string, error) {
+	if key == "" {
+		return value, nil
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(value), &fields); err != nil {
+		return "", fmt.Errorf("segredo não é um objeto JSON para extrair a chave %q", key)
+	}
+	return fieldString(fields, key)
+}
+
+// fieldString retorna o campo de texto do segredo
+func fieldString(fields map[string]interface{}, key string) (string, error) {
+	raw, ok := fields[key]
+	if !ok {
+		return "", fmt.Errorf("%w: chave %q", ErrNotFound, key)
+	}
+	value, ok := raw.(string)
+	if !ok {
+		return "", fmt.Errorf("chave %q do segredo não é texto", key)
+	}
+	return value, nil
+}
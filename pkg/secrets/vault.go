@@ -0,0 +1,141 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/diillson/api-gateway-go/pkg/config"
+)
+
+// VaultProvider lê segredos da engine KV do HashiCorp Vault. A referência é
+// "caminho#chave" dentro do mount configurado; sem chave, usa o campo "value".
+type VaultProvider struct {
+	address   string
+	mount     string
+	kvVersion int
+	namespace string
+	token     string
+	tokenFile string
+	client    *http.Client
+}
+
+// NewVaultProvider cria o provedor do Vault. O token vem da configuração, do
+// arquivo configurado (ex.: Vault Agent) ou da variável VAULT_TOKEN.
+func NewVaultProvider(cfg config.VaultSecretsConfig) (*VaultProvider, error) {
+	address := cfg.Address
+	if address == "" {
+		address = os.Getenv("VAULT_ADDR")
+	}
+	if address == "" {
+		return nil, errors.New("endereço do Vault não configurado")
+	}
+	token := cfg.Token
+	if token == "" && cfg.TokenFile == "" {
+		token = os.Getenv("VAULT_TOKEN")
+	}
+	if token == "" && cfg.TokenFile == "" {
+		return nil, errors.New("token do Vault não configurado")
+	}
+
+	mount := strings.Trim(cfg.Mount, "/")
+	if mount == "" {
+		mount = "secret"
+	}
+	kvVersion := cfg.KVVersion
+	if kvVersion == 0 {
+		kvVersion = 2
+	}
+
+	return &VaultProvider{
+		address:   strings.TrimRight(address, "/"),
+		mount:     mount,
+		kvVersion: kvVersion,
+		namespace: cfg.Namespace,
+		token:     token,
+		tokenFile: cfg.TokenFile,
+		client:    &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// Name identifica o provedor
+func (p *VaultProvider) Name() string {
+	return "vault"
+}
+
+// GetSecret lê o segredo da engine KV
+func (p *VaultProvider) GetSecret(ctx context.Context, ref string) (string, error) {
+	path, key := splitRef(ref)
+	if key == "" {
+		key = "value"
+	}
+
+	endpoint := fmt.Sprintf("%s/v1/%s/%s", p.address, p.mount, strings.TrimLeft(path, "/"))
+	if p.kvVersion == 2 {
+		endpoint = fmt.Sprintf("%s/v1/%s/data/%s", p.address, p.mount, strings.TrimLeft(path, "/"))
+	}
+
+	token, err := p.currentToken()
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if p.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.namespace)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("falha ao consultar o Vault: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", fmt.Errorf("%w: %s", ErrNotFound, path)
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("vault retornou status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var payload struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return "", fmt.Errorf("resposta inválida do Vault: %w", err)
+	}
+
+	fields := payload.Data
+	if p.kvVersion == 2 {
+		// A KV v2 aninha os valores em data.data
+		nested, _ := payload.Data["data"].(map[string]interface{})
+		fields = nested
+	}
+	if fields == nil {
+		return "", fmt.Errorf("%w: %s", ErrNotFound, path)
+	}
+	return fieldString(fields, key)
+}
+
+// currentToken lê o token do arquivo a cada consulta, acompanhando renovações
+func (p *VaultProvider) currentToken() (string, error) {
+	if p.tokenFile == "" {
+		return p.token, nil
+	}
+	data, err := os.ReadFile(p.tokenFile)
+	if err != nil {
+		return "", fmt.Errorf("falha ao ler token do Vault: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
@@ -0,0 +1,275 @@
+package security
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+)
+
+// jwksMinRefreshInterval limita as atualizações provocadas por kid desconhecido,
+// evitando que tokens forjados façam o gateway consultar o provedor a cada requisição
+const jwksMinRefreshInterval = 30 * time.Second
+
+// ErrUnknownKey indica que nenhuma chave do JWKS corresponde ao kid do token
+var ErrUnknownKey = errors.New("chave de assinatura desconhecida")
+
+// jsonWebKey é uma chave pública no formato JWK (RFC 7517)
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// JWKS mantém em memória as chaves públicas publicadas por um provedor de
+// identidade, atualizando-as periodicamente e quando surge um kid desconhecido,
+// o que cobre a rotação de chaves sem reiniciar o gateway
+type JWKS struct {
+	url             string
+	refreshInterval time.Duration
+	client          *http.Client
+	logger          *zap.Logger
+
+	mu          sync.RWMutex
+	keys        map[string]interface{}
+	lastAttempt time.Time
+	refreshes   singleflight.Group
+}
+
+// NewJWKS cria o conjunto de chaves do endpoint informado. As chaves são
+// obtidas na primeira consulta ou em Refresh.
+func NewJWKS(url string, refreshInterval time.Duration, logger *zap.Logger) *JWKS {
+	if refreshInterval <= 0 {
+		refreshInterval = 15 * time.Minute
+	}
+	return &JWKS{
+		url:             url,
+		refreshInterval: refreshInterval,
+		client:          &http.Client{Timeout: 10 * time.Second},
+		logger:          logger,
+		keys:            make(map[string]interface{}),
+	}
+}
+
+// Start atualiza as chaves periodicamente até o contexto ser cancelado
+func (j *JWKS) Start(ctx context.Context) {
+	ticker := time.NewTicker(j.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := j.Refresh(ctx); err != nil && ctx.Err() == nil {
+				j.logger.Warn("Falha ao atualizar chaves JWKS; mantendo as anteriores",
+					zap.String("url", j.url), zap.Error(err))
+			}
+		}
+	}
+}
+
+// Refresh obtém as chaves do endpoint. Atualizações simultâneas são agrupadas
+// em uma única consulta, e em caso de falha as chaves anteriores são mantidas.
+func (j *JWKS) Refresh(ctx context.Context) error {
+	_, err, _ := j.refreshes.Do("refresh", func() (interface{}, error) {
+		j.mu.Lock()
+		j.lastAttempt = time.Now()
+		j.mu.Unlock()
+
+		keys, err := j.fetch(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		j.mu.Lock()
+		j.keys = keys
+		j.mu.Unlock()
+
+		j.logger.Debug("Chaves JWKS atualizadas", zap.String("url", j.url), zap.Int("keys", len(keys)))
+		return nil, nil
+	})
+	return err
+}
+
+// Key retorna a chave pública do kid. Um kid desconhecido provoca uma nova
+// consulta ao endpoint, respeitando o intervalo mínimo entre atualizações.
+func (j *JWKS) Key(ctx context.Context, kid string) (interface{}, error) {
+	j.mu.RLock()
+	key, ok := j.lookup(kid)
+	canRefresh := time.Since(j.lastAttempt) >= jwksMinRefreshInterval
+	j.mu.RUnlock()
+	if ok {
+		return key, nil
+	}
+	if !canRefresh {
+		return nil, ErrUnknownKey
+	}
+
+	if err := j.Refresh(ctx); err != nil {
+		return nil, fmt.Errorf("falha ao atualizar chaves JWKS: %w", err)
+	}
+
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	if key, ok := j.lookup(kid); ok {
+		return key, nil
+	}
+	return nil, ErrUnknownKey
+}
+
+// lookup busca a chave pelo kid. Tokens sem kid são aceitos apenas quando o
+// provedor publica uma única chave. Deve ser chamado com o lock de leitura.
+func (j *JWKS) lookup(kid string) (interface{}, bool) {
+	if kid == "" && len(j.keys) == 1 {
+		for _, key := range j.keys {
+			return key, true
+		}
+	}
+	key, ok := j.keys[kid]
+	return key, ok
+}
+
+// Keyfunc resolve a chave de verificação de um token RSA ou ECDSA pelo kid
+func (j *JWKS) Keyfunc(token *jwt.Token) (interface{}, error) {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS, *jwt.SigningMethodECDSA:
+	default:
+		return nil, fmt.Errorf("método de assinatura inesperado: %v", token.Header["alg"])
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, err := j.Key(context.Background(), kid)
+	if err != nil {
+		return nil, err
+	}
+
+	// A chave precisa ser do mesmo tipo do algoritmo declarado no token
+	switch key.(type) {
+	case *rsa.PublicKey:
+		if _, ok := token.Method.(*jwt.SigningMethodECDSA); ok {
+			return nil, errors.New("algoritmo do token incompatível com a chave")
+		}
+	case *ecdsa.PublicKey:
+		if _, ok := token.Method.(*jwt.SigningMethodECDSA); !ok {
+			return nil, errors.New("algoritmo do token incompatível com a chave")
+		}
+	}
+	return key, nil
+}
+
+// fetch consulta o endpoint e converte as chaves de assinatura suportadas
+func (j *JWKS) fetch(ctx context.Context) (map[string]interface{}, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	res, err := j.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("endpoint JWKS respondeu %d", res.StatusCode)
+	}
+
+	var document struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&document); err != nil {
+		return nil, fmt.Errorf("documento JWKS inválido: %w", err)
+	}
+
+	keys := make(map[string]interface{}, len(document.Keys))
+	for _, jwk := range document.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			j.logger.Warn("Chave JWKS ignorada", zap.String("kid", jwk.Kid), zap.Error(err))
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("nenhuma chave de assinatura suportada no JWKS")
+	}
+	return keys, nil
+}
+
+// publicKey converte a JWK em chave pública RSA ou ECDSA
+func (k jsonWebKey) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("módulo RSA inválido: %w", err)
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil || !e.IsInt64() {
+			return nil, errors.New("expoente RSA inválido")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("curva não suportada: %s", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("coordenada x inválida: %w", err)
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("coordenada y inválida: %w", err)
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("ponto fora da curva")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+
+	default:
+		return nil, fmt.Errorf("tipo de chave não suportado: %s", k.Kty)
+	}
+}
+
+// decodeBigInt decodifica um inteiro em base64url sem padding
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, errors.New("valor vazio")
+	}
+	return new(big.Int).SetBytes(data), nil
+}
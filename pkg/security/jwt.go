@@ -1,29 +1,62 @@
 package security
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"os"
+	"sync"
 	"time"
 
+	"github.com/diillson/api-gateway-go/pkg/config"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
 type Claims struct {
-	UserID string `json:"user_id"`
-	Role   string `json:"role"`
+	UserID            string `json:"user_id"`
+	Role              string `json:"role"`
+	PreferredUsername string `json:"preferred_username,omitempty"`
+	Email             string `json:"email,omitempty"`
+	Tenant            string `json:"tenant,omitempty"`
 	jwt.RegisteredClaims
+
+	// External indica que o token foi emitido por um provedor de identidade
+	// externo e verificado pelo JWKS, e não pelo segredo do gateway
+	External bool `json:"-"`
+
+	// Raw contém todas as claims do token, inclusive as não mapeadas acima
+	// (scope, roles e claims próprias do emissor)
+	Raw map[string]interface{} `json:"-"`
 }
 
+// TokenLifetime é a validade dos tokens emitidos pelo gateway no login
+const TokenLifetime = 24 * time.Hour
+
 type KeyManager struct {
-	secretKey []byte
-	logger    *zap.Logger
+	logger *zap.Logger
+	jwks   *JWKS
+
+	// Trocados nas recargas da configuração
+	mu          sync.RWMutex
+	secretKey   []byte
+	previousKey []byte // segredo anterior ao último auth.jwtSecret recarregado
+	issuer      string
+	audience    []string
+
+	// maxExternalLifetime é a maior validade (exp - iat) aceita nos tokens do
+	// JWKS; zero não limita
+	maxExternalLifetime time.Duration
 }
 
 func NewKeyManager(logger *zap.Logger) (*KeyManager, error) {
 	// Buscando o secret do config - mesmo valor usado no seu generate_token.go
 	secretKey := GetJWTSecret()
 
+	if len(secretKey) == 0 {
+		return nil, errors.New("segredo JWT não configurado")
+	}
 	if len(secretKey) < 32 {
 		return nil, errors.New("jwt secret key muito curta")
 	}
@@ -34,6 +67,53 @@ func NewKeyManager(logger *zap.Logger) (*KeyManager, error) {
 	}, nil
 }
 
+// SetJWKS habilita tokens RS256/ES256 de um provedor de identidade externo,
+// verificados pelas chaves do JWKS. Com issuer ou audience informados, as claims
+// iss e aud desses tokens precisam corresponder.
+func (km *KeyManager) SetJWKS(jwks *JWKS, issuer string, audience []string) {
+	km.jwks = jwks
+	km.mu.Lock()
+	km.issuer = issuer
+	km.audience = audience
+	km.mu.Unlock()
+}
+
+// SetMaxExternalLifetime limita a validade dos tokens do JWKS. Tokens sem iat
+// ou exp, ou com exp além de iat + maxLifetime, são recusados, para que as
+// revogações por subject durem mais que qualquer token aceito.
+func (km *KeyManager) SetMaxExternalLifetime(maxLifetime time.Duration) {
+	km.mu.Lock()
+	km.maxExternalLifetime = maxLifetime
+	km.mu.Unlock()
+}
+
+// Reload aplica as configurações de autenticação recarregadas: o emissor e a
+// audiência exigidos dos tokens do JWKS e auth.jwtSecret, quando o segredo não
+// vem do provedor de segredos nem das variáveis de ambiente. Após a troca do
+// segredo, tokens assinados com o anterior continuam aceitos até expirarem.
+func (km *KeyManager) Reload(cfg config.AuthConfig) error {
+	km.mu.Lock()
+	defer km.mu.Unlock()
+
+	km.issuer = cfg.JWKS.Issuer
+	km.audience = cfg.JWKS.Audience
+
+	if jwtSecretSource.Load() != nil || os.Getenv("JWT_SECRET_KEY") != "" || os.Getenv("AG_AUTH_JWT_SECRET_KEY") != "" {
+		return nil
+	}
+	secret := []byte(cfg.JWTSecret)
+	if len(secret) == 0 || bytes.Equal(secret, km.secretKey) {
+		return nil
+	}
+	if len(secret) < 32 {
+		return errors.New("jwt secret key muito curta")
+	}
+	km.previousKey = km.secretKey
+	km.secretKey = secret
+	km.logger.Info("Segredo JWT trocado pela configuração recarregada")
+	return nil
+}
+
 func (km *KeyManager) GenerateToken(userID, role string, duration time.Duration) (string, error) {
 	expireTime := time.Now().Add(duration)
 
@@ -41,6 +121,7 @@ func (km *KeyManager) GenerateToken(userID, role string, duration time.Duration)
 		UserID: userID,
 		Role:   role,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
 			ExpiresAt: jwt.NewNumericDate(expireTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
@@ -49,7 +130,7 @@ func (km *KeyManager) GenerateToken(userID, role string, duration time.Duration)
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
-	tokenString, err := token.SignedString(km.secretKey)
+	tokenString, err := token.SignedString(km.currentSecret())
 	if err != nil {
 		km.logger.Error("falha ao gerar token JWT", zap.Error(err))
 		return "", err
@@ -60,11 +141,20 @@ func (km *KeyManager) GenerateToken(userID, role string, duration time.Duration)
 
 func (km *KeyManager) VerifyToken(tokenString string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
+		// Tokens assimétricos vêm do provedor de identidade externo
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok && km.jwks != nil {
+			return km.jwks.Keyfunc(token)
+		}
+
 		// Verificar o método de assinatura
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("método de assinatura inesperado: %v", token.Header["alg"])
 		}
-		return km.secretKey, nil
+		// Após uma rotação, tokens assinados com o segredo anterior seguem válidos
+		if previous := km.previousSecret(); previous != nil {
+			return jwt.VerificationKeySet{Keys: []jwt.VerificationKey{km.currentSecret(), previous}}, nil
+		}
+		return km.currentSecret(), nil
 	})
 
 	if err != nil {
@@ -76,8 +166,68 @@ func (km *KeyManager) VerifyToken(tokenString string) (*Claims, error) {
 	}
 
 	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
+		if _, hmac := token.Method.(*jwt.SigningMethodHMAC); !hmac {
+			if err := km.validateExternal(claims); err != nil {
+				return nil, err
+			}
+			claims.External = true
+		}
+
+		// A assinatura já foi verificada; aqui apenas as claims são lidas
+		raw := jwt.MapClaims{}
+		if _, _, err := jwt.NewParser().ParseUnverified(tokenString, raw); err == nil {
+			claims.Raw = raw
+		}
 		return claims, nil
 	}
 
 	return nil, errors.New("token inválido")
 }
+
+// currentSecret retorna o segredo JWT vigente, que o provedor de segredos pode
+// ter rotacionado desde a criação do KeyManager
+func (km *KeyManager) currentSecret() []byte {
+	if jwtSecretSource.Load() != nil {
+		return GetJWTSecret()
+	}
+	km.mu.RLock()
+	defer km.mu.RUnlock()
+	return km.secretKey
+}
+
+// previousSecret retorna o segredo anterior à última rotação, do provedor de
+// segredos ou da configuração recarregada; nil sem rotação
+func (km *KeyManager) previousSecret() []byte {
+	if previous := previousJWTSecret(); previous != nil {
+		return previous
+	}
+	km.mu.RLock()
+	defer km.mu.RUnlock()
+	return km.previousKey
+}
+
+// validateExternal confere o emissor e a audiência de um token externo
+func (km *KeyManager) validateExternal(claims *Claims) error {
+	km.mu.RLock()
+	issuer, audience, maxLifetime := km.issuer, km.audience, km.maxExternalLifetime
+	km.mu.RUnlock()
+
+	if issuer != "" && claims.Issuer != issuer {
+		return fmt.Errorf("emissor do token não aceito: %q", claims.Issuer)
+	}
+	if claims.Subject == "" {
+		return errors.New("token sem claim sub")
+	}
+	if len(audience) > 0 && !containsAny(claims.Audience, audience) {
+		return errors.New("audiência do token não aceita")
+	}
+	if maxLifetime > 0 {
+		if claims.IssuedAt == nil || claims.ExpiresAt == nil {
+			return errors.New("token sem claims iat e exp")
+		}
+		if claims.ExpiresAt.Sub(claims.IssuedAt.Time) > maxLifetime {
+			return fmt.Errorf("validade do token acima do máximo aceito de %s", maxLifetime)
+		}
+	}
+	return nil
+}
@@ -3,15 +3,36 @@ package security
 import (
 	"fmt"
 	"github.com/diillson/api-gateway-go/pkg/config"
+	"github.com/diillson/api-gateway-go/pkg/secrets"
 	"os"
+	"sync/atomic"
 )
 
+// insecureJWTSecret é usado apenas com security.secrets.allowInsecureFallback,
+// em desenvolvimento
+const insecureJWTSecret = "desenvolvimento_inseguro_nao_use_em_producao"
+
+// jwtSecretSource é o segredo JWT obtido do provedor de segredos configurado
+var jwtSecretSource atomic.Pointer[secrets.Secret]
+
+// SetJWTSecretSource passa a obter o segredo JWT do provedor de segredos, que
+// o mantém atualizado. Após uma rotação, tokens assinados com o segredo
+// anterior continuam sendo aceitos até expirarem.
+func SetJWTSecretSource(secret *secrets.Secret) {
+	jwtSecretSource.Store(secret)
+}
+
 // GetJWTSecret obtém o segredo JWT de diferentes fontes na seguinte ordem:
-// 1. Variável de ambiente JWT_SECRET_KEY
-// 2. Arquivo de configuração
-// 3. Fallback para valor padrão (apenas em desenvolvimento)
-// Modificar o retorno para usar um valor padrão
+// 1. Provedor de segredos configurado (security.secrets.jwtSecret)
+// 2. Variáveis de ambiente JWT_SECRET_KEY e AG_AUTH_JWT_SECRET_KEY
+// 3. Arquivo de configuração (auth.jwtSecret)
+// 4. Valor fixo de desenvolvimento, apenas com security.secrets.allowInsecureFallback
+// Sem nenhuma fonte, retorna nil.
 func GetJWTSecret() []byte {
+	if source := jwtSecretSource.Load(); source != nil {
+		return []byte(source.Value())
+	}
+
 	// Primeiro, tentar obter da variável de ambiente
 	secret := os.Getenv("JWT_SECRET_KEY")
 	if secret != "" {
@@ -30,9 +51,24 @@ func GetJWTSecret() []byte {
 		return []byte(cfg.Auth.JWTSecret)
 	}
 
-	// Fallback para o valor padrão (apenas para desenvolvimento)
-	// Em ambientes de produção, isso não deve ser usado
-	fallbackKey := "desenvolvimento_inseguro_nao_use_em_producao"
-	fmt.Println("AVISO: Usando chave JWT de fallback! Isso é inseguro para produção.")
-	return []byte(fallbackKey)
+	// Fallback para o valor padrão, apenas com opt-in explícito
+	if err == nil && cfg.Security.Secrets.AllowInsecureFallback {
+		fmt.Println("AVISO: Usando chave JWT de fallback! Isso é inseguro para produção.")
+		return []byte(insecureJWTSecret)
+	}
+
+	fmt.Println("ERRO: segredo JWT não configurado. Defina JWT_SECRET_KEY, auth.jwtSecret ou security.secrets.jwtSecret " +
+		"(em desenvolvimento, security.secrets.allowInsecureFallback habilita um valor fixo)")
+	return nil
+}
+
+// previousJWTSecret retorna o segredo anterior à última rotação, aceito na
+// verificação de tokens já emitidos; nil sem rotação
+func previousJWTSecret() []byte {
+	if source := jwtSecretSource.Load(); source != nil {
+		if previous := source.Previous(); previous != "" {
+			return []byte(previous)
+		}
+	}
+	return nil
 }
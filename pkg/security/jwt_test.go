@@ -0,0 +1,52 @@
+package security
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+)
+
+func TestValidateExternalLifetime(t *testing.T) {
+	km := &KeyManager{logger: zap.NewNop()}
+	km.SetMaxExternalLifetime(24 * time.Hour)
+
+	now := time.Now()
+	claims := func(issuedAt, expiresAt time.Time) *Claims {
+		c := &Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "parceiro"}}
+		if !issuedAt.IsZero() {
+			c.IssuedAt = jwt.NewNumericDate(issuedAt)
+		}
+		if !expiresAt.IsZero() {
+			c.ExpiresAt = jwt.NewNumericDate(expiresAt)
+		}
+		return c
+	}
+
+	tests := []struct {
+		name    string
+		claims  *Claims
+		wantErr bool
+	}{
+		{"dentro do limite", claims(now, now.Add(time.Hour)), false},
+		{"no limite", claims(now, now.Add(24*time.Hour)), false},
+		{"acima do limite", claims(now, now.Add(25*time.Hour)), true},
+		{"sem iat", claims(time.Time{}, now.Add(time.Hour)), true},
+		{"sem exp", claims(now, time.Time{}), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := km.validateExternal(tt.claims)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateExternal = %v, esperado erro: %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Sem limite configurado, a validade não é verificada
+	km.SetMaxExternalLifetime(0)
+	if err := km.validateExternal(claims(time.Time{}, time.Time{})); err != nil {
+		t.Fatalf("validateExternal sem limite = %v", err)
+	}
+}
@@ -0,0 +1,267 @@
+package security
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/diillson/api-gateway-go/pkg/config"
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+)
+
+// oidcDiscoveryRetryInterval evita consultar a cada requisição um provedor cuja
+// descoberta falhou
+const oidcDiscoveryRetryInterval = 30 * time.Second
+
+// asymmetricMethods são os algoritmos aceitos em tokens de provedores OIDC
+var asymmetricMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+// oidcDiscovery contém os campos usados do documento de descoberta OIDC
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	JWKSURI               string `json:"jwks_uri"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+}
+
+// OIDCTokens são os tokens obtidos na troca do authorization code
+type OIDCTokens struct {
+	AccessToken string `json:"access_token"`
+	IDToken     string `json:"id_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// OIDCProvider valida tokens de um provedor OpenID Connect e conduz o login
+// por authorization code. O documento de descoberta é obtido na primeira
+// utilização e mantido em memória.
+type OIDCProvider struct {
+	cfg    config.OIDCProviderConfig
+	client *http.Client
+	logger *zap.Logger
+
+	mu          sync.Mutex
+	discovery   *oidcDiscovery
+	jwks        *JWKS
+	lastAttempt time.Time
+	lastErr     error
+}
+
+// NewOIDCProvider cria o provedor a partir da configuração
+func NewOIDCProvider(cfg config.OIDCProviderConfig, logger *zap.Logger) *OIDCProvider {
+	return &OIDCProvider{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 10 * time.Second},
+		logger: logger.With(zap.String("oidcProvider", cfg.Name)),
+	}
+}
+
+// Name retorna o nome do provedor
+func (p *OIDCProvider) Name() string {
+	return p.cfg.Name
+}
+
+// SupportsLogin indica se o provedor tem credenciais para o login de navegadores
+func (p *OIDCProvider) SupportsLogin() bool {
+	return p.cfg.ClientID != "" && p.cfg.RedirectURL != ""
+}
+
+// discover obtém o documento de descoberta, reaproveitando o resultado
+func (p *OIDCProvider) discover(ctx context.Context) (*oidcDiscovery, *JWKS, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, p.jwks, nil
+	}
+	if p.lastErr != nil && time.Since(p.lastAttempt) < oidcDiscoveryRetryInterval {
+		return nil, nil, p.lastErr
+	}
+	p.lastAttempt = time.Now()
+
+	discovery, err := p.fetchDiscovery(ctx)
+	if err != nil {
+		p.lastErr = fmt.Errorf("falha na descoberta OIDC de %s: %w", p.cfg.Issuer, err)
+		p.logger.Warn("Falha na descoberta OIDC", zap.Error(err))
+		return nil, nil, p.lastErr
+	}
+
+	p.discovery = discovery
+	p.jwks = NewJWKS(discovery.JWKSURI, 0, p.logger)
+	p.lastErr = nil
+	p.logger.Info("Descoberta OIDC concluída",
+		zap.String("issuer", discovery.Issuer),
+		zap.String("jwksURI", discovery.JWKSURI))
+	return p.discovery, p.jwks, nil
+}
+
+// fetchDiscovery consulta o documento de descoberta do emissor
+func (p *OIDCProvider) fetchDiscovery(ctx context.Context) (*oidcDiscovery, error) {
+	endpoint := strings.TrimSuffix(p.cfg.Issuer, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("documento de descoberta respondeu %d", res.StatusCode)
+	}
+
+	var discovery oidcDiscovery
+	if err := json.NewDecoder(res.Body).Decode(&discovery); err != nil {
+		return nil, fmt.Errorf("documento de descoberta inválido: %w", err)
+	}
+	// O emissor anunciado precisa ser o configurado (OIDC Discovery, seção 4.3)
+	if strings.TrimSuffix(discovery.Issuer, "/") != strings.TrimSuffix(p.cfg.Issuer, "/") {
+		return nil, fmt.Errorf("emissor do documento de descoberta diverge: %q", discovery.Issuer)
+	}
+	if discovery.JWKSURI == "" {
+		return nil, errors.New("documento de descoberta sem jwks_uri")
+	}
+	return &discovery, nil
+}
+
+// VerifyAccessToken valida um access token JWT: assinatura pelo JWKS do
+// provedor, emissor, expiração e, quando configurada, a audiência
+func (p *OIDCProvider) VerifyAccessToken(ctx context.Context, tokenString string) (jwt.MapClaims, error) {
+	return p.verify(ctx, tokenString, p.cfg.Audience)
+}
+
+// VerifyIDToken valida o ID token recebido no login, que deve ser destinado ao
+// cliente do gateway e carregar o nonce da autenticação
+func (p *OIDCProvider) VerifyIDToken(ctx context.Context, tokenString, nonce string) (jwt.MapClaims, error) {
+	claims, err := p.verify(ctx, tokenString, []string{p.cfg.ClientID})
+	if err != nil {
+		return nil, err
+	}
+	if value, _ := claims["nonce"].(string); value != nonce {
+		return nil, errors.New("nonce do ID token inválido")
+	}
+	return claims, nil
+}
+
+// verify valida o token e a audiência informada
+func (p *OIDCProvider) verify(ctx context.Context, tokenString string, audience []string) (jwt.MapClaims, error) {
+	discovery, jwks, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(tokenString, claims, jwks.Keyfunc,
+		jwt.WithValidMethods(asymmetricMethods),
+		jwt.WithIssuer(discovery.Issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(audience) > 0 {
+		tokenAudience, err := claims.GetAudience()
+		if err != nil {
+			return nil, err
+		}
+		if !containsAny(tokenAudience, audience) {
+			return nil, errors.New("audiência do token não aceita")
+		}
+	}
+	return claims, nil
+}
+
+// AuthCodeURL monta a URL de login no provedor
+func (p *OIDCProvider) AuthCodeURL(ctx context.Context, state, nonce string) (string, error) {
+	discovery, _, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	if discovery.AuthorizationEndpoint == "" {
+		return "", errors.New("provedor OIDC sem authorization_endpoint")
+	}
+
+	scopes := []string{"openid"}
+	for _, scope := range p.cfg.Scopes {
+		if scope != "openid" {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	query := url.Values{
+		"response_type": {"code"},
+		"client_id":     {p.cfg.ClientID},
+		"redirect_uri":  {p.cfg.RedirectURL},
+		"scope":         {strings.Join(scopes, " ")},
+		"state":         {state},
+		"nonce":         {nonce},
+	}
+	separator := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return discovery.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+// Exchange troca o authorization code pelos tokens no endpoint de token
+func (p *OIDCProvider) Exchange(ctx context.Context, code string) (*OIDCTokens, error) {
+	discovery, _, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {code},
+		"redirect_uri": {p.cfg.RedirectURL},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, discovery.TokenEndpoint,
+		strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao trocar authorization code: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("endpoint de token respondeu %d", res.StatusCode)
+	}
+
+	var tokens OIDCTokens
+	if err := json.NewDecoder(res.Body).Decode(&tokens); err != nil {
+		return nil, fmt.Errorf("resposta do endpoint de token inválida: %w", err)
+	}
+	if tokens.IDToken == "" {
+		return nil, errors.New("resposta do endpoint de token sem id_token")
+	}
+	return &tokens, nil
+}
+
+// containsAny indica se algum dos valores esperados está na lista
+func containsAny(values, expected []string) bool {
+	for _, want := range expected {
+		for _, value := range values {
+			if value == want {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package telemetry
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"go.uber.org/zap"
+)
+
+// MeterProvider exporta as métricas OpenTelemetry via OTLP
+type MeterProvider struct {
+	provider *sdkmetric.MeterProvider
+	logger   *zap.Logger
+}
+
+// NewMeterProvider inicializa a exportação periódica das métricas para o
+// coletor OTLP (gRPC) e registra o provider global. Os instrumentos criados
+// antes da chamada passam a exportar a partir dela.
+func NewMeterProvider(ctx context.Context, serviceName, endpoint string, interval time.Duration, logger *zap.Logger) (*MeterProvider, error) {
+	res, err := resource.New(ctx,
+		resource.WithAttributes(
+			semconv.ServiceNameKey.String(serviceName),
+			attribute.String("environment", getEnvironment()),
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	exporter, err := otlpmetricgrpc.New(ctx,
+		otlpmetricgrpc.WithEndpoint(endpoint),
+		otlpmetricgrpc.WithInsecure(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter, sdkmetric.WithInterval(interval))),
+		sdkmetric.WithResource(res),
+	)
+	otel.SetMeterProvider(provider)
+
+	logger.Info("Exportação de métricas OpenTelemetry configurada",
+		zap.String("endpoint", endpoint),
+		zap.Duration("interval", interval))
+
+	return &MeterProvider{provider: provider, logger: logger}, nil
+}
+
+// Shutdown envia as métricas pendentes e encerra o provider
+func (mp *MeterProvider) Shutdown(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err := mp.provider.Shutdown(ctx); err != nil {
+		mp.logger.Error("falha ao encerrar meter provider", zap.Error(err))
+	}
+}
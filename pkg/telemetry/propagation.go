@@ -0,0 +1,206 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Formatos de propagação do contexto de tracing aceitos em tracing.propagators
+const (
+	PropagatorTraceContext = "tracecontext"
+	PropagatorBaggage      = "baggage"
+	PropagatorB3           = "b3"      // cabeçalho único b3
+	PropagatorB3Multi      = "b3multi" // cabeçalhos X-B3-*
+	PropagatorJaeger       = "jaeger"  // cabeçalho uber-trace-id
+)
+
+// DefaultPropagators são os formatos usados sem configuração
+var DefaultPropagators = []string{PropagatorTraceContext, PropagatorBaggage}
+
+// NewPropagator compõe os formatos informados. Na extração, o último formato
+// presente na requisição prevalece; na injeção, todos os formatos são
+// escritos na requisição ao backend.
+func NewPropagator(names []string) (propagation.TextMapPropagator, error) {
+	if len(names) == 0 {
+		names = DefaultPropagators
+	}
+	propagators := make([]propagation.TextMapPropagator, 0, len(names))
+	for _, name := range names {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case PropagatorTraceContext:
+			propagators = append(propagators, propagation.TraceContext{})
+		case PropagatorBaggage:
+			propagators = append(propagators, propagation.Baggage{})
+		case PropagatorB3:
+			propagators = append(propagators, b3Propagator{singleHeader: true})
+		case PropagatorB3Multi:
+			propagators = append(propagators, b3Propagator{})
+		case PropagatorJaeger:
+			propagators = append(propagators, jaegerPropagator{})
+		default:
+			return nil, fmt.Errorf("formato de propagação desconhecido: %q", name)
+		}
+	}
+	return propagation.NewCompositeTextMapPropagator(propagators...), nil
+}
+
+// SetPropagators configura globalmente os formatos de propagação, usados na
+// extração das requisições recebidas e na injeção das enviadas aos backends
+func SetPropagators(names []string) error {
+	propagator, err := NewPropagator(names)
+	if err != nil {
+		return err
+	}
+	otel.SetTextMapPropagator(propagator)
+	return nil
+}
+
+// Cabeçalhos do B3
+const (
+	b3Header        = "b3"
+	b3TraceIDHeader = "x-b3-traceid"
+	b3SpanIDHeader  = "x-b3-spanid"
+	b3SampledHeader = "x-b3-sampled"
+	b3FlagsHeader   = "x-b3-flags"
+)
+
+// b3Propagator implementa o formato B3 do Zipkin, com cabeçalho único ou
+// múltiplo na injeção; a extração aceita os dois
+type b3Propagator struct {
+	singleHeader bool
+}
+
+func (p b3Propagator) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return
+	}
+	sampled := "0"
+	if sc.IsSampled() {
+		sampled = "1"
+	}
+	if p.singleHeader {
+		carrier.Set(b3Header, sc.TraceID().String()+"-"+sc.SpanID().String()+"-"+sampled)
+		return
+	}
+	carrier.Set(b3TraceIDHeader, sc.TraceID().String())
+	carrier.Set(b3SpanIDHeader, sc.SpanID().String())
+	carrier.Set(b3SampledHeader, sampled)
+}
+
+func (p b3Propagator) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	if value := carrier.Get(b3Header); value != "" {
+		parts := strings.Split(value, "-")
+		if len(parts) < 2 {
+			return ctx
+		}
+		sampled := ""
+		if len(parts) > 2 {
+			sampled = parts[2]
+		}
+		return extractB3(ctx, parts[0], parts[1], sampled)
+	}
+
+	sampled := carrier.Get(b3SampledHeader)
+	if carrier.Get(b3FlagsHeader) == "1" {
+		sampled = "d"
+	}
+	return extractB3(ctx, carrier.Get(b3TraceIDHeader), carrier.Get(b3SpanIDHeader), sampled)
+}
+
+func (p b3Propagator) Fields() []string {
+	if p.singleHeader {
+		return []string{b3Header}
+	}
+	return []string{b3TraceIDHeader, b3SpanIDHeader, b3SampledHeader, b3FlagsHeader}
+}
+
+// extractB3 interpreta os identificadores do B3; o trace ID de 64 bits é
+// completado com zeros à esquerda
+func extractB3(ctx context.Context, traceID, spanID, sampled string) context.Context {
+	if len(traceID) != 16 && len(traceID) != 32 {
+		return ctx
+	}
+	sc, ok := remoteSpanContext(traceID, spanID, sampled == "1" || sampled == "d" || sampled == "true")
+	if !ok {
+		return ctx
+	}
+	return trace.ContextWithRemoteSpanContext(ctx, sc)
+}
+
+// jaegerHeader é o cabeçalho do formato nativo do Jaeger
+const jaegerHeader = "uber-trace-id"
+
+// jaegerPropagator implementa o formato {trace-id}:{span-id}:{parent-id}:{flags}
+type jaegerPropagator struct{}
+
+func (jaegerPropagator) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return
+	}
+	flags := "0"
+	if sc.IsSampled() {
+		flags = "1"
+	}
+	carrier.Set(jaegerHeader, sc.TraceID().String()+":"+sc.SpanID().String()+":0:"+flags)
+}
+
+func (jaegerPropagator) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	parts := strings.Split(carrier.Get(jaegerHeader), ":")
+	if len(parts) != 4 || len(parts[0]) == 0 || len(parts[0]) > 32 || len(parts[1]) == 0 || len(parts[1]) > 16 {
+		return ctx
+	}
+	flags, err := strconv.ParseUint(parts[3], 16, 8)
+	if err != nil {
+		return ctx
+	}
+	sc, ok := remoteSpanContext(parts[0], parts[1], flags&1 == 1)
+	if !ok {
+		return ctx
+	}
+	return trace.ContextWithRemoteSpanContext(ctx, sc)
+}
+
+func (jaegerPropagator) Fields() []string {
+	return []string{jaegerHeader}
+}
+
+// remoteSpanContext monta o contexto remoto a partir dos identificadores em
+// hexadecimal, completando-os com zeros à esquerda
+func remoteSpanContext(traceID, spanID string, sampled bool) (trace.SpanContext, bool) {
+	var tid trace.TraceID
+	var sid trace.SpanID
+	if !decodeHex(tid[:], traceID) || !decodeHex(sid[:], spanID) {
+		return trace.SpanContext{}, false
+	}
+
+	var flags trace.TraceFlags
+	if sampled {
+		flags = trace.FlagsSampled
+	}
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    tid,
+		SpanID:     sid,
+		TraceFlags: flags,
+		Remote:     true,
+	})
+	return sc, sc.IsValid()
+}
+
+// decodeHex decodifica o valor alinhado à direita no destino
+func decodeHex(dst []byte, value string) bool {
+	if len(value) > len(dst)*2 {
+		return false
+	}
+	value = strings.Repeat("0", len(dst)*2-len(value)) + strings.ToLower(value)
+	_, err := hex.Decode(dst, []byte(value))
+	return err == nil
+}
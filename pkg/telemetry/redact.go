@@ -0,0 +1,144 @@
+package telemetry
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/diillson/api-gateway-go/pkg/config"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// redactedValue substitui os valores mascarados
+const redactedValue = "[REDACTED]"
+
+// redactingExporter mascara os atributos configurados dos spans e dos seus
+// eventos antes de entregá-los ao exportador
+type redactingExporter struct {
+	sdktrace.SpanExporter
+	keys     map[string]bool
+	prefixes []string
+	patterns []*regexp.Regexp
+}
+
+// newRedactingExporter envolve o exportador com as regras de mascaramento.
+// Sem regras, o exportador é retornado sem alteração.
+func newRedactingExporter(exporter sdktrace.SpanExporter, cfg config.TracingRedactConfig) (sdktrace.SpanExporter, error) {
+	if len(cfg.Attributes) == 0 && len(cfg.Patterns) == 0 {
+		return exporter, nil
+	}
+
+	r := &redactingExporter{SpanExporter: exporter, keys: make(map[string]bool)}
+	for _, key := range cfg.Attributes {
+		key = strings.ToLower(key)
+		if prefix, ok := strings.CutSuffix(key, "*"); ok {
+			r.prefixes = append(r.prefixes, prefix)
+			continue
+		}
+		r.keys[key] = true
+	}
+	for _, pattern := range cfg.Patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return r, nil
+}
+
+// ExportSpans implementa sdktrace.SpanExporter
+func (r *redactingExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	redacted := make([]sdktrace.ReadOnlySpan, len(spans))
+	for i, span := range spans {
+		redacted[i] = r.redactSpan(span)
+	}
+	return r.SpanExporter.ExportSpans(ctx, redacted)
+}
+
+// redactSpan retorna o span com os atributos mascarados, ou o próprio span
+// quando nenhum atributo foi alterado
+func (r *redactingExporter) redactSpan(span sdktrace.ReadOnlySpan) sdktrace.ReadOnlySpan {
+	attrs, changed := r.redact(span.Attributes())
+
+	events := span.Events()
+	var redactedEvents []sdktrace.Event
+	for i, event := range events {
+		eventAttrs, eventChanged := r.redact(event.Attributes)
+		if !eventChanged {
+			continue
+		}
+		if redactedEvents == nil {
+			redactedEvents = append([]sdktrace.Event(nil), events...)
+		}
+		redactedEvents[i].Attributes = eventAttrs
+	}
+
+	if !changed && redactedEvents == nil {
+		return span
+	}
+	if redactedEvents == nil {
+		redactedEvents = events
+	}
+	return &redactedSpan{ReadOnlySpan: span, attrs: attrs, events: redactedEvents}
+}
+
+// redact mascara os atributos pelas chaves e pelos padrões configurados
+func (r *redactingExporter) redact(attrs []attribute.KeyValue) ([]attribute.KeyValue, bool) {
+	var result []attribute.KeyValue
+	for i, attr := range attrs {
+		value, changed := r.redactValue(attr)
+		if !changed {
+			continue
+		}
+		if result == nil {
+			result = append([]attribute.KeyValue(nil), attrs...)
+		}
+		result[i] = attribute.String(string(attr.Key), value)
+	}
+	if result == nil {
+		return attrs, false
+	}
+	return result, true
+}
+
+// redactValue retorna o valor mascarado do atributo e se houve alteração
+func (r *redactingExporter) redactValue(attr attribute.KeyValue) (string, bool) {
+	key := strings.ToLower(string(attr.Key))
+	if r.keys[key] {
+		return redactedValue, true
+	}
+	for _, prefix := range r.prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return redactedValue, true
+		}
+	}
+
+	if attr.Value.Type() != attribute.STRING || len(r.patterns) == 0 {
+		return "", false
+	}
+	value := attr.Value.AsString()
+	masked := value
+	for _, re := range r.patterns {
+		masked = re.ReplaceAllString(masked, redactedValue)
+	}
+	return masked, masked != value
+}
+
+// redactedSpan é um span com os atributos e eventos mascarados
+type redactedSpan struct {
+	sdktrace.ReadOnlySpan
+	attrs  []attribute.KeyValue
+	events []sdktrace.Event
+}
+
+// Attributes implementa sdktrace.ReadOnlySpan
+func (s *redactedSpan) Attributes() []attribute.KeyValue {
+	return s.attrs
+}
+
+// Events implementa sdktrace.ReadOnlySpan
+func (s *redactedSpan) Events() []sdktrace.Event {
+	return s.events
+}
@@ -0,0 +1,76 @@
+package telemetry
+
+import (
+	"fmt"
+
+	"github.com/diillson/api-gateway-go/internal/domain/model"
+	"github.com/diillson/api-gateway-go/pkg/config"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// PathAttribute é o atributo com o caminho da requisição, informado na criação
+// do span do servidor para que a amostragem por rota possa avaliá-lo
+const PathAttribute = "url.path"
+
+// routeRule é a amostragem específica de uma rota
+type routeRule struct {
+	path    string
+	sampler sdktrace.Sampler
+}
+
+// routeSampler decide a amostragem pela primeira rota configurada cujo
+// caminho corresponde ao da requisição, ou pelo amostrador padrão
+type routeSampler struct {
+	routes   []routeRule
+	fallback sdktrace.Sampler
+}
+
+// ShouldSample implementa sdktrace.Sampler
+func (s *routeSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	for _, attr := range p.Attributes {
+		if attr.Key != PathAttribute {
+			continue
+		}
+		path := attr.Value.AsString()
+		for _, route := range s.routes {
+			if model.MatchRoutePath(route.path, path) {
+				return route.sampler.ShouldSample(p)
+			}
+		}
+		break
+	}
+	return s.fallback.ShouldSample(p)
+}
+
+// Description implementa sdktrace.Sampler
+func (s *routeSampler) Description() string {
+	return fmt.Sprintf("RouteSampler{routes=%d,fallback=%s}", len(s.routes), s.fallback.Description())
+}
+
+// newSampler monta o amostrador configurado. Com parentbased_ratio (padrão),
+// a decisão do trace recebido do cliente é mantida e as regras valem apenas
+// para os traces iniciados no gateway.
+func newSampler(cfg config.TracingConfig) sdktrace.Sampler {
+	var root sdktrace.Sampler
+	switch cfg.Sampler {
+	case "always_on":
+		root = sdktrace.AlwaysSample()
+	case "always_off":
+		root = sdktrace.NeverSample()
+	default:
+		root = sdktrace.TraceIDRatioBased(cfg.SamplingRatio)
+	}
+
+	if len(cfg.Routes) > 0 {
+		routes := make([]routeRule, 0, len(cfg.Routes))
+		for _, route := range cfg.Routes {
+			routes = append(routes, routeRule{path: route.Path, sampler: sdktrace.TraceIDRatioBased(route.SamplingRatio)})
+		}
+		root = &routeSampler{routes: routes, fallback: root}
+	}
+
+	if cfg.Sampler == "" || cfg.Sampler == "parentbased_ratio" {
+		return sdktrace.ParentBased(root)
+	}
+	return root
+}
@@ -2,6 +2,8 @@ package telemetry
 
 import (
 	"context"
+	"crypto/tls"
+	"fmt"
 	"os"
 	"time"
 
@@ -9,13 +11,13 @@ import (
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
-	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
@@ -26,46 +28,47 @@ type TracerProvider struct {
 }
 
 // NewTracerProvider inicializa e configura o OpenTelemetry
-func NewTracerProvider(ctx context.Context, serviceName, collectorURL string, logger *zap.Logger) (*TracerProvider, error) {
-	// Criar recurso com atributos do serviço
+func NewTracerProvider(ctx context.Context, cfg config.TracingConfig, logger *zap.Logger) (*TracerProvider, error) {
+	// Criar recurso com atributos do serviço, os configurados e os de
+	// OTEL_RESOURCE_ATTRIBUTES
+	attrs := []attribute.KeyValue{
+		semconv.ServiceNameKey.String(cfg.ServiceName),
+		attribute.String("environment", getEnvironment()),
+	}
+	attrs = appendResourceAttributes(attrs, "", cfg.ResourceAttributes)
 	res, err := resource.New(ctx,
-		resource.WithAttributes(
-			semconv.ServiceNameKey.String(serviceName),
-			attribute.String("environment", getEnvironment()),
-		),
+		resource.WithFromEnv(),
+		resource.WithAttributes(attrs...),
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	// Carregar configuração do arquivo
-	cfg, err := loadTracingConfig(logger)
+	// Determinar qual exportador usar baseado na configuração
+	traceExporter, err := getTraceExporter(ctx, cfg, logger)
 	if err != nil {
-		logger.Warn("Erro ao carregar configuração do arquivo, usando valores padrão", zap.Error(err))
+		return nil, err
 	}
 
-	// Determinar qual exportador usar baseado na configuração
-	traceExporter, err := getTraceExporter(ctx, collectorURL, cfg, logger)
+	// Mascarar os atributos sensíveis antes da exportação
+	traceExporter, err = newRedactingExporter(traceExporter, cfg.Redact)
 	if err != nil {
 		return nil, err
 	}
 
+	sampler := newSampler(cfg)
+	logger.Info("Amostragem de tracing configurada", zap.String("sampler", sampler.Description()))
+
 	// Criar o TracerProvider com a configuração
 	tp := sdktrace.NewTracerProvider(
-		sdktrace.WithSampler(sdktrace.TraceIDRatioBased(cfg.SamplingRatio)),
+		sdktrace.WithSampler(sampler),
 		sdktrace.WithBatcher(traceExporter),
 		sdktrace.WithResource(res),
 	)
 
-	// Configurar propagação de contexto
-	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
-		propagation.TraceContext{},
-		propagation.Baggage{},
-	))
-
-	// Configurar o provider global
+	// Configurar o provider global; os formatos de propagação são definidos
+	// por SetPropagators, também sem exportador
 	otel.SetTracerProvider(tp)
-	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
 
 	return &TracerProvider{
 		provider: tp,
@@ -73,74 +76,55 @@ func NewTracerProvider(ctx context.Context, serviceName, collectorURL string, lo
 	}, nil
 }
 
-// loadTracingConfig carrega a configuração de tracing do arquivo ou ambiente
-func loadTracingConfig(logger *zap.Logger) (config.TracingConfig, error) {
-	// Valor padrão
-	defaultConfig := config.TracingConfig{
-		Enabled:       true,
-		Provider:      "otlp",
-		SamplingRatio: 0.1,
-	}
-
-	// Tentar carregar do arquivo
-	cfg, err := config.LoadConfig("./config")
-	if err != nil {
-		logger.Warn("Não foi possível carregar arquivo de configuração", zap.Error(err))
-		return defaultConfig, err
-	}
-
-	// Sobrescrever com variáveis de ambiente se definidas
-	if provider := os.Getenv("AG_TRACING_PROVIDER"); provider != "" {
-		cfg.Tracing.Provider = provider
-	}
-
-	if endpoint := os.Getenv("AG_TRACING_ENDPOINT"); endpoint != "" {
-		cfg.Tracing.Endpoint = endpoint
-	}
-
-	// Verificar se está habilitado via env (prioridade sobre arquivo)
-	if enabled := os.Getenv("AG_TRACING_ENABLED"); enabled != "" {
-		cfg.Tracing.Enabled = (enabled == "true" || enabled == "1")
+// appendResourceAttributes adiciona os atributos configurados, recompondo com
+// ponto as chaves que o viper separa em mapas aninhados
+func appendResourceAttributes(attrs []attribute.KeyValue, prefix string, values map[string]interface{}) []attribute.KeyValue {
+	for key, value := range values {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]interface{}); ok {
+			attrs = appendResourceAttributes(attrs, key, nested)
+			continue
+		}
+		attrs = append(attrs, attribute.String(key, fmt.Sprint(value)))
 	}
-
-	return cfg.Tracing, nil
+	return attrs
 }
 
 // getTraceExporter seleciona o exportador de trace baseado na configuração
-func getTraceExporter(ctx context.Context, endpointURL string, cfg config.TracingConfig, logger *zap.Logger) (sdktrace.SpanExporter, error) {
-	// Usar endpointURL se fornecido como parâmetro, caso contrário usar da configuração
-	if endpointURL == "" {
-		endpointURL = cfg.Endpoint
-	}
-
+func getTraceExporter(ctx context.Context, cfg config.TracingConfig, logger *zap.Logger) (sdktrace.SpanExporter, error) {
 	// Log da configuração usada
 	logger.Info("Configurando exportador de tracing",
 		zap.String("provider", cfg.Provider),
-		zap.String("endpoint", endpointURL),
+		zap.String("endpoint", cfg.Endpoint),
+		zap.Bool("tls", cfg.TLS),
 		zap.Float64("sampling_ratio", cfg.SamplingRatio))
 
 	switch cfg.Provider {
 	case "otlp", "opentelemetry":
-		// Conectar ao coletor OTLP
-		conn, err := grpc.DialContext(ctx, endpointURL,
-			grpc.WithTransportCredentials(insecure.NewCredentials()),
-			grpc.WithBlock())
-		if err != nil {
-			return nil, err
-		}
-		return otlptracegrpc.New(ctx, otlptracegrpc.WithGRPCConn(conn))
-
 	default:
 		logger.Warn("Provedor de tracing desconhecido, usando OTLP como padrão",
 			zap.String("provider", cfg.Provider))
-		conn, err := grpc.DialContext(ctx, endpointURL,
-			grpc.WithTransportCredentials(insecure.NewCredentials()),
-			grpc.WithBlock())
-		if err != nil {
-			return nil, err
-		}
-		return otlptracegrpc.New(ctx, otlptracegrpc.WithGRPCConn(conn))
 	}
+
+	// Conectar ao coletor OTLP
+	transport := insecure.NewCredentials()
+	if cfg.TLS {
+		transport = credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+	}
+	conn, err := grpc.DialContext(ctx, cfg.Endpoint,
+		grpc.WithTransportCredentials(transport),
+		grpc.WithBlock())
+	if err != nil {
+		return nil, err
+	}
+
+	options := []otlptracegrpc.Option{otlptracegrpc.WithGRPCConn(conn)}
+	if len(cfg.Headers) > 0 {
+		options = append(options, otlptracegrpc.WithHeaders(cfg.Headers))
+	}
+	return otlptracegrpc.New(ctx, options...)
 }
 
 // Shutdown encerra o tracer provider de forma limpa
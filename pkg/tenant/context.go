@@ -0,0 +1,28 @@
+package tenant
+
+import "context"
+
+type contextKey struct{}
+
+// WithTenant retorna um contexto associado ao tenant informado
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	if tenantID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, contextKey{}, tenantID)
+}
+
+// FromContext retorna o tenant associado ao contexto ou string vazia
+func FromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	tenantID, _ := ctx.Value(contextKey{}).(string)
+	return tenantID
+}
+
+// Detach cria um contexto independente que preserva apenas o tenant, útil para
+// operações assíncronas que continuam após o fim da requisição
+func Detach(ctx context.Context) context.Context {
+	return WithTenant(context.Background(), FromContext(ctx))
+}
@@ -0,0 +1,512 @@
+// Painel administrativo do API Gateway. Usa apenas a API administrativa, com o
+// token obtido em /auth/login guardado na sessão do navegador.
+(function () {
+  'use strict';
+
+  var TOKEN_KEY = 'apigateway.admin.token';
+  var USER_KEY = 'apigateway.admin.user';
+  var REDACTED = '********';
+  var MAX_LOG_ROWS = 200;
+
+  var $ = function (id) { return document.getElementById(id); };
+
+  var state = {
+    routes: [],
+    metrics: {},
+    previousCalls: {},
+    previousAt: 0,
+    selected: '',
+    editing: '',
+    timers: [],
+    logs: null
+  };
+
+  // api chama a API administrativa e converte as respostas de erro em exceções
+  // com a mensagem do gateway
+  function api(method, path, body) {
+    var options = { method: method, headers: { 'Authorization': 'Bearer ' + sessionStorage.getItem(TOKEN_KEY) } };
+    if (body !== undefined) {
+      options.headers['Content-Type'] = 'application/json';
+      options.body = typeof body === 'string' ? body : JSON.stringify(body);
+    }
+    return fetch(path, options).then(function (resp) {
+      if (resp.status === 401) {
+        logout('Sessão expirada; entre novamente.');
+        throw new Error('Não autenticado');
+      }
+      if (resp.status === 204) {
+        return null;
+      }
+      return resp.json().catch(function () { return {}; }).then(function (data) {
+        if (!resp.ok) {
+          var message = data.error || ('HTTP ' + resp.status);
+          throw new Error(data.details ? message + ': ' + data.details : message);
+        }
+        return data;
+      });
+    });
+  }
+
+  function routeURL(path) {
+    return '/admin/api/v1/routes' + encodeURI(path);
+  }
+
+  function text(value) {
+    return value === undefined || value === null ? '' : String(value);
+  }
+
+  function cell(row, value, className) {
+    var td = document.createElement('td');
+    td.textContent = text(value);
+    if (className) {
+      td.className = className;
+    }
+    row.appendChild(td);
+    return td;
+  }
+
+  function statusClass(status) {
+    return 'status-' + String(status).charAt(0) + 'xx';
+  }
+
+  function formatTime(value) {
+    if (!value || value.indexOf('0001-') === 0) {
+      return '';
+    }
+    return new Date(value).toLocaleTimeString();
+  }
+
+  function showNotice(message) {
+    $('notice').textContent = message;
+    $('notice').hidden = !message;
+  }
+
+  function setResult(element, message, ok) {
+    element.textContent = message;
+    element.className = ok ? 'ok' : 'error';
+  }
+
+  // Sessão
+
+  function login(event) {
+    event.preventDefault();
+    var form = event.target;
+    fetch('/auth/login', {
+      method: 'POST',
+      headers: { 'Content-Type': 'application/json' },
+      body: JSON.stringify({ username: form.username.value, password: form.password.value })
+    }).then(function (resp) {
+      return resp.json().then(function (data) {
+        if (!resp.ok) {
+          throw new Error(data.error || 'Falha no login');
+        }
+        sessionStorage.setItem(TOKEN_KEY, data.token);
+        sessionStorage.setItem(USER_KEY, data.user ? data.user.username : '');
+        form.reset();
+        start();
+      });
+    }).catch(function (err) {
+      $('login-error').textContent = err.message;
+    });
+  }
+
+  function logout(message) {
+    var token = sessionStorage.getItem(TOKEN_KEY);
+    if (token && !message) {
+      fetch('/auth/logout', { method: 'POST', headers: { 'Authorization': 'Bearer ' + token } });
+    }
+    sessionStorage.removeItem(TOKEN_KEY);
+    sessionStorage.removeItem(USER_KEY);
+    stopTimers();
+    stopLogs();
+    $('app').hidden = true;
+    $('login').hidden = false;
+    $('login-error').textContent = message || '';
+  }
+
+  function start() {
+    $('login').hidden = true;
+    $('app').hidden = false;
+    $('user').textContent = sessionStorage.getItem(USER_KEY) || '';
+    showNotice('');
+    showTab('routes');
+  }
+
+  // Abas
+
+  function stopTimers() {
+    state.timers.forEach(clearInterval);
+    state.timers = [];
+  }
+
+  function every(fn, interval) {
+    fn();
+    state.timers.push(setInterval(fn, interval));
+  }
+
+  function showTab(name) {
+    stopTimers();
+    Array.prototype.forEach.call(document.querySelectorAll('nav button'), function (button) {
+      button.classList.toggle('active', button.dataset.tab === name);
+    });
+    Array.prototype.forEach.call(document.querySelectorAll('.tab'), function (tab) {
+      tab.hidden = tab.id !== 'tab-' + name;
+    });
+    if (name !== 'logs') {
+      stopLogs();
+    }
+    if (name === 'routes') {
+      every(refreshRoutes, 5000);
+    } else if (name === 'upstreams') {
+      every(refreshUpstreams, 10000);
+    }
+  }
+
+  // Rotas e métricas
+
+  function loadAllRoutes(page, acc) {
+    return api('GET', '/admin/api/v1/routes?pageSize=500&page=' + page).then(function (data) {
+      acc = acc.concat(data.items || []);
+      if (acc.length < data.total && (data.items || []).length > 0) {
+        return loadAllRoutes(page + 1, acc);
+      }
+      return acc;
+    });
+  }
+
+  function refreshRoutes() {
+    Promise.all([loadAllRoutes(1, []), api('GET', '/admin/metrics').catch(function () { return {}; })])
+      .then(function (results) {
+        var now = Date.now();
+        var elapsed = state.previousAt ? (now - state.previousAt) / 1000 : 0;
+        var rates = {};
+        Object.keys(results[1]).forEach(function (path) {
+          var calls = results[1][path].callCount || 0;
+          if (elapsed > 0 && state.previousCalls[path] !== undefined) {
+            rates[path] = Math.max(0, calls - state.previousCalls[path]) / elapsed;
+          }
+          state.previousCalls[path] = calls;
+        });
+        state.previousAt = now;
+        state.routes = results[0];
+        state.metrics = results[1];
+        state.rates = rates;
+        renderRoutes();
+        if (state.selected) {
+          refreshRouteStats(state.selected);
+        }
+        showNotice('');
+      })
+      .catch(function (err) { showNotice('Falha ao carregar rotas: ' + err.message); });
+  }
+
+  function renderRoutes() {
+    var filter = $('routes-filter').value.trim();
+    var body = $('routes-body');
+    body.textContent = '';
+    var shown = 0;
+    state.routes.forEach(function (route) {
+      if (filter && route.Path.indexOf(filter) === -1) {
+        return;
+      }
+      shown++;
+      var metrics = state.metrics[route.Path] || {};
+      var rate = state.rates && state.rates[route.Path];
+      var row = document.createElement('tr');
+      row.className = 'clickable' + (route.Path === state.selected ? ' selected' : '');
+      cell(row, route.Path, 'wrap');
+      cell(row, (route.Methods || []).join(', '));
+      cell(row, route.IsActive ? 'sim' : 'não');
+      cell(row, metrics.callCount !== undefined ? metrics.callCount : route.CallCount);
+      cell(row, rate !== undefined ? rate.toFixed(2) : '');
+      cell(row, metrics.avgResponseTime || '');
+      var actions = cell(row, '');
+      var edit = document.createElement('button');
+      edit.className = 'secondary';
+      edit.textContent = 'Editar';
+      edit.addEventListener('click', function (event) {
+        event.stopPropagation();
+        openEditor(route.Path);
+      });
+      actions.appendChild(edit);
+      row.addEventListener('click', function () {
+        state.selected = route.Path;
+        renderRoutes();
+        refreshRouteStats(route.Path);
+      });
+      body.appendChild(row);
+    });
+    $('routes-total').textContent = shown + ' de ' + state.routes.length + ' rotas';
+  }
+
+  function refreshRouteStats(path) {
+    api('GET', routeURL(path) + '/metrics').then(function (report) {
+      if (state.selected !== path) {
+        return;
+      }
+      $('route-stats-title').textContent = 'Estatísticas de ' + path;
+      var body = $('route-stats-body');
+      body.textContent = '';
+      (report.windows || []).forEach(function (w) {
+        var row = document.createElement('tr');
+        cell(row, w.window);
+        cell(row, w.requests);
+        cell(row, w.requestsPerSecond.toFixed(2));
+        cell(row, w.errors + ' (' + (w.errorRate * 100).toFixed(1) + '%)');
+        cell(row, w.latencyMs.avg.toFixed(1) + ' ms');
+        cell(row, w.latencyMs.p50.toFixed(1) + ' ms');
+        cell(row, w.latencyMs.p95.toFixed(1) + ' ms');
+        cell(row, w.latencyMs.p99.toFixed(1) + ' ms');
+        body.appendChild(row);
+      });
+      $('route-stats').hidden = false;
+    }).catch(function (err) {
+      $('route-stats-title').textContent = path + ': ' + err.message;
+      $('route-stats-body').textContent = '';
+      $('route-stats').hidden = false;
+    });
+  }
+
+  // Editor de rotas
+
+  function openEditor(path) {
+    state.editing = path;
+    $('editor-result').textContent = '';
+    $('editor-delete').hidden = !path;
+    $('editor-title').textContent = path ? 'Editando ' + path : 'Nova rota';
+    showTab('editor');
+    if (!path) {
+      $('editor-json').value = JSON.stringify({
+        Path: '/api/exemplo',
+        ServiceURL: 'http://backend:8080',
+        Methods: ['GET'],
+        Description: '',
+        IsActive: true
+      }, null, 2);
+      return;
+    }
+    $('editor-json').value = '';
+    api('GET', routeURL(path)).then(function (route) {
+      $('editor-json').value = JSON.stringify(route, null, 2);
+    }).catch(function (err) {
+      setResult($('editor-result'), err.message, false);
+    });
+  }
+
+  // editorRoute lê o JSON do editor; os erros de sintaxe são exibidos sem
+  // consultar o gateway
+  function editorRoute() {
+    var raw = $('editor-json').value;
+    try {
+      JSON.parse(raw);
+    } catch (err) {
+      setResult($('editor-result'), 'JSON inválido: ' + err.message, false);
+      return null;
+    }
+    return raw;
+  }
+
+  function saveRoute(dryRun) {
+    var raw = editorRoute();
+    if (raw === null) {
+      return;
+    }
+    if (!dryRun && raw.indexOf(REDACTED) !== -1) {
+      setResult($('editor-result'), 'Informe novamente os segredos mascarados (' + REDACTED + ') antes de salvar.', false);
+      return;
+    }
+    var query = dryRun ? '?dryRun=true' : '';
+    var request = state.editing
+      ? api('PUT', routeURL(state.editing) + query, raw)
+      : api('POST', '/admin/api/v1/routes' + query, raw);
+    request.then(function (route) {
+      if (dryRun) {
+        setResult($('editor-result'), 'Configuração válida.', true);
+        return;
+      }
+      setResult($('editor-result'), 'Rota ' + route.Path + ' salva.', true);
+      if (!state.editing) {
+        openEditor(route.Path);
+        setResult($('editor-result'), 'Rota ' + route.Path + ' criada.', true);
+      }
+    }).catch(function (err) {
+      setResult($('editor-result'), err.message, false);
+    });
+  }
+
+  function deleteRoute() {
+    if (!state.editing || !confirm('Excluir a rota ' + state.editing + '?')) {
+      return;
+    }
+    api('DELETE', routeURL(state.editing)).then(function () {
+      state.selected = '';
+      showTab('routes');
+    }).catch(function (err) {
+      setResult($('editor-result'), err.message, false);
+    });
+  }
+
+  // Cache
+
+  function clearRouteCache() {
+    api('GET', '/admin/clear-cache').then(function (data) {
+      setResult($('cache-result'), data.message, true);
+    }).catch(function (err) {
+      setResult($('cache-result'), err.message, false);
+    });
+  }
+
+  function purgeResponses() {
+    var path = $('cache-path').value.trim();
+    if (!path) {
+      setResult($('cache-result'), 'Informe o caminho da rota.', false);
+      return;
+    }
+    api('DELETE', '/admin/cache/responses?path=' + encodeURIComponent(path)).then(function (data) {
+      setResult($('cache-result'), data.message + ' (' + data.route + ')', true);
+    }).catch(function (err) {
+      setResult($('cache-result'), err.message, false);
+    });
+  }
+
+  // Saúde dos upstreams
+
+  function refreshUpstreams() {
+    api('GET', '/admin/health/upstreams').then(function (data) {
+      var summary = data.summary || {};
+      $('upstreams-summary').textContent = Object.keys(summary).length
+        ? Object.keys(summary).map(function (status) { return status + ': ' + summary[status]; }).join(' · ')
+        : 'Nenhum alvo com verificação ativa de saúde';
+      var body = $('upstreams-body');
+      body.textContent = '';
+      (data.targets || []).forEach(function (target) {
+        var row = document.createElement('tr');
+        cell(row, target.route, 'wrap');
+        cell(row, target.target, 'wrap');
+        cell(row, target.checker);
+        cell(row, target.status, 'status-' + target.status);
+        cell(row, target.consecutive_successes);
+        cell(row, target.consecutive_failures);
+        cell(row, formatTime(target.last_check));
+        cell(row, target.last_error, 'wrap');
+        body.appendChild(row);
+      });
+      showNotice('');
+    }).catch(function (err) { showNotice('Falha ao consultar upstreams: ' + err.message); });
+  }
+
+  // Log de acesso
+
+  function toggleLogs() {
+    if (state.logs) {
+      stopLogs();
+      return;
+    }
+    var query = [];
+    if ($('logs-route').value.trim()) {
+      query.push('route=' + encodeURIComponent($('logs-route').value.trim()));
+    }
+    if ($('logs-status').value) {
+      query.push('minStatus=' + encodeURIComponent($('logs-status').value));
+    }
+    var controller = new AbortController();
+    state.logs = controller;
+    $('logs-toggle').textContent = 'Parar';
+
+    fetch('/admin/api/v1/logs/access' + (query.length ? '?' + query.join('&') : ''), {
+      headers: { 'Authorization': 'Bearer ' + sessionStorage.getItem(TOKEN_KEY) },
+      signal: controller.signal
+    }).then(function (resp) {
+      if (!resp.ok) {
+        return resp.json().catch(function () { return {}; }).then(function (data) {
+          throw new Error(data.error || ('HTTP ' + resp.status));
+        });
+      }
+      return readLines(resp.body.getReader(), addLogEntry);
+    }).catch(function (err) {
+      if (err.name !== 'AbortError') {
+        showNotice('Acompanhamento do log interrompido: ' + err.message);
+      }
+    }).then(function () {
+      if (state.logs === controller) {
+        stopLogs();
+      }
+    });
+  }
+
+  // readLines entrega cada linha do corpo em streaming ao callback
+  function readLines(reader, onLine) {
+    var decoder = new TextDecoder();
+    var buffer = '';
+    function pump() {
+      return reader.read().then(function (chunk) {
+        if (chunk.done) {
+          return;
+        }
+        buffer += decoder.decode(chunk.value, { stream: true });
+        var lines = buffer.split('\n');
+        buffer = lines.pop();
+        lines.forEach(function (line) {
+          if (line.trim()) {
+            onLine(JSON.parse(line));
+          }
+        });
+        return pump();
+      });
+    }
+    return pump();
+  }
+
+  function addLogEntry(entry) {
+    var body = $('logs-body');
+    var row = document.createElement('tr');
+    cell(row, formatTime(entry.time));
+    cell(row, entry.method);
+    cell(row, entry.path, 'wrap');
+    cell(row, entry.status, statusClass(entry.status));
+    cell(row, entry.latency_ms.toFixed(1) + ' ms');
+    cell(row, entry.bytes);
+    cell(row, entry.ip);
+    cell(row, entry.username);
+    body.insertBefore(row, body.firstChild);
+    while (body.childNodes.length > MAX_LOG_ROWS) {
+      body.removeChild(body.lastChild);
+    }
+  }
+
+  function stopLogs() {
+    if (state.logs) {
+      state.logs.abort();
+      state.logs = null;
+    }
+    $('logs-toggle').textContent = 'Acompanhar';
+  }
+
+  // Inicialização
+
+  $('login-form').addEventListener('submit', login);
+  $('logout').addEventListener('click', function () { logout(); });
+  Array.prototype.forEach.call(document.querySelectorAll('nav button'), function (button) {
+    button.addEventListener('click', function () {
+      if (button.dataset.tab === 'editor') {
+        openEditor(state.editing);
+        return;
+      }
+      showTab(button.dataset.tab);
+    });
+  });
+  $('routes-filter').addEventListener('input', renderRoutes);
+  $('routes-new').addEventListener('click', function () { openEditor(''); });
+  $('editor-validate').addEventListener('click', function () { saveRoute(true); });
+  $('editor-save').addEventListener('click', function () { saveRoute(false); });
+  $('editor-delete').addEventListener('click', deleteRoute);
+  $('cache-clear').addEventListener('click', clearRouteCache);
+  $('cache-purge').addEventListener('click', purgeResponses);
+  $('logs-toggle').addEventListener('click', toggleLogs);
+
+  if (sessionStorage.getItem(TOKEN_KEY)) {
+    start();
+  } else {
+    logout('');
+  }
+})();
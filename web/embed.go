@@ -0,0 +1,19 @@
+// Package web embute no binário os arquivos estáticos do painel administrativo.
+package web
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed admin
+var files embed.FS
+
+// AdminUI retorna os arquivos do painel administrativo servido em /admin/ui
+func AdminUI() fs.FS {
+	sub, err := fs.Sub(files, "admin")
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}